@@ -3,7 +3,7 @@ package assistant
 
 import (
 	"encoding/json"
-	"fmt"
+	"time"
 )
 
 // MessageTextContent represents text content for conversation messages.
@@ -188,6 +188,157 @@ type ToolsDeltaBlock struct {
 // Ensure ToolsDeltaBlock implements MessageContent
 func (ToolsDeltaBlock) isMessageContent() {}
 
+// Delta block type identifiers.
+const (
+	// DeltaTypeContent identifies a TextContentBlock.
+	DeltaTypeContent = "content"
+	// DeltaTypeTools identifies a ToolsDeltaBlock.
+	DeltaTypeTools = "tools"
+	// DeltaTypeToolCalls identifies a ToolCallsBlock.
+	DeltaTypeToolCalls = "tool_calls"
+	// DeltaTypeRetrieval identifies a RetrievalBlock.
+	DeltaTypeRetrieval = "retrieval"
+	// DeltaTypeError identifies an ErrorBlock.
+	DeltaTypeError = "error"
+)
+
+// ToolFunction represents a function invoked by the assistant.
+type ToolFunction struct {
+	// Name is the function name
+	Name string `json:"name"`
+
+	// Arguments are the JSON-encoded function arguments
+	Arguments string `json:"arguments,omitempty"`
+
+	// Outputs is the function result, if returned in the same block
+	Outputs string `json:"outputs,omitempty"`
+}
+
+// CodeInterpreterOutput represents a single output of code execution.
+type CodeInterpreterOutput struct {
+	// Type is the output type ("logs" or "file")
+	Type string `json:"type"`
+
+	// Logs is the textual output of the execution
+	Logs string `json:"logs,omitempty"`
+
+	// File is the URL or ID of a generated file
+	File string `json:"file,omitempty"`
+}
+
+// CodeInterpreterCall represents code executed by the assistant.
+type CodeInterpreterCall struct {
+	// Input is the code that was executed
+	Input string `json:"input"`
+
+	// Outputs are the execution results
+	Outputs []CodeInterpreterOutput `json:"outputs,omitempty"`
+}
+
+// Citation represents a document cited by retrieval.
+type Citation struct {
+	// FileID is the ID of the cited knowledge file
+	FileID string `json:"file_id,omitempty"`
+
+	// Document is the title or name of the cited document
+	Document string `json:"document,omitempty"`
+
+	// Text is the cited passage
+	Text string `json:"text,omitempty"`
+}
+
+// RetrievalCall represents a retrieval performed by the assistant.
+type RetrievalCall struct {
+	// Outputs are the retrieved passages
+	Outputs []Citation `json:"outputs,omitempty"`
+}
+
+// ToolCall represents a single tool invocation by the assistant.
+type ToolCall struct {
+	// ID is the tool call identifier
+	ID string `json:"id,omitempty"`
+
+	// Type is the tool type (e.g. "function", "code_interpreter", "retrieval")
+	Type string `json:"type"`
+
+	// Function is set for function calls
+	Function *ToolFunction `json:"function,omitempty"`
+
+	// CodeInterpreter is set for code execution
+	CodeInterpreter *CodeInterpreterCall `json:"code_interpreter,omitempty"`
+
+	// Retrieval is set for knowledge retrieval
+	Retrieval *RetrievalCall `json:"retrieval,omitempty"`
+}
+
+// ToolCallsBlock represents one or more tool invocations in assistant messages.
+type ToolCallsBlock struct {
+	// Role is the role of the message sender, usually "tool"
+	Role string `json:"role,omitempty"`
+
+	// Type is the type identifier, always "tool_calls"
+	Type string `json:"type"`
+
+	// ToolCalls is the list of tool invocations
+	ToolCalls []ToolCall `json:"tool_calls"`
+}
+
+// Ensure ToolCallsBlock implements MessageContent
+func (ToolCallsBlock) isMessageContent() {}
+
+// RetrievalBlock represents retrieval citations in assistant messages.
+type RetrievalBlock struct {
+	// Role is the role of the message sender
+	Role string `json:"role,omitempty"`
+
+	// Type is the type identifier, always "retrieval"
+	Type string `json:"type"`
+
+	// Citations is the list of cited documents
+	Citations []Citation `json:"citations"`
+}
+
+// Ensure RetrievalBlock implements MessageContent
+func (RetrievalBlock) isMessageContent() {}
+
+// ErrorBlock represents an error reported mid-stream.
+type ErrorBlock struct {
+	// Type is the type identifier, always "error"
+	Type string `json:"type"`
+
+	// Code is the error code identifier
+	Code string `json:"code,omitempty"`
+
+	// Message is the human-readable error message
+	Message string `json:"message"`
+}
+
+// Ensure ErrorBlock implements MessageContent
+func (ErrorBlock) isMessageContent() {}
+
+// UnknownBlock holds a delta block of a type this SDK does not recognize.
+// The original JSON is preserved so no data is lost.
+type UnknownBlock struct {
+	// Type is the type identifier reported by the API
+	Type string `json:"type"`
+
+	// Raw is the original JSON of the block
+	Raw json.RawMessage `json:"-"`
+}
+
+// Ensure UnknownBlock implements MessageContent
+func (UnknownBlock) isMessageContent() {}
+
+// MarshalJSON returns the original JSON of the block.
+func (b UnknownBlock) MarshalJSON() ([]byte, error) {
+	if len(b.Raw) == 0 {
+		return json.Marshal(struct {
+			Type string `json:"type"`
+		}{b.Type})
+	}
+	return b.Raw, nil
+}
+
 // AssistantChoice represents assistant response choice information.
 type AssistantChoice struct {
 	// Index is the choice result index
@@ -221,33 +372,62 @@ func (c *AssistantChoice) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	// Parse the delta based on its type field
-	var typeCheck struct {
-		Type string `json:"type"`
+	// Choices without a delta (e.g. the final chunk) carry no content
+	if len(aux.Delta) == 0 || string(aux.Delta) == "null" {
+		c.Delta = nil
+		return nil
 	}
 
-	if err := json.Unmarshal(aux.Delta, &typeCheck); err != nil {
+	delta, err := decodeBlock(aux.Delta)
+	if err != nil {
 		return err
 	}
+	c.Delta = delta
+	return nil
+}
+
+// decodeBlock decodes a content block by its type field. Blocks of a type
+// this SDK does not recognize are returned as an UnknownBlock with their
+// original JSON.
+func decodeBlock(data json.RawMessage) (MessageContent, error) {
+	var typeCheck struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &typeCheck); err != nil {
+		return nil, err
+	}
 
 	switch typeCheck.Type {
-	case "content":
-		var textBlock TextContentBlock
-		if err := json.Unmarshal(aux.Delta, &textBlock); err != nil {
-			return err
-		}
-		c.Delta = textBlock
-	case "tools":
-		var toolsBlock ToolsDeltaBlock
-		if err := json.Unmarshal(aux.Delta, &toolsBlock); err != nil {
-			return err
-		}
-		c.Delta = toolsBlock
+	case DeltaTypeContent:
+		return decodeBlockAs[TextContentBlock](data)
+	case DeltaTypeTools:
+		return decodeBlockAs[ToolsDeltaBlock](data)
+	case DeltaTypeToolCalls:
+		return decodeBlockAs[ToolCallsBlock](data)
+	case DeltaTypeRetrieval:
+		return decodeBlockAs[RetrievalBlock](data)
+	case DeltaTypeError:
+		return decodeBlockAs[ErrorBlock](data)
+	case BlockTypeText:
+		return decodeBlockAs[MessageTextContent](data)
+	case BlockTypeFile:
+		return decodeBlockAs[FileBlock](data)
 	default:
-		return fmt.Errorf("unknown delta type: %s", typeCheck.Type)
+		// Preserve unrecognized blocks instead of failing the whole stream
+		return UnknownBlock{
+			Type: typeCheck.Type,
+			Raw:  append(json.RawMessage(nil), data...),
+		}, nil
 	}
+}
 
-	return nil
+// decodeBlockAs decodes data as a block of type B.
+func decodeBlockAs[B MessageContent](data json.RawMessage) (MessageContent, error) {
+	var block B
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, err
+	}
+	return block, nil
 }
 
 // CompletionUsage represents token usage statistics.
@@ -309,6 +489,55 @@ func (r *AssistantCompletion) GetText() string {
 	return ""
 }
 
+// GetToolCalls returns the tool invocations from the first choice's delta.
+func (r *AssistantCompletion) GetToolCalls() []ToolCall {
+	if len(r.Choices) == 0 {
+		return nil
+	}
+
+	if block, ok := r.Choices[0].Delta.(ToolCallsBlock); ok {
+		return block.ToolCalls
+	}
+
+	return nil
+}
+
+// GetCitations returns the documents cited in the first choice's delta,
+// either from a retrieval block or from retrieval tool calls.
+func (r *AssistantCompletion) GetCitations() []Citation {
+	if len(r.Choices) == 0 {
+		return nil
+	}
+
+	switch block := r.Choices[0].Delta.(type) {
+	case RetrievalBlock:
+		return block.Citations
+	case ToolCallsBlock:
+		var citations []Citation
+		for _, call := range block.ToolCalls {
+			if call.Retrieval != nil {
+				citations = append(citations, call.Retrieval.Outputs...)
+			}
+		}
+		return citations
+	}
+
+	return nil
+}
+
+// GetErrorBlock returns the error block from the first choice's delta, if any.
+func (r *AssistantCompletion) GetErrorBlock() *ErrorBlock {
+	if len(r.Choices) == 0 {
+		return nil
+	}
+
+	if block, ok := r.Choices[0].Delta.(ErrorBlock); ok {
+		return &block
+	}
+
+	return nil
+}
+
 // IsCompleted returns true if the generation is completed.
 func (r *AssistantCompletion) IsCompleted() bool {
 	return r.Status == "completed"
@@ -324,11 +553,15 @@ func (r *AssistantCompletion) IsFailed() bool {
 	return r.Status == "failed"
 }
 
-// GetError returns the error message if generation failed.
+// GetError returns the error message if generation failed, falling back
+// to the message of an error block in the stream.
 func (r *AssistantCompletion) GetError() string {
 	if r.LastError != nil {
 		return r.LastError.Message
 	}
+	if block := r.GetErrorBlock(); block != nil {
+		return block.Message
+	}
 	return ""
 }
 
@@ -387,11 +620,13 @@ type ConversationUsage struct {
 	// AssistantID is the assistant identifier
 	AssistantID string `json:"assistant_id"`
 
-	// CreateTime is the conversation creation timestamp
-	CreateTime int64 `json:"create_time"`
+	// CreateTime is when the conversation was created. The API returns it
+	// as a Unix epoch in seconds or milliseconds; both are decoded.
+	CreateTime time.Time `json:"create_time"`
 
-	// UpdateTime is the last update timestamp
-	UpdateTime int64 `json:"update_time"`
+	// UpdateTime is when the conversation was last updated, decoded like
+	// CreateTime.
+	UpdateTime time.Time `json:"update_time"`
 
 	// Usage contains token usage statistics
 	Usage CompletionUsage `json:"usage"`
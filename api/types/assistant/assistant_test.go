@@ -561,3 +561,85 @@ func TestExtraParameters(t *testing.T) {
 	assert.Equal(t, "en", params.Translate.FromLanguage)
 	assert.Equal(t, "es", params.Translate.ToLanguage)
 }
+
+func TestAssistantChoice_UnmarshalDeltaBlocks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tool calls", func(t *testing.T) {
+		t.Parallel()
+
+		var choice AssistantChoice
+		err := json.Unmarshal([]byte(`{"index":0,"delta":{"role":"tool","type":"tool_calls","tool_calls":[
+			{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}},
+			{"id":"call_2","type":"code_interpreter","code_interpreter":{"input":"print(1+1)","outputs":[{"type":"logs","logs":"2"}]}},
+			{"id":"call_3","type":"retrieval","retrieval":{"outputs":[{"file_id":"file_9","document":"manual.pdf","text":"Reset the device"}]}}
+		]}}`), &choice)
+		require.NoError(t, err)
+
+		completion := &AssistantCompletion{Choices: []AssistantChoice{choice}}
+		calls := completion.GetToolCalls()
+		require.Len(t, calls, 3)
+		assert.Equal(t, "get_weather", calls[0].Function.Name)
+		assert.Equal(t, "2", calls[1].CodeInterpreter.Outputs[0].Logs)
+
+		citations := completion.GetCitations()
+		require.Len(t, citations, 1)
+		assert.Equal(t, "file_9", citations[0].FileID)
+		assert.Empty(t, completion.GetText())
+	})
+
+	t.Run("retrieval", func(t *testing.T) {
+		t.Parallel()
+
+		var choice AssistantChoice
+		err := json.Unmarshal([]byte(`{"index":0,"delta":{"type":"retrieval","citations":[{"file_id":"file_1","text":"a"},{"file_id":"file_2","text":"b"}]}}`), &choice)
+		require.NoError(t, err)
+
+		completion := &AssistantCompletion{Choices: []AssistantChoice{choice}}
+		citations := completion.GetCitations()
+		require.Len(t, citations, 2)
+		assert.Equal(t, "file_2", citations[1].FileID)
+		assert.Nil(t, completion.GetToolCalls())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		var choice AssistantChoice
+		err := json.Unmarshal([]byte(`{"index":0,"delta":{"type":"error","code":"1301","message":"blocked"}}`), &choice)
+		require.NoError(t, err)
+
+		completion := &AssistantCompletion{Choices: []AssistantChoice{choice}}
+		require.NotNil(t, completion.GetErrorBlock())
+		assert.Equal(t, "1301", completion.GetErrorBlock().Code)
+		assert.Equal(t, "blocked", completion.GetError())
+	})
+
+	t.Run("unknown type is preserved", func(t *testing.T) {
+		t.Parallel()
+
+		raw := `{"type":"web_browser","outputs":[{"url":"https://example.com"}]}`
+		var choice AssistantChoice
+		err := json.Unmarshal([]byte(`{"index":0,"delta":`+raw+`}`), &choice)
+		require.NoError(t, err)
+
+		block, ok := choice.Delta.(UnknownBlock)
+		require.True(t, ok)
+		assert.Equal(t, "web_browser", block.Type)
+		assert.JSONEq(t, raw, string(block.Raw))
+
+		data, err := json.Marshal(block)
+		require.NoError(t, err)
+		assert.JSONEq(t, raw, string(data))
+	})
+
+	t.Run("missing delta", func(t *testing.T) {
+		t.Parallel()
+
+		var choice AssistantChoice
+		err := json.Unmarshal([]byte(`{"index":0,"finish_reason":"stop"}`), &choice)
+		require.NoError(t, err)
+		assert.Nil(t, choice.Delta)
+		assert.Equal(t, "stop", choice.FinishReason)
+	})
+}
@@ -0,0 +1,152 @@
+package assistant
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Defaults for attaching files to a conversation.
+const (
+	// DefaultUploadConcurrency is the default number of files uploaded in parallel.
+	DefaultUploadConcurrency = 4
+
+	// DefaultFilePollInterval is the default longest wait between checks
+	// of the status of an uploaded file.
+	DefaultFilePollInterval = 2 * time.Second
+
+	// DefaultFileTimeout is the default time allowed for uploaded files to
+	// become ready.
+	DefaultFileTimeout = 2 * time.Minute
+)
+
+// FileInput is a file to attach to a conversation: either a file to
+// upload from Reader, or a file uploaded before, by ID.
+type FileInput struct {
+	// Reader is the content of a file to upload.
+	Reader io.Reader
+
+	// Filename is the name of the file to upload.
+	Filename string
+
+	// FileID is the ID of an uploaded file.
+	FileID string
+}
+
+// FileFromReader returns a file uploaded from r as filename.
+//
+// Example:
+//
+//	file, err := os.Open("contract.pdf")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer file.Close()
+//
+//	input := assistant.FileFromReader(file, "contract.pdf")
+func FileFromReader(r io.Reader, filename string) FileInput {
+	return FileInput{Reader: r, Filename: filename}
+}
+
+// FileFromID returns a file uploaded before with the given ID.
+func FileFromID(fileID string) FileInput {
+	return FileInput{FileID: fileID}
+}
+
+// Validate checks that the input has exactly one of a reader and a file ID.
+func (f FileInput) Validate() error {
+	switch {
+	case f.Reader == nil && f.FileID == "":
+		return fmt.Errorf("either a reader or a file ID is required")
+	case f.Reader != nil && f.FileID != "":
+		return fmt.Errorf("must not have both a reader and a file ID")
+	case f.Reader != nil && f.Filename == "":
+		return fmt.Errorf("file name is required with a reader")
+	}
+	return nil
+}
+
+// AttachConfig holds settings for attaching files to a conversation.
+type AttachConfig struct {
+	// Concurrency is the maximum number of uploads in flight.
+	Concurrency int
+
+	// PollInterval is the longest wait between checks of the status of
+	// an uploaded file.
+	PollInterval time.Duration
+
+	// Timeout is the time allowed for uploaded files to become ready.
+	Timeout time.Duration
+
+	// KeepOnFailure keeps the files uploaded by a call that fails. By
+	// default they are deleted.
+	KeepOnFailure bool
+}
+
+// AttachOption configures attaching files to a conversation.
+type AttachOption func(*AttachConfig)
+
+// NewAttachConfig creates an attach configuration with the given options applied.
+func NewAttachConfig(opts ...AttachOption) *AttachConfig {
+	cfg := &AttachConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultUploadConcurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultFilePollInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultFileTimeout
+	}
+	return cfg
+}
+
+// WithUploadConcurrency sets the maximum number of uploads in flight.
+func WithUploadConcurrency(n int) AttachOption {
+	return func(c *AttachConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithFilePolling sets the longest wait between status checks of uploaded
+// files, and the time they are allowed to take to become ready.
+func WithFilePolling(interval, timeout time.Duration) AttachOption {
+	return func(c *AttachConfig) {
+		c.PollInterval = interval
+		c.Timeout = timeout
+	}
+}
+
+// WithKeepOnFailure keeps the files uploaded by a call that fails,
+// instead of deleting them.
+func WithKeepOnFailure() AttachOption {
+	return func(c *AttachConfig) {
+		c.KeepOnFailure = true
+	}
+}
+
+// FileError reports the file input of a conversation that could not be
+// attached.
+type FileError struct {
+	// Index is the position of the file in the inputs.
+	Index int
+
+	// Filename is the name of the file, or its ID for uploaded files.
+	Filename string
+
+	// Err is the cause.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FileError) Error() string {
+	return fmt.Sprintf("attach file %d (%s): %v", e.Index, e.Filename, e.Err)
+}
+
+// Unwrap returns the cause.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,45 @@
+package assistant
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileInput_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, FileFromReader(strings.NewReader("a"), "a.pdf").Validate())
+	assert.NoError(t, FileFromID("file-1").Validate())
+	assert.Error(t, FileInput{}.Validate())
+	assert.Error(t, FileFromReader(strings.NewReader("a"), "").Validate())
+	assert.Error(t, FileInput{Reader: strings.NewReader("a"), Filename: "a.pdf", FileID: "file-1"}.Validate())
+}
+
+func TestNewAttachConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewAttachConfig()
+	assert.Equal(t, DefaultUploadConcurrency, cfg.Concurrency)
+	assert.Equal(t, DefaultFilePollInterval, cfg.PollInterval)
+	assert.Equal(t, DefaultFileTimeout, cfg.Timeout)
+	assert.False(t, cfg.KeepOnFailure)
+
+	cfg = NewAttachConfig(WithUploadConcurrency(2), WithFilePolling(time.Second, time.Minute), WithKeepOnFailure())
+	assert.Equal(t, 2, cfg.Concurrency)
+	assert.Equal(t, time.Second, cfg.PollInterval)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+	assert.True(t, cfg.KeepOnFailure)
+}
+
+func TestFileError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("unsupported file")
+	err := &FileError{Index: 2, Filename: "a.exe", Err: cause}
+	assert.Equal(t, "attach file 2 (a.exe): unsupported file", err.Error())
+	assert.ErrorIs(t, err, cause)
+}
@@ -0,0 +1,61 @@
+package assistant
+
+import "time"
+
+// DefaultReconnectBackoff is the wait before the first reconnect of a
+// conversation stream. It doubles with each attempt.
+const DefaultReconnectBackoff = 500 * time.Millisecond
+
+// StreamConfig holds settings for a conversation stream.
+type StreamConfig struct {
+	// MaxReconnects is how many times a stream interrupted by a transport
+	// error is reopened. Zero disables reconnection.
+	MaxReconnects int
+
+	// ReconnectBackoff is the wait before the first reconnect.
+	ReconnectBackoff time.Duration
+}
+
+// StreamOption configures a conversation stream.
+type StreamOption func(*StreamConfig)
+
+// NewStreamConfig creates a stream configuration with the given options applied.
+func NewStreamConfig(opts ...StreamOption) *StreamConfig {
+	cfg := &StreamConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.ReconnectBackoff <= 0 {
+		cfg.ReconnectBackoff = DefaultReconnectBackoff
+	}
+	return cfg
+}
+
+// WithReconnect reopens a stream interrupted by a transport error before
+// it completed, up to maxReconnects times with exponential backoff. The
+// request is repeated with the Last-Event-ID header set to the ID of the
+// last event received, and events the server replays are skipped.
+//
+// Resuming relies on the server sending event IDs: once an event without an
+// ID was received, the stream is no longer reconnected, as replayed events
+// could not be told apart from new ones. HTTP error responses to a
+// reconnect end the stream.
+//
+// Example:
+//
+//	stream, err := client.Assistant.ConversationStream(ctx, req,
+//	    assistant.WithReconnect(3),
+//	)
+func WithReconnect(maxReconnects int) StreamOption {
+	return func(c *StreamConfig) {
+		c.MaxReconnects = maxReconnects
+	}
+}
+
+// WithReconnectBackoff sets the wait before the first reconnect. It
+// doubles with each attempt.
+func WithReconnectBackoff(backoff time.Duration) StreamOption {
+	return func(c *StreamConfig) {
+		c.ReconnectBackoff = backoff
+	}
+}
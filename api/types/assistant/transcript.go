@@ -0,0 +1,251 @@
+package assistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// Transcript block type identifiers. Transcripts also carry the delta
+// block types, such as DeltaTypeContent and DeltaTypeToolCalls.
+const (
+	// BlockTypeText identifies a MessageTextContent, the content of the
+	// messages sent to the assistant.
+	BlockTypeText = "text"
+	// BlockTypeFile identifies a FileBlock.
+	BlockTypeFile = "file"
+)
+
+// FileBlock represents a file attached to a conversation message.
+type FileBlock struct {
+	// Type is the type identifier, always "file"
+	Type string `json:"type"`
+
+	// FileID is the ID of the uploaded file
+	FileID string `json:"file_id"`
+
+	// FileName is the name of the file, if known
+	FileName string `json:"file_name,omitempty"`
+
+	// FileURL is the URL of the file, if any
+	FileURL string `json:"file_url,omitempty"`
+}
+
+// Ensure FileBlock implements MessageContent
+func (FileBlock) isMessageContent() {}
+
+// TranscriptMessage is a message of a past conversation, as returned by
+// AssistantService.GetConversationMessages.
+type TranscriptMessage struct {
+	// ID is the message identifier
+	ID string `json:"id,omitempty"`
+
+	// Role is the message role (e.g. "user", "assistant", "tool")
+	Role string `json:"role"`
+
+	// Content is the list of content blocks: text, tool events, citations,
+	// attachments and blocks of unknown types, which are kept as an
+	// UnknownBlock with their original JSON.
+	Content []MessageContent `json:"content"`
+
+	// CreateTime is when the message was created. The API returns it as a
+	// Unix epoch in seconds or milliseconds; both are decoded.
+	CreateTime time.Time `json:"create_time"`
+
+	// Metadata is the metadata extension field
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// UnmarshalJSON decodes the content blocks of a message by type and its
+// timestamp from a Unix epoch. Content given as a plain string decodes to
+// a single text block.
+func (m *TranscriptMessage) UnmarshalJSON(data []byte) error {
+	type alias TranscriptMessage
+	var raw struct {
+		alias
+		Content    json.RawMessage `json:"content"`
+		CreateTime json.RawMessage `json:"create_time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := decodeContent(raw.Content)
+	if err != nil {
+		return fmt.Errorf("invalid content: %w", err)
+	}
+	createTime, err := parseEpoch(raw.CreateTime)
+	if err != nil {
+		return fmt.Errorf("invalid create_time: %w", err)
+	}
+
+	*m = TranscriptMessage(raw.alias)
+	m.Content = content
+	m.CreateTime = createTime
+	return nil
+}
+
+// MarshalJSON encodes the timestamp of a message as a Unix epoch in
+// milliseconds, and a zero time as 0.
+func (m TranscriptMessage) MarshalJSON() ([]byte, error) {
+	type alias TranscriptMessage
+	return json.Marshal(struct {
+		alias
+		CreateTime int64 `json:"create_time"`
+	}{
+		alias:      alias(m),
+		CreateTime: epochMillis(m.CreateTime),
+	})
+}
+
+// decodeContent decodes a list of content blocks, a single block or a
+// plain string.
+func decodeContent(data json.RawMessage) ([]MessageContent, error) {
+	text := strings.TrimSpace(string(data))
+	switch {
+	case text == "" || text == "null":
+		return nil, nil
+	case strings.HasPrefix(text, `"`):
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		return []MessageContent{MessageTextContent{Type: BlockTypeText, Text: s}}, nil
+	case strings.HasPrefix(text, "{"):
+		block, err := decodeBlock(data)
+		if err != nil {
+			return nil, err
+		}
+		return []MessageContent{block}, nil
+	}
+
+	var blocks []json.RawMessage
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return nil, err
+	}
+	content := make([]MessageContent, 0, len(blocks))
+	for i, raw := range blocks {
+		block, err := decodeBlock(raw)
+		if err != nil {
+			return nil, fmt.Errorf("block %d: %w", i, err)
+		}
+		content = append(content, block)
+	}
+	return content, nil
+}
+
+// GetText returns the text of the message's text and content blocks,
+// joined in order.
+func (m *TranscriptMessage) GetText() string {
+	var b strings.Builder
+	for _, block := range m.Content {
+		switch block := block.(type) {
+		case MessageTextContent:
+			b.WriteString(block.Text)
+		case TextContentBlock:
+			b.WriteString(block.Content)
+		}
+	}
+	return b.String()
+}
+
+// GetToolCalls returns the tool invocations of the message's tool call
+// blocks.
+func (m *TranscriptMessage) GetToolCalls() []ToolCall {
+	var calls []ToolCall
+	for _, block := range m.Content {
+		if block, ok := block.(ToolCallsBlock); ok {
+			calls = append(calls, block.ToolCalls...)
+		}
+	}
+	return calls
+}
+
+// GetFiles returns the files attached to the message.
+func (m *TranscriptMessage) GetFiles() []FileBlock {
+	var files []FileBlock
+	for _, block := range m.Content {
+		if block, ok := block.(FileBlock); ok {
+			files = append(files, block)
+		}
+	}
+	return files
+}
+
+// ConversationMessageList represents a page of conversation messages.
+type ConversationMessageList struct {
+	// AssistantID is the assistant identifier
+	AssistantID string `json:"assistant_id"`
+
+	// ConversationID is the conversation identifier
+	ConversationID string `json:"conversation_id"`
+
+	// HasMore indicates whether there are more pages available
+	HasMore bool `json:"has_more"`
+
+	// MessageList is the list of messages, oldest first
+	MessageList []TranscriptMessage `json:"message_list"`
+}
+
+// ConversationMessagesResponse represents the response for a conversation
+// messages query.
+type ConversationMessagesResponse struct {
+	// Code is the response status code
+	Code int `json:"code"`
+
+	// Message is the response message
+	Message string `json:"msg"`
+
+	// Data contains the conversation messages
+	Data ConversationMessageList `json:"data"`
+}
+
+// GetMessages returns the messages of the page.
+func (r *ConversationMessagesResponse) GetMessages() []TranscriptMessage {
+	return r.Data.MessageList
+}
+
+// HasMore returns whether there are more pages available.
+func (r *ConversationMessagesResponse) HasMore() bool {
+	return r.Data.HasMore
+}
+
+// ToChatMessages converts a transcript into chat messages, to replay a
+// conversation into the Chat API. User, system and assistant messages
+// keep their text; tool events, citations and attachments are left out,
+// since the Chat API cannot run the assistant's tools, and messages left
+// without text are dropped.
+//
+// Example:
+//
+//	var transcript []assistant.TranscriptMessage
+//	iter := client.Assistant.GetConversationMessagesAll(ctx, "asst_123", "conv_456")
+//	for iter.Next() {
+//	    transcript = append(transcript, *iter.Current())
+//	}
+//	if err := iter.Err(); err != nil {
+//	    return err
+//	}
+//
+//	messages := append(assistant.ToChatMessages(transcript), chat.NewUserMessage("And then?"))
+//	resp, err := client.Chat.Create(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, messages))
+func ToChatMessages(transcript []TranscriptMessage) []chat.Message {
+	messages := make([]chat.Message, 0, len(transcript))
+	for _, m := range transcript {
+		role := chat.Role(m.Role)
+		switch role {
+		case chat.RoleUser, chat.RoleSystem, chat.RoleAssistant:
+		default:
+			continue
+		}
+		text := m.GetText()
+		if text == "" {
+			continue
+		}
+		messages = append(messages, chat.Message{Role: role, Content: text})
+	}
+	return messages
+}
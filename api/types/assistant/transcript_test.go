@@ -0,0 +1,92 @@
+package assistant
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// transcriptJSON is a conversation with text, tool, attachment and
+// unknown blocks, with timestamps in seconds and milliseconds.
+const transcriptJSON = `[
+	{"id":"msg_1","role":"user","create_time":1709296200,"content":[
+		{"type":"text","text":"Summarize the attached manual."},
+		{"type":"file","file_id":"file_9","file_name":"manual.pdf"}
+	]},
+	{"id":"msg_2","role":"tool","create_time":"1709296201000","content":[
+		{"type":"tool_calls","tool_calls":[{"id":"call_1","type":"retrieval","retrieval":{"outputs":[{"file_id":"file_9","text":"Hold reset for 10 seconds"}]}}]}
+	]},
+	{"id":"msg_3","role":"assistant","create_time":1709296202,"content":[
+		{"type":"content","role":"assistant","content":"Hold the reset button "},
+		{"type":"content","role":"assistant","content":"for 10 seconds."},
+		{"type":"chart","spec":{"kind":"bar"}}
+	]},
+	{"id":"msg_4","role":"user","create_time":1709296260,"content":"Thanks!"}
+]`
+
+func TestTranscriptMessage_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var transcript []TranscriptMessage
+	require.NoError(t, json.Unmarshal([]byte(transcriptJSON), &transcript))
+	require.Len(t, transcript, 4)
+
+	user := transcript[0]
+	assert.Equal(t, "Summarize the attached manual.", user.GetText())
+	assert.Equal(t, []FileBlock{{Type: "file", FileID: "file_9", FileName: "manual.pdf"}}, user.GetFiles())
+	assert.True(t, time.Unix(1709296200, 0).Equal(user.CreateTime))
+
+	tool := transcript[1]
+	require.Len(t, tool.GetToolCalls(), 1)
+	assert.Equal(t, "retrieval", tool.GetToolCalls()[0].Type)
+	assert.Equal(t, "Hold reset for 10 seconds", tool.GetToolCalls()[0].Retrieval.Outputs[0].Text)
+	assert.True(t, time.UnixMilli(1709296201000).Equal(tool.CreateTime))
+
+	reply := transcript[2]
+	assert.Equal(t, "Hold the reset button for 10 seconds.", reply.GetText())
+	require.Len(t, reply.Content, 3)
+	unknown, ok := reply.Content[2].(UnknownBlock)
+	require.True(t, ok)
+	assert.Equal(t, "chart", unknown.Type)
+	assert.JSONEq(t, `{"type":"chart","spec":{"kind":"bar"}}`, string(unknown.Raw))
+
+	// Plain string content is a single text block
+	assert.Equal(t, []MessageContent{MessageTextContent{Type: "text", Text: "Thanks!"}}, transcript[3].Content)
+
+	// Re-encoding keeps the unknown block and every other block
+	data, err := json.Marshal(transcript)
+	require.NoError(t, err)
+	var again []TranscriptMessage
+	require.NoError(t, json.Unmarshal(data, &again))
+	assert.Equal(t, transcript, again)
+}
+
+func TestTranscriptMessage_UnmarshalJSONInvalid(t *testing.T) {
+	t.Parallel()
+
+	var msg TranscriptMessage
+	assert.Error(t, json.Unmarshal([]byte(`{"role":"user","content":[{"type":"text","text":1}]}`), &msg))
+	assert.Error(t, json.Unmarshal([]byte(`{"role":"user","create_time":"yesterday"}`), &msg))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"role":"user","content":null}`), &msg))
+	assert.Empty(t, msg.Content)
+}
+
+func TestToChatMessages(t *testing.T) {
+	t.Parallel()
+
+	var transcript []TranscriptMessage
+	require.NoError(t, json.Unmarshal([]byte(transcriptJSON), &transcript))
+
+	assert.Equal(t, []chat.Message{
+		{Role: chat.RoleUser, Content: "Summarize the attached manual."},
+		{Role: chat.RoleAssistant, Content: "Hold the reset button for 10 seconds."},
+		{Role: chat.RoleUser, Content: "Thanks!"},
+	}, ToChatMessages(transcript))
+	assert.Empty(t, ToChatMessages(nil))
+}
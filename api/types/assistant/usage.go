@@ -0,0 +1,163 @@
+package assistant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// millisecondEpochThreshold separates epochs in seconds from epochs in
+// milliseconds: in seconds it is in the year 33658, in milliseconds in
+// September 2001, before the API existed.
+const millisecondEpochThreshold = 1_000_000_000_000
+
+// UnmarshalJSON decodes the timestamps of a conversation from Unix epochs
+// in seconds or milliseconds, given as numbers or numeric strings.
+func (u *ConversationUsage) UnmarshalJSON(data []byte) error {
+	type alias ConversationUsage
+	var raw struct {
+		alias
+		CreateTime json.RawMessage `json:"create_time"`
+		UpdateTime json.RawMessage `json:"update_time"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	createTime, err := parseEpoch(raw.CreateTime)
+	if err != nil {
+		return fmt.Errorf("invalid create_time: %w", err)
+	}
+	updateTime, err := parseEpoch(raw.UpdateTime)
+	if err != nil {
+		return fmt.Errorf("invalid update_time: %w", err)
+	}
+
+	*u = ConversationUsage(raw.alias)
+	u.CreateTime = createTime
+	u.UpdateTime = updateTime
+	return nil
+}
+
+// MarshalJSON encodes the timestamps of a conversation as Unix epochs in
+// milliseconds, and zero times as 0.
+func (u ConversationUsage) MarshalJSON() ([]byte, error) {
+	type alias ConversationUsage
+	return json.Marshal(struct {
+		alias
+		CreateTime int64 `json:"create_time"`
+		UpdateTime int64 `json:"update_time"`
+	}{
+		alias:      alias(u),
+		CreateTime: epochMillis(u.CreateTime),
+		UpdateTime: epochMillis(u.UpdateTime),
+	})
+}
+
+// CreateTimeUnix returns CreateTime as a Unix epoch in seconds, or 0 if it
+// is not set.
+//
+// Deprecated: Use CreateTime, which is a time.Time.
+func (u *ConversationUsage) CreateTimeUnix() int64 {
+	return epochSeconds(u.CreateTime)
+}
+
+// UpdateTimeUnix returns UpdateTime as a Unix epoch in seconds, or 0 if it
+// is not set.
+//
+// Deprecated: Use UpdateTime, which is a time.Time.
+func (u *ConversationUsage) UpdateTimeUnix() int64 {
+	return epochSeconds(u.UpdateTime)
+}
+
+// parseEpoch decodes a Unix epoch in seconds or milliseconds. A missing,
+// null, empty or zero epoch decodes to the zero time.
+func parseEpoch(data json.RawMessage) (time.Time, error) {
+	text := strings.Trim(string(data), `"`)
+	if text == "" || text == "null" {
+		return time.Time{}, nil
+	}
+
+	epoch, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		// Some responses carry a fractional part
+		f, ferr := strconv.ParseFloat(text, 64)
+		if ferr != nil {
+			return time.Time{}, fmt.Errorf("%s is not a Unix epoch", data)
+		}
+		epoch = int64(f)
+	}
+
+	switch {
+	case epoch == 0:
+		return time.Time{}, nil
+	case epoch >= millisecondEpochThreshold || epoch <= -millisecondEpochThreshold:
+		return time.UnixMilli(epoch), nil
+	default:
+		return time.Unix(epoch, 0), nil
+	}
+}
+
+// epochSeconds returns t as a Unix epoch in seconds, or 0 for the zero time.
+func epochSeconds(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// epochMillis returns t as a Unix epoch in milliseconds, or 0 for the zero
+// time.
+func epochMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// UsageListParams holds the parameters for listing conversation usage.
+type UsageListParams struct {
+	// Page is the first page to fetch, starting at 1.
+	Page int
+
+	// PageSize is the number of conversations per page.
+	PageSize int
+}
+
+// UsageListOption configures a conversation usage listing.
+type UsageListOption func(*UsageListParams)
+
+// DefaultUsagePageSize is the page size used when none is given.
+const DefaultUsagePageSize = 10
+
+// NewUsageListParams creates usage list parameters from options. Missing
+// or invalid values are replaced with page 1 and DefaultUsagePageSize.
+func NewUsageListParams(opts ...UsageListOption) *UsageListParams {
+	params := &UsageListParams{}
+	for _, opt := range opts {
+		opt(params)
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	if params.PageSize < 1 {
+		params.PageSize = DefaultUsagePageSize
+	}
+	return params
+}
+
+// WithStartPage starts the listing at the given page, starting at 1.
+func WithStartPage(page int) UsageListOption {
+	return func(p *UsageListParams) {
+		p.Page = page
+	}
+}
+
+// WithUsagePageSize sets the number of conversations fetched per page.
+func WithUsagePageSize(size int) UsageListOption {
+	return func(p *UsageListParams) {
+		p.PageSize = size
+	}
+}
@@ -0,0 +1,99 @@
+package assistant
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConversationUsage_UnmarshalEpochs(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		createTime string
+		want       time.Time
+	}{
+		{"seconds", `1709296200`, created},
+		{"milliseconds", `1709296200123`, created.Add(123 * time.Millisecond)},
+		{"string seconds", `"1709296200"`, created},
+		{"string milliseconds", `"1709296200000"`, created},
+		{"fractional seconds", `1709296200.0`, created},
+		{"zero", `0`, time.Time{}},
+		{"null", `null`, time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var usage ConversationUsage
+			require.NoError(t, json.Unmarshal([]byte(`{
+				"id": "conv_1",
+				"assistant_id": "asst_123",
+				"create_time": `+tt.createTime+`,
+				"usage": {"total_tokens": 42}
+			}`), &usage))
+
+			assert.True(t, tt.want.Equal(usage.CreateTime), "got %v, want %v", usage.CreateTime, tt.want)
+			assert.True(t, usage.UpdateTime.IsZero(), "missing update_time decodes to the zero time")
+			assert.Equal(t, "conv_1", usage.ID)
+			assert.Equal(t, 42, usage.Usage.TotalTokens)
+		})
+	}
+
+	var usage ConversationUsage
+	err := json.Unmarshal([]byte(`{"create_time": "yesterday"}`), &usage)
+	assert.ErrorContains(t, err, "invalid create_time")
+}
+
+func TestConversationUsage_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	usage := ConversationUsage{
+		ID:         "conv_1",
+		CreateTime: time.UnixMilli(1709296200123),
+	}
+
+	data, err := json.Marshal(usage)
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	assert.Equal(t, float64(1709296200123), fields["create_time"])
+	assert.Equal(t, float64(0), fields["update_time"])
+
+	var decoded ConversationUsage
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, usage.CreateTime.Equal(decoded.CreateTime))
+	assert.True(t, decoded.UpdateTime.IsZero())
+}
+
+func TestConversationUsage_UnixShims(t *testing.T) {
+	t.Parallel()
+
+	usage := ConversationUsage{CreateTime: time.UnixMilli(1709296200123)}
+	assert.Equal(t, int64(1709296200), usage.CreateTimeUnix())
+	assert.Equal(t, int64(0), usage.UpdateTimeUnix())
+}
+
+func TestNewUsageListParams(t *testing.T) {
+	t.Parallel()
+
+	params := NewUsageListParams()
+	assert.Equal(t, 1, params.Page)
+	assert.Equal(t, DefaultUsagePageSize, params.PageSize)
+
+	params = NewUsageListParams(WithStartPage(3), WithUsagePageSize(50))
+	assert.Equal(t, 3, params.Page)
+	assert.Equal(t, 50, params.PageSize)
+
+	params = NewUsageListParams(WithStartPage(-1), WithUsagePageSize(0))
+	assert.Equal(t, 1, params.Page)
+	assert.Equal(t, DefaultUsagePageSize, params.PageSize)
+}
@@ -1,7 +1,10 @@
 // Package audio provides types for the Audio API.
 package audio
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 // TranscriptionModel represents the audio transcription model.
 type TranscriptionModel string
@@ -49,6 +52,14 @@ type TranscriptionRequest struct {
 
 	// Temperature is the sampling temperature (0 to 1).
 	Temperature *float64
+
+	// SkipValidation disables the checks of the file before upload:
+	// format, size and duration.
+	SkipValidation bool
+
+	// MaxDuration is the maximum duration of the file checked before
+	// upload. Zero uses DefaultMaxDuration; negative disables the check.
+	MaxDuration time.Duration
 }
 
 // NewTranscriptionRequest creates a new transcription request.
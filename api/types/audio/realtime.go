@@ -0,0 +1,198 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// ModelGLMASR is the GLM-ASR model for file and realtime transcription.
+const ModelGLMASR TranscriptionModel = "glm-asr"
+
+// StreamAudioFormat is the encoding of audio sent to a realtime
+// transcription session.
+type StreamAudioFormat string
+
+const (
+	// StreamAudioFormatPCM16 is raw 16-bit little-endian mono PCM.
+	StreamAudioFormatPCM16 StreamAudioFormat = "pcm16"
+	// StreamAudioFormatWAV is WAV audio; the header is sent once, with
+	// the first chunk.
+	StreamAudioFormatWAV StreamAudioFormat = "wav"
+)
+
+const (
+	// DefaultStreamSampleRate is the sample rate of streamed audio unless
+	// set.
+	DefaultStreamSampleRate = 16000
+
+	// DefaultMaxInFlightChunks is the number of audio chunks that may be
+	// unacknowledged before SendAudio blocks, unless set.
+	DefaultMaxInFlightChunks = 8
+
+	// DefaultFlushTimeout is how long closing a session waits for the
+	// final results, unless set.
+	DefaultFlushTimeout = 10 * time.Second
+)
+
+// Event types of realtime transcription sessions.
+const (
+	// EventSessionUpdate configures the session. Sent by the client.
+	EventSessionUpdate = "transcription_session.update"
+	// EventAudioAppend sends a chunk of audio. Sent by the client.
+	EventAudioAppend = "input_audio_buffer.append"
+	// EventAudioCommit ends the audio, asking the server to finish
+	// transcribing it. Sent by the client.
+	EventAudioCommit = "input_audio_buffer.commit"
+
+	// EventAudioAppended acknowledges an audio chunk by its event ID.
+	EventAudioAppended = "input_audio_buffer.appended"
+	// EventAudioCommitted reports that the final results of the committed
+	// audio were sent.
+	EventAudioCommitted = "input_audio_buffer.committed"
+	// EventTranscriptionDelta carries new text of an utterance.
+	EventTranscriptionDelta = "transcription.delta"
+	// EventTranscriptionCompleted carries the final text of an utterance.
+	EventTranscriptionCompleted = "transcription.completed"
+	// EventError reports a failure of the session.
+	EventError = "error"
+)
+
+// TranscriptionStreamOptions configures a realtime transcription session.
+type TranscriptionStreamOptions struct {
+	// Model is the transcription model (required).
+	Model TranscriptionModel
+
+	// Language is the language of the audio (optional, ISO-639-1 format).
+	Language string
+
+	// AudioFormat is the encoding of the audio chunks. If empty, uses
+	// StreamAudioFormatPCM16.
+	AudioFormat StreamAudioFormat
+
+	// SampleRate is the sample rate of the audio in Hz. If zero, uses
+	// DefaultStreamSampleRate.
+	SampleRate int
+
+	// MaxInFlight is the number of chunks that may be sent without being
+	// acknowledged by the server; SendAudio blocks beyond it. If zero,
+	// uses DefaultMaxInFlightChunks.
+	MaxInFlight int
+
+	// FlushTimeout bounds how long Close waits for the final results. If
+	// zero, uses DefaultFlushTimeout.
+	FlushTimeout time.Duration
+}
+
+// NewTranscriptionStreamOptions creates options for a realtime
+// transcription session with the default audio format.
+//
+// Example:
+//
+//	opts := audio.NewTranscriptionStreamOptions(audio.ModelGLMASR).
+//	    SetLanguage("en").
+//	    SetAudioFormat(audio.StreamAudioFormatPCM16, 16000)
+func NewTranscriptionStreamOptions(model TranscriptionModel) *TranscriptionStreamOptions {
+	return &TranscriptionStreamOptions{
+		Model:       model,
+		AudioFormat: StreamAudioFormatPCM16,
+		SampleRate:  DefaultStreamSampleRate,
+	}
+}
+
+// SetLanguage sets the language of the audio.
+func (o *TranscriptionStreamOptions) SetLanguage(language string) *TranscriptionStreamOptions {
+	o.Language = language
+	return o
+}
+
+// SetAudioFormat sets the encoding and sample rate of the audio.
+func (o *TranscriptionStreamOptions) SetAudioFormat(format StreamAudioFormat, sampleRate int) *TranscriptionStreamOptions {
+	o.AudioFormat = format
+	o.SampleRate = sampleRate
+	return o
+}
+
+// SetMaxInFlight sets the number of unacknowledged chunks before SendAudio
+// blocks.
+func (o *TranscriptionStreamOptions) SetMaxInFlight(n int) *TranscriptionStreamOptions {
+	o.MaxInFlight = n
+	return o
+}
+
+// SetFlushTimeout sets how long Close waits for the final results.
+func (o *TranscriptionStreamOptions) SetFlushTimeout(timeout time.Duration) *TranscriptionStreamOptions {
+	o.FlushTimeout = timeout
+	return o
+}
+
+// Validate checks the options.
+func (o *TranscriptionStreamOptions) Validate() error {
+	if o.Model == "" {
+		return errors.NewValidationError("model", "is required", o.Model)
+	}
+	if o.SampleRate < 0 {
+		return errors.NewValidationError("sample_rate", "must not be negative", o.SampleRate)
+	}
+	if o.MaxInFlight < 0 {
+		return errors.NewValidationError("max_in_flight", "must not be negative", o.MaxInFlight)
+	}
+	if o.FlushTimeout < 0 {
+		return errors.NewValidationError("flush_timeout", "must not be negative", o.FlushTimeout)
+	}
+	return nil
+}
+
+// TranscriptSegment is a partial or final transcript of an utterance.
+type TranscriptSegment struct {
+	// ItemID identifies the utterance. Partial segments of an utterance
+	// share it with its final segment.
+	ItemID string
+
+	// Text is the transcript of the utterance so far, or in full if the
+	// segment is final.
+	Text string
+
+	// Start and End are the offsets of the utterance from the start of
+	// the audio, if reported.
+	Start time.Duration
+	End   time.Duration
+
+	// Final reports whether the text of the utterance is complete.
+	Final bool
+}
+
+// RealtimeEvent is a server event of a realtime transcription session.
+type RealtimeEvent struct {
+	// Type is the event type, such as EventTranscriptionDelta.
+	Type string `json:"type"`
+
+	// EventID is the ID of the client event acknowledged by
+	// EventAudioAppended.
+	EventID string `json:"event_id,omitempty"`
+
+	// ItemID identifies the utterance of transcription events.
+	ItemID string `json:"item_id,omitempty"`
+
+	// Delta is the new text of EventTranscriptionDelta.
+	Delta string `json:"delta,omitempty"`
+
+	// Transcript is the final text of EventTranscriptionCompleted.
+	Transcript string `json:"transcript,omitempty"`
+
+	// StartMs and EndMs are the offsets of the utterance in milliseconds.
+	StartMs int64 `json:"start_ms,omitempty"`
+	EndMs   int64 `json:"end_ms,omitempty"`
+
+	// Error describes the failure of EventError.
+	Error *RealtimeError `json:"error,omitempty"`
+}
+
+// RealtimeError describes a failure reported by a realtime session.
+type RealtimeError struct {
+	// Code is the error code.
+	Code string `json:"code,omitempty"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+}
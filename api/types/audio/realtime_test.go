@@ -0,0 +1,68 @@
+package audio
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestNewTranscriptionStreamOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := NewTranscriptionStreamOptions(ModelGLMASR).
+		SetLanguage("en").
+		SetAudioFormat(StreamAudioFormatWAV, 44100).
+		SetMaxInFlight(4).
+		SetFlushTimeout(time.Second)
+
+	assert.Equal(t, ModelGLMASR, opts.Model)
+	assert.Equal(t, "en", opts.Language)
+	assert.Equal(t, StreamAudioFormatWAV, opts.AudioFormat)
+	assert.Equal(t, 44100, opts.SampleRate)
+	assert.Equal(t, 4, opts.MaxInFlight)
+	assert.Equal(t, time.Second, opts.FlushTimeout)
+	assert.NoError(t, opts.Validate())
+
+	defaults := NewTranscriptionStreamOptions(ModelGLMASR)
+	assert.Equal(t, StreamAudioFormatPCM16, defaults.AudioFormat)
+	assert.Equal(t, DefaultStreamSampleRate, defaults.SampleRate)
+}
+
+func TestTranscriptionStreamOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]*TranscriptionStreamOptions{
+		"model":         {},
+		"sample_rate":   {Model: ModelGLMASR, SampleRate: -1},
+		"max_in_flight": {Model: ModelGLMASR, MaxInFlight: -1},
+		"flush_timeout": {Model: ModelGLMASR, FlushTimeout: -time.Second},
+	}
+	for field, opts := range tests {
+		err := opts.Validate()
+		assert.True(t, errors.IsValidationError(err), field)
+		assert.Contains(t, err.Error(), field)
+	}
+}
+
+func TestRealtimeEvent_JSON(t *testing.T) {
+	t.Parallel()
+
+	var event RealtimeEvent
+	data := `{"type":"transcription.completed","item_id":"item-1","transcript":"hello","start_ms":120,"end_ms":900}`
+	require.NoError(t, json.Unmarshal([]byte(data), &event))
+
+	assert.Equal(t, EventTranscriptionCompleted, event.Type)
+	assert.Equal(t, "item-1", event.ItemID)
+	assert.Equal(t, "hello", event.Transcript)
+	assert.Equal(t, int64(120), event.StartMs)
+	assert.Equal(t, int64(900), event.EndMs)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"error","error":{"code":"bad_audio","message":"invalid"}}`), &event))
+	require.NotNil(t, event.Error)
+	assert.Equal(t, "bad_audio", event.Error.Code)
+}
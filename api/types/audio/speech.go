@@ -0,0 +1,202 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+)
+
+// SpeechModel represents the text-to-speech model.
+type SpeechModel string
+
+const (
+	// ModelCogTTS is the CogTTS model for speech synthesis.
+	ModelCogTTS SpeechModel = "cogtts"
+)
+
+// SpeechFormat represents the audio format of synthesized speech.
+type SpeechFormat string
+
+const (
+	// SpeechFormatMP3 returns MP3-encoded audio.
+	SpeechFormatMP3 SpeechFormat = "mp3"
+	// SpeechFormatWAV returns WAV-encoded audio.
+	SpeechFormatWAV SpeechFormat = "wav"
+	// SpeechFormatPCM returns raw PCM audio.
+	SpeechFormatPCM SpeechFormat = "pcm"
+)
+
+// Built-in voices. Voice IDs returned by Voice.List or Voice.Clone
+// can be used in place of these.
+const (
+	// VoiceTongtong is the default female voice.
+	VoiceTongtong = "tongtong"
+	// VoiceChuichui is a young male voice.
+	VoiceChuichui = "chuichui"
+	// VoiceXiaochen is a mature male voice.
+	VoiceXiaochen = "xiaochen"
+)
+
+// SpeechRequest represents a request to synthesize speech from text.
+type SpeechRequest struct {
+	// Model is the model to use for speech synthesis (required).
+	Model SpeechModel `json:"model"`
+
+	// Input is the text to synthesize (required).
+	Input string `json:"input"`
+
+	// Voice is the voice to use. Either a built-in voice or a
+	// cloned voice ID returned by the Voice API.
+	Voice string `json:"voice"`
+
+	// ResponseFormat is the audio format of the output.
+	ResponseFormat SpeechFormat `json:"response_format,omitempty"`
+
+	// Speed is the playback speed (0.5 to 2.0).
+	Speed *float64 `json:"speed,omitempty"`
+
+	// Volume is the output volume (0 to 10).
+	Volume *float64 `json:"volume,omitempty"`
+
+	// Stream indicates whether to stream audio chunks as they are synthesized.
+	Stream bool `json:"stream,omitempty"`
+
+	// EncodeFormat is the encoding of streamed audio chunks. Only "base64",
+	// the default, is supported: SpeechStream rejects other values, since
+	// SpeechChunk.GetAudio decodes base64.
+	EncodeFormat string `json:"encode_format,omitempty"`
+
+	// RequestID is an optional request ID for tracking.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewSpeechRequest creates a new speech synthesis request.
+//
+// Example:
+//
+//	req := audio.NewSpeechRequest(audio.ModelCogTTS, "Hello there!", audio.VoiceTongtong)
+func NewSpeechRequest(model SpeechModel, input, voice string) *SpeechRequest {
+	return &SpeechRequest{
+		Model:          model,
+		Input:          input,
+		Voice:          voice,
+		ResponseFormat: SpeechFormatWAV,
+	}
+}
+
+// SetResponseFormat sets the output audio format.
+//
+// Example:
+//
+//	req.SetResponseFormat(audio.SpeechFormatMP3)
+func (r *SpeechRequest) SetResponseFormat(format SpeechFormat) *SpeechRequest {
+	r.ResponseFormat = format
+	return r
+}
+
+// SetSpeed sets the playback speed.
+//
+// Example:
+//
+//	req.SetSpeed(1.25)
+func (r *SpeechRequest) SetSpeed(speed float64) *SpeechRequest {
+	r.Speed = &speed
+	return r
+}
+
+// SetVolume sets the output volume.
+//
+// Example:
+//
+//	req.SetVolume(5)
+func (r *SpeechRequest) SetVolume(volume float64) *SpeechRequest {
+	r.Volume = &volume
+	return r
+}
+
+// SetRequestID sets the request ID.
+func (r *SpeechRequest) SetRequestID(requestID string) *SpeechRequest {
+	r.RequestID = requestID
+	return r
+}
+
+// SpeechResponse represents synthesized audio.
+type SpeechResponse struct {
+	// Audio is the raw audio data.
+	Audio []byte
+
+	// ContentType is the MIME type reported by the server.
+	ContentType string
+}
+
+// Bytes returns the raw audio data.
+func (r *SpeechResponse) Bytes() []byte {
+	return r.Audio
+}
+
+// Reader returns an io.Reader over the audio data for playback.
+func (r *SpeechResponse) Reader() io.Reader {
+	return bytes.NewReader(r.Audio)
+}
+
+// SaveTo writes the audio data to the file at path.
+//
+// Example:
+//
+//	if err := resp.SaveTo("greeting.wav"); err != nil {
+//	    // Handle error
+//	}
+func (r *SpeechResponse) SaveTo(path string) error {
+	return os.WriteFile(path, r.Audio, 0o644)
+}
+
+// SpeechChunk represents a chunk of streamed synthesized audio.
+type SpeechChunk struct {
+	// ID is the unique identifier for the synthesis.
+	ID string `json:"id,omitempty"`
+
+	// Created is the Unix timestamp of when the chunk was created.
+	Created int64 `json:"created,omitempty"`
+
+	// Choices is the list of chunk choices.
+	Choices []SpeechChunkChoice `json:"choices"`
+}
+
+// SpeechChunkChoice represents a choice in a streamed speech chunk.
+type SpeechChunkChoice struct {
+	// Index is the index of this choice.
+	Index int `json:"index"`
+
+	// Delta contains the incremental audio data.
+	Delta SpeechDelta `json:"delta"`
+
+	// FinishReason is set on the final chunk.
+	FinishReason string `json:"finish_reason,omitempty"`
+}
+
+// SpeechDelta represents incremental audio in a streamed chunk.
+type SpeechDelta struct {
+	// Role is the role of the author (usually "assistant").
+	Role string `json:"role,omitempty"`
+
+	// Content is the base64-encoded audio data.
+	Content string `json:"content,omitempty"`
+}
+
+// GetAudio decodes and returns the audio data carried by the chunk.
+// Returns nil if the chunk carries no audio.
+func (c *SpeechChunk) GetAudio() ([]byte, error) {
+	if len(c.Choices) == 0 || c.Choices[0].Delta.Content == "" {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(c.Choices[0].Delta.Content)
+}
+
+// IsFinished returns true if this chunk indicates the synthesis is finished.
+func (c *SpeechChunk) IsFinished() bool {
+	if len(c.Choices) == 0 {
+		return false
+	}
+	return c.Choices[0].FinishReason != ""
+}
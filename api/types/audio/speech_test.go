@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpeechRequest(t *testing.T) {
+	t.Parallel()
+
+	req := NewSpeechRequest(ModelCogTTS, "Hello there", VoiceTongtong)
+
+	assert.Equal(t, ModelCogTTS, req.Model)
+	assert.Equal(t, "Hello there", req.Input)
+	assert.Equal(t, VoiceTongtong, req.Voice)
+	assert.Equal(t, SpeechFormatWAV, req.ResponseFormat) // Default
+	assert.Nil(t, req.Speed)
+}
+
+func TestSpeechRequest_Setters(t *testing.T) {
+	t.Parallel()
+
+	req := NewSpeechRequest(ModelCogTTS, "Hi", "voice_clone_123").
+		SetResponseFormat(SpeechFormatMP3).
+		SetSpeed(1.5).
+		SetVolume(3).
+		SetRequestID("req_1")
+
+	assert.Equal(t, SpeechFormatMP3, req.ResponseFormat)
+	require.NotNil(t, req.Speed)
+	assert.Equal(t, 1.5, *req.Speed)
+	require.NotNil(t, req.Volume)
+	assert.Equal(t, 3.0, *req.Volume)
+	assert.Equal(t, "req_1", req.RequestID)
+}
+
+func TestSpeechRequest_JSON(t *testing.T) {
+	t.Parallel()
+
+	t.Run("minimal request omits optional fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewSpeechRequest(ModelCogTTS, "Hi", VoiceTongtong)
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		var got map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &got))
+
+		assert.Equal(t, "cogtts", got["model"])
+		assert.Equal(t, "Hi", got["input"])
+		assert.Equal(t, "tongtong", got["voice"])
+		assert.Equal(t, "wav", got["response_format"])
+		assert.NotContains(t, got, "speed")
+		assert.NotContains(t, got, "stream")
+		assert.NotContains(t, got, "encode_format")
+	})
+
+	t.Run("speed is serialized", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewSpeechRequest(ModelCogTTS, "Hi", VoiceTongtong).SetSpeed(0.8)
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(data), `"speed":0.8`)
+	})
+}
+
+func TestSpeechResponse(t *testing.T) {
+	t.Parallel()
+
+	resp := &SpeechResponse{Audio: []byte("RIFFdata"), ContentType: "audio/wav"}
+
+	assert.Equal(t, []byte("RIFFdata"), resp.Bytes())
+
+	data, err := io.ReadAll(resp.Reader())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("RIFFdata"), data)
+
+	path := filepath.Join(t.TempDir(), "out.wav")
+	require.NoError(t, resp.SaveTo(path))
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("RIFFdata"), saved)
+}
+
+func TestSpeechChunk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes audio", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := SpeechChunk{
+			Choices: []SpeechChunkChoice{
+				{Delta: SpeechDelta{Content: base64.StdEncoding.EncodeToString([]byte("pcm"))}},
+			},
+		}
+
+		data, err := chunk.GetAudio()
+		require.NoError(t, err)
+		assert.Equal(t, []byte("pcm"), data)
+		assert.False(t, chunk.IsFinished())
+	})
+
+	t.Run("empty chunk", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := SpeechChunk{}
+
+		data, err := chunk.GetAudio()
+		require.NoError(t, err)
+		assert.Nil(t, data)
+		assert.False(t, chunk.IsFinished())
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := SpeechChunk{
+			Choices: []SpeechChunkChoice{{Delta: SpeechDelta{Content: "!!not base64"}}},
+		}
+
+		_, err := chunk.GetAudio()
+		assert.Error(t, err)
+	})
+
+	t.Run("finished chunk", func(t *testing.T) {
+		t.Parallel()
+
+		chunk := SpeechChunk{
+			Choices: []SpeechChunkChoice{{FinishReason: "stop"}},
+		}
+
+		assert.True(t, chunk.IsFinished())
+	})
+}
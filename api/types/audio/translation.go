@@ -0,0 +1,70 @@
+package audio
+
+import "io"
+
+// TranslationRequest represents a request to translate audio into English.
+type TranslationRequest struct {
+	// File is the audio file to translate.
+	File io.Reader
+
+	// Filename is the name of the audio file.
+	Filename string
+
+	// Model is the model to use for translation (required).
+	Model TranscriptionModel
+
+	// Prompt is an optional English text to guide the model's style.
+	Prompt string
+
+	// ResponseFormat is the format of the translation output.
+	ResponseFormat ResponseFormat
+
+	// Temperature is the sampling temperature (0 to 1).
+	Temperature *float64
+}
+
+// NewTranslationRequest creates a new translation request.
+//
+// Example:
+//
+//	file, _ := os.Open("ticket.mp3")
+//	req := audio.NewTranslationRequest(file, "ticket.mp3", audio.ModelWhisper1)
+func NewTranslationRequest(file io.Reader, filename string, model TranscriptionModel) *TranslationRequest {
+	return &TranslationRequest{
+		File:           file,
+		Filename:       filename,
+		Model:          model,
+		ResponseFormat: ResponseFormatJSON, // Default to JSON
+	}
+}
+
+// SetPrompt sets a prompt to guide the translation.
+// The prompt should be in English.
+//
+// Example:
+//
+//	req.SetPrompt("A customer describing a billing problem.")
+func (r *TranslationRequest) SetPrompt(prompt string) *TranslationRequest {
+	r.Prompt = prompt
+	return r
+}
+
+// SetResponseFormat sets the response format.
+//
+// Example:
+//
+//	req.SetResponseFormat(audio.ResponseFormatSRT)
+func (r *TranslationRequest) SetResponseFormat(format ResponseFormat) *TranslationRequest {
+	r.ResponseFormat = format
+	return r
+}
+
+// SetTemperature sets the sampling temperature.
+//
+// Example:
+//
+//	req.SetTemperature(0.2)
+func (r *TranslationRequest) SetTemperature(temp float64) *TranslationRequest {
+	r.Temperature = &temp
+	return r
+}
@@ -0,0 +1,36 @@
+package audio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTranslationRequest(t *testing.T) {
+	t.Parallel()
+
+	file := strings.NewReader("audio content")
+	req := NewTranslationRequest(file, "ticket.mp3", ModelWhisper1)
+
+	assert.NotNil(t, req.File)
+	assert.Equal(t, "ticket.mp3", req.Filename)
+	assert.Equal(t, ModelWhisper1, req.Model)
+	assert.Equal(t, ResponseFormatJSON, req.ResponseFormat) // Default
+	assert.Nil(t, req.Temperature)
+}
+
+func TestTranslationRequest_Setters(t *testing.T) {
+	t.Parallel()
+
+	req := NewTranslationRequest(strings.NewReader("audio"), "ticket.mp3", ModelWhisper1).
+		SetPrompt("Billing support call").
+		SetResponseFormat(ResponseFormatVerboseJSON).
+		SetTemperature(0.3)
+
+	assert.Equal(t, "Billing support call", req.Prompt)
+	assert.Equal(t, ResponseFormatVerboseJSON, req.ResponseFormat)
+	require.NotNil(t, req.Temperature)
+	assert.Equal(t, 0.3, *req.Temperature)
+}
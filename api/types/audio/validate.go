@@ -0,0 +1,349 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// FileFormat is the container format of an audio file, detected from its
+// content.
+type FileFormat string
+
+const (
+	// FileFormatMP3 is MPEG audio, with or without an ID3 tag.
+	FileFormatMP3 FileFormat = "mp3"
+	// FileFormatWAV is RIFF WAVE.
+	FileFormatWAV FileFormat = "wav"
+	// FileFormatM4A is MPEG-4 audio.
+	FileFormatM4A FileFormat = "m4a"
+	// FileFormatFLAC is FLAC.
+	FileFormatFLAC FileFormat = "flac"
+	// FileFormatOGG is Ogg, such as Vorbis or Opus.
+	FileFormatOGG FileFormat = "ogg"
+	// FileFormatWebM is WebM.
+	FileFormatWebM FileFormat = "webm"
+)
+
+// supportedFormats lists the formats the transcription endpoint accepts.
+var supportedFormats = []FileFormat{FileFormatMP3, FileFormatWAV, FileFormatM4A, FileFormatFLAC, FileFormatOGG, FileFormatWebM}
+
+const (
+	// MaxFileSize is the documented maximum size of a transcribed file in
+	// bytes.
+	MaxFileSize int64 = 25 << 20
+
+	// DefaultMaxDuration is the documented maximum duration of a
+	// transcribed file, checked before upload unless set with
+	// SetMaxDuration.
+	DefaultMaxDuration = 30 * time.Second
+)
+
+// sniffBytes is the number of bytes read from the start of a file to
+// detect its format and duration. It leaves room for an ID3 tag with
+// cover art before the first MP3 frame.
+const sniffBytes = 256 << 10
+
+// SetSkipValidation sets whether to skip the checks of the file before
+// upload: format, size and duration.
+//
+// Example:
+//
+//	req.SetSkipValidation(true)
+func (r *TranscriptionRequest) SetSkipValidation(skip bool) *TranscriptionRequest {
+	r.SkipValidation = skip
+	return r
+}
+
+// SetMaxDuration overrides the maximum duration of the file checked before
+// upload. A negative duration disables the check.
+//
+// Example:
+//
+//	req.SetMaxDuration(5 * time.Minute)
+func (r *TranscriptionRequest) SetMaxDuration(d time.Duration) *TranscriptionRequest {
+	r.MaxDuration = d
+	return r
+}
+
+// Validate checks that a file and a model are set and, unless
+// SkipValidation is set, that the content of the file is in a supported
+// format and, when its headers tell (WAV, or MP3 with a Xing header), not
+// longer than the maximum duration. The filename extension is not
+// trusted. The start of the file is read from File and put back, so the
+// whole file is still uploaded.
+func (r *TranscriptionRequest) Validate() error {
+	if r.File == nil {
+		return errors.NewValidationError("file", "is required", nil)
+	}
+	if r.Model == "" {
+		return errors.NewValidationError("model", "is required", nil)
+	}
+	if r.SkipValidation {
+		return nil
+	}
+
+	header, err := r.peek(sniffBytes)
+	if err != nil {
+		return err
+	}
+	format := DetectFormat(header)
+	if format == "" {
+		detected := http.DetectContentType(header)
+		if len(header) == 0 {
+			detected = "empty file"
+		}
+		return errors.NewValidationError("file",
+			fmt.Sprintf("is not a supported audio file: detected %s, want one of %s", detected, formatList()), detected)
+	}
+
+	limit := r.MaxDuration
+	if limit == 0 {
+		limit = DefaultMaxDuration
+	}
+	if limit < 0 {
+		return nil
+	}
+	if d, ok := EstimateDuration(format, header); ok && d > limit {
+		return errors.NewValidationError("file",
+			fmt.Sprintf("is a %s file of about %s, over the maximum duration of %s", format, d.Round(time.Second), limit), d)
+	}
+	return nil
+}
+
+// FileSizeLimit returns the maximum upload size in bytes: MaxFileSize, or
+// zero if SkipValidation is set.
+func (r *TranscriptionRequest) FileSizeLimit() int64 {
+	if r.SkipValidation {
+		return 0
+	}
+	return MaxFileSize
+}
+
+// NewFileTooLargeError creates the validation error returned for files
+// larger than limit bytes.
+func NewFileTooLargeError(size, limit int64) *errors.ValidationError {
+	return errors.NewValidationError("file",
+		fmt.Sprintf("exceeds the maximum size of %d bytes", limit), size)
+}
+
+// reopener is implemented by readers that can produce their content again
+// from the start, such as those of zai.NewReplayableReader.
+type reopener interface {
+	Reopen() (io.ReadCloser, error)
+}
+
+// peek returns up to n bytes from the start of File without consuming
+// them: a reopenable file is read from a new reader, a seekable file is
+// rewound and other files are replaced by the bytes read followed by the
+// rest.
+func (r *TranscriptionRequest) peek(n int) ([]byte, error) {
+	switch file := r.File.(type) {
+	case reopener:
+		content, err := file.Reopen()
+		if err != nil {
+			return nil, fmt.Errorf("audio: failed to open file: %w", err)
+		}
+		defer content.Close()
+		return readPrefix(content, n)
+
+	case io.ReadSeeker:
+		start, err := file.Seek(0, io.SeekCurrent)
+		if err == nil {
+			header, readErr := readPrefix(file, n)
+			if _, err := file.Seek(start, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("audio: failed to rewind file: %w", err)
+			}
+			return header, readErr
+		}
+	}
+
+	header, err := readPrefix(r.File, n)
+	r.File = io.MultiReader(bytes.NewReader(header), r.File)
+	return header, err
+}
+
+// readPrefix reads up to n bytes from r.
+func readPrefix(r io.Reader, n int) ([]byte, error) {
+	header, err := io.ReadAll(io.LimitReader(r, int64(n)))
+	if err != nil {
+		return nil, fmt.Errorf("audio: failed to read file: %w", err)
+	}
+	return header, nil
+}
+
+// DetectFormat returns the format of an audio file from the first bytes
+// of its content, or an empty string if it is not a supported format.
+func DetectFormat(header []byte) FileFormat {
+	switch {
+	case len(header) >= 12 && string(header[:4]) == "RIFF" && string(header[8:12]) == "WAVE":
+		return FileFormatWAV
+	case bytes.HasPrefix(header, []byte("fLaC")):
+		return FileFormatFLAC
+	case bytes.HasPrefix(header, []byte("OggS")):
+		return FileFormatOGG
+	case len(header) >= 8 && string(header[4:8]) == "ftyp":
+		return FileFormatM4A
+	case bytes.HasPrefix(header, []byte{0x1A, 0x45, 0xDF, 0xA3}):
+		// Matroska files other than WebM share the EBML signature
+		if bytes.Contains(header[:min(len(header), 64)], []byte("webm")) {
+			return FileFormatWebM
+		}
+		return ""
+	case bytes.HasPrefix(header, []byte("ID3")):
+		return FileFormatMP3
+	}
+	if _, ok := parseMP3Frame(header); ok {
+		return FileFormatMP3
+	}
+	return ""
+}
+
+// EstimateDuration returns the duration of an audio file of format from
+// the first bytes of its content, and false if its headers do not tell:
+// only WAV files and MP3 files with a Xing or Info header are supported.
+func EstimateDuration(format FileFormat, header []byte) (time.Duration, bool) {
+	switch format {
+	case FileFormatWAV:
+		return wavDuration(header)
+	case FileFormatMP3:
+		return mp3Duration(header)
+	}
+	return 0, false
+}
+
+// wavDuration returns the duration of a WAV file from the byte rate of its
+// fmt chunk and the size of its data chunk.
+func wavDuration(header []byte) (time.Duration, bool) {
+	var byteRate uint64
+	for off := int64(12); off+8 <= int64(len(header)); {
+		id := string(header[off : off+4])
+		size := int64(binary.LittleEndian.Uint32(header[off+4 : off+8]))
+		body := off + 8
+
+		switch id {
+		case "fmt ":
+			if body+12 > int64(len(header)) {
+				return 0, false
+			}
+			byteRate = uint64(binary.LittleEndian.Uint32(header[body+8 : body+12]))
+		case "data":
+			// Streamed WAV files leave the size unset
+			if byteRate == 0 || size == 0 || size == 0xFFFFFFFF {
+				return 0, false
+			}
+			return time.Duration(uint64(size) * uint64(time.Second) / byteRate), true
+		}
+
+		// Chunks are padded to an even size
+		off = body + size + size&1
+	}
+	return 0, false
+}
+
+// mp3Frame is the header of an MPEG audio frame.
+type mp3Frame struct {
+	sampleRate int
+	samples    int // per frame
+	sideInfo   int // bytes between the header and a Xing header
+}
+
+// mp3SampleRates holds the sample rates by MPEG version (2.5, reserved,
+// 2, 1) and sample rate index.
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},
+	{},
+	{22050, 24000, 16000},
+	{44100, 48000, 32000},
+}
+
+// parseMP3Frame parses the MPEG audio frame header at the start of data.
+func parseMP3Frame(data []byte) (mp3Frame, bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+	version := data[1] >> 3 & 3
+	layer := data[1] >> 1 & 3
+	rateIndex := data[2] >> 2 & 3
+	bitrateIndex := data[2] >> 4
+	// Layer 0 is ADTS AAC, which shares the sync word
+	if version == 1 || layer == 0 || rateIndex == 3 || bitrateIndex == 15 {
+		return mp3Frame{}, false
+	}
+
+	frame := mp3Frame{sampleRate: mp3SampleRates[version][rateIndex]}
+	mono := data[3]>>6 == 3
+	switch {
+	case layer == 3: // Layer I
+		frame.samples = 384
+	case layer == 2 || version == 3: // Layer II, or MPEG-1 Layer III
+		frame.samples = 1152
+	default:
+		frame.samples = 576
+	}
+	switch {
+	case version == 3 && mono:
+		frame.sideInfo = 17
+	case version == 3:
+		frame.sideInfo = 32
+	case mono:
+		frame.sideInfo = 9
+	default:
+		frame.sideInfo = 17
+	}
+	return frame, true
+}
+
+// mp3Duration returns the duration of an MP3 file from the frame count of
+// the Xing or Info header in its first frame, after any ID3v2 tag.
+func mp3Duration(header []byte) (time.Duration, bool) {
+	off := 0
+	if len(header) >= 10 && string(header[:3]) == "ID3" {
+		// The tag size is a 28-bit syncsafe integer
+		size := int(header[6]&0x7F)<<21 | int(header[7]&0x7F)<<14 | int(header[8]&0x7F)<<7 | int(header[9]&0x7F)
+		off = 10 + size
+		if header[5]&0x10 != 0 {
+			off += 10 // footer
+		}
+	}
+	if off >= len(header) {
+		return 0, false
+	}
+
+	frame, ok := parseMP3Frame(header[off:])
+	if !ok {
+		return 0, false
+	}
+	xing := off + 4 + frame.sideInfo
+	if xing+12 > len(header) {
+		return 0, false
+	}
+	if tag := string(header[xing : xing+4]); tag != "Xing" && tag != "Info" {
+		return 0, false
+	}
+	// The frame count is present if the first flag is set
+	if binary.BigEndian.Uint32(header[xing+4:xing+8])&1 == 0 {
+		return 0, false
+	}
+	frames := binary.BigEndian.Uint32(header[xing+8 : xing+12])
+	if frames == 0 {
+		return 0, false
+	}
+	seconds := float64(frames) * float64(frame.samples) / float64(frame.sampleRate)
+	return time.Duration(seconds * float64(time.Second)), true
+}
+
+// formatList returns the supported formats as a comma-separated list.
+func formatList() string {
+	names := make([]string, len(supportedFormats))
+	for i, format := range supportedFormats {
+		names[i] = string(format)
+	}
+	return strings.Join(names, ", ")
+}
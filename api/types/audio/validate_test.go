@@ -0,0 +1,245 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// onlyReader hides every method of r except Read.
+type onlyReader struct {
+	r io.Reader
+}
+
+func (o onlyReader) Read(p []byte) (int, error) {
+	return o.r.Read(p)
+}
+
+func TestDetectFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		golden string
+		want   FileFormat
+	}{
+		{"speech.mp3", FileFormatMP3},
+		{"speech.wav", FileFormatWAV},
+		{"speech.m4a", FileFormatM4A},
+		{"speech.flac", FileFormatFLAC},
+		{"speech.ogg", FileFormatOGG},
+		{"speech.webm", FileFormatWebM},
+		{"mislabeled.mp3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, DetectFormat(golden.Read(t, tt.golden)))
+		})
+	}
+
+	t.Run("mp3 without a tag", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, FileFormatMP3, DetectFormat([]byte{0xFF, 0xFB, 0x90, 0x44}))
+	})
+
+	t.Run("lookalikes", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, DetectFormat(nil))
+		assert.Empty(t, DetectFormat([]byte{0xFF, 0xF1, 0x50, 0x80}), "ADTS AAC")
+		assert.Empty(t, DetectFormat([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x84, 0x42, 0x82, 0x88, 'm', 'a', 't', 'r', 'o', 's', 'k', 'a'}), "Matroska")
+		assert.Empty(t, DetectFormat([]byte("RIFF\x00\x00\x00\x00AVI ")))
+	})
+}
+
+func TestEstimateDuration(t *testing.T) {
+	t.Parallel()
+
+	d, ok := EstimateDuration(FileFormatWAV, golden.Read(t, "speech.wav"))
+	require.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, d)
+
+	d, ok = EstimateDuration(FileFormatMP3, golden.Read(t, "speech.mp3"))
+	require.True(t, ok)
+	// 100 MPEG-1 Layer III frames of 1152 samples at 44.1 kHz
+	assert.InDelta(t, 100*1152/44100.0, d.Seconds(), 1e-6)
+
+	// Without the Xing header the duration is unknown
+	mp3 := golden.Read(t, "speech.mp3")
+	copy(mp3[bytes.Index(mp3, []byte("Xing")):], "xxxx")
+	_, ok = EstimateDuration(FileFormatMP3, mp3)
+	assert.False(t, ok)
+
+	// A streamed WAV file has no data size
+	wav := golden.Read(t, "speech.wav")
+	binary.LittleEndian.PutUint32(wav[bytes.Index(wav, []byte("data"))+4:], 0xFFFFFFFF)
+	_, ok = EstimateDuration(FileFormatWAV, wav)
+	assert.False(t, ok)
+
+	_, ok = EstimateDuration(FileFormatFLAC, golden.Read(t, "speech.flac"))
+	assert.False(t, ok)
+}
+
+// longWAV returns the header of speech.wav with a data chunk of d at its
+// byte rate of 8000 bytes per second, without the samples.
+func longWAV(t *testing.T, d time.Duration) []byte {
+	t.Helper()
+
+	wav := golden.Read(t, "speech.wav")
+	data := bytes.Index(wav, []byte("data"))
+	binary.LittleEndian.PutUint32(wav[data+4:], uint32(d.Seconds()*8000))
+	return wav[:data+8]
+}
+
+// longMP3 returns speech.mp3 with a Xing header announcing frames frames.
+func longMP3(t *testing.T, frames uint32) []byte {
+	t.Helper()
+
+	mp3 := golden.Read(t, "speech.mp3")
+	binary.BigEndian.PutUint32(mp3[bytes.Index(mp3, []byte("Xing"))+8:], frames)
+	return mp3
+}
+
+func TestTranscriptionRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"speech.mp3", "speech.wav", "speech.m4a", "speech.flac", "speech.ogg", "speech.webm"} {
+		req := NewTranscriptionRequest(bytes.NewReader(golden.Read(t, name)), name, ModelWhisper1)
+		assert.NoError(t, req.Validate(), name)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		setup   func(*TranscriptionRequest)
+		wantErr string
+	}{
+		{
+			name:    "mislabeled file",
+			content: golden.Read(t, "mislabeled.mp3"),
+			wantErr: "is not a supported audio file: detected application/pdf, want one of mp3, wav, m4a, flac, ogg, webm",
+		},
+		{
+			name:    "text file",
+			content: []byte("simulated audio content"),
+			wantErr: "detected text/plain; charset=utf-8",
+		},
+		{
+			name:    "empty file",
+			content: nil,
+			wantErr: "detected empty file",
+		},
+		{
+			name:    "long wav",
+			content: longWAV(t, 40*time.Minute),
+			wantErr: "is a wav file of about 40m0s, over the maximum duration of 30s",
+		},
+		{
+			name:    "long mp3",
+			content: longMP3(t, 2000), // 52s
+			wantErr: "is a mp3 file of about 52s, over the maximum duration of 30s",
+		},
+		{
+			name:    "long wav within a custom maximum",
+			content: longWAV(t, 40*time.Minute),
+			setup:   func(r *TranscriptionRequest) { r.SetMaxDuration(time.Hour) },
+		},
+		{
+			name:    "over a custom maximum",
+			content: golden.Read(t, "speech.mp3"),
+			setup:   func(r *TranscriptionRequest) { r.SetMaxDuration(time.Second) },
+			wantErr: "over the maximum duration of 1s",
+		},
+		{
+			name:    "duration check disabled",
+			content: longWAV(t, 40*time.Minute),
+			setup:   func(r *TranscriptionRequest) { r.SetMaxDuration(-1) },
+		},
+		{
+			name:    "validation skipped",
+			content: golden.Read(t, "mislabeled.mp3"),
+			setup:   func(r *TranscriptionRequest) { r.SetSkipValidation(true) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewTranscriptionRequest(bytes.NewReader(tt.content), "podcast.mp3", ModelWhisper1)
+			if tt.setup != nil {
+				tt.setup(req)
+			}
+
+			err := req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "file", validationErr.Field)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("required fields", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorContains(t, NewTranscriptionRequest(nil, "a.mp3", ModelWhisper1).Validate(), "file")
+		assert.ErrorContains(t, NewTranscriptionRequest(strings.NewReader(""), "a.mp3", "").SetSkipValidation(true).Validate(), "model")
+	})
+}
+
+func TestTranscriptionRequest_ValidateKeepsContent(t *testing.T) {
+	t.Parallel()
+
+	content := golden.Read(t, "speech.wav")
+
+	t.Run("seekable", func(t *testing.T) {
+		t.Parallel()
+
+		file := bytes.NewReader(content)
+		_, err := file.Seek(0, io.SeekStart)
+		require.NoError(t, err)
+
+		req := NewTranscriptionRequest(file, "speech.wav", ModelWhisper1)
+		require.NoError(t, req.Validate())
+		assert.Same(t, file, req.File, "a seekable file is rewound, not replaced")
+
+		got, err := io.ReadAll(req.File)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+	})
+
+	t.Run("not seekable", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewTranscriptionRequest(onlyReader{bytes.NewReader(content)}, "speech.wav", ModelWhisper1)
+		require.NoError(t, req.Validate())
+
+		got, err := io.ReadAll(req.File)
+		require.NoError(t, err)
+		assert.Equal(t, content, got)
+	})
+}
+
+func TestTranscriptionRequest_FileSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	req := NewTranscriptionRequest(strings.NewReader(""), "a.mp3", ModelWhisper1)
+	assert.Equal(t, MaxFileSize, req.FileSizeLimit())
+	assert.Zero(t, req.SetSkipValidation(true).FileSizeLimit())
+}
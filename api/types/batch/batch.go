@@ -1,6 +1,14 @@
 // Package batch provides types for the Batch API.
 package batch
 
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
 // BatchError represents an error that occurred during batch processing.
 type BatchError struct {
 	// Code is the defined business error code
@@ -115,6 +123,10 @@ const (
 	StatusCancelled   = "cancelled"
 )
 
+// MetadataOriginalBatchID is the metadata key linking a batch created by
+// BatchService.RetryFailed to the batch whose failed requests it retries.
+const MetadataOriginalBatchID = "original_batch_id"
+
 // Endpoint constants
 const (
 	EndpointChatCompletions = "/v1/chat/completions"
@@ -190,6 +202,10 @@ type BatchCreateRequest struct {
 
 	// AutoDeleteInputFile indicates whether to automatically delete the input file after processing
 	AutoDeleteInputFile bool `json:"auto_delete_input_file,omitempty"`
+
+	// CallbackURL is the URL the API notifies when the batch completes,
+	// instead of being polled. See SetCallbackURL.
+	CallbackURL string `json:"callback_url,omitempty"`
 }
 
 // NewBatchCreateRequest creates a new batch create request.
@@ -222,6 +238,76 @@ func (r *BatchCreateRequest) SetAutoDeleteInputFile(autoDelete bool) *BatchCreat
 	return r
 }
 
+// SetCallbackURL sets the URL the API notifies with a signed
+// BatchCompleted event when the batch completes, so the batch need not be
+// polled. Verify and decode the notification with webhooks.ParseEvent.
+//
+// Example:
+//
+//	req.SetCallbackURL("https://example.com/hooks/zai")
+func (r *BatchCreateRequest) SetCallbackURL(callbackURL string) *BatchCreateRequest {
+	r.CallbackURL = callbackURL
+	return r
+}
+
+// Validate checks the request before it is sent.
+func (r *BatchCreateRequest) Validate() error {
+	if r.CallbackURL != "" {
+		u, err := url.Parse(r.CallbackURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.NewValidationError("callback_url", "must be an absolute http or https URL", r.CallbackURL)
+		}
+	}
+	return nil
+}
+
+// BatchListParams holds the query parameters for listing batches. Unset
+// fields are omitted, and the filters are applied by the server.
+type BatchListParams struct {
+	// After is the cursor: the ID of the last batch of the previous page.
+	After string
+
+	// Limit is the maximum number of batches per page. Zero uses the API default.
+	Limit int
+
+	// Status lists only batches with this status, e.g. StatusCompleted.
+	Status string
+
+	// CreatedAfter lists only batches created at or after this time.
+	CreatedAfter time.Time
+
+	// CreatedBefore lists only batches created before this time.
+	CreatedBefore time.Time
+
+	// Metadata lists only batches whose metadata has all of these key/value pairs.
+	Metadata map[string]string
+}
+
+// Query returns the parameters as URL query values. Times are Unix
+// seconds, and each metadata pair is sent as metadata[key]=value.
+func (p *BatchListParams) Query() map[string]string {
+	query := make(map[string]string)
+	if p.After != "" {
+		query["after"] = p.After
+	}
+	if p.Limit > 0 {
+		query["limit"] = strconv.Itoa(p.Limit)
+	}
+	if p.Status != "" {
+		query["status"] = p.Status
+	}
+	if !p.CreatedAfter.IsZero() {
+		query["created_after"] = strconv.FormatInt(p.CreatedAfter.Unix(), 10)
+	}
+	if !p.CreatedBefore.IsZero() {
+		query["created_before"] = strconv.FormatInt(p.CreatedBefore.Unix(), 10)
+	}
+	for key, value := range p.Metadata {
+		query["metadata["+key+"]"] = value
+	}
+	return query
+}
+
 // BatchListResponse represents the response from listing batches.
 type BatchListResponse struct {
 	// Data is the list of batch objects
@@ -238,6 +324,10 @@ type BatchListResponse struct {
 
 	// HasMore indicates whether there are more batches available
 	HasMore bool `json:"has_more"`
+
+	// Total is the number of batches matching the filters over all pages,
+	// or nil if the server does not return it
+	Total *int `json:"total,omitempty"`
 }
 
 // GetBatches returns the list of batches.
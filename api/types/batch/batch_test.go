@@ -2,10 +2,14 @@ package batch
 
 import (
 	"encoding/json"
+	"maps"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestBatch_StatusMethods(t *testing.T) {
@@ -311,6 +315,26 @@ func TestBatchCreateRequest(t *testing.T) {
 		assert.Equal(t, req.AutoDeleteInputFile, decoded.AutoDeleteInputFile)
 		assert.Equal(t, req.Metadata, decoded.Metadata)
 	})
+
+	t.Run("callback URL", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewBatchCreateRequest("24h", EndpointChatCompletions, "file_789")
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "callback_url")
+
+		req.SetCallbackURL("https://example.com/hooks/zai")
+		require.NoError(t, req.Validate())
+		data, err = json.Marshal(req)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"callback_url":"https://example.com/hooks/zai"`)
+
+		req.SetCallbackURL("example.com/hooks/zai")
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, req.Validate(), &validationErr)
+		assert.Equal(t, "callback_url", validationErr.Field)
+	})
 }
 
 func TestBatchListResponse(t *testing.T) {
@@ -364,6 +388,16 @@ func TestBatchListResponse(t *testing.T) {
 		assert.Equal(t, StatusInProgress, batches[1].Status)
 
 		assert.True(t, listResp.HasMoreBatches())
+		assert.Nil(t, listResp.Total, "total is not returned")
+	})
+
+	t.Run("total count", func(t *testing.T) {
+		t.Parallel()
+
+		var listResp BatchListResponse
+		require.NoError(t, json.Unmarshal([]byte(`{"object": "list", "data": [], "has_more": false, "total": 0}`), &listResp))
+		require.NotNil(t, listResp.Total)
+		assert.Equal(t, 0, *listResp.Total)
 	})
 
 	t.Run("empty list response", func(t *testing.T) {
@@ -386,6 +420,43 @@ func TestBatchListResponse(t *testing.T) {
 	})
 }
 
+func TestBatchListParams_Query(t *testing.T) {
+	t.Parallel()
+
+	createdAfter := time.Unix(1700000000, 0)
+	createdBefore := time.Unix(1700086400, 0)
+	filters := []struct {
+		set   func(*BatchListParams)
+		query map[string]string
+	}{
+		{func(p *BatchListParams) { p.After = "batch_9" }, map[string]string{"after": "batch_9"}},
+		{func(p *BatchListParams) { p.Limit = 50 }, map[string]string{"limit": "50"}},
+		{func(p *BatchListParams) { p.Status = StatusFailed }, map[string]string{"status": "failed"}},
+		{func(p *BatchListParams) { p.CreatedAfter = createdAfter }, map[string]string{"created_after": "1700000000"}},
+		{func(p *BatchListParams) { p.CreatedBefore = createdBefore }, map[string]string{"created_before": "1700086400"}},
+		{
+			func(p *BatchListParams) { p.Metadata = map[string]string{"user_id": "user_123", "project": "reports"} },
+			map[string]string{"metadata[user_id]": "user_123", "metadata[project]": "reports"},
+		},
+	}
+
+	// Every combination of filters sets exactly the query of its filters
+	for mask := 0; mask < 1<<len(filters); mask++ {
+		params := &BatchListParams{}
+		want := map[string]string{}
+		for i, filter := range filters {
+			if mask&(1<<i) != 0 {
+				filter.set(params)
+				maps.Copy(want, filter.query)
+			}
+		}
+		assert.Equal(t, want, params.Query(), "filters %06b", mask)
+	}
+
+	// Zero values are omitted
+	assert.Empty(t, (&BatchListParams{Limit: -1, Metadata: map[string]string{}}).Query())
+}
+
 func TestBatchRequestCounts(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,154 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+// RequestLine is a line of a batch input file: one request to send to the
+// endpoint of the batch.
+type RequestLine struct {
+	// CustomID identifies the request in the results. It must be unique
+	// within the file.
+	CustomID string `json:"custom_id"`
+
+	// Method is the HTTP method, always "POST"
+	Method string `json:"method"`
+
+	// URL is the endpoint, such as EndpointChatCompletions
+	URL string `json:"url"`
+
+	// Body is the request body
+	Body any `json:"body"`
+}
+
+// InputWriter writes a batch input file in JSONL format, one request per
+// line, without holding the file in memory. All requests of a batch go to
+// the same endpoint.
+//
+// Example:
+//
+//	var buf bytes.Buffer
+//	w := batch.NewInputWriter(&buf)
+//	for i, text := range texts {
+//	    if err := w.AddEmbedding(fmt.Sprintf("doc-%d", i), embeddings.NewEmbeddingRequest("embedding-3", text)); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//	if err := w.Flush(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	file, err := client.Files.Upload(ctx, files.NewFileUploadRequest(bytes.NewReader(buf.Bytes()), "input.jsonl", files.PurposeBatch))
+//	req := batch.NewBatchCreateRequest("24h", w.Endpoint(), file.ID)
+type InputWriter struct {
+	w        *bufio.Writer
+	enc      *json.Encoder
+	endpoint string
+	ids      map[string]struct{}
+}
+
+// NewInputWriter creates a writer of a batch input file to w. Call Flush
+// after the last request.
+func NewInputWriter(w io.Writer) *InputWriter {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+	return &InputWriter{w: bw, enc: enc, ids: make(map[string]struct{})}
+}
+
+// AddChatCompletion adds a chat completion request.
+func (w *InputWriter) AddChatCompletion(customID string, req *chat.ChatCompletionRequest) error {
+	return w.Add(customID, EndpointChatCompletions, req)
+}
+
+// AddEmbedding adds an embeddings request.
+func (w *InputWriter) AddEmbedding(customID string, req *embeddings.EmbeddingRequest) error {
+	return w.Add(customID, EndpointEmbeddings, req)
+}
+
+// Add adds a request with the given body to endpoint. It fails if the
+// custom ID is empty or was already added, or if endpoint differs from the
+// endpoint of the requests added before.
+func (w *InputWriter) Add(customID, endpoint string, body any) error {
+	if customID == "" {
+		return fmt.Errorf("batch: custom ID is required")
+	}
+	if _, ok := w.ids[customID]; ok {
+		return fmt.Errorf("batch: duplicate custom ID %q", customID)
+	}
+	if w.endpoint != "" && endpoint != w.endpoint {
+		return fmt.Errorf("batch: request %q is for %s, but the batch is for %s", customID, endpoint, w.endpoint)
+	}
+
+	line := RequestLine{CustomID: customID, Method: http.MethodPost, URL: endpoint, Body: body}
+	if err := w.enc.Encode(line); err != nil {
+		return fmt.Errorf("batch: failed to write request %q: %w", customID, err)
+	}
+	w.endpoint = endpoint
+	w.ids[customID] = struct{}{}
+	return nil
+}
+
+// Endpoint returns the endpoint of the requests added, or "" if none was.
+func (w *InputWriter) Endpoint() string {
+	return w.endpoint
+}
+
+// Len returns the number of requests added.
+func (w *InputWriter) Len() int {
+	return len(w.ids)
+}
+
+// Flush writes any buffered data to the underlying writer.
+func (w *InputWriter) Flush() error {
+	return w.w.Flush()
+}
+
+// FilterInput copies to w the lines of the batch input file read from r
+// whose custom_id is in customIDs, unchanged, and returns the number of
+// requests copied. A custom ID repeated in the input is copied once. The
+// input is read line by line.
+//
+// Example:
+//
+//	var retry bytes.Buffer
+//	n, err := batch.FilterInput(&retry, input, map[string]bool{"req-7": true, "req-42": true})
+func FilterInput(w io.Writer, r io.Reader, customIDs map[string]bool) (int, error) {
+	reader := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	copied := make(map[string]bool)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return len(copied), fmt.Errorf("batch: failed to read input: %w", err)
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 {
+			var request struct {
+				CustomID string `json:"custom_id"`
+			}
+			if jsonErr := json.Unmarshal(trimmed, &request); jsonErr != nil {
+				return len(copied), fmt.Errorf("batch: invalid input line %d: %w", line, jsonErr)
+			}
+			if customIDs[request.CustomID] && !copied[request.CustomID] {
+				bw.Write(trimmed)
+				if writeErr := bw.WriteByte('\n'); writeErr != nil {
+					return len(copied), fmt.Errorf("batch: failed to write request %q: %w", request.CustomID, writeErr)
+				}
+				copied[request.CustomID] = true
+			}
+		}
+
+		if err == io.EOF {
+			return len(copied), bw.Flush()
+		}
+	}
+}
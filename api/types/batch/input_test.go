@@ -0,0 +1,82 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+func TestInputWriter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewInputWriter(&buf)
+	assert.Empty(t, w.Endpoint())
+
+	require.NoError(t, w.AddEmbedding("doc-1", embeddings.NewEmbeddingRequest("embedding-3", "fish")))
+	require.NoError(t, w.AddEmbedding("doc-2", embeddings.NewEmbeddingRequest("embedding-3", "chips")))
+	require.NoError(t, w.Flush())
+	assert.Equal(t, 2, w.Len())
+	assert.Equal(t, EndpointEmbeddings, w.Endpoint())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.JSONEq(t, `{
+		"custom_id": "doc-1",
+		"method": "POST",
+		"url": "/v1/embeddings",
+		"body": {"model": "embedding-3", "input": "fish"}
+	}`, lines[0])
+	assert.Contains(t, lines[0], "fish", "HTML is not escaped")
+
+	var line RequestLine
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &line))
+	assert.Equal(t, "doc-2", line.CustomID)
+}
+
+func TestInputWriter_Errors(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := NewInputWriter(&buf)
+	require.NoError(t, w.AddChatCompletion("q-1", chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hi")})))
+
+	assert.ErrorContains(t, w.AddEmbedding("", embeddings.NewEmbeddingRequest("embedding-3", "a")), "custom ID is required")
+	assert.ErrorContains(t, w.AddChatCompletion("q-1", chat.NewChatCompletionRequest("glm-4.7", nil)), `duplicate custom ID "q-1"`)
+	assert.ErrorContains(t, w.AddEmbedding("e-1", embeddings.NewEmbeddingRequest("embedding-3", "a")), "the batch is for /v1/chat/completions")
+	assert.ErrorContains(t, w.Add("bad", EndpointChatCompletions, func() {}), `failed to write request "bad"`)
+
+	require.NoError(t, w.Flush())
+	assert.Equal(t, 1, w.Len())
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
+
+func TestFilterInput(t *testing.T) {
+	t.Parallel()
+
+	input := `{"custom_id":"req-1","method":"POST","url":"/v1/embeddings","body":{"input":"a"}}
+{"custom_id":"req-2","method":"POST","url":"/v1/embeddings","body":{"input":"b"}}
+
+{"custom_id":"req-3","method":"POST","url":"/v1/embeddings","body":{"input":"c"}}
+{"custom_id":"req-3","method":"POST","url":"/v1/embeddings","body":{"input":"c"}}
+{"custom_id":"req-4","method":"POST","url":"/v1/embeddings","body":{"input":"d"}}`
+
+	var buf bytes.Buffer
+	n, err := FilterInput(&buf, strings.NewReader(input), map[string]bool{"req-2": true, "req-3": true, "req-4": true, "req-9": true})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n, "req-3 is copied once and req-9 is missing")
+
+	lines := strings.Split(input, "\n")
+	assert.Equal(t, lines[1]+"\n"+lines[3]+"\n"+lines[5]+"\n", buf.String())
+
+	_, err = FilterInput(io.Discard, strings.NewReader(lines[0]+"\n{not json\n"), map[string]bool{})
+	assert.ErrorContains(t, err, "invalid input line 2")
+}
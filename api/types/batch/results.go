@@ -0,0 +1,289 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+// NotCompletedError is returned when the results of a batch that has not
+// completed are requested.
+type NotCompletedError struct {
+	// BatchID is the batch identifier.
+	BatchID string
+
+	// Status is the status of the batch.
+	Status string
+}
+
+// Error implements the error interface.
+func (e *NotCompletedError) Error() string {
+	return fmt.Sprintf("batch: %s is %s, not completed", e.BatchID, e.Status)
+}
+
+// NotTerminalError is returned when a batch that is still running is
+// used where a finished batch is required, such as by RetryFailed.
+type NotTerminalError struct {
+	// BatchID is the batch identifier.
+	BatchID string
+
+	// Status is the status of the batch.
+	Status string
+}
+
+// Error implements the error interface.
+func (e *NotTerminalError) Error() string {
+	return fmt.Sprintf("batch: %s is %s, not finished", e.BatchID, e.Status)
+}
+
+// NoFailedRequestsError is returned when the failed requests of a batch
+// are retried but its error file is missing or empty.
+type NoFailedRequestsError struct {
+	// BatchID is the batch identifier.
+	BatchID string
+}
+
+// Error implements the error interface.
+func (e *NoFailedRequestsError) Error() string {
+	return fmt.Sprintf("batch: %s has no failed requests to retry", e.BatchID)
+}
+
+// ResultResponse is the HTTP response of one request of a batch.
+type ResultResponse struct {
+	// StatusCode is the HTTP status code of the request
+	StatusCode int `json:"status_code"`
+
+	// RequestID is the request identifier
+	RequestID string `json:"request_id,omitempty"`
+
+	// Body is the response body, such as a chat completion
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Result is the outcome of one request of a batch: a line of the output
+// or error file.
+type Result struct {
+	// ID is the identifier of the result line
+	ID string `json:"id,omitempty"`
+
+	// CustomID is the custom_id of the request in the input file
+	CustomID string `json:"custom_id"`
+
+	// Response is the response of the request, if it was sent
+	Response *ResultResponse `json:"response,omitempty"`
+
+	// Error is the error of the request, if it failed
+	Error *BatchError `json:"error,omitempty"`
+}
+
+// OK returns true if the request succeeded.
+func (r *Result) OK() bool {
+	return r.Error == nil && r.Response != nil &&
+		r.Response.StatusCode >= 200 && r.Response.StatusCode < 300
+}
+
+// Decode decodes the response body of a successful request into v.
+func (r *Result) Decode(v any) error {
+	if !r.OK() {
+		return fmt.Errorf("batch: request %s failed: %s", r.CustomID, r.errorMessage())
+	}
+	if err := json.Unmarshal(r.Response.Body, v); err != nil {
+		return fmt.Errorf("batch: failed to decode response of request %s: %w", r.CustomID, err)
+	}
+	return nil
+}
+
+// ChatCompletion decodes the response of a /v1/chat/completions request.
+func (r *Result) ChatCompletion() (*chat.ChatCompletionResponse, error) {
+	var resp chat.ChatCompletionResponse
+	if err := r.Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Embeddings decodes the response of a /v1/embeddings request.
+func (r *Result) Embeddings() (*embeddings.EmbeddingResponse, error) {
+	var resp embeddings.EmbeddingResponse
+	if err := r.Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// errorMessage describes why the request failed.
+func (r *Result) errorMessage() string {
+	switch {
+	case r.Error != nil && r.Error.Message != "":
+		return r.Error.Message
+	case r.Response != nil:
+		return fmt.Sprintf("status %d", r.Response.StatusCode)
+	default:
+		return "no response"
+	}
+}
+
+// normalize fills in Error for requests whose response is an HTTP error,
+// so that failed requests always have an Error.
+func (r *Result) normalize() {
+	if r.Error != nil || r.Response == nil || r.OK() {
+		return
+	}
+
+	var body struct {
+		Error *BatchError `json:"error"`
+	}
+	if json.Unmarshal(r.Response.Body, &body) == nil && body.Error != nil {
+		r.Error = body.Error
+		return
+	}
+	r.Error = &BatchError{Message: fmt.Sprintf("status %d", r.Response.StatusCode)}
+}
+
+// ParseResult parses a line of a batch output or error file.
+func ParseResult(line []byte) (*Result, error) {
+	var result Result
+	if err := json.Unmarshal(line, &result); err != nil {
+		return nil, fmt.Errorf("batch: invalid result line: %w", err)
+	}
+	result.normalize()
+	return &result, nil
+}
+
+// Results are the outcomes of the requests of a completed batch.
+type Results struct {
+	// Batch is the completed batch.
+	Batch *Batch
+
+	// ByCustomID maps the custom_id of each request to its outcome.
+	ByCustomID map[string]*Result
+}
+
+// Get returns the outcome of the request with the given custom_id.
+func (r *Results) Get(customID string) (*Result, bool) {
+	result, ok := r.ByCustomID[customID]
+	return result, ok
+}
+
+// Succeeded returns the successful requests, sorted by custom_id.
+func (r *Results) Succeeded() []*Result {
+	return r.filter(true)
+}
+
+// Failed returns the failed requests, sorted by custom_id.
+func (r *Results) Failed() []*Result {
+	return r.filter(false)
+}
+
+func (r *Results) filter(ok bool) []*Result {
+	var results []*Result
+	for _, result := range r.ByCustomID {
+		if result.OK() == ok {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].CustomID < results[j].CustomID
+	})
+	return results
+}
+
+// ResultIterator parses batch result files line by line, so that large
+// output files are never held in memory. Create one with
+// BatchService.ResultsIterator.
+type ResultIterator struct {
+	open    []func() (io.ReadCloser, error)
+	body    io.ReadCloser
+	reader  *bufio.Reader
+	line    int
+	current *Result
+	err     error
+}
+
+// NewResultIterator creates an iterator over the results in the files
+// returned by open, in order. Each file is opened when the previous one
+// is exhausted.
+func NewResultIterator(open ...func() (io.ReadCloser, error)) *ResultIterator {
+	return &ResultIterator{open: open}
+}
+
+// Next advances to the next result. It returns false when all files are
+// exhausted or an error occurs.
+func (it *ResultIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for {
+		if it.reader == nil {
+			if len(it.open) == 0 {
+				return false
+			}
+			body, err := it.open[0]()
+			it.open = it.open[1:]
+			if err != nil {
+				it.err = err
+				return false
+			}
+			it.body = body
+			it.reader = bufio.NewReader(body)
+			it.line = 0
+		}
+
+		data, err := it.reader.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			it.err = fmt.Errorf("batch: failed to read results: %w", err)
+			it.Close()
+			return false
+		}
+		if err == io.EOF {
+			it.closeBody()
+		}
+		if len(data) > 0 {
+			it.line++
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			result, parseErr := ParseResult(data)
+			if parseErr != nil {
+				it.err = fmt.Errorf("line %d: %w", it.line, parseErr)
+				it.Close()
+				return false
+			}
+			it.current = result
+			return true
+		}
+	}
+}
+
+// Current returns the current result.
+func (it *ResultIterator) Current() *Result {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ResultIterator) Err() error {
+	return it.err
+}
+
+// Close closes the file being read and skips the remaining files.
+func (it *ResultIterator) Close() error {
+	it.open = nil
+	return it.closeBody()
+}
+
+func (it *ResultIterator) closeBody() error {
+	it.reader = nil
+	if it.body == nil {
+		return nil
+	}
+	err := it.body.Close()
+	it.body = nil
+	return err
+}
@@ -0,0 +1,147 @@
+package batch
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseResult(t *testing.T) {
+	t.Parallel()
+
+	t.Run("embeddings success", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseResult([]byte(`{"id":"r1","custom_id":"emb-1","response":{"status_code":200,"body":{"object":"list","model":"embedding-3","data":[{"object":"embedding","index":0,"embedding":[0.5,-0.5]}]}}}`))
+		require.NoError(t, err)
+		assert.True(t, result.OK())
+		assert.Nil(t, result.Error)
+
+		resp, err := result.Embeddings()
+		require.NoError(t, err)
+		require.Len(t, resp.Data, 1)
+		assert.Equal(t, "embedding-3", resp.Model)
+	})
+
+	t.Run("HTTP error response", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseResult([]byte(`{"custom_id":"req-1","response":{"status_code":429,"body":{"error":{"code":"1302","message":"rate limited"}}}}`))
+		require.NoError(t, err)
+		assert.False(t, result.OK())
+		require.NotNil(t, result.Error)
+		assert.Equal(t, "1302", result.Error.Code)
+
+		_, err = result.ChatCompletion()
+		assert.EqualError(t, err, "batch: request req-1 failed: rate limited")
+	})
+
+	t.Run("HTTP error without error body", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := ParseResult([]byte(`{"custom_id":"req-1","response":{"status_code":500,"body":"oops"}}`))
+		require.NoError(t, err)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, "status 500", result.Error.Message)
+	})
+
+	t.Run("invalid line", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseResult([]byte(`[1,2]`))
+		assert.ErrorContains(t, err, "invalid result line")
+	})
+}
+
+// trackedReader records whether it was closed.
+type trackedReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *trackedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func opener(r io.ReadCloser) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) { return r, nil }
+}
+
+func TestResultIterator(t *testing.T) {
+	t.Parallel()
+
+	first := &trackedReader{Reader: strings.NewReader("{\"custom_id\":\"a\",\"response\":{\"status_code\":200}}\n\n{\"custom_id\":\"b\",\"response\":{\"status_code\":200}}")}
+	second := &trackedReader{Reader: strings.NewReader("{\"custom_id\":\"c\",\"error\":{\"message\":\"failed\"}}\n")}
+
+	iter := NewResultIterator(opener(first), opener(second))
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Current().CustomID)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.True(t, first.closed)
+	assert.True(t, second.closed)
+}
+
+func TestResultIterator_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid line", func(t *testing.T) {
+		t.Parallel()
+
+		body := &trackedReader{Reader: strings.NewReader("{\"custom_id\":\"a\"}\n\nnot json\n")}
+		next := &trackedReader{Reader: strings.NewReader("")}
+		iter := NewResultIterator(opener(body), opener(next))
+
+		assert.True(t, iter.Next())
+		assert.False(t, iter.Next())
+		assert.ErrorContains(t, iter.Err(), "line 3")
+		assert.True(t, body.closed)
+		assert.False(t, iter.Next())
+	})
+
+	t.Run("open error", func(t *testing.T) {
+		t.Parallel()
+
+		openErr := errors.New("download failed")
+		iter := NewResultIterator(func() (io.ReadCloser, error) { return nil, openErr })
+		assert.False(t, iter.Next())
+		assert.ErrorIs(t, iter.Err(), openErr)
+	})
+
+	t.Run("close skips remaining files", func(t *testing.T) {
+		t.Parallel()
+
+		body := &trackedReader{Reader: strings.NewReader("{\"custom_id\":\"a\"}\n{\"custom_id\":\"b\"}\n")}
+		iter := NewResultIterator(opener(body), func() (io.ReadCloser, error) {
+			t.Error("unexpected open")
+			return nil, nil
+		})
+
+		assert.True(t, iter.Next())
+		require.NoError(t, iter.Close())
+		assert.True(t, body.closed)
+		assert.False(t, iter.Next())
+	})
+}
+
+func TestResults_Filter(t *testing.T) {
+	t.Parallel()
+
+	ok := &Result{CustomID: "b", Response: &ResultResponse{StatusCode: 200}}
+	ok2 := &Result{CustomID: "a", Response: &ResultResponse{StatusCode: 200}}
+	bad := &Result{CustomID: "c", Error: &BatchError{Message: "failed"}}
+	results := &Results{ByCustomID: map[string]*Result{"a": ok2, "b": ok, "c": bad}}
+
+	assert.Equal(t, []*Result{ok2, ok}, results.Succeeded())
+	assert.Equal(t, []*Result{bad}, results.Failed())
+
+	_, found := results.Get("missing")
+	assert.False(t, found)
+}
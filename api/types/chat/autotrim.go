@@ -0,0 +1,128 @@
+package chat
+
+// CreateConfig holds settings for a chat completion created with
+// Chat.Create.
+type CreateConfig struct {
+	// AutoTrim trims the messages and sends the request once more when the
+	// prompt exceeds the model's context window, if set.
+	AutoTrim *AutoTrimConfig
+}
+
+// CreateOption configures a chat completion created with Chat.Create.
+type CreateOption func(*CreateConfig)
+
+// NewCreateConfig creates a create configuration with the given options
+// applied.
+func NewCreateConfig(opts ...CreateOption) *CreateConfig {
+	cfg := &CreateConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// AutoTrimConfig holds how messages are trimmed when the API rejects a
+// prompt that exceeds the model's context window.
+type AutoTrimConfig struct {
+	// Strategy selects the messages TruncateMessages drops. Leading system
+	// messages are always kept.
+	Strategy TruncationStrategy
+
+	// MaxPromptTokens is the token count the messages are trimmed to. Zero
+	// trims them to three quarters of their counted tokens.
+	MaxPromptTokens int
+
+	// Counter counts the tokens of the messages. Nil uses the offline
+	// estimate of tools.EstimateRequestTokens.
+	Counter TokenCounter
+
+	// OnTrim, if set, is called with the report before the trimmed request
+	// is sent.
+	OnTrim func(*TrimReport)
+}
+
+// AutoTrimOption configures the automatic trimming of a request.
+type AutoTrimOption func(*AutoTrimConfig)
+
+// WithTrimLimit sets the token count the messages are trimmed to, such as
+// the model's context window minus the tokens reserved for the reply.
+func WithTrimLimit(maxPromptTokens int) AutoTrimOption {
+	return func(c *AutoTrimConfig) {
+		c.MaxPromptTokens = maxPromptTokens
+	}
+}
+
+// WithTrimCounter sets the token counter used to trim the messages, such
+// as zai.ToolsService.Counter.
+func WithTrimCounter(counter TokenCounter) AutoTrimOption {
+	return func(c *AutoTrimConfig) {
+		c.Counter = counter
+	}
+}
+
+// WithTrimCallback sets a function called with the report whenever
+// messages are trimmed, for example to log it.
+func WithTrimCallback(fn func(*TrimReport)) AutoTrimOption {
+	return func(c *AutoTrimConfig) {
+		c.OnTrim = fn
+	}
+}
+
+// WithAutoTrim makes Chat.Create recover from a prompt that exceeds the
+// model's context window: when the API rejects the request with the
+// context length error code (errors.CodePromptTooLong), the messages are
+// trimmed with TruncateMessages and the request is sent once more. Tool
+// definitions and the other request fields are sent unchanged, and a tool
+// message is only dropped together with the assistant message that
+// requested it. The response of a trimmed request reports the trimming in
+// ChatCompletionResponse.Trimmed. The request and its memory are not
+// changed.
+//
+// If the messages cannot be trimmed below the limit, Chat.Create returns
+// the API error joined with a *TokenLimitError.
+//
+// Example:
+//
+//	resp, err := client.Chat.Create(ctx, req,
+//	    chat.WithAutoTrim(chat.TruncateOldest,
+//	        chat.WithTrimCallback(func(r *chat.TrimReport) {
+//	            log.Printf("dropped %d messages", r.Dropped())
+//	        }),
+//	    ),
+//	)
+func WithAutoTrim(strategy TruncationStrategy, opts ...AutoTrimOption) CreateOption {
+	return func(c *CreateConfig) {
+		config := &AutoTrimConfig{Strategy: strategy}
+		for _, opt := range opts {
+			opt(config)
+		}
+		c.AutoTrim = config
+	}
+}
+
+// TrimReport describes the messages dropped from a request that exceeded
+// the model's context window.
+type TrimReport struct {
+	// Strategy is the strategy the messages were trimmed with.
+	Strategy TruncationStrategy
+
+	// Messages is the number of messages of the rejected request.
+	Messages int
+
+	// Kept is the number of messages sent after trimming.
+	Kept int
+
+	// Tokens is the counted token count of the rejected messages.
+	Tokens int
+
+	// KeptTokens is the counted token count of the kept messages.
+	KeptTokens int
+
+	// MaxPromptTokens is the token count the messages were trimmed to.
+	MaxPromptTokens int
+}
+
+// Dropped returns the number of dropped messages.
+func (r *TrimReport) Dropped() int {
+	return r.Messages - r.Kept
+}
@@ -0,0 +1,98 @@
+package chat
+
+// RequestBuilder builds chat completion requests from a shared template.
+//
+// A builder is immutable: every With method returns a new builder and
+// leaves the receiver unchanged, and Build returns a deep copy of the
+// template. A builder can therefore be shared between goroutines, for
+// example by a worker pool, and extended per call without aliasing bugs.
+//
+// Example:
+//
+//	template := chat.NewRequestBuilder(chat.ModelGLM47).
+//	    WithSystem("You are a support assistant.").
+//	    WithTools(lookupOrderTool).
+//	    With(func(r *chat.ChatCompletionRequest) { r.SetTemperature(0.2) })
+//
+//	// In each worker
+//	req := template.Build(chat.NewUserMessage(ticket.Text))
+//	resp, err := client.Chat.Create(ctx, req)
+type RequestBuilder struct {
+	template *ChatCompletionRequest
+}
+
+// NewRequestBuilder creates a builder for requests to model.
+// model accepts a model constant or any string.
+func NewRequestBuilder[M ~string](model M) *RequestBuilder {
+	return &RequestBuilder{template: NewChatCompletionRequest(model, nil)}
+}
+
+// NewRequestBuilderFrom creates a builder whose template is a deep copy of
+// req. Later changes to req do not affect the builder.
+func NewRequestBuilderFrom(req *ChatCompletionRequest) *RequestBuilder {
+	return &RequestBuilder{template: req.Clone()}
+}
+
+// WithModel returns a builder that uses model.
+func (b *RequestBuilder) WithModel(model string) *RequestBuilder {
+	return b.With(func(r *ChatCompletionRequest) {
+		r.Model = model
+	})
+}
+
+// WithSystem returns a builder whose requests start with a system message
+// with content. An existing leading system message is replaced.
+func (b *RequestBuilder) WithSystem(content string) *RequestBuilder {
+	return b.With(func(r *ChatCompletionRequest) {
+		system := NewSystemMessage(content)
+		if len(r.Messages) > 0 && r.Messages[0].Role == RoleSystem {
+			r.Messages[0] = system
+			return
+		}
+		r.Messages = append([]Message{system}, r.Messages...)
+	})
+}
+
+// WithHistory returns a builder that appends messages, such as earlier
+// turns of a conversation, to the template. The messages are copied.
+func (b *RequestBuilder) WithHistory(messages ...Message) *RequestBuilder {
+	return b.With(func(r *ChatCompletionRequest) {
+		for _, m := range messages {
+			r.Messages = append(r.Messages, m.Clone())
+		}
+	})
+}
+
+// WithTools returns a builder that adds tools to the template. The tools,
+// including their parameter schemas, are copied.
+func (b *RequestBuilder) WithTools(tools ...Tool) *RequestBuilder {
+	return b.With(func(r *ChatCompletionRequest) {
+		for _, t := range tools {
+			r.Tools = append(r.Tools, t.Clone())
+		}
+	})
+}
+
+// With returns a builder whose template is changed by configure, which
+// receives a copy of the template and may use any request setter.
+//
+// Example:
+//
+//	b = b.With(func(r *chat.ChatCompletionRequest) {
+//	    r.SetMaxTokens(512).DisableThinking()
+//	})
+func (b *RequestBuilder) With(configure func(r *ChatCompletionRequest)) *RequestBuilder {
+	template := b.template.Clone()
+	configure(template)
+	return &RequestBuilder{template: template}
+}
+
+// Build returns a new request from the template with messages appended.
+// The request is a deep copy and may be changed freely.
+func (b *RequestBuilder) Build(messages ...Message) *ChatCompletionRequest {
+	req := b.template.Clone()
+	for _, m := range messages {
+		req.Messages = append(req.Messages, m.Clone())
+	}
+	return req
+}
@@ -0,0 +1,102 @@
+package chat
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	t.Parallel()
+
+	history := []Message{NewUserMessage("Hi"), NewAssistantMessage("Hello!")}
+	base := NewRequestBuilder(ModelGLM47).
+		WithSystem("You are helpful.").
+		WithHistory(history...).
+		WithTools(newWeatherTool())
+
+	req := base.Build(NewUserMessage("Weather in Paris?"))
+	assert.Equal(t, "glm-4.7", req.Model)
+	require.Len(t, req.Messages, 4)
+	assert.Equal(t, RoleSystem, req.Messages[0].Role)
+	assert.Equal(t, "Hello!", req.Messages[2].Content)
+	assert.Equal(t, "Weather in Paris?", req.Messages[3].Content)
+	require.Len(t, req.Tools, 1)
+
+	// With methods leave the receiver unchanged
+	other := base.WithModel("glm-4.6").WithSystem("You are terse.")
+	assert.Equal(t, "glm-4.7", base.Build().Model)
+	assert.Equal(t, "You are helpful.", base.Build().Messages[0].Content)
+	assert.Equal(t, "glm-4.6", other.Build().Model)
+	assert.Len(t, other.Build().Messages, 3, "the system message is replaced")
+	assert.Equal(t, "You are terse.", other.Build().Messages[0].Content)
+
+	// Built requests do not alias the template or the inputs
+	req.Messages[0].Content = "changed"
+	req.Tools[0].Function.Parameters.(map[string]interface{})["type"] = "array"
+	history[0].Content = "changed"
+	fresh := base.Build()
+	assert.Equal(t, "You are helpful.", fresh.Messages[0].Content)
+	assert.Equal(t, "Hi", fresh.Messages[1].Content)
+	assert.Equal(t, "object", fresh.Tools[0].Function.Parameters.(map[string]interface{})["type"])
+}
+
+func TestRequestBuilder_With(t *testing.T) {
+	t.Parallel()
+
+	base := NewRequestBuilder("glm-4.7")
+	tuned := base.With(func(r *ChatCompletionRequest) {
+		r.SetTemperature(0.2).DisableThinking()
+	})
+
+	assert.Nil(t, base.Build().Temperature)
+	req := tuned.Build()
+	require.NotNil(t, req.Temperature)
+	assert.Equal(t, 0.2, *req.Temperature)
+	assert.Equal(t, ThinkingTypeDisabled, req.Thinking.Type)
+
+	*req.Temperature = 1
+	assert.Equal(t, 0.2, *tuned.Build().Temperature)
+}
+
+func TestNewRequestBuilderFrom(t *testing.T) {
+	t.Parallel()
+
+	template := NewChatCompletionRequest(ModelGLM47, []Message{NewSystemMessage("Be brief.")}).SetMaxTokens(50)
+	b := NewRequestBuilderFrom(template)
+
+	template.AddUserMessage("later")
+	*template.MaxTokens = 1
+
+	req := b.Build()
+	assert.Len(t, req.Messages, 1)
+	assert.Equal(t, 50, *req.MaxTokens)
+}
+
+func TestRequestBuilder_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	base := NewRequestBuilder(ModelGLM47).
+		WithSystem("You are helpful.").
+		WithTools(newWeatherTool())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := base.Build(NewUserMessage("question"))
+			req.Messages[0].Content = "mutated"
+			req.Tools[0].Function.Parameters.(map[string]interface{})["type"] = "array"
+			req.SetTemperature(1)
+		}()
+	}
+	wg.Wait()
+
+	req := base.Build()
+	assert.Equal(t, "You are helpful.", req.Messages[0].Content)
+	assert.Equal(t, "object", req.Tools[0].Function.Parameters.(map[string]interface{})["type"])
+	assert.Nil(t, req.Temperature)
+}
@@ -0,0 +1,133 @@
+package chat
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// CacheControlType is the kind of server-side prompt cache of a message.
+type CacheControlType string
+
+const (
+	// CacheControlEphemeral caches the prompt prefix for a short time, so
+	// that later calls sharing the prefix reuse its KV cache.
+	CacheControlEphemeral CacheControlType = "ephemeral"
+)
+
+// CacheControl marks the end of a prompt prefix the server may cache. The
+// messages up to and including the marked one, together with the tools,
+// are cached; later calls with the same prefix report the reused tokens in
+// Usage.PromptTokensDetails.CachedTokens.
+type CacheControl struct {
+	// Type is the kind of cache.
+	Type CacheControlType `json:"type"`
+
+	// TTL is how long the server keeps the cache, as a duration such as
+	// "5m" or "1h". Empty uses the server default.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// NewCacheControl returns an ephemeral cache marker with the server's
+// default TTL.
+func NewCacheControl() *CacheControl {
+	return &CacheControl{Type: CacheControlEphemeral}
+}
+
+// SetTTL sets how long the server keeps the cache, in the largest whole
+// unit of hours, minutes or seconds, such as "1h" or "90s".
+func (c *CacheControl) SetTTL(ttl time.Duration) *CacheControl {
+	switch {
+	case ttl%time.Hour == 0:
+		c.TTL = fmt.Sprintf("%dh", ttl/time.Hour)
+	case ttl%time.Minute == 0:
+		c.TTL = fmt.Sprintf("%dm", ttl/time.Minute)
+	default:
+		c.TTL = fmt.Sprintf("%ds", (ttl+time.Second-1)/time.Second)
+	}
+	return c
+}
+
+// WithCacheControl returns a copy of the message marked as the end of a
+// cacheable prefix.
+//
+// Example:
+//
+//	system := chat.NewSystemMessage(longInstructions).WithCacheControl(chat.NewCacheControl())
+func (m Message) WithCacheControl(c *CacheControl) Message {
+	m.CacheControl = c
+	return m
+}
+
+// WithPrefixCaching marks the leading system messages of req, and with
+// them its tools, as a cacheable prefix by setting an ephemeral
+// CacheControl on the last of them. A request without leading system
+// messages is left unmarked. The messages slice is copied, so a history
+// shared with other requests is not changed.
+//
+// Example:
+//
+//	req := chat.WithPrefixCaching(chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{
+//	    chat.NewSystemMessage(longInstructions),
+//	    chat.NewUserMessage("Summarize the attached report."),
+//	}))
+//	resp, err := client.Chat.Create(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(resp.Usage.GetCachedTokens(), "prompt tokens served from cache")
+func WithPrefixCaching(req *ChatCompletionRequest) *ChatCompletionRequest {
+	last := -1
+	for i, msg := range req.Messages {
+		if msg.Role != RoleSystem {
+			break
+		}
+		last = i
+	}
+	if last < 0 {
+		return req
+	}
+
+	req.Messages = slices.Clone(req.Messages)
+	if req.Messages[last].CacheControl == nil {
+		req.Messages[last].CacheControl = NewCacheControl()
+	}
+	return req
+}
+
+// validateCacheControl checks that only messages of the shared prefix,
+// before the last user message, are marked as cacheable, and that the
+// markers are well formed.
+func (r *ChatCompletionRequest) validateCacheControl() error {
+	end := len(r.Messages) - 1
+	for i := len(r.Messages) - 1; i >= 0; i-- {
+		if r.Messages[i].Role == RoleUser {
+			end = i
+			break
+		}
+	}
+
+	for i, msg := range r.Messages {
+		c := msg.CacheControl
+		if c == nil {
+			continue
+		}
+		field := fmt.Sprintf("messages[%d].cache_control", i)
+		if i >= end {
+			return errors.NewValidationError(field,
+				"only messages of the shared prefix, before the last user message, can be cached", msg.Role)
+		}
+		if c.Type != CacheControlEphemeral {
+			return errors.NewValidationError(field,
+				fmt.Sprintf("type must be %q", CacheControlEphemeral), c.Type)
+		}
+		if c.TTL != "" {
+			if ttl, err := time.ParseDuration(c.TTL); err != nil || ttl <= 0 {
+				return errors.NewValidationError(field, "ttl must be a positive duration such as \"5m\"", c.TTL)
+			}
+		}
+	}
+	return nil
+}
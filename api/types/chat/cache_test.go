@@ -0,0 +1,126 @@
+package chat
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestWithPrefixCaching(t *testing.T) {
+	t.Parallel()
+
+	history := []Message{
+		NewSystemMessage("You are a support agent."),
+		NewSystemMessage("Product manual: ..."),
+		NewUserMessage("How do I reset my router?"),
+	}
+	req := WithPrefixCaching(NewChatCompletionRequest(ModelGLM47, history))
+
+	assert.Nil(t, req.Messages[0].CacheControl)
+	assert.Equal(t, NewCacheControl(), req.Messages[1].CacheControl, "the last system message is marked")
+	assert.Nil(t, req.Messages[2].CacheControl)
+	assert.Nil(t, history[1].CacheControl, "the caller's messages are not changed")
+	assert.NoError(t, req.Validate())
+
+	// An existing marker is kept
+	custom := NewCacheControl().SetTTL(time.Hour)
+	req = WithPrefixCaching(NewChatCompletionRequest(ModelGLM47, []Message{
+		NewSystemMessage("You are a support agent.").WithCacheControl(custom),
+		NewUserMessage("Hello"),
+	}))
+	assert.Same(t, custom, req.Messages[0].CacheControl)
+
+	// Without leading system messages nothing is marked
+	req = WithPrefixCaching(NewChatCompletionRequest(ModelGLM47, []Message{NewUserMessage("Hello")}))
+	assert.Nil(t, req.Messages[0].CacheControl)
+}
+
+func TestCacheControl_SetTTL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ttl  time.Duration
+		want string
+	}{
+		{time.Hour, "1h"},
+		{5 * time.Minute, "5m"},
+		{90 * time.Second, "90s"},
+		{1500 * time.Millisecond, "2s"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, NewCacheControl().SetTTL(tt.ttl).TTL)
+	}
+}
+
+func TestValidate_CacheControl(t *testing.T) {
+	t.Parallel()
+
+	marked := func(m Message) Message { return m.WithCacheControl(NewCacheControl()) }
+	tests := []struct {
+		name     string
+		messages []Message
+		field    string
+	}{
+		{
+			name:     "system prefix",
+			messages: []Message{marked(NewSystemMessage("Rules")), NewUserMessage("Hi")},
+		},
+		{
+			name: "earlier turn",
+			messages: []Message{
+				NewSystemMessage("Rules"),
+				marked(NewUserMessage("Hi")),
+				NewAssistantMessage("Hello!"),
+				NewUserMessage("How are you?"),
+			},
+		},
+		{
+			name:     "last user message",
+			messages: []Message{NewSystemMessage("Rules"), marked(NewUserMessage("Hi"))},
+			field:    "messages[1].cache_control",
+		},
+		{
+			name: "after the last user message",
+			messages: []Message{
+				NewSystemMessage("Rules"),
+				NewUserMessage("Hi"),
+				marked(NewAssistantMessage("Hello!")),
+			},
+			field: "messages[2].cache_control",
+		},
+		{
+			name:     "last message without user messages",
+			messages: []Message{NewSystemMessage("Rules"), marked(NewSystemMessage("More rules"))},
+			field:    "messages[1].cache_control",
+		},
+		{
+			name:     "unknown type",
+			messages: []Message{NewSystemMessage("Rules").WithCacheControl(&CacheControl{Type: "persistent"}), NewUserMessage("Hi")},
+			field:    "messages[0].cache_control",
+		},
+		{
+			name:     "bad ttl",
+			messages: []Message{NewSystemMessage("Rules").WithCacheControl(&CacheControl{Type: CacheControlEphemeral, TTL: "soon"}), NewUserMessage("Hi")},
+			field:    "messages[0].cache_control",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewChatCompletionRequest(ModelGLM47, tt.messages).Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package chat
+
+import (
+	"reflect"
+)
+
+// Clone returns a deep copy of the request. Messages, tools, pointer
+// parameters, the thinking configuration and nested values such as tool
+// parameter maps and multimodal content are copied, so the clone can be
+// changed without affecting r.
+//
+// Example:
+//
+//	next := req.Clone().
+//	    AddMessage(resp.GetFirstChoice().Message).
+//	    AddUserMessage("And then?")
+func (r *ChatCompletionRequest) Clone() *ChatCompletionRequest {
+	if r == nil {
+		return nil
+	}
+	return deepCopy(r)
+}
+
+// Clone returns a deep copy of the message, including multimodal content
+// parts and tool calls.
+func (m Message) Clone() Message {
+	return deepCopy(m)
+}
+
+// Clone returns a deep copy of the tool, including its parameter schema.
+func (t Tool) Clone() Tool {
+	return deepCopy(t)
+}
+
+// deepCopy returns a deep copy of v. Maps, slices, pointers and interface
+// values are copied recursively; unexported struct fields are copied
+// shallowly.
+func deepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+	copyValue(dst, src)
+	return dst.Interface().(T)
+}
+
+// copyValue deep-copies src into dst, which must be settable.
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		ptr := reflect.New(src.Elem().Type())
+		copyValue(ptr.Elem(), src.Elem())
+		dst.Set(ptr)
+
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+		elem := reflect.New(src.Elem().Type()).Elem()
+		copyValue(elem, src.Elem())
+		dst.Set(elem)
+
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			copyValue(elem, iter.Value())
+			m.SetMapIndex(iter.Key(), elem)
+		}
+		dst.Set(m)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			copyValue(dst.Index(i), src.Index(i))
+		}
+
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				copyValue(dst.Field(i), src.Field(i))
+			}
+		}
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,126 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWeatherTool() Tool {
+	return NewFunctionTool("get_weather", "Get the weather", map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"location": map[string]interface{}{"type": "string"},
+			"unit":     map[string]interface{}{"type": "string", "enum": []string{"celsius", "fahrenheit"}},
+		},
+		"required": []interface{}{"location"},
+	})
+}
+
+func newFullRequest() *ChatCompletionRequest {
+	req := NewChatCompletionRequest(ModelGLM47, []Message{
+		NewSystemMessage("Be brief."),
+		{
+			Role: RoleUser,
+			Content: []ContentPart{
+				{Type: "text", Text: "What is this?"},
+				{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/a.png"}},
+			},
+		},
+		{
+			Role:      RoleAssistant,
+			ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{}`}}},
+		},
+	}).
+		SetTemperature(0.7).
+		SetMaxTokens(100).
+		SetStop("END").
+		AddTool(newWeatherTool()).
+		SetResponseFormat(ResponseFormatJSON).
+		EnablePreservedThinking()
+
+	req.SetToolChoiceFunction("get_weather").SetParallelToolCalls(false)
+	req.LogitBias = map[string]float64{"50256": -100}
+	req.Extra = map[string]interface{}{"meta": map[string]interface{}{"tenant": "a"}}
+	return req
+}
+
+func TestChatCompletionRequest_Clone(t *testing.T) {
+	t.Parallel()
+
+	original := newFullRequest()
+	before, err := json.Marshal(original)
+	require.NoError(t, err)
+
+	clone := original.Clone()
+	after, err := json.Marshal(clone)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(after))
+	assert.Equal(t, original.Extra, clone.Extra)
+
+	// Mutate everything reachable from the clone
+	clone.Model = "glm-4.6"
+	*clone.Temperature = 0.1
+	*clone.MaxTokens = 1
+	*clone.Thinking.ClearThinking = true
+	clone.Thinking.Type = ThinkingTypeDisabled
+	clone.ResponseFormat.Type = "text"
+	clone.Stop[0] = "STOP"
+	clone.Messages[0].Content = "Be verbose."
+	clone.Messages[1].Content.([]ContentPart)[0].Text = "changed"
+	clone.Messages[1].Content.([]ContentPart)[1].ImageURL.URL = "https://example.com/b.png"
+	clone.Messages[2].ToolCalls[0].Function.Arguments = `{"x":1}`
+	clone.AddUserMessage("appended")
+
+	params := clone.Tools[0].Function.Parameters.(map[string]interface{})
+	params["properties"].(map[string]interface{})["location"].(map[string]interface{})["type"] = "number"
+	params["properties"].(map[string]interface{})["unit"].(map[string]interface{})["enum"].([]string)[0] = "kelvin"
+	params["required"] = append(params["required"].([]interface{}), "unit")
+	clone.Tools[0].Function.Name = "other"
+
+	clone.ToolChoice.Function = "other"
+	*clone.ParallelToolCalls = true
+	clone.LogitBias["50256"] = 100
+	clone.Extra["meta"].(map[string]interface{})["tenant"] = "b"
+
+	got, err := json.Marshal(original)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(before), string(got), "the original is unchanged")
+	assert.Equal(t, "a", original.Extra["meta"].(map[string]interface{})["tenant"])
+}
+
+func TestChatCompletionRequest_Clone_Nil(t *testing.T) {
+	t.Parallel()
+
+	var req *ChatCompletionRequest
+	assert.Nil(t, req.Clone())
+
+	empty := (&ChatCompletionRequest{}).Clone()
+	assert.Nil(t, empty.Messages)
+	assert.Nil(t, empty.Temperature)
+	assert.Nil(t, empty.ToolChoice)
+}
+
+func TestTool_Clone(t *testing.T) {
+	t.Parallel()
+
+	type schema struct {
+		Type       string                 `json:"type"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	tool := NewFunctionTool("lookup", "Look up", &schema{
+		Type:       "object",
+		Properties: map[string]interface{}{"id": map[string]interface{}{"type": "string"}},
+	})
+
+	clone := tool.Clone()
+	clone.Function.Parameters.(*schema).Properties["id"].(map[string]interface{})["type"] = "integer"
+	clone.Function.Parameters.(*schema).Type = "array"
+
+	original := tool.Function.Parameters.(*schema)
+	assert.Equal(t, "object", original.Type)
+	assert.Equal(t, "string", original.Properties["id"].(map[string]interface{})["type"])
+}
@@ -0,0 +1,174 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GetContentParts returns the content of the message as content parts, or
+// nil if the content is text. Content the API returns as an array decodes
+// to content parts.
+func (m Message) GetContentParts() []ContentPart {
+	parts, _ := m.Content.([]ContentPart)
+	return parts
+}
+
+// UnmarshalJSON decodes the message, accepting content as a string, an
+// array of content parts or null. Content parts decode to []ContentPart;
+// content of another shape is kept as decoded rather than failing the
+// message.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type alias Message
+	var raw struct {
+		alias
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := decodeContent(raw.Content)
+	if err != nil {
+		return fmt.Errorf("invalid content: %w", err)
+	}
+
+	*m = Message(raw.alias)
+	m.Content = content
+	return nil
+}
+
+// GetContentParts returns the content parts of the first choice's message,
+// or nil if there are no choices or the content is text.
+func (r *ChatCompletionResponse) GetContentParts() []ContentPart {
+	choice := r.GetFirstChoice()
+	if choice == nil {
+		return nil
+	}
+	return choice.Message.GetContentParts()
+}
+
+// GetContentParts returns the content parts of the first choice's delta,
+// or nil if there are no choices or the content is text.
+func (c *ChatCompletionChunk) GetContentParts() []ContentPart {
+	if len(c.Choices) == 0 {
+		return nil
+	}
+	return c.Choices[0].Delta.ContentParts
+}
+
+// UnmarshalJSON decodes the delta, accepting content as a string, an array
+// of content parts or null. Content parts are kept in ContentParts and
+// their text is concatenated into Content.
+func (d *Delta) UnmarshalJSON(data []byte) error {
+	type alias Delta
+	var raw struct {
+		alias
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	content, err := decodeContent(raw.Content)
+	if err != nil {
+		return fmt.Errorf("invalid content: %w", err)
+	}
+
+	*d = Delta(raw.alias)
+	switch content := content.(type) {
+	case nil:
+	case string:
+		d.Content = content
+	case []ContentPart:
+		d.ContentParts = content
+		d.Content = joinText(content)
+	default:
+		return fmt.Errorf("invalid content: %T is neither text nor content parts", content)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the delta with its content parts as the content if
+// it has any, so that decoded deltas keep the form they were sent in.
+func (d Delta) MarshalJSON() ([]byte, error) {
+	type alias Delta
+	var content any
+	switch {
+	case d.ContentParts != nil:
+		content = d.ContentParts
+	case d.Content != "":
+		content = d.Content
+	}
+	return json.Marshal(struct {
+		alias
+		Content any `json:"content,omitempty"`
+	}{
+		alias:   alias(d),
+		Content: content,
+	})
+}
+
+// decodeContent decodes message content: null or missing content is nil,
+// text is a string and an array is []ContentPart, with bare strings in the
+// array decoded as text parts. Content of another shape is decoded as is.
+func decodeContent(raw json.RawMessage) (any, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0 || bytes.Equal(raw, []byte("null")):
+		return nil, nil
+	case raw[0] == '"':
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, err
+		}
+		return text, nil
+	case raw[0] == '[':
+		if parts, ok := decodeContentParts(raw); ok {
+			return parts, nil
+		}
+	}
+
+	var content any
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// decodeContentParts decodes an array of content parts and bare strings,
+// reporting false if an element is neither.
+func decodeContentParts(raw json.RawMessage) ([]ContentPart, bool) {
+	var elems []json.RawMessage
+	if err := json.Unmarshal(raw, &elems); err != nil {
+		return nil, false
+	}
+
+	parts := make([]ContentPart, 0, len(elems))
+	for _, elem := range elems {
+		var text string
+		if err := json.Unmarshal(elem, &text); err == nil {
+			parts = append(parts, NewTextContentPart(text))
+			continue
+		}
+		var part ContentPart
+		if err := json.Unmarshal(elem, &part); err != nil || part.Type == "" {
+			return nil, false
+		}
+		parts = append(parts, part)
+	}
+	return parts, true
+}
+
+// joinText concatenates the text of the text parts, leaving out other
+// parts such as images.
+func joinText(parts []ContentPart) string {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Type == "text" {
+			b.WriteString(part.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,170 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_UnmarshalContent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		want      any
+		wantText  string
+		wantParts []ContentPart
+	}{
+		{
+			name:     "string",
+			content:  `"Hello, world"`,
+			want:     "Hello, world",
+			wantText: "Hello, world",
+		},
+		{
+			name:    "text parts",
+			content: `[{"type":"text","text":"Hello"},{"type":"text","text":", world"}]`,
+			want: []ContentPart{
+				NewTextContentPart("Hello"),
+				NewTextContentPart(", world"),
+			},
+			wantText: "Hello, world",
+			wantParts: []ContentPart{
+				NewTextContentPart("Hello"),
+				NewTextContentPart(", world"),
+			},
+		},
+		{
+			name:    "mixed parts",
+			content: `[{"type":"image_url","image_url":{"url":"https://example.com/a.png"}},"Hello"]`,
+			want: []ContentPart{
+				NewImageContentPart("https://example.com/a.png"),
+				NewTextContentPart("Hello"),
+			},
+			wantText: "Hello",
+			wantParts: []ContentPart{
+				NewImageContentPart("https://example.com/a.png"),
+				NewTextContentPart("Hello"),
+			},
+		},
+		{
+			name:    "null",
+			content: `null`,
+		},
+		{
+			name:    "other shape",
+			content: `{"text":"Hello"}`,
+			want:    map[string]any{"text": "Hello"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body := `{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":` + tt.content + `}}`
+			var resp ChatCompletionResponse
+			require.NoError(t, json.Unmarshal([]byte(`{"id":"chatcmpl-1","choices":[`+body+`]}`), &resp))
+
+			msg := resp.Choices[0].Message
+			assert.Equal(t, RoleAssistant, msg.Role)
+			assert.Equal(t, tt.want, msg.Content)
+			assert.Equal(t, tt.wantText, resp.GetContent())
+			assert.Equal(t, tt.wantParts, resp.GetContentParts())
+		})
+	}
+}
+
+func TestMessage_ContentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	messages := []Message{
+		NewUserMessage("Hello"),
+		{Role: RoleUser, Content: []ContentPart{
+			NewTextContentPart("What's in this image?"),
+			NewImageContentPart("https://example.com/a.png"),
+		}},
+		{Role: RoleAssistant, ToolCalls: []ToolCall{{ID: "call_1", Type: "function", Function: FunctionCall{Name: "f", Arguments: "{}"}}}},
+	}
+
+	for _, msg := range messages {
+		data, err := json.Marshal(msg)
+		require.NoError(t, err)
+
+		var decoded Message
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, msg, decoded)
+	}
+}
+
+func TestDelta_UnmarshalContent(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		content   string
+		wantText  string
+		wantParts []ContentPart
+	}{
+		{
+			name:     "string",
+			content:  `"Hel"`,
+			wantText: "Hel",
+		},
+		{
+			name:      "text parts",
+			content:   `[{"type":"text","text":"Hel"},{"type":"text","text":"lo"}]`,
+			wantText:  "Hello",
+			wantParts: []ContentPart{NewTextContentPart("Hel"), NewTextContentPart("lo")},
+		},
+		{
+			name:    "null",
+			content: `null`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			chunk, err := UnmarshalChunk([]byte(`{"id":"chunk-1","choices":[{"index":0,"delta":{"role":"assistant","content":` + tt.content + `}}]}`))
+			require.NoError(t, err)
+			assert.Equal(t, RoleAssistant, chunk.Choices[0].Delta.Role)
+			assert.Equal(t, tt.wantText, chunk.GetContent())
+			assert.Equal(t, tt.wantParts, chunk.GetContentParts())
+
+			// The delta is encoded in the form it was decoded from
+			data, err := json.Marshal(chunk.Choices[0].Delta)
+			require.NoError(t, err)
+			var decoded Delta
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, chunk.Choices[0].Delta, decoded)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := UnmarshalChunk([]byte(`{"choices":[{"delta":{"content":42}}]}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestDelta_MarshalContent(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(Delta{Content: "Hi"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":"Hi"}`, string(data))
+
+	data, err = json.Marshal(Delta{Role: RoleAssistant})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"role":"assistant"}`, string(data))
+
+	data, err = json.Marshal(Delta{Content: "Hi", ContentParts: []ContentPart{NewTextContentPart("Hi")}})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"content":[{"type":"text","text":"Hi"}]}`, string(data))
+}
@@ -0,0 +1,62 @@
+package chat
+
+import (
+	"encoding/json"
+	"maps"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
+
+// SetExtraField sets a top-level request field the SDK has no typed field
+// for, such as a beta parameter. Typed fields take precedence.
+//
+// Example:
+//
+//	req.SetExtraField("sampling_mode", "beta")
+func (r *ChatCompletionRequest) SetExtraField(key string, value any) *ChatCompletionRequest {
+	if r.ExtraFields == nil {
+		r.ExtraFields = make(map[string]any)
+	}
+	r.ExtraFields[key] = value
+	return r
+}
+
+// MarshalJSON encodes the request with ExtraFields merged into the
+// top-level object. Thinking fields the model does not accept, according
+// to ThinkingSupportFor, are left out.
+func (r ChatCompletionRequest) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionRequest
+	r.Thinking = r.Thinking.forModel(r.Model)
+	extra := r.ExtraFields
+	if len(r.Extra) > 0 {
+		extra = maps.Clone(r.Extra)
+		maps.Copy(extra, r.ExtraFields)
+	}
+	return models.MarshalWithExtra(alias(r), extra)
+}
+
+// Extra returns the response fields the SDK has no typed field for as a
+// JSON object, or nil if there are none.
+func (r *ChatCompletionResponse) Extra() json.RawMessage {
+	return r.extra
+}
+
+// UnmarshalJSON decodes the response and keeps unknown fields for Extra.
+func (r *ChatCompletionResponse) UnmarshalJSON(data []byte) error {
+	type alias ChatCompletionResponse
+	var known alias
+	extra, err := models.UnmarshalWithExtra(data, &known)
+	if err != nil {
+		return err
+	}
+
+	*r = ChatCompletionResponse(known)
+	r.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the response including the fields returned by Extra.
+func (r ChatCompletionResponse) MarshalJSON() ([]byte, error) {
+	type alias ChatCompletionResponse
+	return models.MarshalWithRawExtra(alias(r), r.extra)
+}
@@ -0,0 +1,47 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatCompletionRequest_ExtraFields(t *testing.T) {
+	t.Parallel()
+
+	req := NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("hi")}).
+		SetExtraField("sampling_mode", "beta").
+		SetExtraField("model", "ignored")
+	req.Extra = map[string]interface{}{"sampling_mode": "legacy", "legacy_flag": true}
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "glm-4.7", got["model"], "typed fields take precedence")
+	assert.Equal(t, "beta", got["sampling_mode"], "ExtraFields take precedence over Extra")
+	assert.Equal(t, true, got["legacy_flag"])
+}
+
+func TestChatCompletionResponse_Extra(t *testing.T) {
+	t.Parallel()
+
+	body := `{"id":"chatcmpl-1","model":"glm-4.7","choices":[],"content_filter":[{"role":"assistant","level":1}],"trace_id":"t-1"}`
+
+	var resp ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	assert.Equal(t, "chatcmpl-1", resp.ID)
+	assert.JSONEq(t, `{"content_filter":[{"role":"assistant","level":1}],"trace_id":"t-1"}`, string(resp.Extra()))
+
+	// Unknown fields survive a round trip
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"trace_id":"t-1"`)
+
+	var plain ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"chatcmpl-2"}`), &plain))
+	assert.Nil(t, plain.Extra())
+}
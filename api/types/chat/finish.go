@@ -0,0 +1,61 @@
+package chat
+
+// FinishReason is the reason the model stopped generating a choice. Its
+// underlying type is string, so values the API adds later still decode,
+// and it can be compared with string constants.
+type FinishReason string
+
+// Finish reasons reported by the API.
+const (
+	// FinishReasonStop means the model reached a natural end or a stop
+	// sequence.
+	FinishReasonStop FinishReason = "stop"
+
+	// FinishReasonLength means the output reached the token limit and was
+	// truncated.
+	FinishReasonLength FinishReason = "length"
+
+	// FinishReasonToolCalls means the model stopped to call tools.
+	FinishReasonToolCalls FinishReason = "tool_calls"
+
+	// FinishReasonSensitive means the content was blocked by the content
+	// safety review.
+	FinishReasonSensitive FinishReason = "sensitive"
+
+	// FinishReasonNetworkError means generation failed with an error of
+	// the model service.
+	FinishReasonNetworkError FinishReason = "network_error"
+)
+
+// String returns the finish reason as sent by the API.
+func (r FinishReason) String() string {
+	return string(r)
+}
+
+// GetFinishReason returns the finish reason of the first choice, or an
+// empty FinishReason if the response has no choices.
+func (r *ChatCompletionResponse) GetFinishReason() FinishReason {
+	choice := r.GetFirstChoice()
+	if choice == nil {
+		return ""
+	}
+	return choice.FinishReason
+}
+
+// WasTruncated reports whether the first choice stopped at the token
+// limit, so its content is incomplete.
+func (r *ChatCompletionResponse) WasTruncated() bool {
+	return r.GetFinishReason() == FinishReasonLength
+}
+
+// RequestedToolCalls reports whether the first choice stopped to call
+// tools, whose calls are in its message.
+func (r *ChatCompletionResponse) RequestedToolCalls() bool {
+	return r.GetFinishReason() == FinishReasonToolCalls
+}
+
+// WasFiltered reports whether the content safety review blocked the first
+// choice.
+func (r *ChatCompletionResponse) WasFiltered() bool {
+	return r.GetFinishReason() == FinishReasonSensitive
+}
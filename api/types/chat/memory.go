@@ -0,0 +1,356 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Memory stores the history of a conversation. A request with a memory
+// (see ChatCompletionRequest.WithMemory) is sent with the remembered
+// messages, and the exchange is appended to the memory after a successful
+// response.
+//
+// Implementations must be safe for concurrent use.
+type Memory interface {
+	// Append adds messages to the end of the history.
+	Append(ctx context.Context, messages ...Message) error
+
+	// Messages returns the history to send with the next request.
+	Messages(ctx context.Context) ([]Message, error)
+
+	// Clear removes all messages.
+	Clear(ctx context.Context) error
+}
+
+// snapshotter is implemented by memories that can return their stored
+// messages without the work Messages does, such as counting tokens.
+type snapshotter interface {
+	snapshot() []Message
+}
+
+// history holds system messages apart from the rest of a conversation so
+// that windows never evict them.
+type history struct {
+	system   []Message
+	messages []Message
+}
+
+func (h *history) append(messages ...Message) {
+	for _, m := range messages {
+		if m.Role == RoleSystem {
+			h.system = append(h.system, m)
+		} else {
+			h.messages = append(h.messages, m)
+		}
+	}
+}
+
+func (h *history) all() []Message {
+	all := make([]Message, 0, len(h.system)+len(h.messages))
+	all = append(all, h.system...)
+	return append(all, h.messages...)
+}
+
+func (h *history) clear() {
+	h.system = nil
+	h.messages = nil
+}
+
+// firstGroupLen returns the number of leading messages that must be
+// evicted together: an assistant message with tool calls and the tool
+// results that answer it, or a single message.
+func (h *history) firstGroupLen() int {
+	if len(h.messages) == 0 {
+		return 0
+	}
+	n := 1
+	if len(h.messages[0].ToolCalls) > 0 {
+		for n < len(h.messages) && h.messages[n].Role == RoleTool {
+			n++
+		}
+	}
+	return n
+}
+
+// WindowMemory is a Memory that keeps the most recent messages. System
+// messages are pinned: they are never evicted and are always returned
+// first. An assistant message with tool calls is evicted together with
+// its tool results, so the history never starts with an orphaned tool
+// message.
+type WindowMemory struct {
+	mu          sync.Mutex
+	maxMessages int
+	history     history
+}
+
+// NewWindowMemory creates a memory that keeps at most maxMessages messages
+// besides system messages. A maxMessages of zero or less keeps everything.
+//
+// Example:
+//
+//	memory := chat.NewWindowMemory(20)
+//	memory.Append(ctx, chat.NewSystemMessage("You are a helpful assistant."))
+func NewWindowMemory(maxMessages int) *WindowMemory {
+	return &WindowMemory{maxMessages: maxMessages}
+}
+
+// Append adds messages and evicts the oldest messages beyond the window.
+func (m *WindowMemory) Append(ctx context.Context, messages ...Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history.append(messages...)
+	if m.maxMessages > 0 {
+		for len(m.history.messages) > m.maxMessages {
+			n := m.history.firstGroupLen()
+			if n == len(m.history.messages) {
+				// Keep the most recent group even if it exceeds the window
+				break
+			}
+			m.history.messages = slices.Delete(m.history.messages, 0, n)
+		}
+	}
+	return nil
+}
+
+// Messages returns the system messages followed by the window.
+func (m *WindowMemory) Messages(ctx context.Context) ([]Message, error) {
+	return m.snapshot(), nil
+}
+
+// Clear removes all messages, including system messages.
+func (m *WindowMemory) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history.clear()
+	return nil
+}
+
+func (m *WindowMemory) snapshot() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.history.all()
+}
+
+// TokenWindowMemory is a Memory that keeps the most recent messages that
+// fit in a prompt token budget. Tokens are counted lazily: Append only
+// stores messages, and Messages counts them with the TokenCounter and
+// evicts the oldest messages, as TruncateMessages does with
+// TruncateOldest. The result is reused until the next change. System
+// messages are pinned as in WindowMemory.
+type TokenWindowMemory struct {
+	mu        sync.Mutex
+	counter   TokenCounter
+	model     string
+	maxTokens int
+	history   history
+
+	// fitted is true if history is known to fit in maxTokens.
+	fitted bool
+}
+
+// NewTokenWindowMemory creates a memory that keeps the messages that fit
+// in maxTokens prompt tokens for model, counted with counter.
+//
+// Example:
+//
+//	memory := chat.NewTokenWindowMemory(client.Tools.Counter(zai.ModeExact), "glm-4.7", 8000)
+func NewTokenWindowMemory(counter TokenCounter, model string, maxTokens int) *TokenWindowMemory {
+	return &TokenWindowMemory{
+		counter:   counter,
+		model:     model,
+		maxTokens: maxTokens,
+	}
+}
+
+// Append adds messages. They are counted by the next call to Messages.
+func (m *TokenWindowMemory) Append(ctx context.Context, messages ...Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history.append(messages...)
+	if len(messages) > 0 {
+		m.fitted = false
+	}
+	return nil
+}
+
+// Messages evicts the oldest messages that do not fit in the token budget
+// and returns the system messages followed by the rest. If the pinned
+// system messages and the most recent message do not fit on their own, a
+// *TokenLimitError is returned.
+func (m *TokenWindowMemory) Messages(ctx context.Context) ([]Message, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	all := m.history.all()
+	if m.fitted || len(all) == 0 {
+		return all, nil
+	}
+
+	kept, _, err := TruncateMessages(ctx, m.counter, m.model, all, m.maxTokens, TruncateOldest)
+	if err != nil {
+		return nil, err
+	}
+
+	// TruncateOldest keeps the leading system messages and a suffix of the rest
+	m.history.messages = slices.Clone(kept[len(m.history.system):])
+	m.fitted = true
+	return kept, nil
+}
+
+// Clear removes all messages, including system messages.
+func (m *TokenWindowMemory) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.history.clear()
+	m.fitted = false
+	return nil
+}
+
+func (m *TokenWindowMemory) snapshot() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.history.all()
+}
+
+// memoryJSON is the JSON encoding of a saved memory.
+type memoryJSON struct {
+	Messages []Message `json:"messages"`
+}
+
+// SaveMemory writes the messages stored in memory to w as JSON. Memories
+// of this package are saved without counting tokens or evicting messages.
+func SaveMemory(ctx context.Context, w io.Writer, memory Memory) error {
+	messages, err := storedMessages(ctx, memory)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(memoryJSON{Messages: messages}); err != nil {
+		return fmt.Errorf("failed to encode memory: %w", err)
+	}
+	return nil
+}
+
+// LoadMemory reads messages saved with SaveMemory from r and appends them
+// to memory.
+func LoadMemory(ctx context.Context, r io.Reader, memory Memory) error {
+	var saved memoryJSON
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return fmt.Errorf("failed to decode memory: %w", err)
+	}
+	if len(saved.Messages) == 0 {
+		return nil
+	}
+	return memory.Append(ctx, saved.Messages...)
+}
+
+// storedMessages returns the messages to save for memory.
+func storedMessages(ctx context.Context, memory Memory) ([]Message, error) {
+	if s, ok := memory.(snapshotter); ok {
+		return s.snapshot(), nil
+	}
+	return memory.Messages(ctx)
+}
+
+// FileMemory is a Memory that persists another memory to a JSON file, so
+// that a conversation survives restarts. The file is rewritten atomically
+// after every change.
+type FileMemory struct {
+	mu     sync.Mutex
+	path   string
+	memory Memory
+}
+
+// NewFileMemory creates a memory that stores its messages in memory and
+// saves them to the file at path. If the file exists, its messages are
+// loaded into memory.
+//
+// Example:
+//
+//	memory, err := chat.NewFileMemory(ctx, "conversation.json", chat.NewWindowMemory(50))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func NewFileMemory(ctx context.Context, path string, memory Memory) (*FileMemory, error) {
+	if memory == nil {
+		return nil, errors.NewValidationError("memory", "is required", nil)
+	}
+
+	f, err := os.Open(path)
+	switch {
+	case err == nil:
+		defer f.Close()
+		if err := LoadMemory(ctx, f, memory); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to open memory file: %w", err)
+	}
+
+	return &FileMemory{path: path, memory: memory}, nil
+}
+
+// Append adds messages to the underlying memory and saves it.
+func (m *FileMemory) Append(ctx context.Context, messages ...Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.memory.Append(ctx, messages...); err != nil {
+		return err
+	}
+	return m.save(ctx)
+}
+
+// Messages returns the messages of the underlying memory.
+func (m *FileMemory) Messages(ctx context.Context) ([]Message, error) {
+	return m.memory.Messages(ctx)
+}
+
+// Clear clears the underlying memory and saves it.
+func (m *FileMemory) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.memory.Clear(ctx); err != nil {
+		return err
+	}
+	return m.save(ctx)
+}
+
+// save writes the memory to a temporary file and renames it over the
+// memory file, so a crash never leaves a partially written file.
+func (m *FileMemory) save(ctx context.Context) error {
+	tmp, err := os.CreateTemp(filepath.Dir(m.path), filepath.Base(m.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to save memory: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := SaveMemory(ctx, tmp, m.memory); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save memory: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.path); err != nil {
+		return fmt.Errorf("failed to save memory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWindowMemory_Eviction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewWindowMemory(3)
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys"), NewUserMessage("u1"), NewAssistantMessage("a1")))
+	require.NoError(t, memory.Append(ctx, NewUserMessage("u2"), NewAssistantMessage("a2")))
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "a1", "u2", "a2"}, contents(messages))
+
+	// System messages appended later are pinned before the window
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys2"), NewUserMessage("u3")))
+	messages, err = memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "sys2", "u2", "a2", "u3"}, contents(messages))
+}
+
+func TestWindowMemory_ToolRounds(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewWindowMemory(4)
+	require.NoError(t, memory.Append(ctx,
+		NewSystemMessage("sys"),
+		NewUserMessage("u1"),
+		toolCallMessage("call_1"),
+		NewToolMessage("call_1", "t1"),
+		NewToolMessage("call_1", "t2"),
+	))
+
+	// Evicting the tool call evicts its results too
+	require.NoError(t, memory.Append(ctx, NewAssistantMessage("a1"), NewUserMessage("u2")))
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "a1", "u2"}, contents(messages))
+
+	// The most recent group is kept even if it exceeds the window
+	memory = NewWindowMemory(2)
+	require.NoError(t, memory.Append(ctx, toolCallMessage("call_2"), NewToolMessage("call_2", "t1"), NewToolMessage("call_2", "t2")))
+	messages, err = memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"call", "t1", "t2"}, contents(messages))
+}
+
+func TestWindowMemory_Clear(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewWindowMemory(0)
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys"), NewUserMessage("u1")))
+	require.NoError(t, memory.Clear(ctx))
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestWindowMemory_Concurrent(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewWindowMemory(50)
+	var wg sync.WaitGroup
+	for i := range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 10 {
+				assert.NoError(t, memory.Append(ctx, NewUserMessage(fmt.Sprintf("%d-%d", i, j))))
+				_, err := memory.Messages(ctx)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Len(t, messages, 50)
+}
+
+func TestTokenWindowMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// Each message costs 5 tokens: 4 content bytes plus 1
+	counter := &fakeCounter{}
+	memory := NewTokenWindowMemory(counter, "glm-4.7", 17)
+
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys0"), NewUserMessage("usr1"), NewAssistantMessage("ast2")))
+	require.NoError(t, memory.Append(ctx, NewUserMessage("usr3"), NewAssistantMessage("ast4")))
+	assert.Zero(t, counter.calls, "Append must not count tokens")
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys0", "usr3", "ast4"}, contents(messages))
+
+	// The result is reused until the next change
+	calls := counter.calls
+	messages, err = memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys0", "usr3", "ast4"}, contents(messages))
+	assert.Equal(t, calls, counter.calls)
+
+	// Evicted messages are gone for good
+	require.NoError(t, memory.Append(ctx, NewUserMessage("usr5")))
+	messages, err = memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys0", "ast4", "usr5"}, contents(messages))
+}
+
+func TestTokenWindowMemory_LimitError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewTokenWindowMemory(&fakeCounter{}, "glm-4.7", 8)
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys0"), NewUserMessage("usr1")))
+
+	_, err := memory.Messages(ctx)
+	var limitErr *TokenLimitError
+	require.ErrorAs(t, err, &limitErr)
+}
+
+func TestSaveLoadMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	counter := &fakeCounter{}
+	memory := NewTokenWindowMemory(counter, "glm-4.7", 100)
+	require.NoError(t, memory.Append(ctx,
+		NewSystemMessage("sys"),
+		NewUserMessage("u1"),
+		toolCallMessage("call_1"),
+		NewToolMessage("call_1", "t1"),
+	))
+
+	var buf bytes.Buffer
+	require.NoError(t, SaveMemory(ctx, &buf, memory))
+	assert.Zero(t, counter.calls, "SaveMemory must not count tokens")
+
+	loaded := NewWindowMemory(0)
+	require.NoError(t, LoadMemory(ctx, &buf, loaded))
+
+	messages, err := loaded.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+	assert.Equal(t, []string{"sys", "u1", "call", "t1"}, contents(messages))
+	assert.Equal(t, "call_1", messages[2].ToolCalls[0].ID)
+	assert.Equal(t, "call_1", messages[3].ToolCallID)
+
+	err = LoadMemory(ctx, bytes.NewBufferString("{"), loaded)
+	assert.ErrorContains(t, err, "failed to decode memory")
+}
+
+func TestFileMemory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "conversation.json")
+
+	memory, err := NewFileMemory(ctx, path, NewWindowMemory(3))
+	require.NoError(t, err)
+	require.NoError(t, memory.Append(ctx, NewSystemMessage("sys"), NewUserMessage("u1"), NewAssistantMessage("a1")))
+	require.NoError(t, memory.Append(ctx, NewUserMessage("u2"), NewAssistantMessage("a2")))
+
+	// A new memory on the same file picks up where the last one stopped
+	restarted, err := NewFileMemory(ctx, path, NewWindowMemory(3))
+	require.NoError(t, err)
+	messages, err := restarted.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "a1", "u2", "a2"}, contents(messages))
+
+	require.NoError(t, restarted.Clear(ctx))
+	restarted, err = NewFileMemory(ctx, path, NewWindowMemory(3))
+	require.NoError(t, err)
+	messages, err = restarted.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestFileMemory_Corrupt(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "conversation.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+	_, err := NewFileMemory(context.Background(), path, NewWindowMemory(0))
+	assert.ErrorContains(t, err, "failed to decode memory")
+}
+
+func TestChatCompletionRequest_Memory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	memory := NewWindowMemory(0)
+	require.NoError(t, memory.Append(ctx, NewUserMessage("u1"), NewAssistantMessage("a1")))
+
+	req := NewChatCompletionRequest("glm-4.7", []Message{
+		NewSystemMessage("sys"),
+		NewUserMessage("u2"),
+	}).WithMemory(memory)
+	assert.Same(t, memory, req.Memory())
+	assert.Same(t, memory, req.Clone().Memory())
+
+	send, err := req.ResolveMemory(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "u1", "a1", "u2"}, contents(send.Messages))
+	assert.Equal(t, []string{"sys", "u2"}, contents(req.Messages), "the request must not change")
+
+	require.NoError(t, req.Remember(ctx, NewAssistantMessage("a2")))
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "a1", "u2", "a2"}, contents(messages))
+
+	// Without a memory the request is sent as is
+	plain := NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("hi")})
+	send, err = plain.ResolveMemory(ctx)
+	require.NoError(t, err)
+	assert.Same(t, plain, send)
+	assert.NoError(t, plain.Remember(ctx, NewAssistantMessage("hello")))
+}
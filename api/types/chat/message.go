@@ -1,7 +1,11 @@
 // Package chat provides types for the Chat Completions API.
 package chat
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
 // Role represents the role of a message author.
 type Role string
@@ -26,6 +30,7 @@ type Message struct {
 
 	// Content is the content of the message.
 	// Can be a string or an array of content parts for multimodal messages.
+	// Content decoded from an array is []ContentPart; see GetContentParts.
 	Content interface{} `json:"content,omitempty"`
 
 	// ReasoningContent contains the model's reasoning process when thinking mode is enabled.
@@ -45,6 +50,11 @@ type Message struct {
 
 	// FunctionCall is the function call generated by the model (deprecated).
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+
+	// CacheControl marks the message as the end of a prompt prefix the
+	// server may cache. Only messages before the last user message can be
+	// marked; see WithPrefixCaching.
+	CacheControl *CacheControl `json:"cache_control,omitempty"`
 }
 
 // NewUserMessage creates a new user message with text content.
@@ -111,6 +121,33 @@ func NewToolMessage(toolCallID, content string) Message {
 	}
 }
 
+// Text returns the text of the message content: the content itself if it
+// is a string, or its text parts joined with newlines if it is a list of
+// content parts. Other parts, such as images, are left out.
+func (m Message) Text() string {
+	var texts []string
+	switch content := m.Content.(type) {
+	case string:
+		return content
+	case []ContentPart:
+		for _, part := range content {
+			if part.Type == "text" {
+				texts = append(texts, part.Text)
+			}
+		}
+	case []interface{}:
+		// Content decoded from JSON
+		for _, part := range content {
+			if fields, ok := part.(map[string]interface{}); ok && fields["type"] == "text" {
+				if text, ok := fields["text"].(string); ok {
+					texts = append(texts, text)
+				}
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
 // ContentPart represents a part of multimodal message content.
 type ContentPart struct {
 	// Type is the type of content part ("text", "image_url").
@@ -186,6 +223,10 @@ type ToolCall struct {
 
 	// Function is the function call details.
 	Function FunctionCall `json:"function"`
+
+	// Index is the position of the tool call in the response. It is only
+	// set on the tool call fragments of streamed deltas.
+	Index *int `json:"index,omitempty"`
 }
 
 // FunctionCall represents a function call.
@@ -220,11 +261,27 @@ func (fc *FunctionCall) GetArguments(v interface{}) error {
 
 // Tool represents a tool that can be called by the model.
 type Tool struct {
-	// Type is the type of tool (currently only "function").
+	// Type is the type of tool: "function", "retrieval" or "web_search".
 	Type string `json:"type"`
 
-	// Function is the function definition.
-	Function ToolFunction `json:"function"`
+	// Function is the function definition of a "function" tool.
+	Function ToolFunction `json:"function,omitzero"`
+
+	// Retrieval is the knowledge base of a "retrieval" tool.
+	Retrieval *ToolRetrieval `json:"retrieval,omitempty"`
+
+	// WebSearch configures a "web_search" tool.
+	WebSearch *ToolWebSearch `json:"web_search,omitempty"`
+}
+
+// ToolRetrieval lets the model answer from a knowledge base collection.
+type ToolRetrieval struct {
+	// KnowledgeID is the ID of the collection to retrieve from.
+	KnowledgeID string `json:"knowledge_id"`
+
+	// PromptTemplate is the template the retrieved chunks are inserted
+	// into. If empty, the platform default is used.
+	PromptTemplate string `json:"prompt_template,omitempty"`
 }
 
 // ToolFunction represents a function tool definition.
@@ -237,6 +294,10 @@ type ToolFunction struct {
 
 	// Parameters is the JSON Schema for the function parameters.
 	Parameters interface{} `json:"parameters,omitempty"`
+
+	// Strict asks the model to generate arguments that always match
+	// Parameters exactly.
+	Strict bool `json:"strict,omitempty"`
 }
 
 // NewFunctionTool creates a new function tool.
@@ -266,6 +327,9 @@ type ToolFunction struct {
 //	    "Get the current weather in a given location",
 //	    parameters,
 //	)
+//
+// The schema package builds parameters without nested maps, and
+// NewFunctionToolFromStruct derives them from a struct.
 func NewFunctionTool(name, description string, parameters interface{}) Tool {
 	return Tool{
 		Type: "function",
@@ -277,6 +341,21 @@ func NewFunctionTool(name, description string, parameters interface{}) Tool {
 	}
 }
 
+// NewRetrievalTool creates a tool that grounds the model's answers in the
+// knowledge base collection with the given ID.
+//
+// Example:
+//
+//	tool := chat.NewRetrievalTool(collection.ID)
+func NewRetrievalTool(knowledgeID string) Tool {
+	return Tool{
+		Type: "retrieval",
+		Retrieval: &ToolRetrieval{
+			KnowledgeID: knowledgeID,
+		},
+	}
+}
+
 // ToolChoice represents the tool choice configuration.
 type ToolChoice string
 
@@ -291,6 +370,68 @@ const (
 	ToolChoiceRequired ToolChoice = "required"
 )
 
+// ToolChoiceParam is the tool_choice parameter of a request. It is either
+// a mode, encoded as a string such as "auto", or a named function the model
+// must call, encoded as {"type": "function", "function": {"name": "..."}}.
+type ToolChoiceParam struct {
+	// Mode is the tool choice mode. It is ignored if Function is set.
+	Mode ToolChoice
+
+	// Function is the name of the function the model must call.
+	Function string
+}
+
+// NewToolChoice returns a tool_choice parameter for mode.
+func NewToolChoice(mode ToolChoice) *ToolChoiceParam {
+	return &ToolChoiceParam{Mode: mode}
+}
+
+// ToolChoiceFunction returns a tool_choice parameter that forces the model
+// to call the function with name.
+//
+// Example:
+//
+//	req.ToolChoice = chat.ToolChoiceFunction("get_weather")
+func ToolChoiceFunction(name string) *ToolChoiceParam {
+	return &ToolChoiceParam{Function: name}
+}
+
+// toolChoiceFunctionJSON is the wire format of a named function choice.
+type toolChoiceFunctionJSON struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (c ToolChoiceParam) MarshalJSON() ([]byte, error) {
+	if c.Function == "" {
+		return json.Marshal(string(c.Mode))
+	}
+
+	var named toolChoiceFunctionJSON
+	named.Type = "function"
+	named.Function.Name = c.Function
+	return json.Marshal(named)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *ToolChoiceParam) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*c = ToolChoiceParam{Mode: ToolChoice(mode)}
+		return nil
+	}
+
+	var named toolChoiceFunctionJSON
+	if err := json.Unmarshal(data, &named); err != nil {
+		return fmt.Errorf("tool_choice must be a string or a function object: %w", err)
+	}
+	*c = ToolChoiceParam{Function: named.Function.Name}
+	return nil
+}
+
 // ResponseFormat represents the response format configuration.
 type ResponseFormat struct {
 	// Type is the type of response format ("text" or "json_object").
@@ -186,6 +186,27 @@ func TestMessage_JSON(t *testing.T) {
 	})
 }
 
+func TestMessage_Text(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Hello", NewUserMessage("Hello").Text())
+	assert.Equal(t, "", Message{Role: RoleAssistant}.Text())
+
+	multimodal := Message{Role: RoleUser, Content: []ContentPart{
+		NewTextContentPart("What is this?"),
+		NewImageContentPart("https://example.com/a.png"),
+		NewTextContentPart("Be brief."),
+	}}
+	assert.Equal(t, "What is this?\nBe brief.", multimodal.Text())
+
+	// Content parts decoded from JSON
+	data, err := json.Marshal(multimodal)
+	require.NoError(t, err)
+	var decoded Message
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "What is this?\nBe brief.", decoded.Text())
+}
+
 func TestRole_Values(t *testing.T) {
 	t.Parallel()
 
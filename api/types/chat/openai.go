@@ -0,0 +1,256 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ReasoningContentKey is the vendor-extension key under which
+// ExportMessages keeps the reasoning content of assistant messages when
+// WithReasoningContent is given. The OpenAI format has no equivalent field.
+const ReasoningContentKey = "x_zai_reasoning_content"
+
+// ExportConfig holds settings for ExportMessages.
+type ExportConfig struct {
+	// IncludeReasoning keeps the reasoning content of assistant messages
+	// under ReasoningContentKey. Without it, reasoning content is dropped.
+	IncludeReasoning bool
+}
+
+// ExportOption configures ExportMessages.
+type ExportOption func(*ExportConfig)
+
+// WithReasoningContent makes ExportMessages keep the reasoning content of
+// assistant messages under ReasoningContentKey, so that ImportMessages
+// restores it.
+func WithReasoningContent() ExportOption {
+	return func(c *ExportConfig) {
+		c.IncludeReasoning = true
+	}
+}
+
+// openAIConversation is a conversation in the OpenAI chat format.
+type openAIConversation struct {
+	Messages []openAIMessage `json:"messages"`
+}
+
+// openAIMessage is a message in the OpenAI chat format. Content is kept
+// raw, so that empty and null content survive a round trip.
+type openAIMessage struct {
+	Role             string           `json:"role"`
+	Content          json.RawMessage  `json:"content"`
+	Name             string           `json:"name,omitempty"`
+	ToolCalls        []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID       string           `json:"tool_call_id,omitempty"`
+	FunctionCall     *FunctionCall    `json:"function_call,omitempty"`
+	ReasoningContent string           `json:"x_zai_reasoning_content,omitempty"`
+
+	// reasoning_content is read on import, as other OpenAI-compatible
+	// providers export reasoning under that key
+	CompatReasoning string `json:"reasoning_content,omitempty"`
+}
+
+// openAIToolCall is a tool call in the OpenAI chat format.
+type openAIToolCall struct {
+	ID       string       `json:"id"`
+	Type     string       `json:"type"`
+	Function FunctionCall `json:"function"`
+}
+
+// ExportMessages encodes msgs as a conversation in the OpenAI chat format,
+// {"messages": [...]}, for migrating conversations to other providers.
+// Tool calls, tool results and multimodal content map to their OpenAI
+// equivalents. Reasoning content is dropped unless WithReasoningContent is
+// given. Every tool result must follow the assistant message whose tool
+// call it answers, with only other tool results in between.
+//
+// Example:
+//
+//	data, err := chat.ExportMessages(history, chat.WithReasoningContent())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("conversation.json", data, 0o644)
+func ExportMessages(msgs []Message, opts ...ExportOption) ([]byte, error) {
+	config := &ExportConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := checkToolResults(msgs); err != nil {
+		return nil, err
+	}
+
+	conversation := openAIConversation{Messages: make([]openAIMessage, 0, len(msgs))}
+	for i, msg := range msgs {
+		switch msg.Role {
+		case RoleSystem, RoleUser, RoleAssistant, RoleTool, RoleFunction:
+		default:
+			return nil, fmt.Errorf("chat: message %d has unknown role %q", i, msg.Role)
+		}
+
+		content, err := exportContent(msg.Content)
+		if err != nil {
+			return nil, fmt.Errorf("chat: message %d: %w", i, err)
+		}
+		out := openAIMessage{
+			Role:         string(msg.Role),
+			Content:      content,
+			Name:         msg.Name,
+			ToolCallID:   msg.ToolCallID,
+			FunctionCall: msg.FunctionCall,
+		}
+		for _, call := range msg.ToolCalls {
+			out.ToolCalls = append(out.ToolCalls, openAIToolCall{ID: call.ID, Type: call.Type, Function: call.Function})
+		}
+		if config.IncludeReasoning {
+			out.ReasoningContent = msg.ReasoningContent
+		}
+		conversation.Messages = append(conversation.Messages, out)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(conversation); err != nil {
+		return nil, fmt.Errorf("chat: failed to encode messages: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportMessages decodes a conversation in the OpenAI chat format, either
+// {"messages": [...]} or a bare array of messages, as written by
+// ExportMessages or another provider. The "developer" role becomes
+// RoleSystem, multimodal content becomes []ContentPart, and reasoning
+// content under ReasoningContentKey or "reasoning_content" is restored.
+//
+// Example:
+//
+//	data, err := os.ReadFile("conversation.json")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	history, err := chat.ImportMessages(data)
+func ImportMessages(data []byte) ([]Message, error) {
+	var conversation openAIConversation
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &conversation.Messages); err != nil {
+			return nil, fmt.Errorf("chat: failed to decode messages: %w", err)
+		}
+	} else if err := json.Unmarshal(data, &conversation); err != nil {
+		return nil, fmt.Errorf("chat: failed to decode messages: %w", err)
+	}
+
+	msgs := make([]Message, 0, len(conversation.Messages))
+	for i, in := range conversation.Messages {
+		role := Role(in.Role)
+		switch role {
+		case RoleSystem, RoleUser, RoleAssistant, RoleTool, RoleFunction:
+		case "developer":
+			role = RoleSystem
+		default:
+			return nil, fmt.Errorf("chat: message %d has unknown role %q", i, in.Role)
+		}
+
+		content, err := importContent(in.Content)
+		if err != nil {
+			return nil, fmt.Errorf("chat: message %d: %w", i, err)
+		}
+		msg := Message{
+			Role:             role,
+			Content:          content,
+			Name:             in.Name,
+			ToolCallID:       in.ToolCallID,
+			FunctionCall:     in.FunctionCall,
+			ReasoningContent: in.ReasoningContent,
+		}
+		if msg.ReasoningContent == "" {
+			msg.ReasoningContent = in.CompatReasoning
+		}
+		for _, call := range in.ToolCalls {
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{ID: call.ID, Type: call.Type, Function: call.Function})
+		}
+		msgs = append(msgs, msg)
+	}
+
+	if err := checkToolResults(msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// exportContent encodes message content. Nil content is null, and content
+// parts decoded from JSON are normalized to the OpenAI part format.
+func exportContent(content any) (json.RawMessage, error) {
+	switch content := content.(type) {
+	case nil:
+		return json.RawMessage("null"), nil
+	case string:
+		return json.Marshal(content)
+	case []ContentPart:
+		return json.Marshal(content)
+	case []any:
+		data, err := json.Marshal(content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode content: %w", err)
+		}
+		var parts []ContentPart
+		if err := json.Unmarshal(data, &parts); err != nil {
+			return nil, fmt.Errorf("content is neither text nor content parts: %w", err)
+		}
+		return json.Marshal(parts)
+	default:
+		return nil, fmt.Errorf("content of type %T is neither text nor content parts", content)
+	}
+}
+
+// importContent decodes message content: null or missing content is nil,
+// text is a string and an array is []ContentPart.
+func importContent(raw json.RawMessage) (any, error) {
+	content, err := decodeContent(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode content: %w", err)
+	}
+
+	switch content := content.(type) {
+	case nil, string:
+		return content, nil
+	case []ContentPart:
+		for _, part := range content {
+			if part.Type != "text" && part.Type != "image_url" {
+				return nil, fmt.Errorf("unsupported content part type %q", part.Type)
+			}
+		}
+		return content, nil
+	default:
+		return nil, fmt.Errorf("content of type %T is neither text nor content parts", content)
+	}
+}
+
+// checkToolResults returns an error if a tool message does not answer a
+// tool call of the assistant message before it, with only other tool
+// messages in between, or answers one twice. Tool calls without a result
+// are allowed, as a conversation may end while a tool runs.
+func checkToolResults(msgs []Message) error {
+	var pending map[string]bool
+	for i, msg := range msgs {
+		switch {
+		case msg.Role == RoleAssistant && len(msg.ToolCalls) > 0:
+			pending = make(map[string]bool, len(msg.ToolCalls))
+			for _, call := range msg.ToolCalls {
+				pending[call.ID] = true
+			}
+		case msg.Role == RoleTool:
+			if !pending[msg.ToolCallID] {
+				return fmt.Errorf("chat: tool message %d answers tool call %q, which the preceding assistant message did not make or which is already answered",
+					i, msg.ToolCallID)
+			}
+			delete(pending, msg.ToolCallID)
+		default:
+			pending = nil
+		}
+	}
+	return nil
+}
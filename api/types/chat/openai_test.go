@@ -0,0 +1,138 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// everyMessageKind is a conversation with every kind of message.
+func everyMessageKind() []Message {
+	return []Message{
+		NewSystemMessage("You are a travel assistant."),
+		{Role: RoleUser, Name: "ann", Content: "Hi, I am planning a trip."},
+		{Role: RoleUser, Content: []ContentPart{
+			NewTextContentPart("Where was this taken?"),
+			{Type: "image_url", ImageURL: &ImageURL{URL: "https://example.com/beach.jpg", Detail: "high"}},
+		}},
+		{Role: RoleAssistant, Content: "Looks like Nazaré. Shall I check the weather?", ReasoningContent: "The waves and cliffs suggest Nazaré."},
+		NewUserMessage("Yes, and the time there."),
+		{Role: RoleAssistant, ReasoningContent: "I need two tools.", ToolCalls: []ToolCall{
+			{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"city":"Nazaré"}`}},
+			{ID: "call_2", Type: "function", Function: FunctionCall{Name: "get_time", Arguments: `{"timezone":"Europe/Lisbon"}`}},
+		}},
+		NewToolMessage("call_2", "14:05"),
+		NewToolMessage("call_1", `{"temperature":19,"condition":"sunny"}`),
+		NewAssistantMessage("It is 14:05 and sunny at 19°C."),
+		{Role: RoleAssistant, FunctionCall: &FunctionCall{Name: "get_tides", Arguments: `{}`}},
+		{Role: RoleFunction, Name: "get_tides", Content: "high tide at 18:20"},
+		NewUserMessage(""),
+	}
+}
+
+func TestExportMessages_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	msgs := everyMessageKind()
+
+	data, err := ExportMessages(msgs, WithReasoningContent())
+	require.NoError(t, err)
+	imported, err := ImportMessages(data)
+	require.NoError(t, err)
+	assert.Equal(t, msgs, imported)
+
+	// Without the option, only the reasoning content is lost
+	data, err = ExportMessages(msgs)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), ReasoningContentKey)
+	imported, err = ImportMessages(data)
+	require.NoError(t, err)
+	for i := range msgs {
+		msgs[i].ReasoningContent = ""
+	}
+	assert.Equal(t, msgs, imported)
+}
+
+func TestExportMessages_Format(t *testing.T) {
+	t.Parallel()
+
+	data, err := ExportMessages([]Message{
+		{Role: RoleAssistant, ReasoningContent: "Check first.", ToolCalls: []ToolCall{
+			{ID: "call_1", Type: "function", Index: new(int), Function: FunctionCall{Name: "lookup", Arguments: `{"q":"<go>"}`}},
+		}},
+		NewToolMessage("call_1", "found"),
+	}, WithReasoningContent())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"messages": [
+		{
+			"role": "assistant",
+			"content": null,
+			"tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{\"q\":\"<go>\"}"}}],
+			"x_zai_reasoning_content": "Check first."
+		},
+		{"role": "tool", "content": "found", "tool_call_id": "call_1"}
+	]}`, string(data))
+	assert.Contains(t, string(data), `<go>`, "HTML is not escaped")
+}
+
+func TestExportMessages_DecodedContent(t *testing.T) {
+	t.Parallel()
+
+	// Content parts built from generic JSON values are []any
+	msg := Message{Role: RoleUser, Content: []any{map[string]any{"type": "text", "text": "Hi"}}}
+
+	data, err := ExportMessages([]Message{msg})
+	require.NoError(t, err)
+	imported, err := ImportMessages(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{{Role: RoleUser, Content: []ContentPart{NewTextContentPart("Hi")}}}, imported)
+}
+
+func TestImportMessages(t *testing.T) {
+	t.Parallel()
+
+	msgs, err := ImportMessages([]byte(`[
+		{"role": "developer", "content": "Be brief."},
+		{"role": "user", "content": "Why is the sky blue?"},
+		{"role": "assistant", "content": "Rayleigh scattering.", "reasoning_content": "Short answer."}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []Message{
+		NewSystemMessage("Be brief."),
+		NewUserMessage("Why is the sky blue?"),
+		{Role: RoleAssistant, Content: "Rayleigh scattering.", ReasoningContent: "Short answer."},
+	}, msgs)
+}
+
+func TestImportMessages_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"not json":       `{"messages": `,
+		"unknown role":   `{"messages": [{"role": "critic", "content": "No."}]}`,
+		"part type":      `{"messages": [{"role": "user", "content": [{"type": "input_audio"}]}]}`,
+		"content type":   `{"messages": [{"role": "user", "content": 42}]}`,
+		"orphan result":  `{"messages": [{"role": "tool", "tool_call_id": "call_1", "content": "x"}]}`,
+		"answered twice": `{"messages": [{"role": "assistant", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "f", "arguments": "{}"}}]}, {"role": "tool", "tool_call_id": "call_1", "content": "x"}, {"role": "tool", "tool_call_id": "call_1", "content": "y"}]}`,
+		"late result":    `{"messages": [{"role": "assistant", "tool_calls": [{"id": "call_1", "type": "function", "function": {"name": "f", "arguments": "{}"}}]}, {"role": "user", "content": "hurry"}, {"role": "tool", "tool_call_id": "call_1", "content": "x"}]}`,
+	}
+	for name, data := range tests {
+		_, err := ImportMessages([]byte(data))
+		assert.Error(t, err, name)
+	}
+}
+
+func TestExportMessages_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ExportMessages([]Message{{Role: "critic", Content: "No."}})
+	assert.ErrorContains(t, err, `unknown role "critic"`)
+
+	_, err = ExportMessages([]Message{{Role: RoleUser, Content: 42}})
+	assert.ErrorContains(t, err, "content of type int")
+
+	_, err = ExportMessages([]Message{NewUserMessage("Hi"), NewToolMessage("call_1", "x")})
+	assert.ErrorContains(t, err, `tool message 1 answers tool call "call_1"`)
+}
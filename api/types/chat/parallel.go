@@ -0,0 +1,98 @@
+package chat
+
+import "time"
+
+// DefaultParallelConcurrency is the default number of requests
+// ChatService.CreateParallel runs at once.
+const DefaultParallelConcurrency = 8
+
+// DefaultRateLimitRetries is the default number of times
+// ChatService.CreateParallel retries a rate-limited request.
+const DefaultRateLimitRetries = 3
+
+// ParallelProgress reports a finished request of ChatService.CreateParallel.
+type ParallelProgress struct {
+	// Index is the index of the finished request.
+	Index int
+
+	// Err is the error of the request, or nil if it succeeded.
+	Err error
+
+	// Completed is the number of finished requests, including this one.
+	Completed int
+
+	// Total is the number of requests.
+	Total int
+}
+
+// ParallelConfig holds settings for ChatService.CreateParallel.
+type ParallelConfig struct {
+	// Concurrency is the maximum number of requests in flight.
+	Concurrency int
+
+	// RequestTimeout bounds each attempt of a request. Zero means no
+	// per-request limit.
+	RequestTimeout time.Duration
+
+	// RateLimitRetries is how many times a rate-limited request is retried.
+	RateLimitRetries int
+
+	// OnProgress, if set, is called after each request finishes. Calls are
+	// never concurrent.
+	OnProgress func(ParallelProgress)
+}
+
+// ParallelOption configures ChatService.CreateParallel.
+type ParallelOption func(*ParallelConfig)
+
+// NewParallelConfig creates a parallel configuration with the given options applied.
+func NewParallelConfig(opts ...ParallelOption) *ParallelConfig {
+	cfg := &ParallelConfig{
+		Concurrency:      DefaultParallelConcurrency,
+		RateLimitRetries: DefaultRateLimitRetries,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultParallelConcurrency
+	}
+	return cfg
+}
+
+// WithConcurrency sets the maximum number of requests in flight.
+func WithConcurrency(n int) ParallelOption {
+	return func(c *ParallelConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithRequestTimeout limits how long each attempt of a request may take,
+// so that a slow request fails on its own without using up the deadline
+// of the whole fan-out.
+func WithRequestTimeout(timeout time.Duration) ParallelOption {
+	return func(c *ParallelConfig) {
+		c.RequestTimeout = timeout
+	}
+}
+
+// WithRateLimitRetries sets how many times a rate-limited request is
+// retried. Zero disables retries.
+func WithRateLimitRetries(n int) ParallelOption {
+	return func(c *ParallelConfig) {
+		c.RateLimitRetries = n
+	}
+}
+
+// WithProgress sets a callback called after each request finishes.
+//
+// Example:
+//
+//	chat.WithProgress(func(p chat.ParallelProgress) {
+//	    fmt.Printf("%d/%d done\n", p.Completed, p.Total)
+//	})
+func WithProgress(fn func(ParallelProgress)) ParallelOption {
+	return func(c *ParallelConfig) {
+		c.OnProgress = fn
+	}
+}
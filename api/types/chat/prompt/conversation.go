@@ -0,0 +1,100 @@
+package prompt
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Turn is one message of a conversation template: a role, such as
+// "system" or "user", and the template of its content.
+type Turn struct {
+	Role string
+	Text string
+}
+
+// RenderedTurn is one rendered message of a conversation template.
+type RenderedTurn struct {
+	Role    string
+	Content string
+}
+
+// Conversation is a parsed template of several messages, rendered with one
+// set of variables. The chat package converts it to messages with
+// chat.NewMessagesFromTemplate.
+//
+// Example:
+//
+//	conv, err := prompt.NewConversation([]prompt.Turn{
+//	    {Role: "system", Text: "You are a {{.persona}}."},
+//	    {Role: "user", Text: "Summarize {{.doc}} for {{.audience}}."},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	messages, err := chat.NewMessagesFromTemplate(conv, vars)
+type Conversation struct {
+	roles      []string
+	templates  []*Template
+	required   []string
+	strictness Strictness
+}
+
+// NewConversation parses the templates of a conversation. Variables are
+// checked across the conversation: a variable is unused only if no turn
+// uses it.
+func NewConversation(turns []Turn, opts ...Option) (*Conversation, error) {
+	o := newOptions(opts)
+
+	conv := &Conversation{strictness: o.strictness}
+	seen := make(map[string]bool)
+	for i, turn := range turns {
+		tpl, err := parseTemplate(fmt.Sprintf("turn %d", i), turn.Text, o)
+		if err != nil {
+			return nil, fmt.Errorf("prompt: failed to parse turn %d (%s): %w", i, turn.Role, err)
+		}
+		conv.roles = append(conv.roles, turn.Role)
+		conv.templates = append(conv.templates, tpl)
+		for _, name := range tpl.required {
+			seen[name] = true
+		}
+	}
+
+	for name := range seen {
+		conv.required = append(conv.required, name)
+	}
+	sort.Strings(conv.required)
+	return conv, nil
+}
+
+// RequiredVars returns the sorted names of the top-level variables used by
+// any turn.
+func (c *Conversation) RequiredVars() []string {
+	return append([]string(nil), c.required...)
+}
+
+// Render renders every turn with vars, checking the variables as
+// Template.Render does.
+func (c *Conversation) Render(vars map[string]any) ([]RenderedTurn, error) {
+	if err := checkVars(c.required, vars, c.strictness); err != nil {
+		return nil, err
+	}
+
+	turns := make([]RenderedTurn, len(c.templates))
+	for i, tpl := range c.templates {
+		content, err := tpl.execute(vars)
+		if err != nil {
+			return nil, err
+		}
+		turns[i] = RenderedTurn{Role: c.roles[i], Content: content}
+	}
+	return turns, nil
+}
+
+// MustConversation returns conv, and panics if err is not nil.
+func MustConversation(conv *Conversation, err error) *Conversation {
+	if err != nil {
+		panic(err)
+	}
+	return conv
+}
@@ -0,0 +1,288 @@
+// Package prompt renders prompt templates written in Go template syntax,
+// checking that the variables given match the variables the template uses.
+//
+// Templates are rendered with text/template, so values are inserted as is,
+// without HTML escaping.
+//
+// Example:
+//
+//	tpl, err := prompt.New("Summarize {{.doc}} for {{.audience}}")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	fmt.Println(tpl.RequiredVars()) // [audience doc]
+//
+//	text, err := tpl.Render(map[string]any{"doc": doc, "audience": "executives"})
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+var (
+	// ErrMissingVar is returned, wrapped, when a variable the template uses
+	// is not given.
+	ErrMissingVar = errors.New("missing variable")
+
+	// ErrUnusedVar is returned, wrapped, when a variable is given that the
+	// template does not use.
+	ErrUnusedVar = errors.New("unused variable")
+)
+
+// Strictness controls which variable mismatches fail a Render.
+type Strictness int
+
+const (
+	// Strict fails on missing and on unused variables. It is the default.
+	Strict Strictness = iota
+
+	// AllowUnused fails on missing variables but ignores unused ones, for
+	// callers that pass one set of variables to several templates.
+	AllowUnused
+
+	// Lenient renders missing top-level variables as empty and ignores
+	// unused ones.
+	Lenient
+)
+
+// Option configures a Template or Conversation.
+type Option func(*options)
+
+type options struct {
+	strictness Strictness
+	funcs      template.FuncMap
+}
+
+// WithStrictness sets which variable mismatches fail a Render.
+func WithStrictness(strictness Strictness) Option {
+	return func(o *options) {
+		o.strictness = strictness
+	}
+}
+
+// WithFuncs makes functions available to the template, as with
+// template.Template.Funcs.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(o *options) {
+		o.funcs = funcs
+	}
+}
+
+// Template is a parsed prompt template. It is safe for concurrent use.
+type Template struct {
+	tmpl       *template.Template
+	required   []string
+	strictness Strictness
+}
+
+// New parses a prompt template. Variables are referenced as fields of
+// the top-level value, such as {{.doc}} or {{.user.name}}.
+func New(text string, opts ...Option) (*Template, error) {
+	tpl, err := parseTemplate("prompt", text, newOptions(opts))
+	if err != nil {
+		return nil, fmt.Errorf("prompt: failed to parse template: %w", err)
+	}
+	return tpl, nil
+}
+
+// Must returns tpl, and panics if err is not nil. It is intended for
+// templates defined in package variables.
+//
+// Example:
+//
+//	var summarize = prompt.Must(prompt.New("Summarize {{.doc}}"))
+func Must(tpl *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return tpl
+}
+
+// parseTemplate parses text with the given options.
+func parseTemplate(name, text string, o options) (*Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(o.funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{
+		tmpl:       tmpl,
+		required:   requiredVars(tmpl),
+		strictness: o.strictness,
+	}, nil
+}
+
+// RequiredVars returns the sorted names of the top-level variables the
+// template uses. For {{.user.name}}, that is "user".
+func (t *Template) RequiredVars() []string {
+	return append([]string(nil), t.required...)
+}
+
+// Render renders the template with vars. Depending on the strictness, it
+// fails if a variable the template uses is missing from vars or a
+// variable in vars is unused, naming the variables in the error.
+func (t *Template) Render(vars map[string]any) (string, error) {
+	if err := checkVars(t.required, vars, t.strictness); err != nil {
+		return "", err
+	}
+	return t.execute(vars)
+}
+
+// execute renders the template without checking vars. In Lenient mode,
+// missing top-level variables are set to the empty string.
+func (t *Template) execute(vars map[string]any) (string, error) {
+	if t.strictness == Lenient {
+		filled := make(map[string]any, len(t.required)+len(vars))
+		for _, name := range t.required {
+			filled[name] = ""
+		}
+		for name, value := range vars {
+			filled[name] = value
+		}
+		vars = filled
+	}
+
+	var b strings.Builder
+	if err := t.tmpl.Execute(&b, vars); err != nil {
+		return "", fmt.Errorf("prompt: failed to render template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// newOptions applies opts to the default options.
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// checkVars compares the required variables with the given ones.
+func checkVars(required []string, vars map[string]any, strictness Strictness) error {
+	if strictness == Lenient {
+		return nil
+	}
+
+	var missing []string
+	for _, name := range required {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return varError(ErrMissingVar, missing)
+	}
+
+	if strictness == Strict {
+		var unused []string
+		for name := range vars {
+			if !contains(required, name) {
+				unused = append(unused, name)
+			}
+		}
+		if len(unused) > 0 {
+			sort.Strings(unused)
+			return varError(ErrUnusedVar, unused)
+		}
+	}
+	return nil
+}
+
+// varError returns err naming the variables.
+func varError(err error, names []string) error {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	if len(names) == 1 {
+		return fmt.Errorf("prompt: %w %s", err, quoted[0])
+	}
+	return fmt.Errorf("prompt: %ws %s", err, strings.Join(quoted, ", "))
+}
+
+// contains reports whether the sorted names contain name.
+func contains(names []string, name string) bool {
+	i := sort.SearchStrings(names, name)
+	return i < len(names) && names[i] == name
+}
+
+// requiredVars returns the sorted top-level fields used by tmpl and the
+// templates it defines.
+func requiredVars(tmpl *template.Template) []string {
+	seen := make(map[string]bool)
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			collectVars(t.Tree.Root, true, seen)
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectVars adds the top-level fields referenced by node to seen. atRoot
+// reports whether dot is the top-level value at node; it is not inside
+// range and with blocks.
+func collectVars(node parse.Node, atRoot bool, seen map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			collectVars(child, atRoot, seen)
+		}
+	case *parse.ActionNode:
+		collectVars(n.Pipe, atRoot, seen)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			collectVars(cmd, atRoot, seen)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			collectVars(arg, atRoot, seen)
+		}
+	case *parse.FieldNode:
+		if atRoot {
+			seen[n.Ident[0]] = true
+		}
+	case *parse.ChainNode:
+		collectVars(n.Node, atRoot, seen)
+	case *parse.VariableNode:
+		// $ is always the top-level value
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			seen[n.Ident[1]] = true
+		}
+	case *parse.IfNode:
+		collectBranch(&n.BranchNode, atRoot, atRoot, seen)
+	case *parse.WithNode:
+		collectBranch(&n.BranchNode, atRoot, false, seen)
+	case *parse.RangeNode:
+		collectBranch(&n.BranchNode, atRoot, false, seen)
+	case *parse.TemplateNode:
+		collectVars(n.Pipe, atRoot, seen)
+	}
+}
+
+// collectBranch collects the fields of an if, with or range block, whose
+// body is at the root only if bodyAtRoot.
+func collectBranch(n *parse.BranchNode, atRoot, bodyAtRoot bool, seen map[string]bool) {
+	collectVars(n.Pipe, atRoot, seen)
+	collectVars(n.List, bodyAtRoot, seen)
+	// The else branch keeps the outer dot
+	collectVars(n.ElseList, atRoot, seen)
+}
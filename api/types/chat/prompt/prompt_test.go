@@ -0,0 +1,159 @@
+package prompt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplate_RequiredVars(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"plain text", "Hello", nil},
+		{"fields", "Summarize {{.doc}} for {{.audience}}", []string{"audience", "doc"}},
+		{"nested fields", "Hi {{.user.name}} from {{.user.company.name}}", []string{"user"}},
+		{"conditionals", "{{if .formal}}Dear{{else}}Hi{{end}} {{.name}}", []string{"formal", "name"}},
+		{"with rebinds dot", "{{with .user}}{{.name}}{{else}}{{.fallback}}{{end}}", []string{"fallback", "user"}},
+		{"range rebinds dot", "{{range .items}}- {{.title}} ({{$.unit}})\n{{end}}", []string{"items", "unit"}},
+		{"functions", `{{printf "%q" .query | len}}`, []string{"query"}},
+		{"defined templates", `{{define "sig"}}-- {{.author}}{{end}}{{.body}}{{template "sig" .}}`, []string{"author", "body"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tpl, err := New(tt.text)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, tpl.RequiredVars())
+		})
+	}
+}
+
+func TestTemplate_Render(t *testing.T) {
+	t.Parallel()
+
+	tpl := Must(New("Summarize {{.doc}} for {{.audience}}."))
+	got, err := tpl.Render(map[string]any{"doc": "the Q3 report", "audience": "executives"})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize the Q3 report for executives.", got)
+
+	t.Run("nested fields", func(t *testing.T) {
+		t.Parallel()
+
+		type company struct{ Name string }
+		tpl := Must(New("{{.user.name}} works at {{.company.Name}}."))
+		got, err := tpl.Render(map[string]any{
+			"user":    map[string]any{"name": "Ada"},
+			"company": company{Name: "Acme"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Ada works at Acme.", got)
+
+		// A missing nested key fails at render time, naming the key
+		_, err = tpl.Render(map[string]any{
+			"user":    map[string]any{"email": "ada@example.com"},
+			"company": company{Name: "Acme"},
+		})
+		assert.ErrorContains(t, err, `map has no entry for key "name"`)
+	})
+
+	t.Run("no HTML escaping", func(t *testing.T) {
+		t.Parallel()
+
+		tpl := Must(New("Fix this: {{.code}}"))
+		got, err := tpl.Render(map[string]any{"code": `<a href="x?a=1&b=2">'link'</a>`})
+		require.NoError(t, err)
+		assert.Equal(t, `Fix this: <a href="x?a=1&b=2">'link'</a>`, got)
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		t.Parallel()
+
+		tpl := Must(New("{{upper .word}}", WithFuncs(template.FuncMap{"upper": strings.ToUpper})))
+		got, err := tpl.Render(map[string]any{"word": "loud"})
+		require.NoError(t, err)
+		assert.Equal(t, "LOUD", got)
+	})
+}
+
+func TestTemplate_RenderErrors(t *testing.T) {
+	t.Parallel()
+
+	tpl := Must(New("Summarize {{.doc}} for {{.audience}}."))
+
+	_, err := tpl.Render(map[string]any{"doc": "the report"})
+	assert.ErrorIs(t, err, ErrMissingVar)
+	assert.EqualError(t, err, `prompt: missing variable "audience"`)
+
+	_, err = tpl.Render(nil)
+	assert.ErrorIs(t, err, ErrMissingVar)
+	assert.EqualError(t, err, `prompt: missing variables "audience", "doc"`)
+
+	_, err = tpl.Render(map[string]any{"doc": "the report", "audience": "execs", "tone": "dry", "length": 3})
+	assert.ErrorIs(t, err, ErrUnusedVar)
+	assert.EqualError(t, err, `prompt: unused variables "length", "tone"`)
+
+	_, err = New("Summarize {{.doc")
+	assert.ErrorContains(t, err, "prompt: failed to parse template")
+}
+
+func TestTemplate_Strictness(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]any{"doc": "the report", "tone": "dry"}
+
+	tpl := Must(New("Summarize {{.doc}} for {{.audience}}.", WithStrictness(AllowUnused)))
+	_, err := tpl.Render(vars)
+	assert.ErrorIs(t, err, ErrMissingVar)
+
+	tpl = Must(New("Summarize {{.doc}}.", WithStrictness(AllowUnused)))
+	got, err := tpl.Render(vars)
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize the report.", got)
+
+	tpl = Must(New("Summarize {{.doc}} for {{.audience}}.", WithStrictness(Lenient)))
+	got, err = tpl.Render(vars)
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize the report for .", got)
+}
+
+func TestConversation(t *testing.T) {
+	t.Parallel()
+
+	conv, err := NewConversation([]Turn{
+		{Role: "system", Text: "You are a {{.persona}}."},
+		{Role: "user", Text: "Summarize {{.doc}}."},
+		{Role: "assistant", Text: "Here is the summary."},
+		{Role: "user", Text: "Now for {{.audience}}."},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"audience", "doc", "persona"}, conv.RequiredVars())
+
+	turns, err := conv.Render(map[string]any{"persona": "analyst", "doc": "<the report>", "audience": "execs"})
+	require.NoError(t, err)
+	assert.Equal(t, []RenderedTurn{
+		{Role: "system", Content: "You are a analyst."},
+		{Role: "user", Content: "Summarize <the report>."},
+		{Role: "assistant", Content: "Here is the summary."},
+		{Role: "user", Content: "Now for execs."},
+	}, turns)
+
+	// Variables are checked across all turns
+	_, err = conv.Render(map[string]any{"persona": "analyst", "doc": "x"})
+	assert.EqualError(t, err, `prompt: missing variable "audience"`)
+	_, err = conv.Render(map[string]any{"persona": "analyst", "doc": "x", "audience": "y", "extra": 1})
+	assert.True(t, errors.Is(err, ErrUnusedVar))
+
+	_, err = NewConversation([]Turn{{Role: "system", Text: "ok"}, {Role: "user", Text: "{{.broken"}})
+	assert.ErrorContains(t, err, "prompt: failed to parse turn 1 (user)")
+}
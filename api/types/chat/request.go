@@ -1,5 +1,14 @@
 package chat
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/models"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
 // ChatCompletionRequest represents a request to create a chat completion.
 type ChatCompletionRequest struct {
 	// Model is the ID of the model to use.
@@ -28,13 +37,17 @@ type ChatCompletionRequest struct {
 	MaxTokens *int `json:"max_tokens,omitempty"`
 
 	// Stop is a list of sequences where the API will stop generating.
-	Stop []string `json:"stop,omitempty"`
+	Stop StopSequences `json:"stop,omitempty"`
 
 	// Tools is a list of tools the model may call.
 	Tools []Tool `json:"tools,omitempty"`
 
 	// ToolChoice controls which (if any) tool is called by the model.
-	ToolChoice interface{} `json:"tool_choice,omitempty"`
+	ToolChoice *ToolChoiceParam `json:"tool_choice,omitempty"`
+
+	// ParallelToolCalls controls whether the model may call several tools
+	// in one response.
+	ParallelToolCalls *bool `json:"parallel_tool_calls,omitempty"`
 
 	// ResponseFormat specifies the format of the model's output.
 	ResponseFormat *ResponseFormat `json:"response_format,omitempty"`
@@ -72,12 +85,91 @@ type ChatCompletionRequest struct {
 	// Seed is the random seed for deterministic generation.
 	Seed *int `json:"seed,omitempty"`
 
+	// Logprobs requests log probabilities of the output tokens.
+	Logprobs *bool `json:"logprobs,omitempty"`
+
+	// TopLogprobs is the number of most likely tokens to return at each
+	// position. Requires Logprobs.
+	TopLogprobs *int `json:"top_logprobs,omitempty"`
+
 	// Thinking controls the thinking mode for GLM-4.7 and later models.
 	// GLM-4.7 has thinking enabled by default. Use this to disable or configure it.
 	Thinking *ThinkingConfig `json:"thinking,omitempty"`
 
+	// ExtraFields are sent as additional top-level fields, for parameters
+	// the SDK has no typed field for yet. Typed fields take precedence
+	// over entries with the same key.
+	ExtraFields map[string]any `json:"-"`
+
 	// Extra fields for model-specific parameters.
+	//
+	// Deprecated: Use ExtraFields, which take precedence over Extra.
 	Extra map[string]interface{} `json:"-"`
+
+	// memory is the conversation memory set with WithMemory.
+	memory Memory
+
+	// schema is the reply schema set with WithSchema.
+	schema *SchemaConfig
+}
+
+// Penalty bounds for PresencePenalty and FrequencyPenalty.
+const (
+	MinPenalty = -2.0
+	MaxPenalty = 2.0
+)
+
+// StopSequences is a list of stop sequences.
+// It decodes from either a single string or an array of strings.
+type StopSequences []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *StopSequences) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*s = StopSequences{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("stop must be a string or an array of strings: %w", err)
+	}
+	*s = multiple
+	return nil
+}
+
+// Model name constants for chat completions.
+// Any other model name can be passed as a plain string.
+const (
+	ModelGLM47      = models.GLM47
+	ModelGLM46      = models.GLM46
+	ModelGLM45      = models.GLM45
+	ModelGLM45Air   = models.GLM45Air
+	ModelGLM45Flash = models.GLM45Flash
+	ModelGLM4Plus   = models.GLM4Plus
+	ModelGLM4Air    = models.GLM4Air
+	ModelGLM4Flash  = models.GLM4Flash
+	ModelGLM4Long   = models.GLM4Long
+	ModelGLM46V     = models.GLM46V
+	ModelGLM45V     = models.GLM45V
+	ModelGLM4VPlus  = models.GLM4VPlus
+	ModelGLM4VFlash = models.GLM4VFlash
+)
+
+// NewChatCompletionRequest creates a new chat completion request.
+// model accepts a model constant or any string.
+//
+// Example:
+//
+//	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{
+//	    chat.NewUserMessage("Hello!"),
+//	})
+func NewChatCompletionRequest[M ~string](model M, messages []Message) *ChatCompletionRequest {
+	return &ChatCompletionRequest{
+		Model:    string(model),
+		Messages: messages,
+	}
 }
 
 // ThinkingConfig configures the thinking behavior for models that support it.
@@ -92,6 +184,13 @@ type ThinkingConfig struct {
 	// true: Clear reasoning content, don't preserve across turns
 	// Note: When false, you must return complete, unmodified reasoning_content back to the API.
 	ClearThinking *bool `json:"clear_thinking,omitempty"`
+
+	// Effort controls how much the model reasons before answering.
+	// Leave empty to use the model's default.
+	Effort ThinkingEffort `json:"effort,omitempty"`
+
+	// MaxReasoningTokens caps the number of tokens spent on reasoning.
+	MaxReasoningTokens *int `json:"max_reasoning_tokens,omitempty"`
 }
 
 // ThinkingType constants for thinking configuration.
@@ -151,9 +250,43 @@ func (r *ChatCompletionRequest) AddTool(tool Tool) *ChatCompletionRequest {
 	return r
 }
 
-// SetToolChoice sets the tool choice parameter.
+// SetKnowledgeBase grounds the model's answers in the knowledge base
+// collection with the given ID by adding a retrieval tool. It replaces the
+// collection of a retrieval tool already on the request.
+//
+// Example:
+//
+//	req := chat.NewChatCompletionRequest("glm-4.7", messages).
+//	    SetKnowledgeBase(collection.ID)
+func (r *ChatCompletionRequest) SetKnowledgeBase(collectionID string) *ChatCompletionRequest {
+	for i, tool := range r.Tools {
+		if tool.Type == "retrieval" && tool.Retrieval != nil {
+			retrieval := *tool.Retrieval
+			retrieval.KnowledgeID = collectionID
+			r.Tools[i].Retrieval = &retrieval
+			return r
+		}
+	}
+	return r.AddTool(NewRetrievalTool(collectionID))
+}
+
+// SetToolChoice sets the tool choice mode.
 func (r *ChatCompletionRequest) SetToolChoice(choice ToolChoice) *ChatCompletionRequest {
-	r.ToolChoice = string(choice)
+	r.ToolChoice = NewToolChoice(choice)
+	return r
+}
+
+// SetToolChoiceFunction forces the model to call the function with name,
+// which must be one of the request's tools.
+func (r *ChatCompletionRequest) SetToolChoiceFunction(name string) *ChatCompletionRequest {
+	r.ToolChoice = ToolChoiceFunction(name)
+	return r
+}
+
+// SetParallelToolCalls sets whether the model may call several tools in
+// one response.
+func (r *ChatCompletionRequest) SetParallelToolCalls(enabled bool) *ChatCompletionRequest {
+	r.ParallelToolCalls = &enabled
 	return r
 }
 
@@ -195,6 +328,30 @@ func (r *ChatCompletionRequest) EnablePreservedThinking() *ChatCompletionRequest
 	return r
 }
 
+// SetThinkingEffort enables thinking with the given reasoning effort,
+// keeping the rest of the thinking configuration.
+func (r *ChatCompletionRequest) SetThinkingEffort(effort ThinkingEffort) *ChatCompletionRequest {
+	r.enabledThinking().Effort = effort
+	return r
+}
+
+// SetMaxReasoningTokens enables thinking with a reasoning token budget,
+// keeping the rest of the thinking configuration.
+func (r *ChatCompletionRequest) SetMaxReasoningTokens(n int) *ChatCompletionRequest {
+	r.enabledThinking().MaxReasoningTokens = &n
+	return r
+}
+
+// enabledThinking returns the thinking configuration with thinking
+// enabled, creating it if needed.
+func (r *ChatCompletionRequest) enabledThinking() *ThinkingConfig {
+	if r.Thinking == nil {
+		r.Thinking = &ThinkingConfig{}
+	}
+	r.Thinking.Type = ThinkingTypeEnabled
+	return r.Thinking
+}
+
 // SetUserID sets the end-user identifier.
 // The user ID should be 6-128 characters and is used for abuse detection.
 func (r *ChatCompletionRequest) SetUserID(userID string) *ChatCompletionRequest {
@@ -209,9 +366,173 @@ func (r *ChatCompletionRequest) SetRequestID(requestID string) *ChatCompletionRe
 	return r
 }
 
+// WithMemory attaches a conversation memory to the request. Messages then
+// holds only the new messages of the turn, such as the user's question or
+// the results of the tool calls the model requested. When the request is
+// sent, the remembered history is inserted after the request's leading
+// system messages; after a successful response, the new messages and the
+// assistant reply are appended to the memory, so tool call rounds are
+// remembered like any other turn.
+//
+// Chat.Create and Chat.StreamContent update the memory. Chat.CreateStream
+// sends the history but cannot know when the stream is complete; call
+// Remember with the accumulated reply when it is. Clones share the memory.
+//
+// Example:
+//
+//	memory := chat.NewWindowMemory(20)
+//	for _, question := range questions {
+//	    req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{
+//	        chat.NewUserMessage(question),
+//	    }).WithMemory(memory)
+//
+//	    resp, err := client.Chat.Create(ctx, req)
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(resp.GetContent())
+//	}
+func (r *ChatCompletionRequest) WithMemory(memory Memory) *ChatCompletionRequest {
+	r.memory = memory
+	return r
+}
+
+// Memory returns the memory attached with WithMemory, or nil.
+func (r *ChatCompletionRequest) Memory() Memory {
+	return r.memory
+}
+
+// ResolveMemory returns the request to send: a copy of r with the
+// remembered history inserted after its leading system messages. Without
+// a memory it returns r.
+func (r *ChatCompletionRequest) ResolveMemory(ctx context.Context) (*ChatCompletionRequest, error) {
+	if r.memory == nil {
+		return r, nil
+	}
+
+	history, err := r.memory.Messages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load memory: %w", err)
+	}
+
+	system, turn := r.splitSystem()
+	send := *r
+	send.Messages = make([]Message, 0, len(system)+len(history)+len(turn))
+	send.Messages = append(send.Messages, system...)
+	send.Messages = append(send.Messages, history...)
+	send.Messages = append(send.Messages, turn...)
+	return &send, nil
+}
+
+// Remember appends the request's messages, except its leading system
+// messages, and the reply to the memory. It does nothing without a memory.
+func (r *ChatCompletionRequest) Remember(ctx context.Context, reply Message) error {
+	if r.memory == nil {
+		return nil
+	}
+
+	_, turn := r.splitSystem()
+	messages := make([]Message, 0, len(turn)+1)
+	messages = append(messages, turn...)
+	messages = append(messages, reply)
+	if err := r.memory.Append(ctx, messages...); err != nil {
+		return fmt.Errorf("failed to update memory: %w", err)
+	}
+	return nil
+}
+
+// splitSystem splits the messages into the leading system messages and
+// the rest.
+func (r *ChatCompletionRequest) splitSystem() (system, rest []Message) {
+	i := 0
+	for i < len(r.Messages) && r.Messages[i].Role == RoleSystem {
+		i++
+	}
+	return r.Messages[:i], r.Messages[i:]
+}
+
 // SetToolStream enables or disables streaming of tool call responses.
 // When enabled, function call parameters are streamed without buffering.
 func (r *ChatCompletionRequest) SetToolStream(stream bool) *ChatCompletionRequest {
 	r.ToolStream = &stream
 	return r
 }
+
+// SetStop sets the sequences where the API will stop generating.
+func (r *ChatCompletionRequest) SetStop(stop ...string) *ChatCompletionRequest {
+	r.Stop = stop
+	return r
+}
+
+// SetPresencePenalty sets the presence penalty (-2.0 to 2.0).
+func (r *ChatCompletionRequest) SetPresencePenalty(penalty float64) *ChatCompletionRequest {
+	r.PresencePenalty = &penalty
+	return r
+}
+
+// SetFrequencyPenalty sets the frequency penalty (-2.0 to 2.0).
+func (r *ChatCompletionRequest) SetFrequencyPenalty(penalty float64) *ChatCompletionRequest {
+	r.FrequencyPenalty = &penalty
+	return r
+}
+
+// SetSeed sets the random seed for reproducible generation.
+func (r *ChatCompletionRequest) SetSeed(seed int) *ChatCompletionRequest {
+	r.Seed = &seed
+	return r
+}
+
+// SetLogprobs enables or disables log probabilities of the output tokens.
+func (r *ChatCompletionRequest) SetLogprobs(enable bool) *ChatCompletionRequest {
+	r.Logprobs = &enable
+	return r
+}
+
+// SetTopLogprobs sets the number of most likely tokens to return at each
+// position and enables log probabilities.
+func (r *ChatCompletionRequest) SetTopLogprobs(n int) *ChatCompletionRequest {
+	enable := true
+	r.Logprobs = &enable
+	r.TopLogprobs = &n
+	return r
+}
+
+// Validate checks the sampling parameters, the tools (see Tool.Validate),
+// that a named tool choice refers to one of the request's tools and that
+// only prefix messages are marked as cacheable.
+func (r *ChatCompletionRequest) Validate() error {
+	if p := r.PresencePenalty; p != nil && (*p < MinPenalty || *p > MaxPenalty) {
+		return errors.NewValidationError("presence_penalty",
+			fmt.Sprintf("must be between %.1f and %.1f", MinPenalty, MaxPenalty), *p)
+	}
+	if p := r.FrequencyPenalty; p != nil && (*p < MinPenalty || *p > MaxPenalty) {
+		return errors.NewValidationError("frequency_penalty",
+			fmt.Sprintf("must be between %.1f and %.1f", MinPenalty, MaxPenalty), *p)
+	}
+	if n := r.TopLogprobs; n != nil && *n < 0 {
+		return errors.NewValidationError("top_logprobs", "must not be negative", *n)
+	}
+	if err := r.Thinking.validate(); err != nil {
+		return err
+	}
+	for i, tool := range r.Tools {
+		if err := tool.validate(fmt.Sprintf("tools[%d].", i)); err != nil {
+			return err
+		}
+	}
+	if c := r.ToolChoice; c != nil && c.Function != "" && !r.hasFunction(c.Function) {
+		return errors.NewValidationError("tool_choice",
+			fmt.Sprintf("function %q is not one of the request's tools", c.Function), c.Function)
+	}
+	return r.validateCacheControl()
+}
+
+// hasFunction reports whether the request has a function tool with name.
+func (r *ChatCompletionRequest) hasFunction(name string) bool {
+	for _, tool := range r.Tools {
+		if tool.Type == "function" && tool.Function.Name == name {
+			return true
+		}
+	}
+	return false
+}
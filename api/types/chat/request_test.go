@@ -6,8 +6,126 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
+func TestNewChatCompletionRequest(t *testing.T) {
+	t.Parallel()
+
+	messages := []Message{{Role: RoleUser, Content: "Hello"}}
+
+	t.Run("model constant", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewChatCompletionRequest(ModelGLM47, messages)
+		assert.Equal(t, "glm-4.7", req.Model)
+		assert.Equal(t, messages, req.Messages)
+	})
+
+	t.Run("arbitrary string", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewChatCompletionRequest("glm-5-preview", messages)
+		assert.Equal(t, "glm-5-preview", req.Model)
+	})
+}
+
+func TestChatCompletionRequest_SamplingSetters(t *testing.T) {
+	t.Parallel()
+
+	req := NewChatCompletionRequest(ModelGLM47, nil).
+		SetStop("\n\n", "END").
+		SetPresencePenalty(0.5).
+		SetFrequencyPenalty(-1.0).
+		SetSeed(42).
+		SetTopLogprobs(3)
+
+	assert.Equal(t, StopSequences{"\n\n", "END"}, req.Stop)
+	assert.Equal(t, 0.5, *req.PresencePenalty)
+	assert.Equal(t, -1.0, *req.FrequencyPenalty)
+	assert.Equal(t, 42, *req.Seed)
+	assert.True(t, *req.Logprobs)
+	assert.Equal(t, 3, *req.TopLogprobs)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []interface{}{"\n\n", "END"}, decoded["stop"])
+	assert.Equal(t, 0.5, decoded["presence_penalty"])
+	assert.Equal(t, -1.0, decoded["frequency_penalty"])
+	assert.Equal(t, float64(42), decoded["seed"])
+	assert.Equal(t, true, decoded["logprobs"])
+	assert.Equal(t, float64(3), decoded["top_logprobs"])
+
+	req.SetLogprobs(false)
+	assert.False(t, *req.Logprobs)
+}
+
+func TestChatCompletionRequest_SamplingOmitEmpty(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(NewChatCompletionRequest(ModelGLM47, nil))
+	require.NoError(t, err)
+
+	for _, field := range []string{"stop", "presence_penalty", "frequency_penalty", "seed", "logprobs", "top_logprobs"} {
+		assert.NotContains(t, string(data), field)
+	}
+
+	// Explicit zero values are still sent
+	data, err = json.Marshal(NewChatCompletionRequest(ModelGLM47, nil).SetSeed(0).SetPresencePenalty(0))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"seed":0`)
+	assert.Contains(t, string(data), `"presence_penalty":0`)
+}
+
+func TestStopSequences_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var req ChatCompletionRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"stop": "END"}`), &req))
+	assert.Equal(t, StopSequences{"END"}, req.Stop)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"stop": ["a", "b"]}`), &req))
+	assert.Equal(t, StopSequences{"a", "b"}, req.Stop)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"stop": 5}`), &req))
+}
+
+func TestChatCompletionRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     *ChatCompletionRequest
+		wantErr string
+	}{
+		{"no sampling params", NewChatCompletionRequest(ModelGLM47, nil), ""},
+		{"penalties at bounds", NewChatCompletionRequest(ModelGLM47, nil).SetPresencePenalty(-2).SetFrequencyPenalty(2), ""},
+		{"top logprobs zero", NewChatCompletionRequest(ModelGLM47, nil).SetTopLogprobs(0), ""},
+		{"presence penalty too high", NewChatCompletionRequest(ModelGLM47, nil).SetPresencePenalty(2.5), "presence_penalty"},
+		{"frequency penalty too low", NewChatCompletionRequest(ModelGLM47, nil).SetFrequencyPenalty(-2.1), "frequency_penalty"},
+		{"negative top logprobs", NewChatCompletionRequest(ModelGLM47, nil).SetTopLogprobs(-1), "top_logprobs"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
 func TestChatCompletionRequest_Setters(t *testing.T) {
 	t.Parallel()
 
@@ -161,7 +279,7 @@ func TestChatCompletionRequest_Tools(t *testing.T) {
 		req := &ChatCompletionRequest{}
 		req.SetToolChoice(ToolChoiceAuto)
 
-		assert.Equal(t, "auto", req.ToolChoice)
+		assert.Equal(t, NewToolChoice(ToolChoiceAuto), req.ToolChoice)
 	})
 
 	t.Run("chained tool operations", func(t *testing.T) {
@@ -174,10 +292,102 @@ func TestChatCompletionRequest_Tools(t *testing.T) {
 			SetToolChoice(ToolChoiceRequired)
 
 		require.Len(t, req.Tools, 2)
-		assert.Equal(t, "required", req.ToolChoice)
+		assert.Equal(t, ToolChoiceRequired, req.ToolChoice.Mode)
+	})
+}
+
+func TestChatCompletionRequest_ToolChoiceJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		choice *ToolChoiceParam
+		want   string
+	}{
+		{"auto", NewToolChoice(ToolChoiceAuto), `"auto"`},
+		{"none", NewToolChoice(ToolChoiceNone), `"none"`},
+		{"required", NewToolChoice(ToolChoiceRequired), `"required"`},
+		{"function", ToolChoiceFunction("get_weather"), `{"type":"function","function":{"name":"get_weather"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := &ChatCompletionRequest{Model: "glm-4", ToolChoice: tt.choice}
+			data, err := json.Marshal(req)
+			require.NoError(t, err)
+
+			var wire map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(data, &wire))
+			assert.Equal(t, tt.want, string(wire["tool_choice"]))
+
+			var decoded ChatCompletionRequest
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.choice, decoded.ToolChoice)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		var choice ToolChoiceParam
+		assert.Error(t, json.Unmarshal([]byte(`42`), &choice))
 	})
 }
 
+func TestChatCompletionRequest_ParallelToolCalls(t *testing.T) {
+	t.Parallel()
+
+	req := &ChatCompletionRequest{Model: "glm-4"}
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "parallel_tool_calls")
+
+	data, err = json.Marshal(req.SetParallelToolCalls(false))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"parallel_tool_calls":false`)
+}
+
+func TestChatCompletionRequest_Validate_ToolChoice(t *testing.T) {
+	t.Parallel()
+
+	req := &ChatCompletionRequest{Model: "glm-4"}
+	req.AddTool(NewFunctionTool("get_weather", "Get weather", nil))
+
+	assert.NoError(t, req.SetToolChoice(ToolChoiceRequired).Validate())
+	assert.NoError(t, req.SetToolChoiceFunction("get_weather").Validate())
+
+	err := req.SetToolChoiceFunction("get_time").Validate()
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "tool_choice", validationErr.Field)
+	assert.Contains(t, err.Error(), "get_time")
+}
+
+func TestChatCompletionRequest_SetKnowledgeBase(t *testing.T) {
+	t.Parallel()
+
+	req := &ChatCompletionRequest{Model: "glm-4"}
+	req.AddTool(NewFunctionTool("get_weather", "Get weather", nil))
+	req.SetKnowledgeBase("kb-1")
+
+	data, err := json.Marshal(req.Tools)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"type":"function","function":{"name":"get_weather","description":"Get weather"}},
+		{"type":"retrieval","retrieval":{"knowledge_id":"kb-1"}}
+	]`, string(data))
+
+	// Setting it again replaces the collection instead of adding a tool
+	req.Tools[1].Retrieval.PromptTemplate = "Answer from: {{knowledge}}"
+	req.SetKnowledgeBase("kb-2")
+	require.Len(t, req.Tools, 2)
+	assert.Equal(t, "kb-2", req.Tools[1].Retrieval.KnowledgeID)
+	assert.Equal(t, "Answer from: {{knowledge}}", req.Tools[1].Retrieval.PromptTemplate)
+	assert.NoError(t, req.Validate())
+}
+
 func TestChatCompletionRequest_SetResponseFormat(t *testing.T) {
 	t.Parallel()
 
@@ -300,7 +510,7 @@ func TestChatCompletionRequest_CompleteExample(t *testing.T) {
 	assert.Equal(t, 0.7, *req.Temperature)
 	require.NotNil(t, req.MaxTokens)
 	assert.Equal(t, 500, *req.MaxTokens)
-	assert.Equal(t, "auto", req.ToolChoice)
+	assert.Equal(t, ToolChoiceAuto, req.ToolChoice.Mode)
 
 	// Ensure it can be marshaled
 	data, err := json.Marshal(req)
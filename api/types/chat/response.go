@@ -1,6 +1,12 @@
 package chat
 
-import "github.com/sofianhadi1983/zai-sdk-go/internal/models"
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
 
 // ChatCompletionResponse represents the response from a chat completion request.
 type ChatCompletionResponse struct {
@@ -25,8 +31,27 @@ type ChatCompletionResponse struct {
 	// SystemFingerprint is a unique identifier for the model configuration.
 	SystemFingerprint string `json:"system_fingerprint,omitempty"`
 
-	// Extra fields for model-specific data.
-	Extra map[string]interface{} `json:"-"`
+	// ServiceTier is the service tier that processed the request, if the
+	// API reports it.
+	ServiceTier string `json:"service_tier,omitempty"`
+
+	// ModelVersion is the version of the model build that served the
+	// request, if the API reports it.
+	ModelVersion string `json:"model_version,omitempty"`
+
+	// WebSearch is the results of a web search tool, if the request had
+	// one that returns its results.
+	WebSearch []WebSearchResult `json:"web_search,omitempty"`
+
+	// Trimmed reports the messages dropped before the request was sent
+	// again, if Chat.Create trimmed it (see WithAutoTrim).
+	Trimmed *TrimReport `json:"-"`
+
+	// extra holds the response fields the SDK has no typed field for.
+	extra json.RawMessage
+
+	// schema is the schema the reply is validated against.
+	schema *Schema
 }
 
 // Choice represents a completion choice.
@@ -37,9 +62,9 @@ type Choice struct {
 	// Message is the generated message.
 	Message Message `json:"message"`
 
-	// FinishReason is the reason the model stopped generating.
-	// Possible values: "stop", "length", "tool_calls", "content_filter", "function_call"
-	FinishReason string `json:"finish_reason"`
+	// FinishReason is the reason the model stopped generating, such as
+	// FinishReasonStop.
+	FinishReason FinishReason `json:"finish_reason"`
 
 	// LogProbs is the log probabilities for the choice.
 	LogProbs *LogProbs `json:"logprobs,omitempty"`
@@ -78,8 +103,57 @@ type TopLogProb struct {
 	Bytes []int `json:"bytes,omitempty"`
 }
 
-// GetFirstChoice returns the first choice from the response.
-// Returns nil if there are no choices.
+// TokenLogProbs returns the token-level log probabilities of the choice,
+// or nil if logprobs were not requested.
+func (c *Choice) TokenLogProbs() []TokenLogProb {
+	return c.LogProbs.Tokens()
+}
+
+// TokenLogProbs returns the token-level log probabilities of the chunk,
+// or nil if logprobs were not requested.
+func (c *ChunkChoice) TokenLogProbs() []TokenLogProb {
+	return c.LogProbs.Tokens()
+}
+
+// Tokens returns the token-level log probabilities.
+// It is safe to call on a nil LogProbs.
+func (l *LogProbs) Tokens() []TokenLogProb {
+	if l == nil {
+		return nil
+	}
+	return l.Content
+}
+
+// Text returns the tokens concatenated together.
+func (l *LogProbs) Text() string {
+	var sb strings.Builder
+	for _, t := range l.Tokens() {
+		sb.WriteString(t.Token)
+	}
+	return sb.String()
+}
+
+// Sum returns the total log probability of the tokens.
+func (l *LogProbs) Sum() float64 {
+	var sum float64
+	for _, t := range l.Tokens() {
+		sum += t.LogProb
+	}
+	return sum
+}
+
+// Prob returns the probability of the token (e^logprob).
+func (t TokenLogProb) Prob() float64 {
+	return math.Exp(t.LogProb)
+}
+
+// Prob returns the probability of the token (e^logprob).
+func (t TopLogProb) Prob() float64 {
+	return math.Exp(t.LogProb)
+}
+
+// GetFirstChoice returns the first choice, or nil if the response has no
+// choices.
 func (r *ChatCompletionResponse) GetFirstChoice() *Choice {
 	if len(r.Choices) == 0 {
 		return nil
@@ -87,16 +161,20 @@ func (r *ChatCompletionResponse) GetFirstChoice() *Choice {
 	return &r.Choices[0]
 }
 
-// GetContent returns the content of the first choice's message.
-// Returns empty string if there are no choices or the content is not a string.
+// GetContent returns the content of the first choice's message. Content
+// returned as content parts is the concatenated text of its text parts.
+// Returns empty string if there are no choices.
 func (r *ChatCompletionResponse) GetContent() string {
 	choice := r.GetFirstChoice()
 	if choice == nil {
 		return ""
 	}
 
-	if content, ok := choice.Message.Content.(string); ok {
+	switch content := choice.Message.Content.(type) {
+	case string:
 		return content
+	case []ContentPart:
+		return joinText(content)
 	}
 
 	return ""
@@ -113,6 +191,61 @@ func (r *ChatCompletionResponse) GetReasoningContent() string {
 	return choice.Message.ReasoningContent
 }
 
+// ModelBuild identifies the model build that served a request, for
+// reproducibility audits. Fields the API did not report are empty.
+type ModelBuild struct {
+	// Model is the model that served the request.
+	Model string
+
+	// ModelVersion is the version of the model build.
+	ModelVersion string
+
+	// SystemFingerprint identifies the backend configuration.
+	SystemFingerprint string
+
+	// ServiceTier is the service tier that processed the request.
+	ServiceTier string
+}
+
+// IsZero reports whether no field is set.
+func (b ModelBuild) IsZero() bool {
+	return b == ModelBuild{}
+}
+
+// Merge sets the fields of b that are set in other. It is used to collect
+// the build of a stream, whose fields may arrive on different chunks.
+//
+// Example:
+//
+//	var build chat.ModelBuild
+//	for stream.Next() {
+//	    build.Merge(stream.Current().GetModelBuild())
+//	}
+func (b *ModelBuild) Merge(other ModelBuild) {
+	if other.Model != "" {
+		b.Model = other.Model
+	}
+	if other.ModelVersion != "" {
+		b.ModelVersion = other.ModelVersion
+	}
+	if other.SystemFingerprint != "" {
+		b.SystemFingerprint = other.SystemFingerprint
+	}
+	if other.ServiceTier != "" {
+		b.ServiceTier = other.ServiceTier
+	}
+}
+
+// GetModelBuild returns the model build that served the response.
+func (r *ChatCompletionResponse) GetModelBuild() ModelBuild {
+	return ModelBuild{
+		Model:             r.Model,
+		ModelVersion:      r.ModelVersion,
+		SystemFingerprint: r.SystemFingerprint,
+		ServiceTier:       r.ServiceTier,
+	}
+}
+
 // ChatCompletionChunk represents a chunk in a streaming chat completion.
 type ChatCompletionChunk struct {
 	// ID is the unique identifier for the completion.
@@ -131,10 +264,24 @@ type ChatCompletionChunk struct {
 	Choices []ChunkChoice `json:"choices"`
 
 	// SystemFingerprint is a unique identifier for the model configuration.
+	// Like ServiceTier and ModelVersion, it may be sent on the first or the
+	// final chunk only.
 	SystemFingerprint string `json:"system_fingerprint,omitempty"`
 
+	// ServiceTier is the service tier that processed the request, if the
+	// API reports it.
+	ServiceTier string `json:"service_tier,omitempty"`
+
+	// ModelVersion is the version of the model build that served the
+	// request, if the API reports it.
+	ModelVersion string `json:"model_version,omitempty"`
+
 	// Usage is the token usage information (only in the final chunk).
 	Usage *models.Usage `json:"usage,omitempty"`
+
+	// WebSearch is the results of a web search tool, in the chunk that
+	// carries them.
+	WebSearch []WebSearchResult `json:"web_search,omitempty"`
 }
 
 // ChunkChoice represents a choice in a streaming chunk.
@@ -147,7 +294,7 @@ type ChunkChoice struct {
 
 	// FinishReason is the reason the model stopped generating.
 	// Only present in the final chunk.
-	FinishReason string `json:"finish_reason,omitempty"`
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
 
 	// LogProbs is the log probabilities for the choice.
 	LogProbs *LogProbs `json:"logprobs,omitempty"`
@@ -158,9 +305,14 @@ type Delta struct {
 	// Role is the role of the message author (only in the first chunk).
 	Role Role `json:"role,omitempty"`
 
-	// Content is the incremental content.
+	// Content is the incremental content. Content sent as content parts is
+	// the concatenated text of its text parts.
 	Content string `json:"content,omitempty"`
 
+	// ContentParts is the incremental content when it was sent as an
+	// array of content parts, such as by multimodal models.
+	ContentParts []ContentPart `json:"-"`
+
 	// ReasoningContent is the incremental reasoning content when thinking mode is enabled.
 	// This field contains the model's step-by-step reasoning process as it streams.
 	ReasoningContent string `json:"reasoning_content,omitempty"`
@@ -198,3 +350,14 @@ func (c *ChatCompletionChunk) IsFinished() bool {
 	}
 	return c.Choices[0].FinishReason != ""
 }
+
+// GetModelBuild returns the model build fields of the chunk. Use
+// ModelBuild.Merge to collect them over a stream.
+func (c *ChatCompletionChunk) GetModelBuild() ModelBuild {
+	return ModelBuild{
+		Model:             c.Model,
+		ModelVersion:      c.ModelVersion,
+		SystemFingerprint: c.SystemFingerprint,
+		ServiceTier:       c.ServiceTier,
+	}
+}
@@ -2,6 +2,7 @@ package chat
 
 import (
 	"encoding/json"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -70,7 +71,7 @@ func TestChatCompletionResponse_GetContent(t *testing.T) {
 		assert.Equal(t, "", content)
 	})
 
-	t.Run("with non-string content", func(t *testing.T) {
+	t.Run("with content parts", func(t *testing.T) {
 		t.Parallel()
 
 		resp := &ChatCompletionResponse{
@@ -80,14 +81,27 @@ func TestChatCompletionResponse_GetContent(t *testing.T) {
 						Role: RoleAssistant,
 						Content: []ContentPart{
 							NewTextContentPart("Hello"),
+							NewImageContentPart("https://example.com/a.png"),
+							NewTextContentPart(", world"),
 						},
 					},
 				},
 			},
 		}
 
-		content := resp.GetContent()
-		assert.Equal(t, "", content)
+		assert.Equal(t, "Hello, world", resp.GetContent())
+		assert.Len(t, resp.GetContentParts(), 3)
+	})
+
+	t.Run("with content of another type", func(t *testing.T) {
+		t.Parallel()
+
+		resp := &ChatCompletionResponse{
+			Choices: []Choice{{Message: Message{Role: RoleAssistant, Content: 42}}},
+		}
+
+		assert.Equal(t, "", resp.GetContent())
+		assert.Nil(t, resp.GetContentParts())
 	})
 }
 
@@ -131,7 +145,7 @@ func TestChatCompletionResponse_JSON(t *testing.T) {
 
 		choice := resp.Choices[0]
 		assert.Equal(t, 0, choice.Index)
-		assert.Equal(t, "stop", choice.FinishReason)
+		assert.Equal(t, FinishReasonStop, choice.FinishReason)
 		assert.Equal(t, RoleAssistant, choice.Message.Role)
 		assert.Equal(t, "Hello there!", choice.Message.Content)
 
@@ -177,7 +191,7 @@ func TestChatCompletionResponse_JSON(t *testing.T) {
 
 		assert.Len(t, resp.Choices, 1)
 		choice := resp.Choices[0]
-		assert.Equal(t, "tool_calls", choice.FinishReason)
+		assert.Equal(t, FinishReasonToolCalls, choice.FinishReason)
 		require.Len(t, choice.Message.ToolCalls, 1)
 
 		toolCall := choice.Message.ToolCalls[0]
@@ -350,7 +364,7 @@ func TestChatCompletionChunk_JSON(t *testing.T) {
 		err := json.Unmarshal([]byte(jsonData), &chunk)
 		require.NoError(t, err)
 
-		assert.Equal(t, "stop", chunk.Choices[0].FinishReason)
+		assert.Equal(t, FinishReasonStop, chunk.Choices[0].FinishReason)
 		require.NotNil(t, chunk.Usage)
 		assert.Equal(t, 10, chunk.Usage.PromptTokens)
 		assert.True(t, chunk.IsFinished())
@@ -360,21 +374,65 @@ func TestChatCompletionChunk_JSON(t *testing.T) {
 func TestChoice_FinishReasons(t *testing.T) {
 	t.Parallel()
 
-	reasons := []string{"stop", "length", "tool_calls", "content_filter", "function_call"}
+	reasons := []FinishReason{FinishReasonStop, FinishReasonLength, FinishReasonToolCalls,
+		FinishReasonSensitive, FinishReasonNetworkError, "content_filter", "function_call"}
 
 	for _, reason := range reasons {
-		t.Run(reason, func(t *testing.T) {
+		t.Run(reason.String(), func(t *testing.T) {
 			choice := Choice{
 				FinishReason: reason,
 			}
 
 			data, err := json.Marshal(choice)
 			require.NoError(t, err)
-			assert.Contains(t, string(data), reason)
+			assert.Contains(t, string(data), `"finish_reason":"`+reason.String()+`"`)
+
+			var decoded Choice
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, reason, decoded.FinishReason)
 		})
 	}
 }
 
+func TestFinishReason_UnknownValues(t *testing.T) {
+	t.Parallel()
+
+	var resp ChatCompletionResponse
+	err := json.Unmarshal([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"model_context_window_exceeded"}]}`), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, FinishReason("model_context_window_exceeded"), resp.GetFinishReason())
+	assert.False(t, resp.WasTruncated())
+	assert.False(t, resp.RequestedToolCalls())
+	assert.False(t, resp.WasFiltered())
+
+	var chunk ChatCompletionChunk
+	err = json.Unmarshal([]byte(`{"choices":[{"index":0,"delta":{},"finish_reason":"paused"}]}`), &chunk)
+	require.NoError(t, err)
+	assert.Equal(t, FinishReason("paused"), chunk.Choices[0].FinishReason)
+	assert.True(t, chunk.IsFinished())
+}
+
+func TestChatCompletionResponse_FinishHelpers(t *testing.T) {
+	t.Parallel()
+
+	response := func(reason FinishReason) *ChatCompletionResponse {
+		return &ChatCompletionResponse{Choices: []Choice{{FinishReason: reason}}}
+	}
+
+	assert.True(t, response(FinishReasonLength).WasTruncated())
+	assert.False(t, response(FinishReasonStop).WasTruncated())
+	assert.True(t, response(FinishReasonToolCalls).RequestedToolCalls())
+	assert.False(t, response(FinishReasonStop).RequestedToolCalls())
+	assert.True(t, response(FinishReasonSensitive).WasFiltered())
+	assert.False(t, response(FinishReasonNetworkError).WasFiltered())
+
+	empty := &ChatCompletionResponse{}
+	assert.Empty(t, empty.GetFinishReason())
+	assert.False(t, empty.WasTruncated())
+	assert.False(t, empty.RequestedToolCalls())
+	assert.False(t, empty.WasFiltered())
+}
+
 func TestLogProbs_JSON(t *testing.T) {
 	t.Parallel()
 
@@ -413,6 +471,41 @@ func TestLogProbs_JSON(t *testing.T) {
 	assert.Equal(t, "Hi", token.TopLogProbs[1].Token)
 }
 
+func TestLogProbs_Accessors(t *testing.T) {
+	t.Parallel()
+
+	jsonData := `{
+		"index": 0,
+		"message": {"role": "assistant", "content": "Hi there"},
+		"finish_reason": "stop",
+		"logprobs": {"content": [
+			{"token": "Hi", "logprob": -0.1, "top_logprobs": [{"token": "Hi", "logprob": -0.1}, {"token": "Hello", "logprob": -2.5}]},
+			{"token": " there", "logprob": -0.4}
+		]}
+	}`
+
+	var choice Choice
+	require.NoError(t, json.Unmarshal([]byte(jsonData), &choice))
+
+	tokens := choice.TokenLogProbs()
+	require.Len(t, tokens, 2)
+	assert.Equal(t, "Hi", tokens[0].Token)
+	assert.Len(t, tokens[0].TopLogProbs, 2)
+	assert.InDelta(t, math.Exp(-0.1), tokens[0].Prob(), 1e-9)
+	assert.InDelta(t, math.Exp(-2.5), tokens[0].TopLogProbs[1].Prob(), 1e-9)
+
+	assert.Equal(t, "Hi there", choice.LogProbs.Text())
+	assert.InDelta(t, -0.5, choice.LogProbs.Sum(), 1e-9)
+
+	var empty Choice
+	assert.Nil(t, empty.TokenLogProbs())
+	assert.Equal(t, "", empty.LogProbs.Text())
+	assert.Equal(t, 0.0, empty.LogProbs.Sum())
+
+	chunk := ChunkChoice{LogProbs: &LogProbs{Content: []TokenLogProb{{Token: "a"}}}}
+	assert.Len(t, chunk.TokenLogProbs(), 1)
+}
+
 func TestChatCompletionResponse_RealWorldExample(t *testing.T) {
 	t.Parallel()
 
@@ -453,7 +546,7 @@ func TestChatCompletionResponse_RealWorldExample(t *testing.T) {
 
 	choice := resp.GetFirstChoice()
 	require.NotNil(t, choice)
-	assert.Equal(t, "stop", choice.FinishReason)
+	assert.Equal(t, FinishReasonStop, choice.FinishReason)
 
 	// Verify usage
 	require.NotNil(t, resp.Usage)
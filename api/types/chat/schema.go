@@ -0,0 +1,637 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// JSON types used in schemas.
+const (
+	SchemaTypeObject  = "object"
+	SchemaTypeArray   = "array"
+	SchemaTypeString  = "string"
+	SchemaTypeInteger = "integer"
+	SchemaTypeNumber  = "number"
+	SchemaTypeBoolean = "boolean"
+	SchemaTypeNull    = "null"
+)
+
+// Schema is a JSON Schema document describing the structure a reply must
+// have. It supports the keywords needed to describe values encoded from Go
+// types: type, properties, required, items, enum and a boolean
+// additionalProperties. Other keywords are ignored.
+type Schema struct {
+	// Type lists the JSON types the value may have. Empty allows any type.
+	Type SchemaTypes `json:"type,omitempty"`
+
+	// Description describes the value.
+	Description string `json:"description,omitempty"`
+
+	// Properties are the schemas of the properties of an object.
+	Properties map[string]*Schema `json:"properties,omitempty"`
+
+	// Required lists the properties an object must have.
+	Required []string `json:"required,omitempty"`
+
+	// Items is the schema of the elements of an array.
+	Items *Schema `json:"items,omitempty"`
+
+	// Enum lists the values allowed.
+	Enum []any `json:"enum,omitempty"`
+
+	// AdditionalProperties, if false, rejects object properties that are
+	// not listed in Properties.
+	AdditionalProperties *bool `json:"additionalProperties,omitempty"`
+}
+
+// SchemaTypes is the type of a schema: a single JSON type, or several,
+// such as ["string", "null"]. It decodes from either a string or an array
+// of strings.
+type SchemaTypes []string
+
+// MarshalJSON encodes a single type as a string.
+func (t SchemaTypes) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *SchemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = SchemaTypes{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings: %w", err)
+	}
+	*t = multiple
+	return nil
+}
+
+// UnmarshalJSON decodes the schema. An additionalProperties schema is
+// ignored, allowing any additional property.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type alias Schema
+	aux := struct {
+		*alias
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}{alias: (*alias)(s)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	s.AdditionalProperties = nil
+	var allowed bool
+	if json.Unmarshal(aux.AdditionalProperties, &allowed) == nil {
+		s.AdditionalProperties = &allowed
+	}
+	return nil
+}
+
+// ParseSchema parses a JSON Schema document.
+//
+// Example:
+//
+//	schema, err := chat.ParseSchema([]byte(`{
+//	    "type": "object",
+//	    "properties": {"city": {"type": "string"}},
+//	    "required": ["city"]
+//	}`))
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("chat: invalid JSON schema: %w", err)
+	}
+	if err := schema.check("$"); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// check reports unknown types in the schema and its subschemas.
+func (s *Schema) check(path string) error {
+	for _, t := range s.Type {
+		switch t {
+		case SchemaTypeObject, SchemaTypeArray, SchemaTypeString, SchemaTypeInteger,
+			SchemaTypeNumber, SchemaTypeBoolean, SchemaTypeNull:
+		default:
+			return fmt.Errorf("chat: invalid JSON schema: unknown type %q at %s", t, path)
+		}
+	}
+	for name, property := range s.Properties {
+		if property == nil {
+			return fmt.Errorf("chat: invalid JSON schema: property %s has no schema", childPath(path, name))
+		}
+		if err := property.check(childPath(path, name)); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		return s.Items.check(path + "[]")
+	}
+	return nil
+}
+
+// SchemaFor derives the schema of the JSON encoding of T.
+//
+// Properties are named after the json struct tags. Fields are required
+// unless they are pointers or tagged omitempty, and pointers may also be
+// null. The schema struct tag overrides this with "required" or
+// "optional", and restricts the values of a field with "enum=" followed by
+// the values separated by "|"; on a slice, enum applies to its elements.
+// The description struct tag sets the description of a property.
+//
+// Example:
+//
+//	type Forecast struct {
+//	    City  string   `json:"city" description:"City name"`
+//	    Unit  string   `json:"unit" schema:"enum=celsius|fahrenheit"`
+//	    Highs []int    `json:"highs"`
+//	    Note  *string  `json:"note"`
+//	}
+//
+//	schema, err := chat.SchemaFor[Forecast]()
+func SchemaFor[T any]() (*Schema, error) {
+	g := &schemaGenerator{visiting: make(map[reflect.Type]bool)}
+	return g.schema(reflect.TypeFor[T]())
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schemaGenerator derives schemas from Go types.
+type schemaGenerator struct {
+	// visiting holds the structs being derived, to detect recursion
+	visiting map[reflect.Type]bool
+}
+
+// schema derives the schema of t.
+func (g *schemaGenerator) schema(t reflect.Type) (*Schema, error) {
+	switch t {
+	case timeType:
+		return &Schema{Type: SchemaTypes{SchemaTypeString}}, nil
+	case rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		if len(schema.Type) > 0 && !slices.Contains(schema.Type, SchemaTypeNull) {
+			schema.Type = append(schema.Type, SchemaTypeNull)
+		}
+		return schema, nil
+	case reflect.Bool:
+		return &Schema{Type: SchemaTypes{SchemaTypeBoolean}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: SchemaTypes{SchemaTypeInteger}}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaTypes{SchemaTypeNumber}}, nil
+	case reflect.String:
+		return &Schema{Type: SchemaTypes{SchemaTypeString}}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			// Byte slices are encoded as base64 strings
+			return &Schema{Type: SchemaTypes{SchemaTypeString}}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: SchemaTypes{SchemaTypeArray}, Items: items}, nil
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return &Schema{Type: SchemaTypes{SchemaTypeObject}}, nil
+		}
+	case reflect.Struct:
+		return g.object(t)
+	}
+	return nil, fmt.Errorf("chat: cannot derive a JSON schema for type %s", t)
+}
+
+// object derives the schema of a struct.
+func (g *schemaGenerator) object(t reflect.Type) (*Schema, error) {
+	if g.visiting[t] {
+		return nil, fmt.Errorf("chat: cannot derive a JSON schema for recursive type %s", t)
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	schema := &Schema{
+		Type:       SchemaTypes{SchemaTypeObject},
+		Properties: make(map[string]*Schema),
+	}
+	if err := g.addFields(schema, t); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// addFields adds the properties of the fields of struct t to schema,
+// including the fields promoted from embedded structs.
+func (g *schemaGenerator) addFields(schema *Schema, t reflect.Type) error {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := g.addFields(schema, embedded); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := g.schema(field.Type)
+		if err != nil {
+			return err
+		}
+		if description := field.Tag.Get("description"); description != "" {
+			property.Description = description
+		}
+
+		omitted := slices.ContainsFunc(strings.Split(options, ","), func(o string) bool {
+			return o == "omitempty" || o == "omitzero"
+		})
+		required := field.Type.Kind() != reflect.Pointer && !omitted
+
+		for _, option := range strings.Split(field.Tag.Get("schema"), ",") {
+			switch {
+			case option == "required":
+				required = true
+			case option == "optional":
+				required = false
+			case strings.HasPrefix(option, "enum="):
+				if err := setEnum(property, field.Type, strings.Split(option[len("enum="):], "|")); err != nil {
+					return fmt.Errorf("chat: invalid enum of field %s.%s: %w", t, field.Name, err)
+				}
+			}
+		}
+
+		schema.Properties[name] = property
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	return nil
+}
+
+// setEnum restricts the values of schema, derived from t, to values. The
+// values of slices restrict their elements.
+func setEnum(schema *Schema, t reflect.Type, values []string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && schema.Items != nil {
+		return setEnum(schema.Items, t.Elem(), values)
+	}
+
+	enum := make([]any, 0, len(values))
+	for _, value := range values {
+		var (
+			v   any
+			err error
+		)
+		switch t.Kind() {
+		case reflect.String:
+			v = value
+		case reflect.Bool:
+			v, err = strconv.ParseBool(value)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			v, err = strconv.ParseInt(value, 10, 64)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			v, err = strconv.ParseUint(value, 10, 64)
+		case reflect.Float32, reflect.Float64:
+			v, err = strconv.ParseFloat(value, 64)
+		default:
+			return fmt.Errorf("enum is not supported for type %s", t)
+		}
+		if err != nil {
+			return err
+		}
+		enum = append(enum, v)
+	}
+	schema.Enum = enum
+	return nil
+}
+
+// SchemaViolation describes a value that does not match a schema.
+type SchemaViolation struct {
+	// Path locates the value in the document, such as "$.items[0].name".
+	Path string
+
+	// Expected describes what the schema requires.
+	Expected string
+
+	// Got describes the value found.
+	Got string
+}
+
+// String returns the violation as "path: expected ..., got ...".
+func (v SchemaViolation) String() string {
+	return fmt.Sprintf("%s: expected %s, got %s", v.Path, v.Expected, v.Got)
+}
+
+// SchemaValidationError is returned by Chat.Create when the reply still
+// does not match the schema of the request after the corrective retry
+// enabled by WithSchemaRetry.
+type SchemaValidationError struct {
+	// Violations describes where the reply breaks the schema.
+	Violations []SchemaViolation
+}
+
+// Error implements the error interface.
+func (e *SchemaValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.String()
+	}
+	return "chat: reply does not match the schema: " + strings.Join(descriptions, "; ")
+}
+
+// Validate checks the JSON document data against the schema. It returns
+// the violations, or nil if data matches.
+func (s *Schema) Validate(data []byte) []SchemaViolation {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return []SchemaViolation{{Path: "$", Expected: "a JSON document", Got: "no content"}}
+	}
+
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	err := dec.Decode(&value)
+	if err == nil {
+		if dec.Decode(new(json.RawMessage)) != io.EOF {
+			err = fmt.Errorf("data after the JSON document")
+		}
+	}
+	if err != nil {
+		return []SchemaViolation{{Path: "$", Expected: "a JSON document", Got: "invalid JSON (" + err.Error() + ")"}}
+	}
+
+	var violations []SchemaViolation
+	s.validate("$", value, &violations)
+	return violations
+}
+
+// validate appends the violations of value, found at path, to violations.
+func (s *Schema) validate(path string, value any, violations *[]SchemaViolation) {
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return hasType(value, t) }) {
+		*violations = append(*violations, SchemaViolation{
+			Path:     path,
+			Expected: strings.Join(s.Type, " or "),
+			Got:      jsonType(value),
+		})
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.allows(value) {
+		expected, _ := json.Marshal(s.Enum)
+		*violations = append(*violations, SchemaViolation{
+			Path:     path,
+			Expected: "one of " + string(expected),
+			Got:      describeValue(value),
+		})
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, SchemaViolation{
+					Path:     childPath(path, name),
+					Expected: "required property",
+					Got:      "nothing",
+				})
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := s.Properties[name]; ok && property != nil {
+				property.validate(childPath(path, name), v[name], violations)
+			} else if !ok && s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				*violations = append(*violations, SchemaViolation{
+					Path:     childPath(path, name),
+					Expected: "no such property",
+					Got:      jsonType(v[name]),
+				})
+			}
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	}
+}
+
+// allows reports whether value is one of the enum values.
+func (s *Schema) allows(value any) bool {
+	for _, allowed := range s.Enum {
+		// Compare through JSON so that numbers of any Go type match
+		data, err := json.Marshal(allowed)
+		if err != nil {
+			continue
+		}
+		var decoded any
+		if json.Unmarshal(data, &decoded) == nil && reflect.DeepEqual(decoded, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasType reports whether the decoded JSON value has JSON type t.
+func hasType(value any, t string) bool {
+	switch v := value.(type) {
+	case nil:
+		return t == SchemaTypeNull
+	case bool:
+		return t == SchemaTypeBoolean
+	case float64:
+		return t == SchemaTypeNumber || (t == SchemaTypeInteger && v == math.Trunc(v) && !math.IsInf(v, 0))
+	case string:
+		return t == SchemaTypeString
+	case map[string]any:
+		return t == SchemaTypeObject
+	case []any:
+		return t == SchemaTypeArray
+	}
+	return false
+}
+
+// jsonType returns the JSON type of a decoded value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return SchemaTypeNull
+	case bool:
+		return SchemaTypeBoolean
+	case float64:
+		return SchemaTypeNumber
+	case string:
+		return SchemaTypeString
+	case map[string]any:
+		return SchemaTypeObject
+	case []any:
+		return SchemaTypeArray
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// maxDescribedValue is the number of characters of a value shown in a
+// violation.
+const maxDescribedValue = 64
+
+// describeValue returns the JSON encoding of value, shortened if long.
+func describeValue(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return jsonType(value)
+	}
+	if utf8.RuneCount(data) <= maxDescribedValue {
+		return string(data)
+	}
+	return string([]rune(string(data))[:maxDescribedValue]) + "..."
+}
+
+// childPath returns the path of property name of the object at path.
+func childPath(path, name string) string {
+	for i, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return path + "[" + strconv.Quote(name) + "]"
+		}
+	}
+	if name == "" {
+		return path + `[""]`
+	}
+	return path + "." + name
+}
+
+// SchemaConfig holds the schema of a request and how replies that do not
+// match it are handled.
+type SchemaConfig struct {
+	// Schema is the schema replies must match.
+	Schema *Schema
+
+	// RetryOnViolation makes Chat.Create send the request once more when
+	// the reply does not match, followed by the reply and a system
+	// message listing the violations.
+	RetryOnViolation bool
+}
+
+// SchemaOption configures the schema of a request.
+type SchemaOption func(*SchemaConfig)
+
+// WithSchemaRetry makes Chat.Create ask the model once to correct a reply
+// that does not match the schema. If the corrected reply does not match
+// either, Chat.Create returns it with a *SchemaValidationError.
+func WithSchemaRetry() SchemaOption {
+	return func(c *SchemaConfig) {
+		c.RetryOnViolation = true
+	}
+}
+
+// WithSchema attaches a schema to req and requests JSON output unless a
+// response format is set. The schema is used to validate replies on the
+// client: responses returned by Chat.Create carry it, so that
+// ValidateAgainstSchema reports where the reply breaks it. The schema is
+// not sent; describe the expected structure in the messages too.
+//
+// Example:
+//
+//	schema, err := chat.SchemaFor[Forecast]()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	req := chat.WithSchema(chat.NewChatCompletionRequest("glm-4.7", messages), schema, chat.WithSchemaRetry())
+//
+//	resp, err := client.Chat.Create(ctx, req)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, v := range resp.ValidateAgainstSchema() {
+//	    fmt.Println(v)
+//	}
+func WithSchema(req *ChatCompletionRequest, schema *Schema, opts ...SchemaOption) *ChatCompletionRequest {
+	config := &SchemaConfig{Schema: schema}
+	for _, opt := range opts {
+		opt(config)
+	}
+	req.schema = config
+	if req.ResponseFormat == nil {
+		req.SetResponseFormat(ResponseFormatJSON)
+	}
+	return req
+}
+
+// SchemaConfig returns the schema configuration attached with WithSchema,
+// or nil.
+func (r *ChatCompletionRequest) SchemaConfig() *SchemaConfig {
+	return r.schema
+}
+
+// SetSchema sets the schema that ValidateAgainstSchema checks the reply
+// against. Chat.Create sets the schema of the request.
+func (r *ChatCompletionResponse) SetSchema(schema *Schema) {
+	r.schema = schema
+}
+
+// ValidateAgainstSchema checks the content of the first choice against the
+// schema of the response (see WithSchema). It returns the violations, or
+// nil if the content matches or the response has no schema.
+func (r *ChatCompletionResponse) ValidateAgainstSchema() []SchemaViolation {
+	if r.schema == nil {
+		return nil
+	}
+	var content string
+	if choice := r.GetFirstChoice(); choice != nil {
+		content = choice.Message.Text()
+	}
+	return r.schema.Validate([]byte(content))
+}
@@ -0,0 +1,134 @@
+// Package schema builds the JSON Schema of the parameters of function
+// tools, as an alternative to writing nested maps by hand.
+//
+// Example:
+//
+//	params := schema.Object(schema.Properties{
+//	    "location": schema.String("City name, e.g. San Francisco"),
+//	    "unit":     schema.String("Temperature unit", "celsius", "fahrenheit"),
+//	    "days":     schema.Integer(1, 14).Describe("Number of days to forecast"),
+//	}, "location")
+//
+//	tool := chat.NewFunctionTool("get_weather", "Get the weather forecast", params)
+//
+// To derive the schema from a Go struct instead, use
+// chat.NewFunctionToolFromStruct.
+package schema
+
+import (
+	"maps"
+	"math"
+)
+
+// Schema is a JSON Schema. It is a map, so it can be used as the
+// parameters of chat.NewFunctionTool and extended with keywords this
+// package has no helper for.
+type Schema map[string]any
+
+// Properties maps the property names of an object to their schemas.
+type Properties map[string]Schema
+
+// Object returns the schema of an object with the given properties, of
+// which required must be present.
+//
+// Example:
+//
+//	schema.Object(schema.Properties{"query": schema.String("Search query")}, "query")
+func Object(props Properties, required ...string) Schema {
+	if props == nil {
+		props = Properties{}
+	}
+	s := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// String returns the schema of a string. If enum is not empty, the string
+// must be one of its values.
+//
+// Example:
+//
+//	schema.String("Temperature unit", "celsius", "fahrenheit")
+func String(description string, enum ...string) Schema {
+	s := Schema{"type": "string"}
+	if description != "" {
+		s["description"] = description
+	}
+	if len(enum) > 0 {
+		s["enum"] = enum
+	}
+	return s
+}
+
+// Number returns the schema of a number between min and max, inclusive.
+// An infinite bound, such as math.Inf(1), is left out.
+//
+// Example:
+//
+//	schema.Number(0, 1).Describe("Confidence threshold")
+func Number(min, max float64) Schema {
+	return bounded("number", min, max)
+}
+
+// Integer returns the schema of an integer between min and max,
+// inclusive. An infinite bound, such as math.Inf(-1), is left out.
+//
+// Example:
+//
+//	schema.Integer(1, math.Inf(1)).Describe("Page number")
+func Integer(min, max float64) Schema {
+	return bounded("integer", min, max)
+}
+
+// bounded returns the schema of a number of type t between min and max.
+func bounded(t string, min, max float64) Schema {
+	s := Schema{"type": t}
+	if !math.IsInf(min, 0) {
+		s["minimum"] = min
+	}
+	if !math.IsInf(max, 0) {
+		s["maximum"] = max
+	}
+	return s
+}
+
+// Boolean returns the schema of a boolean.
+func Boolean(description string) Schema {
+	s := Schema{"type": "boolean"}
+	if description != "" {
+		s["description"] = description
+	}
+	return s
+}
+
+// Array returns the schema of an array whose elements match items.
+//
+// Example:
+//
+//	schema.Array(schema.String("Tag")).Describe("Tags to filter by")
+func Array(items Schema) Schema {
+	return Schema{"type": "array", "items": items}
+}
+
+// Describe returns a copy of the schema with a description.
+func (s Schema) Describe(description string) Schema {
+	out := maps.Clone(s)
+	if out == nil {
+		out = Schema{}
+	}
+	out["description"] = description
+	return out
+}
+
+// Closed returns a copy of an object schema that rejects properties not
+// listed in its properties, for use with strict tools.
+func (s Schema) Closed() Schema {
+	out := maps.Clone(s)
+	if out == nil {
+		out = Schema{}
+	}
+	out["additionalProperties"] = false
+	return out
+}
@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+)
+
+func TestObject_Golden(t *testing.T) {
+	t.Parallel()
+
+	params := Object(Properties{
+		"location": String("City name, e.g. San Francisco"),
+		"unit":     String("Temperature unit", "celsius", "fahrenheit"),
+		"days":     Integer(1, 14).Describe("Number of days to forecast"),
+		"humidity": Number(0, 1),
+		"alerts":   Boolean("Include weather alerts"),
+		"fields":   Array(String("", "temperature", "wind", "rain")).Describe("Fields to return"),
+		"area": Object(Properties{
+			"lat": Number(-90, 90),
+			"lon": Number(-180, 180),
+		}, "lat", "lon").Closed(),
+	}, "location")
+
+	data, err := json.Marshal(params)
+	require.NoError(t, err)
+	golden.AssertJSON(t, "weather.json", data)
+}
+
+func TestBuilders(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, Schema{"type": "number", "minimum": 0.5}, Number(0.5, math.Inf(1)))
+	assert.Equal(t, Schema{"type": "integer", "maximum": 10.0}, Integer(math.Inf(-1), 10))
+	assert.Equal(t, Schema{"type": "integer"}, Integer(math.Inf(-1), math.Inf(1)))
+	assert.Equal(t, Schema{"type": "string"}, String(""))
+	assert.Equal(t, Schema{"type": "boolean"}, Boolean(""))
+	assert.Equal(t, Schema{"type": "object", "properties": Properties{}}, Object(nil))
+
+	// Describe and Closed return copies
+	s := String("a")
+	described := s.Describe("b")
+	assert.Equal(t, "a", s["description"])
+	assert.Equal(t, "b", described["description"])
+	closed := Object(nil)
+	_ = closed.Closed()
+	assert.NotContains(t, closed, "additionalProperties")
+	assert.Equal(t, "c", Schema(nil).Describe("c")["description"])
+}
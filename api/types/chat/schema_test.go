@@ -0,0 +1,276 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaAddress struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type schemaBase struct {
+	ID string `json:"id"`
+}
+
+type schemaOrder struct {
+	schemaBase
+
+	Customer  string            `json:"customer"`
+	Status    string            `json:"status" schema:"enum=open|shipped|closed"`
+	Priority  int               `json:"priority" schema:"enum=1|2|3"`
+	Tags      []string          `json:"tags" schema:"enum=gift|fragile"`
+	Items     []schemaItem      `json:"items"`
+	Shipping  schemaAddress     `json:"shipping"`
+	Billing   *schemaAddress    `json:"billing"`
+	Note      *string           `json:"note" schema:"required"`
+	Coupon    string            `json:"coupon,omitempty"`
+	Gift      bool              `json:"gift" schema:"optional"`
+	Placed    time.Time         `json:"placed"`
+	Meta      map[string]string `json:"meta"`
+	Raw       json.RawMessage   `json:"raw,omitempty"`
+	Internal  string            `json:"-"`
+	unexposed string
+}
+
+type schemaItem struct {
+	SKU      string  `json:"sku"`
+	Quantity uint    `json:"quantity"`
+	Price    float64 `json:"price"`
+}
+
+func TestSchemaFor(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFor[schemaOrder]()
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaTypes{SchemaTypeObject}, schema.Type)
+	assert.ElementsMatch(t, []string{
+		"id", "customer", "status", "priority", "tags", "items", "shipping", "billing", "note", "coupon", "gift", "placed", "meta", "raw",
+	}, keys(schema.Properties))
+	assert.Equal(t, []string{"id", "customer", "status", "priority", "tags", "items", "shipping", "note", "placed", "meta"}, schema.Required)
+
+	t.Run("nested structs", func(t *testing.T) {
+		shipping := schema.Properties["shipping"]
+		assert.Equal(t, SchemaTypes{SchemaTypeObject}, shipping.Type)
+		assert.Equal(t, []string{"street", "city"}, shipping.Required)
+		assert.Equal(t, SchemaTypes{SchemaTypeString}, shipping.Properties["city"].Type)
+	})
+
+	t.Run("slices", func(t *testing.T) {
+		items := schema.Properties["items"]
+		assert.Equal(t, SchemaTypes{SchemaTypeArray}, items.Type)
+		require.NotNil(t, items.Items)
+		assert.Equal(t, SchemaTypes{SchemaTypeInteger}, items.Items.Properties["quantity"].Type)
+		assert.Equal(t, SchemaTypes{SchemaTypeNumber}, items.Items.Properties["price"].Type)
+	})
+
+	t.Run("pointers are optional and nullable", func(t *testing.T) {
+		billing := schema.Properties["billing"]
+		assert.Equal(t, SchemaTypes{SchemaTypeObject, SchemaTypeNull}, billing.Type)
+		assert.NotContains(t, schema.Required, "billing")
+
+		// Unless tagged required
+		assert.Equal(t, SchemaTypes{SchemaTypeString, SchemaTypeNull}, schema.Properties["note"].Type)
+		assert.Contains(t, schema.Required, "note")
+	})
+
+	t.Run("enum tags", func(t *testing.T) {
+		assert.Equal(t, []any{"open", "shipped", "closed"}, schema.Properties["status"].Enum)
+		assert.Equal(t, []any{int64(1), int64(2), int64(3)}, schema.Properties["priority"].Enum)
+
+		tags := schema.Properties["tags"]
+		assert.Empty(t, tags.Enum)
+		assert.Equal(t, []any{"gift", "fragile"}, tags.Items.Enum, "enum applies to the elements of a slice")
+	})
+
+	t.Run("special types", func(t *testing.T) {
+		assert.Equal(t, SchemaTypes{SchemaTypeString}, schema.Properties["placed"].Type)
+		assert.Equal(t, SchemaTypes{SchemaTypeObject}, schema.Properties["meta"].Type)
+		assert.Empty(t, schema.Properties["raw"].Type)
+	})
+}
+
+func TestSchemaFor_Errors(t *testing.T) {
+	t.Parallel()
+
+	type node struct {
+		Children []node `json:"children"`
+	}
+	_, err := SchemaFor[node]()
+	assert.ErrorContains(t, err, "recursive")
+
+	_, err = SchemaFor[struct {
+		Callback func() `json:"callback"`
+	}]()
+	assert.ErrorContains(t, err, "func()")
+
+	_, err = SchemaFor[struct {
+		Size int `json:"size" schema:"enum=small|large"`
+	}]()
+	assert.ErrorContains(t, err, "invalid enum")
+}
+
+func TestSchemaFor_MarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFor[schemaItem]()
+	require.NoError(t, err)
+	data, err := json.Marshal(schema)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"sku": {"type": "string"},
+			"quantity": {"type": "integer"},
+			"price": {"type": "number"}
+		},
+		"required": ["sku", "quantity", "price"]
+	}`, string(data))
+
+	parsed, err := ParseSchema(data)
+	require.NoError(t, err)
+	assert.Equal(t, schema, parsed)
+}
+
+func TestParseSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": ["string", "null"], "minLength": 1},
+			"scores": {"type": "array", "items": {"type": "integer"}},
+			"extra": {"type": "object", "additionalProperties": {"type": "string"}}
+		},
+		"required": ["name"],
+		"additionalProperties": false
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaTypes{SchemaTypeString, SchemaTypeNull}, schema.Properties["name"].Type)
+	assert.Equal(t, SchemaTypes{SchemaTypeInteger}, schema.Properties["scores"].Items.Type)
+	assert.Nil(t, schema.Properties["extra"].AdditionalProperties, "schema-valued additionalProperties allow any property")
+	require.NotNil(t, schema.AdditionalProperties)
+	assert.False(t, *schema.AdditionalProperties)
+
+	_, err = ParseSchema([]byte(`{"type": "object", "properties": {"a": {"type": "text"}}}`))
+	assert.ErrorContains(t, err, `unknown type "text" at $.a`)
+
+	_, err = ParseSchema([]byte(`{"type": 1}`))
+	assert.Error(t, err)
+}
+
+func TestSchema_Validate(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFor[schemaOrder]()
+	require.NoError(t, err)
+	schema.AdditionalProperties = new(bool)
+
+	valid := `{
+		"id": "o-1", "customer": "Ann", "status": "open", "priority": 2, "tags": ["gift"],
+		"items": [{"sku": "a", "quantity": 1, "price": 9.5}],
+		"shipping": {"street": "Main St", "city": "Oslo"}, "billing": null, "note": null,
+		"placed": "2026-01-02T15:04:05Z", "meta": {}
+	}`
+	assert.Empty(t, schema.Validate([]byte(valid)))
+
+	invalid := `{
+		"id": "o-1", "customer": 7, "status": "lost", "priority": 2.5, "tags": ["gift", "heavy"],
+		"items": [{"sku": "a", "quantity": 1, "price": 9.5}, {"sku": "b", "price": "free"}],
+		"shipping": {"street": "Main St"}, "note": null, "placed": "2026-01-02T15:04:05Z", "meta": {},
+		"discount": 10
+	}`
+	assert.Equal(t, []SchemaViolation{
+		{Path: "$.customer", Expected: "string", Got: "number"},
+		{Path: "$.discount", Expected: "no such property", Got: "number"},
+		{Path: "$.items[1].quantity", Expected: "required property", Got: "nothing"},
+		{Path: "$.items[1].price", Expected: "number", Got: "string"},
+		{Path: "$.priority", Expected: "integer", Got: "number"},
+		{Path: "$.shipping.city", Expected: "required property", Got: "nothing"},
+		{Path: "$.status", Expected: `one of ["open","shipped","closed"]`, Got: `"lost"`},
+		{Path: "$.tags[1]", Expected: `one of ["gift","fragile"]`, Got: `"heavy"`},
+	}, schema.Validate([]byte(invalid)))
+}
+
+func TestSchema_ValidateDocument(t *testing.T) {
+	t.Parallel()
+
+	schema := &Schema{Type: SchemaTypes{SchemaTypeObject}, Required: []string{"a b"}}
+
+	tests := map[string]string{
+		"":           "no content",
+		"not json":   "invalid JSON",
+		`{"a": 1} x`: "data after the JSON document",
+	}
+	for data, got := range tests {
+		violations := schema.Validate([]byte(data))
+		require.Len(t, violations, 1, data)
+		assert.Equal(t, "$", violations[0].Path)
+		assert.Contains(t, violations[0].Got, got)
+	}
+
+	assert.Equal(t, []SchemaViolation{
+		{Path: `$["a b"]`, Expected: "required property", Got: "nothing"},
+	}, schema.Validate([]byte(`{}`)))
+	assert.Equal(t, []SchemaViolation{
+		{Path: "$", Expected: "object", Got: "array"},
+	}, schema.Validate([]byte(`[]`)))
+}
+
+func TestWithSchema(t *testing.T) {
+	t.Parallel()
+
+	schema, err := SchemaFor[schemaAddress]()
+	require.NoError(t, err)
+
+	req := WithSchema(NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("Where?")}), schema, WithSchemaRetry())
+	require.NotNil(t, req.SchemaConfig())
+	assert.Same(t, schema, req.SchemaConfig().Schema)
+	assert.True(t, req.SchemaConfig().RetryOnViolation)
+	assert.Equal(t, &ResponseFormatJSON, req.ResponseFormat)
+
+	// A response format set before is kept, and the schema is not sent
+	req = NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("Where?")}).SetResponseFormat(ResponseFormatText)
+	WithSchema(req, schema)
+	assert.Equal(t, &ResponseFormatText, req.ResponseFormat)
+	assert.False(t, req.SchemaConfig().RetryOnViolation)
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "street")
+}
+
+func TestChatCompletionResponse_ValidateAgainstSchema(t *testing.T) {
+	t.Parallel()
+
+	var resp ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"choices":[{"message":{"role":"assistant","content":"{\"street\":\"Main St\"}"}}]}`), &resp))
+	assert.Nil(t, resp.ValidateAgainstSchema(), "no schema")
+
+	schema, err := SchemaFor[schemaAddress]()
+	require.NoError(t, err)
+	resp.SetSchema(schema)
+	assert.Equal(t, []SchemaViolation{
+		{Path: "$.city", Expected: "required property", Got: "nothing"},
+	}, resp.ValidateAgainstSchema())
+
+	err = &SchemaValidationError{Violations: resp.ValidateAgainstSchema()}
+	assert.EqualError(t, err, "chat: reply does not match the schema: $.city: expected required property, got nothing")
+}
+
+// keys returns the keys of m.
+func keys[V any](m map[string]V) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,372 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+)
+
+// SessionClient sends the requests of a Session. ChatService.NewSession
+// creates sessions backed by the chat service.
+type SessionClient interface {
+	// Create sends a chat completion request.
+	Create(ctx context.Context, req *ChatCompletionRequest, opts ...CreateOption) (*ChatCompletionResponse, error)
+
+	// CreateChunkStream sends a streaming chat completion request.
+	CreateChunkStream(ctx context.Context, req *ChatCompletionRequest, opts ...StreamOption) (ChunkStream, error)
+}
+
+// SessionConfig holds the settings of a Session.
+type SessionConfig struct {
+	// SystemPrompt is sent as a system message before the history of every
+	// turn. It is not part of the history.
+	SystemPrompt string
+
+	// Tools are sent with every turn.
+	Tools []Tool
+
+	// Thinking is the thinking configuration of every turn. With preserved
+	// thinking (clear_thinking false), the reasoning content of earlier
+	// replies is sent back with the history; otherwise it is left out.
+	Thinking *ThinkingConfig
+
+	// Memory, if set, stores the history sent with each turn instead of
+	// the session, for example to keep a window of the conversation or to
+	// persist it.
+	Memory Memory
+
+	// Configure, if set, is called with the request of every turn before
+	// it is sent, to set parameters such as the temperature.
+	Configure func(*ChatCompletionRequest)
+}
+
+// SessionOption configures a Session.
+type SessionOption func(*SessionConfig)
+
+// NewSessionConfig creates a session configuration with the given options applied.
+func NewSessionConfig(opts ...SessionOption) *SessionConfig {
+	cfg := &SessionConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithSystemPrompt sends prompt as the system message of every turn.
+func WithSystemPrompt(prompt string) SessionOption {
+	return func(c *SessionConfig) {
+		c.SystemPrompt = prompt
+	}
+}
+
+// WithSessionTools sends tools with every turn. Replies that call tools are
+// answered with Session.SendMessages and NewToolMessage.
+func WithSessionTools(tools ...Tool) SessionOption {
+	return func(c *SessionConfig) {
+		c.Tools = append(c.Tools, tools...)
+	}
+}
+
+// WithSessionThinking sets the thinking configuration of every turn.
+func WithSessionThinking(config *ThinkingConfig) SessionOption {
+	return func(c *SessionConfig) {
+		c.Thinking = config
+	}
+}
+
+// WithPreservedThinking enables thinking with reasoning continuity, as
+// ChatCompletionRequest.EnablePreservedThinking does, and sends the
+// reasoning content of earlier replies back unmodified with every turn.
+func WithPreservedThinking() SessionOption {
+	return func(c *SessionConfig) {
+		c.Thinking = (&ChatCompletionRequest{}).EnablePreservedThinking().Thinking
+	}
+}
+
+// WithSessionMemory stores the history in memory. See SessionConfig.Memory.
+func WithSessionMemory(memory Memory) SessionOption {
+	return func(c *SessionConfig) {
+		c.Memory = memory
+	}
+}
+
+// WithSessionRequest calls configure with the request of every turn before
+// it is sent.
+//
+// Example:
+//
+//	session := client.Chat.NewSession("glm-4.7",
+//	    chat.WithSessionRequest(func(req *chat.ChatCompletionRequest) {
+//	        req.SetTemperature(0.7).SetMaxTokens(2000)
+//	    }),
+//	)
+func WithSessionRequest(configure func(*ChatCompletionRequest)) SessionOption {
+	return func(c *SessionConfig) {
+		c.Configure = configure
+	}
+}
+
+// Session is a multi-turn conversation. Each turn sends the system prompt,
+// the history and the new messages, and a successful reply is appended to
+// the history together with the new messages; a failed turn leaves the
+// history unchanged, so it can be sent again.
+//
+// A Session is meant for sequential use and is not safe for concurrent
+// use. Use Fork to branch a conversation.
+type Session struct {
+	client  SessionClient
+	model   string
+	config  SessionConfig
+	history []Message
+}
+
+// NewSession creates a session that sends its requests with client. model
+// may be empty if the client has a default model.
+func NewSession(client SessionClient, model string, opts ...SessionOption) *Session {
+	return &Session{
+		client: client,
+		model:  model,
+		config: *NewSessionConfig(opts...),
+	}
+}
+
+// Model returns the model of the session.
+func (s *Session) Model() string {
+	return s.model
+}
+
+// History returns a copy of the messages exchanged in the session, without
+// the system prompt. Replies keep their reasoning content. With a Memory,
+// the history sent with a turn is the memory's instead and may be shorter.
+func (s *Session) History() []Message {
+	history := make([]Message, len(s.history))
+	for i, m := range s.history {
+		history[i] = m.Clone()
+	}
+	return history
+}
+
+// Fork returns a copy of the session, with its own copy of the history,
+// that continues the conversation independently of s. The fork shares the
+// client and the configuration, except for the Memory: the fork keeps its
+// history itself, starting from the history of s.
+//
+// Example:
+//
+//	formal := session.Fork()
+//	casual := session.Fork()
+//	formal.Send(ctx, "Rewrite that formally.")
+//	casual.Send(ctx, "Rewrite that casually.")
+func (s *Session) Fork() *Session {
+	fork := &Session{
+		client:  s.client,
+		model:   s.model,
+		config:  s.config,
+		history: s.History(),
+	}
+	fork.config.Memory = nil
+	return fork
+}
+
+// Send sends userText as the next user message and returns the response.
+//
+// Example:
+//
+//	session := client.Chat.NewSession("glm-4.7", chat.WithPreservedThinking())
+//
+//	resp, err := session.Send(ctx, "If apples cost $2 and oranges $3, and 9 fruits cost $23, how many of each?")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(resp.GetContent())
+//
+//	resp, err = session.Send(ctx, "Verify that answer by checking the totals.")
+func (s *Session) Send(ctx context.Context, userText string, opts ...CreateOption) (*ChatCompletionResponse, error) {
+	return s.SendMessages(ctx, []Message{NewUserMessage(userText)}, opts...)
+}
+
+// SendMessages sends messages as the next turn, for example multimodal
+// user messages or the results of the tool calls of the last reply.
+func (s *Session) SendMessages(ctx context.Context, messages []Message, opts ...CreateOption) (*ChatCompletionResponse, error) {
+	req, err := s.request(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Create(ctx, req, opts...)
+	if err != nil {
+		return resp, err
+	}
+	if choice := resp.GetFirstChoice(); choice != nil {
+		if err := s.record(ctx, messages, choice.Message); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// SendStream sends userText as the next user message and streams the
+// reply. The reply is appended to the history once the stream is read to
+// the end; a stream that fails or is closed early leaves the history
+// unchanged. Finish or close the stream before the next turn.
+//
+// Example:
+//
+//	stream, err := session.SendStream(ctx, "Tell me a story")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//	    if chunk := stream.Current().Chunk; chunk != nil {
+//	        fmt.Print(chunk.GetContent())
+//	    }
+//	}
+//	if err := stream.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (s *Session) SendStream(ctx context.Context, userText string, opts ...StreamOption) (*SessionStream, error) {
+	return s.SendMessagesStream(ctx, []Message{NewUserMessage(userText)}, opts...)
+}
+
+// SendMessagesStream sends messages as the next turn and streams the
+// reply, as SendStream does.
+func (s *Session) SendMessagesStream(ctx context.Context, messages []Message, opts ...StreamOption) (*SessionStream, error) {
+	req, err := s.request(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := s.client.CreateChunkStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionStream{
+		ToolCallCollector: NewToolCallCollector(stream),
+		ctx:               ctx,
+		session:           s,
+		messages:          messages,
+	}, nil
+}
+
+// request builds the request of a turn that sends messages.
+func (s *Session) request(ctx context.Context, messages []Message) (*ChatCompletionRequest, error) {
+	history := s.history
+	if s.config.Memory != nil {
+		var err error
+		if history, err = s.config.Memory.Messages(ctx); err != nil {
+			return nil, fmt.Errorf("failed to load memory: %w", err)
+		}
+	}
+
+	req := &ChatCompletionRequest{
+		Model:    s.model,
+		Messages: make([]Message, 0, len(history)+len(messages)+1),
+	}
+	if s.config.SystemPrompt != "" {
+		req.Messages = append(req.Messages, NewSystemMessage(s.config.SystemPrompt))
+	}
+	for _, m := range history {
+		req.Messages = append(req.Messages, m.Clone())
+	}
+	for _, m := range messages {
+		req.Messages = append(req.Messages, m.Clone())
+	}
+	for _, tool := range s.config.Tools {
+		req.Tools = append(req.Tools, tool.Clone())
+	}
+	if s.config.Thinking != nil {
+		thinking := deepCopy(*s.config.Thinking)
+		req.Thinking = &thinking
+	}
+	if s.config.Configure != nil {
+		s.config.Configure(req)
+	}
+
+	if !preservesThinking(req.Thinking) {
+		for i := range req.Messages {
+			if req.Messages[i].Role == RoleAssistant {
+				req.Messages[i].ReasoningContent = ""
+			}
+		}
+	}
+	return req, nil
+}
+
+// record appends the messages of a turn and its reply to the history.
+func (s *Session) record(ctx context.Context, messages []Message, reply Message) error {
+	turn := make([]Message, 0, len(messages)+1)
+	for _, m := range messages {
+		turn = append(turn, m.Clone())
+	}
+	turn = append(turn, reply.Clone())
+
+	if s.config.Memory != nil {
+		if err := s.config.Memory.Append(ctx, turn...); err != nil {
+			return fmt.Errorf("failed to update memory: %w", err)
+		}
+	}
+	s.history = append(s.history, turn...)
+	return nil
+}
+
+// preservesThinking reports whether config keeps reasoning content across
+// turns.
+func preservesThinking(config *ThinkingConfig) bool {
+	return config != nil && config.Type != ThinkingTypeDisabled &&
+		config.ClearThinking != nil && !*config.ClearThinking
+}
+
+// SessionStream is the streamed reply of a Session turn. It assembles
+// streamed tool calls as ToolCallCollector does, and appends the reply to
+// the session's history when the stream ends.
+type SessionStream struct {
+	*ToolCallCollector
+
+	ctx       context.Context
+	session   *Session
+	messages  []Message
+	reasoning []byte
+	recorded  bool
+	err       error
+}
+
+// Next advances to the next event. It returns false when the stream is
+// exhausted or an error occurs.
+func (s *SessionStream) Next() bool {
+	if s.err != nil {
+		return false
+	}
+	if s.ToolCallCollector.Next() {
+		if chunk := s.Current().Chunk; chunk != nil {
+			for _, choice := range chunk.Choices {
+				if choice.Index == 0 {
+					s.reasoning = append(s.reasoning, choice.Delta.ReasoningContent...)
+				}
+			}
+		}
+		return true
+	}
+
+	if s.ToolCallCollector.Err() == nil && !s.recorded {
+		s.recorded = true
+		s.err = s.session.record(s.ctx, s.messages, s.Message())
+	}
+	return false
+}
+
+// Err returns the error that stopped the stream, if any.
+func (s *SessionStream) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.ToolCallCollector.Err()
+}
+
+// Message returns the assistant reply streamed so far, with its content,
+// reasoning content and completed tool calls.
+func (s *SessionStream) Message() Message {
+	message := s.ToolCallCollector.Message()
+	message.ReasoningContent = string(s.reasoning)
+	return message
+}
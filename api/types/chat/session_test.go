@@ -0,0 +1,268 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSessionClient records the requests of a session and answers with
+// numbered replies, with reasoning content.
+type fakeSessionClient struct {
+	requests []*ChatCompletionRequest
+	err      error
+	chunks   []*ChatCompletionChunk
+}
+
+func (c *fakeSessionClient) Create(ctx context.Context, req *ChatCompletionRequest, opts ...CreateOption) (*ChatCompletionResponse, error) {
+	c.requests = append(c.requests, req)
+	if c.err != nil {
+		return nil, c.err
+	}
+	n := len(c.requests)
+	return &ChatCompletionResponse{
+		Choices: []Choice{{Message: Message{
+			Role:             RoleAssistant,
+			Content:          fmt.Sprintf("a%d", n),
+			ReasoningContent: fmt.Sprintf("r%d", n),
+		}}},
+	}, nil
+}
+
+func (c *fakeSessionClient) CreateChunkStream(ctx context.Context, req *ChatCompletionRequest, opts ...StreamOption) (ChunkStream, error) {
+	c.requests = append(c.requests, req)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &sliceStream{chunks: c.chunks}, nil
+}
+
+// last returns the last request sent.
+func (c *fakeSessionClient) last() *ChatCompletionRequest {
+	return c.requests[len(c.requests)-1]
+}
+
+func TestSession_HistoryGrows(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{}
+	session := NewSession(client, "glm-4.7",
+		WithSystemPrompt("sys"),
+		WithSessionTools(NewFunctionTool("lookup", "Look up a word", nil)),
+		WithSessionRequest(func(req *ChatCompletionRequest) {
+			req.SetTemperature(0.5)
+		}),
+	)
+
+	resp, err := session.Send(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "a1", resp.GetContent())
+	assert.Equal(t, []string{"sys", "u1"}, contents(client.last().Messages))
+
+	_, err = session.Send(ctx, "u2")
+	require.NoError(t, err)
+	req := client.last()
+	assert.Equal(t, []string{"sys", "u1", "a1", "u2"}, contents(req.Messages))
+	assert.Equal(t, "glm-4.7", req.Model)
+	assert.Len(t, req.Tools, 1)
+	require.NotNil(t, req.Temperature)
+	assert.Equal(t, 0.5, *req.Temperature)
+
+	// The system prompt is not part of the history
+	history := session.History()
+	assert.Equal(t, []string{"u1", "a1", "u2", "a2"}, contents(history))
+
+	// History returns a copy
+	history[0].Content = "changed"
+	assert.Equal(t, "u1", session.History()[0].Content)
+}
+
+func TestSession_PreservedThinking(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("preserved", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeSessionClient{}
+		session := NewSession(client, "glm-4.7", WithPreservedThinking())
+		_, err := session.Send(ctx, "u1")
+		require.NoError(t, err)
+		_, err = session.Send(ctx, "u2")
+		require.NoError(t, err)
+
+		req := client.last()
+		require.NotNil(t, req.Thinking)
+		require.NotNil(t, req.Thinking.ClearThinking)
+		assert.False(t, *req.Thinking.ClearThinking)
+		assert.Equal(t, "r1", req.Messages[1].ReasoningContent, "the reasoning content is sent back")
+	})
+
+	t.Run("preserved by the request hook", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeSessionClient{}
+		session := NewSession(client, "glm-4.7", WithSessionRequest(func(req *ChatCompletionRequest) {
+			req.EnablePreservedThinking()
+		}))
+		_, err := session.Send(ctx, "u1")
+		require.NoError(t, err)
+		_, err = session.Send(ctx, "u2")
+		require.NoError(t, err)
+
+		assert.Equal(t, "r1", client.last().Messages[1].ReasoningContent)
+	})
+
+	t.Run("not preserved", func(t *testing.T) {
+		t.Parallel()
+
+		client := &fakeSessionClient{}
+		session := NewSession(client, "glm-4.7")
+		_, err := session.Send(ctx, "u1")
+		require.NoError(t, err)
+		_, err = session.Send(ctx, "u2")
+		require.NoError(t, err)
+
+		assert.Empty(t, client.last().Messages[1].ReasoningContent)
+		assert.Equal(t, "r1", session.History()[1].ReasoningContent, "the history keeps the reasoning content")
+	})
+}
+
+func TestSession_FailedTurn(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{}
+	session := NewSession(client, "glm-4.7")
+	_, err := session.Send(ctx, "u1")
+	require.NoError(t, err)
+
+	client.err = errors.New("unavailable")
+	_, err = session.Send(ctx, "u2")
+	require.Error(t, err)
+	assert.Equal(t, []string{"u1", "a1"}, contents(session.History()))
+
+	client.err = nil
+	_, err = session.Send(ctx, "u2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "a1", "u2"}, contents(client.last().Messages))
+}
+
+func TestSession_Fork(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{}
+	session := NewSession(client, "glm-4.7", WithSystemPrompt("sys"))
+	_, err := session.Send(ctx, "u1")
+	require.NoError(t, err)
+
+	fork := session.Fork()
+	_, err = fork.Send(ctx, "fork")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "u1", "a1", "fork"}, contents(client.last().Messages))
+
+	_, err = session.Send(ctx, "main")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys", "u1", "a1", "main"}, contents(client.last().Messages))
+
+	assert.Equal(t, []string{"u1", "a1", "fork", "a2"}, contents(fork.History()))
+	assert.Equal(t, []string{"u1", "a1", "main", "a3"}, contents(session.History()))
+}
+
+func TestSession_Memory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{}
+	memory := NewWindowMemory(2)
+	session := NewSession(client, "glm-4.7", WithSessionMemory(memory))
+
+	for _, text := range []string{"u1", "u2", "u3"} {
+		_, err := session.Send(ctx, text)
+		require.NoError(t, err)
+	}
+
+	// The window of the memory is sent, the session keeps everything
+	assert.Equal(t, []string{"u2", "a2", "u3"}, contents(client.last().Messages))
+	assert.Len(t, session.History(), 6)
+
+	stored, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "a3"}, contents(stored))
+
+	// A fork does not write to the memory
+	_, err = session.Fork().Send(ctx, "fork")
+	require.NoError(t, err)
+	stored, err = memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "a3"}, contents(stored))
+}
+
+func TestSession_SendStream(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{chunks: []*ChatCompletionChunk{
+		deltaChunk(Delta{ReasoningContent: "thinking "}, ""),
+		deltaChunk(Delta{ReasoningContent: "done"}, ""),
+		deltaChunk(Delta{Content: "Hel"}, ""),
+		deltaChunk(Delta{Content: "lo"}, ""),
+		toolDelta(fragment(0, "call_1", "lookup", `{"word":"hi"}`)),
+		deltaChunk(Delta{}, "tool_calls"),
+	}}
+	session := NewSession(client, "glm-4.7", WithPreservedThinking())
+
+	stream, err := session.SendStream(ctx, "u1")
+	require.NoError(t, err)
+
+	var content string
+	for stream.Next() {
+		if chunk := stream.Current().Chunk; chunk != nil {
+			content += chunk.GetContent()
+		}
+		assert.Empty(t, session.History(), "the reply is recorded when the stream ends")
+	}
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+	assert.Equal(t, "Hello", content)
+
+	history := session.History()
+	require.Len(t, history, 2)
+	reply := history[1]
+	assert.Equal(t, RoleAssistant, reply.Role)
+	assert.Equal(t, "Hello", reply.Content)
+	assert.Equal(t, "thinking done", reply.ReasoningContent)
+	require.Len(t, reply.ToolCalls, 1)
+	assert.Equal(t, "lookup", reply.ToolCalls[0].Function.Name)
+
+	// The tool result continues the conversation with the reasoning
+	client.chunks = nil
+	_, err = session.SendMessages(ctx, []Message{NewToolMessage("call_1", "a greeting")})
+	require.NoError(t, err)
+	sent := client.last().Messages
+	require.Len(t, sent, 3)
+	assert.Equal(t, "thinking done", sent[1].ReasoningContent)
+	assert.Equal(t, RoleTool, sent[2].Role)
+}
+
+func TestSession_SendStream_Error(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := &fakeSessionClient{chunks: []*ChatCompletionChunk{deltaChunk(Delta{Content: "Hel"}, "")}}
+	session := NewSession(client, "glm-4.7")
+
+	stream, err := session.SendStream(ctx, "u1")
+	require.NoError(t, err)
+	stream.ToolCallCollector.stream.(*sliceStream).err = errors.New("connection reset")
+	for stream.Next() {
+	}
+	require.Error(t, stream.Err())
+	assert.Empty(t, session.History())
+}
@@ -0,0 +1,38 @@
+package chat
+
+import "time"
+
+// StreamConfig holds settings for a chat completion stream.
+type StreamConfig struct {
+	// IdleTimeout overrides the client's stream idle timeout if not zero.
+	// Negative disables the idle timeout for the stream.
+	IdleTimeout time.Duration
+}
+
+// StreamOption configures a chat completion stream.
+type StreamOption func(*StreamConfig)
+
+// NewStreamConfig creates a stream configuration with the given options applied.
+func NewStreamConfig(opts ...StreamOption) *StreamConfig {
+	cfg := &StreamConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithStreamIdleTimeout aborts the stream with an
+// *errors.StreamIdleTimeoutError if no event arrives within timeout. The
+// timer restarts with every event. A negative timeout disables the client's
+// default idle timeout for this stream.
+//
+// Example:
+//
+//	stream, err := client.Chat.CreateStream(ctx, req,
+//	    chat.WithStreamIdleTimeout(30*time.Second),
+//	)
+func WithStreamIdleTimeout(timeout time.Duration) StreamOption {
+	return func(c *StreamConfig) {
+		c.IdleTimeout = timeout
+	}
+}
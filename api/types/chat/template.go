@@ -0,0 +1,62 @@
+package chat
+
+import (
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat/prompt"
+)
+
+// NewUserMessageFromTemplate renders tpl with vars into a user message.
+// The error names any missing or unused variable.
+//
+// Example:
+//
+//	var summarize = prompt.Must(prompt.New("Summarize {{.doc}} for {{.audience}}"))
+//
+//	msg, err := chat.NewUserMessageFromTemplate(summarize, map[string]any{
+//	    "doc":      doc,
+//	    "audience": "executives",
+//	})
+func NewUserMessageFromTemplate(tpl *prompt.Template, vars map[string]any) (Message, error) {
+	content, err := tpl.Render(vars)
+	if err != nil {
+		return Message{}, err
+	}
+	return NewUserMessage(content), nil
+}
+
+// NewSystemMessageFromTemplate renders tpl with vars into a system message.
+// The error names any missing or unused variable.
+func NewSystemMessageFromTemplate(tpl *prompt.Template, vars map[string]any) (Message, error) {
+	content, err := tpl.Render(vars)
+	if err != nil {
+		return Message{}, err
+	}
+	return NewSystemMessage(content), nil
+}
+
+// NewMessagesFromTemplate renders a conversation template with vars into
+// messages, one per turn, with the roles of the turns.
+//
+// Example:
+//
+//	conv := prompt.MustConversation(prompt.NewConversation([]prompt.Turn{
+//	    {Role: "system", Text: "You are a {{.persona}}."},
+//	    {Role: "user", Text: "Summarize {{.doc}}."},
+//	}))
+//
+//	messages, err := chat.NewMessagesFromTemplate(conv, vars)
+//	if err != nil {
+//	    return err
+//	}
+//	req := chat.NewChatCompletionRequest(chat.ModelGLM4Plus, messages)
+func NewMessagesFromTemplate(conv *prompt.Conversation, vars map[string]any) ([]Message, error) {
+	turns, err := conv.Render(vars)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]Message, len(turns))
+	for i, turn := range turns {
+		messages[i] = Message{Role: Role(turn.Role), Content: turn.Content}
+	}
+	return messages, nil
+}
@@ -0,0 +1,47 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat/prompt"
+)
+
+func TestNewMessageFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	tpl := prompt.Must(prompt.New("Summarize {{.doc}}"))
+
+	msg, err := NewUserMessageFromTemplate(tpl, map[string]any{"doc": "the report"})
+	require.NoError(t, err)
+	assert.Equal(t, NewUserMessage("Summarize the report"), msg)
+
+	msg, err = NewSystemMessageFromTemplate(tpl, map[string]any{"doc": "the report"})
+	require.NoError(t, err)
+	assert.Equal(t, NewSystemMessage("Summarize the report"), msg)
+
+	_, err = NewUserMessageFromTemplate(tpl, map[string]any{})
+	assert.ErrorIs(t, err, prompt.ErrMissingVar)
+	assert.ErrorContains(t, err, `"doc"`)
+}
+
+func TestNewMessagesFromTemplate(t *testing.T) {
+	t.Parallel()
+
+	conv := prompt.MustConversation(prompt.NewConversation([]prompt.Turn{
+		{Role: "system", Text: "You are a {{.persona}}."},
+		{Role: "user", Text: "Summarize {{.doc}}."},
+	}))
+
+	messages, err := NewMessagesFromTemplate(conv, map[string]any{"persona": "analyst", "doc": "the report"})
+	require.NoError(t, err)
+	assert.Equal(t, []Message{
+		NewSystemMessage("You are a analyst."),
+		NewUserMessage("Summarize the report."),
+	}, messages)
+
+	_, err = NewMessagesFromTemplate(conv, map[string]any{"persona": "analyst"})
+	assert.ErrorIs(t, err, prompt.ErrMissingVar)
+}
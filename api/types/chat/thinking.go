@@ -0,0 +1,104 @@
+package chat
+
+import (
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/models"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// ThinkingEffort is the reasoning effort of a thinking model.
+type ThinkingEffort string
+
+// ThinkingEffort constants.
+const (
+	ThinkingEffortLow    ThinkingEffort = "low"
+	ThinkingEffortMedium ThinkingEffort = "medium"
+	ThinkingEffortHigh   ThinkingEffort = "high"
+)
+
+// ThinkingSupport describes which thinking fields a model accepts.
+type ThinkingSupport struct {
+	// Thinking indicates the model accepts the thinking field.
+	Thinking bool
+
+	// Effort indicates the model accepts a reasoning effort.
+	Effort bool
+
+	// MaxReasoningTokens indicates the model accepts a reasoning token budget.
+	MaxReasoningTokens bool
+}
+
+// thinkingSupport is the thinking support of known models. Models not
+// listed are assumed to accept every field.
+var thinkingSupport = map[models.ID]ThinkingSupport{
+	models.GLM47:      {Thinking: true, Effort: true, MaxReasoningTokens: true},
+	models.GLM46:      {Thinking: true},
+	models.GLM45:      {Thinking: true},
+	models.GLM45Air:   {Thinking: true},
+	models.GLM45Flash: {Thinking: true},
+	models.GLM46V:     {Thinking: true},
+	models.GLM45V:     {Thinking: true},
+	models.GLM4Plus:   {},
+	models.GLM4Air:    {},
+	models.GLM4Flash:  {},
+	models.GLM4Long:   {},
+	models.GLM4VPlus:  {},
+	models.GLM4VFlash: {},
+}
+
+// ThinkingSupportFor returns the thinking fields model accepts. The
+// second result is false for models the SDK does not know, which are
+// assumed to accept every field.
+func ThinkingSupportFor[M ~string](model M) (ThinkingSupport, bool) {
+	support, ok := thinkingSupport[models.ID(model)]
+	if !ok {
+		return ThinkingSupport{Thinking: true, Effort: true, MaxReasoningTokens: true}, false
+	}
+	return support, true
+}
+
+// forModel returns the thinking configuration to send to model, without
+// the fields the model does not accept, or nil if nothing is left.
+func (c *ThinkingConfig) forModel(model string) *ThinkingConfig {
+	if c == nil {
+		return nil
+	}
+	support, _ := ThinkingSupportFor(model)
+	if !support.Thinking {
+		return nil
+	}
+
+	out := *c
+	if !support.Effort {
+		out.Effort = ""
+	}
+	if !support.MaxReasoningTokens {
+		out.MaxReasoningTokens = nil
+	}
+	if out == (ThinkingConfig{}) {
+		return nil
+	}
+	return &out
+}
+
+// validate checks the effort and reasoning token budget.
+func (c *ThinkingConfig) validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Effort {
+	case "", ThinkingEffortLow, ThinkingEffortMedium, ThinkingEffortHigh:
+	default:
+		return errors.NewValidationError("thinking.effort",
+			fmt.Sprintf("must be one of %q, %q or %q", ThinkingEffortLow, ThinkingEffortMedium, ThinkingEffortHigh), c.Effort)
+	}
+	if n := c.MaxReasoningTokens; n != nil && *n <= 0 {
+		return errors.NewValidationError("thinking.max_reasoning_tokens", "must be positive", *n)
+	}
+	if c.Type == ThinkingTypeDisabled && (c.Effort != "" || c.MaxReasoningTokens != nil) {
+		return errors.NewValidationError("thinking",
+			"effort and max_reasoning_tokens require thinking to be enabled", c.Type)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thinkingJSON returns the encoded thinking field of req, or "" if absent.
+func thinkingJSON(t *testing.T, req *ChatCompletionRequest) string {
+	t.Helper()
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(data, &fields))
+	return string(fields["thinking"])
+}
+
+func TestThinking_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	configure := map[string]func(*ChatCompletionRequest){
+		"none":      func(r *ChatCompletionRequest) {},
+		"enable":    func(r *ChatCompletionRequest) { r.EnableThinking() },
+		"disable":   func(r *ChatCompletionRequest) { r.DisableThinking() },
+		"preserved": func(r *ChatCompletionRequest) { r.EnablePreservedThinking() },
+		"effort":    func(r *ChatCompletionRequest) { r.SetThinkingEffort(ThinkingEffortLow) },
+		"budget":    func(r *ChatCompletionRequest) { r.SetMaxReasoningTokens(2048) },
+		"effort and budget": func(r *ChatCompletionRequest) {
+			r.SetThinkingEffort(ThinkingEffortHigh).SetMaxReasoningTokens(4096)
+		},
+		"preserved with effort": func(r *ChatCompletionRequest) {
+			r.EnablePreservedThinking().SetThinkingEffort(ThinkingEffortMedium)
+		},
+		"disable then effort": func(r *ChatCompletionRequest) {
+			r.DisableThinking().SetThinkingEffort(ThinkingEffortLow)
+		},
+	}
+
+	tests := []struct {
+		model string
+		want  map[string]string
+	}{
+		{
+			// Full support
+			model: "glm-4.7",
+			want: map[string]string{
+				"none":                  "",
+				"enable":                `{"type":"enabled"}`,
+				"disable":               `{"type":"disabled"}`,
+				"preserved":             `{"type":"enabled","clear_thinking":false}`,
+				"effort":                `{"type":"enabled","effort":"low"}`,
+				"budget":                `{"type":"enabled","max_reasoning_tokens":2048}`,
+				"effort and budget":     `{"type":"enabled","effort":"high","max_reasoning_tokens":4096}`,
+				"preserved with effort": `{"type":"enabled","clear_thinking":false,"effort":"medium"}`,
+				"disable then effort":   `{"type":"enabled","effort":"low"}`,
+			},
+		},
+		{
+			// Enable and disable only
+			model: "glm-4.5",
+			want: map[string]string{
+				"none":                  "",
+				"enable":                `{"type":"enabled"}`,
+				"disable":               `{"type":"disabled"}`,
+				"preserved":             `{"type":"enabled","clear_thinking":false}`,
+				"effort":                `{"type":"enabled"}`,
+				"budget":                `{"type":"enabled"}`,
+				"effort and budget":     `{"type":"enabled"}`,
+				"preserved with effort": `{"type":"enabled","clear_thinking":false}`,
+				"disable then effort":   `{"type":"enabled"}`,
+			},
+		},
+		{
+			// No thinking field at all
+			model: "glm-4-plus",
+			want:  map[string]string{},
+		},
+		{
+			// Unknown models get every field
+			model: "glm-5-preview",
+			want: map[string]string{
+				"disable":           `{"type":"disabled"}`,
+				"effort and budget": `{"type":"enabled","effort":"high","max_reasoning_tokens":4096}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for name, apply := range configure {
+			want, ok := tt.want[name]
+			if !ok && tt.model != "glm-4-plus" {
+				continue
+			}
+			t.Run(tt.model+"/"+name, func(t *testing.T) {
+				t.Parallel()
+
+				req := NewChatCompletionRequest(tt.model, []Message{NewUserMessage("Hi")})
+				apply(req)
+				if want == "" {
+					assert.Empty(t, thinkingJSON(t, req))
+					return
+				}
+				assert.JSONEq(t, want, thinkingJSON(t, req))
+			})
+		}
+	}
+}
+
+func TestThinking_MarshalDoesNotModifyRequest(t *testing.T) {
+	t.Parallel()
+
+	req := NewChatCompletionRequest(ModelGLM45, []Message{NewUserMessage("Hi")}).
+		SetThinkingEffort(ThinkingEffortHigh)
+	_, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.Equal(t, ThinkingEffortHigh, req.Thinking.Effort)
+}
+
+func TestThinking_UnmarshalRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var req ChatCompletionRequest
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"model": "glm-4.7",
+		"thinking": {"type": "enabled", "effort": "medium", "max_reasoning_tokens": 1024}
+	}`), &req))
+	require.NotNil(t, req.Thinking)
+	assert.Equal(t, ThinkingEffortMedium, req.Thinking.Effort)
+	require.NotNil(t, req.Thinking.MaxReasoningTokens)
+	assert.Equal(t, 1024, *req.Thinking.MaxReasoningTokens)
+}
+
+func TestThinkingSupportFor(t *testing.T) {
+	t.Parallel()
+
+	support, known := ThinkingSupportFor(ModelGLM47)
+	assert.True(t, known)
+	assert.Equal(t, ThinkingSupport{Thinking: true, Effort: true, MaxReasoningTokens: true}, support)
+
+	support, known = ThinkingSupportFor(ModelGLM4Flash)
+	assert.True(t, known)
+	assert.False(t, support.Thinking)
+
+	support, known = ThinkingSupportFor("glm-5-preview")
+	assert.False(t, known)
+	assert.True(t, support.Effort)
+}
+
+func TestThinking_Validate(t *testing.T) {
+	t.Parallel()
+
+	budget := func(n int) *int { return &n }
+	tests := []struct {
+		name   string
+		config *ThinkingConfig
+		field  string
+	}{
+		{name: "valid", config: &ThinkingConfig{Type: ThinkingTypeEnabled, Effort: ThinkingEffortHigh, MaxReasoningTokens: budget(100)}},
+		{name: "unknown effort", config: &ThinkingConfig{Effort: "extreme"}, field: "thinking.effort"},
+		{name: "zero budget", config: &ThinkingConfig{MaxReasoningTokens: budget(0)}, field: "thinking.max_reasoning_tokens"},
+		{name: "effort while disabled", config: &ThinkingConfig{Type: ThinkingTypeDisabled, Effort: ThinkingEffortLow}, field: "thinking"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewChatCompletionRequest(ModelGLM47, []Message{NewUserMessage("Hi")}).SetThinking(tt.config)
+			err := req.Validate()
+			if tt.field == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestThinking_ResponseUsage(t *testing.T) {
+	t.Parallel()
+
+	var resp ChatCompletionResponse
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"id": "chatcmpl-1",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "42", "reasoning_content": "Thinking..."}}],
+		"usage": {
+			"prompt_tokens": 10,
+			"completion_tokens": 120,
+			"total_tokens": 130,
+			"completion_tokens_details": {"reasoning_tokens": 100}
+		}
+	}`), &resp))
+
+	require.NotNil(t, resp.Usage)
+	assert.Equal(t, 100, resp.Usage.GetReasoningTokens())
+	assert.Equal(t, 20, resp.Usage.GetAnswerTokens())
+	assert.Empty(t, resp.Extra())
+}
@@ -0,0 +1,268 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// NewFunctionToolFromStruct creates a function tool whose parameters are
+// the schema of T, a struct, derived as by SchemaFor. Arguments the model
+// sends decode into T with UnmarshalToolArgs.
+//
+// Example:
+//
+//	type WeatherArgs struct {
+//	    Location string `json:"location" description:"City name, e.g. San Francisco"`
+//	    Unit     string `json:"unit,omitempty" schema:"enum=celsius|fahrenheit"`
+//	}
+//
+//	tool, err := chat.NewFunctionToolFromStruct[WeatherArgs]("get_weather", "Get the current weather")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	req.AddTool(tool.WithStrict())
+func NewFunctionToolFromStruct[T any](name, description string) (Tool, error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return Tool{}, fmt.Errorf("chat: parameters of function %q must be a struct, got %s", name, t)
+	}
+	schema, err := SchemaFor[T]()
+	if err != nil {
+		return Tool{}, err
+	}
+	return NewFunctionTool(name, description, schema), nil
+}
+
+// WithStrict returns a copy of the function tool in strict mode, in which
+// the model generates arguments that always match the parameters schema.
+func (t Tool) WithStrict() Tool {
+	t.Function.Strict = true
+	return t
+}
+
+// maxFunctionName is the maximum length of the name of a function tool.
+const maxFunctionName = 64
+
+// Validate checks the tool before it is sent: the type must be set, a
+// function must have a valid name, and its parameters must be a
+// well-formed JSON Schema of an object: known types, properties and
+// required names that match, known keywords and consistent bounds. It
+// returns a *errors.ValidationError naming the offending part of the
+// schema, such as "function.parameters.properties.unit.type".
+func (t Tool) Validate() error {
+	return t.validate("")
+}
+
+// validate checks the tool, prefixing the fields of errors with prefix.
+func (t Tool) validate(prefix string) error {
+	switch t.Type {
+	case "":
+		return errors.NewValidationError(prefix+"type", "is required", nil)
+	case "function":
+		return t.Function.validate(prefix + "function")
+	case "retrieval":
+		if t.Retrieval == nil || t.Retrieval.KnowledgeID == "" {
+			return errors.NewValidationError(prefix+"retrieval.knowledge_id", "is required", nil)
+		}
+	}
+	return nil
+}
+
+// validate checks the function definition at field.
+func (f *ToolFunction) validate(field string) error {
+	if f.Name == "" {
+		return errors.NewValidationError(field+".name", "is required", nil)
+	}
+	if len(f.Name) > maxFunctionName || strings.IndexFunc(f.Name, invalidNameRune) >= 0 {
+		return errors.NewValidationError(field+".name",
+			fmt.Sprintf("must be at most %d letters, digits, underscores, dots or dashes", maxFunctionName), f.Name)
+	}
+
+	if f.Parameters == nil {
+		return nil
+	}
+	field += ".parameters"
+	data, err := json.Marshal(f.Parameters)
+	if err != nil {
+		return errors.NewValidationError(field, fmt.Sprintf("cannot be encoded as JSON: %v", err), nil)
+	}
+	var params any
+	if err := json.Unmarshal(data, &params); err != nil {
+		return errors.NewValidationError(field, fmt.Sprintf("is not valid JSON: %v", err), nil)
+	}
+	if params == nil {
+		return nil
+	}
+	root, ok := params.(map[string]any)
+	if !ok {
+		return errors.NewValidationError(field, "must be a JSON Schema object", string(data))
+	}
+	if len(root) > 0 && root["type"] != SchemaTypeObject {
+		return errors.NewValidationError(field+".type", `must be "object"`, root["type"])
+	}
+	return checkSchema(field, root)
+}
+
+// invalidNameRune reports whether r may not appear in a function name.
+func invalidNameRune(r rune) bool {
+	return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.')
+}
+
+// Keywords of JSON Schema, by the kind of value they take.
+var (
+	// schemaMapKeywords map names to subschemas.
+	schemaMapKeywords = []string{"properties", "patternProperties", "$defs", "definitions", "dependentSchemas"}
+
+	// schemaKeywords take a subschema.
+	schemaKeywords = []string{"items", "additionalProperties", "additionalItems", "not", "contains",
+		"propertyNames", "if", "then", "else", "unevaluatedProperties", "unevaluatedItems"}
+
+	// schemaListKeywords take a non-empty list of subschemas.
+	schemaListKeywords = []string{"anyOf", "oneOf", "allOf", "prefixItems"}
+
+	// numberKeywords take a number.
+	numberKeywords = []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf",
+		"minLength", "maxLength", "minItems", "maxItems", "minProperties", "maxProperties", "minContains", "maxContains"}
+
+	// stringKeywords take a string.
+	stringKeywords = []string{"description", "title", "pattern", "format", "$ref", "$schema", "$id",
+		"$anchor", "$comment", "contentEncoding", "contentMediaType"}
+
+	// otherKeywords take any value.
+	otherKeywords = []string{"const", "default", "examples", "uniqueItems", "dependentRequired",
+		"nullable", "deprecated", "readOnly", "writeOnly"}
+)
+
+// checkSchema checks the JSON Schema s, found at path.
+func checkSchema(path string, s map[string]any) error {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := s[key]
+		at := path + "." + key
+		switch {
+		case key == "type":
+			if err := checkTypes(at, value); err != nil {
+				return err
+			}
+		case key == "required":
+			names, ok := value.([]any)
+			if !ok {
+				return errors.NewValidationError(at, "must be an array of property names", value)
+			}
+			for _, name := range names {
+				if _, ok := name.(string); !ok {
+					return errors.NewValidationError(at, "must be an array of property names", value)
+				}
+			}
+		case key == "enum":
+			if values, ok := value.([]any); !ok || len(values) == 0 {
+				return errors.NewValidationError(at, "must be a non-empty array", value)
+			}
+		case slices.Contains(schemaMapKeywords, key):
+			subschemas, ok := value.(map[string]any)
+			if !ok {
+				return errors.NewValidationError(at, "must be an object of schemas", value)
+			}
+			names := make([]string, 0, len(subschemas))
+			for name := range subschemas {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				if err := checkSubschema(at+"."+name, subschemas[name]); err != nil {
+					return err
+				}
+			}
+		case slices.Contains(schemaKeywords, key):
+			if err := checkSubschema(at, value); err != nil {
+				return err
+			}
+		case slices.Contains(schemaListKeywords, key):
+			subschemas, ok := value.([]any)
+			if !ok || len(subschemas) == 0 {
+				return errors.NewValidationError(at, "must be a non-empty array of schemas", value)
+			}
+			for i, subschema := range subschemas {
+				if err := checkSubschema(fmt.Sprintf("%s[%d]", at, i), subschema); err != nil {
+					return err
+				}
+			}
+		case slices.Contains(numberKeywords, key):
+			if _, ok := value.(float64); !ok {
+				return errors.NewValidationError(at, "must be a number", value)
+			}
+		case slices.Contains(stringKeywords, key):
+			if _, ok := value.(string); !ok {
+				return errors.NewValidationError(at, "must be a string", value)
+			}
+		case slices.Contains(otherKeywords, key), strings.HasPrefix(key, "x-"):
+		default:
+			return errors.NewValidationError(at, "is not a JSON Schema keyword", key)
+		}
+	}
+
+	// Every required property must be defined
+	if names, ok := s["required"].([]any); ok {
+		if properties, ok := s["properties"].(map[string]any); ok {
+			for _, name := range names {
+				if _, ok := properties[name.(string)]; !ok {
+					return errors.NewValidationError(path+".required",
+						fmt.Sprintf("lists %q, which is not in properties", name), name)
+				}
+			}
+		}
+	}
+
+	for _, bounds := range [][2]string{{"minimum", "maximum"}, {"minLength", "maxLength"}, {"minItems", "maxItems"}, {"minProperties", "maxProperties"}} {
+		low, lowOK := s[bounds[0]].(float64)
+		high, highOK := s[bounds[1]].(float64)
+		if lowOK && highOK && low > high {
+			return errors.NewValidationError(path+"."+bounds[0],
+				fmt.Sprintf("is greater than %s %v", bounds[1], high), low)
+		}
+	}
+	return nil
+}
+
+// checkSubschema checks a subschema, which may also be a boolean.
+func checkSubschema(path string, value any) error {
+	switch v := value.(type) {
+	case bool:
+		return nil
+	case map[string]any:
+		return checkSchema(path, v)
+	}
+	return errors.NewValidationError(path, "must be a schema object", value)
+}
+
+// checkTypes checks the value of the type keyword: a JSON type, or a
+// non-empty array of them.
+func checkTypes(path string, value any) error {
+	types, ok := value.([]any)
+	if !ok {
+		types = []any{value}
+	}
+	if len(types) == 0 {
+		return errors.NewValidationError(path, "must not be empty", value)
+	}
+	for _, t := range types {
+		switch t {
+		case SchemaTypeObject, SchemaTypeArray, SchemaTypeString, SchemaTypeInteger,
+			SchemaTypeNumber, SchemaTypeBoolean, SchemaTypeNull:
+		default:
+			return errors.NewValidationError(path, fmt.Sprintf("has unknown type %v", t), t)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat/schema"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+type bookingArgs struct {
+	Hotel     string    `json:"hotel" description:"Hotel name"`
+	CheckIn   time.Time `json:"check_in" description:"Arrival date"`
+	Nights    int       `json:"nights"`
+	Rooms     []string  `json:"rooms" schema:"enum=single|double|suite"`
+	Breakfast *bool     `json:"breakfast"`
+	Notes     string    `json:"notes,omitempty"`
+	Guest     struct {
+		Name  string `json:"name"`
+		Email string `json:"email,omitempty"`
+	} `json:"guest"`
+}
+
+func TestNewFunctionToolFromStruct(t *testing.T) {
+	t.Parallel()
+
+	tool, err := NewFunctionToolFromStruct[bookingArgs]("book_hotel", "Book a hotel room")
+	require.NoError(t, err)
+	require.NoError(t, tool.Validate())
+
+	data, err := json.Marshal(tool.WithStrict())
+	require.NoError(t, err)
+	golden.AssertJSON(t, "tool_from_struct.json", data)
+
+	// The tool decodes from its encoding and its parameters parse back
+	// into the derived schema
+	var decoded Tool
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.NoError(t, decoded.Validate())
+	assert.True(t, decoded.Function.Strict)
+	params, err := json.Marshal(decoded.Function.Parameters)
+	require.NoError(t, err)
+	parsed, err := ParseSchema(params)
+	require.NoError(t, err)
+	assert.Equal(t, tool.Function.Parameters, parsed)
+
+	// Arguments encoded from the struct match the schema
+	breakfast := true
+	args := bookingArgs{Hotel: "Grand", CheckIn: time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), Nights: 2, Rooms: []string{"double"}, Breakfast: &breakfast}
+	args.Guest.Name = "Ana"
+	encoded, err := json.Marshal(args)
+	require.NoError(t, err)
+	assert.Empty(t, parsed.Validate(encoded))
+
+	_, err = NewFunctionToolFromStruct[[]string]("list", "")
+	assert.ErrorContains(t, err, "must be a struct")
+	_, err = NewFunctionToolFromStruct[struct{ C chan int }]("chan", "")
+	assert.ErrorContains(t, err, "cannot derive a JSON schema")
+}
+
+func TestTool_WithStrict(t *testing.T) {
+	t.Parallel()
+
+	tool := NewFunctionTool("get_weather", "", schema.Object(nil))
+	data, err := json.Marshal(tool)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "strict", "omitted unless set")
+
+	strict := tool.WithStrict()
+	assert.False(t, tool.Function.Strict, "WithStrict returns a copy")
+	data, err = json.Marshal(strict)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"strict":true`)
+}
+
+func TestTool_Validate(t *testing.T) {
+	t.Parallel()
+
+	weather := schema.Object(schema.Properties{
+		"location": schema.String("City"),
+		"unit":     schema.String("Unit", "celsius", "fahrenheit"),
+		"days":     schema.Integer(1, 14),
+	}, "location")
+
+	tests := []struct {
+		name      string
+		tool      Tool
+		wantField string
+		wantErr   string
+	}{
+		{name: "built schema", tool: NewFunctionTool("get_weather", "", weather)},
+		{name: "no parameters", tool: NewFunctionTool("now", "", nil)},
+		{name: "empty parameters", tool: NewFunctionTool("now", "", map[string]any{})},
+		{name: "raw JSON", tool: NewFunctionTool("now", "", json.RawMessage(`{"type":"object","properties":{}}`))},
+		{name: "retrieval", tool: NewRetrievalTool("kb_1")},
+		{
+			name: "extension keyword",
+			tool: NewFunctionTool("f", "", map[string]any{"type": "object", "x-internal": true}),
+		},
+		{
+			name:      "missing type",
+			tool:      Tool{},
+			wantField: "type",
+			wantErr:   "is required",
+		},
+		{
+			name:      "missing name",
+			tool:      NewFunctionTool("", "", weather),
+			wantField: "function.name",
+			wantErr:   "is required",
+		},
+		{
+			name:      "invalid name",
+			tool:      NewFunctionTool("get weather", "", weather),
+			wantField: "function.name",
+			wantErr:   "at most 64 letters",
+		},
+		{
+			name:      "not an object",
+			tool:      NewFunctionTool("f", "", []string{"location"}),
+			wantField: "function.parameters",
+			wantErr:   "must be a JSON Schema object",
+		},
+		{
+			name:      "root not an object schema",
+			tool:      NewFunctionTool("f", "", schema.String("x")),
+			wantField: "function.parameters.type",
+			wantErr:   `must be "object"`,
+		},
+		{
+			name: "misspelled type",
+			tool: NewFunctionTool("f", "", map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"unit": map[string]any{"type": "strng"}},
+			}),
+			wantField: "function.parameters.properties.unit.type",
+			wantErr:   "unknown type strng",
+		},
+		{
+			name: "misspelled required",
+			tool: NewFunctionTool("f", "", map[string]any{
+				"type":     "object",
+				"requried": []string{"unit"},
+			}),
+			wantField: "function.parameters.requried",
+			wantErr:   "is not a JSON Schema keyword",
+		},
+		{
+			name: "required is a string",
+			tool: NewFunctionTool("f", "", map[string]any{
+				"type":     "object",
+				"required": "unit",
+			}),
+			wantField: "function.parameters.required",
+			wantErr:   "must be an array of property names",
+		},
+		{
+			name:      "required property not defined",
+			tool:      NewFunctionTool("f", "", schema.Object(schema.Properties{"city": schema.String("")}, "location")),
+			wantField: "function.parameters.required",
+			wantErr:   `lists "location", which is not in properties`,
+		},
+		{
+			name: "property not a schema",
+			tool: NewFunctionTool("f", "", map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"unit": "string"},
+			}),
+			wantField: "function.parameters.properties.unit",
+			wantErr:   "must be a schema object",
+		},
+		{
+			name:      "inverted bounds",
+			tool:      NewFunctionTool("f", "", schema.Object(schema.Properties{"n": schema.Number(10, 1)})),
+			wantField: "function.parameters.properties.n.minimum",
+			wantErr:   "is greater than maximum 1",
+		},
+		{
+			name: "empty enum",
+			tool: NewFunctionTool("f", "", schema.Object(schema.Properties{
+				"items": schema.Array(schema.Schema{"type": "string", "enum": []string{}}),
+			})),
+			wantField: "function.parameters.properties.items.items.enum",
+			wantErr:   "must be a non-empty array",
+		},
+		{
+			name: "invalid anyOf",
+			tool: NewFunctionTool("f", "", schema.Object(schema.Properties{
+				"id": {"anyOf": []any{schema.String(""), map[string]any{"type": 1}}},
+			})),
+			wantField: "function.parameters.properties.id.anyOf[1].type",
+			wantErr:   "unknown type 1",
+		},
+		{
+			name:      "unencodable parameters",
+			tool:      NewFunctionTool("f", "", map[string]any{"type": "object", "default": make(chan int)}),
+			wantField: "function.parameters",
+			wantErr:   "cannot be encoded as JSON",
+		},
+		{
+			name:      "retrieval without knowledge ID",
+			tool:      Tool{Type: "retrieval"},
+			wantField: "retrieval.knowledge_id",
+			wantErr:   "is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.tool.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestChatCompletionRequest_Validate_Tools(t *testing.T) {
+	t.Parallel()
+
+	req := NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("Weather in Paris?")})
+	req.AddTool(NewFunctionTool("get_time", "", nil))
+	req.AddTool(NewFunctionTool("get_weather", "", map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"unit": map[string]any{"type": "strng"}},
+	}))
+
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, req.Validate(), &validationErr)
+	assert.Equal(t, "tools[1].function.parameters.properties.unit.type", validationErr.Field)
+}
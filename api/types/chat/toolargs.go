@@ -0,0 +1,149 @@
+package chat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ToolArgsError is returned by UnmarshalToolArgs when the arguments of a
+// tool call cannot be decoded into the requested type.
+type ToolArgsError struct {
+	// Name is the name of the called function.
+	Name string
+
+	// Arguments is the raw JSON arguments sent by the model.
+	Arguments string
+
+	// Err is the underlying decoding or validation error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ToolArgsError) Error() string {
+	return fmt.Sprintf("chat: invalid arguments for tool %q: %v (arguments: %s)", e.Name, e.Err, e.Arguments)
+}
+
+// Unwrap returns the underlying error.
+func (e *ToolArgsError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalToolArgs decodes the arguments of a tool call into a value of
+// type T. Fields in the arguments that T does not declare are ignored; use
+// UnmarshalToolArgsStrict to reject them.
+//
+// Empty arguments decode as an empty object. If T (or *T) has a
+// Validate() error method, it is called after decoding, which is the place
+// to check for missing required fields. Failures are reported as a
+// *ToolArgsError carrying the tool name and raw arguments.
+//
+// Example:
+//
+//	type WeatherArgs struct {
+//	    Location string `json:"location"`
+//	    Unit     string `json:"unit"`
+//	}
+//
+//	for _, tc := range resp.GetFirstChoice().Message.ToolCalls {
+//	    args, err := chat.UnmarshalToolArgs[WeatherArgs](tc)
+//	    if err != nil {
+//	        // Handle error
+//	    }
+//	    fmt.Printf("Location: %s\n", args.Location)
+//	}
+func UnmarshalToolArgs[T any](tc ToolCall) (T, error) {
+	return unmarshalToolArgs[T](tc, false)
+}
+
+// UnmarshalToolArgsStrict is like UnmarshalToolArgs but also fails if the
+// arguments contain fields that T does not declare.
+//
+// Example:
+//
+//	args, err := chat.UnmarshalToolArgsStrict[WeatherArgs](tc)
+func UnmarshalToolArgsStrict[T any](tc ToolCall) (T, error) {
+	return unmarshalToolArgs[T](tc, true)
+}
+
+// unmarshalToolArgs decodes and validates tool call arguments.
+func unmarshalToolArgs[T any](tc ToolCall, strict bool) (T, error) {
+	var v T
+
+	fail := func(err error) (T, error) {
+		var zero T
+		return zero, &ToolArgsError{
+			Name:      tc.Function.Name,
+			Arguments: tc.Function.Arguments,
+			Err:       err,
+		}
+	}
+
+	data := bytes.TrimSpace([]byte(tc.Function.Arguments))
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if strict {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(&v); err != nil {
+		return fail(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fail(fmt.Errorf("unexpected data after JSON value"))
+	}
+
+	if err := validateToolArgs(&v); err != nil {
+		return fail(err)
+	}
+
+	return v, nil
+}
+
+// validateToolArgs calls the Validate method of the decoded arguments,
+// whether it is declared on T or *T.
+func validateToolArgs[T any](v *T) error {
+	type validator interface{ Validate() error }
+
+	if val, ok := any(*v).(validator); ok {
+		return val.Validate()
+	}
+	if val, ok := any(v).(validator); ok {
+		return val.Validate()
+	}
+	return nil
+}
+
+// NewToolResultMessage creates a tool response message from an arbitrary
+// result value. Strings and json.RawMessage values are sent as-is; any
+// other value is encoded as JSON.
+//
+// Example:
+//
+//	weather := Weather{Temperature: 22, Condition: "sunny"}
+//	msg, err := chat.NewToolResultMessage(tc.ID, weather)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	messages = append(messages, msg)
+func NewToolResultMessage(toolCallID string, result any) (Message, error) {
+	var content string
+
+	switch r := result.(type) {
+	case string:
+		content = r
+	case json.RawMessage:
+		content = string(r)
+	default:
+		data, err := json.Marshal(result)
+		if err != nil {
+			return Message{}, fmt.Errorf("chat: failed to marshal result of tool call %q: %w", toolCallID, err)
+		}
+		content = string(data)
+	}
+
+	return NewToolMessage(toolCallID, content), nil
+}
@@ -0,0 +1,193 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherArgs struct {
+	Location string  `json:"location"`
+	Days     int     `json:"days"`
+	MinTemp  float64 `json:"min_temp"`
+}
+
+func (a weatherArgs) Validate() error {
+	if a.Location == "" {
+		return errors.New("location is required")
+	}
+	return nil
+}
+
+type searchArgs struct {
+	Query string `json:"query"`
+	Limit int64  `json:"limit,string"`
+}
+
+func (a *searchArgs) Validate() error {
+	if a.Query == "" {
+		return errors.New("query is required")
+	}
+	return nil
+}
+
+func newTestToolCall(name, arguments string) ToolCall {
+	return ToolCall{
+		ID:       "call_1",
+		Type:     "function",
+		Function: FunctionCall{Name: name, Arguments: arguments},
+	}
+}
+
+func TestUnmarshalToolArgs(t *testing.T) {
+	t.Parallel()
+
+	tc := newTestToolCall("get_weather", `{"location":"Paris","days":3,"min_temp":12,"unit":"celsius"}`)
+
+	args, err := UnmarshalToolArgs[weatherArgs](tc)
+	require.NoError(t, err)
+	assert.Equal(t, weatherArgs{Location: "Paris", Days: 3, MinTemp: 12}, args)
+
+	ptr, err := UnmarshalToolArgs[*weatherArgs](tc)
+	require.NoError(t, err)
+	assert.Equal(t, &args, ptr)
+}
+
+func TestUnmarshalToolArgsStrict(t *testing.T) {
+	t.Parallel()
+
+	_, err := UnmarshalToolArgsStrict[weatherArgs](newTestToolCall("get_weather", `{"location":"Paris"}`))
+	require.NoError(t, err)
+
+	_, err = UnmarshalToolArgsStrict[weatherArgs](newTestToolCall("get_weather", `{"location":"Paris","unit":"celsius"}`))
+	var argsErr *ToolArgsError
+	require.ErrorAs(t, err, &argsErr)
+	assert.Contains(t, err.Error(), `unknown field "unit"`)
+}
+
+func TestUnmarshalToolArgs_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		arguments string
+	}{
+		{"truncated", `{"location":"Par`},
+		{"not an object", `["Paris"]`},
+		{"trailing data", `{"location":"Paris"} {"location":"Rome"}`},
+		{"wrong type", `{"location":42}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args, err := UnmarshalToolArgs[weatherArgs](newTestToolCall("get_weather", tt.arguments))
+			assert.Equal(t, weatherArgs{}, args)
+
+			var argsErr *ToolArgsError
+			require.ErrorAs(t, err, &argsErr)
+			assert.Equal(t, "get_weather", argsErr.Name)
+			assert.Equal(t, tt.arguments, argsErr.Arguments)
+			assert.Contains(t, err.Error(), `tool "get_weather"`)
+			assert.Contains(t, err.Error(), tt.arguments)
+		})
+	}
+}
+
+func TestUnmarshalToolArgs_MissingRequired(t *testing.T) {
+	t.Parallel()
+
+	t.Run("value receiver", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := UnmarshalToolArgs[weatherArgs](newTestToolCall("get_weather", `{"days":2}`))
+		var argsErr *ToolArgsError
+		require.ErrorAs(t, err, &argsErr)
+		assert.EqualError(t, argsErr.Err, "location is required")
+	})
+
+	t.Run("pointer receiver", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := UnmarshalToolArgs[searchArgs](newTestToolCall("search", ``))
+		var argsErr *ToolArgsError
+		require.ErrorAs(t, err, &argsErr)
+		assert.EqualError(t, argsErr.Err, "query is required")
+	})
+}
+
+func TestUnmarshalToolArgs_NumericCoercion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("integral float into int", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := UnmarshalToolArgs[weatherArgs](newTestToolCall("get_weather", `{"location":"Oslo","days":2.0e0,"min_temp":-3}`))
+		// JSON numbers with a fraction or exponent do not decode into integers
+		require.Error(t, err)
+		assert.Equal(t, weatherArgs{}, args)
+
+		args, err = UnmarshalToolArgs[weatherArgs](newTestToolCall("get_weather", `{"location":"Oslo","days":2,"min_temp":-3}`))
+		require.NoError(t, err)
+		assert.Equal(t, 2, args.Days)
+		assert.Equal(t, -3.0, args.MinTemp)
+	})
+
+	t.Run("quoted number", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := UnmarshalToolArgs[searchArgs](newTestToolCall("search", `{"query":"go","limit":"10"}`))
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), args.Limit)
+
+		_, err = UnmarshalToolArgs[weatherArgs](newTestToolCall("get_weather", `{"location":"Oslo","days":"2"}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("map", func(t *testing.T) {
+		t.Parallel()
+
+		args, err := UnmarshalToolArgs[map[string]any](newTestToolCall("any", `{"n":3}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"n": 3.0}, args)
+	})
+}
+
+func TestNewToolResultMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		result any
+		want   string
+	}{
+		{"string", "sunny, 22C", "sunny, 22C"},
+		{"raw JSON", json.RawMessage(`{"temp":22}`), `{"temp":22}`},
+		{"struct", weatherArgs{Location: "Paris", Days: 1}, `{"location":"Paris","days":1,"min_temp":0}`},
+		{"number", 42, "42"},
+		{"nil", nil, "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg, err := NewToolResultMessage("call_1", tt.result)
+			require.NoError(t, err)
+			assert.Equal(t, RoleTool, msg.Role)
+			assert.Equal(t, "call_1", msg.ToolCallID)
+			assert.Equal(t, tt.want, msg.Content)
+		})
+	}
+
+	t.Run("unmarshalable", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewToolResultMessage("call_1", make(chan int))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,367 @@
+package chat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ChunkStream is a stream of chat completion chunks, such as the stream
+// returned by ChatService.CreateStream.
+type ChunkStream interface {
+	Next() bool
+	Current() *ChatCompletionChunk
+	Err() error
+	Close() error
+}
+
+// ToolCallStreamError is returned by ToolCallCollector when the tool call
+// fragments of a stream cannot be assembled into a valid tool call.
+type ToolCallStreamError struct {
+	// ChoiceIndex is the index of the choice the tool call belongs to.
+	ChoiceIndex int
+
+	// Index is the index of the tool call.
+	Index int
+
+	// ID is the ID of the tool call, if one was received.
+	ID string
+
+	// Name is the name of the called function, if one was received.
+	Name string
+
+	// Arguments is the raw JSON arguments received so far.
+	Arguments string
+
+	// Err describes what is wrong with the tool call.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ToolCallStreamError) Error() string {
+	return fmt.Sprintf("chat: malformed streamed tool call %d (id %q, function %q): %v (arguments: %s)",
+		e.Index, e.ID, e.Name, e.Err, e.Arguments)
+}
+
+// Unwrap returns the underlying error.
+func (e *ToolCallStreamError) Unwrap() error {
+	return e.Err
+}
+
+// StreamEvent is an event of a ToolCallCollector. Exactly one of Chunk and
+// ToolCall is set.
+type StreamEvent struct {
+	// Chunk is a streamed chunk with its tool call fragments removed.
+	// Content and reasoning deltas are passed through as streamed.
+	Chunk *ChatCompletionChunk
+
+	// ToolCall is a completed tool call.
+	ToolCall *ToolCall
+
+	// ChoiceIndex is the index of the choice ToolCall belongs to.
+	ChoiceIndex int
+}
+
+// toolCallKey identifies a tool call within a stream.
+type toolCallKey struct {
+	choice int
+	index  int
+}
+
+// ToolCallCollector wraps a chat completion stream and assembles the
+// fragments of streamed tool calls. Chunks are passed through as they
+// arrive, without their tool call fragments, and each tool call is emitted
+// once its arguments are complete: when the next tool call starts, when
+// the choice finishes or when the stream ends.
+//
+// Completed tool calls must have a function name and, if they have
+// arguments, valid JSON arguments; otherwise the collector stops with a
+// *ToolCallStreamError.
+type ToolCallCollector struct {
+	stream ChunkStream
+
+	// pending is the tool call being assembled for each choice.
+	pending map[int]*ToolCall
+	// pendingIndex is the index of the pending tool call of each choice.
+	pendingIndex map[int]int
+	// completed records the tool calls already emitted.
+	completed map[toolCallKey]bool
+
+	queue    []StreamEvent
+	current  *StreamEvent
+	content  strings.Builder
+	calls    []ToolCall
+	finished bool
+	err      error
+}
+
+// NewToolCallCollector creates a collector that reads chunks from stream.
+//
+// Example:
+//
+//	stream, err := client.Chat.CreateStream(ctx, req)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	collector := chat.NewToolCallCollector(stream)
+//	defer collector.Close()
+//
+//	for collector.Next() {
+//	    event := collector.Current()
+//	    if event.ToolCall != nil {
+//	        // Run the tool
+//	        continue
+//	    }
+//	    fmt.Print(event.Chunk.GetContent())
+//	}
+//	if err := collector.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewToolCallCollector(stream ChunkStream) *ToolCallCollector {
+	return &ToolCallCollector{
+		stream:       stream,
+		pending:      make(map[int]*ToolCall),
+		pendingIndex: make(map[int]int),
+		completed:    make(map[toolCallKey]bool),
+	}
+}
+
+// Next advances to the next event. It returns false when the stream is
+// exhausted or an error occurs.
+func (c *ToolCallCollector) Next() bool {
+	for {
+		if len(c.queue) > 0 {
+			c.current = &c.queue[0]
+			c.queue = c.queue[1:]
+			return true
+		}
+		if c.err != nil || c.finished {
+			return false
+		}
+
+		if !c.stream.Next() {
+			if err := c.stream.Err(); err != nil {
+				c.err = err
+				return false
+			}
+			// The stream ended: whatever is pending is complete
+			c.finished = true
+			for _, choice := range slices.Sorted(maps.Keys(c.pending)) {
+				c.complete(choice)
+				if c.err != nil {
+					break
+				}
+			}
+			continue
+		}
+		if chunk := c.stream.Current(); chunk != nil {
+			c.process(chunk)
+		}
+	}
+}
+
+// Current returns the current event.
+func (c *ToolCallCollector) Current() *StreamEvent {
+	return c.current
+}
+
+// Err returns the error that stopped the collector, if any.
+func (c *ToolCallCollector) Err() error {
+	return c.err
+}
+
+// Close closes the underlying stream.
+func (c *ToolCallCollector) Close() error {
+	return c.stream.Close()
+}
+
+// ToolCalls returns the tool calls of the first choice completed so far.
+func (c *ToolCallCollector) ToolCalls() []ToolCall {
+	return c.calls
+}
+
+// Message returns the assistant message of the first choice streamed so
+// far, with its content and completed tool calls, ready to be appended to
+// the conversation before the tool results.
+func (c *ToolCallCollector) Message() Message {
+	return Message{
+		Role:      RoleAssistant,
+		Content:   c.content.String(),
+		ToolCalls: c.calls,
+	}
+}
+
+// process handles the tool call fragments of chunk and queues its events.
+func (c *ToolCallCollector) process(chunk *ChatCompletionChunk) {
+	out := *chunk
+	out.Choices = make([]ChunkChoice, len(chunk.Choices))
+	hadFragments := false
+	keep := len(chunk.Choices) == 0 || chunk.Usage != nil
+
+	for i, choice := range chunk.Choices {
+		for _, fragment := range choice.Delta.ToolCalls {
+			hadFragments = true
+			if err := c.add(choice.Index, fragment); err != nil {
+				c.err = err
+				return
+			}
+		}
+
+		choice.Delta.ToolCalls = nil
+		out.Choices[i] = choice
+		if !choice.Delta.isEmpty() || choice.FinishReason != "" || choice.LogProbs != nil {
+			keep = true
+		}
+		if choice.Index == 0 {
+			c.content.WriteString(choice.Delta.Content)
+		}
+	}
+
+	// Chunks that only carried tool call fragments have nothing left to pass on
+	if keep || !hadFragments {
+		c.queue = append(c.queue, StreamEvent{Chunk: &out})
+	}
+
+	for _, choice := range chunk.Choices {
+		if choice.FinishReason != "" && c.err == nil {
+			c.complete(choice.Index)
+		}
+	}
+}
+
+// isEmpty returns true if the delta carries nothing but tool call fragments.
+func (d *Delta) isEmpty() bool {
+	return d.Role == "" && d.Content == "" && d.ContentParts == nil && d.ReasoningContent == "" && d.FunctionCall == nil
+}
+
+// add merges a tool call fragment into the pending tool call of choice.
+// A fragment with a new index completes the pending tool call.
+func (c *ToolCallCollector) add(choice int, fragment ToolCall) error {
+	pending := c.pending[choice]
+	index := c.fragmentIndex(choice, pending, fragment)
+
+	if pending != nil && index != c.pendingIndex[choice] {
+		c.complete(choice)
+		if c.err != nil {
+			return c.err
+		}
+		pending = nil
+	}
+	if c.completed[toolCallKey{choice, index}] {
+		return &ToolCallStreamError{
+			ChoiceIndex: choice,
+			Index:       index,
+			ID:          fragment.ID,
+			Name:        fragment.Function.Name,
+			Arguments:   fragment.Function.Arguments,
+			Err:         errors.New("fragment received after the tool call completed"),
+		}
+	}
+
+	if pending == nil {
+		pending = &ToolCall{}
+		c.pending[choice] = pending
+		c.pendingIndex[choice] = index
+	}
+
+	fail := func(format string, args ...any) error {
+		return &ToolCallStreamError{
+			ChoiceIndex: choice,
+			Index:       index,
+			ID:          pending.ID,
+			Name:        pending.Function.Name,
+			Arguments:   pending.Function.Arguments,
+			Err:         fmt.Errorf(format, args...),
+		}
+	}
+
+	switch {
+	case fragment.ID == "" || fragment.ID == pending.ID:
+	case pending.ID == "":
+		pending.ID = fragment.ID
+	default:
+		return fail("ID changed to %q", fragment.ID)
+	}
+	switch {
+	case fragment.Type == "" || fragment.Type == pending.Type:
+	case pending.Type == "":
+		pending.Type = fragment.Type
+	default:
+		return fail("type changed to %q", fragment.Type)
+	}
+	switch {
+	case fragment.Function.Name == "" || fragment.Function.Name == pending.Function.Name:
+	case pending.Function.Name == "":
+		pending.Function.Name = fragment.Function.Name
+	default:
+		return fail("function name changed to %q", fragment.Function.Name)
+	}
+	pending.Function.Arguments += fragment.Function.Arguments
+	return nil
+}
+
+// fragmentIndex returns the index of the tool call a fragment belongs to.
+// Fragments without an index continue the pending tool call, unless they
+// carry a different ID.
+func (c *ToolCallCollector) fragmentIndex(choice int, pending *ToolCall, fragment ToolCall) int {
+	if fragment.Index != nil {
+		return *fragment.Index
+	}
+	if pending == nil {
+		n := 0
+		for c.completed[toolCallKey{choice, n}] {
+			n++
+		}
+		return n
+	}
+	if fragment.ID != "" && pending.ID != "" && fragment.ID != pending.ID {
+		return c.pendingIndex[choice] + 1
+	}
+	return c.pendingIndex[choice]
+}
+
+// complete validates the pending tool call of choice and queues it.
+func (c *ToolCallCollector) complete(choice int) {
+	call := c.pending[choice]
+	if call == nil {
+		return
+	}
+	index := c.pendingIndex[choice]
+	delete(c.pending, choice)
+	delete(c.pendingIndex, choice)
+	c.completed[toolCallKey{choice, index}] = true
+
+	fail := func(err error) {
+		c.err = &ToolCallStreamError{
+			ChoiceIndex: choice,
+			Index:       index,
+			ID:          call.ID,
+			Name:        call.Function.Name,
+			Arguments:   call.Function.Arguments,
+			Err:         err,
+		}
+	}
+
+	if call.Function.Name == "" {
+		fail(errors.New("no function name received"))
+		return
+	}
+	if strings.TrimSpace(call.Function.Arguments) != "" {
+		var args json.RawMessage
+		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+			fail(fmt.Errorf("incomplete or invalid arguments: %w", err))
+			return
+		}
+	}
+	if call.Type == "" {
+		call.Type = "function"
+	}
+
+	if choice == 0 {
+		c.calls = append(c.calls, *call)
+	}
+	c.queue = append(c.queue, StreamEvent{ToolCall: call, ChoiceIndex: choice})
+}
@@ -0,0 +1,218 @@
+package chat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sliceStream is a ChunkStream over a fixed list of chunks.
+type sliceStream struct {
+	chunks  []*ChatCompletionChunk
+	current *ChatCompletionChunk
+	err     error
+	closed  bool
+}
+
+func (s *sliceStream) Next() bool {
+	if len(s.chunks) == 0 {
+		return false
+	}
+	s.current, s.chunks = s.chunks[0], s.chunks[1:]
+	return true
+}
+
+func (s *sliceStream) Current() *ChatCompletionChunk { return s.current }
+func (s *sliceStream) Err() error                    { return s.err }
+func (s *sliceStream) Close() error                  { s.closed = true; return nil }
+
+func deltaChunk(delta Delta, finishReason FinishReason) *ChatCompletionChunk {
+	return &ChatCompletionChunk{
+		ID:      "chatcmpl-1",
+		Choices: []ChunkChoice{{Delta: delta, FinishReason: finishReason}},
+	}
+}
+
+func fragment(index int, id, name, args string) ToolCall {
+	return ToolCall{ID: id, Function: FunctionCall{Name: name, Arguments: args}, Index: &index}
+}
+
+func toolDelta(fragments ...ToolCall) *ChatCompletionChunk {
+	return deltaChunk(Delta{ToolCalls: fragments}, "")
+}
+
+// collect drains the collector into the streamed content and tool calls.
+func collect(c *ToolCallCollector) (string, []ToolCall) {
+	var content string
+	var calls []ToolCall
+	for c.Next() {
+		event := c.Current()
+		if event.ToolCall != nil {
+			calls = append(calls, *event.ToolCall)
+			continue
+		}
+		content += event.Chunk.GetContent()
+	}
+	return content, calls
+}
+
+func TestToolCallCollector(t *testing.T) {
+	t.Parallel()
+
+	stream := &sliceStream{chunks: []*ChatCompletionChunk{
+		deltaChunk(Delta{Role: RoleAssistant, Content: "Checking "}, ""),
+		toolDelta(fragment(0, "call_1", "get_weather", "")),
+		toolDelta(fragment(0, "", "", `{"location":`)),
+		deltaChunk(Delta{Content: "both cities", ToolCalls: []ToolCall{fragment(0, "", "", `"Paris"}`)}}, ""),
+		toolDelta(fragment(1, "call_2", "get_weather", `{"location":"Oslo"}`)),
+		deltaChunk(Delta{}, "tool_calls"),
+	}}
+	collector := NewToolCallCollector(stream)
+
+	var events []string
+	for collector.Next() {
+		if tc := collector.Current().ToolCall; tc != nil {
+			events = append(events, "call:"+tc.ID)
+		} else {
+			events = append(events, "chunk:"+collector.Current().Chunk.GetContent())
+			assert.Empty(t, collector.Current().Chunk.Choices[0].Delta.ToolCalls)
+		}
+	}
+	require.NoError(t, collector.Err())
+
+	// The first call completes when the second starts, the second when the choice finishes
+	assert.Equal(t, []string{"chunk:Checking ", "chunk:both cities", "call:call_1", "chunk:", "call:call_2"}, events)
+
+	calls := collector.ToolCalls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, ToolCall{ID: "call_1", Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`}}, calls[0])
+	assert.Equal(t, `{"location":"Oslo"}`, calls[1].Function.Arguments)
+
+	msg := collector.Message()
+	assert.Equal(t, RoleAssistant, msg.Role)
+	assert.Equal(t, "Checking both cities", msg.Content)
+	assert.Equal(t, calls, msg.ToolCalls)
+
+	require.NoError(t, collector.Close())
+	assert.True(t, stream.closed)
+}
+
+func TestToolCallCollector_EndOfStream(t *testing.T) {
+	t.Parallel()
+
+	// Without a finish reason, pending calls complete when the stream ends
+	collector := NewToolCallCollector(&sliceStream{chunks: []*ChatCompletionChunk{
+		toolDelta(fragment(0, "call_1", "list_files", "")),
+	}})
+	_, calls := collect(collector)
+	require.NoError(t, collector.Err())
+	require.Len(t, calls, 1)
+	assert.Equal(t, "list_files", calls[0].Function.Name)
+}
+
+func TestToolCallCollector_WithoutIndex(t *testing.T) {
+	t.Parallel()
+
+	first := ToolCall{ID: "call_1", Function: FunctionCall{Name: "a", Arguments: `{"x":`}}
+	cont := ToolCall{Function: FunctionCall{Arguments: `1}`}}
+	second := ToolCall{ID: "call_2", Function: FunctionCall{Name: "b", Arguments: `{}`}}
+
+	collector := NewToolCallCollector(&sliceStream{chunks: []*ChatCompletionChunk{
+		toolDelta(first), toolDelta(cont), toolDelta(second),
+	}})
+	_, calls := collect(collector)
+	require.NoError(t, collector.Err())
+	require.Len(t, calls, 2)
+	assert.Equal(t, `{"x":1}`, calls[0].Function.Arguments)
+	assert.Equal(t, "call_2", calls[1].ID)
+}
+
+func TestToolCallCollector_MultipleChoices(t *testing.T) {
+	t.Parallel()
+
+	chunk := &ChatCompletionChunk{Choices: []ChunkChoice{
+		{Index: 0, Delta: Delta{ToolCalls: []ToolCall{fragment(0, "call_a", "a", "{}")}}},
+		{Index: 1, Delta: Delta{ToolCalls: []ToolCall{fragment(0, "call_b", "b", "{}")}}},
+	}}
+	collector := NewToolCallCollector(&sliceStream{chunks: []*ChatCompletionChunk{chunk}})
+
+	choices := map[string]int{}
+	for collector.Next() {
+		if tc := collector.Current().ToolCall; tc != nil {
+			choices[tc.ID] = collector.Current().ChoiceIndex
+		}
+	}
+	require.NoError(t, collector.Err())
+	assert.Equal(t, map[string]int{"call_a": 0, "call_b": 1}, choices)
+	assert.Len(t, collector.ToolCalls(), 1, "ToolCalls only has the first choice")
+}
+
+func TestToolCallCollector_Malformed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		chunks []*ChatCompletionChunk
+		want   string
+	}{
+		{
+			name: "truncated arguments",
+			chunks: []*ChatCompletionChunk{
+				toolDelta(fragment(0, "call_1", "get_weather", `{"location":"Par`)),
+				deltaChunk(Delta{}, "length"),
+			},
+			want: "incomplete or invalid arguments",
+		},
+		{
+			name:   "missing name",
+			chunks: []*ChatCompletionChunk{toolDelta(fragment(0, "call_1", "", `{}`))},
+			want:   "no function name received",
+		},
+		{
+			name: "changed ID",
+			chunks: []*ChatCompletionChunk{
+				toolDelta(fragment(0, "call_1", "a", "")),
+				toolDelta(fragment(0, "call_2", "", "{}")),
+			},
+			want: `ID changed to "call_2"`,
+		},
+		{
+			name: "fragment after completion",
+			chunks: []*ChatCompletionChunk{
+				toolDelta(fragment(0, "call_1", "a", "{}")),
+				toolDelta(fragment(1, "call_2", "b", "{}")),
+				toolDelta(fragment(0, "", "", "{}")),
+			},
+			want: "fragment received after the tool call completed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			collector := NewToolCallCollector(&sliceStream{chunks: tt.chunks})
+			collect(collector)
+
+			var streamErr *ToolCallStreamError
+			require.ErrorAs(t, collector.Err(), &streamErr)
+			assert.Contains(t, streamErr.Error(), tt.want)
+			assert.False(t, collector.Next())
+		})
+	}
+}
+
+func TestToolCallCollector_StreamError(t *testing.T) {
+	t.Parallel()
+
+	streamErr := errors.New("connection reset")
+	collector := NewToolCallCollector(&sliceStream{
+		chunks: []*ChatCompletionChunk{toolDelta(fragment(0, "call_1", "a", `{"x":`))},
+		err:    streamErr,
+	})
+	_, calls := collect(collector)
+	assert.Empty(t, calls, "incomplete calls are not emitted")
+	assert.ErrorIs(t, collector.Err(), streamErr)
+}
@@ -0,0 +1,245 @@
+package chat
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// TokenCounter counts the prompt tokens of a list of messages.
+// zai.ToolsService.Counter returns one that uses the tokenizer endpoint, the
+// offline estimate or both.
+type TokenCounter interface {
+	CountTokens(ctx context.Context, model string, messages []Message) (int, error)
+}
+
+// TruncationStrategy selects which messages TruncateMessages drops.
+// Leading system messages are always kept.
+type TruncationStrategy int
+
+const (
+	// TruncateOldest drops the oldest turns first.
+	TruncateOldest TruncationStrategy = iota
+
+	// TruncateMiddle drops turns from the middle of the conversation,
+	// keeping the earliest and most recent turns. Messages from the last
+	// user message onwards are always kept.
+	TruncateMiddle
+
+	// TruncateHeadTail keeps the first turn, which usually states the task,
+	// and as many of the most recent turns as fit.
+	TruncateHeadTail
+)
+
+// String returns the name of the strategy.
+func (s TruncationStrategy) String() string {
+	switch s {
+	case TruncateOldest:
+		return "oldest"
+	case TruncateMiddle:
+		return "middle"
+	case TruncateHeadTail:
+		return "head_tail"
+	default:
+		return fmt.Sprintf("TruncationStrategy(%d)", int(s))
+	}
+}
+
+// TokenLimitError is returned by TruncateMessages when the messages that
+// cannot be dropped exceed the token limit on their own, for example a
+// single message that is longer than the limit.
+type TokenLimitError struct {
+	// Tokens is the token count of the messages that could not be dropped.
+	Tokens int
+
+	// MaxTokens is the requested limit.
+	MaxTokens int
+
+	// Messages is the number of messages that could not be dropped.
+	Messages int
+}
+
+// Error implements the error interface.
+func (e *TokenLimitError) Error() string {
+	return fmt.Sprintf("chat: %d required message(s) use %d tokens, exceeding the limit of %d",
+		e.Messages, e.Tokens, e.MaxTokens)
+}
+
+// TruncateMessages drops messages until the prompt fits in maxPromptTokens,
+// and returns the kept messages with their token count.
+//
+// Leading system messages are always kept, and a tool message is always
+// dropped together with the assistant message that requested it. Messages
+// are never modified or summarized. The counter is called O(log n) times:
+// once for the full conversation and then in a binary search over the
+// number of dropped turns.
+//
+// If the messages that cannot be dropped exceed the limit on their own,
+// a *TokenLimitError is returned.
+//
+// Example:
+//
+//	kept, tokens, err := chat.TruncateMessages(ctx, client.Tools.Counter(zai.ModeExact), "glm-4.7",
+//	    history, 8000, chat.TruncateMiddle)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Printf("Kept %d of %d messages (%d tokens)\n", len(kept), len(history), tokens)
+func TruncateMessages(ctx context.Context, counter TokenCounter, model string, messages []Message, maxPromptTokens int, strategy TruncationStrategy) ([]Message, int, error) {
+	if counter == nil {
+		return nil, 0, errors.NewValidationError("counter", "is required", nil)
+	}
+	if maxPromptTokens <= 0 {
+		return nil, 0, errors.NewValidationError("max_prompt_tokens", "must be positive", maxPromptTokens)
+	}
+
+	tokens, err := counter.CountTokens(ctx, model, messages)
+	if err != nil {
+		return nil, 0, err
+	}
+	if tokens <= maxPromptTokens {
+		return messages, tokens, nil
+	}
+
+	t, err := newTruncator(messages, strategy)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	count := func(drop int) (int, error) {
+		return counter.CountTokens(ctx, model, t.keep(drop))
+	}
+
+	// Drop as much as possible first to check that a solution exists
+	maxDrop := t.maxDrop()
+	minTokens, err := count(maxDrop)
+	if err != nil {
+		return nil, 0, err
+	}
+	if minTokens > maxPromptTokens {
+		return nil, 0, &TokenLimitError{
+			Tokens:    minTokens,
+			MaxTokens: maxPromptTokens,
+			Messages:  len(t.keep(maxDrop)),
+		}
+	}
+
+	// Find the fewest dropped groups that fit. Dropping 0 is known not
+	// to fit and dropping maxDrop is known to fit.
+	var searchErr error
+	best, bestTokens := maxDrop, minTokens
+	sort.Search(maxDrop-1, func(i int) bool {
+		drop := i + 1
+		if searchErr != nil {
+			return true
+		}
+		n, err := count(drop)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		if n <= maxPromptTokens {
+			if drop < best {
+				best, bestTokens = drop, n
+			}
+			return true
+		}
+		return false
+	})
+	if searchErr != nil {
+		return nil, 0, searchErr
+	}
+
+	return t.keep(best), bestTokens, nil
+}
+
+// truncator splits a conversation into groups of messages that are kept
+// or dropped together.
+type truncator struct {
+	system []Message
+	groups [][]Message
+
+	// droppable is the number of leading groups that may be dropped.
+	droppable int
+
+	// first is the index of the first droppable group.
+	first int
+
+	strategy TruncationStrategy
+}
+
+// newTruncator groups messages for the given strategy.
+func newTruncator(messages []Message, strategy TruncationStrategy) (*truncator, error) {
+	t := &truncator{strategy: strategy}
+
+	i := 0
+	for i < len(messages) && messages[i].Role == RoleSystem {
+		i++
+	}
+	t.system = messages[:i]
+
+	// Tool results belong to the assistant message that requested them
+	for ; i < len(messages); i++ {
+		m := messages[i]
+		if m.Role == RoleTool && len(t.groups) > 0 {
+			last := t.groups[len(t.groups)-1]
+			if last[0].Role == RoleAssistant && len(last[0].ToolCalls) > 0 {
+				t.groups[len(t.groups)-1] = append(last, m)
+				continue
+			}
+		}
+		t.groups = append(t.groups, []Message{m})
+	}
+
+	switch strategy {
+	case TruncateOldest:
+		// The most recent turn is always kept
+		t.droppable = len(t.groups) - 1
+	case TruncateMiddle:
+		// Everything from the last user message onwards is kept
+		t.droppable = len(t.groups) - 1
+		for j := len(t.groups) - 1; j >= 0; j-- {
+			if t.groups[j][0].Role == RoleUser {
+				t.droppable = j
+				break
+			}
+		}
+	case TruncateHeadTail:
+		t.first = 1
+		t.droppable = len(t.groups) - 1
+	default:
+		return nil, errors.NewValidationError("strategy", "unknown truncation strategy", strategy)
+	}
+	if t.droppable < t.first {
+		t.droppable = t.first
+	}
+
+	return t, nil
+}
+
+// maxDrop returns the largest number of groups that may be dropped.
+func (t *truncator) maxDrop() int {
+	return t.droppable - t.first
+}
+
+// keep returns the messages kept when drop groups are dropped. The dropped
+// groups for drop+1 always include those for drop, so the token count
+// decreases monotonically.
+func (t *truncator) keep(drop int) []Message {
+	start := t.first
+	if t.strategy == TruncateMiddle {
+		start = (t.droppable - drop) / 2
+	}
+
+	kept := make([]Message, 0, len(t.system)+len(t.groups))
+	kept = append(kept, t.system...)
+	for i, g := range t.groups {
+		if i >= start && i < start+drop {
+			continue
+		}
+		kept = append(kept, g...)
+	}
+	return kept
+}
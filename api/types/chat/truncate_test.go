@@ -0,0 +1,241 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCounter counts one token per content byte plus one per message.
+type fakeCounter struct {
+	calls int
+	err   error
+}
+
+func (c *fakeCounter) CountTokens(_ context.Context, _ string, messages []Message) (int, error) {
+	c.calls++
+	if c.err != nil {
+		return 0, c.err
+	}
+	n := 0
+	for _, m := range messages {
+		s, _ := m.Content.(string)
+		n += len(s) + 1
+	}
+	return n, nil
+}
+
+// contents returns the string contents of messages.
+func contents(messages []Message) []string {
+	out := make([]string, len(messages))
+	for i, m := range messages {
+		out[i], _ = m.Content.(string)
+	}
+	return out
+}
+
+func toolCallMessage(id string) Message {
+	return Message{
+		Role:      RoleAssistant,
+		Content:   "call",
+		ToolCalls: []ToolCall{{ID: id, Type: "function"}},
+	}
+}
+
+func TestTruncateMessages(t *testing.T) {
+	t.Parallel()
+
+	// Each message costs 5 tokens: 4 content bytes plus 1
+	history := []Message{
+		NewSystemMessage("sys0"),
+		NewUserMessage("usr1"),
+		NewAssistantMessage("ast2"),
+		NewUserMessage("usr3"),
+		NewAssistantMessage("ast4"),
+		NewUserMessage("usr5"),
+		NewAssistantMessage("ast6"),
+		NewUserMessage("usr7"),
+	}
+
+	tests := []struct {
+		name       string
+		strategy   TruncationStrategy
+		max        int
+		want       []string
+		wantTokens int
+	}{
+		{
+			name:       "fits unchanged",
+			strategy:   TruncateOldest,
+			max:        40,
+			want:       contents(history),
+			wantTokens: 40,
+		},
+		{
+			name:       "oldest",
+			strategy:   TruncateOldest,
+			max:        22,
+			want:       []string{"sys0", "usr5", "ast6", "usr7"},
+			wantTokens: 20,
+		},
+		{
+			name:       "middle",
+			strategy:   TruncateMiddle,
+			max:        25,
+			want:       []string{"sys0", "usr1", "usr5", "ast6", "usr7"},
+			wantTokens: 25,
+		},
+		{
+			name:       "head tail",
+			strategy:   TruncateHeadTail,
+			max:        20,
+			want:       []string{"sys0", "usr1", "ast6", "usr7"},
+			wantTokens: 20,
+		},
+		{
+			name:       "oldest keeps only system and last turn",
+			strategy:   TruncateOldest,
+			max:        10,
+			want:       []string{"sys0", "usr7"},
+			wantTokens: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			counter := &fakeCounter{}
+			got, tokens, err := TruncateMessages(context.Background(), counter, "glm-4.7", history, tt.max, tt.strategy)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, contents(got))
+			assert.Equal(t, tt.wantTokens, tokens)
+		})
+	}
+}
+
+func TestTruncateMessages_FewCounterCalls(t *testing.T) {
+	t.Parallel()
+
+	history := []Message{NewSystemMessage("sys")}
+	for i := 0; i < 1000; i++ {
+		history = append(history, NewUserMessage(fmt.Sprintf("m%03d", i)))
+	}
+
+	counter := &fakeCounter{}
+	got, tokens, err := TruncateMessages(context.Background(), counter, "glm-4.7", history, 504, TruncateOldest)
+	require.NoError(t, err)
+
+	assert.Len(t, got, 101)
+	assert.Equal(t, "m900", got[1].Content)
+	assert.Equal(t, 504, tokens)
+	assert.LessOrEqual(t, counter.calls, 2+11, "binary search should need O(log n) calls")
+}
+
+func TestTruncateMessages_ToolMessagesDroppedWithCall(t *testing.T) {
+	t.Parallel()
+
+	history := []Message{
+		NewUserMessage("usr0"),
+		toolCallMessage("call_1"),
+		NewToolMessage("call_1", "res1"),
+		NewToolMessage("call_1", "res2"),
+		NewAssistantMessage("ast4"),
+		NewUserMessage("usr5"),
+	}
+
+	counter := &fakeCounter{}
+	got, _, err := TruncateMessages(context.Background(), counter, "glm-4.7", history, 13, TruncateOldest)
+	require.NoError(t, err)
+
+	for _, m := range got {
+		assert.NotEqual(t, RoleTool, m.Role, "orphaned tool message kept")
+	}
+	assert.Equal(t, []string{"ast4", "usr5"}, contents(got))
+}
+
+func TestTruncateMessages_MiddleKeepsLastUserMessage(t *testing.T) {
+	t.Parallel()
+
+	history := []Message{
+		NewSystemMessage("sys0"),
+		NewUserMessage("usr1"),
+		NewAssistantMessage("ast2"),
+		NewUserMessage("usr3"),
+		toolCallMessage("call_1"),
+		NewToolMessage("call_1", "res5"),
+	}
+
+	counter := &fakeCounter{}
+	got, _, err := TruncateMessages(context.Background(), counter, "glm-4.7", history, 20, TruncateMiddle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sys0", "usr3", "call", "res5"}, contents(got))
+}
+
+func TestTruncateMessages_Errors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single message over limit", func(t *testing.T) {
+		t.Parallel()
+
+		history := []Message{NewUserMessage("this message is far too long")}
+		_, _, err := TruncateMessages(context.Background(), &fakeCounter{}, "glm-4.7", history, 10, TruncateOldest)
+
+		var limitErr *TokenLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, 29, limitErr.Tokens)
+		assert.Equal(t, 10, limitErr.MaxTokens)
+		assert.Equal(t, 1, limitErr.Messages)
+	})
+
+	t.Run("required messages over limit", func(t *testing.T) {
+		t.Parallel()
+
+		history := []Message{
+			NewSystemMessage("a long system prompt"),
+			NewUserMessage("usr1"),
+			NewUserMessage("usr2"),
+		}
+		_, _, err := TruncateMessages(context.Background(), &fakeCounter{}, "glm-4.7", history, 10, TruncateMiddle)
+
+		var limitErr *TokenLimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, 2, limitErr.Messages)
+	})
+
+	t.Run("counter error", func(t *testing.T) {
+		t.Parallel()
+
+		want := errors.New("tokenizer unavailable")
+		_, _, err := TruncateMessages(context.Background(), &fakeCounter{err: want}, "glm-4.7", []Message{NewUserMessage("hi")}, 10, TruncateOldest)
+		assert.ErrorIs(t, err, want)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		history := []Message{NewUserMessage("usr1"), NewUserMessage("usr2")}
+		_, _, err := TruncateMessages(context.Background(), &fakeCounter{}, "glm-4.7", history, 0, TruncateOldest)
+		assert.Error(t, err)
+
+		_, _, err = TruncateMessages(context.Background(), nil, "glm-4.7", history, 10, TruncateOldest)
+		assert.Error(t, err)
+
+		_, _, err = TruncateMessages(context.Background(), &fakeCounter{}, "glm-4.7", history, 5, TruncationStrategy(99))
+		assert.Error(t, err)
+	})
+}
+
+func TestTruncationStrategy_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "oldest", TruncateOldest.String())
+	assert.Equal(t, "middle", TruncateMiddle.String())
+	assert.Equal(t, "head_tail", TruncateHeadTail.String())
+	assert.Equal(t, "TruncationStrategy(99)", TruncationStrategy(99).String())
+}
@@ -0,0 +1,186 @@
+package chat
+
+// ToolWebSearch lets the model search the web during a completion. The
+// results the model used are returned in the WebSearch field of the
+// response, or of the stream chunks.
+type ToolWebSearch struct {
+	// Enable turns the search on or off.
+	Enable *bool `json:"enable,omitempty"`
+
+	// SearchEngine is the search engine, such as "search-prime". If empty,
+	// the platform default is used.
+	SearchEngine string `json:"search_engine,omitempty"`
+
+	// SearchQuery forces the query to search for instead of letting the
+	// model write one.
+	SearchQuery string `json:"search_query,omitempty"`
+
+	// SearchResult returns the search results in the response.
+	SearchResult bool `json:"search_result,omitempty"`
+
+	// SearchPrompt is the prompt the search results are inserted into.
+	SearchPrompt string `json:"search_prompt,omitempty"`
+
+	// Count is the number of results to search for.
+	Count int `json:"count,omitempty"`
+
+	// SearchDomainFilter limits the results to a domain.
+	SearchDomainFilter string `json:"search_domain_filter,omitempty"`
+
+	// SearchRecencyFilter limits the results by age: "oneDay", "oneWeek",
+	// "oneMonth", "oneYear" or "noLimit".
+	SearchRecencyFilter string `json:"search_recency_filter,omitempty"`
+
+	// ContentSize is the length of the result summaries: "medium" or
+	// "high".
+	ContentSize string `json:"content_size,omitempty"`
+
+	// ResultSequence places the results "before" or "after" the answer in
+	// a stream.
+	ResultSequence string `json:"result_sequence,omitempty"`
+}
+
+// WebSearchToolOption configures a web search tool.
+type WebSearchToolOption func(*ToolWebSearch)
+
+// NewWebSearchTool creates a tool that lets the model search the web
+// during a completion. The search is enabled; options configure it.
+//
+// Example:
+//
+//	req := chat.NewChatCompletionRequest(chat.ModelGLM47, messages).
+//	    AddTool(chat.NewWebSearchTool(
+//	        chat.WithSearchResult(true),
+//	        chat.WithSearchCount(5),
+//	        chat.WithSearchRecencyFilter("oneWeek"),
+//	    ))
+//
+//	resp, err := client.Chat.Create(ctx, req)
+//	if err != nil {
+//	    return err
+//	}
+//	for _, result := range resp.GetWebSearchResults() {
+//	    fmt.Printf("%s %s\n", result.Title, result.Link)
+//	}
+func NewWebSearchTool(opts ...WebSearchToolOption) Tool {
+	enable := true
+	search := &ToolWebSearch{Enable: &enable}
+	for _, opt := range opts {
+		opt(search)
+	}
+	return Tool{
+		Type:      "web_search",
+		WebSearch: search,
+	}
+}
+
+// WithSearchEnabled turns the search on or off, such as to keep the tool
+// on a shared request but skip the search for a turn.
+func WithSearchEnabled(enable bool) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.Enable = &enable
+	}
+}
+
+// WithSearchEngine sets the search engine.
+func WithSearchEngine(engine string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchEngine = engine
+	}
+}
+
+// WithSearchQuery forces the query to search for.
+func WithSearchQuery(query string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchQuery = query
+	}
+}
+
+// WithSearchResult sets whether the search results are returned in the
+// response.
+func WithSearchResult(enable bool) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchResult = enable
+	}
+}
+
+// WithSearchPrompt sets the prompt the search results are inserted into.
+func WithSearchPrompt(prompt string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchPrompt = prompt
+	}
+}
+
+// WithSearchCount sets the number of results to search for.
+func WithSearchCount(n int) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.Count = n
+	}
+}
+
+// WithSearchDomainFilter limits the results to a domain.
+func WithSearchDomainFilter(domain string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchDomainFilter = domain
+	}
+}
+
+// WithSearchRecencyFilter limits the results by age.
+func WithSearchRecencyFilter(recency string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.SearchRecencyFilter = recency
+	}
+}
+
+// WithSearchContentSize sets the length of the result summaries.
+func WithSearchContentSize(size string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.ContentSize = size
+	}
+}
+
+// WithSearchResultSequence places the results "before" or "after" the
+// answer in a stream.
+func WithSearchResultSequence(sequence string) WebSearchToolOption {
+	return func(s *ToolWebSearch) {
+		s.ResultSequence = sequence
+	}
+}
+
+// WebSearchResult is a web page the model found with a web search tool.
+type WebSearchResult struct {
+	// Title is the page title.
+	Title string `json:"title"`
+
+	// Link is the page URL.
+	Link string `json:"link"`
+
+	// Content is a snippet of the page.
+	Content string `json:"content,omitempty"`
+
+	// Media is the name of the site.
+	Media string `json:"media,omitempty"`
+
+	// Icon is the URL of the site icon.
+	Icon string `json:"icon,omitempty"`
+
+	// Refer is the citation marker of the result in the answer, such as
+	// "ref_1".
+	Refer string `json:"refer,omitempty"`
+
+	// PublishDate is the publication date of the page.
+	PublishDate string `json:"publish_date,omitempty"`
+}
+
+// GetWebSearchResults returns the web search results of the response, or
+// nil if no web search tool returned results.
+func (r *ChatCompletionResponse) GetWebSearchResults() []WebSearchResult {
+	return r.WebSearch
+}
+
+// GetWebSearchResults returns the web search results carried by the
+// chunk. They usually arrive in one chunk, before or after the answer
+// depending on WithSearchResultSequence.
+func (c *ChatCompletionChunk) GetWebSearchResults() []WebSearchResult {
+	return c.WebSearch
+}
@@ -0,0 +1,78 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebSearchTool(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewWebSearchTool()
+		assert.Equal(t, "web_search", tool.Type)
+		require.NotNil(t, tool.WebSearch)
+		require.NotNil(t, tool.WebSearch.Enable)
+		assert.True(t, *tool.WebSearch.Enable)
+		assert.Empty(t, tool.Function.Name)
+		assert.Nil(t, tool.Retrieval)
+
+		data, err := json.Marshal(tool)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"type":"web_search","web_search":{"enable":true}}`, string(data))
+	})
+
+	t.Run("options", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewWebSearchTool(
+			WithSearchEngine("search-prime"),
+			WithSearchQuery("glm release"),
+			WithSearchResult(true),
+			WithSearchPrompt("Cite the sources: {search_result}"),
+			WithSearchCount(10),
+			WithSearchDomainFilter("z.ai"),
+			WithSearchRecencyFilter("oneWeek"),
+			WithSearchContentSize("medium"),
+			WithSearchResultSequence("after"),
+		)
+
+		assert.Equal(t, &ToolWebSearch{
+			Enable:              tool.WebSearch.Enable,
+			SearchEngine:        "search-prime",
+			SearchQuery:         "glm release",
+			SearchResult:        true,
+			SearchPrompt:        "Cite the sources: {search_result}",
+			Count:               10,
+			SearchDomainFilter:  "z.ai",
+			SearchRecencyFilter: "oneWeek",
+			ContentSize:         "medium",
+			ResultSequence:      "after",
+		}, tool.WebSearch)
+		assert.True(t, *tool.WebSearch.Enable)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		tool := NewWebSearchTool(WithSearchEnabled(false))
+		require.NotNil(t, tool.WebSearch.Enable)
+		assert.False(t, *tool.WebSearch.Enable)
+	})
+}
+
+func TestGetWebSearchResults(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, (&ChatCompletionResponse{}).GetWebSearchResults())
+	assert.Nil(t, (&ChatCompletionChunk{}).GetWebSearchResults())
+
+	results := []WebSearchResult{{Title: "Go", Link: "https://go.dev", Refer: "ref_1"}}
+	assert.Equal(t, results, (&ChatCompletionResponse{WebSearch: results}).GetWebSearchResults())
+	assert.Equal(t, results, (&ChatCompletionChunk{WebSearch: results}).GetWebSearchResults())
+}
@@ -0,0 +1,62 @@
+package chat
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalRequest encodes req in the wire format of the chat completions
+// endpoint, byte for byte as the client sends it: ExtraFields and Extra
+// are merged into the top-level object and thinking fields the model does
+// not accept are left out. The format is covered by the golden files in
+// testdata, so proxies, loggers and batch builders can rely on it.
+//
+// Memory is not resolved; call ResolveMemory first to include the
+// remembered messages.
+//
+// Example:
+//
+//	body, err := chat.MarshalRequest(req)
+//	if err != nil {
+//	    return err
+//	}
+//	log.Printf("POST /chat/completions %s", body)
+func MarshalRequest(req *ChatCompletionRequest) ([]byte, error) {
+	if req == nil {
+		return nil, fmt.Errorf("chat: cannot marshal nil request")
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("chat: failed to marshal request: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalResponse decodes a chat completion response body the way the
+// client does. Fields the SDK has no typed field for are kept and
+// returned by Extra, and survive json.Marshal of the response.
+//
+// Example:
+//
+//	resp, err := chat.UnmarshalResponse(body)
+//	if err != nil {
+//	    return err
+//	}
+//	fmt.Println(resp.GetContent())
+func UnmarshalResponse(data []byte) (*ChatCompletionResponse, error) {
+	var resp ChatCompletionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("chat: failed to unmarshal response: %w", err)
+	}
+	return &resp, nil
+}
+
+// UnmarshalChunk decodes the data of one server-sent event of a streamed
+// chat completion, without the "data: " prefix, the way the client does.
+func UnmarshalChunk(data []byte) (*ChatCompletionChunk, error) {
+	var chunk ChatCompletionChunk
+	if err := json.Unmarshal(data, &chunk); err != nil {
+		return nil, fmt.Errorf("chat: failed to unmarshal chunk: %w", err)
+	}
+	return &chunk, nil
+}
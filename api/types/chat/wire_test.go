@@ -0,0 +1,454 @@
+package chat
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// weatherParameters is the JSON schema of the get_weather tool.
+var weatherParameters = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"location": map[string]any{"type": "string", "description": "City name"},
+		"unit":     map[string]any{"type": "string", "enum": []string{"celsius", "fahrenheit"}},
+	},
+	"required": []string{"location"},
+}
+
+func TestWire_MarshalRequest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		golden string
+		req    func() *ChatCompletionRequest
+	}{
+		{"request_basic.json", func() *ChatCompletionRequest {
+			return NewChatCompletionRequest("glm-4.7", []Message{
+				NewSystemMessage("You are a concise geography tutor."),
+				NewUserMessage("What is the capital of France?"),
+			}).
+				SetTemperature(0.6).
+				SetTopP(0.95).
+				SetMaxTokens(1024).
+				SetStop("\n\n").
+				SetResponseFormat(ResponseFormatText).
+				SetUserID("user-42").
+				SetRequestID("req-7f3c2a").
+				DisableThinking().
+				SetExtraField("sampling_mode", "beta")
+		}},
+		{"request_thinking.json", func() *ChatCompletionRequest {
+			return NewChatCompletionRequest("glm-4.7", []Message{
+				NewUserMessage("What is 17 × 23?"),
+				{Role: RoleAssistant, Content: "17 × 23 = 391.", ReasoningContent: "17 × 20 = 340 and 17 × 3 = 51."},
+				NewUserMessage("And 17 × 24?"),
+			}).
+				EnablePreservedThinking().
+				SetThinkingEffort(ThinkingEffortHigh).
+				SetMaxReasoningTokens(2048).
+				SetStream(true)
+		}},
+		{"request_thinking_filtered.json", func() *ChatCompletionRequest {
+			// GLM-4.6 accepts thinking but no effort or budget
+			return NewChatCompletionRequest("glm-4.6", []Message{NewUserMessage("What is 17 × 23?")}).
+				SetThinkingEffort(ThinkingEffortHigh).
+				SetMaxReasoningTokens(2048)
+		}},
+		{"request_tools.json", func() *ChatCompletionRequest {
+			index := 0
+			return NewChatCompletionRequest("glm-4.7", []Message{
+				NewUserMessage("What's the weather in Beijing?"),
+				{Role: RoleAssistant, ToolCalls: []ToolCall{{
+					ID:       "call_-8392719243524416",
+					Type:     "function",
+					Index:    &index,
+					Function: FunctionCall{Name: "get_weather", Arguments: `{"location":"Beijing","unit":"celsius"}`},
+				}}},
+				NewToolMessage("call_-8392719243524416", `{"temperature":21,"condition":"sunny"}`),
+			}).
+				AddTool(NewFunctionTool("get_weather", "Get the current weather of a city", weatherParameters)).
+				AddTool(NewRetrievalTool("kb-1024")).
+				SetToolChoiceFunction("get_weather").
+				SetParallelToolCalls(false).
+				SetToolStream(true).
+				SetStream(true)
+		}},
+		{"request_multimodal.json", func() *ChatCompletionRequest {
+			image := NewImageContentPart("https://cdn.example.com/cat.png")
+			image.ImageURL.Detail = "high"
+			return NewChatCompletionRequest("glm-4.6v", []Message{{
+				Role: RoleUser,
+				Content: []ContentPart{
+					NewTextContentPart("Compare these two images."),
+					image,
+					NewImageContentPart("data:image/jpeg;base64,/9j/4AAQSkZJRgABAQ=="),
+				},
+			}}).EnableThinking()
+		}},
+		{"request_prefix_caching.json", func() *ChatCompletionRequest {
+			return WithPrefixCaching(NewChatCompletionRequest("glm-4.7", []Message{
+				NewSystemMessage("You are a support agent for Acme. Answer from the product manual."),
+				NewSystemMessage("Product manual: ..."),
+				NewUserMessage("How do I reset my router?"),
+			})).
+				AddTool(NewFunctionTool("get_weather", "Get the current weather of a city", weatherParameters))
+		}},
+		{"request_web_search.json", func() *ChatCompletionRequest {
+			return NewChatCompletionRequest("glm-4.7", []Message{NewUserMessage("What's new in Go 1.25?")}).
+				AddTool(NewWebSearchTool(
+					WithSearchEngine("search-prime"),
+					WithSearchQuery("Go 1.25 release notes"),
+					WithSearchResult(true),
+					WithSearchCount(5),
+					WithSearchDomainFilter("go.dev"),
+					WithSearchRecencyFilter("oneYear"),
+					WithSearchContentSize("high"),
+					WithSearchResultSequence("before"),
+				)).
+				AddTool(NewRetrievalTool("kb-1024")).
+				AddTool(NewWebSearchTool(WithSearchEnabled(false)))
+		}},
+		{"request_cache_ttl.json", func() *ChatCompletionRequest {
+			return NewChatCompletionRequest("glm-4.7", []Message{
+				NewSystemMessage("You are a concise geography tutor."),
+				NewUserMessage("What is the capital of France?").WithCacheControl(NewCacheControl().SetTTL(time.Hour)),
+				{Role: RoleAssistant, Content: "Paris."},
+				NewUserMessage("And of Italy?"),
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := MarshalRequest(tt.req())
+			require.NoError(t, err)
+			golden.AssertJSON(t, tt.golden, data)
+		})
+	}
+}
+
+func TestWire_MarshalRequestNil(t *testing.T) {
+	t.Parallel()
+
+	_, err := MarshalRequest(nil)
+	assert.Error(t, err)
+}
+
+func TestWire_UnmarshalResponse(t *testing.T) {
+	t.Parallel()
+
+	index := func(i int) *int { return &i }
+	tests := []struct {
+		golden string
+		want   ChatCompletionResponse
+		extra  string
+	}{
+		{
+			golden: "response_basic.json",
+			want: ChatCompletionResponse{
+				ID:      "20251016103512c7a9f1e2b4d84c1a",
+				Object:  "chat.completion",
+				Created: 1760582112,
+				Model:   "glm-4.7",
+				Choices: []Choice{{
+					FinishReason: "stop",
+					Message:      Message{Role: RoleAssistant, Content: "Paris is the capital of France."},
+				}},
+				Usage: &models.Usage{
+					PromptTokens: 18, CompletionTokens: 8, TotalTokens: 26,
+					PromptTokensDetails: &models.PromptTokensDetails{CachedTokens: 4},
+				},
+			},
+			extra: `{"request_id":"req-7f3c2a"}`,
+		},
+		{
+			golden: "response_thinking.json",
+			want: ChatCompletionResponse{
+				ID:      "20251016103544a1b2c3d4e5f60718",
+				Object:  "chat.completion",
+				Created: 1760582144,
+				Model:   "glm-4.7",
+				Choices: []Choice{{
+					FinishReason: "stop",
+					Message: Message{
+						Role:             RoleAssistant,
+						Content:          "17 × 23 = 391.",
+						ReasoningContent: "17 × 20 = 340 and 17 × 3 = 51, so the product is 391.",
+					},
+				}},
+				Usage: &models.Usage{
+					PromptTokens: 14, CompletionTokens: 52, TotalTokens: 66,
+					CompletionTokensDetails: &models.CompletionTokensDetails{ReasoningTokens: 41},
+				},
+			},
+			extra: `{"request_id":"req-9d81e0"}`,
+		},
+		{
+			golden: "response_tool_calls.json",
+			want: ChatCompletionResponse{
+				ID:      "2025101610360284c2e1f0a3b94d57",
+				Object:  "chat.completion",
+				Created: 1760582162,
+				Model:   "glm-4.7",
+				Choices: []Choice{{
+					FinishReason: "tool_calls",
+					Message: Message{
+						Role:    RoleAssistant,
+						Content: "",
+						ToolCalls: []ToolCall{
+							{ID: "call_-8392719243524416", Index: index(0), Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"location":"Beijing","unit":"celsius"}`}},
+							{ID: "call_-8392719243524417", Index: index(1), Type: "function", Function: FunctionCall{Name: "get_weather", Arguments: `{"location":"Shanghai","unit":"celsius"}`}},
+						},
+					},
+				}},
+				Usage:     &models.Usage{PromptTokens: 152, CompletionTokens: 38, TotalTokens: 190},
+				WebSearch: []WebSearchResult{{Title: "Beijing weather", Link: "https://weather.example.com/beijing"}},
+			},
+			extra: `{"request_id":"req-4be210"}`,
+		},
+		{
+			golden: "response_web_search.json",
+			want: ChatCompletionResponse{
+				ID:      "20251016104015b3c9d2e1f4a85c67",
+				Object:  "chat.completion",
+				Created: 1760582415,
+				Model:   "glm-4.7",
+				Choices: []Choice{{
+					FinishReason: "stop",
+					Message: Message{
+						Role:    RoleAssistant,
+						Content: "Go 1.25 makes GOMAXPROCS container-aware [ref_1] and adds testing/synctest [ref_2].",
+					},
+				}},
+				Usage: &models.Usage{PromptTokens: 1834, CompletionTokens: 27, TotalTokens: 1861},
+				WebSearch: []WebSearchResult{
+					{
+						Title:       "Go 1.25 Release Notes",
+						Link:        "https://go.dev/doc/go1.25",
+						Content:     "The runtime now considers the CPU bandwidth limit of the cgroup when setting GOMAXPROCS.",
+						Media:       "go.dev",
+						Icon:        "https://go.dev/images/favicon-gopher.png",
+						Refer:       "ref_1",
+						PublishDate: "2025-08-12",
+					},
+					{
+						Title:       "Go 1.25 is released",
+						Link:        "https://go.dev/blog/go1.25",
+						Content:     "The testing/synctest package for testing concurrent code is now generally available.",
+						Media:       "The Go Blog",
+						Refer:       "ref_2",
+						PublishDate: "2025-08-12",
+					},
+				},
+			},
+			extra: `{"request_id":"req-51c0ae"}`,
+		},
+		{
+			golden: "response_model_build.json",
+			want: ChatCompletionResponse{
+				ID:                "20251016104230e5f6a7b8c9d0e1f2",
+				Object:            "chat.completion",
+				Created:           1760582550,
+				Model:             "glm-4.7",
+				ModelVersion:      "glm-4.7-20251001",
+				SystemFingerprint: "fp_8c1e4a7d2b",
+				ServiceTier:       "default",
+				Choices: []Choice{{
+					FinishReason: "stop",
+					Message:      Message{Role: RoleAssistant, Content: "Berlin is the capital of Germany."},
+				}},
+				Usage: &models.Usage{PromptTokens: 18, CompletionTokens: 8, TotalTokens: 26},
+			},
+			extra: `{"request_id":"req-c41d07","region":"cn-north-1"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := UnmarshalResponse(golden.Read(t, tt.golden))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.extra, string(resp.Extra()))
+
+			got := *resp
+			got.extra = nil
+			assert.Equal(t, tt.want, got)
+
+			// Re-encoding keeps every field
+			data, err := json.Marshal(resp)
+			require.NoError(t, err)
+			again, err := UnmarshalResponse(data)
+			require.NoError(t, err)
+			assert.Equal(t, resp, again)
+		})
+	}
+}
+
+func TestWire_ModelBuild(t *testing.T) {
+	t.Parallel()
+
+	resp, err := UnmarshalResponse(golden.Read(t, "response_model_build.json"))
+	require.NoError(t, err)
+	assert.Equal(t, ModelBuild{
+		Model:             "glm-4.7",
+		ModelVersion:      "glm-4.7-20251001",
+		SystemFingerprint: "fp_8c1e4a7d2b",
+		ServiceTier:       "default",
+	}, resp.GetModelBuild())
+
+	// Responses without the fields leave them empty
+	resp, err = UnmarshalResponse(golden.Read(t, "response_basic.json"))
+	require.NoError(t, err)
+	assert.Equal(t, ModelBuild{Model: "glm-4.7"}, resp.GetModelBuild())
+	assert.False(t, resp.GetModelBuild().IsZero())
+	assert.True(t, ModelBuild{}.IsZero())
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "system_fingerprint")
+	assert.NotContains(t, string(data), "service_tier")
+	assert.NotContains(t, string(data), "model_version")
+}
+
+func TestWire_UnmarshalResponseInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := UnmarshalResponse([]byte(`{"choices":"none"}`))
+	assert.ErrorContains(t, err, "chat: failed to unmarshal response")
+}
+
+func TestWire_UnmarshalChunk(t *testing.T) {
+	t.Parallel()
+
+	t.Run("thinking", func(t *testing.T) {
+		t.Parallel()
+
+		var reasoning, content string
+		var last *ChatCompletionChunk
+		for _, event := range golden.Events(t, "stream_thinking.sse") {
+			chunk, err := UnmarshalChunk(event)
+			require.NoError(t, err)
+			assert.Equal(t, "20251016103701f2e3d4c5b6a79801", chunk.ID)
+			assert.Equal(t, "glm-4.7", chunk.Model)
+			assert.Equal(t, RoleAssistant, chunk.Choices[0].Delta.Role)
+			reasoning += chunk.GetReasoningContent()
+			content += chunk.GetContent()
+			last = chunk
+		}
+
+		assert.Equal(t, "The user greets me, so I greet back.", reasoning)
+		assert.Equal(t, "Hello! How can I help?", content)
+		require.NotNil(t, last)
+		assert.True(t, last.IsFinished())
+		assert.Equal(t, FinishReasonStop, last.Choices[0].FinishReason)
+		assert.Equal(t, &models.Usage{
+			PromptTokens: 9, CompletionTokens: 21, TotalTokens: 30,
+			CompletionTokensDetails: &models.CompletionTokensDetails{ReasoningTokens: 12},
+		}, last.Usage)
+	})
+
+	t.Run("tool calls", func(t *testing.T) {
+		t.Parallel()
+
+		stream := &sliceStream{}
+		for _, event := range golden.Events(t, "stream_tool_calls.sse") {
+			chunk, err := UnmarshalChunk(event)
+			require.NoError(t, err)
+			stream.chunks = append(stream.chunks, chunk)
+		}
+		require.Len(t, stream.chunks, 4)
+
+		collector := NewToolCallCollector(stream)
+		for collector.Next() {
+		}
+		require.NoError(t, collector.Err())
+
+		calls := collector.ToolCalls()
+		require.Len(t, calls, 1)
+		assert.Equal(t, "call_-8392719243524501", calls[0].ID)
+		assert.Equal(t, "function", calls[0].Type)
+		assert.Equal(t, "get_weather", calls[0].Function.Name)
+		assert.Equal(t, `{"location":"Beijing"}`, calls[0].Function.Arguments)
+	})
+
+	t.Run("model build", func(t *testing.T) {
+		t.Parallel()
+
+		var build ModelBuild
+		var builds []ModelBuild
+		for _, event := range golden.Events(t, "stream_model_build.sse") {
+			chunk, err := UnmarshalChunk(event)
+			require.NoError(t, err)
+			builds = append(builds, chunk.GetModelBuild())
+			build.Merge(chunk.GetModelBuild())
+		}
+
+		// The fingerprint arrives on the first chunk, the rest on the last
+		require.Len(t, builds, 3)
+		assert.Equal(t, "fp_8c1e4a7d2b", builds[0].SystemFingerprint)
+		assert.Equal(t, ModelBuild{Model: "glm-4.7"}, builds[1])
+		assert.Empty(t, builds[2].SystemFingerprint)
+		assert.Equal(t, ModelBuild{
+			Model:             "glm-4.7",
+			ModelVersion:      "glm-4.7-20251001",
+			SystemFingerprint: "fp_8c1e4a7d2b",
+			ServiceTier:       "default",
+		}, build)
+	})
+
+	t.Run("web search", func(t *testing.T) {
+		t.Parallel()
+
+		var content string
+		var results []WebSearchResult
+		for _, event := range golden.Events(t, "stream_web_search.sse") {
+			chunk, err := UnmarshalChunk(event)
+			require.NoError(t, err)
+			content += chunk.GetContent()
+			results = append(results, chunk.GetWebSearchResults()...)
+		}
+
+		assert.Equal(t, "GOMAXPROCS is now container-aware [ref_1].", content)
+		require.Len(t, results, 1)
+		assert.Equal(t, "Go 1.25 Release Notes", results[0].Title)
+		assert.Equal(t, "https://go.dev/doc/go1.25", results[0].Link)
+		assert.Equal(t, "ref_1", results[0].Refer)
+		assert.NotEmpty(t, results[0].Content)
+	})
+}
+
+func TestWire_UnmarshalChunkInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := UnmarshalChunk([]byte(`data: {}`))
+	assert.ErrorContains(t, err, "chat: failed to unmarshal chunk")
+}
+
+func TestWire_UsageCachedTokens(t *testing.T) {
+	t.Parallel()
+
+	// The prompt cache served part of the prompt
+	resp, err := UnmarshalResponse(golden.Read(t, "response_basic.json"))
+	require.NoError(t, err)
+	assert.True(t, resp.Usage.HasCachedTokens())
+	assert.Equal(t, 4, resp.Usage.GetCachedTokens())
+	assert.Equal(t, 14, resp.Usage.GetUncachedPromptTokens())
+	assert.InDelta(t, 4.0/18.0, resp.Usage.CacheHitRatio(), 1e-9)
+
+	// No prompt_tokens_details block
+	resp, err = UnmarshalResponse(golden.Read(t, "response_tool_calls.json"))
+	require.NoError(t, err)
+	assert.False(t, resp.Usage.HasCachedTokens())
+	assert.Zero(t, resp.Usage.GetCachedTokens())
+	assert.Equal(t, 152, resp.Usage.GetUncachedPromptTokens())
+	assert.Zero(t, resp.Usage.CacheHitRatio())
+}
@@ -0,0 +1,336 @@
+package embeddings
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+const (
+	// DefaultChunkTokens is the chunk size of ChunkText in tokens.
+	DefaultChunkTokens = 512
+
+	// DefaultChunkCharacters is the chunk size of ChunkText in characters.
+	DefaultChunkCharacters = 2000
+
+	// MaxBatchInputs is the most texts sent in one embeddings request.
+	MaxBatchInputs = 64
+)
+
+// ChunkOptions configures how ChunkText splits a text.
+type ChunkOptions struct {
+	// Size is the largest size of a chunk, in tokens or, with Characters,
+	// in characters. Zero means DefaultChunkTokens or
+	// DefaultChunkCharacters.
+	Size int
+
+	// Overlap is how much of the end of a chunk is repeated at the start
+	// of the next one, in the same unit as Size. It must be less than
+	// Size.
+	Overlap int
+
+	// Characters measures Size and Overlap in characters instead of
+	// tokens.
+	Characters bool
+
+	// Model selects the token estimate of tools.EstimateTextTokens.
+	Model string
+
+	// CountTokens counts the tokens of a text instead of the offline
+	// estimate, for example with a tokenizer. It is called many times per
+	// chunk and must be monotonic: a longer text has at least as many
+	// tokens.
+	CountTokens func(text string) int
+
+	// HardSplit cuts chunks at exactly Size. By default a chunk ends at
+	// the last sentence boundary, or else the last word boundary, in its
+	// second half.
+	HardSplit bool
+}
+
+// Validate checks that the sizes are consistent.
+func (o ChunkOptions) Validate() error {
+	if o.Size < 0 {
+		return errors.NewValidationError("size", "must not be negative", o.Size)
+	}
+	if o.Overlap < 0 {
+		return errors.NewValidationError("overlap", "must not be negative", o.Overlap)
+	}
+	if size := o.size(); o.Overlap >= size {
+		return errors.NewValidationError("overlap", "must be less than the chunk size", o.Overlap)
+	}
+	return nil
+}
+
+// size returns the chunk size, with the default applied.
+func (o ChunkOptions) size() int {
+	switch {
+	case o.Size > 0:
+		return o.Size
+	case o.Characters:
+		return DefaultChunkCharacters
+	default:
+		return DefaultChunkTokens
+	}
+}
+
+// measure returns the function that measures a text in the unit of o.
+func (o ChunkOptions) measure() func(string) int {
+	switch {
+	case o.Characters:
+		return utf8.RuneCountInString
+	case o.CountTokens != nil:
+		return o.CountTokens
+	default:
+		return func(text string) int {
+			return tools.EstimateTextTokens(o.Model, text)
+		}
+	}
+}
+
+// Chunk is a part of a text split by ChunkText.
+type Chunk struct {
+	// Index is the position of the chunk in the text.
+	Index int
+
+	// Text is the text of the chunk, source[Start:End].
+	Text string
+
+	// Start and End are the byte offsets of the chunk in the source text.
+	Start int
+	End   int
+
+	// Size is the size of the chunk, in tokens or characters.
+	Size int
+}
+
+// ChunkEmbedding is a chunk paired with its embedding.
+type ChunkEmbedding struct {
+	Chunk
+
+	// Embedding is the embedding vector of the chunk.
+	Embedding []float64
+}
+
+// DocumentEmbedding is the result of embedding a text chunk by chunk.
+type DocumentEmbedding struct {
+	// Model is the model that created the embeddings.
+	Model string
+
+	// Chunks are the chunks of the text with their embeddings, in order.
+	Chunks []ChunkEmbedding
+
+	// Usage is the token usage of all the requests.
+	Usage models.Usage
+}
+
+// ChunkText splits text into chunks of at most opts.Size tokens or
+// characters, with opts.Overlap repeated between consecutive chunks.
+// Leading and trailing whitespace is left out of chunks, and text of only
+// whitespace has no chunks. Invalid options are not reported, see
+// ChunkOptions.Validate: a negative overlap counts as zero and an overlap
+// of at least Size as half of Size.
+//
+// Example:
+//
+//	chunks := embeddings.ChunkText(doc, embeddings.ChunkOptions{Size: 256, Overlap: 32})
+//	for _, c := range chunks {
+//	    fmt.Println(c.Start, c.End, c.Size)
+//	}
+func ChunkText(text string, opts ChunkOptions) []Chunk {
+	c := &chunker{
+		text:      text,
+		size:      opts.size(),
+		overlap:   max(opts.Overlap, 0),
+		measure:   opts.measure(),
+		hardSplit: opts.HardSplit,
+	}
+	if c.overlap >= c.size {
+		c.overlap = c.size / 2
+	}
+	for i := range text {
+		c.offsets = append(c.offsets, i)
+	}
+	c.offsets = append(c.offsets, len(text))
+	return c.chunks()
+}
+
+// chunker splits a text. Positions are rune indexes into offsets, which
+// holds the byte offset of each rune followed by the length of the text.
+type chunker struct {
+	text      string
+	offsets   []int
+	size      int
+	overlap   int
+	measure   func(string) int
+	hardSplit bool
+}
+
+func (c *chunker) chunks() []Chunk {
+	n := len(c.offsets) - 1
+
+	var chunks []Chunk
+	start := c.skipSpace(0)
+	for start < n {
+		end := c.fit(start)
+		if end < n && !c.hardSplit {
+			end = c.snap(start, end)
+		}
+
+		textEnd := end
+		for textEnd > start && unicode.IsSpace(c.rune(textEnd-1)) {
+			textEnd--
+		}
+		text := c.span(start, textEnd)
+		chunks = append(chunks, Chunk{
+			Index: len(chunks),
+			Text:  text,
+			Start: c.offsets[start],
+			End:   c.offsets[textEnd],
+			Size:  c.measure(text),
+		})
+		if end >= n {
+			break
+		}
+
+		next := c.overlapStart(start, textEnd)
+		if next <= start {
+			next = end
+		}
+		start = c.skipSpace(next)
+	}
+	return chunks
+}
+
+// rune returns the rune at position i.
+func (c *chunker) rune(i int) rune {
+	r, _ := utf8.DecodeRuneInString(c.text[c.offsets[i]:])
+	return r
+}
+
+// span returns the text from position i to position j.
+func (c *chunker) span(i, j int) string {
+	return c.text[c.offsets[i]:c.offsets[j]]
+}
+
+// skipSpace returns the first position from i that is not whitespace.
+func (c *chunker) skipSpace(i int) int {
+	for i < len(c.offsets)-1 && unicode.IsSpace(c.rune(i)) {
+		i++
+	}
+	return i
+}
+
+// fit returns the furthest end of a chunk from start that fits the chunk
+// size, and at least the next position. The end is searched by doubling
+// the length, then by bisection, so that only a few spans of about the
+// chunk length are measured.
+func (c *chunker) fit(start int) int {
+	n := len(c.offsets) - 1
+	lo, hi := start+1, start+1
+	for step := 1; ; step *= 2 {
+		if hi >= n {
+			hi = n
+			if c.measure(c.span(start, hi)) <= c.size {
+				return n
+			}
+			break
+		}
+		if c.measure(c.span(start, hi)) > c.size {
+			break
+		}
+		lo = hi
+		hi = start + 2*step
+	}
+
+	// The span to lo fits and the span to hi does not
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if c.measure(c.span(start, mid)) <= c.size {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// snap moves the end of the chunk from start back to the last sentence
+// boundary, or else the last word boundary, in the second half of the
+// chunk. It returns end if there is neither.
+func (c *chunker) snap(start, end int) int {
+	half := start + (end-start)/2
+	for i := end; i > half; i-- {
+		if c.sentenceEnd(i) {
+			return i
+		}
+	}
+	for i := end; i > half; i-- {
+		if unicode.IsSpace(c.rune(i)) && !unicode.IsSpace(c.rune(i-1)) {
+			return i
+		}
+	}
+	return end
+}
+
+// sentenceEnd reports whether a sentence ends before position i: after a
+// line break, a full-width terminator such as "。", or a terminator such
+// as "." followed by whitespace.
+func (c *chunker) sentenceEnd(i int) bool {
+	switch c.rune(i - 1) {
+	case '\n', '。', '！', '？', '…':
+		return true
+	case '.', '!', '?':
+		return i == len(c.offsets)-1 || unicode.IsSpace(c.rune(i))
+	}
+	return false
+}
+
+// overlapStart returns the start of the chunk after the chunk from start
+// to end: the first position whose span to end fits the overlap, moved
+// forward out of a word unless the chunks are split hard.
+func (c *chunker) overlapStart(start, end int) int {
+	if c.overlap == 0 {
+		return end
+	}
+
+	// The span from hi fits and the span from lo does not
+	lo, hi := start, end
+	for hi-lo > 1 {
+		mid := lo + (hi-lo)/2
+		if c.measure(c.span(mid, end)) <= c.overlap {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+
+	if !c.hardSplit {
+		// Do not start in the middle of a word, unless the overlap is
+		// all one word
+		i := hi
+		for i < end && c.inWord(i) {
+			i++
+		}
+		if i < end {
+			hi = i
+		}
+	}
+	return hi
+}
+
+// inWord reports whether position i is inside a word of a script that
+// separates words with spaces.
+func (c *chunker) inWord(i int) bool {
+	return i > 0 && isWordRune(c.rune(i-1)) && isWordRune(c.rune(i))
+}
+
+// isWordRune reports whether r is a letter or digit of a script that
+// separates words with spaces, unlike Chinese, Japanese and Korean.
+func isWordRune(r rune) bool {
+	return (unicode.IsLetter(r) || unicode.IsDigit(r)) &&
+		!unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
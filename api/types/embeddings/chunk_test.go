@@ -0,0 +1,147 @@
+package embeddings
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	zaierrors "github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// checkOffsets checks that every chunk is the text at its byte offsets.
+func checkOffsets(t *testing.T, text string, chunks []Chunk) {
+	t.Helper()
+
+	for i, c := range chunks {
+		assert.Equal(t, i, c.Index)
+		require.True(t, 0 <= c.Start && c.Start < c.End && c.End <= len(text), "chunk %d: [%d, %d)", i, c.Start, c.End)
+		assert.Equal(t, text[c.Start:c.End], c.Text, "chunk %d", i)
+		assert.True(t, utf8.ValidString(c.Text), "chunk %d", i)
+		if i > 0 {
+			assert.Greater(t, c.Start, chunks[i-1].Start, "chunks advance")
+		}
+	}
+}
+
+func chunkTexts(chunks []Chunk) []string {
+	texts := make([]string, len(chunks))
+	for i, c := range chunks {
+		texts[i] = c.Text
+	}
+	return texts
+}
+
+func TestChunkText_Overlap(t *testing.T) {
+	t.Parallel()
+
+	text := "abcdefghijklmnopqrstuvwxyz"
+
+	chunks := ChunkText(text, ChunkOptions{Size: 10, Overlap: 3, Characters: true})
+	checkOffsets(t, text, chunks)
+	assert.Equal(t, []string{"abcdefghij", "hijklmnopq", "opqrstuvwx", "vwxyz"}, chunkTexts(chunks))
+	for i, c := range chunks[1:] {
+		assert.Equal(t, chunks[i].End-3, c.Start, "chunk %d repeats 3 characters", i+1)
+	}
+
+	chunks = ChunkText(text, ChunkOptions{Size: 10, Characters: true})
+	assert.Equal(t, []string{"abcdefghij", "klmnopqrst", "uvwxyz"}, chunkTexts(chunks))
+
+	// Size minus overlap is the stride: 26 characters at stride 6
+	chunks = ChunkText(text, ChunkOptions{Size: 8, Overlap: 2, Characters: true})
+	checkOffsets(t, text, chunks)
+	assert.Equal(t, []int{0, 6, 12, 18}, []int{chunks[0].Start, chunks[1].Start, chunks[2].Start, chunks[3].Start})
+	assert.Len(t, chunks, 4)
+
+	// An overlap of at least the size is halved
+	chunks = ChunkText(text, ChunkOptions{Size: 10, Overlap: 12, Characters: true})
+	assert.Equal(t, 5, chunks[1].Start)
+}
+
+func TestChunkText_Sentences(t *testing.T) {
+	t.Parallel()
+
+	text := "The fox ran. It was quick! Then it slept in the sun for a long while. The end."
+
+	chunks := ChunkText(text, ChunkOptions{Size: 30, Characters: true})
+	checkOffsets(t, text, chunks)
+	assert.Equal(t, []string{"The fox ran. It was quick!", "Then it slept in the sun for a", "long while. The end."}, chunkTexts(chunks))
+
+	// Overlaps start at a word
+	chunks = ChunkText(text, ChunkOptions{Size: 30, Overlap: 8, Characters: true})
+	checkOffsets(t, text, chunks)
+	assert.Equal(t, "quick! Then it slept in the", chunks[1].Text)
+
+	// Hard splits ignore boundaries
+	chunks = ChunkText(text, ChunkOptions{Size: 30, Characters: true, HardSplit: true})
+	checkOffsets(t, text, chunks)
+	assert.Equal(t, "The fox ran. It was quick! The", chunks[0].Text)
+
+	assert.Empty(t, ChunkText("", ChunkOptions{}))
+	assert.Empty(t, ChunkText(" \n\t ", ChunkOptions{}))
+}
+
+func TestChunkText_CJK(t *testing.T) {
+	t.Parallel()
+
+	text := "人工智能是计算机科学的一个分支。它研究智能的本质，并生产出一种新的智能机器。该领域的研究包括机器人、语言识别和图像识别。"
+
+	chunks := ChunkText(text, ChunkOptions{Size: 30, Overlap: 5, Characters: true})
+	checkOffsets(t, text, chunks)
+	for _, c := range chunks {
+		assert.LessOrEqual(t, utf8.RuneCountInString(c.Text), 30)
+		assert.Equal(t, utf8.RuneCountInString(c.Text), c.Size)
+	}
+	assert.Equal(t, "人工智能是计算机科学的一个分支。它研究智能的本质，并生产出一种新的智能机器。"[:len("人工智能是计算机科学的一个分支。")], chunks[0].Text, "chunks end at a sentence")
+	assert.True(t, strings.HasPrefix(chunks[1].Text, "一个分支。"), "the overlap is 5 characters: %q", chunks[1].Text)
+
+	// A token holds more than one CJK character
+	chunks = ChunkText(text, ChunkOptions{Size: 16, Model: "glm-4.7"})
+	checkOffsets(t, text, chunks)
+	for _, c := range chunks {
+		assert.LessOrEqual(t, c.Size, 16)
+	}
+	assert.Less(t, len(chunks), len(ChunkText(text, ChunkOptions{Size: 16, Characters: true})))
+}
+
+func TestChunkText_Tokens(t *testing.T) {
+	t.Parallel()
+
+	words := make([]string, 500)
+	for i := range words {
+		words[i] = "word"
+	}
+	text := strings.Join(words, " ")
+
+	chunks := ChunkText(text, ChunkOptions{Size: 100, Overlap: 10})
+	checkOffsets(t, text, chunks)
+	assert.Len(t, chunks, 6, "500 one-token words at a stride of 90")
+	for _, c := range chunks[:len(chunks)-1] {
+		assert.Equal(t, 100, c.Size)
+	}
+	assert.Equal(t, "word", chunks[1].Text[:4])
+
+	// A custom counter
+	chunks = ChunkText(text, ChunkOptions{Size: 50, CountTokens: func(s string) int { return len(strings.Fields(s)) * 2 }})
+	assert.Len(t, chunks, 20)
+}
+
+func TestChunkOptions_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ChunkOptions{}.Validate())
+	assert.NoError(t, ChunkOptions{Overlap: 100}.Validate(), "below the default size")
+
+	for _, opts := range []ChunkOptions{
+		{Size: -1},
+		{Overlap: -1},
+		{Size: 10, Overlap: 10},
+		{Characters: true, Overlap: DefaultChunkCharacters},
+	} {
+		err := opts.Validate()
+		var validationErr *zaierrors.ValidationError
+		assert.ErrorAs(t, err, &validationErr, "%+v", opts)
+	}
+}
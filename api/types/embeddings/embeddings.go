@@ -1,7 +1,19 @@
 // Package embeddings provides types for the Embeddings API.
 package embeddings
 
-import "github.com/sofianhadi1983/zai-sdk-go/internal/models"
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	apimodels "github.com/sofianhadi1983/zai-sdk-go/api/types/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
 
 // EmbeddingRequest represents a request to create embeddings.
 type EmbeddingRequest struct {
@@ -31,6 +43,11 @@ type EmbeddingRequest struct {
 	// RequestID is a unique identifier for the request.
 	// Optional.
 	RequestID string `json:"request_id,omitempty"`
+
+	// ExtraFields are sent as additional top-level fields, for parameters
+	// the SDK has no typed field for yet. Typed fields take precedence
+	// over entries with the same key.
+	ExtraFields map[string]any `json:"-"`
 }
 
 // SetDimensions sets the dimensions parameter.
@@ -39,7 +56,9 @@ func (r *EmbeddingRequest) SetDimensions(dimensions int) *EmbeddingRequest {
 	return r
 }
 
-// SetEncodingFormat sets the encoding format.
+// SetEncodingFormat sets the encoding format, EncodingFormatFloat or
+// EncodingFormatBase64. Base64 responses are about a third of the size of
+// float responses and are decoded by GetFloatEmbedding.
 func (r *EmbeddingRequest) SetEncodingFormat(format string) *EmbeddingRequest {
 	r.EncodingFormat = format
 	return r
@@ -64,6 +83,9 @@ type EmbeddingResponse struct {
 
 	// Usage is the token usage information.
 	Usage *models.Usage `json:"usage,omitempty"`
+
+	// extra holds the response fields the SDK has no typed field for.
+	extra json.RawMessage
 }
 
 // Embedding represents a single embedding.
@@ -73,15 +95,84 @@ type Embedding struct {
 
 	// Embedding is the embedding vector.
 	// Can be []float64 or string (base64 encoded) depending on encoding_format.
+	// Decoded float vectors are a []interface{} of float64 values.
 	Embedding interface{} `json:"embedding"`
 
 	// Index is the index of the embedding in the list.
 	Index int `json:"index"`
+
+	// Vector is the decoded float vector as a []float64, or nil for base64
+	// embeddings. It is set when the embedding is decoded from JSON and
+	// returned by GetFloatEmbedding without converting Embedding.
+	Vector []float64 `json:"-"`
+}
+
+// UnmarshalJSON decodes the embedding. A float vector is decoded once into
+// Vector, and Embedding holds the same values as a []interface{}.
+func (e *Embedding) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Object    string         `json:"object"`
+		Embedding embeddingValue `json:"embedding"`
+		Index     int            `json:"index"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Object = raw.Object
+	e.Embedding = raw.Embedding.value
+	e.Vector = nil
+	if floats, ok := raw.Embedding.value.([]float64); ok {
+		boxed := make([]interface{}, len(floats))
+		for i, f := range floats {
+			boxed[i] = f
+		}
+		e.Embedding = boxed
+		e.Vector = floats
+	}
+	e.Index = raw.Index
+	return nil
 }
 
-// GetFloatEmbedding returns the embedding as a float64 slice.
-// Returns nil if the embedding is not in float format.
+// embeddingValue decodes a float vector or a base64 string.
+type embeddingValue struct {
+	value interface{}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *embeddingValue) UnmarshalJSON(data []byte) error {
+	switch trimmed := bytes.TrimLeft(data, " \t\r\n"); {
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		var floats []float64
+		if err := json.Unmarshal(data, &floats); err != nil {
+			return err
+		}
+		v.value = floats
+	case bytes.HasPrefix(trimmed, []byte(`"`)):
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		v.value = str
+	default:
+		return json.Unmarshal(data, &v.value)
+	}
+	return nil
+}
+
+// GetFloatEmbedding returns the embedding as a float64 slice, decoding
+// base64 embeddings. Returns nil if the embedding cannot be decoded.
 func (e *Embedding) GetFloatEmbedding() []float64 {
+	if e.Vector != nil {
+		return e.Vector
+	}
+	if str, ok := e.Embedding.(string); ok {
+		floats, err := DecodeBase64Embedding(str)
+		if err != nil {
+			return nil
+		}
+		return floats
+	}
 	if floats, ok := e.Embedding.([]interface{}); ok {
 		result := make([]float64, len(floats))
 		for i, v := range floats {
@@ -106,8 +197,8 @@ func (e *Embedding) GetBase64Embedding() string {
 	return ""
 }
 
-// GetFirstEmbedding returns the first embedding from the response.
-// Returns nil if there are no embeddings.
+// GetFirstEmbedding returns the first embedding, or nil if the response
+// has no embeddings.
 func (r *EmbeddingResponse) GetFirstEmbedding() *Embedding {
 	if len(r.Data) == 0 {
 		return nil
@@ -115,8 +206,8 @@ func (r *EmbeddingResponse) GetFirstEmbedding() *Embedding {
 	return &r.Data[0]
 }
 
-// GetFloatEmbeddings returns all embeddings as float64 slices.
-// Skips any embeddings that are not in float format.
+// GetFloatEmbeddings returns all embeddings as float64 slices, decoding
+// base64 embeddings. Skips any embeddings that cannot be decoded.
 func (r *EmbeddingResponse) GetFloatEmbeddings() [][]float64 {
 	result := make([][]float64, 0, len(r.Data))
 	for _, emb := range r.Data {
@@ -135,18 +226,89 @@ const (
 	EncodingFormatBase64 = "base64"
 )
 
+// DecodeBase64Embedding decodes an embedding returned with
+// EncodingFormatBase64, which is base64 of little-endian float32 values.
+func DecodeBase64Embedding(encoded string) ([]float64, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 embedding: %w", err)
+	}
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("invalid base64 embedding: %d bytes is not a whole number of float32 values", len(data))
+	}
+
+	floats := make([]float64, len(data)/4)
+	for i := range floats {
+		floats[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:])))
+	}
+	return floats, nil
+}
+
+// SupportsBase64 reports whether model is known to accept
+// EncodingFormatBase64.
+func SupportsBase64(model string) bool {
+	return model == string(ModelEmbedding3)
+}
+
+// Model name constants for embeddings.
+// Any other model name can be passed as a plain string.
+const (
+	ModelEmbedding2 = apimodels.Embedding2
+	ModelEmbedding3 = apimodels.Embedding3
+)
+
 // NewEmbeddingRequest creates a new embedding request with a single text input.
-func NewEmbeddingRequest(model, text string) *EmbeddingRequest {
+// model accepts a model constant or any string.
+func NewEmbeddingRequest[M ~string](model M, text string) *EmbeddingRequest {
 	return &EmbeddingRequest{
-		Model: model,
+		Model: string(model),
 		Input: text,
 	}
 }
 
 // NewBatchEmbeddingRequest creates a new embedding request with multiple text inputs.
-func NewBatchEmbeddingRequest(model string, texts []string) *EmbeddingRequest {
+// model accepts a model constant or any string.
+func NewBatchEmbeddingRequest[M ~string](model M, texts []string) *EmbeddingRequest {
 	return &EmbeddingRequest{
-		Model: model,
+		Model: string(model),
 		Input: texts,
 	}
 }
+
+// maxListedFailures is the number of failed inputs BatchResultsError
+// describes in its message.
+const maxListedFailures = 5
+
+// BatchResultsError reports the inputs of an asynchronous embeddings batch
+// that got no embedding.
+type BatchResultsError struct {
+	// Errors maps the index of each failed input to its error.
+	Errors map[int]error
+}
+
+// Error implements the error interface.
+func (e *BatchResultsError) Error() string {
+	indexes := make([]int, 0, len(e.Errors))
+	for i := range e.Errors {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	parts := make([]string, 0, maxListedFailures+1)
+	for _, i := range indexes[:min(len(indexes), maxListedFailures)] {
+		parts = append(parts, fmt.Sprintf("input %d: %v", i, e.Errors[i]))
+	}
+	if len(indexes) > maxListedFailures {
+		parts = append(parts, fmt.Sprintf("and %d more", len(indexes)-maxListedFailures))
+	}
+	return fmt.Sprintf("embeddings: %d input(s) failed: %s", len(indexes), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-input errors.
+func (e *BatchResultsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
@@ -1,7 +1,12 @@
 package embeddings
 
 import (
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -27,6 +32,16 @@ func TestNewBatchEmbeddingRequest(t *testing.T) {
 	assert.Equal(t, texts, req.Input)
 }
 
+func TestNewEmbeddingRequest_ModelConstant(t *testing.T) {
+	t.Parallel()
+
+	req := NewEmbeddingRequest(ModelEmbedding3, "Hello world")
+	assert.Equal(t, "embedding-3", req.Model)
+
+	batch := NewBatchEmbeddingRequest(ModelEmbedding2, []string{"a", "b"})
+	assert.Equal(t, "embedding-2", batch.Model)
+}
+
 func TestEmbeddingRequest_Setters(t *testing.T) {
 	t.Parallel()
 
@@ -162,6 +177,21 @@ func TestEmbedding_GetFloatEmbedding(t *testing.T) {
 	t.Run("with base64 string", func(t *testing.T) {
 		t.Parallel()
 
+		// Little-endian float32 values 1.5, -2.25 and 0.1
+		emb := &Embedding{
+			Embedding: "AADAPwAAEMDNzMw9",
+		}
+
+		floats := emb.GetFloatEmbedding()
+		require.Len(t, floats, 3)
+		assert.Equal(t, 1.5, floats[0])
+		assert.Equal(t, -2.25, floats[1])
+		assert.InDelta(t, 0.1, floats[2], 1e-7)
+	})
+
+	t.Run("with invalid base64 payload", func(t *testing.T) {
+		t.Parallel()
+
 		emb := &Embedding{
 			Embedding: "YmFzZTY0ZW5jb2RlZA==",
 		}
@@ -171,6 +201,63 @@ func TestEmbedding_GetFloatEmbedding(t *testing.T) {
 	})
 }
 
+func TestEmbedding_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	var e Embedding
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"embedding","index":2,"embedding":[0.5,-1,2e-3]}`), &e))
+	assert.Equal(t, "embedding", e.Object)
+	assert.Equal(t, 2, e.Index)
+	assert.Equal(t, []interface{}{0.5, -1.0, 0.002}, e.Embedding)
+	assert.Equal(t, []float64{0.5, -1, 0.002}, e.Vector)
+	assert.Equal(t, e.Vector, e.GetFloatEmbedding())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"index":0,"embedding": "AAAAPw=="}`), &e))
+	assert.Equal(t, "AAAAPw==", e.GetBase64Embedding())
+	assert.Nil(t, e.Vector, "the vector of the previous decode is cleared")
+	assert.Equal(t, []float64{0.5}, e.GetFloatEmbedding())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"index":0,"embedding":null}`), &e))
+	assert.Nil(t, e.Embedding)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"embedding":[1,"two"]}`), &e))
+}
+
+func TestDecodeBase64Embedding(t *testing.T) {
+	t.Parallel()
+
+	want := []float32{0.25, -1, 3.1415927, 1e-8}
+	data := make([]byte, 0, len(want)*4)
+	for _, f := range want {
+		data = binary.LittleEndian.AppendUint32(data, math.Float32bits(f))
+	}
+
+	floats, err := DecodeBase64Embedding(base64.StdEncoding.EncodeToString(data))
+	require.NoError(t, err)
+	require.Len(t, floats, len(want))
+	for i, f := range want {
+		assert.Equal(t, float64(f), floats[i])
+	}
+
+	empty, err := DecodeBase64Embedding("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = DecodeBase64Embedding("not base64!")
+	assert.Error(t, err)
+
+	_, err = DecodeBase64Embedding("AAAA") // 3 bytes
+	assert.ErrorContains(t, err, "float32")
+}
+
+func TestSupportsBase64(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, SupportsBase64(string(ModelEmbedding3)))
+	assert.False(t, SupportsBase64(string(ModelEmbedding2)))
+	assert.False(t, SupportsBase64("custom-model"))
+}
+
 func TestEmbedding_GetBase64Embedding(t *testing.T) {
 	t.Parallel()
 
@@ -430,3 +517,18 @@ func TestEmbeddingResponse_RealWorldExample(t *testing.T) {
 	require.NotNil(t, resp.Usage)
 	assert.Equal(t, 8, resp.Usage.TotalTokens)
 }
+
+func TestBatchResultsError(t *testing.T) {
+	t.Parallel()
+
+	invalid := errors.New("input is invalid")
+	err := &BatchResultsError{Errors: map[int]error{9: invalid, 2: errors.New("no result")}}
+	assert.EqualError(t, err, "embeddings: 2 input(s) failed: input 2: no result; input 9: input is invalid")
+	assert.ErrorIs(t, err, invalid)
+
+	for i := range 5 {
+		err.Errors[10+i] = fmt.Errorf("failure %d", i)
+	}
+	assert.EqualError(t, err, "embeddings: 7 input(s) failed: input 2: no result; input 9: input is invalid; "+
+		"input 10: failure 0; input 11: failure 1; input 12: failure 2; and 2 more")
+}
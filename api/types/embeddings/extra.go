@@ -0,0 +1,50 @@
+package embeddings
+
+import (
+	"encoding/json"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
+
+// SetExtraField sets a top-level request field the SDK has no typed field
+// for, such as a beta parameter. Typed fields take precedence.
+func (r *EmbeddingRequest) SetExtraField(key string, value any) *EmbeddingRequest {
+	if r.ExtraFields == nil {
+		r.ExtraFields = make(map[string]any)
+	}
+	r.ExtraFields[key] = value
+	return r
+}
+
+// MarshalJSON encodes the request with ExtraFields merged into the
+// top-level object.
+func (r EmbeddingRequest) MarshalJSON() ([]byte, error) {
+	type alias EmbeddingRequest
+	return models.MarshalWithExtra(alias(r), r.ExtraFields)
+}
+
+// Extra returns the response fields the SDK has no typed field for as a
+// JSON object, or nil if there are none.
+func (r *EmbeddingResponse) Extra() json.RawMessage {
+	return r.extra
+}
+
+// UnmarshalJSON decodes the response and keeps unknown fields for Extra.
+func (r *EmbeddingResponse) UnmarshalJSON(data []byte) error {
+	type alias EmbeddingResponse
+	var known alias
+	extra, err := models.UnmarshalWithExtra(data, &known)
+	if err != nil {
+		return err
+	}
+
+	*r = EmbeddingResponse(known)
+	r.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the response including the fields returned by Extra.
+func (r EmbeddingResponse) MarshalJSON() ([]byte, error) {
+	type alias EmbeddingResponse
+	return models.MarshalWithRawExtra(alias(r), r.extra)
+}
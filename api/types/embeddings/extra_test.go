@@ -0,0 +1,37 @@
+package embeddings
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingRequest_ExtraFields(t *testing.T) {
+	t.Parallel()
+
+	req := NewEmbeddingRequest("embedding-3", "hello").
+		SetExtraField("beta_option", true).
+		SetExtraField("model", "ignored")
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "embedding-3", got["model"], "typed fields take precedence")
+	assert.Equal(t, true, got["beta_option"])
+}
+
+func TestEmbeddingResponse_Extra(t *testing.T) {
+	t.Parallel()
+
+	var resp EmbeddingResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"object":"list","data":[],"model":"embedding-3","request_id":"req-1"}`), &resp))
+	assert.JSONEq(t, `{"request_id":"req-1"}`, string(resp.Extra()))
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"object":"list","data":[],"model":"embedding-3","request_id":"req-1"}`, string(data))
+}
@@ -0,0 +1,87 @@
+package embeddings
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWire_Request(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		golden string
+		req    *EmbeddingRequest
+	}{
+		{"request_single.json", NewEmbeddingRequest(ModelEmbedding3, "The quick brown fox").
+			SetDimensions(512).
+			SetUser("user-42")},
+		{"request_batch.json", NewBatchEmbeddingRequest(ModelEmbedding3, []string{"first document", "second document"}).
+			SetEncodingFormat(EncodingFormatBase64).
+			SetExtraField("truncate", "end")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(tt.req)
+			require.NoError(t, err)
+			golden.AssertJSON(t, tt.golden, data)
+		})
+	}
+}
+
+func TestWire_Response(t *testing.T) {
+	t.Parallel()
+
+	t.Run("float", func(t *testing.T) {
+		t.Parallel()
+
+		var resp EmbeddingResponse
+		require.NoError(t, json.Unmarshal(golden.Read(t, "response_float.json"), &resp))
+		assert.Equal(t, "list", resp.Object)
+		assert.Equal(t, "embedding-3", resp.Model)
+		assert.Equal(t, &models.Usage{PromptTokens: 12, TotalTokens: 12}, resp.Usage)
+		assert.JSONEq(t, `{"request_id":"req-5a91c0"}`, string(resp.Extra()))
+
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, 1, resp.Data[1].Index)
+		assert.Equal(t, "embedding", resp.Data[1].Object)
+		assert.Equal(t, [][]float64{
+			{-0.0213623, 0.0107879, 0.0412598, -0.0056152},
+			{0.0301514, -0.0172119, 0.0028667, 0.0198975},
+		}, resp.GetFloatEmbeddings())
+
+		assertRoundTrip(t, &resp)
+	})
+
+	t.Run("base64", func(t *testing.T) {
+		t.Parallel()
+
+		var resp EmbeddingResponse
+		require.NoError(t, json.Unmarshal(golden.Read(t, "response_base64.json"), &resp))
+		first := resp.GetFirstEmbedding()
+		require.NotNil(t, first)
+		assert.Equal(t, "AAAAPwAAgL4AAAA+AACAPw==", first.GetBase64Embedding())
+		assert.Equal(t, []float64{0.5, -0.25, 0.125, 1}, first.GetFloatEmbedding())
+
+		assertRoundTrip(t, &resp)
+	})
+}
+
+// assertRoundTrip checks that re-encoding resp keeps every field.
+func assertRoundTrip(t *testing.T, resp *EmbeddingResponse) {
+	t.Helper()
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var again EmbeddingResponse
+	require.NoError(t, json.Unmarshal(data, &again))
+	assert.Equal(t, resp, &again)
+}
@@ -44,6 +44,20 @@ type CreateRequest struct {
 
 	// ToolType specifies the parsing tool to use (required).
 	ToolType ToolType
+
+	// URL is a remote file to parse instead of File.
+	URL string
+
+	// MaxFileSize overrides the maximum upload size in bytes.
+	// If zero, uses the documented limit of the tool type.
+	MaxFileSize int64
+
+	// SkipTypeCheck disables the check that FileType matches the
+	// extension of FileName.
+	SkipTypeCheck bool
+
+	// size is the length of File, or zero if unknown.
+	size int64
 }
 
 // NewCreateRequest creates a new file parser create request.
@@ -93,6 +107,12 @@ type ContentResponse struct {
 
 	// Data is the raw binary data (when FormatType is DownloadLink).
 	Data []byte
+
+	// Status is the task status, when the API reported one.
+	Status TaskStatus
+
+	// Message is the status message, when the API reported one.
+	Message string
 }
 
 // GetContent returns the content as a string.
@@ -128,6 +148,14 @@ type SyncRequest struct {
 
 	// ToolType must be ToolTypePrimeSync for sync operations.
 	ToolType ToolType
+
+	// MaxFileSize overrides the maximum upload size in bytes.
+	// If zero, uses the documented limit of the tool type.
+	MaxFileSize int64
+
+	// SkipTypeCheck disables the check that FileType matches the
+	// extension of FileName.
+	SkipTypeCheck bool
 }
 
 // NewSyncRequest creates a new synchronous file parser request.
@@ -0,0 +1,280 @@
+package fileparser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// ToolLimits lists the files a parsing tool accepts.
+type ToolLimits struct {
+	// FileTypes are the accepted file extensions, without the leading dot.
+	FileTypes []string
+
+	// MaxFileSize is the maximum upload size in bytes.
+	MaxFileSize int64
+}
+
+const megabyte = 1 << 20
+
+// documentTypes are the office and text formats accepted by most tools.
+var documentTypes = []string{"pdf", "doc", "docx", "xls", "xlsx", "ppt", "pptx", "csv", "txt", "md"}
+
+// imageTypes are the image formats accepted by most tools.
+var imageTypes = []string{"png", "jpg", "jpeg", "bmp", "gif", "webp"}
+
+// toolLimits holds the documented limits of each tool type.
+var toolLimits = map[ToolType]ToolLimits{
+	ToolTypeLite: {
+		FileTypes:   concat(documentTypes, []string{"png", "jpg", "jpeg"}),
+		MaxFileSize: 50 * megabyte,
+	},
+	ToolTypeExpert: {
+		FileTypes:   documentTypes,
+		MaxFileSize: 100 * megabyte,
+	},
+	ToolTypePrime: {
+		FileTypes:   concat(documentTypes, []string{"html"}, imageTypes, []string{"tiff", "heic"}),
+		MaxFileSize: 100 * megabyte,
+	},
+	ToolTypePrimeSync: {
+		FileTypes:   concat(documentTypes, []string{"html"}, imageTypes, []string{"tiff", "heic"}),
+		MaxFileSize: 100 * megabyte,
+	},
+}
+
+// LimitsFor returns the file limits of toolType, and false if the tool
+// type is not known to the SDK.
+func LimitsFor(toolType ToolType) (ToolLimits, bool) {
+	limits, ok := toolLimits[toolType]
+	return limits, ok
+}
+
+// NewCreateRequestFromURL creates a request to parse a remote file.
+// The file type is taken from the extension of the URL path; set FileType
+// if the URL has none.
+//
+// Example:
+//
+//	req := fileparser.NewCreateRequestFromURL(
+//	    "https://example.com/report.pdf",
+//	    fileparser.ToolTypePrime,
+//	)
+func NewCreateRequestFromURL(fileURL string, toolType ToolType) *CreateRequest {
+	name := fileURL
+	if u, err := url.Parse(fileURL); err == nil {
+		name = path.Base(u.Path)
+	}
+
+	return &CreateRequest{
+		FileName: name,
+		FileType: extension(name),
+		ToolType: toolType,
+		URL:      fileURL,
+	}
+}
+
+// NewCreateRequestFromBytes creates a request to parse in-memory file data.
+//
+// Example:
+//
+//	data, _ := os.ReadFile("report.pdf")
+//	req := fileparser.NewCreateRequestFromBytes(data, "report.pdf", "pdf", fileparser.ToolTypePrime)
+func NewCreateRequestFromBytes(data []byte, fileName, fileType string, toolType ToolType) *CreateRequest {
+	req := NewCreateRequest(bytes.NewReader(data), fileName, fileType, toolType)
+	req.size = int64(len(data))
+	return req
+}
+
+// NewCreateRequestFromBase64 creates a request to parse a base64-encoded
+// file, as produced by browsers and many storage APIs. The data is decoded
+// on the client and uploaded as a regular file.
+//
+// Example:
+//
+//	req, err := fileparser.NewCreateRequestFromBase64(payload, "scan.png", "png", fileparser.ToolTypeLite)
+//	if err != nil {
+//	    // Handle invalid base64
+//	}
+func NewCreateRequestFromBase64(encoded, fileName, fileType string, toolType ToolType) (*CreateRequest, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.NewValidationError("file", fmt.Sprintf("is not valid base64: %v", err), nil)
+	}
+	return NewCreateRequestFromBytes(data, fileName, fileType, toolType), nil
+}
+
+// SetMaxFileSize overrides the maximum upload size in bytes.
+//
+// Example:
+//
+//	req.SetMaxFileSize(200 << 20)
+func (r *CreateRequest) SetMaxFileSize(size int64) *CreateRequest {
+	r.MaxFileSize = size
+	return r
+}
+
+// SetSkipTypeCheck sets whether to skip the check that FileType matches the
+// extension of FileName.
+//
+// Example:
+//
+//	req.SetSkipTypeCheck(true)
+func (r *CreateRequest) SetSkipTypeCheck(skip bool) *CreateRequest {
+	r.SkipTypeCheck = skip
+	return r
+}
+
+// Validate checks that a file or URL is set and that the file type is
+// accepted by the tool type. Tool types unknown to the SDK are left to
+// the API.
+func (r *CreateRequest) Validate() error {
+	if r.File == nil && r.URL == "" {
+		return errors.NewValidationError("file", "either a file or a URL is required", nil)
+	}
+	if r.File != nil && r.URL != "" {
+		return errors.NewValidationError("url", "cannot be combined with a file", r.URL)
+	}
+	if err := validateFile(r.ToolType, r.FileName, r.FileType, r.SkipTypeCheck); err != nil {
+		return err
+	}
+	if limit := r.FileSizeLimit(); r.size > 0 && limit > 0 && r.size > limit {
+		return NewFileTooLargeError(r.size, limit)
+	}
+	return nil
+}
+
+// FileSizeLimit returns the maximum upload size in bytes: MaxFileSize if
+// set, otherwise the documented limit of the tool type, or zero if unknown.
+func (r *CreateRequest) FileSizeLimit() int64 {
+	return fileSizeLimit(r.ToolType, r.MaxFileSize)
+}
+
+// SetMaxFileSize overrides the maximum upload size in bytes.
+//
+// Example:
+//
+//	req.SetMaxFileSize(200 << 20)
+func (r *SyncRequest) SetMaxFileSize(size int64) *SyncRequest {
+	r.MaxFileSize = size
+	return r
+}
+
+// SetSkipTypeCheck sets whether to skip the check that FileType matches the
+// extension of FileName.
+//
+// Example:
+//
+//	req.SetSkipTypeCheck(true)
+func (r *SyncRequest) SetSkipTypeCheck(skip bool) *SyncRequest {
+	r.SkipTypeCheck = skip
+	return r
+}
+
+// Validate checks that a file is set and that the file type is accepted by
+// the tool type.
+func (r *SyncRequest) Validate() error {
+	if r.File == nil {
+		return errors.NewValidationError("file", "is required", nil)
+	}
+	return validateFile(r.ToolType, r.FileName, r.FileType, r.SkipTypeCheck)
+}
+
+// FileSizeLimit returns the maximum upload size in bytes: MaxFileSize if
+// set, otherwise the documented limit of the tool type, or zero if unknown.
+func (r *SyncRequest) FileSizeLimit() int64 {
+	return fileSizeLimit(r.ToolType, r.MaxFileSize)
+}
+
+// NewFileTooLargeError creates the validation error returned for files
+// larger than limit bytes.
+func NewFileTooLargeError(size, limit int64) *errors.ValidationError {
+	return errors.NewValidationError("file",
+		fmt.Sprintf("exceeds the maximum size of %d bytes", limit), size)
+}
+
+// validateFile checks the file type against the tool type and file name.
+func validateFile(toolType ToolType, fileName, fileType string, skipTypeCheck bool) error {
+	if toolType == "" {
+		return errors.NewValidationError("tool_type", "is required", nil)
+	}
+
+	fileType = normalizeType(fileType)
+	if fileType == "" {
+		return errors.NewValidationError("file_type", "is required", nil)
+	}
+
+	if limits, ok := toolLimits[toolType]; ok && !contains(limits.FileTypes, fileType) {
+		return errors.NewValidationError("file_type",
+			fmt.Sprintf("is not supported by %s (supported: %s)", toolType, strings.Join(limits.FileTypes, ", ")), fileType)
+	}
+
+	if !skipTypeCheck {
+		if ext := extension(fileName); ext != "" && !sameType(ext, fileType) {
+			return errors.NewValidationError("file_type",
+				fmt.Sprintf("does not match the extension of %q", fileName), fileType)
+		}
+	}
+
+	return nil
+}
+
+// fileSizeLimit returns the override if set, otherwise the tool limit.
+func fileSizeLimit(toolType ToolType, override int64) int64 {
+	if override > 0 {
+		return override
+	}
+	return toolLimits[toolType].MaxFileSize
+}
+
+// extension returns the normalized extension of name, without the dot.
+func extension(name string) string {
+	return normalizeType(path.Ext(name))
+}
+
+// normalizeType lowercases a file type and strips a leading dot.
+func normalizeType(fileType string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(fileType), "."))
+}
+
+// sameType reports whether two normalized file types are aliases.
+func sameType(a, b string) bool {
+	alias := func(t string) string {
+		switch t {
+		case "jpeg":
+			return "jpg"
+		case "tif":
+			return "tiff"
+		case "htm":
+			return "html"
+		case "markdown":
+			return "md"
+		}
+		return t
+	}
+	return alias(a) == alias(b)
+}
+
+// contains reports whether list contains fileType or one of its aliases.
+func contains(list []string, fileType string) bool {
+	for _, t := range list {
+		if sameType(t, fileType) {
+			return true
+		}
+	}
+	return false
+}
+
+// concat joins string lists.
+func concat(lists ...[]string) []string {
+	var out []string
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
@@ -0,0 +1,171 @@
+package fileparser
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestNewCreateRequestFromURL(t *testing.T) {
+	t.Parallel()
+
+	req := NewCreateRequestFromURL("https://example.com/files/Report.PDF?sig=abc", ToolTypePrime)
+
+	assert.Nil(t, req.File)
+	assert.Equal(t, "https://example.com/files/Report.PDF?sig=abc", req.URL)
+	assert.Equal(t, "Report.PDF", req.FileName)
+	assert.Equal(t, "pdf", req.FileType)
+	assert.NoError(t, req.Validate())
+
+	req = NewCreateRequestFromURL("https://example.com/download", ToolTypePrime)
+	assert.Equal(t, "", req.FileType)
+	assert.Error(t, req.Validate(), "file type cannot be inferred")
+
+	req.FileType = "docx"
+	assert.NoError(t, req.Validate())
+}
+
+func TestNewCreateRequestFromBytes(t *testing.T) {
+	t.Parallel()
+
+	req := NewCreateRequestFromBytes([]byte("0123456789"), "notes.txt", "txt", ToolTypeLite)
+	require.NoError(t, req.Validate())
+
+	req.SetMaxFileSize(5)
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, req.Validate(), &validationErr)
+	assert.Equal(t, "file", validationErr.Field)
+	assert.Equal(t, int64(10), validationErr.Value)
+}
+
+func TestNewCreateRequestFromBase64(t *testing.T) {
+	t.Parallel()
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("png data"))
+	req, err := NewCreateRequestFromBase64(encoded, "scan.png", "png", ToolTypeLite)
+	require.NoError(t, err)
+	assert.NoError(t, req.Validate())
+
+	_, err = NewCreateRequestFromBase64("not base64!", "scan.png", "png", ToolTypeLite)
+	var validationErr *errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestCreateRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     *CreateRequest
+		field   string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			req:  NewCreateRequest(strings.NewReader("x"), "report.pdf", "pdf", ToolTypeExpert),
+		},
+		{
+			name: "type with dot and case",
+			req:  NewCreateRequest(strings.NewReader("x"), "photo.JPEG", ".JPG", ToolTypeLite),
+		},
+		{
+			name:    "no file",
+			req:     &CreateRequest{FileName: "report.pdf", FileType: "pdf", ToolType: ToolTypePrime},
+			field:   "file",
+			wantErr: true,
+		},
+		{
+			name:    "file and URL",
+			req:     NewCreateRequest(strings.NewReader("x"), "report.pdf", "pdf", ToolTypePrime),
+			field:   "url",
+			wantErr: true,
+		},
+		{
+			name:    "missing tool type",
+			req:     NewCreateRequest(strings.NewReader("x"), "report.pdf", "pdf", ""),
+			field:   "tool_type",
+			wantErr: true,
+		},
+		{
+			name:    "missing file type",
+			req:     NewCreateRequest(strings.NewReader("x"), "report.pdf", "", ToolTypePrime),
+			field:   "file_type",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported by tool",
+			req:     NewCreateRequest(strings.NewReader("x"), "page.html", "html", ToolTypeLite),
+			field:   "file_type",
+			wantErr: true,
+		},
+		{
+			name:    "extension mismatch",
+			req:     NewCreateRequest(strings.NewReader("x"), "sheet.xlsx", "pdf", ToolTypePrime),
+			field:   "file_type",
+			wantErr: true,
+		},
+		{
+			name: "extension mismatch skipped",
+			req:  NewCreateRequest(strings.NewReader("x"), "sheet.xlsx", "pdf", ToolTypePrime).SetSkipTypeCheck(true),
+		},
+		{
+			name: "no extension",
+			req:  NewCreateRequest(strings.NewReader("x"), "upload", "pdf", ToolTypePrime),
+		},
+		{
+			name: "unknown tool type",
+			req:  NewCreateRequest(strings.NewReader("x"), "model.step", "step", ToolType("cad")),
+		},
+	}
+	tests[3].req.URL = "https://example.com/report.pdf"
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
+
+func TestSyncRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	req := NewSyncRequest(strings.NewReader("x"), "report.docx", "docx")
+	assert.NoError(t, req.Validate())
+
+	req = NewSyncRequest(strings.NewReader("x"), "report.docx", "pdf")
+	assert.Error(t, req.Validate())
+	assert.NoError(t, req.SetSkipTypeCheck(true).Validate())
+
+	req = NewSyncRequest(nil, "report.docx", "docx")
+	assert.Error(t, req.Validate())
+}
+
+func TestFileSizeLimit(t *testing.T) {
+	t.Parallel()
+
+	limits, ok := LimitsFor(ToolTypeLite)
+	require.True(t, ok)
+
+	req := NewCreateRequest(strings.NewReader("x"), "a.pdf", "pdf", ToolTypeLite)
+	assert.Equal(t, limits.MaxFileSize, req.FileSizeLimit())
+	assert.Equal(t, int64(1024), req.SetMaxFileSize(1024).FileSizeLimit())
+
+	_, ok = LimitsFor(ToolType("cad"))
+	assert.False(t, ok)
+	assert.Zero(t, NewCreateRequest(nil, "", "", ToolType("cad")).FileSizeLimit())
+}
@@ -0,0 +1,84 @@
+package fileparser
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// TaskStatus represents the status of an asynchronous parsing task.
+type TaskStatus string
+
+const (
+	// TaskStatusProcessing means the task is still being parsed.
+	TaskStatusProcessing TaskStatus = "processing"
+
+	// TaskStatusSucceeded means the result is ready.
+	TaskStatusSucceeded TaskStatus = "succeeded"
+
+	// TaskStatusFailed means parsing failed.
+	TaskStatusFailed TaskStatus = "failed"
+)
+
+// TaskStatusResponse is the JSON body returned by the result endpoint
+// while a task has no result, for example because it is still processing.
+type TaskStatusResponse struct {
+	// TaskID is the parsing task identifier.
+	TaskID string `json:"task_id"`
+
+	// Status is the task status.
+	Status TaskStatus `json:"status"`
+
+	// Message is the status message.
+	Message string `json:"message"`
+}
+
+// IsProcessing returns true if the result is not ready yet.
+func (r *ContentResponse) IsProcessing() bool {
+	return r.Status == TaskStatusProcessing
+}
+
+// IsFailed returns true if the task failed.
+func (r *ContentResponse) IsFailed() bool {
+	return r.Status == TaskStatusFailed
+}
+
+// TaskFailedError is returned when waiting for a task that failed.
+type TaskFailedError struct {
+	// TaskID is the parsing task identifier.
+	TaskID string
+
+	// Message is the failure message reported by the API.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *TaskFailedError) Error() string {
+	return fmt.Sprintf("file parser: task %s failed: %s", e.TaskID, e.Message)
+}
+
+// TimeoutError is returned when a task is not ready before the wait
+// timeout. It matches context.DeadlineExceeded with errors.Is.
+type TimeoutError struct {
+	// TaskID is the parsing task identifier.
+	TaskID string
+
+	// Status is the last status reported by the API.
+	Status TaskStatus
+
+	// Message is the last status message reported by the API.
+	Message string
+
+	// Timeout is the wait timeout that elapsed.
+	Timeout time.Duration
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("file parser: task %s still %s after %s", e.TaskID, e.Status, e.Timeout)
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
@@ -0,0 +1,71 @@
+package files
+
+// DefaultDownloadBufferSize is the size of the chunks a download is copied
+// in when no buffer size is given.
+const DefaultDownloadBufferSize = 32 << 10
+
+// ContentInfo describes the content of a file returned by
+// FilesService.StreamContent.
+type ContentInfo struct {
+	// ContentType is the MIME type of the file.
+	ContentType string
+
+	// ContentLength is the size of the file in bytes, or -1 if the server
+	// does not send it.
+	ContentLength int64
+}
+
+// DownloadProgress reports the progress of a FilesService.DownloadTo call.
+type DownloadProgress struct {
+	// Written is the number of bytes written so far.
+	Written int64
+
+	// Total is the size of the file in bytes, or -1 if unknown.
+	Total int64
+}
+
+// DownloadConfig holds settings for downloading file content.
+type DownloadConfig struct {
+	// BufferSize is the size of the chunks the content is copied in.
+	BufferSize int
+
+	// OnProgress, if set, is called after each chunk is written. Calls are
+	// never concurrent.
+	OnProgress func(DownloadProgress)
+}
+
+// DownloadOption configures downloading file content.
+type DownloadOption func(*DownloadConfig)
+
+// NewDownloadConfig creates a download configuration with the given
+// options applied.
+func NewDownloadConfig(opts ...DownloadOption) *DownloadConfig {
+	cfg := &DownloadConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultDownloadBufferSize
+	}
+	return cfg
+}
+
+// WithBufferSize sets the size of the chunks the content is copied in.
+func WithBufferSize(size int) DownloadOption {
+	return func(c *DownloadConfig) {
+		c.BufferSize = size
+	}
+}
+
+// WithProgress sets a callback called after each chunk is written.
+//
+// Example:
+//
+//	files.WithProgress(func(p files.DownloadProgress) {
+//	    fmt.Printf("%d/%d bytes\n", p.Written, p.Total)
+//	})
+func WithProgress(fn func(DownloadProgress)) DownloadOption {
+	return func(c *DownloadConfig) {
+		c.OnProgress = fn
+	}
+}
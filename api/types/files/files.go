@@ -1,7 +1,10 @@
 // Package files provides types for the Files API.
 package files
 
-import "io"
+import (
+	"io"
+	"strconv"
+)
 
 // FilePurpose represents the intended purpose of a file.
 type FilePurpose string
@@ -13,6 +16,12 @@ const (
 	PurposeFineTune FilePurpose = "fine-tune"
 	// PurposeBatch is for files used for batch processing.
 	PurposeBatch FilePurpose = "batch"
+	// PurposeRetrieval is for documents added to a knowledge base.
+	PurposeRetrieval FilePurpose = "retrieval"
+	// PurposeFileExtract is for documents whose content is extracted.
+	PurposeFileExtract FilePurpose = "file-extract"
+	// PurposeVision is for images used with vision models.
+	PurposeVision FilePurpose = "vision"
 )
 
 // FileStatus represents the status of a file.
@@ -64,6 +73,19 @@ type FileUploadRequest struct {
 
 	// Purpose is the intended purpose of the file.
 	Purpose FilePurpose
+
+	// SkipValidation skips the checks of the file against its purpose;
+	// see Validate.
+	SkipValidation bool
+
+	// MaxFileSize overrides the maximum upload size in bytes. Zero uses
+	// the documented limit of the purpose.
+	MaxFileSize int64
+
+	// ScanLines is the number of lines of a JSONL file checked before
+	// upload. Zero uses DefaultScanLines; a negative number checks every
+	// line.
+	ScanLines int
 }
 
 // NewFileUploadRequest creates a new file upload request.
@@ -92,6 +114,66 @@ type FileListResponse struct {
 	HasMore bool `json:"has_more,omitempty"`
 }
 
+// ListParams holds the query parameters for listing files.
+type ListParams struct {
+	// After is the cursor: the ID of the last file of the previous page.
+	After string
+
+	// Limit is the maximum number of files per page. Zero uses the API default.
+	Limit int
+
+	// Purpose filters the list to files with this purpose.
+	Purpose FilePurpose
+}
+
+// ListOption configures a file list request.
+type ListOption func(*ListParams)
+
+// NewListParams creates list parameters from options.
+func NewListParams(opts ...ListOption) *ListParams {
+	params := &ListParams{}
+	for _, opt := range opts {
+		opt(params)
+	}
+	return params
+}
+
+// WithAfter starts the list after the file with the given ID.
+func WithAfter(fileID string) ListOption {
+	return func(p *ListParams) {
+		p.After = fileID
+	}
+}
+
+// WithLimit sets the maximum number of files per page.
+func WithLimit(limit int) ListOption {
+	return func(p *ListParams) {
+		p.Limit = limit
+	}
+}
+
+// WithPurpose lists only files with the given purpose.
+func WithPurpose(purpose FilePurpose) ListOption {
+	return func(p *ListParams) {
+		p.Purpose = purpose
+	}
+}
+
+// Query returns the parameters as URL query values. Unset parameters are omitted.
+func (p *ListParams) Query() map[string]string {
+	query := make(map[string]string)
+	if p.After != "" {
+		query["after"] = p.After
+	}
+	if p.Limit > 0 {
+		query["limit"] = strconv.Itoa(p.Limit)
+	}
+	if p.Purpose != "" {
+		query["purpose"] = string(p.Purpose)
+	}
+	return query
+}
+
 // FileDeleteResponse represents the response when deleting a file.
 type FileDeleteResponse struct {
 	// ID is the ID of the deleted file.
@@ -157,6 +239,15 @@ func (r *FileListResponse) GetFileIDs() []string {
 	return ids
 }
 
+// GetLastID returns the ID of the last file in the list, which is the
+// cursor for the next page. Returns "" if the list is empty.
+func (r *FileListResponse) GetLastID() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+	return r.Data[len(r.Data)-1].ID
+}
+
 // GetFileByID finds a file by ID in the list response.
 func (r *FileListResponse) GetFileByID(id string) *File {
 	for i := range r.Data {
@@ -245,6 +245,29 @@ func TestFileListResponse_GetFileByID(t *testing.T) {
 	})
 }
 
+func TestFileListResponse_GetLastID(t *testing.T) {
+	t.Parallel()
+
+	resp := &FileListResponse{Data: []File{{ID: "file-1"}, {ID: "file-2"}}}
+	assert.Equal(t, "file-2", resp.GetLastID())
+
+	empty := &FileListResponse{}
+	assert.Equal(t, "", empty.GetLastID())
+}
+
+func TestListParams_Query(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, NewListParams().Query())
+
+	params := NewListParams(WithAfter("file-1"), WithLimit(10), WithPurpose(PurposeBatch))
+	assert.Equal(t, map[string]string{
+		"after":   "file-1",
+		"limit":   "10",
+		"purpose": "batch",
+	}, params.Query())
+}
+
 func TestFileListResponse_GetFilesByPurpose(t *testing.T) {
 	t.Parallel()
 
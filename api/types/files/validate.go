@@ -0,0 +1,278 @@
+package files
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// DefaultScanLines is the number of lines of a JSONL file checked before
+// upload, unless set with SetScanLines.
+const DefaultScanLines = 1000
+
+// PurposeLimits lists the files a purpose accepts.
+type PurposeLimits struct {
+	// Extensions are the accepted file extensions, without the leading dot.
+	Extensions []string
+
+	// MaxFileSize is the maximum upload size in bytes.
+	MaxFileSize int64
+
+	// JSONL reports whether the file must be in JSONL format, one JSON
+	// object per line.
+	JSONL bool
+}
+
+const megabyte = 1 << 20
+
+// documentExtensions are the document formats accepted for knowledge and
+// extraction purposes.
+var documentExtensions = []string{"pdf", "doc", "docx", "xls", "xlsx", "ppt", "pptx", "txt", "md", "csv"}
+
+// imageExtensions are the image formats accepted for vision.
+var imageExtensions = []string{"png", "jpg", "jpeg", "webp", "gif", "bmp"}
+
+// purposeLimits holds the documented limits of each purpose.
+var purposeLimits = map[FilePurpose]PurposeLimits{
+	PurposeBatch:       {Extensions: []string{"jsonl"}, MaxFileSize: 100 * megabyte, JSONL: true},
+	PurposeFineTune:    {Extensions: []string{"jsonl"}, MaxFileSize: 100 * megabyte, JSONL: true},
+	PurposeAssistants:  {Extensions: slices.Concat(documentExtensions, []string{"json", "jsonl", "html"}, imageExtensions), MaxFileSize: 50 * megabyte},
+	PurposeRetrieval:   {Extensions: slices.Concat(documentExtensions, []string{"json"}), MaxFileSize: 50 * megabyte},
+	PurposeFileExtract: {Extensions: slices.Concat(documentExtensions, []string{"png", "jpg", "jpeg"}), MaxFileSize: 50 * megabyte},
+	PurposeVision:      {Extensions: imageExtensions, MaxFileSize: 5 * megabyte},
+}
+
+// LimitsFor returns the file limits of purpose, and false if the purpose is
+// not known to the SDK.
+func LimitsFor(purpose FilePurpose) (PurposeLimits, bool) {
+	limits, ok := purposeLimits[purpose]
+	return limits, ok
+}
+
+// jsonlFormat describes the lines of a JSONL file.
+type jsonlFormat struct {
+	// keys are the keys every line must have.
+	keys []string
+
+	// uniqueKey is a key whose string value must be unique and not empty,
+	// if any.
+	uniqueKey string
+}
+
+// jsonlFormats holds the line format of the JSONL purposes.
+var jsonlFormats = map[FilePurpose]jsonlFormat{
+	PurposeBatch:    {keys: []string{"custom_id", "method", "url", "body"}, uniqueKey: "custom_id"},
+	PurposeFineTune: {keys: []string{"messages"}},
+}
+
+// ValidateBatchInput checks that r is a batch input file: JSONL with one
+// request per line, each with a unique custom_id and a method, url and
+// body. It reads r to the end and returns a *errors.ValidationError naming
+// the first offending line.
+//
+// Example:
+//
+//	f, err := os.Open("input.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//	if err := files.ValidateBatchInput(f); err != nil {
+//	    log.Fatal(err) // validation error for field 'file': line 3 is missing "custom_id"
+//	}
+func ValidateBatchInput(r io.Reader) error {
+	return jsonlFormats[PurposeBatch].validate(r, 0)
+}
+
+// SetSkipValidation sets whether to skip the checks of the file against its
+// purpose: extension, size and, for JSONL purposes, the format of the
+// lines.
+//
+// Example:
+//
+//	req.SetSkipValidation(true)
+func (r *FileUploadRequest) SetSkipValidation(skip bool) *FileUploadRequest {
+	r.SkipValidation = skip
+	return r
+}
+
+// SetMaxFileSize overrides the maximum upload size in bytes.
+//
+// Example:
+//
+//	req.SetMaxFileSize(200 << 20)
+func (r *FileUploadRequest) SetMaxFileSize(size int64) *FileUploadRequest {
+	r.MaxFileSize = size
+	return r
+}
+
+// SetScanLines sets the number of lines of a JSONL file checked before
+// upload. Zero uses DefaultScanLines; a negative number checks every line.
+//
+// Example:
+//
+//	req.SetScanLines(-1)
+func (r *FileUploadRequest) SetScanLines(n int) *FileUploadRequest {
+	r.ScanLines = n
+	return r
+}
+
+// Validate checks that a file is set and, unless SkipValidation is set,
+// that it matches its purpose: the extension of Filename must be accepted,
+// and the first lines of a JSONL file must be JSON objects with the keys
+// the purpose requires. The lines are read from File and put back, so the
+// whole file is still uploaded. Purposes unknown to the SDK are left to
+// the API.
+func (r *FileUploadRequest) Validate() error {
+	if r.File == nil {
+		return errors.NewValidationError("file", "is required", nil)
+	}
+	if r.SkipValidation {
+		return nil
+	}
+
+	limits, ok := purposeLimits[r.Purpose]
+	if !ok {
+		return nil
+	}
+	if ext := extension(r.Filename); !slices.Contains(limits.Extensions, ext) {
+		return errors.NewValidationError("filename",
+			fmt.Sprintf("must have one of the extensions %s for purpose %q", strings.Join(limits.Extensions, ", "), r.Purpose), r.Filename)
+	}
+
+	format, ok := jsonlFormats[r.Purpose]
+	if !ok {
+		return nil
+	}
+	lines := r.ScanLines
+	if lines == 0 {
+		lines = DefaultScanLines
+	}
+	return r.scan(func(file io.Reader) error {
+		return format.validate(file, lines)
+	})
+}
+
+// FileSizeLimit returns the maximum upload size in bytes: MaxFileSize if
+// set, otherwise the documented limit of the purpose, or zero if unknown
+// or SkipValidation is set.
+func (r *FileUploadRequest) FileSizeLimit() int64 {
+	if r.MaxFileSize > 0 {
+		return r.MaxFileSize
+	}
+	if r.SkipValidation {
+		return 0
+	}
+	return purposeLimits[r.Purpose].MaxFileSize
+}
+
+// NewFileTooLargeError creates the validation error returned for files
+// larger than limit bytes.
+func NewFileTooLargeError(size, limit int64) *errors.ValidationError {
+	return errors.NewValidationError("file",
+		fmt.Sprintf("exceeds the maximum size of %d bytes", limit), size)
+}
+
+// reopener is implemented by readers that can produce their content again
+// from the start, such as those of zai.NewReplayableReader.
+type reopener interface {
+	Reopen() (io.ReadCloser, error)
+}
+
+// scan calls check with the content of File without consuming it: a
+// reopenable file is read from a new reader, a seekable file is rewound
+// and other files are replaced by the bytes read followed by the rest.
+func (r *FileUploadRequest) scan(check func(io.Reader) error) error {
+	switch file := r.File.(type) {
+	case reopener:
+		content, err := file.Reopen()
+		if err != nil {
+			return fmt.Errorf("files: failed to open file: %w", err)
+		}
+		defer content.Close()
+		return check(content)
+
+	case io.ReadSeeker:
+		start, err := file.Seek(0, io.SeekCurrent)
+		if err == nil {
+			checkErr := check(file)
+			if _, err := file.Seek(start, io.SeekStart); err != nil {
+				return fmt.Errorf("files: failed to rewind file: %w", err)
+			}
+			return checkErr
+		}
+	}
+
+	var read bytes.Buffer
+	err := check(io.TeeReader(r.File, &read))
+	r.File = io.MultiReader(&read, r.File)
+	return err
+}
+
+// validate checks the first maxLines lines of r, or every line if maxLines
+// is not positive. Blank lines are skipped.
+func (f jsonlFormat) validate(r io.Reader, maxLines int) error {
+	br := bufio.NewReader(r)
+	seen := make(map[string]int)
+	lines := 0
+	for n := 1; maxLines <= 0 || n <= maxLines; n++ {
+		data, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			lines++
+			if err := f.validateLine(n, data, seen); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("files: failed to read line %d: %w", n, err)
+		}
+	}
+
+	if lines == 0 {
+		return errors.NewValidationError("file", "contains no JSON lines", nil)
+	}
+	return nil
+}
+
+// validateLine checks line n, recording its unique key value in seen.
+func (f jsonlFormat) validateLine(n int, data []byte, seen map[string]int) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return errors.NewValidationError("file", fmt.Sprintf("line %d is not a valid JSON object: %v", n, err), n)
+	}
+
+	for _, key := range f.keys {
+		if _, ok := fields[key]; !ok {
+			return errors.NewValidationError("file", fmt.Sprintf("line %d is missing %q", n, key), n)
+		}
+	}
+
+	if f.uniqueKey == "" {
+		return nil
+	}
+	var value string
+	if err := json.Unmarshal(fields[f.uniqueKey], &value); err != nil || value == "" {
+		return errors.NewValidationError("file", fmt.Sprintf("line %d has no %q string", n, f.uniqueKey), n)
+	}
+	if first, ok := seen[value]; ok {
+		return errors.NewValidationError("file",
+			fmt.Sprintf("line %d repeats the %s %q of line %d", n, f.uniqueKey, value, first), n)
+	}
+	seen[value] = n
+	return nil
+}
+
+// extension returns the lowercase extension of name, without the dot.
+func extension(name string) string {
+	return strings.ToLower(strings.TrimPrefix(path.Ext(name), "."))
+}
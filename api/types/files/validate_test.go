@@ -0,0 +1,210 @@
+package files
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// onlyReader hides every method of r except Read.
+type onlyReader struct {
+	r io.Reader
+}
+
+func (o onlyReader) Read(p []byte) (int, error) {
+	return o.r.Read(p)
+}
+
+// reopenable is a reader that can be reopened, counting the reopens.
+type reopenable struct {
+	io.Reader
+	data    []byte
+	reopens int
+}
+
+func (r *reopenable) Reopen() (io.ReadCloser, error) {
+	r.reopens++
+	return io.NopCloser(bytes.NewReader(r.data)), nil
+}
+
+func TestValidateBatchInput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		golden  string
+		wantErr string
+		line    int
+	}{
+		{"batch_valid.jsonl", "", 0},
+		{"batch_invalid_json.jsonl", "line 2 is not a valid JSON object", 2},
+		{"batch_missing_key.jsonl", `line 3 is missing "custom_id"`, 3},
+		{"batch_duplicate_id.jsonl", `line 3 repeats the custom_id "req-1" of line 1`, 3},
+		{"finetune_valid.jsonl", `line 1 is missing "custom_id"`, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateBatchInput(bytes.NewReader(golden.Read(t, tt.golden)))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, "file", validationErr.Field)
+			assert.Contains(t, validationErr.Message, tt.wantErr)
+			assert.Equal(t, tt.line, validationErr.Value)
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		err := ValidateBatchInput(strings.NewReader("\n\n"))
+		assert.True(t, errors.IsValidationError(err))
+		assert.Contains(t, err.Error(), "no JSON lines")
+	})
+
+	t.Run("empty custom ID", func(t *testing.T) {
+		t.Parallel()
+
+		err := ValidateBatchInput(strings.NewReader(`{"custom_id":"","method":"POST","url":"/v4/embeddings","body":{}}`))
+		assert.Contains(t, err.Error(), `line 1 has no "custom_id" string`)
+	})
+}
+
+func TestFileUploadRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		golden   string
+		filename string
+		purpose  FilePurpose
+		wantErr  string
+	}{
+		{"valid batch", "batch_valid.jsonl", "input.jsonl", PurposeBatch, ""},
+		{"broken batch", "batch_missing_key.jsonl", "input.jsonl", PurposeBatch, `line 3 is missing "custom_id"`},
+		{"valid fine-tune", "finetune_valid.jsonl", "train.jsonl", PurposeFineTune, ""},
+		{"broken fine-tune", "finetune_missing_messages.jsonl", "train.jsonl", PurposeFineTune, `line 2 is missing "messages"`},
+		{"batch extension", "batch_valid.jsonl", "input.json", PurposeBatch, "must have one of the extensions jsonl"},
+		{"vision extension", "batch_valid.jsonl", "photo.pdf", PurposeVision, "for purpose \"vision\""},
+		{"uppercase extension", "batch_valid.jsonl", "INPUT.JSONL", PurposeBatch, ""},
+		{"unknown purpose", "batch_invalid_json.jsonl", "input.bin", FilePurpose("code-interpreter"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := NewFileUploadRequest(bytes.NewReader(golden.Read(t, tt.golden)), tt.filename, tt.purpose)
+			err := req.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.IsValidationError(err), "got %v", err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+
+			// Skipping the validation leaves the file to the API
+			assert.NoError(t, req.SetSkipValidation(true).Validate())
+		})
+	}
+
+	t.Run("no file", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewFileUploadRequest(nil, "input.jsonl", PurposeBatch).Validate()
+		assert.True(t, errors.IsValidationError(err))
+	})
+
+	t.Run("scan lines", func(t *testing.T) {
+		t.Parallel()
+
+		data := golden.Read(t, "batch_missing_key.jsonl")
+		req := NewFileUploadRequest(bytes.NewReader(data), "input.jsonl", PurposeBatch).SetScanLines(2)
+		assert.NoError(t, req.Validate(), "the broken line is past the scanned lines")
+		assert.Error(t, req.SetScanLines(-1).Validate())
+	})
+}
+
+func TestFileUploadRequest_ValidateKeepsContent(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	for i := range 3 * DefaultScanLines {
+		fmt.Fprintf(&b, `{"custom_id":"req-%d","method":"POST","url":"/v4/embeddings","body":{"input":"text"}}`+"\n", i)
+	}
+	content := b.String()
+
+	t.Run("seeker", func(t *testing.T) {
+		t.Parallel()
+
+		file := strings.NewReader("header;" + content)
+		file.Seek(int64(len("header;")), io.SeekStart)
+		req := NewFileUploadRequest(file, "input.jsonl", PurposeBatch)
+		require.NoError(t, req.Validate())
+
+		assert.Same(t, file, req.File, "a seekable file is rewound, not replaced")
+		data, err := io.ReadAll(req.File)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewFileUploadRequest(onlyReader{strings.NewReader(content)}, "input.jsonl", PurposeBatch)
+		require.NoError(t, req.Validate())
+
+		data, err := io.ReadAll(req.File)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+
+	t.Run("reopener", func(t *testing.T) {
+		t.Parallel()
+
+		file := &reopenable{Reader: strings.NewReader(content), data: []byte(content)}
+		req := NewFileUploadRequest(file, "input.jsonl", PurposeBatch)
+		require.NoError(t, req.Validate())
+
+		assert.Same(t, file, req.File)
+		assert.Equal(t, 1, file.reopens)
+		data, err := io.ReadAll(req.File)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	})
+}
+
+func TestLimitsFor(t *testing.T) {
+	t.Parallel()
+
+	limits, ok := LimitsFor(PurposeBatch)
+	require.True(t, ok)
+	assert.True(t, limits.JSONL)
+	assert.Equal(t, []string{"jsonl"}, limits.Extensions)
+
+	limits, ok = LimitsFor(PurposeVision)
+	require.True(t, ok)
+	assert.False(t, limits.JSONL)
+	assert.Contains(t, limits.Extensions, "png")
+
+	_, ok = LimitsFor(FilePurpose("code-interpreter"))
+	assert.False(t, ok)
+
+	req := NewFileUploadRequest(strings.NewReader(""), "a.png", PurposeVision)
+	assert.Equal(t, 5*int64(megabyte), req.FileSizeLimit())
+	assert.Equal(t, int64(1024), req.SetMaxFileSize(1024).FileSizeLimit())
+	assert.Zero(t, NewFileUploadRequest(nil, "a.png", PurposeVision).SetSkipValidation(true).FileSizeLimit())
+}
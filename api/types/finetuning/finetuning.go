@@ -0,0 +1,263 @@
+// Package finetuning provides types for the Fine-tuning API.
+package finetuning
+
+// Fine-tuning job status constants
+const (
+	StatusValidatingFiles = "validating_files"
+	StatusQueued          = "queued"
+	StatusRunning         = "running"
+	StatusSucceeded       = "succeeded"
+	StatusFailed          = "failed"
+	StatusCancelled       = "cancelled"
+)
+
+// Hyperparameters controls the fine-tuning training run.
+// Each field accepts either a number or the string "auto".
+type Hyperparameters struct {
+	// NEpochs is the number of epochs to train for (int or "auto").
+	NEpochs interface{} `json:"n_epochs,omitempty"`
+
+	// LearningRateMultiplier scales the base learning rate (float64 or "auto").
+	LearningRateMultiplier interface{} `json:"learning_rate_multiplier,omitempty"`
+
+	// BatchSize is the number of examples in each batch (int or "auto").
+	BatchSize interface{} `json:"batch_size,omitempty"`
+}
+
+// JobError describes why a fine-tuning job failed.
+type JobError struct {
+	// Code is the error code
+	Code string `json:"code,omitempty"`
+
+	// Message is the error message
+	Message string `json:"message,omitempty"`
+
+	// Param is the parameter that caused the error
+	Param string `json:"param,omitempty"`
+}
+
+// Job represents a fine-tuning job.
+type Job struct {
+	// ID is the job identifier
+	ID string `json:"id"`
+
+	// Object is the type identifier, always "fine_tuning.job"
+	Object string `json:"object,omitempty"`
+
+	// Model is the base model being fine-tuned
+	Model string `json:"model"`
+
+	// CreatedAt is the creation time represented by the Unix timestamp (in seconds)
+	CreatedAt int64 `json:"created_at"`
+
+	// FinishedAt is the finish time represented by the Unix timestamp (in seconds)
+	FinishedAt *int64 `json:"finished_at,omitempty"`
+
+	// EstimatedFinish is the estimated finish time represented by the Unix timestamp (in seconds)
+	EstimatedFinish *int64 `json:"estimated_finish,omitempty"`
+
+	// FineTunedModel is the name of the resulting model, set once the job succeeds
+	FineTunedModel string `json:"fine_tuned_model,omitempty"`
+
+	// Status is the status of the job
+	Status string `json:"status"`
+
+	// TrainingFile is the ID of the training file
+	TrainingFile string `json:"training_file"`
+
+	// ValidationFile is the ID of the validation file, if any
+	ValidationFile string `json:"validation_file,omitempty"`
+
+	// Hyperparameters are the hyperparameters used for the job
+	Hyperparameters *Hyperparameters `json:"hyperparameters,omitempty"`
+
+	// ResultFiles are the IDs of the result files
+	ResultFiles []string `json:"result_files,omitempty"`
+
+	// TrainedTokens is the number of billable tokens processed
+	TrainedTokens int `json:"trained_tokens,omitempty"`
+
+	// Error is set when the job failed
+	Error *JobError `json:"error,omitempty"`
+}
+
+// IsRunning returns true if the job has not reached a terminal state yet
+// (validating files, queued, or running).
+func (j *Job) IsRunning() bool {
+	return j.Status == StatusValidatingFiles || j.Status == StatusQueued || j.Status == StatusRunning
+}
+
+// IsSucceeded returns true if the job has completed successfully.
+func (j *Job) IsSucceeded() bool {
+	return j.Status == StatusSucceeded
+}
+
+// IsFailed returns true if the job has failed.
+func (j *Job) IsFailed() bool {
+	return j.Status == StatusFailed
+}
+
+// IsCancelled returns true if the job has been cancelled.
+func (j *Job) IsCancelled() bool {
+	return j.Status == StatusCancelled
+}
+
+// IsTerminal returns true if the job is in a terminal state (succeeded, failed, or cancelled).
+func (j *Job) IsTerminal() bool {
+	return j.IsSucceeded() || j.IsFailed() || j.IsCancelled()
+}
+
+// JobCreateRequest represents a request to create a fine-tuning job.
+type JobCreateRequest struct {
+	// Model is the base model to fine-tune (required)
+	Model string `json:"model"`
+
+	// TrainingFile is the ID of an uploaded file with purpose "fine-tune" (required)
+	TrainingFile string `json:"training_file"`
+
+	// ValidationFile is the ID of an uploaded validation file
+	ValidationFile string `json:"validation_file,omitempty"`
+
+	// Hyperparameters are the training hyperparameters
+	Hyperparameters *Hyperparameters `json:"hyperparameters,omitempty"`
+
+	// Suffix is appended to the fine-tuned model name (up to 64 characters)
+	Suffix string `json:"suffix,omitempty"`
+
+	// RequestID is a unique identifier for the request
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// NewJobCreateRequest creates a new fine-tuning job request.
+//
+// Example:
+//
+//	req := finetuning.NewJobCreateRequest("glm-4-flash", "file_abc123").
+//	    SetEpochs(3).
+//	    SetSuffix("support-bot")
+func NewJobCreateRequest(model, trainingFile string) *JobCreateRequest {
+	return &JobCreateRequest{
+		Model:        model,
+		TrainingFile: trainingFile,
+	}
+}
+
+// SetValidationFile sets the validation file ID.
+func (r *JobCreateRequest) SetValidationFile(fileID string) *JobCreateRequest {
+	r.ValidationFile = fileID
+	return r
+}
+
+// SetEpochs sets the number of training epochs.
+func (r *JobCreateRequest) SetEpochs(epochs int) *JobCreateRequest {
+	r.hyperparameters().NEpochs = epochs
+	return r
+}
+
+// SetLearningRateMultiplier sets the learning rate multiplier.
+func (r *JobCreateRequest) SetLearningRateMultiplier(multiplier float64) *JobCreateRequest {
+	r.hyperparameters().LearningRateMultiplier = multiplier
+	return r
+}
+
+// SetBatchSize sets the training batch size.
+func (r *JobCreateRequest) SetBatchSize(batchSize int) *JobCreateRequest {
+	r.hyperparameters().BatchSize = batchSize
+	return r
+}
+
+// SetSuffix sets the suffix appended to the fine-tuned model name.
+func (r *JobCreateRequest) SetSuffix(suffix string) *JobCreateRequest {
+	r.Suffix = suffix
+	return r
+}
+
+// SetRequestID sets the request ID.
+func (r *JobCreateRequest) SetRequestID(requestID string) *JobCreateRequest {
+	r.RequestID = requestID
+	return r
+}
+
+// hyperparameters returns the request hyperparameters, creating them if needed.
+func (r *JobCreateRequest) hyperparameters() *Hyperparameters {
+	if r.Hyperparameters == nil {
+		r.Hyperparameters = &Hyperparameters{}
+	}
+	return r.Hyperparameters
+}
+
+// JobListResponse represents the response from listing fine-tuning jobs.
+type JobListResponse struct {
+	// Data is the list of jobs
+	Data []Job `json:"data"`
+
+	// Object is the type identifier, always "list"
+	Object string `json:"object"`
+
+	// HasMore indicates whether there are more jobs available
+	HasMore bool `json:"has_more"`
+}
+
+// GetJobs returns the list of jobs.
+func (r *JobListResponse) GetJobs() []Job {
+	return r.Data
+}
+
+// LastID returns the ID of the last job in the list, for use as the next
+// page cursor, or an empty string if the list is empty.
+func (r *JobListResponse) LastID() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+	return r.Data[len(r.Data)-1].ID
+}
+
+// Event represents a fine-tuning job event, such as a training progress update.
+type Event struct {
+	// ID is the event identifier
+	ID string `json:"id"`
+
+	// Object is the type identifier, always "fine_tuning.job.event"
+	Object string `json:"object,omitempty"`
+
+	// CreatedAt is the creation time represented by the Unix timestamp (in seconds)
+	CreatedAt int64 `json:"created_at"`
+
+	// Level is the event level ("info", "warn", or "error")
+	Level string `json:"level,omitempty"`
+
+	// Message is the event message
+	Message string `json:"message"`
+
+	// Type is the event type ("message" or "metrics")
+	Type string `json:"type,omitempty"`
+
+	// Data contains event-specific data such as training metrics
+	Data map[string]interface{} `json:"data,omitempty"`
+}
+
+// EventListResponse represents the response from listing job events.
+type EventListResponse struct {
+	// Data is the list of events
+	Data []Event `json:"data"`
+
+	// Object is the type identifier, always "list"
+	Object string `json:"object"`
+
+	// HasMore indicates whether there are more events available
+	HasMore bool `json:"has_more"`
+}
+
+// GetEvents returns the list of events.
+func (r *EventListResponse) GetEvents() []Event {
+	return r.Data
+}
+
+// LastID returns the ID of the last event in the list, for use as the next
+// page cursor, or an empty string if the list is empty.
+func (r *EventListResponse) LastID() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+	return r.Data[len(r.Data)-1].ID
+}
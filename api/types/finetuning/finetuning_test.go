@@ -0,0 +1,126 @@
+package finetuning
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJob_StatusMethods(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status    string
+		running   bool
+		succeeded bool
+		failed    bool
+		cancelled bool
+		terminal  bool
+	}{
+		{StatusValidatingFiles, true, false, false, false, false},
+		{StatusQueued, true, false, false, false, false},
+		{StatusRunning, true, false, false, false, false},
+		{StatusSucceeded, false, true, false, false, true},
+		{StatusFailed, false, false, true, false, true},
+		{StatusCancelled, false, false, false, true, true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.status, func(t *testing.T) {
+			t.Parallel()
+
+			job := &Job{Status: tt.status}
+			assert.Equal(t, tt.running, job.IsRunning())
+			assert.Equal(t, tt.succeeded, job.IsSucceeded())
+			assert.Equal(t, tt.failed, job.IsFailed())
+			assert.Equal(t, tt.cancelled, job.IsCancelled())
+			assert.Equal(t, tt.terminal, job.IsTerminal())
+		})
+	}
+}
+
+func TestJobCreateRequest(t *testing.T) {
+	t.Parallel()
+
+	t.Run("minimal request omits optional fields", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(NewJobCreateRequest("glm-4-flash", "file_123"))
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"model":"glm-4-flash","training_file":"file_123"}`, string(data))
+	})
+
+	t.Run("setters", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewJobCreateRequest("glm-4-flash", "file_123").
+			SetValidationFile("file_456").
+			SetEpochs(3).
+			SetLearningRateMultiplier(0.5).
+			SetBatchSize(8).
+			SetSuffix("support").
+			SetRequestID("req-1")
+
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{
+			"model": "glm-4-flash",
+			"training_file": "file_123",
+			"validation_file": "file_456",
+			"hyperparameters": {"n_epochs": 3, "learning_rate_multiplier": 0.5, "batch_size": 8},
+			"suffix": "support",
+			"request_id": "req-1"
+		}`, string(data))
+	})
+}
+
+func TestJob_JSON(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+		"id": "ftjob-1",
+		"object": "fine_tuning.job",
+		"model": "glm-4-flash",
+		"created_at": 1700000000,
+		"finished_at": 1700003600,
+		"fine_tuned_model": "glm-4-flash:ft:support",
+		"status": "succeeded",
+		"training_file": "file_123",
+		"hyperparameters": {"n_epochs": "auto", "batch_size": 8},
+		"result_files": ["file_789"],
+		"trained_tokens": 12000
+	}`
+
+	var job Job
+	require.NoError(t, json.Unmarshal([]byte(data), &job))
+
+	assert.Equal(t, "ftjob-1", job.ID)
+	assert.True(t, job.IsSucceeded())
+	assert.Equal(t, "glm-4-flash:ft:support", job.FineTunedModel)
+	require.NotNil(t, job.FinishedAt)
+	assert.Equal(t, int64(1700003600), *job.FinishedAt)
+	require.NotNil(t, job.Hyperparameters)
+	assert.Equal(t, "auto", job.Hyperparameters.NEpochs)
+	assert.Equal(t, float64(8), job.Hyperparameters.BatchSize)
+	assert.Equal(t, []string{"file_789"}, job.ResultFiles)
+	assert.Equal(t, 12000, job.TrainedTokens)
+}
+
+func TestListResponses_LastID(t *testing.T) {
+	t.Parallel()
+
+	jobs := &JobListResponse{Data: []Job{{ID: "a"}, {ID: "b"}}}
+	assert.Equal(t, "b", jobs.LastID())
+	assert.Len(t, jobs.GetJobs(), 2)
+	assert.Empty(t, (&JobListResponse{}).LastID())
+
+	events := &EventListResponse{Data: []Event{{ID: "e1"}}}
+	assert.Equal(t, "e1", events.LastID())
+	assert.Len(t, events.GetEvents(), 1)
+	assert.Empty(t, (&EventListResponse{}).LastID())
+}
@@ -0,0 +1,159 @@
+package images
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Doer sends HTTP requests. *http.Client satisfies this interface.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Download returns the image bytes, fetching the URL with client or
+// decoding the base64 data, whichever the response contains.
+// If client is nil, http.DefaultClient is used.
+//
+// Example:
+//
+//	data, err := resp.GetFirstImage().Download(ctx, nil)
+func (i *ImageData) Download(ctx context.Context, client Doer) ([]byte, error) {
+	data, _, err := i.fetch(ctx, client)
+	return data, err
+}
+
+// SaveTo downloads the image and writes it to path.
+// If path has no extension, one is inferred from the Content-Type or the
+// image bytes and appended.
+//
+// Example:
+//
+//	// Writes sunset.png (or .jpg, .webp, ...)
+//	err := resp.GetFirstImage().SaveTo(ctx, "sunset")
+func (i *ImageData) SaveTo(ctx context.Context, path string) error {
+	_, err := i.SaveToWithClient(ctx, nil, path)
+	return err
+}
+
+// SaveToWithClient is like SaveTo but fetches URLs with client and returns
+// the path that was written.
+func (i *ImageData) SaveToWithClient(ctx context.Context, client Doer, path string) (string, error) {
+	data, contentType, err := i.fetch(ctx, client)
+	if err != nil {
+		return "", err
+	}
+
+	if filepath.Ext(path) == "" {
+		path += DetectExtension(data, contentType)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write image: %w", err)
+	}
+
+	return path, nil
+}
+
+// fetch returns the image bytes and the Content-Type reported by the server.
+func (i *ImageData) fetch(ctx context.Context, client Doer) ([]byte, string, error) {
+	if i.B64JSON != "" {
+		data, err := decodeBase64Image(i.B64JSON)
+		return data, "", err
+	}
+
+	if i.URL == "" {
+		return nil, "", fmt.Errorf("image has neither url nor b64_json")
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, i.URL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create download request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("failed to download image: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image: %w", err)
+	}
+
+	return data, resp.Header.Get("Content-Type"), nil
+}
+
+// decodeBase64Image decodes base64 image data, accepting an optional
+// "data:image/...;base64," prefix.
+func decodeBase64Image(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "data:") {
+		if idx := strings.Index(s, ","); idx >= 0 {
+			s = s[idx+1:]
+		}
+	}
+
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	return data, nil
+}
+
+// DetectExtension returns the file extension (including the dot) for an
+// image, preferring contentType and falling back to the magic bytes of data.
+// It returns ".png" if the format cannot be determined.
+func DetectExtension(data []byte, contentType string) string {
+	if ext := extensionForContentType(contentType); ext != "" {
+		return ext
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return ".png"
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return ".jpg"
+	case bytes.HasPrefix(data, []byte("GIF87a")), bytes.HasPrefix(data, []byte("GIF89a")):
+		return ".gif"
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return ".webp"
+	}
+
+	return ".png"
+}
+
+// extensionForContentType maps an image MIME type to a file extension.
+func extensionForContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	switch mediaType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/gif":
+		return ".gif"
+	case "image/webp":
+		return ".webp"
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package images
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPNG is a minimal PNG header followed by arbitrary bytes.
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDRtest-image")
+
+func newPNGServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(testPNG)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestImageData_Download(t *testing.T) {
+	t.Parallel()
+
+	server := newPNGServer(t)
+
+	t.Run("URL and base64 produce identical bytes", func(t *testing.T) {
+		t.Parallel()
+
+		fromURL, err := (&ImageData{URL: server.URL + "/image.png"}).Download(context.Background(), server.Client())
+		require.NoError(t, err)
+
+		fromB64, err := (&ImageData{B64JSON: base64.StdEncoding.EncodeToString(testPNG)}).Download(context.Background(), nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, testPNG, fromURL)
+		assert.Equal(t, fromURL, fromB64)
+	})
+
+	t.Run("data URI prefix", func(t *testing.T) {
+		t.Parallel()
+
+		img := &ImageData{B64JSON: "data:image/png;base64," + base64.StdEncoding.EncodeToString(testPNG)}
+		data, err := img.Download(context.Background(), nil)
+		require.NoError(t, err)
+		assert.Equal(t, testPNG, data)
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		t.Parallel()
+
+		notFound := httptest.NewServer(http.NotFoundHandler())
+		defer notFound.Close()
+
+		_, err := (&ImageData{URL: notFound.URL}).Download(context.Background(), nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := (&ImageData{URL: server.URL}).Download(ctx, nil)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("empty image", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := (&ImageData{}).Download(context.Background(), nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestImageData_SaveTo(t *testing.T) {
+	t.Parallel()
+
+	server := newPNGServer(t)
+
+	t.Run("infers extension", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "image")
+		err := (&ImageData{URL: server.URL}).SaveTo(context.Background(), path)
+		require.NoError(t, err)
+
+		data, err := os.ReadFile(path + ".png")
+		require.NoError(t, err)
+		assert.Equal(t, testPNG, data)
+	})
+
+	t.Run("keeps explicit extension", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "image.bin")
+		img := &ImageData{B64JSON: base64.StdEncoding.EncodeToString(testPNG)}
+		written, err := img.SaveToWithClient(context.Background(), nil, path)
+		require.NoError(t, err)
+		assert.Equal(t, path, written)
+		assert.FileExists(t, path)
+	})
+}
+
+func TestDetectExtension(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+	}{
+		{"content type wins", testPNG, "image/jpeg", ".jpg"},
+		{"content type with params", nil, "image/webp; charset=binary", ".webp"},
+		{"png magic", testPNG, "application/octet-stream", ".png"},
+		{"jpeg magic", []byte("\xff\xd8\xff\xe0rest"), "", ".jpg"},
+		{"gif magic", []byte("GIF89a..."), "", ".gif"},
+		{"webp magic", []byte("RIFF\x00\x00\x00\x00WEBPVP8 "), "", ".webp"},
+		{"unknown", []byte("????"), "", ".png"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, DetectExtension(tt.data, tt.contentType))
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package images
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// ImageSource is an image sent with an edit or variation request. It is
+// either uploaded from Reader or referenced by URL.
+type ImageSource struct {
+	// Reader is the image file to upload.
+	Reader io.Reader
+
+	// Filename is the name of the uploaded file.
+	Filename string
+
+	// URL is the URL of the image, used instead of uploading a file.
+	URL string
+
+	// err records invalid base64 data passed to ImageFromBase64.
+	err error
+}
+
+// ImageFromReader returns an image uploaded from r as filename.
+//
+// Example:
+//
+//	file, err := os.Open("room.png")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer file.Close()
+//
+//	image := images.ImageFromReader(file, "room.png")
+func ImageFromReader(r io.Reader, filename string) *ImageSource {
+	return &ImageSource{Reader: r, Filename: filename}
+}
+
+// ImageFromBytes returns an image uploaded from data as filename.
+func ImageFromBytes(data []byte, filename string) *ImageSource {
+	return ImageFromReader(bytes.NewReader(data), filename)
+}
+
+// ImageFromURL returns an image referenced by url.
+//
+// Example:
+//
+//	image := images.ImageFromURL("https://bucket.example.com/room.png")
+func ImageFromURL(url string) *ImageSource {
+	return &ImageSource{URL: url}
+}
+
+// ImageFromBase64 returns an image uploaded from base64 data, such as
+// ImageData.B64JSON of a previous generation, as filename. A data URL
+// prefix ("data:image/png;base64,") is accepted. Invalid data is reported
+// by the request's Validate.
+func ImageFromBase64(data, filename string) *ImageSource {
+	if i := strings.Index(data, ";base64,"); i >= 0 && strings.HasPrefix(data, "data:") {
+		data = data[i+len(";base64,"):]
+	}
+	decoded, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return &ImageSource{Filename: filename, err: fmt.Errorf("invalid base64 data: %w", err)}
+	}
+	return ImageFromBytes(decoded, filename)
+}
+
+// validate checks that the source has exactly one of a file and a URL.
+func (s *ImageSource) validate(field string) error {
+	if s.err != nil {
+		return errors.NewValidationError(field, s.err.Error(), nil)
+	}
+	if s.Reader == nil && s.URL == "" {
+		return errors.NewValidationError(field, "either a file or a URL is required", nil)
+	}
+	if s.Reader != nil && s.URL != "" {
+		return errors.NewValidationError(field, "must not have both a file and a URL", s.URL)
+	}
+	if s.Reader != nil && s.Filename == "" {
+		return errors.NewValidationError(field, "file name is required with a file", nil)
+	}
+	return nil
+}
+
+// ImageEditRequest represents a request to edit an image from a prompt.
+type ImageEditRequest struct {
+	// Model is the model to use for image editing (required).
+	Model string
+
+	// Image is the image to edit (required).
+	Image *ImageSource
+
+	// Mask marks the area of Image to edit. It must have the same
+	// dimensions as Image; fully transparent pixels are edited and the
+	// rest of the image is kept. Without a mask the model may change the
+	// whole image. The dimensions are not checked client-side.
+	Mask *ImageSource
+
+	// Prompt is the text description of the desired edit (required).
+	Prompt string
+
+	// Size is the size of the edited images.
+	Size ImageSize
+
+	// N is the number of images to generate.
+	N *int
+
+	// ResponseFormat is the format in which the edited images are returned.
+	ResponseFormat ResponseFormat
+
+	// UserID is a unique identifier for the end-user.
+	UserID string
+}
+
+// NewImageEditRequest creates a new image edit request with required fields.
+//
+// Example:
+//
+//	req := images.NewImageEditRequest(images.ModelCogView4, "Add a red sofa",
+//	    images.ImageFromReader(file, "room.png"))
+func NewImageEditRequest(model, prompt string, image *ImageSource) *ImageEditRequest {
+	return &ImageEditRequest{
+		Model:  model,
+		Prompt: prompt,
+		Image:  image,
+	}
+}
+
+// SetMask sets the mask marking the area of the image to edit.
+//
+// Example:
+//
+//	req.SetMask(images.ImageFromReader(maskFile, "mask.png"))
+func (r *ImageEditRequest) SetMask(mask *ImageSource) *ImageEditRequest {
+	r.Mask = mask
+	return r
+}
+
+// SetSize sets the size of the edited images.
+func (r *ImageEditRequest) SetSize(size ImageSize) *ImageEditRequest {
+	r.Size = size
+	return r
+}
+
+// SetN sets the number of images to generate.
+func (r *ImageEditRequest) SetN(n int) *ImageEditRequest {
+	r.N = &n
+	return r
+}
+
+// SetResponseFormat sets the format of the image data in the response.
+func (r *ImageEditRequest) SetResponseFormat(format ResponseFormat) *ImageEditRequest {
+	r.ResponseFormat = format
+	return r
+}
+
+// SetUserID sets the end-user identifier.
+func (r *ImageEditRequest) SetUserID(userID string) *ImageEditRequest {
+	r.UserID = userID
+	return r
+}
+
+// Validate checks the required fields, and that a mask is only sent with
+// an image to apply it to.
+func (r *ImageEditRequest) Validate() error {
+	if r.Model == "" {
+		return errors.NewValidationError("model", "is required", nil)
+	}
+	if r.Prompt == "" {
+		return errors.NewValidationError("prompt", "is required", nil)
+	}
+	if r.Image == nil {
+		if r.Mask != nil {
+			return errors.NewValidationError("mask", "requires an image", nil)
+		}
+		return errors.NewValidationError("image", "is required", nil)
+	}
+	if err := r.Image.validate("image"); err != nil {
+		return err
+	}
+	if r.Mask != nil {
+		if err := r.Mask.validate("mask"); err != nil {
+			return err
+		}
+	}
+	return validateN(r.N)
+}
+
+// ImageVariationRequest represents a request to generate variations of an
+// image.
+type ImageVariationRequest struct {
+	// Model is the model to use (required).
+	Model string
+
+	// Image is the image to vary (required).
+	Image *ImageSource
+
+	// Size is the size of the generated images.
+	Size ImageSize
+
+	// N is the number of variations to generate.
+	N *int
+
+	// ResponseFormat is the format in which the images are returned.
+	ResponseFormat ResponseFormat
+
+	// UserID is a unique identifier for the end-user.
+	UserID string
+}
+
+// NewImageVariationRequest creates a new image variation request with
+// required fields.
+//
+// Example:
+//
+//	req := images.NewImageVariationRequest(images.ModelCogView4,
+//	    images.ImageFromURL("https://bucket.example.com/logo.png")).SetN(3)
+func NewImageVariationRequest(model string, image *ImageSource) *ImageVariationRequest {
+	return &ImageVariationRequest{
+		Model: model,
+		Image: image,
+	}
+}
+
+// SetSize sets the size of the generated images.
+func (r *ImageVariationRequest) SetSize(size ImageSize) *ImageVariationRequest {
+	r.Size = size
+	return r
+}
+
+// SetN sets the number of variations to generate.
+func (r *ImageVariationRequest) SetN(n int) *ImageVariationRequest {
+	r.N = &n
+	return r
+}
+
+// SetResponseFormat sets the format of the image data in the response.
+func (r *ImageVariationRequest) SetResponseFormat(format ResponseFormat) *ImageVariationRequest {
+	r.ResponseFormat = format
+	return r
+}
+
+// SetUserID sets the end-user identifier.
+func (r *ImageVariationRequest) SetUserID(userID string) *ImageVariationRequest {
+	r.UserID = userID
+	return r
+}
+
+// Validate checks the required fields.
+func (r *ImageVariationRequest) Validate() error {
+	if r.Model == "" {
+		return errors.NewValidationError("model", "is required", nil)
+	}
+	if r.Image == nil {
+		return errors.NewValidationError("image", "is required", nil)
+	}
+	if err := r.Image.validate("image"); err != nil {
+		return err
+	}
+	return validateN(r.N)
+}
+
+// validateN checks that the number of images is between 1 and 10.
+func validateN(n *int) error {
+	if n != nil && (*n < 1 || *n > 10) {
+		return errors.NewValidationError("n", "must be between 1 and 10", *n)
+	}
+	return nil
+}
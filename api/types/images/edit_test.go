@@ -0,0 +1,112 @@
+package images
+
+import (
+	"encoding/base64"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestImageFromBase64(t *testing.T) {
+	t.Parallel()
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("png bytes"))
+
+	for _, data := range []string{encoded, "data:image/png;base64," + encoded} {
+		source := ImageFromBase64(data, "image.png")
+		require.NoError(t, source.validate("image"))
+		content, err := io.ReadAll(source.Reader)
+		require.NoError(t, err)
+		assert.Equal(t, "png bytes", string(content))
+		assert.Equal(t, "image.png", source.Filename)
+	}
+
+	err := ImageFromBase64("not base64!", "image.png").validate("image")
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "image", validationErr.Field)
+}
+
+func TestImageEditRequest_Setters(t *testing.T) {
+	t.Parallel()
+
+	mask := ImageFromURL("https://example.com/mask.png")
+	req := NewImageEditRequest(ModelCogView4, "Add a red sofa", ImageFromURL("https://example.com/room.png")).
+		SetMask(mask).
+		SetSize(Size1024x1024).
+		SetN(2).
+		SetResponseFormat(ResponseFormatB64JSON).
+		SetUserID("user-123456")
+
+	assert.Equal(t, ModelCogView4, req.Model)
+	assert.Equal(t, "Add a red sofa", req.Prompt)
+	assert.Equal(t, "https://example.com/room.png", req.Image.URL)
+	assert.Same(t, mask, req.Mask)
+	assert.Equal(t, Size1024x1024, req.Size)
+	assert.Equal(t, 2, *req.N)
+	assert.Equal(t, ResponseFormatB64JSON, req.ResponseFormat)
+	assert.Equal(t, "user-123456", req.UserID)
+	assert.NoError(t, req.Validate())
+}
+
+func TestImageEditRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	image := func() *ImageSource { return ImageFromReader(strings.NewReader("png"), "room.png") }
+
+	tests := []struct {
+		name  string
+		req   *ImageEditRequest
+		field string
+	}{
+		{"missing model", NewImageEditRequest("", "edit", image()), "model"},
+		{"missing prompt", NewImageEditRequest(ModelCogView4, "", image()), "prompt"},
+		{"missing image", NewImageEditRequest(ModelCogView4, "edit", nil), "image"},
+		{"mask without image", NewImageEditRequest(ModelCogView4, "edit", nil).SetMask(image()), "mask"},
+		{"empty image", NewImageEditRequest(ModelCogView4, "edit", &ImageSource{}), "image"},
+		{"file and URL", NewImageEditRequest(ModelCogView4, "edit", &ImageSource{Reader: strings.NewReader("png"), Filename: "a.png", URL: "https://example.com/a.png"}), "image"},
+		{"file without name", NewImageEditRequest(ModelCogView4, "edit", ImageFromReader(strings.NewReader("png"), "")), "image"},
+		{"empty mask", NewImageEditRequest(ModelCogView4, "edit", image()).SetMask(&ImageSource{}), "mask"},
+		{"n out of range", NewImageEditRequest(ModelCogView4, "edit", image()).SetN(11), "n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, tt.req.Validate(), &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+
+	assert.NoError(t, NewImageEditRequest(ModelCogView4, "edit", image()).SetMask(image()).Validate())
+	assert.NoError(t, NewImageEditRequest(ModelCogView4, "edit", image()).Validate())
+}
+
+func TestImageVariationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	req := NewImageVariationRequest(ModelCogView4, ImageFromBytes([]byte("png"), "logo.png")).
+		SetSize(Size1344x768).
+		SetN(3).
+		SetResponseFormat(ResponseFormatURL).
+		SetUserID("user-123456")
+	assert.Equal(t, Size1344x768, req.Size)
+	assert.Equal(t, 3, *req.N)
+	assert.Equal(t, ResponseFormatURL, req.ResponseFormat)
+	assert.Equal(t, "user-123456", req.UserID)
+	assert.NoError(t, req.Validate())
+
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, NewImageVariationRequest("", ImageFromURL("https://example.com/a.png")).Validate(), &validationErr)
+	assert.Equal(t, "model", validationErr.Field)
+	require.ErrorAs(t, NewImageVariationRequest(ModelCogView4, nil).Validate(), &validationErr)
+	assert.Equal(t, "image", validationErr.Field)
+	require.ErrorAs(t, NewImageVariationRequest(ModelCogView4, ImageFromURL("https://example.com/a.png")).SetN(0).Validate(), &validationErr)
+	assert.Equal(t, "n", validationErr.Field)
+}
@@ -0,0 +1,50 @@
+package images
+
+import (
+	"encoding/json"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
+
+// SetExtraField sets a top-level request field the SDK has no typed field
+// for, such as a beta parameter. Typed fields take precedence.
+func (r *ImageGenerationRequest) SetExtraField(key string, value any) *ImageGenerationRequest {
+	if r.ExtraFields == nil {
+		r.ExtraFields = make(map[string]any)
+	}
+	r.ExtraFields[key] = value
+	return r
+}
+
+// MarshalJSON encodes the request with ExtraFields merged into the
+// top-level object.
+func (r ImageGenerationRequest) MarshalJSON() ([]byte, error) {
+	type alias ImageGenerationRequest
+	return models.MarshalWithExtra(alias(r), r.ExtraFields)
+}
+
+// Extra returns the response fields the SDK has no typed field for as a
+// JSON object, or nil if there are none.
+func (r *ImageGenerationResponse) Extra() json.RawMessage {
+	return r.extra
+}
+
+// UnmarshalJSON decodes the response and keeps unknown fields for Extra.
+func (r *ImageGenerationResponse) UnmarshalJSON(data []byte) error {
+	type alias ImageGenerationResponse
+	var known alias
+	extra, err := models.UnmarshalWithExtra(data, &known)
+	if err != nil {
+		return err
+	}
+
+	*r = ImageGenerationResponse(known)
+	r.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the response including the fields returned by Extra.
+func (r ImageGenerationResponse) MarshalJSON() ([]byte, error) {
+	type alias ImageGenerationResponse
+	return models.MarshalWithRawExtra(alias(r), r.extra)
+}
@@ -0,0 +1,37 @@
+package images
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageGenerationRequest_ExtraFields(t *testing.T) {
+	t.Parallel()
+
+	req := NewImageGenerationRequest("cogview-4", "a cat").
+		SetExtraField("beta_option", true).
+		SetExtraField("model", "ignored")
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "cogview-4", got["model"], "typed fields take precedence")
+	assert.Equal(t, true, got["beta_option"])
+}
+
+func TestImageGenerationResponse_Extra(t *testing.T) {
+	t.Parallel()
+
+	var resp ImageGenerationResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"created":1,"data":[],"id":"img-1"}`), &resp))
+	assert.JSONEq(t, `{"id":"img-1"}`, string(resp.Extra()))
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created":1,"data":[],"id":"img-1"}`, string(data))
+}
@@ -1,7 +1,37 @@
 // Package images provides types for the Images API.
 package images
 
-import "github.com/sofianhadi1983/zai-sdk-go/internal/models"
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Image generation model names.
+const (
+	// ModelCogView3 is the CogView-3 image generation model.
+	ModelCogView3 = "cogview-3"
+	// ModelCogView4 is the CogView-4 image generation model.
+	ModelCogView4 = "cogview-4"
+	// ModelCogView4_250304 is the 2025-03-04 snapshot of CogView-4.
+	ModelCogView4_250304 = "cogview-4-250304"
+)
+
+// Custom size bounds accepted by CogView-4 models.
+const (
+	// MinCustomSize is the minimum width or height in pixels.
+	MinCustomSize = 512
+	// MaxCustomSize is the maximum width or height in pixels.
+	MaxCustomSize = 2048
+	// CustomSizeStep is the value width and height must be divisible by.
+	CustomSizeStep = 16
+	// MaxCustomPixels is the maximum total pixel count (2^21).
+	MaxCustomPixels = 1 << 21
+)
 
 // ImageSize represents the size of the generated image.
 type ImageSize string
@@ -78,6 +108,18 @@ type ImageGenerationRequest struct {
 	// UserID is a unique identifier for the end-user (6-128 characters).
 	// Used for abuse detection and monitoring.
 	UserID string `json:"user_id,omitempty"`
+
+	// WatermarkEnabled controls whether a watermark is added to the image.
+	// Uses the API default when nil.
+	WatermarkEnabled *bool `json:"watermark_enabled,omitempty"`
+
+	// Style is a style hint for the generated image (e.g. "vivid", "natural").
+	Style string `json:"style,omitempty"`
+
+	// ExtraFields are sent as additional top-level fields, for parameters
+	// the SDK has no typed field for yet. Typed fields take precedence
+	// over entries with the same key.
+	ExtraFields map[string]any `json:"-"`
 }
 
 // NewImageGenerationRequest creates a new image generation request with required fields.
@@ -143,6 +185,104 @@ func (r *ImageGenerationRequest) SetUserID(userID string) *ImageGenerationReques
 	return r
 }
 
+// SetWatermarkEnabled sets whether a watermark is added to the generated images.
+//
+// Example:
+//
+//	req.SetWatermarkEnabled(false)
+func (r *ImageGenerationRequest) SetWatermarkEnabled(enabled bool) *ImageGenerationRequest {
+	r.WatermarkEnabled = &enabled
+	return r
+}
+
+// SetStyle sets a style hint for the generated images.
+//
+// Example:
+//
+//	req.SetStyle("vivid")
+func (r *ImageGenerationRequest) SetStyle(style string) *ImageGenerationRequest {
+	r.Style = style
+	return r
+}
+
+// SetCustomSize sets an arbitrary image size as "WIDTHxHEIGHT".
+// Width and height must be between MinCustomSize and MaxCustomSize,
+// divisible by CustomSizeStep, and their product must not exceed
+// MaxCustomPixels. An invalid size is reported by Validate.
+//
+// Example:
+//
+//	req := images.NewImageGenerationRequest(images.ModelCogView4, "A lighthouse at dusk")
+//	req.SetCustomSize(1280, 720)
+func (r *ImageGenerationRequest) SetCustomSize(width, height int) *ImageGenerationRequest {
+	r.Size = ImageSize(fmt.Sprintf("%dx%d", width, height))
+	return r
+}
+
+// fixedSizes are the ImageSize constants, which are sent without checking
+// them against the custom size bounds.
+var fixedSizes = map[ImageSize]bool{
+	Size1024x1024: true,
+	Size768x1344:  true,
+	Size864x1152:  true,
+	Size1344x768:  true,
+	Size1152x864:  true,
+	Size1440x720:  true,
+	Size720x1440:  true,
+	Size1792x1024: true,
+	Size1024x1792: true,
+}
+
+// Validate checks the size of the request. A size that is not one of the
+// ImageSize constants, whether set with SetCustomSize, SetSize or directly,
+// must have the form "WIDTHxHEIGHT" and pass ValidateCustomSize.
+func (r *ImageGenerationRequest) Validate() error {
+	if r.Size == "" || fixedSizes[r.Size] {
+		return nil
+	}
+	width, height, ok := parseSize(string(r.Size))
+	if !ok {
+		return errors.NewValidationError("size", `must have the form "WIDTHxHEIGHT"`, string(r.Size))
+	}
+	return ValidateCustomSize(width, height)
+}
+
+// parseSize parses a size of the form "WIDTHxHEIGHT".
+func parseSize(size string) (width, height int, ok bool) {
+	w, h, ok := strings.Cut(size, "x")
+	if !ok {
+		return 0, 0, false
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil {
+		return 0, 0, false
+	}
+	height, err = strconv.Atoi(h)
+	if err != nil {
+		return 0, 0, false
+	}
+	return width, height, true
+}
+
+// ValidateCustomSize checks that width and height are within the bounds
+// accepted by CogView-4 models.
+func ValidateCustomSize(width, height int) error {
+	size := fmt.Sprintf("%dx%d", width, height)
+	if width < MinCustomSize || width > MaxCustomSize || height < MinCustomSize || height > MaxCustomSize {
+		return errors.NewValidationError("size",
+			fmt.Sprintf("width and height must be between %d and %d", MinCustomSize, MaxCustomSize), size)
+	}
+	if width%CustomSizeStep != 0 || height%CustomSizeStep != 0 {
+		return errors.NewValidationError("size",
+			fmt.Sprintf("width and height must be divisible by %d", CustomSizeStep), size)
+	}
+	if width*height > MaxCustomPixels {
+		return errors.NewValidationError("size",
+			fmt.Sprintf("total pixels must not exceed %d", MaxCustomPixels), size)
+	}
+	return nil
+}
+
 // ImageData represents a single generated image.
 type ImageData struct {
 	// URL is the URL of the generated image (when ResponseFormat is "url").
@@ -188,9 +328,13 @@ type ImageGenerationResponse struct {
 
 	// Usage contains token usage information (if available).
 	Usage *models.Usage `json:"usage,omitempty"`
+
+	// extra holds the response fields the SDK has no typed field for.
+	extra json.RawMessage
 }
 
-// GetFirstImage returns the first generated image, or nil if no images were generated.
+// GetFirstImage returns the first generated image, or nil if the response
+// has no images.
 func (r *ImageGenerationResponse) GetFirstImage() *ImageData {
 	if len(r.Data) == 0 {
 		return nil
@@ -198,8 +342,10 @@ func (r *ImageGenerationResponse) GetFirstImage() *ImageData {
 	return &r.Data[0]
 }
 
-// GetImageURLs returns all image URLs from the response.
-func (r *ImageGenerationResponse) GetImageURLs() []string {
+// GetAllImageURLs returns the URLs of the generated images, skipping
+// images returned as base64 data. It returns an empty slice if there are
+// none.
+func (r *ImageGenerationResponse) GetAllImageURLs() []string {
 	urls := make([]string, 0, len(r.Data))
 	for _, img := range r.Data {
 		if img.URL != "" {
@@ -209,8 +355,9 @@ func (r *ImageGenerationResponse) GetImageURLs() []string {
 	return urls
 }
 
-// GetBase64Images returns all base64-encoded images from the response.
-func (r *ImageGenerationResponse) GetBase64Images() []string {
+// GetAllBase64 returns the base64 data of the generated images, skipping
+// images returned as URLs. It returns an empty slice if there are none.
+func (r *ImageGenerationResponse) GetAllBase64() []string {
 	images := make([]string, 0, len(r.Data))
 	for _, img := range r.Data {
 		if img.B64JSON != "" {
@@ -219,3 +366,15 @@ func (r *ImageGenerationResponse) GetBase64Images() []string {
 	}
 	return images
 }
+
+// GetImageURLs returns all image URLs from the response, as
+// GetAllImageURLs.
+func (r *ImageGenerationResponse) GetImageURLs() []string {
+	return r.GetAllImageURLs()
+}
+
+// GetBase64Images returns all base64-encoded images from the response, as
+// GetAllBase64.
+func (r *ImageGenerationResponse) GetBase64Images() []string {
+	return r.GetAllBase64()
+}
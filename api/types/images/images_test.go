@@ -6,6 +6,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestNewImageGenerationRequest(t *testing.T) {
@@ -332,6 +334,25 @@ func TestImageGenerationResponse_GetBase64Images(t *testing.T) {
 	})
 }
 
+func TestImageGenerationResponse_GetAll(t *testing.T) {
+	t.Parallel()
+
+	resp := &ImageGenerationResponse{
+		Data: []ImageData{
+			{URL: "https://example.com/1.png"},
+			{B64JSON: "data1=="},
+		},
+	}
+	assert.Equal(t, []string{"https://example.com/1.png"}, resp.GetAllImageURLs())
+	assert.Equal(t, []string{"data1=="}, resp.GetAllBase64())
+
+	empty := &ImageGenerationResponse{}
+	assert.NotNil(t, empty.GetAllImageURLs())
+	assert.Empty(t, empty.GetAllImageURLs())
+	assert.NotNil(t, empty.GetAllBase64())
+	assert.Empty(t, empty.GetAllBase64())
+}
+
 func TestImageGenerationResponse_JSON(t *testing.T) {
 	t.Parallel()
 
@@ -515,3 +536,116 @@ func TestImageGenerationResponse_RealWorldExample(t *testing.T) {
 	require.NotNil(t, resp.Usage)
 	assert.Equal(t, 15, resp.Usage.TotalTokens)
 }
+
+func TestImageGenerationRequest_CogView4Params(t *testing.T) {
+	t.Parallel()
+
+	t.Run("setters chain", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewImageGenerationRequest(ModelCogView4, "A lighthouse").
+			SetWatermarkEnabled(false).
+			SetStyle("vivid").
+			SetCustomSize(1280, 720).
+			SetQuality(QualityHD)
+
+		require.NotNil(t, req.WatermarkEnabled)
+		assert.False(t, *req.WatermarkEnabled)
+		assert.Equal(t, "vivid", req.Style)
+		assert.Equal(t, ImageSize("1280x720"), req.Size)
+		assert.NoError(t, req.Validate())
+	})
+
+	t.Run("JSON includes optional fields", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewImageGenerationRequest(ModelCogView4, "A lighthouse").
+			SetWatermarkEnabled(false).
+			SetStyle("natural").
+			SetCustomSize(1024, 1536)
+
+		data, err := json.Marshal(req)
+		require.NoError(t, err)
+
+		assert.Contains(t, string(data), `"model":"cogview-4"`)
+		assert.Contains(t, string(data), `"watermark_enabled":false`)
+		assert.Contains(t, string(data), `"style":"natural"`)
+		assert.Contains(t, string(data), `"size":"1024x1536"`)
+	})
+
+	t.Run("JSON excludes unset fields", func(t *testing.T) {
+		t.Parallel()
+
+		data, err := json.Marshal(NewImageGenerationRequest(ModelCogView4, "A lighthouse"))
+		require.NoError(t, err)
+
+		assert.NotContains(t, string(data), "watermark_enabled")
+		assert.NotContains(t, string(data), "style")
+		assert.NotContains(t, string(data), "size")
+	})
+
+	t.Run("valid size resets previous error", func(t *testing.T) {
+		t.Parallel()
+
+		req := NewImageGenerationRequest(ModelCogView4, "A lighthouse").
+			SetCustomSize(100, 100)
+		assert.Error(t, req.Validate())
+
+		req.SetCustomSize(512, 512)
+		assert.NoError(t, req.Validate())
+
+		req.SetCustomSize(100, 100).SetSize(Size1344x768)
+		assert.NoError(t, req.Validate(), "a fixed size replaces an invalid custom size")
+	})
+
+	t.Run("sizes set without SetCustomSize are checked", func(t *testing.T) {
+		t.Parallel()
+
+		var validationErr *errors.ValidationError
+		req := NewImageGenerationRequest(ModelCogView4, "A lighthouse").SetSize("4096x4096")
+		require.ErrorAs(t, req.Validate(), &validationErr)
+		assert.Equal(t, "size", validationErr.Field)
+
+		req.Size = "1000x1000"
+		assert.Error(t, req.Validate(), "not divisible by the step")
+
+		req.Size = "large"
+		require.ErrorAs(t, req.Validate(), &validationErr)
+
+		req.Size = "1280x720"
+		assert.NoError(t, req.Validate())
+	})
+}
+
+func TestValidateCustomSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		width   int
+		height  int
+		wantErr string
+	}{
+		{"minimum", 512, 512, ""},
+		{"maximum pixels", 2048, 1024, ""},
+		{"too small", 496, 512, "between 512 and 2048"},
+		{"too large", 2064, 512, "between 512 and 2048"},
+		{"not divisible", 1000, 512, "divisible by 16"},
+		{"too many pixels", 2048, 2048, "total pixels"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateCustomSize(tt.width, tt.height)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
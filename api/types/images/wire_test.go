@@ -0,0 +1,85 @@
+package images
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWire_Request(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		golden string
+		req    *ImageGenerationRequest
+	}{
+		{"request_basic.json", NewImageGenerationRequest(ModelCogView4, "A watercolor fox in a snowy forest")},
+		{"request_options.json", NewImageGenerationRequest(ModelCogView4, "A lighthouse at dusk").
+			SetCustomSize(1280, 720).
+			SetQuality(QualityHD).
+			SetN(1).
+			SetResponseFormat(ResponseFormatB64JSON).
+			SetUserID("user-42").
+			SetWatermarkEnabled(false).
+			SetStyle("watercolor")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := json.Marshal(tt.req)
+			require.NoError(t, err)
+			golden.AssertJSON(t, tt.golden, data)
+		})
+	}
+}
+
+func TestWire_Response(t *testing.T) {
+	t.Parallel()
+
+	t.Run("url", func(t *testing.T) {
+		t.Parallel()
+
+		var resp ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(golden.Read(t, "response_url.json"), &resp))
+		assert.Equal(t, int64(1760582400), resp.Created)
+		assert.Equal(t, []string{"https://aigc-files.bigmodel.cn/api/cogview/20251016104000f3a2_0.png"}, resp.GetImageURLs())
+		assert.Equal(t, []ContentFilterItem{{Role: "user", Level: 3}, {Role: "assistant", Level: 3}}, resp.ContentFilter)
+		assert.Nil(t, resp.Usage)
+		assert.JSONEq(t, `{"request_id":"req-c0ffee"}`, string(resp.Extra()))
+
+		assertRoundTrip(t, &resp)
+	})
+
+	t.Run("b64_json", func(t *testing.T) {
+		t.Parallel()
+
+		var resp ImageGenerationResponse
+		require.NoError(t, json.Unmarshal(golden.Read(t, "response_b64.json"), &resp))
+		first := resp.GetFirstImage()
+		require.NotNil(t, first)
+		assert.Empty(t, first.GetImageURL())
+		assert.Equal(t, "A watercolor fox sitting in a snowy forest at dusk", first.RevisedPrompt)
+		assert.Len(t, resp.GetBase64Images(), 1)
+		assert.Equal(t, &models.Usage{}, resp.Usage)
+
+		assertRoundTrip(t, &resp)
+	})
+}
+
+// assertRoundTrip checks that re-encoding resp keeps every field.
+func assertRoundTrip(t *testing.T, resp *ImageGenerationResponse) {
+	t.Helper()
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+
+	var again ImageGenerationResponse
+	require.NoError(t, json.Unmarshal(data, &again))
+	assert.Equal(t, resp, &again)
+}
@@ -0,0 +1,277 @@
+// Package knowledge provides types for the Knowledge Base API.
+package knowledge
+
+import (
+	"strconv"
+)
+
+// DocumentStatus represents the processing status of a document.
+type DocumentStatus string
+
+const (
+	// DocumentStatusProcessing indicates the document is being chunked and embedded.
+	DocumentStatusProcessing DocumentStatus = "processing"
+	// DocumentStatusReady indicates the document can be retrieved.
+	DocumentStatusReady DocumentStatus = "ready"
+	// DocumentStatusFailed indicates the document could not be processed.
+	DocumentStatusFailed DocumentStatus = "failed"
+)
+
+// Chunking strategies.
+const (
+	// ChunkingAuto lets the platform choose how to split documents.
+	ChunkingAuto = "auto"
+	// ChunkingFixed splits documents into chunks of ChunkSize tokens.
+	ChunkingFixed = "fixed"
+	// ChunkingSeparator splits documents at the given separators.
+	ChunkingSeparator = "separator"
+)
+
+// Collection is a knowledge base: a set of documents that can be queried.
+type Collection struct {
+	// ID is the collection identifier
+	ID string `json:"id"`
+
+	// Object is the object type, always "knowledge"
+	Object string `json:"object,omitempty"`
+
+	// Name is the collection name
+	Name string `json:"name"`
+
+	// Description describes the collection contents
+	Description string `json:"description,omitempty"`
+
+	// EmbeddingModel is the model used to embed the documents
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+
+	// DocumentCount is the number of documents in the collection
+	DocumentCount int `json:"document_count,omitempty"`
+
+	// CreatedAt is the creation time represented by the Unix timestamp (in seconds)
+	CreatedAt int64 `json:"created_at,omitempty"`
+}
+
+// CreateCollectionRequest represents a request to create a collection.
+type CreateCollectionRequest struct {
+	// Name is the collection name.
+	// Required.
+	Name string `json:"name"`
+
+	// Description describes the collection contents.
+	Description string `json:"description,omitempty"`
+
+	// EmbeddingModel is the model used to embed the documents.
+	// If empty, the platform default is used.
+	EmbeddingModel string `json:"embedding_model,omitempty"`
+}
+
+// NewCreateCollectionRequest creates a request for a collection named name.
+func NewCreateCollectionRequest(name string) *CreateCollectionRequest {
+	return &CreateCollectionRequest{Name: name}
+}
+
+// SetDescription sets the collection description.
+func (r *CreateCollectionRequest) SetDescription(description string) *CreateCollectionRequest {
+	r.Description = description
+	return r
+}
+
+// SetEmbeddingModel sets the embedding model.
+func (r *CreateCollectionRequest) SetEmbeddingModel(model string) *CreateCollectionRequest {
+	r.EmbeddingModel = model
+	return r
+}
+
+// ChunkingOptions controls how a document is split into chunks.
+// Unset fields use the platform defaults.
+type ChunkingOptions struct {
+	// Strategy is the chunking strategy, such as ChunkingFixed.
+	Strategy string `json:"strategy,omitempty"`
+
+	// ChunkSize is the maximum chunk size in tokens.
+	ChunkSize int `json:"chunk_size,omitempty"`
+
+	// ChunkOverlap is the number of tokens shared by adjacent chunks.
+	ChunkOverlap int `json:"chunk_overlap,omitempty"`
+
+	// Separators are the strings documents are split at with ChunkingSeparator.
+	Separators []string `json:"separators,omitempty"`
+}
+
+// AddDocumentRequest represents a request to add a file to a collection.
+type AddDocumentRequest struct {
+	// FileID is the ID of a file uploaded with the Files API.
+	FileID string `json:"file_id"`
+
+	// Chunking controls how the file is split into chunks.
+	Chunking *ChunkingOptions `json:"chunking,omitempty"`
+}
+
+// Document is a file added to a collection.
+type Document struct {
+	// ID is the document identifier
+	ID string `json:"id"`
+
+	// Object is the object type, always "knowledge.document"
+	Object string `json:"object,omitempty"`
+
+	// CollectionID is the ID of the collection holding the document
+	CollectionID string `json:"knowledge_id"`
+
+	// FileID is the ID of the source file
+	FileID string `json:"file_id"`
+
+	// Filename is the name of the source file
+	Filename string `json:"filename,omitempty"`
+
+	// Status is the processing status
+	Status DocumentStatus `json:"status"`
+
+	// ChunkCount is the number of chunks the document was split into
+	ChunkCount int `json:"chunk_count,omitempty"`
+
+	// FailureReason explains why processing failed
+	FailureReason string `json:"failure_reason,omitempty"`
+
+	// CreatedAt is the creation time represented by the Unix timestamp (in seconds)
+	CreatedAt int64 `json:"created_at,omitempty"`
+}
+
+// IsReady returns true if the document can be retrieved.
+func (d *Document) IsReady() bool {
+	return d.Status == DocumentStatusReady
+}
+
+// IsFailed returns true if the document could not be processed.
+func (d *Document) IsFailed() bool {
+	return d.Status == DocumentStatusFailed
+}
+
+// DocumentListResponse represents a page of documents.
+type DocumentListResponse struct {
+	// Object is the object type, always "list"
+	Object string `json:"object"`
+
+	// Data is the list of documents
+	Data []Document `json:"data"`
+
+	// HasMore indicates if there are more documents available
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+// GetLastID returns the ID of the last document in the page, for use as
+// the cursor of the next page.
+func (r *DocumentListResponse) GetLastID() string {
+	if len(r.Data) == 0 {
+		return ""
+	}
+	return r.Data[len(r.Data)-1].ID
+}
+
+// ListParams holds the query parameters for listing documents.
+type ListParams struct {
+	// After is the cursor: the ID of the last document of the previous page.
+	After string
+
+	// Limit is the maximum number of documents per page. Zero uses the API default.
+	Limit int
+}
+
+// ListOption configures a document list request.
+type ListOption func(*ListParams)
+
+// NewListParams creates list parameters from options.
+func NewListParams(opts ...ListOption) *ListParams {
+	params := &ListParams{}
+	for _, opt := range opts {
+		opt(params)
+	}
+	return params
+}
+
+// WithAfter starts the list after the document with the given ID.
+func WithAfter(documentID string) ListOption {
+	return func(p *ListParams) {
+		p.After = documentID
+	}
+}
+
+// WithLimit sets the maximum number of documents per page.
+func WithLimit(limit int) ListOption {
+	return func(p *ListParams) {
+		p.Limit = limit
+	}
+}
+
+// Query returns the parameters as URL query values. Unset parameters are omitted.
+func (p *ListParams) Query() map[string]string {
+	query := make(map[string]string)
+	if p.After != "" {
+		query["after"] = p.After
+	}
+	if p.Limit > 0 {
+		query["limit"] = strconv.Itoa(p.Limit)
+	}
+	return query
+}
+
+// DeleteDocumentResponse represents the response when deleting a document.
+type DeleteDocumentResponse struct {
+	// ID is the ID of the deleted document
+	ID string `json:"id"`
+
+	// Object is the object type, always "knowledge.document"
+	Object string `json:"object,omitempty"`
+
+	// Deleted indicates whether the document was deleted
+	Deleted bool `json:"deleted"`
+}
+
+// IsDeleted returns true if the document was successfully deleted.
+func (r *DeleteDocumentResponse) IsDeleted() bool {
+	return r.Deleted
+}
+
+// QueryRequest represents a retrieval query against a collection.
+type QueryRequest struct {
+	// Query is the text to find relevant chunks for.
+	Query string `json:"query"`
+
+	// TopK is the maximum number of chunks to return.
+	TopK int `json:"top_k,omitempty"`
+}
+
+// Chunk is a piece of a document returned by a query.
+type Chunk struct {
+	// Text is the chunk content
+	Text string `json:"text"`
+
+	// Score is the relevance of the chunk to the query; higher is more relevant
+	Score float64 `json:"score"`
+
+	// DocumentID is the ID of the document the chunk belongs to
+	DocumentID string `json:"document_id"`
+
+	// FileID is the ID of the source file
+	FileID string `json:"file_id"`
+
+	// StartOffset is the offset of the chunk's first character in the source file
+	StartOffset int `json:"start_offset"`
+
+	// EndOffset is the offset just past the chunk's last character in the source file
+	EndOffset int `json:"end_offset"`
+}
+
+// QueryResponse represents the chunks retrieved for a query.
+type QueryResponse struct {
+	// Object is the object type, always "list"
+	Object string `json:"object,omitempty"`
+
+	// Data are the retrieved chunks, most relevant first
+	Data []Chunk `json:"data"`
+}
+
+// GetChunks returns the retrieved chunks.
+func (r *QueryResponse) GetChunks() []Chunk {
+	return r.Data
+}
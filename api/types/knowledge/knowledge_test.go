@@ -0,0 +1,101 @@
+package knowledge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCollectionRequest(t *testing.T) {
+	t.Parallel()
+
+	req := NewCreateCollectionRequest("manuals").
+		SetDescription("Product manuals").
+		SetEmbeddingModel("embedding-3")
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"manuals","description":"Product manuals","embedding_model":"embedding-3"}`, string(data))
+
+	data, err = json.Marshal(NewCreateCollectionRequest("manuals"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"manuals"}`, string(data))
+}
+
+func TestAddDocumentRequest_JSON(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(AddDocumentRequest{FileID: "file-1"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"file_id":"file-1"}`, string(data))
+
+	data, err = json.Marshal(AddDocumentRequest{
+		FileID:   "file-1",
+		Chunking: &ChunkingOptions{Strategy: ChunkingSeparator, Separators: []string{"\n\n"}},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"file_id":"file-1","chunking":{"strategy":"separator","separators":["\n\n"]}}`, string(data))
+}
+
+func TestDocument_Status(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status DocumentStatus
+		ready  bool
+		failed bool
+	}{
+		{DocumentStatusProcessing, false, false},
+		{DocumentStatusReady, true, false},
+		{DocumentStatusFailed, false, true},
+	}
+
+	for _, tt := range tests {
+		doc := &Document{Status: tt.status}
+		assert.Equal(t, tt.ready, doc.IsReady(), tt.status)
+		assert.Equal(t, tt.failed, doc.IsFailed(), tt.status)
+	}
+}
+
+func TestDocumentListResponse_GetLastID(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, (&DocumentListResponse{}).GetLastID())
+
+	resp := &DocumentListResponse{Data: []Document{{ID: "doc-1"}, {ID: "doc-2"}}}
+	assert.Equal(t, "doc-2", resp.GetLastID())
+}
+
+func TestListParams_Query(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, NewListParams().Query())
+
+	params := NewListParams(WithAfter("doc-1"), WithLimit(20))
+	assert.Equal(t, map[string]string{"after": "doc-1", "limit": "20"}, params.Query())
+}
+
+func TestQueryResponse_JSON(t *testing.T) {
+	t.Parallel()
+
+	body := `{
+		"object": "list",
+		"data": [
+			{"text": "Hold the button", "score": 0.92, "document_id": "doc-1", "file_id": "file-1", "start_offset": 120, "end_offset": 240}
+		]
+	}`
+
+	var resp QueryResponse
+	require.NoError(t, json.Unmarshal([]byte(body), &resp))
+	require.Len(t, resp.GetChunks(), 1)
+
+	chunk := resp.GetChunks()[0]
+	assert.Equal(t, "Hold the button", chunk.Text)
+	assert.Equal(t, 0.92, chunk.Score)
+	assert.Equal(t, "doc-1", chunk.DocumentID)
+	assert.Equal(t, "file-1", chunk.FileID)
+	assert.Equal(t, 120, chunk.StartOffset)
+	assert.Equal(t, 240, chunk.EndOffset)
+}
@@ -0,0 +1,172 @@
+// Package models provides model name constants and types for the Models API.
+package models
+
+// ID identifies a model. The constants below cover current models; any other
+// model name can be used by converting a string, e.g. models.ID("glm-5").
+type ID string
+
+// String returns the model name.
+func (id ID) String() string {
+	return string(id)
+}
+
+// Chat models.
+const (
+	// GLM47 is the GLM-4.7 flagship chat model.
+	GLM47 ID = "glm-4.7"
+	// GLM46 is the GLM-4.6 chat model.
+	GLM46 ID = "glm-4.6"
+	// GLM45 is the GLM-4.5 chat model.
+	GLM45 ID = "glm-4.5"
+	// GLM45Air is the lightweight GLM-4.5-Air chat model.
+	GLM45Air ID = "glm-4.5-air"
+	// GLM45Flash is the free GLM-4.5-Flash chat model.
+	GLM45Flash ID = "glm-4.5-flash"
+	// GLM4Plus is the GLM-4-Plus chat model.
+	GLM4Plus ID = "glm-4-plus"
+	// GLM4Air is the GLM-4-Air chat model.
+	GLM4Air ID = "glm-4-air"
+	// GLM4Flash is the free GLM-4-Flash chat model.
+	GLM4Flash ID = "glm-4-flash"
+	// GLM4Long is the GLM-4-Long chat model with a 1M token context window.
+	GLM4Long ID = "glm-4-long"
+	// GLM4Assistant is the model used by the Assistant API.
+	GLM4Assistant ID = "glm-4-assistant"
+)
+
+// Vision chat models.
+const (
+	// GLM46V is the GLM-4.6V vision model.
+	GLM46V ID = "glm-4.6v"
+	// GLM45V is the GLM-4.5V vision model.
+	GLM45V ID = "glm-4.5v"
+	// GLM4VPlus is the GLM-4V-Plus vision model.
+	GLM4VPlus ID = "glm-4v-plus"
+	// GLM4VFlash is the free GLM-4V-Flash vision model.
+	GLM4VFlash ID = "glm-4v-flash"
+)
+
+// Embedding models.
+const (
+	// Embedding2 is the embedding-2 model (1024 dimensions).
+	Embedding2 ID = "embedding-2"
+	// Embedding3 is the embedding-3 model with configurable dimensions.
+	Embedding3 ID = "embedding-3"
+)
+
+// Image generation models.
+const (
+	// CogView3 is the CogView-3 image model.
+	CogView3 ID = "cogview-3"
+	// CogView3Flash is the free CogView-3-Flash image model.
+	CogView3Flash ID = "cogview-3-flash"
+	// CogView4 is the CogView-4 image model.
+	CogView4 ID = "cogview-4"
+	// CogView4_250304 is the 2025-03-04 snapshot of CogView-4.
+	CogView4_250304 ID = "cogview-4-250304"
+)
+
+// Video generation models.
+const (
+	// CogVideoX is the CogVideoX video model.
+	CogVideoX ID = "cogvideox"
+	// CogVideoX2 is the CogVideoX-2 video model.
+	CogVideoX2 ID = "cogvideox-2"
+	// CogVideoXFlash is the free CogVideoX-Flash video model.
+	CogVideoXFlash ID = "cogvideox-flash"
+)
+
+// Audio models.
+const (
+	// GLMASR is the GLM-ASR speech recognition model.
+	GLMASR ID = "glm-asr"
+	// CogTTS is the CogTTS speech synthesis model.
+	CogTTS ID = "cogtts"
+)
+
+// Capabilities describes the features a model supports.
+type Capabilities struct {
+	// Chat indicates the model supports chat completions.
+	Chat bool `json:"chat,omitempty"`
+
+	// Vision indicates the model accepts image input.
+	Vision bool `json:"vision,omitempty"`
+
+	// FunctionCalling indicates the model supports tool calls.
+	FunctionCalling bool `json:"function_calling,omitempty"`
+
+	// Streaming indicates the model supports streamed responses.
+	Streaming bool `json:"streaming,omitempty"`
+
+	// Thinking indicates the model supports deep thinking mode.
+	Thinking bool `json:"thinking,omitempty"`
+
+	// Embeddings indicates the model produces embeddings.
+	Embeddings bool `json:"embeddings,omitempty"`
+
+	// ImageGeneration indicates the model generates images.
+	ImageGeneration bool `json:"image_generation,omitempty"`
+
+	// VideoGeneration indicates the model generates videos.
+	VideoGeneration bool `json:"video_generation,omitempty"`
+
+	// Audio indicates the model processes or produces audio.
+	Audio bool `json:"audio,omitempty"`
+}
+
+// Model describes a model available to the API key.
+type Model struct {
+	// ID is the model identifier
+	ID ID `json:"id"`
+
+	// Object is the type identifier, always "model"
+	Object string `json:"object,omitempty"`
+
+	// Created is the creation time represented by the Unix timestamp (in seconds)
+	Created int64 `json:"created,omitempty"`
+
+	// OwnedBy is the organization that owns the model
+	OwnedBy string `json:"owned_by,omitempty"`
+
+	// ContextWindow is the maximum number of input and output tokens
+	ContextWindow int `json:"context_window,omitempty"`
+
+	// MaxOutputTokens is the maximum number of tokens the model can generate
+	MaxOutputTokens int `json:"max_output_tokens,omitempty"`
+
+	// Capabilities describes the features the model supports
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+}
+
+// ModelListResponse represents the response from listing models.
+type ModelListResponse struct {
+	// Object is the type identifier, always "list"
+	Object string `json:"object"`
+
+	// Data is the list of models
+	Data []Model `json:"data"`
+}
+
+// GetModels returns the list of models.
+func (r *ModelListResponse) GetModels() []Model {
+	return r.Data
+}
+
+// Find returns the model with the given ID, or nil if it is not listed.
+func (r *ModelListResponse) Find(id ID) *Model {
+	for i := range r.Data {
+		if r.Data[i].ID == id {
+			return &r.Data[i]
+		}
+	}
+	return nil
+}
+
+// IDs returns the IDs of all listed models.
+func (r *ModelListResponse) IDs() []ID {
+	ids := make([]ID, 0, len(r.Data))
+	for _, m := range r.Data {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
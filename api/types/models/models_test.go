@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestID_String(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "glm-4.7", GLM47.String())
+	assert.Equal(t, "embedding-3", Embedding3.String())
+	assert.Equal(t, "glm-5", ID("glm-5").String())
+}
+
+func TestModel_UnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+		"id": "glm-4.6v",
+		"object": "model",
+		"created": 1700000000,
+		"owned_by": "z-ai",
+		"context_window": 131072,
+		"max_output_tokens": 32768,
+		"capabilities": {"chat": true, "vision": true, "function_calling": true, "streaming": true}
+	}`
+
+	var m Model
+	require.NoError(t, json.Unmarshal([]byte(data), &m))
+
+	assert.Equal(t, GLM46V, m.ID)
+	assert.Equal(t, "model", m.Object)
+	assert.Equal(t, int64(1700000000), m.Created)
+	assert.Equal(t, "z-ai", m.OwnedBy)
+	assert.Equal(t, 131072, m.ContextWindow)
+	assert.Equal(t, 32768, m.MaxOutputTokens)
+	require.NotNil(t, m.Capabilities)
+	assert.True(t, m.Capabilities.Chat)
+	assert.True(t, m.Capabilities.Vision)
+	assert.True(t, m.Capabilities.FunctionCalling)
+	assert.True(t, m.Capabilities.Streaming)
+	assert.False(t, m.Capabilities.Thinking)
+	assert.False(t, m.Capabilities.Embeddings)
+}
+
+func TestModelListResponse(t *testing.T) {
+	t.Parallel()
+
+	resp := &ModelListResponse{
+		Object: "list",
+		Data: []Model{
+			{ID: GLM47, ContextWindow: 200000},
+			{ID: Embedding3},
+		},
+	}
+
+	assert.Len(t, resp.GetModels(), 2)
+	assert.Equal(t, []ID{GLM47, Embedding3}, resp.IDs())
+
+	found := resp.Find(GLM47)
+	require.NotNil(t, found)
+	assert.Equal(t, 200000, found.ContextWindow)
+
+	assert.Nil(t, resp.Find(CogView4))
+}
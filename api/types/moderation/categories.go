@@ -0,0 +1,189 @@
+package moderation
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// categoryNames are the category keys with a dedicated field.
+var categoryNames = []string{
+	"harassment",
+	"harassment/threatening",
+	"hate",
+	"hate/threatening",
+	"self-harm",
+	"self-harm/instructions",
+	"self-harm/intent",
+	"sexual",
+	"sexual/minors",
+	"violence",
+	"violence/graphic",
+}
+
+// isKnownCategory reports whether name has a dedicated field.
+func isKnownCategory(name string) bool {
+	for _, n := range categoryNames {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// UnmarshalJSON decodes known categories into fields and keeps the rest in Extra.
+func (c *ModerationCategories) UnmarshalJSON(data []byte) error {
+	type alias ModerationCategories
+	var known alias
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	extra, err := unknownCategories[bool](data)
+	if err != nil {
+		return err
+	}
+
+	*c = ModerationCategories(known)
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the known categories together with Extra.
+func (c ModerationCategories) MarshalJSON() ([]byte, error) {
+	type alias ModerationCategories
+	return marshalWithExtra(alias(c), c.Extra)
+}
+
+// AsMap returns all categories, including Extra, keyed by API name.
+func (c *ModerationCategories) AsMap() map[string]bool {
+	m := map[string]bool{
+		"harassment":             c.Harassment,
+		"harassment/threatening": c.HarassmentThreatening,
+		"hate":                   c.Hate,
+		"hate/threatening":       c.HateThreatening,
+		"self-harm":              c.SelfHarm,
+		"self-harm/instructions": c.SelfHarmInstructions,
+		"self-harm/intent":       c.SelfHarmIntent,
+		"sexual":                 c.Sexual,
+		"sexual/minors":          c.SexualMinors,
+		"violence":               c.Violence,
+		"violence/graphic":       c.ViolenceGraphic,
+	}
+	for k, v := range c.Extra {
+		m[k] = v
+	}
+	return m
+}
+
+// Flagged returns the sorted names of all flagged categories, including Extra.
+//
+// Example:
+//
+//	for _, name := range result.Categories.Flagged() {
+//	    fmt.Printf("flagged: %s (%.2f)\n", name, result.CategoryScores.AsMap()[name])
+//	}
+func (c *ModerationCategories) Flagged() []string {
+	var names []string
+	for k, v := range c.AsMap() {
+		if v {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnmarshalJSON decodes known category scores into fields and keeps the rest in Extra.
+func (s *ModerationCategoryScores) UnmarshalJSON(data []byte) error {
+	type alias ModerationCategoryScores
+	var known alias
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	extra, err := unknownCategories[float64](data)
+	if err != nil {
+		return err
+	}
+
+	*s = ModerationCategoryScores(known)
+	s.Extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the known category scores together with Extra.
+func (s ModerationCategoryScores) MarshalJSON() ([]byte, error) {
+	type alias ModerationCategoryScores
+	return marshalWithExtra(alias(s), s.Extra)
+}
+
+// AsMap returns all category scores, including Extra, keyed by API name.
+func (s *ModerationCategoryScores) AsMap() map[string]float64 {
+	m := map[string]float64{
+		"harassment":             s.Harassment,
+		"harassment/threatening": s.HarassmentThreatening,
+		"hate":                   s.Hate,
+		"hate/threatening":       s.HateThreatening,
+		"self-harm":              s.SelfHarm,
+		"self-harm/instructions": s.SelfHarmInstructions,
+		"self-harm/intent":       s.SelfHarmIntent,
+		"sexual":                 s.Sexual,
+		"sexual/minors":          s.SexualMinors,
+		"violence":               s.Violence,
+		"violence/graphic":       s.ViolenceGraphic,
+	}
+	for k, v := range s.Extra {
+		m[k] = v
+	}
+	return m
+}
+
+// unknownCategories returns the entries of the JSON object in data whose
+// keys have no dedicated field. Values of an unexpected type are skipped.
+func unknownCategories[T any](data []byte) (map[string]T, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var extra map[string]T
+	for k, v := range raw {
+		if isKnownCategory(k) {
+			continue
+		}
+		var value T
+		if err := json.Unmarshal(v, &value); err != nil {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]T)
+		}
+		extra[k] = value
+	}
+	return extra, nil
+}
+
+// marshalWithExtra encodes known and adds the extra entries that do not
+// collide with known keys.
+func marshalWithExtra[T any](known any, extra map[string]T) ([]byte, error) {
+	data, err := json.Marshal(known)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range extra {
+		if _, ok := merged[k]; ok {
+			continue
+		}
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		merged[k] = encoded
+	}
+	return json.Marshal(merged)
+}
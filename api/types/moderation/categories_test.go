@@ -0,0 +1,79 @@
+package moderation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resultWithUnknownCategories = `{
+	"flagged": true,
+	"categories": {
+		"hate": false,
+		"violence": true,
+		"sexual/image": true,
+		"gore": false,
+		"note": "not a bool"
+	},
+	"category_scores": {
+		"hate": 0.01,
+		"violence": 0.91,
+		"sexual/image": 0.87,
+		"gore": 0.2
+	}
+}`
+
+func TestModerationCategories_UnknownKeys(t *testing.T) {
+	t.Parallel()
+
+	var result ModerationResult
+	require.NoError(t, json.Unmarshal([]byte(resultWithUnknownCategories), &result))
+
+	assert.True(t, result.Categories.Violence)
+	assert.False(t, result.Categories.Hate)
+	assert.Equal(t, map[string]bool{"sexual/image": true, "gore": false}, result.Categories.Extra)
+	assert.Equal(t, []string{"sexual/image", "violence"}, result.Categories.Flagged())
+
+	assert.InDelta(t, 0.91, result.CategoryScores.Violence, 1e-9)
+	assert.Equal(t, map[string]float64{"sexual/image": 0.87, "gore": 0.2}, result.CategoryScores.Extra)
+	assert.InDelta(t, 0.87, result.CategoryScores.AsMap()["sexual/image"], 1e-9)
+}
+
+func TestModerationCategories_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	var result ModerationResult
+	require.NoError(t, json.Unmarshal([]byte(resultWithUnknownCategories), &result))
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+
+	var raw struct {
+		Categories     map[string]interface{} `json:"categories"`
+		CategoryScores map[string]interface{} `json:"category_scores"`
+	}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, true, raw.Categories["sexual/image"])
+	assert.Equal(t, true, raw.Categories["violence"])
+	assert.Equal(t, 0.87, raw.CategoryScores["sexual/image"])
+
+	var decoded ModerationResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, result, decoded)
+}
+
+func TestModerationCategories_NoExtra(t *testing.T) {
+	t.Parallel()
+
+	var c ModerationCategories
+	require.NoError(t, json.Unmarshal([]byte(`{"hate": true}`), &c))
+	assert.True(t, c.Hate)
+	assert.Nil(t, c.Extra)
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"hate":true`)
+	assert.NotContains(t, string(data), "Extra")
+}
@@ -0,0 +1,263 @@
+package moderation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBatchSize is the number of texts ModerationsService.CheckMany
+// sends per request.
+const DefaultBatchSize = 100
+
+// DefaultManyConcurrency is the number of requests
+// ModerationsService.CheckMany runs at once.
+const DefaultManyConcurrency = 4
+
+// DefaultBatchRetries is the number of times ModerationsService.CheckMany
+// retries a batch that failed with a transient error.
+const DefaultBatchRetries = 2
+
+// DefaultRetryBackoff is the wait before the first retry of a batch; it
+// doubles with every retry.
+const DefaultRetryBackoff = time.Second
+
+// ManyItem is the result of one text of ModerationsService.CheckMany.
+type ManyItem struct {
+	// Index is the index of the text.
+	Index int
+
+	// Result is the moderation result, or nil if the batch of the text
+	// failed.
+	Result *ModerationResult
+
+	// Err is the error of the batch of the text, or nil.
+	Err error
+
+	// Completed is the number of texts with a result or error, including
+	// this one.
+	Completed int
+
+	// Total is the number of texts.
+	Total int
+}
+
+// ManyConfig holds settings for ModerationsService.CheckMany.
+type ManyConfig struct {
+	// BatchSize is the number of texts sent per request.
+	BatchSize int
+
+	// Concurrency is the maximum number of requests in flight.
+	Concurrency int
+
+	// Retries is how many times a batch that failed with a rate limit,
+	// server or connection error is retried, on top of the client's
+	// retries.
+	Retries int
+
+	// RetryBackoff is the wait before the first retry of a batch. It
+	// doubles with every retry; a Retry-After of a rate limit error takes
+	// precedence.
+	RetryBackoff time.Duration
+
+	// OnResult, if set, is called with the result of every text as soon as
+	// its batch completes. Calls are never concurrent.
+	OnResult func(ManyItem)
+
+	// Ordered delivers results to OnResult in the order of the texts,
+	// holding back batches that complete before an earlier one.
+	Ordered bool
+}
+
+// ManyOption configures ModerationsService.CheckMany.
+type ManyOption func(*ManyConfig)
+
+// NewManyConfig creates a configuration with the given options applied.
+func NewManyConfig(opts ...ManyOption) *ManyConfig {
+	cfg := &ManyConfig{
+		BatchSize:    DefaultBatchSize,
+		Concurrency:  DefaultManyConcurrency,
+		Retries:      DefaultBatchRetries,
+		RetryBackoff: DefaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultManyConcurrency
+	}
+	return cfg
+}
+
+// WithBatchSize sets the number of texts sent per request. Lower it if
+// the texts are long and requests exceed the API's payload limit.
+func WithBatchSize(n int) ManyOption {
+	return func(c *ManyConfig) {
+		c.BatchSize = n
+	}
+}
+
+// WithConcurrency sets the maximum number of requests in flight.
+func WithConcurrency(n int) ManyOption {
+	return func(c *ManyConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithBatchRetries sets how many times a batch that failed with a
+// transient error is retried, and the wait before the first retry. Zero
+// retries disables them.
+func WithBatchRetries(n int, backoff time.Duration) ManyOption {
+	return func(c *ManyConfig) {
+		c.Retries = n
+		c.RetryBackoff = backoff
+	}
+}
+
+// WithResults sets a callback called with the result of every text as
+// soon as its batch completes, in the order of the texts within a batch.
+//
+// Example:
+//
+//	moderation.WithResults(func(item moderation.ManyItem) {
+//	    if item.Result != nil && item.Result.Flagged {
+//	        flagged <- item.Index
+//	    }
+//	})
+func WithResults(fn func(ManyItem)) ManyOption {
+	return func(c *ManyConfig) {
+		c.OnResult = fn
+	}
+}
+
+// WithOrderedResults delivers results to the WithResults callback in the
+// order of the texts.
+func WithOrderedResults() ManyOption {
+	return func(c *ManyConfig) {
+		c.Ordered = true
+	}
+}
+
+// ManySummary aggregates the results of ModerationsService.CheckMany.
+type ManySummary struct {
+	// Total is the number of texts.
+	Total int
+
+	// Checked is the number of texts with a result.
+	Checked int
+
+	// Flagged is the number of flagged texts.
+	Flagged int
+
+	// Failed is the number of texts whose batch failed.
+	Failed int
+
+	// Categories maps category names to the number of texts flagged for
+	// them.
+	Categories map[string]int
+
+	// MaxScores maps category names to their highest score.
+	MaxScores map[string]float64
+}
+
+// Add adds a result to the summary.
+func (s *ManySummary) Add(result *ModerationResult) {
+	if s.Categories == nil {
+		s.Categories = make(map[string]int)
+	}
+	if s.MaxScores == nil {
+		s.MaxScores = make(map[string]float64)
+	}
+
+	s.Checked++
+	if result.Flagged {
+		s.Flagged++
+	}
+	for _, name := range result.Categories.Flagged() {
+		s.Categories[name]++
+	}
+	for name, score := range result.CategoryScores.AsMap() {
+		if highest, ok := s.MaxScores[name]; !ok || score > highest {
+			s.MaxScores[name] = score
+		}
+	}
+}
+
+// ManyResult is the result of ModerationsService.CheckMany.
+type ManyResult struct {
+	// Results holds the result of every text, in the order of the texts.
+	// Results of texts whose batch failed are nil.
+	Results []*ModerationResult
+
+	// Summary aggregates the results.
+	Summary ManySummary
+}
+
+// BatchError reports a batch of ModerationsService.CheckMany that failed.
+type BatchError struct {
+	// Start and End are the indexes of the first text of the batch and of
+	// the text after the last.
+	Start, End int
+
+	// Attempts is the number of requests made for the batch.
+	Attempts int
+
+	// Err is the error of the last attempt.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("texts %d-%d failed after %d attempt(s): %v", e.Start, e.End-1, e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last attempt.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// maxListedBatches is the number of failed batches ManyError lists in its
+// message.
+const maxListedBatches = 5
+
+// ManyError reports the batches of ModerationsService.CheckMany that
+// failed. The results of the other batches are still returned.
+type ManyError struct {
+	// Batches are the failed batches, in the order of the texts.
+	Batches []*BatchError
+}
+
+// Error implements the error interface.
+func (e *ManyError) Error() string {
+	parts := make([]string, 0, maxListedBatches+1)
+	for _, batch := range e.Batches[:min(len(e.Batches), maxListedBatches)] {
+		parts = append(parts, batch.Error())
+	}
+	if len(e.Batches) > maxListedBatches {
+		parts = append(parts, fmt.Sprintf("and %d more", len(e.Batches)-maxListedBatches))
+	}
+	return fmt.Sprintf("moderation: %d batch(es) failed: %s", len(e.Batches), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the errors of the failed batches.
+func (e *ManyError) Unwrap() []error {
+	errs := make([]error, len(e.Batches))
+	for i, batch := range e.Batches {
+		errs[i] = batch
+	}
+	return errs
+}
+
+// NewManyError returns a ManyError of the failed batches, sorted in the
+// order of the texts, or nil if there are none.
+func NewManyError(batches []*BatchError) error {
+	if len(batches) == 0 {
+		return nil
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].Start < batches[j].Start })
+	return &ManyError{Batches: batches}
+}
@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManySummary_Add(t *testing.T) {
+	t.Parallel()
+
+	var summary ManySummary
+	summary.Add(&ModerationResult{
+		Flagged:        true,
+		Categories:     ModerationCategories{Hate: true, Extra: map[string]bool{"image/gore": true}},
+		CategoryScores: ModerationCategoryScores{Hate: 0.9, Violence: 0.2},
+	})
+	summary.Add(&ModerationResult{
+		CategoryScores: ModerationCategoryScores{Hate: 0.1, Violence: 0.4},
+	})
+
+	assert.Equal(t, 2, summary.Checked)
+	assert.Equal(t, 1, summary.Flagged)
+	assert.Equal(t, map[string]int{CategoryHate: 1, "image/gore": 1}, summary.Categories)
+	assert.Equal(t, 0.9, summary.MaxScores[CategoryHate])
+	assert.Equal(t, 0.4, summary.MaxScores[CategoryViolence])
+}
+
+func TestManyError(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, NewManyError(nil))
+
+	cause := errors.New("unavailable")
+	var batches []*BatchError
+	for start := 700; start >= 0; start -= 100 {
+		batches = append(batches, &BatchError{Start: start, End: start + 100, Attempts: 3, Err: cause})
+	}
+
+	err := NewManyError(batches)
+	var manyErr *ManyError
+	require.ErrorAs(t, err, &manyErr)
+	assert.Equal(t, 0, manyErr.Batches[0].Start, "batches are sorted")
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, fmt.Sprintf("moderation: 8 batch(es) failed: %s; %s; %s; %s; %s; and 3 more",
+		"texts 0-99 failed after 3 attempt(s): unavailable",
+		"texts 100-199 failed after 3 attempt(s): unavailable",
+		"texts 200-299 failed after 3 attempt(s): unavailable",
+		"texts 300-399 failed after 3 attempt(s): unavailable",
+		"texts 400-499 failed after 3 attempt(s): unavailable",
+	), err.Error())
+}
@@ -96,6 +96,10 @@ type ModerationCategories struct {
 
 	// ViolenceGraphic: Content that depicts violence or injury in graphic detail.
 	ViolenceGraphic bool `json:"violence/graphic"`
+
+	// Extra holds categories returned by the API that have no field above,
+	// such as image-specific categories.
+	Extra map[string]bool `json:"-"`
 }
 
 // ModerationCategoryScores contains confidence scores for each moderation category.
@@ -132,6 +136,9 @@ type ModerationCategoryScores struct {
 
 	// ViolenceGraphic score (0.0 to 1.0)
 	ViolenceGraphic float64 `json:"violence/graphic"`
+
+	// Extra holds scores for categories that have no field above.
+	Extra map[string]float64 `json:"-"`
 }
 
 // GetResults returns the moderation results.
@@ -0,0 +1,154 @@
+package moderation
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// InputType is the type of a multimodal moderation input.
+type InputType string
+
+const (
+	// InputTypeText is a text input.
+	InputTypeText InputType = "text"
+
+	// InputTypeImageURL is an image given by URL or base64 data URL.
+	InputTypeImageURL InputType = "image_url"
+)
+
+// Input is a single item of multimodal moderation input.
+type Input struct {
+	// Type is the input type.
+	Type InputType `json:"type"`
+
+	// Text is the text to moderate, for text inputs.
+	Text string `json:"text,omitempty"`
+
+	// ImageURL is the image to moderate, for image inputs.
+	ImageURL *ImageURL `json:"image_url,omitempty"`
+}
+
+// ImageURL references an image to moderate.
+type ImageURL struct {
+	// URL is an HTTP(S) URL or a base64 data URL
+	// ("data:image/png;base64,...").
+	URL string `json:"url"`
+}
+
+// NewTextInput creates a text input.
+func NewTextInput(text string) Input {
+	return Input{Type: InputTypeText, Text: text}
+}
+
+// NewImageURLInput creates an image input from an HTTP(S) URL.
+//
+// Example:
+//
+//	input := moderation.NewImageURLInput("https://example.com/upload.png")
+func NewImageURLInput(url string) Input {
+	return Input{Type: InputTypeImageURL, ImageURL: &ImageURL{URL: url}}
+}
+
+// NewImageBase64Input creates an image input from base64-encoded data with
+// the given MIME type, such as "image/png".
+//
+// Example:
+//
+//	data := base64.StdEncoding.EncodeToString(imageBytes)
+//	input := moderation.NewImageBase64Input("image/jpeg", data)
+func NewImageBase64Input(mimeType, data string) Input {
+	return NewImageURLInput("data:" + mimeType + ";base64," + data)
+}
+
+// NewImageBytesInput creates an image input from raw image bytes with the
+// given MIME type.
+func NewImageBytesInput(mimeType string, data []byte) Input {
+	return NewImageBase64Input(mimeType, base64.StdEncoding.EncodeToString(data))
+}
+
+// Validate checks that the input is well-formed.
+func (in Input) Validate() error {
+	switch in.Type {
+	case InputTypeText:
+		if in.Text == "" {
+			return errors.NewValidationError("text", "is required for text input", nil)
+		}
+	case InputTypeImageURL:
+		if in.ImageURL == nil || in.ImageURL.URL == "" {
+			return errors.NewValidationError("image_url", "is required for image input", nil)
+		}
+		url := in.ImageURL.URL
+		if strings.HasPrefix(url, "data:") {
+			header, _, ok := strings.Cut(url, ",")
+			if !ok || !strings.HasSuffix(header, ";base64") {
+				return errors.NewValidationError("image_url", "data URL must be base64 encoded", nil)
+			}
+			mimeType := strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+			if !strings.HasPrefix(mimeType, "image/") {
+				return errors.NewValidationError("image_url", "MIME type must be an image type", mimeType)
+			}
+		}
+	default:
+		return errors.NewValidationError("type", "unsupported input type", in.Type)
+	}
+	return nil
+}
+
+// NewImageModerationRequest creates a moderation request for a single
+// image given by URL.
+//
+// Example:
+//
+//	req := moderation.NewImageModerationRequest("moderation", "https://example.com/upload.png")
+func NewImageModerationRequest(model string, imageURL string) *ModerationRequest {
+	return &ModerationRequest{
+		Model: model,
+		Input: NewImageURLInput(imageURL),
+	}
+}
+
+// NewBase64ImageModerationRequest creates a moderation request for a single
+// base64-encoded image with the given MIME type.
+func NewBase64ImageModerationRequest(model, mimeType, data string) *ModerationRequest {
+	return &ModerationRequest{
+		Model: model,
+		Input: NewImageBase64Input(mimeType, data),
+	}
+}
+
+// NewMultimodalModerationRequest creates a moderation request for a batch
+// of text and image inputs. Results are returned in input order.
+//
+// Example:
+//
+//	req := moderation.NewMultimodalModerationRequest("moderation",
+//	    moderation.NewTextInput(caption),
+//	    moderation.NewImageURLInput(uploadURL),
+//	)
+func NewMultimodalModerationRequest(model string, inputs ...Input) *ModerationRequest {
+	return &ModerationRequest{
+		Model: model,
+		Input: inputs,
+	}
+}
+
+// Validate checks typed multimodal inputs. Untyped inputs are passed
+// through to the API unchanged.
+func (r *ModerationRequest) Validate() error {
+	switch input := r.Input.(type) {
+	case Input:
+		return input.Validate()
+	case []Input:
+		if len(input) == 0 {
+			return errors.NewValidationError("input", "must not be empty", nil)
+		}
+		for _, in := range input {
+			if err := in.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package moderation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewImageModerationRequest(t *testing.T) {
+	t.Parallel()
+
+	req := NewImageModerationRequest("moderation", "https://example.com/a.png")
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "moderation",
+		"input": {"type": "image_url", "image_url": {"url": "https://example.com/a.png"}}
+	}`, string(data))
+	assert.NoError(t, req.Validate())
+}
+
+func TestNewImageBase64Input(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "data:image/png;base64,aGk=", NewImageBase64Input("image/png", "aGk=").ImageURL.URL)
+	assert.Equal(t, "data:image/jpeg;base64,aGk=", NewImageBytesInput("image/jpeg", []byte("hi")).ImageURL.URL)
+
+	req := NewBase64ImageModerationRequest("moderation", "image/webp", "aGk=")
+	assert.Equal(t, NewImageBase64Input("image/webp", "aGk="), req.Input)
+}
+
+func TestNewMultimodalModerationRequest_WireFormat(t *testing.T) {
+	t.Parallel()
+
+	req := NewMultimodalModerationRequest("moderation",
+		NewTextInput("caption"),
+		NewImageURLInput("https://example.com/a.png"),
+		NewImageBase64Input("image/png", "aGk="),
+	)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "moderation",
+		"input": [
+			{"type": "text", "text": "caption"},
+			{"type": "image_url", "image_url": {"url": "https://example.com/a.png"}},
+			{"type": "image_url", "image_url": {"url": "data:image/png;base64,aGk="}}
+		]
+	}`, string(data))
+	assert.NoError(t, req.Validate())
+}
+
+func TestModerationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		req     *ModerationRequest
+		wantErr bool
+	}{
+		{"untyped text", NewTextModerationRequest("moderation", "hi"), false},
+		{"untyped batch", NewBatchTextModerationRequest("moderation", []string{"a"}), false},
+		{"empty batch", NewMultimodalModerationRequest("moderation"), true},
+		{"empty text", NewMultimodalModerationRequest("moderation", NewTextInput("")), true},
+		{"empty image", NewImageModerationRequest("moderation", ""), true},
+		{"non-image MIME type", NewBase64ImageModerationRequest("moderation", "text/plain", "aGk="), true},
+		{"data URL without base64", NewImageModerationRequest("moderation", "data:image/png,raw"), true},
+		{"unknown type", NewMultimodalModerationRequest("moderation", Input{Type: "audio"}), true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
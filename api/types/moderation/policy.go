@@ -0,0 +1,125 @@
+package moderation
+
+import "sort"
+
+// DefaultModel is the moderation model used by a Policy without a Model.
+const DefaultModel = "moderation"
+
+// DefaultChunkSize is the number of characters of streamed output
+// screened at once by a Policy without a ChunkSize.
+const DefaultChunkSize = 1000
+
+// Stages of a moderated chat call, reported by errors.ContentFlaggedError.
+const (
+	// StageInput is the screening of the user prompt.
+	StageInput = "input"
+
+	// StageOutput is the screening of the model output.
+	StageOutput = "output"
+)
+
+// Category names, as returned by the API.
+const (
+	CategoryHarassment            = "harassment"
+	CategoryHarassmentThreatening = "harassment/threatening"
+	CategoryHate                  = "hate"
+	CategoryHateThreatening       = "hate/threatening"
+	CategorySelfHarm              = "self-harm"
+	CategorySelfHarmInstructions  = "self-harm/instructions"
+	CategorySelfHarmIntent        = "self-harm/intent"
+	CategorySexual                = "sexual"
+	CategorySexualMinors          = "sexual/minors"
+	CategoryViolence              = "violence"
+	CategoryViolenceGraphic       = "violence/graphic"
+)
+
+// InputScope selects the user content of a chat request that is screened.
+type InputScope int
+
+const (
+	// InputLastUserMessage screens the last user message of the request.
+	InputLastUserMessage InputScope = iota
+
+	// InputAllUserMessages screens every user message of the request.
+	// History loaded from a request memory is not screened again.
+	InputAllUserMessages
+)
+
+// Policy decides which moderation results block a chat call.
+//
+// A category exceeds the policy when its score reaches its threshold in
+// Thresholds, or DefaultThreshold for categories not listed there, if
+// positive. A policy without any threshold blocks the categories the API
+// flags.
+//
+// Example:
+//
+//	policy := moderation.Policy{
+//	    Thresholds: map[string]float64{
+//	        moderation.CategorySexualMinors: 0.01,
+//	        moderation.CategoryViolence:     0.7,
+//	    },
+//	    DefaultThreshold: 0.9,
+//	    ScreenOutput:     true,
+//	}
+type Policy struct {
+	// Model is the moderation model. If empty, uses DefaultModel.
+	Model string
+
+	// Thresholds maps category names to the score at or above which
+	// content is blocked. A threshold of zero or less leaves the category
+	// unchecked, overriding DefaultThreshold.
+	Thresholds map[string]float64
+
+	// DefaultThreshold is the threshold of categories not in Thresholds.
+	// Zero or less leaves them unchecked.
+	DefaultThreshold float64
+
+	// Input selects the user content that is screened before the call.
+	Input InputScope
+
+	// ScreenOutput also screens the model output before returning it.
+	ScreenOutput bool
+
+	// ChunkSize is the number of characters of streamed output screened at
+	// once; chunks are held back until their text passes. If zero or
+	// less, uses DefaultChunkSize.
+	ChunkSize int
+}
+
+// ModelOrDefault returns the moderation model of the policy.
+func (p Policy) ModelOrDefault() string {
+	if p.Model == "" {
+		return DefaultModel
+	}
+	return p.Model
+}
+
+// ChunkSizeOrDefault returns the streamed output chunk size of the policy.
+func (p Policy) ChunkSizeOrDefault() int {
+	if p.ChunkSize <= 0 {
+		return DefaultChunkSize
+	}
+	return p.ChunkSize
+}
+
+// Violations returns the sorted names of the categories of result that
+// exceed the policy, or nil if the result passes.
+func (p Policy) Violations(result *ModerationResult) []string {
+	if len(p.Thresholds) == 0 && p.DefaultThreshold <= 0 {
+		return result.Categories.Flagged()
+	}
+
+	var names []string
+	for name, score := range result.CategoryScores.AsMap() {
+		threshold, ok := p.Thresholds[name]
+		if !ok {
+			threshold = p.DefaultThreshold
+		}
+		if threshold > 0 && score >= threshold {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Violations(t *testing.T) {
+	t.Parallel()
+
+	var result ModerationResult
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"flagged": true,
+		"categories": {"violence": true, "hate": false, "sexual/image": false},
+		"category_scores": {"violence": 0.85, "hate": 0.4, "harassment": 0.1, "sexual/image": 0.6}
+	}`), &result))
+
+	tests := []struct {
+		name   string
+		policy Policy
+		want   []string
+	}{
+		{"flags without thresholds", Policy{}, []string{CategoryViolence}},
+		{"category threshold", Policy{
+			Thresholds: map[string]float64{CategoryHate: 0.3},
+		}, []string{CategoryHate}},
+		{"threshold is inclusive", Policy{
+			Thresholds: map[string]float64{CategoryHate: 0.4},
+		}, []string{CategoryHate}},
+		{"default threshold", Policy{DefaultThreshold: 0.5}, []string{"sexual/image", CategoryViolence}},
+		{"category overrides default", Policy{
+			Thresholds:       map[string]float64{CategoryViolence: 0.9, CategoryHarassment: 0.05},
+			DefaultThreshold: 0.3,
+		}, []string{CategoryHarassment, CategoryHate, "sexual/image"}},
+		{"zero threshold disables category", Policy{
+			Thresholds:       map[string]float64{CategoryViolence: 0},
+			DefaultThreshold: 0.8,
+		}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.policy.Violations(&result))
+		})
+	}
+}
+
+func TestPolicy_Defaults(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultModel, Policy{}.ModelOrDefault())
+	assert.Equal(t, "moderation-2", Policy{Model: "moderation-2"}.ModelOrDefault())
+	assert.Equal(t, DefaultChunkSize, Policy{}.ChunkSizeOrDefault())
+	assert.Equal(t, DefaultChunkSize, Policy{ChunkSize: -1}.ChunkSizeOrDefault())
+	assert.Equal(t, 200, Policy{ChunkSize: 200}.ChunkSizeOrDefault())
+	assert.Equal(t, InputLastUserMessage, Policy{}.Input)
+}
@@ -9,16 +9,27 @@ type ToolType string
 const (
 	// ToolTypeHandWrite is the handwriting recognition tool.
 	ToolTypeHandWrite ToolType = "hand_write"
+
+	// ToolTypeGeneral is the general printed-text recognition tool.
+	ToolTypeGeneral ToolType = "general"
+
+	// ToolTypeTable is the table recognition tool.
+	ToolTypeTable ToolType = "table"
 )
 
 // OCRRequest represents a request for OCR processing.
 type OCRRequest struct {
-	// File is the image file to process (required).
+	// File is the image file to process.
+	// Either File or ImageURL is required.
 	File io.Reader
 
-	// FileName is the name of the file being uploaded (required).
+	// FileName is the name of the file being uploaded (required with File).
 	FileName string
 
+	// ImageURL is the URL of the image to process.
+	// Either File or ImageURL is required.
+	ImageURL string
+
 	// ToolType specifies the OCR tool to use (required).
 	ToolType ToolType
 
@@ -38,6 +49,24 @@ func NewOCRRequest(file io.Reader, fileName string, toolType ToolType) *OCRReque
 	}
 }
 
+// NewOCRRequestFromURL creates a new OCR request for an image URL.
+//
+// Example:
+//
+//	req := ocr.NewOCRRequestFromURL("https://bucket.example.com/invoice.png", ocr.ToolTypeTable)
+func NewOCRRequestFromURL(imageURL string, toolType ToolType) *OCRRequest {
+	return &OCRRequest{
+		ImageURL: imageURL,
+		ToolType: toolType,
+	}
+}
+
+// SetImageURL sets the image URL to process instead of uploading a file.
+func (r *OCRRequest) SetImageURL(imageURL string) *OCRRequest {
+	r.ImageURL = imageURL
+	return r
+}
+
 // SetLanguageType sets the language type.
 func (r *OCRRequest) SetLanguageType(languageType string) *OCRRequest {
 	r.LanguageType = languageType
@@ -105,6 +134,12 @@ type OCRResponse struct {
 
 	// WordsResult contains the recognition results.
 	WordsResult []WordsResult `json:"words_result,omitempty"`
+
+	// TablesResultNum is the number of recognized tables.
+	TablesResultNum int `json:"tables_result_num,omitempty"`
+
+	// TablesResult contains the recognized tables (table tool only).
+	TablesResult []TableResult `json:"tables_result,omitempty"`
 }
 
 // GetResults returns the recognition results.
@@ -121,6 +156,20 @@ func (r *OCRResponse) HasResults() bool {
 	return r.WordsResultNum > 0 && len(r.WordsResult) > 0
 }
 
+// GetTables returns the recognized tables.
+// Returns an empty slice if no tables are available.
+func (r *OCRResponse) GetTables() []TableResult {
+	if r.TablesResult == nil {
+		return []TableResult{}
+	}
+	return r.TablesResult
+}
+
+// HasTables returns true if the response contains recognized tables.
+func (r *OCRResponse) HasTables() bool {
+	return len(r.TablesResult) > 0
+}
+
 // GetText returns all recognized text concatenated together.
 func (r *OCRResponse) GetText() string {
 	if !r.HasResults() {
@@ -41,6 +41,35 @@ func TestToolType_Constants(t *testing.T) {
 	t.Parallel()
 
 	assert.Equal(t, ToolType("hand_write"), ToolTypeHandWrite)
+	assert.Equal(t, ToolType("general"), ToolTypeGeneral)
+	assert.Equal(t, ToolType("table"), ToolTypeTable)
+}
+
+func TestNewOCRRequestFromURL(t *testing.T) {
+	t.Parallel()
+
+	req := NewOCRRequestFromURL("https://bucket.example.com/a.png", ToolTypeGeneral)
+	assert.Nil(t, req.File)
+	assert.Equal(t, "https://bucket.example.com/a.png", req.ImageURL)
+	assert.Equal(t, ToolTypeGeneral, req.ToolType)
+
+	req = NewOCRRequest(nil, "", ToolTypeTable).SetImageURL("https://bucket.example.com/b.png")
+	assert.Equal(t, "https://bucket.example.com/b.png", req.ImageURL)
+}
+
+func TestOCRResponse_GetTables(t *testing.T) {
+	t.Parallel()
+
+	resp := &OCRResponse{}
+	assert.False(t, resp.HasTables())
+	assert.NotNil(t, resp.GetTables())
+	assert.Empty(t, resp.GetTables())
+
+	jsonData := `{"tables_result_num": 1, "tables_result": [{"rows": 1, "cols": 1, "cells": [{"row": 0, "col": 0, "words": "x"}]}]}`
+	require.NoError(t, json.Unmarshal([]byte(jsonData), resp))
+	assert.True(t, resp.HasTables())
+	assert.Equal(t, 1, resp.TablesResultNum)
+	assert.Equal(t, "x", resp.GetTables()[0].Cells[0].Words)
 }
 
 func TestLocation_JSON(t *testing.T) {
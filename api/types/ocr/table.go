@@ -0,0 +1,103 @@
+package ocr
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+// TableCell represents a single cell of a recognized table.
+type TableCell struct {
+	// Row is the zero-based row index of the cell's top-left corner.
+	Row int `json:"row"`
+
+	// Col is the zero-based column index of the cell's top-left corner.
+	Col int `json:"col"`
+
+	// RowSpan is the number of rows the cell spans (1 if not merged).
+	RowSpan int `json:"row_span,omitempty"`
+
+	// ColSpan is the number of columns the cell spans (1 if not merged).
+	ColSpan int `json:"col_span,omitempty"`
+
+	// Words is the recognized text of the cell.
+	Words string `json:"words"`
+
+	// Location is the bounding box of the cell.
+	Location *Location `json:"location,omitempty"`
+}
+
+// rowSpan returns the row span, treating 0 as 1.
+func (c *TableCell) rowSpan() int {
+	if c.RowSpan < 1 {
+		return 1
+	}
+	return c.RowSpan
+}
+
+// colSpan returns the column span, treating 0 as 1.
+func (c *TableCell) colSpan() int {
+	if c.ColSpan < 1 {
+		return 1
+	}
+	return c.ColSpan
+}
+
+// IsMerged returns true if the cell spans more than one row or column.
+func (c *TableCell) IsMerged() bool {
+	return c.rowSpan() > 1 || c.colSpan() > 1
+}
+
+// TableResult represents a recognized table.
+type TableResult struct {
+	// Rows is the number of rows in the table.
+	Rows int `json:"rows"`
+
+	// Cols is the number of columns in the table.
+	Cols int `json:"cols"`
+
+	// Cells contains the table cells. Merged cells appear once, at their
+	// top-left position, with RowSpan/ColSpan set.
+	Cells []TableCell `json:"cells"`
+
+	// Location is the bounding box of the table.
+	Location *Location `json:"location,omitempty"`
+}
+
+// Grid returns the table as a rows x columns matrix of cell text.
+// A merged cell's text is placed at its top-left position; the other
+// positions it covers are left empty.
+func (t *TableResult) Grid() [][]string {
+	rows, cols := t.Rows, t.Cols
+	for i := range t.Cells {
+		c := &t.Cells[i]
+		if end := c.Row + c.rowSpan(); end > rows {
+			rows = end
+		}
+		if end := c.Col + c.colSpan(); end > cols {
+			cols = end
+		}
+	}
+
+	grid := make([][]string, rows)
+	for i := range grid {
+		grid[i] = make([]string, cols)
+	}
+	for _, c := range t.Cells {
+		if c.Row < 0 || c.Col < 0 {
+			continue
+		}
+		grid[c.Row][c.Col] = c.Words
+	}
+	return grid
+}
+
+// ToCSV returns the table as CSV text, one record per row.
+// Merged cells are written as described in Grid.
+func (t *TableResult) ToCSV() (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(t.Grid()); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
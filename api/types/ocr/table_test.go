@@ -0,0 +1,83 @@
+package ocr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableCell_IsMerged(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, (&TableCell{}).IsMerged())
+	assert.False(t, (&TableCell{RowSpan: 1, ColSpan: 1}).IsMerged())
+	assert.True(t, (&TableCell{RowSpan: 2}).IsMerged())
+	assert.True(t, (&TableCell{ColSpan: 3}).IsMerged())
+}
+
+func TestTableResult_Grid(t *testing.T) {
+	t.Parallel()
+
+	table := &TableResult{
+		Rows: 3,
+		Cols: 3,
+		Cells: []TableCell{
+			{Row: 0, Col: 0, ColSpan: 3, Words: "Header"},
+			{Row: 1, Col: 0, RowSpan: 2, Words: "Group"},
+			{Row: 1, Col: 1, Words: "a"},
+			{Row: 1, Col: 2, Words: "b"},
+			{Row: 2, Col: 1, Words: "c"},
+			{Row: 2, Col: 2, Words: "d"},
+		},
+	}
+
+	assert.Equal(t, [][]string{
+		{"Header", "", ""},
+		{"Group", "a", "b"},
+		{"", "c", "d"},
+	}, table.Grid())
+}
+
+func TestTableResult_Grid_ExpandsToCells(t *testing.T) {
+	t.Parallel()
+
+	table := &TableResult{
+		Cells: []TableCell{
+			{Row: 0, Col: 0, Words: "a"},
+			{Row: 1, Col: 1, ColSpan: 2, Words: "b"},
+		},
+	}
+
+	grid := table.Grid()
+	require.Len(t, grid, 2)
+	assert.Len(t, grid[0], 3)
+	assert.Equal(t, "b", grid[1][1])
+}
+
+func TestTableResult_ToCSV(t *testing.T) {
+	t.Parallel()
+
+	table := &TableResult{
+		Rows: 2,
+		Cols: 2,
+		Cells: []TableCell{
+			{Row: 0, Col: 0, Words: "Name"},
+			{Row: 0, Col: 1, Words: "Note"},
+			{Row: 1, Col: 0, Words: "Widget"},
+			{Row: 1, Col: 1, Words: `says "hi", twice`},
+		},
+	}
+
+	csvText, err := table.ToCSV()
+	require.NoError(t, err)
+	assert.Equal(t, "Name,Note\nWidget,\"says \"\"hi\"\", twice\"\n", csvText)
+}
+
+func TestTableResult_ToCSV_Empty(t *testing.T) {
+	t.Parallel()
+
+	csvText, err := (&TableResult{}).ToCSV()
+	require.NoError(t, err)
+	assert.Empty(t, csvText)
+}
@@ -0,0 +1,203 @@
+package tools
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// tokenProfile holds the parameters of the token estimate for a model
+// family, calibrated against the tokenizer endpoint.
+type tokenProfile struct {
+	// prefixes are the model name prefixes of the family
+	prefixes []string
+
+	// wordChars is the number of letters per token of an ASCII word;
+	// shorter words are a single token with their leading space
+	wordChars int
+
+	// cjkPerToken is the number of Chinese, Japanese and Korean
+	// characters per token
+	cjkPerToken float64
+
+	// otherPerToken is the number of other characters, such as accented
+	// or Cyrillic letters, per token
+	otherPerToken float64
+
+	// requestOverhead is the number of tokens of the prompt template
+	requestOverhead int
+
+	// messageOverhead is the number of tokens each message adds for its
+	// role and separators
+	messageOverhead int
+
+	// toolOverhead is the number of tokens each tool definition or tool
+	// call adds besides its JSON
+	toolOverhead int
+}
+
+// defaultTokenProfile applies to the GLM-4 models and to unknown models.
+var defaultTokenProfile = tokenProfile{
+	wordChars:       9,
+	cjkPerToken:     1.35,
+	otherPerToken:   1.0,
+	requestOverhead: 3,
+	messageOverhead: 2,
+	toolOverhead:    4,
+}
+
+// tokenProfiles are the families whose tokenizer differs from the default.
+var tokenProfiles = []tokenProfile{
+	{
+		prefixes:        []string{"glm-4.5", "glm-4.6", "glm-4.7"},
+		wordChars:       9,
+		cjkPerToken:     1.6,
+		otherPerToken:   1.0,
+		requestOverhead: 3,
+		messageOverhead: 2,
+		toolOverhead:    4,
+	},
+}
+
+// profileFor returns the token profile of model.
+func profileFor(model string) tokenProfile {
+	model = strings.ToLower(model)
+	for _, profile := range tokenProfiles {
+		for _, prefix := range profile.prefixes {
+			if strings.HasPrefix(model, prefix) {
+				return profile
+			}
+		}
+	}
+	return defaultTokenProfile
+}
+
+// EstimateTokens returns an offline estimate of the prompt tokens of
+// messages for model, without calling the tokenizer endpoint. The estimate
+// counts Latin words and CJK characters at the ratios of the model family,
+// plus the overhead of each message, and is usually within 10% of the exact
+// count for text. Images and videos are not counted.
+//
+// Example:
+//
+//	if tools.EstimateTokens("glm-4.7", messages) > 100_000 {
+//	    // Truncate the history first
+//	}
+func EstimateTokens(model string, messages []chat.Message) int {
+	return EstimateRequestTokens(NewTokenizerRequest(model, messages))
+}
+
+// EstimateRequestTokens is like EstimateTokens, but also counts the tool
+// definitions of req.
+func EstimateRequestTokens(req *TokenizerRequest) int {
+	profile := profileFor(req.Model)
+
+	var tokens float64
+	for _, msg := range req.Messages {
+		tokens += float64(profile.messageOverhead)
+		tokens += profile.text(msg.Name)
+		tokens += profile.text(msg.Text())
+		tokens += profile.text(msg.ReasoningContent)
+		for _, call := range msg.ToolCalls {
+			tokens += float64(profile.toolOverhead)
+			tokens += profile.text(call.Function.Name)
+			tokens += profile.text(call.Function.Arguments)
+		}
+		if msg.FunctionCall != nil {
+			tokens += float64(profile.toolOverhead)
+			tokens += profile.text(msg.FunctionCall.Name)
+			tokens += profile.text(msg.FunctionCall.Arguments)
+		}
+	}
+	for _, tool := range req.Tools {
+		tokens += float64(profile.toolOverhead)
+		if data, err := json.Marshal(tool); err == nil {
+			tokens += profile.text(string(data))
+		}
+	}
+	if len(req.Messages) > 0 || len(req.Tools) > 0 {
+		tokens += float64(profile.requestOverhead)
+	}
+
+	return int(math.Round(tokens))
+}
+
+// EstimateTextTokens returns an offline estimate of the tokens of text for
+// model, without the overhead of a chat message. It suits inputs that are
+// not messages, such as embedding inputs.
+func EstimateTextTokens(model, text string) int {
+	return int(math.Ceil(profileFor(model).text(text)))
+}
+
+// text estimates the tokens of s. ASCII text is counted by runs: a word
+// takes one token per wordChars letters, a number one per three digits and
+// punctuation one per two characters, while spaces merge into the next run.
+// Other characters are counted at the ratios of their script.
+func (p tokenProfile) text(s string) float64 {
+	var tokens float64
+	cjk, other := 0, 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		if r >= utf8.RuneSelf {
+			if isCJK(r) {
+				cjk++
+			} else {
+				other++
+			}
+			s = s[size:]
+			continue
+		}
+
+		class := asciiClass(r)
+		n := 1
+		for n < len(s) && s[n] < utf8.RuneSelf && asciiClass(rune(s[n])) == class {
+			n++
+		}
+		switch class {
+		case classLetter:
+			tokens += float64((n + p.wordChars - 1) / p.wordChars)
+		case classDigit:
+			tokens += float64((n + 2) / 3)
+		case classSymbol:
+			tokens += float64((n + 1) / 2)
+		case classBreak:
+			tokens++
+		}
+		s = s[n:]
+	}
+	return tokens + float64(cjk)/p.cjkPerToken + float64(other)/p.otherPerToken
+}
+
+// ASCII character classes of tokenProfile.text.
+const (
+	classSpace = iota
+	classLetter
+	classDigit
+	classSymbol
+	classBreak
+)
+
+// asciiClass returns the class of the ASCII character r.
+func asciiClass(r rune) int {
+	switch {
+	case r == ' ':
+		return classSpace
+	case r == '\n' || r == '\t' || r == '\r':
+		return classBreak
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		return classLetter
+	case '0' <= r && r <= '9':
+		return classDigit
+	default:
+		return classSymbol
+	}
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
@@ -0,0 +1,124 @@
+package tools_test
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// record refreshes the counts of the calibration samples from the tokenizer
+// endpoint, using the client configuration from the environment:
+//
+//	ZAI_API_KEY=... go test ./api/types/tools -run Calibration -record
+var record = flag.Bool("record", false, "record exact token counts in testdata from the tokenizer endpoint")
+
+// estimateTolerance is the largest relative error of the estimate accepted
+// for a calibration sample.
+const estimateTolerance = 0.10
+
+// calibrationSample is a request of testdata/tokenizer_calibration.json
+// with the prompt tokens the tokenizer endpoint counts for it.
+type calibrationSample struct {
+	Name         string         `json:"name"`
+	Model        string         `json:"model"`
+	PromptTokens int            `json:"prompt_tokens"`
+	Messages     []chat.Message `json:"messages"`
+	Tools        []chat.Tool    `json:"tools,omitempty"`
+}
+
+func (s calibrationSample) request() *tools.TokenizerRequest {
+	return tools.NewTokenizerRequest(s.Model, s.Messages).SetTools(s.Tools)
+}
+
+func TestEstimateRequestTokens_Calibration(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join("testdata", "tokenizer_calibration.json")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var samples []calibrationSample
+	require.NoError(t, json.Unmarshal(data, &samples))
+	require.NotEmpty(t, samples)
+
+	if *record {
+		recordCounts(t, path, samples)
+	}
+
+	for _, sample := range samples {
+		t.Run(sample.Name, func(t *testing.T) {
+			estimate := tools.EstimateRequestTokens(sample.request())
+			assert.InEpsilon(t, sample.PromptTokens, estimate, estimateTolerance,
+				"estimated %d tokens, the tokenizer counts %d", estimate, sample.PromptTokens)
+		})
+	}
+}
+
+// recordCounts sets the counts of samples from the tokenizer endpoint and
+// rewrites the calibration file.
+func recordCounts(t *testing.T, path string, samples []calibrationSample) {
+	client, err := zai.NewClientFromEnv()
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i, sample := range samples {
+		n, err := client.Tools.CountTokens(context.Background(), sample.request(), zai.ModeExact)
+		require.NoError(t, err, sample.Name)
+		samples[i].PromptTokens = n
+	}
+
+	data, err := json.MarshalIndent(samples, "", "  ")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(data, '\n'), 0o644))
+}
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+
+	assert.Zero(t, tools.EstimateTokens("glm-4.7", nil))
+
+	messages := []chat.Message{chat.NewUserMessage("Hello")}
+	base := tools.EstimateTokens("glm-4.7", messages)
+	assert.Equal(t, 6, base, "template, role and one word")
+
+	// Longer words take more tokens
+	assert.Equal(t, base+1, tools.EstimateTokens("glm-4.7", []chat.Message{chat.NewUserMessage("Understanding")}))
+
+	// Content parts are counted by their text
+	parts := []chat.Message{{Role: chat.RoleUser, Content: []chat.ContentPart{
+		chat.NewTextContentPart("Hello"),
+		chat.NewImageContentPart("https://example.com/cat.png"),
+	}}}
+	assert.Equal(t, base, tools.EstimateTokens("glm-4.7", parts))
+
+	// CJK text is denser with the newer tokenizer
+	chinese := []chat.Message{chat.NewUserMessage("人工智能是计算机科学的一个分支")}
+	assert.Less(t, tools.EstimateTokens("glm-4.7", chinese), tools.EstimateTokens("glm-4-plus", chinese))
+
+	// Tool definitions add to the estimate
+	req := tools.NewTokenizerRequest("glm-4.7", messages).SetTools([]chat.Tool{
+		chat.NewFunctionTool("get_weather", "Get the weather", map[string]any{"type": "object"}),
+	})
+	assert.Greater(t, tools.EstimateRequestTokens(req), base)
+}
+
+func TestEstimateTextTokens(t *testing.T) {
+	t.Parallel()
+
+	assert.Zero(t, tools.EstimateTextTokens("embedding-3", ""))
+	assert.Equal(t, 1, tools.EstimateTextTokens("embedding-3", "Hello"))
+	assert.Equal(t, 2, tools.EstimateTextTokens("embedding-3", "Hello world"))
+
+	// Without the message overhead
+	messages := []chat.Message{chat.NewUserMessage("Hello world")}
+	assert.Less(t, tools.EstimateTextTokens("glm-4.7", "Hello world"), tools.EstimateTokens("glm-4.7", messages))
+}
@@ -143,13 +143,37 @@ type WebSearchMessage struct {
 	ToolCalls []WebSearchMessageToolCall `json:"tool_calls,omitempty"`
 }
 
+// FinishReason is the reason a web search choice finished, of the same
+// type as the finish reasons of chat completions.
+type FinishReason = chat.FinishReason
+
+// Finish reasons of web search choices.
+const (
+	// FinishReasonStop means the search completed.
+	FinishReasonStop = chat.FinishReasonStop
+
+	// FinishReasonToolCalls means the model stopped to call a tool.
+	FinishReasonToolCalls = chat.FinishReasonToolCalls
+
+	// FinishReasonLength means the output reached the token limit.
+	FinishReasonLength = chat.FinishReasonLength
+
+	// FinishReasonSensitive means the content was blocked by the content
+	// safety review.
+	FinishReasonSensitive = chat.FinishReasonSensitive
+
+	// FinishReasonNetworkError means the search failed with an error of
+	// the model service.
+	FinishReasonNetworkError = chat.FinishReasonNetworkError
+)
+
 // WebSearchChoice represents a choice in the web search response.
 type WebSearchChoice struct {
 	// Index is the choice index.
 	Index int `json:"index"`
 
 	// FinishReason is the reason why generation finished.
-	FinishReason string `json:"finish_reason"`
+	FinishReason FinishReason `json:"finish_reason"`
 
 	// Message contains the response message.
 	Message WebSearchMessage `json:"message"`
@@ -254,8 +278,9 @@ type WebSearchStreamChoice struct {
 	// Index is the index of this choice in the response.
 	Index int `json:"index"`
 
-	// FinishReason is the reason why the generation finished.
-	FinishReason string `json:"finish_reason,omitempty"`
+	// FinishReason is the reason why the generation finished. It is empty
+	// on all but the last chunk of the choice.
+	FinishReason FinishReason `json:"finish_reason,omitempty"`
 
 	// Delta contains the delta changes for this choice.
 	Delta ChoiceDelta `json:"delta"`
@@ -269,6 +294,9 @@ type WebSearchChunk struct {
 	// Created is the timestamp when the chunk was created.
 	Created int64 `json:"created,omitempty"`
 
+	// RequestID is the request identifier.
+	RequestID string `json:"request_id,omitempty"`
+
 	// Choices contains the list of choices in this chunk.
 	Choices []WebSearchStreamChoice `json:"choices"`
 }
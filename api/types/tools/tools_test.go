@@ -143,7 +143,7 @@ func TestWebSearchResponse(t *testing.T) {
 		choices := resp.GetChoices()
 		assert.Len(t, choices, 1)
 		assert.Equal(t, 0, choices[0].Index)
-		assert.Equal(t, "stop", choices[0].FinishReason)
+		assert.Equal(t, FinishReasonStop, choices[0].FinishReason)
 		assert.Equal(t, "assistant", choices[0].Message.Role)
 
 		// Check tool calls
@@ -335,7 +335,7 @@ func TestWebSearchChunk(t *testing.T) {
 
 		assert.Equal(t, "ws_chunk_456", chunk.ID)
 		assert.Len(t, chunk.Choices, 1)
-		assert.Equal(t, "stop", chunk.Choices[0].FinishReason)
+		assert.Equal(t, FinishReasonStop, chunk.Choices[0].FinishReason)
 
 		toolCall := chunk.Choices[0].Delta.ToolCalls[0]
 		assert.NotNil(t, toolCall.SearchResult)
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"errors"
+	"maps"
+	"slices"
+)
+
+// ErrWebSearchTruncated is returned by WebSearchStreamAggregator.Run when
+// the stream ended before every choice received a finish reason, so the
+// response may be missing results.
+var ErrWebSearchTruncated = errors.New("tools: web search stream ended without a finish reason")
+
+// WebSearchChunkStream is a stream of web search chunks, such as the stream
+// returned by ToolsService.WebSearchStream.
+type WebSearchChunkStream interface {
+	Next() bool
+	Current() *WebSearchChunk
+	Err() error
+	Close() error
+}
+
+// webSearchChoiceState is the state of a choice being aggregated.
+type webSearchChoiceState struct {
+	choice WebSearchChoice
+	// positions maps a tool call index to the position of its last tool
+	// call in choice.Message.ToolCalls.
+	positions map[int]int
+}
+
+// WebSearchStreamAggregator wraps a web search stream and assembles its
+// chunks into the response the non-streaming call would have returned, so
+// that downstream code does not depend on the transport mode. Callbacks
+// report search intents, results and recommendations as they arrive.
+type WebSearchStreamAggregator struct {
+	stream WebSearchChunkStream
+
+	onIntent    func(*SearchIntent)
+	onResult    func(*SearchResult)
+	onRecommend func(*SearchRecommend)
+
+	id        string
+	created   int64
+	requestID string
+	choices   map[int]*webSearchChoiceState
+
+	current *WebSearchChunk
+	ended   bool
+	err     error
+}
+
+// NewWebSearchStreamAggregator creates an aggregator that reads chunks from
+// stream.
+//
+// Example:
+//
+//	stream, err := client.Tools.WebSearchStream(ctx, req)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	agg := tools.NewWebSearchStreamAggregator(stream).
+//	    OnResult(func(r *tools.SearchResult) {
+//	        fmt.Println(r.Title)
+//	    })
+//	defer agg.Close()
+//
+//	resp, err := agg.Run()
+//	if err != nil && !stderrors.Is(err, tools.ErrWebSearchTruncated) {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(len(resp.GetSearchResults()), "results")
+func NewWebSearchStreamAggregator(stream WebSearchChunkStream) *WebSearchStreamAggregator {
+	return &WebSearchStreamAggregator{
+		stream:  stream,
+		choices: make(map[int]*webSearchChoiceState),
+	}
+}
+
+// OnIntent sets a callback called with each streamed search intent.
+func (a *WebSearchStreamAggregator) OnIntent(fn func(*SearchIntent)) *WebSearchStreamAggregator {
+	a.onIntent = fn
+	return a
+}
+
+// OnResult sets a callback called with each streamed search result.
+func (a *WebSearchStreamAggregator) OnResult(fn func(*SearchResult)) *WebSearchStreamAggregator {
+	a.onResult = fn
+	return a
+}
+
+// OnRecommend sets a callback called with each streamed search
+// recommendation.
+func (a *WebSearchStreamAggregator) OnRecommend(fn func(*SearchRecommend)) *WebSearchStreamAggregator {
+	a.onRecommend = fn
+	return a
+}
+
+// Next reads the next chunk, adds it to the response and calls the
+// callbacks. It returns false when the stream is exhausted or an error
+// occurs.
+func (a *WebSearchStreamAggregator) Next() bool {
+	if a.ended || a.err != nil {
+		return false
+	}
+	for a.stream.Next() {
+		chunk := a.stream.Current()
+		if chunk == nil {
+			continue
+		}
+		a.add(chunk)
+		a.current = chunk
+		return true
+	}
+	if err := a.stream.Err(); err != nil {
+		a.err = err
+	} else {
+		a.ended = true
+	}
+	return false
+}
+
+// Current returns the current chunk.
+func (a *WebSearchStreamAggregator) Current() *WebSearchChunk {
+	return a.current
+}
+
+// Err returns the error of the underlying stream, if any.
+func (a *WebSearchStreamAggregator) Err() error {
+	return a.err
+}
+
+// Close closes the underlying stream.
+func (a *WebSearchStreamAggregator) Close() error {
+	return a.stream.Close()
+}
+
+// Run reads the rest of the stream and returns the final response. If the
+// stream ended without a finish reason, the response is returned with
+// ErrWebSearchTruncated; if the stream failed, the response so far is
+// returned with the stream's error.
+func (a *WebSearchStreamAggregator) Run() (*WebSearchResponse, error) {
+	for a.Next() {
+	}
+	if a.err != nil {
+		return a.Final(), a.err
+	}
+	if a.Truncated() {
+		return a.Final(), ErrWebSearchTruncated
+	}
+	return a.Final(), nil
+}
+
+// Truncated reports whether the stream ended, without an error, before
+// every choice received a finish reason. A stream without any choice is
+// truncated too.
+func (a *WebSearchStreamAggregator) Truncated() bool {
+	if !a.ended {
+		return false
+	}
+	if len(a.choices) == 0 {
+		return true
+	}
+	for _, state := range a.choices {
+		if state.choice.FinishReason == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Final returns the response assembled from the chunks read so far, in the
+// shape of the non-streaming response. Choices are ordered by index.
+func (a *WebSearchStreamAggregator) Final() *WebSearchResponse {
+	resp := &WebSearchResponse{
+		ID:        a.id,
+		Created:   a.created,
+		RequestID: a.requestID,
+		Choices:   make([]WebSearchChoice, 0, len(a.choices)),
+	}
+	for _, index := range slices.Sorted(maps.Keys(a.choices)) {
+		choice := a.choices[index].choice
+		choice.Message.ToolCalls = slices.Clone(choice.Message.ToolCalls)
+		resp.Choices = append(resp.Choices, choice)
+	}
+	return resp
+}
+
+// add merges chunk into the response and calls the callbacks.
+func (a *WebSearchStreamAggregator) add(chunk *WebSearchChunk) {
+	if a.id == "" {
+		a.id = chunk.ID
+	}
+	if a.created == 0 {
+		a.created = chunk.Created
+	}
+	if a.requestID == "" {
+		a.requestID = chunk.RequestID
+	}
+
+	for _, delta := range chunk.Choices {
+		state := a.choices[delta.Index]
+		if state == nil {
+			state = &webSearchChoiceState{
+				choice:    WebSearchChoice{Index: delta.Index},
+				positions: make(map[int]int),
+			}
+			a.choices[delta.Index] = state
+		}
+		if delta.Delta.Role != "" {
+			state.choice.Message.Role = delta.Delta.Role
+		}
+		if delta.FinishReason != "" {
+			state.choice.FinishReason = delta.FinishReason
+		}
+		for _, fragment := range delta.Delta.ToolCalls {
+			state.merge(fragment)
+			a.notify(fragment)
+		}
+	}
+}
+
+// merge adds a tool call fragment to the message of the choice. A fragment
+// continues the last tool call with its index unless it carries a
+// different ID or a part that tool call already has.
+func (s *webSearchChoiceState) merge(fragment ChoiceDeltaToolCall) {
+	calls := s.choice.Message.ToolCalls
+	if pos, ok := s.positions[fragment.Index]; ok {
+		call := &calls[pos]
+		continues := (fragment.ID == "" || call.ID == "" || fragment.ID == call.ID) &&
+			(fragment.SearchIntent == nil || call.SearchIntent == nil) &&
+			(fragment.SearchResult == nil || call.SearchResult == nil) &&
+			(fragment.SearchRecommend == nil || call.SearchRecommend == nil)
+		if continues {
+			if call.ID == "" {
+				call.ID = fragment.ID
+			}
+			if call.Type == "" {
+				call.Type = fragment.Type
+			}
+			if fragment.SearchIntent != nil {
+				call.SearchIntent = fragment.SearchIntent
+			}
+			if fragment.SearchResult != nil {
+				call.SearchResult = fragment.SearchResult
+			}
+			if fragment.SearchRecommend != nil {
+				call.SearchRecommend = fragment.SearchRecommend
+			}
+			return
+		}
+	}
+
+	s.positions[fragment.Index] = len(calls)
+	s.choice.Message.ToolCalls = append(calls, WebSearchMessageToolCall{
+		ID:              fragment.ID,
+		Type:            fragment.Type,
+		SearchIntent:    fragment.SearchIntent,
+		SearchResult:    fragment.SearchResult,
+		SearchRecommend: fragment.SearchRecommend,
+	})
+}
+
+// notify calls the callbacks with the parts of fragment.
+func (a *WebSearchStreamAggregator) notify(fragment ChoiceDeltaToolCall) {
+	if fragment.SearchIntent != nil && a.onIntent != nil {
+		a.onIntent(fragment.SearchIntent)
+	}
+	if fragment.SearchResult != nil && a.onResult != nil {
+		a.onResult(fragment.SearchResult)
+	}
+	if fragment.SearchRecommend != nil && a.onRecommend != nil {
+		a.onRecommend(fragment.SearchRecommend)
+	}
+}
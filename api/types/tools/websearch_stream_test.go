@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/golden"
+)
+
+// sliceStream is a WebSearchChunkStream over a fixed list of chunks.
+type sliceStream struct {
+	chunks  []*WebSearchChunk
+	current *WebSearchChunk
+	err     error
+	closed  bool
+}
+
+func (s *sliceStream) Next() bool {
+	if len(s.chunks) == 0 {
+		return false
+	}
+	s.current, s.chunks = s.chunks[0], s.chunks[1:]
+	return true
+}
+
+func (s *sliceStream) Current() *WebSearchChunk { return s.current }
+func (s *sliceStream) Err() error               { return s.err }
+func (s *sliceStream) Close() error             { s.closed = true; return nil }
+
+// fixtureStream returns a stream of the chunks of the captured SSE fixture.
+func fixtureStream(t *testing.T) *sliceStream {
+	t.Helper()
+
+	stream := &sliceStream{}
+	for _, event := range golden.Events(t, "web_search_stream.sse") {
+		var chunk WebSearchChunk
+		require.NoError(t, json.Unmarshal(event, &chunk))
+		stream.chunks = append(stream.chunks, &chunk)
+	}
+	require.Len(t, stream.chunks, 5)
+	return stream
+}
+
+func TestWebSearchStreamAggregator_Fixture(t *testing.T) {
+	t.Parallel()
+
+	var events []string
+	stream := fixtureStream(t)
+	agg := NewWebSearchStreamAggregator(stream).
+		OnIntent(func(i *SearchIntent) { events = append(events, "intent:"+i.Query) }).
+		OnResult(func(r *SearchResult) { events = append(events, "result:"+r.Title) }).
+		OnRecommend(func(r *SearchRecommend) { events = append(events, "recommend:"+r.Query) })
+
+	resp, err := agg.Run()
+	require.NoError(t, err)
+	assert.False(t, agg.Truncated())
+	assert.Equal(t, []string{
+		"intent:Go 1.25 release highlights",
+		"result:Go 1.25 Release Notes",
+		"result:Go 1.25 is released",
+		"recommend:Go 1.25 GOMAXPROCS changes",
+	}, events)
+
+	// The streamed response has the shape of the non-streaming one
+	var want WebSearchResponse
+	require.NoError(t, json.Unmarshal(golden.Read(t, "web_search_response.json"), &want))
+	assert.Equal(t, &want, resp)
+
+	got, err := json.Marshal(agg.Final())
+	require.NoError(t, err)
+	assert.JSONEq(t, string(golden.Read(t, "web_search_response.json")), string(got))
+
+	assert.Equal(t, FinishReasonStop, resp.Choices[0].FinishReason)
+	assert.Len(t, resp.GetSearchIntents(), 1)
+	assert.Len(t, resp.GetSearchResults(), 2)
+	assert.Len(t, resp.GetSearchRecommendations(), 1)
+
+	require.NoError(t, agg.Close())
+	assert.True(t, stream.closed)
+}
+
+func TestWebSearchStreamAggregator_Truncated(t *testing.T) {
+	t.Parallel()
+
+	stream := fixtureStream(t)
+	// Drop the chunk with the finish reason
+	stream.chunks = stream.chunks[:len(stream.chunks)-1]
+
+	agg := NewWebSearchStreamAggregator(stream)
+	assert.False(t, agg.Truncated(), "a stream is not truncated before it ends")
+
+	resp, err := agg.Run()
+	assert.ErrorIs(t, err, ErrWebSearchTruncated)
+	assert.True(t, agg.Truncated())
+	assert.NoError(t, agg.Err())
+	require.Len(t, resp.Choices, 1)
+	assert.Empty(t, resp.Choices[0].FinishReason)
+	assert.Len(t, resp.GetToolCalls(), 4, "the partial response is returned")
+
+	_, err = NewWebSearchStreamAggregator(&sliceStream{}).Run()
+	assert.ErrorIs(t, err, ErrWebSearchTruncated, "an empty stream is truncated")
+}
+
+func TestWebSearchStreamAggregator_StreamError(t *testing.T) {
+	t.Parallel()
+
+	stream := fixtureStream(t)
+	stream.chunks = stream.chunks[:2]
+	stream.err = errors.New("connection reset")
+
+	agg := NewWebSearchStreamAggregator(stream)
+	resp, err := agg.Run()
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, err, agg.Err())
+	assert.False(t, agg.Truncated(), "a failed stream is reported by Err")
+	assert.Len(t, resp.GetToolCalls(), 2)
+	assert.False(t, agg.Next())
+}
+
+func TestWebSearchStreamAggregator_Fragments(t *testing.T) {
+	t.Parallel()
+
+	intent := &SearchIntent{Query: "go generics"}
+	first := &SearchResult{Title: "First"}
+	second := &SearchResult{Title: "Second"}
+	chunk := func(finish FinishReason, calls ...ChoiceDeltaToolCall) *WebSearchChunk {
+		return &WebSearchChunk{
+			ID:      "ws-1",
+			Choices: []WebSearchStreamChoice{{FinishReason: finish, Delta: ChoiceDelta{ToolCalls: calls}}},
+		}
+	}
+
+	stream := &sliceStream{chunks: []*WebSearchChunk{
+		// The ID and type arrive before the intent
+		chunk("", ChoiceDeltaToolCall{Index: 0, ID: "call_1", Type: "search_intent"}),
+		chunk("", ChoiceDeltaToolCall{Index: 0, SearchIntent: intent}),
+		// Results with the same index but without IDs are separate tool calls
+		chunk("", ChoiceDeltaToolCall{Index: 1, Type: "search_result", SearchResult: first}),
+		chunk(FinishReasonSensitive, ChoiceDeltaToolCall{Index: 1, Type: "search_result", SearchResult: second}),
+	}}
+
+	var chunks int
+	agg := NewWebSearchStreamAggregator(stream)
+	for agg.Next() {
+		chunks++
+		assert.Equal(t, "ws-1", agg.Current().ID)
+	}
+	require.NoError(t, agg.Err())
+	assert.Equal(t, 4, chunks)
+	assert.False(t, agg.Truncated())
+
+	resp := agg.Final()
+	assert.Equal(t, FinishReasonSensitive, resp.Choices[0].FinishReason)
+	assert.Equal(t, []WebSearchMessageToolCall{
+		{ID: "call_1", Type: "search_intent", SearchIntent: intent},
+		{Type: "search_result", SearchResult: first},
+		{Type: "search_result", SearchResult: second},
+	}, resp.GetToolCalls())
+}
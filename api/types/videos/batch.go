@@ -0,0 +1,163 @@
+package videos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Defaults for retrieving and waiting for multiple tasks.
+const (
+	// DefaultBatchConcurrency is the default number of tasks retrieved in parallel.
+	DefaultBatchConcurrency = 5
+
+	// DefaultPollInterval is the default wait between status checks.
+	DefaultPollInterval = 5 * time.Second
+
+	// DefaultWaitTimeout is the default time allowed for tasks to finish.
+	DefaultWaitTimeout = 5 * time.Minute
+)
+
+// WaitProgress reports the state of the tasks of a VideosService.WaitForAll
+// call after a round of status checks.
+type WaitProgress struct {
+	// Total is the number of tasks.
+	Total int
+
+	// Completed is the number of tasks that completed successfully.
+	Completed int
+
+	// Failed is the number of tasks whose generation failed.
+	Failed int
+
+	// Errored is the number of tasks whose status could not be retrieved.
+	Errored int
+
+	// Processing is the number of tasks still submitted or processing.
+	Processing int
+}
+
+// BatchConfig holds settings for retrieving and waiting for multiple tasks.
+type BatchConfig struct {
+	// Concurrency is the maximum number of status requests in flight.
+	Concurrency int
+
+	// PollInterval is the longest wait between status checks of
+	// WaitForAll, which backs off exponentially up to it.
+	PollInterval time.Duration
+
+	// Timeout is the time WaitForAll allows the tasks to finish.
+	Timeout time.Duration
+
+	// OnProgress, if set, is called by WaitForAll after each round of
+	// status checks. Calls are never concurrent.
+	OnProgress func(WaitProgress)
+}
+
+// BatchOption configures retrieving and waiting for multiple tasks.
+type BatchOption func(*BatchConfig)
+
+// NewBatchConfig creates a batch configuration with the given options applied.
+func NewBatchConfig(opts ...BatchOption) *BatchConfig {
+	cfg := &BatchConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultBatchConcurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWaitTimeout
+	}
+	return cfg
+}
+
+// WithConcurrency sets the maximum number of status requests in flight.
+func WithConcurrency(n int) BatchOption {
+	return func(c *BatchConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithPollInterval sets the longest wait between status checks.
+func WithPollInterval(interval time.Duration) BatchOption {
+	return func(c *BatchConfig) {
+		c.PollInterval = interval
+	}
+}
+
+// WithTimeout sets the time allowed for the tasks to finish.
+func WithTimeout(timeout time.Duration) BatchOption {
+	return func(c *BatchConfig) {
+		c.Timeout = timeout
+	}
+}
+
+// WithProgress sets a callback called after each round of status checks.
+//
+// Example:
+//
+//	videos.WithProgress(func(p videos.WaitProgress) {
+//	    fmt.Printf("%d/%d done, %d failed\n", p.Completed, p.Total, p.Failed)
+//	})
+func WithProgress(fn func(WaitProgress)) BatchOption {
+	return func(c *BatchConfig) {
+		c.OnProgress = fn
+	}
+}
+
+// BatchError reports the tasks whose status could not be retrieved.
+type BatchError struct {
+	// Errors maps each failed task ID to its error.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("%s: %v", id, e.Errors[id]))
+	}
+	return fmt.Sprintf("videos: %d task(s) failed: %s", len(ids), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-task errors.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// TimeoutError is returned by WaitForAll when some tasks are still
+// processing after the wait timeout. It matches context.DeadlineExceeded
+// with errors.Is.
+type TimeoutError struct {
+	// Pending lists the IDs of the tasks still processing, sorted.
+	Pending []string
+
+	// Timeout is the wait timeout that elapsed.
+	Timeout time.Duration
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("videos: %d task(s) still processing after %s: %s", len(e.Pending), e.Timeout, strings.Join(e.Pending, ", "))
+}
+
+// Is reports whether target is context.DeadlineExceeded.
+func (e *TimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
@@ -0,0 +1,59 @@
+package videos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBatchConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg := NewBatchConfig()
+	assert.Equal(t, DefaultBatchConcurrency, cfg.Concurrency)
+	assert.Equal(t, DefaultPollInterval, cfg.PollInterval)
+	assert.Equal(t, DefaultWaitTimeout, cfg.Timeout)
+	assert.Nil(t, cfg.OnProgress)
+
+	called := false
+	cfg = NewBatchConfig(
+		WithConcurrency(10),
+		WithPollInterval(time.Second),
+		WithTimeout(time.Minute),
+		WithProgress(func(WaitProgress) { called = true }),
+	)
+	assert.Equal(t, 10, cfg.Concurrency)
+	assert.Equal(t, time.Second, cfg.PollInterval)
+	assert.Equal(t, time.Minute, cfg.Timeout)
+	cfg.OnProgress(WaitProgress{})
+	assert.True(t, called)
+
+	// Invalid values fall back to the defaults
+	cfg = NewBatchConfig(WithConcurrency(0), WithPollInterval(-1), WithTimeout(0))
+	assert.Equal(t, DefaultBatchConcurrency, cfg.Concurrency)
+	assert.Equal(t, DefaultPollInterval, cfg.PollInterval)
+	assert.Equal(t, DefaultWaitTimeout, cfg.Timeout)
+}
+
+func TestBatchError(t *testing.T) {
+	t.Parallel()
+
+	errB := errors.New("not found")
+	errA := errors.New("timeout")
+	err := &BatchError{Errors: map[string]error{"task-b": errB, "task-a": errA}}
+
+	assert.Equal(t, "videos: 2 task(s) failed: task-a: timeout; task-b: not found", err.Error())
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+}
+
+func TestTimeoutError(t *testing.T) {
+	t.Parallel()
+
+	err := &TimeoutError{Pending: []string{"task-a", "task-b"}, Timeout: time.Minute}
+	assert.Equal(t, "videos: 2 task(s) still processing after 1m0s: task-a, task-b", err.Error())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,24 @@
+package videos
+
+import "net/url"
+
+// SetCallbackURL sets the URL the API notifies with a signed
+// VideoTaskCompleted event when the task completes, so the task need not
+// be polled. Verify and decode the notification with webhooks.ParseEvent.
+//
+// Example:
+//
+//	req.SetCallbackURL("https://example.com/hooks/zai")
+func (r *VideoGenerationRequest) SetCallbackURL(callbackURL string) *VideoGenerationRequest {
+	r.CallbackURL = callbackURL
+	return r
+}
+
+// validCallbackURL reports whether u is an absolute http or https URL.
+func validCallbackURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return false
+	}
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
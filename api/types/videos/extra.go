@@ -0,0 +1,53 @@
+package videos
+
+import (
+	"encoding/json"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
+
+// SetExtraField sets a top-level request field the SDK has no typed field
+// for, such as a beta parameter. Typed fields take precedence.
+func (r *VideoGenerationRequest) SetExtraField(key string, value any) *VideoGenerationRequest {
+	if r.ExtraFields == nil {
+		r.ExtraFields = make(map[string]any)
+	}
+	r.ExtraFields[key] = value
+	return r
+}
+
+// MarshalJSON encodes the request with ExtraFields merged into the
+// top-level object. Base64 image data is sent in image_url as a data URL.
+func (r VideoGenerationRequest) MarshalJSON() ([]byte, error) {
+	type alias VideoGenerationRequest
+	if r.ImageBase64 != "" {
+		r.ImageURL = r.imageDataURL()
+	}
+	return models.MarshalWithExtra(alias(r), r.ExtraFields)
+}
+
+// Extra returns the response fields the SDK has no typed field for as a
+// JSON object, or nil if there are none.
+func (r *VideoGenerationResponse) Extra() json.RawMessage {
+	return r.extra
+}
+
+// UnmarshalJSON decodes the response and keeps unknown fields for Extra.
+func (r *VideoGenerationResponse) UnmarshalJSON(data []byte) error {
+	type alias VideoGenerationResponse
+	var known alias
+	extra, err := models.UnmarshalWithExtra(data, &known)
+	if err != nil {
+		return err
+	}
+
+	*r = VideoGenerationResponse(known)
+	r.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the response including the fields returned by Extra.
+func (r VideoGenerationResponse) MarshalJSON() ([]byte, error) {
+	type alias VideoGenerationResponse
+	return models.MarshalWithRawExtra(alias(r), r.extra)
+}
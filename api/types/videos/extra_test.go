@@ -0,0 +1,37 @@
+package videos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoGenerationRequest_ExtraFields(t *testing.T) {
+	t.Parallel()
+
+	req := NewTextToVideoRequest(ModelCogVideoX, "a cat").
+		SetExtraField("beta_option", true).
+		SetExtraField("model", "ignored")
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "cogvideox", got["model"], "typed fields take precedence")
+	assert.Equal(t, true, got["beta_option"])
+}
+
+func TestVideoGenerationResponse_Extra(t *testing.T) {
+	t.Parallel()
+
+	var resp VideoGenerationResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"task-1","task_status":"PROCESSING"}`), &resp))
+	assert.JSONEq(t, `{"task_status":"PROCESSING"}`, string(resp.Extra()))
+
+	data, err := json.Marshal(resp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"task-1","task_status":"PROCESSING"}`, string(data))
+}
@@ -0,0 +1,117 @@
+package videos
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// MaxImageBytes is the largest image accepted for image-to-video
+// generation, in bytes.
+const MaxImageBytes = 5 << 20
+
+// SetImageURL sets the URL of the image to animate.
+//
+// Example:
+//
+//	req.SetImageURL("https://example.com/image.jpg")
+func (r *VideoGenerationRequest) SetImageURL(url string) *VideoGenerationRequest {
+	r.ImageURL = url
+	return r
+}
+
+// SetImageBase64 sets the image to animate as base64-encoded data with the
+// given MIME type, such as "image/png", for images that are not publicly
+// reachable. The image is sent in image_url as a data URL. A data URL
+// prefix ("data:image/png;base64,") in data is accepted; its MIME type is
+// used if mimeType is empty.
+//
+// Example:
+//
+//	data := base64.StdEncoding.EncodeToString(imageBytes)
+//	req := videos.NewTextToVideoRequest(videos.ModelCogVideoX2, "The waves roll in").
+//	    SetImageBase64(data, "image/jpeg")
+func (r *VideoGenerationRequest) SetImageBase64(data, mimeType string) *VideoGenerationRequest {
+	if header, rest, ok := strings.Cut(data, ","); ok && strings.HasPrefix(header, "data:") && strings.HasSuffix(header, ";base64") {
+		if mimeType == "" {
+			mimeType = strings.TrimSuffix(strings.TrimPrefix(header, "data:"), ";base64")
+		}
+		data = rest
+	}
+	r.ImageBase64 = data
+	r.ImageMIMEType = mimeType
+	return r
+}
+
+// SetImageBytes sets the image to animate from raw image bytes with the
+// given MIME type. See SetImageBase64.
+func (r *VideoGenerationRequest) SetImageBytes(data []byte, mimeType string) *VideoGenerationRequest {
+	return r.SetImageBase64(base64.StdEncoding.EncodeToString(data), mimeType)
+}
+
+// SetImageFileID sets the image to animate as the ID of a file uploaded
+// with the Files API. The ID is sent in image_file_id.
+//
+// Example:
+//
+//	file, err := client.Files.Upload(ctx, files.NewFileUploadRequest(image, "frame.png", files.PurposeVision))
+//	if err != nil {
+//	    // Handle error
+//	}
+//	req := videos.NewTextToVideoRequest(videos.ModelCogVideoX2, "The waves roll in").
+//	    SetImageFileID(file.ID)
+func (r *VideoGenerationRequest) SetImageFileID(fileID string) *VideoGenerationRequest {
+	r.ImageFileID = fileID
+	return r
+}
+
+// validateImage checks that at most one image source is set and that
+// base64 image data is a well-formed image of at most MaxImageBytes.
+func (r *VideoGenerationRequest) validateImage() error {
+	var sources []string
+	if r.ImageURL != "" {
+		sources = append(sources, "image_url")
+	}
+	if r.ImageBase64 != "" {
+		sources = append(sources, "image_base64")
+	}
+	if r.ImageFileID != "" {
+		sources = append(sources, "image_file_id")
+	}
+	if len(sources) > 1 {
+		return errors.NewValidationError("image",
+			fmt.Sprintf("only one image source may be set, got %s", strings.Join(sources, " and ")), sources)
+	}
+
+	if r.ImageBase64 == "" {
+		if r.ImageMIMEType != "" {
+			return errors.NewValidationError("image_mime_type", "is only used with base64 image data", r.ImageMIMEType)
+		}
+		return nil
+	}
+	if !strings.HasPrefix(r.ImageMIMEType, "image/") {
+		return errors.NewValidationError("image_mime_type", "must be an image type, such as image/png", r.ImageMIMEType)
+	}
+	data, err := base64.StdEncoding.DecodeString(r.ImageBase64)
+	if err != nil {
+		return errors.NewValidationError("image_base64", fmt.Sprintf("invalid base64 data: %v", err), nil)
+	}
+	if len(data) > MaxImageBytes {
+		return NewImageTooLargeError(int64(len(data)), MaxImageBytes)
+	}
+	return nil
+}
+
+// NewImageTooLargeError creates the validation error returned for images
+// larger than limit bytes.
+func NewImageTooLargeError(size, limit int64) *errors.ValidationError {
+	return errors.NewValidationError("image",
+		fmt.Sprintf("exceeds the maximum size of %d bytes", limit), size)
+}
+
+// imageDataURL returns the base64 image data as a data URL.
+func (r *VideoGenerationRequest) imageDataURL() string {
+	return "data:" + r.ImageMIMEType + ";base64," + r.ImageBase64
+}
@@ -0,0 +1,128 @@
+package videos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestVideoGenerationRequest_ImageSources(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\nfake image")
+	encoded := base64.StdEncoding.EncodeToString(png)
+
+	tests := []struct {
+		name string
+		req  *VideoGenerationRequest
+		want string
+	}{
+		{
+			name: "url",
+			req:  NewTextToVideoRequest(ModelCogVideoX2, "waves").SetImageURL("https://example.com/frame.png"),
+			want: `{"model":"cogvideox-2","prompt":"waves","image_url":"https://example.com/frame.png"}`,
+		},
+		{
+			name: "base64",
+			req:  NewTextToVideoRequest(ModelCogVideoX2, "waves").SetImageBase64(encoded, "image/png"),
+			want: `{"model":"cogvideox-2","prompt":"waves","image_url":"data:image/png;base64,` + encoded + `"}`,
+		},
+		{
+			name: "base64 data URL",
+			req:  NewTextToVideoRequest(ModelCogVideoX2, "waves").SetImageBase64("data:image/png;base64,"+encoded, ""),
+			want: `{"model":"cogvideox-2","prompt":"waves","image_url":"data:image/png;base64,` + encoded + `"}`,
+		},
+		{
+			name: "bytes",
+			req:  NewTextToVideoRequest(ModelCogVideoX2, "waves").SetImageBytes(png, "image/png"),
+			want: `{"model":"cogvideox-2","prompt":"waves","image_url":"data:image/png;base64,` + encoded + `"}`,
+		},
+		{
+			name: "file ID",
+			req:  NewTextToVideoRequest(ModelCogVideoX2, "waves").SetImageFileID("file-123"),
+			want: `{"model":"cogvideox-2","prompt":"waves","image_file_id":"file-123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.NoError(t, tt.req.Validate())
+			data, err := json.Marshal(tt.req)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestVideoGenerationRequest_ValidateImage(t *testing.T) {
+	t.Parallel()
+
+	encoded := base64.StdEncoding.EncodeToString([]byte("image"))
+
+	tests := []struct {
+		name    string
+		req     *VideoGenerationRequest
+		field   string
+		message string
+	}{
+		{
+			name:    "url and base64",
+			req:     NewImageToVideoRequest(ModelCogVideoX, "https://example.com/a.png").SetImageBase64(encoded, "image/png"),
+			field:   "image",
+			message: "image_url and image_base64",
+		},
+		{
+			name:    "base64 and file ID",
+			req:     NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBase64(encoded, "image/png").SetImageFileID("file-1"),
+			field:   "image",
+			message: "image_base64 and image_file_id",
+		},
+		{
+			name:    "missing MIME type",
+			req:     NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBase64(encoded, ""),
+			field:   "image_mime_type",
+			message: "must be an image type",
+		},
+		{
+			name:    "not an image",
+			req:     NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBase64(encoded, "application/pdf"),
+			field:   "image_mime_type",
+			message: "must be an image type",
+		},
+		{
+			name:    "invalid base64",
+			req:     NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBase64("not base64!", "image/png"),
+			field:   "image_base64",
+			message: "invalid base64 data",
+		},
+		{
+			name:    "too large",
+			req:     NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBytes(make([]byte, MaxImageBytes+1), "image/png"),
+			field:   "image",
+			message: "exceeds the maximum size",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+			assert.Contains(t, validationErr.Message, tt.message)
+		})
+	}
+
+	// An image of exactly the limit is accepted
+	req := NewTextToVideoRequest(ModelCogVideoX, "waves").SetImageBytes(make([]byte, MaxImageBytes), "image/png")
+	assert.NoError(t, req.Validate())
+}
@@ -0,0 +1,205 @@
+package videos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Quality is a video generation quality preset.
+type Quality string
+
+const (
+	// QualitySpeed favors generation speed.
+	QualitySpeed Quality = "speed"
+
+	// QualityQuality favors output quality.
+	QualityQuality Quality = "quality"
+)
+
+// Common video sizes.
+const (
+	Size720p     = "1280x720"
+	Size720pTall = "720x1280"
+	SizeSquare   = "1024x1024"
+	Size1080p    = "1920x1080"
+	Size1080Tall = "1080x1920"
+	Size2K       = "2048x1080"
+	Size4K       = "3840x2160"
+)
+
+// ModelLimits lists the generation parameters a model accepts.
+// An empty list means the parameter is not supported.
+type ModelLimits struct {
+	// Durations are the allowed video lengths in seconds.
+	Durations []int
+
+	// FPS are the allowed frame rates.
+	FPS []int
+
+	// Sizes are the allowed resolutions.
+	Sizes []string
+
+	// Qualities are the allowed quality presets.
+	Qualities []Quality
+
+	// Audio reports whether the model can generate audio.
+	Audio bool
+}
+
+// modelLimits holds the documented limits of each model.
+var modelLimits = map[VideoModel]ModelLimits{
+	ModelCogVideoX: {},
+	ModelCogVideoX2: {
+		Durations: []int{5, 10},
+		FPS:       []int{30, 60},
+		Sizes:     []string{Size720p, Size720pTall, SizeSquare, Size1080p, Size1080Tall, Size2K, Size4K},
+		Qualities: []Quality{QualitySpeed, QualityQuality},
+		Audio:     true,
+	},
+	ModelCogVideoXFlash: {
+		Durations: []int{5, 10},
+		FPS:       []int{30, 60},
+		Sizes:     []string{Size720p, Size720pTall, SizeSquare, Size1080p, Size1080Tall},
+		Qualities: []Quality{QualitySpeed, QualityQuality},
+		Audio:     true,
+	},
+}
+
+// LimitsFor returns the generation parameter limits of model, and false
+// if the model is not known to the SDK.
+func LimitsFor(model VideoModel) (ModelLimits, bool) {
+	limits, ok := modelLimits[model]
+	return limits, ok
+}
+
+// SetDuration sets the video length in seconds.
+//
+// Example:
+//
+//	req.SetDuration(10)
+func (r *VideoGenerationRequest) SetDuration(seconds int) *VideoGenerationRequest {
+	r.Duration = seconds
+	return r
+}
+
+// SetFPS sets the frame rate.
+//
+// Example:
+//
+//	req.SetFPS(60)
+func (r *VideoGenerationRequest) SetFPS(fps int) *VideoGenerationRequest {
+	r.FPS = fps
+	return r
+}
+
+// SetSize sets the video resolution as "WIDTHxHEIGHT".
+//
+// Example:
+//
+//	req.SetSize(videos.Size1080p)
+func (r *VideoGenerationRequest) SetSize(size string) *VideoGenerationRequest {
+	r.Size = size
+	return r
+}
+
+// SetWithAudio sets whether to generate an AI sound track.
+//
+// Example:
+//
+//	req.SetWithAudio(true)
+func (r *VideoGenerationRequest) SetWithAudio(withAudio bool) *VideoGenerationRequest {
+	r.WithAudio = &withAudio
+	return r
+}
+
+// SetQuality sets the quality preset.
+//
+// Example:
+//
+//	req.SetQuality(videos.QualityQuality)
+func (r *VideoGenerationRequest) SetQuality(quality Quality) *VideoGenerationRequest {
+	r.Quality = quality
+	return r
+}
+
+// Validate checks the image source and the generation parameters against
+// the limits of the model. Parameters of models unknown to the SDK are only checked for
+// well-formedness and are otherwise left to the API.
+func (r *VideoGenerationRequest) Validate() error {
+	if r.Model == "" {
+		return errors.NewValidationError("model", "is required", nil)
+	}
+	if r.Duration < 0 {
+		return errors.NewValidationError("duration", "must not be negative", r.Duration)
+	}
+	if r.FPS < 0 {
+		return errors.NewValidationError("fps", "must not be negative", r.FPS)
+	}
+	if r.Size != "" && !validSize(r.Size) {
+		return errors.NewValidationError("size", `must be in the form "WIDTHxHEIGHT"`, r.Size)
+	}
+	if err := r.validateImage(); err != nil {
+		return err
+	}
+	if r.CallbackURL != "" && !validCallbackURL(r.CallbackURL) {
+		return errors.NewValidationError("callback_url", "must be an absolute http or https URL", r.CallbackURL)
+	}
+
+	limits, ok := LimitsFor(r.Model)
+	if !ok {
+		return nil
+	}
+
+	if r.Duration != 0 && !contains(limits.Durations, r.Duration) {
+		return errors.NewValidationError("duration", unsupported(r.Model, limits.Durations), r.Duration)
+	}
+	if r.FPS != 0 && !contains(limits.FPS, r.FPS) {
+		return errors.NewValidationError("fps", unsupported(r.Model, limits.FPS), r.FPS)
+	}
+	if r.Size != "" && !contains(limits.Sizes, r.Size) {
+		return errors.NewValidationError("size", unsupported(r.Model, limits.Sizes), r.Size)
+	}
+	if r.Quality != "" && !contains(limits.Qualities, r.Quality) {
+		return errors.NewValidationError("quality", unsupported(r.Model, limits.Qualities), r.Quality)
+	}
+	if r.WithAudio != nil && *r.WithAudio && !limits.Audio {
+		return errors.NewValidationError("with_audio", fmt.Sprintf("is not supported by %s", r.Model), true)
+	}
+
+	return nil
+}
+
+// validSize reports whether size has the form "WIDTHxHEIGHT".
+func validSize(size string) bool {
+	w, h, ok := strings.Cut(size, "x")
+	if !ok {
+		return false
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width <= 0 {
+		return false
+	}
+	height, err := strconv.Atoi(h)
+	return err == nil && height > 0
+}
+
+// contains reports whether values contains v.
+func contains[T comparable](values []T, v T) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// unsupported describes the allowed values of a parameter for model.
+func unsupported[T any](model VideoModel, allowed []T) string {
+	if len(allowed) == 0 {
+		return fmt.Sprintf("is not supported by %s", model)
+	}
+	return fmt.Sprintf("must be one of %v for %s", allowed, model)
+}
@@ -0,0 +1,123 @@
+package videos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoGenerationRequest_ParamSetters(t *testing.T) {
+	t.Parallel()
+
+	req := NewTextToVideoRequest(ModelCogVideoX2, "A sunset").
+		SetDuration(10).
+		SetFPS(60).
+		SetSize(Size1080p).
+		SetWithAudio(false).
+		SetQuality(QualityQuality)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"model": "cogvideox-2",
+		"prompt": "A sunset",
+		"duration": 10,
+		"fps": 60,
+		"size": "1920x1080",
+		"with_audio": false,
+		"quality": "quality"
+	}`, string(data))
+	assert.NoError(t, req.Validate())
+}
+
+func TestVideoGenerationRequest_UnsetParamsOmitted(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(NewTextToVideoRequest(ModelCogVideoX2, "A sunset"))
+	require.NoError(t, err)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, map[string]interface{}{"model": "cogvideox-2", "prompt": "A sunset"}, body)
+}
+
+func TestVideoGenerationRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		req       *VideoGenerationRequest
+		wantField string
+	}{
+		{"no params", NewTextToVideoRequest(ModelCogVideoX, "x"), ""},
+		{"valid params", NewTextToVideoRequest(ModelCogVideoXFlash, "x").SetDuration(5).SetFPS(30), ""},
+		{"unknown model passes through", NewTextToVideoRequest("cogvideox-9", "x").SetDuration(60).SetSize("640x360"), ""},
+		{"missing model", &VideoGenerationRequest{Prompt: "x"}, "model"},
+		{"duration over model cap", NewTextToVideoRequest(ModelCogVideoX2, "x").SetDuration(60), "duration"},
+		{"negative duration", NewTextToVideoRequest("cogvideox-9", "x").SetDuration(-1), "duration"},
+		{"unsupported fps", NewTextToVideoRequest(ModelCogVideoX2, "x").SetFPS(24), "fps"},
+		{"malformed size", NewTextToVideoRequest("cogvideox-9", "x").SetSize("1080p"), "size"},
+		{"size not offered by model", NewTextToVideoRequest(ModelCogVideoXFlash, "x").SetSize(Size4K), "size"},
+		{"unknown quality", NewTextToVideoRequest(ModelCogVideoX2, "x").SetQuality("ultra"), "quality"},
+		{"params on legacy model", NewTextToVideoRequest(ModelCogVideoX, "x").SetDuration(5), "duration"},
+		{"audio on legacy model", NewTextToVideoRequest(ModelCogVideoX, "x").SetWithAudio(true), "with_audio"},
+		{"audio disabled on legacy model", NewTextToVideoRequest(ModelCogVideoX, "x").SetWithAudio(false), ""},
+		{"callback URL", NewTextToVideoRequest(ModelCogVideoX, "x").SetCallbackURL("https://example.com/hooks/zai"), ""},
+		{"relative callback URL", NewTextToVideoRequest(ModelCogVideoX, "x").SetCallbackURL("/hooks/zai"), "callback_url"},
+		{"callback URL without http", NewTextToVideoRequest(ModelCogVideoX, "x").SetCallbackURL("ftp://example.com/hooks"), "callback_url"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.req.Validate()
+			if tt.wantField == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.wantField, validationErr.Field)
+		})
+	}
+}
+
+func TestLimitsFor(t *testing.T) {
+	t.Parallel()
+
+	limits, ok := LimitsFor(ModelCogVideoX2)
+	require.True(t, ok)
+	assert.Equal(t, []int{5, 10}, limits.Durations)
+	assert.True(t, limits.Audio)
+
+	_, ok = LimitsFor("unknown")
+	assert.False(t, ok)
+}
+
+func TestVideoResult_Metadata(t *testing.T) {
+	t.Parallel()
+
+	var result VideoResult
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"task_id": "t1",
+		"task_status": "completed",
+		"video_result": [{"url": "https://example.com/v.mp4", "duration": 10.04, "width": 1920, "height": 1080, "fps": 60}]
+	}`), &result))
+
+	assert.InDelta(t, 10.04, result.GetDuration(), 1e-9)
+	w, h := result.GetDimensions()
+	assert.Equal(t, 1920, w)
+	assert.Equal(t, 1080, h)
+	assert.Equal(t, 60, result.VideoResult[0].FPS)
+
+	empty := VideoResult{}
+	assert.Zero(t, empty.GetDuration())
+	w, h = empty.GetDimensions()
+	assert.Zero(t, w+h)
+}
@@ -1,12 +1,21 @@
 // Package videos provides types for the Videos API.
 package videos
 
+import "encoding/json"
+
 // VideoModel represents the video generation model.
 type VideoModel string
 
 const (
 	// ModelCogVideoX is the CogVideoX model for video generation.
 	ModelCogVideoX VideoModel = "cogvideox"
+
+	// ModelCogVideoX2 is the CogVideoX-2 model, which supports duration,
+	// frame rate, size, audio and quality settings.
+	ModelCogVideoX2 VideoModel = "cogvideox-2"
+
+	// ModelCogVideoXFlash is the free CogVideoX-Flash model.
+	ModelCogVideoXFlash VideoModel = "cogvideox-flash"
 )
 
 // TaskStatus represents the status of a video generation task.
@@ -31,11 +40,46 @@ type VideoGenerationRequest struct {
 	// Prompt is the text description of the desired video (required for text-to-video).
 	Prompt string `json:"prompt,omitempty"`
 
-	// ImageURL is the URL of the image to animate (required for image-to-video).
+	// ImageURL is the URL of the image to animate. Image-to-video requests
+	// need exactly one of ImageURL, ImageBase64 and ImageFileID.
 	ImageURL string `json:"image_url,omitempty"`
 
+	// ImageBase64 is the base64-encoded image to animate, sent in
+	// image_url as a data URL with ImageMIMEType. See SetImageBase64.
+	ImageBase64 string `json:"-"`
+
+	// ImageMIMEType is the MIME type of ImageBase64, such as "image/png".
+	ImageMIMEType string `json:"-"`
+
+	// ImageFileID is the Files API ID of the image to animate.
+	ImageFileID string `json:"image_file_id,omitempty"`
+
 	// User is a unique identifier representing your end-user.
 	User string `json:"user,omitempty"`
+
+	// Quality trades generation speed for output quality.
+	Quality Quality `json:"quality,omitempty"`
+
+	// WithAudio generates an AI sound track for the video.
+	WithAudio *bool `json:"with_audio,omitempty"`
+
+	// Size is the video resolution, such as "1920x1080".
+	Size string `json:"size,omitempty"`
+
+	// FPS is the frame rate.
+	FPS int `json:"fps,omitempty"`
+
+	// Duration is the video length in seconds.
+	Duration int `json:"duration,omitempty"`
+
+	// CallbackURL is the URL the API notifies when the task completes,
+	// instead of being polled. See SetCallbackURL.
+	CallbackURL string `json:"callback_url,omitempty"`
+
+	// ExtraFields are sent as additional top-level fields, for parameters
+	// the SDK has no typed field for yet. Typed fields take precedence
+	// over entries with the same key.
+	ExtraFields map[string]any `json:"-"`
 }
 
 // NewTextToVideoRequest creates a new text-to-video generation request.
@@ -97,6 +141,9 @@ type VideoGenerationResponse struct {
 
 	// RequestID is the request identifier.
 	RequestID string `json:"request_id,omitempty"`
+
+	// extra holds the response fields the SDK has no typed field for.
+	extra json.RawMessage
 }
 
 // VideoResult represents a completed video generation result.
@@ -124,6 +171,18 @@ type VideoData struct {
 
 	// CoverImageURL is the URL of the video cover image.
 	CoverImageURL string `json:"cover_image_url,omitempty"`
+
+	// Duration is the actual video length in seconds, if reported.
+	Duration float64 `json:"duration,omitempty"`
+
+	// Width is the video width in pixels, if reported.
+	Width int `json:"width,omitempty"`
+
+	// Height is the video height in pixels, if reported.
+	Height int `json:"height,omitempty"`
+
+	// FPS is the actual frame rate, if reported.
+	FPS int `json:"fps,omitempty"`
 }
 
 // GetTaskID returns the task ID.
@@ -166,7 +225,8 @@ func (r *VideoResult) IsProcessing() bool {
 	return r.TaskStatus == StatusProcessing || r.TaskStatus == StatusSubmitted
 }
 
-// GetFirstVideo returns the first generated video, or nil if no videos were generated.
+// GetFirstVideo returns the first generated video, or nil if the result
+// has no videos.
 func (r *VideoResult) GetFirstVideo() *VideoData {
 	if len(r.VideoResult) == 0 {
 		return nil
@@ -192,7 +252,8 @@ func (r *VideoResult) GetCoverImageURL() string {
 	return video.CoverImageURL
 }
 
-// GetAllVideoURLs returns all video URLs from the result.
+// GetAllVideoURLs returns the URLs of the generated videos. It returns an
+// empty slice if there are none.
 func (r *VideoResult) GetAllVideoURLs() []string {
 	urls := make([]string, 0, len(r.VideoResult))
 	for _, video := range r.VideoResult {
@@ -203,6 +264,26 @@ func (r *VideoResult) GetAllVideoURLs() []string {
 	return urls
 }
 
+// GetDuration returns the length in seconds of the first generated video,
+// or 0 if the platform did not report it.
+func (r *VideoResult) GetDuration() float64 {
+	video := r.GetFirstVideo()
+	if video == nil {
+		return 0
+	}
+	return video.Duration
+}
+
+// GetDimensions returns the width and height of the first generated video,
+// or zeros if the platform did not report them.
+func (r *VideoResult) GetDimensions() (width, height int) {
+	video := r.GetFirstVideo()
+	if video == nil {
+		return 0, 0
+	}
+	return video.Width, video.Height
+}
+
 // HasError returns true if there is an error message.
 func (r *VideoResult) HasError() bool {
 	return r.ErrorMessage != ""
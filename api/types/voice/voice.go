@@ -108,6 +108,18 @@ type VoiceListRequest struct {
 	// VoiceName is an optional filter by voice name.
 	VoiceName string `json:"voice_name,omitempty"`
 
+	// NamePrefix is an optional filter that keeps voices whose name starts
+	// with the prefix.
+	NamePrefix string `json:"voice_name_prefix,omitempty"`
+
+	// PageSize is the maximum number of voices per page.
+	// If zero, the API default is used.
+	PageSize int `json:"page_size,omitempty"`
+
+	// Cursor is the NextCursor of the previous page.
+	// If empty, the first page is returned.
+	Cursor string `json:"cursor,omitempty"`
+
 	// RequestID is an optional request ID for tracking.
 	RequestID string `json:"request_id,omitempty"`
 }
@@ -129,6 +141,24 @@ func (r *VoiceListRequest) SetVoiceName(voiceName string) *VoiceListRequest {
 	return r
 }
 
+// SetNamePrefix sets the voice name prefix filter.
+func (r *VoiceListRequest) SetNamePrefix(prefix string) *VoiceListRequest {
+	r.NamePrefix = prefix
+	return r
+}
+
+// SetPageSize sets the maximum number of voices per page.
+func (r *VoiceListRequest) SetPageSize(pageSize int) *VoiceListRequest {
+	r.PageSize = pageSize
+	return r
+}
+
+// SetCursor sets the cursor of the page to fetch.
+func (r *VoiceListRequest) SetCursor(cursor string) *VoiceListRequest {
+	r.Cursor = cursor
+	return r
+}
+
 // SetRequestID sets the request ID.
 func (r *VoiceListRequest) SetRequestID(requestID string) *VoiceListRequest {
 	r.RequestID = requestID
@@ -139,6 +169,12 @@ func (r *VoiceListRequest) SetRequestID(requestID string) *VoiceListRequest {
 type VoiceListResponse struct {
 	// VoiceList contains the list of voices.
 	VoiceList []VoiceData `json:"voice_list"`
+
+	// HasMore indicates whether more pages follow.
+	HasMore bool `json:"has_more"`
+
+	// NextCursor is the cursor of the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // GetVoices returns the list of voices.
@@ -148,3 +184,7 @@ func (r *VoiceListResponse) GetVoices() []VoiceData {
 	}
 	return r.VoiceList
 }
+
+// MaxPreviewLength is the maximum number of characters of the text
+// synthesized by Voice.Preview, which keeps previews short.
+const MaxPreviewLength = 200
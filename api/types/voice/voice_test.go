@@ -291,3 +291,28 @@ func TestVoiceListResponse_JSON(t *testing.T) {
 	assert.Equal(t, resp.VoiceList[0].Voice, decoded.VoiceList[0].Voice)
 	assert.Equal(t, resp.VoiceList[0].VoiceName, decoded.VoiceList[0].VoiceName)
 }
+
+func TestVoiceListRequest_Paging(t *testing.T) {
+	t.Parallel()
+
+	req := NewVoiceListRequest().
+		SetNamePrefix("support-").
+		SetPageSize(50).
+		SetCursor("c2")
+
+	assert.Equal(t, "support-", req.NamePrefix)
+	assert.Equal(t, 50, req.PageSize)
+	assert.Equal(t, "c2", req.Cursor)
+}
+
+func TestVoiceListResponse_Paging_JSON(t *testing.T) {
+	t.Parallel()
+
+	var resp VoiceListResponse
+	err := json.Unmarshal([]byte(`{"voice_list":[{"voice":"v1"}],"has_more":true,"next_cursor":"c2"}`), &resp)
+	require.NoError(t, err)
+
+	assert.Len(t, resp.GetVoices(), 1)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, "c2", resp.NextCursor)
+}
@@ -0,0 +1,188 @@
+package webreader
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultBatchConcurrency is the default number of pages read in parallel.
+const DefaultBatchConcurrency = 5
+
+// BatchConfig holds settings for reading multiple URLs.
+type BatchConfig struct {
+	// Concurrency is the maximum number of requests in flight.
+	Concurrency int
+
+	// ReadTimeout bounds each read, independently of the caller's context
+	// deadline. Zero means no per-read limit.
+	ReadTimeout time.Duration
+
+	// Template holds the options applied to every request. Its URL is ignored.
+	Template Request
+}
+
+// Option configures a batch read.
+type Option func(*BatchConfig)
+
+// NewBatchConfig creates a batch configuration with the given options applied.
+func NewBatchConfig(opts ...Option) *BatchConfig {
+	cfg := &BatchConfig{Concurrency: DefaultBatchConcurrency}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultBatchConcurrency
+	}
+	return cfg
+}
+
+// NewRequest creates a request for url using the template options.
+func (c *BatchConfig) NewRequest(url string) *Request {
+	req := c.Template
+	req.URL = url
+	return &req
+}
+
+// WithConcurrency sets the maximum number of requests in flight.
+func WithConcurrency(n int) Option {
+	return func(c *BatchConfig) {
+		c.Concurrency = n
+	}
+}
+
+// WithReadTimeout limits how long each read may take on the client side.
+// A slow page then fails on its own without using up the deadline of the
+// whole batch. Unlike WithTimeout, it does not change the request sent to
+// the API.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(c *BatchConfig) {
+		c.ReadTimeout = timeout
+	}
+}
+
+// WithReturnFormat sets the return format.
+func WithReturnFormat(format Format) Option {
+	return func(c *BatchConfig) {
+		c.Template.ReturnFormat = format
+	}
+}
+
+// WithExtractMode sets whether the whole page or only the main article is
+// extracted.
+func WithExtractMode(mode ExtractMode) Option {
+	return func(c *BatchConfig) {
+		c.Template.ExtractMode = mode
+	}
+}
+
+// WithIncludeOutline sets whether to return the outline of the page headings.
+func WithIncludeOutline(include bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.IncludeOutline = include
+	}
+}
+
+// WithNoCache disables the server-side cache.
+func WithNoCache(noCache bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.NoCache = noCache
+	}
+}
+
+// WithTimeout sets the per-page timeout in seconds.
+func WithTimeout(timeout string) Option {
+	return func(c *BatchConfig) {
+		c.Template.Timeout = timeout
+	}
+}
+
+// WithUserID sets the end-user ID sent with every request.
+func WithUserID(userID string) Option {
+	return func(c *BatchConfig) {
+		c.Template.UserID = userID
+	}
+}
+
+// WithRetainImages keeps images in the output.
+func WithRetainImages(retain bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.RetainImages = retain
+	}
+}
+
+// WithNoGFM disables GitHub Flavored Markdown.
+func WithNoGFM(noGFM bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.NoGFM = noGFM
+	}
+}
+
+// WithKeepImgDataURL keeps image data URLs.
+func WithKeepImgDataURL(keep bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.KeepImgDataURL = keep
+	}
+}
+
+// WithImagesSummary includes an images summary.
+func WithImagesSummary(withSummary bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.WithImagesSummary = withSummary
+	}
+}
+
+// WithLinksSummary includes a links summary.
+func WithLinksSummary(withSummary bool) Option {
+	return func(c *BatchConfig) {
+		c.Template.WithLinksSummary = withSummary
+	}
+}
+
+// Result is the outcome of reading one URL in a batch.
+type Result struct {
+	// URL is the page URL that was read.
+	URL string
+
+	// Response is the reader response, or nil if the read failed.
+	Response *Response
+
+	// Err is the error for this URL, if any.
+	Err error
+}
+
+// OK returns true if the page was read successfully.
+func (r *Result) OK() bool {
+	return r != nil && r.Err == nil && r.Response != nil
+}
+
+// BatchError reports the URLs that failed in a batch read.
+type BatchError struct {
+	// Errors maps each failed URL to its error.
+	Errors map[string]error
+}
+
+// Error implements the error interface.
+func (e *BatchError) Error() string {
+	urls := make([]string, 0, len(e.Errors))
+	for url := range e.Errors {
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+
+	parts := make([]string, 0, len(urls))
+	for _, url := range urls {
+		parts = append(parts, fmt.Sprintf("%s: %v", url, e.Errors[url]))
+	}
+	return fmt.Sprintf("web reader: %d URL(s) failed: %s", len(urls), strings.Join(parts, "; "))
+}
+
+// Unwrap returns the per-URL errors.
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
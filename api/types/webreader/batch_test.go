@@ -0,0 +1,89 @@
+package webreader
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewBatchConfig(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := NewBatchConfig()
+		assert.Equal(t, DefaultBatchConcurrency, cfg.Concurrency)
+		assert.Equal(t, Request{}, cfg.Template)
+	})
+
+	t.Run("invalid concurrency falls back to default", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := NewBatchConfig(WithConcurrency(0))
+		assert.Equal(t, DefaultBatchConcurrency, cfg.Concurrency)
+	})
+
+	t.Run("options are applied to each request", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := NewBatchConfig(
+			WithConcurrency(8),
+			WithReturnFormat("text"),
+			WithNoCache(true),
+			WithTimeout("20"),
+			WithUserID("user_123456"),
+			WithRetainImages(true),
+			WithNoGFM(true),
+			WithKeepImgDataURL(true),
+			WithImagesSummary(true),
+			WithLinksSummary(true),
+			WithReadTimeout(5*time.Second),
+		)
+		assert.Equal(t, 8, cfg.Concurrency)
+		assert.Equal(t, 5*time.Second, cfg.ReadTimeout)
+
+		a := cfg.NewRequest("https://a.example")
+		b := cfg.NewRequest("https://b.example")
+
+		assert.Equal(t, "https://a.example", a.URL)
+		assert.Equal(t, "https://b.example", b.URL)
+		assert.Equal(t, FormatText, a.ReturnFormat)
+		assert.True(t, a.NoCache)
+		assert.Equal(t, "20", a.Timeout)
+		assert.Equal(t, "user_123456", a.UserID)
+		assert.True(t, a.RetainImages)
+		assert.True(t, a.NoGFM)
+		assert.True(t, a.KeepImgDataURL)
+		assert.True(t, a.WithImagesSummary)
+		assert.True(t, a.WithLinksSummary)
+		assert.Empty(t, cfg.Template.URL)
+	})
+}
+
+func TestResult_OK(t *testing.T) {
+	t.Parallel()
+
+	var nilResult *Result
+	assert.False(t, nilResult.OK())
+	assert.False(t, (&Result{URL: "u"}).OK())
+	assert.False(t, (&Result{Response: &Response{}, Err: errors.New("x")}).OK())
+	assert.True(t, (&Result{Response: &Response{}}).OK())
+}
+
+func TestBatchError(t *testing.T) {
+	t.Parallel()
+
+	errB := errors.New("timeout")
+	errA := errors.New("not found")
+	err := &BatchError{Errors: map[string]error{
+		"https://b.example": errB,
+		"https://a.example": errA,
+	}}
+
+	assert.Equal(t, "web reader: 2 URL(s) failed: https://a.example: not found; https://b.example: timeout", err.Error())
+	assert.ErrorIs(t, err, errA)
+	assert.ErrorIs(t, err, errB)
+}
@@ -1,6 +1,40 @@
 // Package webreader provides types for the Web Reader API.
 package webreader
 
+import (
+	"encoding/json"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Format is the format of the extracted page content.
+type Format string
+
+// Return formats.
+const (
+	// FormatText returns the content as plain text.
+	FormatText Format = "text"
+
+	// FormatMarkdown returns the content as Markdown.
+	FormatMarkdown Format = "markdown"
+
+	// FormatHTML returns the content as HTML.
+	FormatHTML Format = "html"
+)
+
+// ExtractMode selects which part of the page is extracted.
+type ExtractMode string
+
+// Extraction modes.
+const (
+	// ExtractModeFull extracts the whole page.
+	ExtractModeFull ExtractMode = "full"
+
+	// ExtractModeArticle extracts the main article only, leaving out
+	// navigation, sidebars and footers.
+	ExtractModeArticle ExtractMode = "article"
+)
+
 // Request represents a request to read a web page.
 type Request struct {
 	// URL is the target page URL to read (required).
@@ -18,8 +52,14 @@ type Request struct {
 	// NoCache disables cache when true (optional).
 	NoCache bool `json:"no_cache,omitempty"`
 
-	// ReturnFormat specifies the return format, e.g. "markdown" or "text" (optional).
-	ReturnFormat string `json:"return_format,omitempty"`
+	// ReturnFormat specifies the return format (optional).
+	ReturnFormat Format `json:"return_format,omitempty"`
+
+	// ExtractMode selects the whole page or the main article (optional).
+	ExtractMode ExtractMode `json:"extract_mode,omitempty"`
+
+	// IncludeOutline returns the outline of the page headings when true (optional).
+	IncludeOutline bool `json:"include_outline,omitempty"`
 
 	// RetainImages keeps images in output when true (optional).
 	RetainImages bool `json:"retain_images,omitempty"`
@@ -68,12 +108,33 @@ func (r *Request) SetNoCache(noCache bool) *Request {
 	return r
 }
 
-// SetReturnFormat sets the return format (e.g., "markdown" or "text").
-func (r *Request) SetReturnFormat(format string) *Request {
+// SetReturnFormat sets the return format.
+//
+// Example:
+//
+//	req.SetReturnFormat(webreader.FormatMarkdown)
+func (r *Request) SetReturnFormat(format Format) *Request {
 	r.ReturnFormat = format
 	return r
 }
 
+// SetExtractMode sets whether the whole page or only the main article is
+// extracted.
+//
+// Example:
+//
+//	req.SetExtractMode(webreader.ExtractModeArticle)
+func (r *Request) SetExtractMode(mode ExtractMode) *Request {
+	r.ExtractMode = mode
+	return r
+}
+
+// SetIncludeOutline sets whether to return the outline of the page headings.
+func (r *Request) SetIncludeOutline(include bool) *Request {
+	r.IncludeOutline = include
+	return r
+}
+
 // SetRetainImages sets whether to keep images in output.
 func (r *Request) SetRetainImages(retain bool) *Request {
 	r.RetainImages = retain
@@ -104,6 +165,37 @@ func (r *Request) SetWithLinksSummary(withSummary bool) *Request {
 	return r
 }
 
+// Validate checks that the URL is set and that the return format and
+// extraction mode are known.
+func (r *Request) Validate() error {
+	if r.URL == "" {
+		return errors.NewValidationError("url", "is required", nil)
+	}
+	switch r.ReturnFormat {
+	case "", FormatText, FormatMarkdown, FormatHTML:
+	default:
+		return errors.NewValidationError("return_format", `must be one of "text", "markdown" or "html"`, r.ReturnFormat)
+	}
+	switch r.ExtractMode {
+	case "", ExtractModeFull, ExtractModeArticle:
+	default:
+		return errors.NewValidationError("extract_mode", `must be "full" or "article"`, r.ExtractMode)
+	}
+	return nil
+}
+
+// Heading is a heading of the page outline.
+type Heading struct {
+	// Level is the heading level, from 1 for <h1> to 6 for <h6>.
+	Level int `json:"level"`
+
+	// Text is the heading text.
+	Text string `json:"text"`
+
+	// Anchor is the fragment identifier of the heading, if it has one.
+	Anchor string `json:"anchor,omitempty"`
+}
+
 // ReaderData contains the extracted web page data.
 type ReaderData struct {
 	// Images is a map of image URLs.
@@ -127,6 +219,16 @@ type ReaderData struct {
 	// PublishedTime is the publication time.
 	PublishedTime string `json:"publishedTime,omitempty"`
 
+	// Author is the author of the page, if known.
+	Author string `json:"author,omitempty"`
+
+	// SiteName is the name of the site the page belongs to, if known.
+	SiteName string `json:"siteName,omitempty"`
+
+	// Outline lists the headings of the page in document order. It is
+	// only returned when IncludeOutline is set.
+	Outline []Heading `json:"outline,omitempty"`
+
 	// Metadata contains additional metadata.
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 
@@ -134,6 +236,40 @@ type ReaderData struct {
 	External map[string]interface{} `json:"external,omitempty"`
 }
 
+// metadataAuthorKeys and metadataSiteNameKeys are the metadata keys Author
+// and SiteName are read from when the page has no top-level field for them.
+var (
+	metadataAuthorKeys   = []string{"author", "article:author"}
+	metadataSiteNameKeys = []string{"site_name", "og:site_name"}
+)
+
+// UnmarshalJSON decodes the page data, filling Author and SiteName from
+// Metadata when they are only returned there. Metadata is kept as
+// returned, including keys the SDK has no typed field for.
+func (d *ReaderData) UnmarshalJSON(data []byte) error {
+	type alias ReaderData
+	if err := json.Unmarshal(data, (*alias)(d)); err != nil {
+		return err
+	}
+	if d.Author == "" {
+		d.Author = metadataString(d.Metadata, metadataAuthorKeys)
+	}
+	if d.SiteName == "" {
+		d.SiteName = metadataString(d.Metadata, metadataSiteNameKeys)
+	}
+	return nil
+}
+
+// metadataString returns the first non-empty string value of keys in metadata.
+func metadataString(metadata map[string]interface{}, keys []string) string {
+	for _, key := range keys {
+		if value, ok := metadata[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
 // GetContent returns the extracted content.
 func (d *ReaderData) GetContent() string {
 	return d.Content
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestNewRequest(t *testing.T) {
@@ -48,7 +49,7 @@ func TestRequest_BuilderMethods(t *testing.T) {
 	assert.Equal(t, "user_456", req.UserID)
 	assert.Equal(t, "30", req.Timeout)
 	assert.True(t, req.NoCache)
-	assert.Equal(t, "markdown", req.ReturnFormat)
+	assert.Equal(t, FormatMarkdown, req.ReturnFormat)
 	assert.True(t, req.RetainImages)
 	assert.False(t, req.NoGFM)
 	assert.True(t, req.KeepImgDataURL)
@@ -237,3 +238,111 @@ func TestResponse_JSON_NoResult(t *testing.T) {
 	assert.Nil(t, decoded.ReaderResult)
 	assert.False(t, decoded.HasResult())
 }
+
+func TestRequest_ExtractionJSON(t *testing.T) {
+	t.Parallel()
+
+	req := NewRequest("https://example.com/post").
+		SetReturnFormat(FormatHTML).
+		SetExtractMode(ExtractModeArticle).
+		SetIncludeOutline(true)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"url": "https://example.com/post",
+		"return_format": "html",
+		"extract_mode": "article",
+		"include_outline": true
+	}`, string(data))
+
+	// Unset options are left out
+	data, err = json.Marshal(NewRequest("https://example.com/post"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"url": "https://example.com/post"}`, string(data))
+}
+
+func TestRequest_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		req   *Request
+		field string
+	}{
+		{"missing URL", NewRequest(""), "url"},
+		{"unknown format", NewRequest("https://example.com").SetReturnFormat("markdwon"), "return_format"},
+		{"unknown extract mode", NewRequest("https://example.com").SetExtractMode("main"), "extract_mode"},
+	}
+	for _, tt := range tests {
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, tt.req.Validate(), &validationErr, tt.name)
+		assert.Equal(t, tt.field, validationErr.Field, tt.name)
+	}
+
+	for _, format := range []Format{"", FormatText, FormatMarkdown, FormatHTML} {
+		for _, mode := range []ExtractMode{"", ExtractModeFull, ExtractModeArticle} {
+			req := NewRequest("https://example.com").SetReturnFormat(format).SetExtractMode(mode)
+			assert.NoError(t, req.Validate(), "format %q, mode %q", format, mode)
+		}
+	}
+}
+
+func TestResponse_DecodeExtraction(t *testing.T) {
+	t.Parallel()
+
+	t.Run("typed fields", func(t *testing.T) {
+		t.Parallel()
+
+		var resp Response
+		require.NoError(t, json.Unmarshal([]byte(`{"reader_result": {
+			"title": "Release notes",
+			"content": "...",
+			"author": "Jane Doe",
+			"siteName": "Example Blog",
+			"outline": [
+				{"level": 1, "text": "Release notes", "anchor": "release-notes"},
+				{"level": 2, "text": "Fixes"}
+			],
+			"metadata": {"author": "ignored", "og:type": "article"}
+		}}`), &resp))
+
+		data := resp.GetResult()
+		assert.Equal(t, "Jane Doe", data.Author)
+		assert.Equal(t, "Example Blog", data.SiteName)
+		assert.Equal(t, []Heading{
+			{Level: 1, Text: "Release notes", Anchor: "release-notes"},
+			{Level: 2, Text: "Fixes"},
+		}, data.Outline)
+		assert.Equal(t, map[string]interface{}{"author": "ignored", "og:type": "article"}, data.Metadata)
+	})
+
+	t.Run("fields from metadata", func(t *testing.T) {
+		t.Parallel()
+
+		var data ReaderData
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"title": "Post",
+			"metadata": {"author": "John Roe", "og:site_name": "News", "lang": "en", "views": 3}
+		}`), &data))
+
+		assert.Equal(t, "John Roe", data.Author)
+		assert.Equal(t, "News", data.SiteName)
+		// Unknown keys stay in Metadata
+		assert.Equal(t, "en", data.Metadata["lang"])
+		assert.Equal(t, float64(3), data.Metadata["views"])
+	})
+
+	t.Run("absent fields", func(t *testing.T) {
+		t.Parallel()
+
+		var data ReaderData
+		require.NoError(t, json.Unmarshal([]byte(`{"title": "Plain page", "content": "text"}`), &data))
+
+		assert.Equal(t, "Plain page", data.Title)
+		assert.Empty(t, data.Author)
+		assert.Empty(t, data.SiteName)
+		assert.Nil(t, data.Outline)
+		assert.Nil(t, data.Metadata)
+	})
+}
@@ -0,0 +1,77 @@
+package websearch
+
+import "encoding/json"
+
+// EnrichedResult is a search result together with the content of its page
+// as extracted by the web reader.
+type EnrichedResult struct {
+	SearchResultResp
+
+	// FullContent is the extracted page content. Empty if the page was not
+	// read; Content still holds the search snippet.
+	FullContent string `json:"full_content,omitempty"`
+
+	// PageTitle is the page title reported by the reader.
+	PageTitle string `json:"page_title,omitempty"`
+
+	// PublishedTime is the publication time reported by the reader.
+	PublishedTime string `json:"published_time,omitempty"`
+
+	// Err is the error reading the page, if any.
+	Err error `json:"-"`
+}
+
+// UnmarshalJSON decodes the result, which would otherwise decode only the
+// embedded search result.
+func (r *EnrichedResult) UnmarshalJSON(data []byte) error {
+	var page struct {
+		FullContent   string `json:"full_content"`
+		PageTitle     string `json:"page_title"`
+		PublishedTime string `json:"published_time"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+	if err := r.SearchResultResp.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	r.FullContent = page.FullContent
+	r.PageTitle = page.PageTitle
+	r.PublishedTime = page.PublishedTime
+	return nil
+}
+
+// OK returns true if reading the page did not fail.
+func (r *EnrichedResult) OK() bool {
+	return r.Err == nil
+}
+
+// Text returns the full page content if it was fetched, and the search
+// snippet otherwise.
+func (r *EnrichedResult) Text() string {
+	if r.FullContent != "" {
+		return r.FullContent
+	}
+	return r.Content
+}
+
+// EnrichedResponse is the result of a search whose top results were read.
+type EnrichedResponse struct {
+	// Search is the search response.
+	Search *WebSearchResponse `json:"search"`
+
+	// Results are the top search results with their page content, in
+	// search order, with duplicate links removed.
+	Results []EnrichedResult `json:"results"`
+}
+
+// Failed returns the results whose page could not be read.
+func (r *EnrichedResponse) Failed() []EnrichedResult {
+	var failed []EnrichedResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
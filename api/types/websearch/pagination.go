@@ -0,0 +1,77 @@
+package websearch
+
+import "fmt"
+
+// PageInfo describes one page fetched by a multi-page search.
+type PageInfo struct {
+	// Page is the one-based page number
+	Page int `json:"page"`
+
+	// RequestID is the request identifier reported for this page
+	RequestID string `json:"request_id,omitempty"`
+
+	// ID is the response identifier for this page
+	ID string `json:"id,omitempty"`
+
+	// Results is the number of results returned by this page
+	Results int `json:"results"`
+
+	// NewResults is the number of results kept after deduplication
+	NewResults int `json:"new_results"`
+}
+
+// SearchAllResponse aggregates the results of a multi-page search.
+type SearchAllResponse struct {
+	// SearchIntent is the search intent analysis from the first page, if any
+	SearchIntent *SearchIntentResp `json:"search_intent,omitempty"`
+
+	// SearchResult contains the deduplicated results from all pages
+	SearchResult []SearchResultResp `json:"search_result,omitempty"`
+
+	// Pages describes each page that was fetched successfully
+	Pages []PageInfo `json:"pages,omitempty"`
+
+	// HasMore indicates the search stopped at the result limit while
+	// the API still reported more pages.
+	HasMore bool `json:"has_more,omitempty"`
+}
+
+// GetResults returns the aggregated search results.
+func (r *SearchAllResponse) GetResults() []SearchResultResp {
+	return r.SearchResult
+}
+
+// RequestIDs returns the request identifier of each fetched page.
+func (r *SearchAllResponse) RequestIDs() []string {
+	ids := make([]string, 0, len(r.Pages))
+	for _, p := range r.Pages {
+		ids = append(ids, p.RequestID)
+	}
+	return ids
+}
+
+// PageError reports a failure to fetch one page of a multi-page search.
+// Results from earlier pages are still returned alongside it.
+type PageError struct {
+	// Page is the one-based page number that failed
+	Page int
+
+	// RequestID is the request identifier sent for the failed page
+	RequestID string
+
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface.
+func (e *PageError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("web search page %d (request %s): %v", e.Page, e.RequestID, e.Err)
+	}
+	return fmt.Sprintf("web search page %d: %v", e.Page, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PageError) Unwrap() error {
+	return e.Err
+}
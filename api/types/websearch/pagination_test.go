@@ -0,0 +1,36 @@
+package websearch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchAllResponse(t *testing.T) {
+	t.Parallel()
+
+	resp := &SearchAllResponse{
+		SearchResult: []SearchResultResp{{Link: "https://a.example"}},
+		Pages: []PageInfo{
+			{Page: 1, RequestID: "req_1", Results: 1, NewResults: 1},
+			{Page: 2, RequestID: "req_2"},
+		},
+	}
+
+	assert.Len(t, resp.GetResults(), 1)
+	assert.Equal(t, []string{"req_1", "req_2"}, resp.RequestIDs())
+}
+
+func TestPageError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("boom")
+
+	err := &PageError{Page: 3, RequestID: "req_abc", Err: cause}
+	assert.Equal(t, "web search page 3 (request req_abc): boom", err.Error())
+	assert.ErrorIs(t, err, cause)
+
+	err = &PageError{Page: 2, Err: cause}
+	assert.Equal(t, "web search page 2: boom", err.Error())
+}
@@ -0,0 +1,145 @@
+package websearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IntentType is the search intent determined by the API. Types added to
+// the API after this SDK are kept as returned.
+type IntentType string
+
+// Search intent constants
+const (
+	// IntentSearchAll means the query needs a web search.
+	IntentSearchAll IntentType = "SEARCH_ALL"
+
+	// IntentSearchNone means the query can be answered without searching.
+	IntentSearchNone IntentType = "SEARCH_NONE"
+
+	// IntentSearchAlways means the search ran regardless of the intent.
+	IntentSearchAlways IntentType = "SEARCH_ALWAYS"
+)
+
+// publishDateLayouts are the date formats the API returns publish dates
+// in, tried in order.
+var publishDateLayouts = []string{
+	"2006-01-02",
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006/01/02",
+	"2006/1/2",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"2006年01月02日",
+	"2006年1月2日",
+}
+
+// UnmarshalJSON decodes the result and parses its publish date into
+// PublishDateParsed. A publish date returned as a number is kept as its
+// decimal text.
+func (r *SearchResultResp) UnmarshalJSON(data []byte) error {
+	type alias SearchResultResp
+	var raw struct {
+		alias
+		PublishDate json.RawMessage `json:"publish_date"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = SearchResultResp(raw.alias)
+	date := bytes.TrimSpace(raw.PublishDate)
+	switch {
+	case len(date) == 0 || bytes.Equal(date, []byte("null")):
+	case date[0] == '"':
+		if err := json.Unmarshal(date, &r.PublishDate); err != nil {
+			return err
+		}
+	default:
+		r.PublishDate = string(date)
+	}
+	r.PublishDateParsed, _ = parsePublishDate(r.PublishDate)
+	return nil
+}
+
+// parsePublishDate parses a publish date in one of the formats the API
+// uses, including Unix seconds and milliseconds. Dates without a time zone
+// are in UTC.
+func parsePublishDate(date string) (time.Time, bool) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return time.Time{}, false
+	}
+
+	if unix, err := strconv.ParseInt(date, 10, 64); err == nil {
+		if unix > 1e12 {
+			return time.UnixMilli(unix).UTC(), true
+		}
+		return time.Unix(unix, 0).UTC(), true
+	}
+
+	for _, layout := range publishDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SortByScore returns the results ordered from the highest to the lowest
+// score. Results with equal scores keep their search order. The response
+// is not changed.
+func (r *WebSearchResponse) SortByScore() []SearchResultResp {
+	results := slices.Clone(r.SearchResult)
+	slices.SortStableFunc(results, func(a, b SearchResultResp) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return results
+}
+
+// FilterByMedia returns the results from any of the given sources, compared
+// case-insensitively with Media.
+//
+// Example:
+//
+//	results := resp.FilterByMedia("Reuters", "BBC")
+func (r *WebSearchResponse) FilterByMedia(media ...string) []SearchResultResp {
+	var results []SearchResultResp
+	for _, result := range r.SearchResult {
+		if slices.ContainsFunc(media, func(m string) bool { return strings.EqualFold(m, result.Media) }) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
+
+// FilterPublishedAfter returns the results published after t. Results
+// whose publish date is missing or could not be parsed are left out.
+//
+// Example:
+//
+//	recent := resp.FilterPublishedAfter(time.Now().AddDate(0, -1, 0))
+func (r *WebSearchResponse) FilterPublishedAfter(t time.Time) []SearchResultResp {
+	var results []SearchResultResp
+	for _, result := range r.SearchResult {
+		if !result.PublishDateParsed.IsZero() && result.PublishDateParsed.After(t) {
+			results = append(results, result)
+		}
+	}
+	return results
+}
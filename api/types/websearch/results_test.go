@@ -0,0 +1,145 @@
+package websearch
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePublishDate(t *testing.T) {
+	t.Parallel()
+
+	may1 := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		date string
+		want time.Time
+	}{
+		{"2024-05-01", may1},
+		{"May 1, 2024", may1},
+		{"May 01, 2024", may1},
+		{"September 12, 2023", time.Date(2023, time.September, 12, 0, 0, 0, 0, time.UTC)},
+		{"Sep 12, 2023", time.Date(2023, time.September, 12, 0, 0, 0, 0, time.UTC)},
+		{"1 May 2024", may1},
+		{"1714521600", may1},
+		{"1714521600000", may1},
+		{"2024-05-01 08:30:00", time.Date(2024, time.May, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024-05-01T08:30:00Z", time.Date(2024, time.May, 1, 8, 30, 0, 0, time.UTC)},
+		{"2024/05/01", may1},
+		{"2024/5/1", may1},
+		{"2024年05月01日", may1},
+		{"2024年5月1日", may1},
+		{" 2024-05-01 ", may1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.date, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := parsePublishDate(tt.date)
+			require.True(t, ok)
+			assert.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+		})
+	}
+
+	for _, date := range []string{"", "yesterday", "2024-13-45", "01/05/2024"} {
+		_, ok := parsePublishDate(date)
+		assert.False(t, ok, date)
+	}
+}
+
+func TestSearchResultResp_UnmarshalPublishDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		json     string
+		wantDate string
+		want     time.Time
+	}{
+		{"date", `{"publish_date":"2024-05-01","score":0.82}`, "2024-05-01", time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"long date", `{"publish_date":"May 1, 2024"}`, "May 1, 2024", time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"unix number", `{"publish_date":1714521600}`, "1714521600", time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)},
+		{"unknown format", `{"publish_date":"last week"}`, "last week", time.Time{}},
+		{"null", `{"publish_date":null}`, "", time.Time{}},
+		{"missing", `{}`, "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var result SearchResultResp
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &result))
+			assert.Equal(t, tt.wantDate, result.PublishDate)
+			assert.True(t, tt.want.Equal(result.PublishDateParsed), "got %v", result.PublishDateParsed)
+		})
+	}
+
+	var result SearchResultResp
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"AI","score":0.82}`), &result))
+	assert.Equal(t, "AI", result.Title)
+	assert.InDelta(t, 0.82, result.Score, 1e-9)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"publish_date":"2024-05-01","score":"high"}`), &result))
+}
+
+func TestEnrichedResult_Unmarshal(t *testing.T) {
+	t.Parallel()
+
+	var result EnrichedResult
+	require.NoError(t, json.Unmarshal([]byte(`{"title":"AI","publish_date":"2024-05-01","full_content":"Full text","page_title":"AI News","published_time":"2024-05-01T08:00:00Z"}`), &result))
+
+	assert.Equal(t, "AI", result.Title)
+	assert.Equal(t, 2024, result.PublishDateParsed.Year())
+	assert.Equal(t, "Full text", result.FullContent)
+	assert.Equal(t, "AI News", result.PageTitle)
+	assert.Equal(t, "2024-05-01T08:00:00Z", result.PublishedTime)
+}
+
+func TestIntentType(t *testing.T) {
+	t.Parallel()
+
+	var intent SearchIntentResp
+	require.NoError(t, json.Unmarshal([]byte(`{"query":"AI news","intent":"SEARCH_ALL","keywords":"AI"}`), &intent))
+	assert.Equal(t, IntentSearchAll, intent.Intent)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"intent":"SEARCH_LATER"}`), &intent))
+	assert.Equal(t, IntentType("SEARCH_LATER"), intent.Intent, "unknown intents are kept")
+
+	data, err := json.Marshal(SearchIntentResp{Intent: IntentSearchNone})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"intent":"SEARCH_NONE"`)
+}
+
+func TestWebSearchResponse_Helpers(t *testing.T) {
+	t.Parallel()
+
+	var resp WebSearchResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"search_result":[
+		{"title":"a","media":"Reuters","publish_date":"2024-05-01","score":0.4},
+		{"title":"b","media":"BBC","publish_date":"May 20, 2024","score":0.9},
+		{"title":"c","media":"reuters","publish_date":"","score":0.4},
+		{"title":"d","media":"Blog","publish_date":1717200000,"score":0.7}
+	]}`), &resp))
+
+	titles := func(results []SearchResultResp) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Title)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"b", "d", "a", "c"}, titles(resp.SortByScore()))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, titles(resp.SearchResult), "sorting does not change the response")
+
+	assert.Equal(t, []string{"a", "c"}, titles(resp.FilterByMedia("REUTERS")))
+	assert.Equal(t, []string{"a", "b", "c"}, titles(resp.FilterByMedia("Reuters", "BBC")))
+	assert.Empty(t, resp.FilterByMedia("CNN"))
+
+	after := time.Date(2024, time.May, 10, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"b", "d"}, titles(resp.FilterPublishedAfter(after)), "results without a date are left out")
+}
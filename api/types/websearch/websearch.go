@@ -1,6 +1,8 @@
 // Package websearch provides types for the Web Search API.
 package websearch
 
+import "time"
+
 // SensitiveWordCheck represents sensitive word check configuration.
 type SensitiveWordCheck struct {
 	// Type is the sensitive word type, currently only supports "ALL"
@@ -24,8 +26,9 @@ type SearchIntentResp struct {
 	// Query is the search optimized query
 	Query string `json:"query"`
 
-	// Intent is the determined intent type
-	Intent string `json:"intent"`
+	// Intent is the determined intent type, one of the Intent constants
+	// or a type added to the API since
+	Intent IntentType `json:"intent"`
 
 	// Keywords are the search keywords
 	Keywords string `json:"keywords"`
@@ -51,11 +54,19 @@ type SearchResultResp struct {
 	// Refer is the reference number (e.g., "[ref_1]")
 	Refer string `json:"refer"`
 
-	// PublishDate is the publication date
+	// PublishDate is the publication date, as returned by the API
 	PublishDate string `json:"publish_date"`
 
+	// PublishDateParsed is PublishDate parsed, or the zero time if the
+	// date is missing or in a format the SDK does not recognize
+	PublishDateParsed time.Time `json:"-"`
+
 	// Images are URLs of images associated with the result
 	Images []string `json:"images,omitempty"`
+
+	// Score is the relevance score of the result, higher for more
+	// relevant results; zero if the API did not score it
+	Score float64 `json:"score,omitempty"`
 }
 
 // WebSearchResponse represents the web search response.
@@ -74,6 +85,13 @@ type WebSearchResponse struct {
 
 	// SearchResult contains search results
 	SearchResult []SearchResultResp `json:"search_result,omitempty"`
+
+	// NextPageToken is the token for fetching the next page of results.
+	// Empty when the API does not use page tokens or there are no more pages.
+	NextPageToken string `json:"next_page_token,omitempty"`
+
+	// HasMore indicates whether more results are available.
+	HasMore bool `json:"has_more,omitempty"`
 }
 
 // GetResults returns the search results.
@@ -86,6 +104,11 @@ func (r *WebSearchResponse) HasIntent() bool {
 	return r.SearchIntent != nil
 }
 
+// HasNextPage returns true if the API reported more results after this page.
+func (r *WebSearchResponse) HasNextPage() bool {
+	return r.NextPageToken != "" || r.HasMore
+}
+
 // WebSearchRequest represents a web search request.
 type WebSearchRequest struct {
 	// SearchQuery is the search query text (required)
@@ -123,6 +146,12 @@ type WebSearchRequest struct {
 
 	// IncludeImage enables image inclusion in results
 	IncludeImage bool `json:"include_image,omitempty"`
+
+	// Offset is the number of results to skip
+	Offset int `json:"offset,omitempty"`
+
+	// PageToken is the next page token from a previous response
+	PageToken string `json:"page_token,omitempty"`
 }
 
 // Recency filter constants (per Z.ai API specification)
@@ -219,3 +248,15 @@ func (r *WebSearchRequest) SetIncludeImage(include bool) *WebSearchRequest {
 	r.IncludeImage = include
 	return r
 }
+
+// SetOffset sets the number of results to skip.
+func (r *WebSearchRequest) SetOffset(offset int) *WebSearchRequest {
+	r.Offset = offset
+	return r
+}
+
+// SetPageToken sets the page token returned by a previous response.
+func (r *WebSearchRequest) SetPageToken(token string) *WebSearchRequest {
+	r.PageToken = token
+	return r
+}
@@ -136,7 +136,7 @@ func TestWebSearchResponse(t *testing.T) {
 		assert.True(t, resp.HasIntent())
 		assert.NotNil(t, resp.SearchIntent)
 		assert.Equal(t, "AI trends 2024", resp.SearchIntent.Query)
-		assert.Equal(t, "informational", resp.SearchIntent.Intent)
+		assert.Equal(t, IntentType("informational"), resp.SearchIntent.Intent)
 		assert.Equal(t, "AI, trends, 2024", resp.SearchIntent.Keywords)
 
 		// Check search results
@@ -247,7 +247,7 @@ func TestSearchIntentResp(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.Equal(t, "best AI tools", intent.Query)
-	assert.Equal(t, "commercial", intent.Intent)
+	assert.Equal(t, IntentType("commercial"), intent.Intent)
 	assert.Equal(t, "AI, tools, best", intent.Keywords)
 }
 
@@ -306,6 +306,37 @@ func TestSearchResultResp(t *testing.T) {
 	})
 }
 
+func TestWebSearchRequest_Pagination(t *testing.T) {
+	t.Parallel()
+
+	req := NewWebSearchRequest("q").SetOffset(20).SetPageToken("tok_abc")
+	assert.Equal(t, 20, req.Offset)
+	assert.Equal(t, "tok_abc", req.PageToken)
+
+	data, err := json.Marshal(req)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"offset":20`)
+	assert.Contains(t, string(data), `"page_token":"tok_abc"`)
+
+	data, err = json.Marshal(NewWebSearchRequest("q"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "offset")
+	assert.NotContains(t, string(data), "page_token")
+}
+
+func TestWebSearchResponse_HasNextPage(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, (&WebSearchResponse{}).HasNextPage())
+	assert.True(t, (&WebSearchResponse{NextPageToken: "tok"}).HasNextPage())
+	assert.True(t, (&WebSearchResponse{HasMore: true}).HasNextPage())
+
+	var resp WebSearchResponse
+	require.NoError(t, json.Unmarshal([]byte(`{"next_page_token": "tok_2", "has_more": true}`), &resp))
+	assert.Equal(t, "tok_2", resp.NextPageToken)
+	assert.True(t, resp.HasMore)
+}
+
 func TestRecencyFilterConstants(t *testing.T) {
 	t.Parallel()
 
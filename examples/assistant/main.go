@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/assistant"
 	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
@@ -201,38 +202,22 @@ func conversationWithAttachmentsExample(ctx context.Context, client *zai.Client)
 }
 
 func queryConversationUsageExample(ctx context.Context, client *zai.Client) {
-	// Query conversation history and usage
-	page := 1
-	pageSize := 10
+	// Iterate over the conversation history and usage, fetching pages as needed
+	iter := client.Assistant.QueryConversationUsageAll(ctx, "asst_123", assistant.WithUsagePageSize(10))
 
-	resp, err := client.Assistant.QueryConversationUsage(ctx, "asst_123", page, pageSize)
-	if err != nil {
-		log.Printf("Error: %v", err)
-		return
-	}
-
-	fmt.Printf("Conversation History (Page %d):\n", page)
-	for _, conv := range resp.GetConversations() {
+	fmt.Println("Conversation History:")
+	for iter.Next() {
+		conv := iter.Current()
 		fmt.Printf("\nConversation ID: %s\n", conv.ID)
-		fmt.Printf("  Created: %d\n", conv.CreateTime)
-		fmt.Printf("  Updated: %d\n", conv.UpdateTime)
+		fmt.Printf("  Created: %s\n", conv.CreateTime.Format(time.RFC3339))
+		fmt.Printf("  Updated: %s\n", conv.UpdateTime.Format(time.RFC3339))
 		fmt.Printf("  Token Usage:\n")
 		fmt.Printf("    Prompt: %d\n", conv.Usage.PromptTokens)
 		fmt.Printf("    Completion: %d\n", conv.Usage.CompletionTokens)
 		fmt.Printf("    Total: %d\n", conv.Usage.TotalTokens)
 	}
-
-	if resp.HasMore() {
-		fmt.Println("\nMore conversations available. Fetch next page...")
-		// Fetch next page
-		nextPage := page + 1
-		nextResp, err := client.Assistant.QueryConversationUsage(ctx, "asst_123", nextPage, pageSize)
-		if err != nil {
-			log.Printf("Error: %v", err)
-			return
-		}
-
-		fmt.Printf("Page %d: %d conversations\n", nextPage, len(nextResp.GetConversations()))
+	if err := iter.Err(); err != nil {
+		log.Printf("Error: %v", err)
 	}
 }
 
@@ -140,8 +140,11 @@ func retrieveBatchExample(ctx context.Context, client *zai.Client) {
 }
 
 func listBatchesExample(ctx context.Context, client *zai.Client) {
-	// List all batches (first page)
-	resp, err := client.Batch.List(ctx, "", 10)
+	// List the batches of the last week (first page)
+	resp, err := client.Batch.ListWithParams(ctx, &batch.BatchListParams{
+		Limit:        10,
+		CreatedAfter: time.Now().Add(-7 * 24 * time.Hour),
+	})
 	if err != nil {
 		log.Printf("Error: %v", err)
 		return
@@ -252,34 +255,19 @@ func cancelBatchExample(ctx context.Context, client *zai.Client) {
 }
 
 func paginatedListExample(ctx context.Context, client *zai.Client) {
-	// Demonstrate cursor-based pagination
+	// The iterator follows the cursor and fetches pages as needed
 	var allBatches []batch.Batch
-	cursor := ""
 	pageSize := 5
 
-	fmt.Printf("Fetching all batches using pagination (page size: %d)\n", pageSize)
-
-	for page := 1; ; page++ {
-		fmt.Printf("Fetching page %d...\n", page)
-
-		resp, err := client.Batch.List(ctx, cursor, pageSize)
-		if err != nil {
-			log.Printf("Error: %v", err)
-			return
-		}
-
-		batches := resp.GetBatches()
-		fmt.Printf("  Retrieved %d batches\n", len(batches))
+	fmt.Printf("Fetching all batches using auto-pagination (page size: %d)\n", pageSize)
 
-		allBatches = append(allBatches, batches...)
-
-		if !resp.HasMoreBatches() {
-			fmt.Printf("  ✓ No more batches\n")
-			break
-		}
-
-		cursor = resp.LastID
-		fmt.Printf("  → Next cursor: %s\n", cursor)
+	iter := client.Batch.ListAutoPaging(ctx, pageSize)
+	for iter.Next() {
+		allBatches = append(allBatches, *iter.Current())
+	}
+	if err := iter.Err(); err != nil {
+		log.Printf("Error: %v", err)
+		return
 	}
 
 	fmt.Printf("\nTotal batches retrieved: %d\n", len(allBatches))
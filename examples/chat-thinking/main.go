@@ -142,29 +142,21 @@ How many chickens and how many rabbits does the farmer have?`),
 
 func glm47PreservedThinkingExample(ctx context.Context, client *zai.Client) {
 	// Demonstrate preserved thinking across multiple turns
-	// This maintains reasoning continuity by preserving reasoning_content
+	// The session keeps the history and sends each reply back with its
+	// reasoning_content, which maintains reasoning continuity
+	session := client.Chat.NewSession("glm-4.7",
+		chat.WithPreservedThinking(),
+		chat.WithSessionRequest(func(req *chat.ChatCompletionRequest) {
+			req.SetTemperature(0.7).SetMaxTokens(2000)
+		}),
+	)
 
 	// Turn 1: Initial reasoning task
-	messages := []chat.Message{
-		chat.NewUserMessage(`Let's work on a complex problem together.
+	fmt.Println("Turn 1: Initial Problem")
+	resp1, err := session.Send(ctx, `Let's work on a complex problem together.
 
 First, help me understand: If a store sells apples for $2 each and oranges for $3 each,
-and someone spent $23 buying 9 fruits total, how many of each did they buy?`),
-	}
-
-	temp := 0.7
-	maxTokens := 2000
-	req := &chat.ChatCompletionRequest{
-		Model:       "glm-4.7",
-		Messages:    messages,
-		Temperature: &temp,
-		MaxTokens:   &maxTokens,
-	}
-	// Enable preserved thinking for multi-turn reasoning continuity
-	req.EnablePreservedThinking()
-
-	fmt.Println("Turn 1: Initial Problem")
-	resp1, err := client.Chat.Create(ctx, req)
+and someone spent $23 buying 9 fruits total, how many of each did they buy?`)
 	if err != nil {
 		log.Printf("Error: %v", err)
 		return
@@ -176,16 +168,11 @@ and someone spent $23 buying 9 fruits total, how many of each did they buy?`),
 		fmt.Printf("\n[Reasoning preserved: %d chars]\n", len(reasoning))
 	}
 
-	// Turn 2: Follow-up question - preserve the reasoning_content from Turn 1
-	assistantMsg := resp1.GetFirstChoice().Message
-	messages = append(messages, assistantMsg) // Include full message with reasoning_content
-	messages = append(messages, chat.NewUserMessage("Now, can you verify this answer by checking the totals?"))
-
-	req.Messages = messages
-
+	// Turn 2: Follow-up question - the session sends Turn 1 with its
+	// reasoning_content
 	fmt.Println("\n" + strings.Repeat("-", 60))
 	fmt.Println("Turn 2: Follow-up Verification")
-	resp2, err := client.Chat.Create(ctx, req)
+	resp2, err := session.Send(ctx, "Now, can you verify this answer by checking the totals?")
 	if err != nil {
 		log.Printf("Error: %v", err)
 		return
@@ -197,7 +184,7 @@ and someone spent $23 buying 9 fruits total, how many of each did they buy?`),
 		fmt.Printf("\n[Reasoning preserved: %d chars]\n", len(reasoning))
 	}
 
-	fmt.Println("\nNote: The model maintained reasoning continuity across both turns.")
+	fmt.Printf("\nNote: The model maintained reasoning continuity across both turns (%d messages in history).\n", len(session.History()))
 }
 
 func basicThinkingExample(ctx context.Context, client *zai.Client) {
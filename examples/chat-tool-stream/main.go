@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// WeatherArgs are the arguments of the get_weather tool.
+type WeatherArgs struct {
+	Location string `json:"location"`
+}
+
+func main() {
+	// Create a new client
+	client, err := zai.NewClient(
+		zai.WithAPIKey("your-api-key.your-secret"),
+	)
+	if err != nil {
+		log.Fatalf("Failed to create client: %v", err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+
+	weatherTool := chat.NewFunctionTool("get_weather", "Get the current weather for a city", map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"location": map[string]any{"type": "string", "description": "City name"},
+		},
+		"required": []string{"location"},
+	})
+	messages := []chat.Message{
+		chat.NewUserMessage("What's the weather in Paris and Oslo?"),
+	}
+
+	// Stream until the model answers without calling tools. Tool streaming
+	// sends the arguments as they are generated instead of buffering them.
+	for {
+		req := chat.NewChatCompletionRequest(chat.ModelGLM47, messages).
+			AddTool(weatherTool).
+			SetToolStream(true)
+
+		msg, err := streamTurn(ctx, client, req)
+		if err != nil {
+			log.Fatalf("Stream failed: %v", err)
+		}
+		messages = append(messages, msg)
+		if len(msg.ToolCalls) == 0 {
+			fmt.Println()
+			return
+		}
+
+		for _, tc := range msg.ToolCalls {
+			result, err := runTool(tc)
+			if err != nil {
+				log.Fatalf("Tool failed: %v", err)
+			}
+			messages = append(messages, result)
+		}
+	}
+}
+
+// streamTurn streams one model turn, printing content as it arrives, and
+// returns the assistant message with its completed tool calls.
+func streamTurn(ctx context.Context, client *zai.Client, req *chat.ChatCompletionRequest) (chat.Message, error) {
+	stream, err := client.Chat.CreateStream(ctx, req)
+	if err != nil {
+		return chat.Message{}, err
+	}
+
+	// The collector buffers tool call fragments and emits each tool call
+	// once its arguments are complete
+	collector := chat.NewToolCallCollector(stream)
+	defer collector.Close()
+
+	for collector.Next() {
+		event := collector.Current()
+		if event.ToolCall != nil {
+			fmt.Printf("\n[calling %s %s]\n", event.ToolCall.Function.Name, event.ToolCall.Function.Arguments)
+			continue
+		}
+		fmt.Print(event.Chunk.GetContent())
+	}
+	if err := collector.Err(); err != nil {
+		return chat.Message{}, err
+	}
+
+	return collector.Message(), nil
+}
+
+// runTool runs a tool call and returns its result message.
+func runTool(tc chat.ToolCall) (chat.Message, error) {
+	args, err := chat.UnmarshalToolArgs[WeatherArgs](tc)
+	if err != nil {
+		return chat.Message{}, err
+	}
+	return chat.NewToolResultMessage(tc.ID, map[string]any{
+		"location":    args.Location,
+		"temperature": 18,
+		"conditions":  "partly cloudy",
+	})
+}
@@ -37,6 +37,9 @@ func main() {
 
 	fmt.Println("\n=== Example 4: With Parameters ===")
 	parametersExample(ctx, client)
+
+	fmt.Println("\n=== Example 5: Function Calling ===")
+	functionCallingExample(ctx, client)
 }
 
 func basicExample(ctx context.Context, client *zai.Client) {
@@ -143,3 +146,76 @@ func parametersExample(ctx context.Context, client *zai.Client) {
 		fmt.Printf("Finish reason: %s\n", choice.FinishReason)
 	}
 }
+
+// weatherArgs are the arguments of the get_weather tool.
+type weatherArgs struct {
+	Location string `json:"location"`
+	Unit     string `json:"unit"`
+}
+
+// Validate reports missing required arguments.
+func (a weatherArgs) Validate() error {
+	if a.Location == "" {
+		return fmt.Errorf("location is required")
+	}
+	return nil
+}
+
+func functionCallingExample(ctx context.Context, client *zai.Client) {
+	tool := chat.NewFunctionTool(
+		"get_weather",
+		"Get the current weather in a given location",
+		map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"location": map[string]interface{}{"type": "string"},
+				"unit":     map[string]interface{}{"type": "string", "enum": []string{"celsius", "fahrenheit"}},
+			},
+			"required": []string{"location"},
+		},
+	)
+
+	messages := []chat.Message{
+		chat.NewUserMessage("What's the weather in Paris?"),
+	}
+	req := chat.NewChatCompletionRequest("glm-4.7", messages).AddTool(tool)
+
+	resp, err := client.Chat.Create(ctx, req)
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+
+	choice := resp.GetFirstChoice()
+	if choice == nil || len(choice.Message.ToolCalls) == 0 {
+		fmt.Printf("Response: %s\n", resp.GetContent())
+		return
+	}
+
+	// Answer each tool call with a typed result
+	messages = append(messages, choice.Message)
+	for _, tc := range choice.Message.ToolCalls {
+		args, err := chat.UnmarshalToolArgs[weatherArgs](tc)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return
+		}
+		fmt.Printf("Tool called: %s(%s)\n", tc.Function.Name, args.Location)
+
+		result := map[string]interface{}{"location": args.Location, "temperature": 22, "condition": "sunny"}
+		msg, err := chat.NewToolResultMessage(tc.ID, result)
+		if err != nil {
+			log.Printf("Error: %v", err)
+			return
+		}
+		messages = append(messages, msg)
+	}
+
+	resp, err = client.Chat.Create(ctx, chat.NewChatCompletionRequest("glm-4.7", messages))
+	if err != nil {
+		log.Printf("Error: %v", err)
+		return
+	}
+
+	fmt.Printf("Response: %s\n", resp.GetContent())
+}
@@ -159,14 +159,9 @@ func getResultsTextExample(ctx context.Context, client *zai.Client) {
 		return
 	}
 
-	// Small delay to allow processing
+	// Wait for the task to finish processing
 	fmt.Println("Waiting for task to complete...")
-	time.Sleep(2 * time.Second)
-
-	// Get results as text
-	req := fileparser.NewContentRequest(taskID, fileparser.FormatTypeText)
-
-	resp, err := client.FileParser.Content(ctx, req)
+	resp, err := client.FileParser.WaitForContent(ctx, taskID, fileparser.FormatTypeText, 5*time.Second, 2*time.Minute)
 	if err != nil {
 		log.Fatalf("Failed to get parsing results: %v", err)
 	}
@@ -197,14 +192,9 @@ func getResultsDownloadLinkExample(ctx context.Context, client *zai.Client) {
 		return
 	}
 
-	// Small delay to allow processing
+	// Wait for the task to finish processing
 	fmt.Println("Waiting for task to complete...")
-	time.Sleep(2 * time.Second)
-
-	// Get results as download link
-	req := fileparser.NewContentRequest(taskID, fileparser.FormatTypeDownloadLink)
-
-	resp, err := client.FileParser.Content(ctx, req)
+	resp, err := client.FileParser.WaitForContent(ctx, taskID, fileparser.FormatTypeDownloadLink, 5*time.Second, 2*time.Minute)
 	if err != nil {
 		log.Fatalf("Failed to get parsing results: %v", err)
 	}
@@ -57,9 +57,10 @@ func main() {
 func uploadFileExample(ctx context.Context, client *zai.Client) *files.File {
 	// Create a sample file content
 	// In a real scenario, you would read from an actual file
-	fileContent := strings.NewReader(`{"prompt": "Hello", "completion": "World"}
-{"prompt": "What is AI?", "completion": "Artificial Intelligence"}
-{"prompt": "Go is", "completion": "a programming language"}`)
+	// Fine-tune files are JSONL with a "messages" conversation per line
+	fileContent := strings.NewReader(`{"messages": [{"role": "user", "content": "Hello"}, {"role": "assistant", "content": "World"}]}
+{"messages": [{"role": "user", "content": "What is AI?"}, {"role": "assistant", "content": "Artificial Intelligence"}]}
+{"messages": [{"role": "user", "content": "Go is"}, {"role": "assistant", "content": "a programming language"}]}`)
 
 	// Create upload request
 	req := files.NewFileUploadRequest(
@@ -179,8 +180,8 @@ func uploadRealFileExample(ctx context.Context, client *zai.Client) {
 	defer os.Remove(tmpFile.Name())
 
 	// Write some sample data
-	_, err = tmpFile.WriteString(`{"prompt": "Example 1", "completion": "Response 1"}
-{"prompt": "Example 2", "completion": "Response 2"}`)
+	_, err = tmpFile.WriteString(`{"messages": [{"role": "user", "content": "Example 1"}, {"role": "assistant", "content": "Response 1"}]}
+{"messages": [{"role": "user", "content": "Example 2"}, {"role": "assistant", "content": "Response 2"}]}`)
 	if err != nil {
 		log.Printf("Error writing to temp file: %v", err)
 		return
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 
@@ -188,60 +189,43 @@ func streamingWebSearchExample(ctx context.Context, client *zai.Client) {
 
 	fmt.Println("Streaming search results...")
 
-	var intentDisplayed bool
 	var resultCount int
 
-	for stream.Next() {
-		chunk := stream.Current()
-		if chunk == nil {
-			continue
-		}
-
-		// Process chunk
-		for _, choice := range chunk.Choices {
-			// Display role if present
-			if choice.Delta.Role != "" && !intentDisplayed {
-				fmt.Printf("\nRole: %s\n", choice.Delta.Role)
-			}
-
-			// Process tool calls
-			for _, toolCall := range choice.Delta.ToolCalls {
-				// Display search intent
-				if toolCall.SearchIntent != nil && !intentDisplayed {
-					fmt.Println("\n--- Search Intent ---")
-					fmt.Printf("Query: %s\n", toolCall.SearchIntent.Query)
-					fmt.Printf("Intent: %s\n", toolCall.SearchIntent.Intent)
-					fmt.Printf("Keywords: %s\n", toolCall.SearchIntent.Keywords)
-					intentDisplayed = true
-				}
-
-				// Display search result
-				if toolCall.SearchResult != nil {
-					resultCount++
-					fmt.Printf("\n--- Result %d ---\n", resultCount)
-					fmt.Printf("Title: %s\n", toolCall.SearchResult.Title)
-					fmt.Printf("Link: %s\n", toolCall.SearchResult.Link)
-					fmt.Printf("Source: %s\n", toolCall.SearchResult.Media)
-					fmt.Printf("Content: %s\n", truncateString(toolCall.SearchResult.Content, 80))
-				}
-
-				// Display recommendation
-				if toolCall.SearchRecommend != nil {
-					fmt.Printf("\n--- Recommendation ---\n")
-					fmt.Printf("Try: %s\n", toolCall.SearchRecommend.Query)
-				}
-			}
-
-			// Display finish reason
-			if choice.FinishReason != "" {
-				fmt.Printf("\nFinished: %s\n", choice.FinishReason)
-			}
-		}
+	// The aggregator reports each part as it arrives and assembles the
+	// same response as the non-streaming call
+	agg := tools.NewWebSearchStreamAggregator(stream).
+		OnIntent(func(intent *tools.SearchIntent) {
+			fmt.Println("\n--- Search Intent ---")
+			fmt.Printf("Query: %s\n", intent.Query)
+			fmt.Printf("Intent: %s\n", intent.Intent)
+			fmt.Printf("Keywords: %s\n", intent.Keywords)
+		}).
+		OnResult(func(result *tools.SearchResult) {
+			resultCount++
+			fmt.Printf("\n--- Result %d ---\n", resultCount)
+			fmt.Printf("Title: %s\n", result.Title)
+			fmt.Printf("Link: %s\n", result.Link)
+			fmt.Printf("Source: %s\n", result.Media)
+			fmt.Printf("Content: %s\n", truncateString(result.Content, 80))
+		}).
+		OnRecommend(func(recommend *tools.SearchRecommend) {
+			fmt.Printf("\n--- Recommendation ---\n")
+			fmt.Printf("Try: %s\n", recommend.Query)
+		})
+
+	resp, err := agg.Run()
+	switch {
+	case errors.Is(err, tools.ErrWebSearchTruncated):
+		fmt.Println("\nThe stream ended early; results may be incomplete")
+	case err != nil:
+		log.Printf("Stream error: %v", err)
+		return
 	}
 
-	// Check for stream errors
-	if err := stream.Err(); err != nil {
-		log.Printf("Stream error: %v", err)
+	for _, choice := range resp.GetChoices() {
+		if choice.FinishReason != "" {
+			fmt.Printf("\nFinished: %s\n", choice.FinishReason)
+		}
 	}
 
 	fmt.Printf("\nTotal results streamed: %d\n", resultCount)
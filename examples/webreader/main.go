@@ -89,7 +89,7 @@ func markdownExample(ctx context.Context, client *zai.Client) {
 	}
 
 	req := webreader.NewRequest(url).
-		SetReturnFormat("markdown").
+		SetReturnFormat(webreader.FormatMarkdown).
 		SetRetainImages(true).
 		SetRequestID("markdown_req_123")
 
@@ -129,7 +129,7 @@ func summaryExample(ctx context.Context, client *zai.Client) {
 	}
 
 	req := webreader.NewRequest(url).
-		SetReturnFormat("text").
+		SetReturnFormat(webreader.FormatText).
 		SetWithImagesSummary(true).
 		SetWithLinksSummary(true).
 		SetUserID("user_789")
@@ -196,7 +196,7 @@ func noCacheExample(ctx context.Context, client *zai.Client) {
 
 	req := webreader.NewRequest(url).
 		SetNoCache(true).
-		SetReturnFormat("markdown").
+		SetReturnFormat(webreader.FormatMarkdown).
 		SetTimeout("30").
 		SetRequestID("nocache_req_456")
 
@@ -89,6 +89,7 @@ func (tg *TokenGenerator) EnableCache() {
 // GenerateToken generates a JWT token from the API key.
 // The API key should be in the format "apikey.secret".
 // If caching is enabled, it returns a cached token if available and not expired.
+// Errors never quote the API key or its secret, so they are safe to log.
 func (tg *TokenGenerator) GenerateToken(apiKey string) (string, error) {
 	if apiKey == "" {
 		return "", ErrEmptyAPIKey
@@ -105,6 +105,20 @@ func TestTokenGenerator_GenerateToken_InvalidAPIKey(t *testing.T) {
 	}
 }
 
+func TestTokenGenerator_ErrorsOmitSecret(t *testing.T) {
+	t.Parallel()
+
+	for _, apiKey := range []string{"id.s3cr3tv4lue.extra", ".s3cr3tv4lue", "s3cr3tv4lue"} {
+		_, err := NewTokenGenerator().GenerateToken(apiKey)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "s3cr3tv4lue")
+
+		err = ValidateAPIKey(apiKey)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "s3cr3tv4lue")
+	}
+}
+
 func TestTokenGenerator_VerifyToken(t *testing.T) {
 	t.Parallel()
 
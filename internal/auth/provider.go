@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyProvider returns the API key to authenticate a request with, for
+// example from a secret manager.
+type KeyProvider func(ctx context.Context) (string, error)
+
+// ValidateAPIKey checks that apiKey has the "id.secret" format.
+func ValidateAPIKey(apiKey string) error {
+	if apiKey == "" {
+		return ErrEmptyAPIKey
+	}
+
+	id, secret, ok := strings.Cut(apiKey, ".")
+	if !ok || id == "" || secret == "" || strings.Contains(secret, ".") {
+		return ErrInvalidAPIKey
+	}
+	return nil
+}
+
+// KeyCache caches the keys returned by a KeyProvider.
+// It is safe for concurrent use; concurrent fetches are coalesced.
+type KeyCache struct {
+	provider KeyProvider
+	ttl      time.Duration
+	onKey    func(key string)
+
+	mu      sync.Mutex
+	key     string
+	fetched time.Time
+}
+
+// NewKeyCache creates a cache that keeps keys from provider for ttl.
+// A ttl of zero or less fetches a key for every request. onKey, if not
+// nil, is called with every key that differs from the previous one.
+func NewKeyCache(provider KeyProvider, ttl time.Duration, onKey func(key string)) *KeyCache {
+	return &KeyCache{
+		provider: provider,
+		ttl:      ttl,
+		onKey:    onKey,
+	}
+}
+
+// Get returns the cached key, fetching a new one if the cache expired.
+func (c *KeyCache) Get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && c.ttl > 0 && time.Since(c.fetched) < c.ttl {
+		return c.key, nil
+	}
+	return c.fetch(ctx)
+}
+
+// Refresh fetches a new key regardless of the cache, for example after
+// the API rejected the cached key.
+func (c *KeyCache) Refresh(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.fetch(ctx)
+}
+
+// fetch calls the provider and caches its key. c.mu must be held.
+func (c *KeyCache) fetch(ctx context.Context) (string, error) {
+	key, err := c.provider(ctx)
+	if err != nil {
+		return "", fmt.Errorf("API key provider: %w", err)
+	}
+	if err := ValidateAPIKey(key); err != nil {
+		return "", err
+	}
+
+	if key != c.key && c.onKey != nil {
+		c.onKey(key)
+	}
+	c.key = key
+	c.fetched = time.Now()
+	return key, nil
+}
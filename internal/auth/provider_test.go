@@ -0,0 +1,92 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAPIKey(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateAPIKey("id.secret"))
+	assert.Equal(t, ErrEmptyAPIKey, ValidateAPIKey(""))
+
+	for _, key := range []string{"id", "id.", ".secret", "id.secret.extra"} {
+		assert.Equal(t, ErrInvalidAPIKey, ValidateAPIKey(key), key)
+	}
+}
+
+func TestKeyCache(t *testing.T) {
+	t.Parallel()
+
+	var current atomic.Value
+	current.Store("key1.secret")
+	var calls atomic.Int32
+	provider := func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return current.Load().(string), nil
+	}
+
+	var seen []string
+	cache := NewKeyCache(provider, time.Hour, func(key string) { seen = append(seen, key) })
+
+	key, err := cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key1.secret", key)
+
+	key, err = cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key1.secret", key)
+	assert.Equal(t, int32(1), calls.Load(), "the key is cached")
+
+	current.Store("key2.secret")
+	key, err = cache.Refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key2.secret", key)
+
+	key, err = cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "key2.secret", key)
+	assert.Equal(t, []string{"key1.secret", "key2.secret"}, seen)
+}
+
+func TestKeyCache_NoTTL(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	cache := NewKeyCache(func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		return "id.secret", nil
+	}, 0, nil)
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.Get(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestKeyCache_Errors(t *testing.T) {
+	t.Parallel()
+
+	errVault := errors.New("vault unavailable")
+	cache := NewKeyCache(func(ctx context.Context) (string, error) {
+		return "", errVault
+	}, time.Hour, nil)
+
+	_, err := cache.Get(context.Background())
+	assert.ErrorIs(t, err, errVault)
+
+	cache = NewKeyCache(func(ctx context.Context) (string, error) {
+		return "not-a-key", nil
+	}, time.Hour, nil)
+
+	_, err = cache.Get(context.Background())
+	assert.Equal(t, ErrInvalidAPIKey, err)
+}
@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxRefreshLeeway is how long before their expiry tokens from a
+// TokenSource are refreshed, at most. Tokens valid for less than twice as
+// long are refreshed halfway through their lifetime.
+const MaxRefreshLeeway = 30 * time.Second
+
+// ErrEmptyToken is returned when a TokenSource returns an empty token.
+var ErrEmptyToken = errors.New("token source returned an empty token")
+
+// TokenSource returns a bearer token and the time it expires, or the zero
+// time if it does not expire.
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// StaticToken returns a TokenSource of a token that never expires.
+func StaticToken(token string) TokenSource {
+	return func(ctx context.Context) (string, time.Time, error) {
+		return token, time.Time{}, nil
+	}
+}
+
+// SourceCache caches the tokens returned by a TokenSource until shortly
+// before they expire. It is safe for concurrent use: concurrent fetches
+// share one call of the source, and a caller whose context ends stops
+// waiting for it without canceling it for the others.
+type SourceCache struct {
+	source  TokenSource
+	onToken func(token string)
+
+	mu      sync.Mutex
+	token   string
+	refresh time.Time // zero if the token does not expire
+	call    *sourceCall
+}
+
+// sourceCall is a call of the source in flight.
+type sourceCall struct {
+	done  chan struct{}
+	token string
+	err   error
+}
+
+// NewSourceCache creates a cache of the tokens of source. onToken, if not
+// nil, is called with every token that differs from the previous one.
+func NewSourceCache(source TokenSource, onToken func(token string)) *SourceCache {
+	return &SourceCache{source: source, onToken: onToken}
+}
+
+// Get returns the cached token, fetching a new one if there is none or it
+// is about to expire.
+func (c *SourceCache) Get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if c.token != "" && (c.refresh.IsZero() || time.Now().Before(c.refresh)) {
+		token := c.token
+		c.mu.Unlock()
+		return token, nil
+	}
+	return c.fetch(ctx)
+}
+
+// Refresh fetches a new token after the API rejected stale, unless the
+// cached token already differs from it.
+func (c *SourceCache) Refresh(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	if c.token != "" && c.token != stale {
+		token := c.token
+		c.mu.Unlock()
+		return token, nil
+	}
+	return c.fetch(ctx)
+}
+
+// fetch waits for a call of the source, starting one unless one is in
+// flight. c.mu must be held, and is released.
+func (c *SourceCache) fetch(ctx context.Context) (string, error) {
+	call := c.call
+	if call == nil {
+		call = &sourceCall{done: make(chan struct{})}
+		c.call = call
+		go c.run(context.WithoutCancel(ctx), call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// run calls the source and caches its token.
+func (c *SourceCache) run(ctx context.Context, call *sourceCall) {
+	token, expiry, err := c.source(ctx)
+	if err == nil && token == "" {
+		err = ErrEmptyToken
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer close(call.done)
+	c.call = nil
+
+	if err != nil {
+		call.err = fmt.Errorf("token source: %w", err)
+		return
+	}
+	if token != c.token && c.onToken != nil {
+		c.onToken(token)
+	}
+	c.token = token
+	c.refresh = refreshTime(time.Now(), expiry)
+	call.token = token
+}
+
+// refreshTime returns when a token fetched at now that expires at expiry
+// should be refreshed.
+func refreshTime(now, expiry time.Time) time.Time {
+	if expiry.IsZero() {
+		return time.Time{}
+	}
+	leeway := min(MaxRefreshLeeway, expiry.Sub(now)/2)
+	return expiry.Add(-max(leeway, 0))
+}
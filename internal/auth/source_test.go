@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource returns a source of the tokens "token-1", "token-2", ...
+// that expire after ttl, and the number of calls.
+func countingSource(ttl time.Duration) (TokenSource, *atomic.Int32) {
+	var calls atomic.Int32
+	return func(ctx context.Context) (string, time.Time, error) {
+		n := calls.Add(1)
+		return fmt.Sprintf("token-%d", n), time.Now().Add(ttl), nil
+	}, &calls
+}
+
+func TestSourceCache_Expiry(t *testing.T) {
+	t.Parallel()
+
+	source, calls := countingSource(100 * time.Millisecond)
+	var seen []string
+	cache := NewSourceCache(source, func(token string) { seen = append(seen, token) })
+
+	token, err := cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token)
+
+	token, err = cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-1", token, "the token is cached")
+
+	// Refreshed halfway through its lifetime, before it expires
+	time.Sleep(60 * time.Millisecond)
+	token, err = cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token-2", token)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, []string{"token-1", "token-2"}, seen)
+}
+
+func TestSourceCache_Static(t *testing.T) {
+	t.Parallel()
+
+	cache := NewSourceCache(StaticToken("fixed"), nil)
+	for i := 0; i < 3; i++ {
+		token, err := cache.Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "fixed", token)
+	}
+
+	token, err := cache.Refresh(context.Background(), "fixed")
+	require.NoError(t, err)
+	assert.Equal(t, "fixed", token)
+}
+
+func TestSourceCache_SingleFlight(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	var calls atomic.Int32
+	cache := NewSourceCache(func(ctx context.Context) (string, time.Time, error) {
+		calls.Add(1)
+		<-release
+		return "token", time.Now().Add(time.Hour), nil
+	}, nil)
+
+	const callers = 50
+	var wg sync.WaitGroup
+	tokens := make([]string, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _ = cache.Get(context.Background())
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, token := range tokens {
+		assert.Equal(t, "token", token)
+	}
+}
+
+func TestSourceCache_Refresh(t *testing.T) {
+	t.Parallel()
+
+	source, calls := countingSource(time.Hour)
+	cache := NewSourceCache(source, nil)
+
+	stale, err := cache.Get(context.Background())
+	require.NoError(t, err)
+
+	// Concurrent refreshes of the same rejected token fetch once
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := cache.Refresh(context.Background(), stale)
+			assert.NoError(t, err)
+			assert.Equal(t, "token-2", token)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestSourceCache_CanceledWaiter(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	cache := NewSourceCache(func(ctx context.Context) (string, time.Time, error) {
+		<-release
+		return "token", time.Time{}, ctx.Err()
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// The fetch goes on for the other callers
+	close(release)
+	token, err := cache.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
+
+func TestSourceCache_Errors(t *testing.T) {
+	t.Parallel()
+
+	errBroker := errors.New("broker unavailable")
+	cache := NewSourceCache(func(ctx context.Context) (string, time.Time, error) {
+		return "", time.Time{}, errBroker
+	}, nil)
+	_, err := cache.Get(context.Background())
+	assert.ErrorIs(t, err, errBroker)
+	assert.ErrorContains(t, err, "token source")
+
+	cache = NewSourceCache(StaticToken(""), nil)
+	_, err = cache.Get(context.Background())
+	assert.ErrorIs(t, err, ErrEmptyToken)
+}
+
+func TestRefreshTime(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	assert.True(t, refreshTime(now, time.Time{}).IsZero())
+	assert.Equal(t, now.Add(time.Hour-MaxRefreshLeeway), refreshTime(now, now.Add(time.Hour)))
+	assert.Equal(t, now.Add(10*time.Second), refreshTime(now, now.Add(20*time.Second)))
+	assert.Equal(t, now.Add(-time.Second), refreshTime(now, now.Add(-time.Second)), "expired tokens are refreshed")
+}
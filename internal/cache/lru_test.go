@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU_GetSet(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(10)
+
+	_, ok := c.Get("missing")
+	assert.False(t, ok)
+
+	c.Set("a", []byte("1"), 0)
+	v, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	c.Set("a", []byte("2"), 0)
+	v, _ = c.Get("a")
+	assert.Equal(t, []byte("2"), v)
+	assert.Equal(t, 1, c.Len())
+
+	c.Delete("a")
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(2)
+	c.Set("a", []byte("1"), 0)
+	c.Set("b", []byte("2"), 0)
+
+	// Touch "a" so "b" becomes least recently used
+	_, _ = c.Get("a")
+	c.Set("c", []byte("3"), 0)
+
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	_, ok = c.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_TTL(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	c := NewLRU(0)
+	c.now = func() time.Time { return now }
+
+	c.Set("short", []byte("x"), time.Minute)
+	c.Set("forever", []byte("y"), 0)
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.Get("short")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("short")
+	assert.False(t, ok)
+	assert.Equal(t, 1, c.Len())
+
+	_, ok = c.Get("forever")
+	assert.True(t, ok)
+}
+
+func TestLRU_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	c := NewLRU(50)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				key := fmt.Sprintf("k%d", (i*j)%100)
+				c.Set(key, []byte(key), time.Minute)
+				c.Get(key)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, c.Len(), 50)
+}
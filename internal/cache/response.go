@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics is a snapshot of response cache activity.
+type Metrics struct {
+	// Hits is the number of requests served from the cache.
+	Hits int64
+
+	// Misses is the number of cacheable requests sent to the API.
+	Misses int64
+}
+
+// HitRate returns the fraction of cacheable requests served from the cache.
+func (m Metrics) HitRate() float64 {
+	total := m.Hits + m.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(total)
+}
+
+// ResponseCache caches API response bodies keyed by endpoint and request body.
+type ResponseCache struct {
+	store  Store
+	ttl    time.Duration
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewResponseCache creates a response cache backed by store.
+// Entries are stored for ttl; zero or less means no expiry.
+func NewResponseCache(store Store, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+// Key returns the cache key for a request. JSON bodies are canonicalized
+// so that field order and whitespace do not affect the key.
+func (c *ResponseCache) Key(method, url string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write(canonicalJSON(body))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get retrieves a cached body and records a hit or miss.
+func (c *ResponseCache) Get(key string) ([]byte, bool) {
+	data, ok := c.store.Get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return data, ok
+}
+
+// Set stores a body.
+func (c *ResponseCache) Set(key string, data []byte) {
+	c.store.Set(key, data, c.ttl)
+}
+
+// Metrics returns a snapshot of cache activity.
+func (c *ResponseCache) Metrics() Metrics {
+	return Metrics{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+// canonicalJSON re-encodes a JSON document with sorted object keys.
+// Bodies that are not valid JSON are returned unchanged.
+func canonicalJSON(body []byte) []byte {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
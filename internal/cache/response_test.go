@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseCache_Key(t *testing.T) {
+	t.Parallel()
+
+	c := NewResponseCache(NewLRU(0), time.Minute)
+
+	a := c.Key("POST", "https://api.example.com/embeddings", []byte(`{"model":"embedding-3","input":"hi"}`))
+	b := c.Key("POST", "https://api.example.com/embeddings", []byte(`{ "input": "hi",  "model": "embedding-3" }`))
+	assert.Equal(t, a, b, "field order and whitespace should not change the key")
+
+	assert.NotEqual(t, a, c.Key("POST", "https://api.example.com/embeddings", []byte(`{"model":"embedding-3","input":"hello"}`)))
+	assert.NotEqual(t, a, c.Key("POST", "https://api.example.com/tokenizer", []byte(`{"model":"embedding-3","input":"hi"}`)))
+	assert.NotEqual(t, a, c.Key("POST", "https://other.example.com/embeddings", []byte(`{"model":"embedding-3","input":"hi"}`)))
+
+	// Large numbers keep their precision
+	assert.NotEqual(t,
+		c.Key("POST", "/x", []byte(`{"seed":9007199254740993}`)),
+		c.Key("POST", "/x", []byte(`{"seed":9007199254740992}`)),
+	)
+
+	// Non-JSON bodies are hashed as is
+	assert.NotEqual(t, c.Key("POST", "/x", []byte("a")), c.Key("POST", "/x", []byte("b")))
+}
+
+func TestResponseCache_Metrics(t *testing.T) {
+	t.Parallel()
+
+	c := NewResponseCache(NewLRU(0), time.Minute)
+	assert.Equal(t, 0.0, c.Metrics().HitRate())
+
+	_, ok := c.Get("k")
+	assert.False(t, ok)
+
+	c.Set("k", []byte("v"))
+	v, ok := c.Get("k")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("v"), v)
+
+	_, _ = c.Get("k")
+
+	m := c.Metrics()
+	assert.Equal(t, int64(2), m.Hits)
+	assert.Equal(t, int64(1), m.Misses)
+	assert.InDelta(t, 2.0/3.0, m.HitRate(), 1e-9)
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/auth"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// sendFunc sends a request, such as DoWithRetry for regular requests.
+type sendFunc func(ctx context.Context, req *http.Request) (*http.Response, error)
+
+// addAuth adds authentication to the request and returns the API key or
+// token source token used.
+func (c *BaseClient) addAuth(ctx context.Context, req *http.Request) (string, error) {
+	key := c.config.APIKey
+	switch {
+	case c.tokens != nil:
+		var err error
+		key, err = c.tokens.Get(ctx)
+		if err != nil {
+			return "", c.redactError(err)
+		}
+	case c.keys != nil:
+		var err error
+		key, err = c.keys.Get(ctx)
+		if err != nil {
+			return "", c.redactError(providerError(err))
+		}
+	}
+
+	return key, c.setAuth(req, key)
+}
+
+// setAuth sets the authentication and scoping headers of req for key, an
+// API key or a token of the token source.
+func (c *BaseClient) setAuth(req *http.Request, key string) error {
+	var token string
+
+	if c.tokens != nil || c.config.DisableTokenCache {
+		// Use raw API key
+		token = key
+	} else {
+		// Generate JWT token
+		var err error
+		token, err = c.tokenGenerator.GenerateToken(key)
+		if err != nil {
+			return c.redactError(fmt.Errorf("failed to generate auth token: %w", err))
+		}
+	}
+
+	req.Header.Set(constants.HeaderAuthorization, "Bearer "+token)
+
+	// Scope the request to the organization and project
+	if c.config.Organization != "" {
+		req.Header.Set(constants.HeaderOrganization, c.config.Organization)
+	}
+	if c.config.Project != "" {
+		req.Header.Set(constants.HeaderProject, c.config.Project)
+	}
+
+	return nil
+}
+
+// retryUnauthorized sends req once more if the API rejected key with a 401
+// and the key provider or token source now returns another key, for
+// example after the key was rotated or the token expired early. Otherwise
+// resp and err are returned unchanged.
+func (c *BaseClient) retryUnauthorized(ctx context.Context, req *http.Request, key string, resp *http.Response, err error, send sendFunc) (*http.Response, error) {
+	if err != nil || (c.keys == nil && c.tokens == nil) || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// Bodies that cannot be replayed cannot be resent
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	var newKey string
+	var refreshErr error
+	if c.tokens != nil {
+		newKey, refreshErr = c.tokens.Refresh(ctx, key)
+	} else {
+		newKey, refreshErr = c.keys.Refresh(ctx)
+	}
+	if refreshErr != nil || newKey == key {
+		return resp, nil
+	}
+
+	retry := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, bodyErr := req.GetBody()
+		if bodyErr != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	if err := c.setAuth(retry, newKey); err != nil {
+		return resp, nil
+	}
+
+	if c.logger != nil {
+		c.logger.DebugContext(ctx, "Retrying request with refreshed credentials")
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return send(ctx, retry)
+}
+
+// providerError converts a key provider failure to the error returned to
+// the caller. Malformed keys are reported as a ConfigError.
+func providerError(err error) error {
+	if err == auth.ErrInvalidAPIKey || err == auth.ErrEmptyAPIKey {
+		return errors.NewConfigError("APIKey", fmt.Sprintf("API key provider returned an invalid key: %v", err))
+	}
+	return err
+}
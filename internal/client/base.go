@@ -2,18 +2,31 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/sofianhadi1983/zai-sdk-go/internal/auth"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/cache"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/compress"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/ratelimit"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/telemetry"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
 	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
@@ -23,14 +36,41 @@ type Config struct {
 	// APIKey is the API key for authentication (format: "key.secret").
 	APIKey string
 
+	// APIKeyProvider, if set, returns the API key for every request
+	// instead of APIKey.
+	APIKeyProvider auth.KeyProvider
+
+	// APIKeyCacheTTL is how long keys from APIKeyProvider are reused.
+	// Zero or less fetches a key for every request.
+	APIKeyCacheTTL time.Duration
+
+	// TokenSource, if set, returns the bearer token sent with every
+	// request instead of a token signed with the API key.
+	TokenSource auth.TokenSource
+
 	// BaseURL is the base URL for API requests.
 	// If empty, uses the default Z.ai API URL.
 	BaseURL string
 
-	// Timeout is the request timeout.
-	// If zero, uses the default timeout.
+	// Timeout is the timeout of non-streaming requests, including reading
+	// the response. If zero, uses the default timeout. Negative means no
+	// timeout.
 	Timeout time.Duration
 
+	// ConnectTimeout bounds establishing a connection and, separately, its
+	// TLS handshake. If zero, uses the default timeouts. Negative means no
+	// timeout. It is ignored when HTTPClient or Transport is set.
+	ConnectTimeout time.Duration
+
+	// StreamReadTimeout bounds the wait of a stream for its response
+	// headers and for every read of its body. If zero, uses the default
+	// timeout. Negative means no timeout.
+	StreamReadTimeout time.Duration
+
+	// StreamIdleTimeout is the default idle timeout of streams.
+	// Zero means no idle timeout.
+	StreamIdleTimeout time.Duration
+
 	// MaxRetries is the maximum number of retry attempts.
 	// If zero, uses the default max retries.
 	MaxRetries int
@@ -39,13 +79,123 @@ type Config struct {
 	// When true, uses raw API key for authentication.
 	DisableTokenCache bool
 
-	// HTTPClient is a custom HTTP client.
-	// If nil, creates a default client.
+	// HTTPClient is a custom HTTP client that sends every request attempt.
+	// If nil, creates a default client. Closing this client leaves it open.
 	HTTPClient *http.Client
 
+	// Proxy is the proxy used by the default transport.
+	// It is ignored when HTTPClient or Transport is set.
+	Proxy *url.URL
+
 	// Logger is a custom logger.
 	// If nil, uses the default logger.
 	Logger *logger.Logger
+
+	// Hooks are lifecycle hooks called for every request.
+	Hooks []hooks.Hooks
+
+	// LogBodies passes request bodies and stream payloads to hooks.
+	// Credentials are masked even when enabled.
+	LogBodies bool
+
+	// RateLimiter paces requests client-side. If nil, requests are not limited.
+	RateLimiter *ratelimit.Limiter
+
+	// Cache stores responses of deterministic endpoints. If nil, nothing is cached.
+	Cache *cache.ResponseCache
+
+	// RetryPolicy overrides the retry configuration. Zero fields keep the defaults.
+	RetryPolicy transport.RetryPolicy
+
+	// RetryBudget limits the share of requests that may be retries.
+	// If nil, retries are not limited.
+	RetryBudget *transport.RetryBudget
+
+	// CircuitBreaker fails requests fast while their host is failing.
+	// If nil, requests are always sent.
+	CircuitBreaker *transport.CircuitBreaker
+
+	// Organization is sent in the organization header of every API request.
+	Organization string
+
+	// Project is sent in the project header of every API request.
+	Project string
+
+	// Transport, if set, is shared with another client instead of creating
+	// a new connection pool. Closing this client leaves it open.
+	Transport http.RoundTripper
+
+	// ConnectionPool overrides the settings of the connection pool the
+	// client creates. Zero fields keep the defaults. It is ignored when
+	// HTTPClient or Transport is set.
+	ConnectionPool transport.PoolConfig
+
+	// Tracer starts spans for API calls and their attempts.
+	// If nil, requests are not traced.
+	Tracer telemetry.Tracer
+
+	// MaxRequestBytes is the largest request body that is sent.
+	// Zero or less means no limit.
+	MaxRequestBytes int64
+
+	// UploadBufferSize is the largest file content of a multipart upload
+	// that is buffered in memory so that the upload can be retried. Zero or
+	// less buffers nothing.
+	UploadBufferSize int64
+
+	// RequestCompressionMinBytes is the smallest JSON request body that is
+	// gzipped before it is sent. Zero or less sends bodies uncompressed.
+	RequestCompressionMinBytes int64
+
+	// AutoIdempotencyKeys makes Idempotent add a generated Idempotency-Key
+	// header to calls that create server-side resources.
+	AutoIdempotencyKeys bool
+
+	// MaxResponseBytes is the largest response body that is read, except
+	// for streams. Zero or less means no limit.
+	MaxResponseBytes int64
+
+	// MaxStreamBytes is the largest total size of a stream.
+	// Zero or less means no limit.
+	MaxStreamBytes int64
+
+	// Platform selects the endpoint paths of the services.
+	Platform endpoints.Platform
+
+	// EndpointPaths overrides the path templates of individual endpoints.
+	EndpointPaths map[endpoints.Endpoint]string
+
+	// RequestSanitizer is called with the request of services that send
+	// user content before it is serialized. If nil, requests are sent as is.
+	RequestSanitizer func(service string, payload interface{}) error
+}
+
+// redactedConfig is a Config printed without its methods.
+type redactedConfig Config
+
+// String returns the configuration with the API key and the proxy
+// password masked, so that printing it does not leak them.
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", c.redacted())
+}
+
+// GoString is like String, in Go syntax.
+func (c Config) GoString() string {
+	return "client.Config" + strings.TrimPrefix(fmt.Sprintf("%#v", c.redacted()), "client.redactedConfig")
+}
+
+// redacted returns a copy of c with its credentials masked.
+func (c Config) redacted() redactedConfig {
+	r := redactedConfig(c)
+	if r.APIKey != "" {
+		r.APIKey = hooks.RedactedValue
+	}
+	if r.Proxy != nil {
+		if proxy, err := url.Parse(r.Proxy.Redacted()); err == nil {
+			r.Proxy = proxy
+		}
+	}
+	return r
 }
 
 // BaseClient is the base client for making API requests.
@@ -53,7 +203,18 @@ type BaseClient struct {
 	config         *Config
 	httpClient     *transport.RetryableHTTPClient
 	tokenGenerator *auth.TokenGenerator
+	keys           *auth.KeyCache
+	tokens         *auth.SourceCache
+	endpoints      *endpoints.Resolver
 	logger         *logger.Logger
+
+	// compressionOff is set once the server rejects a compressed body, and
+	// disables request compression for the client.
+	compressionOff atomic.Bool
+
+	// closeCtx is canceled with a ClientClosedError when the client is closed.
+	closeCtx context.Context
+	closeFn  context.CancelCauseFunc
 }
 
 // NewBaseClient creates a new base API client.
@@ -63,7 +224,7 @@ func NewBaseClient(config *Config) (*BaseClient, error) {
 	}
 
 	// Validate API key
-	if config.APIKey == "" {
+	if config.APIKey == "" && config.APIKeyProvider == nil && config.TokenSource == nil {
 		return nil, errors.NewConfigError("APIKey", "API key is required")
 	}
 
@@ -76,6 +237,10 @@ func NewBaseClient(config *Config) (*BaseClient, error) {
 		config.Timeout = constants.DefaultTimeout
 	}
 
+	if config.StreamReadTimeout == 0 {
+		config.StreamReadTimeout = constants.DefaultStreamReadTimeout
+	}
+
 	if config.MaxRetries == 0 {
 		config.MaxRetries = constants.DefaultMaxRetries
 	}
@@ -87,21 +252,33 @@ func NewBaseClient(config *Config) (*BaseClient, error) {
 	}
 
 	// Create HTTP client
-	httpConfig := &transport.HTTPClientConfig{
-		BaseURL:        config.BaseURL,
-		Timeout:        config.Timeout,
-		ConnectTimeout: constants.DefaultConnectTimeout,
+	httpConfig := transport.DefaultHTTPClientConfig()
+	httpConfig.BaseURL = config.BaseURL
+	httpConfig.Timeout = config.Timeout
+	httpConfig.StreamReadTimeout = config.StreamReadTimeout
+	if config.ConnectTimeout != 0 {
+		connectTimeout := max(config.ConnectTimeout, 0)
+		httpConfig.ConnectTimeout = connectTimeout
+		httpConfig.TLSHandshakeTimeout = connectTimeout
 	}
+	httpConfig.Transport = config.Transport
+	httpConfig.HTTPClient = config.HTTPClient
+	httpConfig = config.ConnectionPool.Apply(httpConfig)
 
-	if config.HTTPClient != nil {
-		// Use custom HTTP client
-		httpConfig.MaxIdleConns = constants.DefaultMaxIdleConns
-		httpConfig.MaxIdleConnsPerHost = constants.DefaultMaxIdleConnsPerHost
-		httpConfig.IdleConnTimeout = constants.DefaultIdleConnTimeout
+	if config.Proxy != nil {
+		httpConfig.Proxy = http.ProxyURL(config.Proxy)
 	}
 
 	httpClient := transport.NewHTTPClient(httpConfig)
 	httpClient.SetLogger(log)
+	redactor := hooks.NewRedactor(config.LogBodies, credentialSecrets(config.APIKey)...)
+	httpClient.SetRedactor(redactor)
+	for _, h := range config.Hooks {
+		httpClient.AddHooks(h)
+	}
+	httpClient.SetRateLimiter(config.RateLimiter)
+	httpClient.SetTracer(config.Tracer)
+	httpClient.SetCircuitBreaker(config.CircuitBreaker)
 
 	// Create retryable client
 	retryConfig := &transport.RetryConfig{
@@ -112,6 +289,8 @@ func NewBaseClient(config *Config) (*BaseClient, error) {
 		RetryableStatusCodes: constants.RetryableStatusCodes(),
 		EnableJitter:         true,
 	}
+	retryConfig = config.RetryPolicy.Apply(retryConfig)
+	retryConfig.Budget = config.RetryBudget
 
 	retryableClient := transport.NewRetryableHTTPClient(httpClient, retryConfig)
 	retryableClient.SetLogger(log)
@@ -122,36 +301,110 @@ func NewBaseClient(config *Config) (*BaseClient, error) {
 		tokenGen.DisableCache()
 	}
 
+	// Fetch keys per request, masking every new key in hook events
+	var keys *auth.KeyCache
+	if config.APIKeyProvider != nil {
+		keys = auth.NewKeyCache(config.APIKeyProvider, config.APIKeyCacheTTL, func(key string) {
+			redactor.AddSecrets(credentialSecrets(key)...)
+		})
+	}
+
+	// Fetch bearer tokens from the token source, masking them likewise
+	var tokens *auth.SourceCache
+	if config.TokenSource != nil {
+		tokens = auth.NewSourceCache(config.TokenSource, func(token string) {
+			redactor.AddSecrets(token)
+		})
+	}
+
+	closeCtx, closeFn := context.WithCancelCause(context.Background())
+
 	return &BaseClient{
 		config:         config,
 		httpClient:     retryableClient,
 		tokenGenerator: tokenGen,
+		keys:           keys,
+		tokens:         tokens,
+		endpoints:      endpoints.NewResolver(config.Platform, config.BaseURL, config.EndpointPaths),
 		logger:         log,
+		closeCtx:       closeCtx,
+		closeFn:        closeFn,
 	}, nil
 }
 
 // Do executes an HTTP request with retry and authentication.
+// The request is canceled if the client is closed before its response
+// body is closed.
 func (c *BaseClient) Do(ctx context.Context, req *http.Request) (*models.APIResponse, error) {
+	return c.do(ctx, req, "response", c.config.MaxResponseBytes)
+}
+
+// Download performs a GET request for a body of any size, such as file
+// content, that the caller reads incrementally. It is retried like Get,
+// which never buffers a successful body, but the body is limited by
+// MaxStreamBytes instead of MaxResponseBytes. Closing the body releases
+// the connection.
+func (c *BaseClient) Download(ctx context.Context, path string) (*models.APIResponse, error) {
+	req, _, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(ctx, req, "stream", c.config.MaxStreamBytes)
+}
+
+// do executes a request with retry and authentication, limiting the body
+// of a successful response to limit bytes of payload.
+func (c *BaseClient) do(ctx context.Context, req *http.Request, payload string, limit int64) (*models.APIResponse, error) {
+	ctx, release, err := c.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compress large JSON bodies
+	uncompressed, err := c.compressRequest(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
 	// Add authentication
-	if err := c.addAuth(req); err != nil {
+	key, err := c.addAuth(ctx, req)
+	if err != nil {
+		release()
 		return nil, err
 	}
 
+	ctx, span := c.startCall(ctx, req, false)
+
 	// Execute with retry
 	start := time.Now()
 	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	resp, err = c.retryUncompressed(ctx, req, uncompressed, resp, err, c.httpClient.DoWithRetry)
+	resp, err = c.retryUnauthorized(ctx, req, key, resp, err, c.httpClient.DoWithRetry)
 	elapsed := time.Since(start)
 
 	if err != nil {
+		release()
+		err = closedCause(ctx, err)
+		if span != nil {
+			span.End(0, err)
+		}
 		return nil, err
 	}
+	resp.Body = newBoundBody(ctx, resp.Body, endCallOnRelease(span, resp.StatusCode, release))
+	if resp.StatusCode < http.StatusBadRequest {
+		resp.Body = limitBody(resp.Body, payload, resp.ContentLength, limit)
+	} else {
+		resp.Body = limitBody(resp.Body, "response", resp.ContentLength, c.config.MaxResponseBytes)
+	}
 
 	// Wrap response
 	apiResp := models.NewAPIResponse(resp, elapsed)
 
 	// Check for errors
 	if apiResp.IsError() {
-		return apiResp, c.handleErrorResponse(apiResp)
+		return apiResp, c.reportError(ctx, apiResp, c.handleErrorResponse(apiResp))
 	}
 
 	return apiResp, nil
@@ -159,7 +412,7 @@ func (c *BaseClient) Do(ctx context.Context, req *http.Request) (*models.APIResp
 
 // Get performs a GET request.
 func (c *BaseClient) Get(ctx context.Context, path string, query map[string]string) (*models.APIResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	req, _, err := c.newRequest(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -178,95 +431,264 @@ func (c *BaseClient) Get(ctx context.Context, path string, query map[string]stri
 
 // Post performs a POST request with JSON body.
 func (c *BaseClient) Post(ctx context.Context, path string, body interface{}) (*models.APIResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	req, pooled, err := c.newRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, err
 	}
+	defer pooled.done()
 
 	return c.Do(ctx, req)
 }
 
-// PostMultipart performs a POST request with multipart/form-data body.
-func (c *BaseClient) PostMultipart(ctx context.Context, path string, body io.Reader, contentType string) (*models.APIResponse, error) {
-	req, err := c.httpClient.GetClient().NewRequest(ctx, http.MethodPost, path, body)
+// PostCached performs a POST request with JSON body, serving identical
+// requests from the response cache when one is configured. Only use it for
+// endpoints whose response depends solely on the request body.
+func (c *BaseClient) PostCached(ctx context.Context, path string, body interface{}) (*models.APIResponse, error) {
+	if c.config.Cache == nil {
+		return c.Post(ctx, path, body)
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+
+	data, err := c.marshalBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.httpClient.GetClient().NewRequest(ctx, http.MethodPost, path, newBytesReader(data))
 	if err != nil {
 		return nil, err
 	}
 
-	// Set content type for multipart form data
-	req.Header.Set("Content-Type", contentType)
+	key := c.config.Cache.Key(req.Method, req.URL.String(), data)
+	if cached, ok := c.config.Cache.Get(key); ok {
+		return &models.APIResponse{
+			Body:       io.NopCloser(bytes.NewReader(cached)),
+			Headers:    http.Header{"X-Cache": []string{"HIT"}},
+			StatusCode: http.StatusOK,
+			URL:        req.URL.String(),
+			Method:     req.Method,
+		}, nil
+	}
 
-	return c.Do(ctx, req)
+	apiResp, err := c.Do(ctx, req)
+	if err != nil {
+		return apiResp, err
+	}
+
+	// Buffer the body so it can be both cached and parsed
+	respData, err := io.ReadAll(apiResp.Body)
+	apiResp.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	c.config.Cache.Set(key, respData)
+
+	apiResp.Body = io.NopCloser(bytes.NewReader(respData))
+	apiResp.IsClosed = false
+
+	return apiResp, nil
 }
 
 // Put performs a PUT request with JSON body.
 func (c *BaseClient) Put(ctx context.Context, path string, body interface{}) (*models.APIResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodPut, path, body)
+	req, pooled, err := c.newRequest(ctx, http.MethodPut, path, body)
 	if err != nil {
 		return nil, err
 	}
+	defer pooled.done()
 
 	return c.Do(ctx, req)
 }
 
 // Delete performs a DELETE request.
 func (c *BaseClient) Delete(ctx context.Context, path string) (*models.APIResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	req, _, err := c.newRequest(ctx, http.MethodDelete, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(ctx, req)
+}
+
+// Send performs a request with the given method and JSON body, which may
+// be nil.
+func (c *BaseClient) Send(ctx context.Context, method, path string, body interface{}) (*models.APIResponse, error) {
+	req, pooled, err := c.newRequest(ctx, method, path, body)
 	if err != nil {
 		return nil, err
 	}
+	defer pooled.done()
 
 	return c.Do(ctx, req)
 }
 
-// Stream performs a streaming request.
+// Stream performs a streaming POST request.
+// Closing the client cancels the request and closes the stream body.
 func (c *BaseClient) Stream(ctx context.Context, path string, body interface{}) (*models.StreamResponse, error) {
-	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	return c.StreamMethod(ctx, http.MethodPost, path, body)
+}
+
+// StreamMethod performs a streaming request with the given method and JSON
+// body, which may be nil.
+// Closing the client cancels the request and closes the stream body.
+func (c *BaseClient) StreamMethod(ctx context.Context, method, path string, body interface{}) (*models.StreamResponse, error) {
+	boundCtx, release, err := c.bind(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	req, pooled, err := c.newRequest(boundCtx, method, path, body)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	defer pooled.done()
+
 	// Add authentication
-	if err := c.addAuth(req); err != nil {
+	key, err := c.addAuth(boundCtx, req)
+	if err != nil {
+		release()
 		return nil, err
 	}
 
+	boundCtx, span := c.startCall(boundCtx, req, true)
+
 	// Execute request (no retry for streaming)
 	start := time.Now()
-	resp, err := c.httpClient.GetClient().Do(ctx, req)
+	send := c.httpClient.GetClient().DoStream
+	resp, err := send(boundCtx, req)
+	resp, err = c.retryUnauthorized(boundCtx, req, key, resp, err, send)
 	elapsed := time.Since(start)
 
 	if err != nil {
+		release()
+		err = closedCause(boundCtx, err)
+		if span != nil {
+			span.End(0, err)
+		}
 		return nil, err
 	}
 
+	// Close the body as soon as the client is closed to unblock readers
+	rawBody := resp.Body
+	stop := context.AfterFunc(boundCtx, func() { rawBody.Close() })
+	resp.Body = newBoundBody(boundCtx, rawBody, endCallOnRelease(span, resp.StatusCode, func() {
+		stop()
+		release()
+	}))
+	if resp.StatusCode < http.StatusBadRequest {
+		resp.Body = limitBody(resp.Body, "stream", resp.ContentLength, c.config.MaxStreamBytes)
+	} else {
+		resp.Body = limitBody(resp.Body, "response", resp.ContentLength, c.config.MaxResponseBytes)
+	}
+
 	// Wrap response
 	apiResp := models.NewAPIResponse(resp, elapsed)
 
 	// Check for errors
 	if apiResp.IsError() {
-		return nil, c.handleErrorResponse(apiResp)
+		return nil, c.reportError(ctx, apiResp, c.handleErrorResponse(apiResp))
+	}
+
+	streamResp := models.NewStreamResponse(apiResp)
+	streamResp.IdleTimeout = c.config.StreamIdleTimeout
+	eventHooks := c.httpClient.GetClient().Hooks()
+	redactor := c.httpClient.GetClient().Redactor()
+	streamPath := req.URL.Path
+	var streamUsage telemetry.StreamUsage
+	streamResp.OnEvent = func(index int, eventType, id, data string) {
+		eventHooks.OnStreamEvent(ctx, &hooks.StreamEvent{
+			Path:  streamPath,
+			Index: index,
+			Type:  eventType,
+			ID:    id,
+			Data:  redactor.Body([]byte(data)),
+		})
+		if span != nil {
+			if usage, ok := streamUsage.Parse([]byte(data)); ok {
+				span.SetUsage(usage)
+			}
+		}
 	}
 
-	return models.NewStreamResponse(apiResp), nil
+	return streamResp, nil
+}
+
+// DoUnauthenticated executes req with retry but without adding credentials.
+// It is used to fetch absolute URLs returned by the API, such as generated
+// images, which must not receive the API key. The headers and query
+// parameters set on ctx for the API are not sent, and the request does not
+// count against the rate limiter or the retry budget. The response is
+// returned as is, regardless of its status code, and reading more of its
+// body than the maximum response size fails with PayloadTooLargeError.
+func (c *BaseClient) DoUnauthenticated(ctx context.Context, req *http.Request) (*http.Response, error) {
+	ctx, release, err := c.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = transport.ContextExternal(ctx)
+	resp, err := c.httpClient.DoWithRetry(ctx, req)
+	if err != nil {
+		release()
+		return nil, closedCause(ctx, err)
+	}
+	resp.Body = newBoundBody(ctx, resp.Body, release)
+	resp.Body = limitBody(resp.Body, "response", resp.ContentLength, c.config.MaxResponseBytes)
+
+	return resp, nil
 }
 
 // ParseJSON parses a JSON response into the given type.
 func (c *BaseClient) ParseJSON(resp *models.APIResponse, v interface{}) error {
 	defer resp.Close()
 
-	data, err := io.ReadAll(resp.Body)
-	if err != nil {
+	buf := readBufferPool.Get().(*bytes.Buffer)
+	defer putReadBuffer(buf)
+	if cl := c.presize(resp); cl > 0 {
+		buf.Grow(int(cl) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
 		return fmt.Errorf("failed to read response body: %w", err)
 	}
+	data := buf.Bytes()
 
 	if err := json.Unmarshal(data, v); err != nil {
 		return fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
+	// Record the token usage on the call span
+	if c.config.Tracer != nil && resp.HTTPResponse != nil && resp.HTTPResponse.Request != nil {
+		if span := telemetry.CallSpanFromContext(resp.HTTPResponse.Request.Context()); span != nil {
+			if usage, ok := telemetry.ParseUsage(data); ok {
+				span.SetUsage(usage)
+			}
+		}
+	}
+
 	return nil
 }
 
+// presize returns the Content-Length of resp if the buffer its body is read
+// into can be allocated upfront, or 0. A length above the response limit
+// is not trusted, since reading the body fails anyway.
+func (c *BaseClient) presize(resp *models.APIResponse) int64 {
+	if resp.HTTPResponse == nil {
+		return 0
+	}
+	cl := resp.HTTPResponse.ContentLength
+	limit := c.config.MaxResponseBytes
+	if limit <= 0 {
+		limit = maxPooledBuffer
+	}
+	if cl <= 0 || cl > limit {
+		return 0
+	}
+	return cl
+}
+
 // NewTypedStream creates a new typed stream from a stream response.
 // This is a package-level function due to Go's limitation on generic methods.
 func NewTypedStream[T any](streamResp *models.StreamResponse, ctx context.Context) *streaming.Stream[T] {
@@ -274,122 +696,320 @@ func NewTypedStream[T any](streamResp *models.StreamResponse, ctx context.Contex
 		ctx = context.Background()
 	}
 
-	return streaming.NewStream[T](streaming.StreamConfig[T]{
-		Reader:  streamResp.Body,
-		Context: ctx,
-	})
+	config := streaming.StreamConfig[T]{
+		Reader:      streamResp.Body,
+		Context:     ctx,
+		IdleTimeout: streamResp.IdleTimeout,
+	}
+
+	if streamResp.Reconnect != nil {
+		config.Reconnect = streamResp.Reconnect
+		config.MaxReconnects = streamResp.MaxReconnects
+		config.ReconnectBackoff = streamResp.ReconnectBackoff
+	}
+
+	if streamResp.OnEvent != nil {
+		config.OnEvent = func(index int, event *streaming.Event) {
+			streamResp.OnEvent(index, event.Type, event.ID, event.Data)
+		}
+	}
+
+	return streaming.NewStream[T](config)
 }
 
-// Close closes the client and releases resources.
+// Close closes the client and releases resources. In-flight requests and
+// streams are canceled with a ClientClosedError, and later requests fail
+// with the same error. It is safe to call Close more than once.
 func (c *BaseClient) Close() {
+	c.closeFn(errors.NewClientClosedError())
 	c.httpClient.Close()
 }
 
-// newRequest creates a new HTTP request.
-func (c *BaseClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
-	var bodyReader io.Reader
-
-	if body != nil {
-		data, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		// bytes package is imported at the top
-		bodyReader = newBytesReader(data)
+// newRequest creates a new HTTP request. A JSON body is encoded into a
+// pooled buffer; call done on the returned body, which may be nil, once
+// the request was sent.
+func (c *BaseClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, *pooledBody, error) {
+	req, err := c.httpClient.GetClient().NewRequest(ctx, method, path, nil)
+	if err != nil || body == nil {
+		return req, nil, err
 	}
 
-	return c.httpClient.GetClient().NewRequest(ctx, method, path, bodyReader)
+	pooled, err := encodePooledBody(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	if err := c.checkRequestSize(int64(len(pooled.data))); err != nil {
+		pooled.done()
+		return nil, nil, err
+	}
+	pooled.attach(req)
+	return req, pooled, nil
 }
 
 // newBytesReader creates a bytes.Reader from data.
+// Using *bytes.Reader lets net/http set GetBody so the body can be replayed.
 func newBytesReader(data []byte) io.Reader {
-	// Import bytes in the package imports
-	r := &bytesReader{data: data}
-	return r
+	return bytes.NewReader(data)
 }
 
-type bytesReader struct {
-	data []byte
-	pos  int
+// startCall starts the span of an API call if a tracer is configured.
+// The returned context carries the span to every attempt.
+func (c *BaseClient) startCall(ctx context.Context, req *http.Request, stream bool) (context.Context, telemetry.CallSpan) {
+	if c.config.Tracer == nil {
+		return ctx, nil
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, strings.TrimSuffix(urlPath(c.config.BaseURL), "/"))
+	ctx, span := c.config.Tracer.StartCall(ctx, &telemetry.Call{
+		Method:  req.Method,
+		Path:    path,
+		Service: telemetry.ServiceFromPath(path),
+		Model:   requestModel(req),
+		Stream:  stream,
+	})
+	return telemetry.WithCallSpan(ctx, span), span
 }
 
-func (r *bytesReader) Read(p []byte) (n int, err error) {
-	if r.pos >= len(r.data) {
-		return 0, io.EOF
+// endCallOnRelease returns release extended to end span, if any.
+func endCallOnRelease(span telemetry.CallSpan, statusCode int, release func()) func() {
+	if span == nil {
+		return release
+	}
+	return func() {
+		span.End(statusCode, nil)
+		release()
 	}
-	n = copy(p, r.data[r.pos:])
-	r.pos += n
-	return n, nil
 }
 
-// addAuth adds authentication to the request.
-func (c *BaseClient) addAuth(req *http.Request) error {
-	var token string
+// requestModel returns the model of a replayable JSON request body.
+func requestModel(req *http.Request) string {
+	if req.GetBody == nil || !strings.HasPrefix(req.Header.Get(constants.HeaderContentType), constants.ContentTypeJSON) {
+		return ""
+	}
 
-	if c.config.DisableTokenCache {
-		// Use raw API key
-		token = c.config.APIKey
-	} else {
-		// Generate JWT token
-		var err error
-		token, err = c.tokenGenerator.GenerateToken(c.config.APIKey)
-		if err != nil {
-			return fmt.Errorf("failed to generate auth token: %w", err)
-		}
+	data, err := compress.ReadBody(req)
+	if err != nil {
+		return ""
 	}
+	return telemetry.ModelFromBody(data)
+}
 
-	req.Header.Set(constants.HeaderAuthorization, "Bearer "+token)
-	return nil
+// reportError notifies hooks that the API returned an error response.
+func (c *BaseClient) reportError(ctx context.Context, resp *models.APIResponse, err error) error {
+	attempt := 1
+	if resp.HTTPResponse != nil && resp.HTTPResponse.Request != nil {
+		attempt = hooks.AttemptFromContext(resp.HTTPResponse.Request.Context())
+	}
+
+	c.httpClient.GetClient().Hooks().OnError(ctx, &hooks.ErrorEvent{
+		Method:     resp.Method,
+		Path:       urlPath(resp.URL),
+		Attempt:    attempt,
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.RequestID,
+		Err:        err,
+	})
+
+	return err
+}
+
+// redactError masks the credentials of the client in the message of err.
+func (c *BaseClient) redactError(err error) error {
+	return c.httpClient.GetClient().Redactor().Error(err)
+}
+
+// redactedResponse returns a shallow copy of resp whose request has its
+// credential headers masked, so that errors holding the response do not
+// hold the credentials. The body is shared with resp.
+func redactedResponse(resp *http.Response) *http.Response {
+	if resp == nil || resp.Request == nil {
+		return resp
+	}
+	out := *resp
+	out.Request = resp.Request.Clone(resp.Request.Context())
+	out.Request.Header = hooks.NewRedactor(false).Header(resp.Request.Header)
+	if u, err := url.Parse(hooks.RedactURL(resp.Request.URL.String())); err == nil {
+		out.Request.URL = u
+	}
+	return &out
 }
 
-// handleErrorResponse converts an error response to an error.
+// credentialSecrets returns the parts of an API key that must never be logged.
+func credentialSecrets(apiKey string) []string {
+	secrets := []string{apiKey}
+	if _, secret, ok := strings.Cut(apiKey, "."); ok {
+		secrets = append(secrets, secret)
+	}
+	return secrets
+}
+
+// urlPath returns the path component of a URL string.
+func urlPath(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Path
+}
+
+// handleErrorResponse converts an error response to an error. The error is
+// typed by status code and carries the request ID, whether or not the body
+// is a JSON error envelope.
 func (c *BaseClient) handleErrorResponse(resp *models.APIResponse) error {
 	defer resp.Close()
 
-	// Try to parse error response
-	var errResp models.ErrorResponse
+	var (
+		message, code string
+		errResp       *models.ErrorResponse
+	)
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		// Fallback to generic error
-		return errors.NewAPIStatusError(
-			fmt.Sprintf("HTTP %d: failed to read error response", resp.StatusCode),
-			resp.StatusCode,
-			resp.HTTPResponse,
-		)
+		message = fmt.Sprintf("HTTP %d: failed to read error response: %v", resp.StatusCode, err)
+	} else {
+		errResp = parseErrorResponse(data)
+		message = errorMessage(resp.StatusCode, resp.Headers.Get("Content-Type"), data, errResp)
+		if errResp != nil {
+			code = errResp.GetCode()
+		}
 	}
 
-	if err := json.Unmarshal(data, &errResp); err != nil {
-		// Fallback to generic error with body
-		return errors.NewAPIStatusError(
-			fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(data)),
-			resp.StatusCode,
-			resp.HTTPResponse,
-		)
-	}
+	// The body may echo the Authorization header or the API key
+	redactor := c.httpClient.GetClient().Redactor()
+	statusErr := errors.NewAPIStatusError(redactor.String(message), resp.StatusCode, redactedResponse(resp.HTTPResponse))
+	statusErr.Code = code
+	statusErr.RequestID = resp.RequestID
 
 	// Create specific error based on status code
-	message := errResp.GetMessage()
-	statusCode := resp.StatusCode
-
-	switch statusCode {
+	switch resp.StatusCode {
 	case http.StatusBadRequest:
-		return errors.NewAPIRequestFailedError(message, statusCode, resp.HTTPResponse)
+		failedErr := &errors.APIRequestFailedError{APIStatusError: statusErr}
+		if policyErr := contentPolicyError(failedErr, errResp); policyErr != nil {
+			return policyErr
+		}
+		return failedErr
 
 	case http.StatusUnauthorized:
-		return errors.NewAPIAuthenticationError(message, statusCode, resp.HTTPResponse)
+		return &errors.APIAuthenticationError{APIStatusError: statusErr}
 
 	case http.StatusTooManyRequests:
-		return errors.NewAPIReachLimitError(message, statusCode, resp.HTTPResponse)
+		return &errors.APIReachLimitError{
+			APIStatusError: statusErr,
+			RetryAfter:     retryAfterSeconds(resp.Headers.Get("Retry-After"), time.Now()),
+		}
 
 	case http.StatusInternalServerError:
-		return errors.NewAPIInternalError(message, statusCode, resp.HTTPResponse)
+		return &errors.APIInternalError{APIStatusError: statusErr}
 
 	case http.StatusServiceUnavailable:
-		return errors.NewAPIServerFlowExceedError(message, statusCode, resp.HTTPResponse)
+		return &errors.APIServerFlowExceedError{APIStatusError: statusErr}
 
 	default:
-		return errors.NewAPIStatusError(message, statusCode, resp.HTTPResponse)
+		return statusErr
+	}
+}
+
+// parseErrorResponse decodes an error response body, or returns nil if the
+// body is not JSON. The body is decoded once for the message, the code and
+// the content policy details.
+func parseErrorResponse(data []byte) *models.ErrorResponse {
+	var errResp models.ErrorResponse
+	if json.Unmarshal(data, &errResp) != nil {
+		return nil
+	}
+	return &errResp
+}
+
+// contentPolicyError returns a *errors.ContentPolicyError for err if
+// errResp, the decoded body of a 400 response, rejects the request for
+// violating the content policy, or nil otherwise.
+func contentPolicyError(err *errors.APIRequestFailedError, errResp *models.ErrorResponse) *errors.ContentPolicyError {
+	if errResp == nil {
+		return nil
+	}
+	field, categories, ok := errResp.ContentPolicy()
+	if !ok {
+		return nil
+	}
+	return &errors.ContentPolicyError{
+		APIRequestFailedError: err,
+		Field:                 field,
+		Categories:            categories,
+	}
+}
+
+// retryAfterSeconds parses a Retry-After header, given in seconds or as an
+// HTTP date, into whole seconds from now, rounded up. It returns 0 if the
+// header is missing or invalid.
+func retryAfterSeconds(header string, now time.Time) int {
+	if header == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(strings.TrimSpace(header)); err == nil {
+		return max(seconds, 0)
 	}
+	if t, err := http.ParseTime(header); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return int((d + time.Second - 1) / time.Second)
+		}
+	}
+	return 0
+}
+
+// maxErrorBodySnippet is the number of bytes of a non-JSON error body
+// included in the error message.
+const maxErrorBodySnippet = 512
+
+// errorMessage returns the message of an error response body, decoded as
+// errResp by parseErrorResponse. Bodies that are not a JSON error
+// envelope, such as HTML pages from proxies, are summarized with the
+// status code, content type and the start of the body.
+func errorMessage(statusCode int, contentType string, data []byte, errResp *models.ErrorResponse) string {
+	if errResp != nil && errResp.HasError() {
+		return errResp.GetMessage()
+	}
+
+	summary := fmt.Sprintf("HTTP %d", statusCode)
+	if contentType != "" {
+		summary += " (" + contentType + ")"
+	}
+
+	snippet := sanitizeSnippet(data, maxErrorBodySnippet)
+	if snippet == "" {
+		return summary + ": empty response body"
+	}
+	return summary + ": " + snippet
+}
+
+// sanitizeSnippet returns at most limit bytes of data as printable text:
+// invalid UTF-8 (including a rune cut by the truncation) and control
+// characters become spaces, runs of whitespace are collapsed, and a
+// truncated body ends with "...".
+func sanitizeSnippet(data []byte, limit int) string {
+	truncated := len(data) > limit
+	if truncated {
+		data = data[:limit]
+	}
+
+	var b strings.Builder
+	space := false
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if r == utf8.RuneError || unicode.IsSpace(r) || unicode.IsControl(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+
+	if truncated {
+		b.WriteString("...")
+	}
+	return b.String()
 }
 
 // GetConfig returns the client configuration.
@@ -397,6 +1017,30 @@ func (c *BaseClient) GetConfig() *Config {
 	return c.config
 }
 
+// Transport returns the transport that holds the connection pool, for
+// sharing with derived clients.
+func (c *BaseClient) Transport() http.RoundTripper {
+	return c.httpClient.GetClient().Transport()
+}
+
+// Endpoints returns the resolver of the endpoint paths of the services.
+func (c *BaseClient) Endpoints() *endpoints.Resolver {
+	return c.endpoints
+}
+
+// Sanitize passes payload, the request of service, to the configured
+// request sanitizer, which may modify it in place. It returns an
+// *errors.RequestBlockedError if the sanitizer rejects the request.
+func (c *BaseClient) Sanitize(service string, payload interface{}) error {
+	if c.config.RequestSanitizer == nil {
+		return nil
+	}
+	if err := c.config.RequestSanitizer(service, payload); err != nil {
+		return errors.NewRequestBlockedError(service, err)
+	}
+	return nil
+}
+
 // GetLogger returns the client logger.
 func (c *BaseClient) GetLogger() *logger.Logger {
 	return c.logger
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which a buffer is not returned to
+// its pool, so that an occasional large request or response does not stay
+// in memory.
+const maxPooledBuffer = 1 << 20
+
+// jsonBuffer is a buffer with a JSON encoder writing to it.
+type jsonBuffer struct {
+	buf bytes.Buffer
+	enc *json.Encoder
+}
+
+// jsonBufferPool holds the buffers JSON request bodies are encoded into.
+var jsonBufferPool = sync.Pool{
+	New: func() any {
+		b := &jsonBuffer{}
+		b.enc = json.NewEncoder(&b.buf)
+		return b
+	},
+}
+
+// readBufferPool holds the buffers response bodies are read into.
+var readBufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// putReadBuffer returns buf to readBufferPool unless it grew too large.
+func putReadBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	readBufferPool.Put(buf)
+}
+
+// errBodyReleased is returned when the body of a request is replayed after
+// the request is done.
+var errBodyReleased = stderrors.New("request body already released")
+
+// pooledBody is a JSON request body encoded into a pooled buffer. The
+// buffer goes back to the pool once the request is done and every reader
+// of the body is closed: the transport may still read a body after the
+// response arrived, and retries replay it through GetBody.
+type pooledBody struct {
+	mu   sync.Mutex
+	buf  *jsonBuffer
+	data []byte
+
+	// refs counts the open readers, plus one until the request is done.
+	refs int
+}
+
+// encodePooledBody encodes v into a pooled buffer.
+func encodePooledBody(v any) (*pooledBody, error) {
+	buf := jsonBufferPool.Get().(*jsonBuffer)
+	if err := buf.enc.Encode(v); err != nil {
+		buf.buf.Reset()
+		jsonBufferPool.Put(buf)
+		return nil, err
+	}
+
+	// Encode terminates the value with a newline, which json.Marshal does
+	// not
+	data := buf.buf.Bytes()
+	return &pooledBody{buf: buf, data: data[:len(data)-1], refs: 1}, nil
+}
+
+// attach sets b as the body of req, replayable through GetBody.
+func (b *pooledBody) attach(req *http.Request) {
+	req.ContentLength = int64(len(b.data))
+	req.Body, _ = b.newReader()
+	req.GetBody = b.newReader
+}
+
+// done releases the body once its request was sent. It is safe to call
+// on a nil body.
+func (b *pooledBody) done() {
+	if b != nil {
+		b.release()
+	}
+}
+
+// newReader returns a reader of the body. It fails once the body is
+// released.
+func (b *pooledBody) newReader() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.refs == 0 {
+		return nil, errBodyReleased
+	}
+	b.refs++
+	return &pooledReader{body: b}, nil
+}
+
+// release drops a reference to the body, returning the buffer to the pool
+// with the last one.
+func (b *pooledBody) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refs--
+	if b.refs > 0 {
+		return
+	}
+	if b.buf.buf.Cap() <= maxPooledBuffer {
+		b.buf.buf.Reset()
+		jsonBufferPool.Put(b.buf)
+	}
+	b.buf, b.data = nil, nil
+}
+
+// pooledReader reads a pooledBody.
+type pooledReader struct {
+	body   *pooledBody
+	off    int
+	closed bool
+}
+
+// Read implements io.Reader.
+func (r *pooledReader) Read(p []byte) (int, error) {
+	r.body.mu.Lock()
+	defer r.body.mu.Unlock()
+	if r.closed {
+		return 0, fmt.Errorf("read from closed request body")
+	}
+	if r.off >= len(r.body.data) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.body.data[r.off:])
+	r.off += n
+	return n, nil
+}
+
+// Close implements io.Closer. The transport closes every body it is
+// given.
+func (r *pooledReader) Close() error {
+	r.body.mu.Lock()
+	if r.closed {
+		r.body.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	r.body.mu.Unlock()
+	r.body.release()
+	return nil
+}
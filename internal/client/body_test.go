@@ -0,0 +1,123 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPooledBody(t *testing.T) {
+	t.Parallel()
+
+	body, err := encodePooledBody(map[string]string{"model": "embedding-3"})
+	require.NoError(t, err)
+	assert.Equal(t, `{"model":"embedding-3"}`, string(body.data), "no trailing newline")
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com", nil)
+	require.NoError(t, err)
+	body.attach(req)
+	assert.Equal(t, int64(len(`{"model":"embedding-3"}`)), req.ContentLength)
+
+	replay, err := req.GetBody()
+	require.NoError(t, err)
+
+	// The request is done, but the transport still holds its readers
+	body.done()
+	data, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"model":"embedding-3"}`, string(data))
+	require.NoError(t, req.Body.Close())
+	require.NoError(t, req.Body.Close(), "closing twice is a no-op")
+
+	_, err = replay.Read(make([]byte, 1))
+	require.NoError(t, err)
+	require.NoError(t, replay.Close())
+	_, err = replay.Read(make([]byte, 1))
+	assert.Error(t, err, "a closed reader fails")
+
+	// Every reader is closed: the buffer is back in the pool
+	_, err = req.GetBody()
+	assert.ErrorIs(t, err, errBodyReleased)
+
+	var nilBody *pooledBody
+	assert.NotPanics(t, nilBody.done)
+}
+
+func TestEncodePooledBody_Error(t *testing.T) {
+	t.Parallel()
+
+	_, err := encodePooledBody(map[string]any{"bad": make(chan int)})
+	assert.Error(t, err)
+
+	// The buffer returned to the pool after the failure is usable
+	body, err := encodePooledBody("ok")
+	require.NoError(t, err)
+	assert.Equal(t, `"ok"`, string(body.data))
+	body.done()
+}
+
+// TestBaseClient_PooledBody_Concurrent sends concurrent requests, each
+// retried once (PUT is idempotent), and checks that no request body is
+// corrupted by a buffer reused too early. Run with -race.
+func TestBaseClient_PooledBody_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu   sync.Mutex
+		seen = make(map[string]bool)
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		// Fail the first attempt of every body, so that it is replayed
+		mu.Lock()
+		retry := !seen[string(data)]
+		seen[string(data)] = true
+		mu.Unlock()
+		if retry {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	client := newCompressionClient(t, server.URL, 0)
+
+	const workers, requests = 8, 25
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range requests {
+				want := embeddingBody(1 + (w+i)%5)
+				want["id"] = fmt.Sprintf("%d-%d", w, i)
+
+				resp, err := client.Put(context.Background(), "/embeddings", want)
+				if !assert.NoError(t, err) {
+					return
+				}
+				var got map[string]any
+				if !assert.NoError(t, client.ParseJSON(resp, &got)) {
+					return
+				}
+				assert.Equal(t, want["id"], got["id"])
+				assert.Len(t, got["input"], len(want["input"].([]string)))
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Done returns a channel that is closed when the client is closed.
+func (c *BaseClient) Done() <-chan struct{} {
+	return c.closeCtx.Done()
+}
+
+// Err returns a *errors.ClientClosedError if the client is closed, or nil.
+func (c *BaseClient) Err() error {
+	if c.closeCtx.Err() == nil {
+		return nil
+	}
+	return context.Cause(c.closeCtx)
+}
+
+// bind returns a copy of ctx that is also canceled when the client is
+// closed. The release func must be called once the request and its
+// response body are finished with.
+func (c *BaseClient) bind(ctx context.Context) (context.Context, func(), error) {
+	if err := c.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	bound, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(c.closeCtx, func() {
+		cancel(context.Cause(c.closeCtx))
+	})
+	return bound, func() {
+		stop()
+		cancel(context.Canceled)
+	}, nil
+}
+
+// closedCause returns the ClientClosedError that canceled ctx, if any, or err.
+func closedCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.IsClientClosedError(cause) {
+		return cause
+	}
+	return err
+}
+
+// boundBody is a response body whose request context is bound to the
+// client. Closing it releases the binding, and read errors caused by
+// closing the client are reported as ClientClosedError.
+type boundBody struct {
+	io.ReadCloser
+	ctx     context.Context
+	release func()
+	once    sync.Once
+}
+
+// newBoundBody wraps body so that closing it calls release.
+func newBoundBody(ctx context.Context, body io.ReadCloser, release func()) *boundBody {
+	return &boundBody{ReadCloser: body, ctx: ctx, release: release}
+}
+
+// Read implements io.Reader.
+func (b *boundBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err != nil && err != io.EOF {
+		err = closedCause(b.ctx, err)
+	}
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *boundBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/compress"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+)
+
+// maxEncodingErrorBytes is the most of a 400 response body read to check
+// whether the server rejected the request encoding.
+const maxEncodingErrorBytes = 64 << 10
+
+// compressRequest gzips the JSON body of req if request compression is
+// enabled and the body has at least RequestCompressionMinBytes bytes. It
+// returns the uncompressed body if the body was compressed, and nil
+// otherwise. The body is compressed once and replayed on retries.
+func (c *BaseClient) compressRequest(req *http.Request) ([]byte, error) {
+	minBytes := c.config.RequestCompressionMinBytes
+	if minBytes <= 0 || c.compressionOff.Load() || req.GetBody == nil || req.ContentLength < minBytes {
+		return nil, nil
+	}
+	if !strings.HasPrefix(req.Header.Get(constants.HeaderContentType), constants.ContentTypeJSON) ||
+		req.Header.Get(constants.HeaderContentEncoding) != "" {
+		return nil, nil
+	}
+
+	data, err := compress.ReadBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	gzipped, err := compress.Gzip(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(gzipped) >= len(data) {
+		return nil, nil
+	}
+
+	// Close the replaced body, which may hold a pooled buffer
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	req.Header.Set(constants.HeaderContentEncoding, compress.EncodingGzip)
+	compress.SetBody(req, gzipped)
+	return data, nil
+}
+
+// retryUncompressed sends req once more with its uncompressed body if the
+// server rejected the gzipped body, and disables request compression for
+// the client. Otherwise resp and err are returned unchanged. req is
+// restored to the uncompressed body so that later resends match it.
+func (c *BaseClient) retryUncompressed(ctx context.Context, req *http.Request, uncompressed []byte, resp *http.Response, err error, send sendFunc) (*http.Response, error) {
+	if err != nil || uncompressed == nil || !rejectsEncoding(resp) {
+		return resp, err
+	}
+
+	c.compressionOff.Store(true)
+	hooks.Warn(ctx, c.httpClient.GetClient().Hooks(), &hooks.WarningEvent{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Message: fmt.Sprintf("request compression disabled: the server rejected a gzip-encoded body with status %d", resp.StatusCode),
+	})
+	if c.logger != nil {
+		c.logger.WarnContext(ctx, "Request compression disabled after the server rejected a gzip-encoded body",
+			"method", req.Method, "path", req.URL.Path, "status_code", resp.StatusCode)
+	}
+
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	req.Header.Del(constants.HeaderContentEncoding)
+	compress.SetBody(req, uncompressed)
+	retry := req.Clone(ctx)
+	retry.Body, _ = req.GetBody()
+	return send(ctx, retry)
+}
+
+// rejectsEncoding reports whether resp rejects the encoding of the
+// request: a 415 status, or a 400 status whose body mentions the encoding.
+// The body of resp is left readable.
+func rejectsEncoding(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusUnsupportedMediaType:
+		return true
+	case http.StatusBadRequest:
+	default:
+		return false
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxEncodingErrorBytes))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	text := strings.ToLower(string(data))
+	return strings.Contains(text, "encoding") || strings.Contains(text, "gzip") || strings.Contains(text, "compress")
+}
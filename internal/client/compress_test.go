@@ -0,0 +1,319 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/compress"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// receivedRequest is a request body as received by the test server.
+type receivedRequest struct {
+	encoding string
+	body     []byte
+}
+
+// compressionServer returns a server that records the bodies it receives,
+// decompressed, and answers with respond.
+func compressionServer(t *testing.T, respond func(w http.ResponseWriter, n int)) (*httptest.Server, func() []receivedRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		received []receivedRequest
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		encoding := r.Header.Get("Content-Encoding")
+		if encoding == "gzip" {
+			data, err = compress.Gunzip(data)
+			require.NoError(t, err, "the body is valid gzip")
+		}
+
+		mu.Lock()
+		received = append(received, receivedRequest{encoding: encoding, body: data})
+		n := len(received)
+		mu.Unlock()
+
+		respond(w, n)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []receivedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func respondOK(w http.ResponseWriter, _ int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// embeddingBody returns a request body of about n input texts.
+func embeddingBody(n int) map[string]any {
+	input := make([]string, n)
+	for i := range input {
+		input[i] = fmt.Sprintf("Document %d: the quick brown fox jumps over the lazy dog.", i)
+	}
+	return map[string]any{"model": "embedding-3", "input": input}
+}
+
+func newCompressionClient(t *testing.T, url string, minBytes int64, h ...hooks.Hooks) *BaseClient {
+	t.Helper()
+
+	client, err := NewBaseClient(&Config{
+		APIKey:                     "test-key.test-secret",
+		BaseURL:                    url,
+		RequestCompressionMinBytes: minBytes,
+		RetryPolicy:                transport.RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond},
+		Hooks:                      h,
+		LogBodies:                  len(h) > 0,
+	})
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestBaseClient_RequestCompression(t *testing.T) {
+	t.Parallel()
+
+	body := embeddingBody(200)
+	want, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	t.Run("large body", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, respondOK)
+		client := newCompressionClient(t, server.URL, 1024)
+
+		resp, err := client.Post(context.Background(), "/embeddings", body)
+		require.NoError(t, err)
+		resp.Close()
+
+		requests := received()
+		require.Len(t, requests, 1)
+		assert.Equal(t, "gzip", requests[0].encoding)
+		assert.JSONEq(t, string(want), string(requests[0].body))
+	})
+
+	t.Run("small body", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, respondOK)
+		client := newCompressionClient(t, server.URL, int64(len(want))+1)
+
+		resp, err := client.Post(context.Background(), "/embeddings", body)
+		require.NoError(t, err)
+		resp.Close()
+
+		assert.Empty(t, received()[0].encoding)
+		assert.JSONEq(t, string(want), string(received()[0].body))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, respondOK)
+		client := newCompressionClient(t, server.URL, 0)
+
+		resp, err := client.Post(context.Background(), "/embeddings", body)
+		require.NoError(t, err)
+		resp.Close()
+
+		assert.Empty(t, received()[0].encoding)
+	})
+
+	t.Run("retries replay the compressed body", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, func(w http.ResponseWriter, n int) {
+			if n == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			respondOK(w, n)
+		})
+		client := newCompressionClient(t, server.URL, 1024)
+
+		resp, err := client.Put(context.Background(), "/embeddings", body)
+		require.NoError(t, err)
+		resp.Close()
+
+		requests := received()
+		require.Len(t, requests, 2)
+		for _, req := range requests {
+			assert.Equal(t, "gzip", req.encoding)
+			assert.JSONEq(t, string(want), string(req.body))
+		}
+	})
+
+	t.Run("streams are not compressed", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, func(w http.ResponseWriter, _ int) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: [DONE]\n\n"))
+		})
+		client := newCompressionClient(t, server.URL, 1024)
+
+		stream, err := client.Stream(context.Background(), "/chat/completions", body)
+		require.NoError(t, err)
+		stream.Close()
+
+		assert.Empty(t, received()[0].encoding)
+	})
+
+	t.Run("uploads are not compressed", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, respondOK)
+		client := newCompressionClient(t, server.URL, 16)
+
+		form := NewForm()
+		form.WriteField("purpose", "batch")
+		form.AddFile(&FormFile{Field: "file", Filename: "input.jsonl", Reader: strings.NewReader(strings.Repeat(`{"a":1}`+"\n", 100))})
+		resp, err := client.PostForm(context.Background(), "/files", form)
+		require.NoError(t, err)
+		resp.Close()
+
+		assert.Empty(t, received()[0].encoding)
+	})
+
+	t.Run("hooks receive the uncompressed body", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := compressionServer(t, respondOK)
+		recorder := &bodyRecorder{}
+		client := newCompressionClient(t, server.URL, 1024, recorder)
+
+		resp, err := client.Post(context.Background(), "/embeddings", body)
+		require.NoError(t, err)
+		resp.Close()
+
+		assert.JSONEq(t, string(want), string(recorder.body()))
+	})
+}
+
+func TestBaseClient_RequestCompression_Rejected(t *testing.T) {
+	t.Parallel()
+
+	body := embeddingBody(200)
+	want, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"unsupported media type", http.StatusUnsupportedMediaType, ``},
+		{"bad request about the encoding", http.StatusBadRequest, `{"error":{"code":"1210","message":"Unsupported Content-Encoding: gzip"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, received := compressionServer(t, func(w http.ResponseWriter, n int) {
+				if n == 1 {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+					return
+				}
+				respondOK(w, n)
+			})
+			warnings := &warningRecorder{}
+			client := newCompressionClient(t, server.URL, 1024, warnings)
+
+			resp, err := client.Post(context.Background(), "/embeddings", body)
+			require.NoError(t, err)
+			resp.Close()
+
+			// Compression stays disabled for the client
+			resp, err = client.Post(context.Background(), "/embeddings", body)
+			require.NoError(t, err)
+			resp.Close()
+
+			requests := received()
+			require.Len(t, requests, 3)
+			assert.Equal(t, "gzip", requests[0].encoding)
+			for _, req := range requests[1:] {
+				assert.Empty(t, req.encoding)
+				assert.JSONEq(t, string(want), string(req.body))
+			}
+			assert.Equal(t, 1, warnings.count())
+		})
+	}
+
+	t.Run("other bad request", func(t *testing.T) {
+		t.Parallel()
+
+		server, received := compressionServer(t, func(w http.ResponseWriter, _ int) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"1214","message":"input is too long"}}`))
+		})
+		client := newCompressionClient(t, server.URL, 1024)
+
+		_, err := client.Post(context.Background(), "/embeddings", body)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "input is too long", "the error body is still readable")
+		assert.Len(t, received(), 1)
+		assert.False(t, client.compressionOff.Load())
+	})
+}
+
+// bodyRecorder records the body of the last request event.
+type bodyRecorder struct {
+	hooks.NoopHooks
+	mu   sync.Mutex
+	last []byte
+}
+
+func (r *bodyRecorder) OnRequest(_ context.Context, e *hooks.RequestEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.last = e.Body
+}
+
+func (r *bodyRecorder) body() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// warningRecorder counts warnings.
+type warningRecorder struct {
+	hooks.NoopHooks
+	mu       sync.Mutex
+	warnings int
+}
+
+func (r *warningRecorder) OnWarning(context.Context, *hooks.WarningEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.warnings++
+}
+
+func (r *warningRecorder) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.warnings
+}
@@ -0,0 +1,364 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// Reopener is implemented by upload readers that can produce their content
+// again from the start, so that an upload can be retried.
+type Reopener interface {
+	// Reopen returns a new reader positioned at the start of the content.
+	Reopen() (io.ReadCloser, error)
+}
+
+// Form is a multipart/form-data request body. Unlike a body written with
+// multipart.Writer, it is written while it is sent and can be written again
+// when the request is retried.
+type Form struct {
+	boundary string
+	parts    []formPart
+}
+
+// formPart is a form field or, if file is set, a file.
+type formPart struct {
+	name  string
+	value string
+	file  *FormFile
+}
+
+// FormFile is a file of a Form.
+type FormFile struct {
+	// Field is the form field name.
+	Field string
+
+	// Filename is the file name sent with the content.
+	Filename string
+
+	// Reader is the file content. Content implementing Reopener or
+	// io.Seeker is read again on retries; other content is buffered up to
+	// the upload buffer size.
+	Reader io.Reader
+
+	// MaxSize rejects content larger than MaxSize bytes with the error
+	// returned by TooLarge. Zero means no limit.
+	MaxSize int64
+
+	// TooLarge returns the error for content of at least size bytes.
+	TooLarge func(size int64) error
+}
+
+// NewForm returns an empty form with a random boundary.
+func NewForm() *Form {
+	return &Form{boundary: multipart.NewWriter(io.Discard).Boundary()}
+}
+
+// WriteField adds a field.
+func (f *Form) WriteField(name, value string) {
+	f.parts = append(f.parts, formPart{name: name, value: value})
+}
+
+// AddFile adds a file.
+func (f *Form) AddFile(file *FormFile) {
+	f.parts = append(f.parts, formPart{file: file})
+}
+
+// ContentType returns the Content-Type of the form, including its boundary.
+func (f *Form) ContentType() string {
+	return "multipart/form-data; boundary=" + f.boundary
+}
+
+// write writes one copy of the form to w.
+func (f *Form) write(w io.Writer, sources []*uploadSource) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(f.boundary); err != nil {
+		return err
+	}
+
+	for i, part := range f.parts {
+		if part.file == nil {
+			if err := writer.WriteField(part.name, part.value); err != nil {
+				return fmt.Errorf("failed to write %s field: %w", part.name, err)
+			}
+			continue
+		}
+
+		dst, err := writer.CreateFormFile(part.file.Field, part.file.Filename)
+		if err != nil {
+			return fmt.Errorf("failed to create form file: %w", err)
+		}
+		if sources[i] != nil {
+			if err := sources[i].copyTo(dst); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to close multipart writer: %w", err)
+	}
+	return nil
+}
+
+// uploadSource writes the content of a file for each attempt.
+type uploadSource struct {
+	file *FormFile
+
+	// size is the content length, or -1 if unknown
+	size int64
+
+	// replayable reports whether the content can be written more than once
+	replayable bool
+
+	// mu keeps an abandoned attempt from reading while the next one rewinds
+	mu   sync.Mutex
+	open func() (io.Reader, func(), error)
+}
+
+// copyTo writes the content to w.
+func (s *uploadSource) copyTo(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, done, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	if s.file.MaxSize > 0 && s.size < 0 {
+		// Read one byte past the limit to detect oversized content
+		n, err := io.Copy(w, io.LimitReader(r, s.file.MaxSize+1))
+		if err != nil {
+			return fmt.Errorf("failed to copy file content: %w", err)
+		}
+		if n > s.file.MaxSize {
+			return s.file.TooLarge(n)
+		}
+		return nil
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		return fmt.Errorf("failed to copy file content: %w", err)
+	}
+	return nil
+}
+
+// newUploadSource prepares the content of file for replay. Content that
+// implements Reopener is reopened and seekable content is rewound. Other
+// content is buffered if it holds at most bufferSize bytes; larger content
+// is sent once.
+func newUploadSource(file *FormFile, bufferSize int64) (*uploadSource, error) {
+	s := &uploadSource{file: file, size: -1, replayable: true}
+
+	if reopener, ok := file.Reader.(Reopener); ok {
+		s.open = func() (io.Reader, func(), error) {
+			r, err := reopener.Reopen()
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to reopen file content: %w", err)
+			}
+			return r, func() { r.Close() }, nil
+		}
+		return s, nil
+	}
+
+	if seeker, ok := file.Reader.(io.ReadSeeker); ok {
+		if start, end, ok := seekRange(seeker); ok {
+			s.size = end - start
+			s.open = func() (io.Reader, func(), error) {
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, nil, fmt.Errorf("failed to rewind file content: %w", err)
+				}
+				return io.LimitReader(seeker, s.size), func() {}, nil
+			}
+			return s, nil
+		}
+	}
+
+	// Read one byte past the buffer size to tell whether it all fits
+	data, err := io.ReadAll(io.LimitReader(file.Reader, max(bufferSize, 0)+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file content: %w", err)
+	}
+	if int64(len(data)) <= bufferSize {
+		s.size = int64(len(data))
+		s.open = func() (io.Reader, func(), error) {
+			return bytes.NewReader(data), func() {}, nil
+		}
+		return s, nil
+	}
+
+	// Too large to buffer: send what was read followed by the rest, once
+	s.replayable = false
+	used := false
+	s.open = func() (io.Reader, func(), error) {
+		if used {
+			return nil, nil, fmt.Errorf("file content cannot be read again")
+		}
+		used = true
+		return io.MultiReader(bytes.NewReader(data), file.Reader), func() {}, nil
+	}
+	return s, nil
+}
+
+// seekRange returns the current and end offsets of r, leaving r at the
+// current offset. It reports false if r cannot seek.
+func seekRange(r io.Seeker) (start, end int64, ok bool) {
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, 0, false
+	}
+	end, err = r.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, 0, false
+	}
+	if _, err := r.Seek(start, io.SeekStart); err != nil {
+		return 0, 0, false
+	}
+	return start, max(end, start), true
+}
+
+// formBody is the body of one attempt. The form is written on the first
+// read, so a body that is never read starts nothing.
+type formBody struct {
+	form    *Form
+	sources []*uploadSource
+
+	once sync.Once
+	pr   *io.PipeReader
+}
+
+// Read implements io.Reader.
+func (b *formBody) Read(p []byte) (int, error) {
+	b.once.Do(func() {
+		pr, pw := io.Pipe()
+		b.pr = pr
+		go func() {
+			pw.CloseWithError(b.form.write(pw, b.sources))
+		}()
+	})
+	return b.pr.Read(p)
+}
+
+// Close implements io.Closer. It stops a write in progress.
+func (b *formBody) Close() error {
+	b.once.Do(func() {})
+	if b.pr != nil {
+		return b.pr.Close()
+	}
+	return nil
+}
+
+// PostForm performs a POST request with a multipart/form-data body.
+//
+// The body is streamed and written again for each attempt, so a failed
+// upload is retried like any other request. A file whose content cannot be
+// read again, because it neither seeks nor implements Reopener and is
+// larger than Config.UploadBufferSize, is sent once: retries are disabled
+// for the request and a warning is passed to the hooks. Files that exceed
+// their MaxSize or the request size limit fail before anything is sent
+// when their size is known in advance, and while sending otherwise.
+func (c *BaseClient) PostForm(ctx context.Context, path string, form *Form) (*models.APIResponse, error) {
+	sources := make([]*uploadSource, len(form.parts))
+	replayable := true
+	sizes := make([]int64, 0, len(form.parts))
+	for i, part := range form.parts {
+		if part.file == nil || part.file.Reader == nil {
+			continue
+		}
+
+		source, err := newUploadSource(part.file, c.config.UploadBufferSize)
+		if err != nil {
+			return nil, err
+		}
+		if part.file.MaxSize > 0 && source.size > part.file.MaxSize {
+			return nil, part.file.TooLarge(source.size)
+		}
+		sources[i] = source
+		replayable = replayable && source.replayable
+		sizes = append(sizes, source.size)
+	}
+
+	contentLength, err := form.contentLength(sizes)
+	if err != nil {
+		return nil, err
+	}
+	if contentLength >= 0 {
+		if err := c.checkRequestSize(contentLength); err != nil {
+			return nil, err
+		}
+	}
+
+	newBody := func() (io.ReadCloser, error) {
+		body := &formBody{form: form, sources: sources}
+		return limitBody(body, "request", contentLength, c.config.MaxRequestBytes), nil
+	}
+
+	req, err := c.httpClient.GetClient().NewRequest(ctx, http.MethodPost, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.ContentType())
+	req.Body, _ = newBody()
+	req.ContentLength = contentLength
+	if replayable {
+		req.GetBody = newBody
+	} else {
+		ctx = c.disableRetries(ctx, req)
+	}
+
+	return c.Do(ctx, req)
+}
+
+// contentLength returns the length of the form with files of the given
+// sizes, or -1 if a size is unknown.
+func (f *Form) contentLength(sizes []int64) (int64, error) {
+	var total int64
+	for _, size := range sizes {
+		if size < 0 {
+			return -1, nil
+		}
+		total += size
+	}
+
+	// Write the form without file content to count the rest
+	var counter countingWriter
+	if err := f.write(&counter, make([]*uploadSource, len(f.parts))); err != nil {
+		return 0, err
+	}
+	return total + counter.n, nil
+}
+
+// disableRetries returns a copy of ctx that sends req once, and reports
+// the reason to the hooks.
+func (c *BaseClient) disableRetries(ctx context.Context, req *http.Request) context.Context {
+	policy, _ := transport.RetryPolicyFromContext(ctx)
+	policy.MaxAttempts = 1
+
+	hooks.Warn(ctx, c.httpClient.GetClient().Hooks(), &hooks.WarningEvent{
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Message: fmt.Sprintf("upload cannot be retried: file content is larger than the %d byte upload buffer and cannot be read again", c.config.UploadBufferSize),
+	})
+	return transport.ContextWithRetryPolicy(ctx, policy)
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	n int64
+}
+
+// Write implements io.Writer.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// Idempotent returns ctx for a call that creates a server-side resource.
+// If automatic idempotency keys are enabled and ctx carries no
+// Idempotency-Key header, a generated key is attached. The key is the same
+// for every attempt of the call, and marks it as safe to retry.
+func (c *BaseClient) Idempotent(ctx context.Context) context.Context {
+	if !c.config.AutoIdempotencyKeys || transport.HeadersFromContext(ctx).Get(constants.HeaderIdempotencyKey) != "" {
+		return ctx
+	}
+	return transport.ContextWithHeaders(ctx, http.Header{constants.HeaderIdempotencyKey: {transport.NewRequestID()}})
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// marshalBody encodes a JSON request body and checks it against the
+// request size limit.
+func (c *BaseClient) marshalBody(body interface{}) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+	if err := c.checkRequestSize(int64(len(data))); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// checkRequestSize returns a PayloadTooLargeError if size exceeds the
+// request size limit.
+func (c *BaseClient) checkRequestSize(size int64) error {
+	if limit := c.config.MaxRequestBytes; limit > 0 && size > limit {
+		return errors.NewPayloadTooLargeError("request", size, limit)
+	}
+	return nil
+}
+
+// limitedBody is a body that fails with a PayloadTooLargeError
+// once more than limit bytes are read, or on the first read if the
+// declared content length already exceeds the limit.
+type limitedBody struct {
+	io.ReadCloser
+	payload       string
+	limit         int64
+	contentLength int64
+	read          int64
+	err           error
+}
+
+// limitBody wraps body so that reading more than limit bytes fails. A
+// limit of zero or less returns body unchanged.
+func limitBody(body io.ReadCloser, payload string, contentLength, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, payload: payload, limit: limit, contentLength: contentLength}
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.err != nil {
+		return 0, b.err
+	}
+	if b.contentLength > b.limit {
+		b.err = errors.NewPayloadTooLargeError(b.payload, b.contentLength, b.limit)
+		return 0, b.err
+	}
+
+	// Read one byte past the limit to tell a body of exactly limit bytes
+	// from a larger one
+	if remaining := b.limit + 1 - b.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if b.read > b.limit {
+		b.err = errors.NewPayloadTooLargeError(b.payload, b.read, b.limit)
+		return n - int(b.read-b.limit), b.err
+	}
+	return n, err
+}
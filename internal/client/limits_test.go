@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimitedTestClient(t *testing.T, handler http.HandlerFunc, config Config) *BaseClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	config.APIKey = "test-key.test-secret"
+	config.BaseURL = server.URL
+	client, err := NewBaseClient(&config)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestBaseClient_MaxRequestBytes(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	client := newLimitedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{}`))
+	}, Config{MaxRequestBytes: 64})
+
+	_, err := client.Post(context.Background(), "/test", map[string]string{"text": strings.Repeat("a", 100)})
+	var sizeErr *errors.PayloadTooLargeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, "request", sizeErr.Payload)
+	assert.Equal(t, int64(111), sizeErr.Size)
+	assert.Equal(t, int64(64), sizeErr.Limit)
+
+	form := NewForm()
+	form.AddFile(&FormFile{Field: "file", Filename: "a.txt", Reader: strings.NewReader(strings.Repeat("a", 100))})
+	_, err = client.PostForm(context.Background(), "/upload", form)
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Greater(t, sizeErr.Size, int64(100), "the whole form is measured")
+	assert.Zero(t, requests, "oversized requests must not be sent")
+
+	resp, err := client.Post(context.Background(), "/test", map[string]string{"text": "small"})
+	require.NoError(t, err)
+	resp.Close()
+	assert.Equal(t, 1, requests)
+
+	// Forms of unknown length are limited while they are sent
+	form = NewForm()
+	form.AddFile(&FormFile{Field: "file", Filename: "a.txt", Reader: iotest.OneByteReader(strings.NewReader(strings.Repeat("a", 100)))})
+	_, err = client.PostForm(context.Background(), "/upload", form)
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, int64(65), sizeErr.Size)
+}
+
+func TestBaseClient_MaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	body := `{"text":"` + strings.Repeat("a", 100) + `"}`
+	client := newLimitedTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Flushing before writing leaves the length unknown
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte(body))
+	}, Config{MaxResponseBytes: 64})
+
+	for _, path := range []string{"/sized", "/chunked"} {
+		resp, err := client.Get(context.Background(), path, nil)
+		require.NoError(t, err)
+
+		var v map[string]string
+		err = client.ParseJSON(resp, &v)
+		var sizeErr *errors.PayloadTooLargeError
+		require.ErrorAs(t, err, &sizeErr, path)
+		assert.Equal(t, "response", sizeErr.Payload)
+		assert.Equal(t, int64(64), sizeErr.Limit)
+		if path == "/sized" {
+			assert.Equal(t, int64(len(body)), sizeErr.Size)
+		} else {
+			assert.Equal(t, int64(65), sizeErr.Size)
+		}
+	}
+}
+
+func TestLimitedBody(t *testing.T) {
+	t.Parallel()
+
+	// A body of exactly the limit is read in full
+	body := limitBody(io.NopCloser(strings.NewReader("abcd")), "response", -1, 4)
+	data, err := io.ReadAll(iotest.OneByteReader(body))
+	require.NoError(t, err)
+	assert.Equal(t, "abcd", string(data))
+
+	// Reads stop at the limit
+	body = limitBody(io.NopCloser(strings.NewReader("abcdef")), "stream", -1, 4)
+	data, err = io.ReadAll(body)
+	assert.True(t, errors.IsPayloadTooLargeError(err))
+	assert.Equal(t, "abcd", string(data))
+
+	// No limit
+	body = io.NopCloser(strings.NewReader("abcdef"))
+	assert.Equal(t, body, limitBody(body, "response", -1, 0))
+}
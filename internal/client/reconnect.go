@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// EnableReconnect makes the stream of streamResp, opened with method, path
+// and body, reconnect up to maxReconnects times after a transport error.
+// Each reconnect repeats the request with the Last-Event-ID header set to
+// the ID of the last event read and is reported to the hooks and logger.
+func (c *BaseClient) EnableReconnect(streamResp *models.StreamResponse, method, path string, body interface{}, maxReconnects int, backoff time.Duration) {
+	if maxReconnects <= 0 {
+		return
+	}
+
+	streamResp.MaxReconnects = maxReconnects
+	streamResp.ReconnectBackoff = backoff
+	streamResp.Reconnect = func(ctx context.Context, lastEventID string, attempt int, cause error) (io.ReadCloser, error) {
+		hooks.Warn(ctx, c.httpClient.GetClient().Hooks(), &hooks.WarningEvent{
+			Method: method,
+			Path:   path,
+			Message: fmt.Sprintf("stream interrupted: %v; reconnecting (attempt %d of %d) after event %q",
+				cause, attempt, maxReconnects, lastEventID),
+		})
+		if c.logger != nil {
+			c.logger.WarnContext(ctx, "Stream interrupted, reconnecting",
+				"method", method, "path", path, "attempt", attempt, "max_reconnects", maxReconnects,
+				"last_event_id", lastEventID, "error", cause)
+		}
+
+		if lastEventID != "" {
+			ctx = transport.ContextWithHeaders(ctx, http.Header{constants.HeaderLastEventID: {lastEventID}})
+		}
+		resp, err := c.StreamMethod(ctx, method, path, body)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}
+}
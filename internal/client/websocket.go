@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/websocket"
+)
+
+// DialWebSocket opens an authenticated WebSocket connection to path.
+//
+// The handshake is not retried. A handshake rejected with an error status
+// returns the same error as other requests. The connection is closed when
+// ctx is done or the client is closed, and messages are limited to
+// Config.MaxResponseBytes.
+func (c *BaseClient) DialWebSocket(ctx context.Context, path string) (*websocket.Conn, error) {
+	boundCtx, release, err := c.bind(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.httpClient.GetClient().NewRequest(boundCtx, http.MethodGet, path, nil)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	key := websocket.PrepareRequest(req)
+
+	if _, err := c.addAuth(boundCtx, req); err != nil {
+		release()
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.GetClient().Upgrade(boundCtx, req)
+	if err != nil {
+		release()
+		return nil, closedCause(boundCtx, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		release()
+		apiResp := models.NewAPIResponse(resp, time.Since(start))
+		return nil, c.reportError(ctx, apiResp, c.handleErrorResponse(apiResp))
+	}
+
+	conn, err := websocket.NewClientConn(resp, key, c.config.MaxResponseBytes)
+	if err != nil {
+		resp.Body.Close()
+		release()
+		return nil, err
+	}
+
+	stop := context.AfterFunc(boundCtx, func() { conn.Close() })
+	conn.SetCloseHook(func() {
+		stop()
+		release()
+	})
+	return conn, nil
+}
@@ -0,0 +1,77 @@
+// Package compress gzips request bodies and reads them back for the parts
+// of the SDK that inspect requests, such as hooks and the rate limiter.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+)
+
+// EncodingGzip is the Content-Encoding of gzipped bodies.
+const EncodingGzip = "gzip"
+
+// Gzip returns data compressed with gzip.
+func Gzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Gunzip returns the decompressed content of gzipped data.
+func Gunzip(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// IsGzipped reports whether the body of req is gzipped.
+func IsGzipped(req *http.Request) bool {
+	return strings.EqualFold(strings.TrimSpace(req.Header.Get(constants.HeaderContentEncoding)), EncodingGzip)
+}
+
+// ReadBody returns the body of req as sent before compression, read from
+// GetBody so that req can still be sent. It returns nil if req has no
+// replayable body.
+func ReadBody(req *http.Request) ([]byte, error) {
+	if req.GetBody == nil {
+		return nil, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if IsGzipped(req) {
+		return Gunzip(data)
+	}
+	return data, nil
+}
+
+// SetBody replaces the body of req with data, replayable through GetBody.
+func SetBody(req *http.Request, data []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+}
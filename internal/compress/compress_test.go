@@ -0,0 +1,115 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGzip(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(strings.Repeat(`{"role":"user","content":"Hello"}`, 100))
+	gzipped, err := Gzip(data)
+	require.NoError(t, err)
+	assert.Less(t, len(gzipped), len(data))
+
+	decoded, err := Gunzip(gzipped)
+	require.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	_, err = Gunzip(data)
+	assert.Error(t, err)
+}
+
+func TestReadBody(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"model":"glm-4.7"}`)
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com", bytes.NewReader(data))
+	require.NoError(t, err)
+	body, err := ReadBody(req)
+	require.NoError(t, err)
+	assert.Equal(t, data, body)
+
+	gzipped, err := Gzip(data)
+	require.NoError(t, err)
+	SetBody(req, gzipped)
+	req.Header.Set("Content-Encoding", "GZIP")
+	assert.True(t, IsGzipped(req))
+	assert.Equal(t, int64(len(gzipped)), req.ContentLength)
+
+	body, err = ReadBody(req)
+	require.NoError(t, err)
+	assert.Equal(t, data, body)
+
+	// The body can still be sent
+	body, err = ReadBody(req)
+	require.NoError(t, err)
+	assert.Equal(t, data, body)
+
+	req, err = http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	require.NoError(t, err)
+	body, err = ReadBody(req)
+	require.NoError(t, err)
+	assert.Nil(t, body)
+}
+
+// benchmarkPayloads are request bodies typical of large requests.
+func benchmarkPayloads(b *testing.B) map[string][]byte {
+	b.Helper()
+
+	input := make([]string, 2000)
+	for i := range input {
+		input[i] = fmt.Sprintf("Product %d: a lightweight waterproof jacket with taped seams and a packable hood.", i)
+	}
+	messages := make([]map[string]string, 400)
+	for i := range messages {
+		role := "user"
+		if i%2 == 1 {
+			role = "assistant"
+		}
+		messages[i] = map[string]string{"role": role, "content": fmt.Sprintf("Turn %d. Could you summarize the quarterly report and list the open action items for the team?", i)}
+	}
+
+	payloads := make(map[string][]byte)
+	for name, body := range map[string]any{
+		"embedding_batch": map[string]any{"model": "embedding-3", "input": input},
+		"chat_history":    map[string]any{"model": "glm-4.7", "messages": messages},
+	} {
+		data, err := json.Marshal(body)
+		require.NoError(b, err)
+		payloads[name] = data
+	}
+	return payloads
+}
+
+// BenchmarkGzip reports the size of typical large request bodies before
+// and after compression, in bytes, and the compressed size as a
+// percentage of the original.
+func BenchmarkGzip(b *testing.B) {
+	for name, data := range benchmarkPayloads(b) {
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+
+			var gzipped []byte
+			for i := 0; i < b.N; i++ {
+				var err error
+				if gzipped, err = Gzip(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(len(data)), "raw-bytes")
+			b.ReportMetric(float64(len(gzipped)), "gzip-bytes")
+			b.ReportMetric(100*float64(len(gzipped))/float64(len(data)), "%size")
+		})
+	}
+}
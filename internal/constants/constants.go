@@ -26,10 +26,14 @@ const (
 // HTTP Client Defaults
 
 const (
-	// DefaultTimeout is the default timeout for HTTP requests.
+	// DefaultTimeout is the default timeout for non-streaming HTTP requests.
 	// Equivalent to Python SDK's timeout=300.0 (total timeout).
 	DefaultTimeout = 300 * time.Second
 
+	// DefaultStreamReadTimeout is the default maximum wait for the response
+	// headers or the next bytes of a stream.
+	DefaultStreamReadTimeout = 300 * time.Second
+
 	// DefaultConnectTimeout is the default connection timeout.
 	// Equivalent to Python SDK's connect=8.0.
 	DefaultConnectTimeout = 8 * time.Second
@@ -37,19 +41,23 @@ const (
 	// DefaultMaxRetries is the default maximum number of retry attempts.
 	DefaultMaxRetries = 3
 
-	// DefaultMaxConnections is the default maximum number of connections.
-	// Equivalent to Python SDK's max_connections=50.
-	DefaultMaxConnections = 50
+	// DefaultMaxConnections is the number of concurrent requests the
+	// connection pool is sized for.
+	DefaultMaxConnections = 256
 
 	// DefaultMaxIdleConns is the default maximum number of idle connections.
-	// Equivalent to Python SDK's max_keepalive_connections=10.
-	DefaultMaxIdleConns = 10
+	DefaultMaxIdleConns = 256
 
 	// DefaultMaxIdleConnsPerHost is the maximum idle connections per host.
-	DefaultMaxIdleConnsPerHost = 10
+	// API clients talk to a single host, so it matches DefaultMaxIdleConns
+	// to keep a connection for every concurrent request.
+	DefaultMaxIdleConnsPerHost = 256
 
 	// DefaultIdleConnTimeout is the timeout for idle connections.
 	DefaultIdleConnTimeout = 90 * time.Second
+
+	// DefaultTLSHandshakeTimeout is the maximum duration of a TLS handshake.
+	DefaultTLSHandshakeTimeout = 10 * time.Second
 )
 
 // Retry Configuration
@@ -89,6 +97,27 @@ const (
 	// Equivalent to Python SDK's x-source-channel.
 	HeaderSourceChannel = "x-source-channel"
 
+	// HeaderOrganization scopes a request to an organization.
+	HeaderOrganization = "Zai-Organization"
+
+	// HeaderProject scopes a request to a project.
+	HeaderProject = "Zai-Project"
+
+	// HeaderIdempotencyKey marks a request as safe to repeat, so that it is
+	// retried like an idempotent request.
+	HeaderIdempotencyKey = "Idempotency-Key"
+
+	// HeaderRequestID carries the ID of a request, sent with every API
+	// request and echoed by the server, for correlating logs.
+	HeaderRequestID = "X-Request-ID"
+
+	// HeaderContentEncoding is the HTTP Content-Encoding header name.
+	HeaderContentEncoding = "Content-Encoding"
+
+	// HeaderLastEventID asks the server to resume a stream after the event
+	// with this ID.
+	HeaderLastEventID = "Last-Event-ID"
+
 	// HeaderRawResponse is used for raw response handling.
 	// Equivalent to Python SDK's X-Stainless-Raw-Response.
 	HeaderRawResponse = "X-Stainless-Raw-Response"
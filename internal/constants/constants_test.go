@@ -62,10 +62,11 @@ func TestHTTPClientDefaults(t *testing.T) {
 		{"Default Timeout", DefaultTimeout, 300 * time.Second},
 		{"Default Connect Timeout", DefaultConnectTimeout, 8 * time.Second},
 		{"Default Max Retries", DefaultMaxRetries, 3},
-		{"Default Max Connections", DefaultMaxConnections, 50},
-		{"Default Max Idle Conns", DefaultMaxIdleConns, 10},
-		{"Default Max Idle Conns Per Host", DefaultMaxIdleConnsPerHost, 10},
+		{"Default Max Connections", DefaultMaxConnections, 256},
+		{"Default Max Idle Conns", DefaultMaxIdleConns, 256},
+		{"Default Max Idle Conns Per Host", DefaultMaxIdleConnsPerHost, 256},
 		{"Default Idle Conn Timeout", DefaultIdleConnTimeout, 90 * time.Second},
+		{"Default TLS Handshake Timeout", DefaultTLSHandshakeTimeout, 10 * time.Second},
 	}
 
 	for _, tt := range tests {
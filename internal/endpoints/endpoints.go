@@ -0,0 +1,265 @@
+// Package endpoints resolves the path of each API endpoint for the platform
+// a client talks to. The international (Z.ai) and mainland (Zhipu)
+// platforms mostly share paths, but some services live under a different
+// prefix or expect different field values.
+package endpoints
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Platform identifies the API platform.
+type Platform int
+
+const (
+	// International is the Z.ai platform (api.z.ai).
+	International Platform = iota
+	// Zhipu is the mainland China platform (open.bigmodel.cn).
+	Zhipu
+)
+
+// String returns the platform name.
+func (p Platform) String() string {
+	switch p {
+	case International:
+		return "international"
+	case Zhipu:
+		return "zhipu"
+	default:
+		return fmt.Sprintf("Platform(%d)", int(p))
+	}
+}
+
+// Detect returns the platform serving baseURL: Zhipu for bigmodel.cn
+// hosts and International otherwise.
+func Detect(baseURL string) Platform {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return International
+	}
+	host := u.Hostname()
+	if host == "bigmodel.cn" || strings.HasSuffix(host, ".bigmodel.cn") {
+		return Zhipu
+	}
+	return International
+}
+
+// Endpoint names an API endpoint as "<service>.<operation>".
+type Endpoint string
+
+// Endpoints of each service.
+const (
+	ChatCompletions Endpoint = "chat.completions"
+
+	Embeddings Endpoint = "embeddings.create"
+
+	ImageGenerations Endpoint = "images.generations"
+	ImageEdits       Endpoint = "images.edits"
+	ImageVariations  Endpoint = "images.variations"
+
+	Files       Endpoint = "files.files"
+	File        Endpoint = "files.file"
+	FileContent Endpoint = "files.content"
+
+	VideoGenerations Endpoint = "videos.generations"
+	VideoResult      Endpoint = "videos.result"
+
+	AudioTranscriptions Endpoint = "audio.transcriptions"
+	AudioTranslations   Endpoint = "audio.translations"
+	AudioSpeech         Endpoint = "audio.speech"
+	AudioRealtime       Endpoint = "audio.realtime"
+
+	Assistant              Endpoint = "assistant.conversation"
+	AssistantSupport       Endpoint = "assistant.support"
+	AssistantConversations Endpoint = "assistant.conversations"
+	AssistantMessages      Endpoint = "assistant.messages"
+
+	Batches     Endpoint = "batches.batches"
+	Batch       Endpoint = "batches.batch"
+	BatchCancel Endpoint = "batches.cancel"
+
+	FineTuningJobs   Endpoint = "fine_tuning.jobs"
+	FineTuningJob    Endpoint = "fine_tuning.job"
+	FineTuningCancel Endpoint = "fine_tuning.cancel"
+	FineTuningEvents Endpoint = "fine_tuning.events"
+
+	WebSearch Endpoint = "web_search.search"
+
+	Moderations Endpoint = "moderations.create"
+
+	Tools     Endpoint = "tools.web_search"
+	Tokenizer Endpoint = "tools.tokenizer"
+
+	Agents            Endpoint = "agents.invoke"
+	AgentsAsyncResult Endpoint = "agents.async_result"
+
+	VoiceClone  Endpoint = "voice.clone"
+	VoiceDelete Endpoint = "voice.delete"
+	VoiceList   Endpoint = "voice.list"
+
+	OCR      Endpoint = "ocr.recognize"
+	OCRTable Endpoint = "ocr.table"
+
+	FileParserCreate Endpoint = "file_parser.create"
+	FileParserResult Endpoint = "file_parser.result"
+	FileParserSync   Endpoint = "file_parser.sync"
+
+	Reader Endpoint = "web_reader.read"
+
+	Models Endpoint = "models.list"
+	Model  Endpoint = "models.retrieve"
+
+	Knowledge          Endpoint = "knowledge.collections"
+	KnowledgeDocuments Endpoint = "knowledge.documents"
+	KnowledgeDocument  Endpoint = "knowledge.document"
+	KnowledgeQuery     Endpoint = "knowledge.query"
+)
+
+// route is the path template of an endpoint. Path parameters are %s verbs.
+type route struct {
+	path string
+
+	// root resolves path against the host of the base URL instead of
+	// appending it to the base URL.
+	root bool
+}
+
+// routes are the routes of the international platform, which every other
+// platform uses unless it overrides them.
+var routes = map[Endpoint]route{
+	ChatCompletions:        {path: "/chat/completions"},
+	Embeddings:             {path: "/embeddings"},
+	ImageGenerations:       {path: "/images/generations"},
+	ImageEdits:             {path: "/images/edits"},
+	ImageVariations:        {path: "/images/variations"},
+	Files:                  {path: "/files"},
+	File:                   {path: "/files/%s"},
+	FileContent:            {path: "/files/%s/content"},
+	VideoGenerations:       {path: "/videos/generations"},
+	VideoResult:            {path: "/async-result/%s"},
+	AudioTranscriptions:    {path: "/audio/transcriptions"},
+	AudioTranslations:      {path: "/audio/translations"},
+	AudioSpeech:            {path: "/audio/speech"},
+	AudioRealtime:          {path: "/realtime"},
+	Assistant:              {path: "/assistant"},
+	AssistantSupport:       {path: "/assistant/list"},
+	AssistantConversations: {path: "/assistant/conversation/list"},
+	AssistantMessages:      {path: "/assistant/conversation/messages"},
+	Batches:                {path: "/batches"},
+	Batch:                  {path: "/batches/%s"},
+	BatchCancel:            {path: "/batches/%s/cancel"},
+	FineTuningJobs:         {path: "/fine_tuning/jobs"},
+	FineTuningJob:          {path: "/fine_tuning/jobs/%s"},
+	FineTuningCancel:       {path: "/fine_tuning/jobs/%s/cancel"},
+	FineTuningEvents:       {path: "/fine_tuning/jobs/%s/events"},
+	WebSearch:              {path: "/web_search"},
+	Moderations:            {path: "/moderations"},
+	Tools:                  {path: "/tools"},
+	Tokenizer:              {path: "/tokenizer"},
+	Agents:                 {path: "/v1/agents"},
+	AgentsAsyncResult:      {path: "/v1/agents/async-result"},
+	VoiceClone:             {path: "/voice/clone"},
+	VoiceDelete:            {path: "/voice/delete"},
+	VoiceList:              {path: "/voice/list"},
+	OCR:                    {path: "/files/ocr"},
+	OCRTable:               {path: "/files/ocr/table"},
+	FileParserCreate:       {path: "/files/parser/create"},
+	FileParserResult:       {path: "/files/parser/result/%s/%s"},
+	FileParserSync:         {path: "/files/parser/sync"},
+	Reader:                 {path: "/reader"},
+	Models:                 {path: "/models"},
+	Model:                  {path: "/models/%s"},
+	Knowledge:              {path: "/knowledge"},
+	KnowledgeDocuments:     {path: "/knowledge/%s/documents"},
+	KnowledgeDocument:      {path: "/knowledge/%s/documents/%s"},
+	KnowledgeQuery:         {path: "/knowledge/%s/query"},
+}
+
+// platformRoutes are the routes that differ from the international
+// platform.
+var platformRoutes = map[Platform]map[Endpoint]route{
+	// The mainland agent API is served from /api/v1 rather than below
+	// the /api/paas/v4 base path.
+	Zhipu: {
+		Agents:            {path: "/api/v1/agents", root: true},
+		AgentsAsyncResult: {path: "/api/v1/agents/async-result", root: true},
+	},
+}
+
+// platformValues maps request field values that differ between platforms,
+// by endpoint and field name, from the international value to the
+// platform's value.
+var platformValues = map[Platform]map[Endpoint]map[string]map[string]string{
+	// Mainland web search has no search-prime engine; search_pro is the
+	// equivalent tier.
+	Zhipu: {
+		WebSearch: {"search_engine": {"search-prime": "search_pro"}},
+	},
+}
+
+// All returns every endpoint, sorted by name.
+func All() []Endpoint {
+	return slices.Sorted(maps.Keys(routes))
+}
+
+// Known reports whether e is an endpoint.
+func Known(e Endpoint) bool {
+	_, ok := routes[e]
+	return ok
+}
+
+// Resolver resolves endpoint paths and field values for a platform.
+type Resolver struct {
+	platform  Platform
+	origin    string
+	overrides map[Endpoint]string
+}
+
+// NewResolver creates a resolver for platform. baseURL is the client's base
+// URL, used to resolve routes served outside of it. overrides replace the
+// path template of individual endpoints, relative to the base URL unless
+// they are absolute URLs.
+func NewResolver(platform Platform, baseURL string, overrides map[Endpoint]string) *Resolver {
+	r := &Resolver{platform: platform, overrides: overrides}
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		r.origin = u.Scheme + "://" + u.Host
+	}
+	return r
+}
+
+// Platform returns the platform of the resolver.
+func (r *Resolver) Platform() Platform {
+	return r.platform
+}
+
+// Path returns the path of endpoint e with args substituted for its path
+// parameters. Paths are relative to the base URL, or absolute URLs for
+// endpoints served outside of it.
+func (r *Resolver) Path(e Endpoint, args ...any) string {
+	if path, ok := r.overrides[e]; ok {
+		return fmt.Sprintf(path, args...)
+	}
+
+	rt, ok := platformRoutes[r.platform][e]
+	if !ok {
+		rt = routes[e]
+	}
+	path := fmt.Sprintf(rt.path, args...)
+	if rt.root && r.origin != "" {
+		return r.origin + path
+	}
+	return path
+}
+
+// Value returns the value to send in field of endpoint e for the
+// international value v.
+func (r *Resolver) Value(e Endpoint, field, v string) string {
+	if mapped, ok := platformValues[r.platform][e][field][v]; ok {
+		return mapped
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package endpoints
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]Platform{
+		"https://api.z.ai/api/paas/v4":         International,
+		"https://open.bigmodel.cn/api/paas/v4": Zhipu,
+		"https://bigmodel.cn/api":              Zhipu,
+		"https://notbigmodel.cn/api":           International,
+		"http://127.0.0.1:8080":                International,
+		"://invalid":                           International,
+	}
+	for baseURL, want := range tests {
+		assert.Equal(t, want, Detect(baseURL), baseURL)
+	}
+}
+
+func TestResolver_Path(t *testing.T) {
+	t.Parallel()
+
+	international := NewResolver(International, "https://api.z.ai/api/paas/v4", nil)
+	zhipu := NewResolver(Zhipu, "https://open.bigmodel.cn/api/paas/v4", nil)
+
+	assert.Equal(t, "/chat/completions", international.Path(ChatCompletions))
+	assert.Equal(t, "/chat/completions", zhipu.Path(ChatCompletions))
+	assert.Equal(t, "/files/parser/result/task-1/text", zhipu.Path(FileParserResult, "task-1", "text"))
+
+	// Root routes resolve against the host
+	assert.Equal(t, "/v1/agents", international.Path(Agents))
+	assert.Equal(t, "https://open.bigmodel.cn/api/v1/agents", zhipu.Path(Agents))
+	assert.Equal(t, "https://open.bigmodel.cn/api/v1/agents/async-result", zhipu.Path(AgentsAsyncResult))
+}
+
+func TestResolver_Overrides(t *testing.T) {
+	t.Parallel()
+
+	r := NewResolver(Zhipu, "https://open.bigmodel.cn/api/paas/v4", map[Endpoint]string{
+		VideoResult: "/mock/videos/%s",
+		Agents:      "http://localhost:9000/agents",
+	})
+	assert.Equal(t, "/mock/videos/task-1", r.Path(VideoResult, "task-1"))
+	assert.Equal(t, "http://localhost:9000/agents", r.Path(Agents))
+	assert.Equal(t, "/videos/generations", r.Path(VideoGenerations))
+}
+
+func TestResolver_Value(t *testing.T) {
+	t.Parallel()
+
+	international := NewResolver(International, "", nil)
+	zhipu := NewResolver(Zhipu, "", nil)
+
+	assert.Equal(t, "search-prime", international.Value(WebSearch, "search_engine", "search-prime"))
+	assert.Equal(t, "search_pro", zhipu.Value(WebSearch, "search_engine", "search-prime"))
+	assert.Equal(t, "search_std", zhipu.Value(WebSearch, "search_engine", "search_std"))
+	assert.Equal(t, "search-prime", zhipu.Value(ChatCompletions, "search_engine", "search-prime"))
+}
+
+func TestAll(t *testing.T) {
+	t.Parallel()
+
+	all := All()
+	assert.IsIncreasing(t, all)
+	for _, e := range all {
+		assert.True(t, Known(e))
+		assert.Contains(t, string(e), ".", "endpoint names are <service>.<operation>")
+
+		// Every platform route has the same parameters as the default route
+		for platform, overrides := range platformRoutes {
+			if rt, ok := overrides[e]; ok {
+				assert.Equal(t, strings.Count(routes[e].path, "%s"), strings.Count(rt.path, "%s"), "%s on %s", e, platform)
+			}
+		}
+	}
+	for _, overrides := range platformRoutes {
+		for e := range overrides {
+			assert.True(t, Known(e), e)
+		}
+	}
+	assert.False(t, Known("chat.complete"))
+}
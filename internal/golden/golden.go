@@ -0,0 +1,72 @@
+// Package golden compares the wire format of API types with the golden
+// files in the testdata directory of the package under test.
+//
+// Request golden files are rewritten from the current encoding with:
+//
+//	go test ./api/types/chat ./api/types/embeddings ./api/types/images -run Wire -update
+package golden
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update rewrites the golden files instead of comparing with them.
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// Path returns the path of the golden file name in testdata.
+func Path(name string) string {
+	return filepath.Join("testdata", name)
+}
+
+// Read returns the content of the golden file name.
+func Read(t testing.TB, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(Path(name))
+	require.NoError(t, err)
+	return data
+}
+
+// AssertJSON fails the test if got is not the JSON of the golden file
+// name. Key order and whitespace are ignored. With -update, the golden
+// file is rewritten from got instead.
+func AssertJSON(t testing.TB, name string, got []byte) {
+	t.Helper()
+
+	if *update {
+		var indented bytes.Buffer
+		require.NoError(t, json.Indent(&indented, got, "", "  "))
+		indented.WriteByte('\n')
+		require.NoError(t, os.WriteFile(Path(name), indented.Bytes(), 0o644))
+		return
+	}
+	assert.JSONEq(t, string(Read(t, name)), string(got), "wire format drifted from %s; run with -update if the change is intended", Path(name))
+}
+
+// Events returns the data of the server-sent events in the golden file
+// name, without the terminating [DONE] event.
+func Events(t testing.TB, name string) [][]byte {
+	t.Helper()
+
+	var events [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(Read(t, name)))
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok || data == "[DONE]" {
+			continue
+		}
+		events = append(events, []byte(data))
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
@@ -0,0 +1,252 @@
+// Package hooks defines request lifecycle hooks for the Z.ai SDK.
+package hooks
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Hooks receives events for every request the SDK makes.
+// Implementations must be safe for concurrent use.
+type Hooks interface {
+	// OnRequest is called before each HTTP attempt is sent.
+	OnRequest(ctx context.Context, event *RequestEvent)
+
+	// OnResponse is called when an HTTP attempt receives a response,
+	// regardless of its status code.
+	OnResponse(ctx context.Context, event *ResponseEvent)
+
+	// OnRetry is called before the SDK backs off and retries a request.
+	OnRetry(ctx context.Context, event *RetryEvent)
+
+	// OnStreamEvent is called for each server-sent event read from a stream.
+	OnStreamEvent(ctx context.Context, event *StreamEvent)
+
+	// OnError is called when an attempt fails without a response, or when
+	// the API returns an error status to the caller.
+	OnError(ctx context.Context, event *ErrorEvent)
+}
+
+// RequestEvent describes an outgoing HTTP attempt.
+type RequestEvent struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path of the request.
+	Path string
+
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+
+	// RequestID is the ID the request is sent with in the X-Request-ID
+	// header. It is the same for every attempt.
+	RequestID string
+
+	// Header is a copy of the request headers with credentials masked.
+	Header http.Header
+
+	// Body is the request body with credentials masked.
+	// Only populated when body logging is enabled.
+	Body []byte
+}
+
+// ResponseEvent describes a received HTTP response.
+type ResponseEvent struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path of the request.
+	Path string
+
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+
+	// StatusCode is the HTTP status code.
+	StatusCode int
+
+	// Latency is the time from sending the request to receiving headers.
+	Latency time.Duration
+
+	// RequestID is the request ID reported by the server, or else the ID
+	// the request was sent with.
+	RequestID string
+}
+
+// RetryEvent describes a retry that is about to happen.
+type RetryEvent struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path of the request.
+	Path string
+
+	// Attempt is the number of the upcoming attempt, starting at 2.
+	Attempt int
+
+	// Backoff is how long the SDK waits before the upcoming attempt.
+	Backoff time.Duration
+
+	// StatusCode is the status code that triggered the retry, or 0
+	// if the previous attempt failed without a response.
+	StatusCode int
+
+	// Err is the error that triggered the retry, if any.
+	Err error
+}
+
+// StreamEvent describes a server-sent event read from a stream.
+type StreamEvent struct {
+	// Path is the URL path of the streaming request.
+	Path string
+
+	// Index is the zero-based position of the event in the stream.
+	Index int
+
+	// Type is the SSE event type, if any.
+	Type string
+
+	// ID is the SSE event ID, if any.
+	ID string
+
+	// Data is the event payload with credentials masked.
+	// Only populated when body logging is enabled.
+	Data []byte
+}
+
+// ErrorEvent describes a failed request.
+type ErrorEvent struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path of the request.
+	Path string
+
+	// Attempt is the attempt number, starting at 1.
+	Attempt int
+
+	// StatusCode is the HTTP status code, or 0 if no response was received.
+	StatusCode int
+
+	// RequestID is the request ID reported by the server, or else the ID
+	// the request was sent with.
+	RequestID string
+
+	// Err is the error.
+	Err error
+}
+
+// WarningEvent describes a condition the SDK worked around, such as an
+// upload that cannot be retried.
+type WarningEvent struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path of the request.
+	Path string
+
+	// Message describes the condition.
+	Message string
+}
+
+// WarningHooks is implemented by hooks that also receive warnings. It is
+// separate from Hooks so that existing implementations keep compiling.
+type WarningHooks interface {
+	// OnWarning is called when the SDK works around a condition the
+	// caller may want to fix.
+	OnWarning(ctx context.Context, event *WarningEvent)
+}
+
+// Warn passes event to h if it implements WarningHooks.
+func Warn(ctx context.Context, h Hooks, event *WarningEvent) {
+	if w, ok := h.(WarningHooks); ok {
+		w.OnWarning(ctx, event)
+	}
+}
+
+// NoopHooks is a Hooks implementation that does nothing.
+// Embed it to implement only the callbacks you need.
+type NoopHooks struct{}
+
+// OnRequest implements Hooks.
+func (NoopHooks) OnRequest(context.Context, *RequestEvent) {}
+
+// OnResponse implements Hooks.
+func (NoopHooks) OnResponse(context.Context, *ResponseEvent) {}
+
+// OnRetry implements Hooks.
+func (NoopHooks) OnRetry(context.Context, *RetryEvent) {}
+
+// OnStreamEvent implements Hooks.
+func (NoopHooks) OnStreamEvent(context.Context, *StreamEvent) {}
+
+// OnError implements Hooks.
+func (NoopHooks) OnError(context.Context, *ErrorEvent) {}
+
+// OnWarning implements WarningHooks.
+func (NoopHooks) OnWarning(context.Context, *WarningEvent) {}
+
+// Multi fans events out to several hooks in order.
+type Multi []Hooks
+
+// OnRequest implements Hooks.
+func (m Multi) OnRequest(ctx context.Context, event *RequestEvent) {
+	for _, h := range m {
+		h.OnRequest(ctx, event)
+	}
+}
+
+// OnResponse implements Hooks.
+func (m Multi) OnResponse(ctx context.Context, event *ResponseEvent) {
+	for _, h := range m {
+		h.OnResponse(ctx, event)
+	}
+}
+
+// OnRetry implements Hooks.
+func (m Multi) OnRetry(ctx context.Context, event *RetryEvent) {
+	for _, h := range m {
+		h.OnRetry(ctx, event)
+	}
+}
+
+// OnStreamEvent implements Hooks.
+func (m Multi) OnStreamEvent(ctx context.Context, event *StreamEvent) {
+	for _, h := range m {
+		h.OnStreamEvent(ctx, event)
+	}
+}
+
+// OnError implements Hooks.
+func (m Multi) OnError(ctx context.Context, event *ErrorEvent) {
+	for _, h := range m {
+		h.OnError(ctx, event)
+	}
+}
+
+// OnWarning implements WarningHooks, passing event to the hooks that
+// implement it.
+func (m Multi) OnWarning(ctx context.Context, event *WarningEvent) {
+	for _, h := range m {
+		Warn(ctx, h, event)
+	}
+}
+
+// attemptContextKey is the context key for the current attempt number.
+type attemptContextKey struct{}
+
+// WithAttempt returns a copy of ctx carrying the attempt number.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, attempt)
+}
+
+// AttemptFromContext returns the attempt number carried by ctx, or 1.
+func AttemptFromContext(ctx context.Context) int {
+	if ctx == nil {
+		return 1
+	}
+	if attempt, ok := ctx.Value(attemptContextKey{}).(int); ok && attempt > 0 {
+		return attempt
+	}
+	return 1
+}
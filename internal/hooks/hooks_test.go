@@ -0,0 +1,80 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingHooks struct {
+	NoopHooks
+	name  string
+	calls *[]string
+}
+
+func (h countingHooks) OnRequest(context.Context, *RequestEvent) {
+	*h.calls = append(*h.calls, h.name+":request")
+}
+
+func (h countingHooks) OnError(context.Context, *ErrorEvent) {
+	*h.calls = append(*h.calls, h.name+":error")
+}
+
+func TestMulti(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	m := Multi{
+		countingHooks{name: "a", calls: &calls},
+		countingHooks{name: "b", calls: &calls},
+	}
+
+	ctx := context.Background()
+	m.OnRequest(ctx, &RequestEvent{})
+	m.OnResponse(ctx, &ResponseEvent{})
+	m.OnRetry(ctx, &RetryEvent{})
+	m.OnStreamEvent(ctx, &StreamEvent{})
+	m.OnError(ctx, &ErrorEvent{})
+
+	assert.Equal(t, []string{"a:request", "b:request", "a:error", "b:error"}, calls)
+}
+
+// warningCounter implements WarningHooks.
+type warningCounter struct {
+	countingHooks
+}
+
+func (h warningCounter) OnWarning(_ context.Context, e *WarningEvent) {
+	*h.calls = append(*h.calls, h.name+":warning:"+e.Message)
+}
+
+// plainHooks implements only Hooks.
+type plainHooks struct {
+	Hooks
+}
+
+func TestMulti_OnWarning(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	m := Multi{
+		warningCounter{countingHooks{name: "a", calls: &calls}},
+		plainHooks{NoopHooks{}},
+		warningCounter{countingHooks{name: "b", calls: &calls}},
+	}
+
+	Warn(context.Background(), m, &WarningEvent{Message: "slow"})
+	assert.Equal(t, []string{"a:warning:slow", "b:warning:slow"}, calls)
+
+	// Hooks without OnWarning are skipped
+	Warn(context.Background(), plainHooks{NoopHooks{}}, &WarningEvent{})
+}
+
+func TestAttemptContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 1, AttemptFromContext(context.Background()))
+	assert.Equal(t, 3, AttemptFromContext(WithAttempt(context.Background(), 3)))
+	assert.Equal(t, 1, AttemptFromContext(WithAttempt(context.Background(), 0)))
+}
@@ -0,0 +1,111 @@
+package hooks
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+)
+
+// loggingHooks writes lifecycle events to a structured logger.
+type loggingHooks struct {
+	logger *logger.Logger
+}
+
+// NewLoggingHooks returns hooks that log lifecycle events to l.
+//
+// Requests, responses and stream events are logged at debug level,
+// retries at info level, API error responses and warnings at warn level
+// and transport failures at error level. Bodies are only
+// included when the event carries them, i.e. when body logging is enabled.
+func NewLoggingHooks(l *logger.Logger) Hooks {
+	if l == nil {
+		l = logger.Default()
+	}
+	return &loggingHooks{logger: l}
+}
+
+// OnRequest implements Hooks.
+func (h *loggingHooks) OnRequest(ctx context.Context, event *RequestEvent) {
+	attrs := []any{
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+		slog.Int("attempt", event.Attempt),
+		slog.String("request_id", event.RequestID),
+	}
+	if event.Body != nil {
+		attrs = append(attrs, slog.String("body", string(event.Body)))
+	}
+	h.logger.DebugContext(ctx, "HTTP request", attrs...)
+}
+
+// OnResponse implements Hooks.
+func (h *loggingHooks) OnResponse(ctx context.Context, event *ResponseEvent) {
+	h.logger.DebugContext(ctx, "HTTP response",
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+		slog.Int("attempt", event.Attempt),
+		slog.Int("status_code", event.StatusCode),
+		slog.Duration("latency", event.Latency),
+		slog.String("request_id", event.RequestID),
+	)
+}
+
+// OnRetry implements Hooks.
+func (h *loggingHooks) OnRetry(ctx context.Context, event *RetryEvent) {
+	attrs := []any{
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+		slog.Int("attempt", event.Attempt),
+		slog.Duration("backoff", event.Backoff),
+	}
+	if event.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status_code", event.StatusCode))
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	h.logger.InfoContext(ctx, "Retrying HTTP request", attrs...)
+}
+
+// OnStreamEvent implements Hooks.
+func (h *loggingHooks) OnStreamEvent(ctx context.Context, event *StreamEvent) {
+	attrs := []any{
+		slog.String("path", event.Path),
+		slog.Int("index", event.Index),
+	}
+	if event.Type != "" {
+		attrs = append(attrs, slog.String("event", event.Type))
+	}
+	if event.Data != nil {
+		attrs = append(attrs, slog.String("data", string(event.Data)))
+	}
+	h.logger.DebugContext(ctx, "Stream event", attrs...)
+}
+
+// OnError implements Hooks.
+func (h *loggingHooks) OnError(ctx context.Context, event *ErrorEvent) {
+	attrs := []any{
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+		slog.Int("attempt", event.Attempt),
+		slog.String("request_id", event.RequestID),
+	}
+	if event.Err != nil {
+		attrs = append(attrs, slog.String("error", event.Err.Error()))
+	}
+	if event.StatusCode != 0 {
+		attrs = append(attrs, slog.Int("status_code", event.StatusCode))
+		h.logger.WarnContext(ctx, "API error response", attrs...)
+		return
+	}
+	h.logger.ErrorContext(ctx, "HTTP request failed", attrs...)
+}
+
+// OnWarning implements WarningHooks.
+func (h *loggingHooks) OnWarning(ctx context.Context, event *WarningEvent) {
+	h.logger.WarnContext(ctx, event.Message,
+		slog.String("method", event.Method),
+		slog.String("path", event.Path),
+	)
+}
@@ -0,0 +1,51 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+)
+
+func TestLoggingHooks(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := &logger.Logger{Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	h := NewLoggingHooks(l)
+	ctx := context.Background()
+
+	h.OnRequest(ctx, &RequestEvent{Method: "POST", Path: "/chat/completions", Attempt: 1, Body: []byte(`{"model":"glm"}`)})
+	h.OnResponse(ctx, &ResponseEvent{Method: "POST", Path: "/chat/completions", Attempt: 1, StatusCode: 503, Latency: time.Millisecond})
+	h.OnRetry(ctx, &RetryEvent{Method: "POST", Path: "/chat/completions", Attempt: 2, Backoff: time.Second, StatusCode: 503})
+	h.OnStreamEvent(ctx, &StreamEvent{Path: "/chat/completions", Index: 0})
+	h.OnError(ctx, &ErrorEvent{Method: "POST", Path: "/chat/completions", Attempt: 2, Err: errors.New("boom")})
+	h.OnError(ctx, &ErrorEvent{Method: "POST", Path: "/chat/completions", Attempt: 1, StatusCode: 400, Err: errors.New("bad")})
+
+	out := buf.String()
+	assert.Contains(t, out, `level=DEBUG msg="HTTP request"`)
+	assert.Contains(t, out, `body="{\"model\":\"glm\"}"`)
+	assert.Contains(t, out, `msg="HTTP response"`)
+	assert.Contains(t, out, "status_code=503")
+	assert.Contains(t, out, `level=INFO msg="Retrying HTTP request"`)
+	assert.Contains(t, out, "backoff=1s")
+	assert.Contains(t, out, `msg="Stream event"`)
+	assert.NotContains(t, out, "data=")
+	assert.Contains(t, out, `level=ERROR msg="HTTP request failed"`)
+	assert.Contains(t, out, "error=boom")
+	assert.Contains(t, out, `level=WARN msg="API error response"`)
+}
+
+func TestNewLoggingHooks_NilLogger(t *testing.T) {
+	t.Parallel()
+
+	assert.NotPanics(t, func() {
+		NewLoggingHooks(nil).OnRequest(context.Background(), &RequestEvent{})
+	})
+}
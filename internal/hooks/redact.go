@@ -0,0 +1,205 @@
+package hooks
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// RedactedValue replaces masked credentials.
+const RedactedValue = "[REDACTED]"
+
+// sensitiveHeaders are always masked in hook events.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"X-Api-Key",
+}
+
+// sensitiveQueryParams are query parameters whose values are always masked
+// in URLs, compared in lower case.
+var sensitiveQueryParams = map[string]bool{
+	"access_token":     true,
+	"api_key":          true,
+	"apikey":           true,
+	"key":              true,
+	"password":         true,
+	"secret":           true,
+	"sig":              true,
+	"signature":        true,
+	"token":            true,
+	"x-amz-credential": true,
+	"x-amz-signature":  true,
+	"x-goog-signature": true,
+}
+
+// bearerPattern matches bearer tokens, such as an Authorization header
+// echoed in an error response.
+var bearerPattern = regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+
+// userinfoPattern matches the user information of a URL that does not
+// parse.
+var userinfoPattern = regexp.MustCompile(`(//)[^/@\s]*@`)
+
+// queryParamPattern matches the values of sensitive query parameters in a
+// URL that does not parse.
+var queryParamPattern = regexp.MustCompile(`(?i)([?&](?:` + strings.Join(slices.Sorted(maps.Keys(sensitiveQueryParams)), "|") + `)=)[^&#\s"]*`)
+
+// Redactor masks credentials in headers and bodies passed to hooks.
+type Redactor struct {
+	// LogBodies enables passing request bodies and stream payloads to hooks.
+	LogBodies bool
+
+	mu      sync.RWMutex
+	secrets [][]byte
+}
+
+// NewRedactor creates a redactor that masks the given secrets wherever
+// they appear in bodies. Empty secrets are ignored.
+func NewRedactor(logBodies bool, secrets ...string) *Redactor {
+	r := &Redactor{LogBodies: logBodies}
+	r.AddSecrets(secrets...)
+	return r
+}
+
+// AddSecrets masks additional secrets, such as a rotated API key.
+// Empty secrets are ignored.
+func (r *Redactor) AddSecrets(secrets ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range secrets {
+		if s != "" {
+			r.secrets = append(r.secrets, []byte(s))
+		}
+	}
+}
+
+// Header returns a copy of h with sensitive headers masked.
+func (r *Redactor) Header(h http.Header) http.Header {
+	out := h.Clone()
+	if out == nil {
+		return http.Header{}
+	}
+	for _, name := range sensitiveHeaders {
+		if out.Get(name) != "" {
+			out.Set(name, RedactedValue)
+		}
+	}
+	return out
+}
+
+// Body returns a masked copy of body, or nil if body logging is disabled.
+func (r *Redactor) Body(body []byte) []byte {
+	if r == nil || !r.LogBodies || body == nil {
+		return nil
+	}
+	out := append([]byte(nil), body...)
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		out = bytes.ReplaceAll(out, secret, []byte(RedactedValue))
+	}
+	return bearerPattern.ReplaceAll(out, []byte("${1}"+RedactedValue))
+}
+
+// String masks secrets and bearer tokens in s.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return bearerPattern.ReplaceAllString(s, "${1}"+RedactedValue)
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, string(secret), RedactedValue)
+	}
+	return bearerPattern.ReplaceAllString(s, "${1}"+RedactedValue)
+}
+
+// URL masks the password, the values of sensitive query parameters and
+// the secrets in rawURL.
+func (r *Redactor) URL(rawURL string) string {
+	return r.String(RedactURL(rawURL))
+}
+
+// Error returns err with the credentials in its message masked. The URLs
+// of *url.Error values in the chain of err are masked in place; if the
+// message still contains a credential, err is wrapped in an error with
+// the masked message that unwraps to err.
+func (r *Redactor) Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	r.maskURLErrors(err)
+
+	msg := err.Error()
+	if masked := r.String(msg); masked != msg {
+		return &redactedError{msg: masked, err: err}
+	}
+	return err
+}
+
+// maskURLErrors masks the URLs of the *url.Error values in the chain of err.
+func (r *Redactor) maskURLErrors(err error) {
+	for err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = r.URL(urlErr.URL)
+		}
+		if multi, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range multi.Unwrap() {
+				r.maskURLErrors(e)
+			}
+			return
+		}
+		err = errors.Unwrap(err)
+	}
+}
+
+// redactedError is an error whose message has its credentials masked.
+type redactedError struct {
+	msg string
+	err error
+}
+
+// Error implements the error interface.
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the underlying error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// RedactURL masks the password and the values of sensitive query
+// parameters, such as api_key or signature, in rawURL. The user
+// information of a URL that does not parse is masked as a whole.
+func RedactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		rawURL = userinfoPattern.ReplaceAllString(rawURL, "${1}"+RedactedValue+"@")
+		return queryParamPattern.ReplaceAllString(rawURL, "${1}"+RedactedValue)
+	}
+
+	if u.RawQuery != "" {
+		params := strings.Split(u.RawQuery, "&")
+		for i, param := range params {
+			name, _, _ := strings.Cut(param, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil {
+				name = unescaped
+			}
+			if sensitiveQueryParams[strings.ToLower(name)] {
+				params[i], _, _ = strings.Cut(param, "=")
+				params[i] += "=" + RedactedValue
+			}
+		}
+		u.RawQuery = strings.Join(params, "&")
+	}
+	return u.Redacted()
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"slices"
+	"strings"
+)
+
 // ErrorResponse represents an error response from the API.
 type ErrorResponse struct {
 	// Error contains the error details.
@@ -10,6 +15,24 @@ type ErrorResponse struct {
 
 	// Code is a direct error code (some APIs return this at top level).
 	Code string `json:"code,omitempty"`
+
+	// ContentFilter lists the content safety results of a request rejected
+	// by the provider's content safety system.
+	ContentFilter []ContentFilterResult `json:"contentFilter,omitempty"`
+}
+
+// ContentFilterResult is the content safety result of one part of a
+// rejected request.
+type ContentFilterResult struct {
+	// Role is the flagged part: "user" or "history" for the prompt,
+	// "assistant" for the generated output.
+	Role string `json:"role,omitempty"`
+
+	// Level is the severity, from 0 (most severe) to 3.
+	Level int `json:"level"`
+
+	// Categories are the violated categories.
+	Categories []string `json:"categories,omitempty"`
 }
 
 // ErrorDetail contains detailed information about an error.
@@ -25,6 +48,15 @@ type ErrorDetail struct {
 
 	// Param is the parameter that caused the error.
 	Param string `json:"param,omitempty"`
+
+	// Categories are the violated categories of a content policy error.
+	Categories []string `json:"categories,omitempty"`
+}
+
+// HasError reports whether the response is an error envelope, that is
+// whether it has an error object or a top-level message or code.
+func (e *ErrorResponse) HasError() bool {
+	return e.Error != nil || e.Message != "" || e.Code != ""
 }
 
 // GetMessage returns the error message, checking both top-level and nested error.
@@ -49,6 +81,57 @@ func (e *ErrorResponse) GetCode() string {
 	return ""
 }
 
+// contentPolicyCodes are the error codes and types of content policy
+// rejections: the platforms' sensitive content code and the types used by
+// OpenAI-compatible gateways.
+var contentPolicyCodes = map[string]bool{
+	"1301":                     true,
+	"content_filter":           true,
+	"content_policy_violation": true,
+}
+
+// ContentPolicy reports whether the response rejects a request for
+// violating the content policy, and returns the flagged field ("prompt",
+// "image" or "output", or empty if not reported) and the violated
+// categories, sorted.
+func (e *ErrorResponse) ContentPolicy() (field string, categories []string, ok bool) {
+	ok = contentPolicyCodes[e.GetCode()] || len(e.ContentFilter) > 0
+	if e.Error != nil {
+		ok = ok || contentPolicyCodes[e.Error.Type]
+	}
+	if !ok {
+		return "", nil, false
+	}
+
+	if e.Error != nil {
+		field = contentPolicyField(e.Error.Param)
+		categories = append(categories, e.Error.Categories...)
+	}
+	for _, result := range e.ContentFilter {
+		if field == "" {
+			field = contentPolicyField(result.Role)
+		}
+		categories = append(categories, result.Categories...)
+	}
+	slices.Sort(categories)
+	return field, slices.Compact(categories), true
+}
+
+// contentPolicyField maps a parameter name or content filter role to the
+// flagged field.
+func contentPolicyField(name string) string {
+	switch strings.ToLower(name) {
+	case "prompt", "messages", "input", "user", "history":
+		return "prompt"
+	case "image", "image_url", "image_urls":
+		return "image"
+	case "assistant", "output":
+		return "output"
+	default:
+		return ""
+	}
+}
+
 // BatchError represents an error in batch processing.
 type BatchError struct {
 	// Code is the error code.
@@ -8,6 +8,15 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestErrorResponse_HasError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, (&ErrorResponse{Error: &ErrorDetail{Code: "1210"}}).HasError())
+	assert.True(t, (&ErrorResponse{Message: "bad"}).HasError())
+	assert.True(t, (&ErrorResponse{Code: "1210"}).HasError())
+	assert.False(t, (&ErrorResponse{}).HasError())
+}
+
 func TestErrorResponse_GetMessage(t *testing.T) {
 	t.Parallel()
 
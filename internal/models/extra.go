@@ -0,0 +1,261 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// MarshalWithExtra encodes v, which must encode as a JSON object, and adds
+// the entries of extra to the top-level object. Fields present in the
+// encoding of v take precedence over extra entries with the same key.
+func MarshalWithExtra(v any, extra map[string]any) ([]byte, error) {
+	if len(extra) == 0 {
+		return json.Marshal(v)
+	}
+
+	raw := make(map[string]json.RawMessage, len(extra))
+	for k, value := range extra {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		raw[k] = encoded
+	}
+	return merge(v, raw)
+}
+
+// MarshalWithRawExtra is like MarshalWithExtra for extra fields held as a
+// JSON object, such as those returned by UnmarshalWithExtra.
+func MarshalWithRawExtra(v any, extra json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return json.Marshal(v)
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(extra, &raw); err != nil {
+		return nil, err
+	}
+	return merge(v, raw)
+}
+
+// merge encodes v and adds the entries of extra whose keys v does not set.
+func merge(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var merged map[string]json.RawMessage
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+	for k, value := range extra {
+		if _, ok := merged[k]; !ok {
+			merged[k] = value
+		}
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalWithExtra decodes the JSON object in data into v, a pointer to
+// a struct, and returns the members that have no field in v as a JSON
+// object, or nil if there are none. Like encoding/json, field names are
+// matched case-insensitively. The members are found by scanning data once
+// after it is decoded, without decoding their values again.
+func UnmarshalWithExtra(data []byte, v any) (json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	known := jsonFieldNames(reflect.TypeOf(v).Elem())
+	var members [][2][]byte
+	eachMember(data, func(key, value []byte) {
+		if !isKnownField(known, key) {
+			members = append(members, [2][]byte{key, value})
+		}
+	})
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	// Sort the members by key, keeping the last of duplicate keys, as
+	// encoding a map does
+	slices.SortStableFunc(members, func(a, b [2][]byte) int {
+		return bytes.Compare(a[0], b[0])
+	})
+	var extra bytes.Buffer
+	extra.WriteByte('{')
+	for i, m := range members {
+		if i+1 < len(members) && bytes.Equal(m[0], members[i+1][0]) {
+			continue
+		}
+		if extra.Len() > 1 {
+			extra.WriteByte(',')
+		}
+		extra.Write(m[0])
+		extra.WriteByte(':')
+		if err := json.Compact(&extra, m[1]); err != nil {
+			return nil, err
+		}
+	}
+	extra.WriteByte('}')
+	return extra.Bytes(), nil
+}
+
+// isKnownField reports whether the quoted JSON key names a field in known.
+func isKnownField(known map[string]bool, key []byte) bool {
+	name := key[1 : len(key)-1]
+	if bytes.IndexByte(name, '\\') >= 0 {
+		var unquoted string
+		if err := json.Unmarshal(key, &unquoted); err != nil {
+			return false
+		}
+		return known[strings.ToLower(unquoted)]
+	}
+
+	var buf [64]byte
+	lower := buf[:0]
+	for _, c := range name {
+		if c >= utf8.RuneSelf {
+			return known[strings.ToLower(string(name))]
+		}
+		if 'A' <= c && c <= 'Z' {
+			c += 'a' - 'A'
+		}
+		lower = append(lower, c)
+	}
+	return known[string(lower)]
+}
+
+// eachMember calls fn with the quoted key and the value of each member of
+// the JSON object in data, which must be valid JSON. It does nothing if
+// data is not an object.
+func eachMember(data []byte, fn func(key, value []byte)) {
+	i := skipSpace(data, 0)
+	if i == len(data) || data[i] != '{' {
+		return
+	}
+	i = skipSpace(data, i+1)
+	for i < len(data) && data[i] != '}' {
+		keyEnd := skipString(data, i)
+		key := data[i:keyEnd]
+		start := skipSpace(data, skipSpace(data, keyEnd)+1) // skip the colon
+		end := skipValue(data, start)
+		fn(key, data[start:end])
+		i = skipSpace(data, end)
+		if i < len(data) && data[i] == ',' {
+			i = skipSpace(data, i+1)
+		}
+	}
+}
+
+// skipSpace returns the index of the first byte at or after i that is not
+// JSON whitespace.
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipString returns the index just past the JSON string starting at i.
+func skipString(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return i
+}
+
+// skipValue returns the index just past the JSON value starting at i.
+func skipValue(data []byte, i int) int {
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	}
+	for i < len(data) {
+		switch data[i] {
+		case ',', '}', ']', ' ', '\t', '\r', '\n':
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// fieldNamesCache maps struct types to their lower-cased JSON field names.
+var fieldNamesCache sync.Map
+
+// jsonFieldNames returns the lower-cased JSON names of the fields of the
+// struct type t, including promoted fields of embedded structs.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNamesCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+
+	names := make(map[string]bool)
+	addFieldNames(t, names)
+	fieldNamesCache.Store(t, names)
+	return names
+}
+
+func addFieldNames(t reflect.Type, names map[string]bool) {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFieldNames(ft, names)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type extraBase struct {
+	ID string `json:"id"`
+}
+
+type extraTarget struct {
+	extraBase
+	Model   string `json:"model,omitempty"`
+	Count   int
+	Skipped string `json:"-"`
+}
+
+func TestMarshalWithExtra(t *testing.T) {
+	t.Parallel()
+
+	data, err := MarshalWithExtra(extraTarget{extraBase: extraBase{ID: "a"}, Model: "glm-4.7"}, map[string]any{
+		"model": "ignored",
+		"beta":  map[string]any{"enabled": true},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"a","model":"glm-4.7","Count":0,"beta":{"enabled":true}}`, string(data))
+
+	// Omitted typed fields do not shadow extra entries
+	data, err = MarshalWithExtra(extraTarget{}, map[string]any{"model": "extra"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"","model":"extra","Count":0}`, string(data))
+
+	_, err = MarshalWithExtra(extraTarget{}, map[string]any{"bad": func() {}})
+	assert.Error(t, err)
+}
+
+func TestUnmarshalWithExtra(t *testing.T) {
+	t.Parallel()
+
+	var v extraTarget
+	extra, err := UnmarshalWithExtra([]byte(`{"ID":"a","model":"glm-4.7","count":2,"Skipped":"x","trace":{"id":1},"tag":"<b>"}`), &v)
+	require.NoError(t, err)
+	assert.Equal(t, "a", v.ID)
+	assert.Equal(t, 2, v.Count)
+	assert.JSONEq(t, `{"Skipped":"x","trace":{"id":1},"tag":"<b>"}`, string(extra))
+	assert.Contains(t, string(extra), `"<b>"`)
+
+	extra, err = UnmarshalWithExtra([]byte(`{"id":"a"}`), &v)
+	require.NoError(t, err)
+	assert.Nil(t, extra)
+
+	_, err = UnmarshalWithExtra([]byte(`[1]`), &v)
+	assert.Error(t, err)
+
+	extra, err = UnmarshalWithExtra([]byte(`null`), &v)
+	require.NoError(t, err)
+	assert.Nil(t, extra)
+
+	// Strings holding brackets or escaped quotes, escaped keys and
+	// whitespace between tokens
+	data := " {\n \"m\\u006fdel\" : \"x\" ,\"note\":\"a \\\"}\\\" [\",\n\t\"list\": [ 1, {\"b\":\"]\"} ] , \"Ünï\":true,\"n\":-1.5e3 }"
+	extra, err = UnmarshalWithExtra([]byte(data), &v)
+	require.NoError(t, err)
+	assert.Equal(t, "x", v.Model)
+	assert.Equal(t, `{"list":[1,{"b":"]"}],"n":-1.5e3,"note":"a \"}\" [","Ünï":true}`, string(extra), "members are sorted and compacted")
+
+	extra, err = UnmarshalWithExtra([]byte(`{"b":1,"a":2,"b":3}`), &v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2,"b":3}`, string(extra), "the last duplicate wins")
+}
+
+func TestMarshalWithRawExtra(t *testing.T) {
+	t.Parallel()
+
+	data, err := MarshalWithRawExtra(extraTarget{Model: "glm-4.7"}, json.RawMessage(`{"model":"old","trace":1}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"","model":"glm-4.7","Count":0,"trace":1}`, string(data))
+
+	_, err = MarshalWithRawExtra(extraTarget{}, json.RawMessage(`[`))
+	assert.Error(t, err)
+}
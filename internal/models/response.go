@@ -1,6 +1,7 @@
 package models
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"time"
@@ -32,7 +33,8 @@ type APIResponse struct {
 	// Elapsed is the request duration.
 	Elapsed time.Duration
 
-	// RequestID is extracted from the response headers.
+	// RequestID is extracted from the response headers, or else is the
+	// ID the request was sent with.
 	RequestID string
 
 	// IsClosed indicates if the response body has been closed.
@@ -45,6 +47,10 @@ func NewAPIResponse(resp *http.Response, elapsed time.Duration) *APIResponse {
 	if requestID == "" {
 		requestID = resp.Header.Get("Request-ID")
 	}
+	if requestID == "" && resp.Request != nil {
+		// The ID the request was sent with
+		requestID = resp.Request.Header.Get("X-Request-ID")
+	}
 
 	return &APIResponse{
 		HTTPResponse: resp,
@@ -107,6 +113,25 @@ type StreamResponse struct {
 
 	// Err holds any error that occurred during streaming.
 	Err error
+
+	// OnEvent is called for each event read from the stream (optional).
+	// It receives the event index, type, ID and data.
+	OnEvent func(index int, eventType, id, data string)
+
+	// IdleTimeout is how long the stream may wait for an event.
+	// Zero or negative means no limit.
+	IdleTimeout time.Duration
+
+	// Reconnect reopens the stream after a transport error (optional). It
+	// receives the ID of the last event read and the attempt number.
+	Reconnect func(ctx context.Context, lastEventID string, attempt int, cause error) (io.ReadCloser, error)
+
+	// MaxReconnects is how many times Reconnect may be called.
+	MaxReconnects int
+
+	// ReconnectBackoff is the wait before the first reconnect, doubled
+	// with each attempt.
+	ReconnectBackoff time.Duration
 }
 
 // NewStreamResponse creates a new StreamResponse.
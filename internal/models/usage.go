@@ -68,6 +68,22 @@ func (u *Usage) GetCachedTokens() int {
 	return u.PromptTokensDetails.CachedTokens
 }
 
+// GetUncachedPromptTokens returns the number of prompt tokens not served
+// from the prompt cache.
+func (u *Usage) GetUncachedPromptTokens() int {
+	return max(u.PromptTokens-u.GetCachedTokens(), 0)
+}
+
+// CacheHitRatio returns the fraction of prompt tokens served from the
+// prompt cache, between 0 and 1. It returns 0 for a nil usage or a
+// usage without prompt tokens.
+func (u *Usage) CacheHitRatio() float64 {
+	if u == nil || u.PromptTokens == 0 {
+		return 0
+	}
+	return float64(u.GetCachedTokens()) / float64(u.PromptTokens)
+}
+
 // GetReasoningTokens returns the number of reasoning tokens, or 0 if none.
 func (u *Usage) GetReasoningTokens() int {
 	if u.CompletionTokensDetails == nil {
@@ -75,3 +91,9 @@ func (u *Usage) GetReasoningTokens() int {
 	}
 	return u.CompletionTokensDetails.ReasoningTokens
 }
+
+// GetAnswerTokens returns the number of completion tokens spent on the
+// answer, excluding reasoning tokens.
+func (u *Usage) GetAnswerTokens() int {
+	return max(u.CompletionTokens-u.GetReasoningTokens(), 0)
+}
@@ -208,6 +208,92 @@ func TestUsage_GetReasoningTokens(t *testing.T) {
 	}
 }
 
+func TestUsage_GetAnswerTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		usage    *Usage
+		expected int
+	}{
+		{
+			name:     "no details",
+			usage:    &Usage{CompletionTokens: 80},
+			expected: 80,
+		},
+		{
+			name: "with reasoning tokens",
+			usage: &Usage{
+				CompletionTokens: 200,
+				CompletionTokensDetails: &CompletionTokensDetails{
+					ReasoningTokens: 150,
+				},
+			},
+			expected: 50,
+		},
+		{
+			name: "inconsistent counts",
+			usage: &Usage{
+				CompletionTokens: 10,
+				CompletionTokensDetails: &CompletionTokensDetails{
+					ReasoningTokens: 20,
+				},
+			},
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.expected, tt.usage.GetAnswerTokens())
+		})
+	}
+}
+
+func TestUsage_CacheAccessors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		usage    *Usage
+		uncached int
+		ratio    float64
+	}{
+		{
+			name:     "no details",
+			usage:    &Usage{PromptTokens: 80},
+			uncached: 80,
+		},
+		{
+			name: "with cached tokens",
+			usage: &Usage{
+				PromptTokens:        200,
+				PromptTokensDetails: &PromptTokensDetails{CachedTokens: 150},
+			},
+			uncached: 50,
+			ratio:    0.75,
+		},
+		{
+			name:  "no prompt tokens",
+			usage: &Usage{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.uncached, tt.usage.GetUncachedPromptTokens())
+			assert.InDelta(t, tt.ratio, tt.usage.CacheHitRatio(), 1e-9)
+		})
+	}
+
+	var nilUsage *Usage
+	assert.Zero(t, nilUsage.CacheHitRatio())
+}
+
 func TestUsage_JSON(t *testing.T) {
 	t.Parallel()
 
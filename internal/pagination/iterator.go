@@ -0,0 +1,120 @@
+// Package pagination provides iterators over cursor-paginated list endpoints.
+package pagination
+
+import (
+	"context"
+)
+
+// Page is one page of a cursor-paginated list.
+type Page[T any] struct {
+	// Items are the items on the page.
+	Items []T
+
+	// HasMore indicates whether more pages follow.
+	HasMore bool
+
+	// Cursor is passed to the next fetch to retrieve the following page.
+	Cursor string
+}
+
+// FetchFunc fetches the page that starts after cursor.
+// The first page is fetched with the initial cursor given to NewIterator.
+type FetchFunc[T any] func(ctx context.Context, cursor string) (*Page[T], error)
+
+// Iterator walks the items of a paginated list, fetching pages lazily.
+// No request is made until Next is first called.
+//
+// Example:
+//
+//	iter := client.Batch.ListAutoPaging(ctx, 20)
+//	for iter.Next() {
+//	    fmt.Println(iter.Current().ID)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch FetchFunc[T]
+
+	items   []T
+	index   int
+	current *T
+
+	cursor  string
+	hasMore bool
+	err     error
+}
+
+// NewIterator creates an iterator that fetches pages starting at cursor.
+func NewIterator[T any](ctx context.Context, cursor string, fetch FetchFunc[T]) *Iterator[T] {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &Iterator[T]{
+		ctx:     ctx,
+		fetch:   fetch,
+		cursor:  cursor,
+		hasMore: true,
+	}
+}
+
+// Next advances to the next item, fetching the next page when needed.
+// Returns false when there are no more items, the context is cancelled,
+// or a page fails to load.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for {
+		if err := it.ctx.Err(); err != nil {
+			it.err = err
+			it.current = nil
+			return false
+		}
+
+		if it.index < len(it.items) {
+			it.current = &it.items[it.index]
+			it.index++
+			return true
+		}
+
+		it.current = nil
+		if !it.hasMore {
+			return false
+		}
+
+		page, err := it.fetch(it.ctx, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		it.items = page.Items
+		it.index = 0
+
+		// Stop rather than loop forever if the server claims more pages
+		// without advancing the cursor
+		it.hasMore = page.HasMore && page.Cursor != "" && page.Cursor != it.cursor
+		it.cursor = page.Cursor
+	}
+}
+
+// Current returns the current item.
+// Should be called after Next() returns true.
+func (it *Iterator[T]) Current() *T {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Cursor returns the cursor of the next page to fetch. It can be used to
+// resume the iteration later.
+func (it *Iterator[T]) Cursor() string {
+	return it.cursor
+}
@@ -0,0 +1,133 @@
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedFetcher serves pages keyed by cursor and records the cursors requested.
+type pagedFetcher struct {
+	pages   map[string]*Page[int]
+	errAt   string
+	cursors []string
+}
+
+func (f *pagedFetcher) fetch(ctx context.Context, cursor string) (*Page[int], error) {
+	f.cursors = append(f.cursors, cursor)
+	if cursor == f.errAt && f.errAt != "" {
+		return nil, errors.New("page failed")
+	}
+	return f.pages[cursor], nil
+}
+
+func threePages() *pagedFetcher {
+	return &pagedFetcher{pages: map[string]*Page[int]{
+		"":   {Items: []int{1, 2}, HasMore: true, Cursor: "c1"},
+		"c1": {Items: []int{3, 4}, HasMore: true, Cursor: "c2"},
+		"c2": {Items: []int{5}, HasMore: false, Cursor: "c3"},
+	}}
+}
+
+func collect(it *Iterator[int]) []int {
+	var items []int
+	for it.Next() {
+		items = append(items, *it.Current())
+	}
+	return items
+}
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("walks all pages", func(t *testing.T) {
+		t.Parallel()
+
+		f := threePages()
+		it := NewIterator(context.Background(), "", f.fetch)
+
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, collect(it))
+		assert.NoError(t, it.Err())
+		assert.Equal(t, []string{"", "c1", "c2"}, f.cursors)
+		assert.Nil(t, it.Current())
+		assert.False(t, it.Next())
+		assert.Len(t, f.cursors, 3)
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		t.Parallel()
+
+		f := threePages()
+		_ = NewIterator(context.Background(), "", f.fetch)
+
+		assert.Empty(t, f.cursors)
+	})
+
+	t.Run("starts at cursor", func(t *testing.T) {
+		t.Parallel()
+
+		f := threePages()
+		it := NewIterator(context.Background(), "c1", f.fetch)
+
+		assert.Equal(t, []int{3, 4, 5}, collect(it))
+		assert.Equal(t, []string{"c1", "c2"}, f.cursors)
+	})
+
+	t.Run("page error", func(t *testing.T) {
+		t.Parallel()
+
+		f := threePages()
+		f.errAt = "c1"
+		it := NewIterator(context.Background(), "", f.fetch)
+
+		assert.Equal(t, []int{1, 2}, collect(it))
+		require.Error(t, it.Err())
+		assert.Equal(t, "page failed", it.Err().Error())
+		assert.False(t, it.Next())
+		assert.Len(t, f.cursors, 2)
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		f := threePages()
+		ctx, cancel := context.WithCancel(context.Background())
+		it := NewIterator(ctx, "", f.fetch)
+
+		require.True(t, it.Next())
+		cancel()
+
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), context.Canceled)
+		assert.Len(t, f.cursors, 1)
+	})
+
+	t.Run("skips empty pages", func(t *testing.T) {
+		t.Parallel()
+
+		f := &pagedFetcher{pages: map[string]*Page[int]{
+			"":   {HasMore: true, Cursor: "c1"},
+			"c1": {Items: []int{1}},
+		}}
+		it := NewIterator(context.Background(), "", f.fetch)
+
+		assert.Equal(t, []int{1}, collect(it))
+	})
+
+	t.Run("stops when cursor does not advance", func(t *testing.T) {
+		t.Parallel()
+
+		f := &pagedFetcher{pages: map[string]*Page[int]{
+			"":   {Items: []int{1}, HasMore: true, Cursor: "c1"},
+			"c1": {Items: []int{2}, HasMore: true, Cursor: "c1"},
+		}}
+		it := NewIterator(context.Background(), "", f.fetch)
+
+		assert.Equal(t, []int{1, 2}, collect(it))
+		assert.NoError(t, it.Err())
+		assert.Len(t, f.cursors, 2)
+	})
+}
@@ -0,0 +1,182 @@
+// Package poll waits for asynchronous tasks by polling their status with
+// exponential backoff, full jitter and a deadline-aware final poll.
+package poll
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// ErrTimeout is returned by Poll when the task is not done after the
+// timeout. Callers replace it with an error describing the task.
+var ErrTimeout = errors.New("poll: timeout")
+
+const (
+	// DefaultInitialInterval is the first wait between polls when the
+	// config has none, unless the maximum interval is shorter.
+	DefaultInitialInterval = time.Second
+
+	// DefaultMultiplier is the growth factor of the wait between polls.
+	DefaultMultiplier = 2.0
+
+	// MaxFinalPollLead is the longest time before the deadline the final
+	// poll is scheduled at by default.
+	MaxFinalPollLead = time.Second
+
+	// finalPollLeadDivisor sets the default final poll lead to a share of
+	// the timeout, so that short timeouts keep most of their time.
+	finalPollLeadDivisor = 20
+)
+
+// Config is the schedule of a poller.
+type Config struct {
+	// InitialInterval is the wait after the first poll. Zero uses
+	// DefaultInitialInterval, capped at MaxInterval.
+	InitialInterval time.Duration
+
+	// MaxInterval is the longest wait between polls.
+	MaxInterval time.Duration
+
+	// Multiplier is the growth factor of the wait after each poll. Values
+	// below 1 use DefaultMultiplier.
+	Multiplier float64
+
+	// DisableJitter waits exactly the backoff interval. By default each
+	// wait is drawn uniformly between zero and the interval (full jitter),
+	// which spreads the polls of many pending tasks.
+	DisableJitter bool
+
+	// Timeout is the time allowed for the task; zero means no timeout.
+	Timeout time.Duration
+
+	// FinalPollLead is how long before the deadline the final poll is
+	// scheduled when the next wait would overshoot it. Zero uses a
+	// twentieth of the timeout, at most MaxFinalPollLead.
+	FinalPollLead time.Duration
+}
+
+// withDefaults returns the config with zero values replaced by defaults.
+func (c Config) withDefaults() Config {
+	if c.MaxInterval <= 0 {
+		c.MaxInterval = DefaultInitialInterval
+	}
+	if c.InitialInterval <= 0 {
+		c.InitialInterval = DefaultInitialInterval
+	}
+	c.InitialInterval = min(c.InitialInterval, c.MaxInterval)
+	if c.Multiplier < 1 {
+		c.Multiplier = DefaultMultiplier
+	}
+	if c.FinalPollLead <= 0 {
+		c.FinalPollLead = min(MaxFinalPollLead, c.Timeout/finalPollLeadDivisor)
+	}
+	return c
+}
+
+// Poller polls a task on a schedule.
+type Poller struct {
+	config Config
+
+	done  <-chan struct{}
+	cause func() error
+
+	now    func() time.Time
+	wait   func(ctx context.Context, d time.Duration) error
+	random func() float64
+}
+
+// New creates a poller with the schedule of cfg.
+func New(cfg Config) *Poller {
+	p := &Poller{
+		config: cfg.withDefaults(),
+		now:    time.Now,
+		random: rand.Float64,
+	}
+	p.wait = p.sleep
+	return p
+}
+
+// StopOn stops the polling when done is closed, returning the error of
+// cause, such as when the client that polls is closed.
+func (p *Poller) StopOn(done <-chan struct{}, cause func() error) *Poller {
+	p.done = done
+	p.cause = cause
+	return p
+}
+
+// Config returns the schedule of the poller, with defaults applied.
+func (p *Poller) Config() Config {
+	return p.config
+}
+
+// Poll calls check until it reports the task done or fails, waiting
+// between calls with exponential backoff. If the next wait would end after
+// the deadline, the final poll is scheduled FinalPollLead before it
+// instead; if the task is still not done then, Poll returns ErrTimeout.
+func (p *Poller) Poll(ctx context.Context, check func(ctx context.Context) (bool, error)) error {
+	var deadline time.Time
+	if p.config.Timeout > 0 {
+		deadline = p.now().Add(p.config.Timeout)
+	}
+
+	interval := p.config.InitialInterval
+	for final := false; ; {
+		done, err := check(ctx)
+		if err != nil || done {
+			return err
+		}
+		if final {
+			return ErrTimeout
+		}
+
+		delay := p.jitter(interval)
+		interval = min(time.Duration(float64(interval)*p.config.Multiplier), p.config.MaxInterval)
+
+		if !deadline.IsZero() {
+			remaining := deadline.Sub(p.now())
+			if remaining <= 0 {
+				return ErrTimeout
+			}
+			// Land the final poll just before the deadline rather than
+			// sleeping past it
+			if last := remaining - p.config.FinalPollLead; delay >= last {
+				delay = max(last, 0)
+				final = true
+			}
+		}
+
+		if err := p.wait(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// jitter returns the wait for interval: a uniform draw between zero and
+// interval, or interval itself when jitter is disabled.
+func (p *Poller) jitter(interval time.Duration) time.Duration {
+	if p.config.DisableJitter {
+		return interval
+	}
+	return time.Duration(p.random() * float64(interval))
+}
+
+// sleep waits for d, returning early if ctx is done or the poller is
+// stopped.
+func (p *Poller) sleep(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return p.cause()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,184 @@
+package poll
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a clock whose time advances only when the poller waits.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+	polls []time.Duration
+	start time.Time
+}
+
+func newFakeClock() *fakeClock {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeClock{now: start, start: start}
+}
+
+// install makes p use the fake clock and draw random as its jitter.
+func (c *fakeClock) install(p *Poller, random float64) *Poller {
+	p.now = func() time.Time { return c.now }
+	p.wait = func(ctx context.Context, d time.Duration) error {
+		c.waits = append(c.waits, d)
+		c.now = c.now.Add(d)
+		return ctx.Err()
+	}
+	p.random = func() float64 { return random }
+	return p
+}
+
+// check returns a check that records the poll times and reports the task
+// done on poll n, or never if n is 0.
+func (c *fakeClock) check(n int) func(context.Context) (bool, error) {
+	return func(context.Context) (bool, error) {
+		c.polls = append(c.polls, c.now.Sub(c.start))
+		return n > 0 && len(c.polls) == n, nil
+	}
+}
+
+func TestPoll_BackoffSchedule(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{
+		InitialInterval: 250 * time.Millisecond,
+		MaxInterval:     2 * time.Second,
+		DisableJitter:   true,
+	}), 0)
+
+	require.NoError(t, p.Poll(context.Background(), clock.check(7)))
+	assert.Equal(t, []time.Duration{
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		2 * time.Second,
+		2 * time.Second,
+	}, clock.waits)
+	assert.Equal(t, []time.Duration{
+		0,
+		250 * time.Millisecond,
+		750 * time.Millisecond,
+		1750 * time.Millisecond,
+		3750 * time.Millisecond,
+		5750 * time.Millisecond,
+		7750 * time.Millisecond,
+	}, clock.polls)
+}
+
+func TestPoll_FullJitter(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{InitialInterval: time.Second, MaxInterval: 8 * time.Second, Multiplier: 3}), 0.5)
+
+	require.NoError(t, p.Poll(context.Background(), clock.check(4)))
+	// Half of 1s, 3s and 8s (9s capped)
+	assert.Equal(t, []time.Duration{500 * time.Millisecond, 1500 * time.Millisecond, 4 * time.Second}, clock.waits)
+}
+
+func TestPoll_FinalPollBeforeDeadline(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{
+		InitialInterval: time.Second,
+		MaxInterval:     4 * time.Second,
+		DisableJitter:   true,
+		Timeout:         10 * time.Second,
+	}), 0)
+
+	err := p.Poll(context.Background(), clock.check(0))
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	// 1s, 2s and 4s of backoff reach 7s; the next 4s would overshoot the
+	// 10s deadline, so the final poll lands 500ms (a twentieth) before it
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 2500 * time.Millisecond}, clock.waits)
+	assert.Equal(t, 9500*time.Millisecond, clock.polls[len(clock.polls)-1])
+	assert.Len(t, clock.polls, 5)
+}
+
+func TestPoll_FinalPollLead(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{
+		MaxInterval:   time.Minute,
+		DisableJitter: true,
+		Timeout:       time.Hour,
+		FinalPollLead: 5 * time.Second,
+	}), 0)
+
+	assert.ErrorIs(t, p.Poll(context.Background(), clock.check(0)), ErrTimeout)
+	assert.Equal(t, time.Hour-5*time.Second, clock.polls[len(clock.polls)-1])
+
+	// The default lead is capped at MaxFinalPollLead
+	assert.Equal(t, MaxFinalPollLead, New(Config{Timeout: time.Hour}).Config().FinalPollLead)
+}
+
+func TestPoll_DoneOnFinalPoll(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{InitialInterval: 3 * time.Second, MaxInterval: 3 * time.Second, DisableJitter: true, Timeout: 4 * time.Second}), 0)
+
+	// 3s, then the final poll at 3.8s instead of 6s
+	require.NoError(t, p.Poll(context.Background(), clock.check(3)))
+	assert.Equal(t, []time.Duration{0, 3 * time.Second, 3800 * time.Millisecond}, clock.polls)
+}
+
+func TestPoll_Errors(t *testing.T) {
+	t.Parallel()
+
+	clock := newFakeClock()
+	p := clock.install(New(Config{MaxInterval: time.Second}), 0)
+
+	failure := errors.New("boom")
+	calls := 0
+	err := p.Poll(context.Background(), func(context.Context) (bool, error) {
+		calls++
+		if calls == 2 {
+			return false, failure
+		}
+		return false, nil
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 2, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, clock.install(New(Config{}), 0).Poll(ctx, clock.check(0)), context.Canceled)
+}
+
+func TestPoll_StopOn(t *testing.T) {
+	t.Parallel()
+
+	done := make(chan struct{})
+	close(done)
+	closed := errors.New("client closed")
+
+	p := New(Config{InitialInterval: time.Hour, MaxInterval: time.Hour}).StopOn(done, func() error { return closed })
+	err := p.Poll(context.Background(), func(context.Context) (bool, error) { return false, nil })
+	assert.Equal(t, closed, err)
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	t.Parallel()
+
+	cfg := New(Config{MaxInterval: 500 * time.Millisecond}).Config()
+	assert.Equal(t, 500*time.Millisecond, cfg.InitialInterval, "the initial interval is capped at the maximum")
+	assert.Equal(t, DefaultMultiplier, cfg.Multiplier)
+	assert.Zero(t, cfg.FinalPollLead, "no lead without a timeout")
+
+	cfg = New(Config{MaxInterval: 30 * time.Second, Timeout: 10 * time.Second}).Config()
+	assert.Equal(t, DefaultInitialInterval, cfg.InitialInterval)
+	assert.Equal(t, 500*time.Millisecond, cfg.FinalPollLead)
+}
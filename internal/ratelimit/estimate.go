@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"unicode/utf8"
+)
+
+// promptFields are the request fields that contribute to prompt tokens.
+var promptFields = []string{"messages", "input", "prompt", "tools"}
+
+// DefaultEstimator estimates tokens from the request body with EstimateTokens.
+func DefaultEstimator(_ context.Context, _ *http.Request, body []byte) (int, error) {
+	return EstimateTokens(body), nil
+}
+
+// EstimateTokens returns a rough token estimate for a JSON request body:
+// max_tokens plus an estimate of the prompt size. ASCII text is counted at
+// four characters per token and other characters at one token each.
+// Bodies that are not JSON objects count as zero tokens.
+func EstimateTokens(body []byte) int {
+	if len(body) == 0 {
+		return 0
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return 0
+	}
+
+	tokens := 0
+	if raw, ok := fields["max_tokens"]; ok {
+		var maxTokens int
+		if err := json.Unmarshal(raw, &maxTokens); err == nil && maxTokens > 0 {
+			tokens += maxTokens
+		}
+	}
+
+	for _, name := range promptFields {
+		if raw, ok := fields[name]; ok {
+			tokens += estimateText(raw)
+		}
+	}
+
+	return tokens
+}
+
+// estimateText estimates the token count of raw text.
+func estimateText(b []byte) int {
+	ascii, other := 0, 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r < utf8.RuneSelf {
+			ascii++
+		} else {
+			other++
+		}
+		b = b[size:]
+	}
+	return (ascii+3)/4 + other
+}
@@ -0,0 +1,32 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty", ``, 0},
+		{"not JSON", `--boundary`, 0},
+		{"max tokens only", `{"model":"glm-4.7","max_tokens":100}`, 100},
+		{"ascii prompt", `{"prompt":"abcdefgh"}`, 3},                       // 10 chars incl. quotes
+		{"non-ascii prompt", `{"input":"你好"}`, 3},                          // 2 quotes + 2 runes
+		{"messages and max tokens", `{"messages":[],"max_tokens":10}`, 11}, // "[]" is one token
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, EstimateTokens([]byte(tt.body)))
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package ratelimit provides a client-side request and token rate limiter.
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/compress"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+)
+
+// Estimator returns the number of tokens a request is expected to consume.
+// body is the request body, or nil if the request has none.
+type Estimator func(ctx context.Context, req *http.Request, body []byte) (int, error)
+
+// Metrics is a snapshot of limiter activity.
+type Metrics struct {
+	// InFlight is the number of requests admitted and not yet completed.
+	InFlight int64
+
+	// Waiting is the number of requests currently blocked by the limiter.
+	Waiting int64
+
+	// Requests is the total number of requests admitted.
+	Requests int64
+
+	// TotalWait is the total time requests spent waiting for a slot.
+	TotalWait time.Duration
+
+	// MaxWait is the longest time a single request waited for a slot.
+	MaxWait time.Duration
+}
+
+// AverageWait returns the mean time admitted requests waited for a slot.
+func (m Metrics) AverageWait() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.TotalWait / time.Duration(m.Requests)
+}
+
+// Limiter paces requests so that no more than the configured number of
+// requests and tokens are sent per minute. Requests are spread evenly
+// across the minute rather than sent in bursts.
+//
+// A Limiter is safe for concurrent use.
+type Limiter struct {
+	mu              sync.Mutex
+	requestInterval time.Duration
+	tokenInterval   time.Duration
+	tokensPerMinute int
+	nextRequest     time.Time
+	nextToken       time.Time
+	estimator       Estimator
+
+	inFlight  atomic.Int64
+	waiting   atomic.Int64
+	requests  atomic.Int64
+	totalWait atomic.Int64
+	maxWait   atomic.Int64
+}
+
+// New creates a limiter allowing requestsPerMinute requests and
+// tokensPerMinute tokens per minute. A value of zero or less disables
+// the corresponding limit.
+func New(requestsPerMinute, tokensPerMinute int) *Limiter {
+	l := &Limiter{estimator: DefaultEstimator}
+	if requestsPerMinute > 0 {
+		l.requestInterval = time.Minute / time.Duration(requestsPerMinute)
+	}
+	if tokensPerMinute > 0 {
+		l.tokensPerMinute = tokensPerMinute
+		l.tokenInterval = time.Minute / time.Duration(tokensPerMinute)
+	}
+	return l
+}
+
+// SetEstimator replaces the token estimator.
+// If e is nil, DefaultEstimator is used.
+func (l *Limiter) SetEstimator(e Estimator) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if e == nil {
+		e = DefaultEstimator
+	}
+	l.estimator = e
+}
+
+// Acquire blocks until req may be sent or ctx is done. On success it
+// returns a function that must be called once the request completes.
+// Requests whose context was marked with WithoutLimit are admitted
+// immediately.
+func (l *Limiter) Acquire(ctx context.Context, req *http.Request) (release func(), err error) {
+	if Skipped(ctx) {
+		return func() {}, nil
+	}
+
+	tokens := 0
+	if l.tokenInterval > 0 {
+		tokens = l.estimate(ctx, req)
+	}
+
+	if err := l.Wait(ctx, tokens); err != nil {
+		return nil, err
+	}
+
+	l.inFlight.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { l.inFlight.Add(-1) })
+	}, nil
+}
+
+// Wait blocks until a request consuming tokens may be sent or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, tokens int) error {
+	start := time.Now()
+	delay, undo := l.reserve(start, tokens)
+
+	if delay > 0 {
+		l.waiting.Add(1)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+			l.waiting.Add(-1)
+		case <-ctx.Done():
+			timer.Stop()
+			l.waiting.Add(-1)
+			undo()
+			return ctx.Err()
+		}
+	}
+
+	waited := time.Since(start)
+	l.requests.Add(1)
+	l.totalWait.Add(int64(waited))
+	for {
+		current := l.maxWait.Load()
+		if int64(waited) <= current || l.maxWait.CompareAndSwap(current, int64(waited)) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// Metrics returns a snapshot of limiter activity.
+func (l *Limiter) Metrics() Metrics {
+	return Metrics{
+		InFlight:  l.inFlight.Load(),
+		Waiting:   l.waiting.Load(),
+		Requests:  l.requests.Load(),
+		TotalWait: time.Duration(l.totalWait.Load()),
+		MaxWait:   time.Duration(l.maxWait.Load()),
+	}
+}
+
+// reserve books the next available slot and returns how long the caller
+// must wait for it, and a function that gives the slot back if the caller
+// gives up before using it.
+func (l *Limiter) reserve(now time.Time, tokens int) (time.Duration, func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	at := now
+	if l.requestInterval > 0 && l.nextRequest.After(at) {
+		at = l.nextRequest
+	}
+	if l.tokenInterval > 0 && tokens > 0 && l.nextToken.After(at) {
+		at = l.nextToken
+	}
+
+	var requestEnd, tokenEnd time.Time
+	var tokenCost time.Duration
+	if l.requestInterval > 0 {
+		requestEnd = at.Add(l.requestInterval)
+		l.nextRequest = requestEnd
+	}
+	if l.tokenInterval > 0 && tokens > 0 {
+		// A single request may not consume more than a minute's budget
+		if tokens > l.tokensPerMinute {
+			tokens = l.tokensPerMinute
+		}
+		tokenCost = l.tokenInterval * time.Duration(tokens)
+		tokenEnd = at.Add(tokenCost)
+		l.nextToken = tokenEnd
+	}
+
+	undo := func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		// Only the most recent reservation can be returned safely
+		if !requestEnd.IsZero() && l.nextRequest.Equal(requestEnd) {
+			l.nextRequest = requestEnd.Add(-l.requestInterval)
+		}
+		if !tokenEnd.IsZero() && l.nextToken.Equal(tokenEnd) {
+			l.nextToken = tokenEnd.Add(-tokenCost)
+		}
+	}
+
+	return at.Sub(now), undo
+}
+
+// estimate returns the expected token cost of req. Only JSON bodies are
+// read: other bodies, such as multipart uploads, may be large streams that
+// hold no prompt, and the estimator receives a nil body for them.
+func (l *Limiter) estimate(ctx context.Context, req *http.Request) int {
+	l.mu.Lock()
+	estimator := l.estimator
+	l.mu.Unlock()
+
+	var body []byte
+	if strings.HasPrefix(req.Header.Get(constants.HeaderContentType), constants.ContentTypeJSON) {
+		body, _ = compress.ReadBody(req)
+	}
+
+	tokens, err := estimator(ctx, req, body)
+	if err != nil {
+		return EstimateTokens(body)
+	}
+	return tokens
+}
+
+// skipContextKey marks contexts whose requests bypass the limiter.
+type skipContextKey struct{}
+
+// WithoutLimit returns a copy of ctx whose requests bypass the limiter.
+// It is used for internal requests such as token counting.
+func WithoutLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipContextKey{}, true)
+}
+
+// Skipped reports whether ctx was marked with WithoutLimit.
+func Skipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipContextKey{}).(bool)
+	return skip
+}
@@ -0,0 +1,164 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_RequestPacing(t *testing.T) {
+	t.Parallel()
+
+	// 6000 RPM = one request every 10ms
+	l := New(6000, 0)
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, l.Wait(context.Background(), 0))
+		}()
+	}
+	wg.Wait()
+
+	// The first request goes immediately, the rest are spaced 10ms apart
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	m := l.Metrics()
+	assert.Equal(t, int64(20), m.Requests)
+	assert.Equal(t, int64(0), m.Waiting)
+	assert.GreaterOrEqual(t, m.MaxWait, 180*time.Millisecond)
+	assert.Greater(t, m.AverageWait(), time.Duration(0))
+}
+
+func TestLimiter_TokenPacing(t *testing.T) {
+	t.Parallel()
+
+	// 60000 TPM = one token per millisecond
+	l := New(0, 60000)
+
+	start := time.Now()
+	require.NoError(t, l.Wait(context.Background(), 100))
+	require.NoError(t, l.Wait(context.Background(), 100))
+	require.NoError(t, l.Wait(context.Background(), 0))
+
+	// The second request waits for the first one's 100 tokens; the third
+	// costs nothing and is not delayed by the token budget
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 95*time.Millisecond)
+	assert.Less(t, elapsed, 190*time.Millisecond)
+}
+
+func TestLimiter_ContextCancellation(t *testing.T) {
+	t.Parallel()
+
+	// 60 RPM = one request per second
+	l := New(60, 0)
+	require.NoError(t, l.Wait(context.Background(), 0))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := l.Wait(ctx, 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int64(0), l.Metrics().Waiting)
+	assert.Equal(t, int64(1), l.Metrics().Requests)
+
+	// The cancelled reservation is returned, so the next slot is still
+	// one interval after the first request rather than two
+	l.mu.Lock()
+	next := l.nextRequest
+	l.mu.Unlock()
+	assert.Less(t, time.Until(next), 1100*time.Millisecond)
+}
+
+func TestLimiter_Acquire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("tracks in-flight requests", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(6000, 0)
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+
+		release, err := l.Acquire(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), l.Metrics().InFlight)
+
+		release()
+		release()
+		assert.Equal(t, int64(0), l.Metrics().InFlight)
+	})
+
+	t.Run("uses estimator", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(0, 60000)
+		var gotBody string
+		l.SetEstimator(func(_ context.Context, _ *http.Request, body []byte) (int, error) {
+			gotBody = string(body)
+			return 50, nil
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(`{"a":1}`))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		release, err := l.Acquire(context.Background(), req)
+		require.NoError(t, err)
+		release()
+
+		assert.Equal(t, `{"a":1}`, gotBody)
+		l.mu.Lock()
+		next := l.nextToken
+		l.mu.Unlock()
+		assert.Greater(t, time.Until(next), 30*time.Millisecond)
+	})
+
+	t.Run("does not read other bodies", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(0, 60000)
+		var gotBody []byte
+		estimated := false
+		l.SetEstimator(func(_ context.Context, _ *http.Request, body []byte) (int, error) {
+			gotBody, estimated = body, true
+			return 0, nil
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("--boundary\r\n"))
+		req.Header.Set("Content-Type", "multipart/form-data; boundary=boundary")
+		req.GetBody = func() (io.ReadCloser, error) {
+			t.Error("the upload body is read")
+			return nil, nil
+		}
+		release, err := l.Acquire(context.Background(), req)
+		require.NoError(t, err)
+		release()
+
+		assert.True(t, estimated)
+		assert.Nil(t, gotBody)
+	})
+
+	t.Run("skipped context is not limited", func(t *testing.T) {
+		t.Parallel()
+
+		l := New(1, 0)
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		ctx := WithoutLimit(context.Background())
+
+		for i := 0; i < 3; i++ {
+			release, err := l.Acquire(ctx, req)
+			require.NoError(t, err)
+			release()
+		}
+		assert.Equal(t, int64(0), l.Metrics().Requests)
+	})
+}
@@ -0,0 +1,96 @@
+package streaming
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// MaxReconnectBackoff caps the wait between reconnect attempts.
+const MaxReconnectBackoff = 30 * time.Second
+
+// ReconnectFunc reopens a stream that failed with cause. lastEventID is the
+// ID of the last event read, or empty if no event was read yet, and should
+// be sent as the Last-Event-ID header so that the server resumes after it.
+// attempt starts at 1.
+type ReconnectFunc func(ctx context.Context, lastEventID string, attempt int, cause error) (io.ReadCloser, error)
+
+// track records the ID of event and reports whether the event is new. An
+// event without an ID makes the stream unresumable, as replayed copies of
+// it could not be told apart from new events.
+func (s *Stream[T]) track(event *Event) bool {
+	if event.ID == "" {
+		if !event.IsDone() {
+			s.unresumable = true
+		}
+		return true
+	}
+	if _, seen := s.seenIDs[event.ID]; seen {
+		return false
+	}
+	s.seenIDs[event.ID] = struct{}{}
+	s.lastEventID = event.ID
+	s.unresumable = false
+	return true
+}
+
+// canReconnect reports whether the stream may reconnect after the read
+// error err. The end of the stream, Close, the idle timeout and context
+// cancellation end the stream, as do HTTP error responses to a reconnect.
+func (s *Stream[T]) canReconnect(err error) bool {
+	if s.reconnect == nil || s.reconnects >= s.maxReconnects || s.unresumable {
+		return false
+	}
+	if stderrors.Is(err, ErrStreamDone) || stderrors.Is(err, io.EOF) || s.ctx.Err() != nil {
+		return false
+	}
+	var statusErr *errors.APIStatusError
+	if stderrors.As(err, &statusErr) {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !s.closed && s.abortErr == nil
+}
+
+// reopen waits for the backoff and replaces the reader of the stream with
+// a new one from the reconnect function.
+func (s *Stream[T]) reopen(cause error) error {
+	s.reconnects++
+
+	backoff := s.reconnectBackoff << (s.reconnects - 1)
+	if backoff > MaxReconnectBackoff || backoff < 0 {
+		backoff = MaxReconnectBackoff
+	}
+	if backoff > 0 {
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-s.done:
+			return ErrStreamClosed
+		}
+	}
+
+	reader, err := s.reconnect(s.ctx, s.lastEventID, s.reconnects, cause)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		reader.Close()
+		return ErrStreamClosed
+	}
+	s.reader.Close()
+	s.reader = reader
+	s.parser = NewSSEParser(reader)
+	return nil
+}
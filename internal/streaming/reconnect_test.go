@@ -0,0 +1,137 @@
+package streaming
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+var errConnectionReset = stderrors.New("connection reset")
+
+// droppedReader returns data, then fails with errConnectionReset.
+func droppedReader(data string) io.ReadCloser {
+	return nopCloser{io.MultiReader(strings.NewReader(data), iotest.ErrReader(errConnectionReset))}
+}
+
+func readContents(t *testing.T, stream *Stream[testMessage]) []string {
+	t.Helper()
+
+	var contents []string
+	for stream.Next() {
+		contents = append(contents, stream.Current().Content)
+	}
+	return contents
+}
+
+func TestStream_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+	stream := NewStream[testMessage](StreamConfig[testMessage]{
+		Reader: droppedReader("id: 1\ndata: {\"content\":\"a\"}\n\nid: 2\ndata: {\"content\":\"b\"}\n\n"),
+		Reconnect: func(_ context.Context, lastEventID string, attempt int, cause error) (io.ReadCloser, error) {
+			calls = append(calls, lastEventID)
+			assert.ErrorIs(t, cause, errConnectionReset)
+			if attempt == 1 {
+				return droppedReader("id: 2\ndata: {\"content\":\"b\"}\n\nid: 3\ndata: {\"content\":\"c\"}\n\n"), nil
+			}
+			return nopCloser{strings.NewReader("id: 3\ndata: {\"content\":\"c\"}\n\nid: 4\ndata: {\"content\":\"d\"}\n\ndata: [DONE]\n\n")}, nil
+		},
+		MaxReconnects: 3,
+	})
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, readContents(t, stream))
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []string{"2", "3"}, calls)
+}
+
+func TestStream_ReconnectLimits(t *testing.T) {
+	t.Parallel()
+
+	t.Run("max reconnects", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := 0
+		stream := NewStream[testMessage](StreamConfig[testMessage]{
+			Reader: droppedReader("id: 1\ndata: {\"content\":\"a\"}\n\n"),
+			Reconnect: func(context.Context, string, int, error) (io.ReadCloser, error) {
+				attempts++
+				return droppedReader(""), nil
+			},
+			MaxReconnects: 2,
+		})
+
+		assert.Equal(t, []string{"a"}, readContents(t, stream))
+		assert.ErrorIs(t, stream.Err(), errConnectionReset)
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("events without IDs", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := 0
+		stream := NewStream[testMessage](StreamConfig[testMessage]{
+			Reader: droppedReader("data: {\"content\":\"a\"}\n\n"),
+			Reconnect: func(context.Context, string, int, error) (io.ReadCloser, error) {
+				attempts++
+				return nil, stderrors.New("unexpected reconnect")
+			},
+			MaxReconnects: 2,
+		})
+
+		assert.Equal(t, []string{"a"}, readContents(t, stream))
+		assert.ErrorIs(t, stream.Err(), errConnectionReset)
+		assert.Zero(t, attempts)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := 0
+		statusErr := errors.NewAPIStatusError("not found", http.StatusNotFound, nil)
+		stream := NewStream[testMessage](StreamConfig[testMessage]{
+			Reader: droppedReader("id: 1\ndata: {\"content\":\"a\"}\n\n"),
+			Reconnect: func(context.Context, string, int, error) (io.ReadCloser, error) {
+				attempts++
+				return nil, statusErr
+			},
+			MaxReconnects: 3,
+		})
+
+		assert.Equal(t, []string{"a"}, readContents(t, stream))
+		assert.ErrorIs(t, stream.Err(), statusErr)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("canceled during backoff", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		stream := NewStream[testMessage](StreamConfig[testMessage]{
+			Reader:  droppedReader("id: 1\ndata: {\"content\":\"a\"}\n\n"),
+			Context: ctx,
+			Reconnect: func(context.Context, string, int, error) (io.ReadCloser, error) {
+				t.Error("unexpected reconnect")
+				return nil, nil
+			},
+			MaxReconnects:    3,
+			ReconnectBackoff: MaxReconnectBackoff,
+		})
+
+		require.True(t, stream.Next())
+		// Cancel while waiting for the backoff
+		time.AfterFunc(10*time.Millisecond, cancel)
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"strconv"
 	"strings"
 )
 
@@ -45,7 +46,8 @@ type Event struct {
 	// ID is the event ID (from "id:" field).
 	ID string
 
-	// Retry is the retry timeout in milliseconds.
+	// Retry is the reconnection time in milliseconds (from "retry:" field),
+	// or 0 if not set.
 	Retry int
 
 	// Raw holds the complete raw event text.
@@ -63,9 +65,15 @@ func (e *Event) IsDone() bool {
 }
 
 // SSEParser parses Server-Sent Events from a stream.
+//
+// It follows the SSE specification: lines may end in LF, CRLF or CR,
+// comment lines (starting with ":") are ignored, multiple "data:" lines are
+// joined with newlines, and blocks without data (such as keep-alives or a
+// lone "event:" field) are not dispatched.
 type SSEParser struct {
 	scanner *bufio.Scanner
 	reader  io.Reader
+	started bool
 }
 
 // NewSSEParser creates a new SSE parser for the given reader.
@@ -74,6 +82,7 @@ func NewSSEParser(reader io.Reader) *SSEParser {
 	// Increase buffer size for large events
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024) // Max 1MB per line
+	scanner.Split(scanSSELines)
 
 	return &SSEParser{
 		scanner: scanner,
@@ -87,37 +96,48 @@ func NewSSEParser(reader io.Reader) *SSEParser {
 func (p *SSEParser) Next() (*Event, error) {
 	event := &Event{}
 	var rawLines []string
-	var hasData bool
+	var dataLines []string
 
 	for p.scanner.Scan() {
 		line := p.scanner.Text()
-		rawLines = append(rawLines, line)
+		if !p.started {
+			// A leading byte order mark is ignored (per SSE spec)
+			line = strings.TrimPrefix(line, "\uFEFF")
+			p.started = true
+		}
 
 		// Empty line indicates end of event
 		if line == "" {
-			if hasData {
-				event.Raw = strings.Join(rawLines, "\n")
-
-				// Check for done sentinel
-				if event.IsDone() {
-					return event, ErrStreamDone
-				}
-
-				return event, nil
+			if len(dataLines) > 0 {
+				return p.dispatch(event, rawLines, dataLines)
 			}
-			// Reset for next event
+			// Discard blocks without data and reset for next event
+			event = &Event{}
 			rawLines = rawLines[:0]
 			continue
 		}
 
-		// Parse field
-		if err := p.parseField(line, event); err != nil {
-			continue // Skip invalid fields per SSE spec
-		}
+		rawLines = append(rawLines, line)
 
-		// Track if we have any data
-		if strings.HasPrefix(line, EventFieldData) {
-			hasData = true
+		field, value := parseFieldLine(line)
+		switch field {
+		case "":
+			// Comment line
+		case "data":
+			dataLines = append(dataLines, value)
+		case "event":
+			event.Type = value
+		case "id":
+			// IDs containing NULL are ignored (per SSE spec)
+			if !strings.ContainsRune(value, 0) {
+				event.ID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				event.Retry = ms
+			}
+		default:
+			// Unknown fields are ignored per SSE spec
 		}
 	}
 
@@ -126,64 +146,81 @@ func (p *SSEParser) Next() (*Event, error) {
 		return nil, err
 	}
 
-	// If we have accumulated data, return it
-	if hasData {
-		event.Raw = strings.Join(rawLines, "\n")
-		if event.IsDone() {
-			return event, ErrStreamDone
-		}
-		return event, nil
+	// Dispatch a final event that was not followed by a blank line
+	if len(dataLines) > 0 {
+		return p.dispatch(event, rawLines, dataLines)
 	}
 
 	return nil, io.EOF
 }
 
-// parseField parses a single SSE field line.
-func (p *SSEParser) parseField(line string, event *Event) error {
+// dispatch completes an event from its accumulated lines.
+func (p *SSEParser) dispatch(event *Event, rawLines, dataLines []string) (*Event, error) {
+	event.Data = strings.Join(dataLines, "\n")
+	event.Raw = strings.Join(rawLines, "\n")
+
+	// Check for done sentinel
+	if event.IsDone() {
+		return event, ErrStreamDone
+	}
+
+	return event, nil
+}
+
+// parseFieldLine splits an SSE line into field name and value.
+// Comment lines return an empty field name. A line without a colon is a
+// field with an empty value.
+func parseFieldLine(line string) (field, value string) {
 	// Comments start with ":"
 	if strings.HasPrefix(line, ":") {
-		return nil // Ignore comments
+		return "", ""
 	}
 
 	// Split on first colon
 	colonIdx := strings.Index(line, ":")
 	if colonIdx == -1 {
-		// Field with no value
-		return nil
+		return line, ""
 	}
 
-	field := line[:colonIdx]
-	value := line[colonIdx+1:]
+	field = line[:colonIdx]
+	value = line[colonIdx+1:]
 
 	// Remove leading space from value (per SSE spec)
-	if len(value) > 0 && value[0] == ' ' {
-		value = value[1:]
-	}
-
-	switch field {
-	case "data":
-		// Multiple data fields are concatenated with newlines
-		if event.Data != "" {
-			event.Data += "\n"
-		}
-		event.Data += value
+	value = strings.TrimPrefix(value, " ")
 
-	case "event":
-		event.Type = value
+	return field, value
+}
 
-	case "id":
-		event.ID = value
+// scanSSELines is a bufio.SplitFunc that splits on LF, CRLF or a lone CR.
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
 
-	case "retry":
-		// Retry is milliseconds as integer
-		// We don't parse it here, just store as-is
-		// Consumer can parse if needed
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\n' {
+			return i + 1, data[:i], nil
+		}
+		// CR: need the next byte to tell CRLF from a lone CR
+		if i+1 < len(data) {
+			if data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+			return i + 1, data[:i], nil
+		}
+		if atEOF {
+			return i + 1, data[:i], nil
+		}
+		return 0, nil, nil
+	}
 
-	default:
-		// Unknown fields are ignored per SSE spec
+	// Final line without a terminator
+	if atEOF {
+		return len(data), data, nil
 	}
 
-	return nil
+	// Request more data
+	return 0, nil, nil
 }
 
 // SSELineParser is a simpler line-by-line SSE parser.
@@ -261,24 +298,7 @@ func ParseEventLines(lines []string) *Event {
 	var dataLines []string
 
 	for _, line := range lines {
-		// Skip comments
-		if strings.HasPrefix(line, ":") {
-			continue
-		}
-
-		colonIdx := strings.Index(line, ":")
-		if colonIdx == -1 {
-			continue
-		}
-
-		field := line[:colonIdx]
-		value := line[colonIdx+1:]
-
-		// Remove leading space
-		if len(value) > 0 && value[0] == ' ' {
-			value = value[1:]
-		}
-
+		field, value := parseFieldLine(line)
 		switch field {
 		case "data":
 			dataLines = append(dataLines, value)
@@ -286,6 +306,10 @@ func ParseEventLines(lines []string) *Event {
 			event.Type = value
 		case "id":
 			event.ID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				event.Retry = ms
+			}
 		}
 	}
 
@@ -466,3 +466,140 @@ data: [DONE]
 	assert.ErrorIs(t, err, ErrStreamDone)
 	assert.True(t, event.IsDone())
 }
+
+// chunkedReader returns its chunks one Read call at a time, simulating
+// payloads that arrive across several TCP reads.
+type chunkedReader struct {
+	chunks []string
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks[0] = r.chunks[0][n:]
+	if r.chunks[0] == "" {
+		r.chunks = r.chunks[1:]
+	}
+	return n, nil
+}
+
+func TestSSEParser_Next_Robustness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []Event
+		done   bool
+	}{
+		{
+			name:   "keep-alive comments between events",
+			chunks: []string{": keep-alive\n\ndata: a\n\n: keep-alive\n\n:\n\ndata: b\n\n"},
+			want:   []Event{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "CRLF line endings",
+			chunks: []string{"event: message\r\ndata: a\r\n\r\ndata: b\r\n\r\ndata: [DONE]\r\n\r\n"},
+			want:   []Event{{Type: "message", Data: "a"}, {Data: "b"}},
+			done:   true,
+		},
+		{
+			name:   "CR line endings",
+			chunks: []string{"data: a\rdata: b\r\rdata: c\r\r"},
+			want:   []Event{{Data: "a\nb"}, {Data: "c"}},
+		},
+		{
+			name:   "multi-line data joined with newlines",
+			chunks: []string{"data: {\"content\":\ndata: \"hi\"}\n\n"},
+			want:   []Event{{Data: "{\"content\":\n\"hi\"}"}},
+		},
+		{
+			name:   "event id and retry fields",
+			chunks: []string{"retry: 3000\nid: 7\nevent: delta\ndata: x\n\n"},
+			want:   []Event{{Type: "delta", ID: "7", Retry: 3000, Data: "x"}},
+		},
+		{
+			name:   "invalid retry is ignored",
+			chunks: []string{"retry: soon\ndata: x\n\n"},
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "event without data does not leak into the next event",
+			chunks: []string{"event: ping\n\ndata: x\n\n"},
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "data field without colon",
+			chunks: []string{"data\ndata: x\n\n"},
+			want:   []Event{{Data: "\nx"}},
+		},
+		{
+			name:   "unknown fields and stray text are ignored",
+			chunks: []string{"foo: bar\nnonsense\ndata: x\n\n"},
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "leading byte order mark",
+			chunks: []string{"\uFEFFdata: x\n\n"},
+			want:   []Event{{Data: "x"}},
+		},
+		{
+			name:   "final event without trailing blank line",
+			chunks: []string{"data: a\n\ndata: b"},
+			want:   []Event{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "frame split across two reads",
+			chunks: []string{"data: {\"content\":\"hel", "lo\"}\n\ndata: [DONE]\n\n"},
+			want:   []Event{{Data: `{"content":"hello"}`}},
+			done:   true,
+		},
+		{
+			name:   "CRLF split between reads",
+			chunks: []string{"data: a\r", "\n\r", "\ndata: b\r\n\r\n"},
+			want:   []Event{{Data: "a"}, {Data: "b"}},
+		},
+		{
+			name:   "field name split between reads",
+			chunks: []string{"da", "ta: x\n", "\n"},
+			want:   []Event{{Data: "x"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			parser := NewSSEParser(&chunkedReader{chunks: append([]string(nil), tt.chunks...)})
+
+			var got []Event
+			var endErr error
+			for {
+				event, err := parser.Next()
+				if err != nil {
+					endErr = err
+					break
+				}
+				got = append(got, Event{Type: event.Type, ID: event.ID, Retry: event.Retry, Data: event.Data})
+			}
+
+			assert.Equal(t, tt.want, got)
+			if tt.done {
+				assert.ErrorIs(t, endErr, ErrStreamDone)
+			} else {
+				assert.ErrorIs(t, endErr, io.EOF)
+			}
+		})
+	}
+}
+
+func TestParseEventLines_Retry(t *testing.T) {
+	t.Parallel()
+
+	event := ParseEventLines([]string{": comment", "retry: 1500", "data: x"})
+	assert.Equal(t, 1500, event.Retry)
+	assert.Equal(t, "x", event.Data)
+}
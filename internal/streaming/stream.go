@@ -3,24 +3,33 @@ package streaming
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	stderrors "errors"
 	"io"
+	"strings"
 	"sync"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 var (
 	// ErrStreamClosed is returned when attempting to read from a closed stream.
-	ErrStreamClosed = errors.New("stream closed")
+	ErrStreamClosed = stderrors.New("stream closed")
 
 	// ErrStreamNotStarted is returned when attempting operations before streaming starts.
-	ErrStreamNotStarted = errors.New("stream not started")
+	ErrStreamNotStarted = stderrors.New("stream not started")
 )
 
 // Stream represents a generic streaming response reader.
+//
+// Close may be called concurrently with Next and unblocks a pending read.
 type Stream[T any] struct {
 	parser *SSEParser
 	reader io.ReadCloser
 
+	// readMu serializes calls to Next
+	readMu sync.Mutex
+
 	// Current event and error
 	mu      sync.RWMutex
 	current *T
@@ -35,6 +44,24 @@ type Stream[T any] struct {
 
 	// Unmarshal function for custom parsing
 	unmarshal func([]byte) (*T, error)
+
+	// Event callback and number of events seen so far
+	onEvent    func(index int, event *Event)
+	eventCount int
+
+	// Idle timeout and the error that aborted the stream, if any
+	idleTimeout time.Duration
+	idleTimer   *time.Timer
+	abortErr    error
+
+	// Reconnection after transport errors, see reconnect.go
+	reconnect        ReconnectFunc
+	maxReconnects    int
+	reconnectBackoff time.Duration
+	reconnects       int
+	lastEventID      string
+	seenIDs          map[string]struct{}
+	unresumable      bool
 }
 
 // StreamConfig holds configuration for creating a stream.
@@ -48,6 +75,25 @@ type StreamConfig[T any] struct {
 	// Unmarshal is a custom unmarshaling function.
 	// If nil, uses json.Unmarshal.
 	Unmarshal func([]byte) (*T, error)
+
+	// OnEvent is called for each non-empty event read from the stream (optional).
+	OnEvent func(index int, event *Event)
+
+	// IdleTimeout aborts the stream with a *errors.StreamIdleTimeoutError
+	// if no event arrives within this duration while Next is waiting.
+	// Zero or negative means no timeout.
+	IdleTimeout time.Duration
+
+	// Reconnect reopens the stream after a transport error (optional).
+	// Events replayed by the new stream are skipped by event ID.
+	Reconnect ReconnectFunc
+
+	// MaxReconnects is how many times Reconnect may be called.
+	MaxReconnects int
+
+	// ReconnectBackoff is the wait before the first reconnect. It doubles
+	// with each attempt, up to MaxReconnectBackoff.
+	ReconnectBackoff time.Duration
 }
 
 // NewStream creates a new typed stream reader.
@@ -64,23 +110,34 @@ func NewStream[T any](config StreamConfig[T]) *Stream[T] {
 		}
 	}
 
-	return &Stream[T]{
-		parser:    NewSSEParser(config.Reader),
-		reader:    config.Reader,
-		done:      make(chan struct{}),
-		ctx:       config.Context,
-		unmarshal: config.Unmarshal,
+	s := &Stream[T]{
+		parser:      NewSSEParser(config.Reader),
+		reader:      config.Reader,
+		done:        make(chan struct{}),
+		ctx:         config.Context,
+		unmarshal:   config.Unmarshal,
+		onEvent:     config.OnEvent,
+		idleTimeout: config.IdleTimeout,
+	}
+	if config.Reconnect != nil && config.MaxReconnects > 0 {
+		s.reconnect = config.Reconnect
+		s.maxReconnects = config.MaxReconnects
+		s.reconnectBackoff = config.ReconnectBackoff
+		s.seenIDs = make(map[string]struct{})
 	}
+	return s
 }
 
 // Next advances to the next event in the stream.
 // Returns false when the stream is complete or encounters an error.
 func (s *Stream[T]) Next() bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	s.readMu.Lock()
+	defer s.readMu.Unlock()
 
+	s.mu.Lock()
 	if s.closed {
 		s.err = ErrStreamClosed
+		s.mu.Unlock()
 		return false
 	}
 
@@ -89,42 +146,50 @@ func (s *Stream[T]) Next() bool {
 	case <-s.ctx.Done():
 		s.err = s.ctx.Err()
 		s.closeInternal()
+		s.mu.Unlock()
 		return false
 	default:
 	}
+	s.mu.Unlock()
 
-	// Read next event
-	event, err := s.parser.Next()
-	if err != nil {
-		if errors.Is(err, ErrStreamDone) {
-			// Stream completed successfully
-			s.closeInternal()
-			return false
+	// Read without holding mu so that Close can interrupt the read
+	event, err := s.readEvent()
+	for err != nil && s.canReconnect(err) {
+		if err = s.reopen(err); err == nil {
+			event, err = s.readEvent()
 		}
+	}
 
-		if errors.Is(err, io.EOF) {
-			// End of stream
-			s.closeInternal()
-			return false
-		}
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		// Error occurred
-		s.err = err
+	if err != nil {
+		switch {
+		case s.abortErr != nil:
+			s.err = s.abortErr
+		case s.closed:
+			// Closed by another goroutine during the read
+			s.err = ErrStreamClosed
+		case stderrors.Is(err, ErrStreamDone), stderrors.Is(err, io.EOF):
+			// Stream completed successfully
+		default:
+			s.err = err
+		}
 		s.closeInternal()
 		return false
 	}
 
-	// Skip empty events
-	if event.IsEmpty() {
-		return s.Next() // Recursively read next event
-	}
-
 	// Check for done sentinel
 	if event.IsDone() {
 		s.closeInternal()
 		return false
 	}
 
+	if s.onEvent != nil {
+		s.onEvent(s.eventCount, event)
+	}
+	s.eventCount++
+
 	// Parse event data
 	parsed, err := s.unmarshal([]byte(event.Data))
 	if err != nil {
@@ -136,6 +201,60 @@ func (s *Stream[T]) Next() bool {
 	return true
 }
 
+// readEvent reads the next event with a payload, skipping keep-alives and,
+// on reconnecting streams, events already read. The idle timer restarts
+// for every event received.
+func (s *Stream[T]) readEvent() (*Event, error) {
+	for {
+		s.startIdleTimer()
+		event, err := s.parser.Next()
+		s.stopIdleTimer()
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip events without a payload (e.g. "data:" keep-alives)
+		if strings.TrimSpace(event.Data) == "" {
+			continue
+		}
+		if s.reconnect != nil && !s.track(event) {
+			continue
+		}
+		return event, nil
+	}
+}
+
+// startIdleTimer arms the idle timeout, if any, for one read.
+func (s *Stream[T]) startIdleTimer() {
+	if s.idleTimeout <= 0 {
+		return
+	}
+	if s.idleTimer == nil {
+		s.idleTimer = time.AfterFunc(s.idleTimeout, s.idle)
+		return
+	}
+	s.idleTimer.Reset(s.idleTimeout)
+}
+
+// stopIdleTimer disarms the idle timeout.
+func (s *Stream[T]) stopIdleTimer() {
+	if s.idleTimer != nil {
+		s.idleTimer.Stop()
+	}
+}
+
+// idle aborts the stream after the idle timeout expired.
+func (s *Stream[T]) idle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.abortErr = errors.NewStreamIdleTimeoutError(s.idleTimeout)
+	s.closeInternal()
+}
+
 // Current returns the current event data.
 // Should be called after Next() returns true.
 func (s *Stream[T]) Current() *T {
@@ -153,7 +272,8 @@ func (s *Stream[T]) Err() error {
 	return s.err
 }
 
-// Close closes the stream and releases resources.
+// Close closes the stream and releases resources. It unblocks a Next call
+// waiting for data in another goroutine, which then returns false.
 func (s *Stream[T]) Close() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -290,7 +410,7 @@ func (s *RawStream) Next() bool {
 
 	event, err := s.parser.Next()
 	if err != nil {
-		if errors.Is(err, ErrStreamDone) || errors.Is(err, io.EOF) {
+		if stderrors.Is(err, ErrStreamDone) || stderrors.Is(err, io.EOF) {
 			s.closeInternal()
 			return false
 		}
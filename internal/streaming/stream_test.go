@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"io"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 type testMessage struct {
@@ -129,6 +132,38 @@ data: [DONE]
 	assert.NoError(t, stream.Err())
 }
 
+func TestStream_OnEvent(t *testing.T) {
+	t.Parallel()
+
+	data := `event: message
+data: {"content":"first","role":"user"}
+
+data: {"content":"second","role":"assistant"}
+
+data: [DONE]
+
+`
+	reader := nopCloser{strings.NewReader(data)}
+
+	var indexes []int
+	var types []string
+	stream := NewStream[testMessage](StreamConfig[testMessage]{
+		Reader: reader,
+		OnEvent: func(index int, event *Event) {
+			indexes = append(indexes, index)
+			types = append(types, event.Type)
+		},
+	})
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+	require.NoError(t, stream.Err())
+
+	assert.Equal(t, []int{0, 1}, indexes)
+	assert.Equal(t, "message", types[0])
+}
+
 func TestStream_Recv(t *testing.T) {
 	t.Parallel()
 
@@ -442,3 +477,153 @@ func TestRawStream_Close(t *testing.T) {
 	assert.False(t, stream.Next())
 	assert.ErrorIs(t, stream.Err(), ErrStreamClosed)
 }
+
+func TestStream_Next_Robustness(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []string
+	}{
+		{
+			name: "keep-alives and empty data frames are skipped",
+			chunks: []string{
+				": keep-alive\n\n",
+				"data:\n\n",
+				"data: {\"content\":\"a\"}\n\n",
+				"event: ping\n\n",
+				"data: \n\n",
+				"data: {\"content\":\"b\"}\n\n",
+				"data: [DONE]\n\n",
+			},
+			want: []string{"a", "b"},
+		},
+		{
+			name: "multi-line JSON payload",
+			chunks: []string{
+				"data: {\"content\":\n",
+				"data: \"joined\",\"role\":\"assistant\"}\n\n",
+			},
+			want: []string{"joined"},
+		},
+		{
+			name:   "frame split across reads with CRLF",
+			chunks: []string{"data: {\"cont", "ent\":\"x\"}\r\n", "\r\ndata: {\"content\":\"y\"}\r\n\r\n"},
+			want:   []string{"x", "y"},
+		},
+		{
+			name:   "ends at EOF without DONE",
+			chunks: []string{"data: {\"content\":\"x\"}\n\n: bye\n"},
+			want:   []string{"x"},
+		},
+		{
+			name:   "stops at DONE even if more data follows",
+			chunks: []string{"data: {\"content\":\"x\"}\n\ndata: [DONE]\n\ndata: {\"content\":\"y\"}\n\n"},
+			want:   []string{"x"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stream := NewStream[testMessage](StreamConfig[testMessage]{
+				Reader: nopCloser{&chunkedReader{chunks: append([]string(nil), tt.chunks...)}},
+			})
+			defer stream.Close()
+
+			var got []string
+			for stream.Next() {
+				require.NoError(t, stream.Err())
+				got = append(got, stream.Current().Content)
+			}
+
+			assert.NoError(t, stream.Err())
+			assert.Equal(t, tt.want, got)
+			assert.True(t, stream.IsClosed())
+		})
+	}
+}
+
+func TestStream_IdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	// The writer sends one event and then stalls without closing
+	pr, pw := io.Pipe()
+	go pw.Write([]byte("data: {\"content\":\"hello\"}\n\n"))
+	defer pw.Close()
+
+	stream := NewStream[testMessage](StreamConfig[testMessage]{
+		Reader:      pr,
+		IdleTimeout: 100 * time.Millisecond,
+	})
+
+	require.True(t, stream.Next())
+	assert.Equal(t, "hello", stream.Current().Content)
+
+	start := time.Now()
+	assert.False(t, stream.Next())
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	var idleErr *errors.StreamIdleTimeoutError
+	require.ErrorAs(t, stream.Err(), &idleErr)
+	assert.Equal(t, 100*time.Millisecond, idleErr.Timeout)
+	assert.True(t, stream.IsClosed())
+}
+
+func TestStream_IdleTimeout_ResetsOnEvent(t *testing.T) {
+	t.Parallel()
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer pw.Close()
+		for i := 0; i < 5; i++ {
+			time.Sleep(40 * time.Millisecond)
+			pw.Write([]byte("data: {\"content\":\"tick\"}\n\n"))
+		}
+	}()
+
+	stream := NewStream[testMessage](StreamConfig[testMessage]{
+		Reader:      pr,
+		IdleTimeout: 150 * time.Millisecond,
+	})
+
+	// The stream as a whole outlasts the idle timeout
+	items, err := stream.All()
+	require.NoError(t, err)
+	assert.Len(t, items, 5)
+}
+
+func TestStream_CloseUnblocksNext(t *testing.T) {
+	t.Parallel()
+
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	stream := NewStream[testMessage](StreamConfig[testMessage]{
+		Reader: pr,
+	})
+
+	var returned atomic.Bool
+	result := make(chan bool)
+	go func() {
+		ok := stream.Next()
+		returned.Store(true)
+		result <- ok
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, returned.Load(), "Next blocks while no data arrives")
+
+	require.NoError(t, stream.Close())
+
+	select {
+	case ok := <-result:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not unblock Next")
+	}
+	assert.ErrorIs(t, stream.Err(), ErrStreamClosed)
+}
@@ -0,0 +1,211 @@
+// Package telemetry defines the tracing interface the SDK instruments API
+// calls with. The SDK calls a Tracer only when one is configured, so
+// clients without tracing pay no cost.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// Tracer starts spans for API calls and their HTTP attempts.
+// Implementations must be safe for concurrent use.
+type Tracer interface {
+	// StartCall is called once per API call, before the first attempt.
+	// The returned context is used for every attempt of the call.
+	StartCall(ctx context.Context, call *Call) (context.Context, CallSpan)
+
+	// StartAttempt is called before each HTTP attempt is sent, including
+	// retries. It may add trace context headers to req.
+	StartAttempt(ctx context.Context, req *http.Request, attempt int) (context.Context, AttemptSpan)
+}
+
+// Call describes an API call.
+type Call struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the endpoint path relative to the base URL, such as
+	// "/chat/completions".
+	Path string
+
+	// Service is the API service, taken from the first path segment,
+	// such as "chat", "embeddings" or "files".
+	Service string
+
+	// Model is the model named in the JSON request body, if any.
+	Model string
+
+	// Stream is true for streaming calls.
+	Stream bool
+}
+
+// CallSpan is the span of an API call.
+type CallSpan interface {
+	// SetUsage records the token usage reported by the API.
+	SetUsage(usage Usage)
+
+	// End ends the span once the response body is closed. statusCode is
+	// zero and err is set if no response was received.
+	End(statusCode int, err error)
+}
+
+// AttemptSpan is the span of a single HTTP attempt.
+type AttemptSpan interface {
+	// End ends the span when the response headers are received or the
+	// attempt fails.
+	End(statusCode int, err error)
+}
+
+// Usage is the token usage of an API call, with the model build that
+// served it as far as the response reports it.
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+
+	// Model is the model named in the response.
+	Model string `json:"-"`
+
+	// ModelVersion is the version of the model build.
+	ModelVersion string `json:"-"`
+
+	// SystemFingerprint identifies the backend configuration.
+	SystemFingerprint string `json:"-"`
+
+	// ServiceTier is the service tier that processed the call.
+	ServiceTier string `json:"-"`
+}
+
+type callSpanKey struct{}
+
+// WithCallSpan returns a copy of ctx carrying the span of an API call.
+func WithCallSpan(ctx context.Context, span CallSpan) context.Context {
+	return context.WithValue(ctx, callSpanKey{}, span)
+}
+
+// CallSpanFromContext returns the call span carried by ctx, or nil.
+func CallSpanFromContext(ctx context.Context) CallSpan {
+	span, _ := ctx.Value(callSpanKey{}).(CallSpan)
+	return span
+}
+
+// ServiceFromPath returns the service of an endpoint path, which is its
+// first segment.
+func ServiceFromPath(path string) string {
+	service, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return service
+}
+
+// ModelFromBody returns the model field of a JSON request body.
+func ModelFromBody(body []byte) string {
+	var fields struct {
+		Model string `json:"model"`
+	}
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	return fields.Model
+}
+
+// usageFields are the top-level response fields ParseUsage reads.
+type usageFields struct {
+	Usage             *Usage `json:"usage"`
+	Model             string `json:"model"`
+	ModelVersion      string `json:"model_version"`
+	SystemFingerprint string `json:"system_fingerprint"`
+	ServiceTier       string `json:"service_tier"`
+}
+
+// build copies the model build fields into u.
+func (f *usageFields) build(u *Usage) {
+	u.Model = f.Model
+	u.ModelVersion = f.ModelVersion
+	u.SystemFingerprint = f.SystemFingerprint
+	u.ServiceTier = f.ServiceTier
+}
+
+// ParseUsage returns the usage field of a JSON response body or stream
+// chunk, with the model build fields next to it, and false if there is no
+// usage.
+func ParseUsage(body []byte) (Usage, bool) {
+	if !bytes.Contains(body, []byte(`"usage"`)) {
+		return Usage{}, false
+	}
+
+	var fields usageFields
+	if json.Unmarshal(body, &fields) != nil || fields.Usage == nil {
+		return Usage{}, false
+	}
+	usage := *fields.Usage
+	fields.build(&usage)
+	return usage, true
+}
+
+// buildKeys are the keys of the model build fields that are sent on a
+// single chunk of some streams.
+var buildKeys = [][]byte{
+	[]byte(`"system_fingerprint"`),
+	[]byte(`"service_tier"`),
+	[]byte(`"model_version"`),
+}
+
+// StreamUsage parses the usage of a stream. The model build fields may
+// arrive on an earlier chunk than the usage, so it remembers them.
+// The zero value is ready to use.
+type StreamUsage struct {
+	build Usage
+}
+
+// Parse returns the usage of a stream chunk, with the model build fields
+// of the stream so far, and false if the chunk has no usage.
+func (s *StreamUsage) Parse(chunk []byte) (Usage, bool) {
+	hasUsage := bytes.Contains(chunk, []byte(`"usage"`))
+	hasBuild := false
+	for _, key := range buildKeys {
+		if bytes.Contains(chunk, key) {
+			hasBuild = true
+			break
+		}
+	}
+	if !hasUsage && !hasBuild {
+		return Usage{}, false
+	}
+
+	var fields usageFields
+	if json.Unmarshal(chunk, &fields) != nil {
+		return Usage{}, false
+	}
+	var build Usage
+	fields.build(&build)
+	s.merge(build)
+	if fields.Usage == nil {
+		return Usage{}, false
+	}
+
+	usage := *fields.Usage
+	usage.Model = s.build.Model
+	usage.ModelVersion = s.build.ModelVersion
+	usage.SystemFingerprint = s.build.SystemFingerprint
+	usage.ServiceTier = s.build.ServiceTier
+	return usage, true
+}
+
+// merge records the model build fields set in u.
+func (s *StreamUsage) merge(u Usage) {
+	if u.Model != "" {
+		s.build.Model = u.Model
+	}
+	if u.ModelVersion != "" {
+		s.build.ModelVersion = u.ModelVersion
+	}
+	if u.SystemFingerprint != "" {
+		s.build.SystemFingerprint = u.SystemFingerprint
+	}
+	if u.ServiceTier != "" {
+		s.build.ServiceTier = u.ServiceTier
+	}
+}
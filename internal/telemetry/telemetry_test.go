@@ -0,0 +1,80 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nopSpan struct{}
+
+func (nopSpan) SetUsage(Usage) {}
+func (nopSpan) End(int, error) {}
+
+func TestCallSpanContext(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, CallSpanFromContext(context.Background()))
+
+	ctx := WithCallSpan(context.Background(), nopSpan{})
+	assert.Equal(t, nopSpan{}, CallSpanFromContext(ctx))
+}
+
+func TestServiceFromPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "chat", ServiceFromPath("/chat/completions"))
+	assert.Equal(t, "embeddings", ServiceFromPath("/embeddings"))
+	assert.Equal(t, "files", ServiceFromPath("files/file-abc/content"))
+	assert.Equal(t, "", ServiceFromPath(""))
+}
+
+func TestModelFromBody(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "glm-4.7", ModelFromBody([]byte(`{"model":"glm-4.7","messages":[]}`)))
+	assert.Equal(t, "", ModelFromBody([]byte(`{"input":"text"}`)))
+	assert.Equal(t, "", ModelFromBody([]byte(`not json`)))
+}
+
+func TestParseUsage(t *testing.T) {
+	t.Parallel()
+
+	usage, ok := ParseUsage([]byte(`{"id":"1","usage":{"prompt_tokens":10,"completion_tokens":2,"total_tokens":12}}`))
+	assert.True(t, ok)
+	assert.Equal(t, Usage{PromptTokens: 10, CompletionTokens: 2, TotalTokens: 12}, usage)
+
+	_, ok = ParseUsage([]byte(`{"choices":[{"delta":{"content":"hi"}}]}`))
+	assert.False(t, ok)
+
+	_, ok = ParseUsage([]byte(`{"usage":null}`))
+	assert.False(t, ok)
+
+	usage, ok = ParseUsage([]byte(`{"model":"glm-4.7","model_version":"2025-10-01","system_fingerprint":"fp_3b2a1c",` +
+		`"service_tier":"standard","usage":{"prompt_tokens":1,"completion_tokens":1,"total_tokens":2}}`))
+	assert.True(t, ok)
+	assert.Equal(t, Usage{
+		PromptTokens: 1, CompletionTokens: 1, TotalTokens: 2,
+		Model: "glm-4.7", ModelVersion: "2025-10-01", SystemFingerprint: "fp_3b2a1c", ServiceTier: "standard",
+	}, usage)
+}
+
+func TestStreamUsage(t *testing.T) {
+	t.Parallel()
+
+	var s StreamUsage
+	_, ok := s.Parse([]byte(`{"model":"glm-4.7","system_fingerprint":"fp_3b2a1c","choices":[{"delta":{"content":"hi"}}]}`))
+	assert.False(t, ok)
+	_, ok = s.Parse([]byte(`{"model":"glm-4.7","choices":[{"delta":{"content":"!"}}]}`))
+	assert.False(t, ok)
+
+	// The build fields of earlier chunks are kept
+	usage, ok := s.Parse([]byte(`{"model":"glm-4.7","service_tier":"standard","choices":[],` +
+		`"usage":{"prompt_tokens":5,"completion_tokens":1,"total_tokens":6}}`))
+	assert.True(t, ok)
+	assert.Equal(t, Usage{
+		PromptTokens: 5, CompletionTokens: 1, TotalTokens: 6,
+		Model: "glm-4.7", SystemFingerprint: "fp_3b2a1c", ServiceTier: "standard",
+	}, usage)
+}
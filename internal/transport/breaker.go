@@ -0,0 +1,336 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Circuit breaker defaults, used for zero fields of CircuitBreakerConfig.
+const (
+	// DefaultCircuitFailures is the number of consecutive failures that
+	// opens a circuit when neither ConsecutiveFailures nor FailureRate is
+	// set.
+	DefaultCircuitFailures = 5
+
+	// DefaultCircuitMinRequests is the number of requests within the
+	// window before the failure rate is considered.
+	DefaultCircuitMinRequests = 10
+
+	// DefaultCircuitWindow is the sliding window of the failure rate.
+	DefaultCircuitWindow = 10 * time.Second
+
+	// DefaultCircuitCoolDown is how long a circuit stays open.
+	DefaultCircuitCoolDown = 30 * time.Second
+
+	// DefaultCircuitHalfOpenRequests is the number of probes let through
+	// a half-open circuit at a time.
+	DefaultCircuitHalfOpenRequests = 1
+)
+
+// circuitBuckets is the number of buckets the failure-rate window is split into.
+const circuitBuckets = 10
+
+// CircuitState is the state of a circuit.
+type CircuitState int
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails every request without sending it.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probes through. A
+	// successful probe closes the circuit and a failed one opens it again.
+	CircuitHalfOpen
+)
+
+// String returns the state name.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker. Zero fields use the
+// defaults above.
+type CircuitBreakerConfig struct {
+	// ConsecutiveFailures opens the circuit after this many failures in a
+	// row. Zero disables the check, unless FailureRate is zero too.
+	ConsecutiveFailures int
+
+	// FailureRate opens the circuit when at least this share of the
+	// requests within Window failed, between 0 and 1. Zero disables the
+	// check.
+	FailureRate float64
+
+	// MinRequests is the number of requests within Window before
+	// FailureRate is considered.
+	MinRequests int
+
+	// Window is the sliding window of FailureRate.
+	Window time.Duration
+
+	// CoolDown is how long the circuit stays open before probing.
+	CoolDown time.Duration
+
+	// HalfOpenRequests is the number of probes let through at a time
+	// while the circuit is half-open.
+	HalfOpenRequests int
+
+	// OnStateChange is called with the host whenever its circuit changes
+	// state. It must not block.
+	OnStateChange func(host string, from, to CircuitState)
+
+	// Now returns the current time. If nil, uses time.Now.
+	Now func() time.Time
+}
+
+// circuitBucket counts requests and failures in one slice of the window.
+type circuitBucket struct {
+	start    time.Time
+	requests int
+	failures int
+}
+
+// circuit is the state of the breaker for one host.
+type circuit struct {
+	state    CircuitState
+	openedAt time.Time
+
+	// generation changes with every state change, so that outcomes of
+	// requests admitted in an earlier state are ignored.
+	generation  uint64
+	consecutive int
+	probes      int
+	buckets     [circuitBuckets]circuitBucket
+}
+
+// circuitResult is the outcome of a request passed through a circuit.
+type circuitResult int
+
+const (
+	circuitSuccess circuitResult = iota
+	circuitFailure
+
+	// circuitIgnored is the outcome of requests canceled by the caller,
+	// which say nothing about the health of the host.
+	circuitIgnored
+)
+
+// CircuitBreaker fails requests fast while a host is failing: after too
+// many 5xx responses or connection errors the circuit of the host opens
+// for a cool-down period, then lets a few probes through to decide
+// whether to close again. 4xx responses never open a circuit.
+// It is safe for concurrent use.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	config   CircuitBreakerConfig
+	width    time.Duration
+	circuits map[string]*circuit
+}
+
+// NewCircuitBreaker creates a circuit breaker with config.
+func NewCircuitBreaker(config CircuitBreakerConfig) *CircuitBreaker {
+	if config.ConsecutiveFailures <= 0 && config.FailureRate <= 0 {
+		config.ConsecutiveFailures = DefaultCircuitFailures
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = DefaultCircuitMinRequests
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultCircuitWindow
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = DefaultCircuitCoolDown
+	}
+	if config.HalfOpenRequests <= 0 {
+		config.HalfOpenRequests = DefaultCircuitHalfOpenRequests
+	}
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	width := config.Window / circuitBuckets
+	if width <= 0 {
+		width = 1
+	}
+
+	return &CircuitBreaker{
+		config:   config,
+		width:    width,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// State returns the state of the circuit of host. An open circuit whose
+// cool-down has passed is reported as half-open.
+func (b *CircuitBreaker) State(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[host]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && !b.config.Now().Before(c.openedAt.Add(b.config.CoolDown)) {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// allow admits a request to host, returning a function that must be
+// called with its outcome, or an *errors.CircuitOpenError if the circuit
+// is open.
+func (b *CircuitBreaker) allow(host string) (func(circuitResult), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	now := b.config.Now()
+
+	if c.state == CircuitOpen {
+		retryAt := c.openedAt.Add(b.config.CoolDown)
+		if now.Before(retryAt) {
+			return nil, errors.NewCircuitOpenError(host, retryAt)
+		}
+		b.transition(host, c, CircuitHalfOpen)
+	}
+
+	probe := c.state == CircuitHalfOpen
+	if probe {
+		if c.probes >= b.config.HalfOpenRequests {
+			return nil, errors.NewCircuitOpenError(host, now)
+		}
+		c.probes++
+	}
+
+	generation := c.generation
+	var once sync.Once
+	return func(result circuitResult) {
+		once.Do(func() { b.record(host, generation, probe, result) })
+	}, nil
+}
+
+// record applies the outcome of a request admitted in generation.
+func (b *CircuitBreaker) record(host string, generation uint64, probe bool, result circuitResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	if c.generation != generation {
+		return
+	}
+
+	if probe {
+		c.probes--
+		switch result {
+		case circuitSuccess:
+			b.transition(host, c, CircuitClosed)
+		case circuitFailure:
+			b.transition(host, c, CircuitOpen)
+		}
+		return
+	}
+
+	if result == circuitIgnored {
+		return
+	}
+
+	bucket := b.bucket(c)
+	bucket.requests++
+	if result == circuitSuccess {
+		c.consecutive = 0
+		return
+	}
+	bucket.failures++
+	c.consecutive++
+
+	if b.config.ConsecutiveFailures > 0 && c.consecutive >= b.config.ConsecutiveFailures {
+		b.transition(host, c, CircuitOpen)
+		return
+	}
+	if b.config.FailureRate > 0 {
+		requests, failures := b.totals(c)
+		if requests >= b.config.MinRequests && float64(failures) >= b.config.FailureRate*float64(requests) {
+			b.transition(host, c, CircuitOpen)
+		}
+	}
+}
+
+// circuit returns the circuit of host, creating it if needed.
+// Must be called with lock held.
+func (b *CircuitBreaker) circuit(host string) *circuit {
+	c, ok := b.circuits[host]
+	if !ok {
+		c = &circuit{}
+		b.circuits[host] = c
+	}
+	return c
+}
+
+// transition moves c to state, resetting its counters.
+// Must be called with lock held.
+func (b *CircuitBreaker) transition(host string, c *circuit, state CircuitState) {
+	from := c.state
+	*c = circuit{state: state, generation: c.generation + 1}
+	if state == CircuitOpen {
+		c.openedAt = b.config.Now()
+	}
+	if b.config.OnStateChange != nil && from != state {
+		b.config.OnStateChange(host, from, state)
+	}
+}
+
+// bucket returns the bucket of c for the current time, resetting it if
+// stale. Must be called with lock held.
+func (b *CircuitBreaker) bucket(c *circuit) *circuitBucket {
+	start := b.config.Now().Truncate(b.width)
+	idx := int(start.UnixNano()/int64(b.width)) % circuitBuckets
+	bucket := &c.buckets[idx]
+	if !bucket.start.Equal(start) {
+		*bucket = circuitBucket{start: start}
+	}
+	return bucket
+}
+
+// totals sums the buckets of c inside the window.
+// Must be called with lock held.
+func (b *CircuitBreaker) totals(c *circuit) (requests, failures int) {
+	cutoff := b.config.Now().Truncate(b.width).Add(-b.width * (circuitBuckets - 1))
+	for _, bucket := range c.buckets {
+		if !bucket.start.Before(cutoff) {
+			requests += bucket.requests
+			failures += bucket.failures
+		}
+	}
+	return requests, failures
+}
+
+// circuitOutcome classifies the result of an attempt for the breaker:
+// 5xx responses and connection errors are failures, requests canceled by
+// the caller are ignored, and everything else is a success.
+func circuitOutcome(ctx context.Context, resp *http.Response, err error) circuitResult {
+	if err != nil {
+		if ctx.Err() != nil {
+			return circuitIgnored
+		}
+		return circuitFailure
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return circuitFailure
+	}
+	return circuitSuccess
+}
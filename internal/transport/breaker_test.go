@@ -0,0 +1,281 @@
+package transport
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// fakeClock is a manually advanced clock for circuit breaker tests.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// stateChange is a circuit state change reported to OnStateChange.
+type stateChange struct {
+	host     string
+	from, to CircuitState
+}
+
+// newTestBreaker creates a breaker on a fake clock that records its state
+// changes.
+func newTestBreaker(config CircuitBreakerConfig) (*CircuitBreaker, *fakeClock, *[]stateChange) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	changes := &[]stateChange{}
+	config.Now = clock.Now
+	config.OnStateChange = func(host string, from, to CircuitState) {
+		*changes = append(*changes, stateChange{host, from, to})
+	}
+	return NewCircuitBreaker(config), clock, changes
+}
+
+// pass sends one request through the breaker with result, reporting
+// whether it was admitted.
+func pass(t *testing.T, b *CircuitBreaker, host string, result circuitResult) bool {
+	t.Helper()
+
+	done, err := b.allow(host)
+	if err != nil {
+		if !errors.IsCircuitOpenError(err) {
+			t.Fatalf("allow() error = %v, want CircuitOpenError", err)
+		}
+		return false
+	}
+	done(result)
+	return true
+}
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	t.Parallel()
+
+	b, clock, changes := newTestBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		CoolDown:            10 * time.Second,
+	})
+
+	// A success resets the count of consecutive failures
+	pass(t, b, "api", circuitFailure)
+	pass(t, b, "api", circuitFailure)
+	pass(t, b, "api", circuitSuccess)
+	pass(t, b, "api", circuitFailure)
+	pass(t, b, "api", circuitFailure)
+	if got := b.State("api"); got != CircuitClosed {
+		t.Fatalf("State() = %v, want closed", got)
+	}
+
+	pass(t, b, "api", circuitFailure)
+	if got := b.State("api"); got != CircuitOpen {
+		t.Fatalf("State() = %v, want open", got)
+	}
+
+	// Open circuits fail fast until the cool-down has passed
+	_, err := b.allow("api")
+	var circuitErr *errors.CircuitOpenError
+	if !stderrors.As(err, &circuitErr) {
+		t.Fatalf("allow() error = %v, want CircuitOpenError", err)
+	}
+	if circuitErr.Host != "api" || !circuitErr.RetryAt.Equal(clock.now.Add(10*time.Second)) {
+		t.Errorf("CircuitOpenError = %+v", circuitErr)
+	}
+
+	// Other hosts are not affected
+	if !pass(t, b, "other", circuitSuccess) {
+		t.Error("request to another host was refused")
+	}
+
+	clock.Advance(10 * time.Second)
+	if got := b.State("api"); got != CircuitHalfOpen {
+		t.Fatalf("State() after cool-down = %v, want half-open", got)
+	}
+
+	// A successful probe closes the circuit
+	if !pass(t, b, "api", circuitSuccess) {
+		t.Fatal("probe was refused")
+	}
+	if got := b.State("api"); got != CircuitClosed {
+		t.Fatalf("State() after probe = %v, want closed", got)
+	}
+
+	want := []stateChange{
+		{"api", CircuitClosed, CircuitOpen},
+		{"api", CircuitOpen, CircuitHalfOpen},
+		{"api", CircuitHalfOpen, CircuitClosed},
+	}
+	if len(*changes) != len(want) {
+		t.Fatalf("state changes = %v, want %v", *changes, want)
+	}
+	for i := range want {
+		if (*changes)[i] != want[i] {
+			t.Errorf("state change %d = %v, want %v", i, (*changes)[i], want[i])
+		}
+	}
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	t.Parallel()
+
+	b, clock, _ := newTestBreaker(CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		CoolDown:            time.Second,
+		HalfOpenRequests:    2,
+	})
+
+	pass(t, b, "api", circuitFailure)
+	clock.Advance(time.Second)
+
+	// Only HalfOpenRequests probes are let through at a time
+	first, err := b.allow("api")
+	if err != nil {
+		t.Fatalf("first probe: %v", err)
+	}
+	second, err := b.allow("api")
+	if err != nil {
+		t.Fatalf("second probe: %v", err)
+	}
+	if _, err := b.allow("api"); !errors.IsCircuitOpenError(err) {
+		t.Fatalf("third probe error = %v, want CircuitOpenError", err)
+	}
+
+	// A failed probe opens the circuit again, and the outcome of the
+	// other probe no longer counts
+	first(circuitFailure)
+	second(circuitSuccess)
+	if got := b.State("api"); got != CircuitOpen {
+		t.Fatalf("State() after failed probe = %v, want open", got)
+	}
+
+	// Canceled probes free their slot without closing the circuit
+	clock.Advance(time.Second)
+	pass(t, b, "api", circuitIgnored)
+	if got := b.State("api"); got != CircuitHalfOpen {
+		t.Fatalf("State() after canceled probe = %v, want half-open", got)
+	}
+	pass(t, b, "api", circuitSuccess)
+	if got := b.State("api"); got != CircuitClosed {
+		t.Fatalf("State() after probe = %v, want closed", got)
+	}
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	t.Parallel()
+
+	b, clock, _ := newTestBreaker(CircuitBreakerConfig{
+		FailureRate: 0.5,
+		MinRequests: 4,
+		Window:      10 * time.Second,
+	})
+
+	// Below MinRequests, failures do not open the circuit
+	pass(t, b, "api", circuitFailure)
+	pass(t, b, "api", circuitSuccess)
+	pass(t, b, "api", circuitFailure)
+	if got := b.State("api"); got != CircuitClosed {
+		t.Fatalf("State() = %v, want closed", got)
+	}
+
+	// Old requests slide out of the window
+	clock.Advance(15 * time.Second)
+	pass(t, b, "api", circuitSuccess)
+	pass(t, b, "api", circuitSuccess)
+	pass(t, b, "api", circuitSuccess)
+	pass(t, b, "api", circuitFailure)
+	if got := b.State("api"); got != CircuitClosed {
+		t.Fatalf("State() at 25%% failures = %v, want closed", got)
+	}
+
+	pass(t, b, "api", circuitFailure)
+	pass(t, b, "api", circuitFailure)
+	if got := b.State("api"); got != CircuitOpen {
+		t.Fatalf("State() at 50%% failures = %v, want open", got)
+	}
+}
+
+func TestCircuitBreaker_StaleOutcome(t *testing.T) {
+	t.Parallel()
+
+	b, clock, _ := newTestBreaker(CircuitBreakerConfig{ConsecutiveFailures: 1, CoolDown: time.Second})
+
+	// A request admitted while closed finishes after the circuit opened
+	// and closed again; its failure must not reopen the circuit
+	slow, err := b.allow("api")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pass(t, b, "api", circuitFailure)
+	clock.Advance(time.Second)
+	pass(t, b, "api", circuitSuccess)
+
+	slow(circuitFailure)
+	if got := b.State("api"); got != CircuitClosed {
+		t.Errorf("State() = %v, want closed", got)
+	}
+}
+
+func TestCircuitOutcome(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want circuitResult
+	}{
+		{"ok", context.Background(), &http.Response{StatusCode: http.StatusOK}, nil, circuitSuccess},
+		{"bad request", context.Background(), &http.Response{StatusCode: http.StatusBadRequest}, nil, circuitSuccess},
+		{"rate limited", context.Background(), &http.Response{StatusCode: http.StatusTooManyRequests}, nil, circuitSuccess},
+		{"server error", context.Background(), &http.Response{StatusCode: http.StatusBadGateway}, nil, circuitFailure},
+		{"connection error", context.Background(), nil, &url.Error{Op: "Post", Err: context.DeadlineExceeded}, circuitFailure},
+		{"canceled", canceled, nil, context.Canceled, circuitIgnored},
+	}
+	for _, tt := range tests {
+		if got := circuitOutcome(tt.ctx, tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: circuitOutcome() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDoWithRetry_CircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := DefaultRetryConfig()
+	config.MaxRetries = 5
+	client, _ := newPolicyTestClient(server.URL, config)
+	breaker, _, _ := newTestBreaker(CircuitBreakerConfig{ConsecutiveFailures: 2})
+	client.GetClient().SetCircuitBreaker(breaker)
+
+	ctx := context.Background()
+	req, err := client.GetClient().NewRequest(ctx, http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	// Retries stop as soon as the circuit opens
+	_, err = client.DoWithRetry(ctx, req)
+	if !errors.IsCircuitOpenError(err) {
+		t.Fatalf("DoWithRetry error = %v, want CircuitOpenError", err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
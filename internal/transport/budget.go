@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"sync"
+	"time"
+)
+
+// retryBudgetBuckets is the number of buckets the sliding window is split into.
+const retryBudgetBuckets = 10
+
+// DefaultMinRetriesPerWindow is the number of retries always allowed per
+// window, so that low-traffic clients can still retry.
+const DefaultMinRetriesPerWindow = 10
+
+// budgetBucket counts requests and retries in one slice of the window.
+type budgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// RetryBudget limits retries to a fraction of requests within a sliding
+// window, preventing retry storms when the API is degraded.
+// It is safe for concurrent use.
+type RetryBudget struct {
+	mu         sync.Mutex
+	ratio      float64
+	minRetries int
+	width      time.Duration
+	buckets    [retryBudgetBuckets]budgetBucket
+	now        func() time.Time
+}
+
+// NewRetryBudget creates a budget allowing retries up to ratio times the
+// number of requests seen in window, plus minRetries per window.
+func NewRetryBudget(ratio float64, window time.Duration, minRetries int) *RetryBudget {
+	if window <= 0 {
+		window = time.Minute
+	}
+	width := window / retryBudgetBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return &RetryBudget{
+		ratio:      ratio,
+		minRetries: minRetries,
+		width:      width,
+		now:        time.Now,
+	}
+}
+
+// RecordRequest records an initial (non-retry) attempt.
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.current().requests++
+}
+
+// TryRetry reports whether a retry is within budget, recording it if so.
+func (b *RetryBudget) TryRetry() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	requests, retries := b.totals()
+	allowed := int(float64(requests)*b.ratio) + b.minRetries
+	if retries >= allowed {
+		return false
+	}
+
+	b.current().retries++
+	return true
+}
+
+// Counts returns the number of requests and retries in the current window.
+func (b *RetryBudget) Counts() (requests, retries int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.totals()
+}
+
+// current returns the bucket for the current time, resetting it if stale.
+// Must be called with lock held.
+func (b *RetryBudget) current() *budgetBucket {
+	start := b.now().Truncate(b.width)
+	idx := int(start.UnixNano()/int64(b.width)) % retryBudgetBuckets
+	bucket := &b.buckets[idx]
+	if !bucket.start.Equal(start) {
+		*bucket = budgetBucket{start: start}
+	}
+	return bucket
+}
+
+// totals sums the buckets inside the window.
+// Must be called with lock held.
+func (b *RetryBudget) totals() (requests, retries int) {
+	cutoff := b.now().Truncate(b.width).Add(-b.width * (retryBudgetBuckets - 1))
+	for _, bucket := range b.buckets {
+		if !bucket.start.Before(cutoff) {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	return requests, retries
+}
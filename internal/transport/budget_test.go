@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+	budget := NewRetryBudget(0.5, 10*time.Second, 1)
+	budget.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		budget.RecordRequest()
+	}
+
+	// 4 requests * 0.5 + 1 minimum = 3 retries
+	for i := 0; i < 3; i++ {
+		if !budget.TryRetry() {
+			t.Fatalf("TryRetry() #%d = false, want true", i+1)
+		}
+	}
+	if budget.TryRetry() {
+		t.Error("TryRetry() = true after budget exhausted")
+	}
+
+	requests, retries := budget.Counts()
+	if requests != 4 || retries != 3 {
+		t.Errorf("Counts() = %d, %d, want 4, 3", requests, retries)
+	}
+
+	// Half a window later, old counts still apply
+	now = now.Add(5 * time.Second)
+	if budget.TryRetry() {
+		t.Error("TryRetry() = true within the same window")
+	}
+
+	// Once the window slides past, the budget recovers
+	now = now.Add(10 * time.Second)
+	requests, retries = budget.Counts()
+	if requests != 0 || retries != 0 {
+		t.Errorf("Counts() after window = %d, %d, want 0, 0", requests, retries)
+	}
+	if !budget.TryRetry() {
+		t.Error("TryRetry() = false after window slid")
+	}
+}
+
+func TestDoWithRetry_Budget(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := DefaultRetryConfig()
+	config.MaxRetries = 5
+	config.Budget = NewRetryBudget(0, time.Minute, 2)
+	client, _ := newPolicyTestClient(server.URL, config)
+
+	do := func() int {
+		ctx := context.Background()
+		req, err := client.GetClient().NewRequest(ctx, http.MethodGet, "/test", nil)
+		if err != nil {
+			t.Fatalf("NewRequest failed: %v", err)
+		}
+		resp, err := client.DoWithRetry(ctx, req)
+		if err != nil {
+			t.Fatalf("DoWithRetry failed: %v", err)
+		}
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if got := do(); got != http.StatusServiceUnavailable {
+		t.Errorf("StatusCode = %d, want 503", got)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3 (1 request + 2 budgeted retries)", got)
+	}
+
+	// The budget is spent, so the next request is not retried
+	attempts.Store(0)
+	do()
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// contextKey is the type for context keys defined in this package.
+type contextKey int
+
+const (
+	headersContextKey contextKey = iota
+	queryParamsContextKey
+	retryPolicyContextKey
+	externalContextKey
+)
+
+// ContextWithHeaders returns a copy of ctx carrying per-request headers.
+// Headers already attached to ctx are kept unless overridden by h.
+// The given header is copied, so later changes to h do not affect ctx.
+func ContextWithHeaders(ctx context.Context, h http.Header) context.Context {
+	merged := HeadersFromContext(ctx).Clone()
+	if merged == nil {
+		merged = make(http.Header, len(h))
+	}
+	for k, v := range h {
+		merged[http.CanonicalHeaderKey(k)] = append([]string(nil), v...)
+	}
+	return context.WithValue(ctx, headersContextKey, merged)
+}
+
+// HeadersFromContext returns the per-request headers attached to ctx, or nil.
+// The returned header must not be modified.
+func HeadersFromContext(ctx context.Context) http.Header {
+	if ctx == nil {
+		return nil
+	}
+	h, _ := ctx.Value(headersContextKey).(http.Header)
+	return h
+}
+
+// ContextWithQueryParams returns a copy of ctx carrying per-request query parameters.
+// Parameters already attached to ctx are kept unless overridden by q.
+// The given values are copied, so later changes to q do not affect ctx.
+func ContextWithQueryParams(ctx context.Context, q url.Values) context.Context {
+	existing := QueryParamsFromContext(ctx)
+	merged := make(url.Values, len(existing)+len(q))
+	for k, v := range existing {
+		merged[k] = append([]string(nil), v...)
+	}
+	for k, v := range q {
+		merged[k] = append([]string(nil), v...)
+	}
+	return context.WithValue(ctx, queryParamsContextKey, merged)
+}
+
+// QueryParamsFromContext returns the per-request query parameters attached to ctx, or nil.
+// The returned values must not be modified.
+func QueryParamsFromContext(ctx context.Context) url.Values {
+	if ctx == nil {
+		return nil
+	}
+	q, _ := ctx.Value(queryParamsContextKey).(url.Values)
+	return q
+}
+
+// ContextExternal returns a copy of ctx for requests to a host other than
+// the API, such as downloads of generated images from a CDN. They are sent
+// without the per-request headers and query parameters of ctx, which are
+// meant for the API, and do not count against the client-side rate
+// limiter or the retry budget.
+func ContextExternal(ctx context.Context) context.Context {
+	return context.WithValue(ctx, externalContextKey, true)
+}
+
+// isExternal reports whether ctx is marked by ContextExternal.
+func isExternal(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	external, _ := ctx.Value(externalContextKey).(bool)
+	return external
+}
+
+// applyContextOverrides merges per-request headers and query parameters
+// from ctx into req. Values from ctx replace any existing values.
+func applyContextOverrides(ctx context.Context, req *http.Request) {
+	for k, v := range HeadersFromContext(ctx) {
+		req.Header[k] = append([]string(nil), v...)
+	}
+
+	if params := QueryParamsFromContext(ctx); len(params) > 0 {
+		q := req.URL.Query()
+		for k, v := range params {
+			q[k] = append([]string(nil), v...)
+		}
+		req.URL.RawQuery = q.Encode()
+	}
+}
@@ -0,0 +1,116 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestContextWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested contexts merge and override", func(t *testing.T) {
+		t.Parallel()
+
+		parent := ContextWithHeaders(context.Background(), http.Header{
+			"X-Tenant": []string{"a"},
+			"X-Flag":   []string{"1"},
+		})
+		child := ContextWithHeaders(parent, http.Header{"x-tenant": []string{"b"}})
+
+		got := HeadersFromContext(child)
+		if got.Get("X-Tenant") != "b" {
+			t.Errorf("X-Tenant = %q, want b", got.Get("X-Tenant"))
+		}
+		if got.Get("X-Flag") != "1" {
+			t.Errorf("X-Flag = %q, want 1", got.Get("X-Flag"))
+		}
+
+		if HeadersFromContext(parent).Get("X-Tenant") != "a" {
+			t.Error("parent headers were modified by child")
+		}
+	})
+
+	t.Run("input header is copied", func(t *testing.T) {
+		t.Parallel()
+
+		h := http.Header{"X-Flag": []string{"1"}}
+		ctx := ContextWithHeaders(context.Background(), h)
+		h.Set("X-Flag", "2")
+
+		if HeadersFromContext(ctx).Get("X-Flag") != "1" {
+			t.Error("context headers changed after mutating input")
+		}
+	})
+
+	t.Run("no headers", func(t *testing.T) {
+		t.Parallel()
+
+		if HeadersFromContext(context.Background()) != nil {
+			t.Error("expected nil headers")
+		}
+	})
+}
+
+func TestContextWithQueryParams(t *testing.T) {
+	t.Parallel()
+
+	parent := ContextWithQueryParams(context.Background(), url.Values{"a": []string{"1"}, "b": []string{"1"}})
+	child := ContextWithQueryParams(parent, url.Values{"b": []string{"2"}})
+
+	got := QueryParamsFromContext(child)
+	if got.Get("a") != "1" || got.Get("b") != "2" {
+		t.Errorf("unexpected merged params: %v", got)
+	}
+	if QueryParamsFromContext(parent).Get("b") != "1" {
+		t.Error("parent params were modified by child")
+	}
+}
+
+func TestHTTPClient_Do_ContextOverrides(t *testing.T) {
+	t.Parallel()
+
+	var gotHeader http.Header
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Clone()
+		gotQuery = r.URL.Query()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig()
+	config.BaseURL = server.URL
+	client := NewHTTPClient(config)
+
+	ctx := ContextWithHeaders(context.Background(), http.Header{
+		"X-Request-Source": []string{"test"},
+		"Authorization":    []string{"Bearer sub-account"},
+	})
+	ctx = ContextWithQueryParams(ctx, url.Values{"beta": []string{"true"}, "limit": []string{"5"}})
+
+	req, err := client.NewRequest(ctx, http.MethodGet, "/items", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	req.URL.RawQuery = "limit=10&after=x"
+	req.Header.Set("Authorization", "Bearer default")
+
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if gotHeader.Get("X-Request-Source") != "test" {
+		t.Errorf("X-Request-Source = %q, want test", gotHeader.Get("X-Request-Source"))
+	}
+	if gotHeader.Get("Authorization") != "Bearer sub-account" {
+		t.Errorf("Authorization = %q, want override", gotHeader.Get("Authorization"))
+	}
+	if gotQuery.Get("beta") != "true" || gotQuery.Get("limit") != "5" || gotQuery.Get("after") != "x" {
+		t.Errorf("unexpected query: %v", gotQuery)
+	}
+}
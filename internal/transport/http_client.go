@@ -6,15 +6,18 @@ import (
 	"crypto/tls"
 	"fmt"
 	"io"
-	"log/slog"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/sofianhadi1983/zai-sdk-go/internal/compress"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/ratelimit"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/telemetry"
 )
 
 // HTTPClientConfig holds configuration for the HTTP client.
@@ -22,9 +25,16 @@ type HTTPClientConfig struct {
 	// BaseURL is the base URL for all requests.
 	BaseURL string
 
-	// Timeout is the maximum duration for a request.
+	// Timeout is the maximum duration of a non-streaming request, from
+	// sending it to reading the last byte of the response. Zero or less
+	// means no timeout.
 	Timeout time.Duration
 
+	// StreamReadTimeout is the maximum wait of a stream for its response
+	// headers and for every read of its body. Zero or less means no
+	// timeout.
+	StreamReadTimeout time.Duration
+
 	// ConnectTimeout is the maximum duration for establishing a connection.
 	ConnectTimeout time.Duration
 
@@ -56,6 +66,24 @@ type HTTPClientConfig struct {
 
 	// MaxConnsPerHost limits the total number of connections per host.
 	MaxConnsPerHost int
+
+	// ForceAttemptHTTP2 negotiates HTTP/2 over TLS, which the transport
+	// does not do by default once its dialer is customized.
+	ForceAttemptHTTP2 bool
+
+	// Transport, if set, is used instead of creating a new transport, so
+	// that several clients share one connection pool. The connection
+	// settings above are then ignored, and Close leaves the transport open.
+	Transport http.RoundTripper
+
+	// HTTPClient, if set, sends all requests instead of an internal client.
+	// It takes precedence over Transport and the settings above, including
+	// Timeout and StreamReadTimeout, and Close leaves its connections open.
+	HTTPClient *http.Client
+
+	// Proxy returns the proxy for a request of the internal transport.
+	// If nil, no proxy is used.
+	Proxy func(*http.Request) (*url.URL, error)
 }
 
 // DefaultHTTPClientConfig returns the default HTTP client configuration.
@@ -63,15 +91,17 @@ func DefaultHTTPClientConfig() *HTTPClientConfig {
 	return &HTTPClientConfig{
 		BaseURL:               constants.ZaiBaseURL,
 		Timeout:               constants.DefaultTimeout,
+		StreamReadTimeout:     constants.DefaultStreamReadTimeout,
 		ConnectTimeout:        constants.DefaultConnectTimeout,
 		MaxIdleConns:          constants.DefaultMaxIdleConns,
 		MaxIdleConnsPerHost:   constants.DefaultMaxIdleConnsPerHost,
 		IdleConnTimeout:       constants.DefaultIdleConnTimeout,
-		TLSHandshakeTimeout:   10 * time.Second,
+		TLSHandshakeTimeout:   constants.DefaultTLSHandshakeTimeout,
 		ExpectContinueTimeout: 1 * time.Second,
 		DisableKeepAlives:     false,
 		DisableCompression:    false,
 		MaxConnsPerHost:       0, // 0 means no limit
+		ForceAttemptHTTP2:     true,
 	}
 }
 
@@ -88,6 +118,16 @@ type HTTPClient struct {
 	requestMiddlewares  []RequestMiddleware
 	responseMiddlewares []ResponseMiddleware
 	logger              *logger.Logger
+	userHooks           []hooks.Hooks
+	hooks               hooks.Hooks
+	redactor            *hooks.Redactor
+	limiter             *ratelimit.Limiter
+	breaker             *CircuitBreaker
+	tracer              telemetry.Tracer
+
+	// sharedTransport is true when the transport is owned by another client
+	// or by the user.
+	sharedTransport bool
 }
 
 // NewHTTPClient creates a new HTTP client with the given configuration.
@@ -96,8 +136,31 @@ func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
 		config = DefaultHTTPClientConfig()
 	}
 
+	// The request and stream read timeouts are applied per request, since
+	// a client timeout would also end streams that are still receiving
+	httpClient := config.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Transport: config.Transport}
+	}
+
+	c := &HTTPClient{
+		client:              httpClient,
+		config:              config,
+		requestMiddlewares:  make([]RequestMiddleware, 0),
+		responseMiddlewares: make([]ResponseMiddleware, 0),
+		logger:              logger.Default(),
+		redactor:            hooks.NewRedactor(false),
+		sharedTransport:     config.HTTPClient != nil || config.Transport != nil,
+	}
+	c.rebuildHooks()
+
+	if c.sharedTransport {
+		return c
+	}
+
 	// Create custom transport
 	transport := &http.Transport{
+		Proxy: config.Proxy,
 		DialContext: (&net.Dialer{
 			Timeout:   config.ConnectTimeout,
 			KeepAlive: 30 * time.Second,
@@ -110,28 +173,91 @@ func NewHTTPClient(config *HTTPClientConfig) *HTTPClient {
 		ExpectContinueTimeout: config.ExpectContinueTimeout,
 		DisableKeepAlives:     config.DisableKeepAlives,
 		DisableCompression:    config.DisableCompression,
+		ForceAttemptHTTP2:     config.ForceAttemptHTTP2,
 	}
 
 	// Apply custom TLS config if provided
 	if config.TLSConfig != nil {
 		transport.TLSClientConfig = config.TLSConfig
 	}
+	c.client.Transport = transport
 
-	return &HTTPClient{
-		client: &http.Client{
-			Transport: transport,
-			Timeout:   config.Timeout,
-		},
-		config:              config,
-		requestMiddlewares:  make([]RequestMiddleware, 0),
-		responseMiddlewares: make([]ResponseMiddleware, 0),
-		logger:              logger.Default(),
+	return c
+}
+
+// Transport returns the transport that holds the connection pool.
+func (c *HTTPClient) Transport() http.RoundTripper {
+	if c.client.Transport == nil {
+		return http.DefaultTransport
 	}
+	return c.client.Transport
 }
 
 // SetLogger sets a custom logger for the HTTP client.
 func (c *HTTPClient) SetLogger(l *logger.Logger) {
 	c.logger = l
+	c.rebuildHooks()
+}
+
+// AddHooks registers lifecycle hooks that are called for every request.
+// Hooks are called in registration order, after the built-in logging hooks.
+func (c *HTTPClient) AddHooks(h hooks.Hooks) {
+	c.userHooks = append(c.userHooks, h)
+	c.rebuildHooks()
+}
+
+// Hooks returns the lifecycle hooks of the client, including the built-in logging hooks.
+func (c *HTTPClient) Hooks() hooks.Hooks {
+	return c.hooks
+}
+
+// SetRedactor sets the redactor used to mask credentials in hook events.
+func (c *HTTPClient) SetRedactor(r *hooks.Redactor) {
+	c.redactor = r
+}
+
+// Redactor returns the redactor used to mask credentials in hook events.
+func (c *HTTPClient) Redactor() *hooks.Redactor {
+	return c.redactor
+}
+
+// SetRateLimiter sets a limiter that every request must pass before being sent.
+// A nil limiter disables client-side rate limiting.
+func (c *HTTPClient) SetRateLimiter(l *ratelimit.Limiter) {
+	c.limiter = l
+}
+
+// RateLimiter returns the client-side rate limiter, or nil if none is set.
+func (c *HTTPClient) RateLimiter() *ratelimit.Limiter {
+	return c.limiter
+}
+
+// SetCircuitBreaker sets a breaker that fails requests fast while their
+// host is failing. A nil breaker disables it.
+func (c *HTTPClient) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// CircuitBreaker returns the circuit breaker, or nil if none is set.
+func (c *HTTPClient) CircuitBreaker() *CircuitBreaker {
+	return c.breaker
+}
+
+// SetTracer sets the tracer that starts a span for every attempt.
+// A nil tracer disables tracing.
+func (c *HTTPClient) SetTracer(t telemetry.Tracer) {
+	c.tracer = t
+}
+
+// Tracer returns the tracer, or nil if none is set.
+func (c *HTTPClient) Tracer() telemetry.Tracer {
+	return c.tracer
+}
+
+// rebuildHooks combines the logging hooks with the user-provided hooks.
+func (c *HTTPClient) rebuildHooks() {
+	combined := hooks.Multi{hooks.NewLoggingHooks(c.logger)}
+	c.hooks = append(combined, c.userHooks...)
 }
 
 // AddRequestMiddleware adds a middleware to process requests before they're sent.
@@ -153,26 +279,62 @@ func (c *HTTPClient) NewRequest(ctx context.Context, method, path string, body i
 		return nil, fmt.Errorf("failed to build URL: %w", err)
 	}
 
-	// Create the request
+	// Create the request; its error quotes the URL, which may carry
+	// credentials
 	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", c.redactor.Error(err))
 	}
 
 	// Set default headers
 	c.setDefaultHeaders(req)
 
+	// Identify the call; retries repeat the ID, and a per-request header
+	// from the context replaces it
+	req.Header.Set(constants.HeaderRequestID, NewRequestID())
+
 	return req, nil
 }
 
 // Do executes an HTTP request and returns the response.
-// The response body must be closed by the caller.
+// The response body must be closed by the caller. The request timeout
+// bounds the request until the body is read.
 func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.do(ctx, req, c.client, sendRequest)
+}
+
+// DoStream executes a streaming request like Do, but bounds the wait for
+// the response headers and every read of the body by the stream read
+// timeout instead of the request timeout, so that a stream may last as
+// long as it keeps receiving.
+func (c *HTTPClient) DoStream(ctx context.Context, req *http.Request) (*http.Response, error) {
+	return c.do(ctx, req, c.client, sendStream)
+}
+
+// Upgrade executes a protocol upgrade request, such as a WebSocket
+// handshake, like Do but without the request timeout or the timeout of a
+// custom client, which would close the upgraded connection once they
+// expire.
+func (c *HTTPClient) Upgrade(ctx context.Context, req *http.Request) (*http.Response, error) {
+	client := *c.client
+	client.Timeout = 0
+	return c.do(ctx, req, &client, sendUpgrade)
+}
+
+// do executes req with client, with the timeouts of mode.
+func (c *HTTPClient) do(ctx context.Context, req *http.Request, client *http.Client, mode sendMode) (resp *http.Response, err error) {
 	// Ensure the request has a context
 	if req.Context() == nil {
 		req = req.WithContext(ctx)
 	}
 
+	// Apply per-request headers and query parameters from the context,
+	// which are meant for the API only
+	external := isExternal(ctx)
+	if !external {
+		applyContextOverrides(ctx, req)
+	}
+
 	// Apply request middlewares
 	for _, middleware := range c.requestMiddlewares {
 		if err := middleware(req); err != nil {
@@ -180,24 +342,70 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 		}
 	}
 
-	// Log the request
-	if c.logger != nil {
-		c.logger.DebugContext(ctx, "HTTP request",
-			slog.String("method", req.Method),
-			slog.String("url", req.URL.String()),
-		)
+	// Fail fast while the circuit of the host is open
+	if c.breaker != nil {
+		done, openErr := c.breaker.allow(req.URL.Host)
+		if openErr != nil {
+			return nil, openErr
+		}
+		defer func() { done(circuitOutcome(ctx, resp, err)) }()
+	}
+
+	// Wait for the client-side rate limiter
+	if c.limiter != nil && !external {
+		release, err := c.limiter.Acquire(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("rate limiter: %w", err)
+		}
+		defer release()
+	}
+
+	attempt := hooks.AttemptFromContext(ctx)
+
+	// Trace the attempt, propagating the trace context in the headers
+	if c.tracer != nil {
+		var span telemetry.AttemptSpan
+		ctx, span = c.tracer.StartAttempt(ctx, req, attempt)
+		req = req.WithContext(ctx)
+		defer func() {
+			if err != nil {
+				span.End(0, err)
+			} else {
+				span.End(resp.StatusCode, nil)
+			}
+		}()
 	}
 
+	outgoingID := req.Header.Get(constants.HeaderRequestID)
+	c.hooks.OnRequest(ctx, &hooks.RequestEvent{
+		Method:    req.Method,
+		Path:      req.URL.Path,
+		Attempt:   attempt,
+		RequestID: outgoingID,
+		Header:    c.redactor.Header(req.Header),
+		Body:      c.requestBody(req),
+	})
+
 	// Execute the request
-	resp, err := c.client.Do(req)
+	guard, req := c.guard(req, mode)
+	start := time.Now()
+	resp, err = client.Do(req)
+	latency := time.Since(start)
 	if err != nil {
-		if c.logger != nil {
-			c.logger.ErrorContext(ctx, "HTTP request failed",
-				slog.String("error", err.Error()),
-			)
-		}
+		err = guard.fail(err)
+
+		// The error quotes the URL, which may carry credentials
+		err = c.redactor.Error(err)
+		c.hooks.OnError(ctx, &hooks.ErrorEvent{
+			Method:    req.Method,
+			Path:      req.URL.Path,
+			Attempt:   attempt,
+			RequestID: outgoingID,
+			Err:       err,
+		})
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
+	resp.Body = guard.body(resp.Body)
 
 	// Apply response middlewares
 	for _, middleware := range c.responseMiddlewares {
@@ -207,19 +415,50 @@ func (c *HTTPClient) Do(ctx context.Context, req *http.Request) (*http.Response,
 		}
 	}
 
-	// Log the response
-	if c.logger != nil {
-		c.logger.DebugContext(ctx, "HTTP response",
-			slog.Int("status_code", resp.StatusCode),
-			slog.String("status", resp.Status),
-		)
+	requestID := resp.Header.Get(constants.HeaderRequestID)
+	if requestID == "" {
+		requestID = resp.Header.Get("Request-ID")
 	}
+	if requestID == "" {
+		requestID = outgoingID
+	}
+
+	c.hooks.OnResponse(ctx, &hooks.ResponseEvent{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		Attempt:    attempt,
+		StatusCode: resp.StatusCode,
+		Latency:    latency,
+		RequestID:  requestID,
+	})
 
 	return resp, nil
 }
 
+// requestBody returns the masked JSON request body for hook events.
+// Returns nil unless body logging is enabled and the body can be replayed.
+func (c *HTTPClient) requestBody(req *http.Request) []byte {
+	if !c.redactor.LogBodies || req.GetBody == nil {
+		return nil
+	}
+	if !strings.HasPrefix(req.Header.Get(constants.HeaderContentType), constants.ContentTypeJSON) {
+		return nil
+	}
+
+	data, err := compress.ReadBody(req)
+	if err != nil {
+		return nil
+	}
+
+	return c.redactor.Body(data)
+}
+
 // Close closes idle connections in the HTTP client.
+// A shared transport is left untouched, since its owner may still use it.
 func (c *HTTPClient) Close() {
+	if c.sharedTransport {
+		return
+	}
 	c.client.CloseIdleConnections()
 }
 
@@ -231,9 +470,10 @@ func (c *HTTPClient) buildURL(path string) (string, error) {
 	}
 
 	// Parse the base URL
+	// The error quotes the base URL, which may carry credentials
 	baseURL, err := url.Parse(c.config.BaseURL)
 	if err != nil {
-		return "", fmt.Errorf("invalid base URL: %w", err)
+		return "", fmt.Errorf("invalid base URL: %w", c.redactor.Error(err))
 	}
 
 	// Ensure base URL ends with /
@@ -423,6 +423,60 @@ func TestHTTPClient_Close(t *testing.T) {
 	client.Close()
 }
 
+// closeCountingTransport counts CloseIdleConnections calls.
+type closeCountingTransport struct {
+	http.RoundTripper
+	closed int
+}
+
+func (t *closeCountingTransport) CloseIdleConnections() {
+	t.closed++
+}
+
+func TestHTTPClient_SharedTransport(t *testing.T) {
+	t.Parallel()
+
+	owner := NewHTTPClient(nil)
+	shared := &closeCountingTransport{RoundTripper: owner.Transport()}
+
+	config := DefaultHTTPClientConfig()
+	config.Transport = shared
+	client := NewHTTPClient(config)
+
+	if client.Transport() != shared {
+		t.Error("NewHTTPClient should use the configured transport")
+	}
+
+	client.Close()
+	if shared.closed != 0 {
+		t.Errorf("Close closed idle connections of a shared transport %d time(s)", shared.closed)
+	}
+}
+
+func TestHTTPClient_UserHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	shared := &closeCountingTransport{RoundTripper: http.DefaultTransport}
+	httpClient := &http.Client{Transport: shared}
+
+	config := DefaultHTTPClientConfig()
+	config.HTTPClient = httpClient
+	config.Transport = http.DefaultTransport
+	client := NewHTTPClient(config)
+
+	if client.client != httpClient {
+		t.Error("NewHTTPClient should use the configured HTTP client")
+	}
+	if client.Transport() != shared {
+		t.Error("HTTPClient should take precedence over Transport")
+	}
+
+	client.Close()
+	if shared.closed != 0 {
+		t.Errorf("Close closed idle connections of a user client %d time(s)", shared.closed)
+	}
+}
+
 func TestHTTPClient_SetDefaultHeaders(t *testing.T) {
 	t.Parallel()
 
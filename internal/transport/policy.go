@@ -0,0 +1,113 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+
+	zaierrors "github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// NonIdempotentRetry controls when requests with non-idempotent methods,
+// such as POST, are retried after a transport error.
+type NonIdempotentRetry int
+
+const (
+	// NonIdempotentRetryDefault inherits the client setting.
+	NonIdempotentRetryDefault NonIdempotentRetry = iota
+
+	// NonIdempotentRetryNever never retries non-idempotent requests.
+	NonIdempotentRetryNever
+
+	// NonIdempotentRetryPreRequest retries only when the error shows the
+	// request never reached the server, e.g. connection refused or a DNS
+	// failure.
+	NonIdempotentRetryPreRequest
+
+	// NonIdempotentRetryAlways also retries ambiguous errors where the
+	// server may have received the request, such as a reset connection.
+	NonIdempotentRetryAlways
+)
+
+// RetryPolicy overrides retry behavior. Zero fields inherit the
+// client setting.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// 1 disables retries.
+	MaxAttempts int
+
+	// InitialBackoff is the backoff before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the backoff between retries.
+	MaxBackoff time.Duration
+
+	// NonIdempotent controls retries of non-idempotent requests after
+	// transport errors. Retryable status codes are only retried for
+	// idempotent requests, and requests with an Idempotency-Key header,
+	// regardless of this setting.
+	NonIdempotent NonIdempotentRetry
+}
+
+// Apply returns a copy of config with the policy's non-zero fields applied.
+func (p RetryPolicy) Apply(config *RetryConfig) *RetryConfig {
+	out := *config
+	if p.MaxAttempts > 0 {
+		out.MaxRetries = p.MaxAttempts - 1
+	}
+	if p.InitialBackoff > 0 {
+		out.InitialBackoff = p.InitialBackoff
+	}
+	if p.MaxBackoff > 0 {
+		out.MaxBackoff = p.MaxBackoff
+	}
+	if p.NonIdempotent != NonIdempotentRetryDefault {
+		out.NonIdempotent = p.NonIdempotent
+	}
+	return &out
+}
+
+// ContextWithRetryPolicy returns a copy of ctx carrying a per-request retry policy.
+func ContextWithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyContextKey, policy)
+}
+
+// RetryPolicyFromContext returns the retry policy attached to ctx, if any.
+func RetryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	if ctx == nil {
+		return RetryPolicy{}, false
+	}
+	policy, ok := ctx.Value(retryPolicyContextKey).(RetryPolicy)
+	return policy, ok
+}
+
+// IsPreRequestError reports whether err shows that the request was never
+// sent to the server, making it safe to retry any request.
+func IsPreRequestError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var connectErr *zaierrors.ConnectTimeoutError
+	if errors.As(err, &connectErr) {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,230 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+)
+
+func TestRetryPolicy_Apply(t *testing.T) {
+	t.Parallel()
+
+	base := DefaultRetryConfig()
+	base.NonIdempotent = NonIdempotentRetryNever
+
+	t.Run("zero policy inherits", func(t *testing.T) {
+		t.Parallel()
+
+		got := RetryPolicy{}.Apply(base)
+		if got == base {
+			t.Error("Apply() should return a copy")
+		}
+		if got.MaxRetries != base.MaxRetries || got.InitialBackoff != base.InitialBackoff ||
+			got.MaxBackoff != base.MaxBackoff || got.NonIdempotent != base.NonIdempotent {
+			t.Errorf("Apply() = %+v, want %+v", got, base)
+		}
+	})
+
+	t.Run("non-zero fields override", func(t *testing.T) {
+		t.Parallel()
+
+		got := RetryPolicy{
+			MaxAttempts:    1,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     2 * time.Millisecond,
+			NonIdempotent:  NonIdempotentRetryPreRequest,
+		}.Apply(base)
+
+		if got.MaxRetries != 0 {
+			t.Errorf("MaxRetries = %d, want 0", got.MaxRetries)
+		}
+		if got.InitialBackoff != time.Millisecond || got.MaxBackoff != 2*time.Millisecond {
+			t.Errorf("backoff = %v..%v, want 1ms..2ms", got.InitialBackoff, got.MaxBackoff)
+		}
+		if got.NonIdempotent != NonIdempotentRetryPreRequest {
+			t.Errorf("NonIdempotent = %v, want PreRequest", got.NonIdempotent)
+		}
+		if base.MaxRetries == 0 {
+			t.Error("base config was modified")
+		}
+	})
+}
+
+func TestContextWithRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	if _, ok := RetryPolicyFromContext(context.Background()); ok {
+		t.Error("expected no policy on background context")
+	}
+
+	want := RetryPolicy{MaxAttempts: 2}
+	got, ok := RetryPolicyFromContext(ContextWithRetryPolicy(context.Background(), want))
+	if !ok || got != want {
+		t.Errorf("RetryPolicyFromContext() = %+v, %v, want %+v, true", got, ok, want)
+	}
+}
+
+func TestIsPreRequestError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"dns failure", &net.DNSError{Err: "no such host", Name: "api.invalid"}, true},
+		{"connection refused", &net.OpError{Op: "read", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, true},
+		{"dial error", &net.OpError{Op: "dial", Err: errors.New("i/o timeout")}, true},
+		{"wrapped dial error", fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: errors.New("x")}), true},
+		{"connection reset", &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}, false},
+		{"unexpected EOF", io.ErrUnexpectedEOF, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsPreRequestError(tt.err); got != tt.want {
+				t.Errorf("IsPreRequestError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// retryCounter counts retries reported through hooks.
+type retryCounter struct {
+	hooks.NoopHooks
+	retries atomic.Int32
+}
+
+func (r *retryCounter) OnRetry(context.Context, *hooks.RetryEvent) {
+	r.retries.Add(1)
+}
+
+// newPolicyTestClient creates a retry client with fast backoff.
+func newPolicyTestClient(baseURL string, config *RetryConfig) (*RetryableHTTPClient, *retryCounter) {
+	httpClient := NewHTTPClient(&HTTPClientConfig{
+		BaseURL: baseURL,
+		Timeout: 5 * time.Second,
+	})
+	counter := &retryCounter{}
+	httpClient.AddHooks(counter)
+
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+	config.InitialBackoff = time.Millisecond
+	config.MaxBackoff = 2 * time.Millisecond
+	return NewRetryableHTTPClient(httpClient, config), counter
+}
+
+func TestDoWithRetry_NonIdempotentPolicy(t *testing.T) {
+	t.Parallel()
+
+	// A closed listener gives a connection refused (pre-request) error
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	refusedURL := "http://" + ln.Addr().String()
+	ln.Close()
+
+	// A server that drops the connection gives an ambiguous error
+	var dropped atomic.Int32
+	dropServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dropped.Add(1)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+	}))
+	t.Cleanup(dropServer.Close)
+
+	tests := []struct {
+		name        string
+		baseURL     string
+		mode        NonIdempotentRetry
+		wantRetries int32
+	}{
+		{"refused default retries", refusedURL, NonIdempotentRetryDefault, 2},
+		{"refused never", refusedURL, NonIdempotentRetryNever, 0},
+		{"refused pre-request", refusedURL, NonIdempotentRetryPreRequest, 2},
+		{"dropped pre-request", dropServer.URL, NonIdempotentRetryPreRequest, 0},
+		{"dropped always", dropServer.URL, NonIdempotentRetryAlways, 2},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, counter := newPolicyTestClient(tt.baseURL, nil)
+			ctx := ContextWithRetryPolicy(context.Background(), RetryPolicy{
+				MaxAttempts:   3,
+				NonIdempotent: tt.mode,
+			})
+
+			req, err := client.GetClient().NewRequest(ctx, http.MethodPost, "/test", strings.NewReader(`{}`))
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+
+			resp, err := client.DoWithRetry(ctx, req)
+			if err == nil {
+				resp.Body.Close()
+				t.Fatal("expected error")
+			}
+			if got := counter.retries.Load(); got != tt.wantRetries {
+				t.Errorf("retries = %d, want %d", got, tt.wantRetries)
+			}
+		})
+	}
+}
+
+func TestDoWithRetry_PolicyPrecedence(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := DefaultRetryConfig()
+	config.MaxRetries = 1
+	client, _ := newPolicyTestClient(server.URL, config)
+
+	ctx := ContextWithRetryPolicy(context.Background(), RetryPolicy{MaxAttempts: 4})
+	req, err := client.GetClient().NewRequest(ctx, http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	resp, err := client.DoWithRetry(ctx, req)
+	if err != nil {
+		t.Fatalf("DoWithRetry failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := attempts.Load(); got != 4 {
+		t.Errorf("attempts = %d, want 4", got)
+	}
+	if config.MaxRetries != 1 {
+		t.Errorf("client config was modified: MaxRetries = %d", config.MaxRetries)
+	}
+}
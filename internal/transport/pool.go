@@ -0,0 +1,66 @@
+package transport
+
+import "time"
+
+// PoolConfig overrides the connection settings of the transport created
+// by NewHTTPClient. Zero fields keep the defaults of
+// DefaultHTTPClientConfig. It has no effect when HTTPClientConfig.Transport
+// or HTTPClientConfig.HTTPClient is set.
+type PoolConfig struct {
+	// MaxIdleConns is the maximum number of idle connections across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections per
+	// host. It should be at least the expected number of concurrent
+	// requests, or connections are closed and reopened under load.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits the total number of connections per host.
+	// Requests over the limit wait for a connection.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long an idle connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// TLSHandshakeTimeout is the maximum duration of a TLS handshake.
+	TLSHandshakeTimeout time.Duration
+
+	// DialTimeout is the maximum duration for establishing a connection.
+	DialTimeout time.Duration
+
+	// DisableHTTP2 keeps connections on HTTP/1.1.
+	DisableHTTP2 bool
+
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+}
+
+// Apply returns a copy of config with the pool's non-zero fields applied.
+func (p PoolConfig) Apply(config *HTTPClientConfig) *HTTPClientConfig {
+	out := *config
+	if p.MaxIdleConns > 0 {
+		out.MaxIdleConns = p.MaxIdleConns
+	}
+	if p.MaxIdleConnsPerHost > 0 {
+		out.MaxIdleConnsPerHost = p.MaxIdleConnsPerHost
+	}
+	if p.MaxConnsPerHost > 0 {
+		out.MaxConnsPerHost = p.MaxConnsPerHost
+	}
+	if p.IdleConnTimeout > 0 {
+		out.IdleConnTimeout = p.IdleConnTimeout
+	}
+	if p.TLSHandshakeTimeout > 0 {
+		out.TLSHandshakeTimeout = p.TLSHandshakeTimeout
+	}
+	if p.DialTimeout > 0 {
+		out.ConnectTimeout = p.DialTimeout
+	}
+	if p.DisableHTTP2 {
+		out.ForceAttemptHTTP2 = false
+	}
+	if p.DisableKeepAlives {
+		out.DisableKeepAlives = true
+	}
+	return &out
+}
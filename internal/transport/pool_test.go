@@ -0,0 +1,171 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+)
+
+func TestPoolConfig_Apply(t *testing.T) {
+	t.Parallel()
+
+	defaults := DefaultHTTPClientConfig()
+
+	// A zero pool keeps the defaults
+	if got := (PoolConfig{}).Apply(defaults); !reflect.DeepEqual(got, defaults) {
+		t.Errorf("zero PoolConfig changed the config: %+v", got)
+	}
+
+	got := PoolConfig{
+		MaxIdleConns:        300,
+		MaxIdleConnsPerHost: 200,
+		MaxConnsPerHost:     250,
+		IdleConnTimeout:     time.Minute,
+		TLSHandshakeTimeout: 3 * time.Second,
+		DialTimeout:         2 * time.Second,
+		DisableHTTP2:        true,
+		DisableKeepAlives:   true,
+	}.Apply(defaults)
+
+	want := *defaults
+	want.MaxIdleConns = 300
+	want.MaxIdleConnsPerHost = 200
+	want.MaxConnsPerHost = 250
+	want.IdleConnTimeout = time.Minute
+	want.TLSHandshakeTimeout = 3 * time.Second
+	want.ConnectTimeout = 2 * time.Second
+	want.ForceAttemptHTTP2 = false
+	want.DisableKeepAlives = true
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("Apply() = %+v, want %+v", *got, want)
+	}
+
+	if defaults.MaxIdleConnsPerHost != constants.DefaultMaxIdleConnsPerHost {
+		t.Error("Apply modified its argument")
+	}
+}
+
+func TestNewHTTPClient_TransportSettings(t *testing.T) {
+	t.Parallel()
+
+	config := PoolConfig{
+		MaxIdleConns:        300,
+		MaxIdleConnsPerHost: 200,
+		MaxConnsPerHost:     250,
+		IdleConnTimeout:     time.Minute,
+		TLSHandshakeTimeout: 3 * time.Second,
+		DisableHTTP2:        true,
+		DisableKeepAlives:   true,
+	}.Apply(DefaultHTTPClientConfig())
+
+	rt, ok := NewHTTPClient(config).Transport().(*http.Transport)
+	if !ok {
+		t.Fatalf("Transport() is not an *http.Transport")
+	}
+	if rt.MaxIdleConns != 300 || rt.MaxIdleConnsPerHost != 200 || rt.MaxConnsPerHost != 250 {
+		t.Errorf("pool sizes = %d/%d/%d, want 300/200/250", rt.MaxIdleConns, rt.MaxIdleConnsPerHost, rt.MaxConnsPerHost)
+	}
+	if rt.IdleConnTimeout != time.Minute {
+		t.Errorf("IdleConnTimeout = %v, want 1m", rt.IdleConnTimeout)
+	}
+	if rt.TLSHandshakeTimeout != 3*time.Second {
+		t.Errorf("TLSHandshakeTimeout = %v, want 3s", rt.TLSHandshakeTimeout)
+	}
+	if rt.ForceAttemptHTTP2 {
+		t.Error("ForceAttemptHTTP2 = true, want false")
+	}
+	if !rt.DisableKeepAlives {
+		t.Error("DisableKeepAlives = false, want true")
+	}
+
+	rt, _ = NewHTTPClient(DefaultHTTPClientConfig()).Transport().(*http.Transport)
+	if !rt.ForceAttemptHTTP2 {
+		t.Error("ForceAttemptHTTP2 is not enabled by default")
+	}
+}
+
+// BenchmarkHTTPClient_Concurrent sends 200 concurrent requests per
+// iteration, with the connection pool of a zero HTTPClientConfig, which
+// keeps only two idle connections per host, and with the SDK defaults.
+// conns/op reports the connections opened per iteration.
+func BenchmarkHTTPClient_Concurrent(b *testing.B) {
+	const concurrency = 200
+
+	benchmarks := []struct {
+		name   string
+		config func(baseURL string) *HTTPClientConfig
+	}{
+		{"zero pool", func(baseURL string) *HTTPClientConfig {
+			return &HTTPClientConfig{BaseURL: baseURL, Timeout: constants.DefaultTimeout, ConnectTimeout: constants.DefaultConnectTimeout}
+		}},
+		{"default pool", func(baseURL string) *HTTPClientConfig {
+			config := DefaultHTTPClientConfig()
+			config.BaseURL = baseURL
+			return config
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			var conns atomic.Int64
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(time.Millisecond)
+				w.Write([]byte(`{"status":"ok"}`))
+			}))
+			server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					conns.Add(1)
+				}
+			}
+			server.Start()
+			defer server.Close()
+
+			client := NewHTTPClient(bm.config(server.URL))
+			defer client.Close()
+
+			ctx := context.Background()
+			round := func() {
+				var wg sync.WaitGroup
+				for j := 0; j < concurrency; j++ {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+						req, err := client.NewRequest(ctx, http.MethodGet, "/ping", nil)
+						if err != nil {
+							b.Error(err)
+							return
+						}
+						resp, err := client.Do(ctx, req)
+						if err != nil {
+							b.Error(err)
+							return
+						}
+						io.Copy(io.Discard, resp.Body)
+						resp.Body.Close()
+					}()
+				}
+				wg.Wait()
+			}
+
+			// Fill the pool first
+			round()
+			conns.Store(0)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				round()
+			}
+			b.StopTimer()
+			b.ReportMetric(float64(conns.Load())/float64(b.N), "conns/op")
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package transport
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestID(t *testing.T) {
+	t.Parallel()
+
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	id := NewRequestID()
+	assert.Regexp(t, uuid, id)
+	assert.NotEqual(t, id, NewRequestID())
+}
@@ -1,7 +1,10 @@
 package transport
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,7 +15,10 @@ import (
 	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // RetryConfig holds configuration for retry behavior.
@@ -34,6 +40,14 @@ type RetryConfig struct {
 
 	// EnableJitter adds randomness to backoff to prevent thundering herd.
 	EnableJitter bool
+
+	// NonIdempotent controls retries of non-idempotent requests after
+	// transport errors. The default retries all network errors.
+	NonIdempotent NonIdempotentRetry
+
+	// Budget limits the share of requests that may be retries.
+	// Nil disables the budget.
+	Budget *RetryBudget
 }
 
 // DefaultRetryConfig returns the default retry configuration.
@@ -75,11 +89,28 @@ func (c *RetryableHTTPClient) SetLogger(l *logger.Logger) {
 
 // DoWithRetry executes an HTTP request with retry logic.
 // It will retry on retryable errors and status codes with exponential backoff.
+// A RetryPolicy attached to ctx takes precedence over the client configuration.
 func (c *RetryableHTTPClient) DoWithRetry(ctx context.Context, req *http.Request) (*http.Response, error) {
 	var lastErr error
 	var resp *http.Response
 
-	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
+	config := c.config
+	if policy, ok := RetryPolicyFromContext(ctx); ok {
+		config = policy.Apply(config)
+	}
+
+	// Requests to other hosts do not take part in the retry budget
+	if config.Budget != nil && isExternal(ctx) {
+		unbudgeted := *config
+		unbudgeted.Budget = nil
+		config = &unbudgeted
+	}
+
+	if config.Budget != nil {
+		config.Budget.RecordRequest()
+	}
+
+	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		// Check if context is cancelled before attempting
 		select {
 		case <-ctx.Done():
@@ -99,24 +130,29 @@ func (c *RetryableHTTPClient) DoWithRetry(ctx context.Context, req *http.Request
 			}
 		}
 
-		// Log retry attempt
-		if attempt > 0 && c.logger != nil {
-			c.logger.InfoContext(ctx, "Retrying HTTP request",
-				slog.Int("attempt", attempt),
-				slog.Int("max_retries", c.config.MaxRetries),
-			)
-		}
-
-		// Execute the request
-		resp, lastErr = c.client.Do(ctx, reqToSend)
+		// Execute the request, tagging it with its attempt number
+		attemptCtx := hooks.WithAttempt(ctx, attempt+1)
+		reqToSend = reqToSend.WithContext(attemptCtx)
+		resp, lastErr = c.client.Do(attemptCtx, reqToSend)
 
 		// Check if we should retry
-		shouldRetry, retryAfter := c.shouldRetry(resp, lastErr, attempt)
+		shouldRetry, retryAfter := c.shouldRetry(ctx, config, req, resp, lastErr, attempt)
 		if !shouldRetry {
 			// Success or non-retryable error
 			return resp, lastErr
 		}
 
+		// Give up if retries would exceed the client's retry budget
+		if config.Budget != nil && !config.Budget.TryRetry() {
+			if c.logger != nil {
+				c.logger.WarnContext(ctx, "Retry budget exhausted",
+					slog.String("method", req.Method),
+					slog.String("path", req.URL.Path),
+				)
+			}
+			return resp, lastErr
+		}
+
 		// Close response body if present (we'll retry)
 		if resp != nil && resp.Body != nil {
 			io.Copy(io.Discard, resp.Body) // Drain the body
@@ -124,15 +160,20 @@ func (c *RetryableHTTPClient) DoWithRetry(ctx context.Context, req *http.Request
 		}
 
 		// Don't sleep after the last attempt
-		if attempt < c.config.MaxRetries {
-			backoff := c.calculateBackoff(attempt, retryAfter)
-
-			if c.logger != nil {
-				c.logger.DebugContext(ctx, "Backing off before retry",
-					slog.Duration("backoff", backoff),
-					slog.Int("attempt", attempt+1),
-				)
+		if attempt < config.MaxRetries {
+			backoff := config.backoff(attempt, retryAfter)
+
+			event := &hooks.RetryEvent{
+				Method:  req.Method,
+				Path:    req.URL.Path,
+				Attempt: attempt + 2,
+				Backoff: backoff,
+				Err:     lastErr,
+			}
+			if resp != nil {
+				event.StatusCode = resp.StatusCode
 			}
+			c.client.Hooks().OnRetry(ctx, event)
 
 			// Sleep with context awareness
 			select {
@@ -147,7 +188,7 @@ func (c *RetryableHTTPClient) DoWithRetry(ctx context.Context, req *http.Request
 	// All retries exhausted
 	if c.logger != nil {
 		c.logger.WarnContext(ctx, "All retry attempts exhausted",
-			slog.Int("max_retries", c.config.MaxRetries),
+			slog.Int("max_retries", config.MaxRetries),
 		)
 	}
 
@@ -156,18 +197,37 @@ func (c *RetryableHTTPClient) DoWithRetry(ctx context.Context, req *http.Request
 
 // shouldRetry determines if a request should be retried based on the response and error.
 // It returns whether to retry and an optional retry-after duration from the response headers.
-func (c *RetryableHTTPClient) shouldRetry(resp *http.Response, err error, attempt int) (bool, time.Duration) {
+func (c *RetryableHTTPClient) shouldRetry(ctx context.Context, config *RetryConfig, req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
 	// Don't retry if we've exhausted all attempts
-	if attempt >= c.config.MaxRetries {
+	if attempt >= config.MaxRetries {
 		return false, 0
 	}
 
 	// If there's an error, retry for network errors
 	if err != nil {
+		// Don't retry while the circuit breaker is open
+		var circuitErr *errors.CircuitOpenError
+		if stderrors.As(err, &circuitErr) {
+			return false, 0
+		}
+
 		// Don't retry on context cancellation
-		if err == context.Canceled || err == context.DeadlineExceeded {
+		if err == context.Canceled || err == context.DeadlineExceeded || ctx.Err() != nil {
 			return false, 0
 		}
+
+		// Non-idempotent requests may already have been processed
+		if !c.isIdempotent(ctx, req) {
+			switch config.NonIdempotent {
+			case NonIdempotentRetryNever:
+				return false, 0
+			case NonIdempotentRetryPreRequest:
+				if !IsPreRequestError(err) {
+					return false, 0
+				}
+			}
+		}
+
 		// Retry on network errors
 		return true, 0
 	}
@@ -177,10 +237,19 @@ func (c *RetryableHTTPClient) shouldRetry(resp *http.Response, err error, attemp
 		return true, 0
 	}
 
-	// Check if the status code is retryable
-	if c.isRetryableStatusCode(resp.StatusCode) {
-		// Check if the HTTP method is idempotent
-		if !c.isIdempotentMethod(resp.Request.Method) {
+	// The provider error code takes precedence over the status code: some
+	// 400 responses are transient, and some 429 responses report an
+	// exhausted quota that retries cannot fix
+	retryable := c.isRetryableStatusCode(resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		if codeRetryable, known := errors.RetryableCode(peekErrorCode(resp)); known {
+			retryable = codeRetryable
+		}
+	}
+
+	if retryable {
+		// Check if the request is idempotent
+		if !c.isIdempotent(ctx, req) {
 			return false, 0
 		}
 
@@ -193,6 +262,33 @@ func (c *RetryableHTTPClient) shouldRetry(resp *http.Response, err error, attemp
 	return false, 0
 }
 
+// maxErrorCodePeek is the number of bytes of an error response body read
+// to find its provider error code.
+const maxErrorCodePeek = 64 << 10
+
+// peekErrorCode returns the provider error code of an error response body,
+// or an empty string if the body is not a JSON error envelope. The bytes
+// read are put back, so that the body can still be read in full.
+func peekErrorCode(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return ""
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorCodePeek))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	if err != nil {
+		return ""
+	}
+
+	var errResp models.ErrorResponse
+	if json.Unmarshal(data, &errResp) != nil {
+		return ""
+	}
+	return errResp.GetCode()
+}
+
 // isRetryableStatusCode checks if a status code is in the list of retryable codes.
 func (c *RetryableHTTPClient) isRetryableStatusCode(statusCode int) bool {
 	for _, code := range c.config.RetryableStatusCodes {
@@ -203,6 +299,17 @@ func (c *RetryableHTTPClient) isRetryableStatusCode(statusCode int) bool {
 	return false
 }
 
+// isIdempotent reports whether req is safe to repeat: its method is
+// idempotent, or it is marked with an Idempotency-Key header, set on the
+// request or as a per-request header.
+func (c *RetryableHTTPClient) isIdempotent(ctx context.Context, req *http.Request) bool {
+	if c.isIdempotentMethod(req.Method) {
+		return true
+	}
+	return req.Header.Get(constants.HeaderIdempotencyKey) != "" ||
+		HeadersFromContext(ctx).Get(constants.HeaderIdempotencyKey) != ""
+}
+
 // isIdempotentMethod checks if an HTTP method is idempotent (safe to retry).
 func (c *RetryableHTTPClient) isIdempotentMethod(method string) bool {
 	// GET, HEAD, OPTIONS, TRACE are always idempotent
@@ -251,21 +358,26 @@ func (c *RetryableHTTPClient) parseRetryAfter(resp *http.Response) time.Duration
 // calculateBackoff calculates the backoff duration for a retry attempt.
 // It uses exponential backoff with optional jitter.
 func (c *RetryableHTTPClient) calculateBackoff(attempt int, retryAfter time.Duration) time.Duration {
+	return c.config.backoff(attempt, retryAfter)
+}
+
+// backoff calculates the backoff duration for a retry attempt under this configuration.
+func (config *RetryConfig) backoff(attempt int, retryAfter time.Duration) time.Duration {
 	// If server provided a Retry-After header and it's reasonable, use it
-	if retryAfter > 0 && retryAfter <= c.config.MaxBackoff {
+	if retryAfter > 0 && retryAfter <= config.MaxBackoff {
 		return retryAfter
 	}
 
 	// Calculate exponential backoff
-	backoff := float64(c.config.InitialBackoff) * math.Pow(c.config.BackoffMultiplier, float64(attempt))
+	backoff := float64(config.InitialBackoff) * math.Pow(config.BackoffMultiplier, float64(attempt))
 
 	// Apply maximum backoff limit
-	if backoff > float64(c.config.MaxBackoff) {
-		backoff = float64(c.config.MaxBackoff)
+	if backoff > float64(config.MaxBackoff) {
+		backoff = float64(config.MaxBackoff)
 	}
 
 	// Add jitter to prevent thundering herd
-	if c.config.EnableJitter {
+	if config.EnableJitter {
 		// Add random jitter between 0% and 25% of the backoff
 		jitter := backoff * 0.25 * rand.Float64()
 		backoff += jitter
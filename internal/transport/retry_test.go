@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -162,6 +163,71 @@ func TestRetryableHTTPClient_RetryOn500(t *testing.T) {
 	}
 }
 
+func TestRetryableHTTPClient_ErrorCodes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		statusCode   int
+		body         string
+		wantAttempts int
+	}{
+		{"transient code on 400", http.StatusBadRequest, `{"error":{"code":"1234","message":"network error"}}`, 3},
+		{"quota code on 429", http.StatusTooManyRequests, `{"error":{"code":"1113","message":"insufficient balance"}}`, 1},
+		{"rate limit code on 429", http.StatusTooManyRequests, `{"error":{"code":"1302","message":"too many requests"}}`, 3},
+		{"unknown code on 429", http.StatusTooManyRequests, `{"error":{"code":"9999","message":"slow down"}}`, 3},
+		{"parameter code on 400", http.StatusBadRequest, `{"error":{"code":"1210","message":"invalid parameter"}}`, 1},
+		{"no code on 400", http.StatusBadRequest, `bad request`, 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			httpClient := NewHTTPClient(&HTTPClientConfig{BaseURL: server.URL, Timeout: 10 * time.Second})
+			config := DefaultRetryConfig()
+			config.MaxRetries = 2
+			config.InitialBackoff = time.Millisecond
+			config.MaxBackoff = time.Millisecond
+			retryClient := NewRetryableHTTPClient(httpClient, config)
+
+			ctx := context.Background()
+			req, err := httpClient.NewRequest(ctx, http.MethodGet, "/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest failed: %v", err)
+			}
+
+			resp, err := retryClient.DoWithRetry(ctx, req)
+			if err != nil {
+				t.Fatalf("DoWithRetry failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+
+			// The body read to find the code is still readable
+			data, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("ReadAll failed: %v", err)
+			}
+			if string(data) != tt.body {
+				t.Errorf("body = %q, want %q", data, tt.body)
+			}
+		})
+	}
+}
+
 func TestRetryableHTTPClient_ExhaustRetries(t *testing.T) {
 	t.Parallel()
 
@@ -282,6 +348,60 @@ func TestRetryableHTTPClient_NoRetryOnPOST(t *testing.T) {
 	}
 }
 
+func TestRetryableHTTPClient_RetryPOSTWithIdempotencyKey(t *testing.T) {
+	t.Parallel()
+
+	attempts := 0
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	httpClient := NewHTTPClient(&HTTPClientConfig{
+		BaseURL: server.URL,
+		Timeout: 5 * time.Second,
+	})
+	retryClient := NewRetryableHTTPClient(httpClient, &RetryConfig{
+		MaxRetries:           2,
+		InitialBackoff:       time.Millisecond,
+		MaxBackoff:           time.Millisecond,
+		BackoffMultiplier:    2,
+		RetryableStatusCodes: []int{http.StatusServiceUnavailable},
+	})
+
+	ctx := ContextWithHeaders(context.Background(), http.Header{"Idempotency-Key": {"upload-1"}})
+	req, err := httpClient.NewRequest(ctx, http.MethodPost, "/test", strings.NewReader(`{"data": "test"}`))
+	if err != nil {
+		t.Fatalf("NewRequest failed: %v", err)
+	}
+
+	resp, err := retryClient.DoWithRetry(ctx, req)
+	if err != nil {
+		t.Fatalf("DoWithRetry failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts (POST marked idempotent), got %d", attempts)
+	}
+	for i, body := range bodies {
+		if body != `{"data": "test"}` {
+			t.Errorf("Attempt %d sent body %q", i+1, body)
+		}
+	}
+}
+
 func TestRetryableHTTPClient_RetryAfterHeader(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,198 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	stderrors "errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// sendMode selects the timeouts that apply to a request.
+type sendMode int
+
+const (
+	// sendRequest bounds the whole request, including reading the body,
+	// by the request timeout.
+	sendRequest sendMode = iota
+
+	// sendStream bounds the wait for the response headers and every read
+	// of the body by the stream read timeout.
+	sendStream
+
+	// sendUpgrade applies only the connect timeout, since the upgraded
+	// connection stays open.
+	sendUpgrade
+)
+
+// Causes of the cancellation of a request by its timeouts.
+var (
+	errRequestTimeout    = stderrors.New("request timeout")
+	errStreamReadTimeout = stderrors.New("stream read timeout")
+)
+
+// timeoutGuard applies the request or stream read timeout to one attempt
+// and reports which phase timed out. Its timer is stopped when the
+// attempt fails or its response body is closed.
+type timeoutGuard struct {
+	parent  context.Context
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	request *http.Request
+	mode    sendMode
+
+	// timeout is the request or stream read timeout, and timer cancels
+	// the attempt when it expires. timer is nil without a timeout.
+	timeout time.Duration
+	timer   *time.Timer
+
+	connectTimeout      time.Duration
+	tlsHandshakeTimeout time.Duration
+	connected           atomic.Bool
+	handshaking         atomic.Bool
+}
+
+// guard starts the timeouts of an attempt of req in mode and returns the
+// request to send, bound to them.
+func (c *HTTPClient) guard(req *http.Request, mode sendMode) (*timeoutGuard, *http.Request) {
+	g := &timeoutGuard{
+		parent: req.Context(),
+		mode:   mode,
+	}
+	// An upgraded connection outlives the request, so it keeps the
+	// caller's context
+	if mode == sendUpgrade {
+		g.ctx, g.cancel = g.parent, func(error) {}
+	} else {
+		g.ctx, g.cancel = context.WithCancelCause(g.parent)
+	}
+
+	// The connect timeouts of a shared transport are not known
+	if !c.sharedTransport {
+		g.connectTimeout = c.config.ConnectTimeout
+		g.tlsHandshakeTimeout = c.config.TLSHandshakeTimeout
+	}
+
+	// Only the client's own timeouts apply to a custom HTTP client
+	if c.config.HTTPClient == nil {
+		switch mode {
+		case sendRequest:
+			g.timeout = c.config.Timeout
+		case sendStream:
+			g.timeout = c.config.StreamReadTimeout
+		}
+	}
+	if g.timeout > 0 {
+		cause := errRequestTimeout
+		if mode == sendStream {
+			cause = errStreamReadTimeout
+		}
+		g.timer = time.AfterFunc(g.timeout, func() { g.cancel(cause) })
+	}
+
+	// Track the connection, to tell connect timeouts from the others
+	ctx := httptrace.WithClientTrace(g.ctx, &httptrace.ClientTrace{
+		TLSHandshakeStart: func() { g.handshaking.Store(true) },
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			// A failed handshake leaves the flag to classify its error
+			if err == nil {
+				g.handshaking.Store(false)
+			}
+		},
+		GotConn: func(httptrace.GotConnInfo) { g.connected.Store(true) },
+	})
+	g.request = req.WithContext(ctx)
+	return g, g.request
+}
+
+// fail stops the timeouts of a failed attempt and returns err, or the
+// timeout error of the phase that timed out.
+func (g *timeoutGuard) fail(err error) error {
+	err = g.classify(err)
+	g.release()
+	return err
+}
+
+// body wraps the body of a successful attempt so that reads are bounded
+// by the timeouts and closing it stops them. The stream read timeout
+// stops while the headers are being handled and restarts with every read.
+// The body of an upgrade, the connection itself, is returned as is.
+func (g *timeoutGuard) body(body io.ReadCloser) io.ReadCloser {
+	if g.mode == sendUpgrade {
+		return body
+	}
+	if g.mode == sendStream && g.timer != nil {
+		g.timer.Stop()
+	}
+	return &guardedBody{ReadCloser: body, guard: g}
+}
+
+// release stops the timer and releases the context of the attempt.
+func (g *timeoutGuard) release() {
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	g.cancel(nil)
+}
+
+// classify returns the timeout error of the phase that caused err, or err
+// if no timeout of the client did. A deadline or cancellation of the
+// caller's context is returned as is: the tighter deadline wins.
+func (g *timeoutGuard) classify(err error) error {
+	if err == nil || g.parent.Err() != nil {
+		return err
+	}
+
+	switch context.Cause(g.ctx) {
+	case errRequestTimeout:
+		return errors.NewRequestTimeoutError(g.request, g.timeout)
+	case errStreamReadTimeout:
+		return errors.NewStreamReadTimeoutError(g.request, g.timeout)
+	}
+
+	var netErr net.Error
+	if !g.connected.Load() && stderrors.As(err, &netErr) && netErr.Timeout() {
+		timeout := g.connectTimeout
+		if g.handshaking.Load() {
+			timeout = g.tlsHandshakeTimeout
+		}
+		return errors.NewConnectTimeoutError(g.request, timeout)
+	}
+	return err
+}
+
+// guardedBody is a response body bounded by the timeouts of its attempt.
+type guardedBody struct {
+	io.ReadCloser
+	guard *timeoutGuard
+}
+
+// Read reads from the body, restarting the stream read timeout.
+func (b *guardedBody) Read(p []byte) (int, error) {
+	g := b.guard
+	stream := g.mode == sendStream && g.timer != nil
+	if stream {
+		g.timer.Reset(g.timeout)
+	}
+	n, err := b.ReadCloser.Read(p)
+	if stream {
+		g.timer.Stop()
+	}
+	if err != nil && err != io.EOF {
+		err = g.classify(err)
+	}
+	return n, err
+}
+
+// Close closes the body and stops the timeouts.
+func (b *guardedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.guard.release()
+	return err
+}
@@ -0,0 +1,269 @@
+package transport
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// stallingListener accepts connections and never answers, like a server
+// that hangs in the TLS handshake.
+func stallingListener(t *testing.T) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() failed: %v", err)
+	}
+	go func() {
+		var conns []net.Conn
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				for _, conn := range conns {
+					conn.Close()
+				}
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+	return ln.Addr().String()
+}
+
+// newTimeoutTestClient returns a client of url with the given request and
+// stream read timeouts.
+func newTimeoutTestClient(url string, timeout, streamReadTimeout time.Duration) *HTTPClient {
+	config := DefaultHTTPClientConfig()
+	config.BaseURL = url
+	config.Timeout = timeout
+	config.StreamReadTimeout = streamReadTimeout
+	return NewHTTPClient(config)
+}
+
+func TestHTTPClient_ConnectTimeout(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultHTTPClientConfig()
+	config.BaseURL = "https://" + stallingListener(t)
+	config.TLSHandshakeTimeout = 100 * time.Millisecond
+	client := NewHTTPClient(config)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	start := time.Now()
+	_, err = client.Do(context.Background(), req)
+	var timeoutErr *errors.ConnectTimeoutError
+	if !stderrors.As(err, &timeoutErr) {
+		t.Fatalf("Do() error = %v, want a ConnectTimeoutError", err)
+	}
+	if timeoutErr.Phase != errors.TimeoutPhaseConnect || timeoutErr.Timeout != 100*time.Millisecond {
+		t.Errorf("Phase, Timeout = %q, %v, want connect, 100ms", timeoutErr.Phase, timeoutErr.Timeout)
+	}
+	if !errors.IsTimeoutError(err) {
+		t.Error("a ConnectTimeoutError should be an APITimeoutError")
+	}
+	if !IsPreRequestError(err) {
+		t.Error("a connect timeout should be a pre-request error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Do() took %v", elapsed)
+	}
+}
+
+func TestHTTPClient_RequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Send the headers and part of the body, then stall
+		fmt.Fprint(w, `{"partial":`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := newTimeoutTestClient(server.URL, 100*time.Millisecond, 0)
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	var timeoutErr *errors.RequestTimeoutError
+	if !stderrors.As(err, &timeoutErr) {
+		t.Fatalf("ReadAll() error = %v, want a RequestTimeoutError", err)
+	}
+	if timeoutErr.Phase != errors.TimeoutPhaseRequest || timeoutErr.Timeout != 100*time.Millisecond {
+		t.Errorf("Phase, Timeout = %q, %v, want request, 100ms", timeoutErr.Phase, timeoutErr.Timeout)
+	}
+	if IsPreRequestError(err) {
+		t.Error("a request timeout should not be a pre-request error")
+	}
+}
+
+func TestHTTPClient_StreamReadTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := range 5 {
+			fmt.Fprintf(w, "data: %d\n\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(40 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	// The stream outlasts the request timeout, which does not apply to it
+	client := newTimeoutTestClient(server.URL, 50*time.Millisecond, 150*time.Millisecond)
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/stream", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+
+	resp, err := client.DoStream(context.Background(), req)
+	if err != nil {
+		t.Fatalf("DoStream() failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	var timeoutErr *errors.StreamReadTimeoutError
+	if !stderrors.As(err, &timeoutErr) {
+		t.Fatalf("ReadAll() error = %v, want a StreamReadTimeoutError", err)
+	}
+	if timeoutErr.Phase != errors.TimeoutPhaseStreamRead || timeoutErr.Timeout != 150*time.Millisecond {
+		t.Errorf("Phase, Timeout = %q, %v, want stream read, 150ms", timeoutErr.Phase, timeoutErr.Timeout)
+	}
+	if want := "data: 0\n\ndata: 1\n\ndata: 2\n\ndata: 3\n\ndata: 4\n\n"; string(data) != want {
+		t.Errorf("data = %q, want %q", data, want)
+	}
+}
+
+func TestHTTPClient_ContextDeadlineWins(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	t.Cleanup(server.Close)
+
+	client := newTimeoutTestClient(server.URL, 5*time.Second, 5*time.Second)
+
+	for name, do := range map[string]func(context.Context, *http.Request) (*http.Response, error){
+		"request": client.Do,
+		"stream":  client.DoStream,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			req, err := client.NewRequest(ctx, http.MethodGet, "/test", nil)
+			if err != nil {
+				t.Fatalf("NewRequest() failed: %v", err)
+			}
+			resp, err := do(ctx, req)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			_, err = io.ReadAll(resp.Body)
+			if !stderrors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("ReadAll() error = %v, want context.DeadlineExceeded", err)
+			}
+			if errors.IsTimeoutError(err) {
+				t.Error("the deadline of the context should not be reported as a client timeout")
+			}
+		})
+	}
+}
+
+func TestHTTPClient_TimeoutReleased(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client := newTimeoutTestClient(server.URL, time.Minute, time.Minute)
+	for name, do := range map[string]func(context.Context, *http.Request) (*http.Response, error){
+		"request": client.Do,
+		"stream":  client.DoStream,
+	} {
+		req, err := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+		if err != nil {
+			t.Fatalf("NewRequest() failed: %v", err)
+		}
+		resp, err := do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+		if _, err := io.ReadAll(resp.Body); err != nil {
+			t.Fatalf("%s: ReadAll() failed: %v", name, err)
+		}
+		resp.Body.Close()
+
+		guard := resp.Body.(*guardedBody).guard
+		if guard.timer.Stop() {
+			t.Errorf("%s: the timer is still running after Close", name)
+		}
+		if guard.ctx.Err() == nil {
+			t.Errorf("%s: the context is not released after Close", name)
+		}
+	}
+}
+
+func TestHTTPClient_CustomClientTimeouts(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	// The timeouts of the config do not apply to a custom client
+	config := DefaultHTTPClientConfig()
+	config.BaseURL = server.URL
+	config.Timeout = 10 * time.Millisecond
+	config.HTTPClient = &http.Client{}
+	client := NewHTTPClient(config)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/test", nil)
+	if err != nil {
+		t.Fatalf("NewRequest() failed: %v", err)
+	}
+	resp, err := client.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if data, err := io.ReadAll(resp.Body); err != nil || string(data) != "ok" {
+		t.Errorf("ReadAll() = %q, %v, want ok", data, err)
+	}
+}
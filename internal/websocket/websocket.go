@@ -0,0 +1,374 @@
+// Package websocket implements the WebSocket protocol (RFC 6455) on top of
+// net/http for the realtime APIs. It supports what those APIs need:
+// unfragmented writes, fragmented reads, ping and pong, and the close
+// handshake. Extensions and subprotocols are not supported.
+package websocket
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// acceptGUID is appended to the handshake key to compute the accept key.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcode is the type of a frame.
+type Opcode byte
+
+// Opcodes defined by RFC 6455.
+const (
+	OpContinuation Opcode = 0x0
+	OpText         Opcode = 0x1
+	OpBinary       Opcode = 0x2
+	OpClose        Opcode = 0x8
+	OpPing         Opcode = 0x9
+	OpPong         Opcode = 0xA
+)
+
+// Close codes defined by RFC 6455.
+const (
+	CloseNormal        = 1000
+	CloseGoingAway     = 1001
+	CloseProtocolError = 1002
+	CloseNoStatus      = 1005
+	CloseMessageTooBig = 1009
+)
+
+var (
+	// ErrMessageTooLarge is returned by ReadMessage for a message larger
+	// than the connection's limit.
+	ErrMessageTooLarge = errors.New("websocket: message too large")
+
+	// ErrCloseSent is returned when writing after a close frame was sent.
+	ErrCloseSent = errors.New("websocket: close frame already sent")
+
+	// errProtocol is returned for frames that break the protocol.
+	errProtocol = errors.New("websocket: protocol error")
+)
+
+// CloseError is returned by ReadMessage when the peer closes the
+// connection.
+type CloseError struct {
+	// Code is the close code, or CloseNoStatus if the peer sent none.
+	Code int
+
+	// Reason is the close reason, if any.
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *CloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: closed with code %d: %s", e.Code, e.Reason)
+}
+
+// PrepareRequest turns req into an opening handshake and returns the key
+// that NewClientConn checks the response against.
+func PrepareRequest(req *http.Request) string {
+	var nonce [16]byte
+	rand.Read(nonce[:])
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req.Method = http.MethodGet
+	req.Header.Del("Content-Type")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	return key
+}
+
+// NewClientConn checks the response to a handshake prepared with
+// PrepareRequest and returns the connection. Messages larger than
+// maxMessage bytes fail to read; zero or less means no limit.
+func NewClientConn(resp *http.Response, key string, maxMessage int64) (*Conn, error) {
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket: handshake failed with status %d", resp.StatusCode)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") || !hasToken(resp.Header.Get("Connection"), "upgrade") {
+		return nil, errors.New("websocket: handshake response does not upgrade the connection")
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != acceptKey(key) {
+		return nil, errors.New("websocket: handshake response has an invalid accept key")
+	}
+
+	rwc, ok := resp.Body.(io.ReadWriteCloser)
+	if !ok {
+		return nil, errors.New("websocket: upgraded connection is not writable")
+	}
+	return newConn(rwc, bufio.NewReader(rwc), true, maxMessage), nil
+}
+
+// Upgrade completes the opening handshake on the server side. It is meant
+// for tests and fake servers.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "not a websocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a websocket handshake")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("websocket: response writer cannot hijack the connection")
+	}
+	netConn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(brw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", acceptKey(key))
+	if err := brw.Flush(); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newConn(netConn, brw.Reader, false, 0), nil
+}
+
+// Conn is a WebSocket connection. ReadMessage must not be called
+// concurrently; writes and Close may be called from any goroutine.
+type Conn struct {
+	rwc        io.ReadWriteCloser
+	br         *bufio.Reader
+	client     bool
+	maxMessage int64
+
+	writeMu   sync.Mutex
+	closeSent bool
+
+	closeOnce sync.Once
+	closeErr  error
+	onClose   func()
+}
+
+// newConn returns a connection over rwc, reading through br. Client
+// connections mask their frames.
+func newConn(rwc io.ReadWriteCloser, br *bufio.Reader, client bool, maxMessage int64) *Conn {
+	return &Conn{rwc: rwc, br: br, client: client, maxMessage: maxMessage}
+}
+
+// SetCloseHook registers f to run once when the connection is closed. It
+// must be called before the connection is used.
+func (c *Conn) SetCloseHook(f func()) {
+	c.onClose = f
+}
+
+// WriteMessage writes a text or binary message in a single frame.
+func (c *Conn) WriteMessage(op Opcode, data []byte) error {
+	return c.writeFrame(op, data)
+}
+
+// WriteClose sends a close frame, starting the close handshake. The reply
+// of the peer ends ReadMessage with a *CloseError.
+func (c *Conn) WriteClose(code int, reason string) error {
+	payload := binary.BigEndian.AppendUint16(nil, uint16(code))
+	return c.writeFrame(OpClose, append(payload, reason...))
+}
+
+// Close sends a close frame, unless one was sent, and closes the
+// connection without waiting for the reply of the peer.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		c.WriteClose(CloseNormal, "")
+		c.closeErr = c.rwc.Close()
+		if c.onClose != nil {
+			c.onClose()
+		}
+	})
+	return c.closeErr
+}
+
+// writeFrame writes a single final frame.
+func (c *Conn) writeFrame(op Opcode, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return ErrCloseSent
+	}
+	if op == OpClose {
+		c.closeSent = true
+	}
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+
+	frame := make([]byte, 0, 14+len(payload))
+	frame = append(frame, 0x80|byte(op))
+	switch n := len(payload); {
+	case n <= 125:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.client {
+		var key [4]byte
+		rand.Read(key[:])
+		frame = append(frame, key[:]...)
+		start := len(frame)
+		frame = append(frame, payload...)
+		mask(frame[start:], key)
+	} else {
+		frame = append(frame, payload...)
+	}
+
+	_, err := c.rwc.Write(frame)
+	return err
+}
+
+// ReadMessage reads the next text or binary message. Pings are answered
+// and pongs are skipped. When the peer closes the connection, the close is
+// answered and a *CloseError is returned.
+func (c *Conn) ReadMessage() (Opcode, []byte, error) {
+	var (
+		op      Opcode
+		message []byte
+		started bool
+	)
+	for {
+		fin, frameOp, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch frameOp {
+		case OpPing:
+			if err := c.writeFrame(OpPong, payload); err != nil && err != ErrCloseSent {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			closeErr := &CloseError{Code: CloseNoStatus}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			c.WriteClose(closeErr.Code, "")
+			return 0, nil, closeErr
+		case OpContinuation:
+			if !started {
+				return 0, nil, c.fail(CloseProtocolError, errProtocol)
+			}
+		case OpText, OpBinary:
+			if started {
+				return 0, nil, c.fail(CloseProtocolError, errProtocol)
+			}
+			op, started = frameOp, true
+		default:
+			return 0, nil, c.fail(CloseProtocolError, errProtocol)
+		}
+
+		if c.maxMessage > 0 && int64(len(message)+len(payload)) > c.maxMessage {
+			return 0, nil, c.fail(CloseMessageTooBig, ErrMessageTooLarge)
+		}
+		message = append(message, payload...)
+		if fin {
+			return op, message, nil
+		}
+	}
+}
+
+// readFrame reads a frame and unmasks its payload.
+func (c *Conn) readFrame() (fin bool, op Opcode, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	op = Opcode(header[0] & 0x0F)
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, c.fail(CloseProtocolError, errProtocol)
+	}
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	// Control frames are short and never fragmented
+	if op >= OpClose && (length > 125 || !fin) {
+		return false, 0, nil, c.fail(CloseProtocolError, errProtocol)
+	}
+	if length > 1<<62 || (c.maxMessage > 0 && int64(length) > c.maxMessage) {
+		return false, 0, nil, c.fail(CloseMessageTooBig, ErrMessageTooLarge)
+	}
+
+	var key [4]byte
+	masked := header[1]&0x80 != 0
+	if masked {
+		if _, err := io.ReadFull(c.br, key[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		mask(payload, key)
+	}
+	return fin, op, payload, nil
+}
+
+// fail sends a close frame with code and returns err.
+func (c *Conn) fail(code int, err error) error {
+	c.WriteClose(code, "")
+	return err
+}
+
+// mask applies the masking key to data in place.
+func mask(data []byte, key [4]byte) {
+	for i := range data {
+		data[i] ^= key[i%4]
+	}
+}
+
+// acceptKey returns the accept key for a handshake key.
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// hasToken reports whether the comma-separated header value contains
+// token, ignoring case.
+func hasToken(value, token string) bool {
+	for _, part := range strings.Split(value, ",") {
+		if strings.EqualFold(strings.TrimSpace(part), token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// pipe returns a connected client and server. Writes to a pipe block until
+// the peer reads, so the ends are closed without a close handshake when
+// the test ends.
+func pipe(t *testing.T, maxMessage int64) (client, server *Conn) {
+	c, s := net.Pipe()
+	t.Cleanup(func() {
+		c.Close()
+		s.Close()
+	})
+	client = newConn(c, bufio.NewReader(c), true, maxMessage)
+	server = newConn(s, bufio.NewReader(s), false, 0)
+	return client, server
+}
+
+func TestConn_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	client, server := pipe(t, 0)
+
+	messages := [][]byte{[]byte("hello"), bytes.Repeat([]byte("a"), 300), bytes.Repeat([]byte("b"), 70000)}
+	go func() {
+		for _, m := range messages {
+			client.WriteMessage(OpText, m)
+		}
+	}()
+
+	for _, want := range messages {
+		op, got, err := server.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage() error = %v", err)
+		}
+		if op != OpText || !bytes.Equal(got, want) {
+			t.Errorf("ReadMessage() = %v, %d bytes, want text, %d bytes", op, len(got), len(want))
+		}
+	}
+}
+
+func TestConn_ReadFragmentedMessageAndPing(t *testing.T) {
+	t.Parallel()
+
+	client, server := pipe(t, 0)
+
+	go func() {
+		server.rwc.Write([]byte{byte(OpText), 3, 'h', 'e', 'l'})
+		server.rwc.Write([]byte{0x80 | byte(OpPing), 1, 'p'})
+		server.rwc.Write([]byte{0x80 | byte(OpContinuation), 2, 'l', 'o'})
+	}()
+
+	pong := make(chan []byte, 1)
+	go func() {
+		_, _, payload, _ := server.readFrame()
+		pong <- payload
+	}()
+
+	op, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if op != OpText || string(data) != "hello" {
+		t.Errorf("ReadMessage() = %v, %q, want text, %q", op, data, "hello")
+	}
+	if got := <-pong; string(got) != "p" {
+		t.Errorf("pong payload = %q, want %q", got, "p")
+	}
+}
+
+func TestConn_MessageTooLarge(t *testing.T) {
+	t.Parallel()
+
+	client, server := pipe(t, 4)
+
+	go server.WriteMessage(OpText, []byte("too large"))
+	go server.readFrame()
+
+	if _, _, err := client.ReadMessage(); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("ReadMessage() error = %v, want ErrMessageTooLarge", err)
+	}
+}
+
+func TestConn_CloseHandshake(t *testing.T) {
+	t.Parallel()
+
+	client, server := pipe(t, 0)
+
+	// The server echoes the close, which ends the client's reads
+	clientErr := make(chan error, 1)
+	go func() {
+		client.WriteClose(CloseNormal, "bye")
+		_, _, err := client.ReadMessage()
+		clientErr <- err
+	}()
+
+	_, _, err := server.ReadMessage()
+	var closeErr *CloseError
+	if !errors.As(err, &closeErr) || closeErr.Code != CloseNormal || closeErr.Reason != "bye" {
+		t.Fatalf("server ReadMessage() error = %v, want close 1000 bye", err)
+	}
+	if err := <-clientErr; !errors.As(err, &closeErr) || closeErr.Code != CloseNormal {
+		t.Errorf("client ReadMessage() error = %v, want close 1000", err)
+	}
+	if err := client.WriteMessage(OpText, []byte("late")); !errors.Is(err, ErrCloseSent) {
+		t.Errorf("WriteMessage() after close error = %v, want ErrCloseSent", err)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		if op, data, err := conn.ReadMessage(); err == nil {
+			conn.WriteMessage(op, data)
+		}
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := PrepareRequest(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("handshake error = %v", err)
+	}
+
+	conn, err := NewClientConn(resp, key, 0)
+	if err != nil {
+		t.Fatalf("NewClientConn() error = %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.WriteMessage(OpBinary, []byte{1, 2, 3}); err != nil {
+		t.Fatalf("WriteMessage() error = %v", err)
+	}
+	op, data, err := conn.ReadMessage()
+	if err != nil || op != OpBinary || !bytes.Equal(data, []byte{1, 2, 3}) {
+		t.Errorf("echo = %v, %v, %v, want binary [1 2 3]", op, data, err)
+	}
+
+	if _, err := NewClientConn(resp, "other-key", 0); err == nil {
+		t.Error("NewClientConn() with a different key succeeded")
+	}
+}
+
+func TestUpgrade_NotAHandshake(t *testing.T) {
+	t.Parallel()
+
+	rec := httptest.NewRecorder()
+	if _, err := Upgrade(rec, httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Error("Upgrade() of a plain request succeeded")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
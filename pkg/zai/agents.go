@@ -5,6 +5,7 @@ import (
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/agents"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 	streaming "github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
 )
 
@@ -43,7 +44,7 @@ func (s *AgentsService) Invoke(ctx context.Context, req *agents.AgentInvokeReque
 	req.Stream = false
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/v1/agents", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.Agents), req)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +88,7 @@ func (s *AgentsService) InvokeStream(ctx context.Context, req *agents.AgentInvok
 	req.Stream = true
 
 	// Make the streaming request
-	streamResp, err := s.client.Stream(ctx, "/v1/agents", req)
+	streamResp, err := s.client.Stream(ctx, s.client.Endpoints().Path(endpoints.Agents), req)
 	if err != nil {
 		return nil, err
 	}
@@ -115,7 +116,7 @@ func (s *AgentsService) InvokeStream(ctx context.Context, req *agents.AgentInvok
 //	}
 func (s *AgentsService) AsyncResult(ctx context.Context, req *agents.AgentAsyncResultRequest) (*agents.AgentCompletionResponse, error) {
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/v1/agents/async-result", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.AgentsAsyncResult), req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,97 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/auth"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// DefaultAPIKeyCacheTTL is how long keys returned by an APIKeyProvider are
+// reused unless WithAPIKeyCacheTTL is given.
+const DefaultAPIKeyCacheTTL = time.Minute
+
+// APIKeyProvider returns the API key to authenticate requests with, in the
+// format "id.secret". It must be safe for concurrent use.
+type APIKeyProvider func(ctx context.Context) (string, error)
+
+// WithAPIKeyProvider fetches the API key from provider instead of using a
+// fixed key, so keys can be rotated without restarting the process, for
+// example by reading them from a secret manager.
+//
+// Keys are reused for DefaultAPIKeyCacheTTL (see WithAPIKeyCacheTTL). If the
+// API rejects a request with 401 Unauthorized, the provider is asked for a
+// new key and, if it returns a different one, the request is sent once more
+// with it. Provider errors are returned to the caller, and a malformed key
+// fails the request with a ConfigError.
+//
+// WithAPIKeyProvider replaces a key set with WithAPIKey, and WithAPIKey
+// given later replaces the provider.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+//	        return secrets.Get(ctx, "zai-api-key")
+//	    }),
+//	)
+func WithAPIKeyProvider(provider APIKeyProvider) ClientOption {
+	return func(c *ClientConfig) {
+		c.APIKey = ""
+		c.APIKeyProvider = provider
+	}
+}
+
+// WithAPIKeyCacheTTL sets how long keys returned by the APIKeyProvider are
+// reused. Negative values fetch a key for every request.
+func WithAPIKeyCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.APIKeyCacheTTL = ttl
+	}
+}
+
+// EnvAPIKeyProvider returns an APIKeyProvider that reads the API key from
+// the environment variable name on every fetch.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKeyProvider(zai.EnvAPIKeyProvider("ZAI_API_KEY")),
+//	)
+func EnvAPIKeyProvider(name string) APIKeyProvider {
+	return func(ctx context.Context) (string, error) {
+		key := os.Getenv(name)
+		if key == "" {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return key, nil
+	}
+}
+
+// validateAPIKey checks the API key configuration of config.
+func validateAPIKey(config *ClientConfig) error {
+	if config.Token != "" || config.TokenSource != nil {
+		return validateToken(config)
+	}
+	if config.APIKeyProvider != nil {
+		return nil
+	}
+	if config.APIKey == "" {
+		return errors.NewConfigError("APIKey", "API key is required")
+	}
+	if auth.ValidateAPIKey(config.APIKey) != nil {
+		return errors.NewConfigError("APIKey", `API key must be in the format "id.secret"`)
+	}
+	return nil
+}
+
+// apiKeyCacheTTL returns the key cache TTL of config.
+func apiKeyCacheTTL(config *ClientConfig) time.Duration {
+	if config.APIKeyCacheTTL == 0 {
+		return DefaultAPIKeyCacheTTL
+	}
+	return config.APIKeyCacheTTL
+}
@@ -0,0 +1,192 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// newRotatingServer serves chat completions and streams to requests that
+// carry the accepted key as a raw bearer token, and 401 to all others.
+func newRotatingServer(t *testing.T, accepted *atomic.Value) (*httptest.Server, *atomic.Int32) {
+	var unauthorized atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer "+accepted.Load().(string) {
+			unauthorized.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"code":"1001","message":"invalid api key"}}`))
+			return
+		}
+
+		if r.URL.Path == "/models" {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"object":"list","data":[]}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &unauthorized
+}
+
+func TestWithAPIKeyProvider_Rotation(t *testing.T) {
+	t.Parallel()
+
+	var accepted, current atomic.Value
+	accepted.Store("key1.secret1")
+	current.Store("key1.secret1")
+	server, unauthorized := newRotatingServer(t, &accepted)
+
+	var fetches atomic.Int32
+	client, err := NewClient(
+		WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+			fetches.Add(1)
+			return current.Load().(string), nil
+		}),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Models.List(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), fetches.Load(), "the key is cached")
+
+	// Rotate the key while the client still caches the old one
+	accepted.Store("key2.secret2")
+	current.Store("key2.secret2")
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err, "the request is retried with the refreshed key")
+	assert.Equal(t, int32(1), unauthorized.Load())
+
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+
+	// Streams are retried too
+	accepted.Store("key3.secret3")
+	current.Store("key3.secret3")
+
+	content, err = client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+	assert.Equal(t, int32(2), unauthorized.Load())
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestWithAPIKeyProvider_RevokedKey(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("other.secret")
+	server, unauthorized := newRotatingServer(t, &accepted)
+
+	client, err := NewClient(
+		WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+			return "revoked.secret", nil
+		}),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	assert.True(t, errors.IsAuthenticationError(err), "got %v", err)
+	assert.Equal(t, int32(1), unauthorized.Load(), "the same key is not retried")
+}
+
+func TestWithAPIKeyProvider_Errors(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("key1.secret1")
+	server, _ := newRotatingServer(t, &accepted)
+
+	t.Run("provider error", func(t *testing.T) {
+		t.Parallel()
+
+		errVault := stderrors.New("vault unavailable")
+		client, err := NewClient(
+			WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+				return "", errVault
+			}),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Models.List(context.Background())
+		assert.ErrorIs(t, err, errVault)
+
+		_, err = client.Chat.StreamContent(context.Background(), newChatRequest())
+		assert.ErrorIs(t, err, errVault)
+	})
+
+	t.Run("malformed key", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(
+			WithAPIKeyProvider(func(ctx context.Context) (string, error) {
+				return "no-secret", nil
+			}),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Models.List(context.Background())
+		var configErr *errors.ConfigError
+		require.ErrorAs(t, err, &configErr)
+		assert.Equal(t, "APIKey", configErr.Field)
+	})
+}
+
+func TestNewClient_MalformedAPIKey(t *testing.T) {
+	t.Parallel()
+
+	for _, key := range []string{"no-secret", "id.", ".secret", "a.b.c"} {
+		client, err := NewClient(WithAPIKey(key))
+		assert.Nil(t, client)
+
+		var configErr *errors.ConfigError
+		require.ErrorAs(t, err, &configErr, key)
+		assert.Equal(t, "APIKey", configErr.Field)
+		assert.Contains(t, configErr.Error(), "id.secret")
+	}
+}
+
+func TestAPIKeyOptions_LastWins(t *testing.T) {
+	t.Parallel()
+
+	provider := EnvAPIKeyProvider("ZAI_TEST_UNSET_KEY")
+
+	config := &ClientConfig{}
+	WithAPIKey("key.secret")(config)
+	WithAPIKeyProvider(provider)(config)
+	assert.Empty(t, config.APIKey)
+	assert.NotNil(t, config.APIKeyProvider)
+
+	WithAPIKey("key.secret")(config)
+	assert.Equal(t, "key.secret", config.APIKey)
+	assert.Nil(t, config.APIKeyProvider)
+
+	_, err := provider(context.Background())
+	assert.ErrorContains(t, err, "ZAI_TEST_UNSET_KEY")
+}
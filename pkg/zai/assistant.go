@@ -2,12 +2,28 @@ package zai
 
 import (
 	"context"
+	stderrors "errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/assistant"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/pagination"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
+// assistantFileInitialPollDelay is the first delay between status checks
+// of files uploaded by CreateConversationWithFiles.
+const assistantFileInitialPollDelay = 250 * time.Millisecond
+
 // AssistantService provides access to the Assistant API.
 type AssistantService struct {
 	client *client.BaseClient
@@ -41,9 +57,12 @@ func newAssistantService(baseClient *client.BaseClient) *AssistantService {
 func (s *AssistantService) Conversation(ctx context.Context, req *assistant.ConversationRequest) (*assistant.AssistantCompletion, error) {
 	// Ensure stream is set to false for non-streaming requests
 	req.Stream = false
+	if err := s.client.Sanitize(ServiceAssistant, req); err != nil {
+		return nil, err
+	}
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/assistant", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.Assistant), req)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +77,7 @@ func (s *AssistantService) Conversation(ctx context.Context, req *assistant.Conv
 }
 
 // ConversationStream creates a streaming conversation with an assistant.
+// assistant.WithReconnect resumes the stream after a dropped connection.
 //
 // Example:
 //
@@ -85,16 +105,23 @@ func (s *AssistantService) Conversation(ctx context.Context, req *assistant.Conv
 //	if err := stream.Err(); err != nil {
 //	    // Handle error
 //	}
-func (s *AssistantService) ConversationStream(ctx context.Context, req *assistant.ConversationRequest) (*streaming.Stream[assistant.AssistantCompletion], error) {
+func (s *AssistantService) ConversationStream(ctx context.Context, req *assistant.ConversationRequest, opts ...assistant.StreamOption) (*streaming.Stream[assistant.AssistantCompletion], error) {
 	// Ensure stream is set to true
 	req.Stream = true
+	if err := s.client.Sanitize(ServiceAssistant, req); err != nil {
+		return nil, err
+	}
 
 	// Make the streaming request
-	streamResp, err := s.client.Stream(ctx, "/assistant", req)
+	path := s.client.Endpoints().Path(endpoints.Assistant)
+	streamResp, err := s.client.Stream(ctx, path, req)
 	if err != nil {
 		return nil, err
 	}
 
+	cfg := assistant.NewStreamConfig(opts...)
+	s.client.EnableReconnect(streamResp, http.MethodPost, path, req, cfg.MaxReconnects, cfg.ReconnectBackoff)
+
 	// Create typed stream
 	return client.NewTypedStream[assistant.AssistantCompletion](streamResp, ctx), nil
 }
@@ -122,7 +149,7 @@ func (s *AssistantService) QuerySupport(ctx context.Context, assistantIDs []stri
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/assistant/list", body)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.AssistantSupport), body)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +163,8 @@ func (s *AssistantService) QuerySupport(ctx context.Context, assistantIDs []stri
 	return &resp, nil
 }
 
-// QueryConversationUsage retrieves conversation usage history for an assistant.
+// QueryConversationUsage retrieves one page of the conversation usage history
+// for an assistant. QueryConversationUsageAll iterates over all pages.
 //
 // Example:
 //
@@ -160,7 +188,7 @@ func (s *AssistantService) QueryConversationUsage(ctx context.Context, assistant
 		page = 1
 	}
 	if pageSize < 1 {
-		pageSize = 10
+		pageSize = assistant.DefaultUsagePageSize
 	}
 
 	body := map[string]interface{}{
@@ -170,7 +198,7 @@ func (s *AssistantService) QueryConversationUsage(ctx context.Context, assistant
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/assistant/conversation/list", body)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.AssistantConversations), body)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +212,139 @@ func (s *AssistantService) QueryConversationUsage(ctx context.Context, assistant
 	return &resp, nil
 }
 
+// QueryConversationUsageAll returns an iterator over the conversation
+// usage of an assistant, fetching pages lazily as the iteration advances.
+// assistant.WithStartPage sets the first page and
+// assistant.WithUsagePageSize the page size. The iteration stops with
+// ctx.Err() once ctx is cancelled, including between pages.
+//
+// Example:
+//
+//	iter := client.Assistant.QueryConversationUsageAll(ctx, "asst_123",
+//	    assistant.WithUsagePageSize(50))
+//	for iter.Next() {
+//	    conv := iter.Current()
+//	    fmt.Printf("%s %s: %d tokens\n",
+//	        conv.CreateTime.Format(time.DateOnly), conv.ID, conv.Usage.TotalTokens)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *AssistantService) QueryConversationUsageAll(ctx context.Context, assistantID string, opts ...assistant.UsageListOption) *pagination.Iterator[assistant.ConversationUsage] {
+	params := assistant.NewUsageListParams(opts...)
+
+	// The cursor is the number of the page to fetch
+	return pagination.NewIterator(ctx, strconv.Itoa(params.Page), func(ctx context.Context, cursor string) (*pagination.Page[assistant.ConversationUsage], error) {
+		page, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+		}
+
+		resp, err := s.QueryConversationUsage(ctx, assistantID, page, params.PageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		conversations := resp.GetConversations()
+		return &pagination.Page[assistant.ConversationUsage]{
+			Items: conversations,
+			// Stop rather than page forever if the server claims more
+			// pages but returns an empty one
+			HasMore: resp.HasMore() && len(conversations) > 0,
+			Cursor:  strconv.Itoa(page + 1),
+		}, nil
+	})
+}
+
+// GetConversationMessages retrieves a page of the messages of a past
+// conversation, oldest first, with their content blocks, tool events and
+// timestamps. page starts at 1; invalid values are replaced with page 1
+// and assistant.DefaultUsagePageSize.
+//
+// Example:
+//
+//	resp, err := client.Assistant.GetConversationMessages(ctx, "asst_123", "conv_456", 1, 20)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, msg := range resp.GetMessages() {
+//	    fmt.Printf("%s %s: %s\n", msg.CreateTime.Format(time.TimeOnly), msg.Role, msg.GetText())
+//	}
+func (s *AssistantService) GetConversationMessages(ctx context.Context, assistantID, conversationID string, page, pageSize int) (*assistant.ConversationMessagesResponse, error) {
+	if assistantID == "" {
+		return nil, errors.NewValidationError("assistant_id", "is required", nil)
+	}
+	if conversationID == "" {
+		return nil, errors.NewValidationError("conversation_id", "is required", nil)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = assistant.DefaultUsagePageSize
+	}
+
+	body := map[string]interface{}{
+		"assistant_id":    assistantID,
+		"conversation_id": conversationID,
+		"page":            page,
+		"page_size":       pageSize,
+	}
+
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.AssistantMessages), body)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp assistant.ConversationMessagesResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// GetConversationMessagesAll returns an iterator over the messages of a
+// past conversation, fetching pages lazily as the iteration advances.
+// assistant.WithStartPage sets the first page and
+// assistant.WithUsagePageSize the page size. assistant.ToChatMessages
+// converts the collected transcript for the Chat API.
+//
+// Example:
+//
+//	iter := client.Assistant.GetConversationMessagesAll(ctx, "asst_123", "conv_456")
+//	for iter.Next() {
+//	    msg := iter.Current()
+//	    fmt.Printf("%s: %s\n", msg.Role, msg.GetText())
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *AssistantService) GetConversationMessagesAll(ctx context.Context, assistantID, conversationID string, opts ...assistant.UsageListOption) *pagination.Iterator[assistant.TranscriptMessage] {
+	params := assistant.NewUsageListParams(opts...)
+
+	// The cursor is the number of the page to fetch
+	return pagination.NewIterator(ctx, strconv.Itoa(params.Page), func(ctx context.Context, cursor string) (*pagination.Page[assistant.TranscriptMessage], error) {
+		page, err := strconv.Atoi(cursor)
+		if err != nil {
+			return nil, fmt.Errorf("invalid page cursor %q: %w", cursor, err)
+		}
+
+		resp, err := s.GetConversationMessages(ctx, assistantID, conversationID, page, params.PageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		messages := resp.GetMessages()
+		return &pagination.Page[assistant.TranscriptMessage]{
+			Items:   messages,
+			HasMore: resp.HasMore() && len(messages) > 0,
+			Cursor:  strconv.Itoa(page + 1),
+		}, nil
+	})
+}
+
 // CreateConversation is a convenience method to create a new conversation.
 //
 // Example:
@@ -230,3 +391,204 @@ func (s *AssistantService) ContinueConversation(ctx context.Context, assistantID
 	req.SetConversationID(conversationID)
 	return s.Conversation(ctx, req)
 }
+
+// CreateConversationWithFiles creates a new conversation with files
+// attached. Files given as readers are uploaded concurrently with purpose
+// "assistants" and polled until they are uploaded or processed; files
+// given by ID are attached as they are. The conversation is sent once
+// every file is ready.
+//
+// If a file cannot be uploaded or becomes ready with an error, the call
+// fails with an *assistant.FileError and remaining uploads are canceled.
+// When the call fails, the files it uploaded are deleted unless
+// assistant.WithKeepOnFailure is given.
+//
+// Example:
+//
+//	contract, _ := os.Open("contract.pdf")
+//	defer contract.Close()
+//
+//	resp, err := client.Assistant.CreateConversationWithFiles(ctx, "asst_123",
+//	    "Summarize the termination clauses",
+//	    []assistant.FileInput{
+//	        assistant.FileFromReader(contract, "contract.pdf"),
+//	        assistant.FileFromID("file-abc123"),
+//	    },
+//	)
+func (s *AssistantService) CreateConversationWithFiles(ctx context.Context, assistantID, prompt string, inputs []assistant.FileInput, opts ...assistant.AttachOption) (*assistant.AssistantCompletion, error) {
+	cfg := assistant.NewAttachConfig(opts...)
+
+	for i, input := range inputs {
+		if err := input.Validate(); err != nil {
+			return nil, &assistant.FileError{Index: i, Filename: fileInputName(input), Err: err}
+		}
+	}
+
+	fileIDs, uploaded, err := s.uploadFiles(ctx, inputs, cfg)
+	if err == nil {
+		attachments := make([]assistant.AssistantAttachment, len(fileIDs))
+		for i, id := range fileIDs {
+			attachments[i] = assistant.AssistantAttachment{FileID: id}
+		}
+
+		req := assistant.NewConversationRequest(assistantID, []assistant.ConversationMessage{
+			{
+				Role: "user",
+				Content: []assistant.MessageContent{
+					assistant.MessageTextContent{
+						Type: "text",
+						Text: prompt,
+					},
+				},
+			},
+		})
+		req.SetAttachments(attachments)
+
+		var resp *assistant.AssistantCompletion
+		if resp, err = s.Conversation(ctx, req); err == nil {
+			return resp, nil
+		}
+	}
+
+	if !cfg.KeepOnFailure {
+		s.deleteFiles(ctx, uploaded)
+	}
+	return nil, err
+}
+
+// uploadFiles uploads the reader inputs concurrently and waits for them to
+// be ready. It returns the file IDs of all inputs in order, and the IDs of
+// the files it uploaded, including on failure.
+func (s *AssistantService) uploadFiles(ctx context.Context, inputs []assistant.FileInput, cfg *assistant.AttachConfig) ([]string, []string, error) {
+	fileIDs := make([]string, len(inputs))
+	errs := make([]error, len(inputs))
+
+	// Stop the other uploads as soon as one fails
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	fileService := newFilesService(s.client)
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, input := range inputs {
+		if input.Reader == nil {
+			fileIDs[i] = input.FileID
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, input assistant.FileInput) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			file, err := fileService.Upload(ctx, files.NewFileUploadRequest(input.Reader, input.Filename, files.PurposeAssistants))
+			if err == nil {
+				fileIDs[i] = file.ID
+				err = s.waitForFile(ctx, fileService, file, cfg)
+			}
+			if err != nil {
+				errs[i] = err
+				cancel()
+			}
+		}(i, input)
+	}
+	wg.Wait()
+
+	var uploaded []string
+	for i, input := range inputs {
+		if input.Reader != nil && fileIDs[i] != "" {
+			uploaded = append(uploaded, fileIDs[i])
+		}
+	}
+
+	// Report the first failure rather than the cancellations it caused
+	var firstErr error
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		fileErr := &assistant.FileError{Index: i, Filename: fileInputName(inputs[i]), Err: err}
+		if firstErr == nil || (isCanceled(firstErr) && !isCanceled(err)) {
+			firstErr = fileErr
+		}
+	}
+	if firstErr != nil {
+		return nil, uploaded, firstErr
+	}
+
+	return fileIDs, uploaded, nil
+}
+
+// waitForFile polls an uploaded file until it is uploaded or processed.
+// Polling starts quickly and backs off exponentially up to the poll
+// interval of cfg.
+func (s *AssistantService) waitForFile(ctx context.Context, fileService *FilesService, file *files.File, cfg *assistant.AttachConfig) error {
+	poller := newPoller(s.client, poll.Config{
+		InitialInterval: assistantFileInitialPollDelay,
+		MaxInterval:     cfg.PollInterval,
+		Timeout:         cfg.Timeout,
+	}, nil)
+
+	// The upload response is the first status
+	first := true
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if !first {
+			var err error
+			if file, err = fileService.Retrieve(ctx, file.ID); err != nil {
+				return false, err
+			}
+		}
+		first = false
+
+		if file.HasError() {
+			return false, fmt.Errorf("file %s failed processing: %s", file.ID, file.StatusDetails)
+		}
+		return file.IsUploaded(), nil
+	})
+	if stderrors.Is(err, poll.ErrTimeout) {
+		return fmt.Errorf("file %s not ready after %s (status %q)", file.ID, cfg.Timeout, file.Status)
+	}
+	return err
+}
+
+// deleteFiles deletes files uploaded by a failed call. Deletion runs even
+// if ctx was canceled, and its errors are logged rather than returned.
+func (s *AssistantService) deleteFiles(ctx context.Context, fileIDs []string) {
+	if len(fileIDs) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	fileService := newFilesService(s.client)
+	for _, id := range fileIDs {
+		if _, err := fileService.Delete(ctx, id); err != nil {
+			s.client.GetLogger().WarnContext(ctx, "Failed to delete uploaded file",
+				slog.String("file_id", id),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// fileInputName returns the name of input for error messages.
+func fileInputName(input assistant.FileInput) string {
+	if input.Reader != nil {
+		return input.Filename
+	}
+	return input.FileID
+}
+
+// isCanceled reports whether err is a context cancellation.
+func isCanceled(err error) bool {
+	return stderrors.Is(err, context.Canceled)
+}
@@ -3,13 +3,21 @@ package zai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/assistant"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestAssistantService_Conversation(t *testing.T) {
@@ -220,6 +228,80 @@ func TestAssistantService_ConversationStream(t *testing.T) {
 	assert.True(t, chunks[2].IsCompleted())
 }
 
+// mixedAssistantStream is an SSE stream containing every assistant delta
+// block type, plus one type the SDK does not know about.
+const mixedAssistantStream = `data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"in_progress","choices":[{"index":0,"delta":{"role":"assistant","type":"content","content":"Let me check."}}]}
+
+data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"in_progress","choices":[{"index":0,"delta":{"role":"tool","type":"tool_calls","tool_calls":[{"id":"call_1","type":"code_interpreter","code_interpreter":{"input":"print(6*7)","outputs":[{"type":"logs","logs":"42"}]}}]}}]}
+
+data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"in_progress","choices":[{"index":0,"delta":{"role":"tool","type":"retrieval","citations":[{"file_id":"file_abc","document":"faq.md","text":"The answer is 42."}]}}]}
+
+data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"in_progress","choices":[{"index":0,"delta":{"type":"tools","tool_call_id":"call_2","tool_name":"search","tool_output":"ok"}}]}
+
+data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"in_progress","choices":[{"index":0,"delta":{"type":"drawing_tool","outputs":[{"image":"https://example.com/a.png"}]}}]}
+
+data: {"id":"req_1","conversation_id":"conv_1","assistant_id":"asst_1","status":"failed","choices":[{"index":0,"delta":{"type":"error","code":"1301","message":"content blocked"},"finish_reason":"sensitive"}]}
+
+data: [DONE]
+
+`
+
+func TestAssistantService_ConversationStream_MixedBlocks(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(mixedAssistantStream))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := assistant.NewConversationRequest("asst_1", []assistant.ConversationMessage{
+		{Role: "user", Content: []assistant.MessageContent{assistant.MessageTextContent{Type: "text", Text: "What is 6*7?"}}},
+	})
+
+	stream, err := client.Assistant.ConversationStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var chunks []assistant.AssistantCompletion
+	for stream.Next() {
+		chunks = append(chunks, *stream.Current())
+	}
+	require.NoError(t, stream.Err())
+	require.Len(t, chunks, 6)
+
+	assert.Equal(t, "Let me check.", chunks[0].GetText())
+
+	calls := chunks[1].GetToolCalls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, "print(6*7)", calls[0].CodeInterpreter.Input)
+	assert.Equal(t, "42", calls[0].CodeInterpreter.Outputs[0].Logs)
+
+	citations := chunks[2].GetCitations()
+	require.Len(t, citations, 1)
+	assert.Equal(t, "file_abc", citations[0].FileID)
+
+	tools, ok := chunks[3].Choices[0].Delta.(assistant.ToolsDeltaBlock)
+	require.True(t, ok)
+	assert.Equal(t, "search", tools.ToolName)
+
+	unknown, ok := chunks[4].Choices[0].Delta.(assistant.UnknownBlock)
+	require.True(t, ok)
+	assert.Equal(t, "drawing_tool", unknown.Type)
+	assert.Contains(t, string(unknown.Raw), "https://example.com/a.png")
+
+	assert.True(t, chunks[5].IsFailed())
+	assert.Equal(t, "content blocked", chunks[5].GetError())
+	assert.Equal(t, "sensitive", chunks[5].Choices[0].FinishReason)
+}
+
 func TestAssistantService_QuerySupport(t *testing.T) {
 	t.Parallel()
 
@@ -361,8 +443,8 @@ func TestAssistantService_QueryConversationUsage(t *testing.T) {
 					{
 						ID:          "conv_1",
 						AssistantID: "asst_123",
-						CreateTime:  1609459200,
-						UpdateTime:  1609545600,
+						CreateTime:  time.Unix(1609459200, 0),
+						UpdateTime:  time.Unix(1609545600, 0),
 						Usage: assistant.CompletionUsage{
 							PromptTokens:     100,
 							CompletionTokens: 50,
@@ -372,8 +454,8 @@ func TestAssistantService_QueryConversationUsage(t *testing.T) {
 					{
 						ID:          "conv_2",
 						AssistantID: "asst_123",
-						CreateTime:  1609632000,
-						UpdateTime:  1609718400,
+						CreateTime:  time.Unix(1609632000, 0),
+						UpdateTime:  time.Unix(1609718400, 0),
 						Usage: assistant.CompletionUsage{
 							PromptTokens:     200,
 							CompletionTokens: 100,
@@ -410,6 +492,8 @@ func TestAssistantService_QueryConversationUsage(t *testing.T) {
 	conv1 := conversations[0]
 	assert.Equal(t, "conv_1", conv1.ID)
 	assert.Equal(t, "asst_123", conv1.AssistantID)
+	assert.True(t, time.Unix(1609459200, 0).Equal(conv1.CreateTime))
+	assert.True(t, time.Unix(1609545600, 0).Equal(conv1.UpdateTime))
 	assert.Equal(t, 150, conv1.Usage.TotalTokens)
 
 	conv2 := conversations[1]
@@ -417,6 +501,217 @@ func TestAssistantService_QueryConversationUsage(t *testing.T) {
 	assert.Equal(t, 300, conv2.Usage.TotalTokens)
 }
 
+// usagePagesHandler serves three pages of two conversations, with
+// timestamps in seconds on odd pages and milliseconds on even ones, and
+// records the requested pages.
+func usagePagesHandler(t *testing.T, pages *[]int, mu *sync.Mutex) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			AssistantID string `json:"assistant_id"`
+			Page        int    `json:"page"`
+			PageSize    int    `json:"page_size"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "asst_123", body.AssistantID)
+		assert.Equal(t, 2, body.PageSize)
+
+		mu.Lock()
+		*pages = append(*pages, body.Page)
+		mu.Unlock()
+
+		var conversations []string
+		for i := 0; i < 2; i++ {
+			n := (body.Page-1)*2 + i + 1
+			epoch := int64(1709296200 + n*3600)
+			if body.Page%2 == 0 {
+				epoch *= 1000
+			}
+			conversations = append(conversations, fmt.Sprintf(`{"id":"conv_%d","assistant_id":"asst_123","create_time":%d,"update_time":%d,"usage":{"total_tokens":%d}}`, n, epoch, epoch, n*10))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"code":200,"msg":"success","data":{"assistant_id":"asst_123","has_more":%t,"conversation_list":[%s]}}`,
+			body.Page < 3, strings.Join(conversations, ","))
+	}
+}
+
+func TestAssistantService_QueryConversationUsageAll(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		pages []int
+	)
+	server := httptest.NewServer(usagePagesHandler(t, &pages, &mu))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	iter := client.Assistant.QueryConversationUsageAll(context.Background(), "asst_123", assistant.WithUsagePageSize(2))
+
+	// Pages are fetched lazily
+	mu.Lock()
+	assert.Empty(t, pages)
+	mu.Unlock()
+
+	var ids []string
+	for iter.Next() {
+		conv := iter.Current()
+		ids = append(ids, conv.ID)
+
+		// Seconds and milliseconds decode to the same hourly timestamps
+		n := len(ids)
+		want := time.Unix(int64(1709296200+n*3600), 0)
+		assert.True(t, want.Equal(conv.CreateTime), "%s: CreateTime = %v, want %v", conv.ID, conv.CreateTime, want)
+		assert.Equal(t, n*10, conv.Usage.TotalTokens)
+	}
+	require.NoError(t, iter.Err())
+
+	assert.Equal(t, []string{"conv_1", "conv_2", "conv_3", "conv_4", "conv_5", "conv_6"}, ids)
+	assert.Equal(t, []int{1, 2, 3}, pages)
+}
+
+// messagePagesHandler serves a conversation of three messages, two per
+// page, with text, tool and attachment blocks.
+func messagePagesHandler(t *testing.T, pages *[]int, mu *sync.Mutex) http.HandlerFunc {
+	messages := []string{
+		`{"id":"msg_1","role":"user","create_time":1709296200,"content":[{"type":"text","text":"Summarize the attached manual."},{"type":"file","file_id":"file_9","file_name":"manual.pdf"}]}`,
+		`{"id":"msg_2","role":"tool","create_time":1709296201000,"content":[{"type":"tool_calls","tool_calls":[{"id":"call_1","type":"function","function":{"name":"lookup","arguments":"{}","outputs":"ok"}}]}]}`,
+		`{"id":"msg_3","role":"assistant","create_time":1709296202,"content":[{"type":"content","role":"assistant","content":"Hold reset for 10 seconds."}]}`,
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/assistant/conversation/messages", r.URL.Path)
+
+		var body struct {
+			AssistantID    string `json:"assistant_id"`
+			ConversationID string `json:"conversation_id"`
+			Page           int    `json:"page"`
+			PageSize       int    `json:"page_size"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "asst_123", body.AssistantID)
+		assert.Equal(t, "conv_456", body.ConversationID)
+		assert.Equal(t, 2, body.PageSize)
+
+		mu.Lock()
+		*pages = append(*pages, body.Page)
+		mu.Unlock()
+
+		start := min((body.Page-1)*2, len(messages))
+		end := min(start+2, len(messages))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"code":200,"msg":"success","data":{"assistant_id":"asst_123","conversation_id":"conv_456","has_more":%t,"message_list":[%s]}}`,
+			end < len(messages), strings.Join(messages[start:end], ","))
+	}
+}
+
+func TestAssistantService_GetConversationMessages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		pages []int
+	)
+	server := httptest.NewServer(messagePagesHandler(t, &pages, &mu))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	resp, err := client.Assistant.GetConversationMessages(context.Background(), "asst_123", "conv_456", 1, 2)
+	require.NoError(t, err)
+	assert.True(t, resp.HasMore())
+	messages := resp.GetMessages()
+	require.Len(t, messages, 2)
+
+	assert.Equal(t, "user", messages[0].Role)
+	assert.Equal(t, "Summarize the attached manual.", messages[0].GetText())
+	assert.Equal(t, "file_9", messages[0].GetFiles()[0].FileID)
+	assert.True(t, time.Unix(1709296200, 0).Equal(messages[0].CreateTime))
+
+	assert.Equal(t, "tool", messages[1].Role)
+	require.Len(t, messages[1].GetToolCalls(), 1)
+	assert.Equal(t, "lookup", messages[1].GetToolCalls()[0].Function.Name)
+	assert.True(t, time.UnixMilli(1709296201000).Equal(messages[1].CreateTime))
+
+	// Missing IDs fail before a request is sent
+	_, err = client.Assistant.GetConversationMessages(context.Background(), "asst_123", "", 1, 2)
+	assert.True(t, errors.IsValidationError(err))
+	mu.Lock()
+	assert.Equal(t, []int{1}, pages)
+	mu.Unlock()
+}
+
+func TestAssistantService_GetConversationMessagesAll(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		pages []int
+	)
+	server := httptest.NewServer(messagePagesHandler(t, &pages, &mu))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	iter := client.Assistant.GetConversationMessagesAll(context.Background(), "asst_123", "conv_456", assistant.WithUsagePageSize(2))
+
+	var transcript []assistant.TranscriptMessage
+	for iter.Next() {
+		transcript = append(transcript, *iter.Current())
+	}
+	require.NoError(t, iter.Err())
+	require.Len(t, transcript, 3)
+	assert.Equal(t, "msg_3", transcript[2].ID)
+	assert.Equal(t, []int{1, 2}, pages)
+
+	// The tool event and the attachment are not replayed
+	assert.Equal(t, []chat.Message{
+		chat.NewUserMessage("Summarize the attached manual."),
+		chat.NewAssistantMessage("Hold reset for 10 seconds."),
+	}, assistant.ToChatMessages(transcript))
+}
+
+func TestAssistantService_QueryConversationUsageAll_Cancel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		pages []int
+	)
+	server := httptest.NewServer(usagePagesHandler(t, &pages, &mu))
+	defer server.Close()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	iter := client.Assistant.QueryConversationUsageAll(ctx, "asst_123",
+		assistant.WithStartPage(2), assistant.WithUsagePageSize(2))
+
+	require.True(t, iter.Next())
+	assert.Equal(t, "conv_3", iter.Current().ID)
+
+	// Cancelling stops the iteration before the next item or page
+	cancel()
+	assert.False(t, iter.Next())
+	assert.ErrorIs(t, iter.Err(), context.Canceled)
+	assert.Nil(t, iter.Current())
+
+	mu.Lock()
+	assert.Equal(t, []int{2}, pages)
+	mu.Unlock()
+}
+
 func TestAssistantService_CreateConversation(t *testing.T) {
 	t.Parallel()
 
@@ -567,3 +862,286 @@ func TestNewAssistantService(t *testing.T) {
 	// Verify Assistant service is initialized
 	assert.NotNil(t, client.Assistant)
 }
+
+// attachServer is a mock files and assistant API for
+// CreateConversationWithFiles.
+type attachServer struct {
+	t *testing.T
+
+	mu          sync.Mutex
+	failUpload  string            // file name whose upload fails
+	processing  map[string]bool   // file IDs still processing on first retrieval
+	deleted     []string          // deleted file IDs
+	attachments []string          // file IDs attached to the conversation
+	purposes    map[string]string // upload purpose by file name
+}
+
+func (s *attachServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/files":
+		require.NoError(s.t, r.ParseMultipartForm(1<<20))
+		name := r.MultipartForm.File["file"][0].Filename
+		s.purposes[name] = r.FormValue("purpose")
+		if name == s.failUpload {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"1214","message":"unsupported file"}}`))
+			return
+		}
+		id := "file-" + name
+		status := files.StatusUploaded
+		if s.processing[id] {
+			status = "processing"
+		}
+		json.NewEncoder(w).Encode(files.File{ID: id, Filename: name, Status: status})
+
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/files/"):
+		id := strings.TrimPrefix(r.URL.Path, "/files/")
+		json.NewEncoder(w).Encode(files.File{ID: id, Status: files.StatusProcessed})
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/files/"):
+		id := strings.TrimPrefix(r.URL.Path, "/files/")
+		s.deleted = append(s.deleted, id)
+		json.NewEncoder(w).Encode(files.FileDeleteResponse{ID: id, Deleted: true})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/assistant":
+		var req struct {
+			Attachments []assistant.AssistantAttachment `json:"attachments"`
+		}
+		require.NoError(s.t, json.NewDecoder(r.Body).Decode(&req))
+		for _, a := range req.Attachments {
+			s.attachments = append(s.attachments, a.FileID)
+		}
+		json.NewEncoder(w).Encode(assistant.AssistantCompletion{ID: "req-1", ConversationID: "conv-1", Status: "completed"})
+
+	default:
+		s.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newAttachTestClient creates a client against an attachServer.
+func newAttachTestClient(t *testing.T) (*Client, *attachServer) {
+	t.Helper()
+
+	mock := &attachServer{t: t, processing: map[string]bool{}, purposes: map[string]string{}}
+	server := httptest.NewServer(mock)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, mock
+}
+
+func TestAssistantService_CreateConversationWithFiles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mixed file IDs and uploads", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := newAttachTestClient(t)
+		mock.processing["file-b.pdf"] = true
+
+		resp, err := client.Assistant.CreateConversationWithFiles(context.Background(), "asst_123", "Compare them",
+			[]assistant.FileInput{
+				assistant.FileFromReader(strings.NewReader("a"), "a.pdf"),
+				assistant.FileFromID("file-existing"),
+				assistant.FileFromReader(strings.NewReader("b"), "b.pdf"),
+			},
+			assistant.WithFilePolling(time.Millisecond, time.Second),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "conv-1", resp.ConversationID)
+
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		assert.Equal(t, []string{"file-a.pdf", "file-existing", "file-b.pdf"}, mock.attachments)
+		assert.Equal(t, map[string]string{"a.pdf": "assistants", "b.pdf": "assistants"}, mock.purposes)
+		assert.Empty(t, mock.deleted)
+	})
+
+	t.Run("upload failure deletes uploaded files", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := newAttachTestClient(t)
+		mock.failUpload = "b.pdf"
+
+		_, err := client.Assistant.CreateConversationWithFiles(context.Background(), "asst_123", "Compare them",
+			[]assistant.FileInput{
+				assistant.FileFromReader(strings.NewReader("a"), "a.pdf"),
+				assistant.FileFromReader(strings.NewReader("b"), "b.pdf"),
+				assistant.FileFromID("file-existing"),
+			},
+			assistant.WithUploadConcurrency(1),
+		)
+		var fileErr *assistant.FileError
+		require.ErrorAs(t, err, &fileErr)
+		assert.Equal(t, 1, fileErr.Index)
+		assert.Equal(t, "b.pdf", fileErr.Filename)
+		assert.True(t, errors.IsRequestError(err))
+
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		assert.Empty(t, mock.attachments, "the conversation is not sent")
+		assert.Equal(t, []string{"file-a.pdf"}, mock.deleted, "only files uploaded by the call are deleted")
+	})
+
+	t.Run("keep uploaded files on failure", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := newAttachTestClient(t)
+		mock.failUpload = "b.pdf"
+
+		_, err := client.Assistant.CreateConversationWithFiles(context.Background(), "asst_123", "Compare them",
+			[]assistant.FileInput{
+				assistant.FileFromReader(strings.NewReader("a"), "a.pdf"),
+				assistant.FileFromReader(strings.NewReader("b"), "b.pdf"),
+			},
+			assistant.WithUploadConcurrency(1),
+			assistant.WithKeepOnFailure(),
+		)
+		require.Error(t, err)
+
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		assert.Empty(t, mock.deleted)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		client, mock := newAttachTestClient(t)
+
+		_, err := client.Assistant.CreateConversationWithFiles(context.Background(), "asst_123", "Compare them",
+			[]assistant.FileInput{assistant.FileFromID("file-existing"), {}})
+		var fileErr *assistant.FileError
+		require.ErrorAs(t, err, &fileErr)
+		assert.Equal(t, 1, fileErr.Index)
+
+		mock.mu.Lock()
+		defer mock.mu.Unlock()
+		assert.Empty(t, mock.attachments)
+	})
+}
+
+// assistantStreamEvent returns an SSE event with the given ID whose chunk
+// has text as content.
+func assistantStreamEvent(id int, text string) string {
+	return fmt.Sprintf("id: %d\ndata: {\"id\":\"req_1\",\"conversation_id\":\"conv_1\",\"status\":\"in_progress\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"type\":\"content\",\"content\":%q}}]}\n\n", id, text)
+}
+
+func TestAssistantService_ConversationStream_Reconnect(t *testing.T) {
+	t.Parallel()
+
+	words := []string{"Once ", "upon ", "a ", "time ", "there ", "was ", "a ", "fox."}
+
+	var (
+		mu           sync.Mutex
+		lastEventIDs []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		lastEventIDs = append(lastEventIDs, r.Header.Get("Last-Event-ID"))
+		attempt := len(lastEventIDs)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		if attempt == 1 {
+			// Send three events, then drop the connection
+			for i := 0; i < 3; i++ {
+				w.Write([]byte(assistantStreamEvent(i+1, words[i])))
+			}
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+			return
+		}
+
+		// Resume from the last event ID, replaying that event
+		from, err := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+		require.NoError(t, err)
+		for i := from - 1; i < len(words); i++ {
+			w.Write([]byte(assistantStreamEvent(i+1, words[i])))
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	warnings := &warningHooks{}
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithHooks(warnings),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := assistant.NewConversationRequest("asst_1", []assistant.ConversationMessage{
+		{Role: "user", Content: []assistant.MessageContent{assistant.MessageTextContent{Type: "text", Text: "Tell me a story"}}},
+	})
+
+	stream, err := client.Assistant.ConversationStream(context.Background(), req,
+		assistant.WithReconnect(2),
+		assistant.WithReconnectBackoff(time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var text strings.Builder
+	for stream.Next() {
+		text.WriteString(stream.Current().GetText())
+	}
+	require.NoError(t, stream.Err())
+
+	assert.Equal(t, strings.Join(words, ""), text.String(), "replayed events are skipped")
+	assert.Equal(t, []string{"", "3"}, lastEventIDs)
+
+	warnings.mu.Lock()
+	defer warnings.mu.Unlock()
+	require.Len(t, warnings.warnings, 1)
+	assert.Contains(t, warnings.warnings[0].Message, "attempt 1 of 2")
+}
+
+func TestAssistantService_ConversationStream_NoReconnect(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(assistantStreamEvent(1, "Once ")))
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		conn.Close()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := assistant.NewConversationRequest("asst_1", []assistant.ConversationMessage{
+		{Role: "user", Content: []assistant.MessageContent{assistant.MessageTextContent{Type: "text", Text: "Tell me a story"}}},
+	})
+
+	stream, err := client.Assistant.ConversationStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+	assert.Error(t, stream.Err(), "the dropped connection is reported")
+	assert.Equal(t, 1, requests)
+}
@@ -1,14 +1,15 @@
 package zai
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"mime/multipart"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // AudioService provides access to the Audio API.
@@ -23,7 +24,10 @@ func newAudioService(baseClient *client.BaseClient) *AudioService {
 	}
 }
 
-// Transcribe transcribes audio to text.
+// Transcribe transcribes audio to text. The file is checked before it is
+// uploaded, see audio.TranscriptionRequest.Validate: a file that is not in
+// a supported format, or is over the size or duration limit, fails with a
+// *errors.ValidationError without being sent.
 //
 // Example:
 //
@@ -45,66 +49,116 @@ func newAudioService(baseClient *client.BaseClient) *AudioService {
 //	fmt.Printf("Language: %s\n", resp.GetLanguage())
 //	fmt.Printf("Duration: %.2f seconds\n", resp.GetDuration())
 func (s *AudioService) Transcribe(ctx context.Context, req *audio.TranscriptionRequest) (*audio.TranscriptionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.postAudioForm(ctx, s.client.Endpoints().Path(endpoints.AudioTranscriptions), &audioForm{
+		file:           req.File,
+		filename:       req.Filename,
+		model:          req.Model,
+		language:       req.Language,
+		prompt:         req.Prompt,
+		responseFormat: req.ResponseFormat,
+		temperature:    req.Temperature,
+		maxSize:        req.FileSizeLimit(),
+	})
+}
+
+// Translate translates audio in any supported language into English text.
+//
+// Example:
+//
+//	file, err := os.Open("ticket.mp3")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer file.Close()
+//
+//	req := audio.NewTranslationRequest(file, "ticket.mp3", audio.ModelWhisper1)
+//	req.SetResponseFormat(audio.ResponseFormatVerboseJSON)
+//
+//	resp, err := client.Audio.Translate(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Translation: %s\n", resp.GetText())
+func (s *AudioService) Translate(ctx context.Context, req *audio.TranslationRequest) (*audio.TranscriptionResponse, error) {
+	return s.postAudioForm(ctx, s.client.Endpoints().Path(endpoints.AudioTranslations), &audioForm{
+		file:           req.File,
+		filename:       req.Filename,
+		model:          req.Model,
+		prompt:         req.Prompt,
+		responseFormat: req.ResponseFormat,
+		temperature:    req.Temperature,
+	})
+}
+
+// audioForm holds the multipart fields shared by transcription and translation.
+type audioForm struct {
+	file           io.Reader
+	filename       string
+	model          audio.TranscriptionModel
+	language       string
+	prompt         string
+	responseFormat audio.ResponseFormat
+	temperature    *float64
+
+	// maxSize rejects files larger than maxSize bytes. Zero means no
+	// limit.
+	maxSize int64
+}
+
+// postAudioForm uploads an audio file as multipart form data and parses the
+// response according to the requested format.
+func (s *AudioService) postAudioForm(ctx context.Context, path string, form *audioForm) (*audio.TranscriptionResponse, error) {
 	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	body := client.NewForm()
 
 	// Add the model field
-	if err := writer.WriteField("model", string(req.Model)); err != nil {
-		return nil, fmt.Errorf("failed to write model field: %w", err)
-	}
+	body.WriteField("model", string(form.model))
 
 	// Add optional fields
-	if req.Language != "" {
-		if err := writer.WriteField("language", req.Language); err != nil {
-			return nil, fmt.Errorf("failed to write language field: %w", err)
-		}
+	if form.language != "" {
+		body.WriteField("language", form.language)
 	}
 
-	if req.Prompt != "" {
-		if err := writer.WriteField("prompt", req.Prompt); err != nil {
-			return nil, fmt.Errorf("failed to write prompt field: %w", err)
-		}
+	if form.prompt != "" {
+		body.WriteField("prompt", form.prompt)
 	}
 
-	if req.ResponseFormat != "" {
-		if err := writer.WriteField("response_format", string(req.ResponseFormat)); err != nil {
-			return nil, fmt.Errorf("failed to write response_format field: %w", err)
-		}
+	if form.responseFormat != "" {
+		body.WriteField("response_format", string(form.responseFormat))
 	}
 
-	if req.Temperature != nil {
-		if err := writer.WriteField("temperature", fmt.Sprintf("%f", *req.Temperature)); err != nil {
-			return nil, fmt.Errorf("failed to write temperature field: %w", err)
-		}
+	if form.temperature != nil {
+		body.WriteField("temperature", fmt.Sprintf("%f", *form.temperature))
 	}
 
 	// Add the audio file
-	part, err := writer.CreateFormFile("file", req.Filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	// Copy file content to the form
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Close the writer to finalize the multipart message
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
-	}
+	body.AddFile(&client.FormFile{
+		Field:    "file",
+		Filename: form.filename,
+		Reader:   form.file,
+		MaxSize:  form.maxSize,
+		TooLarge: func(size int64) error {
+			return audio.NewFileTooLargeError(size, form.maxSize)
+		},
+	})
 
-	// Make the API request using PostMultipart
-	apiResp, err := s.client.PostMultipart(ctx, "/audio/transcriptions", &buf, writer.FormDataContentType())
+	// Make the API request
+	apiResp, err := s.client.PostForm(ctx, path, body)
 	if err != nil {
 		return nil, err
 	}
 
 	// Handle different response formats
-	if req.ResponseFormat == audio.ResponseFormatText ||
-		req.ResponseFormat == audio.ResponseFormatVTT ||
-		req.ResponseFormat == audio.ResponseFormatSRT {
+	if form.responseFormat == audio.ResponseFormatText ||
+		form.responseFormat == audio.ResponseFormatVTT ||
+		form.responseFormat == audio.ResponseFormatSRT {
+		defer apiResp.Close()
+
 		// For text-based formats, read as plain text
 		content, err := io.ReadAll(apiResp.Body)
 		if err != nil {
@@ -148,6 +202,29 @@ func (s *AudioService) TranscribeFile(ctx context.Context, file io.Reader, filen
 	return resp.GetText(), nil
 }
 
+// TranslateFile is a convenience method to translate an audio file into
+// English with default settings. Returns the translated text.
+//
+// Example:
+//
+//	file, _ := os.Open("ticket.mp3")
+//	text, err := client.Audio.TranslateFile(ctx, file, "ticket.mp3")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Translation: %s\n", text)
+func (s *AudioService) TranslateFile(ctx context.Context, file io.Reader, filename string) (string, error) {
+	req := audio.NewTranslationRequest(file, filename, audio.ModelWhisper1)
+
+	resp, err := s.Translate(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetText(), nil
+}
+
 // TranscribeWithSegments transcribes audio and returns detailed segments with timestamps.
 // This is useful for generating subtitles or analyzing speech patterns.
 //
@@ -175,3 +252,89 @@ func (s *AudioService) TranscribeWithSegments(ctx context.Context, file io.Reade
 
 	return s.Transcribe(ctx, req)
 }
+
+// Speech synthesizes speech from text.
+//
+// Example:
+//
+//	req := audio.NewSpeechRequest(audio.ModelCogTTS, "Hello there!", audio.VoiceTongtong)
+//	req.SetResponseFormat(audio.SpeechFormatMP3).SetSpeed(1.1)
+//
+//	resp, err := client.Audio.Speech(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if err := resp.SaveTo("hello.mp3"); err != nil {
+//	    // Handle error
+//	}
+func (s *AudioService) Speech(ctx context.Context, req *audio.SpeechRequest) (*audio.SpeechResponse, error) {
+	copied := *req
+	copied.Stream = false
+
+	// Make the API request
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.AudioSpeech), &copied)
+	if err != nil {
+		return nil, err
+	}
+	defer apiResp.Close()
+
+	// Read the raw audio data
+	data, err := io.ReadAll(apiResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audio data: %w", err)
+	}
+
+	return &audio.SpeechResponse{
+		Audio:       data,
+		ContentType: apiResp.Headers.Get("Content-Type"),
+	}, nil
+}
+
+// SpeechStream synthesizes speech from text and streams audio chunks as
+// they are produced, so playback can start before synthesis finishes.
+//
+// Example:
+//
+//	req := audio.NewSpeechRequest(audio.ModelCogTTS, longText, audio.VoiceTongtong)
+//	req.SetResponseFormat(audio.SpeechFormatPCM)
+//
+//	stream, err := client.Audio.SpeechStream(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//	    data, err := stream.Current().GetAudio()
+//	    if err != nil {
+//	        // Handle error
+//	    }
+//	    player.Write(data)
+//	}
+//
+//	if err := stream.Err(); err != nil {
+//	    // Handle stream error
+//	}
+func (s *AudioService) SpeechStream(ctx context.Context, req *audio.SpeechRequest) (*streaming.Stream[audio.SpeechChunk], error) {
+	// Ensure stream is enabled and chunks are base64 encoded, without
+	// changing the caller's request
+	copied := *req
+	copied.Stream = true
+	if copied.EncodeFormat == "" {
+		copied.EncodeFormat = "base64"
+	}
+	if copied.EncodeFormat != "base64" {
+		return nil, errors.NewValidationError("encode_format",
+			`must be "base64", the encoding SpeechChunk.GetAudio decodes`, copied.EncodeFormat)
+	}
+
+	// Make the streaming request
+	streamResp, err := s.client.Stream(ctx, s.client.Endpoints().Path(endpoints.AudioSpeech), &copied)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create typed stream
+	return client.NewTypedStream[audio.SpeechChunk](streamResp, ctx), nil
+}
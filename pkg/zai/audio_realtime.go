@@ -0,0 +1,420 @@
+package zai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/websocket"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// ErrSessionClosed is returned by SendAudio after the session is closed.
+var ErrSessionClosed = stderrors.New("transcription session is closed")
+
+// TranscribeStream opens a realtime transcription session over a
+// WebSocket. Audio is sent in chunks with SendAudio while partial and
+// final transcript segments are read with Next and Current, typically
+// from another goroutine.
+//
+// SendAudio blocks once opts.MaxInFlight chunks are waiting to be
+// acknowledged by the server, so a caller producing audio faster than the
+// server accepts it is slowed down instead of queueing without bound.
+// Canceling ctx ends the session, and Err then returns the context error.
+//
+// Example:
+//
+//	session, err := client.Audio.TranscribeStream(ctx, audio.NewTranscriptionStreamOptions(audio.ModelGLMASR))
+//	if err != nil {
+//	    return err
+//	}
+//
+//	go func() {
+//	    for chunk := range microphone {
+//	        if err := session.SendAudio(chunk); err != nil {
+//	            break
+//	        }
+//	    }
+//	    session.Close() // flushes the final results
+//	}()
+//
+//	for session.Next() {
+//	    segment := session.Current()
+//	    if segment.Final {
+//	        fmt.Printf("[%s] %s\n", segment.Start, segment.Text)
+//	    }
+//	}
+//	if err := session.Err(); err != nil {
+//	    return err
+//	}
+func (s *AudioService) TranscribeStream(ctx context.Context, opts *audio.TranscriptionStreamOptions) (*TranscriptionSession, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	resolved := *opts
+	if resolved.AudioFormat == "" {
+		resolved.AudioFormat = audio.StreamAudioFormatPCM16
+	}
+	if resolved.SampleRate == 0 {
+		resolved.SampleRate = audio.DefaultStreamSampleRate
+	}
+	if resolved.MaxInFlight == 0 {
+		resolved.MaxInFlight = audio.DefaultMaxInFlightChunks
+	}
+	if resolved.FlushTimeout == 0 {
+		resolved.FlushTimeout = audio.DefaultFlushTimeout
+	}
+
+	conn, err := s.client.DialWebSocket(ctx, s.client.Endpoints().Path(endpoints.AudioRealtime))
+	if err != nil {
+		return nil, err
+	}
+
+	session := &TranscriptionSession{
+		ctx:       ctx,
+		client:    s.client,
+		conn:      conn,
+		opts:      resolved,
+		window:    make(chan struct{}, resolved.MaxInFlight),
+		pending:   make(map[string]struct{}),
+		partial:   make(map[string]string),
+		ready:     make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		committed: make(chan struct{}),
+	}
+
+	err = session.send(&realtimeClientEvent{
+		Type: audio.EventSessionUpdate,
+		Session: &realtimeSessionConfig{
+			Model:       resolved.Model,
+			Language:    resolved.Language,
+			AudioFormat: resolved.AudioFormat,
+			SampleRate:  resolved.SampleRate,
+		},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	go session.read()
+	return session, nil
+}
+
+// realtimeClientEvent is an event sent to a realtime transcription session.
+type realtimeClientEvent struct {
+	Type    string                 `json:"type"`
+	EventID string                 `json:"event_id,omitempty"`
+	Audio   string                 `json:"audio,omitempty"`
+	Session *realtimeSessionConfig `json:"session,omitempty"`
+}
+
+// realtimeSessionConfig is the configuration sent when a session opens.
+type realtimeSessionConfig struct {
+	Model       audio.TranscriptionModel `json:"model"`
+	Language    string                   `json:"language,omitempty"`
+	AudioFormat audio.StreamAudioFormat  `json:"input_audio_format"`
+	SampleRate  int                      `json:"sample_rate"`
+}
+
+// TranscriptionSession is a realtime transcription session opened by
+// AudioService.TranscribeStream. SendAudio and Close may be called from one
+// goroutine while another reads segments with Next.
+type TranscriptionSession struct {
+	ctx    context.Context
+	client *client.BaseClient
+	conn   *websocket.Conn
+	opts   audio.TranscriptionStreamOptions
+
+	// window holds a token for every unacknowledged chunk
+	window chan struct{}
+
+	mu       sync.Mutex
+	nextID   int
+	pending  map[string]struct{}
+	partial  map[string]string
+	segments []*audio.TranscriptSegment
+	finished bool
+	err      error
+
+	// ready is signaled when segments are queued or reading stops
+	ready chan struct{}
+
+	// done is closed when reading stops
+	done chan struct{}
+
+	// committed is closed when the server has sent the final results
+	committed     chan struct{}
+	committedOnce sync.Once
+
+	// closing rejects new audio; hangup expects the connection to end
+	closing   atomic.Bool
+	hangup    atomic.Bool
+	closeOnce sync.Once
+	closeErr  error
+
+	current *audio.TranscriptSegment
+}
+
+// SendAudio sends a chunk of audio. It blocks while the maximum number of
+// chunks is waiting to be acknowledged, and fails with ErrSessionClosed
+// once Close is called, or with the error that ended the session.
+func (t *TranscriptionSession) SendAudio(chunk []byte) error {
+	if len(chunk) == 0 {
+		return nil
+	}
+	if err := t.Err(); err != nil {
+		return err
+	}
+	if t.closing.Load() {
+		return ErrSessionClosed
+	}
+
+	select {
+	case t.window <- struct{}{}:
+	case <-t.done:
+		if err := t.Err(); err != nil {
+			return err
+		}
+		return ErrSessionClosed
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	}
+
+	t.mu.Lock()
+	t.nextID++
+	id := fmt.Sprintf("audio_%d", t.nextID)
+	t.pending[id] = struct{}{}
+	t.mu.Unlock()
+
+	return t.send(&realtimeClientEvent{
+		Type:    audio.EventAudioAppend,
+		EventID: id,
+		Audio:   base64.StdEncoding.EncodeToString(chunk),
+	})
+}
+
+// Next waits for the next transcript segment. It returns false once the
+// session has ended and every segment was read.
+func (t *TranscriptionSession) Next() bool {
+	for {
+		t.mu.Lock()
+		if len(t.segments) > 0 {
+			t.current = t.segments[0]
+			t.segments = t.segments[1:]
+			t.mu.Unlock()
+			return true
+		}
+		finished := t.finished
+		t.mu.Unlock()
+
+		if finished {
+			return false
+		}
+		<-t.ready
+	}
+}
+
+// Current returns the current segment.
+func (t *TranscriptionSession) Current() *audio.TranscriptSegment {
+	return t.current
+}
+
+// Err returns the error that ended the session, or nil if it was closed
+// normally.
+func (t *TranscriptionSession) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Close ends the audio and waits up to the flush timeout for the final
+// results, which remain readable with Next, then closes the connection.
+// It returns the error that ended the session, or an error if the final
+// results could not be flushed.
+func (t *TranscriptionSession) Close() error {
+	t.closeOnce.Do(func() {
+		t.closing.Store(true)
+		t.closeErr = t.flush()
+
+		t.hangup.Store(true)
+		t.conn.WriteClose(websocket.CloseNormal, "")
+		timer := time.NewTimer(t.opts.FlushTimeout)
+		select {
+		case <-t.done:
+		case <-timer.C:
+		}
+		timer.Stop()
+		t.conn.Close()
+		<-t.done
+	})
+	return t.closeErr
+}
+
+// flush commits the audio and waits for the final results.
+func (t *TranscriptionSession) flush() error {
+	select {
+	case <-t.done:
+		return t.Err()
+	default:
+	}
+
+	if err := t.send(&realtimeClientEvent{Type: audio.EventAudioCommit}); err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(t.opts.FlushTimeout)
+	defer timer.Stop()
+	select {
+	case <-t.committed:
+		return nil
+	case <-t.done:
+		return t.Err()
+	case <-t.ctx.Done():
+		return t.ctx.Err()
+	case <-timer.C:
+		return fmt.Errorf("timed out after %s waiting for the final transcription results", t.opts.FlushTimeout)
+	}
+}
+
+// send writes a client event.
+func (t *TranscriptionSession) send(event *realtimeClientEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s event: %w", event.Type, err)
+	}
+	if err := t.conn.WriteMessage(websocket.OpText, data); err != nil {
+		return t.cause(fmt.Errorf("failed to send %s event: %w", event.Type, err))
+	}
+	return nil
+}
+
+// read handles server events until the connection ends.
+func (t *TranscriptionSession) read() {
+	defer close(t.done)
+
+	for {
+		_, data, err := t.conn.ReadMessage()
+		if err != nil {
+			t.finish(err)
+			return
+		}
+
+		var event audio.RealtimeEvent
+		if err := json.Unmarshal(data, &event); err != nil {
+			t.fail(fmt.Errorf("failed to decode transcription event: %w", err))
+			return
+		}
+
+		switch event.Type {
+		case audio.EventAudioAppended:
+			t.acknowledge(event.EventID)
+		case audio.EventTranscriptionDelta:
+			t.mu.Lock()
+			t.partial[event.ItemID] += event.Delta
+			t.queue(segment(&event, t.partial[event.ItemID], false))
+			t.mu.Unlock()
+		case audio.EventTranscriptionCompleted:
+			t.mu.Lock()
+			delete(t.partial, event.ItemID)
+			t.queue(segment(&event, event.Transcript, true))
+			t.mu.Unlock()
+		case audio.EventAudioCommitted:
+			t.committedOnce.Do(func() { close(t.committed) })
+		case audio.EventError:
+			message := "transcription session failed"
+			if event.Error != nil && event.Error.Message != "" {
+				message = event.Error.Message
+			}
+			t.fail(errors.NewAPIResponseError(message, nil, event))
+			return
+		}
+	}
+}
+
+// acknowledge frees the window slot of the chunk with the given event ID.
+// Acknowledgements may arrive in any order; unknown IDs are ignored.
+func (t *TranscriptionSession) acknowledge(id string) {
+	t.mu.Lock()
+	_, ok := t.pending[id]
+	delete(t.pending, id)
+	t.mu.Unlock()
+
+	if ok {
+		<-t.window
+	}
+}
+
+// queue adds a segment for Next. The caller holds t.mu.
+func (t *TranscriptionSession) queue(s *audio.TranscriptSegment) {
+	t.segments = append(t.segments, s)
+	t.signal()
+}
+
+// signal wakes a waiting Next.
+func (t *TranscriptionSession) signal() {
+	select {
+	case t.ready <- struct{}{}:
+	default:
+	}
+}
+
+// fail ends the session with err and closes the connection.
+func (t *TranscriptionSession) fail(err error) {
+	t.mu.Lock()
+	t.err = err
+	t.finished = true
+	t.signal()
+	t.mu.Unlock()
+	t.conn.Close()
+}
+
+// finish ends the session after the connection ended with err. Endings
+// expected after Close, or a normal close by the server, are not errors.
+func (t *TranscriptionSession) finish(err error) {
+	var closeErr *websocket.CloseError
+	if t.hangup.Load() || (stderrors.As(err, &closeErr) && closeErr.Code == websocket.CloseNormal) {
+		err = nil
+	} else {
+		err = t.cause(err)
+	}
+
+	t.mu.Lock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.finished = true
+	t.signal()
+	t.mu.Unlock()
+}
+
+// cause returns the context or client closed error that ended the
+// session, if any, or err.
+func (t *TranscriptionSession) cause(err error) error {
+	if ctxErr := t.ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if closedErr := t.client.Err(); closedErr != nil {
+		return closedErr
+	}
+	return err
+}
+
+// segment converts a transcription event to a segment with text.
+func segment(event *audio.RealtimeEvent, text string, final bool) *audio.TranscriptSegment {
+	return &audio.TranscriptSegment{
+		ItemID: event.ItemID,
+		Text:   text,
+		Start:  time.Duration(event.StartMs) * time.Millisecond,
+		End:    time.Duration(event.EndMs) * time.Millisecond,
+		Final:  final,
+	}
+}
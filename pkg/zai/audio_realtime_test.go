@@ -0,0 +1,312 @@
+package zai
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/websocket"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// newRealtimeTestClient returns a client whose realtime endpoint upgrades
+// the connection and hands it to serve, after checking the session
+// configuration.
+func newRealtimeTestClient(t *testing.T, serve func(conn *websocket.Conn)) *Client {
+	return newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/realtime", r.URL.Path)
+		assert.Contains(t, r.Header.Get("Authorization"), "test-key.test-secret")
+
+		conn, err := websocket.Upgrade(w, r)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		update := readClientEvent(t, conn)
+		assert.Equal(t, audio.EventSessionUpdate, update.Type)
+		if assert.NotNil(t, update.Session) {
+			assert.Equal(t, audio.ModelGLMASR, update.Session.Model)
+			assert.Equal(t, audio.StreamAudioFormatPCM16, update.Session.AudioFormat)
+			assert.Equal(t, audio.DefaultStreamSampleRate, update.Session.SampleRate)
+		}
+		serve(conn)
+	})
+}
+
+// readClientEvent reads an event sent by the session.
+func readClientEvent(t *testing.T, conn *websocket.Conn) *realtimeClientEvent {
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		return &realtimeClientEvent{}
+	}
+	var event realtimeClientEvent
+	assert.NoError(t, json.Unmarshal(data, &event))
+	return &event
+}
+
+// writeServerEvent sends an event to the session.
+func writeServerEvent(t *testing.T, conn *websocket.Conn, event audio.RealtimeEvent) {
+	data, err := json.Marshal(event)
+	assert.NoError(t, err)
+	assert.NoError(t, conn.WriteMessage(websocket.OpText, data))
+}
+
+// readSegments reads every segment of the session.
+func readSegments(session *TranscriptionSession) []audio.TranscriptSegment {
+	var segments []audio.TranscriptSegment
+	for session.Next() {
+		segments = append(segments, *session.Current())
+	}
+	return segments
+}
+
+func TestAudioService_TranscribeStream(t *testing.T) {
+	t.Parallel()
+
+	acked := make(chan struct{})
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		var ids []string
+		for range 3 {
+			event := readClientEvent(t, conn)
+			assert.Equal(t, audio.EventAudioAppend, event.Type)
+			ids = append(ids, event.EventID)
+		}
+
+		// Acknowledge out of order
+		for _, i := range []int{2, 0, 1} {
+			writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioAppended, EventID: ids[i]})
+		}
+		close(acked)
+
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionDelta, ItemID: "item-1", Delta: "Hello", StartMs: 0, EndMs: 400})
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionDelta, ItemID: "item-1", Delta: " wor", StartMs: 0, EndMs: 800})
+
+		commit := readClientEvent(t, conn)
+		assert.Equal(t, audio.EventAudioCommit, commit.Type)
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionCompleted, ItemID: "item-1", Transcript: "Hello world", StartMs: 0, EndMs: 1200})
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioCommitted})
+
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		assert.ErrorAs(t, err, &closeErr)
+	})
+
+	opts := audio.NewTranscriptionStreamOptions(audio.ModelGLMASR).SetMaxInFlight(3)
+	session, err := client.Audio.TranscribeStream(context.Background(), opts)
+	require.NoError(t, err)
+
+	for range 3 {
+		require.NoError(t, session.SendAudio([]byte{1, 2, 3, 4}))
+	}
+	<-acked
+
+	// Every chunk was acknowledged, so the window is free again
+	sent := make(chan error, 1)
+	go func() { sent <- session.SendAudio(nil) }()
+	require.NoError(t, <-sent)
+
+	closed := make(chan error, 1)
+	go func() { closed <- session.Close() }()
+
+	segments := readSegments(session)
+	require.NoError(t, <-closed)
+	require.NoError(t, session.Err())
+
+	assert.Equal(t, []audio.TranscriptSegment{
+		{ItemID: "item-1", Text: "Hello", End: 400 * time.Millisecond},
+		{ItemID: "item-1", Text: "Hello wor", End: 800 * time.Millisecond},
+		{ItemID: "item-1", Text: "Hello world", End: 1200 * time.Millisecond, Final: true},
+	}, segments)
+
+	assert.ErrorIs(t, session.SendAudio([]byte{1}), ErrSessionClosed)
+}
+
+func TestAudioService_TranscribeStream_Backpressure(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		first := readClientEvent(t, conn)
+		second := readClientEvent(t, conn)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("chunk-2")), second.Audio)
+
+		<-release
+		// The later chunk is acknowledged first, which frees a slot
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioAppended, EventID: second.EventID})
+
+		third := readClientEvent(t, conn)
+		assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("chunk-3")), third.Audio)
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioAppended, EventID: first.EventID})
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioAppended, EventID: third.EventID})
+
+		readClientEvent(t, conn)
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioCommitted})
+		conn.ReadMessage()
+	})
+
+	opts := audio.NewTranscriptionStreamOptions(audio.ModelGLMASR).SetMaxInFlight(2)
+	session, err := client.Audio.TranscribeStream(context.Background(), opts)
+	require.NoError(t, err)
+
+	require.NoError(t, session.SendAudio([]byte("chunk-1")))
+	require.NoError(t, session.SendAudio([]byte("chunk-2")))
+
+	sent := make(chan error, 1)
+	go func() { sent <- session.SendAudio([]byte("chunk-3")) }()
+
+	select {
+	case err := <-sent:
+		t.Fatalf("SendAudio returned %v before a chunk was acknowledged", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case err := <-sent:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("SendAudio is still blocked after an acknowledgement")
+	}
+
+	require.NoError(t, session.Close())
+	assert.Empty(t, readSegments(session))
+}
+
+func TestAudioService_TranscribeStream_CloseFlushesFinalResults(t *testing.T) {
+	t.Parallel()
+
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		chunk := readClientEvent(t, conn)
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioAppended, EventID: chunk.EventID})
+
+		// The final results are only sent once the audio is committed
+		assert.Equal(t, audio.EventAudioCommit, readClientEvent(t, conn).Type)
+		time.Sleep(20 * time.Millisecond)
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionCompleted, ItemID: "item-1", Transcript: "first", StartMs: 0, EndMs: 500})
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionCompleted, ItemID: "item-2", Transcript: "second", StartMs: 500, EndMs: 900})
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventAudioCommitted})
+		conn.ReadMessage()
+	})
+
+	session, err := client.Audio.TranscribeStream(context.Background(), audio.NewTranscriptionStreamOptions(audio.ModelGLMASR))
+	require.NoError(t, err)
+	require.NoError(t, session.SendAudio([]byte("audio")))
+	require.NoError(t, session.Close())
+
+	segments := readSegments(session)
+	require.NoError(t, session.Err())
+	require.Len(t, segments, 2)
+	assert.Equal(t, "first", segments[0].Text)
+	assert.Equal(t, "second", segments[1].Text)
+	assert.Equal(t, 500*time.Millisecond, segments[1].Start)
+	assert.True(t, segments[1].Final)
+}
+
+func TestAudioService_TranscribeStream_FlushTimeout(t *testing.T) {
+	t.Parallel()
+
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		// Never commits
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	opts := audio.NewTranscriptionStreamOptions(audio.ModelGLMASR).SetFlushTimeout(20 * time.Millisecond)
+	session, err := client.Audio.TranscribeStream(context.Background(), opts)
+	require.NoError(t, err)
+
+	err = session.Close()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "final transcription results")
+	assert.False(t, session.Next())
+}
+
+func TestAudioService_TranscribeStream_ContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := audio.NewTranscriptionStreamOptions(audio.ModelGLMASR).SetMaxInFlight(1)
+	session, err := client.Audio.TranscribeStream(ctx, opts)
+	require.NoError(t, err)
+
+	// The chunk is never acknowledged, so the next one blocks until the
+	// context is canceled
+	require.NoError(t, session.SendAudio([]byte("audio")))
+	sent := make(chan error, 1)
+	go func() { sent <- session.SendAudio([]byte("audio")) }()
+
+	cancel()
+	assert.ErrorIs(t, <-sent, context.Canceled)
+	assert.False(t, session.Next())
+	assert.ErrorIs(t, session.Err(), context.Canceled)
+	assert.ErrorIs(t, session.Close(), context.Canceled)
+}
+
+func TestAudioService_TranscribeStream_ErrorEvent(t *testing.T) {
+	t.Parallel()
+
+	client := newRealtimeTestClient(t, func(conn *websocket.Conn) {
+		writeServerEvent(t, conn, audio.RealtimeEvent{Type: audio.EventTranscriptionDelta, ItemID: "item-1", Delta: "partial"})
+		writeServerEvent(t, conn, audio.RealtimeEvent{
+			Type:  audio.EventError,
+			Error: &audio.RealtimeError{Code: "invalid_audio", Message: "unsupported sample rate"},
+		})
+		conn.ReadMessage()
+	})
+
+	session, err := client.Audio.TranscribeStream(context.Background(), audio.NewTranscriptionStreamOptions(audio.ModelGLMASR))
+	require.NoError(t, err)
+
+	segments := readSegments(session)
+	require.Len(t, segments, 1, "segments received before the error are kept")
+	assert.False(t, segments[0].Final)
+
+	var apiErr *errors.APIResponseError
+	require.ErrorAs(t, session.Err(), &apiErr)
+	assert.Contains(t, apiErr.Error(), "unsupported sample rate")
+	assert.ErrorAs(t, session.SendAudio([]byte("audio")), &apiErr)
+	assert.ErrorAs(t, session.Close(), &apiErr)
+}
+
+func TestAudioService_TranscribeStream_HandshakeRejected(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":"1000","message":"invalid api key"}}`))
+	})
+
+	_, err := client.Audio.TranscribeStream(context.Background(), audio.NewTranscriptionStreamOptions(audio.ModelGLMASR))
+	assert.True(t, errors.IsAuthenticationError(err), "got %v", err)
+}
+
+func TestAudioService_TranscribeStream_Validation(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is sent for invalid options")
+	})
+
+	_, err := client.Audio.TranscribeStream(context.Background(), &audio.TranscriptionStreamOptions{})
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+}
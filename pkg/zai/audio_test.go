@@ -1,19 +1,28 @@
 package zai
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
 )
 
+// mp3Tag is an empty ID3v2 tag, which makes the content after it pass the
+// format check as an MP3 file.
+const mp3Tag = "ID3\x04\x00\x00\x00\x00\x00\x00"
+
 func TestAudioService_Transcribe_JSON(t *testing.T) {
 	t.Parallel()
 
@@ -44,7 +53,7 @@ func TestAudioService_Transcribe_JSON(t *testing.T) {
 		// Read file content
 		content, err := io.ReadAll(file)
 		require.NoError(t, err)
-		assert.Equal(t, "audio content", string(content))
+		assert.Equal(t, mp3Tag+"audio content", string(content))
 
 		// Send JSON response
 		resp := audio.TranscriptionResponse{
@@ -68,7 +77,7 @@ func TestAudioService_Transcribe_JSON(t *testing.T) {
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("audio content")
+	file := strings.NewReader(mp3Tag + "audio content")
 	req := audio.NewTranscriptionRequest(file, "audio.mp3", audio.ModelWhisper1)
 	req.SetLanguage("en").
 		SetPrompt("AI conversation").
@@ -135,7 +144,7 @@ func TestAudioService_Transcribe_VerboseJSON(t *testing.T) {
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("audio content")
+	file := strings.NewReader(mp3Tag + "audio content")
 	req := audio.NewTranscriptionRequest(file, "audio.mp3", audio.ModelWhisper1)
 	req.SetResponseFormat(audio.ResponseFormatVerboseJSON)
 
@@ -186,7 +195,7 @@ func TestAudioService_Transcribe_Text(t *testing.T) {
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("audio content")
+	file := strings.NewReader(mp3Tag + "audio content")
 	req := audio.NewTranscriptionRequest(file, "audio.mp3", audio.ModelWhisper1)
 	req.SetResponseFormat(audio.ResponseFormatText)
 
@@ -233,7 +242,7 @@ world`
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("audio content")
+	file := strings.NewReader(mp3Tag + "audio content")
 	req := audio.NewTranscriptionRequest(file, "audio.mp3", audio.ModelWhisper1)
 	req.SetResponseFormat(audio.ResponseFormatVTT)
 
@@ -280,7 +289,7 @@ world`
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("audio content")
+	file := strings.NewReader(mp3Tag + "audio content")
 	req := audio.NewTranscriptionRequest(file, "audio.mp3", audio.ModelWhisper1)
 	req.SetResponseFormat(audio.ResponseFormatSRT)
 
@@ -331,7 +340,7 @@ func TestAudioService_TranscribeFile(t *testing.T) {
 	defer client.Close()
 
 	// Make request
-	file := strings.NewReader("interview audio")
+	file := strings.NewReader(mp3Tag + "interview audio")
 	text, err := client.Audio.TranscribeFile(context.Background(), file, "interview.mp3")
 	require.NoError(t, err)
 
@@ -394,7 +403,7 @@ func TestAudioService_TranscribeWithSegments(t *testing.T) {
 	defer client.Close()
 
 	// Make request
-	file := strings.NewReader("podcast audio")
+	file := strings.NewReader(mp3Tag + "podcast audio")
 	resp, err := client.Audio.TranscribeWithSegments(
 		context.Background(),
 		file,
@@ -452,7 +461,7 @@ func TestAudioService_TranscribeWithSegments_NoLanguage(t *testing.T) {
 	defer client.Close()
 
 	// Make request without language
-	file := strings.NewReader("audio")
+	file := strings.NewReader(mp3Tag + "audio")
 	resp, err := client.Audio.TranscribeWithSegments(
 		context.Background(),
 		file,
@@ -484,7 +493,7 @@ func TestAudioService_Transcribe_APIError(t *testing.T) {
 	defer client.Close()
 
 	// Create request
-	file := strings.NewReader("invalid audio")
+	file := strings.NewReader(mp3Tag + "invalid audio")
 	req := audio.NewTranscriptionRequest(file, "invalid.mp3", audio.ModelWhisper1)
 
 	// Make request
@@ -528,7 +537,7 @@ func TestAudioService_Transcribe_MinimalRequest(t *testing.T) {
 	defer client.Close()
 
 	// Create minimal request
-	file := strings.NewReader("audio")
+	file := strings.NewReader(mp3Tag + "audio")
 	req := audio.NewTranscriptionRequest(file, "test.mp3", audio.ModelWhisper1)
 
 	// Make request
@@ -576,7 +585,7 @@ func TestAudioService_Transcribe_AllOptionalFields(t *testing.T) {
 	defer client.Close()
 
 	// Create request with all optional fields
-	file := strings.NewReader("spanish audio")
+	file := strings.NewReader(mp3Tag + "spanish audio")
 	req := audio.NewTranscriptionRequest(file, "spanish.mp3", audio.ModelWhisper1)
 	req.SetLanguage("es").
 		SetPrompt("Spanish conversation about technology").
@@ -617,7 +626,7 @@ func TestAudioService_Transcribe_ContextCancellation(t *testing.T) {
 	cancel() // Cancel immediately
 
 	// Create request
-	file := strings.NewReader("audio")
+	file := strings.NewReader(mp3Tag + "audio")
 	req := audio.NewTranscriptionRequest(file, "test.mp3", audio.ModelWhisper1)
 
 	// Make request with cancelled context
@@ -627,6 +636,220 @@ func TestAudioService_Transcribe_ContextCancellation(t *testing.T) {
 	assert.Contains(t, err.Error(), "context canceled")
 }
 
+func TestAudioService_Transcribe_Prevalidation(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		writeJSON(w, audio.TranscriptionResponse{Text: "ok"})
+	})
+	ctx := context.Background()
+
+	// A PDF named as an MP3 is rejected by its content
+	pdf := "%PDF-1.4\n%%EOF\n"
+	_, err := client.Audio.Transcribe(ctx, audio.NewTranscriptionRequest(strings.NewReader(pdf), "episode.mp3", audio.ModelWhisper1))
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), "detected application/pdf")
+
+	// A WAV file announcing a 2 hour podcast
+	wav := make([]byte, 44)
+	copy(wav, "RIFF\x00\x00\x00\x00WAVEfmt \x10\x00\x00\x00")
+	binary.LittleEndian.PutUint16(wav[20:], 1)     // PCM
+	binary.LittleEndian.PutUint16(wav[22:], 1)     // mono
+	binary.LittleEndian.PutUint32(wav[24:], 16000) // sample rate
+	binary.LittleEndian.PutUint32(wav[28:], 32000) // byte rate
+	binary.LittleEndian.PutUint16(wav[32:], 2)     // block align
+	binary.LittleEndian.PutUint16(wav[34:], 16)    // bits per sample
+	copy(wav[36:], "data")
+	binary.LittleEndian.PutUint32(wav[40:], 2*3600*32000)
+	_, err = client.Audio.Transcribe(ctx, audio.NewTranscriptionRequest(bytes.NewReader(wav), "podcast.wav", audio.ModelWhisper1))
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), "about 2h0m0s, over the maximum duration of 30s")
+
+	// A file over the size limit fails before it is sent
+	large := make([]byte, audio.MaxFileSize+1)
+	copy(large, mp3Tag)
+	_, err = client.Audio.Transcribe(ctx, audio.NewTranscriptionRequest(bytes.NewReader(large), "large.mp3", audio.ModelWhisper1))
+	require.ErrorAs(t, err, &validationErr)
+	assert.Contains(t, err.Error(), "exceeds the maximum size")
+
+	assert.Zero(t, requests.Load(), "rejected files are not uploaded")
+
+	// The checks can be turned off
+	req := audio.NewTranscriptionRequest(strings.NewReader(pdf), "episode.mp3", audio.ModelWhisper1).SetSkipValidation(true)
+	resp, err := client.Audio.Transcribe(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", resp.GetText())
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestAudioService_Translate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		format      audio.ResponseFormat
+		contentType string
+		body        string
+		want        audio.TranscriptionResponse
+	}{
+		{
+			name:        "json",
+			format:      audio.ResponseFormatJSON,
+			contentType: "application/json",
+			body:        `{"text":"My invoice is wrong."}`,
+			want:        audio.TranscriptionResponse{Text: "My invoice is wrong."},
+		},
+		{
+			name:        "text",
+			format:      audio.ResponseFormatText,
+			contentType: "text/plain",
+			body:        "My invoice is wrong.",
+			want:        audio.TranscriptionResponse{Text: "My invoice is wrong."},
+		},
+		{
+			name:        "srt",
+			format:      audio.ResponseFormatSRT,
+			contentType: "application/x-subrip",
+			body:        "1\n00:00:00,000 --> 00:00:02,000\nMy invoice is wrong.",
+			want:        audio.TranscriptionResponse{Text: "1\n00:00:00,000 --> 00:00:02,000\nMy invoice is wrong."},
+		},
+		{
+			name:        "vtt",
+			format:      audio.ResponseFormatVTT,
+			contentType: "text/vtt",
+			body:        "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\nMy invoice is wrong.",
+			want:        audio.TranscriptionResponse{Text: "WEBVTT\n\n00:00:00.000 --> 00:00:02.000\nMy invoice is wrong."},
+		},
+		{
+			name:        "verbose_json",
+			format:      audio.ResponseFormatVerboseJSON,
+			contentType: "application/json",
+			body: `{"task":"translate","language":"german","duration":2.0,"text":"My invoice is wrong.",` +
+				`"segments":[{"id":0,"start":0.0,"end":2.0,"text":"My invoice is wrong."}]}`,
+			want: audio.TranscriptionResponse{
+				Text:     "My invoice is wrong.",
+				Task:     "translate",
+				Language: "german",
+				Duration: 2.0,
+				Segments: []audio.TranscriptionSegment{
+					{ID: 0, Start: 0.0, End: 2.0, Text: "My invoice is wrong."},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Mock server
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Verify request
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "/audio/translations", r.URL.Path)
+
+				err := r.ParseMultipartForm(10 << 20)
+				require.NoError(t, err)
+
+				assert.Equal(t, "whisper-1", r.FormValue("model"))
+				assert.Equal(t, "Billing support call", r.FormValue("prompt"))
+				assert.Equal(t, string(tt.format), r.FormValue("response_format"))
+				assert.Equal(t, "0.100000", r.FormValue("temperature"))
+				assert.Empty(t, r.FormValue("language"))
+
+				file, header, err := r.FormFile("file")
+				require.NoError(t, err)
+				defer file.Close()
+				assert.Equal(t, "ticket.mp3", header.Filename)
+
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			// Create client
+			client, err := NewClient(
+				WithAPIKey("test-key.test-secret"),
+				WithBaseURL(server.URL),
+			)
+			require.NoError(t, err)
+			defer client.Close()
+
+			// Create request
+			req := audio.NewTranslationRequest(strings.NewReader("audio content"), "ticket.mp3", audio.ModelWhisper1)
+			req.SetPrompt("Billing support call").
+				SetResponseFormat(tt.format).
+				SetTemperature(0.1)
+
+			// Make request
+			resp, err := client.Audio.Translate(context.Background(), req)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+
+			assert.Equal(t, tt.want, *resp)
+		})
+	}
+}
+
+func TestAudioService_TranslateFile(t *testing.T) {
+	t.Parallel()
+
+	// Mock server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/translations", r.URL.Path)
+
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+
+		// Verify default settings
+		assert.Equal(t, "whisper-1", r.FormValue("model"))
+		assert.Equal(t, "json", r.FormValue("response_format"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(audio.TranscriptionResponse{Text: "Hello, I need help."})
+	}))
+	defer server.Close()
+
+	// Create client
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	text, err := client.Audio.TranslateFile(context.Background(), strings.NewReader("audio"), "ticket.mp3")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, I need help.", text)
+}
+
+func TestAudioService_Translate_APIError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"1214","message":"unsupported audio format"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := audio.NewTranslationRequest(strings.NewReader("audio"), "ticket.xyz", audio.ModelWhisper1)
+	resp, err := client.Audio.Translate(context.Background(), req)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+}
+
 func TestNewAudioService(t *testing.T) {
 	t.Parallel()
 
@@ -637,3 +860,167 @@ func TestNewAudioService(t *testing.T) {
 	// Verify Audio service is initialized
 	assert.NotNil(t, client.Audio)
 }
+
+func TestAudioService_Speech(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/audio/speech", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "cogtts", body["model"])
+		assert.Equal(t, "Hello world", body["input"])
+		assert.Equal(t, "voice_clone_1", body["voice"])
+		assert.Equal(t, "mp3", body["response_format"])
+		assert.Equal(t, 1.2, body["speed"])
+		assert.NotContains(t, body, "stream")
+
+		w.Header().Set("Content-Type", "audio/mpeg")
+		w.Write([]byte("ID3 fake mp3 bytes"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := audio.NewSpeechRequest(audio.ModelCogTTS, "Hello world", "voice_clone_1").
+		SetResponseFormat(audio.SpeechFormatMP3).
+		SetSpeed(1.2)
+
+	resp, err := client.Audio.Speech(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	assert.Equal(t, []byte("ID3 fake mp3 bytes"), resp.Bytes())
+	assert.Equal(t, "audio/mpeg", resp.ContentType)
+
+	// A request left streaming by the caller is sent without streaming
+	req.Stream = true
+	_, err = client.Audio.Speech(context.Background(), req)
+	require.NoError(t, err)
+	assert.True(t, req.Stream, "the caller's request is not changed")
+}
+
+func TestAudioService_Speech_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"unknown voice","code":"1214"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := audio.NewSpeechRequest(audio.ModelCogTTS, "Hello", "missing")
+
+	resp, err := client.Audio.Speech(context.Background(), req)
+	assert.Error(t, err)
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "unknown voice")
+}
+
+func TestAudioService_SpeechStream_EncodeFormat(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL("http://127.0.0.1:1"),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := audio.NewSpeechRequest(audio.ModelCogTTS, "Hello", audio.VoiceTongtong)
+	req.EncodeFormat = "hex"
+
+	stream, err := client.Audio.SpeechStream(context.Background(), req)
+	assert.Nil(t, stream)
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "encode_format", validationErr.Field)
+}
+
+func TestAudioService_SpeechStream(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/audio/speech", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, true, body["stream"])
+		assert.Equal(t, "base64", body["encode_format"])
+		assert.Equal(t, "pcm", body["response_format"])
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher, _ := w.(http.Flusher)
+
+		parts := []string{"first-", "second-", "third"}
+		for _, part := range parts {
+			chunk := audio.SpeechChunk{
+				Choices: []audio.SpeechChunkChoice{
+					{Delta: audio.SpeechDelta{Content: base64.StdEncoding.EncodeToString([]byte(part))}},
+				},
+			}
+			data, _ := json.Marshal(chunk)
+			w.Write([]byte("data: "))
+			w.Write(data)
+			w.Write([]byte("\n\n"))
+			flusher.Flush()
+		}
+
+		final, _ := json.Marshal(audio.SpeechChunk{
+			Choices: []audio.SpeechChunkChoice{{FinishReason: "stop"}},
+		})
+		w.Write([]byte("data: "))
+		w.Write(final)
+		w.Write([]byte("\n\ndata: [DONE]\n\n"))
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := audio.NewSpeechRequest(audio.ModelCogTTS, "A long passage", audio.VoiceTongtong).
+		SetResponseFormat(audio.SpeechFormatPCM)
+
+	stream, err := client.Audio.SpeechStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var collected []byte
+	var finished bool
+	for stream.Next() {
+		chunk := stream.Current()
+		data, err := chunk.GetAudio()
+		require.NoError(t, err)
+		collected = append(collected, data...)
+		if chunk.IsFinished() {
+			finished = true
+		}
+	}
+
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "first-second-third", string(collected))
+	assert.True(t, finished)
+
+	assert.False(t, req.Stream, "the caller's request is not changed")
+	assert.Empty(t, req.EncodeFormat)
+}
@@ -1,11 +1,19 @@
 package zai
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"maps"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/pagination"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // BatchService provides access to the Batch API.
@@ -35,9 +43,18 @@ func newBatchService(baseClient *client.BaseClient) *BatchService {
 //	    // Handle error
 //	}
 //	fmt.Printf("Batch ID: %s, Status: %s\n", batchJob.ID, batchJob.Status)
+//
+// Instead of polling, the API can notify a callback URL when the batch
+// completes (see the webhooks package):
+//
+//	req.SetCallbackURL("https://example.com/hooks/zai")
 func (s *BatchService) Create(ctx context.Context, req *batch.BatchCreateRequest) (*batch.Batch, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/batches", req)
+	apiResp, err := s.client.Post(s.client.Idempotent(ctx), s.client.Endpoints().Path(endpoints.Batches), req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +89,7 @@ func (s *BatchService) Retrieve(ctx context.Context, batchID string) (*batch.Bat
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Get(ctx, fmt.Sprintf("/batches/%s", batchID), nil)
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.Batch, batchID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -88,10 +105,23 @@ func (s *BatchService) Retrieve(ctx context.Context, batchID string) (*batch.Bat
 
 // List lists batches with cursor-based pagination.
 //
+// Deprecated: Use ListWithParams, which also filters batches on the server.
+func (s *BatchService) List(ctx context.Context, after string, limit int) (*batch.BatchListResponse, error) {
+	return s.ListWithParams(ctx, &batch.BatchListParams{After: after, Limit: limit})
+}
+
+// ListWithParams lists a page of batches. The server filters them by
+// status, creation time and metadata, and Total is set if the server
+// counts the matching batches. Nil params lists all batches.
+//
 // Example:
 //
-//	// Get first page
-//	resp, err := client.Batch.List(ctx, "", 20)
+//	resp, err := client.Batch.ListWithParams(ctx, &batch.BatchListParams{
+//	    Limit:        20,
+//	    Status:       batch.StatusCompleted,
+//	    CreatedAfter: time.Now().Add(-24 * time.Hour),
+//	    Metadata:     map[string]string{"user_id": "user_123"},
+//	})
 //	if err != nil {
 //	    // Handle error
 //	}
@@ -102,21 +132,19 @@ func (s *BatchService) Retrieve(ctx context.Context, batchID string) (*batch.Bat
 //
 //	// Get next page if available
 //	if resp.HasMoreBatches() {
-//	    nextResp, err := client.Batch.List(ctx, resp.LastID, 20)
+//	    nextResp, err := client.Batch.ListWithParams(ctx, &batch.BatchListParams{After: resp.LastID, Limit: 20})
 //	    // Process next page...
 //	}
-func (s *BatchService) List(ctx context.Context, after string, limit int) (*batch.BatchListResponse, error) {
-	// Build query parameters
-	query := make(map[string]string)
-	if after != "" {
-		query["after"] = after
+func (s *BatchService) ListWithParams(ctx context.Context, params *batch.BatchListParams) (*batch.BatchListResponse, error) {
+	if params == nil {
+		params = &batch.BatchListParams{}
 	}
-	if limit > 0 {
-		query["limit"] = fmt.Sprintf("%d", limit)
+	if !params.CreatedAfter.IsZero() && !params.CreatedBefore.IsZero() && !params.CreatedAfter.Before(params.CreatedBefore) {
+		return nil, errors.NewValidationError("created_before", "must be after created_after", params.CreatedBefore)
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Get(ctx, "/batches", query)
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.Batches), params.Query())
 	if err != nil {
 		return nil, err
 	}
@@ -130,6 +158,67 @@ func (s *BatchService) List(ctx context.Context, after string, limit int) (*batc
 	return &resp, nil
 }
 
+// ListAutoPaging returns an iterator over all batches, fetching pages of
+// pageSize batches lazily as the iteration advances. A pageSize of zero
+// uses the API default.
+//
+// Example:
+//
+//	iter := client.Batch.ListAutoPaging(ctx, 20)
+//	for iter.Next() {
+//	    batchJob := iter.Current()
+//	    fmt.Printf("Batch %s: %s\n", batchJob.ID, batchJob.Status)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *BatchService) ListAutoPaging(ctx context.Context, pageSize int) *pagination.Iterator[batch.Batch] {
+	return s.ListAutoPagingWithParams(ctx, &batch.BatchListParams{Limit: pageSize})
+}
+
+// ListAutoPagingWithParams returns an iterator over all batches matching
+// the filters of params, fetching pages lazily as the iteration advances.
+// params.After sets the starting cursor and params.Limit the page size.
+//
+// Example:
+//
+//	iter := client.Batch.ListAutoPagingWithParams(ctx, &batch.BatchListParams{
+//	    Status:   batch.StatusFailed,
+//	    Metadata: map[string]string{"project": "reports"},
+//	})
+//	for iter.Next() {
+//	    fmt.Println(iter.Current().ID)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *BatchService) ListAutoPagingWithParams(ctx context.Context, params *batch.BatchListParams) *pagination.Iterator[batch.Batch] {
+	if params == nil {
+		params = &batch.BatchListParams{}
+	}
+
+	return pagination.NewIterator(ctx, params.After, func(ctx context.Context, cursor string) (*pagination.Page[batch.Batch], error) {
+		pageParams := *params
+		pageParams.After = cursor
+
+		resp, err := s.ListWithParams(ctx, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		next := resp.LastID
+		if next == "" && len(resp.Data) > 0 {
+			next = resp.Data[len(resp.Data)-1].ID
+		}
+
+		return &pagination.Page[batch.Batch]{
+			Items:   resp.Data,
+			HasMore: resp.HasMore,
+			Cursor:  next,
+		}, nil
+	})
+}
+
 // Cancel cancels an in-progress batch.
 //
 // Example:
@@ -150,7 +239,7 @@ func (s *BatchService) Cancel(ctx context.Context, batchID string) (*batch.Batch
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, fmt.Sprintf("/batches/%s/cancel", batchID), nil)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.BatchCancel, batchID), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -163,3 +252,253 @@ func (s *BatchService) Cancel(ctx context.Context, batchID string) (*batch.Batch
 
 	return &resp, nil
 }
+
+// CancelAndDrain cancels a batch and waits until it stops, so that its
+// output and error files hold the requests that ran before the
+// cancellation took effect. A batch that already finished is returned
+// as is. Polling backs off exponentially up to DefaultBatchPollInterval;
+// opts change the schedule.
+//
+// Example:
+//
+//	batchJob, err := client.Batch.CancelAndDrain(ctx, "batch_abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	fmt.Printf("Batch stopped: %s, error file: %s\n", batchJob.Status, batchJob.ErrorFileID)
+func (s *BatchService) CancelAndDrain(ctx context.Context, batchID string, opts ...PollOption) (*batch.Batch, error) {
+	batchJob, err := s.Retrieve(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batchJob.IsTerminal() {
+		return batchJob, nil
+	}
+	if !batchJob.IsCancelling() {
+		if batchJob, err = s.Cancel(ctx, batchID); err != nil {
+			return nil, err
+		}
+	}
+
+	poller := newPoller(s.client, poll.Config{MaxInterval: DefaultBatchPollInterval}, opts)
+	err = poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if batchJob.IsTerminal() {
+			return true, nil
+		}
+		job, err := s.Retrieve(ctx, batchID)
+		if err != nil {
+			return false, err
+		}
+		batchJob = job
+		return job.IsTerminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return batchJob, nil
+}
+
+// RetryFailed re-submits only the failed requests of a finished batch,
+// instead of the whole input file. It reads the custom IDs from the
+// error file of the batch, copies their lines unchanged from its input
+// file into a new input file, uploads it and creates a batch for the
+// same endpoint with the given completion window ("" keeps the window of
+// the batch). The new batch keeps the metadata of the batch, and its
+// MetadataOriginalBatchID key is set to batchID.
+//
+// A batch that is still running yields a *batch.NotTerminalError, and a
+// batch without failed requests a *batch.NoFailedRequestsError. The input
+// file must still exist: create batches to retry with
+// SetAutoDeleteInputFile(false).
+//
+// Example:
+//
+//	retry, err := client.Batch.RetryFailed(ctx, "batch_abc123", "24h")
+//	var none *batch.NoFailedRequestsError
+//	if errors.As(err, &none) {
+//	    // Every request succeeded
+//	} else if err != nil {
+//	    // Handle error
+//	}
+//	fmt.Printf("Retrying the failed requests in %s\n", retry.ID)
+func (s *BatchService) RetryFailed(ctx context.Context, batchID string, window string) (*batch.Batch, error) {
+	source, err := s.Retrieve(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !source.IsTerminal() {
+		return nil, &batch.NotTerminalError{BatchID: source.ID, Status: source.Status}
+	}
+	if window == "" {
+		window = source.CompletionWindow
+	}
+
+	failed, err := s.failedCustomIDs(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if len(failed) == 0 {
+		return nil, &batch.NoFailedRequestsError{BatchID: source.ID}
+	}
+	if source.InputFileID == "" {
+		return nil, fmt.Errorf("batch: %s has no input file", source.ID)
+	}
+
+	filesService := newFilesService(s.client)
+	apiResp, err := filesService.openContent(ctx, source.InputFileID)
+	if err != nil {
+		return nil, fmt.Errorf("batch: failed to download input file %s: %w", source.InputFileID, err)
+	}
+	var input bytes.Buffer
+	copied, err := batch.FilterInput(&input, apiResp.Body, failed)
+	apiResp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if copied < len(failed) {
+		return nil, fmt.Errorf("batch: input file %s has %d of the %d failed requests of %s",
+			source.InputFileID, copied, len(failed), source.ID)
+	}
+
+	file, err := filesService.Upload(ctx, files.NewFileUploadRequest(bytes.NewReader(input.Bytes()), "retry-"+source.ID+".jsonl", files.PurposeBatch))
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := maps.Clone(source.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+	metadata[batch.MetadataOriginalBatchID] = source.ID
+	return s.Create(ctx, batch.NewBatchCreateRequest(window, source.Endpoint, file.ID).SetMetadata(metadata))
+}
+
+// failedCustomIDs returns the custom IDs in the error file of a batch.
+func (s *BatchService) failedCustomIDs(ctx context.Context, batchJob *batch.Batch) (map[string]bool, error) {
+	failed := make(map[string]bool)
+	if batchJob.ErrorFileID == "" {
+		return failed, nil
+	}
+
+	filesService := newFilesService(s.client)
+	iter := batch.NewResultIterator(func() (io.ReadCloser, error) {
+		apiResp, err := filesService.openContent(ctx, batchJob.ErrorFileID)
+		if err != nil {
+			return nil, err
+		}
+		return apiResp.Body, nil
+	})
+	defer iter.Close()
+
+	for iter.Next() {
+		if customID := iter.Current().CustomID; customID != "" {
+			failed[customID] = true
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("batch: failed to read error file %s: %w", batchJob.ErrorFileID, err)
+	}
+	return failed, nil
+}
+
+// Results downloads the output and error files of a completed batch and
+// returns the outcome of every request keyed by custom_id. Successful
+// responses are decoded with Result.ChatCompletion or Result.Embeddings;
+// failed requests have a Result.Error. The files are parsed line by line,
+// but all results are kept in memory; use ResultsIterator for very large
+// batches. A batch that has not completed yields a *batch.NotCompletedError.
+//
+// Example:
+//
+//	results, err := client.Batch.Results(ctx, "batch_abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, result := range results.Succeeded() {
+//	    resp, err := result.ChatCompletion()
+//	    if err != nil {
+//	        // Handle error
+//	    }
+//	    fmt.Printf("%s: %s\n", result.CustomID, resp.GetContent())
+//	}
+//	for _, result := range results.Failed() {
+//	    fmt.Printf("%s failed: %s\n", result.CustomID, result.Error.Message)
+//	}
+func (s *BatchService) Results(ctx context.Context, batchID string) (*batch.Results, error) {
+	batchJob, iter, err := s.resultsIterator(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	results := &batch.Results{
+		Batch:      batchJob,
+		ByCustomID: make(map[string]*batch.Result),
+	}
+	for iter.Next() {
+		result := iter.Current()
+		results.ByCustomID[result.CustomID] = result
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// ResultsIterator returns an iterator over the outcomes of the requests of
+// a completed batch, reading the output file and then the error file line
+// by line without holding them in memory. The caller must close the
+// iterator. A batch that has not completed yields a
+// *batch.NotCompletedError.
+//
+// Example:
+//
+//	iter, err := client.Batch.ResultsIterator(ctx, "batch_abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer iter.Close()
+//
+//	for iter.Next() {
+//	    result := iter.Current()
+//	    if !result.OK() {
+//	        fmt.Printf("%s failed: %s\n", result.CustomID, result.Error.Message)
+//	    }
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *BatchService) ResultsIterator(ctx context.Context, batchID string) (*batch.ResultIterator, error) {
+	_, iter, err := s.resultsIterator(ctx, batchID)
+	return iter, err
+}
+
+// resultsIterator retrieves the batch and checks that it completed.
+func (s *BatchService) resultsIterator(ctx context.Context, batchID string) (*batch.Batch, *batch.ResultIterator, error) {
+	batchJob, err := s.Retrieve(ctx, batchID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !batchJob.IsCompleted() {
+		return nil, nil, &batch.NotCompletedError{BatchID: batchJob.ID, Status: batchJob.Status}
+	}
+
+	filesService := newFilesService(s.client)
+	var open []func() (io.ReadCloser, error)
+	for _, fileID := range []string{batchJob.OutputFileID, batchJob.ErrorFileID} {
+		if fileID == "" {
+			continue
+		}
+		open = append(open, func() (io.ReadCloser, error) {
+			apiResp, err := filesService.openContent(ctx, fileID)
+			if err != nil {
+				return nil, err
+			}
+			return apiResp.Body, nil
+		})
+	}
+
+	return batchJob, batch.NewResultIterator(open...), nil
+}
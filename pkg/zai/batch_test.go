@@ -1,15 +1,25 @@
 package zai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	batchTypes "github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestBatchService_Create(t *testing.T) {
@@ -74,6 +84,19 @@ func TestBatchService_Create(t *testing.T) {
 	assert.Equal(t, "user_123", batch.Metadata["user_id"])
 }
 
+func TestBatchService_Create_InvalidCallbackURL(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	})
+
+	req := batchTypes.NewBatchCreateRequest("24h", batchTypes.EndpointChatCompletions, "file_abc123").
+		SetCallbackURL("hooks/zai")
+	_, err := client.Batch.Create(context.Background(), req)
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+}
+
 func TestBatchService_Retrieve(t *testing.T) {
 	t.Parallel()
 
@@ -251,6 +274,195 @@ func TestBatchService_List(t *testing.T) {
 	})
 }
 
+func TestBatchService_ListWithParams(t *testing.T) {
+	t.Parallel()
+
+	var rawQuery atomic.Value
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/batches", r.URL.Path)
+		rawQuery.Store(r.URL.RawQuery)
+		writeJSON(w, map[string]any{"object": "list", "data": []any{map[string]any{"id": "batch_1"}}, "has_more": false, "total": 42})
+	})
+
+	filters := []struct {
+		set   func(*batchTypes.BatchListParams)
+		query string
+	}{
+		{func(p *batchTypes.BatchListParams) { p.After = "batch_9" }, "after=batch_9"},
+		{func(p *batchTypes.BatchListParams) { p.Limit = 20 }, "limit=20"},
+		{func(p *batchTypes.BatchListParams) { p.Status = batchTypes.StatusCompleted }, "status=completed"},
+		{func(p *batchTypes.BatchListParams) { p.CreatedAfter = time.Unix(1700000000, 0) }, "created_after=1700000000"},
+		{func(p *batchTypes.BatchListParams) { p.CreatedBefore = time.Unix(1800000000, 0) }, "created_before=1800000000"},
+		{func(p *batchTypes.BatchListParams) { p.Metadata = map[string]string{"user id": "a&b"} }, "metadata%5Buser+id%5D=a%26b"},
+	}
+
+	// Every combination of filters reaches the server, escaped
+	for mask := 0; mask < 1<<len(filters); mask++ {
+		params := &batchTypes.BatchListParams{}
+		var want []string
+		for i, filter := range filters {
+			if mask&(1<<i) != 0 {
+				filter.set(params)
+				want = append(want, filter.query)
+			}
+		}
+		resp, err := client.Batch.ListWithParams(context.Background(), params)
+		require.NoError(t, err)
+		got := strings.Split(rawQuery.Load().(string), "&")
+		if len(want) == 0 {
+			want = []string{""}
+		}
+		assert.ElementsMatch(t, want, got, "filters %06b", mask)
+		require.NotNil(t, resp.Total)
+		assert.Equal(t, 42, *resp.Total)
+	}
+
+	_, err := client.Batch.ListWithParams(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Empty(t, rawQuery.Load())
+
+	// The deprecated positional form sends the same query
+	_, err = client.Batch.List(context.Background(), "batch_9", 20)
+	require.NoError(t, err)
+	assert.Equal(t, "after=batch_9&limit=20", rawQuery.Load())
+
+	_, err = client.Batch.ListWithParams(context.Background(), &batchTypes.BatchListParams{
+		CreatedAfter:  time.Unix(1800000000, 0),
+		CreatedBefore: time.Unix(1700000000, 0),
+	})
+	assert.True(t, errors.IsValidationError(err))
+}
+
+func TestBatchService_ListAutoPagingWithParams(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]batchTypes.BatchListResponse{
+		"":        {Data: []batchTypes.Batch{{ID: "batch_1"}, {ID: "batch_2"}}, LastID: "batch_2", HasMore: true},
+		"batch_2": {Data: []batchTypes.Batch{{ID: "batch_3"}}, HasMore: false},
+	}
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// The filters are sent with every page
+		query := r.URL.Query()
+		assert.Equal(t, "failed", query.Get("status"))
+		assert.Equal(t, "reports", query.Get("metadata[project]"))
+		writeJSON(w, pages[query.Get("after")])
+	})
+
+	iter := client.Batch.ListAutoPagingWithParams(context.Background(), &batchTypes.BatchListParams{
+		Status:   batchTypes.StatusFailed,
+		Metadata: map[string]string{"project": "reports"},
+	})
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Current().ID)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, []string{"batch_1", "batch_2", "batch_3"}, ids)
+}
+
+func TestBatchService_ListAutoPaging(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]batchTypes.BatchListResponse{
+		"":        {Data: []batchTypes.Batch{{ID: "batch_1"}, {ID: "batch_2"}}, LastID: "batch_2", HasMore: true},
+		"batch_2": {Data: []batchTypes.Batch{{ID: "batch_3"}, {ID: "batch_4"}}, LastID: "batch_4", HasMore: true},
+		"batch_4": {Data: []batchTypes.Batch{{ID: "batch_5"}}, LastID: "batch_5", HasMore: false},
+	}
+
+	newServer := func(t *testing.T, calls *atomic.Int32, failAfter string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			assert.Equal(t, "/batches", r.URL.Path)
+			assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+			after := r.URL.Query().Get("after")
+			w.Header().Set("Content-Type", "application/json")
+			if failAfter != "" && after == failAfter {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"error": map[string]string{"code": "1210", "message": "invalid cursor"},
+				})
+				return
+			}
+			json.NewEncoder(w).Encode(pages[after])
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	newClient := func(t *testing.T, server *httptest.Server) *Client {
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		t.Cleanup(func() { client.Close() })
+		return client
+	}
+
+	t.Run("three pages", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client := newClient(t, newServer(t, &calls, ""))
+
+		iter := client.Batch.ListAutoPaging(context.Background(), 2)
+		var ids []string
+		for iter.Next() {
+			ids = append(ids, iter.Current().ID)
+		}
+		require.NoError(t, iter.Err())
+
+		assert.Equal(t, []string{"batch_1", "batch_2", "batch_3", "batch_4", "batch_5"}, ids)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("not started", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client := newClient(t, newServer(t, &calls, ""))
+
+		_ = client.Batch.ListAutoPaging(context.Background(), 2)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("page error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client := newClient(t, newServer(t, &calls, "batch_2"))
+
+		iter := client.Batch.ListAutoPaging(context.Background(), 2)
+		var ids []string
+		for iter.Next() {
+			ids = append(ids, iter.Current().ID)
+		}
+
+		assert.Equal(t, []string{"batch_1", "batch_2"}, ids)
+		require.Error(t, iter.Err())
+		assert.Contains(t, iter.Err().Error(), "invalid cursor")
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		client := newClient(t, newServer(t, &calls, ""))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		iter := client.Batch.ListAutoPaging(ctx, 2)
+		require.True(t, iter.Next())
+		cancel()
+
+		assert.False(t, iter.Next())
+		assert.ErrorIs(t, iter.Err(), context.Canceled)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
+
 func TestBatchService_Cancel(t *testing.T) {
 	t.Parallel()
 
@@ -330,3 +542,350 @@ func TestBatchService_APIError(t *testing.T) {
 	_, err = client.Batch.Retrieve(context.Background(), "nonexistent_batch")
 	require.Error(t, err)
 }
+
+// batchResultsServer serves a batch with the given status and output and
+// error files.
+func batchResultsServer(t *testing.T, status string, output, errorsFile []byte) *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /batches/{id}", func(w http.ResponseWriter, r *http.Request) {
+		resp := batchTypes.Batch{ID: r.PathValue("id"), Object: "batch", Status: status}
+		if output != nil {
+			resp.OutputFileID = "file_out"
+		}
+		if errorsFile != nil {
+			resp.ErrorFileID = "file_err"
+		}
+		writeJSON(w, resp)
+	})
+	mux.HandleFunc("GET /files/file_out/content", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jsonl")
+		w.Write(output)
+	})
+	mux.HandleFunc("GET /files/file_err/content", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/jsonl")
+		w.Write(errorsFile)
+	})
+	return newRawTestClient(t, mux.ServeHTTP)
+}
+
+// syntheticBatchOutput returns n chat completion result lines.
+func syntheticBatchOutput(n int) []byte {
+	var buf bytes.Buffer
+	padding := strings.Repeat("lorem ipsum ", 20)
+	for i := range n {
+		fmt.Fprintf(&buf, `{"id":"batch_req_%d","custom_id":"req-%d","response":{"status_code":200,"request_id":"r%d","body":{"id":"chatcmpl-%d","model":"glm-4.7","choices":[{"index":0,"message":{"role":"assistant","content":"answer %d %s"},"finish_reason":"stop"}]}},"error":null}`+"\n",
+			i, i, i, i, i, padding)
+	}
+	return buf.Bytes()
+}
+
+const batchErrorFile = `{"id":"batch_req_e1","custom_id":"req-bad","response":{"status_code":400,"request_id":"r-bad","body":{"error":{"code":"1214","message":"messages is empty"}}},"error":null}
+{"id":"batch_req_e2","custom_id":"req-expired","response":null,"error":{"code":"batch_expired","message":"request expired"}}
+`
+
+func TestBatchService_Results(t *testing.T) {
+	t.Parallel()
+
+	output := syntheticBatchOutput(10000)
+	require.Greater(t, len(output), 2<<20, "the output file should be multi-megabyte")
+
+	client := batchResultsServer(t, batchTypes.StatusCompleted, output, []byte(batchErrorFile))
+
+	results, err := client.Batch.Results(context.Background(), "batch_abc123")
+	require.NoError(t, err)
+	assert.Equal(t, "batch_abc123", results.Batch.ID)
+	assert.Len(t, results.ByCustomID, 10002)
+	assert.Len(t, results.Succeeded(), 10000)
+
+	result, ok := results.Get("req-9999")
+	require.True(t, ok)
+	assert.True(t, result.OK())
+	resp, err := result.ChatCompletion()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(resp.GetContent(), "answer 9999 "))
+
+	failed := results.Failed()
+	require.Len(t, failed, 2)
+	assert.Equal(t, "req-bad", failed[0].CustomID)
+	assert.Equal(t, "1214", failed[0].Error.Code)
+	assert.Equal(t, "messages is empty", failed[0].Error.Message)
+	assert.Equal(t, "req-expired", failed[1].CustomID)
+	assert.Equal(t, "request expired", failed[1].Error.Message)
+
+	_, err = failed[0].ChatCompletion()
+	assert.ErrorContains(t, err, "messages is empty")
+}
+
+func TestBatchService_ResultsIterator(t *testing.T) {
+	t.Parallel()
+
+	client := batchResultsServer(t, batchTypes.StatusCompleted, syntheticBatchOutput(20000), []byte(batchErrorFile))
+
+	iter, err := client.Batch.ResultsIterator(context.Background(), "batch_abc123")
+	require.NoError(t, err)
+	defer iter.Close()
+
+	var ok, failed int
+	for iter.Next() {
+		if iter.Current().OK() {
+			ok++
+		} else {
+			failed++
+		}
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, 20000, ok)
+	assert.Equal(t, 2, failed)
+}
+
+func TestBatchService_Results_OnlyErrors(t *testing.T) {
+	t.Parallel()
+
+	client := batchResultsServer(t, batchTypes.StatusCompleted, nil, []byte(batchErrorFile))
+
+	results, err := client.Batch.Results(context.Background(), "batch_abc123")
+	require.NoError(t, err)
+	assert.Empty(t, results.Succeeded())
+	assert.Len(t, results.Failed(), 2)
+}
+
+func TestBatchService_Results_NotCompleted(t *testing.T) {
+	t.Parallel()
+
+	client := batchResultsServer(t, batchTypes.StatusInProgress, nil, nil)
+
+	_, err := client.Batch.Results(context.Background(), "batch_abc123")
+	var notCompleted *batchTypes.NotCompletedError
+	require.ErrorAs(t, err, &notCompleted)
+	assert.Equal(t, "batch_abc123", notCompleted.BatchID)
+	assert.Equal(t, batchTypes.StatusInProgress, notCompleted.Status)
+
+	_, err = client.Batch.ResultsIterator(context.Background(), "batch_abc123")
+	require.ErrorAs(t, err, &notCompleted)
+}
+
+func TestBatchService_Results_InvalidLine(t *testing.T) {
+	t.Parallel()
+
+	output := append(syntheticBatchOutput(2), []byte("{not json\n")...)
+	client := batchResultsServer(t, batchTypes.StatusCompleted, output, nil)
+
+	_, err := client.Batch.Results(context.Background(), "batch_abc123")
+	assert.ErrorContains(t, err, "line 3")
+}
+
+// retryBatchServer is a fake Files and Batch API serving a finished batch
+// "batch_src" of n chat completion requests req-0 to req-(n-1), of which
+// those in failed are listed in its error file.
+type retryBatchServer struct {
+	t *testing.T
+
+	status string
+	input  []byte
+	errors []byte
+
+	mu       sync.Mutex
+	uploaded []byte
+	filename string
+	created  *batchTypes.BatchCreateRequest
+}
+
+func newRetryBatchServer(t *testing.T, n int, failed []int) *retryBatchServer {
+	var input bytes.Buffer
+	w := batchTypes.NewInputWriter(&input)
+	for i := range n {
+		req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage(fmt.Sprintf("question %d", i))})
+		require.NoError(t, w.AddChatCompletion(fmt.Sprintf("req-%d", i), req))
+	}
+	require.NoError(t, w.Flush())
+
+	var errorsFile bytes.Buffer
+	for j, i := range failed {
+		if j%2 == 0 {
+			fmt.Fprintf(&errorsFile, `{"id":"batch_req_%d","custom_id":"req-%d","response":{"status_code":429,"body":{"error":{"code":"1302","message":"rate limited"}}},"error":null}`+"\n", i, i)
+		} else {
+			fmt.Fprintf(&errorsFile, `{"id":"batch_req_%d","custom_id":"req-%d","response":null,"error":{"code":"batch_expired","message":"request expired"}}`+"\n", i, i)
+		}
+	}
+
+	return &retryBatchServer{t: t, status: batchTypes.StatusCompleted, input: input.Bytes(), errors: errorsFile.Bytes()}
+}
+
+func (s *retryBatchServer) client() *Client {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /batches/batch_src", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, batchTypes.Batch{
+			ID:               "batch_src",
+			Object:           "batch",
+			CompletionWindow: "24h",
+			Endpoint:         batchTypes.EndpointChatCompletions,
+			InputFileID:      "file_in",
+			Status:           s.status,
+			OutputFileID:     "file_out",
+			ErrorFileID:      "file_err",
+			Metadata:         map[string]string{"project": "reports"},
+		})
+	})
+	mux.HandleFunc("GET /files/file_in/content", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(s.input)
+	})
+	mux.HandleFunc("GET /files/file_err/content", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(s.errors)
+	})
+	mux.HandleFunc("POST /files", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(s.t, err)
+		assert.Equal(s.t, "batch", r.FormValue("purpose"))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.filename = header.Filename
+		s.uploaded, err = io.ReadAll(file)
+		require.NoError(s.t, err)
+		writeJSON(w, map[string]any{"id": "file_retry", "object": "file", "purpose": "batch"})
+	})
+	mux.HandleFunc("POST /batches", func(w http.ResponseWriter, r *http.Request) {
+		var req batchTypes.BatchCreateRequest
+		require.NoError(s.t, json.NewDecoder(r.Body).Decode(&req))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.created = &req
+		writeJSON(w, batchTypes.Batch{ID: "batch_retry", Object: "batch", Endpoint: req.Endpoint,
+			InputFileID: req.InputFileID, Metadata: req.Metadata, Status: batchTypes.StatusValidating})
+	})
+	return newRawTestClient(s.t, mux.ServeHTTP)
+}
+
+func TestBatchService_RetryFailed(t *testing.T) {
+	t.Parallel()
+
+	failed := []int{3, 14, 15, 42, 57, 88, 99}
+	server := newRetryBatchServer(t, 100, failed)
+	client := server.client()
+
+	retry, err := client.Batch.RetryFailed(context.Background(), "batch_src", "")
+	require.NoError(t, err)
+	assert.Equal(t, "batch_retry", retry.ID)
+	assert.Equal(t, "batch_src", retry.Metadata[batchTypes.MetadataOriginalBatchID])
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	require.NotNil(t, server.created)
+	assert.Equal(t, "file_retry", server.created.InputFileID)
+	assert.Equal(t, batchTypes.EndpointChatCompletions, server.created.Endpoint)
+	assert.Equal(t, "24h", server.created.CompletionWindow, "the window of the batch is kept")
+	assert.Equal(t, map[string]string{"project": "reports", "original_batch_id": "batch_src"}, server.created.Metadata)
+	assert.Equal(t, "retry-batch_src.jsonl", server.filename)
+
+	// Only the failed requests are re-submitted, byte for byte
+	inputLines := strings.Split(strings.TrimSuffix(string(server.input), "\n"), "\n")
+	require.Len(t, inputLines, 100)
+	retried := strings.Split(strings.TrimSuffix(string(server.uploaded), "\n"), "\n")
+	require.Len(t, retried, len(failed))
+	for j, i := range failed {
+		assert.Equal(t, inputLines[i], retried[j])
+	}
+}
+
+func TestBatchService_RetryFailed_Window(t *testing.T) {
+	t.Parallel()
+
+	server := newRetryBatchServer(t, 10, []int{1})
+	_, err := server.client().Batch.RetryFailed(context.Background(), "batch_src", "48h")
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Equal(t, "48h", server.created.CompletionWindow)
+}
+
+func TestBatchService_RetryFailed_NotTerminal(t *testing.T) {
+	t.Parallel()
+
+	server := newRetryBatchServer(t, 10, []int{1})
+	server.status = batchTypes.StatusInProgress
+
+	_, err := server.client().Batch.RetryFailed(context.Background(), "batch_src", "24h")
+	var notTerminal *batchTypes.NotTerminalError
+	require.ErrorAs(t, err, &notTerminal)
+	assert.Equal(t, "batch_src", notTerminal.BatchID)
+	assert.Equal(t, batchTypes.StatusInProgress, notTerminal.Status)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Nil(t, server.uploaded, "nothing is uploaded")
+}
+
+func TestBatchService_RetryFailed_NoFailures(t *testing.T) {
+	t.Parallel()
+
+	server := newRetryBatchServer(t, 10, nil)
+
+	_, err := server.client().Batch.RetryFailed(context.Background(), "batch_src", "24h")
+	var none *batchTypes.NoFailedRequestsError
+	require.ErrorAs(t, err, &none)
+	assert.Equal(t, "batch_src", none.BatchID)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Nil(t, server.created, "no batch is created")
+}
+
+func TestBatchService_RetryFailed_MissingInput(t *testing.T) {
+	t.Parallel()
+
+	// The error file lists a request that is not in the input file
+	server := newRetryBatchServer(t, 10, []int{4, 12})
+
+	_, err := server.client().Batch.RetryFailed(context.Background(), "batch_src", "24h")
+	assert.ErrorContains(t, err, "input file file_in has 1 of the 2 failed requests of batch_src")
+}
+
+func TestBatchService_CancelAndDrain(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		status  = batchTypes.StatusInProgress
+		checks  int
+		cancels int
+	)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /batches/batch_abc123", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		checks++
+		if status == batchTypes.StatusCancelling && checks > 3 {
+			status = batchTypes.StatusCancelled
+		}
+		resp := batchTypes.Batch{ID: "batch_abc123", Object: "batch", Status: status}
+		if status == batchTypes.StatusCancelled {
+			resp.OutputFileID = "file_out"
+		}
+		writeJSON(w, resp)
+	})
+	mux.HandleFunc("POST /batches/batch_abc123/cancel", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		cancels++
+		status = batchTypes.StatusCancelling
+		writeJSON(w, batchTypes.Batch{ID: "batch_abc123", Object: "batch", Status: status})
+	})
+	client := newRawTestClient(t, mux.ServeHTTP)
+
+	batchJob, err := client.Batch.CancelAndDrain(context.Background(), "batch_abc123", WithPollInitialInterval(time.Millisecond))
+	require.NoError(t, err)
+	assert.True(t, batchJob.IsCancelled())
+	assert.Equal(t, "file_out", batchJob.OutputFileID)
+
+	// A finished batch is not cancelled again
+	batchJob, err = client.Batch.CancelAndDrain(context.Background(), "batch_abc123")
+	require.NoError(t, err)
+	assert.True(t, batchJob.IsCancelled())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, cancels)
+	assert.Equal(t, 5, checks)
+}
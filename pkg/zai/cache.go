@@ -0,0 +1,69 @@
+package zai
+
+import (
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/cache"
+)
+
+// DefaultCacheTTL is how long cached responses are kept unless
+// WithCacheTTL is given.
+const DefaultCacheTTL = time.Hour
+
+// Cache stores response bodies for deterministic endpoints.
+// Implementations must be safe for concurrent use.
+type Cache = cache.Store
+
+// LRUCache is an in-memory Cache that evicts the least recently used entry.
+type LRUCache = cache.LRU
+
+// CacheMetrics is a snapshot of response cache hits and misses.
+type CacheMetrics = cache.Metrics
+
+// NewLRUCache creates an in-memory cache holding at most maxEntries responses.
+// If maxEntries is zero or less, the cache has no size limit.
+func NewLRUCache(maxEntries int) *LRUCache {
+	return cache.NewLRU(maxEntries)
+}
+
+// WithCache enables response caching for deterministic endpoints.
+//
+// Only Embeddings.Create, Tools.Tokenizer and WebReader.Read (unless the
+// request sets NoCache) are cached. Requests are keyed by endpoint and a hash
+// of the canonicalized request body; only successful responses are stored.
+// Chat completions and streaming requests are never cached.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithCache(zai.NewLRUCache(1000)),
+//	)
+func WithCache(c Cache) ClientOption {
+	return func(cfg *ClientConfig) {
+		cfg.Cache = c
+	}
+}
+
+// WithCacheTTL sets how long cached responses are kept.
+// A negative ttl keeps entries until they are evicted.
+// It has no effect without WithCache.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(cfg *ClientConfig) {
+		cfg.CacheTTL = ttl
+	}
+}
+
+// CacheMetrics returns a snapshot of response cache hits and misses,
+// or zero metrics if no cache is configured.
+//
+// Example:
+//
+//	m := client.CacheMetrics()
+//	fmt.Printf("hits: %d, misses: %d, hit rate: %.2f\n", m.Hits, m.Misses, m.HitRate())
+func (c *Client) CacheMetrics() CacheMetrics {
+	if c.responseCache == nil {
+		return CacheMetrics{}
+	}
+	return c.responseCache.Metrics()
+}
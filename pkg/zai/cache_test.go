@@ -0,0 +1,158 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCountingServer returns a server that counts requests per path and
+// replies with body.
+func newCountingServer(t *testing.T, body string) (*httptest.Server, map[string]*int32) {
+	t.Helper()
+
+	counts := map[string]*int32{
+		"/embeddings":       new(int32),
+		"/tokenizer":        new(int32),
+		"/reader":           new(int32),
+		"/chat/completions": new(int32),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n, ok := counts[r.URL.Path]; ok {
+			atomic.AddInt32(n, 1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, counts
+}
+
+func TestWithCache_Embeddings(t *testing.T) {
+	t.Parallel()
+
+	server, counts := newCountingServer(t, `{"object":"list","model":"embedding-3","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2]}]}`)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithCache(NewLRUCache(100)),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	first, err := client.Embeddings.Create(ctx, embeddings.NewEmbeddingRequest(embeddings.ModelEmbedding3, "hello"))
+	require.NoError(t, err)
+	second, err := client.Embeddings.Create(ctx, embeddings.NewEmbeddingRequest(embeddings.ModelEmbedding3, "hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(counts["/embeddings"]), "second identical request should be served from cache")
+
+	_, err = client.Embeddings.Create(ctx, embeddings.NewEmbeddingRequest(embeddings.ModelEmbedding3, "different"))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(counts["/embeddings"]))
+
+	assert.Equal(t, CacheMetrics{Hits: 1, Misses: 2}, client.CacheMetrics())
+}
+
+func TestWithCache_TokenizerAndWebReader(t *testing.T) {
+	t.Parallel()
+
+	server, counts := newCountingServer(t, `{"usage":{"prompt_tokens":3,"total_tokens":3},"reader_result":{"title":"t"}}`)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithCache(NewLRUCache(100)),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	messages := []chat.Message{{Role: chat.RoleUser, Content: "hi"}}
+	for i := 0; i < 2; i++ {
+		_, err = client.Tools.Tokenizer(ctx, tools.NewTokenizerRequest("glm-4.7", messages))
+		require.NoError(t, err)
+
+		_, err = client.WebReader.Read(ctx, webreader.NewRequest("https://example.com"))
+		require.NoError(t, err)
+
+		_, err = client.WebReader.Read(ctx, webreader.NewRequest("https://example.com").SetNoCache(true))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(counts["/tokenizer"]))
+	assert.Equal(t, int32(3), atomic.LoadInt32(counts["/reader"]), "NoCache requests must bypass the cache")
+}
+
+func TestWithCache_ChatNotCached(t *testing.T) {
+	t.Parallel()
+
+	server, counts := newCountingServer(t, mockChatResponse)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithCache(NewLRUCache(100)),
+	)
+	require.NoError(t, err)
+
+	req := &chat.ChatCompletionRequest{
+		Model:    "glm-4.7",
+		Messages: []chat.Message{{Role: chat.RoleUser, Content: "hi"}},
+	}
+	for i := 0; i < 2; i++ {
+		_, err = client.Chat.Create(context.Background(), req)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(counts["/chat/completions"]))
+	assert.Equal(t, CacheMetrics{}, client.CacheMetrics())
+}
+
+func TestWithCache_Disabled(t *testing.T) {
+	t.Parallel()
+
+	server, counts := newCountingServer(t, `{"object":"list","data":[]}`)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.Embeddings.Create(context.Background(), embeddings.NewEmbeddingRequest("embedding-3", "hello"))
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(counts["/embeddings"]))
+	assert.Equal(t, CacheMetrics{}, client.CacheMetrics())
+}
+
+func TestWithCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	config := &ClientConfig{}
+	WithCacheTTL(10 * time.Minute)(config)
+	assert.Equal(t, 10*time.Minute, config.CacheTTL)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithCache(NewLRUCache(1)),
+		WithCacheTTL(-1),
+	)
+	require.NoError(t, err)
+	assert.NotNil(t, client.responseCache)
+}
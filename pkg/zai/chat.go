@@ -5,22 +5,43 @@ import (
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // ChatService provides access to the Chat Completions API.
 type ChatService struct {
-	client *client.BaseClient
+	client       *client.BaseClient
+	defaultModel string
 }
 
 // newChatService creates a new chat service.
-func newChatService(baseClient *client.BaseClient) *ChatService {
+func newChatService(baseClient *client.BaseClient, defaultModel string) *ChatService {
 	return &ChatService{
-		client: baseClient,
+		client:       baseClient,
+		defaultModel: defaultModel,
 	}
 }
 
-// Create creates a chat completion.
+// Create creates a chat completion. If the request has a memory (see
+// chat.ChatCompletionRequest.WithMemory), the remembered history is sent
+// with it and the exchange is appended to the memory after a successful
+// response.
+//
+// A request without a model is sent with the client's default chat
+// model, see WithDefaultChatModel.
+//
+// If the request has a schema (see chat.WithSchema), the response carries
+// it for ValidateAgainstSchema. With chat.WithSchemaRetry, a reply that
+// does not match is sent back once, followed by a system message listing
+// the violations, for the model to correct. A corrected reply that does
+// not match either is returned with a *chat.SchemaValidationError and is
+// not remembered.
+//
+// With chat.WithAutoTrim, a request the API rejects because the prompt
+// exceeds the model's context window is trimmed and sent once more; the
+// response reports the dropped messages in Trimmed.
 //
 // Example:
 //
@@ -37,9 +58,68 @@ func newChatService(baseClient *client.BaseClient) *ChatService {
 //	}
 //
 //	fmt.Println(resp.GetContent())
-func (s *ChatService) Create(ctx context.Context, req *chat.ChatCompletionRequest) (*chat.ChatCompletionResponse, error) {
+func (s *ChatService) Create(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.CreateOption) (*chat.ChatCompletionResponse, error) {
+	send, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.create(ctx, send)
+	if cfg := chat.NewCreateConfig(opts...); err != nil && cfg.AutoTrim != nil && errors.IsContextLengthExceeded(err) {
+		send, resp, err = s.trimmedRetry(ctx, send, cfg.AutoTrim, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if config := req.SchemaConfig(); config != nil {
+		if resp, err = s.checkSchema(ctx, send, resp, config); err != nil {
+			return resp, err
+		}
+	}
+
+	if choice := resp.GetFirstChoice(); choice != nil {
+		if err := req.Remember(ctx, choice.Message); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// resolve returns the request to send for req: with the history of its
+// memory, and with the default model if it has none. req is not changed.
+func (s *ChatService) resolve(ctx context.Context, req *chat.ChatCompletionRequest) (*chat.ChatCompletionRequest, error) {
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultChatModel")
+	if err != nil {
+		return nil, err
+	}
+
+	send, err := req.ResolveMemory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if send.Model != model {
+		if send == req {
+			copied := *req
+			send = &copied
+		}
+		send.Model = model
+	}
+	return send, nil
+}
+
+// create sends a chat completion request as is.
+func (s *ChatService) create(ctx context.Context, send *chat.ChatCompletionRequest) (*chat.ChatCompletionResponse, error) {
+	if err := send.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.client.Sanitize(ServiceChat, send); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/chat/completions", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.ChatCompletions), send)
 	if err != nil {
 		return nil, err
 	}
@@ -49,12 +129,14 @@ func (s *ChatService) Create(ctx context.Context, req *chat.ChatCompletionReques
 	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
 		return nil, err
 	}
-
 	return &resp, nil
 }
 
 // CreateStream creates a streaming chat completion.
-// Returns a stream of chat completion chunks.
+// Returns a stream of chat completion chunks. chat.WithStreamIdleTimeout
+// overrides the client's stream idle timeout for this stream. The history
+// of a request memory is sent, but the memory is not updated; see
+// chat.ChatCompletionRequest.WithMemory.
 //
 // Example:
 //
@@ -80,23 +162,40 @@ func (s *ChatService) Create(ctx context.Context, req *chat.ChatCompletionReques
 //	if err := stream.Err(); err != nil {
 //	    // Handle stream error
 //	}
-func (s *ChatService) CreateStream(ctx context.Context, req *chat.ChatCompletionRequest) (*streaming.Stream[chat.ChatCompletionChunk], error) {
+func (s *ChatService) CreateStream(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (*streaming.Stream[chat.ChatCompletionChunk], error) {
+	send, err := s.resolve(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := send.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.client.Sanitize(ServiceChat, send); err != nil {
+		return nil, err
+	}
+
 	// Ensure stream is enabled
 	stream := true
 	req.Stream = &stream
+	send.Stream = &stream
 
 	// Make the streaming request
-	streamResp, err := s.client.Stream(ctx, "/chat/completions", req)
+	streamResp, err := s.client.Stream(ctx, s.client.Endpoints().Path(endpoints.ChatCompletions), send)
 	if err != nil {
 		return nil, err
 	}
+	if cfg := chat.NewStreamConfig(opts...); cfg.IdleTimeout != 0 {
+		streamResp.IdleTimeout = cfg.IdleTimeout
+	}
 
 	// Create typed stream
 	return client.NewTypedStream[chat.ChatCompletionChunk](streamResp, ctx), nil
 }
 
 // StreamContent is a convenience method that streams content and collects it into a string.
-// Returns the complete content and any error that occurred.
+// Returns the complete content and any error that occurred. opts are passed
+// to CreateStream. If the request has a memory, the collected content is
+// appended to it as the assistant reply once the stream completes.
 //
 // Example:
 //
@@ -113,8 +212,8 @@ func (s *ChatService) CreateStream(ctx context.Context, req *chat.ChatCompletion
 //	}
 //
 //	fmt.Println(content)
-func (s *ChatService) StreamContent(ctx context.Context, req *chat.ChatCompletionRequest) (string, error) {
-	stream, err := s.CreateStream(ctx, req)
+func (s *ChatService) StreamContent(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (string, error) {
+	stream, err := s.CreateStream(ctx, req, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -132,5 +231,9 @@ func (s *ChatService) StreamContent(ctx context.Context, req *chat.ChatCompletio
 		return content, err
 	}
 
+	if err := req.Remember(ctx, chat.NewAssistantMessage(content)); err != nil {
+		return content, err
+	}
+
 	return content, nil
 }
@@ -0,0 +1,129 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+func TestChatService_Memory(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received [][]string
+		turn     int
+	)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chat.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		defer mu.Unlock()
+		var roles []string
+		for _, m := range req.Messages {
+			roles = append(roles, fmt.Sprintf("%s:%v", m.Role, m.Content))
+		}
+		received = append(received, roles)
+		turn++
+
+		message := map[string]any{"role": "assistant", "content": fmt.Sprintf("reply %d", turn)}
+		if turn == 2 {
+			// The second turn asks for a tool call
+			message = map[string]any{
+				"role": "assistant",
+				"tool_calls": []map[string]any{{
+					"id":       "call_1",
+					"type":     "function",
+					"function": map[string]any{"name": "lookup", "arguments": "{}"},
+				}},
+			}
+		}
+		writeJSON(w, map[string]any{
+			"id":      fmt.Sprintf("chatcmpl-%d", turn),
+			"model":   "glm-4.7",
+			"choices": []map[string]any{{"index": 0, "message": message, "finish_reason": "stop"}},
+		})
+	})
+
+	ctx := context.Background()
+	memory := chat.NewWindowMemory(0)
+	require.NoError(t, memory.Append(ctx, chat.NewSystemMessage("be brief")))
+
+	send := func(messages ...chat.Message) {
+		req := chat.NewChatCompletionRequest("glm-4.7", messages).WithMemory(memory)
+		_, err := client.Chat.Create(ctx, req)
+		require.NoError(t, err)
+	}
+
+	send(chat.NewUserMessage("hi"))
+	send(chat.NewUserMessage("look it up"))
+	send(chat.NewToolMessage("call_1", "42"))
+
+	assert.Equal(t, [][]string{
+		{"system:be brief", "user:hi"},
+		{"system:be brief", "user:hi", "assistant:reply 1", "user:look it up"},
+		{"system:be brief", "user:hi", "assistant:reply 1", "user:look it up", "assistant:<nil>", "tool:42"},
+	}, received)
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 7)
+	assert.Equal(t, "call_1", messages[4].ToolCalls[0].ID)
+	assert.Equal(t, "reply 3", messages[6].Content)
+}
+
+func TestChatService_Memory_Failure(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"1210","message":"invalid"}}`))
+	})
+
+	ctx := context.Background()
+	memory := chat.NewWindowMemory(0)
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")}).WithMemory(memory)
+
+	_, err := client.Chat.Create(ctx, req)
+	require.Error(t, err)
+
+	// A failed request leaves the memory unchanged
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestChatService_StreamContent_Memory(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte(`data: {"id":"1","choices":[{"index":0,"delta":{"content":"Hel"}}]}` + "\n\n"))
+		w.Write([]byte(`data: {"id":"1","choices":[{"index":0,"delta":{"content":"lo"}}]}` + "\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	})
+
+	ctx := context.Background()
+	memory := chat.NewWindowMemory(0)
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")}).WithMemory(memory)
+
+	content, err := client.Chat.StreamContent(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", content)
+
+	messages, err := memory.Messages(ctx)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, "hi", messages[0].Content)
+	assert.Equal(t, chat.RoleAssistant, messages[1].Role)
+	assert.Equal(t, "Hello", messages[1].Content)
+}
@@ -0,0 +1,239 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// CreateModerated creates a chat completion gated by moderation. The user
+// content selected by policy.Input is screened with the Moderations API
+// first; if it exceeds the policy, the call fails with an
+// *errors.ContentFlaggedError and the model is not called. With
+// policy.ScreenOutput, the content of the reply is screened the same way
+// before it is returned. Only text is screened.
+//
+// If the request has a memory, the reply is remembered only after it
+// passes screening.
+//
+// Example:
+//
+//	policy := moderation.Policy{
+//	    Thresholds:   map[string]float64{moderation.CategoryViolence: 0.7},
+//	    ScreenOutput: true,
+//	}
+//
+//	resp, err := client.Chat.CreateModerated(ctx, req, policy)
+//	var flagged *errors.ContentFlaggedError
+//	if errors.As(err, &flagged) {
+//	    fmt.Printf("%s blocked: %v\n", flagged.Stage, flagged.Categories)
+//	}
+func (s *ChatService) CreateModerated(ctx context.Context, req *chat.ChatCompletionRequest, policy moderation.Policy) (*chat.ChatCompletionResponse, error) {
+	if err := s.screen(ctx, policy, moderation.StageInput, moderatedInput(req, policy.Input)); err != nil {
+		return nil, err
+	}
+
+	// Send without the memory, so that a flagged reply is not remembered
+	send, err := req.ResolveMemory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	detached := *send
+	detached.WithMemory(nil)
+
+	resp, err := s.Create(ctx, &detached)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy.ScreenOutput {
+		for _, choice := range resp.Choices {
+			if err := s.screen(ctx, policy, moderation.StageOutput, choice.Message.Text()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if choice := resp.GetFirstChoice(); choice != nil {
+		if err := req.Remember(ctx, choice.Message); err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// CreateModeratedStream creates a streaming chat completion gated by
+// moderation. The user content is screened as by CreateModerated before
+// the stream is opened. With policy.ScreenOutput, chunks are held back
+// until policy.ChunkSize characters of content have arrived, or the stream
+// ends, and released once that text passes screening; when it does not,
+// the stream stops and Err returns an *errors.ContentFlaggedError. Text
+// is screened one chunk at a time, so a larger ChunkSize gives the
+// moderation model more context at the cost of latency.
+//
+// As with CreateStream, the memory of the request is not updated.
+//
+// Example:
+//
+//	stream, err := client.Chat.CreateModeratedStream(ctx, req, moderation.Policy{
+//	    ScreenOutput: true,
+//	    ChunkSize:    200,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//	    fmt.Print(stream.Current().GetContent())
+//	}
+//	if err := stream.Err(); err != nil {
+//	    return err
+//	}
+func (s *ChatService) CreateModeratedStream(ctx context.Context, req *chat.ChatCompletionRequest, policy moderation.Policy, opts ...chat.StreamOption) (*ModeratedStream, error) {
+	if err := s.screen(ctx, policy, moderation.StageInput, moderatedInput(req, policy.Input)); err != nil {
+		return nil, err
+	}
+
+	stream, err := s.CreateStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &ModeratedStream{
+		ctx:     ctx,
+		service: s,
+		policy:  policy,
+		stream:  stream,
+	}, nil
+}
+
+// screen moderates text and returns an *errors.ContentFlaggedError if it
+// exceeds the policy. Blank text passes without a request.
+func (s *ChatService) screen(ctx context.Context, policy moderation.Policy, stage, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	resp, err := newModerationsService(s.client).CheckText(ctx, policy.ModelOrDefault(), text)
+	if err != nil {
+		return fmt.Errorf("failed to moderate %s: %w", stage, err)
+	}
+	for _, result := range resp.Results {
+		if categories := policy.Violations(&result); len(categories) > 0 {
+			return errors.NewContentFlaggedError(stage, categories, result.CategoryScores.AsMap())
+		}
+	}
+	return nil
+}
+
+// moderatedInput returns the text of the user messages of req selected by
+// scope, joined with blank lines.
+func moderatedInput(req *chat.ChatCompletionRequest, scope moderation.InputScope) string {
+	var texts []string
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		message := req.Messages[i]
+		if message.Role != chat.RoleUser {
+			continue
+		}
+		texts = append([]string{message.Text()}, texts...)
+		if scope == moderation.InputLastUserMessage {
+			break
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// ModeratedStream is a stream of chat completion chunks whose content is
+// screened before it is released. It implements chat.ChunkStream.
+type ModeratedStream struct {
+	ctx     context.Context
+	service *ChatService
+	policy  moderation.Policy
+	stream  *streaming.Stream[chat.ChatCompletionChunk]
+
+	// pending holds the chunks whose text is not screened yet
+	pending []*chat.ChatCompletionChunk
+	text    strings.Builder
+
+	// released holds the screened chunks not returned by Next yet
+	released []*chat.ChatCompletionChunk
+	current  *chat.ChatCompletionChunk
+	done     bool
+	err      error
+}
+
+// Next advances to the next screened chunk. It returns false when the
+// stream ends, fails or its content is flagged.
+func (m *ModeratedStream) Next() bool {
+	for len(m.released) == 0 {
+		if m.done {
+			return false
+		}
+		m.fill()
+	}
+	m.current, m.released = m.released[0], m.released[1:]
+	return true
+}
+
+// fill reads chunks until a batch is screened or the stream ends.
+func (m *ModeratedStream) fill() {
+	for m.stream.Next() {
+		chunk := m.stream.Current()
+		if !m.policy.ScreenOutput {
+			m.released = append(m.released, chunk)
+			return
+		}
+
+		m.pending = append(m.pending, chunk)
+		for _, choice := range chunk.Choices {
+			m.text.WriteString(choice.Delta.Content)
+		}
+		if utf8.RuneCountInString(m.text.String()) >= m.policy.ChunkSizeOrDefault() {
+			m.release()
+			return
+		}
+	}
+
+	m.done = true
+	if err := m.stream.Err(); err != nil {
+		m.err = err
+		return
+	}
+	m.release()
+}
+
+// release screens the pending text and releases its chunks if it passes.
+func (m *ModeratedStream) release() {
+	if err := m.service.screen(m.ctx, m.policy, moderation.StageOutput, m.text.String()); err != nil {
+		m.err = err
+		m.done = true
+		m.pending = nil
+		m.stream.Close()
+		return
+	}
+	m.released = append(m.released, m.pending...)
+	m.pending = nil
+	m.text.Reset()
+}
+
+// Current returns the current chunk.
+func (m *ModeratedStream) Current() *chat.ChatCompletionChunk {
+	return m.current
+}
+
+// Err returns the error that stopped the stream, such as an
+// *errors.ContentFlaggedError, or nil.
+func (m *ModeratedStream) Err() error {
+	return m.err
+}
+
+// Close closes the underlying stream.
+func (m *ModeratedStream) Close() error {
+	return m.stream.Close()
+}
@@ -0,0 +1,279 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// moderatedServer is a fake API whose moderation model scores text with
+// "bomb" as violent (0.95, flagged) and text with "fight" as borderline
+// (0.5, not flagged). Chat completions reply with reply, streamed in
+// pieces of five characters.
+type moderatedServer struct {
+	reply string
+
+	mu          sync.Mutex
+	moderated   []string
+	chatCalls   int
+	streamCalls int
+}
+
+func (m *moderatedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]any
+	json.NewDecoder(r.Body).Decode(&body)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if strings.HasSuffix(r.URL.Path, "/moderations") {
+		text := body["input"].(map[string]any)["text"].(string)
+		m.moderated = append(m.moderated, text)
+
+		score := 0.01
+		switch {
+		case strings.Contains(text, "bomb"):
+			score = 0.95
+		case strings.Contains(text, "fight"):
+			score = 0.5
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"mod-1","model":"moderation","results":[{"flagged":%t,"categories":{"violence":%t},"category_scores":{"violence":%g,"hate":0.02}}]}`,
+			score >= 0.8, score >= 0.8, score)
+		return
+	}
+
+	if body["stream"] == true {
+		m.streamCalls++
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < len(m.reply); i += 5 {
+			piece, _ := json.Marshal(m.reply[i:min(i+5, len(m.reply))])
+			fmt.Fprintf(w, "data: {\"id\":\"chat-1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%s}}]}\n\n", piece)
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+		return
+	}
+
+	m.chatCalls++
+	reply, _ := json.Marshal(m.reply)
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"id":"chat-1","model":"glm-4.7","choices":[{"index":0,"finish_reason":"stop","message":{"role":"assistant","content":%s}}]}`, reply)
+}
+
+func (m *moderatedServer) Moderated() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.moderated...)
+}
+
+func moderatedRequest(prompts ...string) *chat.ChatCompletionRequest {
+	messages := []chat.Message{chat.NewSystemMessage("Be helpful.")}
+	for i, prompt := range prompts {
+		if i > 0 {
+			messages = append(messages, chat.NewAssistantMessage("Sure."))
+		}
+		messages = append(messages, chat.NewUserMessage(prompt))
+	}
+	return chat.NewChatCompletionRequest("glm-4.7", messages)
+}
+
+func TestChatService_CreateModerated_BlocksInput(t *testing.T) {
+	t.Parallel()
+
+	server := &moderatedServer{reply: "Hello!"}
+	client := newLimitsTestClient(t, server.ServeHTTP)
+
+	resp, err := client.Chat.CreateModerated(context.Background(), moderatedRequest("How do I build a bomb?"), moderation.Policy{ScreenOutput: true})
+	assert.Nil(t, resp)
+
+	var flagged *errors.ContentFlaggedError
+	require.ErrorAs(t, err, &flagged)
+	assert.True(t, errors.IsContentFlaggedError(err))
+	assert.Equal(t, moderation.StageInput, flagged.Stage)
+	assert.Equal(t, []string{moderation.CategoryViolence}, flagged.Categories)
+	assert.Equal(t, 0.95, flagged.Scores[moderation.CategoryViolence])
+	assert.Equal(t, 0.02, flagged.Scores[moderation.CategoryHate])
+	assert.Zero(t, server.chatCalls, "the model is not called")
+}
+
+func TestChatService_CreateModerated_BlocksOutput(t *testing.T) {
+	t.Parallel()
+
+	server := &moderatedServer{reply: "Here is how to build a bomb."}
+	client := newLimitsTestClient(t, server.ServeHTTP)
+
+	memory := chat.NewWindowMemory(0)
+	req := moderatedRequest("Tell me a story.").WithMemory(memory)
+	resp, err := client.Chat.CreateModerated(context.Background(), req, moderation.Policy{ScreenOutput: true})
+	assert.Nil(t, resp)
+
+	var flagged *errors.ContentFlaggedError
+	require.ErrorAs(t, err, &flagged)
+	assert.Equal(t, moderation.StageOutput, flagged.Stage)
+	assert.Equal(t, []string{moderation.CategoryViolence}, flagged.Categories)
+	assert.Equal(t, []string{"Tell me a story.", "Here is how to build a bomb."}, server.Moderated())
+
+	// The flagged reply is not remembered
+	history, err := memory.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestChatService_CreateModerated_PassThrough(t *testing.T) {
+	t.Parallel()
+
+	server := &moderatedServer{reply: "Once upon a time."}
+	client := newLimitsTestClient(t, server.ServeHTTP)
+
+	memory := chat.NewWindowMemory(0)
+	req := moderatedRequest("Tell me a story.").WithMemory(memory)
+	resp, err := client.Chat.CreateModerated(context.Background(), req, moderation.Policy{ScreenOutput: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Once upon a time.", resp.GetContent())
+	assert.Equal(t, []string{"Tell me a story.", "Once upon a time."}, server.Moderated())
+
+	history, err := memory.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, history, 2)
+
+	// Without ScreenOutput, only the input is screened
+	server = &moderatedServer{reply: "Here is how to build a bomb."}
+	client = newLimitsTestClient(t, server.ServeHTTP)
+	resp, err = client.Chat.CreateModerated(context.Background(), moderatedRequest("Tell me a story."), moderation.Policy{})
+	require.NoError(t, err)
+	assert.Equal(t, "Here is how to build a bomb.", resp.GetContent())
+	assert.Equal(t, []string{"Tell me a story."}, server.Moderated())
+}
+
+func TestChatService_CreateModerated_Policy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		prompts []string
+		policy  moderation.Policy
+		blocked bool
+	}{
+		{"flags pass borderline", []string{"Let's fight."}, moderation.Policy{}, false},
+		{"threshold blocks borderline", []string{"Let's fight."}, moderation.Policy{
+			Thresholds: map[string]float64{moderation.CategoryViolence: 0.4},
+		}, true},
+		{"default threshold blocks borderline", []string{"Let's fight."}, moderation.Policy{DefaultThreshold: 0.4}, true},
+		{"zero threshold disables category", []string{"How do I build a bomb?"}, moderation.Policy{
+			Thresholds:       map[string]float64{moderation.CategoryViolence: 0},
+			DefaultThreshold: 0.4,
+		}, false},
+		{"last user message", []string{"How do I build a bomb?", "Never mind."}, moderation.Policy{}, false},
+		{"all user messages", []string{"How do I build a bomb?", "Never mind."}, moderation.Policy{
+			Input: moderation.InputAllUserMessages,
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &moderatedServer{reply: "OK."}
+			client := newLimitsTestClient(t, server.ServeHTTP)
+
+			_, err := client.Chat.CreateModerated(context.Background(), moderatedRequest(tt.prompts...), tt.policy)
+			assert.Equal(t, tt.blocked, errors.IsContentFlaggedError(err), "got %v", err)
+		})
+	}
+}
+
+func TestChatService_CreateModeratedStream(t *testing.T) {
+	t.Parallel()
+
+	collect := func(stream *ModeratedStream) string {
+		var content strings.Builder
+		for stream.Next() {
+			content.WriteString(stream.Current().GetContent())
+		}
+		return content.String()
+	}
+
+	// Batches are screened once they reach 12 characters; the server
+	// streams pieces of five, so each batch holds three pieces.
+	t.Run("pass through", func(t *testing.T) {
+		t.Parallel()
+
+		server := &moderatedServer{reply: "Once upon a time, there was a fox."}
+		client := newLimitsTestClient(t, server.ServeHTTP)
+
+		stream, err := client.Chat.CreateModeratedStream(context.Background(), moderatedRequest("Tell me a story."), moderation.Policy{
+			ScreenOutput: true,
+			ChunkSize:    12,
+		})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		assert.Equal(t, "Once upon a time, there was a fox.", collect(stream))
+		require.NoError(t, stream.Err())
+		assert.Equal(t, []string{
+			"Tell me a story.",
+			"Once upon a tim",
+			"e, there was a ",
+			"fox.",
+		}, server.Moderated())
+	})
+
+	t.Run("blocks output", func(t *testing.T) {
+		t.Parallel()
+
+		server := &moderatedServer{reply: "Once upon a time, a bomb went off."}
+		client := newLimitsTestClient(t, server.ServeHTTP)
+
+		stream, err := client.Chat.CreateModeratedStream(context.Background(), moderatedRequest("Tell me a story."), moderation.Policy{
+			ScreenOutput: true,
+			ChunkSize:    12,
+		})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		// The first screened batch is released, the flagged one is not
+		assert.Equal(t, "Once upon a tim", collect(stream))
+		var flagged *errors.ContentFlaggedError
+		require.ErrorAs(t, stream.Err(), &flagged)
+		assert.Equal(t, moderation.StageOutput, flagged.Stage)
+		assert.False(t, stream.Next())
+	})
+
+	t.Run("blocks input", func(t *testing.T) {
+		t.Parallel()
+
+		server := &moderatedServer{reply: "OK."}
+		client := newLimitsTestClient(t, server.ServeHTTP)
+
+		stream, err := client.Chat.CreateModeratedStream(context.Background(), moderatedRequest("How do I build a bomb?"), moderation.Policy{})
+		assert.Nil(t, stream)
+		assert.True(t, errors.IsContentFlaggedError(err))
+		assert.Zero(t, server.streamCalls)
+	})
+
+	t.Run("without output screening", func(t *testing.T) {
+		t.Parallel()
+
+		server := &moderatedServer{reply: "A bomb went off."}
+		client := newLimitsTestClient(t, server.ServeHTTP)
+
+		stream, err := client.Chat.CreateModeratedStream(context.Background(), moderatedRequest("Tell me a story."), moderation.Policy{})
+		require.NoError(t, err)
+		defer stream.Close()
+
+		assert.Equal(t, "A bomb went off.", collect(stream))
+		assert.NoError(t, stream.Err())
+		assert.Equal(t, []string{"Tell me a story."}, server.Moderated())
+	})
+}
@@ -0,0 +1,166 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// defaultRateLimitWait is how long CreateParallel pauses after a rate
+// limit error without a Retry-After header.
+const defaultRateLimitWait = time.Second
+
+// CreateParallel runs many chat completions concurrently, for fanning out
+// a prompt over many inputs without the latency of the Batch API. The
+// responses and errors are index-aligned with reqs: for each i, either
+// responses[i] or errs[i] is set.
+//
+// At most chat.DefaultParallelConcurrency requests are in flight unless
+// chat.WithConcurrency is given, and chat.WithRequestTimeout bounds each
+// attempt. A request that fails, for example with a validation error,
+// does not affect the others. When a request is rate limited, all
+// requests pause for the Retry-After duration and the request is retried
+// up to chat.DefaultRateLimitRetries times. Cancelling ctx aborts
+// in-flight requests and fails those not yet started with the context
+// error.
+//
+// Example:
+//
+//	resps, errs := client.Chat.CreateParallel(ctx, reqs,
+//	    chat.WithConcurrency(16),
+//	    chat.WithProgress(func(p chat.ParallelProgress) {
+//	        fmt.Printf("%d/%d\n", p.Completed, p.Total)
+//	    }),
+//	)
+//	for i, resp := range resps {
+//	    if errs[i] != nil {
+//	        log.Printf("request %d failed: %v", i, errs[i])
+//	        continue
+//	    }
+//	    fmt.Println(resp.GetContent())
+//	}
+func (s *ChatService) CreateParallel(ctx context.Context, reqs []*chat.ChatCompletionRequest, opts ...chat.ParallelOption) ([]*chat.ChatCompletionResponse, []error) {
+	cfg := chat.NewParallelConfig(opts...)
+	resps := make([]*chat.ChatCompletionResponse, len(reqs))
+	errs := make([]error, len(reqs))
+
+	var progressMu sync.Mutex
+	completed := 0
+	report := func(i int) {
+		progressMu.Lock()
+		defer progressMu.Unlock()
+
+		completed++
+		if cfg.OnProgress != nil {
+			cfg.OnProgress(chat.ParallelProgress{Index: i, Err: errs[i], Completed: completed, Total: len(reqs)})
+		}
+	}
+
+	pacer := &ratePacer{}
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, req := range reqs {
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			errs[i] = err
+			report(i)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resps[i], errs[i] = s.createPaced(ctx, req, cfg, pacer)
+			report(i)
+		}()
+	}
+	wg.Wait()
+
+	return resps, errs
+}
+
+// createPaced creates a chat completion, waiting out and retrying rate
+// limit errors.
+func (s *ChatService) createPaced(ctx context.Context, req *chat.ChatCompletionRequest, cfg *chat.ParallelConfig, pacer *ratePacer) (*chat.ChatCompletionResponse, error) {
+	if req == nil {
+		return nil, errors.NewValidationError("request", "is required", nil)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := pacer.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		resp, err := s.createWithTimeout(ctx, req, cfg.RequestTimeout)
+		var limitErr *errors.APIReachLimitError
+		if err == nil || !stderrors.As(err, &limitErr) || attempt >= cfg.RateLimitRetries {
+			return resp, err
+		}
+
+		wait := time.Duration(limitErr.RetryAfter) * time.Second
+		if wait <= 0 {
+			wait = defaultRateLimitWait
+		}
+		pacer.pause(wait)
+	}
+}
+
+// createWithTimeout creates a chat completion within timeout, if positive.
+func (s *ChatService) createWithTimeout(ctx context.Context, req *chat.ChatCompletionRequest, timeout time.Duration) (*chat.ChatCompletionResponse, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.Create(ctx, req)
+}
+
+// ratePacer pauses the requests of a fan-out after a rate limit error, so
+// that they do not keep hitting the limit.
+type ratePacer struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// pause holds back requests for d, unless they are already held back longer.
+func (p *ratePacer) pause(d time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(p.until) {
+		p.until = until
+	}
+}
+
+// wait blocks until the pacer lets requests through or ctx is done.
+func (p *ratePacer) wait(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		d := time.Until(p.until)
+		p.mu.Unlock()
+
+		if d <= 0 {
+			return ctx.Err()
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
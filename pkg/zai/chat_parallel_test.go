@@ -0,0 +1,204 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoChat replies to a chat completion with the last message's content.
+func echoChat(w http.ResponseWriter, r *http.Request) {
+	var req chat.ChatCompletionRequest
+	json.NewDecoder(r.Body).Decode(&req)
+	content, _ := req.Messages[len(req.Messages)-1].Content.(string)
+	writeJSON(w, chat.ChatCompletionResponse{
+		ID:      "chatcmpl-" + content,
+		Choices: []chat.Choice{{Message: chat.NewAssistantMessage(content)}},
+	})
+}
+
+func parallelRequests(prompts ...string) []*chat.ChatCompletionRequest {
+	reqs := make([]*chat.ChatCompletionRequest, len(prompts))
+	for i, p := range prompts {
+		reqs[i] = chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage(p)})
+	}
+	return reqs
+}
+
+func TestChatService_CreateParallel(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, echoChat)
+
+	reqs := parallelRequests("a", "b", "c", "d", "e")
+	reqs[1] = nil
+	reqs[3].SetPresencePenalty(5) // fails validation
+
+	var progress []chat.ParallelProgress
+	resps, errs := client.Chat.CreateParallel(context.Background(), reqs,
+		chat.WithConcurrency(2),
+		chat.WithProgress(func(p chat.ParallelProgress) { progress = append(progress, p) }),
+	)
+
+	require.Len(t, resps, 5)
+	require.Len(t, errs, 5)
+	for _, i := range []int{0, 2, 4} {
+		require.NoError(t, errs[i])
+		assert.Equal(t, reqs[i].Messages[0].Content, resps[i].GetContent())
+	}
+	assert.True(t, errors.IsValidationError(errs[1]))
+	assert.Nil(t, resps[1])
+	assert.True(t, errors.IsValidationError(errs[3]))
+	assert.Nil(t, resps[3])
+
+	require.Len(t, progress, 5)
+	for i, p := range progress {
+		assert.Equal(t, i+1, p.Completed)
+		assert.Equal(t, 5, p.Total)
+		assert.Equal(t, errs[p.Index], p.Err)
+	}
+}
+
+func TestChatService_CreateParallel_ConcurrencyCap(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		echoChat(w, r)
+	})
+
+	reqs := parallelRequests("1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12")
+	_, errs := client.Chat.CreateParallel(context.Background(), reqs, chat.WithConcurrency(3))
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), peak.Load())
+}
+
+func TestChatService_CreateParallel_RateLimitPacing(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		arrivals []time.Time
+		limited  time.Time
+	)
+	second := make(chan struct{})
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		n := len(arrivals)
+		mu.Unlock()
+
+		switch n {
+		case 1:
+			// Rate limit the first request once the second is in flight
+			<-second
+			mu.Lock()
+			limited = time.Now()
+			mu.Unlock()
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		case 2:
+			close(second)
+			// Finish after the rate limit has been seen by the client
+			time.Sleep(100 * time.Millisecond)
+		}
+		echoChat(w, r)
+	})
+
+	resps, errs := client.Chat.CreateParallel(context.Background(), parallelRequests("a", "b", "c", "d"),
+		chat.WithConcurrency(2))
+	for i, err := range errs {
+		require.NoError(t, err)
+		assert.NotNil(t, resps[i])
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, arrivals, 5, "the rate-limited request is retried")
+	for _, at := range arrivals[2:] {
+		assert.GreaterOrEqual(t, at.Sub(limited), 900*time.Millisecond, "requests must wait for Retry-After")
+	}
+}
+
+func TestChatService_CreateParallel_RateLimitRetriesExhausted(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, errs := client.Chat.CreateParallel(context.Background(), parallelRequests("a"),
+		chat.WithRateLimitRetries(0))
+	assert.True(t, errors.IsRateLimitError(errs[0]))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestChatService_CreateParallel_Cancel(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 10)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	start := time.Now()
+	resps, errs := client.Chat.CreateParallel(ctx, parallelRequests("a", "b", "c", "d", "e", "f"),
+		chat.WithConcurrency(2))
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	for i, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled, "request %d", i)
+		assert.Nil(t, resps[i])
+	}
+}
+
+func TestChatService_CreateParallel_RequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chat.ChatCompletionRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Messages[0].Content == "slow" {
+			<-r.Context().Done()
+			return
+		}
+		writeJSON(w, chat.ChatCompletionResponse{Choices: []chat.Choice{{Message: chat.NewAssistantMessage("ok")}}})
+	})
+
+	_, errs := client.Chat.CreateParallel(context.Background(), parallelRequests("fast", "slow"),
+		chat.WithRequestTimeout(100*time.Millisecond))
+	assert.NoError(t, errs[0])
+	assert.ErrorIs(t, errs[1], context.DeadlineExceeded)
+}
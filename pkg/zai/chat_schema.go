@@ -0,0 +1,56 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// checkSchema attaches the schema to resp and, if the request retries on
+// violations, asks the model once to correct a reply that does not match.
+func (s *ChatService) checkSchema(ctx context.Context, send *chat.ChatCompletionRequest, resp *chat.ChatCompletionResponse, config *chat.SchemaConfig) (*chat.ChatCompletionResponse, error) {
+	resp.SetSchema(config.Schema)
+	if !config.RetryOnViolation {
+		return resp, nil
+	}
+	violations := resp.ValidateAgainstSchema()
+	if len(violations) == 0 {
+		return resp, nil
+	}
+
+	retry := *send
+	retry.Messages = slices.Clip(send.Messages)
+	if choice := resp.GetFirstChoice(); choice != nil {
+		retry.Messages = append(retry.Messages, choice.Message)
+	}
+	retry.Messages = append(retry.Messages, schemaCorrection(config.Schema, violations))
+
+	corrected, err := s.create(ctx, &retry)
+	if err != nil {
+		return nil, err
+	}
+	corrected.SetSchema(config.Schema)
+	if violations := corrected.ValidateAgainstSchema(); len(violations) > 0 {
+		return corrected, &chat.SchemaValidationError{Violations: violations}
+	}
+	return corrected, nil
+}
+
+// schemaCorrection returns the system message asking the model to correct
+// a reply with violations of schema.
+func schemaCorrection(schema *chat.Schema, violations []chat.SchemaViolation) chat.Message {
+	var b strings.Builder
+	b.WriteString("Your previous reply does not match the required JSON schema:\n")
+	for _, v := range violations {
+		fmt.Fprintf(&b, "- %s\n", v)
+	}
+	if data, err := json.Marshal(schema); err == nil {
+		fmt.Fprintf(&b, "The schema is: %s\n", data)
+	}
+	b.WriteString("Reply again with only the corrected JSON.")
+	return chat.NewSystemMessage(b.String())
+}
@@ -0,0 +1,132 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+type schemaCity struct {
+	Name       string `json:"name"`
+	Population int    `json:"population"`
+}
+
+// newSchemaTestClient returns a client whose chat endpoint replies with
+// replies in turn and records the messages of every request.
+func newSchemaTestClient(t *testing.T, replies ...string) (*Client, func() [][]chat.Message) {
+	var (
+		mu       sync.Mutex
+		received [][]chat.Message
+	)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chat.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, &chat.ResponseFormatJSON, req.ResponseFormat)
+
+		mu.Lock()
+		defer mu.Unlock()
+		reply := replies[len(received)]
+		received = append(received, req.Messages)
+
+		writeJSON(w, map[string]any{
+			"id":      "chatcmpl-1",
+			"model":   "glm-4.7",
+			"choices": []map[string]any{{"index": 0, "message": map[string]any{"role": "assistant", "content": reply}, "finish_reason": "stop"}},
+		})
+	})
+	return client, func() [][]chat.Message {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+func newSchemaRequest(t *testing.T, opts ...chat.SchemaOption) *chat.ChatCompletionRequest {
+	schema, err := chat.SchemaFor[schemaCity]()
+	require.NoError(t, err)
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Largest city in Norway?")})
+	return chat.WithSchema(req, schema, opts...)
+}
+
+func TestChatService_CreateWithSchema(t *testing.T) {
+	t.Parallel()
+
+	client, received := newSchemaTestClient(t, `{"name": "Oslo", "population": "700k"}`)
+
+	resp, err := client.Chat.Create(context.Background(), newSchemaRequest(t))
+	require.NoError(t, err, "violations are only reported without the retry")
+
+	assert.Len(t, received(), 1)
+	assert.Equal(t, []chat.SchemaViolation{
+		{Path: "$.population", Expected: "integer", Got: "string"},
+	}, resp.ValidateAgainstSchema())
+}
+
+func TestChatService_CreateWithSchemaRetry(t *testing.T) {
+	t.Parallel()
+
+	invalid := `{"name": "Oslo"}`
+	client, received := newSchemaTestClient(t, invalid, `{"name": "Oslo", "population": 709000}`)
+
+	memory := chat.NewWindowMemory(0)
+	req := newSchemaRequest(t, chat.WithSchemaRetry()).WithMemory(memory)
+	resp, err := client.Chat.Create(context.Background(), req)
+	require.NoError(t, err)
+	assert.Empty(t, resp.ValidateAgainstSchema())
+
+	requests := received()
+	require.Len(t, requests, 2)
+	retry := requests[1]
+	require.Len(t, retry, 3)
+	assert.Equal(t, chat.RoleAssistant, retry[1].Role)
+	assert.Equal(t, invalid, retry[1].Content)
+	assert.Equal(t, chat.RoleSystem, retry[2].Role)
+	assert.Contains(t, retry[2].Content, "- $.population: expected required property, got nothing")
+	assert.Contains(t, retry[2].Content, `"required":["name","population"]`)
+
+	// Only the corrected reply is remembered
+	messages, err := memory.Messages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	assert.Equal(t, resp.GetContent(), messages[1].Content)
+}
+
+func TestChatService_CreateWithSchemaRetry_StillInvalid(t *testing.T) {
+	t.Parallel()
+
+	client, received := newSchemaTestClient(t, "Oslo", `{"name": "Oslo", "population": 7.5}`)
+
+	memory := chat.NewWindowMemory(0)
+	req := newSchemaRequest(t, chat.WithSchemaRetry()).WithMemory(memory)
+	resp, err := client.Chat.Create(context.Background(), req)
+
+	var schemaErr *chat.SchemaValidationError
+	require.ErrorAs(t, err, &schemaErr)
+	assert.Equal(t, []chat.SchemaViolation{
+		{Path: "$.population", Expected: "integer", Got: "number"},
+	}, schemaErr.Violations)
+	require.NotNil(t, resp, "the corrected reply is returned with the error")
+	assert.Contains(t, resp.GetContent(), "7.5")
+	assert.Len(t, received(), 2, "the request is retried only once")
+
+	messages, err := memory.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestChatService_CreateWithSchemaRetry_Valid(t *testing.T) {
+	t.Parallel()
+
+	client, received := newSchemaTestClient(t, `{"name": "Oslo", "population": 709000}`)
+
+	_, err := client.Chat.Create(context.Background(), newSchemaRequest(t, chat.WithSchemaRetry()))
+	require.NoError(t, err)
+	assert.Len(t, received(), 1)
+}
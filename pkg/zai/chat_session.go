@@ -0,0 +1,45 @@
+package zai
+
+import (
+	"context"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// NewSession creates a multi-turn conversation with model, which keeps the
+// history and sends it with every turn. An empty model uses the client's
+// default chat model. A session is not safe for concurrent use; see
+// chat.Session.
+//
+// Example:
+//
+//	session := client.Chat.NewSession("glm-4.7",
+//	    chat.WithSystemPrompt("You are a patient math tutor."),
+//	    chat.WithPreservedThinking(),
+//	)
+//
+//	resp, err := session.Send(ctx, "How many of 9 fruits are apples if apples cost $2, oranges $3 and the total is $23?")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(resp.GetContent())
+//
+//	// The reply, with its reasoning content, is sent back with this turn
+//	resp, err = session.Send(ctx, "Verify that answer by checking the totals.")
+func (s *ChatService) NewSession(model string, opts ...chat.SessionOption) *chat.Session {
+	return chat.NewSession(sessionClient{s}, model, opts...)
+}
+
+// sessionClient sends the requests of a chat.Session with a ChatService.
+type sessionClient struct {
+	*ChatService
+}
+
+// CreateChunkStream creates a streaming chat completion.
+func (c sessionClient) CreateChunkStream(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (chat.ChunkStream, error) {
+	stream, err := c.CreateStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
@@ -0,0 +1,91 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+func TestChatService_NewSession(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []map[string]any
+	)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		mu.Lock()
+		received = append(received, body)
+		turn := len(received)
+		mu.Unlock()
+
+		if body["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"reasoning_content\":\"streamed reasoning\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"streamed reply\"},\"finish_reason\":\"stop\"}]}\n\n")
+			fmt.Fprint(w, "data: [DONE]\n\n")
+			return
+		}
+		writeJSON(w, map[string]any{
+			"id":    fmt.Sprintf("chatcmpl-%d", turn),
+			"model": "glm-4.7",
+			"choices": []map[string]any{{
+				"index": 0,
+				"message": map[string]any{
+					"role":              "assistant",
+					"content":           fmt.Sprintf("reply %d", turn),
+					"reasoning_content": fmt.Sprintf("reasoning %d", turn),
+				},
+				"finish_reason": "stop",
+			}},
+		})
+	})
+
+	ctx := context.Background()
+	session := client.Chat.NewSession("glm-4.7",
+		chat.WithSystemPrompt("be brief"),
+		chat.WithPreservedThinking(),
+	)
+
+	resp, err := session.Send(ctx, "question 1")
+	require.NoError(t, err)
+	assert.Equal(t, "reply 1", resp.GetContent())
+
+	stream, err := session.SendStream(ctx, "question 2")
+	require.NoError(t, err)
+	for stream.Next() {
+	}
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+
+	_, err = session.Send(ctx, "question 3")
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+
+	last := received[2]
+	assert.Equal(t, map[string]any{"type": "enabled", "clear_thinking": false}, last["thinking"])
+
+	messages := last["messages"].([]any)
+	require.Len(t, messages, 6)
+	assert.Equal(t, "be brief", messages[0].(map[string]any)["content"])
+	assert.Equal(t, "reasoning 1", messages[2].(map[string]any)["reasoning_content"], "reasoning is sent back unmodified")
+	assert.Equal(t, "streamed reasoning", messages[4].(map[string]any)["reasoning_content"])
+	assert.Equal(t, "streamed reply", messages[4].(map[string]any)["content"])
+	assert.Equal(t, "question 3", messages[5].(map[string]any)["content"])
+
+	assert.Len(t, session.History(), 6)
+}
@@ -7,10 +7,12 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestChatService_Create(t *testing.T) {
@@ -541,3 +543,128 @@ func TestClient_ChatService_Integration(t *testing.T) {
 		assert.Contains(t, conversationLog, "user: Thanks!")
 	})
 }
+
+func TestChatService_Create_ValidatesSampling(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")}).
+		SetFrequencyPenalty(3)
+
+	_, err = client.Chat.Create(context.Background(), req)
+	require.Error(t, err)
+
+	var validationErr *errors.ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = client.Chat.CreateStream(context.Background(), req)
+	require.Error(t, err)
+
+	assert.False(t, called)
+}
+
+// newStallingStreamServer sends one chunk and then stops sending without
+// closing the connection, as a proxy that dropped the upstream would.
+func newStallingStreamServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hel\"}}]}\n\n"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestChatService_StreamIdleTimeout(t *testing.T) {
+	t.Parallel()
+
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hello")})
+
+	t.Run("client default", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(newStallingStreamServer(t).URL),
+			WithStreamIdleTimeout(200*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		start := time.Now()
+		content, err := client.Chat.StreamContent(context.Background(), req.Clone())
+		assert.Less(t, time.Since(start), 5*time.Second)
+
+		assert.Equal(t, "Hel", content)
+		assert.True(t, errors.IsStreamIdleTimeoutError(err), "got %v", err)
+	})
+
+	t.Run("per-stream override", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(newStallingStreamServer(t).URL),
+			WithStreamIdleTimeout(time.Hour),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		stream, err := client.Chat.CreateStream(context.Background(), req.Clone(),
+			chat.WithStreamIdleTimeout(100*time.Millisecond),
+		)
+		require.NoError(t, err)
+		defer stream.Close()
+
+		require.True(t, stream.Next())
+		assert.False(t, stream.Next())
+
+		var idleErr *errors.StreamIdleTimeoutError
+		require.ErrorAs(t, stream.Err(), &idleErr)
+		assert.Equal(t, 100*time.Millisecond, idleErr.Timeout)
+	})
+
+	t.Run("close unblocks a stalled stream", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(newStallingStreamServer(t).URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		stream, err := client.Chat.CreateStream(context.Background(), req.Clone())
+		require.NoError(t, err)
+		require.True(t, stream.Next())
+
+		result := make(chan bool)
+		go func() { result <- stream.Next() }()
+
+		time.Sleep(50 * time.Millisecond)
+		require.NoError(t, stream.Close())
+
+		select {
+		case ok := <-result:
+			assert.False(t, ok)
+		case <-time.After(2 * time.Second):
+			t.Fatal("Close did not unblock Next")
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+)
+
+// trimmedRetry trims the messages of send, which the API rejected with
+// the context length error apiErr, and sends the trimmed request once. It
+// returns the request sent, or apiErr if no message could be dropped.
+func (s *ChatService) trimmedRetry(ctx context.Context, send *chat.ChatCompletionRequest, config *chat.AutoTrimConfig, apiErr error) (*chat.ChatCompletionRequest, *chat.ChatCompletionResponse, error) {
+	counter := config.Counter
+	if counter == nil {
+		counter = estimateCounter{}
+	}
+
+	tokens, err := counter.CountTokens(ctx, send.Model, send.Messages)
+	if err != nil {
+		return send, nil, stderrors.Join(apiErr, err)
+	}
+	limit := config.MaxPromptTokens
+	if limit <= 0 {
+		limit = tokens * 3 / 4
+	}
+
+	kept, keptTokens, err := chat.TruncateMessages(ctx, counter, send.Model, send.Messages, limit, config.Strategy)
+	if err != nil {
+		return send, nil, stderrors.Join(apiErr, err)
+	}
+	if len(kept) == len(send.Messages) {
+		return send, nil, apiErr
+	}
+
+	report := &chat.TrimReport{
+		Strategy:        config.Strategy,
+		Messages:        len(send.Messages),
+		Kept:            len(kept),
+		Tokens:          tokens,
+		KeptTokens:      keptTokens,
+		MaxPromptTokens: limit,
+	}
+	if config.OnTrim != nil {
+		config.OnTrim(report)
+	}
+
+	retry := *send
+	retry.Messages = kept
+	resp, err := s.create(ctx, &retry)
+	if err != nil {
+		return &retry, nil, err
+	}
+	resp.Trimmed = report
+	return &retry, resp, nil
+}
+
+// estimateCounter is the chat.TokenCounter of the offline token estimate.
+type estimateCounter struct{}
+
+// CountTokens implements chat.TokenCounter.
+func (estimateCounter) CountTokens(_ context.Context, model string, messages []chat.Message) (int, error) {
+	return tools.EstimateRequestTokens(tools.NewTokenizerRequest(model, messages)), nil
+}
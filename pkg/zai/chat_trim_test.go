@@ -0,0 +1,185 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// newTrimTestClient returns a client whose chat endpoint rejects requests
+// with more than maxMessages messages with the context length error code,
+// and records every request.
+func newTrimTestClient(t *testing.T, maxMessages int) (*Client, func() []chat.ChatCompletionRequest) {
+	var (
+		mu       sync.Mutex
+		received []chat.ChatCompletionRequest
+	)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var req chat.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		mu.Lock()
+		received = append(received, req)
+		mu.Unlock()
+
+		if len(req.Messages) > maxMessages {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"1261","message":"Prompt exceeds max length"}}`))
+			return
+		}
+		writeJSON(w, map[string]any{
+			"id":      "chatcmpl-1",
+			"model":   "glm-4.7",
+			"choices": []map[string]any{{"index": 0, "message": map[string]any{"role": "assistant", "content": "Done"}, "finish_reason": "stop"}},
+		})
+	})
+	return client, func() []chat.ChatCompletionRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return received
+	}
+}
+
+// longConversation returns a system message followed by turns of long
+// messages, the oldest of them a tool call round.
+func longConversation(turns int) []chat.Message {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog. ", 20)
+	messages := []chat.Message{
+		chat.NewSystemMessage("You are a helpful assistant."),
+		chat.NewUserMessage(text),
+		{Role: chat.RoleAssistant, ToolCalls: []chat.ToolCall{{ID: "call_1", Type: "function", Function: chat.FunctionCall{Name: "lookup", Arguments: `{"q":"fox"}`}}}},
+		chat.NewToolMessage("call_1", text),
+		chat.NewAssistantMessage(text),
+	}
+	for range turns {
+		messages = append(messages, chat.NewUserMessage(text), chat.NewAssistantMessage(text))
+	}
+	return append(messages, chat.NewUserMessage("Summarize our conversation."))
+}
+
+func TestChatService_CreateWithAutoTrim(t *testing.T) {
+	t.Parallel()
+
+	messages := longConversation(4)
+	client, received := newTrimTestClient(t, len(messages)-1)
+
+	req := chat.NewChatCompletionRequest("glm-4.7", messages)
+	req.Tools = []chat.Tool{chat.NewFunctionTool("lookup", "Looks up a term", nil)}
+
+	var reports []*chat.TrimReport
+	resp, err := client.Chat.Create(context.Background(), req,
+		chat.WithAutoTrim(chat.TruncateOldest, chat.WithTrimCallback(func(r *chat.TrimReport) {
+			reports = append(reports, r)
+		})),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Done", resp.GetContent())
+
+	requests := received()
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[0].Messages, len(messages))
+
+	trimmed := requests[1].Messages
+	assert.Less(t, len(trimmed), len(messages))
+	assert.Equal(t, chat.RoleSystem, trimmed[0].Role, "the system message is kept")
+	assert.NotEqual(t, chat.RoleTool, trimmed[1].Role, "a tool message is dropped with its tool call")
+	assert.Equal(t, "Summarize our conversation.", trimmed[len(trimmed)-1].Content)
+	assert.Equal(t, requests[0].Tools, requests[1].Tools, "tool definitions are sent unchanged")
+
+	require.NotNil(t, resp.Trimmed)
+	assert.Equal(t, []*chat.TrimReport{resp.Trimmed}, reports)
+	assert.Equal(t, chat.TruncateOldest, resp.Trimmed.Strategy)
+	assert.Equal(t, len(messages), resp.Trimmed.Messages)
+	assert.Equal(t, len(trimmed), resp.Trimmed.Kept)
+	assert.Equal(t, len(messages)-len(trimmed), resp.Trimmed.Dropped())
+	assert.LessOrEqual(t, resp.Trimmed.KeptTokens, resp.Trimmed.MaxPromptTokens)
+	assert.Less(t, resp.Trimmed.MaxPromptTokens, resp.Trimmed.Tokens)
+
+	assert.Equal(t, messages, req.Messages, "the request is not changed")
+}
+
+func TestChatService_CreateWithAutoTrim_Limit(t *testing.T) {
+	t.Parallel()
+
+	messages := longConversation(4)
+	client, received := newTrimTestClient(t, 3)
+
+	resp, err := client.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", messages),
+		chat.WithAutoTrim(chat.TruncateOldest, chat.WithTrimLimit(250)),
+	)
+	require.NoError(t, err)
+
+	requests := received()
+	require.Len(t, requests, 2)
+	assert.Len(t, requests[1].Messages, 3)
+	assert.Equal(t, 250, resp.Trimmed.MaxPromptTokens)
+}
+
+func TestChatService_CreateWithAutoTrim_Failures(t *testing.T) {
+	t.Parallel()
+
+	t.Run("without auto-trim", func(t *testing.T) {
+		t.Parallel()
+
+		messages := longConversation(2)
+		client, received := newTrimTestClient(t, 1)
+
+		_, err := client.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", messages))
+		assert.True(t, errors.IsContextLengthExceeded(err))
+		assert.Len(t, received(), 1)
+	})
+
+	t.Run("retried once", func(t *testing.T) {
+		t.Parallel()
+
+		messages := longConversation(2)
+		client, received := newTrimTestClient(t, 1)
+
+		resp, err := client.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", messages),
+			chat.WithAutoTrim(chat.TruncateOldest))
+		assert.Nil(t, resp)
+		assert.True(t, errors.IsContextLengthExceeded(err))
+		assert.Len(t, received(), 2)
+	})
+
+	t.Run("nothing to drop", func(t *testing.T) {
+		t.Parallel()
+
+		client, received := newTrimTestClient(t, 1)
+		messages := []chat.Message{
+			chat.NewSystemMessage("You are a helpful assistant."),
+			chat.NewUserMessage(strings.Repeat("word ", 500)),
+		}
+
+		_, err := client.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", messages),
+			chat.WithAutoTrim(chat.TruncateOldest))
+		assert.True(t, errors.IsContextLengthExceeded(err))
+		var limitErr *chat.TokenLimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Len(t, received(), 1)
+	})
+
+	t.Run("other error", func(t *testing.T) {
+		t.Parallel()
+
+		client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":{"code":"1214","message":"invalid"}}`))
+		})
+
+		var trimmed bool
+		_, err := client.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", longConversation(2)),
+			chat.WithAutoTrim(chat.TruncateOldest, chat.WithTrimCallback(func(*chat.TrimReport) { trimmed = true })))
+		assert.True(t, errors.IsInvalidParameter(err))
+		assert.False(t, trimmed)
+	})
+}
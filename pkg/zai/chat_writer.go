@@ -0,0 +1,190 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// StreamToWriter streams a chat completion, writing the content deltas of
+// the first choice to contentW and its reasoning deltas to reasoningW as
+// they arrive, and returns the accumulated response with the usage and
+// finish reason of the stream. Either writer may be nil to discard that
+// output. opts are passed to CreateStream.
+//
+// Writers with a Flush method, such as http.ResponseWriter or
+// bufio.Writer, are flushed after every delta so the output appears in
+// real time. A failing write or flush stops the stream and is returned
+// wrapped. If the request has a memory, the accumulated message is
+// appended to it as the assistant reply once the stream completes.
+//
+// Example:
+//
+//	resp, err := client.Chat.StreamToWriter(ctx, req, os.Stdout, os.Stderr)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("\n%d tokens, finished: %s\n", resp.Usage.TotalTokens, resp.Choices[0].FinishReason)
+func (s *ChatService) StreamToWriter(ctx context.Context, req *chat.ChatCompletionRequest, contentW, reasoningW io.Writer, opts ...chat.StreamOption) (*chat.ChatCompletionResponse, error) {
+	stream, err := s.CreateStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	collector := chat.NewToolCallCollector(stream)
+	defer collector.Close()
+
+	acc := newStreamAccumulator()
+	for collector.Next() {
+		event := collector.Current()
+		if event.ToolCall != nil {
+			c := acc.choice(event.ChoiceIndex)
+			c.Message.ToolCalls = append(c.Message.ToolCalls, *event.ToolCall)
+			continue
+		}
+
+		chunk := event.Chunk
+		acc.add(chunk)
+		for _, choice := range chunk.Choices {
+			if choice.Index != 0 {
+				continue
+			}
+			// Reasoning precedes the content it leads to
+			if err := writeDelta(reasoningW, choice.Delta.ReasoningContent); err != nil {
+				return acc.response(), fmt.Errorf("failed to write reasoning: %w", err)
+			}
+			if err := writeDelta(contentW, choice.Delta.Content); err != nil {
+				return acc.response(), fmt.Errorf("failed to write content: %w", err)
+			}
+		}
+	}
+
+	resp := acc.response()
+	if err := collector.Err(); err != nil {
+		return resp, err
+	}
+
+	if choice := resp.GetFirstChoice(); choice != nil {
+		if err := req.Remember(ctx, choice.Message); err != nil {
+			return resp, err
+		}
+	}
+
+	return resp, nil
+}
+
+// writeDelta writes delta to w and flushes w if it supports flushing.
+// Empty deltas and nil writers are skipped.
+func writeDelta(w io.Writer, delta string) error {
+	if w == nil || delta == "" {
+		return nil
+	}
+	if _, err := io.WriteString(w, delta); err != nil {
+		return err
+	}
+
+	switch f := w.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
+
+// streamAccumulator assembles a chat completion response from streamed
+// chunks.
+type streamAccumulator struct {
+	resp      chat.ChatCompletionResponse
+	choices   map[int]*chat.Choice
+	content   map[int]*strings.Builder
+	reasoning map[int]*strings.Builder
+}
+
+// newStreamAccumulator creates an empty accumulator.
+func newStreamAccumulator() *streamAccumulator {
+	return &streamAccumulator{
+		resp:      chat.ChatCompletionResponse{Object: "chat.completion"},
+		choices:   make(map[int]*chat.Choice),
+		content:   make(map[int]*strings.Builder),
+		reasoning: make(map[int]*strings.Builder),
+	}
+}
+
+// choice returns the choice with index, creating it if needed.
+func (a *streamAccumulator) choice(index int) *chat.Choice {
+	c, ok := a.choices[index]
+	if !ok {
+		c = &chat.Choice{Index: index, Message: chat.Message{Role: chat.RoleAssistant}}
+		a.choices[index] = c
+		a.content[index] = &strings.Builder{}
+		a.reasoning[index] = &strings.Builder{}
+	}
+	return c
+}
+
+// add merges chunk into the response.
+func (a *streamAccumulator) add(chunk *chat.ChatCompletionChunk) {
+	if chunk.ID != "" {
+		a.resp.ID = chunk.ID
+	}
+	if chunk.Model != "" {
+		a.resp.Model = chunk.Model
+	}
+	if chunk.Created != 0 {
+		a.resp.Created = chunk.Created
+	}
+	if chunk.SystemFingerprint != "" {
+		a.resp.SystemFingerprint = chunk.SystemFingerprint
+	}
+	if chunk.ServiceTier != "" {
+		a.resp.ServiceTier = chunk.ServiceTier
+	}
+	if chunk.ModelVersion != "" {
+		a.resp.ModelVersion = chunk.ModelVersion
+	}
+	if chunk.Usage != nil {
+		a.resp.Usage = chunk.Usage
+	}
+
+	for _, cc := range chunk.Choices {
+		c := a.choice(cc.Index)
+		if cc.Delta.Role != "" {
+			c.Message.Role = cc.Delta.Role
+		}
+		a.content[cc.Index].WriteString(cc.Delta.Content)
+		a.reasoning[cc.Index].WriteString(cc.Delta.ReasoningContent)
+		if cc.Delta.FunctionCall != nil {
+			c.Message.FunctionCall = cc.Delta.FunctionCall
+		}
+		if cc.FinishReason != "" {
+			c.FinishReason = cc.FinishReason
+		}
+		if cc.LogProbs != nil {
+			if c.LogProbs == nil {
+				c.LogProbs = &chat.LogProbs{}
+			}
+			c.LogProbs.Content = append(c.LogProbs.Content, cc.LogProbs.Content...)
+		}
+	}
+}
+
+// response returns the response accumulated so far, with its choices in
+// index order.
+func (a *streamAccumulator) response() *chat.ChatCompletionResponse {
+	resp := a.resp
+	resp.Choices = make([]chat.Choice, 0, len(a.choices))
+	for _, index := range slices.Sorted(maps.Keys(a.choices)) {
+		c := *a.choices[index]
+		c.Message.Content = a.content[index].String()
+		c.Message.ReasoningContent = a.reasoning[index].String()
+		resp.Choices = append(resp.Choices, c)
+	}
+	return &resp
+}
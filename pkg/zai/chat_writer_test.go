@@ -0,0 +1,158 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// recordingWriter records every write and flush, in order.
+type recordingWriter struct {
+	name   string
+	events *[]string
+	err    error
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	*w.events = append(*w.events, w.name+":"+string(p))
+	return len(p), nil
+}
+
+func (w *recordingWriter) Flush() {
+	*w.events = append(*w.events, w.name+":flush")
+}
+
+// newSSEServer streams events as server-sent events, followed by [DONE].
+func newSSEServer(t *testing.T, events ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range events {
+			w.Write([]byte("data: " + event + "\n\n"))
+		}
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writerStreamEvents is a stream with reasoning, content, a tool call and
+// usage, with the model build fields split over the first and last chunk.
+var writerStreamEvents = []string{
+	`{"id":"chat-1","created":1700000000,"model":"glm-4.7","system_fingerprint":"fp_8c1e4a7d2b","choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"Think"}}]}`,
+	`{"id":"chat-1","choices":[{"index":0,"delta":{"reasoning_content":"ing."}}]}`,
+	`{"id":"chat-1","choices":[{"index":0,"delta":{"content":"Hé"}}]}`,
+	`{"id":"chat-1","choices":[{"index":0,"delta":{"content":"llo\n"}}]}`,
+	`{"id":"chat-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call-1","type":"function","function":{"name":"lookup","arguments":"{\"q\":"}}]}}]}`,
+	`{"id":"chat-1","choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"go\"}"}}]}}]}`,
+	`{"id":"chat-1","model_version":"glm-4.7-20251001","service_tier":"default","choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}],"usage":{"prompt_tokens":5,"completion_tokens":7,"total_tokens":12}}`,
+}
+
+func TestChatService_StreamToWriter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("writes deltas in order", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(newSSEServer(t, writerStreamEvents...).URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		var events []string
+		content := &recordingWriter{name: "content", events: &events}
+		reasoning := &recordingWriter{name: "reasoning", events: &events}
+
+		resp, err := client.Chat.StreamToWriter(context.Background(), newChatRequest(), content, reasoning)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{
+			"reasoning:Think", "reasoning:flush",
+			"reasoning:ing.", "reasoning:flush",
+			"content:Hé", "content:flush",
+			"content:llo\n", "content:flush",
+		}, events)
+
+		assert.Equal(t, "chat-1", resp.ID)
+		assert.Equal(t, "glm-4.7", resp.Model)
+		assert.Equal(t, int64(1700000000), resp.Created)
+		require.Len(t, resp.Choices, 1)
+		assert.Equal(t, "Héllo\n", resp.GetContent())
+		assert.Equal(t, "Thinking.", resp.GetReasoningContent())
+		assert.Equal(t, chat.FinishReasonToolCalls, resp.Choices[0].FinishReason)
+		require.Len(t, resp.Choices[0].Message.ToolCalls, 1)
+		assert.Equal(t, "lookup", resp.Choices[0].Message.ToolCalls[0].Function.Name)
+		assert.Equal(t, `{"q":"go"}`, resp.Choices[0].Message.ToolCalls[0].Function.Arguments)
+		require.NotNil(t, resp.Usage)
+		assert.Equal(t, 12, resp.Usage.TotalTokens)
+		assert.Equal(t, chat.ModelBuild{
+			Model:             "glm-4.7",
+			ModelVersion:      "glm-4.7-20251001",
+			SystemFingerprint: "fp_8c1e4a7d2b",
+			ServiceTier:       "default",
+		}, resp.GetModelBuild())
+	})
+
+	t.Run("nil writers", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(newSSEServer(t, writerStreamEvents...).URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		var content strings.Builder
+		resp, err := client.Chat.StreamToWriter(context.Background(), newChatRequest(), &content, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "Héllo\n", content.String())
+		assert.Equal(t, "Thinking.", resp.GetReasoningContent())
+	})
+
+	t.Run("writer error aborts the stream", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(newSSEServer(t, writerStreamEvents...).URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		var events []string
+		broken := stderrors.New("broken pipe")
+		content := &recordingWriter{name: "content", events: &events, err: broken}
+		reasoning := &recordingWriter{name: "reasoning", events: &events}
+
+		resp, err := client.Chat.StreamToWriter(context.Background(), newChatRequest(), content, reasoning)
+		require.ErrorIs(t, err, broken)
+		assert.Contains(t, err.Error(), "write content")
+
+		// Nothing is read past the failed write
+		assert.Equal(t, []string{"reasoning:Think", "reasoning:flush", "reasoning:ing.", "reasoning:flush"}, events)
+		assert.Equal(t, "Hé", resp.GetContent())
+		assert.Nil(t, resp.Usage)
+	})
+
+	t.Run("remembers the reply", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(newSSEServer(t, writerStreamEvents[:4]...).URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		memory := chat.NewWindowMemory(0)
+		req := newChatRequest().WithMemory(memory)
+		_, err = client.Chat.StreamToWriter(context.Background(), req, nil, nil)
+		require.NoError(t, err)
+
+		messages, err := memory.Messages(context.Background())
+		require.NoError(t, err)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "Héllo\n", messages[1].Content)
+		assert.Equal(t, "Thinking.", messages[1].ReasoningContent)
+	})
+}
@@ -0,0 +1,71 @@
+package zai
+
+import (
+	"net/url"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// CircuitBreakerConfig configures the circuit breaker set with
+// WithCircuitBreaker. Zero fields use the defaults: the circuit opens
+// after 5 consecutive failures, stays open for 30 seconds and then lets
+// one probe request through.
+type CircuitBreakerConfig = transport.CircuitBreakerConfig
+
+// CircuitState is the state of the circuit of a host.
+type CircuitState = transport.CircuitState
+
+const (
+	// CircuitClosed lets every request through.
+	CircuitClosed = transport.CircuitClosed
+
+	// CircuitOpen fails every request with an *errors.CircuitOpenError
+	// without sending it.
+	CircuitOpen = transport.CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probe requests through.
+	// A successful probe closes the circuit and a failed one opens it
+	// again.
+	CircuitHalfOpen = transport.CircuitHalfOpen
+)
+
+// WithCircuitBreaker fails requests fast while the API is failing, instead
+// of sending and retrying them. After too many 5xx responses or connection
+// errors to a host, its circuit opens: requests fail immediately with an
+// *errors.CircuitOpenError, and are not retried, until the cool-down has
+// passed. Then a limited number of probes decide whether the circuit
+// closes again. 4xx responses, including rate limits, never open a
+// circuit. Each attempt counts, including retries and streams.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithCircuitBreaker(zai.CircuitBreakerConfig{
+//	        FailureRate: 0.5,
+//	        MinRequests: 20,
+//	        Window:      time.Minute,
+//	        CoolDown:    15 * time.Second,
+//	        OnStateChange: func(host string, from, to zai.CircuitState) {
+//	            log.Printf("circuit for %s: %s -> %s", host, from, to)
+//	        },
+//	    }),
+//	)
+func WithCircuitBreaker(config CircuitBreakerConfig) ClientOption {
+	return func(c *ClientConfig) {
+		c.CircuitBreaker = &config
+	}
+}
+
+// CircuitState returns the state of the circuit of the API host. It is
+// CircuitClosed without WithCircuitBreaker.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	u, err := url.Parse(c.config.BaseURL)
+	if err != nil {
+		return CircuitClosed
+	}
+	return c.breaker.State(u.Host)
+}
@@ -0,0 +1,100 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	var status atomic.Int32
+	var calls atomic.Int32
+	status.Store(http.StatusInternalServerError)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(int(status.Load()))
+		w.Write([]byte(`{"error":{"code":"1234","message":"failed"}}`))
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	var clockNow atomic.Int64
+	clockNow.Store(now.UnixNano())
+	var states []CircuitState
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(1),
+		WithCircuitBreaker(CircuitBreakerConfig{
+			ConsecutiveFailures: 3,
+			CoolDown:            time.Minute,
+			Now:                 func() time.Time { return time.Unix(0, clockNow.Load()) },
+			OnStateChange: func(host string, from, to CircuitState) {
+				states = append(states, to)
+			},
+		}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := context.Background()
+	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("Hi")})
+
+	// Client errors never open the circuit
+	status.Store(http.StatusBadRequest)
+	for i := 0; i < 5; i++ {
+		_, err := client.Chat.Create(ctx, req)
+		require.Error(t, err)
+		assert.False(t, errors.IsCircuitOpenError(err))
+	}
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+
+	// Server errors do
+	status.Store(http.StatusInternalServerError)
+	for i := 0; i < 3; i++ {
+		_, err := client.Chat.Create(ctx, req)
+		require.Error(t, err)
+		assert.True(t, errors.IsServerError(err))
+	}
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	// Requests and streams fail fast while the circuit is open
+	sent := calls.Load()
+	_, err = client.Chat.Create(ctx, req)
+	var circuitErr *errors.CircuitOpenError
+	require.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, now.Add(time.Minute), circuitErr.RetryAt)
+	_, err = client.Chat.CreateStream(ctx, req)
+	assert.True(t, errors.IsCircuitOpenError(err))
+	assert.Equal(t, sent, calls.Load(), "no request is sent while the circuit is open")
+
+	// After the cool-down a successful probe closes the circuit
+	status.Store(http.StatusOK)
+	clockNow.Store(now.Add(time.Minute).UnixNano())
+	assert.Equal(t, CircuitHalfOpen, client.CircuitState())
+	_, err = client.Chat.Create(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}, states)
+}
+
+func TestClient_CircuitStateWithoutBreaker(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+}
@@ -2,19 +2,30 @@
 package zai
 
 import (
-	"os"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/sofianhadi1983/zai-sdk-go/internal/auth"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/cache"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
-	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/ratelimit"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
 )
 
 // Client is the main SDK client for Z.ai API.
 type Client struct {
-	baseClient *client.BaseClient
-	config     *ClientConfig
+	baseClient    *client.BaseClient
+	config        *ClientConfig
+	rateLimiter   *ratelimit.Limiter
+	breaker       *transport.CircuitBreaker
+	responseCache *cache.ResponseCache
 
 	// Chat provides access to the Chat Completions API.
 	Chat *ChatService
@@ -40,6 +51,9 @@ type Client struct {
 	// Batch provides access to the Batch API.
 	Batch *BatchService
 
+	// FineTuning provides access to the Fine-tuning API.
+	FineTuning *FineTuningService
+
 	// WebSearch provides access to the Web Search API.
 	WebSearch *WebSearchService
 
@@ -63,6 +77,12 @@ type Client struct {
 
 	// WebReader provides access to the Web Reader API.
 	WebReader *WebReaderService
+
+	// Models provides access to the Models API.
+	Models *ModelsService
+
+	// Knowledge provides access to the Knowledge Base API.
+	Knowledge *KnowledgeService
 }
 
 // ClientConfig holds configuration for the SDK client.
@@ -70,14 +90,44 @@ type ClientConfig struct {
 	// APIKey is the API key for authentication (format: "key.secret").
 	APIKey string
 
+	// APIKeyProvider returns the API key per request instead of APIKey.
+	APIKeyProvider APIKeyProvider
+
+	// APIKeyCacheTTL is how long keys from APIKeyProvider are reused.
+	// If zero, uses DefaultAPIKeyCacheTTL. Negative means no reuse.
+	APIKeyCacheTTL time.Duration
+
+	// Token is a bearer token sent instead of a token signed with the API
+	// key.
+	Token string
+
+	// TokenSource returns the bearer token sent instead of a token signed
+	// with the API key, and refreshes it before it expires.
+	TokenSource TokenSource
+
 	// BaseURL is the base URL for API requests.
 	// If empty, uses the default Z.ai API URL.
 	BaseURL string
 
-	// Timeout is the request timeout.
-	// If zero, uses the default timeout (120 seconds).
+	// Timeout is the timeout of non-streaming requests, including reading
+	// the response. If zero, uses DefaultRequestTimeout. Negative means no
+	// timeout.
 	Timeout time.Duration
 
+	// ConnectTimeout bounds establishing a connection and, separately, its
+	// TLS handshake. If zero, uses DefaultDialTimeout and a 10 second TLS
+	// handshake timeout. Negative means no timeout.
+	ConnectTimeout time.Duration
+
+	// StreamReadTimeout bounds the wait of a stream for its response
+	// headers and for every read of its body. If zero, uses
+	// DefaultStreamReadTimeout. Negative means no timeout.
+	StreamReadTimeout time.Duration
+
+	// StreamIdleTimeout aborts streams that receive no event for this
+	// long. Zero means no idle timeout.
+	StreamIdleTimeout time.Duration
+
 	// MaxRetries is the maximum number of retry attempts.
 	// If zero, uses the default (3 retries).
 	MaxRetries int
@@ -89,6 +139,153 @@ type ClientConfig struct {
 	// Logger is a custom logger.
 	// If nil, uses the default logger.
 	Logger *logger.Logger
+
+	// Hooks are lifecycle hooks called for every request.
+	Hooks []Hooks
+
+	// LogBodies passes request bodies and stream payloads to hooks and logs.
+	LogBodies bool
+
+	// RequestsPerMinute limits requests client-side. Zero means no limit.
+	RequestsPerMinute int
+
+	// TokensPerMinute limits estimated tokens client-side. Zero means no limit.
+	TokensPerMinute int
+
+	// AccurateTokenCounting uses the tokenizer endpoint to count chat
+	// prompt tokens for rate limiting instead of a local estimate.
+	AccurateTokenCounting bool
+
+	// Base64Embeddings makes the embeddings convenience methods request
+	// base64-encoded embeddings from models that support them.
+	Base64Embeddings bool
+
+	// DefaultChatModel is the model of chat completion requests without one.
+	DefaultChatModel string
+
+	// DefaultEmbeddingModel is the model of embedding requests without one.
+	DefaultEmbeddingModel string
+
+	// DefaultImageModel is the model of image requests without one.
+	DefaultImageModel string
+
+	// DefaultVideoModel is the model of video generation requests without one.
+	DefaultVideoModel string
+
+	// Cache stores responses of deterministic endpoints. If nil, nothing is cached.
+	Cache Cache
+
+	// CacheTTL is how long cached responses are kept.
+	// If zero, uses DefaultCacheTTL. Negative means no expiry.
+	CacheTTL time.Duration
+
+	// RetryPolicy overrides the default retry behavior for all requests.
+	RetryPolicy RetryPolicy
+
+	// RetryBudgetRatio is the maximum ratio of retries to requests within
+	// RetryBudgetWindow. Zero disables the retry budget.
+	RetryBudgetRatio float64
+
+	// RetryBudgetWindow is the sliding window of the retry budget.
+	// If zero, uses DefaultRetryBudgetWindow.
+	RetryBudgetWindow time.Duration
+
+	// CircuitBreaker configures a circuit breaker that fails requests fast
+	// while the API is failing. If nil, there is no circuit breaker.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// Organization scopes every request to an organization.
+	Organization string
+
+	// Project scopes every request to a project.
+	Project string
+
+	// HTTPClient sends all requests instead of an internal client.
+	// If nil, the SDK creates its own client.
+	HTTPClient *http.Client
+
+	// Transport sends all requests of the internal client.
+	// If nil, the SDK creates its own transport.
+	Transport http.RoundTripper
+
+	// ProxyURL is the proxy used by the SDK's own transport.
+	ProxyURL string
+
+	// ConnectionPool overrides the connection pool settings of the SDK's
+	// own transport. Zero fields keep the defaults.
+	ConnectionPool PoolConfig
+
+	// Tracer starts spans for API calls. If nil, calls are not traced.
+	Tracer Tracer
+
+	// MaxRequestBytes is the largest request body that is sent.
+	// If zero, uses DefaultMaxRequestBytes. Negative means no limit.
+	MaxRequestBytes int64
+
+	// MaxResponseBytes is the largest response body that is read.
+	// If zero, uses DefaultMaxResponseBytes. Negative means no limit.
+	MaxResponseBytes int64
+
+	// MaxStreamBytes is the largest total size of a stream.
+	// Zero or less means no limit.
+	MaxStreamBytes int64
+
+	// RequestCompressionMinBytes is the smallest JSON request body that is
+	// gzipped before it is sent. Zero sends bodies uncompressed. See
+	// WithRequestCompression.
+	RequestCompressionMinBytes int
+
+	// AutoIdempotencyKeys sends a generated Idempotency-Key header with
+	// calls that create server-side resources. See WithIdempotencyKeys.
+	AutoIdempotencyKeys bool
+
+	// UploadBufferSize is the largest uploaded file content that is
+	// buffered so that the upload can be retried. If zero, uses
+	// DefaultUploadBufferSize. Negative buffers nothing.
+	UploadBufferSize int64
+
+	// Platform selects the endpoint paths of the services.
+	// If PlatformAuto, it is detected from BaseURL.
+	Platform Platform
+
+	// EndpointPaths overrides the paths of individual endpoints, by
+	// endpoint name. See WithEndpointPath.
+	EndpointPaths map[string]string
+
+	// RequestSanitizer inspects and may redact or block requests that send
+	// user content. If nil, requests are sent as is.
+	RequestSanitizer RequestSanitizer
+
+	// transport is the connection pool shared with the parent client of a
+	// client created by Client.WithOptions.
+	transport http.RoundTripper
+}
+
+// redactedClientConfig is a ClientConfig printed without its methods.
+type redactedClientConfig ClientConfig
+
+// String returns the configuration with the API key and the proxy
+// password masked, so that printing it does not leak them.
+func (c ClientConfig) String() string {
+	return fmt.Sprintf("%+v", c.redacted())
+}
+
+// GoString is like String, in Go syntax.
+func (c ClientConfig) GoString() string {
+	return "zai.ClientConfig" + strings.TrimPrefix(fmt.Sprintf("%#v", c.redacted()), "zai.redactedClientConfig")
+}
+
+// redacted returns a copy of c with its credentials masked.
+func (c ClientConfig) redacted() redactedClientConfig {
+	r := redactedClientConfig(c)
+	if r.APIKey != "" {
+		r.APIKey = hooks.RedactedValue
+	}
+	if r.Token != "" {
+		r.Token = hooks.RedactedValue
+	}
+	r.ProxyURL = hooks.RedactURL(r.ProxyURL)
+	return r
 }
 
 // ClientOption is a functional option for configuring the Client.
@@ -97,7 +294,8 @@ type ClientOption func(*ClientConfig)
 // WithAPIKey sets the API key for the client.
 //
 // The API key should be in the format "key.secret" as provided
-// by the Z.ai platform.
+// by the Z.ai platform; NewClient returns a ConfigError otherwise.
+// To rotate keys without restarting, use WithAPIKeyProvider.
 //
 // Example:
 //
@@ -107,6 +305,7 @@ type ClientOption func(*ClientConfig)
 func WithAPIKey(apiKey string) ClientOption {
 	return func(c *ClientConfig) {
 		c.APIKey = apiKey
+		c.APIKeyProvider = nil
 	}
 }
 
@@ -127,10 +326,9 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithTimeout sets the request timeout.
-//
-// This controls how long the client will wait for a response
-// before timing out. Default is 120 seconds.
+// WithTimeout sets the request timeout. It is the same as
+// WithRequestTimeout: streams are bounded by WithStreamReadTimeout instead,
+// and connections by WithConnectTimeout.
 //
 // Example:
 //
@@ -139,8 +337,25 @@ func WithBaseURL(baseURL string) ClientOption {
 //	    zai.WithTimeout(60 * time.Second),
 //	)
 func WithTimeout(timeout time.Duration) ClientOption {
+	return WithRequestTimeout(timeout)
+}
+
+// WithStreamIdleTimeout sets the default idle timeout of streams. A stream
+// that receives no event for this long, for example because a proxy
+// dropped the connection without closing it, fails with an
+// *errors.StreamIdleTimeoutError instead of blocking forever. The timer
+// restarts with every event. Individual chat streams can override it with
+// chat.WithStreamIdleTimeout.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithStreamIdleTimeout(30 * time.Second),
+//	)
+func WithStreamIdleTimeout(timeout time.Duration) ClientOption {
 	return func(c *ClientConfig) {
-		c.Timeout = timeout
+		c.StreamIdleTimeout = timeout
 	}
 }
 
@@ -179,21 +394,55 @@ func WithDisableTokenCache() ClientOption {
 	}
 }
 
-// WithLogger sets a custom logger.
+// WithLogger sets a custom structured logger.
 //
-// Use this option to provide your own logger for debugging
-// and monitoring API requests.
+// Request lifecycle events are written to the logger: requests, responses
+// and stream events at debug level, retries at info level, API error
+// responses at warn level and transport failures at error level.
+// Credentials are always redacted.
 //
 // Example:
 //
-//	customLogger := logger.New(logger.LevelDebug)
+//	handler := slog.NewJSONHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
 //	client, err := zai.NewClient(
 //	    zai.WithAPIKey("your-key"),
-//	    zai.WithLogger(customLogger),
+//	    zai.WithLogger(slog.New(handler)),
+//	)
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *ClientConfig) {
+		if l == nil {
+			c.Logger = nil
+			return
+		}
+		c.Logger = &logger.Logger{Logger: l}
+	}
+}
+
+// WithOrganization scopes every request to the given organization.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithOrganization("org-123"),
 //	)
-func WithLogger(logger *logger.Logger) ClientOption {
+func WithOrganization(orgID string) ClientOption {
 	return func(c *ClientConfig) {
-		c.Logger = logger
+		c.Organization = orgID
+	}
+}
+
+// WithProject scopes every request to the given project.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithProject("proj-123"),
+//	)
+func WithProject(projectID string) ClientOption {
+	return func(c *ClientConfig) {
+		c.Project = projectID
 	}
 }
 
@@ -259,7 +508,8 @@ func NewClient(opts ...ClientOption) (*Client, error) {
 //	resp, err := client.Chat.Create(context.Background(), req)
 func NewZhipuClient(opts ...ClientOption) (*Client, error) {
 	config := &ClientConfig{
-		BaseURL: constants.ZhipuBaseURL,
+		BaseURL:  constants.ZhipuBaseURL,
+		Platform: PlatformZhipu,
 	}
 
 	for _, opt := range opts {
@@ -270,17 +520,29 @@ func NewZhipuClient(opts ...ClientOption) (*Client, error) {
 }
 
 // NewClientFromEnv creates a new client from environment variables.
-// Reads ZAI_API_KEY and optionally ZAI_BASE_URL from environment.
+// Options take precedence over the environment, which takes precedence
+// over the defaults. Unset and empty variables keep the defaults.
 //
 // Environment variables:
 //   - ZAI_API_KEY: Required. Your API key in format "key.secret"
-//   - ZAI_BASE_URL: Optional. Custom base URL for API requests
+//   - ZAI_BASE_URL: Custom base URL for API requests. Defaults to the URL
+//     of the platform.
+//   - ZAI_TIMEOUT: Request timeout, as a Go duration such as "90s" or a
+//     whole number of seconds. Must be positive.
+//   - ZAI_MAX_RETRIES: Maximum retry attempts. A non-negative integer;
+//     zero keeps the default.
+//   - ZAI_PLATFORM: "auto", "international" or "zhipu", in any case.
+//   - ZAI_PROXY: Proxy URL, such as "http://proxy.internal:3128".
+//   - ZAI_LOG_LEVEL: "debug", "info", "warn" or "error", in any case.
+//
+// A malformed value fails with an *errors.ConfigError whose Field is the
+// name of the variable.
 //
 // Example:
 //
 //	// Set environment variables first:
 //	// export ZAI_API_KEY="your-api-key.your-secret"
-//	// export ZAI_BASE_URL="https://custom.api.url"  # optional
+//	// export ZAI_TIMEOUT="60s"  # optional
 //
 //	client, err := zai.NewClientFromEnv()
 //	if err != nil {
@@ -295,35 +557,23 @@ func NewZhipuClient(opts ...ClientOption) (*Client, error) {
 //	    zai.WithMaxRetries(3),
 //	)
 func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
-	apiKey := os.Getenv("ZAI_API_KEY")
-	baseURL := os.Getenv("ZAI_BASE_URL")
-
-	config := &ClientConfig{
-		APIKey:  apiKey,
-		BaseURL: baseURL,
-	}
-
-	// Apply additional options
-	for _, opt := range opts {
-		opt(config)
-	}
-
-	// Set default base URL if not provided
-	if config.BaseURL == "" {
-		config.BaseURL = constants.ZaiBaseURL
+	envOpts, err := envOptions()
+	if err != nil {
+		return nil, err
 	}
-
-	return newClient(config)
+	return newClientWithDefaultURL(append(envCredentials(envOpts, opts), opts...))
 }
 
 // NewZhipuClientFromEnv creates a new Chinese client from environment variables.
-// Reads ZAI_API_KEY from environment and uses Zhipu base URL.
+// It reads the same variables as NewClientFromEnv, but defaults to the Zhipu
+// base URL and platform.
 //
 // This is a convenience function for Chinese users that automatically
 // uses the domestic API endpoint (https://open.bigmodel.cn/api/paas/v4/).
 //
 // Environment variables:
 //   - ZAI_API_KEY: Required. Your API key in format "key.secret"
+//   - The optional variables of NewClientFromEnv
 //
 // Example:
 //
@@ -336,15 +586,17 @@ func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
 //	}
 //	defer client.Close()
 func NewZhipuClientFromEnv(opts ...ClientOption) (*Client, error) {
-	apiKey := os.Getenv("ZAI_API_KEY")
+	envOpts, err := envOptions()
+	if err != nil {
+		return nil, err
+	}
 
 	config := &ClientConfig{
-		APIKey:  apiKey,
-		BaseURL: constants.ZhipuBaseURL,
+		BaseURL:  constants.ZhipuBaseURL,
+		Platform: PlatformZhipu,
 	}
 
-	// Apply additional options
-	for _, opt := range opts {
+	for _, opt := range append(envCredentials(envOpts, opts), opts...) {
 		opt(config)
 	}
 
@@ -354,18 +606,99 @@ func NewZhipuClientFromEnv(opts ...ClientOption) (*Client, error) {
 // newClient creates a new client from the given configuration.
 func newClient(config *ClientConfig) (*Client, error) {
 	// Validate configuration
-	if config.APIKey == "" {
-		return nil, errors.NewConfigError("APIKey", "API key is required")
+	if err := validateAPIKey(config); err != nil {
+		return nil, err
+	}
+
+	proxy, err := parseProxyURL(config.ProxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	paths, err := endpointPaths(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// Do not retry on top of a transport that retries itself
+	retryPolicy := config.RetryPolicy
+	if retryPolicy.MaxAttempts == 0 && handlesRetries(config) {
+		retryPolicy.MaxAttempts = 1
+	}
+
+	rt := config.Transport
+	if rt == nil {
+		rt = config.transport
 	}
 
 	// Create internal base client config
 	baseConfig := &client.Config{
 		APIKey:            config.APIKey,
+		APIKeyProvider:    auth.KeyProvider(config.APIKeyProvider),
+		APIKeyCacheTTL:    apiKeyCacheTTL(config),
+		TokenSource:       tokenSource(config),
 		BaseURL:           config.BaseURL,
 		Timeout:           config.Timeout,
+		ConnectTimeout:    config.ConnectTimeout,
+		StreamReadTimeout: config.StreamReadTimeout,
+		StreamIdleTimeout: config.StreamIdleTimeout,
 		MaxRetries:        config.MaxRetries,
 		DisableTokenCache: config.DisableTokenCache,
 		Logger:            config.Logger,
+		Hooks:             config.Hooks,
+		LogBodies:         config.LogBodies,
+		RetryPolicy:       retryPolicy,
+		Organization:      config.Organization,
+		Project:           config.Project,
+		HTTPClient:        config.HTTPClient,
+		Transport:         rt,
+		ConnectionPool:    config.ConnectionPool,
+		Proxy:             proxy,
+		Tracer:            config.Tracer,
+		MaxRequestBytes:   payloadLimit(config.MaxRequestBytes, DefaultMaxRequestBytes),
+		MaxResponseBytes:  payloadLimit(config.MaxResponseBytes, DefaultMaxResponseBytes),
+		MaxStreamBytes:    config.MaxStreamBytes,
+		UploadBufferSize:  payloadLimit(config.UploadBufferSize, DefaultUploadBufferSize),
+		Platform:          resolvePlatform(config),
+		EndpointPaths:     paths,
+		RequestSanitizer:  config.RequestSanitizer,
+
+		RequestCompressionMinBytes: int64(config.RequestCompressionMinBytes),
+		AutoIdempotencyKeys:        config.AutoIdempotencyKeys,
+	}
+
+	// Create the retry budget
+	if config.RetryBudgetRatio > 0 {
+		window := config.RetryBudgetWindow
+		if window <= 0 {
+			window = DefaultRetryBudgetWindow
+		}
+		baseConfig.RetryBudget = transport.NewRetryBudget(config.RetryBudgetRatio, window, transport.DefaultMinRetriesPerWindow)
+	}
+
+	// Create the circuit breaker
+	var breaker *transport.CircuitBreaker
+	if config.CircuitBreaker != nil {
+		breaker = transport.NewCircuitBreaker(*config.CircuitBreaker)
+		baseConfig.CircuitBreaker = breaker
+	}
+
+	// Create the shared client-side rate limiter
+	var limiter *ratelimit.Limiter
+	if config.RequestsPerMinute > 0 || config.TokensPerMinute > 0 {
+		limiter = ratelimit.New(config.RequestsPerMinute, config.TokensPerMinute)
+		baseConfig.RateLimiter = limiter
+	}
+
+	// Create the response cache
+	var responseCache *cache.ResponseCache
+	if config.Cache != nil {
+		ttl := config.CacheTTL
+		if ttl == 0 {
+			ttl = DefaultCacheTTL
+		}
+		responseCache = cache.NewResponseCache(config.Cache, ttl)
+		baseConfig.Cache = responseCache
 	}
 
 	// Create base client
@@ -375,19 +708,23 @@ func newClient(config *ClientConfig) (*Client, error) {
 	}
 
 	c := &Client{
-		baseClient: baseClient,
-		config:     config,
+		baseClient:    baseClient,
+		config:        config,
+		rateLimiter:   limiter,
+		breaker:       breaker,
+		responseCache: responseCache,
 	}
 
 	// Initialize services
-	c.Chat = newChatService(baseClient)
-	c.Embeddings = newEmbeddingsService(baseClient)
-	c.Images = newImagesService(baseClient)
+	c.Chat = newChatService(baseClient, config.DefaultChatModel)
+	c.Embeddings = newEmbeddingsService(baseClient, config.Base64Embeddings, config.DefaultEmbeddingModel)
+	c.Images = newImagesService(baseClient, config.DefaultImageModel)
 	c.Files = newFilesService(baseClient)
-	c.Videos = newVideosService(baseClient)
+	c.Videos = newVideosService(baseClient, config.DefaultVideoModel)
 	c.Audio = newAudioService(baseClient)
 	c.Assistant = newAssistantService(baseClient)
 	c.Batch = newBatchService(baseClient)
+	c.FineTuning = newFineTuningService(baseClient)
 	c.WebSearch = newWebSearchService(baseClient)
 	c.Moderations = newModerationsService(baseClient)
 	c.Tools = newToolsService(baseClient)
@@ -396,10 +733,70 @@ func newClient(config *ClientConfig) (*Client, error) {
 	c.OCR = newOCRService(baseClient)
 	c.FileParser = newFileParserService(baseClient)
 	c.WebReader = newWebReaderService(baseClient)
+	c.Models = newModelsService(baseClient)
+	c.Knowledge = newKnowledgeService(baseClient)
+
+	if limiter != nil && config.AccurateTokenCounting {
+		limiter.SetEstimator(c.countChatTokens)
+	}
 
 	return c, nil
 }
 
+// WithOptions returns a client with the options applied on top of this
+// client's configuration, for example to use another API key, base URL or
+// project.
+//
+// The derived client shares this client's connection pool, so creating one
+// per request or tenant is cheap, unless the options set another HTTP
+// client, transport, proxy or connection pool settings. It has its own
+// rate limiter, retry budget and circuit breaker. Closing the derived
+// client does not affect this client, and closing this client does not
+// close the derived client.
+//
+// Credentials given in opts, with WithAPIKey, WithAPIKeyProvider, WithToken
+// or WithTokenSource, replace those of this client instead of being
+// combined with them.
+//
+// Example:
+//
+//	tenant, err := client.WithOptions(
+//	    zai.WithAPIKey(tenantKey),
+//	    zai.WithProject(tenantProject),
+//	)
+//	if err != nil {
+//	    return err
+//	}
+//	defer tenant.Close()
+//
+//	resp, err := tenant.Chat.Create(ctx, req)
+func (c *Client) WithOptions(opts ...ClientOption) (*Client, error) {
+	if err := c.baseClient.Err(); err != nil {
+		return nil, err
+	}
+
+	config := *c.config
+	config.Hooks = append([]Hooks(nil), c.config.Hooks...)
+	config.EndpointPaths = maps.Clone(c.config.EndpointPaths)
+	if setsCredentials(opts) {
+		clearCredentials(&config)
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	// Share the connection pool unless the options configure another one
+	if config.HTTPClient == c.config.HTTPClient &&
+		config.Transport == c.config.Transport &&
+		config.ProxyURL == c.config.ProxyURL &&
+		config.ConnectionPool == c.config.ConnectionPool {
+		config.transport = c.baseClient.Transport()
+	}
+
+	return newClient(&config)
+}
+
 // GetConfig returns the client configuration.
 //
 // This method allows you to inspect the current client configuration
@@ -434,6 +831,10 @@ func (c *Client) GetLogger() *logger.Logger {
 // to ensure proper cleanup of resources, especially HTTP connections.
 // It's recommended to use defer to ensure cleanup happens.
 //
+// Close cancels requests and streams that are in flight, which then fail
+// with an *errors.ClientClosedError, as do all calls made after Close.
+// It is safe to call Close more than once.
+//
 // Example:
 //
 //	client, err := zai.NewClient(zai.WithAPIKey("your-key"))
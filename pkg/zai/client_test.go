@@ -1,14 +1,24 @@
 package zai
 
 import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestNewClient(t *testing.T) {
@@ -54,7 +64,7 @@ func TestNewClient(t *testing.T) {
 			WithTimeout(customTimeout),
 			WithMaxRetries(customRetries),
 			WithDisableTokenCache(),
-			WithLogger(customLogger),
+			WithLogger(customLogger.Logger),
 		)
 
 		require.NoError(t, err)
@@ -66,7 +76,7 @@ func TestNewClient(t *testing.T) {
 		assert.Equal(t, customTimeout, client.config.Timeout)
 		assert.Equal(t, customRetries, client.config.MaxRetries)
 		assert.True(t, client.config.DisableTokenCache)
-		assert.Equal(t, customLogger, client.config.Logger)
+		assert.Equal(t, customLogger.Logger, client.config.Logger.Logger)
 	})
 
 	t.Run("with partial options", func(t *testing.T) {
@@ -346,6 +356,264 @@ func TestClient_Close(t *testing.T) {
 	client.Close()
 }
 
+func TestClient_Close_CancelsInFlight(t *testing.T) {
+	t.Parallel()
+
+	// The server never finishes a response
+	started := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Stream bool `json:"stream"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if body.Stream {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n"))
+			w.(http.Flusher).Flush()
+		}
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	newClient := func(t *testing.T) *Client {
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		return client
+	}
+	req := func() *chat.ChatCompletionRequest {
+		return chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		client := newClient(t)
+
+		stream, err := client.Chat.CreateStream(context.Background(), req())
+		require.NoError(t, err)
+		defer stream.Close()
+
+		require.True(t, stream.Next())
+		<-started
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			client.Close()
+		}()
+
+		start := time.Now()
+		for stream.Next() {
+		}
+		assert.Less(t, time.Since(start), 5*time.Second, "stream loop did not exit promptly")
+		assert.True(t, errors.IsClientClosedError(stream.Err()), "got %v", stream.Err())
+	})
+
+	t.Run("request", func(t *testing.T) {
+		client := newClient(t)
+
+		go func() {
+			<-started
+			client.Close()
+		}()
+
+		_, err := client.Chat.Create(context.Background(), req())
+		assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestClient_Close_FailsFast(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithCache(NewLRUCache(10)),
+	)
+	require.NoError(t, err)
+	client.Close()
+
+	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")})
+
+	_, err = client.Chat.Create(context.Background(), req)
+	assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+
+	_, err = client.Chat.CreateStream(context.Background(), req)
+	assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+
+	_, err = client.Models.List(context.Background())
+	assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+
+	_, err = client.Tools.CountTokens(context.Background(), tools.NewTokenizerRequest("glm-4.7", req.Messages), ModeExact)
+	assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+
+	assert.False(t, called, "closed client should not send requests")
+}
+
+func TestClient_Close_StopsPolling(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"task_id":"t1","task_status":"processing"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		client.Close()
+	}()
+
+	start := time.Now()
+	_, err = client.Videos.WaitForCompletion(context.Background(), "t1", time.Minute, time.Hour)
+	assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_WithOptions(t *testing.T) {
+	t.Parallel()
+
+	type seen struct {
+		auth, org, project string
+	}
+
+	newServer := func(t *testing.T) (*httptest.Server, *[]seen, *atomic.Int32) {
+		var mu sync.Mutex
+		var requests []seen
+		var conns atomic.Int32
+
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests = append(requests, seen{
+				auth:    r.Header.Get("Authorization"),
+				org:     r.Header.Get(constants.HeaderOrganization),
+				project: r.Header.Get(constants.HeaderProject),
+			})
+			mu.Unlock()
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"object":"list","data":[]}`))
+		}))
+		server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+			if state == http.StateNew {
+				conns.Add(1)
+			}
+		}
+		server.Start()
+		t.Cleanup(server.Close)
+		return server, &requests, &conns
+	}
+
+	newParent := func(t *testing.T, server *httptest.Server) *Client {
+		client, err := NewClient(
+			WithAPIKey("parent-key.parent-secret"),
+			WithBaseURL(server.URL),
+			WithDisableTokenCache(),
+			WithOrganization("org-1"),
+			WithProject("proj-1"),
+		)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		return client
+	}
+
+	t.Run("headers per client", func(t *testing.T) {
+		t.Parallel()
+
+		server, requests, _ := newServer(t)
+		parent := newParent(t, server)
+
+		derived, err := parent.WithOptions(
+			WithAPIKey("tenant-key.tenant-secret"),
+			WithProject("proj-2"),
+		)
+		require.NoError(t, err)
+		defer derived.Close()
+
+		_, err = parent.Models.List(context.Background())
+		require.NoError(t, err)
+		_, err = derived.Models.List(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []seen{
+			{auth: "Bearer parent-key.parent-secret", org: "org-1", project: "proj-1"},
+			{auth: "Bearer tenant-key.tenant-secret", org: "org-1", project: "proj-2"},
+		}, *requests)
+
+		assert.Equal(t, "proj-1", parent.GetConfig().Project)
+		assert.Equal(t, "proj-2", derived.GetConfig().Project)
+	})
+
+	t.Run("shares transport", func(t *testing.T) {
+		t.Parallel()
+
+		server, _, conns := newServer(t)
+		parent := newParent(t, server)
+
+		derived, err := parent.WithOptions(WithAPIKey("tenant-key.tenant-secret"))
+		require.NoError(t, err)
+		defer derived.Close()
+
+		assert.Same(t, parent.baseClient.Transport(), derived.baseClient.Transport())
+
+		for i := 0; i < 3; i++ {
+			_, err = parent.Models.List(context.Background())
+			require.NoError(t, err)
+			_, err = derived.Models.List(context.Background())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), conns.Load(), "requests should reuse one pooled connection")
+	})
+
+	t.Run("close derived", func(t *testing.T) {
+		t.Parallel()
+
+		server, _, conns := newServer(t)
+		parent := newParent(t, server)
+
+		derived, err := parent.WithOptions(WithProject("proj-2"))
+		require.NoError(t, err)
+
+		_, err = derived.Models.List(context.Background())
+		require.NoError(t, err)
+		derived.Close()
+
+		_, err = derived.Models.List(context.Background())
+		assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+
+		_, err = parent.Models.List(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), conns.Load(), "closing the derived client should keep pooled connections")
+	})
+
+	t.Run("closed parent", func(t *testing.T) {
+		t.Parallel()
+
+		server, _, _ := newServer(t)
+		parent := newParent(t, server)
+		parent.Close()
+
+		derived, err := parent.WithOptions(WithProject("proj-2"))
+		assert.Nil(t, derived)
+		assert.True(t, errors.IsClientClosedError(err), "got %v", err)
+	})
+}
+
 func TestClientOptions(t *testing.T) {
 	t.Parallel()
 
@@ -404,9 +672,9 @@ func TestClientOptions(t *testing.T) {
 
 		config := &ClientConfig{}
 		customLogger := logger.Default()
-		opt := WithLogger(customLogger)
+		opt := WithLogger(customLogger.Logger)
 		opt(config)
 
-		assert.Equal(t, customLogger, config.Logger)
+		assert.Equal(t, customLogger.Logger, config.Logger.Logger)
 	})
 }
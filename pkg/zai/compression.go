@@ -0,0 +1,26 @@
+package zai
+
+// WithRequestCompression gzips JSON request bodies of at least minBytes
+// bytes, such as large embedding batches and long chat histories, and
+// sends them with a Content-Encoding: gzip header. Each body is compressed
+// once and the compressed bytes are replayed on retries. Streaming
+// requests, multipart uploads and bodies that do not shrink are sent
+// uncompressed. WithMaxRequestBytes applies to the uncompressed size.
+//
+// If the server rejects a compressed body with a 415 status, or a 400
+// status whose message mentions the encoding, the request is sent once
+// more uncompressed and compression stays disabled for the client. A
+// warning is passed to hooks that implement WarningHooks and to the
+// logger. Zero or less disables compression, the default.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRequestCompression(64 << 10), // gzip bodies of 64 KiB or more
+//	)
+func WithRequestCompression(minBytes int) ClientOption {
+	return func(c *ClientConfig) {
+		c.RequestCompressionMinBytes = minBytes
+	}
+}
@@ -0,0 +1,53 @@
+package zai
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+func TestWithRequestCompression(t *testing.T) {
+	t.Parallel()
+
+	config := &ClientConfig{}
+	WithRequestCompression(4096)(config)
+	assert.Equal(t, 4096, config.RequestCompressionMinBytes)
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+
+		var req struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(zr).Decode(&req))
+		assert.Len(t, req.Input, 100)
+
+		writeJSON(w, map[string]any{"object": "list", "model": "embedding-3", "data": []any{}})
+	}, WithRequestCompression(1024))
+
+	input := make([]string, 100)
+	for i := range input {
+		input[i] = strings.Repeat("text ", 10)
+	}
+	_, err := client.Embeddings.Create(context.Background(), embeddings.NewBatchEmbeddingRequest("embedding-3", input))
+	require.NoError(t, err)
+
+	// Small chat requests are sent as is
+	chatClient := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Content-Encoding"))
+		writeJSON(w, map[string]any{"id": "chatcmpl-1", "choices": []any{}})
+	}, WithRequestCompression(1024))
+	_, err = chatClient.Chat.Create(context.Background(), chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hi")}))
+	require.NoError(t, err)
+}
@@ -0,0 +1,301 @@
+package zai
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/logger"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Environment variables read by NewClientFromEnv and ConfigFromEnv.
+const (
+	// EnvAPIKey is the API key, in the format "key.secret".
+	EnvAPIKey = "ZAI_API_KEY"
+
+	// EnvBaseURL is the base URL for API requests.
+	EnvBaseURL = "ZAI_BASE_URL"
+
+	// EnvTimeout is the request timeout. See Config.Timeout.
+	EnvTimeout = "ZAI_TIMEOUT"
+
+	// EnvMaxRetries is the maximum number of retry attempts. See
+	// Config.MaxRetries.
+	EnvMaxRetries = "ZAI_MAX_RETRIES"
+
+	// EnvPlatform is the API platform. See Config.Platform.
+	EnvPlatform = "ZAI_PLATFORM"
+
+	// EnvProxy is the proxy URL. See Config.Proxy.
+	EnvProxy = "ZAI_PROXY"
+
+	// EnvLogLevel is the minimum level of the SDK logs. See
+	// Config.LogLevel.
+	EnvLogLevel = "ZAI_LOG_LEVEL"
+)
+
+// Config is a plain client configuration for loading from files or the
+// environment. Its fields are strings and numbers with json and yaml tags,
+// so callers can unmarshal it from their own configuration format. Empty
+// fields keep the SDK defaults.
+//
+// Example:
+//
+//	var cfg zai.Config
+//	if err := yaml.Unmarshal(data, &cfg); err != nil {
+//	    return err
+//	}
+//
+//	client, err := zai.NewClientFromConfig(cfg)
+type Config struct {
+	// APIKey is the API key, in the format "key.secret".
+	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+
+	// BaseURL is the base URL for API requests. If empty, uses the
+	// default URL of the platform.
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+
+	// Timeout is the request timeout: a Go duration such as "90s" or
+	// "2m", or a whole number of seconds such as "90". It must be
+	// positive.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// MaxRetries is the maximum number of retry attempts. It must not be
+	// negative; zero keeps the default, as with WithMaxRetries.
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+
+	// Platform is "auto", "international" or "zhipu", in any case. See
+	// WithPlatform.
+	Platform string `json:"platform,omitempty" yaml:"platform,omitempty"`
+
+	// Proxy is the proxy URL, such as "http://proxy.internal:3128". See
+	// WithProxy.
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+
+	// LogLevel is "debug", "info", "warn" or "error", in any case. The
+	// SDK then logs at or above that level in text format to standard
+	// output. WithLogger replaces this logger.
+	LogLevel string `json:"log_level,omitempty" yaml:"log_level,omitempty"`
+}
+
+// redactedConfig is a Config printed without its methods.
+type redactedConfig Config
+
+// String returns the configuration with the API key and the proxy
+// password masked, so that printing it does not leak them.
+func (c Config) String() string {
+	return fmt.Sprintf("%+v", c.redacted())
+}
+
+// GoString is like String, in Go syntax.
+func (c Config) GoString() string {
+	return "zai.Config" + strings.TrimPrefix(fmt.Sprintf("%#v", c.redacted()), "zai.redactedConfig")
+}
+
+// redacted returns a copy of c with its credentials masked.
+func (c Config) redacted() redactedConfig {
+	r := redactedConfig(c)
+	if r.APIKey != "" {
+		r.APIKey = hooks.RedactedValue
+	}
+	r.Proxy = hooks.RedactURL(r.Proxy)
+	return r
+}
+
+// ConfigFromEnv returns the configuration set by the ZAI_* environment
+// variables. Unset and empty variables leave their fields empty. It fails
+// with an *errors.ConfigError only if ZAI_MAX_RETRIES is not an integer;
+// the other values are validated when the Config is used.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		APIKey:   os.Getenv(EnvAPIKey),
+		BaseURL:  os.Getenv(EnvBaseURL),
+		Timeout:  os.Getenv(EnvTimeout),
+		Platform: os.Getenv(EnvPlatform),
+		Proxy:    os.Getenv(EnvProxy),
+		LogLevel: os.Getenv(EnvLogLevel),
+	}
+
+	if value := strings.TrimSpace(os.Getenv(EnvMaxRetries)); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil {
+			return Config{}, errors.NewConfigError(EnvMaxRetries, fmt.Sprintf("invalid integer %q", value))
+		}
+		cfg.MaxRetries = retries
+	}
+	return cfg, nil
+}
+
+// Options validates the configuration and returns the client options that
+// apply it. Malformed values are reported as an *errors.ConfigError naming
+// the field.
+func (c Config) Options() ([]ClientOption, error) {
+	return c.options(configFields)
+}
+
+// NewClientFromConfig creates a new client from a plain configuration.
+// Options take precedence over the configuration, which takes precedence
+// over the defaults. Malformed values fail with an *errors.ConfigError.
+//
+// Example:
+//
+//	var cfg zai.Config
+//	if err := json.Unmarshal(data, &cfg); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	client, err := zai.NewClientFromConfig(cfg, zai.WithMaxRetries(5))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer client.Close()
+func NewClientFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	configOpts, err := cfg.Options()
+	if err != nil {
+		return nil, err
+	}
+	return newClientWithDefaultURL(append(configOpts, opts...))
+}
+
+// configFieldNames names the fields of a Config in errors.
+type configFieldNames struct {
+	timeout, maxRetries, platform, proxy, logLevel string
+}
+
+var (
+	configFields = configFieldNames{"Timeout", "MaxRetries", "Platform", "Proxy", "LogLevel"}
+	envFields    = configFieldNames{EnvTimeout, EnvMaxRetries, EnvPlatform, EnvProxy, EnvLogLevel}
+)
+
+// options converts the configuration into client options, naming fields
+// in errors as given by names.
+func (c Config) options(names configFieldNames) ([]ClientOption, error) {
+	var opts []ClientOption
+	if c.APIKey != "" {
+		opts = append(opts, WithAPIKey(c.APIKey))
+	}
+	if c.BaseURL != "" {
+		opts = append(opts, WithBaseURL(c.BaseURL))
+	}
+
+	if c.Timeout != "" {
+		timeout, err := parseTimeout(names.timeout, c.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTimeout(timeout))
+	}
+
+	if c.MaxRetries < 0 {
+		return nil, errors.NewConfigError(names.maxRetries, fmt.Sprintf("must not be negative, got %d", c.MaxRetries))
+	}
+	if c.MaxRetries > 0 {
+		opts = append(opts, WithMaxRetries(c.MaxRetries))
+	}
+
+	if c.Platform != "" {
+		platform, err := parsePlatform(names.platform, c.Platform)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithPlatform(platform))
+	}
+
+	if c.Proxy != "" {
+		if _, err := parseProxyURL(c.Proxy); err != nil {
+			return nil, errors.NewConfigError(names.proxy, fmt.Sprintf("invalid proxy URL %q: must be an absolute URL such as http://host:port", hooks.RedactURL(c.Proxy)))
+		}
+		opts = append(opts, WithProxy(c.Proxy))
+	}
+
+	if c.LogLevel != "" {
+		level, err := parseLogLevel(names.logLevel, c.LogLevel)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, func(config *ClientConfig) {
+			config.Logger = logger.New(&logger.Config{Level: level})
+		})
+	}
+
+	return opts, nil
+}
+
+// envOptions returns the client options set by the environment.
+func envOptions() ([]ClientOption, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.options(envFields)
+}
+
+// newClientWithDefaultURL creates a client from opts, using the default
+// base URL of the configured platform if none is set.
+func newClientWithDefaultURL(opts []ClientOption) (*Client, error) {
+	config := &ClientConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = constants.ZaiBaseURL
+		if config.Platform == PlatformZhipu {
+			config.BaseURL = constants.ZhipuBaseURL
+		}
+	}
+
+	return newClient(config)
+}
+
+// parseTimeout parses a positive timeout given as a Go duration or a whole
+// number of seconds.
+func parseTimeout(field, value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(value)
+		if atoiErr != nil {
+			return 0, errors.NewConfigError(field, fmt.Sprintf("invalid duration %q: use a Go duration such as \"90s\" or a number of seconds", value))
+		}
+		timeout = time.Duration(seconds) * time.Second
+	}
+
+	if timeout <= 0 {
+		return 0, errors.NewConfigError(field, fmt.Sprintf("must be positive, got %q", value))
+	}
+	return timeout, nil
+}
+
+// parsePlatform parses a platform name as returned by Platform.String.
+func parsePlatform(field, value string) (Platform, error) {
+	for _, platform := range []Platform{PlatformAuto, PlatformInternational, PlatformZhipu} {
+		if strings.EqualFold(strings.TrimSpace(value), platform.String()) {
+			return platform, nil
+		}
+	}
+	return PlatformAuto, errors.NewConfigError(field, fmt.Sprintf("unknown platform %q: must be auto, international or zhipu", value))
+}
+
+// parseLogLevel parses a log level name.
+func parseLogLevel(field, value string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return logger.LevelDebug, nil
+	case "info":
+		return logger.LevelInfo, nil
+	case "warn", "warning":
+		return logger.LevelWarn, nil
+	case "error":
+		return logger.LevelError, nil
+	default:
+		return 0, errors.NewConfigError(field, fmt.Sprintf("unknown log level %q: must be debug, info, warn or error", value))
+	}
+}
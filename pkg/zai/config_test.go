@@ -0,0 +1,204 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// clearEnv unsets every variable read by NewClientFromEnv for the test.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{EnvAPIKey, EnvBaseURL, EnvTimeout, EnvMaxRetries, EnvPlatform, EnvProxy, EnvLogLevel} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestNewClientFromEnv_AllVariables(t *testing.T) {
+	clearEnv(t)
+	t.Setenv(EnvAPIKey, "env-key.env-secret")
+	t.Setenv(EnvBaseURL, "https://proxy.example.com/api/paas/v4")
+	t.Setenv(EnvTimeout, "45s")
+	t.Setenv(EnvMaxRetries, "7")
+	t.Setenv(EnvPlatform, "Zhipu")
+	t.Setenv(EnvProxy, "http://proxy.internal:3128")
+	t.Setenv(EnvLogLevel, "DEBUG")
+
+	client, err := NewClientFromEnv()
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, "env-key.env-secret", client.config.APIKey)
+	assert.Equal(t, "https://proxy.example.com/api/paas/v4", client.config.BaseURL)
+	assert.Equal(t, 45*time.Second, client.config.Timeout)
+	assert.Equal(t, 7, client.config.MaxRetries)
+	assert.Equal(t, PlatformZhipu, client.Platform())
+	assert.Equal(t, "http://proxy.internal:3128", client.config.ProxyURL)
+	assert.True(t, client.GetLogger().Enabled(context.Background(), slog.LevelDebug))
+}
+
+func TestNewClientFromEnv_Precedence(t *testing.T) {
+	clearEnv(t)
+	t.Setenv(EnvAPIKey, "env-key.env-secret")
+	t.Setenv(EnvTimeout, "45")
+	t.Setenv(EnvMaxRetries, "7")
+
+	// Options override the environment
+	client, err := NewClientFromEnv(WithTimeout(time.Minute), WithAPIKey("opt-key.opt-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, "opt-key.opt-secret", client.config.APIKey)
+	assert.Equal(t, time.Minute, client.config.Timeout)
+	assert.Equal(t, 7, client.config.MaxRetries)
+
+	// The environment overrides the defaults; unset variables keep them
+	client, err = NewClientFromEnv()
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, 45*time.Second, client.config.Timeout)
+	assert.Equal(t, constants.ZaiBaseURL, client.config.BaseURL)
+	assert.Equal(t, PlatformInternational, client.Platform())
+	assert.Empty(t, client.config.ProxyURL)
+
+	// The platform selects the default base URL
+	t.Setenv(EnvPlatform, "zhipu")
+	client, err = NewClientFromEnv()
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, constants.ZhipuBaseURL, client.config.BaseURL)
+}
+
+func TestNewClientFromEnv_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     string
+		value   string
+		message string
+	}{
+		{"duration", EnvTimeout, "30 seconds", `invalid duration "30 seconds"`},
+		{"negative duration", EnvTimeout, "-5s", "must be positive"},
+		{"zero seconds", EnvTimeout, "0", "must be positive"},
+		{"retries", EnvMaxRetries, "three", `invalid integer "three"`},
+		{"negative retries", EnvMaxRetries, "-1", "must not be negative"},
+		{"platform", EnvPlatform, "mars", `unknown platform "mars": must be auto, international or zhipu`},
+		{"proxy", EnvProxy, "proxy.internal:3128", "invalid proxy URL"},
+		{"log level", EnvLogLevel, "verbose", `unknown log level "verbose"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t)
+			t.Setenv(EnvAPIKey, "env-key.env-secret")
+			t.Setenv(tt.env, tt.value)
+
+			client, err := NewClientFromEnv()
+			assert.Nil(t, client)
+
+			var configErr *errors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, tt.env, configErr.Field)
+			assert.Contains(t, configErr.Error(), tt.message)
+		})
+	}
+}
+
+func TestNewZhipuClientFromEnv_Variables(t *testing.T) {
+	clearEnv(t)
+	t.Setenv(EnvAPIKey, "env-key.env-secret")
+	t.Setenv(EnvTimeout, "2m")
+
+	client, err := NewZhipuClientFromEnv()
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, constants.ZhipuBaseURL, client.config.BaseURL)
+	assert.Equal(t, PlatformZhipu, client.Platform())
+	assert.Equal(t, 2*time.Minute, client.config.Timeout)
+
+	t.Setenv(EnvPlatform, "unknown")
+	_, err = NewZhipuClientFromEnv()
+	assert.True(t, errors.IsConfigError(err))
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	t.Parallel()
+
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"api_key": "cfg-key.cfg-secret",
+		"timeout": "90s",
+		"max_retries": 4,
+		"platform": "international",
+		"proxy": "http://proxy.internal:3128",
+		"log_level": "warn"
+	}`), &cfg))
+
+	client, err := NewClientFromConfig(cfg, WithMaxRetries(9))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, "cfg-key.cfg-secret", client.config.APIKey)
+	assert.Equal(t, constants.ZaiBaseURL, client.config.BaseURL)
+	assert.Equal(t, 90*time.Second, client.config.Timeout)
+	assert.Equal(t, 9, client.config.MaxRetries, "options override the config")
+	assert.Equal(t, PlatformInternational, client.Platform())
+	assert.Equal(t, "http://proxy.internal:3128", client.config.ProxyURL)
+	assert.False(t, client.GetLogger().Enabled(context.Background(), slog.LevelInfo))
+	assert.True(t, client.GetLogger().Enabled(context.Background(), slog.LevelWarn))
+}
+
+func TestNewClientFromConfig_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		cfg   Config
+		field string
+	}{
+		{"timeout", Config{Timeout: "soon"}, "Timeout"},
+		{"max retries", Config{MaxRetries: -2}, "MaxRetries"},
+		{"platform", Config{Platform: "bigmodel"}, "Platform"},
+		{"proxy", Config{Proxy: "://"}, "Proxy"},
+		{"log level", Config{LogLevel: "trace"}, "LogLevel"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tt.cfg.APIKey = "cfg-key.cfg-secret"
+			client, err := NewClientFromConfig(tt.cfg)
+			assert.Nil(t, client)
+
+			var configErr *errors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, tt.field, configErr.Field)
+		})
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	clearEnv(t)
+	t.Setenv(EnvAPIKey, "env-key.env-secret")
+	t.Setenv(EnvMaxRetries, " 2 ")
+	t.Setenv(EnvLogLevel, "info")
+
+	cfg, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Config{APIKey: "env-key.env-secret", MaxRetries: 2, LogLevel: "info"}, cfg)
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"api_key":"env-key.env-secret","max_retries":2,"log_level":"info"}`, string(data))
+}
@@ -0,0 +1,45 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// WithHeaders returns a copy of ctx that adds the given HTTP headers to
+// every request made with it, including streaming requests.
+//
+// Per-request headers override client defaults, including the
+// Authorization header. Headers set on a parent context are inherited
+// and can be overridden by a nested call. Requests made with the parent
+// context are unaffected.
+//
+// Example:
+//
+//	ctx := zai.WithHeaders(ctx, http.Header{
+//	    "X-Request-Source": []string{"billing-worker"},
+//	})
+//
+//	resp, err := client.Chat.Create(ctx, req)
+func WithHeaders(ctx context.Context, headers http.Header) context.Context {
+	return transport.ContextWithHeaders(ctx, headers)
+}
+
+// WithQueryParams returns a copy of ctx that adds the given query
+// parameters to every request made with it, including streaming requests.
+//
+// Per-request parameters replace any parameter of the same name set by
+// the service method.
+//
+// Example:
+//
+//	ctx := zai.WithQueryParams(ctx, url.Values{
+//	    "beta": []string{"true"},
+//	})
+//
+//	resp, err := client.Embeddings.Create(ctx, req)
+func WithQueryParams(ctx context.Context, params url.Values) context.Context {
+	return transport.ContextWithQueryParams(ctx, params)
+}
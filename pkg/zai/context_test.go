@@ -0,0 +1,92 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var headers []http.Header
+	var queries []url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		headers = append(headers, r.Header.Clone())
+		queries = append(queries, r.URL.Query())
+		mu.Unlock()
+
+		if body["stream"] == true {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"id\":\"1\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\ndata: [DONE]\n\n"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	parent := context.Background()
+	ctx := WithHeaders(parent, http.Header{
+		"X-Request-Source": []string{"worker"},
+		"Authorization":    []string{"Bearer sub-account-token"},
+	})
+	ctx = WithQueryParams(ctx, url.Values{"beta": []string{"true"}})
+
+	newReq := func() *chat.ChatCompletionRequest {
+		return &chat.ChatCompletionRequest{
+			Model:    "glm-4.7",
+			Messages: []chat.Message{chat.NewUserMessage("hi")},
+		}
+	}
+
+	// Request with per-request overrides
+	_, err = client.Chat.Create(ctx, newReq())
+	require.NoError(t, err)
+
+	// Streaming request with per-request overrides
+	stream, err := client.Chat.CreateStream(ctx, newReq())
+	require.NoError(t, err)
+	for stream.Next() {
+	}
+	require.NoError(t, stream.Err())
+	stream.Close()
+
+	// Request reusing the ancestor context must not carry the overrides
+	_, err = client.Chat.Create(parent, newReq())
+	require.NoError(t, err)
+
+	require.Len(t, headers, 3)
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, "worker", headers[i].Get("X-Request-Source"))
+		assert.Equal(t, "Bearer sub-account-token", headers[i].Get("Authorization"))
+		assert.Equal(t, "true", queries[i].Get("beta"))
+	}
+
+	assert.Empty(t, headers[2].Get("X-Request-Source"))
+	assert.NotEqual(t, "Bearer sub-account-token", headers[2].Get("Authorization"))
+	assert.Contains(t, headers[2].Get("Authorization"), "Bearer ")
+	assert.Empty(t, queries[2].Get("beta"))
+}
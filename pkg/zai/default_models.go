@@ -0,0 +1,71 @@
+package zai
+
+import (
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// WithDefaultChatModel sets the model of chat completion requests whose
+// Model is empty, so that the model is configured in one place. A model
+// set on the request always wins.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithDefaultChatModel(chat.ModelGLM47),
+//	)
+//
+//	// Sent with glm-4.7
+//	resp, err := client.Chat.Create(ctx, &chat.ChatCompletionRequest{
+//	    Messages: []chat.Message{chat.NewUserMessage("Hello!")},
+//	})
+func WithDefaultChatModel[M ~string](model M) ClientOption {
+	return func(c *ClientConfig) {
+		c.DefaultChatModel = string(model)
+	}
+}
+
+// WithDefaultEmbeddingModel sets the model of embedding requests whose
+// Model is empty, including those of Embeddings.Embed and
+// Embeddings.EmbedBatch. A model set on the request always wins.
+func WithDefaultEmbeddingModel[M ~string](model M) ClientOption {
+	return func(c *ClientConfig) {
+		c.DefaultEmbeddingModel = string(model)
+	}
+}
+
+// WithDefaultImageModel sets the model of image generation, edit and
+// variation requests whose Model is empty. The convenience methods, such
+// as Images.Generate, use it when given an empty model. A model set on the
+// request always wins.
+func WithDefaultImageModel[M ~string](model M) ClientOption {
+	return func(c *ClientConfig) {
+		c.DefaultImageModel = string(model)
+	}
+}
+
+// WithDefaultVideoModel sets the model of video generation requests whose
+// Model is empty. The convenience methods, such as Videos.GenerateText,
+// use it when given an empty model. A model set on the request always
+// wins.
+func WithDefaultVideoModel[M ~string](model M) ClientOption {
+	return func(c *ClientConfig) {
+		c.DefaultVideoModel = string(model)
+	}
+}
+
+// resolveModel returns model, or the default if model is empty. If both
+// are empty, it returns an *errors.ConfigError naming the config field
+// of the default.
+func resolveModel[M ~string](model, defaultModel M, field string) (M, error) {
+	if model != "" {
+		return model, nil
+	}
+	if defaultModel == "" {
+		return "", errors.NewConfigError(field,
+			fmt.Sprintf("request has no model and the client has no %s; set the model of the request or configure a default", field))
+	}
+	return defaultModel, nil
+}
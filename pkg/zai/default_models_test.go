@@ -0,0 +1,197 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/images"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// modelServer records the model of every JSON request it answers.
+type modelServer struct {
+	mu     sync.Mutex
+	models []string
+}
+
+func (s *modelServer) handle(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Model string `json:"model"`
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		body.Model = r.FormValue("model")
+	} else {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	s.mu.Lock()
+	s.models = append(s.models, body.Model)
+	s.mu.Unlock()
+
+	switch r.URL.Path {
+	case "/chat/completions":
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n"))
+			return
+		}
+		writeJSON(w, map[string]any{"choices": []any{map[string]any{"index": 0, "message": map[string]any{"role": "assistant", "content": "Hi"}}}})
+	case "/embeddings":
+		writeJSON(w, map[string]any{"object": "list", "data": []any{map[string]any{"object": "embedding", "index": 0, "embedding": []float64{0.5}}}})
+	case "/images/generations", "/images/edits", "/images/variations":
+		writeJSON(w, map[string]any{"data": []any{map[string]any{"url": "https://example.com/a.png"}}})
+	case "/videos/generations":
+		writeJSON(w, map[string]any{"id": "task-1", "task_status": "PROCESSING"})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// last returns the model of the last request.
+func (s *modelServer) last() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.models) == 0 {
+		return ""
+	}
+	return s.models[len(s.models)-1]
+}
+
+// count returns the number of requests answered.
+func (s *modelServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.models)
+}
+
+func newModelTestClient(t *testing.T, opts ...ClientOption) (*Client, *modelServer) {
+	recorder := &modelServer{}
+	server := httptest.NewServer(http.HandlerFunc(recorder.handle))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(append([]ClientOption{
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+	}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, recorder
+}
+
+func TestDefaultModels_Precedence(t *testing.T) {
+	t.Parallel()
+
+	client, server := newModelTestClient(t,
+		WithDefaultChatModel(chat.ModelGLM47),
+		WithDefaultEmbeddingModel(embeddings.ModelEmbedding3),
+		WithDefaultImageModel(images.ModelCogView4),
+		WithDefaultVideoModel(videos.ModelCogVideoX),
+	)
+	ctx := context.Background()
+	hello := []chat.Message{chat.NewUserMessage("Hello")}
+
+	// Chat: an empty model inherits the default without changing the request
+	req := &chat.ChatCompletionRequest{Messages: hello}
+	_, err := client.Chat.Create(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "glm-4.7", server.last())
+	assert.Empty(t, req.Model, "the request is not changed")
+
+	_, err = client.Chat.Create(ctx, chat.NewChatCompletionRequest("glm-4-plus", hello))
+	require.NoError(t, err)
+	assert.Equal(t, "glm-4-plus", server.last(), "an explicit model wins")
+
+	_, err = client.Chat.StreamContent(ctx, &chat.ChatCompletionRequest{Messages: hello})
+	require.NoError(t, err)
+	assert.Equal(t, "glm-4.7", server.last())
+
+	memory := chat.NewWindowMemory(10)
+	_, err = client.Chat.Create(ctx, (&chat.ChatCompletionRequest{Messages: hello}).WithMemory(memory))
+	require.NoError(t, err)
+	assert.Equal(t, "glm-4.7", server.last(), "requests with a memory inherit the default")
+
+	// Embeddings, including the variants without a model
+	_, err = client.Embeddings.Embed(ctx, "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-3", server.last())
+	_, err = client.Embeddings.EmbedBatch(ctx, []string{"Hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-3", server.last())
+	_, err = client.Embeddings.CreateSingle(ctx, "embedding-2", "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-2", server.last())
+
+	// Images
+	_, err = client.Images.Generate(ctx, "", "A fox")
+	require.NoError(t, err)
+	assert.Equal(t, "cogview-4", server.last())
+	_, err = client.Images.Create(ctx, images.NewImageGenerationRequest("cogview-3", "A fox"))
+	require.NoError(t, err)
+	assert.Equal(t, "cogview-3", server.last())
+	_, err = client.Images.Variations(ctx, images.NewImageVariationRequest("", images.ImageFromURL("https://example.com/fox.png")))
+	require.NoError(t, err)
+	assert.Equal(t, "cogview-4", server.last())
+
+	// Videos
+	_, err = client.Videos.GenerateText(ctx, "", "A fox")
+	require.NoError(t, err)
+	assert.Equal(t, "cogvideox", server.last())
+	_, err = client.Videos.GenerateText(ctx, videos.ModelCogVideoX2, "A fox")
+	require.NoError(t, err)
+	assert.Equal(t, "cogvideox-2", server.last())
+
+	// Derived clients keep the defaults
+	derived, err := client.WithOptions(WithProject("other"))
+	require.NoError(t, err)
+	defer derived.Close()
+	_, err = derived.Embeddings.Embed(ctx, "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-3", server.last())
+}
+
+func TestDefaultModels_Missing(t *testing.T) {
+	t.Parallel()
+
+	client, server := newModelTestClient(t)
+	ctx := context.Background()
+
+	requireConfigError := func(err error, field string) {
+		t.Helper()
+
+		var configErr *errors.ConfigError
+		require.ErrorAs(t, err, &configErr)
+		assert.Equal(t, field, configErr.Field)
+		assert.Contains(t, configErr.Error(), "no model")
+	}
+
+	_, err := client.Chat.Create(ctx, &chat.ChatCompletionRequest{Messages: []chat.Message{chat.NewUserMessage("Hello")}})
+	requireConfigError(err, "DefaultChatModel")
+	_, err = client.Chat.CreateStream(ctx, &chat.ChatCompletionRequest{Messages: []chat.Message{chat.NewUserMessage("Hello")}})
+	requireConfigError(err, "DefaultChatModel")
+	_, err = client.Embeddings.Embed(ctx, "Hello")
+	requireConfigError(err, "DefaultEmbeddingModel")
+	_, err = client.Images.Generate(ctx, "", "A fox")
+	requireConfigError(err, "DefaultImageModel")
+	_, err = client.Images.Edit(ctx, images.NewImageEditRequest("", "Add a hat", images.ImageFromURL("https://example.com/fox.png")))
+	requireConfigError(err, "DefaultImageModel")
+	_, err = client.Videos.GenerateFromImage(ctx, "", "https://example.com/fox.png")
+	requireConfigError(err, "DefaultVideoModel")
+
+	assert.Zero(t, server.count(), "no request is sent without a model")
+
+	// An explicit model needs no default
+	_, err = client.Embeddings.CreateSingle(ctx, "embedding-3", "Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-3", server.last())
+}
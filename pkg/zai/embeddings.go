@@ -5,21 +5,28 @@ import (
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 )
 
 // EmbeddingsService provides access to the Embeddings API.
 type EmbeddingsService struct {
-	client *client.BaseClient
+	client       *client.BaseClient
+	base64       bool
+	defaultModel string
 }
 
 // newEmbeddingsService creates a new embeddings service.
-func newEmbeddingsService(baseClient *client.BaseClient) *EmbeddingsService {
+func newEmbeddingsService(baseClient *client.BaseClient, base64 bool, defaultModel string) *EmbeddingsService {
 	return &EmbeddingsService{
-		client: baseClient,
+		client:       baseClient,
+		base64:       base64,
+		defaultModel: defaultModel,
 	}
 }
 
-// Create creates embeddings for the given input text(s).
+// Create creates embeddings for the given input text(s). A request
+// without a model is sent with the client's default embedding model, see
+// WithDefaultEmbeddingModel.
 //
 // Example with single text:
 //
@@ -47,8 +54,22 @@ func newEmbeddingsService(baseClient *client.BaseClient) *EmbeddingsService {
 //	    fmt.Printf("Embedding %d: %d dimensions\n", emb.Index, len(floats))
 //	}
 func (s *EmbeddingsService) Create(ctx context.Context, req *embeddings.EmbeddingRequest) (*embeddings.EmbeddingResponse, error) {
-	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/embeddings", req)
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultEmbeddingModel")
+	if err != nil {
+		return nil, err
+	}
+	if model != req.Model {
+		withModel := *req
+		withModel.Model = model
+		req = &withModel
+	}
+
+	if err := s.client.Sanitize(ServiceEmbeddings, req); err != nil {
+		return nil, err
+	}
+
+	// Make the API request (identical requests may be served from the cache)
+	apiResp, err := s.client.PostCached(ctx, s.client.Endpoints().Path(endpoints.Embeddings), req)
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +84,8 @@ func (s *EmbeddingsService) Create(ctx context.Context, req *embeddings.Embeddin
 }
 
 // CreateSingle is a convenience method for creating embeddings for a single text.
-// Returns the embedding vector directly.
+// Returns the embedding vector directly. With WithBase64Embeddings, the
+// embedding is transferred as base64 if the model supports it.
 //
 // Example:
 //
@@ -74,7 +96,7 @@ func (s *EmbeddingsService) Create(ctx context.Context, req *embeddings.Embeddin
 //
 //	fmt.Printf("Got embedding with %d dimensions\n", len(embedding))
 func (s *EmbeddingsService) CreateSingle(ctx context.Context, model, text string) ([]float64, error) {
-	req := embeddings.NewEmbeddingRequest(model, text)
+	req := s.withEncoding(embeddings.NewEmbeddingRequest(model, text))
 
 	resp, err := s.Create(ctx, req)
 	if err != nil {
@@ -89,8 +111,21 @@ func (s *EmbeddingsService) CreateSingle(ctx context.Context, model, text string
 	return emb.GetFloatEmbedding(), nil
 }
 
+// Embed is CreateSingle with the client's default embedding model, see
+// WithDefaultEmbeddingModel.
+//
+// Example:
+//
+//	client, err := zai.NewClient(zai.WithDefaultEmbeddingModel(embeddings.ModelEmbedding3))
+//	...
+//	embedding, err := client.Embeddings.Embed(ctx, "Hello world")
+func (s *EmbeddingsService) Embed(ctx context.Context, text string) ([]float64, error) {
+	return s.CreateSingle(ctx, "", text)
+}
+
 // CreateBatch is a convenience method for creating embeddings for multiple texts.
-// Returns a slice of embedding vectors.
+// Returns a slice of embedding vectors. With WithBase64Embeddings, the
+// embeddings are transferred as base64 if the model supports it.
 //
 // Example:
 //
@@ -104,7 +139,7 @@ func (s *EmbeddingsService) CreateSingle(ctx context.Context, model, text string
 //	    fmt.Printf("Text %d: %d dimensions\n", i, len(embedding))
 //	}
 func (s *EmbeddingsService) CreateBatch(ctx context.Context, model string, texts []string) ([][]float64, error) {
-	req := embeddings.NewBatchEmbeddingRequest(model, texts)
+	req := s.withEncoding(embeddings.NewBatchEmbeddingRequest(model, texts))
 
 	resp, err := s.Create(ctx, req)
 	if err != nil {
@@ -113,3 +148,36 @@ func (s *EmbeddingsService) CreateBatch(ctx context.Context, model string, texts
 
 	return resp.GetFloatEmbeddings(), nil
 }
+
+// EmbedBatch is CreateBatch with the client's default embedding model, see
+// WithDefaultEmbeddingModel.
+//
+// Example:
+//
+//	vectors, err := client.Embeddings.EmbedBatch(ctx, []string{"Hello", "World"})
+func (s *EmbeddingsService) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	return s.CreateBatch(ctx, "", texts)
+}
+
+// withEncoding requests base64 embeddings if enabled and supported by the
+// model.
+func (s *EmbeddingsService) withEncoding(req *embeddings.EmbeddingRequest) *embeddings.EmbeddingRequest {
+	model := req.Model
+	if model == "" {
+		model = s.defaultModel
+	}
+	if s.base64 && embeddings.SupportsBase64(model) {
+		req.SetEncodingFormat(embeddings.EncodingFormatBase64)
+	}
+	return req
+}
+
+// WithBase64Embeddings makes Embeddings.CreateSingle and
+// Embeddings.CreateBatch request base64-encoded embeddings from models that
+// support them, which cuts the response size to about a third. The returned
+// vectors are the same, up to float32 precision.
+func WithBase64Embeddings() ClientOption {
+	return func(c *ClientConfig) {
+		c.Base64Embeddings = true
+	}
+}
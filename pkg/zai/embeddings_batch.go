@@ -0,0 +1,204 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// DefaultBatchPollInterval is the default longest wait between status
+// checks of EmbeddingBatch.Wait.
+const DefaultBatchPollInterval = 30 * time.Second
+
+// embeddingInputPrefix prefixes the index of an input in the custom IDs of
+// the requests of CreateAsyncBatch.
+const embeddingInputPrefix = "input-"
+
+// CreateAsyncBatch embeds texts with the Batch API, which is cheaper than
+// Create for large volumes but completes within the completion window,
+// such as "24h", instead of immediately. It writes one request per text to
+// a JSONL input file, uploads it and creates the batch. With
+// WithBase64Embeddings, the embeddings are requested as base64 if the
+// model supports it. An empty model uses the client's default embedding
+// model.
+//
+// Example:
+//
+//	job, err := client.Embeddings.CreateAsyncBatch(ctx, "embedding-3", documents, "24h")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if _, err := job.Wait(ctx); err != nil {
+//	    // Handle error
+//	}
+//	vectors, err := job.Results(ctx)
+//	if err != nil {
+//	    // vectors[i] is nil for the inputs listed in the
+//	    // *embeddings.BatchResultsError
+//	}
+func (s *EmbeddingsService) CreateAsyncBatch(ctx context.Context, model string, texts []string, window string) (*EmbeddingBatch, error) {
+	model, err := resolveModel(model, s.defaultModel, "DefaultEmbeddingModel")
+	if err != nil {
+		return nil, err
+	}
+	if len(texts) == 0 {
+		return nil, errors.NewValidationError("texts", "must not be empty", len(texts))
+	}
+	if window == "" {
+		return nil, errors.NewValidationError("window", "is required", window)
+	}
+
+	var input bytes.Buffer
+	w := batch.NewInputWriter(&input)
+	for i, text := range texts {
+		req := s.withEncoding(embeddings.NewEmbeddingRequest(model, text))
+		if err := s.client.Sanitize(ServiceEmbeddings, req); err != nil {
+			return nil, err
+		}
+		if err := w.AddEmbedding(embeddingInputPrefix+strconv.Itoa(i), req); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+
+	file, err := newFilesService(s.client).Upload(ctx, files.NewFileUploadRequest(bytes.NewReader(input.Bytes()), "embeddings.jsonl", files.PurposeBatch))
+	if err != nil {
+		return nil, err
+	}
+
+	batches := newBatchService(s.client)
+	job, err := batches.Create(ctx, batch.NewBatchCreateRequest(window, batch.EndpointEmbeddings, file.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmbeddingBatch{
+		PollInterval: DefaultBatchPollInterval,
+		batches:      batches,
+		batch:        job,
+		inputs:       len(texts),
+	}, nil
+}
+
+// EmbeddingBatch is an embeddings batch created by
+// EmbeddingsService.CreateAsyncBatch. It is not safe for concurrent use.
+type EmbeddingBatch struct {
+	// PollInterval is the longest wait between status checks of Wait.
+	PollInterval time.Duration
+
+	batches *BatchService
+	batch   *batch.Batch
+	inputs  int
+}
+
+// ID returns the batch ID, which Batch.Retrieve and Batch.Cancel accept.
+func (b *EmbeddingBatch) ID() string {
+	return b.batch.ID
+}
+
+// Batch returns the batch as of its last status check.
+func (b *EmbeddingBatch) Batch() *batch.Batch {
+	return b.batch
+}
+
+// Wait polls the batch until it completes, fails, expires or is cancelled,
+// and returns it. Polling backs off exponentially up to PollInterval;
+// opts change the schedule.
+func (b *EmbeddingBatch) Wait(ctx context.Context, opts ...PollOption) (*batch.Batch, error) {
+	interval := b.PollInterval
+	if interval <= 0 {
+		interval = DefaultBatchPollInterval
+	}
+
+	poller := newPoller(b.batches.client, poll.Config{MaxInterval: interval}, opts)
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		job, err := b.batches.Retrieve(ctx, b.ID())
+		if err != nil {
+			return false, err
+		}
+		b.batch = job
+		return job.IsTerminal(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return b.batch, nil
+}
+
+// Results downloads the results of the completed batch and returns the
+// embeddings in the order of the texts. The result files are read line by
+// line, in any order. If some inputs got no embedding, their vectors are
+// nil and the returned error is an *embeddings.BatchResultsError listing
+// them. A batch that has not completed yields a *batch.NotCompletedError.
+func (b *EmbeddingBatch) Results(ctx context.Context) ([][]float64, error) {
+	iter, err := b.batches.ResultsIterator(ctx, b.ID())
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	vectors := make([][]float64, b.inputs)
+	failed := make(map[int]error)
+	for iter.Next() {
+		result := iter.Current()
+		i, ok := b.inputIndex(result.CustomID)
+		if !ok {
+			continue
+		}
+
+		resp, err := result.Embeddings()
+		if err != nil {
+			failed[i] = err
+			continue
+		}
+		var vector []float64
+		if emb := resp.GetFirstEmbedding(); emb != nil {
+			vector = emb.GetFloatEmbedding()
+		}
+		if vector == nil {
+			failed[i] = fmt.Errorf("batch: response of request %s has no embedding", result.CustomID)
+			continue
+		}
+		vectors[i] = vector
+		delete(failed, i)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, vector := range vectors {
+		if vector == nil && failed[i] == nil {
+			failed[i] = fmt.Errorf("batch: no result for request %s%d", embeddingInputPrefix, i)
+		}
+	}
+	if len(failed) > 0 {
+		return vectors, &embeddings.BatchResultsError{Errors: failed}
+	}
+	return vectors, nil
+}
+
+// inputIndex returns the index of the input of the request with the
+// given custom ID.
+func (b *EmbeddingBatch) inputIndex(customID string) (int, bool) {
+	digits, ok := strings.CutPrefix(customID, embeddingInputPrefix)
+	if !ok {
+		return 0, false
+	}
+	i, err := strconv.Atoi(digits)
+	if err != nil || i < 0 || i >= b.inputs {
+		return 0, false
+	}
+	return i, true
+}
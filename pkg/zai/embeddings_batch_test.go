@@ -0,0 +1,228 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	batchTypes "github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// embeddingBatchServer is a fake Files and Batch API that runs embeddings
+// batches. The embedding of "document N" is [N, 2N]; requests whose input
+// is in fail end up in the error file, and results are written in random
+// order.
+type embeddingBatchServer struct {
+	t *testing.T
+
+	// polls is the number of status checks before the batch completes
+	polls int
+
+	// fail lists the inputs whose requests fail
+	fail map[string]bool
+
+	mu      sync.Mutex
+	input   []byte
+	checks  int
+	created *batchTypes.BatchCreateRequest
+	output  bytes.Buffer
+	errors  bytes.Buffer
+}
+
+func (s *embeddingBatchServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /files", func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		require.NoError(s.t, err)
+		assert.Equal(s.t, "batch", r.FormValue("purpose"))
+		assert.Equal(s.t, "embeddings.jsonl", header.Filename)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.input, err = io.ReadAll(file)
+		require.NoError(s.t, err)
+		writeJSON(w, map[string]any{"id": "file_in", "object": "file", "purpose": "batch"})
+	})
+	mux.HandleFunc("POST /batches", func(w http.ResponseWriter, r *http.Request) {
+		var req batchTypes.BatchCreateRequest
+		require.NoError(s.t, json.NewDecoder(r.Body).Decode(&req))
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.created = &req
+		writeJSON(w, batchTypes.Batch{ID: "batch_emb", Object: "batch", Endpoint: req.Endpoint, Status: batchTypes.StatusValidating})
+	})
+	mux.HandleFunc("GET /batches/batch_emb", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.checks++
+		if s.checks <= s.polls {
+			writeJSON(w, batchTypes.Batch{ID: "batch_emb", Object: "batch", Status: batchTypes.StatusInProgress})
+			return
+		}
+		if s.output.Len() == 0 && s.errors.Len() == 0 {
+			s.run()
+		}
+		writeJSON(w, batchTypes.Batch{ID: "batch_emb", Object: "batch", Status: batchTypes.StatusCompleted, OutputFileID: "file_out", ErrorFileID: "file_err"})
+	})
+	mux.HandleFunc("GET /files/file_out/content", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Write(s.output.Bytes())
+	})
+	mux.HandleFunc("GET /files/file_err/content", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Write(s.errors.Bytes())
+	})
+	return mux
+}
+
+// run processes the input file into the output and error files.
+func (s *embeddingBatchServer) run() {
+	lines := strings.Split(strings.TrimSpace(string(s.input)), "\n")
+	rand.New(rand.NewSource(1)).Shuffle(len(lines), func(i, j int) {
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+
+	for _, line := range lines {
+		var req struct {
+			CustomID string                      `json:"custom_id"`
+			Method   string                      `json:"method"`
+			URL      string                      `json:"url"`
+			Body     embeddings.EmbeddingRequest `json:"body"`
+		}
+		require.NoError(s.t, json.Unmarshal([]byte(line), &req))
+		assert.Equal(s.t, http.MethodPost, req.Method)
+		assert.Equal(s.t, batchTypes.EndpointEmbeddings, req.URL)
+
+		text := req.Body.Input.(string)
+		if s.fail[text] {
+			fmt.Fprintf(&s.errors, `{"custom_id":%q,"response":{"status_code":400,"body":{"error":{"code":"1214","message":"input is invalid"}}}}`+"\n", req.CustomID)
+			continue
+		}
+
+		var n int
+		fmt.Sscanf(text, "document %d", &n)
+		fmt.Fprintf(&s.output, `{"custom_id":%q,"response":{"status_code":200,"body":{"object":"list","model":%q,"data":[{"object":"embedding","index":0,"embedding":[%d,%d]}]}}}`+"\n",
+			req.CustomID, req.Body.Model, n, 2*n)
+	}
+}
+
+func documents(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("document %d", i)
+	}
+	return texts
+}
+
+func TestEmbeddingsService_CreateAsyncBatch(t *testing.T) {
+	t.Parallel()
+
+	server := &embeddingBatchServer{t: t, polls: 2}
+	client := newRawTestClient(t, server.handler().ServeHTTP)
+	ctx := context.Background()
+
+	texts := documents(10000)
+	job, err := client.Embeddings.CreateAsyncBatch(ctx, "embedding-3", texts, "24h")
+	require.NoError(t, err)
+	assert.Equal(t, "batch_emb", job.ID())
+	assert.Equal(t, batchTypes.EndpointEmbeddings, server.created.Endpoint)
+	assert.Equal(t, "file_in", server.created.InputFileID)
+	assert.Equal(t, "24h", server.created.CompletionWindow)
+	assert.Equal(t, 10000, bytes.Count(server.input, []byte("\n")))
+
+	// The batch is still running
+	_, err = job.Results(ctx)
+	var notCompleted *batchTypes.NotCompletedError
+	require.ErrorAs(t, err, &notCompleted)
+
+	job.PollInterval = time.Millisecond
+	done, err := job.Wait(ctx)
+	require.NoError(t, err)
+	assert.True(t, done.IsCompleted())
+	assert.Same(t, done, job.Batch())
+
+	vectors, err := job.Results(ctx)
+	require.NoError(t, err)
+	require.Len(t, vectors, len(texts))
+	for i, vector := range vectors {
+		require.Equal(t, []float64{float64(i), float64(2 * i)}, vector, "input %d", i)
+	}
+}
+
+func TestEmbeddingsService_CreateAsyncBatch_Failures(t *testing.T) {
+	t.Parallel()
+
+	server := &embeddingBatchServer{t: t, fail: map[string]bool{"document 3": true}}
+	client := newRawTestClient(t, server.handler().ServeHTTP)
+	ctx := context.Background()
+
+	job, err := client.Embeddings.CreateAsyncBatch(ctx, "embedding-3", documents(5), "24h")
+	require.NoError(t, err)
+	_, err = job.Wait(ctx)
+	require.NoError(t, err)
+
+	// Drop the result of the last input from the output file
+	server.mu.Lock()
+	lines := strings.SplitAfter(server.output.String(), "\n")
+	server.output.Reset()
+	for _, line := range lines {
+		if !strings.Contains(line, `"input-4"`) {
+			server.output.WriteString(line)
+		}
+	}
+	server.mu.Unlock()
+
+	vectors, err := job.Results(ctx)
+	var resultsErr *embeddings.BatchResultsError
+	require.ErrorAs(t, err, &resultsErr)
+	require.Len(t, resultsErr.Errors, 2)
+	assert.ErrorContains(t, resultsErr.Errors[3], "input is invalid")
+	assert.ErrorContains(t, resultsErr.Errors[4], "no result")
+
+	require.Len(t, vectors, 5)
+	assert.Equal(t, []float64{2, 4}, vectors[2])
+	assert.Nil(t, vectors[3])
+	assert.Nil(t, vectors[4])
+}
+
+func TestEmbeddingsService_CreateAsyncBatch_Validation(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is sent for invalid arguments")
+	})
+
+	tests := map[string]struct {
+		model  string
+		texts  []string
+		window string
+	}{
+		"texts":  {"embedding-3", nil, "24h"},
+		"window": {"embedding-3", []string{"a"}, ""},
+	}
+	for field, tt := range tests {
+		_, err := client.Embeddings.CreateAsyncBatch(context.Background(), tt.model, tt.texts, tt.window)
+		assert.True(t, errors.IsValidationError(err), field)
+		assert.ErrorContains(t, err, field)
+	}
+
+	// Without a model or a default model
+	_, err := client.Embeddings.CreateAsyncBatch(context.Background(), "", []string{"a"}, "24h")
+	assert.True(t, errors.IsConfigError(err))
+}
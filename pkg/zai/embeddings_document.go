@@ -0,0 +1,86 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+// EmbedDocument splits text into chunks with embeddings.ChunkText and
+// embeds every chunk, sending at most embeddings.MaxBatchInputs chunks per
+// request. The result pairs each chunk and its byte offsets in text with
+// its embedding, and adds up the usage of the requests. Tokens are
+// estimated for the model unless opts sets a Model or a counter. An empty
+// model uses the client's default embedding model.
+//
+// Example:
+//
+//	doc, err := client.Embeddings.EmbedDocument(ctx, "embedding-3", text,
+//	    embeddings.ChunkOptions{Size: 512, Overlap: 64},
+//	)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	for _, c := range doc.Chunks {
+//	    index.Add(text[c.Start:c.End], c.Embedding)
+//	}
+func (s *EmbeddingsService) EmbedDocument(ctx context.Context, model, text string, opts embeddings.ChunkOptions) (*embeddings.DocumentEmbedding, error) {
+	model, err := resolveModel(model, s.defaultModel, "DefaultEmbeddingModel")
+	if err != nil {
+		return nil, err
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if opts.Model == "" {
+		opts.Model = model
+	}
+
+	chunks := embeddings.ChunkText(text, opts)
+	doc := &embeddings.DocumentEmbedding{
+		Model:  model,
+		Chunks: make([]embeddings.ChunkEmbedding, len(chunks)),
+	}
+	for start := 0; start < len(chunks); start += embeddings.MaxBatchInputs {
+		batch := chunks[start:min(start+embeddings.MaxBatchInputs, len(chunks))]
+		texts := make([]string, len(batch))
+		for i, c := range batch {
+			texts[i] = c.Text
+		}
+
+		resp, err := s.Create(ctx, s.withEncoding(embeddings.NewBatchEmbeddingRequest(model, texts)))
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data) != len(batch) {
+			return nil, fmt.Errorf("embeddings: got %d embeddings for %d chunks", len(resp.Data), len(batch))
+		}
+		for i, emb := range resp.Data {
+			// Embeddings are matched to chunks by index if it is valid
+			index := i
+			if emb.Index >= 0 && emb.Index < len(batch) {
+				index = emb.Index
+			}
+			if doc.Chunks[start+index].Embedding != nil {
+				return nil, fmt.Errorf("embeddings: chunk %d: more than one embedding", start+index)
+			}
+			vector := emb.GetFloatEmbedding()
+			if vector == nil {
+				return nil, fmt.Errorf("embeddings: chunk %d: cannot decode the embedding", start+index)
+			}
+			doc.Chunks[start+index] = embeddings.ChunkEmbedding{Chunk: batch[index], Embedding: vector}
+		}
+
+		if resp.Model != "" {
+			doc.Model = resp.Model
+		}
+		if resp.Usage != nil {
+			doc.Usage.PromptTokens += resp.Usage.PromptTokens
+			doc.Usage.CompletionTokens += resp.Usage.CompletionTokens
+			doc.Usage.TotalTokens += resp.Usage.TotalTokens
+		}
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,99 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	embeddingstypes "github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestEmbeddingsService_EmbedDocument(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu      sync.Mutex
+		batches []int
+	)
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+
+		var req struct {
+			Model string   `json:"model"`
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "embedding-3", req.Model)
+
+		mu.Lock()
+		batches = append(batches, len(req.Input))
+		mu.Unlock()
+
+		// Answer in reverse order, with the input length as the vector
+		data := make([]map[string]any, 0, len(req.Input))
+		for i := len(req.Input) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{"object": "embedding", "index": i, "embedding": []float64{float64(len(req.Input[i]))}})
+		}
+		writeJSON(w, map[string]any{
+			"object": "list",
+			"model":  "embedding-3",
+			"data":   data,
+			"usage":  map[string]int{"prompt_tokens": 10 * len(req.Input), "total_tokens": 10 * len(req.Input)},
+		})
+	})
+
+	sentences := make([]string, 300)
+	for i := range sentences {
+		sentences[i] = fmt.Sprintf("Sentence number %d.", i)
+	}
+	text := strings.Join(sentences, " ")
+
+	doc, err := client.Embeddings.EmbedDocument(context.Background(), "embedding-3", text,
+		embeddingstypes.ChunkOptions{Size: 40, Characters: true},
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{64, 64, len(doc.Chunks) - 128}, batches, "chunks are sent in batches of 64")
+	assert.Equal(t, "embedding-3", doc.Model)
+	assert.Equal(t, 10*len(doc.Chunks), doc.Usage.PromptTokens)
+	assert.Equal(t, 10*len(doc.Chunks), doc.Usage.TotalTokens)
+
+	for i, c := range doc.Chunks {
+		assert.Equal(t, i, c.Index)
+		assert.Equal(t, text[c.Start:c.End], c.Text)
+		assert.Equal(t, []float64{float64(len(c.Text))}, c.Embedding, "chunk %d has its own embedding", i)
+	}
+	assert.Equal(t, "Sentence number 0. Sentence number 1.", doc.Chunks[0].Text)
+}
+
+func TestEmbeddingsService_EmbedDocument_Errors(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"data": []map[string]any{{"index": 0, "embedding": []float64{1}}}})
+	})
+
+	_, err := client.Embeddings.EmbedDocument(context.Background(), "embedding-3", "text",
+		embeddingstypes.ChunkOptions{Size: 10, Overlap: 10},
+	)
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "overlap", validationErr.Field)
+
+	_, err = client.Embeddings.EmbedDocument(context.Background(), "embedding-3", "One. Two. Three.",
+		embeddingstypes.ChunkOptions{Size: 6, Characters: true},
+	)
+	assert.ErrorContains(t, err, "got 1 embeddings for 3 chunks")
+
+	doc, err := client.Embeddings.EmbedDocument(context.Background(), "embedding-3", "  ", embeddingstypes.ChunkOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, doc.Chunks)
+}
@@ -2,9 +2,13 @@ package zai
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
+	"math"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -521,3 +525,72 @@ func TestClient_EmbeddingsService_Integration(t *testing.T) {
 		// to find that "cat" and "kitten" are more similar than "cat" and "dog"
 	})
 }
+
+func TestEmbeddingsService_Base64(t *testing.T) {
+	t.Parallel()
+
+	vectors := [][]float64{
+		{0.0123456789, -0.98765, 0.5, 1e-6},
+		{-0.333333333, 0.142857142, 0.999999, -0.000042},
+	}
+
+	var mu sync.Mutex
+	var formats []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req embeddingstypes.EmbeddingRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		mu.Lock()
+		formats = append(formats, req.EncodingFormat)
+		mu.Unlock()
+
+		resp := embeddingstypes.EmbeddingResponse{Object: "list", Model: req.Model}
+		for i, vector := range vectors {
+			var embedding interface{} = vector
+			if req.EncodingFormat == embeddingstypes.EncodingFormatBase64 {
+				data := make([]byte, 0, len(vector)*4)
+				for _, f := range vector {
+					data = binary.LittleEndian.AppendUint32(data, math.Float32bits(float32(f)))
+				}
+				embedding = base64.StdEncoding.EncodeToString(data)
+			}
+			resp.Data = append(resp.Data, embeddingstypes.Embedding{Object: "embedding", Index: i, Embedding: embedding})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	newClient := func(opts ...ClientOption) *Client {
+		client, err := NewClient(append([]ClientOption{
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		}, opts...)...)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		return client
+	}
+
+	texts := []string{"a", "b"}
+	floatResult, err := newClient().Embeddings.CreateBatch(context.Background(), "embedding-3", texts)
+	require.NoError(t, err)
+
+	client := newClient(WithBase64Embeddings())
+	base64Result, err := client.Embeddings.CreateBatch(context.Background(), "embedding-3", texts)
+	require.NoError(t, err)
+
+	require.Len(t, base64Result, len(vectors))
+	for i := range vectors {
+		assert.InDeltaSlice(t, floatResult[i], base64Result[i], 1e-7)
+	}
+
+	single, err := client.Embeddings.CreateSingle(context.Background(), "embedding-3", "a")
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, vectors[0], single, 1e-7)
+
+	// Models without base64 support keep the float format
+	_, err = client.Embeddings.CreateBatch(context.Background(), "embedding-2", texts)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"", "base64", "base64", ""}, formats)
+}
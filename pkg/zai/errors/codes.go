@@ -0,0 +1,180 @@
+package errors
+
+import (
+	"errors"
+)
+
+// Provider error codes, returned in the "code" field of API error bodies.
+// Both the Z.ai and the Zhipu AI platforms use these codes.
+const (
+	// CodeAuthFailed means the request could not be authenticated.
+	CodeAuthFailed = "1000"
+	// CodeAuthHeaderMissing means the request has no Authorization header.
+	CodeAuthHeaderMissing = "1001"
+	// CodeInvalidToken means the authentication token is invalid.
+	CodeInvalidToken = "1002"
+	// CodeTokenExpired means the authentication token has expired.
+	CodeTokenExpired = "1003"
+	// CodeAuthTokenFailed means the authentication token failed to verify.
+	CodeAuthTokenFailed = "1004"
+
+	// CodeAccountInactive means the account is not activated.
+	CodeAccountInactive = "1110"
+	// CodeAccountNotFound means the account does not exist.
+	CodeAccountNotFound = "1111"
+	// CodeAccountLocked means the account is locked.
+	CodeAccountLocked = "1112"
+	// CodeAccountInArrears means the account balance is exhausted.
+	CodeAccountInArrears = "1113"
+	// CodeAccountUnavailable means the account cannot be used for now.
+	CodeAccountUnavailable = "1120"
+
+	// CodeInvalidParameters means the request parameters are invalid.
+	CodeInvalidParameters = "1210"
+	// CodeModelNotFound means the model does not exist.
+	CodeModelNotFound = "1211"
+	// CodeMethodNotSupported means the model does not support the call
+	// method, such as streaming.
+	CodeMethodNotSupported = "1212"
+	// CodePromptMissing means the request has no prompt.
+	CodePromptMissing = "1213"
+	// CodeInvalidParameter means a request parameter is invalid.
+	CodeInvalidParameter = "1214"
+	// CodeNoPermission means the account may not call the API.
+	CodeNoPermission = "1220"
+	// CodeAPIOffline means the API has been taken offline.
+	CodeAPIOffline = "1221"
+	// CodeAPINotFound means the API does not exist.
+	CodeAPINotFound = "1222"
+	// CodeNetworkError means the platform failed to reach an upstream
+	// service; the request may succeed when retried.
+	CodeNetworkError = "1234"
+	// CodePromptTooLong means the prompt exceeds the model's context.
+	CodePromptTooLong = "1261"
+
+	// CodeCallBlocked means the API call was blocked.
+	CodeCallBlocked = "1300"
+	// CodeSensitiveContent means the input or the output was rejected by
+	// the content safety system.
+	CodeSensitiveContent = "1301"
+	// CodeConcurrencyLimit means the account has too many concurrent
+	// requests.
+	CodeConcurrencyLimit = "1302"
+	// CodeRateLimit means the account sends requests too often.
+	CodeRateLimit = "1303"
+	// CodeDailyLimit means the daily call limit of the API is reached.
+	CodeDailyLimit = "1304"
+	// CodeOverloaded means the platform is overloaded.
+	CodeOverloaded = "1305"
+	// CodeUsageLimit means the usage limit of the account's resource
+	// package is reached.
+	CodeUsageLimit = "1308"
+	// CodePlanExpired means the account's subscription plan has expired.
+	CodePlanExpired = "1309"
+	// CodePeriodLimit means the weekly or monthly usage limit is reached.
+	CodePeriodLimit = "1310"
+
+	// CodeInternalError means the platform failed with an internal error.
+	CodeInternalError = "500"
+)
+
+// codeClass groups provider error codes by how the caller should react.
+type codeClass int
+
+const (
+	classAuthentication codeClass = iota + 1
+	classAccount
+	classQuota
+	classInvalidParameter
+	classModelNotFound
+	classPermission
+	classContentPolicy
+	classRateLimit
+	classTransient
+)
+
+// codeClasses maps the documented provider error codes to their class.
+var codeClasses = map[string]codeClass{
+	CodeAuthFailed:         classAuthentication,
+	CodeAuthHeaderMissing:  classAuthentication,
+	CodeInvalidToken:       classAuthentication,
+	CodeTokenExpired:       classAuthentication,
+	CodeAuthTokenFailed:    classAuthentication,
+	CodeAccountInactive:    classAccount,
+	CodeAccountNotFound:    classAccount,
+	CodeAccountLocked:      classAccount,
+	CodeAccountInArrears:   classQuota,
+	CodeAccountUnavailable: classAccount,
+	CodeInvalidParameters:  classInvalidParameter,
+	CodeModelNotFound:      classModelNotFound,
+	CodeMethodNotSupported: classInvalidParameter,
+	CodePromptMissing:      classInvalidParameter,
+	CodeInvalidParameter:   classInvalidParameter,
+	CodeNoPermission:       classPermission,
+	CodeAPIOffline:         classPermission,
+	CodeAPINotFound:        classPermission,
+	CodeNetworkError:       classTransient,
+	CodePromptTooLong:      classInvalidParameter,
+	CodeCallBlocked:        classPermission,
+	CodeSensitiveContent:   classContentPolicy,
+	CodeConcurrencyLimit:   classRateLimit,
+	CodeRateLimit:          classRateLimit,
+	CodeDailyLimit:         classQuota,
+	CodeOverloaded:         classTransient,
+	CodeUsageLimit:         classQuota,
+	CodePlanExpired:        classQuota,
+	CodePeriodLimit:        classQuota,
+	CodeInternalError:      classTransient,
+}
+
+// RetryableCode reports whether a request that failed with the provider
+// error code may succeed when retried, and whether the code is one of the
+// documented codes. Rate limit and transient codes are retryable, even when
+// the API answers them with a 400 status; quota, account, authentication
+// and parameter codes are not, even when answered with a 429 status.
+func RetryableCode(code string) (retryable, known bool) {
+	class, known := codeClasses[code]
+	return class == classRateLimit || class == classTransient, known
+}
+
+// Code returns the provider error code of err, such as "1211", or an empty
+// string if err is not an API error or the API reported no code.
+//
+// Example:
+//
+//	if errors.Code(err) == errors.CodeModelNotFound {
+//	    // fall back to another model
+//	}
+func Code(err error) string {
+	var statusErr *APIStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Code
+	}
+	return ""
+}
+
+// IsQuotaExhausted checks if the API rejected a request because the
+// account's balance, resource package or usage limit is exhausted. Such
+// requests fail until the quota is renewed, so they are not retried.
+func IsQuotaExhausted(err error) bool {
+	return codeClasses[Code(err)] == classQuota
+}
+
+// IsInvalidParameter checks if the API rejected a request for invalid or
+// missing parameters, including a prompt that is too long.
+func IsInvalidParameter(err error) bool {
+	return codeClasses[Code(err)] == classInvalidParameter
+}
+
+// IsModelNotFound checks if the API rejected a request because the model
+// does not exist or is not available to the account.
+func IsModelNotFound(err error) bool {
+	return codeClasses[Code(err)] == classModelNotFound
+}
+
+// IsContextLengthExceeded checks if the API rejected a request because the
+// prompt exceeds the model's context window. Such requests succeed only
+// with fewer or shorter messages; see chat.WithAutoTrim.
+func IsContextLengthExceeded(err error) bool {
+	return Code(err) == CodePromptTooLong
+}
@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	err := NewAPIRequestFailedError("Unknown Model", 400, nil)
+	err.Code = CodeModelNotFound
+
+	if got := Code(fmt.Errorf("chat: %w", err)); got != "1211" {
+		t.Errorf("Code() = %q, want %q", got, "1211")
+	}
+	if got := Code(NewAPIRequestFailedError("bad request", 400, nil)); got != "" {
+		t.Errorf("Code() without code = %q, want empty", got)
+	}
+	if got := Code(NewValidationError("model", "required", "")); got != "" {
+		t.Errorf("Code() of a validation error = %q, want empty", got)
+	}
+	if got := Code(nil); got != "" {
+		t.Errorf("Code(nil) = %q, want empty", got)
+	}
+
+	policyErr := NewContentPolicyError("unsafe", 400, nil)
+	policyErr.Code = CodeSensitiveContent
+	if got := Code(policyErr); got != CodeSensitiveContent {
+		t.Errorf("Code() of a content policy error = %q, want %q", got, CodeSensitiveContent)
+	}
+}
+
+func TestCodeHelpers(t *testing.T) {
+	t.Parallel()
+
+	withCode := func(code string, statusCode int) error {
+		err := NewAPIStatusError("failed", statusCode, nil)
+		err.Code = code
+		return fmt.Errorf("request: %w", err)
+	}
+
+	tests := []struct {
+		code             string
+		statusCode       int
+		quota            bool
+		invalidParameter bool
+		modelNotFound    bool
+		contextLength    bool
+	}{
+		{code: "1113", statusCode: 429, quota: true},
+		{code: "1304", statusCode: 429, quota: true},
+		{code: "1308", statusCode: 429, quota: true},
+		{code: "1310", statusCode: 429, quota: true},
+		{code: "1210", statusCode: 400, invalidParameter: true},
+		{code: "1214", statusCode: 400, invalidParameter: true},
+		{code: "1261", statusCode: 400, invalidParameter: true, contextLength: true},
+		{code: "1211", statusCode: 400, modelNotFound: true},
+		{code: "1302", statusCode: 429},
+		{code: "1301", statusCode: 400},
+		{code: "9999", statusCode: 400},
+		{code: "", statusCode: 500},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			t.Parallel()
+
+			err := withCode(tt.code, tt.statusCode)
+			if got := IsQuotaExhausted(err); got != tt.quota {
+				t.Errorf("IsQuotaExhausted() = %v, want %v", got, tt.quota)
+			}
+			if got := IsInvalidParameter(err); got != tt.invalidParameter {
+				t.Errorf("IsInvalidParameter() = %v, want %v", got, tt.invalidParameter)
+			}
+			if got := IsModelNotFound(err); got != tt.modelNotFound {
+				t.Errorf("IsModelNotFound() = %v, want %v", got, tt.modelNotFound)
+			}
+			if got := IsContextLengthExceeded(err); got != tt.contextLength {
+				t.Errorf("IsContextLengthExceeded() = %v, want %v", got, tt.contextLength)
+			}
+		})
+	}
+}
+
+func TestRetryableCode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		code          string
+		wantRetryable bool
+		wantKnown     bool
+	}{
+		{"1234", true, true},
+		{"1302", true, true},
+		{"1303", true, true},
+		{"1305", true, true},
+		{"500", true, true},
+		{"1113", false, true},
+		{"1304", false, true},
+		{"1211", false, true},
+		{"1301", false, true},
+		{"1002", false, true},
+		{"9999", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		retryable, known := RetryableCode(tt.code)
+		if retryable != tt.wantRetryable || known != tt.wantKnown {
+			t.Errorf("RetryableCode(%q) = %v, %v, want %v, %v", tt.code, retryable, known, tt.wantRetryable, tt.wantKnown)
+		}
+	}
+}
@@ -2,9 +2,12 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // ZaiError is the base error type for all Z.ai SDK errors.
@@ -27,16 +30,21 @@ func NewZaiError(message string) *ZaiError {
 type APIStatusError struct {
 	*ZaiError
 	StatusCode int
+	Code       string // The provider's error code, e.g. "1211"; see Code
 	Response   *http.Response
 	RequestID  string // Optional request ID for tracing
 }
 
 // Error implements the error interface for APIStatusError.
 func (e *APIStatusError) Error() string {
+	status := fmt.Sprintf("status %d", e.StatusCode)
+	if e.Code != "" {
+		status += ", code " + e.Code
+	}
 	if e.RequestID != "" {
-		return fmt.Sprintf("API error (status %d, request_id: %s): %s", e.StatusCode, e.RequestID, e.Message)
+		status += ", request_id: " + e.RequestID
 	}
-	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+	return fmt.Sprintf("API error (%s): %s", status, e.Message)
 }
 
 // Unwrap implements error unwrapping for APIStatusError.
@@ -227,9 +235,31 @@ func NewAPIConnectionError(request *http.Request, message string) *APIConnection
 	}
 }
 
-// APITimeoutError indicates a request timeout occurred.
+// TimeoutPhase is the phase of a request that timed out.
+type TimeoutPhase string
+
+const (
+	// TimeoutPhaseConnect is establishing the connection, including the
+	// TLS handshake.
+	TimeoutPhaseConnect TimeoutPhase = "connect"
+
+	// TimeoutPhaseRequest is a whole non-streaming request, from sending
+	// it to reading the last byte of the response.
+	TimeoutPhaseRequest TimeoutPhase = "request"
+
+	// TimeoutPhaseStreamRead is waiting for the response headers or the
+	// next bytes of a stream.
+	TimeoutPhaseStreamRead TimeoutPhase = "stream read"
+)
+
+// APITimeoutError indicates a request timeout occurred. Timeouts of the
+// client's connect, request and stream read timeouts are reported as
+// *ConnectTimeoutError, *RequestTimeoutError and *StreamReadTimeoutError,
+// which unwrap to an APITimeoutError with the Phase and Timeout set.
 type APITimeoutError struct {
 	*APIConnectionError
+	Phase   TimeoutPhase  // The phase that timed out, if known
+	Timeout time.Duration // The timeout that expired, or zero if unknown
 }
 
 // Unwrap implements error unwrapping for APITimeoutError.
@@ -244,6 +274,67 @@ func NewAPITimeoutError(request *http.Request) *APITimeoutError {
 	}
 }
 
+// newPhaseTimeoutError creates an APITimeoutError for a phase.
+func newPhaseTimeoutError(request *http.Request, phase TimeoutPhase, timeout time.Duration) *APITimeoutError {
+	message := fmt.Sprintf("%s timed out.", phase)
+	if timeout > 0 {
+		message = fmt.Sprintf("%s timed out after %s.", phase, timeout)
+	}
+	return &APITimeoutError{
+		APIConnectionError: NewAPIConnectionError(request, strings.ToUpper(message[:1])+message[1:]),
+		Phase:              phase,
+		Timeout:            timeout,
+	}
+}
+
+// ConnectTimeoutError indicates that the connection, including the TLS
+// handshake, was not established within the connect timeout.
+type ConnectTimeoutError struct {
+	*APITimeoutError
+}
+
+// Unwrap implements error unwrapping for ConnectTimeoutError.
+func (e *ConnectTimeoutError) Unwrap() error {
+	return e.APITimeoutError
+}
+
+// NewConnectTimeoutError creates a new ConnectTimeoutError.
+func NewConnectTimeoutError(request *http.Request, timeout time.Duration) *ConnectTimeoutError {
+	return &ConnectTimeoutError{APITimeoutError: newPhaseTimeoutError(request, TimeoutPhaseConnect, timeout)}
+}
+
+// RequestTimeoutError indicates that a non-streaming request, including
+// reading its response body, did not finish within the request timeout.
+type RequestTimeoutError struct {
+	*APITimeoutError
+}
+
+// Unwrap implements error unwrapping for RequestTimeoutError.
+func (e *RequestTimeoutError) Unwrap() error {
+	return e.APITimeoutError
+}
+
+// NewRequestTimeoutError creates a new RequestTimeoutError.
+func NewRequestTimeoutError(request *http.Request, timeout time.Duration) *RequestTimeoutError {
+	return &RequestTimeoutError{APITimeoutError: newPhaseTimeoutError(request, TimeoutPhaseRequest, timeout)}
+}
+
+// StreamReadTimeoutError indicates that the response headers or the next
+// bytes of a stream did not arrive within the stream read timeout.
+type StreamReadTimeoutError struct {
+	*APITimeoutError
+}
+
+// Unwrap implements error unwrapping for StreamReadTimeoutError.
+func (e *StreamReadTimeoutError) Unwrap() error {
+	return e.APITimeoutError
+}
+
+// NewStreamReadTimeoutError creates a new StreamReadTimeoutError.
+func NewStreamReadTimeoutError(request *http.Request, timeout time.Duration) *StreamReadTimeoutError {
+	return &StreamReadTimeoutError{APITimeoutError: newPhaseTimeoutError(request, TimeoutPhaseStreamRead, timeout)}
+}
+
 // ConfigError represents a configuration error.
 type ConfigError struct {
 	*ZaiError
@@ -300,6 +391,251 @@ func NewValidationError(field, message string, value interface{}) *ValidationErr
 	}
 }
 
+// ClientClosedError is returned by requests that were in flight when the
+// client was closed, and by any call made on a closed client.
+type ClientClosedError struct {
+	*ZaiError
+}
+
+// Unwrap implements error unwrapping for ClientClosedError.
+func (e *ClientClosedError) Unwrap() error {
+	return e.ZaiError
+}
+
+// Is reports whether target is context.Canceled, so that code treating
+// cancellation specially also recognizes a closed client.
+func (e *ClientClosedError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+// NewClientClosedError creates a new ClientClosedError.
+func NewClientClosedError() *ClientClosedError {
+	return &ClientClosedError{
+		ZaiError: &ZaiError{Message: "client is closed"},
+	}
+}
+
+// StreamIdleTimeoutError is returned by a stream that received no event
+// within its idle timeout, for example because a proxy dropped the
+// connection without closing it.
+type StreamIdleTimeoutError struct {
+	*ZaiError
+	Timeout time.Duration // The idle timeout that expired
+}
+
+// Unwrap implements error unwrapping for StreamIdleTimeoutError.
+func (e *StreamIdleTimeoutError) Unwrap() error {
+	return e.ZaiError
+}
+
+// Is reports whether target is context.DeadlineExceeded, so that code
+// handling deadlines also recognizes an idle stream.
+func (e *StreamIdleTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// NewStreamIdleTimeoutError creates a new StreamIdleTimeoutError.
+func NewStreamIdleTimeoutError(timeout time.Duration) *StreamIdleTimeoutError {
+	return &StreamIdleTimeoutError{
+		ZaiError: &ZaiError{Message: fmt.Sprintf("no stream event received for %s", timeout)},
+		Timeout:  timeout,
+	}
+}
+
+// PayloadTooLargeError is returned when a request body exceeds the
+// client's request size limit, or a response or stream body exceeds its
+// response size limit.
+type PayloadTooLargeError struct {
+	*ZaiError
+	Payload string // "request", "response" or "stream"
+	Size    int64  // The size of the body, or the bytes read when the limit was exceeded
+	Limit   int64  // The limit that was exceeded
+}
+
+// Unwrap implements error unwrapping for PayloadTooLargeError.
+func (e *PayloadTooLargeError) Unwrap() error {
+	return e.ZaiError
+}
+
+// NewPayloadTooLargeError creates a new PayloadTooLargeError.
+func NewPayloadTooLargeError(payload string, size, limit int64) *PayloadTooLargeError {
+	return &PayloadTooLargeError{
+		ZaiError: &ZaiError{Message: fmt.Sprintf("%s body of %d bytes exceeds the limit of %d bytes", payload, size, limit)},
+		Payload:  payload,
+		Size:     size,
+		Limit:    limit,
+	}
+}
+
+// CircuitOpenError is returned without sending a request while the
+// client's circuit breaker for the host is open.
+type CircuitOpenError struct {
+	*ZaiError
+	Host    string    // The host whose circuit is open
+	RetryAt time.Time // When the circuit lets probe requests through again
+}
+
+// Unwrap implements error unwrapping for CircuitOpenError.
+func (e *CircuitOpenError) Unwrap() error {
+	return e.ZaiError
+}
+
+// NewCircuitOpenError creates a new CircuitOpenError.
+func NewCircuitOpenError(host string, retryAt time.Time) *CircuitOpenError {
+	return &CircuitOpenError{
+		ZaiError: &ZaiError{Message: fmt.Sprintf("circuit breaker is open for %s", host)},
+		Host:     host,
+		RetryAt:  retryAt,
+	}
+}
+
+// RequestBlockedError is returned without sending a request when the
+// client's request sanitizer rejects it.
+type RequestBlockedError struct {
+	*ZaiError
+	Service string // The service of the blocked request, e.g. "chat"
+	Err     error  // The error returned by the sanitizer
+}
+
+// Unwrap implements error unwrapping for RequestBlockedError, exposing
+// both the SDK error and the sanitizer's error.
+func (e *RequestBlockedError) Unwrap() []error {
+	return []error{e.ZaiError, e.Err}
+}
+
+// NewRequestBlockedError creates a new RequestBlockedError.
+func NewRequestBlockedError(service string, err error) *RequestBlockedError {
+	return &RequestBlockedError{
+		ZaiError: &ZaiError{Message: fmt.Sprintf("%s request blocked by sanitizer: %v", service, err)},
+		Service:  service,
+		Err:      err,
+	}
+}
+
+// ContentFlaggedError is returned by moderated chat calls when the user
+// prompt or the model output exceeds the moderation policy.
+type ContentFlaggedError struct {
+	*ZaiError
+	Stage      string             // "input" or "output"
+	Categories []string           // The categories that exceeded the policy, sorted
+	Scores     map[string]float64 // The scores of all categories
+}
+
+// Unwrap implements error unwrapping for ContentFlaggedError.
+func (e *ContentFlaggedError) Unwrap() error {
+	return e.ZaiError
+}
+
+// NewContentFlaggedError creates a new ContentFlaggedError.
+func NewContentFlaggedError(stage string, categories []string, scores map[string]float64) *ContentFlaggedError {
+	return &ContentFlaggedError{
+		ZaiError:   &ZaiError{Message: fmt.Sprintf("%s flagged by moderation: %s", stage, strings.Join(categories, ", "))},
+		Stage:      stage,
+		Categories: categories,
+		Scores:     scores,
+	}
+}
+
+// ContentPolicyError is returned when the API rejects a request because
+// the prompt, an input image or the generated output tripped the
+// provider's content safety system. The API answers such requests with
+// status 400, so IsRequestError also reports true.
+type ContentPolicyError struct {
+	*APIRequestFailedError
+	Field      string   // "prompt", "image" or "output"; empty if not reported
+	Categories []string // The violated categories, sorted; empty if not reported
+}
+
+// Unwrap implements error unwrapping for ContentPolicyError.
+func (e *ContentPolicyError) Unwrap() error {
+	return e.APIRequestFailedError
+}
+
+// NewContentPolicyError creates a new ContentPolicyError.
+func NewContentPolicyError(message string, statusCode int, response *http.Response) *ContentPolicyError {
+	return &ContentPolicyError{
+		APIRequestFailedError: NewAPIRequestFailedError(message, statusCode, response),
+	}
+}
+
+// EmptyResultError is returned by convenience methods, such as
+// Images.Generate or Videos.GenerateText, when the API answers
+// successfully but without the result they return, so that an empty URL
+// or task ID is not passed on silently. The lower-level methods, such as
+// Images.Create, return the response as is.
+type EmptyResultError struct {
+	*ZaiError
+	Operation string // The method that got no result, e.g. "Images.Generate"
+	Result    string // What the response lacked, e.g. "image" or "task ID"
+}
+
+// Unwrap implements error unwrapping for EmptyResultError.
+func (e *EmptyResultError) Unwrap() error {
+	return e.ZaiError
+}
+
+// NewEmptyResultError creates a new EmptyResultError.
+func NewEmptyResultError(operation, result string) *EmptyResultError {
+	return &EmptyResultError{
+		ZaiError:  &ZaiError{Message: fmt.Sprintf("%s: the API returned no %s", operation, result)},
+		Operation: operation,
+		Result:    result,
+	}
+}
+
+// PingFailure classifies why a health check failed.
+type PingFailure string
+
+const (
+	// PingFailureDNS means the API host name could not be resolved.
+	PingFailureDNS PingFailure = "dns"
+
+	// PingFailureConnect means no connection could be made to the API host.
+	PingFailureConnect PingFailure = "connect"
+
+	// PingFailureTLS means the TLS handshake failed, for example because
+	// the server certificate is not trusted.
+	PingFailureTLS PingFailure = "tls"
+
+	// PingFailureTimeout means the API did not answer in time.
+	PingFailureTimeout PingFailure = "timeout"
+
+	// PingFailureAuth means the API rejected the credentials (401).
+	PingFailureAuth PingFailure = "auth"
+
+	// PingFailureServer means the API failed with a server error (5xx).
+	PingFailureServer PingFailure = "server"
+
+	// PingFailureOther covers other failures, such as rate limiting or an
+	// open circuit breaker.
+	PingFailureOther PingFailure = "other"
+)
+
+// PingError is returned by a health check that failed. Err is the error of
+// the underlying request, so the other Is* helpers also apply.
+type PingError struct {
+	*ZaiError
+	Kind       PingFailure // What failed
+	StatusCode int         // The HTTP status code, if the API answered
+	Err        error       // The error of the request
+}
+
+// Unwrap implements error unwrapping for PingError, exposing both the SDK
+// error and the error of the request.
+func (e *PingError) Unwrap() []error {
+	return []error{e.ZaiError, e.Err}
+}
+
+// NewPingError creates a new PingError.
+func NewPingError(kind PingFailure, statusCode int, err error) *PingError {
+	return &PingError{
+		ZaiError:   &ZaiError{Message: fmt.Sprintf("health check failed (%s): %v", kind, err)},
+		Kind:       kind,
+		StatusCode: statusCode,
+		Err:        err,
+	}
+}
+
 // Error type checking helpers
 
 // IsAuthenticationError checks if the error is an authentication error.
@@ -339,14 +675,92 @@ func IsTimeoutError(err error) bool {
 	return errors.As(err, &timeoutErr)
 }
 
+// IsConnectTimeoutError checks if the connect timeout expired.
+func IsConnectTimeoutError(err error) bool {
+	var timeoutErr *ConnectTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// IsRequestTimeoutError checks if the request timeout expired.
+func IsRequestTimeoutError(err error) bool {
+	var timeoutErr *RequestTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
+// IsStreamReadTimeoutError checks if the stream read timeout expired.
+func IsStreamReadTimeoutError(err error) bool {
+	var timeoutErr *StreamReadTimeoutError
+	return errors.As(err, &timeoutErr)
+}
+
 // IsConfigError checks if the error is a configuration error.
 func IsConfigError(err error) bool {
 	var configErr *ConfigError
 	return errors.As(err, &configErr)
 }
 
+// IsClientClosedError checks if the error was caused by closing the client.
+func IsClientClosedError(err error) bool {
+	var closedErr *ClientClosedError
+	return errors.As(err, &closedErr)
+}
+
+// IsStreamIdleTimeoutError checks if a stream was aborted by its idle timeout.
+func IsStreamIdleTimeoutError(err error) bool {
+	var idleErr *StreamIdleTimeoutError
+	return errors.As(err, &idleErr)
+}
+
 // IsValidationError checks if the error is a validation error.
 func IsValidationError(err error) bool {
 	var validationErr *ValidationError
 	return errors.As(err, &validationErr)
 }
+
+// IsCircuitOpenError checks if a request was refused by an open circuit
+// breaker.
+func IsCircuitOpenError(err error) bool {
+	var circuitErr *CircuitOpenError
+	return errors.As(err, &circuitErr)
+}
+
+// IsRequestBlockedError checks if a request was rejected by the client's
+// request sanitizer.
+func IsRequestBlockedError(err error) bool {
+	var blockedErr *RequestBlockedError
+	return errors.As(err, &blockedErr)
+}
+
+// IsPayloadTooLargeError checks if a request or response body exceeded the
+// client's size limits.
+func IsPayloadTooLargeError(err error) bool {
+	var sizeErr *PayloadTooLargeError
+	return errors.As(err, &sizeErr)
+}
+
+// IsContentFlaggedError checks if a moderated call was blocked because
+// content exceeded the moderation policy.
+func IsContentFlaggedError(err error) bool {
+	var flaggedErr *ContentFlaggedError
+	return errors.As(err, &flaggedErr)
+}
+
+// IsContentPolicyError checks if the API rejected a request for violating
+// its content policy.
+func IsContentPolicyError(err error) bool {
+	var policyErr *ContentPolicyError
+	return errors.As(err, &policyErr)
+}
+
+// IsEmptyResultError checks if a convenience method got a successful
+// response without the result it returns.
+func IsEmptyResultError(err error) bool {
+	var emptyErr *EmptyResultError
+	return errors.As(err, &emptyErr)
+}
+
+// IsPingError checks if a health check failed.
+func IsPingError(err error) bool {
+	var pingErr *PingError
+	return errors.As(err, &pingErr)
+}
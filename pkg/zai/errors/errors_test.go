@@ -1,11 +1,14 @@
 package errors
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestZaiError(t *testing.T) {
@@ -53,6 +56,7 @@ func TestAPIStatusError(t *testing.T) {
 		name       string
 		message    string
 		statusCode int
+		code       string
 		requestID  string
 		wantErr    string
 	}{
@@ -70,6 +74,14 @@ func TestAPIStatusError(t *testing.T) {
 			requestID:  "req-123",
 			wantErr:    "API error (status 400, request_id: req-123): bad request",
 		},
+		{
+			name:       "error with code",
+			message:    "Unknown Model, please check the model code.",
+			statusCode: 400,
+			code:       "1211",
+			requestID:  "req-123",
+			wantErr:    "API error (status 400, code 1211, request_id: req-123): Unknown Model, please check the model code.",
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,6 +90,7 @@ func TestAPIStatusError(t *testing.T) {
 			t.Parallel()
 
 			err := NewAPIStatusError(tt.message, tt.statusCode, resp)
+			err.Code = tt.code
 			err.RequestID = tt.requestID
 
 			if err.Error() != tt.wantErr {
@@ -374,6 +387,50 @@ func TestAPITimeoutError(t *testing.T) {
 	}
 }
 
+func TestPhaseTimeoutErrors(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest("POST", "/api/v1/test", nil)
+	tests := []struct {
+		err     error
+		is      func(error) bool
+		phase   TimeoutPhase
+		timeout time.Duration
+		wantErr string
+	}{
+		{NewConnectTimeoutError(req, 8*time.Second), IsConnectTimeoutError, TimeoutPhaseConnect, 8 * time.Second,
+			"API response error for POST /api/v1/test: Connect timed out after 8s."},
+		{NewRequestTimeoutError(req, time.Minute), IsRequestTimeoutError, TimeoutPhaseRequest, time.Minute,
+			"API response error for POST /api/v1/test: Request timed out after 1m0s."},
+		{NewStreamReadTimeoutError(req, 0), IsStreamReadTimeoutError, TimeoutPhaseStreamRead, 0,
+			"API response error for POST /api/v1/test: Stream read timed out."},
+	}
+
+	for _, tt := range tests {
+		if tt.err.Error() != tt.wantErr {
+			t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.wantErr)
+		}
+		if !tt.is(tt.err) {
+			t.Errorf("%s: the phase check should return true", tt.phase)
+		}
+
+		var timeoutErr *APITimeoutError
+		if !errors.As(tt.err, &timeoutErr) {
+			t.Fatalf("%s: the error should unwrap to APITimeoutError", tt.phase)
+		}
+		if timeoutErr.Phase != tt.phase || timeoutErr.Timeout != tt.timeout {
+			t.Errorf("Phase, Timeout = %q, %v, want %q, %v", timeoutErr.Phase, timeoutErr.Timeout, tt.phase, tt.timeout)
+		}
+	}
+
+	if IsConnectTimeoutError(NewRequestTimeoutError(req, time.Second)) {
+		t.Error("IsConnectTimeoutError should return false for a request timeout")
+	}
+	if IsRequestTimeoutError(NewAPITimeoutError(req)) {
+		t.Error("IsRequestTimeoutError should return false for a plain APITimeoutError")
+	}
+}
+
 func TestConfigError(t *testing.T) {
 	t.Parallel()
 
@@ -475,6 +532,31 @@ func TestValidationError(t *testing.T) {
 	}
 }
 
+func TestClientClosedError(t *testing.T) {
+	t.Parallel()
+
+	err := NewClientClosedError()
+	if err.Error() != "client is closed" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "client is closed")
+	}
+
+	if !IsClientClosedError(err) {
+		t.Error("IsClientClosedError should return true for ClientClosedError")
+	}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Error("ClientClosedError should match context.Canceled")
+	}
+
+	if IsClientClosedError(context.Canceled) {
+		t.Error("IsClientClosedError should return false for context.Canceled")
+	}
+
+	if IsClientClosedError(nil) {
+		t.Error("IsClientClosedError should return false for nil error")
+	}
+}
+
 func TestErrorInterface(t *testing.T) {
 	t.Parallel()
 
@@ -732,3 +814,178 @@ func TestErrorHelpers(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamIdleTimeoutError(t *testing.T) {
+	t.Parallel()
+
+	err := NewStreamIdleTimeoutError(30 * time.Second)
+	if err.Error() != "no stream event received for 30s" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", err.Timeout)
+	}
+
+	if !IsStreamIdleTimeoutError(err) {
+		t.Error("IsStreamIdleTimeoutError should return true for StreamIdleTimeoutError")
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Error("StreamIdleTimeoutError should match context.DeadlineExceeded")
+	}
+
+	if IsStreamIdleTimeoutError(context.DeadlineExceeded) {
+		t.Error("IsStreamIdleTimeoutError should return false for context.DeadlineExceeded")
+	}
+}
+
+func TestPayloadTooLargeError(t *testing.T) {
+	t.Parallel()
+
+	err := NewPayloadTooLargeError("request", 2048, 1024)
+	if err.Error() != "request body of 2048 bytes exceeds the limit of 1024 bytes" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Payload != "request" || err.Size != 2048 || err.Limit != 1024 {
+		t.Errorf("unexpected fields: %+v", err)
+	}
+
+	if !IsPayloadTooLargeError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsPayloadTooLargeError should return true for a wrapped PayloadTooLargeError")
+	}
+
+	if IsPayloadTooLargeError(nil) {
+		t.Error("IsPayloadTooLargeError should return false for nil error")
+	}
+}
+
+func TestCircuitOpenError(t *testing.T) {
+	t.Parallel()
+
+	retryAt := time.Unix(1700000030, 0)
+	err := NewCircuitOpenError("api.z.ai", retryAt)
+	if err.Error() != "circuit breaker is open for api.z.ai" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Host != "api.z.ai" || !err.RetryAt.Equal(retryAt) {
+		t.Errorf("unexpected fields: %+v", err)
+	}
+
+	if !IsCircuitOpenError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsCircuitOpenError should return true for a wrapped CircuitOpenError")
+	}
+
+	if IsCircuitOpenError(nil) {
+		t.Error("IsCircuitOpenError should return false for nil error")
+	}
+}
+
+func TestRequestBlockedError(t *testing.T) {
+	t.Parallel()
+
+	cause := errors.New("prompt contains a customer ID")
+	err := NewRequestBlockedError("chat", cause)
+	if err.Error() != "chat request blocked by sanitizer: prompt contains a customer ID" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Service != "chat" {
+		t.Errorf("Service = %q, want chat", err.Service)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("RequestBlockedError should unwrap to the sanitizer error")
+	}
+
+	if !IsRequestBlockedError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsRequestBlockedError should return true for a wrapped RequestBlockedError")
+	}
+
+	if IsRequestBlockedError(cause) {
+		t.Error("IsRequestBlockedError should return false for other errors")
+	}
+}
+
+func TestContentFlaggedError(t *testing.T) {
+	t.Parallel()
+
+	scores := map[string]float64{"violence": 0.9, "hate": 0.7, "sexual": 0.01}
+	err := NewContentFlaggedError("output", []string{"hate", "violence"}, scores)
+	if err.Error() != "output flagged by moderation: hate, violence" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Stage != "output" || len(err.Categories) != 2 || err.Scores["violence"] != 0.9 {
+		t.Errorf("unexpected fields: %+v", err)
+	}
+
+	if !IsContentFlaggedError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsContentFlaggedError should return true for a wrapped ContentFlaggedError")
+	}
+
+	if IsContentFlaggedError(errors.New("other")) {
+		t.Error("IsContentFlaggedError should return false for other errors")
+	}
+}
+
+func TestPingError(t *testing.T) {
+	t.Parallel()
+
+	cause := NewAPIAuthenticationError("invalid API key", 401, nil)
+	err := NewPingError(PingFailureAuth, 401, cause)
+	if err.Error() != "health check failed (auth): API error (status 401): invalid API key" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	wrapped := fmt.Errorf("startup: %w", err)
+	if !IsPingError(wrapped) {
+		t.Error("IsPingError should return true for a wrapped PingError")
+	}
+	if !IsAuthenticationError(wrapped) {
+		t.Error("IsAuthenticationError should see the error of the request")
+	}
+
+	if IsPingError(cause) {
+		t.Error("IsPingError should return false for other errors")
+	}
+}
+
+func TestContentPolicyError(t *testing.T) {
+	t.Parallel()
+
+	err := NewContentPolicyError("unsafe content", 400, nil)
+	err.Code = "1301"
+	err.Field = "prompt"
+	if err.Error() != "API error (status 400, code 1301): unsafe content" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+
+	wrapped := fmt.Errorf("generate: %w", err)
+	if !IsContentPolicyError(wrapped) {
+		t.Error("IsContentPolicyError should return true for a wrapped ContentPolicyError")
+	}
+	if !IsRequestError(wrapped) {
+		t.Error("IsRequestError should return true for a ContentPolicyError")
+	}
+
+	if IsContentPolicyError(NewAPIRequestFailedError("bad request", 400, nil)) {
+		t.Error("IsContentPolicyError should return false for other request errors")
+	}
+}
+
+func TestEmptyResultError(t *testing.T) {
+	t.Parallel()
+
+	err := NewEmptyResultError("Images.Generate", "image")
+	if err.Error() != "Images.Generate: the API returned no image" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if err.Operation != "Images.Generate" || err.Result != "image" {
+		t.Errorf("unexpected fields: %+v", err)
+	}
+
+	if !IsEmptyResultError(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsEmptyResultError should return true for a wrapped EmptyResultError")
+	}
+
+	if IsEmptyResultError(NewValidationError("model", "is required", nil)) {
+		t.Error("IsEmptyResultError should return false for other errors")
+	}
+}
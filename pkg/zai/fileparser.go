@@ -1,16 +1,25 @@
 package zai
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
-	"mime/multipart"
+	"strings"
+	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
 )
 
+// fileParserInitialPollDelay is the default first delay of
+// WaitForContent, which doubles on every poll up to the poll interval.
+const fileParserInitialPollDelay = 250 * time.Millisecond
+
 // FileParserService provides access to the File Parser API.
 type FileParserService struct {
 	client *client.BaseClient
@@ -25,6 +34,9 @@ func newFileParserService(baseClient *client.BaseClient) *FileParserService {
 
 // Create creates an asynchronous file parsing task.
 //
+// The request is checked with CreateRequest.Validate and the file size
+// limit is enforced before anything is uploaded.
+//
 // Example:
 //
 //	file, err := os.Open("document.pdf")
@@ -50,38 +62,18 @@ func newFileParserService(baseClient *client.BaseClient) *FileParserService {
 //	    // Use the TaskID to retrieve results later with Content()
 //	}
 func (s *FileParserService) Create(ctx context.Context, req *fileparser.CreateRequest) (*fileparser.CreateResponse, error) {
-	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add the file_type field (required)
-	if err := writer.WriteField("file_type", req.FileType); err != nil {
-		return nil, fmt.Errorf("failed to write file_type field: %w", err)
-	}
-
-	// Add the tool_type field (required)
-	if err := writer.WriteField("tool_type", string(req.ToolType)); err != nil {
-		return nil, fmt.Errorf("failed to write tool_type field: %w", err)
-	}
-
-	// Add the file
-	part, err := writer.CreateFormFile("file", req.FileName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	// Copy file content to the form
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Close the writer to finalize the multipart message
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Make the API request using PostMultipart
-	apiResp, err := s.client.PostMultipart(ctx, "/files/parser/create", &buf, writer.FormDataContentType())
+	apiResp, err := s.postParserForm(ctx, s.client.Endpoints().Path(endpoints.FileParserCreate), &parserForm{
+		file:     req.File,
+		fileName: req.FileName,
+		fileType: req.FileType,
+		toolType: req.ToolType,
+		url:      req.URL,
+		maxSize:  req.FileSizeLimit(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -125,13 +117,14 @@ func (s *FileParserService) Create(ctx context.Context, req *fileparser.CreateRe
 //	}
 func (s *FileParserService) Content(ctx context.Context, req *fileparser.ContentRequest) (*fileparser.ContentResponse, error) {
 	// Build the path
-	path := fmt.Sprintf("/files/parser/result/%s/%s", req.TaskID, req.FormatType)
+	path := s.client.Endpoints().Path(endpoints.FileParserResult, req.TaskID, req.FormatType)
 
 	// Make the API request
 	apiResp, err := s.client.Get(ctx, path, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer apiResp.Close()
 
 	// Read the response body
 	data, err := io.ReadAll(apiResp.Body)
@@ -141,6 +134,16 @@ func (s *FileParserService) Content(ctx context.Context, req *fileparser.Content
 
 	resp := &fileparser.ContentResponse{}
 
+	// A JSON body with a status describes the task rather than the result,
+	// for example while it is still processing
+	if strings.Contains(apiResp.Headers.Get("Content-Type"), "application/json") {
+		var status fileparser.TaskStatusResponse
+		if json.Unmarshal(data, &status) == nil {
+			resp.Status = status.Status
+			resp.Message = status.Message
+		}
+	}
+
 	// Check the format type to determine how to handle the response
 	if req.FormatType == fileparser.FormatTypeText {
 		// For text format, store as string
@@ -153,8 +156,94 @@ func (s *FileParserService) Content(ctx context.Context, req *fileparser.Content
 	return resp, nil
 }
 
+// WaitForContent polls a parsing task until its result is ready and
+// returns it. Polling starts quickly and backs off exponentially up to
+// pollInterval; opts change the schedule. Defaults are used for zero
+// pollInterval (5s) and timeout (5m).
+//
+// A task that fails returns a *fileparser.TaskFailedError, and a task that
+// is still processing after timeout returns a *fileparser.TimeoutError with
+// the last reported status.
+//
+// Example:
+//
+//	resp, err := client.FileParser.WaitForContent(ctx, taskID, fileparser.FormatTypeText, 5*time.Second, 2*time.Minute)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Parsed text: %s\n", resp.GetContent())
+func (s *FileParserService) WaitForContent(ctx context.Context, taskID string, format fileparser.FormatType, pollInterval, timeout time.Duration, opts ...PollOption) (*fileparser.ContentResponse, error) {
+	if pollInterval == 0 {
+		pollInterval = 5 * time.Second
+	}
+
+	if timeout == 0 {
+		timeout = 5 * time.Minute
+	}
+
+	req := fileparser.NewContentRequest(taskID, format)
+	var resp *fileparser.ContentResponse
+	poller := newPoller(s.client, poll.Config{
+		InitialInterval: fileParserInitialPollDelay,
+		MaxInterval:     pollInterval,
+		Timeout:         timeout,
+	}, opts)
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if resp, err = s.Content(ctx, req); err != nil {
+			return false, err
+		}
+		if resp.IsFailed() {
+			return false, &fileparser.TaskFailedError{TaskID: taskID, Message: resp.Message}
+		}
+		return !resp.IsProcessing(), nil
+	})
+	if stderrors.Is(err, poll.ErrTimeout) {
+		return nil, &fileparser.TimeoutError{
+			TaskID:  taskID,
+			Status:  resp.Status,
+			Message: resp.Message,
+			Timeout: timeout,
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ParseAndWait creates an asynchronous parsing task and waits for its
+// result with WaitForContent, passing it opts.
+//
+// Example:
+//
+//	req := fileparser.NewCreateRequestFromURL("https://example.com/report.pdf", fileparser.ToolTypePrime)
+//
+//	resp, err := client.FileParser.ParseAndWait(ctx, req, fileparser.FormatTypeText, 5*time.Second, 2*time.Minute)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Parsed text: %s\n", resp.GetContent())
+func (s *FileParserService) ParseAndWait(ctx context.Context, req *fileparser.CreateRequest, format fileparser.FormatType, pollInterval, timeout time.Duration, opts ...PollOption) (*fileparser.ContentResponse, error) {
+	created, err := s.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !created.Success || created.TaskID == "" {
+		return nil, fmt.Errorf("failed to create file parsing task: %s", created.Message)
+	}
+
+	return s.WaitForContent(ctx, created.TaskID, format, pollInterval, timeout, opts...)
+}
+
 // CreateSync creates a synchronous file parsing task and returns the result immediately.
 //
+// The request is checked with SyncRequest.Validate and the file size limit
+// is enforced before anything is uploaded.
+//
 // Example:
 //
 //	file, err := os.Open("document.docx")
@@ -175,38 +264,17 @@ func (s *FileParserService) Content(ctx context.Context, req *fileparser.Content
 //	    fmt.Printf("Download URL: %s\n", resp.GetDownloadURL())
 //	}
 func (s *FileParserService) CreateSync(ctx context.Context, req *fileparser.SyncRequest) (*fileparser.SyncResponse, error) {
-	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
-
-	// Add the file_type field (required)
-	if err := writer.WriteField("file_type", req.FileType); err != nil {
-		return nil, fmt.Errorf("failed to write file_type field: %w", err)
-	}
-
-	// Add the tool_type field (must be "prime-sync")
-	if err := writer.WriteField("tool_type", string(req.ToolType)); err != nil {
-		return nil, fmt.Errorf("failed to write tool_type field: %w", err)
-	}
-
-	// Add the file
-	part, err := writer.CreateFormFile("file", req.FileName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	// Copy file content to the form
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Close the writer to finalize the multipart message
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	if err := req.Validate(); err != nil {
+		return nil, err
 	}
 
-	// Make the API request using PostMultipart
-	apiResp, err := s.client.PostMultipart(ctx, "/files/parser/sync", &buf, writer.FormDataContentType())
+	apiResp, err := s.postParserForm(ctx, s.client.Endpoints().Path(endpoints.FileParserSync), &parserForm{
+		file:     req.File,
+		fileName: req.FileName,
+		fileType: req.FileType,
+		toolType: req.ToolType,
+		maxSize:  req.FileSizeLimit(),
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -219,3 +287,46 @@ func (s *FileParserService) CreateSync(ctx context.Context, req *fileparser.Sync
 
 	return &resp, nil
 }
+
+// parserForm holds the multipart fields of a parsing task.
+type parserForm struct {
+	file     io.Reader
+	fileName string
+	fileType string
+	toolType fileparser.ToolType
+	url      string
+
+	// maxSize is the upload limit in bytes, or zero for no limit.
+	maxSize int64
+}
+
+// postParserForm uploads a parsing task as multipart form data. A file
+// over the size limit is rejected without being uploaded when its size is
+// known in advance, as for seekable and small files.
+func (s *FileParserService) postParserForm(ctx context.Context, path string, form *parserForm) (*models.APIResponse, error) {
+	// Create multipart form data
+	body := client.NewForm()
+
+	// Add the file_type and tool_type fields (required)
+	body.WriteField("file_type", form.fileType)
+	body.WriteField("tool_type", string(form.toolType))
+
+	if form.url != "" {
+		// Let the service fetch the remote file
+		body.WriteField("file_url", form.url)
+	} else {
+		// Add the file
+		body.AddFile(&client.FormFile{
+			Field:    "file",
+			Filename: form.fileName,
+			Reader:   form.file,
+			MaxSize:  form.maxSize,
+			TooLarge: func(size int64) error {
+				return fileparser.NewFileTooLargeError(size, form.maxSize)
+			},
+		})
+	}
+
+	// Make the API request
+	return s.client.PostForm(ctx, path, body)
+}
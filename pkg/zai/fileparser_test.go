@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -275,3 +278,239 @@ func TestFileParserService_CreateSync_EmptyContent(t *testing.T) {
 	assert.Equal(t, "", resp.GetContent())
 	assert.Equal(t, "", resp.GetDownloadURL())
 }
+
+func TestFileParserService_Create_FromURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+
+		assert.Equal(t, "pdf", r.FormValue("file_type"))
+		assert.Equal(t, "prime", r.FormValue("tool_type"))
+		assert.Equal(t, "https://example.com/docs/report.pdf", r.FormValue("file_url"))
+
+		_, _, err = r.FormFile("file")
+		assert.ErrorIs(t, err, http.ErrMissingFile)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(fileparser.CreateResponse{TaskID: "task_url", Success: true})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := fileparser.NewCreateRequestFromURL("https://example.com/docs/report.pdf", fileparser.ToolTypePrime)
+
+	resp, err := client.FileParser.Create(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "task_url", resp.TaskID)
+}
+
+func TestFileParserService_Create_Validation(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(fileparser.CreateResponse{TaskID: "task_ok", Success: true})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	t.Run("type mismatch", func(t *testing.T) {
+		req := fileparser.NewCreateRequest(strings.NewReader("data"), "report.docx", "pdf", fileparser.ToolTypePrime)
+
+		_, err := client.FileParser.Create(context.Background(), req)
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "file_type", validationErr.Field)
+	})
+
+	t.Run("type mismatch skipped", func(t *testing.T) {
+		req := fileparser.NewCreateRequest(strings.NewReader("data"), "export.bin", "pdf", fileparser.ToolTypePrime).
+			SetSkipTypeCheck(true)
+
+		_, err := client.FileParser.Create(context.Background(), req)
+		require.NoError(t, err)
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		req := fileparser.NewCreateRequest(strings.NewReader("data"), "page.html", "html", fileparser.ToolTypeExpert)
+
+		_, err := client.FileParser.Create(context.Background(), req)
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, err.Error(), "not supported by expert")
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		req := fileparser.NewCreateRequest(strings.NewReader("0123456789"), "notes.txt", "txt", fileparser.ToolTypeLite).
+			SetMaxFileSize(4)
+
+		_, err := client.FileParser.Create(context.Background(), req)
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, err.Error(), "maximum size of 4 bytes")
+	})
+
+	t.Run("sync type mismatch", func(t *testing.T) {
+		req := fileparser.NewSyncRequest(strings.NewReader("data"), "scan.png", "pdf")
+
+		_, err := client.FileParser.CreateSync(context.Background(), req)
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+	})
+
+	assert.Equal(t, int32(1), requests.Load(), "only the skipped type check should reach the server")
+}
+
+// newParserResultServer serves the given result endpoint bodies in order,
+// repeating the last one.
+func newParserResultServer(t *testing.T, bodies ...string) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/files/parser/create":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(fileparser.CreateResponse{TaskID: "task_wait", Success: true})
+		case "/files/parser/result/task_wait/text":
+			n := int(calls.Add(1))
+			body := bodies[min(n, len(bodies))-1]
+			if strings.HasPrefix(body, "{") {
+				w.Header().Set("Content-Type", "application/json")
+			} else {
+				w.Header().Set("Content-Type", "text/plain")
+			}
+			w.Write([]byte(body))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestFileParserService_WaitForContent(t *testing.T) {
+	t.Parallel()
+
+	const processing = `{"task_id":"task_wait","status":"processing","message":"parsing"}`
+
+	newClient := func(t *testing.T, server *httptest.Server) *Client {
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+		return client
+	}
+
+	t.Run("ready after processing", func(t *testing.T) {
+		t.Parallel()
+
+		server, calls := newParserResultServer(t, processing, processing, "Parsed document text")
+		client := newClient(t, server)
+
+		resp, err := client.FileParser.WaitForContent(context.Background(), "task_wait", fileparser.FormatTypeText, 20*time.Millisecond, 5*time.Second)
+		require.NoError(t, err)
+		assert.Equal(t, "Parsed document text", resp.GetContent())
+		assert.False(t, resp.IsProcessing())
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newParserResultServer(t, processing, `{"task_id":"task_wait","status":"failed","message":"unreadable file"}`)
+		client := newClient(t, server)
+
+		_, err := client.FileParser.WaitForContent(context.Background(), "task_wait", fileparser.FormatTypeText, 20*time.Millisecond, 5*time.Second)
+		var failedErr *fileparser.TaskFailedError
+		require.ErrorAs(t, err, &failedErr)
+		assert.Equal(t, "unreadable file", failedErr.Message)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newParserResultServer(t, processing)
+		client := newClient(t, server)
+
+		_, err := client.FileParser.WaitForContent(context.Background(), "task_wait", fileparser.FormatTypeText, 20*time.Millisecond, 100*time.Millisecond)
+		var timeoutErr *fileparser.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, "task_wait", timeoutErr.TaskID)
+		assert.Equal(t, fileparser.TaskStatusProcessing, timeoutErr.Status)
+		assert.Equal(t, "parsing", timeoutErr.Message)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		server, _ := newParserResultServer(t, processing)
+		client := newClient(t, server)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.FileParser.WaitForContent(ctx, "task_wait", fileparser.FormatTypeText, time.Second, time.Minute)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		var timeoutErr *fileparser.TimeoutError
+		assert.NotErrorAs(t, err, &timeoutErr)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"1002","message":"task not found"}}`))
+		}))
+		defer server.Close()
+		client := newClient(t, server)
+
+		_, err := client.FileParser.WaitForContent(context.Background(), "task_wait", fileparser.FormatTypeText, 20*time.Millisecond, time.Second)
+		var apiErr *errors.APIStatusError
+		assert.ErrorAs(t, err, &apiErr)
+	})
+}
+
+func TestFileParserService_ParseAndWait(t *testing.T) {
+	t.Parallel()
+
+	server, calls := newParserResultServer(t,
+		`{"task_id":"task_wait","status":"processing"}`,
+		`{"task_id":"task_wait","status":"processing"}`,
+		"Parsed document text",
+	)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := fileparser.NewCreateRequest(strings.NewReader("pdf data"), "report.pdf", "pdf", fileparser.ToolTypePrime)
+
+	resp, err := client.FileParser.ParseAndWait(context.Background(), req, fileparser.FormatTypeText, 20*time.Millisecond, 5*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "Parsed document text", resp.GetContent())
+	assert.Equal(t, int32(3), calls.Load())
+}
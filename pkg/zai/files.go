@@ -1,14 +1,16 @@
 package zai
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
-	"mime/multipart"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/pagination"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // FilesService provides access to the Files API.
@@ -25,6 +27,11 @@ func newFilesService(baseClient *client.BaseClient) *FilesService {
 
 // Upload uploads a file to the API.
 //
+// The request is checked with FileUploadRequest.Validate, so a file whose
+// extension, size or JSONL lines do not suit its purpose fails with a
+// *errors.ValidationError before it is sent. SetSkipValidation turns the
+// checks off.
+//
 // Example:
 //
 //	file, err := os.Open("training_data.jsonl")
@@ -41,33 +48,30 @@ func newFilesService(baseClient *client.BaseClient) *FilesService {
 //
 //	fmt.Printf("Uploaded file ID: %s\n", uploadedFile.ID)
 func (s *FilesService) Upload(ctx context.Context, req *files.FileUploadRequest) (*files.File, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	form := client.NewForm()
 
 	// Add the purpose field
-	if err := writer.WriteField("purpose", string(req.Purpose)); err != nil {
-		return nil, fmt.Errorf("failed to write purpose field: %w", err)
-	}
+	form.WriteField("purpose", string(req.Purpose))
 
 	// Add the file
-	part, err := writer.CreateFormFile("file", req.Filename)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
+	maxSize := req.FileSizeLimit()
+	form.AddFile(&client.FormFile{
+		Field:    "file",
+		Filename: req.Filename,
+		Reader:   req.File,
+		MaxSize:  maxSize,
+		TooLarge: func(size int64) error {
+			return files.NewFileTooLargeError(size, maxSize)
+		},
+	})
 
-	// Copy file content to the form
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
-	}
-
-	// Close the writer to finalize the multipart message
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
-	}
-
-	// Make the API request using PostMultipart
-	apiResp, err := s.client.PostMultipart(ctx, "/files", &buf, writer.FormDataContentType())
+	// Make the API request
+	apiResp, err := s.client.PostForm(s.client.Idempotent(ctx), s.client.Endpoints().Path(endpoints.Files), form)
 	if err != nil {
 		return nil, err
 	}
@@ -81,11 +85,15 @@ func (s *FilesService) Upload(ctx context.Context, req *files.FileUploadRequest)
 	return &file, nil
 }
 
-// List retrieves a list of files.
+// List retrieves a page of files. Options set the cursor, page size and
+// purpose filter; without options the API defaults are used.
 //
 // Example:
 //
-//	fileList, err := client.Files.List(ctx)
+//	fileList, err := client.Files.List(ctx,
+//	    files.WithPurpose(files.PurposeBatch),
+//	    files.WithLimit(20),
+//	)
 //	if err != nil {
 //	    // Handle error
 //	}
@@ -93,9 +101,15 @@ func (s *FilesService) Upload(ctx context.Context, req *files.FileUploadRequest)
 //	for _, file := range fileList.GetFiles() {
 //	    fmt.Printf("File: %s (%s)\n", file.Filename, file.ID)
 //	}
-func (s *FilesService) List(ctx context.Context) (*files.FileListResponse, error) {
+func (s *FilesService) List(ctx context.Context, opts ...files.ListOption) (*files.FileListResponse, error) {
+	params := files.NewListParams(opts...)
+	return s.list(ctx, params)
+}
+
+// list fetches one page of files.
+func (s *FilesService) list(ctx context.Context, params *files.ListParams) (*files.FileListResponse, error) {
 	// Make the API request
-	apiResp, err := s.client.Get(ctx, "/files", nil)
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.Files), params.Query())
 	if err != nil {
 		return nil, err
 	}
@@ -109,6 +123,40 @@ func (s *FilesService) List(ctx context.Context) (*files.FileListResponse, error
 	return &resp, nil
 }
 
+// ListAutoPaging returns an iterator over all files, fetching pages lazily
+// as the iteration advances. files.WithAfter sets the starting cursor and
+// files.WithLimit the page size.
+//
+// Example:
+//
+//	iter := client.Files.ListAutoPaging(ctx, files.WithPurpose(files.PurposeFineTune))
+//	for iter.Next() {
+//	    file := iter.Current()
+//	    fmt.Printf("File: %s (%s)\n", file.Filename, file.ID)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *FilesService) ListAutoPaging(ctx context.Context, opts ...files.ListOption) *pagination.Iterator[files.File] {
+	params := files.NewListParams(opts...)
+
+	return pagination.NewIterator(ctx, params.After, func(ctx context.Context, cursor string) (*pagination.Page[files.File], error) {
+		pageParams := *params
+		pageParams.After = cursor
+
+		resp, err := s.list(ctx, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[files.File]{
+			Items:   resp.Data,
+			HasMore: resp.HasMore,
+			Cursor:  resp.GetLastID(),
+		}, nil
+	})
+}
+
 // Retrieve retrieves information about a specific file.
 //
 // Example:
@@ -121,7 +169,7 @@ func (s *FilesService) List(ctx context.Context) (*files.FileListResponse, error
 //	fmt.Printf("File: %s, Size: %d bytes\n", file.Filename, file.Bytes)
 func (s *FilesService) Retrieve(ctx context.Context, fileID string) (*files.File, error) {
 	// Make the API request
-	path := fmt.Sprintf("/files/%s", fileID)
+	path := s.client.Endpoints().Path(endpoints.File, fileID)
 	apiResp, err := s.client.Get(ctx, path, nil)
 	if err != nil {
 		return nil, err
@@ -150,7 +198,7 @@ func (s *FilesService) Retrieve(ctx context.Context, fileID string) (*files.File
 //	}
 func (s *FilesService) Delete(ctx context.Context, fileID string) (*files.FileDeleteResponse, error) {
 	// Make the API request
-	path := fmt.Sprintf("/files/%s", fileID)
+	path := s.client.Endpoints().Path(endpoints.File, fileID)
 	apiResp, err := s.client.Delete(ctx, path)
 	if err != nil {
 		return nil, err
@@ -165,7 +213,9 @@ func (s *FilesService) Delete(ctx context.Context, fileID string) (*files.FileDe
 	return &resp, nil
 }
 
-// RetrieveContent retrieves the content of a file.
+// RetrieveContent retrieves the content of a file into memory. The content
+// is limited like other responses, see WithMaxResponseBytes; use
+// StreamContent or DownloadTo for large files such as batch output.
 //
 // Example:
 //
@@ -176,12 +226,11 @@ func (s *FilesService) Delete(ctx context.Context, fileID string) (*files.FileDe
 //
 //	fmt.Printf("File content:\n%s\n", content.String())
 func (s *FilesService) RetrieveContent(ctx context.Context, fileID string) (*files.FileContentResponse, error) {
-	// Make the API request
-	path := fmt.Sprintf("/files/%s/content", fileID)
-	apiResp, err := s.client.Get(ctx, path, nil)
+	apiResp, err := s.openContent(ctx, fileID)
 	if err != nil {
 		return nil, err
 	}
+	defer apiResp.Close()
 
 	// Read the response body
 	content, err := io.ReadAll(apiResp.Body)
@@ -194,3 +243,99 @@ func (s *FilesService) RetrieveContent(ctx context.Context, fileID string) (*fil
 		ContentType: apiResp.Headers.Get("Content-Type"),
 	}, nil
 }
+
+// StreamContent returns the content of a file for incremental reading,
+// with its content type and length. The body is not buffered, and it is
+// limited only by WithMaxStreamBytes, so files of any size can be read
+// with constant memory. The caller must close the body, which releases
+// the connection; closing the client also closes it.
+//
+// Example:
+//
+//	body, info, err := client.Files.StreamContent(ctx, "file-abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer body.Close()
+//
+//	fmt.Printf("Streaming %d bytes of %s\n", info.ContentLength, info.ContentType)
+//	scanner := bufio.NewScanner(body)
+//	for scanner.Scan() {
+//	    // Process a line
+//	}
+func (s *FilesService) StreamContent(ctx context.Context, fileID string) (io.ReadCloser, *files.ContentInfo, error) {
+	if fileID == "" {
+		return nil, nil, errors.NewValidationError("file_id", "is required", fileID)
+	}
+
+	apiResp, err := s.client.Download(ctx, s.client.Endpoints().Path(endpoints.FileContent, fileID))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info := &files.ContentInfo{
+		ContentType:   apiResp.Headers.Get("Content-Type"),
+		ContentLength: apiResp.HTTPResponse.ContentLength,
+	}
+	return apiResp.Body, info, nil
+}
+
+// DownloadTo streams the content of a file to w and returns the number of
+// bytes written. files.WithProgress sets a callback called after each
+// chunk, and files.WithBufferSize the chunk size. The content is never
+// held in memory beyond one chunk.
+//
+// Example:
+//
+//	out, err := os.Create("output.jsonl")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer out.Close()
+//
+//	n, err := client.Files.DownloadTo(ctx, batchJob.OutputFileID, out,
+//	    files.WithProgress(func(p files.DownloadProgress) {
+//	        fmt.Printf("\r%d/%d bytes", p.Written, p.Total)
+//	    }),
+//	)
+func (s *FilesService) DownloadTo(ctx context.Context, fileID string, w io.Writer, opts ...files.DownloadOption) (int64, error) {
+	cfg := files.NewDownloadConfig(opts...)
+
+	body, info, err := s.StreamContent(ctx, fileID)
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	progress := files.DownloadProgress{Total: info.ContentLength}
+	buf := make([]byte, cfg.BufferSize)
+	for {
+		n, readErr := body.Read(buf)
+		if n > 0 {
+			written, err := w.Write(buf[:n])
+			progress.Written += int64(written)
+			if err == nil && written < n {
+				err = io.ErrShortWrite
+			}
+			if err != nil {
+				return progress.Written, fmt.Errorf("failed to write file content: %w", err)
+			}
+			if cfg.OnProgress != nil {
+				cfg.OnProgress(progress)
+			}
+		}
+		if readErr == io.EOF {
+			return progress.Written, nil
+		}
+		if readErr != nil {
+			return progress.Written, fmt.Errorf("failed to read file content: %w", readErr)
+		}
+	}
+}
+
+// openContent requests the content of a file. The caller must close the
+// response.
+func (s *FilesService) openContent(ctx context.Context, fileID string) (*models.APIResponse, error) {
+	path := s.client.Endpoints().Path(endpoints.FileContent, fileID)
+	return s.client.Get(ctx, path, nil)
+}
@@ -3,15 +3,21 @@ package zai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	filestypes "github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestFilesService_Upload(t *testing.T) {
@@ -43,7 +49,7 @@ func TestFilesService_Upload(t *testing.T) {
 			// Read file content
 			content, err := io.ReadAll(file)
 			assert.NoError(t, err)
-			assert.Contains(t, string(content), "test data")
+			assert.Contains(t, string(content), `"messages"`)
 			file.Close()
 
 			// Send response
@@ -72,7 +78,7 @@ func TestFilesService_Upload(t *testing.T) {
 		defer client.Close()
 
 		// Upload file
-		fileContent := strings.NewReader("test data")
+		fileContent := strings.NewReader(`{"messages":[{"role":"user","content":"Hi"},{"role":"assistant","content":"Hello"}]}`)
 		req := filestypes.NewFileUploadRequest(fileContent, "training.jsonl", filestypes.PurposeFineTune)
 
 		uploadedFile, err := client.Files.Upload(context.Background(), req)
@@ -107,7 +113,8 @@ func TestFilesService_Upload(t *testing.T) {
 		defer client.Close()
 
 		fileContent := strings.NewReader("invalid data")
-		req := filestypes.NewFileUploadRequest(fileContent, "bad.txt", filestypes.PurposeFineTune)
+		req := filestypes.NewFileUploadRequest(fileContent, "bad.txt", filestypes.PurposeFineTune).
+			SetSkipValidation(true)
 
 		uploadedFile, err := client.Files.Upload(context.Background(), req)
 		assert.Error(t, err)
@@ -224,6 +231,82 @@ func TestFilesService_List(t *testing.T) {
 	})
 }
 
+func TestFilesService_List_Params(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "file-9", query.Get("after"))
+		assert.Equal(t, "5", query.Get("limit"))
+		assert.Equal(t, "batch", query.Get("purpose"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(filestypes.FileListResponse{Object: "list"})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Files.List(context.Background(),
+		filestypes.WithAfter("file-9"),
+		filestypes.WithLimit(5),
+		filestypes.WithPurpose(filestypes.PurposeBatch),
+	)
+	require.NoError(t, err)
+}
+
+func TestFilesService_ListAutoPaging(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]filestypes.FileListResponse{
+		"":       {Data: []filestypes.File{{ID: "file-1"}, {ID: "file-2"}}, HasMore: true},
+		"file-2": {Data: []filestypes.File{{ID: "file-3"}, {ID: "file-4"}}, HasMore: true},
+		"file-4": {Data: []filestypes.File{{ID: "file-5"}}, HasMore: false},
+	}
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		assert.Equal(t, "/files", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		assert.Equal(t, "fine-tune", r.URL.Query().Get("purpose"))
+
+		page, ok := pages[r.URL.Query().Get("after")]
+		assert.True(t, ok, "unexpected cursor %q", r.URL.Query().Get("after"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	iter := client.Files.ListAutoPaging(context.Background(),
+		filestypes.WithLimit(2),
+		filestypes.WithPurpose(filestypes.PurposeFineTune),
+	)
+	assert.Equal(t, int32(0), calls.Load(), "no request before Next")
+
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Current().ID)
+	}
+	require.NoError(t, iter.Err())
+
+	assert.Equal(t, []string{"file-1", "file-2", "file-3", "file-4", "file-5"}, ids)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
 func TestFilesService_Retrieve(t *testing.T) {
 	t.Parallel()
 
@@ -442,6 +525,160 @@ func TestFilesService_RetrieveContent(t *testing.T) {
 	})
 }
 
+// patternByte is the byte at offset i of a synthetic file.
+func patternByte(i int64) byte {
+	return byte(i * 31 % 251)
+}
+
+// patternReader generates a synthetic file without holding it in memory.
+type patternReader struct {
+	offset int64
+}
+
+func (r *patternReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = patternByte(r.offset)
+		r.offset++
+	}
+	return len(p), nil
+}
+
+// patternWriter checks that it is written a synthetic file.
+type patternWriter struct {
+	written int64
+	err     error
+}
+
+func (w *patternWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if w.err == nil && b != patternByte(w.written) {
+			w.err = fmt.Errorf("byte %d is %d, want %d", w.written, b, patternByte(w.written))
+		}
+		w.written++
+	}
+	return len(p), nil
+}
+
+// newContentServer serves a synthetic file of size bytes.
+func newContentServer(t *testing.T, size int64) (*Client, *atomic.Int32) {
+	var aborted atomic.Int32
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files/file-big/content", r.URL.Path)
+		w.Header().Set("Content-Type", "application/jsonl")
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+		if _, err := io.CopyN(w, &patternReader{}, size); err != nil {
+			aborted.Add(1)
+		}
+	})
+	return client, &aborted
+}
+
+func TestFilesService_DownloadTo_BoundedMemory(t *testing.T) {
+	// Not parallel, so that the allocations measured are the download's
+	const size = 50 << 20
+	client, _ := newContentServer(t, size)
+
+	var chunks int
+	var last filestypes.DownloadProgress
+	out := &patternWriter{}
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	n, err := client.Files.DownloadTo(context.Background(), "file-big", out,
+		filestypes.WithBufferSize(64<<10),
+		filestypes.WithProgress(func(p filestypes.DownloadProgress) {
+			assert.LessOrEqual(t, p.Written-last.Written, int64(64<<10), "chunks are at most the buffer size")
+			last = p
+			chunks++
+		}),
+	)
+	runtime.ReadMemStats(&after)
+
+	require.NoError(t, err)
+	require.NoError(t, out.err)
+	assert.EqualValues(t, size, n)
+	assert.EqualValues(t, size, out.written)
+	assert.Equal(t, filestypes.DownloadProgress{Written: size, Total: size}, last)
+	assert.GreaterOrEqual(t, chunks, size/(64<<10))
+
+	allocated := after.TotalAlloc - before.TotalAlloc
+	assert.Less(t, allocated, uint64(4<<20), "downloading %d bytes allocated %d bytes", size, allocated)
+}
+
+func TestFilesService_StreamContent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("larger than the response limit", func(t *testing.T) {
+		t.Parallel()
+
+		const size = 50 << 20
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/jsonl")
+			w.Header().Set("Content-Length", strconv.Itoa(size))
+			io.CopyN(w, &patternReader{}, size)
+		}))
+		t.Cleanup(server.Close)
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+			WithMaxResponseBytes(1<<20),
+		)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+
+		body, info, err := client.Files.StreamContent(context.Background(), "file-big")
+		require.NoError(t, err)
+		defer body.Close()
+		assert.Equal(t, &filestypes.ContentInfo{ContentType: "application/jsonl", ContentLength: size}, info)
+
+		out := &patternWriter{}
+		_, err = io.Copy(out, body)
+		require.NoError(t, err)
+		require.NoError(t, out.err)
+		assert.EqualValues(t, size, out.written)
+
+		// Buffering the same file in memory is refused
+		_, err = client.Files.RetrieveContent(context.Background(), "file-big")
+		assert.True(t, errors.IsPayloadTooLargeError(err))
+	})
+
+	t.Run("close releases the connection", func(t *testing.T) {
+		t.Parallel()
+
+		client, aborted := newContentServer(t, 50<<20)
+		body, _, err := client.Files.StreamContent(context.Background(), "file-big")
+		require.NoError(t, err)
+
+		_, err = io.CopyN(io.Discard, body, 1<<20)
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+
+		assert.Eventually(t, func() bool { return aborted.Load() == 1 }, 5*time.Second, 10*time.Millisecond,
+			"the server stops sending once the body is closed")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			writeJSON(w, map[string]any{"error": map[string]any{"message": "File not found"}})
+		})
+
+		_, _, err := client.Files.StreamContent(context.Background(), "file-missing")
+		var statusErr *errors.APIStatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+
+		_, err = client.Files.DownloadTo(context.Background(), "file-missing", io.Discard)
+		assert.ErrorAs(t, err, &statusErr)
+
+		_, _, err = client.Files.StreamContent(context.Background(), "")
+		assert.True(t, errors.IsValidationError(err))
+	})
+}
+
 func TestClient_FilesService_Integration(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,252 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/finetuning"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
+)
+
+// FineTuningService provides access to the Fine-tuning API.
+type FineTuningService struct {
+	client *client.BaseClient
+}
+
+// newFineTuningService creates a new fine-tuning service.
+func newFineTuningService(baseClient *client.BaseClient) *FineTuningService {
+	return &FineTuningService{
+		client: baseClient,
+	}
+}
+
+// Create creates a new fine-tuning job.
+//
+// Example:
+//
+//	file, err := client.Files.Upload(ctx, files.NewFileUploadRequest(f, "train.jsonl", files.PurposeFineTune))
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	req := finetuning.NewJobCreateRequest("glm-4-flash", file.ID).
+//	    SetEpochs(3).
+//	    SetLearningRateMultiplier(1.0).
+//	    SetSuffix("support-bot")
+//
+//	job, err := client.FineTuning.Create(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//	fmt.Printf("Job ID: %s, Status: %s\n", job.ID, job.Status)
+func (s *FineTuningService) Create(ctx context.Context, req *finetuning.JobCreateRequest) (*finetuning.Job, error) {
+	// Make the API request
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.FineTuningJobs), req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp finetuning.Job
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Retrieve retrieves a fine-tuning job by ID.
+//
+// Example:
+//
+//	job, err := client.FineTuning.Retrieve(ctx, "ftjob-abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if job.IsSucceeded() {
+//	    fmt.Printf("Fine-tuned model: %s\n", job.FineTunedModel)
+//	}
+func (s *FineTuningService) Retrieve(ctx context.Context, jobID string) (*finetuning.Job, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("job ID cannot be empty")
+	}
+
+	// Make the API request
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.FineTuningJob, jobID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp finetuning.Job
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// List lists fine-tuning jobs with cursor-based pagination.
+//
+// Example:
+//
+//	// Get first page
+//	resp, err := client.FineTuning.List(ctx, "", 20)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, job := range resp.GetJobs() {
+//	    fmt.Printf("Job %s: %s\n", job.ID, job.Status)
+//	}
+//
+//	// Get next page if available
+//	if resp.HasMore {
+//	    nextResp, err := client.FineTuning.List(ctx, resp.LastID(), 20)
+//	    // Process next page...
+//	}
+func (s *FineTuningService) List(ctx context.Context, after string, limit int) (*finetuning.JobListResponse, error) {
+	// Build query parameters
+	query := make(map[string]string)
+	if after != "" {
+		query["after"] = after
+	}
+	if limit > 0 {
+		query["limit"] = fmt.Sprintf("%d", limit)
+	}
+
+	// Make the API request
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.FineTuningJobs), query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp finetuning.JobListResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Cancel cancels a running fine-tuning job.
+//
+// Example:
+//
+//	job, err := client.FineTuning.Cancel(ctx, "ftjob-abc123")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if job.IsCancelled() {
+//	    fmt.Println("Job has been cancelled")
+//	}
+func (s *FineTuningService) Cancel(ctx context.Context, jobID string) (*finetuning.Job, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("job ID cannot be empty")
+	}
+
+	// Make the API request
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.FineTuningCancel, jobID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp finetuning.Job
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListEvents lists training events for a fine-tuning job with cursor-based
+// pagination. Poll it with the last seen event ID to follow training progress.
+//
+// Example:
+//
+//	resp, err := client.FineTuning.ListEvents(ctx, "ftjob-abc123", "", 50)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, event := range resp.GetEvents() {
+//	    fmt.Printf("[%s] %s\n", event.Level, event.Message)
+//	}
+func (s *FineTuningService) ListEvents(ctx context.Context, jobID, after string, limit int) (*finetuning.EventListResponse, error) {
+	if jobID == "" {
+		return nil, fmt.Errorf("job ID cannot be empty")
+	}
+
+	// Build query parameters
+	query := make(map[string]string)
+	if after != "" {
+		query["after"] = after
+	}
+	if limit > 0 {
+		query["limit"] = fmt.Sprintf("%d", limit)
+	}
+
+	// Make the API request
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.FineTuningEvents, jobID), query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp finetuning.EventListResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// WaitForCompletion waits for a fine-tuning job to reach a terminal state.
+// It polls the job status with exponential backoff up to pollInterval
+// until it succeeds, fails or is cancelled; opts change the schedule.
+// Defaults are used for zero pollInterval (30s) and timeout (2h).
+//
+// Example:
+//
+//	job, err := client.FineTuning.WaitForCompletion(ctx, "ftjob-abc123", 30*time.Second, 2*time.Hour)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if job.IsSucceeded() {
+//	    fmt.Printf("Fine-tuned model: %s\n", job.FineTunedModel)
+//	}
+func (s *FineTuningService) WaitForCompletion(ctx context.Context, jobID string, pollInterval, timeout time.Duration, opts ...PollOption) (*finetuning.Job, error) {
+	if pollInterval == 0 {
+		pollInterval = 30 * time.Second
+	}
+
+	if timeout == 0 {
+		timeout = 2 * time.Hour
+	}
+
+	var job *finetuning.Job
+	poller := newPoller(s.client, poll.Config{MaxInterval: pollInterval, Timeout: timeout}, opts)
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if job, err = s.Retrieve(ctx, jobID); err != nil {
+			return false, err
+		}
+		return job.IsTerminal(), nil
+	})
+	if stderrors.Is(err, poll.ErrTimeout) {
+		return nil, fmt.Errorf("timeout waiting for fine-tuning job to complete")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return job, nil
+}
@@ -0,0 +1,189 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/finetuning"
+)
+
+// fineTuningServer is a mock Fine-tuning API that advances a job through
+// queued -> running -> succeeded on each retrieve.
+type fineTuningServer struct {
+	mu       sync.Mutex
+	job      finetuning.Job
+	retrieve int
+}
+
+func (s *fineTuningServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/fine_tuning/jobs":
+		var req finetuning.JobCreateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.job = finetuning.Job{
+			ID:              "ftjob-123",
+			Object:          "fine_tuning.job",
+			Model:           req.Model,
+			TrainingFile:    req.TrainingFile,
+			Hyperparameters: req.Hyperparameters,
+			Status:          finetuning.StatusQueued,
+			CreatedAt:       1700000000,
+		}
+		json.NewEncoder(w).Encode(s.job)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs/ftjob-123":
+		s.retrieve++
+		switch s.retrieve {
+		case 1:
+			s.job.Status = finetuning.StatusRunning
+		default:
+			s.job.Status = finetuning.StatusSucceeded
+			s.job.FineTunedModel = "glm-4-flash:ft:support"
+		}
+		json.NewEncoder(w).Encode(s.job)
+
+	case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs":
+		json.NewEncoder(w).Encode(finetuning.JobListResponse{
+			Object:  "list",
+			Data:    []finetuning.Job{s.job},
+			HasMore: r.URL.Query().Get("after") == "",
+		})
+
+	case r.Method == http.MethodGet && r.URL.Path == "/fine_tuning/jobs/ftjob-123/events":
+		events := []finetuning.Event{
+			{ID: "ev-1", Level: "info", Message: "Job started", Type: "message"},
+			{ID: "ev-2", Level: "info", Message: "Step 10/100", Type: "metrics", Data: map[string]interface{}{"step": 10.0}},
+		}
+		if r.URL.Query().Get("after") == "ev-1" {
+			events = events[1:]
+		}
+		json.NewEncoder(w).Encode(finetuning.EventListResponse{Object: "list", Data: events})
+
+	case r.Method == http.MethodPost && r.URL.Path == "/fine_tuning/jobs/ftjob-123/cancel":
+		s.job.Status = finetuning.StatusCancelled
+		json.NewEncoder(w).Encode(s.job)
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"message":"not found"}}`))
+	}
+}
+
+func newFineTuningTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(&fineTuningServer{})
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestFineTuningService_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	client := newFineTuningTestClient(t)
+	ctx := context.Background()
+
+	req := finetuning.NewJobCreateRequest("glm-4-flash", "file_abc").
+		SetEpochs(3).
+		SetLearningRateMultiplier(0.5).
+		SetBatchSize(8).
+		SetSuffix("support")
+
+	job, err := client.FineTuning.Create(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "ftjob-123", job.ID)
+	assert.Equal(t, "file_abc", job.TrainingFile)
+	assert.True(t, job.IsRunning())
+	require.NotNil(t, job.Hyperparameters)
+	assert.Equal(t, float64(3), job.Hyperparameters.NEpochs)
+
+	list, err := client.FineTuning.List(ctx, "", 10)
+	require.NoError(t, err)
+	require.Len(t, list.GetJobs(), 1)
+	assert.True(t, list.HasMore)
+
+	next, err := client.FineTuning.List(ctx, list.LastID(), 10)
+	require.NoError(t, err)
+	assert.False(t, next.HasMore)
+
+	events, err := client.FineTuning.ListEvents(ctx, job.ID, "", 0)
+	require.NoError(t, err)
+	require.Len(t, events.GetEvents(), 2)
+
+	events, err = client.FineTuning.ListEvents(ctx, job.ID, "ev-1", 0)
+	require.NoError(t, err)
+	require.Len(t, events.GetEvents(), 1)
+	assert.Equal(t, "metrics", events.Data[0].Type)
+
+	done, err := client.FineTuning.WaitForCompletion(ctx, job.ID, 10*time.Millisecond, time.Second)
+	require.NoError(t, err)
+	assert.True(t, done.IsSucceeded())
+	assert.Equal(t, "glm-4-flash:ft:support", done.FineTunedModel)
+}
+
+func TestFineTuningService_Cancel(t *testing.T) {
+	t.Parallel()
+
+	client := newFineTuningTestClient(t)
+	ctx := context.Background()
+
+	job, err := client.FineTuning.Create(ctx, finetuning.NewJobCreateRequest("glm-4-flash", "file_abc"))
+	require.NoError(t, err)
+
+	cancelled, err := client.FineTuning.Cancel(ctx, job.ID)
+	require.NoError(t, err)
+	assert.True(t, cancelled.IsCancelled())
+	assert.True(t, cancelled.IsTerminal())
+}
+
+func TestFineTuningService_EmptyID(t *testing.T) {
+	t.Parallel()
+
+	client := newFineTuningTestClient(t)
+	ctx := context.Background()
+
+	_, err := client.FineTuning.Retrieve(ctx, "")
+	assert.Error(t, err)
+
+	_, err = client.FineTuning.Cancel(ctx, "")
+	assert.Error(t, err)
+
+	_, err = client.FineTuning.ListEvents(ctx, "", "", 0)
+	assert.Error(t, err)
+}
+
+func TestFineTuningService_WaitForCompletion_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	client := newFineTuningTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := client.FineTuning.WaitForCompletion(ctx, "ftjob-123", time.Millisecond, time.Second)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,148 @@
+package zai
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/ratelimit"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// HealthStatus is the result of a health check by Client.Healthz.
+type HealthStatus struct {
+	// Healthy reports whether the check succeeded.
+	Healthy bool `json:"healthy"`
+
+	// Latency is the duration of the check request.
+	Latency time.Duration `json:"latency"`
+
+	// Endpoint is the URL the check requested.
+	Endpoint string `json:"endpoint"`
+
+	// Platform is the platform the client talks to.
+	Platform Platform `json:"platform"`
+
+	// BaseURL is the base URL of the client, after defaults are applied.
+	BaseURL string `json:"base_url"`
+
+	// CheckedAt is when the check started.
+	CheckedAt time.Time `json:"checked_at"`
+
+	// Error is the reason the check failed, or nil if it succeeded.
+	Error *errors.PingError `json:"-"`
+}
+
+// Ping verifies the credentials and the connection to the API with an
+// authenticated request that lists the models, which consumes no tokens.
+// The request is sent once, without retries or rate limiting. A failure is
+// an *errors.PingError whose Kind tells DNS, connection, TLS, timeout,
+// authentication and server failures apart.
+//
+// Example:
+//
+//	if err := client.Ping(ctx); err != nil {
+//	    var pingErr *errors.PingError
+//	    if stderrors.As(err, &pingErr) && pingErr.Kind == errors.PingFailureAuth {
+//	        log.Fatal("invalid API key")
+//	    }
+//	    log.Fatal(err)
+//	}
+func (c *Client) Ping(ctx context.Context) error {
+	status := c.Healthz(ctx)
+	if status.Error != nil {
+		return status.Error
+	}
+	return nil
+}
+
+// Healthz runs the check of Ping and describes the result, for readiness
+// probes and status pages.
+//
+// Example:
+//
+//	http.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+//	    status := client.Healthz(r.Context())
+//	    if !status.Healthy {
+//	        w.WriteHeader(http.StatusServiceUnavailable)
+//	    }
+//	    json.NewEncoder(w).Encode(status)
+//	})
+func (c *Client) Healthz(ctx context.Context) *HealthStatus {
+	baseURL := c.baseClient.GetConfig().BaseURL
+	path := c.baseClient.Endpoints().Path(endpoints.Models)
+	status := &HealthStatus{
+		Endpoint:  endpointURL(baseURL, path),
+		Platform:  c.Platform(),
+		BaseURL:   baseURL,
+		CheckedAt: time.Now(),
+	}
+
+	ctx = WithRetryPolicy(ratelimit.WithoutLimit(ctx), RetryPolicy{MaxAttempts: 1})
+	resp, err := c.baseClient.Get(ctx, path, nil)
+	status.Latency = time.Since(status.CheckedAt)
+	if err != nil {
+		status.Error = newPingError(err)
+		return status
+	}
+	resp.Close()
+
+	status.Healthy = true
+	return status
+}
+
+// endpointURL returns the URL of path, which is relative to baseURL unless
+// it is an absolute URL.
+func endpointURL(baseURL, path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// newPingError classifies err, the error of the health check request.
+func newPingError(err error) *errors.PingError {
+	var statusErr *errors.APIStatusError
+	if stderrors.As(err, &statusErr) {
+		switch {
+		case statusErr.StatusCode == http.StatusUnauthorized:
+			return errors.NewPingError(errors.PingFailureAuth, statusErr.StatusCode, err)
+		case statusErr.StatusCode >= http.StatusInternalServerError:
+			return errors.NewPingError(errors.PingFailureServer, statusErr.StatusCode, err)
+		default:
+			return errors.NewPingError(errors.PingFailureOther, statusErr.StatusCode, err)
+		}
+	}
+
+	var (
+		dnsErr       *net.DNSError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		headerErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		opErr        *net.OpError
+		netErr       net.Error
+	)
+	switch {
+	case stderrors.As(err, &dnsErr):
+		return errors.NewPingError(errors.PingFailureDNS, 0, err)
+	case stderrors.As(err, &verifyErr), stderrors.As(err, &authorityErr), stderrors.As(err, &hostnameErr),
+		stderrors.As(err, &invalidErr), stderrors.As(err, &headerErr), stderrors.As(err, &alertErr):
+		return errors.NewPingError(errors.PingFailureTLS, 0, err)
+	case errors.IsTimeoutError(err), stderrors.Is(err, context.DeadlineExceeded),
+		stderrors.As(err, &netErr) && netErr.Timeout():
+		return errors.NewPingError(errors.PingFailureTimeout, 0, err)
+	case stderrors.As(err, &opErr) && opErr.Op == "dial":
+		return errors.NewPingError(errors.PingFailureConnect, 0, err)
+	default:
+		return errors.NewPingError(errors.PingFailureOther, 0, err)
+	}
+}
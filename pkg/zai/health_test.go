@@ -0,0 +1,161 @@
+package zai
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// newHealthTestClient returns a client of baseURL that retries, so that
+// the tests show health checks do not.
+func newHealthTestClient(t *testing.T, baseURL string, opts ...ClientOption) *Client {
+	client, err := NewClient(append([]ClientOption{
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(baseURL),
+		WithDisableTokenCache(),
+		WithMaxRetries(3),
+		WithTimeout(5 * time.Second),
+	}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+// requirePingFailure asserts that err is a *errors.PingError of kind.
+func requirePingFailure(t *testing.T, err error, kind errors.PingFailure) *errors.PingError {
+	t.Helper()
+
+	require.Error(t, err)
+	pingErr, ok := err.(*errors.PingError)
+	require.True(t, ok, "got %T: %v", err, err)
+	assert.Equal(t, kind, pingErr.Kind, "got %v", err)
+	return pingErr
+}
+
+func TestClient_Healthz(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/models", r.URL.Path)
+		assert.NotEmpty(t, r.Header.Get("Authorization"))
+		writeJSON(w, map[string]any{"object": "list", "data": []any{}})
+	}))
+	t.Cleanup(server.Close)
+	client := newHealthTestClient(t, server.URL+"/")
+
+	require.NoError(t, client.Ping(context.Background()))
+
+	status := client.Healthz(context.Background())
+	assert.True(t, status.Healthy)
+	assert.Nil(t, status.Error)
+	assert.Positive(t, status.Latency)
+	assert.Equal(t, server.URL+"/models", status.Endpoint)
+	assert.Equal(t, server.URL+"/", status.BaseURL)
+	assert.Equal(t, PlatformInternational, status.Platform)
+	assert.WithinDuration(t, time.Now(), status.CheckedAt, time.Minute)
+}
+
+func TestClient_Ping_WrongKey(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer right-key.secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]any{"error": map[string]any{"code": "1000", "message": "Authentication failed"}})
+			return
+		}
+		writeJSON(w, map[string]any{"object": "list", "data": []any{}})
+	}))
+	t.Cleanup(server.Close)
+
+	client := newHealthTestClient(t, server.URL, WithAPIKey("wrong-key.secret"))
+	err := client.Ping(context.Background())
+	pingErr := requirePingFailure(t, err, errors.PingFailureAuth)
+	assert.Equal(t, http.StatusUnauthorized, pingErr.StatusCode)
+	assert.True(t, errors.IsAuthenticationError(err))
+
+	status := client.Healthz(context.Background())
+	assert.False(t, status.Healthy)
+	assert.Equal(t, errors.PingFailureAuth, status.Error.Kind)
+
+	client = newHealthTestClient(t, server.URL, WithAPIKey("right-key.secret"))
+	require.NoError(t, client.Ping(context.Background()))
+}
+
+func TestClient_Ping_ServerError(t *testing.T) {
+	t.Parallel()
+
+	for _, code := range []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(code)
+			writeJSON(w, map[string]any{"error": map[string]any{"code": "500", "message": "unavailable"}})
+		}))
+		t.Cleanup(server.Close)
+
+		pingErr := requirePingFailure(t, newHealthTestClient(t, server.URL).Ping(context.Background()), errors.PingFailureServer)
+		assert.Equal(t, code, pingErr.StatusCode)
+		assert.EqualValues(t, 1, calls.Load(), "health checks are not retried")
+	}
+}
+
+func TestClient_Ping_ConnectionRefused(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	err = newHealthTestClient(t, "http://"+addr).Ping(context.Background())
+	pingErr := requirePingFailure(t, err, errors.PingFailureConnect)
+	assert.Zero(t, pingErr.StatusCode)
+}
+
+func TestClient_Ping_DNS(t *testing.T) {
+	t.Parallel()
+
+	// The .invalid top-level domain never resolves
+	err := newHealthTestClient(t, "http://api.zai-sdk.invalid").Ping(context.Background())
+	requirePingFailure(t, err, errors.PingFailureDNS)
+}
+
+func TestClient_Ping_TLS(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request is made without a trusted certificate")
+	}))
+	t.Cleanup(server.Close)
+
+	// The client does not trust the test server's certificate
+	err := newHealthTestClient(t, server.URL).Ping(context.Background())
+	requirePingFailure(t, err, errors.PingFailureTLS)
+}
+
+func TestClient_Ping_Timeout(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := newHealthTestClient(t, server.URL).Ping(ctx)
+	requirePingFailure(t, err, errors.PingFailureTimeout)
+}
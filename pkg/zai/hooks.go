@@ -0,0 +1,72 @@
+package zai
+
+import (
+	"github.com/sofianhadi1983/zai-sdk-go/internal/hooks"
+)
+
+// Hooks receives lifecycle events for every request the client makes,
+// including streaming requests. Implementations must be safe for
+// concurrent use. Embed NoopHooks to implement only some callbacks.
+type Hooks = hooks.Hooks
+
+// NoopHooks is a Hooks implementation that does nothing.
+type NoopHooks = hooks.NoopHooks
+
+// RequestEvent describes an outgoing HTTP attempt.
+type RequestEvent = hooks.RequestEvent
+
+// ResponseEvent describes a received HTTP response.
+type ResponseEvent = hooks.ResponseEvent
+
+// RetryEvent describes a retry that is about to happen.
+type RetryEvent = hooks.RetryEvent
+
+// StreamEvent describes a server-sent event read from a stream.
+type StreamEvent = hooks.StreamEvent
+
+// ErrorEvent describes a failed request.
+type ErrorEvent = hooks.ErrorEvent
+
+// WarningEvent describes a condition the SDK worked around, such as an
+// upload that cannot be retried.
+type WarningEvent = hooks.WarningEvent
+
+// WarningHooks is implemented by hooks that also receive warnings. Hooks
+// registered with WithHooks receive them if they implement it; NoopHooks
+// does.
+type WarningHooks = hooks.WarningHooks
+
+// RedactedValue replaces credentials in hook events and logs.
+const RedactedValue = hooks.RedactedValue
+
+// WithHooks registers lifecycle hooks on the client.
+// It may be passed several times; hooks are called in registration order.
+//
+// Example:
+//
+//	type metrics struct{ zai.NoopHooks }
+//
+//	func (metrics) OnResponse(ctx context.Context, e *zai.ResponseEvent) {
+//	    latency.Observe(e.Latency.Seconds())
+//	}
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithHooks(metrics{}),
+//	)
+func WithHooks(h Hooks) ClientOption {
+	return func(c *ClientConfig) {
+		if h != nil {
+			c.Hooks = append(c.Hooks, h)
+		}
+	}
+}
+
+// WithLogBodies enables passing request bodies and stream payloads to hooks
+// and the logger. It is disabled by default. The API key is masked even when
+// body logging is enabled.
+func WithLogBodies(enabled bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.LogBodies = enabled
+	}
+}
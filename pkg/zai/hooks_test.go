@@ -0,0 +1,230 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// recordingHooks records every lifecycle event it receives.
+type recordingHooks struct {
+	mu       sync.Mutex
+	events   []string
+	requests []*RequestEvent
+	streams  []*StreamEvent
+	errors   []*ErrorEvent
+}
+
+func (h *recordingHooks) record(s string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, s)
+}
+
+func (h *recordingHooks) OnRequest(_ context.Context, e *RequestEvent) {
+	h.record(fmt.Sprintf("request:%d", e.Attempt))
+	h.mu.Lock()
+	h.requests = append(h.requests, e)
+	h.mu.Unlock()
+}
+
+func (h *recordingHooks) OnResponse(_ context.Context, e *ResponseEvent) {
+	h.record(fmt.Sprintf("response:%d:%d", e.Attempt, e.StatusCode))
+}
+
+func (h *recordingHooks) OnRetry(_ context.Context, e *RetryEvent) {
+	h.record(fmt.Sprintf("retry:%d:%d", e.Attempt, e.StatusCode))
+}
+
+func (h *recordingHooks) OnStreamEvent(_ context.Context, e *StreamEvent) {
+	h.record(fmt.Sprintf("stream:%d", e.Index))
+	h.mu.Lock()
+	h.streams = append(h.streams, e)
+	h.mu.Unlock()
+}
+
+func (h *recordingHooks) OnError(_ context.Context, e *ErrorEvent) {
+	h.record(fmt.Sprintf("error:%d:%d", e.Attempt, e.StatusCode))
+	h.mu.Lock()
+	h.errors = append(h.errors, e)
+	h.mu.Unlock()
+}
+
+func TestHooks_RetryOrdering(t *testing.T) {
+	t.Parallel()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			w.Write([]byte(`{"error":{"code":"503","message":"busy"}}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	rec := &recordingHooks{}
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithHooks(rec),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Files.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"request:1", "response:1:503", "retry:2:503",
+		"request:2", "response:2:503", "retry:3:503",
+		"request:3", "response:3:200",
+	}, rec.events)
+}
+
+func TestHooks_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"code":"1214","message":"bad request"}}`))
+	}))
+	defer server.Close()
+
+	rec := &recordingHooks{}
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithHooks(rec),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Files.List(context.Background())
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"request:1", "response:1:400", "error:1:400"}, rec.events)
+	require.Len(t, rec.errors, 1)
+	assert.Equal(t, "/files", rec.errors[0].Path)
+	assert.Equal(t, err, rec.errors[0].Err)
+}
+
+func TestHooks_Redaction(t *testing.T) {
+	t.Parallel()
+
+	const apiKey = "test-key.test-secret"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+	}))
+	defer server.Close()
+
+	newReq := func() *chat.ChatCompletionRequest {
+		return &chat.ChatCompletionRequest{
+			Model:    "glm-4.7",
+			Messages: []chat.Message{chat.NewUserMessage("my key is " + apiKey)},
+		}
+	}
+
+	t.Run("bodies omitted by default", func(t *testing.T) {
+		rec := &recordingHooks{}
+		client, err := NewClient(WithAPIKey(apiKey), WithBaseURL(server.URL), WithHooks(rec), WithDisableTokenCache())
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Chat.Create(context.Background(), newReq())
+		require.NoError(t, err)
+
+		require.Len(t, rec.requests, 1)
+		assert.Equal(t, RedactedValue, rec.requests[0].Header.Get("Authorization"))
+		assert.Nil(t, rec.requests[0].Body)
+	})
+
+	t.Run("bodies masked when enabled", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		rec := &recordingHooks{}
+		client, err := NewClient(
+			WithAPIKey(apiKey),
+			WithBaseURL(server.URL),
+			WithHooks(rec),
+			WithLogger(logger),
+			WithLogBodies(true),
+			WithDisableTokenCache(),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Chat.Create(context.Background(), newReq())
+		require.NoError(t, err)
+
+		require.Len(t, rec.requests, 1)
+		body := string(rec.requests[0].Body)
+		assert.Contains(t, body, "glm-4.7")
+		assert.Contains(t, body, RedactedValue)
+		assert.NotContains(t, body, "test-secret")
+
+		assert.Contains(t, buf.String(), "HTTP request")
+		assert.NotContains(t, buf.String(), "test-secret")
+	})
+}
+
+func TestHooks_StreamEvents(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, chunk := range []string{
+			`{"id":"1","choices":[{"index":0,"delta":{"content":"Hel"}}]}`,
+			`{"id":"1","choices":[{"index":0,"delta":{"content":"lo"}}]}`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", chunk)
+			flusher.Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+	}))
+	defer server.Close()
+
+	rec := &recordingHooks{}
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithHooks(rec),
+		WithLogBodies(true),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	stream, err := client.Chat.CreateStream(context.Background(), &chat.ChatCompletionRequest{
+		Model:    "glm-4.7",
+		Messages: []chat.Message{chat.NewUserMessage("hi")},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	for stream.Next() {
+	}
+	require.NoError(t, stream.Err())
+
+	assert.Equal(t, []string{"request:1", "response:1:200", "stream:0", "stream:1"}, rec.events)
+	require.Len(t, rec.streams, 2)
+	assert.Equal(t, "/chat/completions", rec.streams[1].Path)
+	assert.Contains(t, string(rec.streams[1].Data), `"lo"`)
+}
@@ -0,0 +1,155 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+// cannedTransport answers every request with the same JSON body, without
+// a network round trip, so that benchmarks measure the SDK alone. The
+// request body is drained and closed like a real transport does.
+type cannedTransport struct {
+	body []byte
+}
+
+func (t *cannedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(t.body)),
+		ContentLength: int64(len(t.body)),
+		Request:       req,
+	}, nil
+}
+
+// newCannedClient returns a client whose requests are answered with v
+// encoded as JSON.
+func newCannedClient(tb testing.TB, v any) *Client {
+	body, err := json.Marshal(v)
+	require.NoError(tb, err)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL("https://api.example.com/v4"),
+		WithTransport(&cannedTransport{body: body}),
+	)
+	require.NoError(tb, err)
+	tb.Cleanup(client.Close)
+	return client
+}
+
+// embeddingResponse returns an embeddings response with n vectors of dim
+// dimensions.
+func embeddingResponse(n, dim int) map[string]any {
+	data := make([]map[string]any, n)
+	for i := range data {
+		vector := make([]float64, dim)
+		for j := range vector {
+			vector[j] = float64(i*dim+j) / 1e6
+		}
+		data[i] = map[string]any{"object": "embedding", "index": i, "embedding": vector}
+	}
+	return map[string]any{
+		"object": "list",
+		"model":  "embedding-3",
+		"data":   data,
+		"usage":  map[string]int{"prompt_tokens": 8 * n, "total_tokens": 8 * n},
+	}
+}
+
+func BenchmarkEmbeddingsCreate(b *testing.B) {
+	client := newCannedClient(b, embeddingResponse(1, 1024))
+	req := embeddings.NewEmbeddingRequest("embedding-3", "The quick brown fox jumps over the lazy dog")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.Embeddings.Create(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEmbeddingsCreate_ExtraFields(b *testing.B) {
+	// Unknown top-level fields are collected into Extra
+	resp := embeddingResponse(1, 1024)
+	resp["id"] = "emb-1"
+	resp["created"] = 1760582112
+	resp["request_id"] = "req-1"
+	client := newCannedClient(b, resp)
+	req := embeddings.NewEmbeddingRequest("embedding-3", "The quick brown fox jumps over the lazy dog")
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		resp, err := client.Embeddings.Create(ctx, req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(resp.Extra()) == 0 {
+			b.Fatal("missing extra fields")
+		}
+	}
+}
+
+func BenchmarkEmbeddingsCreate_Parallel(b *testing.B) {
+	client := newCannedClient(b, embeddingResponse(1, 1024))
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		req := embeddings.NewEmbeddingRequest("embedding-3", "The quick brown fox jumps over the lazy dog")
+		for pb.Next() {
+			if _, err := client.Embeddings.Create(ctx, req); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkChatCreate(b *testing.B) {
+	client := newCannedClient(b, map[string]any{
+		"id":      "chatcmpl-1",
+		"object":  "chat.completion",
+		"created": 1760582112,
+		"model":   "glm-4.7",
+		"choices": []map[string]any{{
+			"index":         0,
+			"finish_reason": "stop",
+			"message":       map[string]string{"role": "assistant", "content": "Paris is the capital of France."},
+		}},
+		"usage": map[string]int{"prompt_tokens": 18, "completion_tokens": 8, "total_tokens": 26},
+	})
+	messages := make([]chat.Message, 0, 8)
+	for i := range 8 {
+		messages = append(messages, chat.NewUserMessage("Question number "+strconv.Itoa(i)+" about the capitals of Europe."))
+	}
+	req := chat.NewChatCompletionRequest("glm-4.7", messages)
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		if _, err := client.Chat.Create(ctx, req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// WithIdempotencyKey returns a copy of ctx that sends key in the
+// Idempotency-Key header of the requests made with it. Every attempt of a
+// call repeats the key, and a request with a key is retried after errors
+// like an idempotent request, see RetryPolicy.
+//
+// Use one key per logical operation, such as creating one batch, and reuse
+// it when retrying the operation yourself. The Z.ai API does not document
+// support for the header, so whether the server deduplicates requests by
+// key is not guaranteed.
+//
+// Example:
+//
+//	ctx := zai.WithIdempotencyKey(ctx, "nightly-batch-2026-10-17")
+//	job, err := client.Batch.Create(ctx, req)
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return transport.ContextWithHeaders(ctx, http.Header{constants.HeaderIdempotencyKey: {key}})
+}
+
+// WithRequestID returns a copy of ctx that sends id in the X-Request-ID
+// header of the requests made with it, instead of a generated ID. The ID
+// is passed to hooks and logs and is the RequestID of API errors, unless
+// the server reports its own.
+//
+// Example:
+//
+//	ctx := zai.WithRequestID(ctx, traceID)
+//	resp, err := client.Chat.Create(ctx, req)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return transport.ContextWithHeaders(ctx, http.Header{constants.HeaderRequestID: {id}})
+}
+
+// WithIdempotencyKeys sends a generated Idempotency-Key header with calls
+// that create server-side resources: Batch.Create, Videos.Create,
+// Voice.Clone and Files.Upload, unless the context already has a key from
+// WithIdempotencyKey. The key stays the same across the retries of a call,
+// which are then allowed for retryable status codes as well as transport
+// errors.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithIdempotencyKeys(),
+//	)
+func WithIdempotencyKeys() ClientOption {
+	return func(c *ClientConfig) {
+		c.AutoIdempotencyKeys = true
+	}
+}
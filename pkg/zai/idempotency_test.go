@@ -0,0 +1,157 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/voice"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// headerRecorder records the idempotency key and request ID of each
+// request, and fails the first failures requests with a 503 status.
+type headerRecorder struct {
+	mu       sync.Mutex
+	keys     []string
+	ids      []string
+	failures int
+}
+
+func (h *headerRecorder) handler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.mu.Lock()
+		h.keys = append(h.keys, r.Header.Get("Idempotency-Key"))
+		h.ids = append(h.ids, r.Header.Get("X-Request-ID"))
+		fail := len(h.keys) <= h.failures
+		h.mu.Unlock()
+
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+var fastRetries = WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+func TestWithIdempotencyKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		call func(ctx context.Context, c *Client) error
+	}{
+		{"Batch.Create", `{"id":"batch_1"}`, func(ctx context.Context, c *Client) error {
+			_, err := c.Batch.Create(ctx, batch.NewBatchCreateRequest("24h", batch.EndpointChatCompletions, "file_1"))
+			return err
+		}},
+		{"Voice.Clone", `{"voice":"voice_1"}`, func(ctx context.Context, c *Client) error {
+			_, err := c.Voice.Clone(ctx, voice.NewVoiceCloneRequest("my_voice", "Hello", "Preview", "file_1", "cogtts"))
+			return err
+		}},
+		{"Files.Upload", `{"id":"file_1"}`, func(ctx context.Context, c *Client) error {
+			_, err := c.Files.Upload(ctx, files.NewFileUploadRequest(strings.NewReader(`{"messages":[]}`), "train.jsonl", files.PurposeFineTune))
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &headerRecorder{failures: 2}
+			client := newLimitsTestClient(t, recorder.handler(tt.body), fastRetries, WithIdempotencyKeys())
+
+			require.NoError(t, tt.call(context.Background(), client))
+			require.NoError(t, tt.call(context.Background(), client))
+
+			recorder.mu.Lock()
+			defer recorder.mu.Unlock()
+			require.Len(t, recorder.keys, 4, "the failed attempts are retried")
+			assert.NotEmpty(t, recorder.keys[0])
+			assert.Equal(t, []string{recorder.keys[0], recorder.keys[0], recorder.keys[0]}, recorder.keys[:3], "the key is stable across retries")
+			assert.Equal(t, []string{recorder.ids[0], recorder.ids[0], recorder.ids[0]}, recorder.ids[:3], "so is the request ID")
+			assert.NotEqual(t, recorder.keys[0], recorder.keys[3], "each call has its own key")
+			assert.NotEqual(t, recorder.ids[0], recorder.ids[3])
+		})
+	}
+
+	t.Run("caller key", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &headerRecorder{failures: 1}
+		client := newLimitsTestClient(t, recorder.handler(`{"id":"batch_1"}`), fastRetries, WithIdempotencyKeys())
+
+		ctx := WithIdempotencyKey(context.Background(), "batch-2026-10-17")
+		_, err := client.Batch.Create(ctx, batch.NewBatchCreateRequest("24h", batch.EndpointChatCompletions, "file_1"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"batch-2026-10-17", "batch-2026-10-17"}, recorder.keys)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		recorder := &headerRecorder{failures: 1}
+		client := newLimitsTestClient(t, recorder.handler(`{"id":"batch_1"}`), fastRetries)
+
+		_, err := client.Batch.Create(context.Background(), batch.NewBatchCreateRequest("24h", batch.EndpointChatCompletions, "file_1"))
+		require.Error(t, err, "a POST without a key is not retried after a 503")
+		assert.Equal(t, []string{""}, recorder.keys)
+		assert.Len(t, recorder.ids[0], 36, "a request ID is still sent")
+	})
+}
+
+// requestIDHooks records the request IDs of request and error events.
+type requestIDHooks struct {
+	NoopHooks
+
+	mu       sync.Mutex
+	requests []string
+	errors   []string
+}
+
+func (h *requestIDHooks) OnRequest(_ context.Context, e *RequestEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, e.RequestID)
+}
+
+func (h *requestIDHooks) OnError(_ context.Context, e *ErrorEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.errors = append(h.errors, e.RequestID)
+}
+
+func TestWithRequestID(t *testing.T) {
+	t.Parallel()
+
+	recorder := &headerRecorder{failures: 3}
+	hooks := &requestIDHooks{}
+	client := newLimitsTestClient(t, recorder.handler(`{}`), WithHooks(hooks))
+
+	ctx := WithRequestID(context.Background(), "trace-123")
+	_, err := client.Batch.Retrieve(ctx, "batch_1")
+
+	var statusErr *errors.APIStatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, "trace-123", statusErr.RequestID, "the error echoes the request ID")
+	assert.Contains(t, err.Error(), "request_id: trace-123")
+
+	assert.Equal(t, []string{"trace-123", "trace-123"}, recorder.ids)
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	assert.Equal(t, []string{"trace-123", "trace-123"}, hooks.requests)
+	assert.Equal(t, []string{"trace-123"}, hooks.errors)
+}
@@ -2,24 +2,33 @@ package zai
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"strconv"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/images"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // ImagesService provides access to the Images API.
 type ImagesService struct {
-	client *client.BaseClient
+	client       *client.BaseClient
+	defaultModel string
 }
 
 // newImagesService creates a new images service.
-func newImagesService(baseClient *client.BaseClient) *ImagesService {
+func newImagesService(baseClient *client.BaseClient, defaultModel string) *ImagesService {
 	return &ImagesService{
-		client: baseClient,
+		client:       baseClient,
+		defaultModel: defaultModel,
 	}
 }
 
-// Create generates images based on the provided prompt.
+// Create generates images based on the provided prompt. A request without
+// a model is sent with the client's default image model, see
+// WithDefaultImageModel.
 //
 // Example:
 //
@@ -36,8 +45,21 @@ func newImagesService(baseClient *client.BaseClient) *ImagesService {
 //	    fmt.Printf("Image URL: %s\n", firstImage.GetImageURL())
 //	}
 func (s *ImagesService) Create(ctx context.Context, req *images.ImageGenerationRequest) (*images.ImageGenerationResponse, error) {
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultImageModel")
+	if err != nil {
+		return nil, err
+	}
+	if model != req.Model {
+		withModel := *req
+		withModel.Model = model
+		req = &withModel
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/images/generations", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.ImageGenerations), req)
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +74,9 @@ func (s *ImagesService) Create(ctx context.Context, req *images.ImageGenerationR
 }
 
 // Generate is a convenience method for generating a single image from a text prompt.
-// Returns the URL or base64 data of the first generated image.
+// Returns the URL or base64 data of the first generated image, or an
+// *errors.EmptyResultError if the response has none. An empty model uses
+// the client's default image model.
 //
 // Example:
 //
@@ -70,21 +94,14 @@ func (s *ImagesService) Generate(ctx context.Context, model, prompt string) (str
 		return "", err
 	}
 
-	firstImage := resp.GetFirstImage()
-	if firstImage == nil {
-		return "", nil
-	}
-
 	// Return URL if available, otherwise return base64 data
-	if firstImage.URL != "" {
-		return firstImage.URL, nil
-	}
-
-	return firstImage.B64JSON, nil
+	return firstImage("Images.Generate", resp)
 }
 
 // GenerateMultiple is a convenience method for generating multiple images from a text prompt.
-// Returns URLs or base64 data of all generated images.
+// Returns URLs or base64 data of all generated images, or an
+// *errors.EmptyResultError if the response has none. An empty model uses
+// the client's default image model.
 //
 // Example:
 //
@@ -106,10 +123,296 @@ func (s *ImagesService) GenerateMultiple(ctx context.Context, model, prompt stri
 	}
 
 	// Return URLs if available, otherwise return base64 data
-	urls := resp.GetImageURLs()
-	if len(urls) > 0 {
+	return allImages("Images.GenerateMultiple", resp)
+}
+
+// Edit edits an image from a prompt, optionally limited to the area marked
+// by a mask. The image and mask are uploaded as multipart form data, or
+// sent as URLs.
+//
+// Example:
+//
+//	image, _ := os.Open("room.png")
+//	defer image.Close()
+//	mask, _ := os.Open("room-mask.png")
+//	defer mask.Close()
+//
+//	req := images.NewImageEditRequest(images.ModelCogView4, "Add a red sofa",
+//	    images.ImageFromReader(image, "room.png")).
+//	    SetMask(images.ImageFromReader(mask, "room-mask.png"))
+//
+//	resp, err := client.Images.Edit(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Edited image: %s\n", resp.GetFirstImage().GetImageURL())
+func (s *ImagesService) Edit(ctx context.Context, req *images.ImageEditRequest) (*images.ImageGenerationResponse, error) {
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultImageModel")
+	if err != nil {
+		return nil, err
+	}
+	if model != req.Model {
+		withModel := *req
+		withModel.Model = model
+		req = &withModel
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.postImageForm(ctx, s.client.Endpoints().Path(endpoints.ImageEdits), &imageForm{
+		model:          req.Model,
+		image:          req.Image,
+		mask:           req.Mask,
+		prompt:         req.Prompt,
+		size:           req.Size,
+		n:              req.N,
+		responseFormat: req.ResponseFormat,
+		userID:         req.UserID,
+	})
+}
+
+// Variations generates variations of an image.
+//
+// Example:
+//
+//	req := images.NewImageVariationRequest(images.ModelCogView4,
+//	    images.ImageFromURL("https://bucket.example.com/logo.png")).SetN(3)
+//
+//	resp, err := client.Images.Variations(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, url := range resp.GetAllImageURLs() {
+//	    fmt.Println(url)
+//	}
+func (s *ImagesService) Variations(ctx context.Context, req *images.ImageVariationRequest) (*images.ImageGenerationResponse, error) {
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultImageModel")
+	if err != nil {
+		return nil, err
+	}
+	if model != req.Model {
+		withModel := *req
+		withModel.Model = model
+		req = &withModel
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.postImageForm(ctx, s.client.Endpoints().Path(endpoints.ImageVariations), &imageForm{
+		model:          req.Model,
+		image:          req.Image,
+		size:           req.Size,
+		n:              req.N,
+		responseFormat: req.ResponseFormat,
+		userID:         req.UserID,
+	})
+}
+
+// EditImage is a convenience method for editing an image file from a
+// prompt. Returns the URL or base64 data of the first edited image, or an
+// *errors.EmptyResultError if the response has none.
+//
+// Example:
+//
+//	file, _ := os.Open("room.png")
+//	defer file.Close()
+//
+//	imageURL, err := client.Images.EditImage(ctx, images.ModelCogView4, "Add a red sofa", file, "room.png")
+func (s *ImagesService) EditImage(ctx context.Context, model, prompt string, image io.Reader, filename string) (string, error) {
+	req := images.NewImageEditRequest(model, prompt, images.ImageFromReader(image, filename))
+
+	resp, err := s.Edit(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return firstImage("Images.EditImage", resp)
+}
+
+// VaryImage is a convenience method for generating count variations of an
+// image file. Returns URLs or base64 data of all generated images, or an
+// *errors.EmptyResultError if the response has none.
+//
+// Example:
+//
+//	file, _ := os.Open("logo.png")
+//	defer file.Close()
+//
+//	imageURLs, err := client.Images.VaryImage(ctx, images.ModelCogView4, file, "logo.png", 3)
+func (s *ImagesService) VaryImage(ctx context.Context, model string, image io.Reader, filename string, count int) ([]string, error) {
+	req := images.NewImageVariationRequest(model, images.ImageFromReader(image, filename)).SetN(count)
+
+	resp, err := s.Variations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return allImages("Images.VaryImage", resp)
+}
+
+// imageForm holds the multipart fields shared by image edits and variations.
+type imageForm struct {
+	model          string
+	image          *images.ImageSource
+	mask           *images.ImageSource
+	prompt         string
+	size           images.ImageSize
+	n              *int
+	responseFormat images.ResponseFormat
+	userID         string
+}
+
+// postImageForm sends an image edit or variation request as multipart form
+// data and parses the response.
+func (s *ImagesService) postImageForm(ctx context.Context, path string, form *imageForm) (*images.ImageGenerationResponse, error) {
+	// Create multipart form data
+	body := client.NewForm()
+
+	fields := []struct{ name, value string }{
+		{"model", form.model},
+		{"prompt", form.prompt},
+		{"size", string(form.size)},
+		{"response_format", string(form.responseFormat)},
+		{"user_id", form.userID},
+	}
+	if form.n != nil {
+		fields = append(fields, struct{ name, value string }{"n", strconv.Itoa(*form.n)})
+	}
+	for _, field := range fields {
+		if field.value == "" {
+			continue
+		}
+		body.WriteField(field.name, field.value)
+	}
+
+	// Add the image and the mask
+	addImagePart(body, "image", form.image)
+	if form.mask != nil {
+		addImagePart(body, "mask", form.mask)
+	}
+
+	// Make the API request
+	apiResp, err := s.client.PostForm(ctx, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp images.ImageGenerationResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// addImagePart adds source as the file part name, or as the name_url
+// field for an image referenced by URL.
+func addImagePart(form *client.Form, name string, source *images.ImageSource) {
+	if source.Reader == nil {
+		form.WriteField(name+"_url", source.URL)
+		return
+	}
+	form.AddFile(&client.FormFile{Field: name, Filename: source.Filename, Reader: source.Reader})
+}
+
+// firstImage returns the URL, or otherwise the base64 data, of the first
+// image of resp, or an EmptyResultError for operation if it has neither.
+func firstImage(operation string, resp *images.ImageGenerationResponse) (string, error) {
+	image := resp.GetFirstImage()
+	switch {
+	case image == nil:
+		return "", errors.NewEmptyResultError(operation, "image")
+	case image.URL != "":
+		return image.URL, nil
+	case image.B64JSON != "":
+		return image.B64JSON, nil
+	}
+	return "", errors.NewEmptyResultError(operation, "image URL or data")
+}
+
+// allImages returns the URLs, or otherwise the base64 data, of the images
+// of resp, or an EmptyResultError for operation if there are none.
+func allImages(operation string, resp *images.ImageGenerationResponse) ([]string, error) {
+	if urls := resp.GetAllImageURLs(); len(urls) > 0 {
 		return urls, nil
 	}
+	if data := resp.GetAllBase64(); len(data) > 0 {
+		return data, nil
+	}
+	return nil, errors.NewEmptyResultError(operation, "image")
+}
+
+// Download returns the bytes of a generated image, fetching its URL or
+// decoding its base64 data. URL downloads honor ctx and retry transient
+// failures using the client's retry configuration.
+//
+// Example:
+//
+//	resp, err := client.Images.Create(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	data, err := client.Images.Download(ctx, resp.GetFirstImage())
+func (s *ImagesService) Download(ctx context.Context, image *images.ImageData) ([]byte, error) {
+	return image.Download(ctx, s.doer())
+}
+
+// SaveTo downloads a generated image and writes it to path, appending an
+// extension inferred from the image if path has none. It returns the path
+// that was written.
+//
+// Example:
+//
+//	path, err := client.Images.SaveTo(ctx, resp.GetFirstImage(), "out/sunset")
+func (s *ImagesService) SaveTo(ctx context.Context, image *images.ImageData, path string) (string, error) {
+	return image.SaveToWithClient(ctx, s.doer(), path)
+}
+
+// GenerateToFile generates a single image and writes it to path, appending
+// an extension inferred from the image if path has none. It returns the
+// path that was written, or an *errors.EmptyResultError if the response
+// has no image.
+//
+// Example:
+//
+//	path, err := client.Images.GenerateToFile(ctx, images.ModelCogView4, "A red fox in snow", "fox")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Saved to %s\n", path)
+func (s *ImagesService) GenerateToFile(ctx context.Context, model, prompt, path string) (string, error) {
+	resp, err := s.Create(ctx, images.NewImageGenerationRequest(model, prompt))
+	if err != nil {
+		return "", err
+	}
+
+	image := resp.GetFirstImage()
+	if image == nil {
+		return "", errors.NewEmptyResultError("Images.GenerateToFile", "image")
+	}
+
+	return s.SaveTo(ctx, image, path)
+}
+
+// doer returns an images.Doer that fetches through the client's retrying
+// transport without sending credentials.
+func (s *ImagesService) doer() images.Doer {
+	return downloadDoer{client: s.client}
+}
+
+// downloadDoer adapts BaseClient to images.Doer.
+type downloadDoer struct {
+	client *client.BaseClient
+}
 
-	return resp.GetBase64Images(), nil
+// Do implements images.Doer.
+func (d downloadDoer) Do(req *http.Request) (*http.Response, error) {
+	return d.client.DoUnauthenticated(req.Context(), req)
 }
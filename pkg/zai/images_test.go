@@ -1,15 +1,28 @@
 package zai
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
 	imagestypes "github.com/sofianhadi1983/zai-sdk-go/api/types/images"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestImagesService_Create(t *testing.T) {
@@ -189,6 +202,57 @@ func TestImagesService_Create(t *testing.T) {
 		assert.Nil(t, resp)
 		assert.Contains(t, err.Error(), "Invalid prompt")
 	})
+
+	t.Run("invalid custom size", func(t *testing.T) {
+		t.Parallel()
+
+		called := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+		defer server.Close()
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		req := imagestypes.NewImageGenerationRequest(imagestypes.ModelCogView4, "A cat").
+			SetCustomSize(4096, 4096)
+
+		resp, err := client.Images.Create(context.Background(), req)
+		assert.Error(t, err)
+		assert.Nil(t, resp)
+		assert.True(t, errors.IsValidationError(err))
+		assert.False(t, called)
+	})
+}
+
+func TestImagesService_Create_ContentPolicy(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]any{
+			"contentFilter": []any{map[string]any{"role": "user", "level": 1}},
+			"error":         map[string]any{"code": "1301", "message": "unsafe content"},
+		})
+	})
+	ctx := context.Background()
+
+	_, err := client.Images.Create(ctx, imagestypes.NewImageGenerationRequest("cogview-4", "prompt"))
+	var policyErr *errors.ContentPolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, "1301", policyErr.Code)
+	assert.Equal(t, "prompt", policyErr.Field)
+
+	// Chat completions and video generation map the same errors
+	_, err = client.Chat.Create(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("prompt")}))
+	assert.True(t, errors.IsContentPolicyError(err), "chat: %v", err)
+	_, err = client.Videos.Create(ctx, videos.NewTextToVideoRequest(videos.ModelCogVideoX, "prompt"))
+	assert.True(t, errors.IsContentPolicyError(err), "videos: %v", err)
 }
 
 func TestImagesService_Generate(t *testing.T) {
@@ -284,7 +348,9 @@ func TestImagesService_Generate(t *testing.T) {
 			"cogview-3",
 			"test",
 		)
-		require.NoError(t, err)
+		var emptyErr *errors.EmptyResultError
+		require.ErrorAs(t, err, &emptyErr)
+		assert.Equal(t, "Images.Generate", emptyErr.Operation)
 		assert.Empty(t, imageURL)
 	})
 
@@ -431,8 +497,8 @@ func TestImagesService_GenerateMultiple(t *testing.T) {
 			"test",
 			3,
 		)
-		require.NoError(t, err)
-		assert.Len(t, images, 0)
+		assert.True(t, errors.IsEmptyResultError(err))
+		assert.Empty(t, images)
 	})
 
 	t.Run("API error", func(t *testing.T) {
@@ -468,6 +534,134 @@ func TestImagesService_GenerateMultiple(t *testing.T) {
 	})
 }
 
+func TestImagesService_Download(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\nimage-bytes")
+
+	var imageCalls int32
+	var sawAuth atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/images/generations":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(imagestypes.ImageGenerationResponse{
+				Data: []imagestypes.ImageData{{URL: "http://" + r.Host + "/image"}},
+			})
+		case "/image":
+			if r.Header.Get("Authorization") != "" {
+				sawAuth.Store(true)
+			}
+			// Fail the first attempt to exercise retries
+			if atomic.AddInt32(&imageCalls, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(png)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	path, err := client.Images.GenerateToFile(context.Background(), imagestypes.ModelCogView4, "A fox", filepath.Join(t.TempDir(), "fox"))
+	require.NoError(t, err)
+	assert.Equal(t, ".png", filepath.Ext(path))
+
+	fromURL, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	fromB64, err := client.Images.Download(context.Background(), &imagestypes.ImageData{
+		B64JSON: base64.StdEncoding.EncodeToString(png),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, png, fromURL)
+	assert.Equal(t, fromURL, fromB64)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&imageCalls))
+	assert.False(t, sawAuth.Load(), "credentials must not be sent to image URLs")
+}
+
+func TestImagesService_Download_ContextOverrides(t *testing.T) {
+	t.Parallel()
+
+	const signedQuery = "X-Amz-Signature=a%2Fb%3D&Expires=1700000000&b=1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "context headers are meant for the API")
+		assert.Empty(t, r.Header.Get("Idempotency-Key"))
+		assert.NotEqual(t, "trace-123", r.Header.Get("X-Request-ID"))
+		assert.Equal(t, signedQuery, r.URL.RawQuery, "the presigned query is sent as is")
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\nimage-bytes"))
+	}))
+	defer server.Close()
+
+	// One request a minute: a download counting against the limiter
+	// would block the next one
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithRateLimit(1, 0),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx := WithHeaders(context.Background(), http.Header{"Authorization": {"Bearer leaked"}})
+	ctx = WithQueryParams(ctx, url.Values{"debug": {"1"}})
+	ctx = WithIdempotencyKey(ctx, "key-1")
+	ctx = WithRequestID(ctx, "trace-123")
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	image := &imagestypes.ImageData{URL: server.URL + "/image?" + signedQuery}
+	for i := 0; i < 3; i++ {
+		data, err := client.Images.Download(ctx, image)
+		require.NoError(t, err)
+		assert.NotEmpty(t, data)
+	}
+}
+
+func TestImagesService_Download_MaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	image := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 4096)...)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		if r.URL.Path == "/chunked" {
+			// Without a Content-Length the limit applies while reading
+			w.Write(image[:1024])
+			w.(http.Flusher).Flush()
+			w.Write(image[1024:])
+			return
+		}
+		w.Write(image)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithMaxResponseBytes(2048),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for _, path := range []string{"/image", "/chunked"} {
+		data, err := client.Images.Download(context.Background(), &imagestypes.ImageData{URL: server.URL + path})
+		assert.Nil(t, data, path)
+		var sizeErr *errors.PayloadTooLargeError
+		require.ErrorAs(t, err, &sizeErr, path)
+		assert.Equal(t, "response", sizeErr.Payload)
+		assert.Equal(t, int64(2048), sizeErr.Limit)
+	}
+}
+
 func TestClient_ImagesService_Integration(t *testing.T) {
 	t.Parallel()
 
@@ -540,3 +734,211 @@ func TestClient_ImagesService_Integration(t *testing.T) {
 		assert.Len(t, urls, 2)
 	})
 }
+
+// imageFormServer returns a mock server that parses multipart image
+// requests with check and responds with a single image URL.
+func imageFormServer(t *testing.T, path string, check func(form *multipart.Form)) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, path, r.URL.Path)
+		assert.True(t, strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data"))
+		require.NoError(t, r.ParseMultipartForm(1<<20))
+		check(r.MultipartForm)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(imagestypes.ImageGenerationResponse{
+			Created: 1677652288,
+			Data:    []imagestypes.ImageData{{URL: "https://example.com/edited.png"}},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// formFile returns the name and content of the file part field of form.
+func formFile(t *testing.T, form *multipart.Form, field string) (string, string) {
+	t.Helper()
+
+	require.Len(t, form.File[field], 1, field)
+	header := form.File[field][0]
+	file, err := header.Open()
+	require.NoError(t, err)
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	require.NoError(t, err)
+	return header.Filename, string(content)
+}
+
+func TestImagesService_Edit(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uploads image and mask", func(t *testing.T) {
+		t.Parallel()
+
+		server := imageFormServer(t, "/images/edits", func(form *multipart.Form) {
+			assert.Equal(t, []string{"cogview-4"}, form.Value["model"])
+			assert.Equal(t, []string{"Add a red sofa"}, form.Value["prompt"])
+			assert.Equal(t, []string{"1024x1024"}, form.Value["size"])
+			assert.Equal(t, []string{"2"}, form.Value["n"])
+			assert.Equal(t, []string{"url"}, form.Value["response_format"])
+			assert.Equal(t, []string{"user-123456"}, form.Value["user_id"])
+
+			name, content := formFile(t, form, "image")
+			assert.Equal(t, "room.png", name)
+			assert.Equal(t, "room bytes", content)
+			name, content = formFile(t, form, "mask")
+			assert.Equal(t, "mask.png", name)
+			assert.Equal(t, "mask bytes", content)
+		})
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		req := imagestypes.NewImageEditRequest(imagestypes.ModelCogView4, "Add a red sofa",
+			imagestypes.ImageFromReader(strings.NewReader("room bytes"), "room.png")).
+			SetMask(imagestypes.ImageFromBase64(base64.StdEncoding.EncodeToString([]byte("mask bytes")), "mask.png")).
+			SetSize(imagestypes.Size1024x1024).
+			SetN(2).
+			SetResponseFormat(imagestypes.ResponseFormatURL).
+			SetUserID("user-123456")
+
+		resp, err := client.Images.Edit(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/edited.png", resp.GetFirstImage().GetImageURL())
+	})
+
+	t.Run("references images by URL", func(t *testing.T) {
+		t.Parallel()
+
+		server := imageFormServer(t, "/images/edits", func(form *multipart.Form) {
+			assert.Equal(t, []string{"https://example.com/room.png"}, form.Value["image_url"])
+			assert.Equal(t, []string{"https://example.com/mask.png"}, form.Value["mask_url"])
+			assert.Empty(t, form.File)
+			assert.NotContains(t, form.Value, "n")
+			assert.NotContains(t, form.Value, "size")
+		})
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		req := imagestypes.NewImageEditRequest(imagestypes.ModelCogView4, "Add a red sofa",
+			imagestypes.ImageFromURL("https://example.com/room.png")).
+			SetMask(imagestypes.ImageFromURL("https://example.com/mask.png"))
+
+		_, err = client.Images.Edit(context.Background(), req)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid request is not sent", func(t *testing.T) {
+		t.Parallel()
+
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+		}))
+		defer server.Close()
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		req := imagestypes.NewImageEditRequest(imagestypes.ModelCogView4, "Add a red sofa", nil).
+			SetMask(imagestypes.ImageFromURL("https://example.com/mask.png"))
+
+		_, err = client.Images.Edit(context.Background(), req)
+		var validationErr *errors.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "mask", validationErr.Field)
+		assert.Zero(t, calls.Load())
+	})
+
+	t.Run("EditImage", func(t *testing.T) {
+		t.Parallel()
+
+		server := imageFormServer(t, "/images/edits", func(form *multipart.Form) {
+			assert.Equal(t, []string{"Make it snow"}, form.Value["prompt"])
+			name, content := formFile(t, form, "image")
+			assert.Equal(t, "street.jpg", name)
+			assert.Equal(t, "street bytes", content)
+			assert.NotContains(t, form.File, "mask")
+		})
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		imageURL, err := client.Images.EditImage(context.Background(), imagestypes.ModelCogView4,
+			"Make it snow", strings.NewReader("street bytes"), "street.jpg")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/edited.png", imageURL)
+	})
+}
+
+func TestImagesService_Variations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("uploads image", func(t *testing.T) {
+		t.Parallel()
+
+		server := imageFormServer(t, "/images/variations", func(form *multipart.Form) {
+			assert.Equal(t, []string{"cogview-4"}, form.Value["model"])
+			assert.Equal(t, []string{"3"}, form.Value["n"])
+			assert.NotContains(t, form.Value, "prompt")
+			name, content := formFile(t, form, "image")
+			assert.Equal(t, "logo.png", name)
+			assert.Equal(t, "logo bytes", content)
+		})
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		req := imagestypes.NewImageVariationRequest(imagestypes.ModelCogView4,
+			imagestypes.ImageFromBytes([]byte("logo bytes"), "logo.png")).SetN(3)
+
+		resp, err := client.Images.Variations(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/edited.png"}, resp.GetImageURLs())
+	})
+
+	t.Run("VaryImage", func(t *testing.T) {
+		t.Parallel()
+
+		server := imageFormServer(t, "/images/variations", func(form *multipart.Form) {
+			assert.Equal(t, []string{"2"}, form.Value["n"])
+			_, content := formFile(t, form, "image")
+			assert.Equal(t, "logo bytes", content)
+		})
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		imageURLs, err := client.Images.VaryImage(context.Background(), imagestypes.ModelCogView4,
+			strings.NewReader("logo bytes"), "logo.png", 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://example.com/edited.png"}, imageURLs)
+	})
+}
@@ -0,0 +1,121 @@
+package zai
+
+import (
+	"context"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+)
+
+// ChatStream is a stream of chat completion chunks. The stream returned
+// by ChatService.CreateStream implements it, and so do the fakes of the
+// zaitest package.
+//
+// Example:
+//
+//	func printStream(stream zai.ChatStream) error {
+//	    defer stream.Close()
+//	    for stream.Next() {
+//	        fmt.Print(stream.Current().GetContent())
+//	    }
+//	    return stream.Err()
+//	}
+type ChatStream interface {
+	// Next advances to the next chunk. It returns false when the stream
+	// ends or fails.
+	Next() bool
+
+	// Current returns the current chunk.
+	Current() *chat.ChatCompletionChunk
+
+	// Err returns the error that ended the stream, if any.
+	Err() error
+
+	// Close closes the stream.
+	Close() error
+}
+
+// ChatAPI is the part of the Chat Completions API that code consuming
+// completions usually needs. Depend on it instead of *ChatService to
+// unit test that code with zaitest.FakeChat. Client.ChatAPI returns the
+// implementation backed by the client.
+//
+// Example:
+//
+//	func summarize(ctx context.Context, api zai.ChatAPI, text string) (string, error) {
+//	    resp, err := api.Create(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{
+//	        chat.NewUserMessage("Summarize: " + text),
+//	    }))
+//	    if err != nil {
+//	        return "", err
+//	    }
+//	    return resp.GetContent(), nil
+//	}
+//
+//	summary, err := summarize(ctx, client.ChatAPI(), text)
+type ChatAPI interface {
+	// Create creates a chat completion, as ChatService.Create.
+	Create(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.CreateOption) (*chat.ChatCompletionResponse, error)
+
+	// CreateStream creates a streaming chat completion, as
+	// ChatService.CreateStream. The caller must close the stream.
+	CreateStream(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (ChatStream, error)
+}
+
+// EmbeddingsAPI is the part of the Embeddings API that code consuming
+// embeddings usually needs. *EmbeddingsService implements it, and so does
+// zaitest.FakeEmbeddings.
+type EmbeddingsAPI interface {
+	// Create creates embeddings, as EmbeddingsService.Create.
+	Create(ctx context.Context, req *embeddings.EmbeddingRequest) (*embeddings.EmbeddingResponse, error)
+
+	// CreateSingle embeds one text with model.
+	CreateSingle(ctx context.Context, model, text string) ([]float64, error)
+
+	// CreateBatch embeds texts with model.
+	CreateBatch(ctx context.Context, model string, texts []string) ([][]float64, error)
+
+	// Embed embeds one text with the default embedding model.
+	Embed(ctx context.Context, text string) ([]float64, error)
+
+	// EmbedBatch embeds texts with the default embedding model.
+	EmbedBatch(ctx context.Context, texts []string) ([][]float64, error)
+}
+
+var (
+	_ ChatAPI       = chatAPI{}
+	_ ChatStream    = (*streaming.Stream[chat.ChatCompletionChunk])(nil)
+	_ EmbeddingsAPI = (*EmbeddingsService)(nil)
+)
+
+// ChatAPI returns the Chat Completions API of the client as a ChatAPI.
+// It is backed by c.Chat, whose CreateStream returns a concrete stream
+// type rather than a ChatStream.
+func (c *Client) ChatAPI() ChatAPI {
+	return chatAPI{c.Chat}
+}
+
+// EmbeddingsAPI returns the Embeddings API of the client as an
+// EmbeddingsAPI, that is c.Embeddings.
+func (c *Client) EmbeddingsAPI() EmbeddingsAPI {
+	return c.Embeddings
+}
+
+// chatAPI adapts a ChatService to ChatAPI.
+type chatAPI struct {
+	service *ChatService
+}
+
+func (a chatAPI) Create(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.CreateOption) (*chat.ChatCompletionResponse, error) {
+	return a.service.Create(ctx, req, opts...)
+}
+
+func (a chatAPI) CreateStream(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (ChatStream, error) {
+	stream, err := a.service.CreateStream(ctx, req, opts...)
+	if err != nil {
+		// A nil *Stream in a ChatStream would not compare equal to nil
+		return nil, err
+	}
+	return stream, nil
+}
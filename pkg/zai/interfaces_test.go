@@ -0,0 +1,46 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+func TestClient_ChatAPI(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, part := range []string{"Hello", ", world"} {
+			fmt.Fprintf(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", part)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hi")})
+
+	var api ChatAPI = client.ChatAPI()
+	stream, err := api.CreateStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content strings.Builder
+	for stream.Next() {
+		content.WriteString(stream.Current().GetContent())
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "Hello, world", content.String())
+
+	// An invalid request returns a nil interface, not a nil stream in one
+	stream, err = api.CreateStream(context.Background(), &chat.ChatCompletionRequest{})
+	assert.Error(t, err)
+	assert.True(t, stream == nil)
+
+	assert.Same(t, client.Embeddings, client.EmbeddingsAPI())
+}
@@ -0,0 +1,252 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/knowledge"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/pagination"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// KnowledgeService provides access to the Knowledge Base API, which stores
+// documents in collections and retrieves the chunks relevant to a query.
+type KnowledgeService struct {
+	client *client.BaseClient
+}
+
+// newKnowledgeService creates a new knowledge service.
+func newKnowledgeService(baseClient *client.BaseClient) *KnowledgeService {
+	return &KnowledgeService{
+		client: baseClient,
+	}
+}
+
+// CreateCollection creates a document collection.
+//
+// Example:
+//
+//	req := knowledge.NewCreateCollectionRequest("product-docs").
+//	    SetDescription("Product manuals")
+//	collection, err := client.Knowledge.CreateCollection(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Collection ID: %s\n", collection.ID)
+func (s *KnowledgeService) CreateCollection(ctx context.Context, req *knowledge.CreateCollectionRequest) (*knowledge.Collection, error) {
+	if req == nil || req.Name == "" {
+		return nil, errors.NewValidationError("name", "is required", nil)
+	}
+
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.Knowledge), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection knowledge.Collection
+	if err := s.client.ParseJSON(apiResp, &collection); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// AddDocument adds a file uploaded with the Files API to a collection.
+// chunking controls how the file is split; nil uses the platform defaults.
+// The document is processed asynchronously: it can be queried once its
+// status is knowledge.DocumentStatusReady.
+//
+// Example:
+//
+//	doc, err := client.Knowledge.AddDocument(ctx, collection.ID, file.ID, &knowledge.ChunkingOptions{
+//	    Strategy:     knowledge.ChunkingFixed,
+//	    ChunkSize:    512,
+//	    ChunkOverlap: 64,
+//	})
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	fmt.Printf("Document %s: %s\n", doc.ID, doc.Status)
+func (s *KnowledgeService) AddDocument(ctx context.Context, collectionID, fileID string, chunking *knowledge.ChunkingOptions) (*knowledge.Document, error) {
+	if collectionID == "" {
+		return nil, fmt.Errorf("collection ID cannot be empty")
+	}
+	if fileID == "" {
+		return nil, fmt.Errorf("file ID cannot be empty")
+	}
+
+	req := &knowledge.AddDocumentRequest{
+		FileID:   fileID,
+		Chunking: chunking,
+	}
+
+	path := s.client.Endpoints().Path(endpoints.KnowledgeDocuments, collectionID)
+	apiResp, err := s.client.Post(ctx, path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc knowledge.Document
+	if err := s.client.ParseJSON(apiResp, &doc); err != nil {
+		return nil, err
+	}
+
+	return &doc, nil
+}
+
+// ListDocuments lists one page of the documents in a collection.
+//
+// Example:
+//
+//	resp, err := client.Knowledge.ListDocuments(ctx, collection.ID, knowledge.WithLimit(20))
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, doc := range resp.Data {
+//	    fmt.Printf("Document %s: %s\n", doc.ID, doc.Status)
+//	}
+func (s *KnowledgeService) ListDocuments(ctx context.Context, collectionID string, opts ...knowledge.ListOption) (*knowledge.DocumentListResponse, error) {
+	if collectionID == "" {
+		return nil, fmt.Errorf("collection ID cannot be empty")
+	}
+
+	return s.listDocuments(ctx, collectionID, knowledge.NewListParams(opts...))
+}
+
+// listDocuments fetches one page of documents.
+func (s *KnowledgeService) listDocuments(ctx context.Context, collectionID string, params *knowledge.ListParams) (*knowledge.DocumentListResponse, error) {
+	path := s.client.Endpoints().Path(endpoints.KnowledgeDocuments, collectionID)
+	apiResp, err := s.client.Get(ctx, path, params.Query())
+	if err != nil {
+		return nil, err
+	}
+
+	var resp knowledge.DocumentListResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// ListDocumentsAutoPaging returns an iterator over all documents in a
+// collection, fetching pages lazily as the iteration advances.
+// knowledge.WithAfter sets the starting cursor and knowledge.WithLimit the
+// page size.
+//
+// Example:
+//
+//	iter := client.Knowledge.ListDocumentsAutoPaging(ctx, collection.ID)
+//	for iter.Next() {
+//	    doc := iter.Current()
+//	    fmt.Printf("Document %s: %s\n", doc.ID, doc.Status)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *KnowledgeService) ListDocumentsAutoPaging(ctx context.Context, collectionID string, opts ...knowledge.ListOption) *pagination.Iterator[knowledge.Document] {
+	params := knowledge.NewListParams(opts...)
+
+	return pagination.NewIterator(ctx, params.After, func(ctx context.Context, cursor string) (*pagination.Page[knowledge.Document], error) {
+		if collectionID == "" {
+			return nil, fmt.Errorf("collection ID cannot be empty")
+		}
+
+		pageParams := *params
+		pageParams.After = cursor
+
+		resp, err := s.listDocuments(ctx, collectionID, &pageParams)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[knowledge.Document]{
+			Items:   resp.Data,
+			HasMore: resp.HasMore,
+			Cursor:  resp.GetLastID(),
+		}, nil
+	})
+}
+
+// DeleteDocument removes a document from a collection. The source file is
+// not deleted.
+//
+// Example:
+//
+//	resp, err := client.Knowledge.DeleteDocument(ctx, collection.ID, doc.ID)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if resp.IsDeleted() {
+//	    fmt.Println("Document deleted successfully")
+//	}
+func (s *KnowledgeService) DeleteDocument(ctx context.Context, collectionID, documentID string) (*knowledge.DeleteDocumentResponse, error) {
+	if collectionID == "" {
+		return nil, fmt.Errorf("collection ID cannot be empty")
+	}
+	if documentID == "" {
+		return nil, fmt.Errorf("document ID cannot be empty")
+	}
+
+	path := s.client.Endpoints().Path(endpoints.KnowledgeDocument, collectionID, documentID)
+	apiResp, err := s.client.Delete(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp knowledge.DeleteDocumentResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Query retrieves the topK chunks of a collection most relevant to query,
+// most relevant first. A topK of zero uses the API default.
+//
+// Example:
+//
+//	resp, err := client.Knowledge.Query(ctx, collection.ID, "How do I reset the device?", 5)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, chunk := range resp.Data {
+//	    fmt.Printf("%.2f %s[%d:%d]: %s\n", chunk.Score, chunk.FileID, chunk.StartOffset, chunk.EndOffset, chunk.Text)
+//	}
+func (s *KnowledgeService) Query(ctx context.Context, collectionID, query string, topK int) (*knowledge.QueryResponse, error) {
+	if collectionID == "" {
+		return nil, fmt.Errorf("collection ID cannot be empty")
+	}
+	if query == "" {
+		return nil, errors.NewValidationError("query", "is required", nil)
+	}
+	if topK < 0 {
+		return nil, errors.NewValidationError("top_k", "must not be negative", topK)
+	}
+
+	req := &knowledge.QueryRequest{
+		Query: query,
+		TopK:  topK,
+	}
+
+	path := s.client.Endpoints().Path(endpoints.KnowledgeQuery, collectionID)
+	apiResp, err := s.client.Post(ctx, path, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp knowledge.QueryResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,275 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/knowledge"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// knowledgeServer is an in-memory Knowledge Base API.
+type knowledgeServer struct {
+	mu          sync.Mutex
+	collections map[string]*knowledge.Collection
+	documents   map[string][]knowledge.Document
+	chunking    map[string]*knowledge.ChunkingOptions
+	nextID      int
+}
+
+func newKnowledgeServer() *knowledgeServer {
+	return &knowledgeServer{
+		collections: make(map[string]*knowledge.Collection),
+		documents:   make(map[string][]knowledge.Document),
+		chunking:    make(map[string]*knowledge.ChunkingOptions),
+	}
+}
+
+func (s *knowledgeServer) id(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func (s *knowledgeServer) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /knowledge", func(w http.ResponseWriter, r *http.Request) {
+		var req knowledge.CreateCollectionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		c := &knowledge.Collection{ID: s.id("kb"), Object: "knowledge", Name: req.Name, Description: req.Description}
+		s.collections[c.ID] = c
+		writeJSON(w, c)
+	})
+
+	mux.HandleFunc("POST /knowledge/{id}/documents", func(w http.ResponseWriter, r *http.Request) {
+		var req knowledge.AddDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		kbID := r.PathValue("id")
+		if s.collections[kbID] == nil {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"code":"1211","message":"knowledge not found"}}`))
+			return
+		}
+		doc := knowledge.Document{ID: s.id("doc"), CollectionID: kbID, FileID: req.FileID, Status: knowledge.DocumentStatusReady}
+		s.documents[kbID] = append(s.documents[kbID], doc)
+		s.chunking[doc.ID] = req.Chunking
+		writeJSON(w, doc)
+	})
+
+	mux.HandleFunc("GET /knowledge/{id}/documents", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		docs := s.documents[r.PathValue("id")]
+
+		start := 0
+		if after := r.URL.Query().Get("after"); after != "" {
+			for i, doc := range docs {
+				if doc.ID == after {
+					start = i + 1
+				}
+			}
+		}
+		limit := len(docs)
+		if l := r.URL.Query().Get("limit"); l != "" {
+			limit, _ = strconv.Atoi(l)
+		}
+		end := min(start+limit, len(docs))
+
+		writeJSON(w, knowledge.DocumentListResponse{
+			Object:  "list",
+			Data:    docs[start:end],
+			HasMore: end < len(docs),
+		})
+	})
+
+	mux.HandleFunc("DELETE /knowledge/{id}/documents/{doc}", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		kbID, docID := r.PathValue("id"), r.PathValue("doc")
+		docs := s.documents[kbID]
+		for i, doc := range docs {
+			if doc.ID == docID {
+				s.documents[kbID] = append(docs[:i:i], docs[i+1:]...)
+				writeJSON(w, knowledge.DeleteDocumentResponse{ID: docID, Object: "knowledge.document", Deleted: true})
+				return
+			}
+		}
+		writeJSON(w, knowledge.DeleteDocumentResponse{ID: docID, Object: "knowledge.document"})
+	})
+
+	mux.HandleFunc("POST /knowledge/{id}/query", func(w http.ResponseWriter, r *http.Request) {
+		var req knowledge.QueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var chunks []knowledge.Chunk
+		for i, doc := range s.documents[r.PathValue("id")] {
+			if len(chunks) == req.TopK {
+				break
+			}
+			chunks = append(chunks, knowledge.Chunk{
+				Text:        req.Query + " answer",
+				Score:       1 / float64(i+1),
+				DocumentID:  doc.ID,
+				FileID:      doc.FileID,
+				StartOffset: 100 * i,
+				EndOffset:   100*i + 50,
+			})
+		}
+		writeJSON(w, knowledge.QueryResponse{Object: "list", Data: chunks})
+	})
+
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestKnowledgeService_Lifecycle(t *testing.T) {
+	t.Parallel()
+
+	server := newKnowledgeServer()
+	client := newRawTestClient(t, server.handler().ServeHTTP)
+	ctx := context.Background()
+
+	collection, err := client.Knowledge.CreateCollection(ctx,
+		knowledge.NewCreateCollectionRequest("manuals").SetDescription("Product manuals"))
+	require.NoError(t, err)
+	assert.Equal(t, "manuals", collection.Name)
+	assert.Equal(t, "Product manuals", collection.Description)
+	require.NotEmpty(t, collection.ID)
+
+	chunking := &knowledge.ChunkingOptions{Strategy: knowledge.ChunkingFixed, ChunkSize: 512, ChunkOverlap: 64}
+	var docIDs []string
+	for i := range 3 {
+		doc, err := client.Knowledge.AddDocument(ctx, collection.ID, fmt.Sprintf("file-%d", i), chunking)
+		require.NoError(t, err)
+		assert.Equal(t, collection.ID, doc.CollectionID)
+		assert.Equal(t, fmt.Sprintf("file-%d", i), doc.FileID)
+		assert.True(t, doc.IsReady())
+		assert.Equal(t, chunking, server.chunking[doc.ID])
+		docIDs = append(docIDs, doc.ID)
+	}
+
+	page, err := client.Knowledge.ListDocuments(ctx, collection.ID, knowledge.WithLimit(2))
+	require.NoError(t, err)
+	assert.Len(t, page.Data, 2)
+	assert.True(t, page.HasMore)
+
+	var listed []string
+	iter := client.Knowledge.ListDocumentsAutoPaging(ctx, collection.ID, knowledge.WithLimit(2))
+	for iter.Next() {
+		listed = append(listed, iter.Current().ID)
+	}
+	require.NoError(t, iter.Err())
+	assert.Equal(t, docIDs, listed)
+
+	deleted, err := client.Knowledge.DeleteDocument(ctx, collection.ID, docIDs[1])
+	require.NoError(t, err)
+	assert.True(t, deleted.IsDeleted())
+	assert.Equal(t, docIDs[1], deleted.ID)
+
+	page, err = client.Knowledge.ListDocuments(ctx, collection.ID)
+	require.NoError(t, err)
+	require.Len(t, page.Data, 2)
+	assert.Equal(t, docIDs[0], page.Data[0].ID)
+	assert.Equal(t, docIDs[2], page.Data[1].ID)
+	assert.False(t, page.HasMore)
+
+	_, err = client.Knowledge.AddDocument(ctx, "kb-missing", "file-0", nil)
+	assert.IsType(t, &errors.APIStatusError{}, err)
+}
+
+func TestKnowledgeService_Query(t *testing.T) {
+	t.Parallel()
+
+	server := newKnowledgeServer()
+	client := newRawTestClient(t, server.handler().ServeHTTP)
+	ctx := context.Background()
+
+	collection, err := client.Knowledge.CreateCollection(ctx, knowledge.NewCreateCollectionRequest("manuals"))
+	require.NoError(t, err)
+	for _, fileID := range []string{"file-a", "file-b", "file-c"} {
+		_, err := client.Knowledge.AddDocument(ctx, collection.ID, fileID, nil)
+		require.NoError(t, err)
+	}
+
+	resp, err := client.Knowledge.Query(ctx, collection.ID, "reset", 2)
+	require.NoError(t, err)
+
+	chunks := resp.GetChunks()
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "reset answer", chunks[0].Text)
+	assert.Equal(t, 1.0, chunks[0].Score)
+	assert.Equal(t, "file-a", chunks[0].FileID)
+	assert.Equal(t, 0, chunks[0].StartOffset)
+	assert.Equal(t, 50, chunks[0].EndOffset)
+	assert.Equal(t, 0.5, chunks[1].Score)
+	assert.Equal(t, "file-b", chunks[1].FileID)
+	assert.Equal(t, 100, chunks[1].StartOffset)
+	assert.Equal(t, 150, chunks[1].EndOffset)
+}
+
+func TestKnowledgeService_Validation(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+	ctx := context.Background()
+
+	_, err := client.Knowledge.CreateCollection(ctx, knowledge.NewCreateCollectionRequest(""))
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "name", validationErr.Field)
+
+	_, err = client.Knowledge.AddDocument(ctx, "", "file-1", nil)
+	assert.EqualError(t, err, "collection ID cannot be empty")
+
+	_, err = client.Knowledge.AddDocument(ctx, "kb-1", "", nil)
+	assert.EqualError(t, err, "file ID cannot be empty")
+
+	_, err = client.Knowledge.ListDocuments(ctx, "")
+	assert.EqualError(t, err, "collection ID cannot be empty")
+
+	iter := client.Knowledge.ListDocumentsAutoPaging(ctx, "")
+	assert.False(t, iter.Next())
+	assert.EqualError(t, iter.Err(), "collection ID cannot be empty")
+
+	_, err = client.Knowledge.DeleteDocument(ctx, "kb-1", "")
+	assert.EqualError(t, err, "document ID cannot be empty")
+
+	_, err = client.Knowledge.Query(ctx, "kb-1", "", 5)
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "query", validationErr.Field)
+
+	_, err = client.Knowledge.Query(ctx, "kb-1", "reset", -1)
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "top_k", validationErr.Field)
+}
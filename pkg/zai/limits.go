@@ -0,0 +1,86 @@
+package zai
+
+// DefaultMaxRequestBytes is the largest request body the client sends
+// unless WithMaxRequestBytes is given.
+const DefaultMaxRequestBytes = 128 << 20
+
+// DefaultMaxResponseBytes is the largest response body the client reads
+// unless WithMaxResponseBytes is given.
+const DefaultMaxResponseBytes = 256 << 20
+
+// WithMaxRequestBytes limits the size of request bodies. Requests whose
+// serialized body is larger, including multipart uploads with their
+// files, fail with an *errors.PayloadTooLargeError carrying the actual
+// size before anything is sent. Uploads of a file whose size is unknown,
+// see WithUploadBufferSize, fail once the limit is reached while sending.
+// Zero or less removes the limit. The default is DefaultMaxRequestBytes.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithMaxRequestBytes(8 << 20), // 8 MiB
+//	)
+func WithMaxRequestBytes(n int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxRequestBytes = noLimitIfZero(n)
+	}
+}
+
+// WithMaxResponseBytes limits the size of response bodies, such as JSON
+// responses, downloaded file contents and images fetched from generated
+// image URLs. Reading a larger body fails with
+// an *errors.PayloadTooLargeError; a body whose Content-Length exceeds the
+// limit fails without being read. Streams are limited separately by
+// WithMaxStreamBytes. Zero or less removes the limit. The default is
+// DefaultMaxResponseBytes.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithMaxResponseBytes(1 << 30), // 1 GiB for large batch outputs
+//	)
+func WithMaxResponseBytes(n int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxResponseBytes = noLimitIfZero(n)
+	}
+}
+
+// WithMaxStreamBytes limits the total size of a streaming response. A
+// stream that grows larger fails with an *errors.PayloadTooLargeError.
+// Streams are not limited by default.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithMaxStreamBytes(16 << 20), // 16 MiB
+//	)
+func WithMaxStreamBytes(n int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.MaxStreamBytes = n
+	}
+}
+
+// noLimitIfZero maps the zero or negative size of an option to the
+// negative size that ClientConfig uses for no limit.
+func noLimitIfZero(n int64) int64 {
+	if n <= 0 {
+		return -1
+	}
+	return n
+}
+
+// payloadLimit returns the size limit to use for a ClientConfig size:
+// the default if zero, no limit if negative.
+func payloadLimit(n, defaultLimit int64) int64 {
+	switch {
+	case n == 0:
+		return defaultLimit
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
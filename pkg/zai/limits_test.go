@@ -0,0 +1,143 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLimitsTestClient(t *testing.T, handler http.HandlerFunc, opts ...ClientOption) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	opts = append([]ClientOption{
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+		WithMaxRetries(1),
+	}, opts...)
+	client, err := NewClient(opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestPayloadLimit(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, int64(DefaultMaxRequestBytes), payloadLimit(0, DefaultMaxRequestBytes))
+	assert.Equal(t, int64(0), payloadLimit(-1, DefaultMaxRequestBytes))
+	assert.Equal(t, int64(10), payloadLimit(10, DefaultMaxRequestBytes))
+
+	config := &ClientConfig{}
+	WithMaxRequestBytes(0)(config)
+	WithMaxResponseBytes(2048)(config)
+	assert.Equal(t, int64(-1), config.MaxRequestBytes, "zero removes the limit")
+	assert.Equal(t, int64(2048), config.MaxResponseBytes)
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+	assert.Equal(t, int64(DefaultMaxRequestBytes), client.baseClient.GetConfig().MaxRequestBytes)
+	assert.Equal(t, int64(DefaultMaxResponseBytes), client.baseClient.GetConfig().MaxResponseBytes)
+}
+
+func TestMaxRequestBytes_Chat(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}, WithMaxRequestBytes(1024))
+
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage(strings.Repeat("a", 2000))})
+	_, err := client.Chat.Create(context.Background(), req)
+
+	var sizeErr *errors.PayloadTooLargeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, "request", sizeErr.Payload)
+	assert.Greater(t, sizeErr.Size, int64(2000))
+	assert.Equal(t, int64(1024), sizeErr.Limit)
+	assert.Zero(t, requests.Load())
+}
+
+func TestMaxRequestBytes_Upload(t *testing.T) {
+	t.Parallel()
+
+	var requests atomic.Int32
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"id":"file-1","object":"file"}`)
+	}, WithMaxRequestBytes(4096))
+
+	// The file alone is under the limit, but not with the multipart framing
+	upload := func(size int) error {
+		req := files.NewFileUploadRequest(bytes.NewReader(make([]byte, size)), "data.jsonl", files.PurposeBatch).
+			SetSkipValidation(true)
+		_, err := client.Files.Upload(context.Background(), req)
+		return err
+	}
+
+	var sizeErr *errors.PayloadTooLargeError
+	require.ErrorAs(t, upload(4000), &sizeErr)
+	assert.Greater(t, sizeErr.Size, int64(4000))
+	assert.Zero(t, requests.Load())
+
+	require.NoError(t, upload(1000))
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestMaxResponseBytes(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":"chatcmpl-1","choices":[{"message":{"role":"assistant","content":%q}}]}`, strings.Repeat("a", 4096))
+	}, WithMaxResponseBytes(1024))
+
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")})
+	_, err := client.Chat.Create(context.Background(), req)
+	assert.True(t, errors.IsPayloadTooLargeError(err), "got %v", err)
+
+	unlimited := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":"chatcmpl-1","choices":[{"message":{"role":"assistant","content":%q}}]}`, strings.Repeat("a", 4096))
+	}, WithMaxResponseBytes(0))
+	resp, err := unlimited.Chat.Create(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, resp.GetContent(), 4096)
+}
+
+func TestMaxStreamBytes(t *testing.T) {
+	t.Parallel()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for range 100 {
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"0123456789\"}}]}\n\n")
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")})
+
+	// Streams are not limited by the response limit
+	client := newLimitsTestClient(t, handler, WithMaxResponseBytes(1024))
+	content, err := client.Chat.StreamContent(context.Background(), req)
+	require.NoError(t, err)
+	assert.Len(t, content, 1000)
+
+	client = newLimitsTestClient(t, handler, WithMaxStreamBytes(1024))
+	_, err = client.Chat.StreamContent(context.Background(), req)
+	var sizeErr *errors.PayloadTooLargeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, "stream", sizeErr.Payload)
+}
@@ -0,0 +1,82 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/models"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+)
+
+// ModelsService provides access to the Models API.
+type ModelsService struct {
+	client *client.BaseClient
+}
+
+// newModelsService creates a new models service.
+func newModelsService(baseClient *client.BaseClient) *ModelsService {
+	return &ModelsService{
+		client: baseClient,
+	}
+}
+
+// List lists the models available to the API key.
+//
+// Example:
+//
+//	resp, err := client.Models.List(ctx)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, m := range resp.Data {
+//	    fmt.Printf("%s (context window: %d)\n", m.ID, m.ContextWindow)
+//	}
+func (s *ModelsService) List(ctx context.Context) (*models.ModelListResponse, error) {
+	// Make the API request
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.Models), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp models.ModelListResponse
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+// Retrieve retrieves a model by ID.
+//
+// Example:
+//
+//	model, err := client.Models.Retrieve(ctx, models.GLM47)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if model.Capabilities != nil && model.Capabilities.Vision {
+//	    fmt.Println("Model accepts images")
+//	}
+func (s *ModelsService) Retrieve(ctx context.Context, modelID models.ID) (*models.Model, error) {
+	if modelID == "" {
+		return nil, fmt.Errorf("model ID cannot be empty")
+	}
+
+	// Make the API request
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.Model, modelID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the response
+	var resp models.Model
+	if err := s.client.ParseJSON(apiResp, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,109 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModelsService_List(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/models", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{
+			"object": "list",
+			"data": [
+				{"id": "glm-4.7", "object": "model", "owned_by": "z-ai", "context_window": 200000, "capabilities": {"chat": true, "thinking": true}},
+				{"id": "embedding-3", "object": "model", "owned_by": "z-ai", "capabilities": {"embeddings": true}}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Models.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "list", resp.Object)
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, models.GLM47, resp.Data[0].ID)
+	assert.Equal(t, 200000, resp.Data[0].ContextWindow)
+	assert.True(t, resp.Data[0].Capabilities.Thinking)
+	assert.Equal(t, models.Embedding3, resp.Data[1].ID)
+	assert.True(t, resp.Data[1].Capabilities.Embeddings)
+}
+
+func TestModelsService_Retrieve(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/models/glm-4.6v", r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"id": "glm-4.6v", "object": "model", "context_window": 131072, "max_output_tokens": 32768, "capabilities": {"chat": true, "vision": true}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	model, err := client.Models.Retrieve(context.Background(), models.GLM46V)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.GLM46V, model.ID)
+	assert.Equal(t, 131072, model.ContextWindow)
+	assert.Equal(t, 32768, model.MaxOutputTokens)
+	require.NotNil(t, model.Capabilities)
+	assert.True(t, model.Capabilities.Vision)
+}
+
+func TestModelsService_Retrieve_EmptyID(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+
+	_, err = client.Models.Retrieve(context.Background(), "")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "model ID cannot be empty")
+}
+
+func TestModelsService_Retrieve_NotFound(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": {"message": "Model not found", "code": "1211"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Models.Retrieve(context.Background(), models.ID("glm-unknown"))
+	assert.Error(t, err)
+}
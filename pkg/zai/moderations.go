@@ -5,6 +5,7 @@ import (
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 )
 
 // ModerationsService provides access to the Moderations API.
@@ -41,8 +42,15 @@ func newModerationsService(baseClient *client.BaseClient) *ModerationsService {
 //	    }
 //	}
 func (s *ModerationsService) Create(ctx context.Context, req *moderation.ModerationRequest) (*moderation.ModerationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.client.Sanitize(ServiceModerations, req); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/moderations", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.Moderations), req)
 	if err != nil {
 		return nil, err
 	}
@@ -73,6 +81,23 @@ func (s *ModerationsService) CheckText(ctx context.Context, model string, text s
 	return s.Create(ctx, req)
 }
 
+// CheckImage is a convenience method for checking a single image by URL.
+//
+// Example:
+//
+//	resp, err := client.Moderations.CheckImage(ctx, "moderation", "https://example.com/upload.png")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if resp.IsFlagged() {
+//	    fmt.Println("Image flagged")
+//	}
+func (s *ModerationsService) CheckImage(ctx context.Context, model string, imageURL string) (*moderation.ModerationResponse, error) {
+	req := moderation.NewImageModerationRequest(model, imageURL)
+	return s.Create(ctx, req)
+}
+
 // CheckBatch is a convenience method for checking multiple text strings at once.
 //
 // Example:
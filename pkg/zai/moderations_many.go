@@ -0,0 +1,209 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// errResultCount is returned for a batch whose response does not have one
+// result per text.
+var errResultCount = stderrors.New("moderation: the number of results does not match the texts")
+
+// CheckMany moderates a large number of texts. The texts are sent in
+// batches of moderation.DefaultBatchSize, at most
+// moderation.DefaultManyConcurrency at once; moderation.WithBatchSize and
+// moderation.WithConcurrency change both. A batch that fails with a rate
+// limit, server or connection error is retried up to
+// moderation.DefaultBatchRetries times.
+//
+// The results are index-aligned with texts and summarized per category.
+// With moderation.WithResults, every result is also passed to a callback
+// as soon as its batch completes, for progress reporting or to act on
+// flagged texts early. If batches still fail after their retries, the
+// results of the other batches are returned with a *moderation.ManyError
+// listing the failed batches. Cancelling ctx fails the batches not yet
+// finished. An empty model uses moderation.DefaultModel.
+//
+// Example:
+//
+//	result, err := client.Moderations.CheckMany(ctx, "moderation", comments,
+//	    moderation.WithResults(func(item moderation.ManyItem) {
+//	        fmt.Printf("%d/%d\n", item.Completed, item.Total)
+//	    }),
+//	)
+//	if err != nil {
+//	    // result.Results[i] is nil for the texts of the failed batches
+//	}
+//	fmt.Println(result.Summary.Flagged, "flagged:", result.Summary.Categories)
+func (s *ModerationsService) CheckMany(ctx context.Context, model string, texts []string, opts ...moderation.ManyOption) (*moderation.ManyResult, error) {
+	if len(texts) == 0 {
+		return nil, errors.NewValidationError("texts", "must not be empty", len(texts))
+	}
+	if model == "" {
+		model = moderation.DefaultModel
+	}
+
+	cfg := moderation.NewManyConfig(opts...)
+	c := &manyCollector{
+		cfg: cfg,
+		result: &moderation.ManyResult{
+			Results: make([]*moderation.ModerationResult, len(texts)),
+			Summary: moderation.ManySummary{
+				Total:      len(texts),
+				Categories: make(map[string]int),
+				MaxScores:  make(map[string]float64),
+			},
+		},
+		pending: make(map[int][]moderation.ManyItem),
+	}
+
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(texts); start += cfg.BatchSize {
+		end := min(start+cfg.BatchSize, len(texts))
+		if ctx.Err() == nil {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			c.add(start, end, nil, &moderation.BatchError{Start: start, End: end, Err: err})
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results, attempts, err := s.checkBatch(ctx, model, texts[start:end], cfg)
+			if err != nil {
+				c.add(start, end, nil, &moderation.BatchError{Start: start, End: end, Attempts: attempts, Err: err})
+				return
+			}
+			c.add(start, end, results, nil)
+		}()
+	}
+	wg.Wait()
+
+	return c.result, moderation.NewManyError(c.failed)
+}
+
+// checkBatch moderates a batch of texts, retrying transient errors. It
+// returns the results and the number of attempts made.
+func (s *ModerationsService) checkBatch(ctx context.Context, model string, texts []string, cfg *moderation.ManyConfig) ([]moderation.ModerationResult, int, error) {
+	backoff := cfg.RetryBackoff
+	for attempt := 1; ; attempt++ {
+		resp, err := s.CheckBatch(ctx, model, texts)
+		if err == nil && len(resp.Results) != len(texts) {
+			err = fmt.Errorf("%w: got %d results for %d texts", errResultCount, len(resp.Results), len(texts))
+		}
+		if err == nil {
+			return resp.Results, attempt, nil
+		}
+		if attempt > cfg.Retries || !isTransientBatchError(err) || ctx.Err() != nil {
+			return nil, attempt, err
+		}
+
+		wait := backoff
+		var limitErr *errors.APIReachLimitError
+		if stderrors.As(err, &limitErr) && limitErr.RetryAfter > 0 {
+			wait = time.Duration(limitErr.RetryAfter) * time.Second
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// isTransientBatchError reports whether a failed batch may succeed when
+// retried.
+func isTransientBatchError(err error) bool {
+	return errors.IsRateLimitError(err) || errors.IsServerError(err) ||
+		errors.IsConnectionError(err) || stderrors.Is(err, errResultCount)
+}
+
+// manyCollector gathers the results of the batches of CheckMany and
+// passes them to the result callback.
+type manyCollector struct {
+	mu     sync.Mutex
+	cfg    *moderation.ManyConfig
+	result *moderation.ManyResult
+	failed []*moderation.BatchError
+
+	// delivered is the number of results passed to the callback.
+	delivered int
+
+	// pending holds the results of batches that completed before an
+	// earlier batch, by the index of their first text, and next is the
+	// index of the first text not yet delivered.
+	pending map[int][]moderation.ManyItem
+	next    int
+}
+
+// add records the results of the batch of the texts from start to end, or
+// its error.
+func (c *manyCollector) add(start, end int, results []moderation.ModerationResult, batchErr *moderation.BatchError) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	items := make([]moderation.ManyItem, 0, end-start)
+	for i := start; i < end; i++ {
+		item := moderation.ManyItem{Index: i, Total: len(c.result.Results)}
+		if batchErr != nil {
+			item.Err = batchErr
+			c.result.Summary.Failed++
+		} else {
+			result := &results[i-start]
+			item.Result = result
+			c.result.Results[i] = result
+			c.result.Summary.Add(result)
+		}
+		items = append(items, item)
+	}
+	if batchErr != nil {
+		c.failed = append(c.failed, batchErr)
+	}
+
+	if c.cfg.OnResult == nil {
+		return
+	}
+	if !c.cfg.Ordered {
+		c.deliver(items)
+		return
+	}
+
+	// Deliver the batch once all earlier batches are delivered
+	c.pending[start] = items
+	for {
+		items, ok := c.pending[c.next]
+		if !ok {
+			return
+		}
+		delete(c.pending, c.next)
+		c.deliver(items)
+		c.next += len(items)
+	}
+}
+
+// deliver passes items to the result callback.
+func (c *manyCollector) deliver(items []moderation.ManyItem) {
+	for _, item := range items {
+		c.delivered++
+		item.Completed = c.delivered
+		c.cfg.OnResult(item)
+	}
+}
@@ -0,0 +1,214 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+)
+
+// moderationCorpus returns n texts "t<i>". The mock server of
+// moderationHandler flags every tenth text for violence and scores each
+// text by its index.
+func moderationCorpus(n int) []string {
+	texts := make([]string, n)
+	for i := range texts {
+		texts[i] = "t" + strconv.Itoa(i)
+	}
+	return texts
+}
+
+// moderationHandler answers batch moderation requests for
+// moderationCorpus texts. fail, if set, returns the status code of a
+// failed attempt of the batch starting at the given index, or zero.
+func moderationHandler(t *testing.T, fail func(start, attempt int) int) http.HandlerFunc {
+	var (
+		mu       sync.Mutex
+		attempts = make(map[int]int)
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input []string `json:"input"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		start, err := strconv.Atoi(strings.TrimPrefix(body.Input[0], "t"))
+		require.NoError(t, err)
+
+		mu.Lock()
+		attempts[start]++
+		attempt := attempts[start]
+		mu.Unlock()
+
+		if fail != nil {
+			if status := fail(start, attempt); status != 0 {
+				w.WriteHeader(status)
+				fmt.Fprint(w, `{"error":{"code":"1234","message":"batch failed"}}`)
+				return
+			}
+		}
+
+		results := make([]map[string]any, len(body.Input))
+		for j, text := range body.Input {
+			i, err := strconv.Atoi(strings.TrimPrefix(text, "t"))
+			require.NoError(t, err)
+			flagged := i%10 == 0
+			results[j] = map[string]any{
+				"flagged":         flagged,
+				"categories":      map[string]bool{"violence": flagged},
+				"category_scores": map[string]float64{"violence": float64(i) / 100000},
+			}
+		}
+		writeJSON(w, map[string]any{"id": "modr-1", "model": "moderation", "results": results})
+	}
+}
+
+func TestModerationsService_CheckMany(t *testing.T) {
+	t.Parallel()
+
+	const total = 10000
+	var inFlight, maxInFlight atomic.Int32
+	handler := moderationHandler(t, func(start, attempt int) int {
+		switch {
+		case start == 500 && attempt == 1:
+			return http.StatusServiceUnavailable
+		case start == 9900:
+			return http.StatusBadRequest
+		}
+		return 0
+	})
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		handler(w, r)
+	})
+
+	var (
+		items     []moderation.ManyItem
+		lastIndex = make(map[int]int)
+	)
+	result, err := client.Moderations.CheckMany(context.Background(), "", moderationCorpus(total),
+		moderation.WithConcurrency(8),
+		moderation.WithBatchRetries(2, time.Millisecond),
+		moderation.WithResults(func(item moderation.ManyItem) {
+			items = append(items, item)
+			batch := item.Index / moderation.DefaultBatchSize
+			if last, ok := lastIndex[batch]; ok {
+				assert.Equal(t, last+1, item.Index, "results within a batch are in order")
+			}
+			lastIndex[batch] = item.Index
+		}),
+	)
+
+	// The batch with a client error fails, the others succeed
+	var manyErr *moderation.ManyError
+	require.ErrorAs(t, err, &manyErr)
+	require.Len(t, manyErr.Batches, 1)
+	failed := manyErr.Batches[0]
+	assert.Equal(t, 9900, failed.Start)
+	assert.Equal(t, 10000, failed.End)
+	assert.Equal(t, 1, failed.Attempts, "client errors are not retried")
+
+	require.Len(t, result.Results, total)
+	for i, r := range result.Results {
+		if i >= 9900 {
+			assert.Nil(t, r)
+			continue
+		}
+		require.NotNil(t, r, "result %d", i)
+		require.Equal(t, float64(i)/100000, r.CategoryScores.Violence, "result %d is aligned", i)
+	}
+
+	summary := result.Summary
+	assert.Equal(t, total, summary.Total)
+	assert.Equal(t, 9900, summary.Checked)
+	assert.Equal(t, 100, summary.Failed)
+	assert.Equal(t, 990, summary.Flagged)
+	assert.Equal(t, map[string]int{moderation.CategoryViolence: 990}, summary.Categories)
+	assert.Equal(t, 0.09899, summary.MaxScores[moderation.CategoryViolence])
+
+	// Every text is reported once, failed ones with the batch error
+	require.Len(t, items, total)
+	seen := make(map[int]bool, total)
+	for n, item := range items {
+		assert.False(t, seen[item.Index], "index %d reported twice", item.Index)
+		seen[item.Index] = true
+		assert.Equal(t, n+1, item.Completed)
+		assert.Equal(t, total, item.Total)
+		if item.Index >= 9900 {
+			assert.ErrorIs(t, item.Err, failed)
+		}
+	}
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(8))
+}
+
+func TestModerationsService_CheckMany_Ordered(t *testing.T) {
+	t.Parallel()
+
+	handler := moderationHandler(t, nil)
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// Complete batches out of order
+		time.Sleep(time.Duration(rand.IntN(5)) * time.Millisecond)
+		handler(w, r)
+	})
+
+	var indexes []int
+	_, err := client.Moderations.CheckMany(context.Background(), "moderation", moderationCorpus(1000),
+		moderation.WithBatchSize(10),
+		moderation.WithConcurrency(16),
+		moderation.WithOrderedResults(),
+		moderation.WithResults(func(item moderation.ManyItem) {
+			indexes = append(indexes, item.Index)
+		}),
+	)
+	require.NoError(t, err)
+
+	require.Len(t, indexes, 1000)
+	for i, index := range indexes {
+		require.Equal(t, i, index)
+	}
+}
+
+func TestModerationsService_CheckMany_Retries(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, moderationHandler(t, func(start, attempt int) int {
+		return http.StatusServiceUnavailable
+	}))
+
+	result, err := client.Moderations.CheckMany(context.Background(), "moderation", moderationCorpus(5),
+		moderation.WithBatchRetries(2, time.Millisecond),
+	)
+	var manyErr *moderation.ManyError
+	require.ErrorAs(t, err, &manyErr)
+	require.Len(t, manyErr.Batches, 1)
+	assert.Equal(t, 3, manyErr.Batches[0].Attempts)
+	assert.Equal(t, 5, result.Summary.Failed)
+}
+
+func TestModerationsService_CheckMany_Empty(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("no request expected")
+	})
+	_, err := client.Moderations.CheckMany(context.Background(), "moderation", nil)
+	require.Error(t, err)
+}
@@ -390,3 +390,45 @@ func TestModerationsService_Create_APIError(t *testing.T) {
 	_, err = client.Moderations.Create(context.Background(), req)
 	require.Error(t, err)
 }
+
+func TestModerationsService_CheckImage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, map[string]interface{}{
+			"type":      "image_url",
+			"image_url": map[string]interface{}{"url": "https://example.com/a.png"},
+		}, body["input"])
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"modr-1","model":"moderation","results":[{"flagged":true,"categories":{"sexual/image":true},"category_scores":{"sexual/image":0.9}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Moderations.CheckImage(context.Background(), "moderation", "https://example.com/a.png")
+	require.NoError(t, err)
+	assert.True(t, resp.IsFlagged())
+	assert.Equal(t, []string{"sexual/image"}, resp.Results[0].Categories.Flagged())
+}
+
+func TestModerationsService_Create_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL("http://127.0.0.1:0"),
+	)
+	require.NoError(t, err)
+
+	req := moderation.NewBase64ImageModerationRequest("moderation", "application/pdf", "aGk=")
+	_, err = client.Moderations.Create(context.Background(), req)
+	assert.Error(t, err)
+}
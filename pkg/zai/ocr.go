@@ -1,17 +1,22 @@
 package zai
 
 import (
-	"bytes"
 	"context"
 	"fmt"
-	"io"
-	"mime/multipart"
 	"strconv"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/ocr"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 )
 
+// ocrPaths maps each OCR tool type to its endpoint.
+var ocrPaths = map[ocr.ToolType]endpoints.Endpoint{
+	ocr.ToolTypeHandWrite: endpoints.OCR,
+	ocr.ToolTypeGeneral:   endpoints.OCR,
+	ocr.ToolTypeTable:     endpoints.OCRTable,
+}
+
 // OCRService provides access to the OCR API.
 type OCRService struct {
 	client *client.BaseClient
@@ -50,46 +55,67 @@ func newOCRService(baseClient *client.BaseClient) *OCRService {
 //	    }
 //	}
 func (s *OCRService) HandwritingOCR(ctx context.Context, req *ocr.OCRRequest) (*ocr.OCRResponse, error) {
+	return s.Recognize(ctx, req)
+}
+
+// Recognize performs OCR on an image using the request's tool type.
+// Handwriting and general OCR return WordsResult; table OCR returns TablesResult.
+//
+// Example:
+//
+//	req := ocr.NewOCRRequestFromURL("https://bucket.example.com/invoice.png", ocr.ToolTypeTable)
+//
+//	resp, err := client.OCR.Recognize(ctx, req)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, table := range resp.GetTables() {
+//	    csvText, err := table.ToCSV()
+//	    if err != nil {
+//	        // Handle error
+//	    }
+//	    fmt.Println(csvText)
+//	}
+func (s *OCRService) Recognize(ctx context.Context, req *ocr.OCRRequest) (*ocr.OCRResponse, error) {
+	if req.ToolType == "" {
+		return nil, fmt.Errorf("tool type cannot be empty")
+	}
+	if req.File == nil && req.ImageURL == "" {
+		return nil, fmt.Errorf("either file or image URL is required")
+	}
+
+	endpoint, ok := ocrPaths[req.ToolType]
+	if !ok {
+		endpoint = endpoints.OCR
+	}
+	path := s.client.Endpoints().Path(endpoint)
+
 	// Create multipart form data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	form := client.NewForm()
 
 	// Add the tool_type field (required)
-	if err := writer.WriteField("tool_type", string(req.ToolType)); err != nil {
-		return nil, fmt.Errorf("failed to write tool_type field: %w", err)
-	}
+	form.WriteField("tool_type", string(req.ToolType))
 
 	// Add optional fields
 	if req.LanguageType != "" {
-		if err := writer.WriteField("language_type", req.LanguageType); err != nil {
-			return nil, fmt.Errorf("failed to write language_type field: %w", err)
-		}
+		form.WriteField("language_type", req.LanguageType)
 	}
 
 	if req.Probability {
-		if err := writer.WriteField("probability", strconv.FormatBool(req.Probability)); err != nil {
-			return nil, fmt.Errorf("failed to write probability field: %w", err)
-		}
-	}
-
-	// Add the image file
-	part, err := writer.CreateFormFile("file", req.FileName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
-	}
-
-	// Copy file content to the form
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file content: %w", err)
+		form.WriteField("probability", strconv.FormatBool(req.Probability))
 	}
 
-	// Close the writer to finalize the multipart message
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer: %w", err)
+	if req.File != nil {
+		// Add the image file
+		form.AddFile(&client.FormFile{Field: "file", Filename: req.FileName, Reader: req.File})
+	} else {
+		// Reference the image by URL
+		form.WriteField("image_url", req.ImageURL)
 	}
 
-	// Make the API request using PostMultipart
-	apiResp, err := s.client.PostMultipart(ctx, "/files/ocr", &buf, writer.FormDataContentType())
+	// Make the API request
+	apiResp, err := s.client.PostForm(ctx, path, form)
 	if err != nil {
 		return nil, err
 	}
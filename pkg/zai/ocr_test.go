@@ -299,3 +299,102 @@ func TestOCRService_HandwritingOCR_MultipartCreation(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, resp)
 }
+
+func TestOCRService_Recognize_General(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files/ocr", r.URL.Path)
+
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "general", r.FormValue("tool_type"))
+
+		resp := ocr.OCRResponse{
+			TaskID:         "task_general",
+			Status:         "succeeded",
+			WordsResultNum: 1,
+			WordsResult:    []ocr.WordsResult{{Words: "Printed text"}},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := ocr.NewOCRRequest(strings.NewReader("image"), "doc.png", ocr.ToolTypeGeneral)
+	resp, err := client.OCR.Recognize(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Printed text", resp.GetText())
+}
+
+func TestOCRService_Recognize_TableFromURL(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/files/ocr/table", r.URL.Path)
+
+		err := r.ParseMultipartForm(10 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, "table", r.FormValue("tool_type"))
+		assert.Equal(t, "https://bucket.example.com/invoice.png", r.FormValue("image_url"))
+
+		_, _, err = r.FormFile("file")
+		assert.ErrorIs(t, err, http.ErrMissingFile)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"task_id": "task_table",
+			"status": "succeeded",
+			"tables_result_num": 1,
+			"tables_result": [{
+				"rows": 2,
+				"cols": 2,
+				"cells": [
+					{"row": 0, "col": 0, "col_span": 2, "words": "Total"},
+					{"row": 1, "col": 0, "words": "a"},
+					{"row": 1, "col": 1, "words": "b"}
+				]
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := ocr.NewOCRRequestFromURL("https://bucket.example.com/invoice.png", ocr.ToolTypeTable)
+	resp, err := client.OCR.Recognize(context.Background(), req)
+	require.NoError(t, err)
+
+	require.True(t, resp.HasTables())
+	table := resp.GetTables()[0]
+	csvText, err := table.ToCSV()
+	require.NoError(t, err)
+	assert.Equal(t, "Total,\na,b\n", csvText)
+}
+
+func TestOCRService_Recognize_InvalidRequest(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+
+	_, err = client.OCR.Recognize(context.Background(), &ocr.OCRRequest{ToolType: ocr.ToolTypeGeneral})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "either file or image URL is required")
+
+	_, err = client.OCR.Recognize(context.Background(), ocr.NewOCRRequestFromURL("https://example.com/a.png", ""))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tool type cannot be empty")
+}
@@ -0,0 +1,279 @@
+// Package otelzai traces Z.ai SDK calls with OpenTelemetry.
+//
+// Every API call gets a client span carrying the service, model, endpoint
+// path, status code, retry count and token usage of the call, and the
+// model build that served it as far as the response reports it. Each HTTP
+// attempt, including retries, gets a child span, and the W3C trace context
+// is propagated in the headers of every attempt. Streaming calls end when
+// the stream is closed and record how long the stream was read.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    otelzai.WithTracing(otelzai.WithTracerProvider(tp)),
+//	)
+package otelzai
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// ScopeName is the instrumentation scope name of the spans.
+const ScopeName = "github.com/sofianhadi1983/zai-sdk-go/pkg/zai/otelzai"
+
+// Span attribute keys.
+const (
+	// AttrService is the API service, such as "chat" or "files".
+	AttrService = attribute.Key("zai.service")
+
+	// AttrModel is the model named in the request.
+	AttrModel = attribute.Key("gen_ai.request.model")
+
+	// AttrMethod is the HTTP method.
+	AttrMethod = attribute.Key("http.request.method")
+
+	// AttrPath is the endpoint path relative to the base URL.
+	AttrPath = attribute.Key("url.path")
+
+	// AttrStatusCode is the HTTP status code of the final response.
+	AttrStatusCode = attribute.Key("http.response.status_code")
+
+	// AttrRetryCount is the number of retries made for the call.
+	AttrRetryCount = attribute.Key("zai.retry_count")
+
+	// AttrAttempt is the attempt number of an attempt span, starting at 1.
+	AttrAttempt = attribute.Key("zai.attempt")
+
+	// AttrInputTokens is the number of prompt tokens used.
+	AttrInputTokens = attribute.Key("gen_ai.usage.input_tokens")
+
+	// AttrOutputTokens is the number of completion tokens used.
+	AttrOutputTokens = attribute.Key("gen_ai.usage.output_tokens")
+
+	// AttrTotalTokens is the total number of tokens used.
+	AttrTotalTokens = attribute.Key("zai.usage.total_tokens")
+
+	// AttrResponseModel is the model named in the response.
+	AttrResponseModel = attribute.Key("gen_ai.response.model")
+
+	// AttrModelVersion is the version of the model build that served the
+	// call.
+	AttrModelVersion = attribute.Key("zai.response.model_version")
+
+	// AttrSystemFingerprint identifies the backend configuration that
+	// served the call.
+	AttrSystemFingerprint = attribute.Key("zai.response.system_fingerprint")
+
+	// AttrServiceTier is the service tier that processed the call.
+	AttrServiceTier = attribute.Key("zai.response.service_tier")
+
+	// AttrStream is true for streaming calls.
+	AttrStream = attribute.Key("zai.stream")
+
+	// AttrStreamDuration is the time in milliseconds from receiving the
+	// stream response to closing the stream.
+	AttrStreamDuration = attribute.Key("zai.stream.duration_ms")
+)
+
+// Option configures the tracer.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the tracer provider.
+// If not set, the global tracer provider is used.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = tp
+	}
+}
+
+// WithPropagator sets the propagator that writes the trace context into
+// request headers. If not set, the W3C trace context is propagated.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
+
+// NewTracer creates a tracer for zai.WithTracer.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithTracer(otelzai.NewTracer()),
+//	)
+func NewTracer(opts ...Option) zai.Tracer {
+	cfg := &config{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+	if cfg.propagator == nil {
+		cfg.propagator = propagation.TraceContext{}
+	}
+
+	return &tracer{
+		tracer:     cfg.provider.Tracer(ScopeName, trace.WithInstrumentationVersion(zai.Version())),
+		propagator: cfg.propagator,
+	}
+}
+
+// WithTracing traces every API call of the client with OpenTelemetry.
+// It is shorthand for zai.WithTracer(otelzai.NewTracer(opts...)).
+func WithTracing(opts ...Option) zai.ClientOption {
+	return zai.WithTracer(NewTracer(opts...))
+}
+
+// tracer implements zai.Tracer.
+type tracer struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+type callSpanKey struct{}
+
+// StartCall implements zai.Tracer.
+func (t *tracer) StartCall(ctx context.Context, call *zai.TracedCall) (context.Context, zai.CallSpan) {
+	attrs := []attribute.KeyValue{
+		AttrService.String(call.Service),
+		AttrMethod.String(call.Method),
+		AttrPath.String(call.Path),
+		AttrStream.Bool(call.Stream),
+	}
+	if call.Model != "" {
+		attrs = append(attrs, AttrModel.String(call.Model))
+	}
+
+	ctx, span := t.tracer.Start(ctx, "zai."+call.Service,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	cs := &callSpan{span: span, stream: call.Stream}
+	return context.WithValue(ctx, callSpanKey{}, cs), cs
+}
+
+// StartAttempt implements zai.Tracer.
+func (t *tracer) StartAttempt(ctx context.Context, req *http.Request, attempt int) (context.Context, zai.AttemptSpan) {
+	ctx, span := t.tracer.Start(ctx, req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(AttrAttempt.Int(attempt)),
+	)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	call, _ := ctx.Value(callSpanKey{}).(*callSpan)
+	if call != nil {
+		call.startAttempt(attempt)
+	}
+
+	return ctx, &attemptSpan{span: span, call: call}
+}
+
+// callSpan implements zai.CallSpan.
+type callSpan struct {
+	span   trace.Span
+	stream bool
+
+	mu        sync.Mutex
+	attempts  int
+	responded time.Time
+	ended     bool
+}
+
+func (s *callSpan) startAttempt(attempt int) {
+	s.mu.Lock()
+	s.attempts = max(s.attempts, attempt)
+	s.mu.Unlock()
+}
+
+func (s *callSpan) respond() {
+	s.mu.Lock()
+	s.responded = time.Now()
+	s.mu.Unlock()
+}
+
+// SetUsage implements zai.CallSpan.
+func (s *callSpan) SetUsage(usage zai.TokenUsage) {
+	s.span.SetAttributes(
+		AttrInputTokens.Int(usage.PromptTokens),
+		AttrOutputTokens.Int(usage.CompletionTokens),
+		AttrTotalTokens.Int(usage.TotalTokens),
+	)
+	for _, attr := range []attribute.KeyValue{
+		AttrResponseModel.String(usage.Model),
+		AttrModelVersion.String(usage.ModelVersion),
+		AttrSystemFingerprint.String(usage.SystemFingerprint),
+		AttrServiceTier.String(usage.ServiceTier),
+	} {
+		if attr.Value.AsString() != "" {
+			s.span.SetAttributes(attr)
+		}
+	}
+}
+
+// End implements zai.CallSpan.
+func (s *callSpan) End(statusCode int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.ended = true
+
+	if s.attempts > 0 {
+		s.span.SetAttributes(AttrRetryCount.Int(s.attempts - 1))
+	}
+	if s.stream && !s.responded.IsZero() {
+		s.span.SetAttributes(AttrStreamDuration.Int64(time.Since(s.responded).Milliseconds()))
+	}
+	endSpan(s.span, statusCode, err)
+}
+
+// attemptSpan implements zai.AttemptSpan.
+type attemptSpan struct {
+	span trace.Span
+	call *callSpan
+}
+
+// End implements zai.AttemptSpan.
+func (s *attemptSpan) End(statusCode int, err error) {
+	if s.call != nil && err == nil {
+		s.call.respond()
+	}
+	endSpan(s.span, statusCode, err)
+}
+
+// endSpan records the outcome of a request on span and ends it.
+func endSpan(span trace.Span, statusCode int, err error) {
+	switch {
+	case err != nil:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	case statusCode >= http.StatusBadRequest:
+		span.SetStatus(codes.Error, http.StatusText(statusCode))
+	}
+	if statusCode != 0 {
+		span.SetAttributes(AttrStatusCode.Int(statusCode))
+	}
+	span.End()
+}
@@ -0,0 +1,192 @@
+package otelzai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// newTracedClient creates a client for server.URL that exports its spans to
+// the returned in-memory exporter.
+func newTracedClient(t *testing.T, serverURL string, opts ...zai.ClientOption) (*zai.Client, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { tp.Shutdown(context.Background()) })
+
+	opts = append([]zai.ClientOption{
+		zai.WithAPIKey("test-key.test-secret"),
+		zai.WithBaseURL(serverURL + "/api/paas/v4"),
+		zai.WithDisableTokenCache(),
+		WithTracing(WithTracerProvider(tp)),
+	}, opts...)
+
+	client, err := zai.NewClient(opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+
+	return client, exporter
+}
+
+// spansByName splits exported spans into the call span and attempt spans.
+func spansByName(t *testing.T, exporter *tracetest.InMemoryExporter, callName string) (tracetest.SpanStub, []tracetest.SpanStub) {
+	var call *tracetest.SpanStub
+	var attempts []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Name == callName {
+			call = &span
+		} else {
+			attempts = append(attempts, span)
+		}
+	}
+	require.NotNil(t, call, "call span %q", callName)
+	return *call, attempts
+}
+
+func attrs(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func newChatRequest() *chat.ChatCompletionRequest {
+	return chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hello")})
+}
+
+func TestTracing_ChatCompletion(t *testing.T) {
+	t.Parallel()
+
+	var traceparent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent.Store(r.Header.Get("Traceparent"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","model":"glm-4.7","system_fingerprint":"fp_3b2a1c","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}],` +
+			`"usage":{"prompt_tokens":12,"completion_tokens":3,"total_tokens":15}}`))
+	}))
+	defer server.Close()
+
+	client, exporter := newTracedClient(t, server.URL)
+
+	_, err := client.Chat.Create(context.Background(), newChatRequest())
+	require.NoError(t, err)
+
+	call, attempts := spansByName(t, exporter, "zai.chat")
+	assert.Equal(t, trace.SpanKindClient, call.SpanKind)
+	assert.Equal(t, codes.Unset, call.Status.Code)
+
+	got := attrs(call)
+	assert.Equal(t, "chat", got[AttrService].AsString())
+	assert.Equal(t, "glm-4.7", got[AttrModel].AsString())
+	assert.Equal(t, "POST", got[AttrMethod].AsString())
+	assert.Equal(t, "/chat/completions", got[AttrPath].AsString())
+	assert.Equal(t, int64(200), got[AttrStatusCode].AsInt64())
+	assert.Equal(t, int64(0), got[AttrRetryCount].AsInt64())
+	assert.Equal(t, int64(12), got[AttrInputTokens].AsInt64())
+	assert.Equal(t, int64(3), got[AttrOutputTokens].AsInt64())
+	assert.Equal(t, int64(15), got[AttrTotalTokens].AsInt64())
+	assert.Equal(t, "glm-4.7", got[AttrResponseModel].AsString())
+	assert.Equal(t, "fp_3b2a1c", got[AttrSystemFingerprint].AsString())
+	assert.NotContains(t, got, AttrServiceTier)
+	assert.False(t, got[AttrStream].AsBool())
+
+	require.Len(t, attempts, 1)
+	attempt := attempts[0]
+	assert.Equal(t, call.SpanContext.SpanID(), attempt.Parent.SpanID())
+	assert.Equal(t, int64(1), attrs(attempt)[AttrAttempt].AsInt64())
+
+	// The attempt span is propagated to the server
+	want := "00-" + attempt.SpanContext.TraceID().String() + "-" + attempt.SpanContext.SpanID().String() + "-01"
+	assert.Equal(t, want, traceparent.Load())
+}
+
+func TestTracing_RetriedFailure(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":{"code":"1305","message":"overloaded"}}`))
+	}))
+	defer server.Close()
+
+	client, exporter := newTracedClient(t, server.URL,
+		zai.WithDefaultRetryPolicy(zai.RetryPolicy{MaxAttempts: 3, InitialBackoff: 1, MaxBackoff: 1}),
+	)
+
+	_, err := client.Models.List(context.Background())
+	require.Error(t, err)
+
+	call, attempts := spansByName(t, exporter, "zai.models")
+	assert.Equal(t, codes.Error, call.Status.Code)
+
+	got := attrs(call)
+	assert.Equal(t, "models", got[AttrService].AsString())
+	assert.Equal(t, "GET", got[AttrMethod].AsString())
+	assert.Equal(t, "/models", got[AttrPath].AsString())
+	assert.Equal(t, int64(503), got[AttrStatusCode].AsInt64())
+	assert.Equal(t, int64(2), got[AttrRetryCount].AsInt64())
+	assert.NotContains(t, got, AttrModel)
+
+	require.Len(t, attempts, 3)
+	for i, attempt := range attempts {
+		assert.Equal(t, call.SpanContext.SpanID(), attempt.Parent.SpanID())
+		assert.Equal(t, int64(i+1), attrs(attempt)[AttrAttempt].AsInt64())
+		assert.Equal(t, int64(503), attrs(attempt)[AttrStatusCode].AsInt64())
+		assert.Equal(t, codes.Error, attempt.Status.Code)
+	}
+
+	// Each attempt propagates its own span
+	require.Len(t, traceparents, 3)
+	assert.NotEqual(t, traceparents[0], traceparents[1])
+	assert.Contains(t, traceparents[2], call.SpanContext.TraceID().String())
+}
+
+func TestTracing_Stream(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"model\":\"glm-4.7\",\"system_fingerprint\":\"fp_3b2a1c\",\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+		w.Write([]byte("data: {\"model\":\"glm-4.7\",\"service_tier\":\"standard\",\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":1,\"total_tokens\":6}}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client, exporter := newTracedClient(t, server.URL)
+
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+
+	call, attempts := spansByName(t, exporter, "zai.chat")
+	got := attrs(call)
+	assert.True(t, got[AttrStream].AsBool())
+	assert.Equal(t, int64(200), got[AttrStatusCode].AsInt64())
+	assert.Equal(t, int64(6), got[AttrTotalTokens].AsInt64())
+	// The fingerprint of the first chunk is recorded with the usage of the last
+	assert.Equal(t, "fp_3b2a1c", got[AttrSystemFingerprint].AsString())
+	assert.Equal(t, "standard", got[AttrServiceTier].AsString())
+	assert.Equal(t, "glm-4.7", got[AttrResponseModel].AsString())
+	assert.Contains(t, got, AttrStreamDuration)
+	require.Len(t, attempts, 1)
+}
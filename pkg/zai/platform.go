@@ -0,0 +1,126 @@
+package zai
+
+import (
+	"fmt"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Platform identifies the API platform a client talks to. Services resolve
+// their endpoint paths, and some request field values, for the platform.
+type Platform int
+
+const (
+	// PlatformAuto detects the platform from the base URL: PlatformZhipu
+	// for open.bigmodel.cn and PlatformInternational otherwise.
+	PlatformAuto Platform = iota
+
+	// PlatformInternational is the Z.ai platform (api.z.ai).
+	PlatformInternational
+
+	// PlatformZhipu is the mainland China platform (open.bigmodel.cn).
+	PlatformZhipu
+)
+
+// String returns the platform name.
+func (p Platform) String() string {
+	switch p {
+	case PlatformAuto:
+		return "auto"
+	case PlatformInternational:
+		return "international"
+	case PlatformZhipu:
+		return "zhipu"
+	default:
+		return fmt.Sprintf("Platform(%d)", int(p))
+	}
+}
+
+// WithPlatform sets the platform whose endpoint paths the services use,
+// for base URLs that do not identify it, such as a proxy in front of the
+// Zhipu API. NewZhipuClient uses PlatformZhipu.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-api-key.your-secret"),
+//	    zai.WithBaseURL("https://llm-proxy.internal/zhipu/api/paas/v4"),
+//	    zai.WithPlatform(zai.PlatformZhipu),
+//	)
+func WithPlatform(platform Platform) ClientOption {
+	return func(c *ClientConfig) {
+		c.Platform = platform
+	}
+}
+
+// WithEndpointPath overrides the path of one endpoint, for example to
+// point a single service at a mock server in tests. Endpoints are named
+// "<service>.<operation>", such as "chat.completions" or "videos.result";
+// Endpoints lists them all. The path is relative to the base URL unless it
+// is an absolute URL, and must have a %s verb for each path parameter of
+// the endpoint, such as the task ID of "videos.result".
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-api-key.your-secret"),
+//	    zai.WithEndpointPath("videos.result", "/mock/videos/%s"),
+//	)
+func WithEndpointPath(endpoint, path string) ClientOption {
+	return func(c *ClientConfig) {
+		if c.EndpointPaths == nil {
+			c.EndpointPaths = make(map[string]string)
+		}
+		c.EndpointPaths[endpoint] = path
+	}
+}
+
+// Endpoints returns the names of all endpoints accepted by
+// WithEndpointPath.
+func Endpoints() []string {
+	all := endpoints.All()
+	names := make([]string, len(all))
+	for i, e := range all {
+		names[i] = string(e)
+	}
+	return names
+}
+
+// Platform returns the platform the client talks to. It is never
+// PlatformAuto.
+func (c *Client) Platform() Platform {
+	if c.baseClient.Endpoints().Platform() == endpoints.Zhipu {
+		return PlatformZhipu
+	}
+	return PlatformInternational
+}
+
+// resolvePlatform returns the endpoint platform of config.
+func resolvePlatform(config *ClientConfig) endpoints.Platform {
+	switch config.Platform {
+	case PlatformInternational:
+		return endpoints.International
+	case PlatformZhipu:
+		return endpoints.Zhipu
+	default:
+		return endpoints.Detect(config.BaseURL)
+	}
+}
+
+// endpointPaths validates the endpoint overrides of config.
+func endpointPaths(config *ClientConfig) (map[endpoints.Endpoint]string, error) {
+	if len(config.EndpointPaths) == 0 {
+		return nil, nil
+	}
+
+	paths := make(map[endpoints.Endpoint]string, len(config.EndpointPaths))
+	for name, path := range config.EndpointPaths {
+		e := endpoints.Endpoint(name)
+		if !endpoints.Known(e) {
+			return nil, errors.NewConfigError("EndpointPaths", fmt.Sprintf("unknown endpoint %q", name))
+		}
+		paths[e] = path
+	}
+	return paths, nil
+}
@@ -0,0 +1,400 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/agents"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/assistant"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/finetuning"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/images"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/knowledge"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/ocr"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/voice"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/websearch"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// basePath is the path of the mock servers' base URL, like the real API.
+const basePath = "/api/paas/v4"
+
+// pathRecorder is a mock API server that records the requested paths.
+type pathRecorder struct {
+	mu     sync.Mutex
+	paths  []string
+	bodies []string
+}
+
+func (p *pathRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	p.mu.Lock()
+	p.paths = append(p.paths, r.URL.Path)
+	p.bodies = append(p.bodies, string(body))
+	p.mu.Unlock()
+	writeJSON(w, map[string]any{})
+}
+
+func (p *pathRecorder) last() (path, body string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.paths) == 0 {
+		return "", ""
+	}
+	return p.paths[len(p.paths)-1], p.bodies[len(p.bodies)-1]
+}
+
+// newPlatformClient creates a client for platform against a mock server.
+func newPlatformClient(t *testing.T, platform Platform, opts ...ClientOption) (*Client, *pathRecorder) {
+	t.Helper()
+
+	recorder := &pathRecorder{}
+	server := httptest.NewServer(recorder)
+	t.Cleanup(server.Close)
+
+	opts = append([]ClientOption{
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL + basePath),
+		WithPlatform(platform),
+		WithDisableTokenCache(),
+		WithMaxRetries(1),
+	}, opts...)
+	client, err := NewClient(opts...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, recorder
+}
+
+// ignore discards the result of a call; only the requested path matters.
+func ignore[T any](_ T, err error) error {
+	return err
+}
+
+// closeStream closes a stream returned by a streaming call.
+func closeStream[T io.Closer](stream T, err error) error {
+	if err != nil {
+		return err
+	}
+	return stream.Close()
+}
+
+var userMessages = []chat.Message{chat.NewUserMessage("Hi")}
+
+// platformCalls call every endpoint of every service once. path is the
+// path requested on the international platform, relative to the base URL,
+// and zhipu the path requested on the Zhipu platform, from the host root,
+// if it is not below the base URL.
+var platformCalls = []struct {
+	name  string
+	call  func(ctx context.Context, c *Client) error
+	path  string
+	zhipu string
+}{
+	{"Chat.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Chat.Create(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, userMessages)))
+	}, "/chat/completions", ""},
+	{"Chat.CreateStream", func(ctx context.Context, c *Client) error {
+		return closeStream(c.Chat.CreateStream(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, userMessages)))
+	}, "/chat/completions", ""},
+	{"Embeddings.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Embeddings.Create(ctx, embeddings.NewEmbeddingRequest("embedding-3", "Hi")))
+	}, "/embeddings", ""},
+	{"Images.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Images.Create(ctx, images.NewImageGenerationRequest(images.ModelCogView4, "A cat")))
+	}, "/images/generations", ""},
+	{"Images.Edit", func(ctx context.Context, c *Client) error {
+		return ignore(c.Images.Edit(ctx, images.NewImageEditRequest(images.ModelCogView4, "A hat", images.ImageFromURL("https://example.com/a.png"))))
+	}, "/images/edits", ""},
+	{"Images.Variations", func(ctx context.Context, c *Client) error {
+		return ignore(c.Images.Variations(ctx, images.NewImageVariationRequest(images.ModelCogView4, images.ImageFromURL("https://example.com/a.png"))))
+	}, "/images/variations", ""},
+	{"Files.Upload", func(ctx context.Context, c *Client) error {
+		return ignore(c.Files.Upload(ctx, files.NewFileUploadRequest(strings.NewReader(batchInput(1)), "batch.jsonl", files.PurposeBatch)))
+	}, "/files", ""},
+	{"Files.List", func(ctx context.Context, c *Client) error {
+		return ignore(c.Files.List(ctx))
+	}, "/files", ""},
+	{"Files.Retrieve", func(ctx context.Context, c *Client) error {
+		return ignore(c.Files.Retrieve(ctx, "file-1"))
+	}, "/files/file-1", ""},
+	{"Files.Delete", func(ctx context.Context, c *Client) error {
+		return ignore(c.Files.Delete(ctx, "file-1"))
+	}, "/files/file-1", ""},
+	{"Files.RetrieveContent", func(ctx context.Context, c *Client) error {
+		return ignore(c.Files.RetrieveContent(ctx, "file-1"))
+	}, "/files/file-1/content", ""},
+	{"Videos.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Videos.Create(ctx, videos.NewTextToVideoRequest(videos.ModelCogVideoX, "A cat")))
+	}, "/videos/generations", ""},
+	{"Videos.Retrieve", func(ctx context.Context, c *Client) error {
+		return ignore(c.Videos.Retrieve(ctx, "task-1"))
+	}, "/async-result/task-1", ""},
+	{"Audio.Transcribe", func(ctx context.Context, c *Client) error {
+		return ignore(c.Audio.Transcribe(ctx, audio.NewTranscriptionRequest(strings.NewReader("RIFF\x00\x00\x00\x00WAVE"), "a.wav", audio.ModelWhisper1)))
+	}, "/audio/transcriptions", ""},
+	{"Audio.Translate", func(ctx context.Context, c *Client) error {
+		return ignore(c.Audio.Translate(ctx, audio.NewTranslationRequest(strings.NewReader("RIFF"), "a.wav", audio.ModelWhisper1)))
+	}, "/audio/translations", ""},
+	{"Audio.Speech", func(ctx context.Context, c *Client) error {
+		return ignore(c.Audio.Speech(ctx, audio.NewSpeechRequest(audio.ModelCogTTS, "Hi", audio.VoiceTongtong)))
+	}, "/audio/speech", ""},
+	{"Audio.SpeechStream", func(ctx context.Context, c *Client) error {
+		return closeStream(c.Audio.SpeechStream(ctx, audio.NewSpeechRequest(audio.ModelCogTTS, "Hi", audio.VoiceTongtong)))
+	}, "/audio/speech", ""},
+	{"Assistant.Conversation", func(ctx context.Context, c *Client) error {
+		return ignore(c.Assistant.CreateConversation(ctx, "asst-1", "Hi"))
+	}, "/assistant", ""},
+	{"Assistant.ConversationStream", func(ctx context.Context, c *Client) error {
+		return closeStream(c.Assistant.ConversationStream(ctx, assistant.NewConversationRequest("asst-1", nil)))
+	}, "/assistant", ""},
+	{"Assistant.QuerySupport", func(ctx context.Context, c *Client) error {
+		return ignore(c.Assistant.QuerySupport(ctx, []string{"asst-1"}))
+	}, "/assistant/list", ""},
+	{"Assistant.QueryConversationUsage", func(ctx context.Context, c *Client) error {
+		return ignore(c.Assistant.QueryConversationUsage(ctx, "asst-1", 1, 10))
+	}, "/assistant/conversation/list", ""},
+	{"Batch.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Batch.Create(ctx, batch.NewBatchCreateRequest("24h", "/v4/chat/completions", "file-1")))
+	}, "/batches", ""},
+	{"Batch.Retrieve", func(ctx context.Context, c *Client) error {
+		return ignore(c.Batch.Retrieve(ctx, "batch-1"))
+	}, "/batches/batch-1", ""},
+	{"Batch.List", func(ctx context.Context, c *Client) error {
+		return ignore(c.Batch.List(ctx, "", 10))
+	}, "/batches", ""},
+	{"Batch.Cancel", func(ctx context.Context, c *Client) error {
+		return ignore(c.Batch.Cancel(ctx, "batch-1"))
+	}, "/batches/batch-1/cancel", ""},
+	{"FineTuning.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.FineTuning.Create(ctx, finetuning.NewJobCreateRequest("glm-4-flash", "file-1")))
+	}, "/fine_tuning/jobs", ""},
+	{"FineTuning.Retrieve", func(ctx context.Context, c *Client) error {
+		return ignore(c.FineTuning.Retrieve(ctx, "job-1"))
+	}, "/fine_tuning/jobs/job-1", ""},
+	{"FineTuning.List", func(ctx context.Context, c *Client) error {
+		return ignore(c.FineTuning.List(ctx, "", 10))
+	}, "/fine_tuning/jobs", ""},
+	{"FineTuning.Cancel", func(ctx context.Context, c *Client) error {
+		return ignore(c.FineTuning.Cancel(ctx, "job-1"))
+	}, "/fine_tuning/jobs/job-1/cancel", ""},
+	{"FineTuning.ListEvents", func(ctx context.Context, c *Client) error {
+		return ignore(c.FineTuning.ListEvents(ctx, "job-1", "", 10))
+	}, "/fine_tuning/jobs/job-1/events", ""},
+	{"WebSearch.Search", func(ctx context.Context, c *Client) error {
+		return ignore(c.WebSearch.Search(ctx, websearch.NewWebSearchRequest("golang")))
+	}, "/web_search", ""},
+	{"Moderations.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.Moderations.Create(ctx, moderation.NewTextModerationRequest("moderation", "Hi")))
+	}, "/moderations", ""},
+	{"Tools.WebSearch", func(ctx context.Context, c *Client) error {
+		return ignore(c.Tools.WebSearch(ctx, tools.NewWebSearchRequest("web-search-pro", userMessages)))
+	}, "/tools", ""},
+	{"Tools.WebSearchStream", func(ctx context.Context, c *Client) error {
+		return closeStream(c.Tools.WebSearchStream(ctx, tools.NewWebSearchRequest("web-search-pro", userMessages)))
+	}, "/tools", ""},
+	{"Tools.Tokenizer", func(ctx context.Context, c *Client) error {
+		return ignore(c.Tools.Tokenizer(ctx, tools.NewTokenizerRequest("glm-4.7", userMessages)))
+	}, "/tokenizer", ""},
+	{"Agents.Invoke", func(ctx context.Context, c *Client) error {
+		return ignore(c.Agents.Invoke(ctx, agents.NewAgentInvokeRequest("agent-1", userMessages)))
+	}, "/v1/agents", "/api/v1/agents"},
+	{"Agents.InvokeStream", func(ctx context.Context, c *Client) error {
+		return closeStream(c.Agents.InvokeStream(ctx, agents.NewAgentInvokeRequest("agent-1", userMessages)))
+	}, "/v1/agents", "/api/v1/agents"},
+	{"Agents.AsyncResult", func(ctx context.Context, c *Client) error {
+		return ignore(c.Agents.AsyncResult(ctx, agents.NewAgentAsyncResultRequest("agent-1").SetAsyncID("async-1")))
+	}, "/v1/agents/async-result", "/api/v1/agents/async-result"},
+	{"Voice.Clone", func(ctx context.Context, c *Client) error {
+		return ignore(c.Voice.Clone(ctx, voice.NewVoiceCloneRequest("narrator", "Hi", "Hello", "file-1", "cogtts-clone")))
+	}, "/voice/clone", ""},
+	{"Voice.Delete", func(ctx context.Context, c *Client) error {
+		return ignore(c.Voice.Delete(ctx, voice.NewVoiceDeleteRequest("voice-1")))
+	}, "/voice/delete", ""},
+	{"Voice.List", func(ctx context.Context, c *Client) error {
+		return ignore(c.Voice.List(ctx, voice.NewVoiceListRequest()))
+	}, "/voice/list", ""},
+	{"OCR.Recognize", func(ctx context.Context, c *Client) error {
+		return ignore(c.OCR.Recognize(ctx, ocr.NewOCRRequestFromURL("https://example.com/a.png", ocr.ToolTypeHandWrite)))
+	}, "/files/ocr", ""},
+	{"OCR.Recognize table", func(ctx context.Context, c *Client) error {
+		return ignore(c.OCR.Recognize(ctx, ocr.NewOCRRequestFromURL("https://example.com/a.png", ocr.ToolTypeTable)))
+	}, "/files/ocr/table", ""},
+	{"FileParser.Create", func(ctx context.Context, c *Client) error {
+		return ignore(c.FileParser.Create(ctx, fileparser.NewCreateRequestFromURL("https://example.com/a.pdf", fileparser.ToolTypeLite)))
+	}, "/files/parser/create", ""},
+	{"FileParser.Content", func(ctx context.Context, c *Client) error {
+		return ignore(c.FileParser.Content(ctx, fileparser.NewContentRequest("task-1", fileparser.FormatTypeText)))
+	}, "/files/parser/result/task-1/text", ""},
+	{"FileParser.CreateSync", func(ctx context.Context, c *Client) error {
+		return ignore(c.FileParser.CreateSync(ctx, fileparser.NewSyncRequest(strings.NewReader("%PDF"), "a.pdf", "pdf")))
+	}, "/files/parser/sync", ""},
+	{"WebReader.Read", func(ctx context.Context, c *Client) error {
+		return ignore(c.WebReader.Read(ctx, webreader.NewRequest("https://example.com")))
+	}, "/reader", ""},
+	{"Models.List", func(ctx context.Context, c *Client) error {
+		return ignore(c.Models.List(ctx))
+	}, "/models", ""},
+	{"Models.Retrieve", func(ctx context.Context, c *Client) error {
+		return ignore(c.Models.Retrieve(ctx, "glm-4.7"))
+	}, "/models/glm-4.7", ""},
+	{"Knowledge.CreateCollection", func(ctx context.Context, c *Client) error {
+		return ignore(c.Knowledge.CreateCollection(ctx, knowledge.NewCreateCollectionRequest("manuals")))
+	}, "/knowledge", ""},
+	{"Knowledge.AddDocument", func(ctx context.Context, c *Client) error {
+		return ignore(c.Knowledge.AddDocument(ctx, "kb-1", "file-1", nil))
+	}, "/knowledge/kb-1/documents", ""},
+	{"Knowledge.ListDocuments", func(ctx context.Context, c *Client) error {
+		return ignore(c.Knowledge.ListDocuments(ctx, "kb-1"))
+	}, "/knowledge/kb-1/documents", ""},
+	{"Knowledge.DeleteDocument", func(ctx context.Context, c *Client) error {
+		return ignore(c.Knowledge.DeleteDocument(ctx, "kb-1", "doc-1"))
+	}, "/knowledge/kb-1/documents/doc-1", ""},
+	{"Knowledge.Query", func(ctx context.Context, c *Client) error {
+		return ignore(c.Knowledge.Query(ctx, "kb-1", "refunds", 3))
+	}, "/knowledge/kb-1/query", ""},
+}
+
+func TestPlatform_EndpointPaths(t *testing.T) {
+	t.Parallel()
+
+	for _, platform := range []Platform{PlatformInternational, PlatformZhipu} {
+		for _, tt := range platformCalls {
+			t.Run(platform.String()+"/"+tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				client, recorder := newPlatformClient(t, platform)
+				tt.call(context.Background(), client)
+
+				want := basePath + tt.path
+				if platform == PlatformZhipu && tt.zhipu != "" {
+					want = tt.zhipu
+				}
+				path, _ := recorder.last()
+				assert.Equal(t, want, path)
+			})
+		}
+	}
+}
+
+func TestPlatform_Detect(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		new  func(...ClientOption) (*Client, error)
+		opts []ClientOption
+		want Platform
+	}{
+		{"default", NewClient, nil, PlatformInternational},
+		{"zhipu client", NewZhipuClient, nil, PlatformZhipu},
+		{"zhipu client behind a proxy", NewZhipuClient, []ClientOption{WithBaseURL("https://proxy.example.com/v4")}, PlatformZhipu},
+		{"zhipu base URL", NewClient, []ClientOption{WithBaseURL("https://open.bigmodel.cn/api/paas/v4")}, PlatformZhipu},
+		{"explicit platform", NewClient, []ClientOption{WithBaseURL("https://proxy.example.com/v4"), WithPlatform(PlatformZhipu)}, PlatformZhipu},
+		{"explicit international", NewZhipuClient, []ClientOption{WithPlatform(PlatformInternational)}, PlatformInternational},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := tt.new(append([]ClientOption{WithAPIKey("test-key.test-secret")}, tt.opts...)...)
+			require.NoError(t, err)
+			defer client.Close()
+			assert.Equal(t, tt.want, client.Platform())
+		})
+	}
+}
+
+func TestPlatform_EndpointOverride(t *testing.T) {
+	t.Parallel()
+
+	client, recorder := newPlatformClient(t, PlatformZhipu,
+		WithEndpointPath("videos.result", "/mock/videos/%s"),
+		WithEndpointPath("chat.completions", "/mock/chat"),
+	)
+	ctx := context.Background()
+
+	client.Videos.Retrieve(ctx, "task-1")
+	path, _ := recorder.last()
+	assert.Equal(t, basePath+"/mock/videos/task-1", path)
+
+	client.Chat.Create(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, userMessages))
+	path, _ = recorder.last()
+	assert.Equal(t, basePath+"/mock/chat", path)
+
+	// Other endpoints keep their platform path
+	client.Agents.AsyncResult(ctx, agents.NewAgentAsyncResultRequest("agent-1").SetAsyncID("async-1"))
+	path, _ = recorder.last()
+	assert.Equal(t, "/api/v1/agents/async-result", path)
+
+	// Derived clients do not share overrides with their parent
+	derived, err := client.WithOptions(WithEndpointPath("models.list", "/mock/models"))
+	require.NoError(t, err)
+	defer derived.Close()
+	assert.NotContains(t, client.GetConfig().EndpointPaths, "models.list")
+	assert.Equal(t, PlatformZhipu, derived.Platform())
+}
+
+func TestPlatform_UnknownEndpoint(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithEndpointPath("chat.complete", "/mock/chat"),
+	)
+	var configErr *errors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "EndpointPaths", configErr.Field)
+	assert.Contains(t, err.Error(), "chat.complete")
+
+	assert.Contains(t, Endpoints(), "chat.completions")
+	assert.Contains(t, Endpoints(), "videos.result")
+}
+
+func TestPlatform_WebSearchEngine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		platform Platform
+		engine   string
+		want     string
+	}{
+		{PlatformInternational, websearch.SearchEnginePrime, websearch.SearchEnginePrime},
+		{PlatformZhipu, websearch.SearchEnginePrime, "search_pro"},
+		{PlatformZhipu, "search_std", "search_std"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.platform.String()+"/"+tt.engine, func(t *testing.T) {
+			t.Parallel()
+
+			client, recorder := newPlatformClient(t, tt.platform)
+			req := websearch.NewWebSearchRequest("golang").SetSearchEngine(tt.engine)
+			_, err := client.WebSearch.Search(context.Background(), req)
+			require.NoError(t, err)
+
+			_, body := recorder.last()
+			var sent map[string]any
+			require.NoError(t, json.Unmarshal([]byte(body), &sent))
+			assert.Equal(t, tt.want, sent["search_engine"])
+			assert.Equal(t, tt.engine, req.SearchEngine, "the request is not modified")
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package zai
+
+import (
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
+)
+
+// PollOption configures the schedule of the helpers that wait for
+// asynchronous tasks, such as Videos.WaitForCompletion. The helpers poll
+// with exponential backoff up to their poll interval, with full jitter,
+// and schedule the final poll just before the timeout rather than after
+// it.
+//
+// Example:
+//
+//	result, err := client.Videos.WaitForCompletion(ctx, taskID, 10*time.Second, 5*time.Minute,
+//	    zai.WithPollInitialInterval(500*time.Millisecond),
+//	    zai.WithPollJitter(false),
+//	)
+type PollOption func(*poll.Config)
+
+// WithPollInitialInterval sets the wait after the first status check, so
+// that short tasks are picked up quickly. The wait then doubles up to the
+// poll interval of the helper.
+func WithPollInitialInterval(d time.Duration) PollOption {
+	return func(c *poll.Config) {
+		c.InitialInterval = d
+	}
+}
+
+// WithPollMultiplier sets the growth factor of the wait between status
+// checks. 1 polls at the initial interval throughout; values below 1 are
+// ignored.
+func WithPollMultiplier(m float64) PollOption {
+	return func(c *poll.Config) {
+		c.Multiplier = m
+	}
+}
+
+// WithPollJitter enables or disables full jitter, which draws each wait
+// uniformly between zero and the backoff interval. It is enabled by
+// default so that many pending tasks do not poll in lockstep.
+func WithPollJitter(enabled bool) PollOption {
+	return func(c *poll.Config) {
+		c.DisableJitter = !enabled
+	}
+}
+
+// WithFinalPollLead sets how long before the timeout the final status
+// check is scheduled when the next wait would overshoot it. By default it
+// is a twentieth of the timeout, at most a second.
+func WithFinalPollLead(d time.Duration) PollOption {
+	return func(c *poll.Config) {
+		c.FinalPollLead = d
+	}
+}
+
+// newPoller creates a poller with the schedule of cfg changed by opts,
+// which stops when c is closed.
+func newPoller(c *client.BaseClient, cfg poll.Config, opts []PollOption) *poll.Poller {
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return poll.New(cfg).StopOn(c.Done(), c.Err)
+}
@@ -0,0 +1,124 @@
+package zai
+
+import (
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// Connection pool defaults of the SDK's own transport.
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections kept to
+	// the API unless WithMaxIdleConnsPerHost is given. It should be at
+	// least the number of concurrent requests.
+	DefaultMaxIdleConnsPerHost = constants.DefaultMaxIdleConnsPerHost
+
+	// DefaultIdleConnTimeout is how long idle connections are kept open
+	// unless WithIdleConnTimeout is given.
+	DefaultIdleConnTimeout = constants.DefaultIdleConnTimeout
+
+	// DefaultDialTimeout is the maximum duration for establishing a
+	// connection unless WithDialTimeout is given.
+	DefaultDialTimeout = constants.DefaultConnectTimeout
+)
+
+// PoolConfig overrides the connection pool settings of the SDK's own
+// transport. Zero fields keep the defaults. The settings are ignored when
+// WithHTTPClient or WithTransport is used, since those bring their own
+// connections.
+type PoolConfig = transport.PoolConfig
+
+// WithConnectionPool sets all connection pool settings at once. Zero
+// fields keep the defaults.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithConnectionPool(zai.PoolConfig{
+//	        MaxIdleConnsPerHost: 512,
+//	        IdleConnTimeout:     2 * time.Minute,
+//	    }),
+//	)
+func WithConnectionPool(pool PoolConfig) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool = pool
+	}
+}
+
+// WithMaxIdleConns sets the maximum number of idle connections across all
+// hosts.
+func WithMaxIdleConns(n int) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.MaxIdleConns = n
+	}
+}
+
+// WithMaxIdleConnsPerHost sets the maximum number of idle connections kept
+// to the API. Set it to at least the expected number of concurrent
+// requests; otherwise connections are closed and reopened under load. The
+// default is DefaultMaxIdleConnsPerHost.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithMaxIdleConnsPerHost(512),
+//	)
+func WithMaxIdleConnsPerHost(n int) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.MaxIdleConnsPerHost = n
+	}
+}
+
+// WithMaxConnsPerHost limits the number of connections to the API,
+// including connections in use. Requests over the limit wait for a
+// connection. There is no limit by default.
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnTimeout sets how long idle connections are kept open. The
+// default is DefaultIdleConnTimeout.
+func WithIdleConnTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.IdleConnTimeout = timeout
+	}
+}
+
+// WithTLSHandshakeTimeout sets the maximum duration of a TLS handshake.
+func WithTLSHandshakeTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.TLSHandshakeTimeout = timeout
+	}
+}
+
+// WithDialTimeout sets the maximum duration for establishing a connection.
+// The default is DefaultDialTimeout.
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.DialTimeout = timeout
+	}
+}
+
+// WithForceAttemptHTTP2 controls whether connections negotiate HTTP/2,
+// which multiplexes concurrent requests over one connection. It is enabled
+// by default; pass false to stay on HTTP/1.1.
+func WithForceAttemptHTTP2(enabled bool) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.DisableHTTP2 = !enabled
+	}
+}
+
+// WithKeepAliveDisabled opens a new connection for every request instead
+// of reusing idle ones. It is an escape hatch for proxies and other
+// middleboxes that break long-lived connections, at the cost of a new
+// handshake per request.
+func WithKeepAliveDisabled() ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectionPool.DisableKeepAlives = true
+	}
+}
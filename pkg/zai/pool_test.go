@@ -0,0 +1,108 @@
+package zai
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sdkTransport returns the transport the client created.
+func sdkTransport(t *testing.T, client *Client) *http.Transport {
+	t.Helper()
+
+	rt, ok := client.baseClient.Transport().(*http.Transport)
+	require.True(t, ok, "got %T", client.baseClient.Transport())
+	return rt
+}
+
+func TestConnectionPool_Defaults(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	rt := sdkTransport(t, client)
+	assert.Equal(t, DefaultMaxIdleConnsPerHost, rt.MaxIdleConnsPerHost)
+	assert.GreaterOrEqual(t, rt.MaxIdleConns, rt.MaxIdleConnsPerHost)
+	assert.Zero(t, rt.MaxConnsPerHost)
+	assert.Equal(t, DefaultIdleConnTimeout, rt.IdleConnTimeout)
+	assert.Equal(t, 10*time.Second, rt.TLSHandshakeTimeout)
+	assert.True(t, rt.ForceAttemptHTTP2)
+	assert.False(t, rt.DisableKeepAlives)
+}
+
+func TestConnectionPool_Options(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithMaxIdleConns(300),
+		WithMaxIdleConnsPerHost(200),
+		WithMaxConnsPerHost(250),
+		WithIdleConnTimeout(2*time.Minute),
+		WithTLSHandshakeTimeout(3*time.Second),
+		WithDialTimeout(2*time.Second),
+		WithForceAttemptHTTP2(false),
+		WithKeepAliveDisabled(),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	rt := sdkTransport(t, client)
+	assert.Equal(t, 300, rt.MaxIdleConns)
+	assert.Equal(t, 200, rt.MaxIdleConnsPerHost)
+	assert.Equal(t, 250, rt.MaxConnsPerHost)
+	assert.Equal(t, 2*time.Minute, rt.IdleConnTimeout)
+	assert.Equal(t, 3*time.Second, rt.TLSHandshakeTimeout)
+	assert.False(t, rt.ForceAttemptHTTP2)
+	assert.True(t, rt.DisableKeepAlives)
+	assert.Equal(t, PoolConfig{
+		MaxIdleConns:        300,
+		MaxIdleConnsPerHost: 200,
+		MaxConnsPerHost:     250,
+		IdleConnTimeout:     2 * time.Minute,
+		TLSHandshakeTimeout: 3 * time.Second,
+		DialTimeout:         2 * time.Second,
+		DisableHTTP2:        true,
+		DisableKeepAlives:   true,
+	}, client.GetConfig().ConnectionPool)
+}
+
+func TestConnectionPool_Ignored(t *testing.T) {
+	t.Parallel()
+
+	shared := &http.Transport{}
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithTransport(shared),
+		WithMaxIdleConnsPerHost(200),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Same(t, shared, client.baseClient.Transport())
+	assert.Zero(t, shared.MaxIdleConnsPerHost)
+}
+
+func TestConnectionPool_WithOptions(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	same, err := client.WithOptions(WithProject("proj-1"))
+	require.NoError(t, err)
+	defer same.Close()
+	assert.Same(t, client.baseClient.Transport(), same.baseClient.Transport())
+
+	tuned, err := client.WithOptions(WithMaxIdleConnsPerHost(512))
+	require.NoError(t, err)
+	defer tuned.Close()
+	assert.NotSame(t, client.baseClient.Transport(), tuned.baseClient.Transport())
+	assert.Equal(t, 512, sdkTransport(t, tuned).MaxIdleConnsPerHost)
+}
@@ -0,0 +1,86 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/ratelimit"
+)
+
+// RateLimitMetrics is a snapshot of client-side rate limiter activity.
+type RateLimitMetrics = ratelimit.Metrics
+
+// WithRateLimit paces requests client-side so that no more than
+// requestsPerMinute requests and tokensPerMinute tokens are sent per minute.
+// Pass zero to leave either limit unset.
+//
+// The limiter is shared by all services of the client and is safe for
+// concurrent use. Requests block until a slot is available or their context
+// is done. Token cost is estimated as max_tokens plus a local estimate of the
+// prompt size; see WithAccurateTokenCounting for exact chat prompt counts.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRateLimit(600, 100000),
+//	)
+func WithRateLimit(requestsPerMinute, tokensPerMinute int) ClientOption {
+	return func(c *ClientConfig) {
+		c.RequestsPerMinute = requestsPerMinute
+		c.TokensPerMinute = tokensPerMinute
+	}
+}
+
+// WithAccurateTokenCounting counts chat prompt tokens with the tokenizer
+// endpoint for token-per-minute limiting, instead of a local estimate.
+// This costs one extra tokenizer call per chat request; tokenizer calls are
+// not themselves rate limited. It has no effect without WithRateLimit.
+func WithAccurateTokenCounting() ClientOption {
+	return func(c *ClientConfig) {
+		c.AccurateTokenCounting = true
+	}
+}
+
+// RateLimitMetrics returns a snapshot of client-side rate limiter activity,
+// or zero metrics if no rate limit is configured.
+//
+// Example:
+//
+//	m := client.RateLimitMetrics()
+//	fmt.Printf("in flight: %d, avg wait: %s\n", m.InFlight, m.AverageWait())
+func (c *Client) RateLimitMetrics() RateLimitMetrics {
+	if c.rateLimiter == nil {
+		return RateLimitMetrics{}
+	}
+	return c.rateLimiter.Metrics()
+}
+
+// countChatTokens estimates the token cost of a request, using the
+// tokenizer endpoint for chat completions.
+func (c *Client) countChatTokens(ctx context.Context, req *http.Request, body []byte) (int, error) {
+	if !strings.HasSuffix(req.URL.Path, "/chat/completions") {
+		return ratelimit.EstimateTokens(body), nil
+	}
+
+	var chatReq chat.ChatCompletionRequest
+	if err := json.Unmarshal(body, &chatReq); err != nil {
+		return 0, err
+	}
+
+	tokenizerReq := tools.NewTokenizerRequest(chatReq.Model, chatReq.Messages).SetTools(chatReq.Tools)
+	resp, err := c.Tools.Tokenizer(ratelimit.WithoutLimit(ctx), tokenizerReq)
+	if err != nil {
+		return 0, err
+	}
+
+	tokens := resp.Usage.TotalTokens
+	if chatReq.MaxTokens != nil {
+		tokens += *chatReq.MaxTokens
+	}
+	return tokens, nil
+}
@@ -0,0 +1,170 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+const mockChatResponse = `{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`
+
+func TestWithRateLimit_ConcurrentChatCalls(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var arrivals []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockChatResponse))
+	}))
+	defer server.Close()
+
+	// 6000 RPM = one request every 10ms
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithRateLimit(6000, 0),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	const calls = 100
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Chat.Create(context.Background(), &chat.ChatCompletionRequest{
+				Model:    "glm-4.7",
+				Messages: []chat.Message{chat.NewUserMessage("hi")},
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// 100 requests spaced 10ms apart take at least 990ms
+	assert.GreaterOrEqual(t, elapsed, 950*time.Millisecond)
+
+	require.Len(t, arrivals, calls)
+	sort.Slice(arrivals, func(i, j int) bool { return arrivals[i].Before(arrivals[j]) })
+
+	// No 100ms window may contain more than the paced number of requests,
+	// allowing a little scheduling slack
+	for i := range arrivals {
+		inWindow := 0
+		for j := i; j < len(arrivals) && arrivals[j].Sub(arrivals[i]) < 100*time.Millisecond; j++ {
+			inWindow++
+		}
+		assert.LessOrEqual(t, inWindow, 13, "too many requests in window starting at %d", i)
+	}
+
+	m := client.RateLimitMetrics()
+	assert.Equal(t, int64(calls), m.Requests)
+	assert.Equal(t, int64(0), m.InFlight)
+	assert.Equal(t, int64(0), m.Waiting)
+	assert.Greater(t, m.MaxWait, 900*time.Millisecond)
+}
+
+func TestWithRateLimit_ContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(mockChatResponse))
+	}))
+	defer server.Close()
+
+	// 1 RPM: the second request would wait a minute
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithRateLimit(1, 0),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	req := func() *chat.ChatCompletionRequest {
+		return &chat.ChatCompletionRequest{
+			Model:    "glm-4.7",
+			Messages: []chat.Message{chat.NewUserMessage("hi")},
+		}
+	}
+
+	_, err = client.Chat.Create(context.Background(), req())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = client.Chat.Create(ctx, req())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWithAccurateTokenCounting(t *testing.T) {
+	t.Parallel()
+
+	var tokenizerCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tokenizer":
+			tokenizerCalls.Add(1)
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			assert.Equal(t, "glm-4.7", body["model"])
+			w.Write([]byte(`{"id":"tok","usage":{"prompt_tokens":40,"total_tokens":40}}`))
+		default:
+			w.Write([]byte(mockChatResponse))
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithRateLimit(0, 600000),
+		WithAccurateTokenCounting(),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Chat.Create(context.Background(), (&chat.ChatCompletionRequest{
+		Model:    "glm-4.7",
+		Messages: []chat.Message{chat.NewUserMessage("hi")},
+	}).SetMaxTokens(60))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), tokenizerCalls.Load())
+	// The tokenizer call itself bypasses the limiter
+	assert.Equal(t, int64(1), client.RateLimitMetrics().Requests)
+}
+
+func TestClient_RateLimitMetrics_NoLimit(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, RateLimitMetrics{}, client.RateLimitMetrics())
+}
@@ -0,0 +1,94 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// Do calls an API endpoint that has no typed service yet, such as a newly
+// released one. Prefer the typed services where they exist; Do is an
+// escape hatch that trades type safety for immediate access.
+//
+// The request gets the same authentication, base URL, headers, retries,
+// rate limiting, hooks and tracing as the services. path is relative to
+// the base URL; query parameters are added with WithQueryParams and
+// per-request headers with WithHeaders. body, if not nil, is encoded as
+// JSON. The JSON response is decoded into out, which may be nil to discard
+// the response. Error responses are returned as the same typed errors as
+// the services return, such as *errors.RateLimitError.
+//
+// Example:
+//
+//	var result struct {
+//	    Items []string `json:"items"`
+//	}
+//	err := client.Do(ctx, http.MethodPost, "/new-endpoint", map[string]any{
+//	    "query": "hello",
+//	}, &result)
+func (c *Client) Do(ctx context.Context, method, path string, body, out any) error {
+	if err := validateRawPath(path); err != nil {
+		return err
+	}
+
+	apiResp, err := c.baseClient.Send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return apiResp.Close()
+	}
+	return c.baseClient.ParseJSON(apiResp, out)
+}
+
+// DoStream calls a streaming API endpoint that has no typed service yet and
+// returns its server-sent events. Each event's data is returned as raw
+// JSON for the caller to decode. See Do for how the request is built.
+//
+// Example:
+//
+//	stream, err := client.DoStream(ctx, http.MethodPost, "/new-endpoint", req)
+//	if err != nil {
+//	    return err
+//	}
+//	defer stream.Close()
+//
+//	for stream.Next() {
+//	    var chunk MyChunk
+//	    if err := json.Unmarshal(*stream.Current(), &chunk); err != nil {
+//	        return err
+//	    }
+//	}
+//	if err := stream.Err(); err != nil {
+//	    return err
+//	}
+func (c *Client) DoStream(ctx context.Context, method, path string, body any) (*streaming.Stream[json.RawMessage], error) {
+	if err := validateRawPath(path); err != nil {
+		return nil, err
+	}
+
+	streamResp, err := c.baseClient.StreamMethod(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.NewTypedStream[json.RawMessage](streamResp, ctx), nil
+}
+
+// validateRawPath rejects absolute URLs, which would send the credentials
+// to another host.
+func validateRawPath(path string) error {
+	u, err := url.Parse(path)
+	if err != nil {
+		return errors.NewValidationError("path", "invalid path: "+err.Error(), path)
+	}
+	if u.IsAbs() || u.Host != "" {
+		return errors.NewValidationError("path", "must be relative to the base URL", path)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package zai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func newRawTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestClient_Do(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/beta/echo", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("version"))
+		assert.Equal(t, "Bearer test-key.test-secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "raw-test", r.Header.Get("X-Source"))
+
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"query":"hello"}`, string(body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":["a","b"]}`))
+	})
+
+	ctx := WithHeaders(context.Background(), http.Header{"X-Source": []string{"raw-test"}})
+	ctx = WithQueryParams(ctx, url.Values{"version": []string{"2"}})
+
+	var out struct {
+		Items []string `json:"items"`
+	}
+	err := client.Do(ctx, http.MethodPost, "/beta/echo", map[string]string{"query": "hello"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, out.Items)
+
+	// A nil out discards the response
+	require.NoError(t, client.Do(ctx, http.MethodPost, "/beta/echo", map[string]string{"query": "hello"}, nil))
+}
+
+func TestClient_Do_Retry(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	var out map[string]bool
+	err := client.Do(context.Background(), http.MethodGet, "/beta/status", nil, &out)
+	require.NoError(t, err)
+	assert.True(t, out["ok"])
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestClient_Do_ErrorParity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"bad request", http.StatusBadRequest, `{"error":{"code":"1210","message":"invalid parameter"}}`, &errors.APIRequestFailedError{}},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"code":"1001","message":"invalid api key"}}`, &errors.APIAuthenticationError{}},
+		{"not found", http.StatusNotFound, `{"error":{"code":"1211","message":"model not found"}}`, &errors.APIStatusError{}},
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"code":"1302","message":"slow down"}}`, &errors.APIReachLimitError{}},
+		{"non-JSON body", http.StatusBadGateway, `<html>bad gateway</html>`, &errors.APIStatusError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			})
+
+			// Errors quote the request ID
+			ctx := WithRequestID(context.Background(), "req-parity")
+
+			req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hello")})
+			_, serviceErr := client.Chat.Create(ctx, req)
+			rawErr := client.Do(ctx, http.MethodPost, "/chat/completions", req, &chat.ChatCompletionResponse{})
+
+			require.Error(t, rawErr)
+			assert.IsType(t, tt.want, rawErr)
+			assert.IsType(t, serviceErr, rawErr)
+			assert.Equal(t, serviceErr.Error(), rawErr.Error())
+
+			_, streamErr := client.DoStream(ctx, http.MethodPost, "/chat/completions", req)
+			assert.IsType(t, serviceErr, streamErr)
+		})
+	}
+}
+
+func TestClient_Do_AbsoluteURL(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	})
+
+	err := client.Do(context.Background(), http.MethodGet, "https://example.com/steal", nil, nil)
+	var validationErr *errors.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "path", validationErr.Field)
+
+	_, err = client.DoStream(context.Background(), http.MethodGet, "//example.com/steal", nil)
+	require.ErrorAs(t, err, &validationErr)
+}
+
+func TestClient_DoStream(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "/beta/events", r.URL.Path)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: {\"n\":1}\n\n"))
+		w.Write([]byte("data: {\"n\":2}\n\n"))
+		w.Write([]byte("data: [DONE]\n\n"))
+	})
+
+	stream, err := client.DoStream(context.Background(), http.MethodGet, "/beta/events", nil)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var got []int
+	for stream.Next() {
+		var event struct {
+			N int `json:"n"`
+		}
+		require.NoError(t, json.Unmarshal(*stream.Current(), &event))
+		got = append(got, event.N)
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, []int{1, 2}, got)
+}
@@ -0,0 +1,205 @@
+// Package redact provides a request sanitizer that replaces personal data
+// and other sensitive text in requests with placeholder tokens, using
+// regular expressions.
+//
+// Example:
+//
+//	redactor := redact.New(
+//	    redact.Email(),
+//	    redact.Phone(),
+//	    redact.Hostnames("corp.example.com", "internal"),
+//	    redact.Pattern("employee-id", `\bEMP-\d{6}\b`, "[EMPLOYEE_ID]"),
+//	)
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRequestSanitizer(redactor.Sanitize),
+//	)
+package redact
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Default replacement tokens.
+const (
+	// EmailToken replaces email addresses.
+	EmailToken = "[EMAIL]"
+
+	// PhoneToken replaces phone numbers.
+	PhoneToken = "[PHONE]"
+
+	// HostnameToken replaces internal hostnames.
+	HostnameToken = "[HOST]"
+)
+
+// emailPattern matches email addresses.
+var emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+
+// phonePattern matches phone numbers of 10 or 11 digits, such as
+// "+1 (415) 555-0100", "415.555.0100" or "+86 138 0013 8000", with an
+// optional country code.
+var phonePattern = regexp.MustCompile(`(?:\+\d{1,3}[\s.-]?)?(?:\(\d{3}\)\s?|\b\d{3}[\s.-]?)\d{3,4}[\s.-]?\d{4}\b`)
+
+// Rule replaces the matches of a pattern.
+type Rule struct {
+	// Name identifies the rule.
+	Name string
+
+	// Pattern matches the text to replace.
+	Pattern *regexp.Regexp
+
+	// Replacement replaces every match. $1 and ${name} refer to
+	// submatches, as in regexp.Regexp.ReplaceAllString.
+	Replacement string
+}
+
+// Email returns a rule that replaces email addresses with EmailToken.
+func Email() Rule {
+	return Rule{Name: "email", Pattern: emailPattern, Replacement: EmailToken}
+}
+
+// Phone returns a rule that replaces phone numbers with PhoneToken.
+func Phone() Rule {
+	return Rule{Name: "phone", Pattern: phonePattern, Replacement: PhoneToken}
+}
+
+// Hostnames returns a rule that replaces the given domains and their
+// subdomains with HostnameToken. For example, Hostnames("corp.example.com")
+// replaces "corp.example.com" and "db1.eu.corp.example.com" but not
+// "example.com".
+func Hostnames(domains ...string) Rule {
+	quoted := make([]string, len(domains))
+	for i, domain := range domains {
+		quoted[i] = regexp.QuoteMeta(strings.Trim(domain, "."))
+	}
+	expr := `(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)*(?:` + strings.Join(quoted, "|") + `)\b`
+	return Rule{Name: "hostname", Pattern: regexp.MustCompile(expr), Replacement: HostnameToken}
+}
+
+// Pattern returns a rule that replaces the matches of the regular
+// expression expr with replacement. It panics if expr does not compile.
+func Pattern(name, expr, replacement string) Rule {
+	return Rule{Name: name, Pattern: regexp.MustCompile(expr), Replacement: replacement}
+}
+
+// WithReplacement returns a copy of the rule with another replacement.
+//
+// Example:
+//
+//	redact.Email().WithReplacement("<email>")
+func (r Rule) WithReplacement(replacement string) Rule {
+	r.Replacement = replacement
+	return r
+}
+
+// Redactor replaces the matches of its rules in text and in the string
+// fields of requests. It is safe for concurrent use.
+type Redactor struct {
+	rules []Rule
+}
+
+// New creates a redactor that applies rules in order.
+func New(rules ...Rule) *Redactor {
+	return &Redactor{rules: rules}
+}
+
+// String returns s with the matches of every rule replaced.
+func (r *Redactor) String(s string) string {
+	for _, rule := range r.rules {
+		s = rule.Pattern.ReplaceAllString(s, rule.Replacement)
+	}
+	return s
+}
+
+// Redact replaces the matches of every rule in the strings reachable from
+// payload, which must be a pointer: exported string fields, slice and
+// array elements, map values and interface values, following pointers.
+// Unexported fields and byte slices, such as json.RawMessage, are left
+// unchanged.
+func (r *Redactor) Redact(payload any) error {
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Pointer {
+		return fmt.Errorf("redact: payload must be a pointer, got %T", payload)
+	}
+	r.walk(v, make(map[uintptr]bool))
+	return nil
+}
+
+// Sanitize redacts payload. Its signature matches zai.RequestSanitizer,
+// so a redactor can be passed to zai.WithRequestSanitizer; it redacts the
+// requests of every service.
+func (r *Redactor) Sanitize(service string, payload any) error {
+	return r.Redact(payload)
+}
+
+// walk redacts the strings reachable from v. visited records the pointers
+// already walked, so that cyclic structures terminate.
+func (r *Redactor) walk(v reflect.Value, visited map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || visited[v.Pointer()] {
+			return
+		}
+		visited[v.Pointer()] = true
+		r.walk(v.Elem(), visited)
+
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(r.String(v.String()))
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				r.walk(v.Field(i), visited)
+			}
+		}
+
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			r.walk(v.Index(i), visited)
+		}
+
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			r.walk(v.Index(i), visited)
+		}
+
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// Map values cannot be modified in place: redact a copy
+			value := r.redactCopy(iter.Value(), visited)
+			v.SetMapIndex(iter.Key(), value)
+		}
+
+	case reflect.Interface:
+		if v.IsNil() || !v.CanSet() {
+			return
+		}
+		v.Set(r.redactCopy(v.Elem(), visited))
+	}
+}
+
+// redactCopy returns a redacted copy of v, which may not be settable.
+// Pointers are shared, so the values they point to are redacted in place.
+func (r *Redactor) redactCopy(v reflect.Value, visited map[uintptr]bool) reflect.Value {
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	c := reflect.New(v.Type()).Elem()
+	c.Set(v)
+	r.walk(c, visited)
+	return c
+}
@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRules(t *testing.T) {
+	t.Parallel()
+
+	r := New(Email(), Phone(), Hostnames("corp.example.com", ".internal"))
+
+	tests := []struct {
+		in, want string
+	}{
+		{"mail jane.doe+tag@example.co.uk today", "mail [EMAIL] today"},
+		{"call +1 (415) 555-0100 or 415.555.0100", "call [PHONE] or [PHONE]"},
+		{"call +86 138 0013 8000 or 13800138000", "call [PHONE] or [PHONE]"},
+		{"ssh db1.eu.corp.example.com and build.internal", "ssh [HOST] and [HOST]"},
+		{"DB1.CORP.EXAMPLE.COM", "[HOST]"},
+		{"ops@build.internal", "[EMAIL]"},
+		// Text that only looks similar is kept
+		{"released 2024-10-16, build 4155, see example.com", "released 2024-10-16, build 4155, see example.com"},
+		{"notcorp.example.com.evil", "notcorp.example.com.evil"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, r.String(tt.in), tt.in)
+	}
+}
+
+func TestPattern(t *testing.T) {
+	t.Parallel()
+
+	r := New(
+		Pattern("employee-id", `\bEMP-(\d{2})\d{4}\b`, "EMP-${1}****"),
+		Email().WithReplacement("<email>"),
+	)
+	assert.Equal(t, "EMP-12**** wrote from <email>", r.String("EMP-123456 wrote from a@b.io"))
+
+	assert.Panics(t, func() { Pattern("bad", `(`, "") })
+}
+
+// testPart is a content part held in an interface value.
+type testPart struct {
+	Type string
+	Text string
+}
+
+type testMessage struct {
+	Role    string
+	Content any
+}
+
+type testRequest struct {
+	Model    string
+	Messages []testMessage
+	Input    any
+	Tags     map[string]string
+	Extra    map[string]any
+	Next     *testRequest
+	Raw      json.RawMessage
+	Count    int
+	private  string
+}
+
+func TestRedactor_Redact(t *testing.T) {
+	t.Parallel()
+
+	r := New(Email())
+	req := &testRequest{
+		Model: "glm-4.7",
+		Messages: []testMessage{
+			{Role: "user", Content: "I am a@b.io"},
+			{Role: "user", Content: []testPart{{Type: "text", Text: "cc c@d.io"}}},
+			{Role: "user", Content: []any{testPart{Type: "text", Text: "e@f.io"}, &testPart{Type: "text", Text: "g@h.io"}}},
+			{Role: "user", Content: nil},
+		},
+		Input:   []string{"x@y.io", "plain"},
+		Tags:    map[string]string{"owner": "o@p.io"},
+		Extra:   map[string]any{"note": "n@m.io", "nested": map[string]any{"k": "k@l.io"}, "n": 1},
+		Raw:     json.RawMessage(`"r@s.io"`),
+		Count:   3,
+		private: "q@r.io",
+	}
+	req.Next = req
+
+	require.NoError(t, r.Redact(req))
+
+	assert.Equal(t, "glm-4.7", req.Model)
+	assert.Equal(t, "I am [EMAIL]", req.Messages[0].Content)
+	assert.Equal(t, []testPart{{Type: "text", Text: "cc [EMAIL]"}}, req.Messages[1].Content)
+	parts := req.Messages[2].Content.([]any)
+	assert.Equal(t, testPart{Type: "text", Text: "[EMAIL]"}, parts[0])
+	assert.Equal(t, &testPart{Type: "text", Text: "[EMAIL]"}, parts[1])
+	assert.Nil(t, req.Messages[3].Content)
+	assert.Equal(t, []string{"[EMAIL]", "plain"}, req.Input)
+	assert.Equal(t, "[EMAIL]", req.Tags["owner"])
+	assert.Equal(t, "[EMAIL]", req.Extra["note"])
+	assert.Equal(t, "[EMAIL]", req.Extra["nested"].(map[string]any)["k"])
+	assert.Equal(t, 1, req.Extra["n"])
+
+	// Raw JSON and unexported fields are left alone
+	assert.Equal(t, `"r@s.io"`, string(req.Raw))
+	assert.Equal(t, "q@r.io", req.private)
+	assert.Equal(t, 3, req.Count)
+}
+
+func TestRedactor_RedactRequiresPointer(t *testing.T) {
+	t.Parallel()
+
+	assert.Error(t, New(Email()).Redact(testRequest{}))
+	assert.NoError(t, New(Email()).Sanitize("chat", &testRequest{}))
+}
@@ -0,0 +1,93 @@
+package zai
+
+import (
+	"context"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/transport"
+)
+
+// RetryPolicy overrides retry behavior for a client or a single request.
+// Zero fields inherit the next level: per-request policies take precedence
+// over the client policy, which takes precedence over the SDK defaults.
+type RetryPolicy = transport.RetryPolicy
+
+// NonIdempotentRetry controls when non-idempotent requests, such as chat
+// completions, are retried after a network error.
+type NonIdempotentRetry = transport.NonIdempotentRetry
+
+const (
+	// NonIdempotentRetryDefault inherits the client setting.
+	NonIdempotentRetryDefault = transport.NonIdempotentRetryDefault
+
+	// NonIdempotentRetryNever never retries non-idempotent requests.
+	NonIdempotentRetryNever = transport.NonIdempotentRetryNever
+
+	// NonIdempotentRetryPreRequest retries non-idempotent requests only
+	// when the request never reached the server, such as connection
+	// refused or DNS failures.
+	NonIdempotentRetryPreRequest = transport.NonIdempotentRetryPreRequest
+
+	// NonIdempotentRetryAlways retries non-idempotent requests after any
+	// network error, even when the server may have processed them.
+	// This is the SDK default.
+	NonIdempotentRetryAlways = transport.NonIdempotentRetryAlways
+)
+
+// DefaultRetryBudgetWindow is the sliding window used by WithRetryBudget
+// when no window is given.
+const DefaultRetryBudgetWindow = 10 * time.Second
+
+// WithRetryPolicy returns a copy of ctx whose requests use the given retry
+// policy. Zero fields of policy keep the client setting.
+//
+// Example:
+//
+//	// Fail fast, and only retry if the request never left the machine
+//	ctx := zai.WithRetryPolicy(ctx, zai.RetryPolicy{
+//	    MaxAttempts:   2,
+//	    MaxBackoff:    500 * time.Millisecond,
+//	    NonIdempotent: zai.NonIdempotentRetryPreRequest,
+//	})
+//
+//	resp, err := client.Chat.Create(ctx, req)
+func WithRetryPolicy(ctx context.Context, policy RetryPolicy) context.Context {
+	return transport.ContextWithRetryPolicy(ctx, policy)
+}
+
+// WithDefaultRetryPolicy sets the retry policy for all requests made by
+// the client. A non-zero MaxAttempts takes precedence over WithMaxRetries.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithDefaultRetryPolicy(zai.RetryPolicy{
+//	        NonIdempotent: zai.NonIdempotentRetryPreRequest,
+//	    }),
+//	)
+func WithDefaultRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *ClientConfig) {
+		c.RetryPolicy = policy
+	}
+}
+
+// WithRetryBudget limits retries to ratio times the number of requests
+// made within the sliding window, preventing retry storms when the API is
+// degraded. A small number of retries per window is always allowed so that
+// low-traffic clients can still recover from transient errors.
+// If window is zero, DefaultRetryBudgetWindow is used.
+//
+// Example:
+//
+//	// At most 10% of requests may be retries
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRetryBudget(0.1, time.Minute),
+//	)
+func WithRetryBudget(ratio float64, window time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.RetryBudgetRatio = ratio
+		c.RetryBudgetWindow = window
+	}
+}
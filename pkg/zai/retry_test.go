@@ -0,0 +1,121 @@
+package zai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Precedence(t *testing.T) {
+	t.Parallel()
+
+	// Keeps the SDK default attempts but backs off quickly
+	fast := RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		policy       *RetryPolicy
+		wantAttempts int32
+	}{
+		{
+			name:         "defaults",
+			opts:         []ClientOption{WithDefaultRetryPolicy(fast)},
+			wantAttempts: 4,
+		},
+		{
+			name:         "client max retries over defaults",
+			opts:         []ClientOption{WithDefaultRetryPolicy(fast), WithMaxRetries(1)},
+			wantAttempts: 2,
+		},
+		{
+			name: "client policy over client max retries",
+			opts: []ClientOption{
+				WithMaxRetries(1),
+				WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 3, MaxBackoff: 2 * time.Millisecond}),
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:         "per-request disables retries",
+			opts:         []ClientOption{WithDefaultRetryPolicy(fast), WithMaxRetries(2)},
+			policy:       &RetryPolicy{MaxAttempts: 1},
+			wantAttempts: 1,
+		},
+		{
+			name:         "per-request over client",
+			opts:         []ClientOption{WithDefaultRetryPolicy(fast), WithMaxRetries(1)},
+			policy:       &RetryPolicy{MaxAttempts: 5},
+			wantAttempts: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":{"code":"1305","message":"busy"}}`))
+			}))
+			defer server.Close()
+
+			opts := append([]ClientOption{
+				WithAPIKey("test-key.test-secret"),
+				WithBaseURL(server.URL),
+			}, tt.opts...)
+			client, err := NewClient(opts...)
+			require.NoError(t, err)
+			defer client.Close()
+
+			ctx := context.Background()
+			if tt.policy != nil {
+				ctx = WithRetryPolicy(ctx, *tt.policy)
+			}
+
+			_, err = client.Models.List(ctx)
+			assert.Error(t, err)
+			assert.Equal(t, tt.wantAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestWithRetryBudget(t *testing.T) {
+	t.Parallel()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(20),
+		WithDefaultRetryPolicy(RetryPolicy{InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+		WithRetryBudget(0.1, time.Minute),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Equal(t, 0.1, client.GetConfig().RetryBudgetRatio)
+
+	_, err = client.Models.List(context.Background())
+	assert.Error(t, err)
+
+	// One request allows the minimum retries per window, not all 20
+	assert.Less(t, attempts.Load(), int32(21))
+	assert.Greater(t, attempts.Load(), int32(1))
+}
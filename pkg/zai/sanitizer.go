@@ -0,0 +1,48 @@
+package zai
+
+// Services passed to a RequestSanitizer.
+const (
+	// ServiceChat is the service of *chat.ChatCompletionRequest payloads.
+	ServiceChat = "chat"
+
+	// ServiceEmbeddings is the service of *embeddings.EmbeddingRequest payloads.
+	ServiceEmbeddings = "embeddings"
+
+	// ServiceAssistant is the service of *assistant.ConversationRequest payloads.
+	ServiceAssistant = "assistant"
+
+	// ServiceModerations is the service of *moderation.ModerationRequest payloads.
+	ServiceModerations = "moderations"
+
+	// ServiceTools is the service of *tools.WebSearchRequest and
+	// *tools.TokenizerRequest payloads.
+	ServiceTools = "tools"
+)
+
+// RequestSanitizer inspects the request of a service before it is
+// serialized. It may modify payload, a pointer to the request struct, in
+// place, for example to redact personal data, or return an error to block
+// the request. It must be safe for concurrent use.
+type RequestSanitizer func(service string, payload any) error
+
+// WithRequestSanitizer sets a sanitizer that is called with every chat,
+// embeddings, assistant conversation, moderation and tools request just
+// before it is serialized, and before the connection of streaming requests
+// is opened. It runs once per call, not per retry. When it returns an
+// error, the request is not sent and the call fails with an
+// *errors.RequestBlockedError wrapping it. The redact package provides a
+// regular expression based sanitizer.
+//
+// Example:
+//
+//	redactor := redact.New(redact.Email(), redact.Phone(), redact.Hostnames("corp.example.com"))
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRequestSanitizer(redactor.Sanitize),
+//	)
+func WithRequestSanitizer(sanitizer RequestSanitizer) ClientOption {
+	return func(c *ClientConfig) {
+		c.RequestSanitizer = sanitizer
+	}
+}
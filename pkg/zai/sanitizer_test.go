@@ -0,0 +1,159 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/assistant"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/moderation"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretEmail is the personal data the sanitizer tests redact.
+const secretEmail = "jane.doe@example.com"
+
+// sanitizedCalls sends a request with secretEmail through every
+// sanitized service.
+var sanitizedCalls = []struct {
+	name    string
+	service string
+	call    func(ctx context.Context, c *Client) error
+}{
+	{"Chat.Create", ServiceChat, func(ctx context.Context, c *Client) error {
+		return ignore(c.Chat.Create(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Email " + secretEmail)})))
+	}},
+	{"Chat.CreateStream", ServiceChat, func(ctx context.Context, c *Client) error {
+		return closeStream(c.Chat.CreateStream(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Email " + secretEmail)})))
+	}},
+	{"Embeddings.Create", ServiceEmbeddings, func(ctx context.Context, c *Client) error {
+		return ignore(c.Embeddings.Create(ctx, embeddings.NewBatchEmbeddingRequest("embedding-3", []string{"Email " + secretEmail})))
+	}},
+	{"Assistant.Conversation", ServiceAssistant, func(ctx context.Context, c *Client) error {
+		return ignore(c.Assistant.Conversation(ctx, assistant.NewConversationRequest("asst_1", []assistant.ConversationMessage{{
+			Role:    "user",
+			Content: []assistant.MessageContent{assistant.MessageTextContent{Type: "text", Text: "Email " + secretEmail}},
+		}})))
+	}},
+	{"Assistant.ConversationStream", ServiceAssistant, func(ctx context.Context, c *Client) error {
+		return closeStream(c.Assistant.ConversationStream(ctx, assistant.NewConversationRequest("asst_1", []assistant.ConversationMessage{{
+			Role:    "user",
+			Content: []assistant.MessageContent{assistant.MessageTextContent{Type: "text", Text: "Email " + secretEmail}},
+		}})))
+	}},
+	{"Moderations.Create", ServiceModerations, func(ctx context.Context, c *Client) error {
+		return ignore(c.Moderations.Create(ctx, moderation.NewTextModerationRequest("moderation", "Email "+secretEmail)))
+	}},
+	{"Tools.WebSearch", ServiceTools, func(ctx context.Context, c *Client) error {
+		return ignore(c.Tools.WebSearch(ctx, tools.NewWebSearchRequest("web-search-pro", []chat.Message{chat.NewUserMessage("Email " + secretEmail)})))
+	}},
+	{"Tools.WebSearchStream", ServiceTools, func(ctx context.Context, c *Client) error {
+		return closeStream(c.Tools.WebSearchStream(ctx, tools.NewWebSearchRequest("web-search-pro", []chat.Message{chat.NewUserMessage("Email " + secretEmail)})))
+	}},
+	{"Tools.Tokenizer", ServiceTools, func(ctx context.Context, c *Client) error {
+		return ignore(c.Tools.Tokenizer(ctx, tools.NewTokenizerRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Email " + secretEmail)})))
+	}},
+}
+
+// bodyRecorder is a server handler that records request bodies.
+type bodyRecorder struct {
+	mu     sync.Mutex
+	bodies []string
+}
+
+func (b *bodyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	b.mu.Lock()
+	b.bodies = append(b.bodies, string(body))
+	b.mu.Unlock()
+
+	if strings.Contains(string(body), `"stream":true`) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: [DONE]\n\n"))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{}`))
+}
+
+func (b *bodyRecorder) Bodies() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.bodies...)
+}
+
+func TestWithRequestSanitizer_Redacts(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range sanitizedCalls {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			recorder := &bodyRecorder{}
+			var services []string
+			redactor := redact.New(redact.Email())
+			client := newLimitsTestClient(t, recorder.ServeHTTP, WithRequestSanitizer(func(service string, payload any) error {
+				services = append(services, service)
+				return redactor.Sanitize(service, payload)
+			}))
+
+			require.NoError(t, tt.call(context.Background(), client))
+
+			bodies := recorder.Bodies()
+			require.Len(t, bodies, 1)
+			assert.Contains(t, bodies[0], "Email "+redact.EmailToken)
+			assert.NotContains(t, bodies[0], secretEmail)
+			assert.Equal(t, []string{tt.service}, services)
+		})
+	}
+}
+
+func TestWithRequestSanitizer_Blocks(t *testing.T) {
+	t.Parallel()
+
+	blocked := stderrors.New("prompt contains personal data")
+	for _, tt := range sanitizedCalls {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var requests atomic.Int32
+			client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+			}, WithRequestSanitizer(func(service string, payload any) error {
+				return blocked
+			}))
+
+			err := tt.call(context.Background(), client)
+			assert.True(t, errors.IsRequestBlockedError(err), "got %v", err)
+			assert.ErrorIs(t, err, blocked)
+
+			// Streams fail before the connection is opened
+			assert.Zero(t, requests.Load())
+		})
+	}
+}
+
+func TestWithRequestSanitizer_OtherServices(t *testing.T) {
+	t.Parallel()
+
+	recorder := &bodyRecorder{}
+	client := newLimitsTestClient(t, recorder.ServeHTTP, WithRequestSanitizer(func(service string, payload any) error {
+		t.Errorf("sanitizer called for %s", service)
+		return nil
+	}))
+
+	_, err := client.Assistant.QuerySupport(context.Background(), []string{"asst_1"})
+	require.NoError(t, err)
+	assert.Len(t, recorder.Bodies(), 1)
+}
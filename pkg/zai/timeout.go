@@ -0,0 +1,79 @@
+package zai
+
+import (
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/constants"
+)
+
+// Timeout defaults. A deadline or cancellation of the context of a call
+// applies as well: whichever expires first ends the call.
+const (
+	// DefaultRequestTimeout bounds non-streaming requests unless
+	// WithRequestTimeout is given.
+	DefaultRequestTimeout = constants.DefaultTimeout
+
+	// DefaultStreamReadTimeout bounds every wait of a stream for data
+	// unless WithStreamReadTimeout is given.
+	DefaultStreamReadTimeout = constants.DefaultStreamReadTimeout
+)
+
+// WithConnectTimeout sets the maximum duration for establishing a
+// connection, and separately for its TLS handshake. A connection that is
+// not established in time fails with an *errors.ConnectTimeoutError; since
+// the request was never sent, it is retried even if not idempotent. The
+// defaults are DefaultDialTimeout and a 10 second TLS handshake timeout.
+// WithDialTimeout and WithTLSHandshakeTimeout override either part. The
+// setting is ignored with WithHTTPClient or WithTransport.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithConnectTimeout(3 * time.Second),
+//	)
+func WithConnectTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.ConnectTimeout = timeout
+	}
+}
+
+// WithRequestTimeout sets the maximum duration of each attempt of a
+// non-streaming request, from sending it to reading the last byte of the
+// response. An attempt that takes longer fails with an
+// *errors.RequestTimeoutError. It does not apply to streams, which may
+// last as long as they keep receiving data. The default is
+// DefaultRequestTimeout; a negative timeout disables it. The timeout of
+// an *http.Client given with WithHTTPClient is used instead.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithRequestTimeout(60 * time.Second),
+//	)
+func WithRequestTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.Timeout = timeout
+	}
+}
+
+// WithStreamReadTimeout sets the maximum wait of a stream for its
+// response headers and for every read of its body. A stream that receives
+// nothing for this long fails with an *errors.StreamReadTimeoutError; the
+// timer restarts with every read, so healthy streams may run for any
+// length of time. It complements WithStreamIdleTimeout, which bounds the
+// wait for a whole event. The default is DefaultStreamReadTimeout; a
+// negative timeout disables it. It is ignored with WithHTTPClient.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithStreamReadTimeout(30 * time.Second),
+//	)
+func WithStreamReadTimeout(timeout time.Duration) ClientOption {
+	return func(c *ClientConfig) {
+		c.StreamReadTimeout = timeout
+	}
+}
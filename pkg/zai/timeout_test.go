@@ -0,0 +1,88 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	t.Parallel()
+
+	client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// The headers arrive, the body stalls
+		fmt.Fprint(w, `{"id":"chatcmpl-1",`)
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}, WithRequestTimeout(100*time.Millisecond))
+
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")})
+	_, err := client.Chat.Create(context.Background(), req)
+	assert.True(t, errors.IsRequestTimeoutError(err), "got %v", err)
+	assert.True(t, errors.IsTimeoutError(err))
+}
+
+func TestWithStreamReadTimeout(t *testing.T) {
+	t.Parallel()
+
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("hi")})
+
+	t.Run("a slow stream outlasts the request timeout", func(t *testing.T) {
+		t.Parallel()
+
+		client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			for range 4 {
+				fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n")
+				w.(http.Flusher).Flush()
+				time.Sleep(40 * time.Millisecond)
+			}
+			fmt.Fprint(w, "data: [DONE]\n\n")
+		}, WithRequestTimeout(50*time.Millisecond), WithStreamReadTimeout(time.Second))
+
+		content, err := client.Chat.StreamContent(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "aaaa", content)
+	})
+
+	t.Run("a stalled stream times out", func(t *testing.T) {
+		t.Parallel()
+
+		client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"a\"}}]}\n\n")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}, WithStreamReadTimeout(100*time.Millisecond))
+
+		_, err := client.Chat.StreamContent(context.Background(), req)
+		var timeoutErr *errors.StreamReadTimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, 100*time.Millisecond, timeoutErr.Timeout)
+	})
+
+	t.Run("the deadline of the context wins", func(t *testing.T) {
+		t.Parallel()
+
+		client := newLimitsTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}, WithStreamReadTimeout(time.Minute))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err := client.Chat.StreamContent(ctx, req)
+		assert.True(t, stderrors.Is(err, context.DeadlineExceeded), "got %v", err)
+		assert.False(t, errors.IsTimeoutError(err))
+	})
+}
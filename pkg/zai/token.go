@@ -0,0 +1,130 @@
+package zai
+
+import (
+	"context"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/auth"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// TokenSource returns a bearer token to authenticate requests with and
+// the time it expires, or the zero time if it does not expire. It must be
+// safe for concurrent use.
+type TokenSource func(ctx context.Context) (token string, expiry time.Time, err error)
+
+// WithToken sends token as the bearer token of every request, instead of
+// a token the SDK signs with an API key. Use it when tokens are issued by
+// a broker and the application must not hold the API key. The token is
+// sent as is and never refreshed; use WithTokenSource for tokens that
+// expire.
+//
+// WithToken cannot be combined with WithAPIKey, WithAPIKeyProvider or
+// WithTokenSource: NewClient returns a ConfigError. It replaces the API key
+// read from ZAI_API_KEY by NewClientFromEnv, and the credentials of the
+// parent client in Client.WithOptions.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithToken(os.Getenv("ZAI_TOKEN")),
+//	)
+func WithToken(token string) ClientOption {
+	return func(c *ClientConfig) {
+		c.Token = token
+	}
+}
+
+// WithTokenSource fetches bearer tokens from source instead of signing
+// them with an API key. A token is reused until shortly before it
+// expires, and concurrent requests that need a new token share one call
+// of source. If the API rejects a request with 401 Unauthorized, source
+// is called again and, if it returns a different token, the request is
+// sent once more with it. Source errors are returned to the caller.
+//
+// The call of source outlives the context of the request that started
+// it, so that the other requests waiting for the token are not failed by
+// its cancellation; source should bound its own duration.
+//
+// WithTokenSource cannot be combined with WithAPIKey, WithAPIKeyProvider
+// or WithToken: NewClient returns a ConfigError. Like WithToken, it
+// replaces the API key read by NewClientFromEnv and the credentials of the
+// parent client in Client.WithOptions.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithTokenSource(func(ctx context.Context) (string, time.Time, error) {
+//	        resp, err := broker.IssueToken(ctx, "zai")
+//	        if err != nil {
+//	            return "", time.Time{}, err
+//	        }
+//	        return resp.Token, resp.ExpiresAt, nil
+//	    }),
+//	)
+func WithTokenSource(source TokenSource) ClientOption {
+	return func(c *ClientConfig) {
+		c.TokenSource = source
+	}
+}
+
+// validateToken checks the token configuration of config, which has a
+// token or a token source.
+func validateToken(config *ClientConfig) error {
+	if config.Token != "" && config.TokenSource != nil {
+		return errors.NewConfigError("Token", "WithToken and WithTokenSource cannot be combined")
+	}
+	if config.APIKey != "" || config.APIKeyProvider != nil {
+		return errors.NewConfigError("Token", "a token cannot be combined with an API key")
+	}
+	return nil
+}
+
+// applied returns a configuration with only opts applied.
+func applied(opts []ClientOption) *ClientConfig {
+	config := &ClientConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// setsCredentials reports whether opts configure an API key, an API key
+// provider, a token or a token source.
+func setsCredentials(opts []ClientOption) bool {
+	config := applied(opts)
+	return config.APIKey != "" || config.APIKeyProvider != nil ||
+		config.Token != "" || config.TokenSource != nil
+}
+
+// clearCredentials removes the credentials of config.
+func clearCredentials(config *ClientConfig) {
+	config.APIKey = ""
+	config.APIKeyProvider = nil
+	config.Token = ""
+	config.TokenSource = nil
+}
+
+// envCredentials returns envOpts, followed by an option that drops the API
+// key read from the environment if opts authenticate with a token, which
+// replaces it rather than conflicting with it.
+func envCredentials(envOpts, opts []ClientOption) []ClientOption {
+	if config := applied(opts); config.Token == "" && config.TokenSource == nil {
+		return envOpts
+	}
+	return append(envOpts, func(c *ClientConfig) {
+		c.APIKey = ""
+	})
+}
+
+// tokenSource returns the token source of config, or nil if it
+// authenticates with an API key.
+func tokenSource(config *ClientConfig) auth.TokenSource {
+	switch {
+	case config.TokenSource != nil:
+		return auth.TokenSource(config.TokenSource)
+	case config.Token != "":
+		return auth.StaticToken(config.Token)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package zai
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// tokenBroker is a fake token source issuing the tokens "token-1",
+// "token-2", ... that expire after ttl, and telling the server of
+// newRotatingServer to accept only the latest.
+type tokenBroker struct {
+	ttl      time.Duration
+	delay    time.Duration
+	accepted *atomic.Value
+	issued   atomic.Int32
+}
+
+func (b *tokenBroker) source(ctx context.Context) (string, time.Time, error) {
+	time.Sleep(b.delay)
+	token := fmt.Sprintf("token-%d", b.issued.Add(1))
+	b.accepted.Store(token)
+	return token, time.Now().Add(b.ttl), nil
+}
+
+func TestWithToken(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("broker-issued-token")
+	server, unauthorized := newRotatingServer(t, &accepted)
+
+	client, err := NewClient(WithToken("broker-issued-token"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err, "the token is sent as is")
+
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+
+	// A static token cannot be refreshed, so it is not retried
+	accepted.Store("other-token")
+	_, err = client.Models.List(context.Background())
+	assert.True(t, errors.IsAuthenticationError(err), "got %v", err)
+	assert.Equal(t, int32(1), unauthorized.Load())
+
+	assert.NotContains(t, client.GetConfig().String(), "broker-issued-token")
+}
+
+func TestWithTokenSource_Expiry(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("")
+	server, unauthorized := newRotatingServer(t, &accepted)
+	broker := &tokenBroker{ttl: 200 * time.Millisecond, accepted: &accepted}
+
+	client, err := NewClient(WithTokenSource(broker.source), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		_, err := client.Models.List(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), broker.issued.Load(), "the token is cached")
+
+	// The token is replaced before it expires
+	time.Sleep(120 * time.Millisecond)
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), broker.issued.Load())
+	assert.Zero(t, unauthorized.Load(), "no request is sent with an expiring token")
+}
+
+func TestWithTokenSource_ConcurrentBurst(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("")
+	server, unauthorized := newRotatingServer(t, &accepted)
+	broker := &tokenBroker{ttl: time.Hour, delay: 50 * time.Millisecond, accepted: &accepted}
+
+	client, err := NewClient(WithTokenSource(broker.source), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Models.List(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), broker.issued.Load(), "concurrent requests share one fetch")
+	assert.Zero(t, unauthorized.Load())
+}
+
+func TestWithTokenSource_RevokedToken(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("")
+	server, unauthorized := newRotatingServer(t, &accepted)
+	broker := &tokenBroker{ttl: time.Hour, accepted: &accepted}
+
+	client, err := NewClient(WithTokenSource(broker.source), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err)
+
+	// The broker revokes the cached token long before its expiry
+	accepted.Store("revoked")
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Models.List(context.Background())
+			assert.NoError(t, err, "the request is retried with a refreshed token")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), broker.issued.Load(), "the rejected token is refreshed once")
+	assert.Equal(t, int32(10), unauthorized.Load())
+
+	// Streams are retried too
+	accepted.Store("revoked")
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+	assert.Equal(t, int32(3), broker.issued.Load())
+}
+
+func TestWithTokenSource_Errors(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("")
+	server, _ := newRotatingServer(t, &accepted)
+
+	errBroker := stderrors.New("broker unavailable")
+	client, err := NewClient(
+		WithTokenSource(func(ctx context.Context) (string, time.Time, error) {
+			return "", time.Time{}, errBroker
+		}),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	assert.ErrorIs(t, err, errBroker)
+
+	_, err = client.Chat.StreamContent(context.Background(), newChatRequest())
+	assert.ErrorIs(t, err, errBroker)
+}
+
+func TestWithToken_FromEnv(t *testing.T) {
+	t.Setenv(EnvAPIKey, "env-key.env-secret")
+
+	var accepted atomic.Value
+	accepted.Store("broker-issued-token")
+	server, unauthorized := newRotatingServer(t, &accepted)
+
+	client, err := NewClientFromEnv(WithToken("broker-issued-token"), WithBaseURL(server.URL))
+	require.NoError(t, err, "the token replaces the API key of the environment")
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, unauthorized.Load())
+
+	// An explicit API key still conflicts with the token
+	_, err = NewClientFromEnv(WithAPIKey("key.secret"), WithToken("broker-issued-token"))
+	var configErr *errors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+	assert.Equal(t, "Token", configErr.Field)
+}
+
+func TestWithToken_WithOptions(t *testing.T) {
+	t.Parallel()
+
+	var accepted atomic.Value
+	accepted.Store("broker-issued-token")
+	server, _ := newRotatingServer(t, &accepted)
+
+	parent, err := NewClient(WithAPIKey("key.secret"), WithBaseURL(server.URL))
+	require.NoError(t, err)
+	defer parent.Close()
+
+	derived, err := parent.WithOptions(WithToken("broker-issued-token"))
+	require.NoError(t, err, "the token replaces the credentials of the parent")
+	defer derived.Close()
+
+	_, err = derived.Models.List(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, derived.GetConfig().APIKey)
+
+	_, err = parent.Models.List(context.Background())
+	assert.True(t, errors.IsAuthenticationError(err), "the parent keeps its API key")
+
+	// Credentials combined within the options still conflict
+	_, err = parent.WithOptions(WithAPIKey("other.secret"), WithToken("broker-issued-token"))
+	var configErr *errors.ConfigError
+	require.ErrorAs(t, err, &configErr)
+}
+
+func TestTokenOptions_ConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	source := func(ctx context.Context) (string, time.Time, error) {
+		return "token", time.Time{}, nil
+	}
+	tests := map[string][]ClientOption{
+		"key and token":          {WithAPIKey("key.secret"), WithToken("token")},
+		"token and key":          {WithToken("token"), WithAPIKey("key.secret")},
+		"key and token source":   {WithAPIKey("key.secret"), WithTokenSource(source)},
+		"provider and token":     {WithAPIKeyProvider(EnvAPIKeyProvider("ZAI_TEST_UNSET_KEY")), WithToken("token")},
+		"token and token source": {WithToken("token"), WithTokenSource(source)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			client, err := NewClient(opts...)
+			assert.Nil(t, client)
+			var configErr *errors.ConfigError
+			require.ErrorAs(t, err, &configErr)
+			assert.Equal(t, "Token", configErr.Field)
+		})
+	}
+}
@@ -5,18 +5,21 @@ import (
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 	streaming "github.com/sofianhadi1983/zai-sdk-go/internal/streaming"
 )
 
 // ToolsService provides access to the Tools API.
 type ToolsService struct {
-	client *client.BaseClient
+	client      *client.BaseClient
+	calibration *tokenCalibration
 }
 
 // newToolsService creates a new tools service.
 func newToolsService(baseClient *client.BaseClient) *ToolsService {
 	return &ToolsService{
-		client: baseClient,
+		client:      baseClient,
+		calibration: newTokenCalibration(),
 	}
 }
 
@@ -55,9 +58,12 @@ func newToolsService(baseClient *client.BaseClient) *ToolsService {
 func (s *ToolsService) WebSearch(ctx context.Context, req *tools.WebSearchRequest) (*tools.WebSearchResponse, error) {
 	// Ensure streaming is disabled for non-streaming request
 	req.Stream = false
+	if err := s.client.Sanitize(ServiceTools, req); err != nil {
+		return nil, err
+	}
 
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/tools", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.Tools), req)
 	if err != nil {
 		return nil, err
 	}
@@ -72,6 +78,8 @@ func (s *ToolsService) WebSearch(ctx context.Context, req *tools.WebSearchReques
 }
 
 // WebSearchStream performs streaming web search using AI models.
+// tools.NewWebSearchStreamAggregator assembles the chunks into the response
+// of WebSearch.
 //
 // Example (streaming):
 //
@@ -111,9 +119,12 @@ func (s *ToolsService) WebSearch(ctx context.Context, req *tools.WebSearchReques
 func (s *ToolsService) WebSearchStream(ctx context.Context, req *tools.WebSearchRequest) (*streaming.Stream[tools.WebSearchChunk], error) {
 	// Ensure streaming is enabled
 	req.Stream = true
+	if err := s.client.Sanitize(ServiceTools, req); err != nil {
+		return nil, err
+	}
 
 	// Make the streaming request
-	streamResp, err := s.client.Stream(ctx, "/tools", req)
+	streamResp, err := s.client.Stream(ctx, s.client.Endpoints().Path(endpoints.Tools), req)
 	if err != nil {
 		return nil, err
 	}
@@ -144,8 +155,12 @@ func (s *ToolsService) WebSearchStream(ctx context.Context, req *tools.WebSearch
 //	fmt.Printf("Prompt tokens: %d\n", resp.Usage.PromptTokens)
 //	fmt.Printf("Total tokens: %d\n", resp.Usage.TotalTokens)
 func (s *ToolsService) Tokenizer(ctx context.Context, req *tools.TokenizerRequest) (*tools.TokenizerResponse, error) {
+	if err := s.client.Sanitize(ServiceTools, req); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/tokenizer", req)
+	apiResp, err := s.client.PostCached(ctx, s.client.Endpoints().Path(endpoints.Tokenizer), req)
 	if err != nil {
 		return nil, err
 	}
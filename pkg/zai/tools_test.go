@@ -6,10 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,7 +42,7 @@ func TestToolsService_WebSearch(t *testing.T) {
 			Choices: []tools.WebSearchChoice{
 				{
 					Index:        0,
-					FinishReason: "stop",
+					FinishReason: tools.FinishReasonStop,
 					Message: tools.WebSearchMessage{
 						Role: "assistant",
 						ToolCalls: []tools.WebSearchMessageToolCall{
@@ -113,7 +115,7 @@ func TestToolsService_WebSearch(t *testing.T) {
 	// Verify choices
 	choices := resp.GetChoices()
 	assert.Len(t, choices, 1)
-	assert.Equal(t, "stop", choices[0].FinishReason)
+	assert.Equal(t, tools.FinishReasonStop, choices[0].FinishReason)
 
 	// Verify tool calls
 	toolCalls := resp.GetToolCalls()
@@ -297,7 +299,7 @@ func TestToolsService_WebSearchStream(t *testing.T) {
 				Choices: []tools.WebSearchStreamChoice{
 					{
 						Index:        0,
-						FinishReason: "stop",
+						FinishReason: tools.FinishReasonStop,
 						Delta: tools.ChoiceDelta{
 							ToolCalls: []tools.ChoiceDeltaToolCall{
 								{
@@ -380,7 +382,7 @@ func TestToolsService_WebSearchStream(t *testing.T) {
 
 	// Verify third chunk (search recommendation and finish)
 	assert.Equal(t, "ws_chunk_3", chunks[2].ID)
-	assert.Equal(t, "stop", chunks[2].Choices[0].FinishReason)
+	assert.Equal(t, tools.FinishReasonStop, chunks[2].Choices[0].FinishReason)
 	assert.NotNil(t, chunks[2].Choices[0].Delta.ToolCalls[0].SearchRecommend)
 	assert.Equal(t, "try this query", chunks[2].Choices[0].Delta.ToolCalls[0].SearchRecommend.Query)
 }
@@ -412,3 +414,125 @@ func TestToolsService_WebSearch_APIError(t *testing.T) {
 	_, err = client.Tools.WebSearch(context.Background(), req)
 	require.Error(t, err)
 }
+
+func TestToolsService_CountTokens_Truncate(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tokenizer", r.URL.Path)
+		calls++
+
+		var req tools.TokenizerRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "glm-4.7", req.Model)
+
+		// 10 tokens per message
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"id":    "tok-1",
+			"usage": map[string]interface{}{"prompt_tokens": 10 * len(req.Messages), "total_tokens": 10 * len(req.Messages)},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	messages := []chat.Message{
+		chat.NewSystemMessage("You are helpful."),
+		chat.NewUserMessage("first"),
+		chat.NewAssistantMessage("reply"),
+		chat.NewUserMessage("second"),
+	}
+
+	n, err := client.Tools.CountTokens(context.Background(), tools.NewTokenizerRequest("glm-4.7", messages), ModeExact)
+	require.NoError(t, err)
+	assert.Equal(t, 40, n)
+
+	kept, tokens, err := chat.TruncateMessages(context.Background(), client.Tools.Counter(ModeExact), "glm-4.7", messages, 25, chat.TruncateOldest)
+	require.NoError(t, err)
+	assert.Equal(t, 20, tokens)
+	assert.Equal(t, []chat.Message{messages[0], messages[3]}, kept)
+}
+
+func TestToolsService_CountTokens_Modes(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var req tools.TokenizerRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		// The tokenizer counts twice the estimate, plus an image
+		prompt := 2*tools.EstimateRequestTokens(&req) + 100
+		writeJSON(w, map[string]any{
+			"id":    "tok-1",
+			"usage": map[string]any{"prompt_tokens": prompt, "image_tokens": 100, "total_tokens": prompt},
+		})
+	})
+	ctx := context.Background()
+
+	req := tools.NewTokenizerRequest("glm-4.7", []chat.Message{chat.NewUserMessage("How many tokens is this sentence?")})
+	estimate := tools.EstimateRequestTokens(req)
+
+	n, err := client.Tools.CountTokens(ctx, req, ModeEstimate)
+	require.NoError(t, err)
+	assert.Equal(t, estimate, n)
+	assert.Zero(t, calls.Load(), "estimates are offline")
+
+	n, err = client.Tools.CountTokens(ctx, req, ModeExact)
+	require.NoError(t, err)
+	assert.Equal(t, 2*estimate+100, n)
+	assert.EqualValues(t, 1, calls.Load())
+
+	// The first request of a model is calibrated, and the next ones are
+	// estimated with the calibrated scale
+	n, err = client.Tools.CountTokens(ctx, req, ModeAuto)
+	require.NoError(t, err)
+	assert.Equal(t, 2*estimate+100, n)
+	for range CalibrationInterval {
+		n, err = client.Tools.CountTokens(ctx, req, ModeAuto)
+		require.NoError(t, err)
+		assert.Equal(t, 2*estimate, n)
+	}
+	assert.EqualValues(t, 2, calls.Load())
+
+	_, err = client.Tools.CountTokens(ctx, req, ModeAuto)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, calls.Load(), "calibrated again after CalibrationInterval requests")
+
+	// Other models are calibrated separately
+	_, err = client.Tools.CountTokens(ctx, tools.NewTokenizerRequest("glm-4-plus", req.Messages), ModeAuto)
+	require.NoError(t, err)
+	assert.EqualValues(t, 4, calls.Load())
+
+	_, err = client.Tools.CountTokens(ctx, req, TokenCountMode(7))
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+}
+
+func TestToolsService_CountTokens_AutoFallback(t *testing.T) {
+	t.Parallel()
+
+	client := newRawTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]any{"error": map[string]any{"code": "1210", "message": "invalid model"}})
+	})
+
+	req := tools.NewTokenizerRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hello")})
+	n, err := client.Tools.CountTokens(context.Background(), req, ModeAuto)
+	require.NoError(t, err, "a failed calibration falls back to the estimate")
+	assert.Equal(t, tools.EstimateRequestTokens(req), n)
+
+	_, err = client.Tools.CountTokens(context.Background(), req, ModeExact)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Tools.CountTokens(ctx, tools.NewTokenizerRequest("glm-4.6", req.Messages), ModeAuto)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,195 @@
+package zai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/tools"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// TokenCountMode selects how ToolsService.CountTokens counts tokens.
+type TokenCountMode int
+
+const (
+	// ModeExact counts tokens with the tokenizer endpoint.
+	ModeExact TokenCountMode = iota
+
+	// ModeEstimate estimates tokens offline with tools.EstimateRequestTokens,
+	// which is usually within 10% of the exact count.
+	ModeEstimate
+
+	// ModeAuto estimates tokens offline, and calibrates the estimate of
+	// each model with the tokenizer endpoint on the first request and
+	// every CalibrationInterval requests after that.
+	ModeAuto
+)
+
+// CalibrationInterval is the number of requests of a model that ModeAuto
+// estimates between two calls to the tokenizer endpoint.
+const CalibrationInterval = 50
+
+// String returns the name of the mode.
+func (m TokenCountMode) String() string {
+	switch m {
+	case ModeExact:
+		return "exact"
+	case ModeEstimate:
+		return "estimate"
+	case ModeAuto:
+		return "auto"
+	default:
+		return fmt.Sprintf("TokenCountMode(%d)", int(m))
+	}
+}
+
+// CountTokens returns the number of prompt tokens of req, counted as mode
+// selects. In ModeAuto, a failed calibration is not an error: the request
+// is estimated and calibration is retried after CalibrationInterval
+// requests, unless ctx is done or the client is closed.
+//
+// Example:
+//
+//	req := tools.NewTokenizerRequest("glm-4.7", messages).SetTools(toolDefs)
+//	n, err := client.Tools.CountTokens(ctx, req, zai.ModeAuto)
+func (s *ToolsService) CountTokens(ctx context.Context, req *tools.TokenizerRequest, mode TokenCountMode) (int, error) {
+	switch mode {
+	case ModeExact:
+		resp, err := s.Tokenizer(ctx, req)
+		if err != nil {
+			return 0, err
+		}
+		return resp.Usage.PromptTokens, nil
+	case ModeEstimate:
+		return tools.EstimateRequestTokens(req), nil
+	case ModeAuto:
+		return s.countAuto(ctx, req)
+	default:
+		return 0, errors.NewValidationError("mode", "is not a token count mode", mode)
+	}
+}
+
+// Counter returns a chat.TokenCounter that counts tokens as mode selects,
+// for chat.TruncateMessages and chat.NewTokenWindowMemory.
+//
+// Example:
+//
+//	kept, tokens, err := chat.TruncateMessages(ctx, client.Tools.Counter(zai.ModeAuto), "glm-4.7",
+//	    history, 8000, chat.TruncateOldest)
+func (s *ToolsService) Counter(mode TokenCountMode) chat.TokenCounter {
+	return tokenCounter{tools: s, mode: mode}
+}
+
+// tokenCounter is the chat.TokenCounter of ToolsService.Counter.
+type tokenCounter struct {
+	tools *ToolsService
+	mode  TokenCountMode
+}
+
+// CountTokens implements chat.TokenCounter.
+func (c tokenCounter) CountTokens(ctx context.Context, model string, messages []chat.Message) (int, error) {
+	return c.tools.CountTokens(ctx, tools.NewTokenizerRequest(model, messages), c.mode)
+}
+
+// countAuto estimates the tokens of req, calibrating the estimate when it
+// is due.
+func (s *ToolsService) countAuto(ctx context.Context, req *tools.TokenizerRequest) (int, error) {
+	estimate := tools.EstimateRequestTokens(req)
+	if !s.calibration.due(req.Model) {
+		return s.calibration.apply(req.Model, estimate), nil
+	}
+
+	resp, err := s.Tokenizer(ctx, req)
+	if err != nil {
+		if ctx.Err() != nil || errors.IsClientClosedError(err) {
+			return 0, err
+		}
+		return s.calibration.apply(req.Model, estimate), nil
+	}
+
+	// Images and videos are not estimated
+	usage := resp.Usage
+	s.calibration.update(req.Model, estimate, usage.PromptTokens-usage.ImageTokens-usage.VideoTokens)
+	return usage.PromptTokens, nil
+}
+
+// tokenCalibration holds the correction of the token estimate of each
+// model for ModeAuto.
+type tokenCalibration struct {
+	mu     sync.Mutex
+	models map[string]*modelCalibration
+}
+
+// modelCalibration is the calibration of a model.
+type modelCalibration struct {
+	// scale is the ratio of the exact count to the estimate, or 0 before
+	// the first calibration
+	scale float64
+
+	// requests is the number of requests since the last calibration
+	requests int
+}
+
+func newTokenCalibration() *tokenCalibration {
+	return &tokenCalibration{models: make(map[string]*modelCalibration)}
+}
+
+// model returns the calibration of model. The caller holds c.mu.
+func (c *tokenCalibration) model(model string) *modelCalibration {
+	m, ok := c.models[model]
+	if !ok {
+		m = &modelCalibration{requests: CalibrationInterval}
+		c.models[model] = m
+	}
+	return m
+}
+
+// due reports whether the request of model should be calibrated, and
+// counts it otherwise.
+func (c *tokenCalibration) due(model string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := c.model(model)
+	if m.requests >= CalibrationInterval {
+		m.requests = 0
+		return true
+	}
+	m.requests++
+	return false
+}
+
+// update adjusts the scale of model from the estimate and the exact count
+// of a request. The scale follows an exponential moving average, so a
+// single unusual request does not skew it.
+func (c *tokenCalibration) update(model string, estimate, exact int) {
+	if estimate <= 0 || exact <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := c.model(model)
+	ratio := float64(exact) / float64(estimate)
+	if m.scale == 0 {
+		m.scale = ratio
+	} else {
+		m.scale = (m.scale + ratio) / 2
+	}
+}
+
+// apply returns estimate corrected by the scale of model.
+func (c *tokenCalibration) apply(model string, estimate int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m := c.model(model)
+	if m.scale == 0 {
+		return estimate
+	}
+	return int(math.Round(float64(estimate) * m.scale))
+}
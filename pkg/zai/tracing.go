@@ -0,0 +1,38 @@
+package zai
+
+import (
+	"github.com/sofianhadi1983/zai-sdk-go/internal/telemetry"
+)
+
+// Tracer starts spans for API calls and their HTTP attempts, for example to
+// export them with OpenTelemetry (see the otelzai package). Implementations
+// must be safe for concurrent use.
+type Tracer = telemetry.Tracer
+
+// TracedCall describes an API call passed to Tracer.StartCall.
+type TracedCall = telemetry.Call
+
+// CallSpan is the span of an API call. It covers every attempt of the
+// call and, for streams, ends when the stream is closed.
+type CallSpan = telemetry.CallSpan
+
+// AttemptSpan is the span of a single HTTP attempt.
+type AttemptSpan = telemetry.AttemptSpan
+
+// TokenUsage is the token usage reported by the API for a call.
+type TokenUsage = telemetry.Usage
+
+// WithTracer traces every API call with the given tracer. Without a tracer
+// the SDK does no tracing work at all.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithTracer(otelzai.NewTracer()),
+//	)
+func WithTracer(t Tracer) ClientOption {
+	return func(c *ClientConfig) {
+		c.Tracer = t
+	}
+}
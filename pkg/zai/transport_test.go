@@ -0,0 +1,272 @@
+package zai
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// recordingTransport records the requests it sends and counts
+// CloseIdleConnections calls.
+type recordingTransport struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	requests []*http.Request
+	closed   int
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.requests = append(t.requests, req)
+	t.mu.Unlock()
+	return t.next.RoundTrip(req)
+}
+
+func (t *recordingTransport) CloseIdleConnections() {
+	t.mu.Lock()
+	t.closed++
+	t.mu.Unlock()
+}
+
+func (t *recordingTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+// retryingTransport makes up to two attempts for 503 responses itself.
+type retryingTransport struct {
+	recordingTransport
+}
+
+func (t *retryingTransport) HandlesRetries() bool {
+	return true
+}
+
+func (t *retryingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.recordingTransport.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+		return resp, err
+	}
+	resp.Body.Close()
+	return t.recordingTransport.RoundTrip(req)
+}
+
+// newFlakyServer fails the first failures requests with 503 and then
+// serves chat completions and streams.
+func newFlakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"stream":true`) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"Hi\"}}]}\n\n"))
+			w.Write([]byte("data: [DONE]\n\n"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"1","choices":[{"index":0,"message":{"role":"assistant","content":"Hi"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func newChatRequest() *chat.ChatCompletionRequest {
+	return chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Hello")})
+}
+
+func TestWithTransport(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newFlakyServer(t, 0)
+	rt := &recordingTransport{next: http.DefaultTransport}
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithDisableTokenCache(),
+		WithTransport(rt),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Chat.Create(context.Background(), newChatRequest())
+	require.NoError(t, err)
+
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+
+	require.Equal(t, 2, rt.count())
+	for _, req := range rt.requests {
+		assert.Equal(t, "Bearer test-key.test-secret", req.Header.Get("Authorization"), "auth is applied before the transport")
+	}
+
+	client.Close()
+	assert.Zero(t, rt.closed, "Close must not close a user transport")
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newFlakyServer(t, 0)
+	rt := &recordingTransport{next: http.DefaultTransport}
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithHTTPClient(&http.Client{Transport: rt}),
+		WithTransport(&recordingTransport{next: http.DefaultTransport}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.Chat.Create(context.Background(), newChatRequest())
+	require.NoError(t, err)
+
+	content, err := client.Chat.StreamContent(context.Background(), newChatRequest())
+	require.NoError(t, err)
+	assert.Equal(t, "Hi", content)
+
+	assert.Equal(t, 2, rt.count(), "WithHTTPClient takes precedence over WithTransport")
+
+	client.Close()
+	assert.Zero(t, rt.closed, "Close must not close a user client")
+}
+
+func TestTransportLayering(t *testing.T) {
+	t.Parallel()
+
+	t.Run("SDK retries on top of transport", func(t *testing.T) {
+		t.Parallel()
+
+		server, calls := newFlakyServer(t, 2)
+		rt := &recordingTransport{next: http.DefaultTransport}
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+			WithTransport(rt),
+			WithDefaultRetryPolicy(RetryPolicy{InitialBackoff: 1, MaxBackoff: 1}),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		resp, err := client.Models.List(context.Background())
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+
+		// Every attempt goes through the transport
+		assert.Equal(t, 3, rt.count())
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("retrying transport is not wrapped", func(t *testing.T) {
+		t.Parallel()
+
+		server, calls := newFlakyServer(t, 100)
+		rt := &retryingTransport{recordingTransport{next: http.DefaultTransport}}
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+			WithHTTPClient(&http.Client{Transport: rt}),
+			WithDefaultRetryPolicy(RetryPolicy{InitialBackoff: 1, MaxBackoff: 1}),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Models.List(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(2), calls.Load(), "only the transport retries")
+	})
+
+	t.Run("explicit policy retries a retrying transport", func(t *testing.T) {
+		t.Parallel()
+
+		server, calls := newFlakyServer(t, 100)
+		rt := &retryingTransport{recordingTransport{next: http.DefaultTransport}}
+
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithBaseURL(server.URL),
+			WithTransport(rt),
+			WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 2, InitialBackoff: 1, MaxBackoff: 1}),
+		)
+		require.NoError(t, err)
+		defer client.Close()
+
+		_, err = client.Models.List(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, int32(4), calls.Load(), "two SDK attempts of two transport attempts")
+	})
+}
+
+func TestWithProxy(t *testing.T) {
+	t.Parallel()
+
+	var proxied atomic.Int32
+	var proxyAuth atomic.Value
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied.Add(1)
+		proxyAuth.Store(r.Header.Get("Proxy-Authorization"))
+		assert.Equal(t, "api.zai.invalid", r.URL.Host, "proxy receives the absolute target URL")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer proxy.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL("http://api.zai.invalid/api/paas/v4"),
+		WithProxy("http://user:secret@"+proxy.Listener.Addr().String()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.Models.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), proxied.Load())
+	assert.Equal(t, "Basic "+base64.StdEncoding.EncodeToString([]byte("user:secret")), proxyAuth.Load())
+
+	t.Run("derived client with another proxy", func(t *testing.T) {
+		derived, err := client.WithOptions(WithProxy("http://other-proxy.invalid:3128"))
+		require.NoError(t, err)
+		defer derived.Close()
+
+		assert.NotSame(t, client.baseClient.Transport(), derived.baseClient.Transport())
+	})
+}
+
+func TestWithProxy_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, proxyURL := range []string{"proxy.internal:3128", "://bad", "http://"} {
+		client, err := NewClient(
+			WithAPIKey("test-key.test-secret"),
+			WithProxy(proxyURL),
+		)
+		assert.Nil(t, client)
+
+		var configErr *errors.ConfigError
+		assert.ErrorAs(t, err, &configErr, proxyURL)
+	}
+}
@@ -0,0 +1,98 @@
+package zai
+
+import (
+	"io"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+)
+
+// DefaultUploadBufferSize is the largest uploaded file content that is
+// buffered for retries unless WithUploadBufferSize is given.
+const DefaultUploadBufferSize = 8 << 20
+
+// WithUploadBufferSize sets how much of an uploaded file is buffered in
+// memory so that the upload can be retried.
+//
+// Uploads are retried by reading the file again: readers that implement
+// io.Seeker, such as *os.File and *bytes.Reader, are rewound, and readers
+// created with NewReplayableReader are reopened. Other readers are
+// buffered if they hold at most n bytes. A larger file is sent once, with
+// retries disabled for the request and a warning passed to the hooks.
+// Zero or less buffers nothing. The default is DefaultUploadBufferSize.
+//
+// Example:
+//
+//	client, err := zai.NewClient(
+//	    zai.WithAPIKey("your-key"),
+//	    zai.WithUploadBufferSize(32 << 20), // 32 MiB
+//	)
+func WithUploadBufferSize(n int64) ClientOption {
+	return func(c *ClientConfig) {
+		c.UploadBufferSize = noLimitIfZero(n)
+	}
+}
+
+// ReaderFactory opens the content of an upload from the start. It is
+// called once per attempt.
+type ReaderFactory func() (io.ReadCloser, error)
+
+// NewReplayableReader returns a reader of the content opened by open that
+// uploads can read again on retries, without buffering it. Use it for
+// large content that cannot seek, such as an object storage download.
+//
+// Example:
+//
+//	file := zai.NewReplayableReader(func() (io.ReadCloser, error) {
+//	    return bucket.NewReader(ctx, "audio/meeting.wav")
+//	})
+//
+//	req := audio.NewTranscriptionRequest(file, "meeting.wav", audio.ModelWhisper1)
+//	resp, err := client.Audio.Transcribe(ctx, req)
+func NewReplayableReader(open ReaderFactory) io.ReadCloser {
+	return &replayableReader{open: open}
+}
+
+// replayableReader reads the content of a ReaderFactory. It implements
+// client.Reopener, so uploads reopen it for every attempt.
+type replayableReader struct {
+	open ReaderFactory
+
+	mu      sync.Mutex
+	current io.ReadCloser
+}
+
+var _ client.Reopener = (*replayableReader)(nil)
+
+// Read implements io.Reader, opening the content on the first read.
+func (r *replayableReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current == nil {
+		current, err := r.open()
+		if err != nil {
+			return 0, err
+		}
+		r.current = current
+	}
+	return r.current.Read(p)
+}
+
+// Close implements io.Closer, closing the content opened by Read.
+func (r *replayableReader) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+// Reopen implements client.Reopener.
+func (r *replayableReader) Reopen() (io.ReadCloser, error) {
+	return r.open()
+}
@@ -0,0 +1,207 @@
+package zai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/files"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+)
+
+// uploadServer is a fake Files API that fails the first failures uploads
+// with 503 and records the file content of every attempt.
+type uploadServer struct {
+	failures int
+
+	mu       sync.Mutex
+	attempts int
+	contents []string
+}
+
+func (u *uploadServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.attempts++
+	content := "<missing>"
+	if file, _, err := r.FormFile("file"); err == nil {
+		data, _ := io.ReadAll(file)
+		content = string(data)
+	}
+	u.contents = append(u.contents, content)
+
+	if u.attempts <= u.failures {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"id":"file-1","object":"file","filename":"data.jsonl","purpose":"batch"}`))
+}
+
+// warningHooks records warnings.
+type warningHooks struct {
+	NoopHooks
+
+	mu       sync.Mutex
+	warnings []*WarningEvent
+}
+
+func (h *warningHooks) OnWarning(_ context.Context, e *WarningEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.warnings = append(h.warnings, e)
+}
+
+// onlyReader hides every method of r except Read.
+type onlyReader struct {
+	r io.Reader
+}
+
+func (o onlyReader) Read(p []byte) (int, error) {
+	return o.r.Read(p)
+}
+
+// batchInput returns a batch input file of n chat completion requests.
+func batchInput(n int) string {
+	var b strings.Builder
+	for i := range n {
+		fmt.Fprintf(&b, `{"custom_id":"req-%d","method":"POST","url":"/v4/chat/completions","body":{"model":"glm-4.7"}}`+"\n", i)
+	}
+	return b.String()
+}
+
+// idempotentUpload returns a context that marks uploads as safe to retry.
+func idempotentUpload() context.Context {
+	return WithHeaders(context.Background(), http.Header{"Idempotency-Key": {"upload-1"}})
+}
+
+func newUploadTestClient(t *testing.T, server *uploadServer, opts ...ClientOption) *Client {
+	return newLimitsTestClient(t, server.ServeHTTP, append([]ClientOption{
+		WithDefaultRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}),
+	}, opts...)...)
+}
+
+func TestUpload_RetryReplaysFile(t *testing.T) {
+	t.Parallel()
+
+	content := batchInput(100)
+
+	tests := []struct {
+		name string
+		file func() io.Reader
+	}{
+		{"seeker", func() io.Reader {
+			r := strings.NewReader("header;" + content)
+			r.Seek(int64(len("header;")), io.SeekStart)
+			return r
+		}},
+		{"buffered", func() io.Reader { return onlyReader{strings.NewReader(content)} }},
+		{"factory", func() io.Reader {
+			return NewReplayableReader(func() (io.ReadCloser, error) {
+				return io.NopCloser(strings.NewReader(content)), nil
+			})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := &uploadServer{failures: 1}
+			hooks := &warningHooks{}
+			// The buffer holds exactly the file, so the plain reader is
+			// buffered
+			client := newUploadTestClient(t, server, WithHooks(hooks), WithUploadBufferSize(int64(len(content))))
+
+			file, err := client.Files.Upload(idempotentUpload(), files.NewFileUploadRequest(tt.file(), "data.jsonl", files.PurposeBatch))
+			require.NoError(t, err)
+			assert.Equal(t, "file-1", file.ID)
+
+			assert.Equal(t, 2, server.attempts)
+			assert.Equal(t, []string{content, content}, server.contents, "the full file is sent on every attempt")
+			assert.Empty(t, hooks.warnings)
+		})
+	}
+}
+
+func TestUpload_TokenRateLimitDoesNotReadFile(t *testing.T) {
+	t.Parallel()
+
+	content := batchInput(100)
+	upload := func(opts ...ClientOption) int32 {
+		server := &uploadServer{}
+		client := newUploadTestClient(t, server, opts...)
+
+		var opens atomic.Int32
+		file := NewReplayableReader(func() (io.ReadCloser, error) {
+			opens.Add(1)
+			return io.NopCloser(strings.NewReader(content)), nil
+		})
+		_, err := client.Files.Upload(context.Background(), files.NewFileUploadRequest(file, "data.jsonl", files.PurposeBatch))
+		require.NoError(t, err)
+		assert.Equal(t, []string{content}, server.contents)
+		return opens.Load()
+	}
+
+	assert.Equal(t, upload(), upload(WithRateLimit(0, 100000)),
+		"the limiter does not read the upload to estimate tokens")
+}
+
+func TestUpload_LargeStreamIsNotRetried(t *testing.T) {
+	t.Parallel()
+
+	content := batchInput(100)
+	server := &uploadServer{failures: 1}
+	hooks := &warningHooks{}
+	client := newUploadTestClient(t, server, WithHooks(hooks), WithUploadBufferSize(1024))
+
+	_, err := client.Files.Upload(idempotentUpload(), files.NewFileUploadRequest(onlyReader{strings.NewReader(content)}, "data.jsonl", files.PurposeBatch))
+	require.Error(t, err)
+
+	// The file is sent in full, once
+	assert.Equal(t, 1, server.attempts)
+	assert.Equal(t, []string{content}, server.contents)
+
+	require.Len(t, hooks.warnings, 1)
+	assert.Equal(t, http.MethodPost, hooks.warnings[0].Method)
+	assert.Contains(t, hooks.warnings[0].Message, "upload cannot be retried")
+}
+
+func TestUpload_FileTooLarge(t *testing.T) {
+	t.Parallel()
+
+	server := &uploadServer{}
+	client := newUploadTestClient(t, server, WithUploadBufferSize(4))
+
+	req := fileparser.NewSyncRequest(onlyReader{bytes.NewReader(make([]byte, 100))}, "doc.pdf", "pdf")
+	req.MaxFileSize = 50
+
+	// The stream is too large to buffer, so the limit is enforced while
+	// sending
+	_, err := client.FileParser.CreateSync(context.Background(), req)
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+	assert.Contains(t, err.Error(), "50")
+}
+
+func TestWithUploadBufferSize(t *testing.T) {
+	t.Parallel()
+
+	config := &ClientConfig{}
+	WithUploadBufferSize(0)(config)
+	assert.Equal(t, int64(0), payloadLimit(config.UploadBufferSize, DefaultUploadBufferSize))
+
+	client := newUploadTestClient(t, &uploadServer{})
+	assert.Equal(t, int64(DefaultUploadBufferSize), client.baseClient.GetConfig().UploadBufferSize)
+}
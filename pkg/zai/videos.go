@@ -2,26 +2,39 @@ package zai
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/poll"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // VideosService provides access to the Videos API.
 type VideosService struct {
-	client *client.BaseClient
+	client       *client.BaseClient
+	defaultModel videos.VideoModel
 }
 
 // newVideosService creates a new videos service.
-func newVideosService(baseClient *client.BaseClient) *VideosService {
+func newVideosService(baseClient *client.BaseClient, defaultModel string) *VideosService {
 	return &VideosService{
-		client: baseClient,
+		client:       baseClient,
+		defaultModel: videos.VideoModel(defaultModel),
 	}
 }
 
-// Create submits a video generation task.
+// Create submits a video generation task. A request without a model is
+// sent with the client's default video model, see WithDefaultVideoModel.
 //
 // Example for text-to-video:
 //
@@ -40,9 +53,37 @@ func newVideosService(baseClient *client.BaseClient) *VideosService {
 //	if err != nil {
 //	    // Handle error
 //	}
+//
+// Generation parameters are validated against the model's limits before
+// the request is sent:
+//
+//	req := videos.NewTextToVideoRequest(videos.ModelCogVideoX2, "A cat playing with a ball").
+//	    SetDuration(10).
+//	    SetFPS(60).
+//	    SetSize(videos.Size1080p).
+//	    SetWithAudio(true).
+//	    SetQuality(videos.QualityQuality)
+//
+// Instead of polling, the API can notify a callback URL when the task
+// completes (see the webhooks package):
+//
+//	req.SetCallbackURL("https://example.com/hooks/zai")
 func (s *VideosService) Create(ctx context.Context, req *videos.VideoGenerationRequest) (*videos.VideoGenerationResponse, error) {
+	model, err := resolveModel(req.Model, s.defaultModel, "DefaultVideoModel")
+	if err != nil {
+		return nil, err
+	}
+	if model != req.Model {
+		withModel := *req
+		withModel.Model = model
+		req = &withModel
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/videos/generations", req)
+	apiResp, err := s.client.Post(s.client.Idempotent(ctx), s.client.Endpoints().Path(endpoints.VideoGenerations), req)
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +115,7 @@ func (s *VideosService) Create(ctx context.Context, req *videos.VideoGenerationR
 //	}
 func (s *VideosService) Retrieve(ctx context.Context, taskID string) (*videos.VideoResult, error) {
 	// Make the API request
-	path := fmt.Sprintf("/async-result/%s", taskID)
+	path := s.client.Endpoints().Path(endpoints.VideoResult, taskID)
 	apiResp, err := s.client.Get(ctx, path, nil)
 	if err != nil {
 		return nil, err
@@ -90,7 +131,9 @@ func (s *VideosService) Retrieve(ctx context.Context, taskID string) (*videos.Vi
 }
 
 // GenerateText is a convenience method for text-to-video generation.
-// Returns the task ID for later retrieval.
+// Returns the task ID for later retrieval, or an *errors.EmptyResultError
+// if the response has none. An empty model uses the client's default
+// video model.
 //
 // Example:
 //
@@ -108,11 +151,13 @@ func (s *VideosService) GenerateText(ctx context.Context, model videos.VideoMode
 		return "", err
 	}
 
-	return resp.GetTaskID(), nil
+	return videoTaskID("Videos.GenerateText", resp)
 }
 
 // GenerateFromImage is a convenience method for image-to-video generation.
-// Returns the task ID for later retrieval.
+// Returns the task ID for later retrieval, or an *errors.EmptyResultError
+// if the response has none. An empty model uses the client's default
+// video model.
 //
 // Example:
 //
@@ -130,11 +175,53 @@ func (s *VideosService) GenerateFromImage(ctx context.Context, model videos.Vide
 		return "", err
 	}
 
-	return resp.GetTaskID(), nil
+	return videoTaskID("Videos.GenerateFromImage", resp)
+}
+
+// GenerateFromImageReader is a convenience method for image-to-video
+// generation from an image that is not publicly reachable. The image is
+// read from r, checked against videos.MaxImageBytes and sent base64
+// encoded. An empty mimeType is detected from the image data. Returns the
+// task ID for later retrieval, or an *errors.EmptyResultError if the
+// response has none. An empty model uses the client's default video model.
+//
+// Example:
+//
+//	file, err := os.Open("frame.jpg")
+//	if err != nil {
+//	    // Handle error
+//	}
+//	defer file.Close()
+//
+//	taskID, err := client.Videos.GenerateFromImageReader(ctx, videos.ModelCogVideoX2, file, "image/jpeg")
+func (s *VideosService) GenerateFromImageReader(ctx context.Context, model videos.VideoModel, r io.Reader, mimeType string) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, videos.MaxImageBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read image: %w", err)
+	}
+	if len(data) > videos.MaxImageBytes {
+		return "", videos.NewImageTooLargeError(int64(len(data)), videos.MaxImageBytes)
+	}
+	if mimeType == "" {
+		mimeType, _, _ = strings.Cut(http.DetectContentType(data), ";")
+	}
+
+	req := (&videos.VideoGenerationRequest{Model: model}).SetImageBytes(data, mimeType)
+	resp, err := s.Create(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	return videoTaskID("Videos.GenerateFromImageReader", resp)
 }
 
 // WaitForCompletion waits for a video generation task to complete.
-// It polls the task status at regular intervals until completion or failure.
+// It polls the task status with exponential backoff up to pollInterval
+// until completion or failure; opts change the schedule. Defaults are
+// used for zero pollInterval (5s) and timeout (5m).
+//
+// A task that completes without any video URL is returned together with
+// an *errors.EmptyResultError.
 //
 // Example:
 //
@@ -146,7 +233,7 @@ func (s *VideosService) GenerateFromImage(ctx context.Context, model videos.Vide
 //	if result.IsCompleted() {
 //	    fmt.Printf("Video ready: %s\n", result.GetVideoURL())
 //	}
-func (s *VideosService) WaitForCompletion(ctx context.Context, taskID string, pollInterval, timeout time.Duration) (*videos.VideoResult, error) {
+func (s *VideosService) WaitForCompletion(ctx context.Context, taskID string, pollInterval, timeout time.Duration, opts ...PollOption) (*videos.VideoResult, error) {
 	if pollInterval == 0 {
 		pollInterval = 5 * time.Second
 	}
@@ -155,40 +242,216 @@ func (s *VideosService) WaitForCompletion(ctx context.Context, taskID string, po
 		timeout = 5 * time.Minute
 	}
 
-	deadline := time.Now().Add(timeout)
-	ticker := time.NewTicker(pollInterval)
-	defer ticker.Stop()
+	var result *videos.VideoResult
+	poller := newPoller(s.client, poll.Config{MaxInterval: pollInterval, Timeout: timeout}, opts)
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		var err error
+		if result, err = s.Retrieve(ctx, taskID); err != nil {
+			return false, err
+		}
+		return result.IsCompleted() || result.IsFailed(), nil
+	})
+	if stderrors.Is(err, poll.ErrTimeout) {
+		return nil, fmt.Errorf("timeout waiting for video generation to complete")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.IsCompleted() && len(result.GetAllVideoURLs()) == 0 {
+		return result, errors.NewEmptyResultError("Videos.WaitForCompletion", "video URL")
+	}
+	return result, nil
+}
+
+// videoTaskID returns the task ID of resp, or an EmptyResultError for
+// operation if it has none.
+func videoTaskID(operation string, resp *videos.VideoGenerationResponse) (string, error) {
+	if id := resp.GetTaskID(); id != "" {
+		return id, nil
+	}
+	return "", errors.NewEmptyResultError(operation, "task ID")
+}
 
-	for {
-		// Check deadline
-		if time.Now().After(deadline) {
-			return nil, fmt.Errorf("timeout waiting for video generation to complete")
+// RetrieveBatch retrieves the status and result of many video generation
+// tasks concurrently, instead of one Retrieve call after another. At most
+// videos.DefaultBatchConcurrency requests are in flight unless
+// videos.WithConcurrency is given. Duplicate task IDs are retrieved once.
+//
+// The results are keyed by task ID. If some tasks cannot be retrieved,
+// RetrieveBatch still returns the others, with a *videos.BatchError
+// mapping each failed task ID to its error.
+//
+// Example:
+//
+//	results, err := client.Videos.RetrieveBatch(ctx, taskIDs)
+//	var batchErr *videos.BatchError
+//	if err != nil && !errors.As(err, &batchErr) {
+//	    // Handle error
+//	}
+//
+//	for id, result := range results {
+//	    fmt.Printf("%s: %s\n", id, result.TaskStatus)
+//	}
+func (s *VideosService) RetrieveBatch(ctx context.Context, taskIDs []string, opts ...videos.BatchOption) (map[string]*videos.VideoResult, error) {
+	cfg := videos.NewBatchConfig(opts...)
+
+	results, failed := s.retrieveAll(ctx, uniqueTaskIDs(taskIDs), cfg.Concurrency)
+	if len(failed) > 0 {
+		return results, &videos.BatchError{Errors: failed}
+	}
+
+	return results, nil
+}
+
+// WaitForAll waits for many video generation tasks to complete or fail,
+// checking the status of the unfinished tasks every poll interval (see
+// videos.WithPollInterval) with bounded concurrency. videos.WithProgress
+// reports the counts of completed, failed and processing tasks after each
+// round of checks.
+//
+// The results hold the last status of every task that could be retrieved.
+// Tasks whose status cannot be retrieved are not checked again and are
+// reported in a *videos.BatchError once the others have finished. If
+// tasks are still processing after the timeout (see videos.WithTimeout),
+// WaitForAll returns a *videos.TimeoutError listing them.
+//
+// Example:
+//
+//	results, err := client.Videos.WaitForAll(ctx, taskIDs,
+//	    videos.WithPollInterval(10*time.Second),
+//	    videos.WithTimeout(10*time.Minute),
+//	    videos.WithProgress(func(p videos.WaitProgress) {
+//	        fmt.Printf("%d/%d done\n", p.Completed+p.Failed, p.Total)
+//	    }),
+//	)
+//	if err != nil {
+//	    // Handle error; results holds the tasks retrieved so far
+//	}
+//
+//	for id, result := range results {
+//	    if result.IsCompleted() {
+//	        fmt.Printf("%s: %s\n", id, result.GetVideoURL())
+//	    }
+//	}
+func (s *VideosService) WaitForAll(ctx context.Context, taskIDs []string, opts ...videos.BatchOption) (map[string]*videos.VideoResult, error) {
+	cfg := videos.NewBatchConfig(opts...)
+	ids := uniqueTaskIDs(taskIDs)
+
+	results := make(map[string]*videos.VideoResult, len(ids))
+	failed := make(map[string]error)
+	pending := ids
+
+	poller := newPoller(s.client, poll.Config{MaxInterval: cfg.PollInterval, Timeout: cfg.Timeout}, nil)
+	err := poller.Poll(ctx, func(ctx context.Context) (bool, error) {
+		if len(pending) == 0 {
+			return true, nil
 		}
 
-		// Check if context is done
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
+		round, errs := s.retrieveAll(ctx, pending, cfg.Concurrency)
+		if err := ctx.Err(); err != nil {
+			return false, err
 		}
+		maps.Copy(results, round)
+		maps.Copy(failed, errs)
 
-		// Retrieve current status
-		result, err := s.Retrieve(ctx, taskID)
-		if err != nil {
-			return nil, err
+		// Poll again the tasks that are neither finished nor failed to retrieve
+		var next []string
+		for _, id := range pending {
+			if result, ok := round[id]; ok && !result.IsCompleted() && !result.IsFailed() {
+				next = append(next, id)
+			}
 		}
+		pending = next
 
-		// Check if task is complete or failed
-		if result.IsCompleted() || result.IsFailed() {
-			return result, nil
+		if cfg.OnProgress != nil {
+			cfg.OnProgress(waitProgress(ids, results, failed))
 		}
+		return len(pending) == 0, nil
+	})
+	if stderrors.Is(err, poll.ErrTimeout) {
+		slices.Sort(pending)
+		return results, &videos.TimeoutError{Pending: pending, Timeout: cfg.Timeout}
+	}
+	if err != nil {
+		return results, err
+	}
 
-		// Wait for next poll
+	if len(failed) > 0 {
+		return results, &videos.BatchError{Errors: failed}
+	}
+
+	return results, nil
+}
+
+// retrieveAll retrieves the tasks concurrently, returning the results and
+// the errors by task ID.
+func (s *VideosService) retrieveAll(ctx context.Context, taskIDs []string, concurrency int) (map[string]*videos.VideoResult, map[string]error) {
+	results := make(map[string]*videos.VideoResult, len(taskIDs))
+	failed := make(map[string]error)
+
+	var mu sync.Mutex
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for _, id := range taskIDs {
 		select {
+		case sem <- struct{}{}:
 		case <-ctx.Done():
-			return nil, ctx.Err()
-		case <-ticker.C:
-			// Continue polling
+			mu.Lock()
+			failed[id] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := s.Retrieve(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[id] = err
+				return
+			}
+			results[id] = result
+		}(id)
+	}
+	wg.Wait()
+
+	return results, failed
+}
+
+// waitProgress counts the states of the tasks of a WaitForAll call.
+func waitProgress(ids []string, results map[string]*videos.VideoResult, failed map[string]error) videos.WaitProgress {
+	progress := videos.WaitProgress{Total: len(ids)}
+	for _, id := range ids {
+		result, ok := results[id]
+		switch {
+		case failed[id] != nil:
+			progress.Errored++
+		case ok && result.IsCompleted():
+			progress.Completed++
+		case ok && result.IsFailed():
+			progress.Failed++
+		default:
+			progress.Processing++
+		}
+	}
+	return progress
+}
+
+// uniqueTaskIDs returns taskIDs without duplicates, in their first order.
+func uniqueTaskIDs(taskIDs []string) []string {
+	seen := make(map[string]bool, len(taskIDs))
+	unique := make([]string, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
 		}
 	}
+	return unique
 }
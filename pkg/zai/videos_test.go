@@ -1,16 +1,23 @@
 package zai
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	videostypes "github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 func TestVideosService_Create(t *testing.T) {
@@ -313,6 +320,27 @@ func TestVideosService_GenerateText(t *testing.T) {
 		assert.Empty(t, taskID)
 		assert.Contains(t, err.Error(), "Invalid API key")
 	})
+
+	t.Run("no task ID", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(videostypes.VideoGenerationResponse{RequestID: "req-1"})
+		}))
+		defer server.Close()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		taskID, err := client.Videos.GenerateText(context.Background(), videostypes.ModelCogVideoX, "test")
+		var emptyErr *errors.EmptyResultError
+		require.ErrorAs(t, err, &emptyErr)
+		assert.Equal(t, "Videos.GenerateText", emptyErr.Operation)
+		assert.Equal(t, "task ID", emptyErr.Result)
+		assert.Empty(t, taskID)
+	})
 }
 
 func TestVideosService_GenerateFromImage(t *testing.T) {
@@ -348,6 +376,56 @@ func TestVideosService_GenerateFromImage(t *testing.T) {
 	})
 }
 
+func TestVideosService_GenerateFromImageReader(t *testing.T) {
+	t.Parallel()
+
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR fake image")
+
+	t.Run("sends the image as a data URL", func(t *testing.T) {
+		t.Parallel()
+
+		var body map[string]any
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(videostypes.VideoGenerationResponse{ID: "task-reader-1"})
+		}))
+		defer server.Close()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		// The MIME type is detected from the data
+		taskID, err := client.Videos.GenerateFromImageReader(context.Background(), videostypes.ModelCogVideoX2, bytes.NewReader(png), "")
+		require.NoError(t, err)
+		assert.Equal(t, "task-reader-1", taskID)
+		assert.Equal(t, "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png), body["image_url"])
+		assert.NotContains(t, body, "image_file_id")
+	})
+
+	t.Run("rejects images that are too large", func(t *testing.T) {
+		t.Parallel()
+
+		var called atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called.Store(true)
+		}))
+		defer server.Close()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		large := io.MultiReader(bytes.NewReader(png), strings.NewReader(strings.Repeat("x", videostypes.MaxImageBytes)))
+		_, err = client.Videos.GenerateFromImageReader(context.Background(), videostypes.ModelCogVideoX2, large, "image/png")
+		require.Error(t, err)
+		assert.True(t, errors.IsValidationError(err), "got %v", err)
+		assert.Contains(t, err.Error(), "exceeds the maximum size")
+		assert.False(t, called.Load(), "no request is sent")
+	})
+}
+
 func TestVideosService_WaitForCompletion(t *testing.T) {
 	t.Parallel()
 
@@ -470,6 +548,57 @@ func TestVideosService_WaitForCompletion(t *testing.T) {
 		assert.Contains(t, err.Error(), "timeout")
 	})
 
+	t.Run("completes without videos", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(videostypes.VideoResult{TaskID: "task-empty", TaskStatus: videostypes.StatusCompleted})
+		}))
+		defer server.Close()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		result, err := client.Videos.WaitForCompletion(context.Background(), "task-empty", 100*time.Millisecond, time.Second)
+		assert.True(t, errors.IsEmptyResultError(err))
+		require.NotNil(t, result)
+		assert.True(t, result.IsCompleted())
+	})
+
+	t.Run("poll options", func(t *testing.T) {
+		t.Parallel()
+
+		var polls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			polls.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(videostypes.VideoResult{TaskID: "task-slow", TaskStatus: videostypes.StatusProcessing})
+		}))
+		defer server.Close()
+
+		client, err := NewClient(WithAPIKey("test-key.test-secret"), WithBaseURL(server.URL))
+		require.NoError(t, err)
+		defer client.Close()
+
+		// An hour-long poll interval would overshoot the timeout: the
+		// backoff starts at 50ms and the final poll lands before the
+		// timeout instead
+		start := time.Now()
+		_, err = client.Videos.WaitForCompletion(context.Background(), "task-slow", time.Hour, time.Second,
+			WithPollInitialInterval(50*time.Millisecond),
+			WithPollJitter(false),
+			WithFinalPollLead(300*time.Millisecond),
+		)
+		elapsed := time.Since(start)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timeout")
+		assert.Less(t, elapsed, time.Second, "the final poll lands before the timeout")
+		assert.GreaterOrEqual(t, elapsed, 600*time.Millisecond)
+		assert.GreaterOrEqual(t, polls.Load(), int32(4), "polls at 0, 50ms, 150ms, 350ms and 700ms")
+	})
+
 	t.Run("context canceled", func(t *testing.T) {
 		t.Parallel()
 
@@ -584,3 +713,223 @@ func TestClient_VideosService_Integration(t *testing.T) {
 		assert.NotEmpty(t, result.GetVideoURL())
 	})
 }
+
+func TestVideosService_Create_InvalidParams(t *testing.T) {
+	t.Parallel()
+
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := videostypes.NewTextToVideoRequest(videostypes.ModelCogVideoX2, "A sunset").SetDuration(60)
+	_, err = client.Videos.Create(context.Background(), req)
+
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+	assert.False(t, called, "request should not reach the server")
+}
+
+// taskServer serves the async results of video tasks. Each task returns
+// its statuses in turn, repeating the last one; a status of "missing"
+// responds with 404.
+type taskServer struct {
+	mu       sync.Mutex
+	statuses map[string][]videostypes.TaskStatus
+	calls    map[string]int
+	inFlight int
+	maxSeen  int
+}
+
+func newTaskServer(t *testing.T, statuses map[string][]videostypes.TaskStatus, opts ...ClientOption) (*Client, *taskServer) {
+	ts := &taskServer{statuses: statuses, calls: make(map[string]int)}
+	server := httptest.NewServer(ts)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(append([]ClientOption{
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+		WithMaxRetries(1),
+	}, opts...)...)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client, ts
+}
+
+func (ts *taskServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/async-result/")
+
+	ts.mu.Lock()
+	ts.inFlight++
+	ts.maxSeen = max(ts.maxSeen, ts.inFlight)
+	statuses := ts.statuses[id]
+	call := ts.calls[id]
+	ts.calls[id]++
+	ts.mu.Unlock()
+
+	// Hold the request briefly so that concurrent requests overlap
+	time.Sleep(10 * time.Millisecond)
+
+	ts.mu.Lock()
+	ts.inFlight--
+	ts.mu.Unlock()
+
+	status := statuses[min(call, len(statuses)-1)]
+	if len(statuses) == 0 || status == "missing" {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]any{"error": map[string]string{"message": "task not found"}})
+		return
+	}
+
+	result := videostypes.VideoResult{TaskID: id, TaskStatus: status}
+	if status == videostypes.StatusCompleted {
+		result.VideoResult = []videostypes.VideoData{{URL: "https://example.com/" + id + ".mp4"}}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func (ts *taskServer) Calls(id string) int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.calls[id]
+}
+
+func TestVideosService_RetrieveBatch(t *testing.T) {
+	t.Parallel()
+
+	client, ts := newTaskServer(t, map[string][]videostypes.TaskStatus{
+		"done":       {videostypes.StatusCompleted},
+		"running":    {videostypes.StatusProcessing},
+		"queued":     {videostypes.StatusSubmitted},
+		"broken":     {videostypes.StatusFailed},
+		"unknown":    {"missing"},
+		"done-again": {videostypes.StatusCompleted},
+	})
+
+	ids := []string{"done", "running", "queued", "broken", "unknown", "done-again", "done"}
+	results, err := client.Videos.RetrieveBatch(context.Background(), ids, videostypes.WithConcurrency(2))
+
+	// The failed retrieval is reported without failing the others
+	var batchErr *videostypes.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	require.Len(t, batchErr.Errors, 1)
+	assert.ErrorContains(t, batchErr.Errors["unknown"], "task not found")
+
+	require.Len(t, results, 5)
+	assert.True(t, results["done"].IsCompleted())
+	assert.Equal(t, "https://example.com/done.mp4", results["done"].GetVideoURL())
+	assert.True(t, results["running"].IsProcessing())
+	assert.True(t, results["queued"].IsProcessing())
+	assert.True(t, results["broken"].IsFailed())
+	assert.NotContains(t, results, "unknown")
+
+	// Duplicates are retrieved once, and at most 2 requests are in flight
+	assert.Equal(t, 1, ts.Calls("done"))
+	assert.LessOrEqual(t, ts.maxSeen, 2)
+
+	results, err = client.Videos.RetrieveBatch(context.Background(), []string{"done", "broken"})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+}
+
+func TestVideosService_WaitForAll(t *testing.T) {
+	t.Parallel()
+
+	t.Run("mixed results", func(t *testing.T) {
+		t.Parallel()
+
+		client, ts := newTaskServer(t, map[string][]videostypes.TaskStatus{
+			"fast":    {videostypes.StatusCompleted},
+			"slow":    {videostypes.StatusSubmitted, videostypes.StatusProcessing, videostypes.StatusCompleted},
+			"broken":  {videostypes.StatusProcessing, videostypes.StatusFailed},
+			"unknown": {"missing"},
+		})
+
+		var progress []videostypes.WaitProgress
+		results, err := client.Videos.WaitForAll(context.Background(), []string{"fast", "slow", "broken", "unknown"},
+			videostypes.WithPollInterval(20*time.Millisecond),
+			videostypes.WithProgress(func(p videostypes.WaitProgress) {
+				progress = append(progress, p)
+			}),
+		)
+
+		var batchErr *videostypes.BatchError
+		require.ErrorAs(t, err, &batchErr)
+		assert.Contains(t, batchErr.Errors, "unknown")
+
+		assert.True(t, results["fast"].IsCompleted())
+		assert.True(t, results["slow"].IsCompleted())
+		assert.True(t, results["broken"].IsFailed())
+
+		assert.Equal(t, []videostypes.WaitProgress{
+			{Total: 4, Completed: 1, Errored: 1, Processing: 2},
+			{Total: 4, Completed: 1, Failed: 1, Errored: 1, Processing: 1},
+			{Total: 4, Completed: 2, Failed: 1, Errored: 1},
+		}, progress)
+
+		// Finished and missing tasks are not polled again
+		assert.Equal(t, 1, ts.Calls("fast"))
+		assert.Equal(t, 1, ts.Calls("unknown"))
+		assert.Equal(t, 2, ts.Calls("broken"))
+		assert.Equal(t, 3, ts.Calls("slow"))
+	})
+
+	t.Run("all completed", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := newTaskServer(t, map[string][]videostypes.TaskStatus{
+			"a": {videostypes.StatusProcessing, videostypes.StatusCompleted},
+			"b": {videostypes.StatusCompleted},
+		})
+
+		results, err := client.Videos.WaitForAll(context.Background(), []string{"a", "b"}, videostypes.WithPollInterval(10*time.Millisecond))
+		require.NoError(t, err)
+		assert.True(t, results["a"].IsCompleted())
+		assert.True(t, results["b"].IsCompleted())
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := newTaskServer(t, map[string][]videostypes.TaskStatus{
+			"stuck":  {videostypes.StatusProcessing},
+			"queued": {videostypes.StatusSubmitted},
+			"done":   {videostypes.StatusCompleted},
+		})
+
+		start := time.Now()
+		results, err := client.Videos.WaitForAll(context.Background(), []string{"stuck", "queued", "done"},
+			videostypes.WithPollInterval(20*time.Millisecond),
+			videostypes.WithTimeout(100*time.Millisecond),
+		)
+		assert.Less(t, time.Since(start), 2*time.Second)
+
+		var timeoutErr *videostypes.TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, []string{"queued", "stuck"}, timeoutErr.Pending)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.True(t, results["done"].IsCompleted())
+		assert.True(t, results["stuck"].IsProcessing())
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		t.Parallel()
+
+		client, _ := newTaskServer(t, map[string][]videostypes.TaskStatus{
+			"stuck": {videostypes.StatusProcessing},
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err := client.Videos.WaitForAll(ctx, []string{"stuck"}, videostypes.WithPollInterval(10*time.Millisecond))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -2,9 +2,17 @@ package zai
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/voice"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/pagination"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 )
 
 // VoiceService provides access to the Voice API.
@@ -39,7 +47,7 @@ func newVoiceService(baseClient *client.BaseClient) *VoiceService {
 //	fmt.Printf("Cloned voice: %s\n", resp.Voice)
 func (s *VoiceService) Clone(ctx context.Context, req *voice.VoiceCloneRequest) (*voice.VoiceCloneResponse, error) {
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/voice/clone", req)
+	apiResp, err := s.client.Post(s.client.Idempotent(ctx), s.client.Endpoints().Path(endpoints.VoiceClone), req)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +76,7 @@ func (s *VoiceService) Clone(ctx context.Context, req *voice.VoiceCloneRequest)
 //	fmt.Printf("Deleted voice: %s at %s\n", resp.Voice, resp.UpdateTime)
 func (s *VoiceService) Delete(ctx context.Context, req *voice.VoiceDeleteRequest) (*voice.VoiceDeleteResponse, error) {
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/voice/delete", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.VoiceDelete), req)
 	if err != nil {
 		return nil, err
 	}
@@ -115,12 +123,21 @@ func (s *VoiceService) List(ctx context.Context, req *voice.VoiceListRequest) (*
 	if req.VoiceName != "" {
 		query["voiceName"] = req.VoiceName
 	}
+	if req.NamePrefix != "" {
+		query["voiceNamePrefix"] = req.NamePrefix
+	}
+	if req.PageSize > 0 {
+		query["pageSize"] = strconv.Itoa(req.PageSize)
+	}
+	if req.Cursor != "" {
+		query["cursor"] = req.Cursor
+	}
 	if req.RequestID != "" {
 		query["request_id"] = req.RequestID
 	}
 
 	// Make the API request
-	apiResp, err := s.client.Get(ctx, "/voice/list", query)
+	apiResp, err := s.client.Get(ctx, s.client.Endpoints().Path(endpoints.VoiceList), query)
 	if err != nil {
 		return nil, err
 	}
@@ -131,5 +148,84 @@ func (s *VoiceService) List(ctx context.Context, req *voice.VoiceListRequest) (*
 		return nil, err
 	}
 
+	// Apply the prefix filter in case the server ignores it
+	if req.NamePrefix != "" {
+		voices := resp.VoiceList[:0]
+		for _, v := range resp.VoiceList {
+			if strings.HasPrefix(v.VoiceName, req.NamePrefix) {
+				voices = append(voices, v)
+			}
+		}
+		resp.VoiceList = voices
+	}
+
 	return &resp, nil
 }
+
+// ListAutoPaging returns an iterator over all voices matching req,
+// fetching pages lazily as the iteration advances. req.Cursor sets the
+// starting cursor and req.PageSize the page size.
+//
+// Example:
+//
+//	req := voice.NewVoiceListRequest().
+//	    SetVoiceType("cloned").
+//	    SetNamePrefix("support-")
+//
+//	iter := client.Voice.ListAutoPaging(ctx, req)
+//	for iter.Next() {
+//	    v := iter.Current()
+//	    fmt.Printf("Voice: %s (%s)\n", v.VoiceName, v.Voice)
+//	}
+//	if err := iter.Err(); err != nil {
+//	    // Handle error
+//	}
+func (s *VoiceService) ListAutoPaging(ctx context.Context, req *voice.VoiceListRequest) *pagination.Iterator[voice.VoiceData] {
+	return pagination.NewIterator(ctx, req.Cursor, func(ctx context.Context, cursor string) (*pagination.Page[voice.VoiceData], error) {
+		pageReq := *req
+		pageReq.Cursor = cursor
+
+		resp, err := s.List(ctx, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pagination.Page[voice.VoiceData]{
+			Items:   resp.VoiceList,
+			HasMore: resp.HasMore,
+			Cursor:  resp.NextCursor,
+		}, nil
+	})
+}
+
+// Preview synthesizes a short sample of text with a cloned voice, so a
+// clone can be checked before it is used for speech synthesis. The text
+// is limited to voice.MaxPreviewLength characters.
+//
+// Example:
+//
+//	sample, err := client.Voice.Preview(ctx, clone.Voice, "Hi, this is my cloned voice.")
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	if err := sample.SaveTo("preview.wav"); err != nil {
+//	    // Handle error
+//	}
+func (s *VoiceService) Preview(ctx context.Context, voiceID, text string) (*audio.SpeechResponse, error) {
+	if voiceID == "" {
+		return nil, errors.NewValidationError("voice", "is required", nil)
+	}
+	if text == "" {
+		return nil, errors.NewValidationError("text", "is required", nil)
+	}
+	if n := utf8.RuneCountInString(text); n > voice.MaxPreviewLength {
+		return nil, errors.NewValidationError("text",
+			fmt.Sprintf("must be at most %d characters for a preview", voice.MaxPreviewLength), n)
+	}
+
+	req := audio.NewSpeechRequest(audio.ModelCogTTS, text, voiceID).
+		SetResponseFormat(audio.SpeechFormatWAV)
+
+	return newAudioService(s.client).Speech(ctx, req)
+}
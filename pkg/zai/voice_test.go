@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/audio"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/voice"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -315,3 +318,148 @@ func TestVoiceService_List_EmptyResult(t *testing.T) {
 	assert.NotNil(t, voices)
 	assert.Len(t, voices, 0)
 }
+
+func TestVoiceService_ListAutoPaging(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]voice.VoiceListResponse{
+		"": {
+			VoiceList:  []voice.VoiceData{{Voice: "v1", VoiceName: "support-1"}, {Voice: "v2", VoiceName: "support-2"}},
+			HasMore:    true,
+			NextCursor: "c2",
+		},
+		"c2": {
+			VoiceList:  []voice.VoiceData{{Voice: "v3", VoiceName: "support-3"}, {Voice: "v4", VoiceName: "sales-1"}},
+			HasMore:    true,
+			NextCursor: "c3",
+		},
+		"c3": {
+			VoiceList: []voice.VoiceData{{Voice: "v5", VoiceName: "support-4"}},
+		},
+	}
+
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/voice/list", r.URL.Path)
+		assert.Equal(t, "cloned", r.URL.Query().Get("voiceType"))
+		assert.Equal(t, "support-", r.URL.Query().Get("voiceNamePrefix"))
+		assert.Equal(t, "2", r.URL.Query().Get("pageSize"))
+
+		cursor := r.URL.Query().Get("cursor")
+		cursors = append(cursors, cursor)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(pages[cursor])
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := voice.NewVoiceListRequest().
+		SetVoiceType("cloned").
+		SetNamePrefix("support-").
+		SetPageSize(2)
+
+	iter := client.Voice.ListAutoPaging(context.Background(), req)
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Current().Voice)
+	}
+	require.NoError(t, iter.Err())
+
+	// The prefix filter also applies when the server ignores it
+	assert.Equal(t, []string{"v1", "v2", "v3", "v5"}, ids)
+	assert.Equal(t, []string{"", "c2", "c3"}, cursors)
+	assert.Empty(t, req.Cursor, "the request is not modified")
+}
+
+func TestVoiceService_List_Page(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "c2", r.URL.Query().Get("cursor"))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"voice_list":[{"voice":"v3"}],"has_more":true,"next_cursor":"c3"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.Voice.List(context.Background(), voice.NewVoiceListRequest().SetCursor("c2"))
+	require.NoError(t, err)
+	assert.Len(t, resp.GetVoices(), 1)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, "c3", resp.NextCursor)
+}
+
+func TestVoiceService_Preview(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/audio/speech", r.URL.Path)
+
+		var req audio.SpeechRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "voice_456", req.Voice)
+		assert.Equal(t, "Hi, this is my cloned voice.", req.Input)
+		assert.Equal(t, audio.ModelCogTTS, req.Model)
+		assert.Equal(t, audio.SpeechFormatWAV, req.ResponseFormat)
+		assert.False(t, req.Stream)
+
+		w.Header().Set("Content-Type", "audio/wav")
+		w.Write([]byte("RIFF....WAVE"))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	sample, err := client.Voice.Preview(context.Background(), "voice_456", "Hi, this is my cloned voice.")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("RIFF....WAVE"), sample.Bytes())
+	assert.Equal(t, "audio/wav", sample.ContentType)
+}
+
+func TestVoiceService_Preview_Validation(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL("http://127.0.0.1:0"),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		voiceID string
+		text    string
+		field   string
+	}{
+		{name: "missing voice", text: "hello", field: "voice"},
+		{name: "missing text", voiceID: "voice_456", field: "text"},
+		{name: "text too long", voiceID: "voice_456", text: strings.Repeat("语", voice.MaxPreviewLength+1), field: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Voice.Preview(context.Background(), tt.voiceID, tt.text)
+
+			var validationErr *errors.ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, tt.field, validationErr.Field)
+		})
+	}
+}
@@ -0,0 +1,320 @@
+// Package webhooks verifies and decodes the notifications the API sends to
+// the callback URL of an asynchronous task, such as a batch or a video
+// generation task, when the task completes.
+//
+// Every notification is signed with HMAC-SHA256 over its timestamp and
+// body, using the webhook secret of the account. ParseEvent checks the
+// signature in constant time, rejects notifications whose timestamp is
+// outside the tolerance to prevent replays, and decodes the body into a
+// typed event.
+//
+// Example:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//	    payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+//	    if err != nil {
+//	        http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+//	        return
+//	    }
+//	    event, err := webhooks.ParseEvent(payload, r.Header, secret)
+//	    if err != nil {
+//	        http.Error(w, "invalid signature", http.StatusBadRequest)
+//	        return
+//	    }
+//
+//	    switch e := event.(type) {
+//	    case *webhooks.BatchCompleted:
+//	        fmt.Println("batch", e.Batch.ID, e.Batch.Status)
+//	    case *webhooks.VideoTaskCompleted:
+//	        fmt.Println("video", e.Result.GetVideoURL())
+//	    case *webhooks.FileParseCompleted:
+//	        fmt.Println("parse task", e.Task.TaskID, e.Task.Status)
+//	    case *webhooks.RawEvent:
+//	        // An event type this SDK version does not know
+//	    }
+//	    w.WriteHeader(http.StatusNoContent)
+//	}
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+)
+
+// Notification headers.
+const (
+	// SignatureHeader carries the signatures of a notification, as
+	// space-separated "v1=<hex>" values. There is more than one while
+	// the webhook secret is being rotated.
+	SignatureHeader = "X-Zai-Signature"
+
+	// TimestampHeader carries the Unix time in seconds at which the
+	// notification was signed.
+	TimestampHeader = "X-Zai-Timestamp"
+)
+
+// signatureVersion prefixes the HMAC-SHA256 signatures.
+const signatureVersion = "v1="
+
+// DefaultTolerance is how far the timestamp of a notification may be from
+// the current time before ParseEvent rejects it as a replay.
+const DefaultTolerance = 5 * time.Minute
+
+// Event types.
+const (
+	// TypeBatchCompleted is sent when a batch reaches a terminal status.
+	TypeBatchCompleted = "batch.completed"
+
+	// TypeVideoTaskCompleted is sent when a video generation task
+	// succeeds or fails.
+	TypeVideoTaskCompleted = "video.task.completed"
+
+	// TypeFileParseCompleted is sent when a file parsing task succeeds or
+	// fails.
+	TypeFileParseCompleted = "file_parser.task.completed"
+)
+
+var (
+	// ErrMissingSignature is returned for a notification without the
+	// signature or timestamp header.
+	ErrMissingSignature = errors.New("webhooks: missing signature")
+
+	// ErrInvalidSignature is returned for a notification whose signature
+	// does not match its body and timestamp, for example because either
+	// was tampered with or the secret is wrong.
+	ErrInvalidSignature = errors.New("webhooks: invalid signature")
+
+	// ErrTimestampOutOfTolerance is returned for a notification signed
+	// too long ago or too far in the future, such as a replayed one.
+	ErrTimestampOutOfTolerance = errors.New("webhooks: timestamp outside the tolerance")
+)
+
+// Event is a decoded notification: a *BatchCompleted,
+// *VideoTaskCompleted, *FileParseCompleted or, for event types this SDK
+// version does not know, a *RawEvent.
+type Event interface {
+	// EventID returns the ID of the event. A notification that is
+	// delivered again keeps its ID, so it can be used to deduplicate.
+	EventID() string
+
+	// EventType returns the type of the event, such as
+	// TypeBatchCompleted.
+	EventType() string
+}
+
+// Envelope holds the fields common to every event.
+type Envelope struct {
+	// ID is the ID of the event.
+	ID string `json:"id"`
+
+	// Type is the type of the event.
+	Type string `json:"type"`
+
+	// CreatedAt is the Unix time in seconds at which the event occurred.
+	CreatedAt int64 `json:"created_at"`
+
+	// Data is the undecoded payload of the event.
+	Data json.RawMessage `json:"data"`
+}
+
+// EventID implements Event.
+func (e *Envelope) EventID() string {
+	return e.ID
+}
+
+// EventType implements Event.
+func (e *Envelope) EventType() string {
+	return e.Type
+}
+
+// BatchCompleted reports a batch that reached a terminal status. Check
+// Batch.Status: a failed, expired or cancelled batch is reported too.
+type BatchCompleted struct {
+	Envelope
+
+	// Batch is the batch at the time of the event.
+	Batch batch.Batch
+}
+
+// VideoTaskCompleted reports a video generation task that succeeded or
+// failed.
+type VideoTaskCompleted struct {
+	Envelope
+
+	// Result is the task result, as returned by Videos.Retrieve.
+	Result videos.VideoResult
+}
+
+// FileParseCompleted reports a file parsing task that succeeded or
+// failed. Fetch the parsed content with FileParser.Content.
+type FileParseCompleted struct {
+	Envelope
+
+	// Task is the status of the task.
+	Task fileparser.TaskStatusResponse
+}
+
+// RawEvent is an event of a type this SDK version does not decode. Its
+// payload is in Data.
+type RawEvent struct {
+	Envelope
+}
+
+// Option configures ParseEvent and Verify.
+type Option func(*config)
+
+type config struct {
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// WithTolerance sets how far the timestamp of a notification may be from
+// the current time. Zero or negative disables the check, which leaves
+// replays undetected.
+func WithTolerance(d time.Duration) Option {
+	return func(c *config) {
+		c.tolerance = d
+	}
+}
+
+// WithClock sets the function returning the current time the timestamp
+// of a notification is checked against.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) {
+		c.now = now
+	}
+}
+
+func newConfig(opts []Option) *config {
+	cfg := &config{tolerance: DefaultTolerance, now: time.Now}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ParseEvent verifies the signature of a notification, see Verify, and
+// decodes its body. payload must be the raw request body: the signature
+// does not match a body that was decoded and encoded again.
+func ParseEvent(payload []byte, headers http.Header, secret string, opts ...Option) (Event, error) {
+	if err := Verify(payload, headers, secret, opts...); err != nil {
+		return nil, err
+	}
+
+	var env Envelope
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return nil, fmt.Errorf("webhooks: failed to decode event: %w", err)
+	}
+	if env.Type == "" {
+		return nil, errors.New("webhooks: event has no type")
+	}
+
+	var (
+		event Event
+		data  any
+	)
+	switch env.Type {
+	case TypeBatchCompleted:
+		e := &BatchCompleted{Envelope: env}
+		event, data = e, &e.Batch
+	case TypeVideoTaskCompleted:
+		e := &VideoTaskCompleted{Envelope: env}
+		event, data = e, &e.Result
+	case TypeFileParseCompleted:
+		e := &FileParseCompleted{Envelope: env}
+		event, data = e, &e.Task
+	default:
+		return &RawEvent{Envelope: env}, nil
+	}
+
+	if len(env.Data) == 0 {
+		return nil, fmt.Errorf("webhooks: %s event has no data", env.Type)
+	}
+	if err := json.Unmarshal(env.Data, data); err != nil {
+		return nil, fmt.Errorf("webhooks: failed to decode %s event: %w", env.Type, err)
+	}
+	return event, nil
+}
+
+// Verify checks that a notification was signed with secret and that its
+// timestamp is within DefaultTolerance of the current time. Signatures are
+// compared in constant time. It returns ErrMissingSignature,
+// ErrInvalidSignature or ErrTimestampOutOfTolerance.
+func Verify(payload []byte, headers http.Header, secret string, opts ...Option) error {
+	cfg := newConfig(opts)
+
+	timestamp := headers.Get(TimestampHeader)
+	signatures := headers.Get(SignatureHeader)
+	if timestamp == "" || signatures == "" {
+		return ErrMissingSignature
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed timestamp %q", ErrInvalidSignature, timestamp)
+	}
+
+	// Check the signature first, so that a forged timestamp is reported
+	// as such rather than as a replay
+	expected := sign(payload, secret, timestamp)
+	valid := false
+	for _, value := range strings.Fields(signatures) {
+		hexSig, ok := strings.CutPrefix(value, signatureVersion)
+		if !ok {
+			continue
+		}
+		sig, err := hex.DecodeString(hexSig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(sig, expected) {
+			valid = true
+		}
+	}
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	if cfg.tolerance > 0 {
+		age := cfg.now().Sub(time.Unix(seconds, 0))
+		if age > cfg.tolerance || age < -cfg.tolerance {
+			return fmt.Errorf("%w: signed %s ago", ErrTimestampOutOfTolerance, age.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Sign returns the headers of a notification of payload signed with
+// secret at time t. It is meant for testing webhook handlers.
+//
+// Example:
+//
+//	req := httptest.NewRequest(http.MethodPost, "/hooks/zai", bytes.NewReader(payload))
+//	maps.Copy(req.Header, webhooks.Sign(payload, secret, time.Now()))
+func Sign(payload []byte, secret string, t time.Time) http.Header {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	headers := make(http.Header)
+	headers.Set(TimestampHeader, timestamp)
+	headers.Set(SignatureHeader, signatureVersion+hex.EncodeToString(sign(payload, secret, timestamp)))
+	return headers
+}
+
+// sign returns the HMAC-SHA256 of timestamp and payload.
+func sign(payload []byte, secret, timestamp string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
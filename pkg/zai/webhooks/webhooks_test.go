@@ -0,0 +1,261 @@
+package webhooks
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/fileparser"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+)
+
+const testSecret = "whsec_test"
+
+const (
+	batchPayload = `{"id":"evt_1","type":"batch.completed","created_at":1760582112,` +
+		`"data":{"id":"batch_123","object":"batch","status":"completed","output_file_id":"file_out",` +
+		`"request_counts":{"completed":10,"failed":0,"total":10}}}`
+
+	videoPayload = `{"id":"evt_2","type":"video.task.completed","created_at":1760582113,` +
+		`"data":{"task_id":"task_abc","task_status":"completed",` +
+		`"video_result":[{"url":"https://cdn.example.com/v.mp4","cover_image_url":"https://cdn.example.com/v.jpg"}]}}`
+
+	parsePayload = `{"id":"evt_3","type":"file_parser.task.completed","created_at":1760582114,` +
+		`"data":{"task_id":"parse_1","status":"succeeded","message":"ok"}}`
+
+	unknownPayload = `{"id":"evt_4","type":"assistant.run.completed","created_at":1760582115,"data":{"run_id":"run_1"}}`
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+
+	t.Run("batch completed", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(batchPayload)
+		event, err := ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		require.NoError(t, err)
+
+		e, ok := event.(*BatchCompleted)
+		require.True(t, ok, "got %T", event)
+		assert.Equal(t, "evt_1", e.EventID())
+		assert.Equal(t, TypeBatchCompleted, e.EventType())
+		assert.Equal(t, int64(1760582112), e.CreatedAt)
+		assert.Equal(t, "batch_123", e.Batch.ID)
+		assert.Equal(t, batch.StatusCompleted, e.Batch.Status)
+		assert.Equal(t, "file_out", e.Batch.OutputFileID)
+		assert.Equal(t, 10, e.Batch.RequestCounts.Total)
+	})
+
+	t.Run("video task completed", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(videoPayload)
+		event, err := ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		require.NoError(t, err)
+
+		e, ok := event.(*VideoTaskCompleted)
+		require.True(t, ok, "got %T", event)
+		assert.Equal(t, "task_abc", e.Result.TaskID)
+		assert.Equal(t, videos.StatusCompleted, e.Result.TaskStatus)
+		assert.Equal(t, "https://cdn.example.com/v.mp4", e.Result.GetVideoURL())
+	})
+
+	t.Run("file parse completed", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(parsePayload)
+		event, err := ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		require.NoError(t, err)
+
+		e, ok := event.(*FileParseCompleted)
+		require.True(t, ok, "got %T", event)
+		assert.Equal(t, "parse_1", e.Task.TaskID)
+		assert.Equal(t, fileparser.TaskStatusSucceeded, e.Task.Status)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(unknownPayload)
+		event, err := ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		require.NoError(t, err)
+
+		e, ok := event.(*RawEvent)
+		require.True(t, ok, "got %T", event)
+		assert.Equal(t, "assistant.run.completed", e.EventType())
+		assert.JSONEq(t, `{"run_id":"run_1"}`, string(e.Data))
+	})
+
+	t.Run("malformed data", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(`{"id":"evt_5","type":"batch.completed","data":{"request_counts":"none"}}`)
+		event, err := ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		assert.ErrorContains(t, err, "failed to decode batch.completed event")
+		assert.Nil(t, event)
+
+		payload = []byte(`{"id":"evt_6","type":"batch.completed"}`)
+		_, err = ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		assert.ErrorContains(t, err, "has no data")
+
+		payload = []byte(`{"id":"evt_7"}`)
+		_, err = ParseEvent(payload, Sign(payload, testSecret, time.Now()), testSecret)
+		assert.ErrorContains(t, err, "has no type")
+	})
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(batchPayload)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		headers http.Header
+		opts    []Option
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			payload: payload,
+			headers: Sign(payload, testSecret, now),
+		},
+		{
+			name:    "tampered payload",
+			payload: []byte(`{"id":"evt_1","type":"batch.completed","data":{"id":"batch_999"}}`),
+			headers: Sign(payload, testSecret, now),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "wrong secret",
+			payload: payload,
+			headers: Sign(payload, "whsec_other", now),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "tampered timestamp",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now.Add(-time.Hour))
+				h.Set(TimestampHeader, Sign(payload, testSecret, now).Get(TimestampHeader))
+				return h
+			}(),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "malformed timestamp",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now)
+				h.Set(TimestampHeader, "yesterday")
+				return h
+			}(),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "malformed signature",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now)
+				h.Set(SignatureHeader, "v1=not-hex")
+				return h
+			}(),
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:    "expired",
+			payload: payload,
+			headers: Sign(payload, testSecret, now.Add(-10*time.Minute)),
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+		{
+			name:    "from the future",
+			payload: payload,
+			headers: Sign(payload, testSecret, now.Add(10*time.Minute)),
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+		{
+			name:    "within a custom tolerance",
+			payload: payload,
+			headers: Sign(payload, testSecret, now.Add(-10*time.Minute)),
+			opts:    []Option{WithTolerance(time.Hour)},
+		},
+		{
+			name:    "tolerance check disabled",
+			payload: payload,
+			headers: Sign(payload, testSecret, now.Add(-48*time.Hour)),
+			opts:    []Option{WithTolerance(0)},
+		},
+		{
+			name:    "expired by the clock",
+			payload: payload,
+			headers: Sign(payload, testSecret, now),
+			opts:    []Option{WithClock(func() time.Time { return now.Add(time.Hour) })},
+			wantErr: ErrTimestampOutOfTolerance,
+		},
+		{
+			name:    "rotated secret",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now)
+				old := Sign(payload, "whsec_old", now)
+				h.Set(SignatureHeader, old.Get(SignatureHeader)+" v0=ignored "+h.Get(SignatureHeader))
+				return h
+			}(),
+		},
+		{
+			name:    "missing signature",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now)
+				h.Del(SignatureHeader)
+				return h
+			}(),
+			wantErr: ErrMissingSignature,
+		},
+		{
+			name:    "missing timestamp",
+			payload: payload,
+			headers: func() http.Header {
+				h := Sign(payload, testSecret, now)
+				h.Del(TimestampHeader)
+				return h
+			}(),
+			wantErr: ErrMissingSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := Verify(tt.payload, tt.headers, testSecret, tt.opts...)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			// ParseEvent verifies before decoding
+			event, err := ParseEvent(tt.payload, tt.headers, testSecret, tt.opts...)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Nil(t, event)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	headers := Sign([]byte(`{}`), "secret", time.Unix(1760582112, 0))
+	assert.Equal(t, "1760582112", headers.Get(TimestampHeader))
+	// HMAC-SHA256("secret", "1760582112.{}")
+	assert.Equal(t, "v1=4603a17a4098c25edb63ad72622c2eb463db696adc3610e2b4dd95df0cfd1d3f", headers.Get(SignatureHeader))
+}
@@ -2,9 +2,11 @@ package zai
 
 import (
 	"context"
+	"sync"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 )
 
 // WebReaderService provides access to the Web Reader API.
@@ -19,12 +21,13 @@ func newWebReaderService(baseClient *client.BaseClient) *WebReaderService {
 	}
 }
 
-// Read reads and extracts content from a web page.
+// Read reads and extracts content from a web page. The request is
+// validated before it is sent.
 //
 // Example:
 //
 //	req := webreader.NewRequest("https://example.com").
-//	    SetReturnFormat("markdown").
+//	    SetReturnFormat(webreader.FormatMarkdown).
 //	    SetRetainImages(true).
 //	    SetWithLinksSummary(true)
 //
@@ -55,13 +58,21 @@ func newWebReaderService(baseClient *client.BaseClient) *WebReaderService {
 //
 //	req := webreader.NewRequest("https://news.example.com").
 //	    SetNoCache(true).
-//	    SetReturnFormat("markdown").
+//	    SetReturnFormat(webreader.FormatMarkdown).
 //	    SetWithImagesSummary(true)
 //
 //	resp, err := client.WebReader.Read(ctx, req)
 func (s *WebReaderService) Read(ctx context.Context, req *webreader.Request) (*webreader.Response, error) {
-	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/reader", req)
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Make the API request, bypassing the client cache when NoCache is set
+	post := s.client.PostCached
+	if req.NoCache {
+		post = s.client.Post
+	}
+	apiResp, err := post(ctx, s.client.Endpoints().Path(endpoints.Reader), req)
 	if err != nil {
 		return nil, err
 	}
@@ -74,3 +85,76 @@ func (s *WebReaderService) Read(ctx context.Context, req *webreader.Request) (*w
 
 	return &resp, nil
 }
+
+// ReadBatch reads multiple pages concurrently, applying the same options to
+// each request. Results are returned in the same order as urls.
+//
+// At most webreader.DefaultBatchConcurrency requests are in flight unless
+// webreader.WithConcurrency is given, and webreader.WithReadTimeout bounds
+// each read. If some pages fail, ReadBatch still returns every result, with
+// Err set on the failed entries, and a *webreader.BatchError mapping each
+// failed URL to its error. Cancelling ctx aborts in-flight requests and
+// skips those not yet started.
+//
+// Example:
+//
+//	results, err := client.WebReader.ReadBatch(ctx, urls,
+//	    webreader.WithReturnFormat(webreader.FormatMarkdown),
+//	    webreader.WithConcurrency(10),
+//	)
+//	var batchErr *webreader.BatchError
+//	if err != nil && !errors.As(err, &batchErr) {
+//	    // Handle error
+//	}
+//
+//	for _, result := range results {
+//	    if result.OK() {
+//	        fmt.Printf("%s: %s\n", result.URL, result.Response.GetTitle())
+//	    }
+//	}
+func (s *WebReaderService) ReadBatch(ctx context.Context, urls []string, opts ...webreader.Option) ([]*webreader.Result, error) {
+	cfg := webreader.NewBatchConfig(opts...)
+	results := make([]*webreader.Result, len(urls))
+
+	sem := make(chan struct{}, cfg.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, url := range urls {
+		results[i] = &webreader.Result{URL: url}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *webreader.Result) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			readCtx := ctx
+			if cfg.ReadTimeout > 0 {
+				var cancel context.CancelFunc
+				readCtx, cancel = context.WithTimeout(ctx, cfg.ReadTimeout)
+				defer cancel()
+			}
+
+			result.Response, result.Err = s.Read(readCtx, cfg.NewRequest(result.URL))
+		}(results[i])
+	}
+	wg.Wait()
+
+	failed := make(map[string]error)
+	for _, result := range results {
+		if result.Err != nil {
+			failed[result.URL] = result.Err
+		}
+	}
+	if len(failed) > 0 {
+		return results, &webreader.BatchError{Errors: failed}
+	}
+
+	return results, nil
+}
@@ -3,11 +3,15 @@ package zai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -83,7 +87,7 @@ func TestWebReaderService_Read_WithOptions(t *testing.T) {
 		assert.Equal(t, "user_456", req.UserID)
 		assert.Equal(t, "30", req.Timeout)
 		assert.True(t, req.NoCache)
-		assert.Equal(t, "markdown", req.ReturnFormat)
+		assert.Equal(t, webreader.FormatMarkdown, req.ReturnFormat)
 		assert.True(t, req.RetainImages)
 		assert.True(t, req.WithImagesSummary)
 		assert.True(t, req.WithLinksSummary)
@@ -132,7 +136,7 @@ func TestWebReaderService_Read_TextFormat(t *testing.T) {
 		var req webreader.Request
 		json.NewDecoder(r.Body).Decode(&req)
 
-		assert.Equal(t, "text", req.ReturnFormat)
+		assert.Equal(t, webreader.FormatText, req.ReturnFormat)
 
 		resp := webreader.Response{
 			ReaderResult: &webreader.ReaderData{
@@ -271,3 +275,196 @@ func TestWebReaderService_Read_NoGFM(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotNil(t, resp)
 }
+
+func TestWebReaderService_ReadBatch(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+
+		var req webreader.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, webreader.FormatMarkdown, req.ReturnFormat)
+		assert.True(t, req.NoCache)
+
+		time.Sleep(50 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(webreader.Response{
+			ReaderResult: &webreader.ReaderData{Title: "Title of " + req.URL, URL: req.URL},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	urls := make([]string, 10)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/%d", i)
+	}
+
+	results, err := client.WebReader.ReadBatch(context.Background(), urls,
+		webreader.WithReturnFormat("markdown"),
+		webreader.WithNoCache(true),
+		webreader.WithConcurrency(3),
+	)
+	require.NoError(t, err)
+	require.Len(t, results, len(urls))
+
+	for i, result := range results {
+		assert.True(t, result.OK())
+		assert.Equal(t, urls[i], result.URL)
+		assert.Equal(t, "Title of "+urls[i], result.Response.GetTitle())
+	}
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxInFlight), int32(3))
+	assert.Greater(t, atomic.LoadInt32(&maxInFlight), int32(1))
+}
+
+func TestWebReaderService_ReadBatch_PartialFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req webreader.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		w.Header().Set("Content-Type", "application/json")
+		if req.URL == "https://bad.example" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "URL cannot be read", "code": "1214"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(webreader.Response{
+			ReaderResult: &webreader.ReaderData{Title: "ok"},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	urls := []string{"https://a.example", "https://bad.example", "https://c.example"}
+	results, err := client.WebReader.ReadBatch(context.Background(), urls)
+	require.Error(t, err)
+
+	var batchErr *webreader.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Len(t, batchErr.Errors, 1)
+	assert.Contains(t, batchErr.Errors, "https://bad.example")
+
+	require.Len(t, results, 3)
+	assert.True(t, results[0].OK())
+	assert.False(t, results[1].OK())
+	assert.Error(t, results[1].Err)
+	assert.True(t, results[2].OK())
+}
+
+func TestWebReaderService_ReadBatch_ContextCancel(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	urls := []string{"https://a.example", "https://b.example", "https://c.example", "https://d.example"}
+
+	start := time.Now()
+	results, err := client.WebReader.ReadBatch(ctx, urls, webreader.WithConcurrency(2))
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, results, len(urls))
+	for _, result := range results {
+		assert.Error(t, result.Err)
+	}
+}
+
+func TestWebReaderService_Read_Extraction(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req webreader.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, webreader.ExtractModeArticle, req.ExtractMode)
+		assert.True(t, req.IncludeOutline)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"reader_result": {
+			"title": "Post",
+			"content": "# Post",
+			"outline": [{"level": 1, "text": "Post"}],
+			"metadata": {"author": "Jane Doe", "site_name": "Example", "lang": "en"}
+		}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := webreader.NewRequest("https://example.com/post").
+		SetExtractMode(webreader.ExtractModeArticle).
+		SetIncludeOutline(true)
+
+	resp, err := client.WebReader.Read(context.Background(), req)
+	require.NoError(t, err)
+
+	result := resp.GetResult()
+	assert.Equal(t, []webreader.Heading{{Level: 1, Text: "Post"}}, result.Outline)
+	assert.Equal(t, "Jane Doe", result.Author)
+	assert.Equal(t, "Example", result.SiteName)
+	assert.Equal(t, "en", result.Metadata["lang"])
+}
+
+func TestWebReaderService_Read_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	_, err = client.WebReader.Read(context.Background(), webreader.NewRequest("https://example.com").SetReturnFormat("markdwon"))
+	assert.True(t, errors.IsValidationError(err), "got %v", err)
+	assert.Zero(t, calls.Load())
+}
@@ -2,9 +2,12 @@ package zai
 
 import (
 	"context"
+	"fmt"
 
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/websearch"
 	"github.com/sofianhadi1983/zai-sdk-go/internal/client"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/endpoints"
 )
 
 // WebSearchService provides access to the Web Search API.
@@ -48,8 +51,16 @@ func newWebSearchService(baseClient *client.BaseClient) *WebSearchService {
 //	    fmt.Printf("   Published: %s\n", result.PublishDate)
 //	}
 func (s *WebSearchService) Search(ctx context.Context, req *websearch.WebSearchRequest) (*websearch.WebSearchResponse, error) {
+	// Use the platform's name for the search engine without changing req
+	send := req
+	if engine := s.client.Endpoints().Value(endpoints.WebSearch, "search_engine", req.SearchEngine); engine != req.SearchEngine {
+		copied := *req
+		copied.SearchEngine = engine
+		send = &copied
+	}
+
 	// Make the API request
-	apiResp, err := s.client.Post(ctx, "/web_search", req)
+	apiResp, err := s.client.Post(ctx, s.client.Endpoints().Path(endpoints.WebSearch), send)
 	if err != nil {
 		return nil, err
 	}
@@ -62,3 +73,186 @@ func (s *WebSearchService) Search(ctx context.Context, req *websearch.WebSearchR
 
 	return &resp, nil
 }
+
+// SearchAll fetches pages of results until maxResults unique results have
+// been collected or the API reports no more pages. Results are deduplicated
+// by Link. The request is not modified.
+//
+// If the caller set a RequestID, it is sent unchanged for the first page and
+// suffixed with "-p<N>" for later pages so each page stays traceable.
+//
+// If a page fails, SearchAll returns the results collected so far together
+// with a *websearch.PageError describing the failed page.
+//
+// Example:
+//
+//	req := websearch.NewWebSearchRequest("open source LLM benchmarks").
+//	    SetCount(20)
+//
+//	resp, err := client.WebSearch.SearchAll(ctx, req, 50)
+//	if err != nil {
+//	    var pageErr *websearch.PageError
+//	    if !errors.As(err, &pageErr) || resp == nil {
+//	        // Handle error
+//	    }
+//	    // resp still holds results from earlier pages
+//	}
+//
+//	for _, result := range resp.GetResults() {
+//	    fmt.Println(result.Title, result.Link)
+//	}
+func (s *WebSearchService) SearchAll(ctx context.Context, req *websearch.WebSearchRequest, maxResults int) (*websearch.SearchAllResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+	if maxResults <= 0 {
+		return nil, fmt.Errorf("maxResults must be positive")
+	}
+
+	pageReq := *req
+	all := &websearch.SearchAllResponse{}
+	seen := make(map[string]bool)
+
+	for page := 1; ; page++ {
+		if req.RequestID != "" && page > 1 {
+			pageReq.RequestID = fmt.Sprintf("%s-p%d", req.RequestID, page)
+		}
+
+		resp, err := s.Search(ctx, &pageReq)
+		if err != nil {
+			return all, &websearch.PageError{Page: page, RequestID: pageReq.RequestID, Err: err}
+		}
+
+		if page == 1 {
+			all.SearchIntent = resp.SearchIntent
+		}
+
+		info := websearch.PageInfo{
+			Page:      page,
+			RequestID: resp.RequestID,
+			ID:        resp.ID,
+			Results:   len(resp.SearchResult),
+		}
+		for _, result := range resp.SearchResult {
+			if len(all.SearchResult) >= maxResults {
+				break
+			}
+			key := result.Link
+			if key != "" {
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+			all.SearchResult = append(all.SearchResult, result)
+			info.NewResults++
+		}
+		all.Pages = append(all.Pages, info)
+
+		if !resp.HasNextPage() || len(resp.SearchResult) == 0 {
+			return all, nil
+		}
+		if len(all.SearchResult) >= maxResults {
+			all.HasMore = true
+			return all, nil
+		}
+		// Stop if the API is not advancing.
+		if resp.NextPageToken != "" && resp.NextPageToken == pageReq.PageToken {
+			return all, nil
+		}
+		if resp.NextPageToken == "" && info.NewResults == 0 {
+			return all, nil
+		}
+
+		if resp.NextPageToken != "" {
+			pageReq.PageToken = resp.NextPageToken
+		} else {
+			pageReq.PageToken = ""
+			pageReq.Offset += len(resp.SearchResult)
+		}
+	}
+}
+
+// SearchAndRead searches the web and reads the pages of the top topN
+// results with the web reader, returning each result with the full page
+// content, title and publication time. If topN is zero or negative, every
+// result is read.
+//
+// Duplicate links are removed before reading, and pages are read
+// concurrently as in WebReader.ReadBatch, which readerOpts configure; use
+// webreader.WithReadTimeout to bound each read separately from the
+// deadline of ctx. A failed read does not fail the call: the result keeps
+// its search snippet and has Err set. An error is returned only if the
+// search fails or ctx is done.
+//
+// Example:
+//
+//	req := websearch.NewWebSearchRequest("Go 1.25 release notes")
+//
+//	resp, err := client.WebSearch.SearchAndRead(ctx, req, 3,
+//	    webreader.WithReturnFormat(webreader.FormatMarkdown),
+//	    webreader.WithReadTimeout(10*time.Second),
+//	)
+//	if err != nil {
+//	    // Handle error
+//	}
+//
+//	for _, result := range resp.Results {
+//	    fmt.Println(result.Link, len(result.Text()))
+//	}
+func (s *WebSearchService) SearchAndRead(ctx context.Context, req *websearch.WebSearchRequest, topN int, readerOpts ...webreader.Option) (*websearch.EnrichedResponse, error) {
+	if req == nil {
+		return nil, fmt.Errorf("request cannot be nil")
+	}
+
+	search, err := s.Search(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &websearch.EnrichedResponse{Search: search}
+	seen := make(map[string]bool)
+	var urls []string
+	for _, result := range search.SearchResult {
+		if topN > 0 && len(resp.Results) >= topN {
+			break
+		}
+		if result.Link != "" {
+			if seen[result.Link] {
+				continue
+			}
+			seen[result.Link] = true
+			urls = append(urls, result.Link)
+		}
+		resp.Results = append(resp.Results, websearch.EnrichedResult{SearchResultResp: result})
+	}
+
+	// Per-page failures are reported on the results
+	pages, _ := newWebReaderService(s.client).ReadBatch(ctx, urls, readerOpts...)
+	read := make(map[string]*webreader.Result, len(pages))
+	for _, page := range pages {
+		read[page.URL] = page
+	}
+
+	for i := range resp.Results {
+		result := &resp.Results[i]
+		page := read[result.Link]
+		if page == nil {
+			continue
+		}
+		if page.Err != nil {
+			result.Err = page.Err
+			continue
+		}
+		if data := page.Response.GetResult(); data != nil {
+			result.FullContent = data.Content
+			result.PageTitle = data.Title
+			result.PublishedTime = data.PublishedTime
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return resp, err
+	}
+	return resp, nil
+}
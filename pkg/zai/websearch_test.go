@@ -3,10 +3,14 @@ package zai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/webreader"
 	"github.com/sofianhadi1983/zai-sdk-go/api/types/websearch"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -97,7 +101,7 @@ func TestWebSearchService_Search(t *testing.T) {
 	assert.True(t, resp.HasIntent())
 	assert.NotNil(t, resp.SearchIntent)
 	assert.Equal(t, "AI trends 2024", resp.SearchIntent.Query)
-	assert.Equal(t, "informational", resp.SearchIntent.Intent)
+	assert.Equal(t, websearch.IntentType("informational"), resp.SearchIntent.Intent)
 	assert.Equal(t, "AI, trends, 2024, technology", resp.SearchIntent.Keywords)
 
 	// Verify search results
@@ -347,3 +351,276 @@ func TestWebSearchService_Search_APIError(t *testing.T) {
 	_, err = client.WebSearch.Search(context.Background(), req)
 	require.Error(t, err)
 }
+
+func TestWebSearchService_SearchAll_PageToken(t *testing.T) {
+	t.Parallel()
+
+	var requests []websearch.WebSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody websearch.WebSearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		requests = append(requests, reqBody)
+
+		var resp websearch.WebSearchResponse
+		switch reqBody.PageToken {
+		case "":
+			resp = websearch.WebSearchResponse{
+				RequestID:     "srv_1",
+				SearchIntent:  &websearch.SearchIntentResp{Intent: "informational"},
+				SearchResult:  []websearch.SearchResultResp{{Link: "https://a.example"}, {Link: "https://b.example"}},
+				NextPageToken: "tok_2",
+			}
+		case "tok_2":
+			resp = websearch.WebSearchResponse{
+				RequestID:     "srv_2",
+				SearchResult:  []websearch.SearchResultResp{{Link: "https://b.example"}, {Link: "https://c.example"}},
+				NextPageToken: "tok_3",
+			}
+		case "tok_3":
+			resp = websearch.WebSearchResponse{
+				RequestID:    "srv_3",
+				SearchResult: []websearch.SearchResultResp{{Link: "https://d.example"}},
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := websearch.NewWebSearchRequest("golang").SetCount(2).SetRequestID("my_req")
+	resp, err := client.WebSearch.SearchAll(context.Background(), req, 10)
+	require.NoError(t, err)
+
+	require.Len(t, resp.SearchResult, 4)
+	assert.Equal(t, "https://a.example", resp.SearchResult[0].Link)
+	assert.Equal(t, "https://d.example", resp.SearchResult[3].Link)
+	assert.Equal(t, websearch.IntentType("informational"), resp.SearchIntent.Intent)
+	assert.False(t, resp.HasMore)
+	assert.Equal(t, []string{"srv_1", "srv_2", "srv_3"}, resp.RequestIDs())
+	assert.Equal(t, 1, resp.Pages[1].NewResults)
+
+	require.Len(t, requests, 3)
+	assert.Equal(t, "my_req", requests[0].RequestID)
+	assert.Equal(t, "my_req-p2", requests[1].RequestID)
+	assert.Equal(t, "my_req-p3", requests[2].RequestID)
+	assert.Equal(t, "tok_3", requests[2].PageToken)
+
+	// The caller's request is left untouched.
+	assert.Equal(t, "my_req", req.RequestID)
+	assert.Empty(t, req.PageToken)
+}
+
+func TestWebSearchService_SearchAll_OffsetAndLimit(t *testing.T) {
+	t.Parallel()
+
+	var offsets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqBody websearch.WebSearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		offsets = append(offsets, reqBody.Offset)
+
+		resp := websearch.WebSearchResponse{HasMore: true}
+		for i := 0; i < 3; i++ {
+			resp.SearchResult = append(resp.SearchResult, websearch.SearchResultResp{
+				Link: fmt.Sprintf("https://example.com/%d", reqBody.Offset+i),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.WebSearch.SearchAll(context.Background(), websearch.NewWebSearchRequest("golang").SetCount(3), 7)
+	require.NoError(t, err)
+
+	assert.Len(t, resp.SearchResult, 7)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, []int{0, 3, 6}, offsets)
+	assert.Equal(t, "https://example.com/6", resp.SearchResult[6].Link)
+}
+
+func TestWebSearchService_SearchAll_PageError(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "Invalid page token", "code": "1214"}}`))
+			return
+		}
+		json.NewEncoder(w).Encode(websearch.WebSearchResponse{
+			RequestID:     "srv_1",
+			SearchResult:  []websearch.SearchResultResp{{Link: "https://a.example"}},
+			NextPageToken: "tok_2",
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	req := websearch.NewWebSearchRequest("golang").SetRequestID("my_req")
+	resp, err := client.WebSearch.SearchAll(context.Background(), req, 10)
+	require.Error(t, err)
+
+	var pageErr *websearch.PageError
+	require.ErrorAs(t, err, &pageErr)
+	assert.Equal(t, 2, pageErr.Page)
+	assert.Equal(t, "my_req-p2", pageErr.RequestID)
+
+	require.NotNil(t, resp)
+	require.Len(t, resp.SearchResult, 1)
+	assert.Equal(t, "https://a.example", resp.SearchResult[0].Link)
+	assert.Equal(t, []string{"srv_1"}, resp.RequestIDs())
+}
+
+func TestWebSearchService_SearchAll_InvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	client, err := NewClient(WithAPIKey("test-key.test-secret"))
+	require.NoError(t, err)
+
+	_, err = client.WebSearch.SearchAll(context.Background(), nil, 10)
+	assert.Error(t, err)
+
+	_, err = client.WebSearch.SearchAll(context.Background(), websearch.NewWebSearchRequest("q"), 0)
+	assert.Error(t, err)
+}
+
+func TestWebSearchService_SearchAndRead(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	reads := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.URL.Path == "/web_search" {
+			json.NewEncoder(w).Encode(websearch.WebSearchResponse{
+				SearchResult: []websearch.SearchResultResp{
+					{Title: "A", Link: "https://a.example", Content: "snippet a"},
+					{Title: "A again", Link: "https://a.example", Content: "snippet a2"},
+					{Title: "Bad", Link: "https://bad.example", Content: "snippet bad"},
+					{Title: "Slow", Link: "https://slow.example", Content: "snippet slow"},
+					{Title: "D", Link: "https://d.example", Content: "snippet d"},
+				},
+			})
+			return
+		}
+
+		var req webreader.Request
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, webreader.FormatMarkdown, req.ReturnFormat)
+		mu.Lock()
+		reads[req.URL]++
+		mu.Unlock()
+
+		switch req.URL {
+		case "https://bad.example":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": {"message": "URL cannot be read", "code": "1214"}}`))
+		case "https://slow.example":
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+		default:
+			json.NewEncoder(w).Encode(webreader.Response{
+				ReaderResult: &webreader.ReaderData{
+					Title:         "Page " + req.URL,
+					Content:       "full content of " + req.URL,
+					PublishedTime: "2026-01-02",
+				},
+			})
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.WebSearch.SearchAndRead(context.Background(), websearch.NewWebSearchRequest("query"), 3,
+		webreader.WithReturnFormat("markdown"),
+		webreader.WithReadTimeout(200*time.Millisecond),
+	)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	require.Len(t, resp.Search.SearchResult, 5)
+	require.Len(t, resp.Results, 3, "duplicate links are dropped before taking the top results")
+
+	a := resp.Results[0]
+	assert.True(t, a.OK())
+	assert.Equal(t, "A", a.Title)
+	assert.Equal(t, "snippet a", a.Content)
+	assert.Equal(t, "full content of https://a.example", a.FullContent)
+	assert.Equal(t, "Page https://a.example", a.PageTitle)
+	assert.Equal(t, "2026-01-02", a.PublishedTime)
+	assert.Equal(t, a.FullContent, a.Text())
+
+	bad := resp.Results[1]
+	assert.False(t, bad.OK())
+	assert.Error(t, bad.Err)
+	assert.Empty(t, bad.FullContent)
+	assert.Equal(t, "snippet bad", bad.Text())
+
+	slow := resp.Results[2]
+	assert.ErrorIs(t, slow.Err, context.DeadlineExceeded)
+	assert.Equal(t, "snippet slow", slow.Text())
+
+	assert.Len(t, resp.Failed(), 2)
+	assert.Equal(t, map[string]int{
+		"https://a.example":    1,
+		"https://bad.example":  1,
+		"https://slow.example": 1,
+	}, reads)
+}
+
+func TestWebSearchService_SearchAndRead_SearchError(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/web_search", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": {"message": "Invalid query", "code": "1214"}}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(
+		WithAPIKey("test-key.test-secret"),
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.WebSearch.SearchAndRead(context.Background(), websearch.NewWebSearchRequest("query"), 3)
+	require.Error(t, err)
+	assert.Nil(t, resp)
+
+	_, err = client.WebSearch.SearchAndRead(context.Background(), nil, 3)
+	assert.Error(t, err)
+}
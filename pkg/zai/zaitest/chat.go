@@ -0,0 +1,221 @@
+package zaitest
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// ErrUnscripted is returned by a fake called more times than scripted.
+var ErrUnscripted = errors.New("zaitest: no scripted response left")
+
+var (
+	_ zai.ChatAPI    = (*FakeChat)(nil)
+	_ zai.ChatStream = (*FakeStream)(nil)
+)
+
+// FakeChat is a zai.ChatAPI whose responses are scripted in advance. Each
+// call of Create returns the next scripted response or error, and each
+// call of CreateStream the next scripted chunk sequence, in the order
+// they were added. It records the requests and is safe for concurrent
+// use.
+//
+// Example:
+//
+//	fake := zaitest.NewFakeChat().
+//	    AddReply("Paris").
+//	    AddStreamReply("The capital ", "is Paris.").
+//	    AddError(context.DeadlineExceeded)
+type FakeChat struct {
+	mu        sync.Mutex
+	responses []reply
+	streams   []streamScript
+	requests  []*chat.ChatCompletionRequest
+	opened    []*FakeStream
+}
+
+// reply is a scripted result of Create.
+type reply struct {
+	resp *chat.ChatCompletionResponse
+	err  error
+}
+
+// streamScript is a scripted result of CreateStream.
+type streamScript struct {
+	chunks []*chat.ChatCompletionChunk
+	err    error
+}
+
+// NewFakeChat creates a FakeChat with nothing scripted.
+func NewFakeChat() *FakeChat {
+	return &FakeChat{}
+}
+
+// AddResponse scripts the next response of Create.
+func (f *FakeChat) AddResponse(resp *chat.ChatCompletionResponse) *FakeChat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, reply{resp: resp})
+	return f
+}
+
+// AddReply scripts the next response of Create as an assistant message
+// with content, see ChatCompletion.
+func (f *FakeChat) AddReply(content string) *FakeChat {
+	return f.AddResponse(ChatCompletion(content))
+}
+
+// AddError scripts the next call of Create to fail with err.
+func (f *FakeChat) AddError(err error) *FakeChat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.responses = append(f.responses, reply{err: err})
+	return f
+}
+
+// AddStream scripts the chunks of the next stream of CreateStream.
+func (f *FakeChat) AddStream(chunks ...*chat.ChatCompletionChunk) *FakeChat {
+	return f.AddStreamError(nil, chunks...)
+}
+
+// AddStreamReply scripts the next stream of CreateStream as the content
+// parts of an assistant message, see ChatChunks.
+func (f *FakeChat) AddStreamReply(parts ...string) *FakeChat {
+	return f.AddStream(ChatChunks(parts...)...)
+}
+
+// AddStreamError scripts the next stream of CreateStream to yield chunks
+// and then fail with err, as when the connection drops. Without chunks,
+// CreateStream itself returns err, as when the request is rejected.
+func (f *FakeChat) AddStreamError(err error, chunks ...*chat.ChatCompletionChunk) *FakeChat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streams = append(f.streams, streamScript{chunks: chunks, err: err})
+	return f
+}
+
+// Create returns the next scripted response, or ErrUnscripted if none
+// is left.
+func (f *FakeChat) Create(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.CreateOption) (*chat.ChatCompletionResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if len(f.responses) == 0 {
+		return nil, ErrUnscripted
+	}
+	next := f.responses[0]
+	f.responses = f.responses[1:]
+	return next.resp, next.err
+}
+
+// CreateStream returns a FakeStream of the next scripted chunks, or
+// ErrUnscripted if none are left.
+func (f *FakeChat) CreateStream(ctx context.Context, req *chat.ChatCompletionRequest, opts ...chat.StreamOption) (zai.ChatStream, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if len(f.streams) == 0 {
+		return nil, ErrUnscripted
+	}
+	next := f.streams[0]
+	f.streams = f.streams[1:]
+	if len(next.chunks) == 0 && next.err != nil {
+		return nil, next.err
+	}
+
+	stream := NewFakeStream(next.err, next.chunks...)
+	f.opened = append(f.opened, stream)
+	return stream, nil
+}
+
+// Requests returns the requests of the calls of Create and CreateStream,
+// in order.
+func (f *FakeChat) Requests() []*chat.ChatCompletionRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*chat.ChatCompletionRequest(nil), f.requests...)
+}
+
+// Streams returns the streams returned by CreateStream, in order, to
+// check for example that they were closed.
+func (f *FakeChat) Streams() []*FakeStream {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*FakeStream(nil), f.opened...)
+}
+
+// Remaining returns the number of scripted responses and streams not
+// yet returned.
+func (f *FakeChat) Remaining() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.responses) + len(f.streams)
+}
+
+// FakeStream is a zai.ChatStream that yields a fixed sequence of chunks.
+// Like the streams of the SDK, it is not safe for concurrent use.
+type FakeStream struct {
+	chunks  []*chat.ChatCompletionChunk
+	err     error
+	next    int
+	current *chat.ChatCompletionChunk
+	failed  bool
+	closed  bool
+}
+
+// NewFakeStream creates a stream that yields chunks and then ends with
+// err, or successfully if err is nil.
+func NewFakeStream(err error, chunks ...*chat.ChatCompletionChunk) *FakeStream {
+	return &FakeStream{chunks: chunks, err: err}
+}
+
+// Next advances to the next chunk. It returns false once the chunks are
+// exhausted or the stream is closed.
+func (s *FakeStream) Next() bool {
+	if s.closed || s.failed {
+		return false
+	}
+	if s.next == len(s.chunks) {
+		s.failed = s.err != nil
+		s.current = nil
+		return false
+	}
+	s.current = s.chunks[s.next]
+	s.next++
+	return true
+}
+
+// Current returns the current chunk.
+func (s *FakeStream) Current() *chat.ChatCompletionChunk {
+	return s.current
+}
+
+// Err returns the scripted error once every chunk has been read.
+func (s *FakeStream) Err() error {
+	if s.failed {
+		return s.err
+	}
+	return nil
+}
+
+// Close closes the stream.
+func (s *FakeStream) Close() error {
+	s.closed = true
+	return nil
+}
+
+// Closed reports whether Close was called.
+func (s *FakeStream) Closed() bool {
+	return s.closed
+}
@@ -0,0 +1,140 @@
+package zaitest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// collect is code under test: it streams a reply and returns its content.
+func collect(ctx context.Context, api zai.ChatAPI, question string) (string, error) {
+	stream, err := api.CreateStream(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage(question)}))
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for stream.Next() {
+		content.WriteString(stream.Current().GetContent())
+	}
+	return content.String(), stream.Err()
+}
+
+func TestFakeChat_Create(t *testing.T) {
+	t.Parallel()
+
+	errRejected := errors.New("rejected")
+	fake := NewFakeChat().AddReply("Paris").AddError(errRejected)
+	ctx := context.Background()
+	req := chat.NewChatCompletionRequest("glm-4.7", []chat.Message{chat.NewUserMessage("Capital of France?")})
+
+	resp, err := fake.Create(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Paris", resp.GetContent())
+	assert.Equal(t, chat.FinishReasonStop, resp.Choices[0].FinishReason)
+
+	_, err = fake.Create(ctx, req)
+	assert.ErrorIs(t, err, errRejected)
+
+	_, err = fake.Create(ctx, req)
+	assert.ErrorIs(t, err, ErrUnscripted)
+
+	assert.Len(t, fake.Requests(), 3)
+	assert.Same(t, req, fake.Requests()[0])
+	assert.Zero(t, fake.Remaining())
+}
+
+func TestFakeChat_CreateStream(t *testing.T) {
+	t.Parallel()
+
+	errDropped := errors.New("connection reset")
+	errRejected := errors.New("rejected")
+	fake := NewFakeChat().
+		AddStreamReply("Hello", ", ", "world").
+		AddStreamError(errDropped, ChatChunks("Hel")...).
+		AddStreamError(errRejected)
+	ctx := context.Background()
+
+	got, err := collect(ctx, fake, "Hi")
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, world", got)
+
+	got, err = collect(ctx, fake, "Hi")
+	assert.ErrorIs(t, err, errDropped)
+	assert.Equal(t, "Hel", got, "the chunks before the error are read")
+
+	_, err = collect(ctx, fake, "Hi")
+	assert.ErrorIs(t, err, errRejected)
+
+	_, err = collect(ctx, fake, "Hi")
+	assert.ErrorIs(t, err, ErrUnscripted)
+
+	streams := fake.Streams()
+	require.Len(t, streams, 2, "rejected requests open no stream")
+	for _, stream := range streams {
+		assert.True(t, stream.Closed())
+	}
+	assert.Len(t, fake.Requests(), 4)
+}
+
+func TestFakeChat_Canceled(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeChat().AddReply("unused").AddStreamReply("unused")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := fake.Create(ctx, &chat.ChatCompletionRequest{})
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = fake.CreateStream(ctx, &chat.ChatCompletionRequest{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, fake.Remaining())
+}
+
+func TestFakeChat_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeChat()
+	for range 50 {
+		fake.AddReply("ok")
+	}
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			resp, err := fake.Create(context.Background(), &chat.ChatCompletionRequest{})
+			if assert.NoError(t, err) {
+				assert.Equal(t, "ok", resp.GetContent())
+			}
+		})
+	}
+	wg.Wait()
+	assert.Len(t, fake.Requests(), 50)
+}
+
+func TestFakeStream(t *testing.T) {
+	t.Parallel()
+
+	stream := NewFakeStream(nil, ChatChunks("a", "b")...)
+	require.True(t, stream.Next())
+	assert.Equal(t, "a", stream.Current().GetContent())
+	require.True(t, stream.Next())
+	assert.Equal(t, "b", stream.Current().GetContent())
+	require.NoError(t, stream.Close())
+	assert.False(t, stream.Next(), "a closed stream ends")
+	assert.NoError(t, stream.Err())
+
+	empty := NewFakeStream(nil)
+	assert.False(t, empty.Next())
+	assert.Nil(t, empty.Current())
+	assert.NoError(t, empty.Err())
+}
@@ -0,0 +1,44 @@
+package zaitest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+// TestAPIKey is the API key used by NewTestClient.
+const TestAPIKey = "zaitest-key.zaitest-secret"
+
+// NewTestClient starts a fake server and returns a client wired to it.
+// Retries back off for a millisecond so error scenarios run quickly.
+// Both are closed when the test ends. Options are applied after the
+// defaults and can override them.
+func NewTestClient(t testing.TB, opts ...zai.ClientOption) (*zai.Client, *Server) {
+	t.Helper()
+
+	server := NewServer(t)
+	return server.NewClient(t, opts...), server
+}
+
+// NewClient returns a client wired to the server that is closed when the
+// test ends.
+func (s *Server) NewClient(t testing.TB, opts ...zai.ClientOption) *zai.Client {
+	t.Helper()
+
+	defaults := []zai.ClientOption{
+		zai.WithAPIKey(TestAPIKey),
+		zai.WithBaseURL(s.URL),
+		zai.WithDefaultRetryPolicy(zai.RetryPolicy{
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+		}),
+	}
+
+	client, err := zai.NewClient(append(defaults, opts...)...)
+	if err != nil {
+		t.Fatalf("zaitest: cannot create client: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
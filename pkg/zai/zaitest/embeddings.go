@@ -0,0 +1,164 @@
+package zaitest
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+)
+
+var _ zai.EmbeddingsAPI = (*FakeEmbeddings)(nil)
+
+// DefaultDimensions is the number of dimensions of the vectors of a
+// FakeEmbeddings created with zero dimensions.
+const DefaultDimensions = 8
+
+// FakeEmbeddings is a zai.EmbeddingsAPI that embeds each text as the
+// vector set with SetVector or, by default, as Vector of the text, so the
+// same text always gets the same vector. It records the requests and is
+// safe for concurrent use.
+//
+// Example:
+//
+//	fake := zaitest.NewFakeEmbeddings(3).SetVector("cat", []float64{1, 0, 0})
+//
+//	vector, err := fake.Embed(ctx, "cat") // [1 0 0]
+type FakeEmbeddings struct {
+	dimensions int
+
+	mu       sync.Mutex
+	vectors  map[string][]float64
+	err      error
+	requests []*embeddings.EmbeddingRequest
+}
+
+// NewFakeEmbeddings creates a FakeEmbeddings of vectors of dimensions, or
+// DefaultDimensions if zero.
+func NewFakeEmbeddings(dimensions int) *FakeEmbeddings {
+	if dimensions <= 0 {
+		dimensions = DefaultDimensions
+	}
+	return &FakeEmbeddings{dimensions: dimensions, vectors: make(map[string][]float64)}
+}
+
+// SetVector sets the embedding of text.
+func (f *FakeEmbeddings) SetVector(text string, vector []float64) *FakeEmbeddings {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.vectors[text] = vector
+	return f
+}
+
+// SetError makes every following call fail with err, or succeed again if
+// err is nil.
+func (f *FakeEmbeddings) SetError(err error) *FakeEmbeddings {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+	return f
+}
+
+// Create embeds the input of req, a string or a slice of strings, in
+// order.
+func (f *FakeEmbeddings) Create(ctx context.Context, req *embeddings.EmbeddingRequest) (*embeddings.EmbeddingResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, req)
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	var texts []string
+	switch input := req.Input.(type) {
+	case string:
+		texts = []string{input}
+	case []string:
+		texts = input
+	case []any:
+		for _, item := range input {
+			text, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("zaitest: unsupported input element %T", item)
+			}
+			texts = append(texts, text)
+		}
+	default:
+		return nil, fmt.Errorf("zaitest: unsupported input %T", req.Input)
+	}
+
+	resp := &embeddings.EmbeddingResponse{Object: "list", Model: req.Model}
+	for i, text := range texts {
+		vector, ok := f.vectors[text]
+		if !ok {
+			vector = Vector(text, f.dimensions)
+		}
+		resp.Data = append(resp.Data, embeddings.Embedding{Object: "embedding", Embedding: vector, Index: i})
+	}
+	return resp, nil
+}
+
+// CreateSingle embeds text.
+func (f *FakeEmbeddings) CreateSingle(ctx context.Context, model, text string) ([]float64, error) {
+	resp, err := f.Create(ctx, embeddings.NewEmbeddingRequest(model, text))
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetFirstEmbedding().GetFloatEmbedding(), nil
+}
+
+// CreateBatch embeds texts.
+func (f *FakeEmbeddings) CreateBatch(ctx context.Context, model string, texts []string) ([][]float64, error) {
+	resp, err := f.Create(ctx, embeddings.NewBatchEmbeddingRequest(model, texts))
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetFloatEmbeddings(), nil
+}
+
+// Embed embeds text with an empty model.
+func (f *FakeEmbeddings) Embed(ctx context.Context, text string) ([]float64, error) {
+	return f.CreateSingle(ctx, "", text)
+}
+
+// EmbedBatch embeds texts with an empty model.
+func (f *FakeEmbeddings) EmbedBatch(ctx context.Context, texts []string) ([][]float64, error) {
+	return f.CreateBatch(ctx, "", texts)
+}
+
+// Requests returns the requests of the calls, in order.
+func (f *FakeEmbeddings) Requests() []*embeddings.EmbeddingRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*embeddings.EmbeddingRequest(nil), f.requests...)
+}
+
+// Vector returns a unit vector of dimensions derived from a hash of text:
+// equal texts get equal vectors, and different texts almost surely
+// different ones.
+func Vector(text string, dimensions int) []float64 {
+	h := fnv.New64a()
+	h.Write([]byte(text))
+	seed := h.Sum64()
+	r := rand.New(rand.NewPCG(seed, seed))
+
+	vector := make([]float64, dimensions)
+	var norm float64
+	for i := range vector {
+		vector[i] = r.Float64()*2 - 1
+		norm += vector[i] * vector[i]
+	}
+	norm = math.Sqrt(norm)
+	for i := range vector {
+		vector[i] /= norm
+	}
+	return vector
+}
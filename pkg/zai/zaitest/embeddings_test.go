@@ -0,0 +1,65 @@
+package zaitest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+)
+
+func TestFakeEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	fake := NewFakeEmbeddings(3).SetVector("cat", []float64{1, 0, 0})
+	ctx := context.Background()
+
+	vector, err := fake.Embed(ctx, "cat")
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 0, 0}, vector)
+
+	vectors, err := fake.CreateBatch(ctx, "embedding-3", []string{"dog", "cat", "dog"})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	assert.Equal(t, Vector("dog", 3), vectors[0])
+	assert.Equal(t, vectors[0], vectors[2], "equal texts get equal vectors")
+	assert.NotEqual(t, vectors[0], Vector("dogs", 3))
+
+	resp, err := fake.Create(ctx, &embeddings.EmbeddingRequest{Model: "embedding-3", Input: []any{"a", "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "embedding-3", resp.Model)
+	assert.Equal(t, 1, resp.Data[1].Index)
+
+	_, err = fake.Create(ctx, &embeddings.EmbeddingRequest{Input: 42})
+	assert.ErrorContains(t, err, "unsupported input int")
+
+	errQuota := errors.New("quota exceeded")
+	fake.SetError(errQuota)
+	_, err = fake.EmbedBatch(ctx, []string{"x"})
+	assert.ErrorIs(t, err, errQuota)
+
+	requests := fake.Requests()
+	require.Len(t, requests, 5)
+	assert.Equal(t, "embedding-3", requests[1].Model)
+}
+
+func TestVector(t *testing.T) {
+	t.Parallel()
+
+	vector := Vector("hello", 16)
+	require.Len(t, vector, 16)
+
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	assert.InDelta(t, 1, math.Sqrt(norm), 1e-9)
+
+	vectors, err := NewFakeEmbeddings(0).EmbedBatch(context.Background(), []string{"hello"})
+	require.NoError(t, err)
+	assert.Equal(t, Vector("hello", DefaultDimensions), vectors[0])
+}
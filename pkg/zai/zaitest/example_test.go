@@ -0,0 +1,59 @@
+package zaitest_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai/zaitest"
+)
+
+// shout is the code under test. It takes a zai.ChatAPI, so production
+// code passes client.ChatAPI() and tests a FakeChat.
+func shout(ctx context.Context, api zai.ChatAPI, question string) (string, error) {
+	stream, err := api.CreateStream(ctx, chat.NewChatCompletionRequest("glm-4.7", []chat.Message{
+		chat.NewUserMessage(question),
+	}))
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	var answer strings.Builder
+	for stream.Next() {
+		answer.WriteString(strings.ToUpper(stream.Current().GetContent()))
+	}
+	return answer.String(), stream.Err()
+}
+
+func ExampleFakeChat() {
+	fake := zaitest.NewFakeChat().AddStreamReply("The capital ", "is Paris.")
+
+	answer, err := shout(context.Background(), fake, "What is the capital of France?")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(answer)
+	fmt.Println(fake.Requests()[0].Messages[0].Text())
+	fmt.Println(fake.Streams()[0].Closed())
+	// Output:
+	// THE CAPITAL IS PARIS.
+	// What is the capital of France?
+	// true
+}
+
+func ExampleFakeEmbeddings() {
+	fake := zaitest.NewFakeEmbeddings(3).SetVector("cat", []float64{1, 0, 0})
+
+	vector, err := fake.Embed(context.Background(), "cat")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Println(vector)
+	// Output:
+	// [1 0 0]
+}
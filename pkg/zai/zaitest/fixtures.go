@@ -0,0 +1,179 @@
+package zaitest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/sofianhadi1983/zai-sdk-go/internal/models"
+)
+
+// API paths served by the fake server.
+const (
+	PathChatCompletions = "/chat/completions"
+	PathEmbeddings      = "/embeddings"
+	PathBatches         = "/batches"
+	PathAsyncResult     = "/async-result"
+)
+
+// DefaultModel is the model reported by canned responses.
+const DefaultModel = "glm-4.7"
+
+// ChatCompletion returns a completed chat response with the given assistant content.
+func ChatCompletion(content string) *chat.ChatCompletionResponse {
+	return &chat.ChatCompletionResponse{
+		ID:      "chatcmpl-zaitest",
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   DefaultModel,
+		Choices: []chat.Choice{{
+			Index:        0,
+			Message:      chat.NewAssistantMessage(content),
+			FinishReason: chat.FinishReasonStop,
+		}},
+		Usage: &models.Usage{
+			PromptTokens:     10,
+			CompletionTokens: len(content),
+			TotalTokens:      10 + len(content),
+		},
+	}
+}
+
+// ChatChunks returns stream chunks that deliver parts as content deltas,
+// followed by a final chunk with finish reason "stop".
+func ChatChunks(parts ...string) []*chat.ChatCompletionChunk {
+	chunks := make([]*chat.ChatCompletionChunk, 0, len(parts)+1)
+	for i, part := range parts {
+		delta := chat.Delta{Content: part}
+		if i == 0 {
+			delta.Role = chat.RoleAssistant
+		}
+		chunks = append(chunks, newChunk(chat.ChunkChoice{Delta: delta}))
+	}
+	return append(chunks, newChunk(chat.ChunkChoice{FinishReason: chat.FinishReasonStop}))
+}
+
+// ToolCallChunks returns stream chunks that deliver calls as tool call
+// deltas, as the API streams them: the ID and function name first, then
+// the arguments in two fragments. A final chunk has finish reason
+// "tool_calls".
+func ToolCallChunks(calls ...chat.ToolCall) []*chat.ChatCompletionChunk {
+	chunks := make([]*chat.ChatCompletionChunk, 0, 3*len(calls)+1)
+	for i, call := range calls {
+		index := i
+		half := len(call.Function.Arguments) / 2
+		fragments := []chat.ToolCall{
+			{ID: call.ID, Type: "function", Function: chat.FunctionCall{Name: call.Function.Name}},
+			{Function: chat.FunctionCall{Arguments: call.Function.Arguments[:half]}},
+			{Function: chat.FunctionCall{Arguments: call.Function.Arguments[half:]}},
+		}
+		for _, fragment := range fragments {
+			fragment.Index = &index
+			delta := chat.Delta{ToolCalls: []chat.ToolCall{fragment}}
+			if len(chunks) == 0 {
+				delta.Role = chat.RoleAssistant
+			}
+			chunks = append(chunks, newChunk(chat.ChunkChoice{Delta: delta}))
+		}
+	}
+	return append(chunks, newChunk(chat.ChunkChoice{FinishReason: chat.FinishReasonToolCalls}))
+}
+
+// newChunk creates a stream chunk with a single choice.
+func newChunk(choice chat.ChunkChoice) *chat.ChatCompletionChunk {
+	return &chat.ChatCompletionChunk{
+		ID:      "chatcmpl-zaitest",
+		Object:  "chat.completion.chunk",
+		Created: time.Now().Unix(),
+		Model:   DefaultModel,
+		Choices: []chat.ChunkChoice{choice},
+	}
+}
+
+// EnqueueChat queues a chat completion response.
+func (s *Server) EnqueueChat(resp *chat.ChatCompletionResponse) *Server {
+	return s.Enqueue(http.MethodPost, PathChatCompletions, JSON(http.StatusOK, resp))
+}
+
+// EnqueueChatStream queues a streaming chat completion made of chunks.
+//
+// Example:
+//
+//	server.EnqueueChatStream(zaitest.ChatChunks("Hel", "lo")...)
+func (s *Server) EnqueueChatStream(chunks ...*chat.ChatCompletionChunk) *Server {
+	events := make([]interface{}, len(chunks))
+	for i, c := range chunks {
+		events[i] = c
+	}
+	return s.Enqueue(http.MethodPost, PathChatCompletions, Stream(events...))
+}
+
+// EnqueueEmbeddings queues an embeddings response with one embedding per vector.
+func (s *Server) EnqueueEmbeddings(vectors ...[]float64) *Server {
+	resp := &embeddings.EmbeddingResponse{
+		Object: "list",
+		Model:  "embedding-3",
+		Data:   make([]embeddings.Embedding, len(vectors)),
+		Usage:  &models.Usage{PromptTokens: len(vectors), TotalTokens: len(vectors)},
+	}
+	for i, v := range vectors {
+		resp.Data[i] = embeddings.Embedding{Object: "embedding", Embedding: v, Index: i}
+	}
+	return s.Enqueue(http.MethodPost, PathEmbeddings, JSON(http.StatusOK, resp))
+}
+
+// EnqueueBatchLifecycle queues one Batches.Retrieve response per status for
+// batchID, so that polling observes the statuses in order. A completed
+// batch reports an output file.
+//
+// Example:
+//
+//	server.EnqueueBatchLifecycle("batch_1",
+//	    batch.StatusValidating, batch.StatusInProgress, batch.StatusCompleted)
+func (s *Server) EnqueueBatchLifecycle(batchID string, statuses ...string) *Server {
+	path := PathBatches + "/" + batchID
+	for _, status := range statuses {
+		b := &batch.Batch{
+			ID:               batchID,
+			Object:           "batch",
+			Status:           status,
+			CompletionWindow: "24h",
+			Endpoint:         batch.EndpointChatCompletions,
+			InputFileID:      "file-zaitest-input",
+			CreatedAt:        time.Now().Unix(),
+		}
+		if status == batch.StatusCompleted {
+			b.OutputFileID = "file-zaitest-output"
+		}
+		s.Enqueue(http.MethodGet, path, JSON(http.StatusOK, b))
+	}
+	return s
+}
+
+// EnqueueAsyncResult queues one async task result per status for taskID,
+// for scripting "processing, processing, completed" polling scenarios.
+//
+// Example:
+//
+//	server.EnqueueAsyncResult("task_1",
+//	    videos.StatusProcessing, videos.StatusProcessing, videos.StatusCompleted)
+func (s *Server) EnqueueAsyncResult(taskID string, statuses ...videos.TaskStatus) *Server {
+	path := PathAsyncResult + "/" + taskID
+	for _, status := range statuses {
+		result := &videos.VideoResult{
+			TaskID:     taskID,
+			TaskStatus: status,
+		}
+		switch status {
+		case videos.StatusCompleted:
+			result.VideoResult = []videos.VideoData{{URL: "https://example.com/" + taskID + ".mp4"}}
+		case videos.StatusFailed:
+			result.ErrorMessage = "zaitest: task failed"
+		}
+		s.Enqueue(http.MethodGet, path, JSON(http.StatusOK, result))
+	}
+	return s
+}
@@ -0,0 +1,121 @@
+package zaitest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/batch"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/videos"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_EnqueueChatStream(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueChatStream(ChatChunks("Hel", "lo")...)
+
+	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")})
+	stream, err := client.Chat.CreateStream(context.Background(), req)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	var content string
+	var finish chat.FinishReason
+	for stream.Next() {
+		chunk := stream.Current()
+		content += chunk.GetContent()
+		if len(chunk.Choices) > 0 && chunk.Choices[0].FinishReason != "" {
+			finish = chunk.Choices[0].FinishReason
+		}
+	}
+	require.NoError(t, stream.Err())
+	assert.Equal(t, "Hello", content)
+	assert.Equal(t, chat.FinishReasonStop, finish)
+}
+
+func TestServer_EnqueueToolCallStream(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueChatStream(ToolCallChunks(
+		chat.ToolCall{ID: "call_1", Function: chat.FunctionCall{Name: "get_weather", Arguments: `{"location":"Paris"}`}},
+		chat.ToolCall{ID: "call_2", Function: chat.FunctionCall{Name: "get_time", Arguments: `{"zone":"CET"}`}},
+	)...)
+
+	req := chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")})
+	stream, err := client.Chat.CreateStream(context.Background(), req)
+	require.NoError(t, err)
+	collector := chat.NewToolCallCollector(stream)
+	defer collector.Close()
+
+	var names []string
+	for collector.Next() {
+		if tc := collector.Current().ToolCall; tc != nil {
+			names = append(names, tc.Function.Name)
+		}
+	}
+	require.NoError(t, collector.Err())
+	assert.Equal(t, []string{"get_weather", "get_time"}, names)
+	assert.Equal(t, `{"location":"Paris"}`, collector.ToolCalls()[0].Function.Arguments)
+	assert.Equal(t, `{"zone":"CET"}`, collector.ToolCalls()[1].Function.Arguments)
+}
+
+func TestServer_EnqueueEmbeddings(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueEmbeddings([]float64{0.1, 0.2}, []float64{0.3, 0.4})
+
+	resp, err := client.Embeddings.Create(context.Background(),
+		embeddings.NewBatchEmbeddingRequest(embeddings.ModelEmbedding3, []string{"a", "b"}))
+	require.NoError(t, err)
+
+	require.Len(t, resp.Data, 2)
+	assert.Equal(t, []float64{0.3, 0.4}, resp.Data[1].GetFloatEmbedding())
+}
+
+func TestServer_EnqueueBatchLifecycle(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueBatchLifecycle("batch_1", batch.StatusValidating, batch.StatusInProgress, batch.StatusCompleted)
+
+	var statuses []string
+	for {
+		b, err := client.Batch.Retrieve(context.Background(), "batch_1")
+		require.NoError(t, err)
+		statuses = append(statuses, b.Status)
+		if b.Status == batch.StatusCompleted {
+			assert.NotEmpty(t, b.OutputFileID)
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"validating", "in_progress", "completed"}, statuses)
+}
+
+func TestServer_EnqueueAsyncResult(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueAsyncResult("task_1", videos.StatusProcessing, videos.StatusProcessing, videos.StatusCompleted)
+
+	polls := 0
+	for {
+		polls++
+		result, err := client.Videos.Retrieve(context.Background(), "task_1")
+		require.NoError(t, err)
+		if result.IsCompleted() {
+			assert.Equal(t, "https://example.com/task_1.mp4", result.GetVideoURL())
+			break
+		}
+		assert.True(t, result.IsProcessing())
+	}
+
+	assert.Equal(t, 3, polls)
+	assert.Zero(t, server.Pending())
+}
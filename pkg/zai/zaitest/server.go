@@ -0,0 +1,334 @@
+// Package zaitest provides a fake Z.ai API server for unit testing code
+// that uses the SDK.
+//
+// Tests enqueue canned responses per endpoint; each request consumes the
+// next response queued for its method and path. Requests are recorded so
+// tests can assert on the model, messages and headers that were sent.
+//
+// Example:
+//
+//	func TestSummarize(t *testing.T) {
+//	    client, server := zaitest.NewTestClient(t)
+//	    server.EnqueueChat(zaitest.ChatCompletion("a short summary"))
+//
+//	    got, err := Summarize(context.Background(), client, "long text")
+//	    require.NoError(t, err)
+//	    assert.Equal(t, "a short summary", got)
+//
+//	    req, _ := server.LastRequest().ChatRequest()
+//	    assert.Equal(t, "glm-4.7", req.Model)
+//	}
+//
+// Code that depends on the zai.ChatAPI, zai.ChatStream or
+// zai.EmbeddingsAPI interfaces instead of *zai.Client can be tested
+// without a server, with the scripted fakes FakeChat, FakeStream and
+// FakeEmbeddings:
+//
+//	fake := zaitest.NewFakeChat().AddStreamReply("Hello", ", world")
+//
+//	got, err := Collect(ctx, fake) // takes a zai.ChatAPI
+//	require.NoError(t, err)
+//	assert.Equal(t, "Hello, world", got)
+package zaitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+)
+
+// Request is a request received by the fake server.
+type Request struct {
+	// Method is the HTTP method.
+	Method string
+
+	// Path is the URL path, without the base URL.
+	Path string
+
+	// Query is the parsed query string.
+	Query url.Values
+
+	// Header is the request headers.
+	Header http.Header
+
+	// Body is the raw request body.
+	Body []byte
+}
+
+// Decode unmarshals the JSON request body into v.
+func (r *Request) Decode(v interface{}) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+// ChatRequest decodes the body as a chat completion request.
+func (r *Request) ChatRequest() (*chat.ChatCompletionRequest, error) {
+	var req chat.ChatCompletionRequest
+	if err := r.Decode(&req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// Model returns the "model" field of the JSON body, if any.
+func (r *Request) Model() string {
+	var body struct {
+		Model string `json:"model"`
+	}
+	_ = r.Decode(&body)
+	return body.Model
+}
+
+// Response is a canned response served by the fake server.
+type Response struct {
+	// Status is the HTTP status code. Zero means 200.
+	Status int
+
+	// Header is added to the response headers.
+	Header http.Header
+
+	// Body is the raw response body.
+	Body []byte
+
+	// Events, if non-nil, are written as server-sent events followed by
+	// "data: [DONE]" instead of Body.
+	Events [][]byte
+
+	// Delay is how long to wait before responding.
+	Delay time.Duration
+}
+
+// JSON returns a response with v encoded as the JSON body.
+// It panics if v cannot be encoded.
+func JSON(status int, v interface{}) *Response {
+	body, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("zaitest: cannot encode response: %v", err))
+	}
+	return &Response{
+		Status: status,
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body:   body,
+	}
+}
+
+// Error returns an API error response in the Z.ai error format.
+func Error(status int, code, message string) *Response {
+	return JSON(status, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+		},
+	})
+}
+
+// RateLimited returns a 429 response with a Retry-After header.
+func RateLimited(retryAfter time.Duration) *Response {
+	resp := Error(http.StatusTooManyRequests, "1302", "rate limit exceeded")
+	resp.Header.Set("Retry-After", strconv.Itoa(int(retryAfter.Round(time.Second)/time.Second)))
+	return resp
+}
+
+// ServerError returns a 500 response.
+func ServerError() *Response {
+	return Error(http.StatusInternalServerError, "500", "internal server error")
+}
+
+// Malformed returns a 200 response whose JSON body is truncated.
+func Malformed() *Response {
+	return &Response{
+		Header: http.Header{"Content-Type": []string{"application/json"}},
+		Body:   []byte(`{"id": "malformed", "choices": [`),
+	}
+}
+
+// Stream returns a server-sent event response with each event encoded as JSON.
+// It panics if an event cannot be encoded.
+func Stream(events ...interface{}) *Response {
+	resp := &Response{
+		Header: http.Header{"Content-Type": []string{"text/event-stream"}},
+		Events: make([][]byte, 0, len(events)),
+	}
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			panic(fmt.Sprintf("zaitest: cannot encode event: %v", err))
+		}
+		resp.Events = append(resp.Events, data)
+	}
+	return resp
+}
+
+// Server is a fake Z.ai API server. It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server.
+	URL string
+
+	t      testing.TB
+	server *httptest.Server
+
+	mu       sync.Mutex
+	queues   map[string][]*Response
+	requests []*Request
+	hooks    []func(*Request)
+}
+
+// NewServer starts a fake server that is closed when the test ends.
+// Unexpected requests fail the test and receive a 404 error response.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:      t,
+		queues: make(map[string][]*Response),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.server.URL
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Enqueue adds responses for method and path, served in order.
+func (s *Server) Enqueue(method, path string, responses ...*Response) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := routeKey(method, path)
+	s.queues[key] = append(s.queues[key], responses...)
+	return s
+}
+
+// OnRequest registers fn to be called with every received request before
+// it is answered. Use it to assert on models, messages or headers.
+// fn runs on the server goroutine, so it must use t.Error rather than
+// t.Fatal or require.
+func (s *Server) OnRequest(fn func(*Request)) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hooks = append(s.hooks, fn)
+	return s
+}
+
+// Requests returns all requests received so far, in order.
+func (s *Server) Requests() []*Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]*Request(nil), s.requests...)
+}
+
+// LastRequest returns the most recent request, or nil if there was none.
+func (s *Server) LastRequest() *Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.requests) == 0 {
+		return nil
+	}
+	return s.requests[len(s.requests)-1]
+}
+
+// Pending returns the number of queued responses not yet served.
+func (s *Server) Pending() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := 0
+	for _, q := range s.queues {
+		n += len(q)
+	}
+	return n
+}
+
+// serveHTTP records the request and serves the next queued response.
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	req := &Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	hooks := make([]func(*Request), len(s.hooks))
+	copy(hooks, s.hooks)
+	key := routeKey(r.Method, r.URL.Path)
+	var resp *Response
+	if q := s.queues[key]; len(q) > 0 {
+		resp, s.queues[key] = q[0], q[1:]
+	}
+	s.mu.Unlock()
+
+	for _, fn := range hooks {
+		fn(req)
+	}
+
+	if resp == nil {
+		s.t.Errorf("zaitest: unexpected request %s", key)
+		resp = Error(http.StatusNotFound, "404", "zaitest: no response queued for "+key)
+	}
+
+	if resp.Delay > 0 {
+		select {
+		case <-time.After(resp.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	writeResponse(w, resp)
+}
+
+// writeResponse writes resp to w.
+func writeResponse(w http.ResponseWriter, resp *Response) {
+	for k, v := range resp.Header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+
+	if resp.Events == nil {
+		w.Write(resp.Body)
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	for _, event := range resp.Events {
+		var buf bytes.Buffer
+		buf.WriteString("data: ")
+		buf.Write(event)
+		buf.WriteString("\n\n")
+		w.Write(buf.Bytes())
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	w.Write([]byte("data: [DONE]\n\n"))
+}
+
+// routeKey identifies an endpoint.
+func routeKey(method, path string) string {
+	return method + " " + path
+}
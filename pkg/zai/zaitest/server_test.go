@@ -0,0 +1,133 @@
+package zaitest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/chat"
+	"github.com/sofianhadi1983/zai-sdk-go/api/types/embeddings"
+	"github.com/sofianhadi1983/zai-sdk-go/pkg/zai"
+	zaierrors "github.com/sofianhadi1983/zai-sdk-go/pkg/zai/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTB captures test failures reported by the server.
+type recordingTB struct {
+	testing.TB
+
+	mu     sync.Mutex
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServer_RecordsRequestsAndRunsHooks(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	server.EnqueueChat(ChatCompletion("hi there"))
+
+	var seen []string
+	server.OnRequest(func(r *Request) {
+		req, err := r.ChatRequest()
+		if assert.NoError(t, err) {
+			seen = append(seen, req.Messages[0].Content.(string))
+		}
+		assert.Equal(t, "billing", r.Header.Get("X-Tenant"))
+	})
+
+	ctx := zai.WithHeaders(context.Background(), http.Header{"X-Tenant": []string{"billing"}})
+	resp, err := client.Chat.Create(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{
+		chat.NewUserMessage("hello"),
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, "hi there", resp.GetContent())
+	assert.Equal(t, []string{"hello"}, seen)
+	assert.Equal(t, "glm-4.7", server.LastRequest().Model())
+	assert.Equal(t, PathChatCompletions, server.LastRequest().Path)
+	assert.Len(t, server.Requests(), 1)
+	assert.Zero(t, server.Pending())
+}
+
+func TestServer_ErrorInjection(t *testing.T) {
+	t.Parallel()
+
+	newReq := func() *chat.ChatCompletionRequest {
+		return chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")})
+	}
+
+	t.Run("rate limited", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := NewTestClient(t)
+		server.Enqueue(http.MethodPost, PathChatCompletions, RateLimited(2*time.Second))
+
+		_, err := client.Chat.Create(context.Background(), newReq())
+		assert.True(t, zaierrors.IsRateLimitError(err), "got %v", err)
+	})
+
+	t.Run("server error then success on idempotent request", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := NewTestClient(t)
+		server.Enqueue(http.MethodGet, "/batches/batch_1", ServerError())
+		server.EnqueueBatchLifecycle("batch_1", "completed")
+
+		b, err := client.Batch.Retrieve(context.Background(), "batch_1")
+		require.NoError(t, err)
+		assert.Equal(t, "completed", b.Status)
+		assert.Len(t, server.Requests(), 2)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := NewTestClient(t)
+		server.Enqueue(http.MethodPost, PathChatCompletions, Malformed())
+
+		_, err := client.Chat.Create(context.Background(), newReq())
+		assert.Error(t, err)
+	})
+}
+
+func TestServer_UnexpectedRequest(t *testing.T) {
+	t.Parallel()
+
+	tb := &recordingTB{TB: t}
+	server := NewServer(tb)
+	client := server.NewClient(t)
+
+	_, err := client.Embeddings.Create(context.Background(), embeddings.NewEmbeddingRequest(embeddings.ModelEmbedding3, "hi"))
+	assert.Error(t, err)
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+	require.Len(t, tb.errors, 1)
+	assert.True(t, strings.Contains(tb.errors[0], "POST /embeddings"), tb.errors[0])
+}
+
+func TestServer_Delay(t *testing.T) {
+	t.Parallel()
+
+	client, server := NewTestClient(t)
+	resp := JSON(http.StatusOK, ChatCompletion("late"))
+	resp.Delay = 200 * time.Millisecond
+	server.Enqueue(http.MethodPost, PathChatCompletions, resp)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := client.Chat.Create(ctx, chat.NewChatCompletionRequest(chat.ModelGLM47, []chat.Message{chat.NewUserMessage("hi")}))
+	assert.Error(t, err)
+}